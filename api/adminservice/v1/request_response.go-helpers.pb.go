@@ -325,6 +325,80 @@ func (this *DescribeHistoryHostResponse) Equal(that interface{}) bool {
 	return proto.Equal(this, that1)
 }
 
+// Marshal an object of type DrainHistoryHostRequest to the protobuf v3 wire format
+func (val *DrainHistoryHostRequest) Marshal() ([]byte, error) {
+	return proto.Marshal(val)
+}
+
+// Unmarshal an object of type DrainHistoryHostRequest from the protobuf v3 wire format
+func (val *DrainHistoryHostRequest) Unmarshal(buf []byte) error {
+	return proto.Unmarshal(buf, val)
+}
+
+// Size returns the size of the object, in bytes, once serialized
+func (val *DrainHistoryHostRequest) Size() int {
+	return proto.Size(val)
+}
+
+// Equal returns whether two DrainHistoryHostRequest values are equivalent by recursively
+// comparing the message's fields.
+// For more information see the documentation for
+// https://pkg.go.dev/google.golang.org/protobuf/proto#Equal
+func (this *DrainHistoryHostRequest) Equal(that interface{}) bool {
+	if that == nil {
+		return this == nil
+	}
+
+	var that1 *DrainHistoryHostRequest
+	switch t := that.(type) {
+	case *DrainHistoryHostRequest:
+		that1 = t
+	case DrainHistoryHostRequest:
+		that1 = &t
+	default:
+		return false
+	}
+
+	return proto.Equal(this, that1)
+}
+
+// Marshal an object of type DrainHistoryHostResponse to the protobuf v3 wire format
+func (val *DrainHistoryHostResponse) Marshal() ([]byte, error) {
+	return proto.Marshal(val)
+}
+
+// Unmarshal an object of type DrainHistoryHostResponse from the protobuf v3 wire format
+func (val *DrainHistoryHostResponse) Unmarshal(buf []byte) error {
+	return proto.Unmarshal(buf, val)
+}
+
+// Size returns the size of the object, in bytes, once serialized
+func (val *DrainHistoryHostResponse) Size() int {
+	return proto.Size(val)
+}
+
+// Equal returns whether two DrainHistoryHostResponse values are equivalent by recursively
+// comparing the message's fields.
+// For more information see the documentation for
+// https://pkg.go.dev/google.golang.org/protobuf/proto#Equal
+func (this *DrainHistoryHostResponse) Equal(that interface{}) bool {
+	if that == nil {
+		return this == nil
+	}
+
+	var that1 *DrainHistoryHostResponse
+	switch t := that.(type) {
+	case *DrainHistoryHostResponse:
+		that1 = t
+	case DrainHistoryHostResponse:
+		that1 = &t
+	default:
+		return false
+	}
+
+	return proto.Equal(this, that1)
+}
+
 // Marshal an object of type CloseShardRequest to the protobuf v3 wire format
 func (val *CloseShardRequest) Marshal() ([]byte, error) {
 	return proto.Marshal(val)
@@ -2840,3 +2914,669 @@ func (this *ListQueuesResponse) Equal(that interface{}) bool {
 
 	return proto.Equal(this, that1)
 }
+
+// Marshal an object of type StartHistoryShardCountMigrationRequest to the protobuf v3 wire format
+func (val *StartHistoryShardCountMigrationRequest) Marshal() ([]byte, error) {
+	return proto.Marshal(val)
+}
+
+// Unmarshal an object of type StartHistoryShardCountMigrationRequest from the protobuf v3 wire format
+func (val *StartHistoryShardCountMigrationRequest) Unmarshal(buf []byte) error {
+	return proto.Unmarshal(buf, val)
+}
+
+// Size returns the size of the object, in bytes, once serialized
+func (val *StartHistoryShardCountMigrationRequest) Size() int {
+	return proto.Size(val)
+}
+
+// Equal returns whether two StartHistoryShardCountMigrationRequest values are equivalent by recursively
+// comparing the message's fields.
+// For more information see the documentation for
+// https://pkg.go.dev/google.golang.org/protobuf/proto#Equal
+func (this *StartHistoryShardCountMigrationRequest) Equal(that interface{}) bool {
+	if that == nil {
+		return this == nil
+	}
+
+	var that1 *StartHistoryShardCountMigrationRequest
+	switch t := that.(type) {
+	case *StartHistoryShardCountMigrationRequest:
+		that1 = t
+	case StartHistoryShardCountMigrationRequest:
+		that1 = &t
+	default:
+		return false
+	}
+
+	return proto.Equal(this, that1)
+}
+
+// Marshal an object of type StartHistoryShardCountMigrationResponse to the protobuf v3 wire format
+func (val *StartHistoryShardCountMigrationResponse) Marshal() ([]byte, error) {
+	return proto.Marshal(val)
+}
+
+// Unmarshal an object of type StartHistoryShardCountMigrationResponse from the protobuf v3 wire format
+func (val *StartHistoryShardCountMigrationResponse) Unmarshal(buf []byte) error {
+	return proto.Unmarshal(buf, val)
+}
+
+// Size returns the size of the object, in bytes, once serialized
+func (val *StartHistoryShardCountMigrationResponse) Size() int {
+	return proto.Size(val)
+}
+
+// Equal returns whether two StartHistoryShardCountMigrationResponse values are equivalent by recursively
+// comparing the message's fields.
+// For more information see the documentation for
+// https://pkg.go.dev/google.golang.org/protobuf/proto#Equal
+func (this *StartHistoryShardCountMigrationResponse) Equal(that interface{}) bool {
+	if that == nil {
+		return this == nil
+	}
+
+	var that1 *StartHistoryShardCountMigrationResponse
+	switch t := that.(type) {
+	case *StartHistoryShardCountMigrationResponse:
+		that1 = t
+	case StartHistoryShardCountMigrationResponse:
+		that1 = &t
+	default:
+		return false
+	}
+
+	return proto.Equal(this, that1)
+}
+
+// Marshal an object of type DescribeHistoryShardCountMigrationRequest to the protobuf v3 wire format
+func (val *DescribeHistoryShardCountMigrationRequest) Marshal() ([]byte, error) {
+	return proto.Marshal(val)
+}
+
+// Unmarshal an object of type DescribeHistoryShardCountMigrationRequest from the protobuf v3 wire format
+func (val *DescribeHistoryShardCountMigrationRequest) Unmarshal(buf []byte) error {
+	return proto.Unmarshal(buf, val)
+}
+
+// Size returns the size of the object, in bytes, once serialized
+func (val *DescribeHistoryShardCountMigrationRequest) Size() int {
+	return proto.Size(val)
+}
+
+// Equal returns whether two DescribeHistoryShardCountMigrationRequest values are equivalent by recursively
+// comparing the message's fields.
+// For more information see the documentation for
+// https://pkg.go.dev/google.golang.org/protobuf/proto#Equal
+func (this *DescribeHistoryShardCountMigrationRequest) Equal(that interface{}) bool {
+	if that == nil {
+		return this == nil
+	}
+
+	var that1 *DescribeHistoryShardCountMigrationRequest
+	switch t := that.(type) {
+	case *DescribeHistoryShardCountMigrationRequest:
+		that1 = t
+	case DescribeHistoryShardCountMigrationRequest:
+		that1 = &t
+	default:
+		return false
+	}
+
+	return proto.Equal(this, that1)
+}
+
+// Marshal an object of type DescribeHistoryShardCountMigrationResponse to the protobuf v3 wire format
+func (val *DescribeHistoryShardCountMigrationResponse) Marshal() ([]byte, error) {
+	return proto.Marshal(val)
+}
+
+// Unmarshal an object of type DescribeHistoryShardCountMigrationResponse from the protobuf v3 wire format
+func (val *DescribeHistoryShardCountMigrationResponse) Unmarshal(buf []byte) error {
+	return proto.Unmarshal(buf, val)
+}
+
+// Size returns the size of the object, in bytes, once serialized
+func (val *DescribeHistoryShardCountMigrationResponse) Size() int {
+	return proto.Size(val)
+}
+
+// Equal returns whether two DescribeHistoryShardCountMigrationResponse values are equivalent by recursively
+// comparing the message's fields.
+// For more information see the documentation for
+// https://pkg.go.dev/google.golang.org/protobuf/proto#Equal
+func (this *DescribeHistoryShardCountMigrationResponse) Equal(that interface{}) bool {
+	if that == nil {
+		return this == nil
+	}
+
+	var that1 *DescribeHistoryShardCountMigrationResponse
+	switch t := that.(type) {
+	case *DescribeHistoryShardCountMigrationResponse:
+		that1 = t
+	case DescribeHistoryShardCountMigrationResponse:
+		that1 = &t
+	default:
+		return false
+	}
+
+	return proto.Equal(this, that1)
+}
+
+// Marshal an object of type PauseTaskQueueRequest to the protobuf v3 wire format
+func (val *PauseTaskQueueRequest) Marshal() ([]byte, error) {
+	return proto.Marshal(val)
+}
+
+// Unmarshal an object of type PauseTaskQueueRequest from the protobuf v3 wire format
+func (val *PauseTaskQueueRequest) Unmarshal(buf []byte) error {
+	return proto.Unmarshal(buf, val)
+}
+
+// Size returns the size of the object, in bytes, once serialized
+func (val *PauseTaskQueueRequest) Size() int {
+	return proto.Size(val)
+}
+
+// Equal returns whether two PauseTaskQueueRequest values are equivalent by recursively
+// comparing the message's fields.
+// For more information see the documentation for
+// https://pkg.go.dev/google.golang.org/protobuf/proto#Equal
+func (this *PauseTaskQueueRequest) Equal(that interface{}) bool {
+	if that == nil {
+		return this == nil
+	}
+
+	var that1 *PauseTaskQueueRequest
+	switch t := that.(type) {
+	case *PauseTaskQueueRequest:
+		that1 = t
+	case PauseTaskQueueRequest:
+		that1 = &t
+	default:
+		return false
+	}
+
+	return proto.Equal(this, that1)
+}
+
+// Marshal an object of type PauseTaskQueueResponse to the protobuf v3 wire format
+func (val *PauseTaskQueueResponse) Marshal() ([]byte, error) {
+	return proto.Marshal(val)
+}
+
+// Unmarshal an object of type PauseTaskQueueResponse from the protobuf v3 wire format
+func (val *PauseTaskQueueResponse) Unmarshal(buf []byte) error {
+	return proto.Unmarshal(buf, val)
+}
+
+// Size returns the size of the object, in bytes, once serialized
+func (val *PauseTaskQueueResponse) Size() int {
+	return proto.Size(val)
+}
+
+// Equal returns whether two PauseTaskQueueResponse values are equivalent by recursively
+// comparing the message's fields.
+// For more information see the documentation for
+// https://pkg.go.dev/google.golang.org/protobuf/proto#Equal
+func (this *PauseTaskQueueResponse) Equal(that interface{}) bool {
+	if that == nil {
+		return this == nil
+	}
+
+	var that1 *PauseTaskQueueResponse
+	switch t := that.(type) {
+	case *PauseTaskQueueResponse:
+		that1 = t
+	case PauseTaskQueueResponse:
+		that1 = &t
+	default:
+		return false
+	}
+
+	return proto.Equal(this, that1)
+}
+
+// Marshal an object of type ResumeTaskQueueRequest to the protobuf v3 wire format
+func (val *ResumeTaskQueueRequest) Marshal() ([]byte, error) {
+	return proto.Marshal(val)
+}
+
+// Unmarshal an object of type ResumeTaskQueueRequest from the protobuf v3 wire format
+func (val *ResumeTaskQueueRequest) Unmarshal(buf []byte) error {
+	return proto.Unmarshal(buf, val)
+}
+
+// Size returns the size of the object, in bytes, once serialized
+func (val *ResumeTaskQueueRequest) Size() int {
+	return proto.Size(val)
+}
+
+// Equal returns whether two ResumeTaskQueueRequest values are equivalent by recursively
+// comparing the message's fields.
+// For more information see the documentation for
+// https://pkg.go.dev/google.golang.org/protobuf/proto#Equal
+func (this *ResumeTaskQueueRequest) Equal(that interface{}) bool {
+	if that == nil {
+		return this == nil
+	}
+
+	var that1 *ResumeTaskQueueRequest
+	switch t := that.(type) {
+	case *ResumeTaskQueueRequest:
+		that1 = t
+	case ResumeTaskQueueRequest:
+		that1 = &t
+	default:
+		return false
+	}
+
+	return proto.Equal(this, that1)
+}
+
+// Marshal an object of type ResumeTaskQueueResponse to the protobuf v3 wire format
+func (val *ResumeTaskQueueResponse) Marshal() ([]byte, error) {
+	return proto.Marshal(val)
+}
+
+// Unmarshal an object of type ResumeTaskQueueResponse from the protobuf v3 wire format
+func (val *ResumeTaskQueueResponse) Unmarshal(buf []byte) error {
+	return proto.Unmarshal(buf, val)
+}
+
+// Size returns the size of the object, in bytes, once serialized
+func (val *ResumeTaskQueueResponse) Size() int {
+	return proto.Size(val)
+}
+
+// Equal returns whether two ResumeTaskQueueResponse values are equivalent by recursively
+// comparing the message's fields.
+// For more information see the documentation for
+// https://pkg.go.dev/google.golang.org/protobuf/proto#Equal
+func (this *ResumeTaskQueueResponse) Equal(that interface{}) bool {
+	if that == nil {
+		return this == nil
+	}
+
+	var that1 *ResumeTaskQueueResponse
+	switch t := that.(type) {
+	case *ResumeTaskQueueResponse:
+		that1 = t
+	case ResumeTaskQueueResponse:
+		that1 = &t
+	default:
+		return false
+	}
+
+	return proto.Equal(this, that1)
+}
+
+// Marshal an object of type StartVisibilityReindexRequest to the protobuf v3 wire format
+func (val *StartVisibilityReindexRequest) Marshal() ([]byte, error) {
+	return proto.Marshal(val)
+}
+
+// Unmarshal an object of type StartVisibilityReindexRequest from the protobuf v3 wire format
+func (val *StartVisibilityReindexRequest) Unmarshal(buf []byte) error {
+	return proto.Unmarshal(buf, val)
+}
+
+// Size returns the size of the object, in bytes, once serialized
+func (val *StartVisibilityReindexRequest) Size() int {
+	return proto.Size(val)
+}
+
+// Equal returns whether two StartVisibilityReindexRequest values are equivalent by recursively
+// comparing the message's fields.
+// For more information see the documentation for
+// https://pkg.go.dev/google.golang.org/protobuf/proto#Equal
+func (this *StartVisibilityReindexRequest) Equal(that interface{}) bool {
+	if that == nil {
+		return this == nil
+	}
+
+	var that1 *StartVisibilityReindexRequest
+	switch t := that.(type) {
+	case *StartVisibilityReindexRequest:
+		that1 = t
+	case StartVisibilityReindexRequest:
+		that1 = &t
+	default:
+		return false
+	}
+
+	return proto.Equal(this, that1)
+}
+
+// Marshal an object of type StartVisibilityReindexResponse to the protobuf v3 wire format
+func (val *StartVisibilityReindexResponse) Marshal() ([]byte, error) {
+	return proto.Marshal(val)
+}
+
+// Unmarshal an object of type StartVisibilityReindexResponse from the protobuf v3 wire format
+func (val *StartVisibilityReindexResponse) Unmarshal(buf []byte) error {
+	return proto.Unmarshal(buf, val)
+}
+
+// Size returns the size of the object, in bytes, once serialized
+func (val *StartVisibilityReindexResponse) Size() int {
+	return proto.Size(val)
+}
+
+// Equal returns whether two StartVisibilityReindexResponse values are equivalent by recursively
+// comparing the message's fields.
+// For more information see the documentation for
+// https://pkg.go.dev/google.golang.org/protobuf/proto#Equal
+func (this *StartVisibilityReindexResponse) Equal(that interface{}) bool {
+	if that == nil {
+		return this == nil
+	}
+
+	var that1 *StartVisibilityReindexResponse
+	switch t := that.(type) {
+	case *StartVisibilityReindexResponse:
+		that1 = t
+	case StartVisibilityReindexResponse:
+		that1 = &t
+	default:
+		return false
+	}
+
+	return proto.Equal(this, that1)
+}
+
+// Marshal an object of type DescribeVisibilityReindexRequest to the protobuf v3 wire format
+func (val *DescribeVisibilityReindexRequest) Marshal() ([]byte, error) {
+	return proto.Marshal(val)
+}
+
+// Unmarshal an object of type DescribeVisibilityReindexRequest from the protobuf v3 wire format
+func (val *DescribeVisibilityReindexRequest) Unmarshal(buf []byte) error {
+	return proto.Unmarshal(buf, val)
+}
+
+// Size returns the size of the object, in bytes, once serialized
+func (val *DescribeVisibilityReindexRequest) Size() int {
+	return proto.Size(val)
+}
+
+// Equal returns whether two DescribeVisibilityReindexRequest values are equivalent by recursively
+// comparing the message's fields.
+// For more information see the documentation for
+// https://pkg.go.dev/google.golang.org/protobuf/proto#Equal
+func (this *DescribeVisibilityReindexRequest) Equal(that interface{}) bool {
+	if that == nil {
+		return this == nil
+	}
+
+	var that1 *DescribeVisibilityReindexRequest
+	switch t := that.(type) {
+	case *DescribeVisibilityReindexRequest:
+		that1 = t
+	case DescribeVisibilityReindexRequest:
+		that1 = &t
+	default:
+		return false
+	}
+
+	return proto.Equal(this, that1)
+}
+
+// Marshal an object of type DescribeVisibilityReindexResponse to the protobuf v3 wire format
+func (val *DescribeVisibilityReindexResponse) Marshal() ([]byte, error) {
+	return proto.Marshal(val)
+}
+
+// Unmarshal an object of type DescribeVisibilityReindexResponse from the protobuf v3 wire format
+func (val *DescribeVisibilityReindexResponse) Unmarshal(buf []byte) error {
+	return proto.Unmarshal(buf, val)
+}
+
+// Size returns the size of the object, in bytes, once serialized
+func (val *DescribeVisibilityReindexResponse) Size() int {
+	return proto.Size(val)
+}
+
+// Equal returns whether two DescribeVisibilityReindexResponse values are equivalent by recursively
+// comparing the message's fields.
+// For more information see the documentation for
+// https://pkg.go.dev/google.golang.org/protobuf/proto#Equal
+func (this *DescribeVisibilityReindexResponse) Equal(that interface{}) bool {
+	if that == nil {
+		return this == nil
+	}
+
+	var that1 *DescribeVisibilityReindexResponse
+	switch t := that.(type) {
+	case *DescribeVisibilityReindexResponse:
+		that1 = t
+	case DescribeVisibilityReindexResponse:
+		that1 = &t
+	default:
+		return false
+	}
+
+	return proto.Equal(this, that1)
+}
+
+// Marshal an object of type SaveVisibilityQueryRequest to the protobuf v3 wire format
+func (val *SaveVisibilityQueryRequest) Marshal() ([]byte, error) {
+	return proto.Marshal(val)
+}
+
+// Unmarshal an object of type SaveVisibilityQueryRequest from the protobuf v3 wire format
+func (val *SaveVisibilityQueryRequest) Unmarshal(buf []byte) error {
+	return proto.Unmarshal(buf, val)
+}
+
+// Size returns the size of the object, in bytes, once serialized
+func (val *SaveVisibilityQueryRequest) Size() int {
+	return proto.Size(val)
+}
+
+// Equal returns whether two SaveVisibilityQueryRequest values are equivalent by recursively
+// comparing the message's fields.
+// For more information see the documentation for
+// https://pkg.go.dev/google.golang.org/protobuf/proto#Equal
+func (this *SaveVisibilityQueryRequest) Equal(that interface{}) bool {
+	if that == nil {
+		return this == nil
+	}
+
+	var that1 *SaveVisibilityQueryRequest
+	switch t := that.(type) {
+	case *SaveVisibilityQueryRequest:
+		that1 = t
+	case SaveVisibilityQueryRequest:
+		that1 = &t
+	default:
+		return false
+	}
+
+	return proto.Equal(this, that1)
+}
+
+// Marshal an object of type SaveVisibilityQueryResponse to the protobuf v3 wire format
+func (val *SaveVisibilityQueryResponse) Marshal() ([]byte, error) {
+	return proto.Marshal(val)
+}
+
+// Unmarshal an object of type SaveVisibilityQueryResponse from the protobuf v3 wire format
+func (val *SaveVisibilityQueryResponse) Unmarshal(buf []byte) error {
+	return proto.Unmarshal(buf, val)
+}
+
+// Size returns the size of the object, in bytes, once serialized
+func (val *SaveVisibilityQueryResponse) Size() int {
+	return proto.Size(val)
+}
+
+// Equal returns whether two SaveVisibilityQueryResponse values are equivalent by recursively
+// comparing the message's fields.
+// For more information see the documentation for
+// https://pkg.go.dev/google.golang.org/protobuf/proto#Equal
+func (this *SaveVisibilityQueryResponse) Equal(that interface{}) bool {
+	if that == nil {
+		return this == nil
+	}
+
+	var that1 *SaveVisibilityQueryResponse
+	switch t := that.(type) {
+	case *SaveVisibilityQueryResponse:
+		that1 = t
+	case SaveVisibilityQueryResponse:
+		that1 = &t
+	default:
+		return false
+	}
+
+	return proto.Equal(this, that1)
+}
+
+// Marshal an object of type DeleteSavedVisibilityQueryRequest to the protobuf v3 wire format
+func (val *DeleteSavedVisibilityQueryRequest) Marshal() ([]byte, error) {
+	return proto.Marshal(val)
+}
+
+// Unmarshal an object of type DeleteSavedVisibilityQueryRequest from the protobuf v3 wire format
+func (val *DeleteSavedVisibilityQueryRequest) Unmarshal(buf []byte) error {
+	return proto.Unmarshal(buf, val)
+}
+
+// Size returns the size of the object, in bytes, once serialized
+func (val *DeleteSavedVisibilityQueryRequest) Size() int {
+	return proto.Size(val)
+}
+
+// Equal returns whether two DeleteSavedVisibilityQueryRequest values are equivalent by recursively
+// comparing the message's fields.
+// For more information see the documentation for
+// https://pkg.go.dev/google.golang.org/protobuf/proto#Equal
+func (this *DeleteSavedVisibilityQueryRequest) Equal(that interface{}) bool {
+	if that == nil {
+		return this == nil
+	}
+
+	var that1 *DeleteSavedVisibilityQueryRequest
+	switch t := that.(type) {
+	case *DeleteSavedVisibilityQueryRequest:
+		that1 = t
+	case DeleteSavedVisibilityQueryRequest:
+		that1 = &t
+	default:
+		return false
+	}
+
+	return proto.Equal(this, that1)
+}
+
+// Marshal an object of type DeleteSavedVisibilityQueryResponse to the protobuf v3 wire format
+func (val *DeleteSavedVisibilityQueryResponse) Marshal() ([]byte, error) {
+	return proto.Marshal(val)
+}
+
+// Unmarshal an object of type DeleteSavedVisibilityQueryResponse from the protobuf v3 wire format
+func (val *DeleteSavedVisibilityQueryResponse) Unmarshal(buf []byte) error {
+	return proto.Unmarshal(buf, val)
+}
+
+// Size returns the size of the object, in bytes, once serialized
+func (val *DeleteSavedVisibilityQueryResponse) Size() int {
+	return proto.Size(val)
+}
+
+// Equal returns whether two DeleteSavedVisibilityQueryResponse values are equivalent by recursively
+// comparing the message's fields.
+// For more information see the documentation for
+// https://pkg.go.dev/google.golang.org/protobuf/proto#Equal
+func (this *DeleteSavedVisibilityQueryResponse) Equal(that interface{}) bool {
+	if that == nil {
+		return this == nil
+	}
+
+	var that1 *DeleteSavedVisibilityQueryResponse
+	switch t := that.(type) {
+	case *DeleteSavedVisibilityQueryResponse:
+		that1 = t
+	case DeleteSavedVisibilityQueryResponse:
+		that1 = &t
+	default:
+		return false
+	}
+
+	return proto.Equal(this, that1)
+}
+
+// Marshal an object of type UndrainHistoryHostRequest to the protobuf v3 wire format
+func (val *UndrainHistoryHostRequest) Marshal() ([]byte, error) {
+	return proto.Marshal(val)
+}
+
+// Unmarshal an object of type UndrainHistoryHostRequest from the protobuf v3 wire format
+func (val *UndrainHistoryHostRequest) Unmarshal(buf []byte) error {
+	return proto.Unmarshal(buf, val)
+}
+
+// Size returns the size of the object, in bytes, once serialized
+func (val *UndrainHistoryHostRequest) Size() int {
+	return proto.Size(val)
+}
+
+// Equal returns whether two UndrainHistoryHostRequest values are equivalent by recursively
+// comparing the message's fields.
+// For more information see the documentation for
+// https://pkg.go.dev/google.golang.org/protobuf/proto#Equal
+func (this *UndrainHistoryHostRequest) Equal(that interface{}) bool {
+	if that == nil {
+		return this == nil
+	}
+
+	var that1 *UndrainHistoryHostRequest
+	switch t := that.(type) {
+	case *UndrainHistoryHostRequest:
+		that1 = t
+	case UndrainHistoryHostRequest:
+		that1 = &t
+	default:
+		return false
+	}
+
+	return proto.Equal(this, that1)
+}
+
+// Marshal an object of type UndrainHistoryHostResponse to the protobuf v3 wire format
+func (val *UndrainHistoryHostResponse) Marshal() ([]byte, error) {
+	return proto.Marshal(val)
+}
+
+// Unmarshal an object of type UndrainHistoryHostResponse from the protobuf v3 wire format
+func (val *UndrainHistoryHostResponse) Unmarshal(buf []byte) error {
+	return proto.Unmarshal(buf, val)
+}
+
+// Size returns the size of the object, in bytes, once serialized
+func (val *UndrainHistoryHostResponse) Size() int {
+	return proto.Size(val)
+}
+
+// Equal returns whether two UndrainHistoryHostResponse values are equivalent by recursively
+// comparing the message's fields.
+// For more information see the documentation for
+// https://pkg.go.dev/google.golang.org/protobuf/proto#Equal
+func (this *UndrainHistoryHostResponse) Equal(that interface{}) bool {
+	if that == nil {
+		return this == nil
+	}
+
+	var that1 *UndrainHistoryHostResponse
+	switch t := that.(type) {
+	case *UndrainHistoryHostResponse:
+		that1 = t
+	case UndrainHistoryHostResponse:
+		that1 = &t
+	default:
+		return false
+	}
+
+	return proto.Equal(this, that1)
+}
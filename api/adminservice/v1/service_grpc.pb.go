@@ -40,43 +40,53 @@ import (
 const _ = grpc.SupportPackageIsVersion7
 
 const (
-	AdminService_RebuildMutableState_FullMethodName               = "/temporal.server.api.adminservice.v1.AdminService/RebuildMutableState"
-	AdminService_ImportWorkflowExecution_FullMethodName           = "/temporal.server.api.adminservice.v1.AdminService/ImportWorkflowExecution"
-	AdminService_DescribeMutableState_FullMethodName              = "/temporal.server.api.adminservice.v1.AdminService/DescribeMutableState"
-	AdminService_DescribeHistoryHost_FullMethodName               = "/temporal.server.api.adminservice.v1.AdminService/DescribeHistoryHost"
-	AdminService_GetShard_FullMethodName                          = "/temporal.server.api.adminservice.v1.AdminService/GetShard"
-	AdminService_CloseShard_FullMethodName                        = "/temporal.server.api.adminservice.v1.AdminService/CloseShard"
-	AdminService_ListHistoryTasks_FullMethodName                  = "/temporal.server.api.adminservice.v1.AdminService/ListHistoryTasks"
-	AdminService_RemoveTask_FullMethodName                        = "/temporal.server.api.adminservice.v1.AdminService/RemoveTask"
-	AdminService_GetWorkflowExecutionRawHistoryV2_FullMethodName  = "/temporal.server.api.adminservice.v1.AdminService/GetWorkflowExecutionRawHistoryV2"
-	AdminService_GetReplicationMessages_FullMethodName            = "/temporal.server.api.adminservice.v1.AdminService/GetReplicationMessages"
-	AdminService_GetNamespaceReplicationMessages_FullMethodName   = "/temporal.server.api.adminservice.v1.AdminService/GetNamespaceReplicationMessages"
-	AdminService_GetDLQReplicationMessages_FullMethodName         = "/temporal.server.api.adminservice.v1.AdminService/GetDLQReplicationMessages"
-	AdminService_ReapplyEvents_FullMethodName                     = "/temporal.server.api.adminservice.v1.AdminService/ReapplyEvents"
-	AdminService_AddSearchAttributes_FullMethodName               = "/temporal.server.api.adminservice.v1.AdminService/AddSearchAttributes"
-	AdminService_RemoveSearchAttributes_FullMethodName            = "/temporal.server.api.adminservice.v1.AdminService/RemoveSearchAttributes"
-	AdminService_GetSearchAttributes_FullMethodName               = "/temporal.server.api.adminservice.v1.AdminService/GetSearchAttributes"
-	AdminService_DescribeCluster_FullMethodName                   = "/temporal.server.api.adminservice.v1.AdminService/DescribeCluster"
-	AdminService_ListClusters_FullMethodName                      = "/temporal.server.api.adminservice.v1.AdminService/ListClusters"
-	AdminService_ListClusterMembers_FullMethodName                = "/temporal.server.api.adminservice.v1.AdminService/ListClusterMembers"
-	AdminService_AddOrUpdateRemoteCluster_FullMethodName          = "/temporal.server.api.adminservice.v1.AdminService/AddOrUpdateRemoteCluster"
-	AdminService_RemoveRemoteCluster_FullMethodName               = "/temporal.server.api.adminservice.v1.AdminService/RemoveRemoteCluster"
-	AdminService_GetDLQMessages_FullMethodName                    = "/temporal.server.api.adminservice.v1.AdminService/GetDLQMessages"
-	AdminService_PurgeDLQMessages_FullMethodName                  = "/temporal.server.api.adminservice.v1.AdminService/PurgeDLQMessages"
-	AdminService_MergeDLQMessages_FullMethodName                  = "/temporal.server.api.adminservice.v1.AdminService/MergeDLQMessages"
-	AdminService_RefreshWorkflowTasks_FullMethodName              = "/temporal.server.api.adminservice.v1.AdminService/RefreshWorkflowTasks"
-	AdminService_ResendReplicationTasks_FullMethodName            = "/temporal.server.api.adminservice.v1.AdminService/ResendReplicationTasks"
-	AdminService_GetTaskQueueTasks_FullMethodName                 = "/temporal.server.api.adminservice.v1.AdminService/GetTaskQueueTasks"
-	AdminService_DeleteWorkflowExecution_FullMethodName           = "/temporal.server.api.adminservice.v1.AdminService/DeleteWorkflowExecution"
-	AdminService_StreamWorkflowReplicationMessages_FullMethodName = "/temporal.server.api.adminservice.v1.AdminService/StreamWorkflowReplicationMessages"
-	AdminService_GetNamespace_FullMethodName                      = "/temporal.server.api.adminservice.v1.AdminService/GetNamespace"
-	AdminService_GetDLQTasks_FullMethodName                       = "/temporal.server.api.adminservice.v1.AdminService/GetDLQTasks"
-	AdminService_PurgeDLQTasks_FullMethodName                     = "/temporal.server.api.adminservice.v1.AdminService/PurgeDLQTasks"
-	AdminService_MergeDLQTasks_FullMethodName                     = "/temporal.server.api.adminservice.v1.AdminService/MergeDLQTasks"
-	AdminService_DescribeDLQJob_FullMethodName                    = "/temporal.server.api.adminservice.v1.AdminService/DescribeDLQJob"
-	AdminService_CancelDLQJob_FullMethodName                      = "/temporal.server.api.adminservice.v1.AdminService/CancelDLQJob"
-	AdminService_AddTasks_FullMethodName                          = "/temporal.server.api.adminservice.v1.AdminService/AddTasks"
-	AdminService_ListQueues_FullMethodName                        = "/temporal.server.api.adminservice.v1.AdminService/ListQueues"
+	AdminService_RebuildMutableState_FullMethodName                = "/temporal.server.api.adminservice.v1.AdminService/RebuildMutableState"
+	AdminService_ImportWorkflowExecution_FullMethodName            = "/temporal.server.api.adminservice.v1.AdminService/ImportWorkflowExecution"
+	AdminService_DescribeMutableState_FullMethodName               = "/temporal.server.api.adminservice.v1.AdminService/DescribeMutableState"
+	AdminService_DescribeHistoryHost_FullMethodName                = "/temporal.server.api.adminservice.v1.AdminService/DescribeHistoryHost"
+	AdminService_DrainHistoryHost_FullMethodName                   = "/temporal.server.api.adminservice.v1.AdminService/DrainHistoryHost"
+	AdminService_GetShard_FullMethodName                           = "/temporal.server.api.adminservice.v1.AdminService/GetShard"
+	AdminService_CloseShard_FullMethodName                         = "/temporal.server.api.adminservice.v1.AdminService/CloseShard"
+	AdminService_ListHistoryTasks_FullMethodName                   = "/temporal.server.api.adminservice.v1.AdminService/ListHistoryTasks"
+	AdminService_RemoveTask_FullMethodName                         = "/temporal.server.api.adminservice.v1.AdminService/RemoveTask"
+	AdminService_GetWorkflowExecutionRawHistoryV2_FullMethodName   = "/temporal.server.api.adminservice.v1.AdminService/GetWorkflowExecutionRawHistoryV2"
+	AdminService_GetReplicationMessages_FullMethodName             = "/temporal.server.api.adminservice.v1.AdminService/GetReplicationMessages"
+	AdminService_GetNamespaceReplicationMessages_FullMethodName    = "/temporal.server.api.adminservice.v1.AdminService/GetNamespaceReplicationMessages"
+	AdminService_GetDLQReplicationMessages_FullMethodName          = "/temporal.server.api.adminservice.v1.AdminService/GetDLQReplicationMessages"
+	AdminService_ReapplyEvents_FullMethodName                      = "/temporal.server.api.adminservice.v1.AdminService/ReapplyEvents"
+	AdminService_AddSearchAttributes_FullMethodName                = "/temporal.server.api.adminservice.v1.AdminService/AddSearchAttributes"
+	AdminService_RemoveSearchAttributes_FullMethodName             = "/temporal.server.api.adminservice.v1.AdminService/RemoveSearchAttributes"
+	AdminService_GetSearchAttributes_FullMethodName                = "/temporal.server.api.adminservice.v1.AdminService/GetSearchAttributes"
+	AdminService_DescribeCluster_FullMethodName                    = "/temporal.server.api.adminservice.v1.AdminService/DescribeCluster"
+	AdminService_ListClusters_FullMethodName                       = "/temporal.server.api.adminservice.v1.AdminService/ListClusters"
+	AdminService_ListClusterMembers_FullMethodName                 = "/temporal.server.api.adminservice.v1.AdminService/ListClusterMembers"
+	AdminService_AddOrUpdateRemoteCluster_FullMethodName           = "/temporal.server.api.adminservice.v1.AdminService/AddOrUpdateRemoteCluster"
+	AdminService_RemoveRemoteCluster_FullMethodName                = "/temporal.server.api.adminservice.v1.AdminService/RemoveRemoteCluster"
+	AdminService_GetDLQMessages_FullMethodName                     = "/temporal.server.api.adminservice.v1.AdminService/GetDLQMessages"
+	AdminService_PurgeDLQMessages_FullMethodName                   = "/temporal.server.api.adminservice.v1.AdminService/PurgeDLQMessages"
+	AdminService_MergeDLQMessages_FullMethodName                   = "/temporal.server.api.adminservice.v1.AdminService/MergeDLQMessages"
+	AdminService_RefreshWorkflowTasks_FullMethodName               = "/temporal.server.api.adminservice.v1.AdminService/RefreshWorkflowTasks"
+	AdminService_ResendReplicationTasks_FullMethodName             = "/temporal.server.api.adminservice.v1.AdminService/ResendReplicationTasks"
+	AdminService_GetTaskQueueTasks_FullMethodName                  = "/temporal.server.api.adminservice.v1.AdminService/GetTaskQueueTasks"
+	AdminService_DeleteWorkflowExecution_FullMethodName            = "/temporal.server.api.adminservice.v1.AdminService/DeleteWorkflowExecution"
+	AdminService_StreamWorkflowReplicationMessages_FullMethodName  = "/temporal.server.api.adminservice.v1.AdminService/StreamWorkflowReplicationMessages"
+	AdminService_GetNamespace_FullMethodName                       = "/temporal.server.api.adminservice.v1.AdminService/GetNamespace"
+	AdminService_GetDLQTasks_FullMethodName                        = "/temporal.server.api.adminservice.v1.AdminService/GetDLQTasks"
+	AdminService_PurgeDLQTasks_FullMethodName                      = "/temporal.server.api.adminservice.v1.AdminService/PurgeDLQTasks"
+	AdminService_MergeDLQTasks_FullMethodName                      = "/temporal.server.api.adminservice.v1.AdminService/MergeDLQTasks"
+	AdminService_DescribeDLQJob_FullMethodName                     = "/temporal.server.api.adminservice.v1.AdminService/DescribeDLQJob"
+	AdminService_CancelDLQJob_FullMethodName                       = "/temporal.server.api.adminservice.v1.AdminService/CancelDLQJob"
+	AdminService_AddTasks_FullMethodName                           = "/temporal.server.api.adminservice.v1.AdminService/AddTasks"
+	AdminService_ListQueues_FullMethodName                         = "/temporal.server.api.adminservice.v1.AdminService/ListQueues"
+	AdminService_StartHistoryShardCountMigration_FullMethodName    = "/temporal.server.api.adminservice.v1.AdminService/StartHistoryShardCountMigration"
+	AdminService_DescribeHistoryShardCountMigration_FullMethodName = "/temporal.server.api.adminservice.v1.AdminService/DescribeHistoryShardCountMigration"
+	AdminService_PauseTaskQueue_FullMethodName                     = "/temporal.server.api.adminservice.v1.AdminService/PauseTaskQueue"
+	AdminService_ResumeTaskQueue_FullMethodName                    = "/temporal.server.api.adminservice.v1.AdminService/ResumeTaskQueue"
+	AdminService_StartVisibilityReindex_FullMethodName             = "/temporal.server.api.adminservice.v1.AdminService/StartVisibilityReindex"
+	AdminService_DescribeVisibilityReindex_FullMethodName          = "/temporal.server.api.adminservice.v1.AdminService/DescribeVisibilityReindex"
+	AdminService_SaveVisibilityQuery_FullMethodName                = "/temporal.server.api.adminservice.v1.AdminService/SaveVisibilityQuery"
+	AdminService_DeleteSavedVisibilityQuery_FullMethodName         = "/temporal.server.api.adminservice.v1.AdminService/DeleteSavedVisibilityQuery"
+	AdminService_UndrainHistoryHost_FullMethodName                 = "/temporal.server.api.adminservice.v1.AdminService/UndrainHistoryHost"
 )
 
 // AdminServiceClient is the client API for AdminService service.
@@ -93,6 +103,8 @@ type AdminServiceClient interface {
 	DescribeMutableState(ctx context.Context, in *DescribeMutableStateRequest, opts ...grpc.CallOption) (*DescribeMutableStateResponse, error)
 	// DescribeHistoryHost returns information about the internal states of a history host
 	DescribeHistoryHost(ctx context.Context, in *DescribeHistoryHostRequest, opts ...grpc.CallOption) (*DescribeHistoryHostResponse, error)
+	// DrainHistoryHost makes a history host hand off all its shards to the other history hosts ahead of a shutdown.
+	DrainHistoryHost(ctx context.Context, in *DrainHistoryHostRequest, opts ...grpc.CallOption) (*DrainHistoryHostResponse, error)
 	GetShard(ctx context.Context, in *GetShardRequest, opts ...grpc.CallOption) (*GetShardResponse, error)
 	CloseShard(ctx context.Context, in *CloseShardRequest, opts ...grpc.CallOption) (*CloseShardResponse, error)
 	ListHistoryTasks(ctx context.Context, in *ListHistoryTasksRequest, opts ...grpc.CallOption) (*ListHistoryTasksResponse, error)
@@ -159,6 +171,27 @@ type AdminServiceClient interface {
 	CancelDLQJob(ctx context.Context, in *CancelDLQJobRequest, opts ...grpc.CallOption) (*CancelDLQJobResponse, error)
 	AddTasks(ctx context.Context, in *AddTasksRequest, opts ...grpc.CallOption) (*AddTasksResponse, error)
 	ListQueues(ctx context.Context, in *ListQueuesRequest, opts ...grpc.CallOption) (*ListQueuesResponse, error)
+	// StartHistoryShardCountMigration starts raising the number of history shards of the cluster.
+	StartHistoryShardCountMigration(ctx context.Context, in *StartHistoryShardCountMigrationRequest, opts ...grpc.CallOption) (*StartHistoryShardCountMigrationResponse, error)
+	// DescribeHistoryShardCountMigration returns the progress of the history shard count migration.
+	DescribeHistoryShardCountMigration(ctx context.Context, in *DescribeHistoryShardCountMigrationRequest, opts ...grpc.CallOption) (*DescribeHistoryShardCountMigrationResponse, error)
+	// PauseTaskQueue stops dispatching tasks of a task queue to pollers until it's resumed.
+	// Tasks added while the task queue is paused are kept in its backlog.
+	PauseTaskQueue(ctx context.Context, in *PauseTaskQueueRequest, opts ...grpc.CallOption) (*PauseTaskQueueResponse, error)
+	// ResumeTaskQueue resumes dispatching tasks of a paused task queue.
+	ResumeTaskQueue(ctx context.Context, in *ResumeTaskQueueRequest, opts ...grpc.CallOption) (*ResumeTaskQueueResponse, error)
+	// StartVisibilityReindex starts writing the visibility records of all existing executions to a visibility store.
+	StartVisibilityReindex(ctx context.Context, in *StartVisibilityReindexRequest, opts ...grpc.CallOption) (*StartVisibilityReindexResponse, error)
+	// DescribeVisibilityReindex returns the progress of the last visibility reindex.
+	DescribeVisibilityReindex(ctx context.Context, in *DescribeVisibilityReindexRequest, opts ...grpc.CallOption) (*DescribeVisibilityReindexResponse, error)
+	// SaveVisibilityQuery saves a visibility query in the namespace metadata, or replaces the query saved under the
+	// same name. Users run it by passing "@<name>" as the query of ListWorkflowExecutions, and it isn't checked
+	// against the namespace max visibility query cost.
+	SaveVisibilityQuery(ctx context.Context, in *SaveVisibilityQueryRequest, opts ...grpc.CallOption) (*SaveVisibilityQueryResponse, error)
+	// DeleteSavedVisibilityQuery removes a saved visibility query from the namespace metadata.
+	DeleteSavedVisibilityQuery(ctx context.Context, in *DeleteSavedVisibilityQueryRequest, opts ...grpc.CallOption) (*DeleteSavedVisibilityQueryResponse, error)
+	// UndrainHistoryHost stops the drain of a history host, which rejoins the membership ring and acquires shards again.
+	UndrainHistoryHost(ctx context.Context, in *UndrainHistoryHostRequest, opts ...grpc.CallOption) (*UndrainHistoryHostResponse, error)
 }
 
 type adminServiceClient struct {
@@ -205,6 +238,15 @@ func (c *adminServiceClient) DescribeHistoryHost(ctx context.Context, in *Descri
 	return out, nil
 }
 
+func (c *adminServiceClient) DrainHistoryHost(ctx context.Context, in *DrainHistoryHostRequest, opts ...grpc.CallOption) (*DrainHistoryHostResponse, error) {
+	out := new(DrainHistoryHostResponse)
+	err := c.cc.Invoke(ctx, AdminService_DrainHistoryHost_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *adminServiceClient) GetShard(ctx context.Context, in *GetShardRequest, opts ...grpc.CallOption) (*GetShardResponse, error) {
 	out := new(GetShardResponse)
 	err := c.cc.Invoke(ctx, AdminService_GetShard_FullMethodName, in, out, opts...)
@@ -524,6 +566,87 @@ func (c *adminServiceClient) ListQueues(ctx context.Context, in *ListQueuesReque
 	return out, nil
 }
 
+func (c *adminServiceClient) StartHistoryShardCountMigration(ctx context.Context, in *StartHistoryShardCountMigrationRequest, opts ...grpc.CallOption) (*StartHistoryShardCountMigrationResponse, error) {
+	out := new(StartHistoryShardCountMigrationResponse)
+	err := c.cc.Invoke(ctx, AdminService_StartHistoryShardCountMigration_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *adminServiceClient) DescribeHistoryShardCountMigration(ctx context.Context, in *DescribeHistoryShardCountMigrationRequest, opts ...grpc.CallOption) (*DescribeHistoryShardCountMigrationResponse, error) {
+	out := new(DescribeHistoryShardCountMigrationResponse)
+	err := c.cc.Invoke(ctx, AdminService_DescribeHistoryShardCountMigration_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *adminServiceClient) PauseTaskQueue(ctx context.Context, in *PauseTaskQueueRequest, opts ...grpc.CallOption) (*PauseTaskQueueResponse, error) {
+	out := new(PauseTaskQueueResponse)
+	err := c.cc.Invoke(ctx, AdminService_PauseTaskQueue_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *adminServiceClient) ResumeTaskQueue(ctx context.Context, in *ResumeTaskQueueRequest, opts ...grpc.CallOption) (*ResumeTaskQueueResponse, error) {
+	out := new(ResumeTaskQueueResponse)
+	err := c.cc.Invoke(ctx, AdminService_ResumeTaskQueue_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *adminServiceClient) StartVisibilityReindex(ctx context.Context, in *StartVisibilityReindexRequest, opts ...grpc.CallOption) (*StartVisibilityReindexResponse, error) {
+	out := new(StartVisibilityReindexResponse)
+	err := c.cc.Invoke(ctx, AdminService_StartVisibilityReindex_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *adminServiceClient) DescribeVisibilityReindex(ctx context.Context, in *DescribeVisibilityReindexRequest, opts ...grpc.CallOption) (*DescribeVisibilityReindexResponse, error) {
+	out := new(DescribeVisibilityReindexResponse)
+	err := c.cc.Invoke(ctx, AdminService_DescribeVisibilityReindex_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *adminServiceClient) SaveVisibilityQuery(ctx context.Context, in *SaveVisibilityQueryRequest, opts ...grpc.CallOption) (*SaveVisibilityQueryResponse, error) {
+	out := new(SaveVisibilityQueryResponse)
+	err := c.cc.Invoke(ctx, AdminService_SaveVisibilityQuery_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *adminServiceClient) DeleteSavedVisibilityQuery(ctx context.Context, in *DeleteSavedVisibilityQueryRequest, opts ...grpc.CallOption) (*DeleteSavedVisibilityQueryResponse, error) {
+	out := new(DeleteSavedVisibilityQueryResponse)
+	err := c.cc.Invoke(ctx, AdminService_DeleteSavedVisibilityQuery_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *adminServiceClient) UndrainHistoryHost(ctx context.Context, in *UndrainHistoryHostRequest, opts ...grpc.CallOption) (*UndrainHistoryHostResponse, error) {
+	out := new(UndrainHistoryHostResponse)
+	err := c.cc.Invoke(ctx, AdminService_UndrainHistoryHost_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // AdminServiceServer is the server API for AdminService service.
 // All implementations must embed UnimplementedAdminServiceServer
 // for forward compatibility
@@ -538,6 +661,8 @@ type AdminServiceServer interface {
 	DescribeMutableState(context.Context, *DescribeMutableStateRequest) (*DescribeMutableStateResponse, error)
 	// DescribeHistoryHost returns information about the internal states of a history host
 	DescribeHistoryHost(context.Context, *DescribeHistoryHostRequest) (*DescribeHistoryHostResponse, error)
+	// DrainHistoryHost makes a history host hand off all its shards to the other history hosts ahead of a shutdown.
+	DrainHistoryHost(context.Context, *DrainHistoryHostRequest) (*DrainHistoryHostResponse, error)
 	GetShard(context.Context, *GetShardRequest) (*GetShardResponse, error)
 	CloseShard(context.Context, *CloseShardRequest) (*CloseShardResponse, error)
 	ListHistoryTasks(context.Context, *ListHistoryTasksRequest) (*ListHistoryTasksResponse, error)
@@ -604,6 +729,27 @@ type AdminServiceServer interface {
 	CancelDLQJob(context.Context, *CancelDLQJobRequest) (*CancelDLQJobResponse, error)
 	AddTasks(context.Context, *AddTasksRequest) (*AddTasksResponse, error)
 	ListQueues(context.Context, *ListQueuesRequest) (*ListQueuesResponse, error)
+	// StartHistoryShardCountMigration starts raising the number of history shards of the cluster.
+	StartHistoryShardCountMigration(context.Context, *StartHistoryShardCountMigrationRequest) (*StartHistoryShardCountMigrationResponse, error)
+	// DescribeHistoryShardCountMigration returns the progress of the history shard count migration.
+	DescribeHistoryShardCountMigration(context.Context, *DescribeHistoryShardCountMigrationRequest) (*DescribeHistoryShardCountMigrationResponse, error)
+	// PauseTaskQueue stops dispatching tasks of a task queue to pollers until it's resumed.
+	// Tasks added while the task queue is paused are kept in its backlog.
+	PauseTaskQueue(context.Context, *PauseTaskQueueRequest) (*PauseTaskQueueResponse, error)
+	// ResumeTaskQueue resumes dispatching tasks of a paused task queue.
+	ResumeTaskQueue(context.Context, *ResumeTaskQueueRequest) (*ResumeTaskQueueResponse, error)
+	// StartVisibilityReindex starts writing the visibility records of all existing executions to a visibility store.
+	StartVisibilityReindex(context.Context, *StartVisibilityReindexRequest) (*StartVisibilityReindexResponse, error)
+	// DescribeVisibilityReindex returns the progress of the last visibility reindex.
+	DescribeVisibilityReindex(context.Context, *DescribeVisibilityReindexRequest) (*DescribeVisibilityReindexResponse, error)
+	// SaveVisibilityQuery saves a visibility query in the namespace metadata, or replaces the query saved under the
+	// same name. Users run it by passing "@<name>" as the query of ListWorkflowExecutions, and it isn't checked
+	// against the namespace max visibility query cost.
+	SaveVisibilityQuery(context.Context, *SaveVisibilityQueryRequest) (*SaveVisibilityQueryResponse, error)
+	// DeleteSavedVisibilityQuery removes a saved visibility query from the namespace metadata.
+	DeleteSavedVisibilityQuery(context.Context, *DeleteSavedVisibilityQueryRequest) (*DeleteSavedVisibilityQueryResponse, error)
+	// UndrainHistoryHost stops the drain of a history host, which rejoins the membership ring and acquires shards again.
+	UndrainHistoryHost(context.Context, *UndrainHistoryHostRequest) (*UndrainHistoryHostResponse, error)
 	mustEmbedUnimplementedAdminServiceServer()
 }
 
@@ -623,6 +769,9 @@ func (UnimplementedAdminServiceServer) DescribeMutableState(context.Context, *De
 func (UnimplementedAdminServiceServer) DescribeHistoryHost(context.Context, *DescribeHistoryHostRequest) (*DescribeHistoryHostResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method DescribeHistoryHost not implemented")
 }
+func (UnimplementedAdminServiceServer) DrainHistoryHost(context.Context, *DrainHistoryHostRequest) (*DrainHistoryHostResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DrainHistoryHost not implemented")
+}
 func (UnimplementedAdminServiceServer) GetShard(context.Context, *GetShardRequest) (*GetShardResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method GetShard not implemented")
 }
@@ -722,6 +871,33 @@ func (UnimplementedAdminServiceServer) AddTasks(context.Context, *AddTasksReques
 func (UnimplementedAdminServiceServer) ListQueues(context.Context, *ListQueuesRequest) (*ListQueuesResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method ListQueues not implemented")
 }
+func (UnimplementedAdminServiceServer) StartHistoryShardCountMigration(context.Context, *StartHistoryShardCountMigrationRequest) (*StartHistoryShardCountMigrationResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method StartHistoryShardCountMigration not implemented")
+}
+func (UnimplementedAdminServiceServer) DescribeHistoryShardCountMigration(context.Context, *DescribeHistoryShardCountMigrationRequest) (*DescribeHistoryShardCountMigrationResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DescribeHistoryShardCountMigration not implemented")
+}
+func (UnimplementedAdminServiceServer) PauseTaskQueue(context.Context, *PauseTaskQueueRequest) (*PauseTaskQueueResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method PauseTaskQueue not implemented")
+}
+func (UnimplementedAdminServiceServer) ResumeTaskQueue(context.Context, *ResumeTaskQueueRequest) (*ResumeTaskQueueResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ResumeTaskQueue not implemented")
+}
+func (UnimplementedAdminServiceServer) StartVisibilityReindex(context.Context, *StartVisibilityReindexRequest) (*StartVisibilityReindexResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method StartVisibilityReindex not implemented")
+}
+func (UnimplementedAdminServiceServer) DescribeVisibilityReindex(context.Context, *DescribeVisibilityReindexRequest) (*DescribeVisibilityReindexResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DescribeVisibilityReindex not implemented")
+}
+func (UnimplementedAdminServiceServer) SaveVisibilityQuery(context.Context, *SaveVisibilityQueryRequest) (*SaveVisibilityQueryResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SaveVisibilityQuery not implemented")
+}
+func (UnimplementedAdminServiceServer) DeleteSavedVisibilityQuery(context.Context, *DeleteSavedVisibilityQueryRequest) (*DeleteSavedVisibilityQueryResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteSavedVisibilityQuery not implemented")
+}
+func (UnimplementedAdminServiceServer) UndrainHistoryHost(context.Context, *UndrainHistoryHostRequest) (*UndrainHistoryHostResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UndrainHistoryHost not implemented")
+}
 func (UnimplementedAdminServiceServer) mustEmbedUnimplementedAdminServiceServer() {}
 
 // UnsafeAdminServiceServer may be embedded to opt out of forward compatibility for this service.
@@ -807,6 +983,24 @@ func _AdminService_DescribeHistoryHost_Handler(srv interface{}, ctx context.Cont
 	return interceptor(ctx, in, info, handler)
 }
 
+func _AdminService_DrainHistoryHost_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DrainHistoryHostRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminServiceServer).DrainHistoryHost(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AdminService_DrainHistoryHost_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminServiceServer).DrainHistoryHost(ctx, req.(*DrainHistoryHostRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _AdminService_GetShard_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(GetShardRequest)
 	if err := dec(in); err != nil {
@@ -1409,6 +1603,168 @@ func _AdminService_ListQueues_Handler(srv interface{}, ctx context.Context, dec
 	return interceptor(ctx, in, info, handler)
 }
 
+func _AdminService_StartHistoryShardCountMigration_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(StartHistoryShardCountMigrationRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminServiceServer).StartHistoryShardCountMigration(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AdminService_StartHistoryShardCountMigration_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminServiceServer).StartHistoryShardCountMigration(ctx, req.(*StartHistoryShardCountMigrationRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AdminService_DescribeHistoryShardCountMigration_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DescribeHistoryShardCountMigrationRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminServiceServer).DescribeHistoryShardCountMigration(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AdminService_DescribeHistoryShardCountMigration_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminServiceServer).DescribeHistoryShardCountMigration(ctx, req.(*DescribeHistoryShardCountMigrationRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AdminService_PauseTaskQueue_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PauseTaskQueueRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminServiceServer).PauseTaskQueue(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AdminService_PauseTaskQueue_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminServiceServer).PauseTaskQueue(ctx, req.(*PauseTaskQueueRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AdminService_ResumeTaskQueue_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ResumeTaskQueueRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminServiceServer).ResumeTaskQueue(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AdminService_ResumeTaskQueue_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminServiceServer).ResumeTaskQueue(ctx, req.(*ResumeTaskQueueRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AdminService_StartVisibilityReindex_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(StartVisibilityReindexRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminServiceServer).StartVisibilityReindex(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AdminService_StartVisibilityReindex_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminServiceServer).StartVisibilityReindex(ctx, req.(*StartVisibilityReindexRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AdminService_DescribeVisibilityReindex_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DescribeVisibilityReindexRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminServiceServer).DescribeVisibilityReindex(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AdminService_DescribeVisibilityReindex_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminServiceServer).DescribeVisibilityReindex(ctx, req.(*DescribeVisibilityReindexRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AdminService_SaveVisibilityQuery_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SaveVisibilityQueryRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminServiceServer).SaveVisibilityQuery(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AdminService_SaveVisibilityQuery_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminServiceServer).SaveVisibilityQuery(ctx, req.(*SaveVisibilityQueryRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AdminService_DeleteSavedVisibilityQuery_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteSavedVisibilityQueryRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminServiceServer).DeleteSavedVisibilityQuery(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AdminService_DeleteSavedVisibilityQuery_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminServiceServer).DeleteSavedVisibilityQuery(ctx, req.(*DeleteSavedVisibilityQueryRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AdminService_UndrainHistoryHost_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UndrainHistoryHostRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminServiceServer).UndrainHistoryHost(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AdminService_UndrainHistoryHost_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminServiceServer).UndrainHistoryHost(ctx, req.(*UndrainHistoryHostRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // AdminService_ServiceDesc is the grpc.ServiceDesc for AdminService service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -1432,6 +1788,10 @@ var AdminService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "DescribeHistoryHost",
 			Handler:    _AdminService_DescribeHistoryHost_Handler,
 		},
+		{
+			MethodName: "DrainHistoryHost",
+			Handler:    _AdminService_DrainHistoryHost_Handler,
+		},
 		{
 			MethodName: "GetShard",
 			Handler:    _AdminService_GetShard_Handler,
@@ -1560,6 +1920,42 @@ var AdminService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "ListQueues",
 			Handler:    _AdminService_ListQueues_Handler,
 		},
+		{
+			MethodName: "StartHistoryShardCountMigration",
+			Handler:    _AdminService_StartHistoryShardCountMigration_Handler,
+		},
+		{
+			MethodName: "DescribeHistoryShardCountMigration",
+			Handler:    _AdminService_DescribeHistoryShardCountMigration_Handler,
+		},
+		{
+			MethodName: "PauseTaskQueue",
+			Handler:    _AdminService_PauseTaskQueue_Handler,
+		},
+		{
+			MethodName: "ResumeTaskQueue",
+			Handler:    _AdminService_ResumeTaskQueue_Handler,
+		},
+		{
+			MethodName: "StartVisibilityReindex",
+			Handler:    _AdminService_StartVisibilityReindex_Handler,
+		},
+		{
+			MethodName: "DescribeVisibilityReindex",
+			Handler:    _AdminService_DescribeVisibilityReindex_Handler,
+		},
+		{
+			MethodName: "SaveVisibilityQuery",
+			Handler:    _AdminService_SaveVisibilityQuery_Handler,
+		},
+		{
+			MethodName: "DeleteSavedVisibilityQuery",
+			Handler:    _AdminService_DeleteSavedVisibilityQuery_Handler,
+		},
+		{
+			MethodName: "UndrainHistoryHost",
+			Handler:    _AdminService_UndrainHistoryHost_Handler,
+		},
 	},
 	Streams: []grpc.StreamDesc{
 		{
@@ -56,6 +56,46 @@ func NewMockAdminServiceClient(ctrl *gomock.Controller) *MockAdminServiceClient
 	return mock
 }
 
+// DeleteSavedVisibilityQuery mocks base method.
+func (m *MockAdminServiceClient) DeleteSavedVisibilityQuery(ctx context.Context, in *adminservice.DeleteSavedVisibilityQueryRequest, opts ...grpc.CallOption) (*adminservice.DeleteSavedVisibilityQueryResponse, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{ctx, in}
+	for _, a := range opts {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "DeleteSavedVisibilityQuery", varargs...)
+	ret0, _ := ret[0].(*adminservice.DeleteSavedVisibilityQueryResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteSavedVisibilityQuery indicates an expected call of DeleteSavedVisibilityQuery.
+func (mr *MockAdminServiceClientMockRecorder) DeleteSavedVisibilityQuery(ctx, in interface{}, opts ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{ctx, in}, opts...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteSavedVisibilityQuery", reflect.TypeOf((*MockAdminServiceClient)(nil).DeleteSavedVisibilityQuery), varargs...)
+}
+
+// DescribeVisibilityReindex mocks base method.
+func (m *MockAdminServiceClient) DescribeVisibilityReindex(ctx context.Context, in *adminservice.DescribeVisibilityReindexRequest, opts ...grpc.CallOption) (*adminservice.DescribeVisibilityReindexResponse, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{ctx, in}
+	for _, a := range opts {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "DescribeVisibilityReindex", varargs...)
+	ret0, _ := ret[0].(*adminservice.DescribeVisibilityReindexResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DescribeVisibilityReindex indicates an expected call of DescribeVisibilityReindex.
+func (mr *MockAdminServiceClientMockRecorder) DescribeVisibilityReindex(ctx, in interface{}, opts ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{ctx, in}, opts...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DescribeVisibilityReindex", reflect.TypeOf((*MockAdminServiceClient)(nil).DescribeVisibilityReindex), varargs...)
+}
+
 // EXPECT returns an object that allows the caller to indicate expected use.
 func (m *MockAdminServiceClient) EXPECT() *MockAdminServiceClientMockRecorder {
 	return m.recorder
@@ -241,6 +281,26 @@ func (mr *MockAdminServiceClientMockRecorder) DescribeHistoryHost(ctx, in interf
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DescribeHistoryHost", reflect.TypeOf((*MockAdminServiceClient)(nil).DescribeHistoryHost), varargs...)
 }
 
+// DescribeHistoryShardCountMigration mocks base method.
+func (m *MockAdminServiceClient) DescribeHistoryShardCountMigration(ctx context.Context, in *adminservice.DescribeHistoryShardCountMigrationRequest, opts ...grpc.CallOption) (*adminservice.DescribeHistoryShardCountMigrationResponse, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{ctx, in}
+	for _, a := range opts {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "DescribeHistoryShardCountMigration", varargs...)
+	ret0, _ := ret[0].(*adminservice.DescribeHistoryShardCountMigrationResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DescribeHistoryShardCountMigration indicates an expected call of DescribeHistoryShardCountMigration.
+func (mr *MockAdminServiceClientMockRecorder) DescribeHistoryShardCountMigration(ctx, in interface{}, opts ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{ctx, in}, opts...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DescribeHistoryShardCountMigration", reflect.TypeOf((*MockAdminServiceClient)(nil).DescribeHistoryShardCountMigration), varargs...)
+}
+
 // DescribeMutableState mocks base method.
 func (m *MockAdminServiceClient) DescribeMutableState(ctx context.Context, in *adminservice.DescribeMutableStateRequest, opts ...grpc.CallOption) (*adminservice.DescribeMutableStateResponse, error) {
 	m.ctrl.T.Helper()
@@ -261,6 +321,26 @@ func (mr *MockAdminServiceClientMockRecorder) DescribeMutableState(ctx, in inter
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DescribeMutableState", reflect.TypeOf((*MockAdminServiceClient)(nil).DescribeMutableState), varargs...)
 }
 
+// DrainHistoryHost mocks base method.
+func (m *MockAdminServiceClient) DrainHistoryHost(ctx context.Context, in *adminservice.DrainHistoryHostRequest, opts ...grpc.CallOption) (*adminservice.DrainHistoryHostResponse, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{ctx, in}
+	for _, a := range opts {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "DrainHistoryHost", varargs...)
+	ret0, _ := ret[0].(*adminservice.DrainHistoryHostResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DrainHistoryHost indicates an expected call of DrainHistoryHost.
+func (mr *MockAdminServiceClientMockRecorder) DrainHistoryHost(ctx, in interface{}, opts ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{ctx, in}, opts...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DrainHistoryHost", reflect.TypeOf((*MockAdminServiceClient)(nil).DrainHistoryHost), varargs...)
+}
+
 // GetDLQMessages mocks base method.
 func (m *MockAdminServiceClient) GetDLQMessages(ctx context.Context, in *adminservice.GetDLQMessagesRequest, opts ...grpc.CallOption) (*adminservice.GetDLQMessagesResponse, error) {
 	m.ctrl.T.Helper()
@@ -601,6 +681,26 @@ func (mr *MockAdminServiceClientMockRecorder) MergeDLQTasks(ctx, in interface{},
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MergeDLQTasks", reflect.TypeOf((*MockAdminServiceClient)(nil).MergeDLQTasks), varargs...)
 }
 
+// PauseTaskQueue mocks base method.
+func (m *MockAdminServiceClient) PauseTaskQueue(ctx context.Context, in *adminservice.PauseTaskQueueRequest, opts ...grpc.CallOption) (*adminservice.PauseTaskQueueResponse, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{ctx, in}
+	for _, a := range opts {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "PauseTaskQueue", varargs...)
+	ret0, _ := ret[0].(*adminservice.PauseTaskQueueResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PauseTaskQueue indicates an expected call of PauseTaskQueue.
+func (mr *MockAdminServiceClientMockRecorder) PauseTaskQueue(ctx, in interface{}, opts ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{ctx, in}, opts...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PauseTaskQueue", reflect.TypeOf((*MockAdminServiceClient)(nil).PauseTaskQueue), varargs...)
+}
+
 // PurgeDLQMessages mocks base method.
 func (m *MockAdminServiceClient) PurgeDLQMessages(ctx context.Context, in *adminservice.PurgeDLQMessagesRequest, opts ...grpc.CallOption) (*adminservice.PurgeDLQMessagesResponse, error) {
 	m.ctrl.T.Helper()
@@ -781,6 +881,86 @@ func (mr *MockAdminServiceClientMockRecorder) ResendReplicationTasks(ctx, in int
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ResendReplicationTasks", reflect.TypeOf((*MockAdminServiceClient)(nil).ResendReplicationTasks), varargs...)
 }
 
+// ResumeTaskQueue mocks base method.
+func (m *MockAdminServiceClient) ResumeTaskQueue(ctx context.Context, in *adminservice.ResumeTaskQueueRequest, opts ...grpc.CallOption) (*adminservice.ResumeTaskQueueResponse, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{ctx, in}
+	for _, a := range opts {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "ResumeTaskQueue", varargs...)
+	ret0, _ := ret[0].(*adminservice.ResumeTaskQueueResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ResumeTaskQueue indicates an expected call of ResumeTaskQueue.
+func (mr *MockAdminServiceClientMockRecorder) ResumeTaskQueue(ctx, in interface{}, opts ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{ctx, in}, opts...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ResumeTaskQueue", reflect.TypeOf((*MockAdminServiceClient)(nil).ResumeTaskQueue), varargs...)
+}
+
+// SaveVisibilityQuery mocks base method.
+func (m *MockAdminServiceClient) SaveVisibilityQuery(ctx context.Context, in *adminservice.SaveVisibilityQueryRequest, opts ...grpc.CallOption) (*adminservice.SaveVisibilityQueryResponse, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{ctx, in}
+	for _, a := range opts {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "SaveVisibilityQuery", varargs...)
+	ret0, _ := ret[0].(*adminservice.SaveVisibilityQueryResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SaveVisibilityQuery indicates an expected call of SaveVisibilityQuery.
+func (mr *MockAdminServiceClientMockRecorder) SaveVisibilityQuery(ctx, in interface{}, opts ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{ctx, in}, opts...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveVisibilityQuery", reflect.TypeOf((*MockAdminServiceClient)(nil).SaveVisibilityQuery), varargs...)
+}
+
+// StartHistoryShardCountMigration mocks base method.
+func (m *MockAdminServiceClient) StartHistoryShardCountMigration(ctx context.Context, in *adminservice.StartHistoryShardCountMigrationRequest, opts ...grpc.CallOption) (*adminservice.StartHistoryShardCountMigrationResponse, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{ctx, in}
+	for _, a := range opts {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "StartHistoryShardCountMigration", varargs...)
+	ret0, _ := ret[0].(*adminservice.StartHistoryShardCountMigrationResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// StartHistoryShardCountMigration indicates an expected call of StartHistoryShardCountMigration.
+func (mr *MockAdminServiceClientMockRecorder) StartHistoryShardCountMigration(ctx, in interface{}, opts ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{ctx, in}, opts...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "StartHistoryShardCountMigration", reflect.TypeOf((*MockAdminServiceClient)(nil).StartHistoryShardCountMigration), varargs...)
+}
+
+// StartVisibilityReindex mocks base method.
+func (m *MockAdminServiceClient) StartVisibilityReindex(ctx context.Context, in *adminservice.StartVisibilityReindexRequest, opts ...grpc.CallOption) (*adminservice.StartVisibilityReindexResponse, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{ctx, in}
+	for _, a := range opts {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "StartVisibilityReindex", varargs...)
+	ret0, _ := ret[0].(*adminservice.StartVisibilityReindexResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// StartVisibilityReindex indicates an expected call of StartVisibilityReindex.
+func (mr *MockAdminServiceClientMockRecorder) StartVisibilityReindex(ctx, in interface{}, opts ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{ctx, in}, opts...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "StartVisibilityReindex", reflect.TypeOf((*MockAdminServiceClient)(nil).StartVisibilityReindex), varargs...)
+}
+
 // StreamWorkflowReplicationMessages mocks base method.
 func (m *MockAdminServiceClient) StreamWorkflowReplicationMessages(ctx context.Context, opts ...grpc.CallOption) (adminservice.AdminService_StreamWorkflowReplicationMessagesClient, error) {
 	m.ctrl.T.Helper()
@@ -801,6 +981,26 @@ func (mr *MockAdminServiceClientMockRecorder) StreamWorkflowReplicationMessages(
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "StreamWorkflowReplicationMessages", reflect.TypeOf((*MockAdminServiceClient)(nil).StreamWorkflowReplicationMessages), varargs...)
 }
 
+// UndrainHistoryHost mocks base method.
+func (m *MockAdminServiceClient) UndrainHistoryHost(ctx context.Context, in *adminservice.UndrainHistoryHostRequest, opts ...grpc.CallOption) (*adminservice.UndrainHistoryHostResponse, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{ctx, in}
+	for _, a := range opts {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "UndrainHistoryHost", varargs...)
+	ret0, _ := ret[0].(*adminservice.UndrainHistoryHostResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UndrainHistoryHost indicates an expected call of UndrainHistoryHost.
+func (mr *MockAdminServiceClientMockRecorder) UndrainHistoryHost(ctx, in interface{}, opts ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{ctx, in}, opts...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UndrainHistoryHost", reflect.TypeOf((*MockAdminServiceClient)(nil).UndrainHistoryHost), varargs...)
+}
+
 // MockAdminService_StreamWorkflowReplicationMessagesClient is a mock of AdminService_StreamWorkflowReplicationMessagesClient interface.
 type MockAdminService_StreamWorkflowReplicationMessagesClient struct {
 	ctrl     *gomock.Controller
@@ -956,6 +1156,36 @@ func NewMockAdminServiceServer(ctrl *gomock.Controller) *MockAdminServiceServer
 	return mock
 }
 
+// DeleteSavedVisibilityQuery mocks base method.
+func (m *MockAdminServiceServer) DeleteSavedVisibilityQuery(arg0 context.Context, arg1 *adminservice.DeleteSavedVisibilityQueryRequest) (*adminservice.DeleteSavedVisibilityQueryResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteSavedVisibilityQuery", arg0, arg1)
+	ret0, _ := ret[0].(*adminservice.DeleteSavedVisibilityQueryResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteSavedVisibilityQuery indicates an expected call of DeleteSavedVisibilityQuery.
+func (mr *MockAdminServiceServerMockRecorder) DeleteSavedVisibilityQuery(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteSavedVisibilityQuery", reflect.TypeOf((*MockAdminServiceServer)(nil).DeleteSavedVisibilityQuery), arg0, arg1)
+}
+
+// DescribeVisibilityReindex mocks base method.
+func (m *MockAdminServiceServer) DescribeVisibilityReindex(arg0 context.Context, arg1 *adminservice.DescribeVisibilityReindexRequest) (*adminservice.DescribeVisibilityReindexResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DescribeVisibilityReindex", arg0, arg1)
+	ret0, _ := ret[0].(*adminservice.DescribeVisibilityReindexResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DescribeVisibilityReindex indicates an expected call of DescribeVisibilityReindex.
+func (mr *MockAdminServiceServerMockRecorder) DescribeVisibilityReindex(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DescribeVisibilityReindex", reflect.TypeOf((*MockAdminServiceServer)(nil).DescribeVisibilityReindex), arg0, arg1)
+}
+
 // EXPECT returns an object that allows the caller to indicate expected use.
 func (m *MockAdminServiceServer) EXPECT() *MockAdminServiceServerMockRecorder {
 	return m.recorder
@@ -1096,6 +1326,21 @@ func (mr *MockAdminServiceServerMockRecorder) DescribeHistoryHost(arg0, arg1 int
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DescribeHistoryHost", reflect.TypeOf((*MockAdminServiceServer)(nil).DescribeHistoryHost), arg0, arg1)
 }
 
+// DescribeHistoryShardCountMigration mocks base method.
+func (m *MockAdminServiceServer) DescribeHistoryShardCountMigration(arg0 context.Context, arg1 *adminservice.DescribeHistoryShardCountMigrationRequest) (*adminservice.DescribeHistoryShardCountMigrationResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DescribeHistoryShardCountMigration", arg0, arg1)
+	ret0, _ := ret[0].(*adminservice.DescribeHistoryShardCountMigrationResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DescribeHistoryShardCountMigration indicates an expected call of DescribeHistoryShardCountMigration.
+func (mr *MockAdminServiceServerMockRecorder) DescribeHistoryShardCountMigration(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DescribeHistoryShardCountMigration", reflect.TypeOf((*MockAdminServiceServer)(nil).DescribeHistoryShardCountMigration), arg0, arg1)
+}
+
 // DescribeMutableState mocks base method.
 func (m *MockAdminServiceServer) DescribeMutableState(arg0 context.Context, arg1 *adminservice.DescribeMutableStateRequest) (*adminservice.DescribeMutableStateResponse, error) {
 	m.ctrl.T.Helper()
@@ -1111,6 +1356,21 @@ func (mr *MockAdminServiceServerMockRecorder) DescribeMutableState(arg0, arg1 in
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DescribeMutableState", reflect.TypeOf((*MockAdminServiceServer)(nil).DescribeMutableState), arg0, arg1)
 }
 
+// DrainHistoryHost mocks base method.
+func (m *MockAdminServiceServer) DrainHistoryHost(arg0 context.Context, arg1 *adminservice.DrainHistoryHostRequest) (*adminservice.DrainHistoryHostResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DrainHistoryHost", arg0, arg1)
+	ret0, _ := ret[0].(*adminservice.DrainHistoryHostResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DrainHistoryHost indicates an expected call of DrainHistoryHost.
+func (mr *MockAdminServiceServerMockRecorder) DrainHistoryHost(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DrainHistoryHost", reflect.TypeOf((*MockAdminServiceServer)(nil).DrainHistoryHost), arg0, arg1)
+}
+
 // GetDLQMessages mocks base method.
 func (m *MockAdminServiceServer) GetDLQMessages(arg0 context.Context, arg1 *adminservice.GetDLQMessagesRequest) (*adminservice.GetDLQMessagesResponse, error) {
 	m.ctrl.T.Helper()
@@ -1366,6 +1626,21 @@ func (mr *MockAdminServiceServerMockRecorder) MergeDLQTasks(arg0, arg1 interface
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MergeDLQTasks", reflect.TypeOf((*MockAdminServiceServer)(nil).MergeDLQTasks), arg0, arg1)
 }
 
+// PauseTaskQueue mocks base method.
+func (m *MockAdminServiceServer) PauseTaskQueue(arg0 context.Context, arg1 *adminservice.PauseTaskQueueRequest) (*adminservice.PauseTaskQueueResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PauseTaskQueue", arg0, arg1)
+	ret0, _ := ret[0].(*adminservice.PauseTaskQueueResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PauseTaskQueue indicates an expected call of PauseTaskQueue.
+func (mr *MockAdminServiceServerMockRecorder) PauseTaskQueue(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PauseTaskQueue", reflect.TypeOf((*MockAdminServiceServer)(nil).PauseTaskQueue), arg0, arg1)
+}
+
 // PurgeDLQMessages mocks base method.
 func (m *MockAdminServiceServer) PurgeDLQMessages(arg0 context.Context, arg1 *adminservice.PurgeDLQMessagesRequest) (*adminservice.PurgeDLQMessagesResponse, error) {
 	m.ctrl.T.Helper()
@@ -1501,6 +1776,66 @@ func (mr *MockAdminServiceServerMockRecorder) ResendReplicationTasks(arg0, arg1
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ResendReplicationTasks", reflect.TypeOf((*MockAdminServiceServer)(nil).ResendReplicationTasks), arg0, arg1)
 }
 
+// ResumeTaskQueue mocks base method.
+func (m *MockAdminServiceServer) ResumeTaskQueue(arg0 context.Context, arg1 *adminservice.ResumeTaskQueueRequest) (*adminservice.ResumeTaskQueueResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ResumeTaskQueue", arg0, arg1)
+	ret0, _ := ret[0].(*adminservice.ResumeTaskQueueResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ResumeTaskQueue indicates an expected call of ResumeTaskQueue.
+func (mr *MockAdminServiceServerMockRecorder) ResumeTaskQueue(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ResumeTaskQueue", reflect.TypeOf((*MockAdminServiceServer)(nil).ResumeTaskQueue), arg0, arg1)
+}
+
+// SaveVisibilityQuery mocks base method.
+func (m *MockAdminServiceServer) SaveVisibilityQuery(arg0 context.Context, arg1 *adminservice.SaveVisibilityQueryRequest) (*adminservice.SaveVisibilityQueryResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SaveVisibilityQuery", arg0, arg1)
+	ret0, _ := ret[0].(*adminservice.SaveVisibilityQueryResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SaveVisibilityQuery indicates an expected call of SaveVisibilityQuery.
+func (mr *MockAdminServiceServerMockRecorder) SaveVisibilityQuery(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveVisibilityQuery", reflect.TypeOf((*MockAdminServiceServer)(nil).SaveVisibilityQuery), arg0, arg1)
+}
+
+// StartHistoryShardCountMigration mocks base method.
+func (m *MockAdminServiceServer) StartHistoryShardCountMigration(arg0 context.Context, arg1 *adminservice.StartHistoryShardCountMigrationRequest) (*adminservice.StartHistoryShardCountMigrationResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "StartHistoryShardCountMigration", arg0, arg1)
+	ret0, _ := ret[0].(*adminservice.StartHistoryShardCountMigrationResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// StartHistoryShardCountMigration indicates an expected call of StartHistoryShardCountMigration.
+func (mr *MockAdminServiceServerMockRecorder) StartHistoryShardCountMigration(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "StartHistoryShardCountMigration", reflect.TypeOf((*MockAdminServiceServer)(nil).StartHistoryShardCountMigration), arg0, arg1)
+}
+
+// StartVisibilityReindex mocks base method.
+func (m *MockAdminServiceServer) StartVisibilityReindex(arg0 context.Context, arg1 *adminservice.StartVisibilityReindexRequest) (*adminservice.StartVisibilityReindexResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "StartVisibilityReindex", arg0, arg1)
+	ret0, _ := ret[0].(*adminservice.StartVisibilityReindexResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// StartVisibilityReindex indicates an expected call of StartVisibilityReindex.
+func (mr *MockAdminServiceServerMockRecorder) StartVisibilityReindex(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "StartVisibilityReindex", reflect.TypeOf((*MockAdminServiceServer)(nil).StartVisibilityReindex), arg0, arg1)
+}
+
 // StreamWorkflowReplicationMessages mocks base method.
 func (m *MockAdminServiceServer) StreamWorkflowReplicationMessages(arg0 adminservice.AdminService_StreamWorkflowReplicationMessagesServer) error {
 	m.ctrl.T.Helper()
@@ -1515,6 +1850,21 @@ func (mr *MockAdminServiceServerMockRecorder) StreamWorkflowReplicationMessages(
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "StreamWorkflowReplicationMessages", reflect.TypeOf((*MockAdminServiceServer)(nil).StreamWorkflowReplicationMessages), arg0)
 }
 
+// UndrainHistoryHost mocks base method.
+func (m *MockAdminServiceServer) UndrainHistoryHost(arg0 context.Context, arg1 *adminservice.UndrainHistoryHostRequest) (*adminservice.UndrainHistoryHostResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UndrainHistoryHost", arg0, arg1)
+	ret0, _ := ret[0].(*adminservice.UndrainHistoryHostResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UndrainHistoryHost indicates an expected call of UndrainHistoryHost.
+func (mr *MockAdminServiceServerMockRecorder) UndrainHistoryHost(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UndrainHistoryHost", reflect.TypeOf((*MockAdminServiceServer)(nil).UndrainHistoryHost), arg0, arg1)
+}
+
 // mustEmbedUnimplementedAdminServiceServer mocks base method.
 func (m *MockAdminServiceServer) mustEmbedUnimplementedAdminServiceServer() {
 	m.ctrl.T.Helper()
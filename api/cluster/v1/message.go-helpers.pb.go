@@ -140,6 +140,43 @@ func (this *MembershipInfo) Equal(that interface{}) bool {
 	return proto.Equal(this, that1)
 }
 
+// Marshal an object of type HistoryHostDrainInfo to the protobuf v3 wire format
+func (val *HistoryHostDrainInfo) Marshal() ([]byte, error) {
+	return proto.Marshal(val)
+}
+
+// Unmarshal an object of type HistoryHostDrainInfo from the protobuf v3 wire format
+func (val *HistoryHostDrainInfo) Unmarshal(buf []byte) error {
+	return proto.Unmarshal(buf, val)
+}
+
+// Size returns the size of the object, in bytes, once serialized
+func (val *HistoryHostDrainInfo) Size() int {
+	return proto.Size(val)
+}
+
+// Equal returns whether two HistoryHostDrainInfo values are equivalent by recursively
+// comparing the message's fields.
+// For more information see the documentation for
+// https://pkg.go.dev/google.golang.org/protobuf/proto#Equal
+func (this *HistoryHostDrainInfo) Equal(that interface{}) bool {
+	if that == nil {
+		return this == nil
+	}
+
+	var that1 *HistoryHostDrainInfo
+	switch t := that.(type) {
+	case *HistoryHostDrainInfo:
+		that1 = t
+	case HistoryHostDrainInfo:
+		that1 = &t
+	default:
+		return false
+	}
+
+	return proto.Equal(this, that1)
+}
+
 // Marshal an object of type ClusterMember to the protobuf v3 wire format
 func (val *ClusterMember) Marshal() ([]byte, error) {
 	return proto.Marshal(val)
@@ -49,3 +49,43 @@ func ClusterMemberRoleFromString(s string) (ClusterMemberRole, error) {
 	}
 	return ClusterMemberRole(0), fmt.Errorf("%s is not a valid ClusterMemberRole", s)
 }
+
+var (
+	HistoryShardCountMigrationState_shorthandValue = map[string]int32{
+		"Unspecified": 0,
+		"Copying":     1,
+		"Cutover":     2,
+		"Completed":   3,
+	}
+)
+
+// HistoryShardCountMigrationStateFromString parses a HistoryShardCountMigrationState value from  either the protojson
+// canonical SCREAMING_CASE enum or the traditional temporal PascalCase enum to HistoryShardCountMigrationState
+func HistoryShardCountMigrationStateFromString(s string) (HistoryShardCountMigrationState, error) {
+	if v, ok := HistoryShardCountMigrationState_value[s]; ok {
+		return HistoryShardCountMigrationState(v), nil
+	} else if v, ok := HistoryShardCountMigrationState_shorthandValue[s]; ok {
+		return HistoryShardCountMigrationState(v), nil
+	}
+	return HistoryShardCountMigrationState(0), fmt.Errorf("%s is not a valid HistoryShardCountMigrationState", s)
+}
+
+var (
+	HistoryHostDrainState_shorthandValue = map[string]int32{
+		"Unspecified": 0,
+		"Draining":    1,
+		"Drained":     2,
+		"Failed":      3,
+	}
+)
+
+// HistoryHostDrainStateFromString parses a HistoryHostDrainState value from  either the protojson
+// canonical SCREAMING_CASE enum or the traditional temporal PascalCase enum to HistoryHostDrainState
+func HistoryHostDrainStateFromString(s string) (HistoryHostDrainState, error) {
+	if v, ok := HistoryHostDrainState_value[s]; ok {
+		return HistoryHostDrainState(v), nil
+	} else if v, ok := HistoryHostDrainState_shorthandValue[s]; ok {
+		return HistoryHostDrainState(v), nil
+	}
+	return HistoryHostDrainState(0), fmt.Errorf("%s is not a valid HistoryHostDrainState", s)
+}
@@ -111,6 +111,142 @@ func (ClusterMemberRole) EnumDescriptor() ([]byte, []int) {
 	return file_temporal_server_api_enums_v1_cluster_proto_rawDescGZIP(), []int{0}
 }
 
+type HistoryShardCountMigrationState int32
+
+const (
+	HISTORY_SHARD_COUNT_MIGRATION_STATE_UNSPECIFIED HistoryShardCountMigrationState = 0
+	// Executions are being copied online to the shards they map to under the target shard count.
+	HISTORY_SHARD_COUNT_MIGRATION_STATE_COPYING HistoryShardCountMigrationState = 1
+	// Copy is done and the persisted shard count has been switched. Source shards are finalized
+	// by history hosts running with the target shard count as they acquire them.
+	HISTORY_SHARD_COUNT_MIGRATION_STATE_CUTOVER   HistoryShardCountMigrationState = 2
+	HISTORY_SHARD_COUNT_MIGRATION_STATE_COMPLETED HistoryShardCountMigrationState = 3
+)
+
+// Enum value maps for HistoryShardCountMigrationState.
+var (
+	HistoryShardCountMigrationState_name = map[int32]string{
+		0: "HISTORY_SHARD_COUNT_MIGRATION_STATE_UNSPECIFIED",
+		1: "HISTORY_SHARD_COUNT_MIGRATION_STATE_COPYING",
+		2: "HISTORY_SHARD_COUNT_MIGRATION_STATE_CUTOVER",
+		3: "HISTORY_SHARD_COUNT_MIGRATION_STATE_COMPLETED",
+	}
+	HistoryShardCountMigrationState_value = map[string]int32{
+		"HISTORY_SHARD_COUNT_MIGRATION_STATE_UNSPECIFIED": 0,
+		"HISTORY_SHARD_COUNT_MIGRATION_STATE_COPYING":     1,
+		"HISTORY_SHARD_COUNT_MIGRATION_STATE_CUTOVER":     2,
+		"HISTORY_SHARD_COUNT_MIGRATION_STATE_COMPLETED":   3,
+	}
+)
+
+func (x HistoryShardCountMigrationState) Enum() *HistoryShardCountMigrationState {
+	p := new(HistoryShardCountMigrationState)
+	*p = x
+	return p
+}
+
+func (x HistoryShardCountMigrationState) String() string {
+	switch x {
+	case HISTORY_SHARD_COUNT_MIGRATION_STATE_UNSPECIFIED:
+		return "Unspecified"
+	case HISTORY_SHARD_COUNT_MIGRATION_STATE_COPYING:
+		return "Copying"
+	case HISTORY_SHARD_COUNT_MIGRATION_STATE_CUTOVER:
+		return "Cutover"
+	case HISTORY_SHARD_COUNT_MIGRATION_STATE_COMPLETED:
+		return "Completed"
+	default:
+		return strconv.Itoa(int(x))
+	}
+
+}
+
+func (HistoryShardCountMigrationState) Descriptor() protoreflect.EnumDescriptor {
+	return file_temporal_server_api_enums_v1_cluster_proto_enumTypes[1].Descriptor()
+}
+
+func (HistoryShardCountMigrationState) Type() protoreflect.EnumType {
+	return &file_temporal_server_api_enums_v1_cluster_proto_enumTypes[1]
+}
+
+func (x HistoryShardCountMigrationState) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use HistoryShardCountMigrationState.Descriptor instead.
+func (HistoryShardCountMigrationState) EnumDescriptor() ([]byte, []int) {
+	return file_temporal_server_api_enums_v1_cluster_proto_rawDescGZIP(), []int{1}
+}
+
+type HistoryHostDrainState int32
+
+const (
+	HISTORY_HOST_DRAIN_STATE_UNSPECIFIED HistoryHostDrainState = 0
+	// The host stopped acquiring shards and is flushing and closing the shards it owns.
+	HISTORY_HOST_DRAIN_STATE_DRAINING HistoryHostDrainState = 1
+	// All shards were closed and the host announced through membership that it is draining,
+	// so their new owners acquire them right away.
+	HISTORY_HOST_DRAIN_STATE_DRAINED HistoryHostDrainState = 2
+	// The drain stopped before all shards were closed, the host is back in the membership ring
+	// and acquires shards again.
+	HISTORY_HOST_DRAIN_STATE_FAILED HistoryHostDrainState = 3
+)
+
+// Enum value maps for HistoryHostDrainState.
+var (
+	HistoryHostDrainState_name = map[int32]string{
+		0: "HISTORY_HOST_DRAIN_STATE_UNSPECIFIED",
+		1: "HISTORY_HOST_DRAIN_STATE_DRAINING",
+		2: "HISTORY_HOST_DRAIN_STATE_DRAINED",
+		3: "HISTORY_HOST_DRAIN_STATE_FAILED",
+	}
+	HistoryHostDrainState_value = map[string]int32{
+		"HISTORY_HOST_DRAIN_STATE_UNSPECIFIED": 0,
+		"HISTORY_HOST_DRAIN_STATE_DRAINING":    1,
+		"HISTORY_HOST_DRAIN_STATE_DRAINED":     2,
+		"HISTORY_HOST_DRAIN_STATE_FAILED":      3,
+	}
+)
+
+func (x HistoryHostDrainState) Enum() *HistoryHostDrainState {
+	p := new(HistoryHostDrainState)
+	*p = x
+	return p
+}
+
+func (x HistoryHostDrainState) String() string {
+	switch x {
+	case HISTORY_HOST_DRAIN_STATE_UNSPECIFIED:
+		return "Unspecified"
+	case HISTORY_HOST_DRAIN_STATE_DRAINING:
+		return "Draining"
+	case HISTORY_HOST_DRAIN_STATE_DRAINED:
+		return "Drained"
+	case HISTORY_HOST_DRAIN_STATE_FAILED:
+		return "Failed"
+	default:
+		return strconv.Itoa(int(x))
+	}
+
+}
+
+func (HistoryHostDrainState) Descriptor() protoreflect.EnumDescriptor {
+	return file_temporal_server_api_enums_v1_cluster_proto_enumTypes[2].Descriptor()
+}
+
+func (HistoryHostDrainState) Type() protoreflect.EnumType {
+	return &file_temporal_server_api_enums_v1_cluster_proto_enumTypes[2]
+}
+
+func (x HistoryHostDrainState) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use HistoryHostDrainState.Descriptor instead.
+func (HistoryHostDrainState) EnumDescriptor() ([]byte, []int) {
+	return file_temporal_server_api_enums_v1_cluster_proto_rawDescGZIP(), []int{2}
+}
+
 var File_temporal_server_api_enums_v1_cluster_proto protoreflect.FileDescriptor
 
 var file_temporal_server_api_enums_v1_cluster_proto_rawDesc = []byte{
@@ -130,10 +266,37 @@ var file_temporal_server_api_enums_v1_cluster_proto_rawDesc = []byte{
 	0x54, 0x45, 0x52, 0x5f, 0x4d, 0x45, 0x4d, 0x42, 0x45, 0x52, 0x5f, 0x52, 0x4f, 0x4c, 0x45, 0x5f,
 	0x4d, 0x41, 0x54, 0x43, 0x48, 0x49, 0x4e, 0x47, 0x10, 0x03, 0x12, 0x1e, 0x0a, 0x1a, 0x43, 0x4c,
 	0x55, 0x53, 0x54, 0x45, 0x52, 0x5f, 0x4d, 0x45, 0x4d, 0x42, 0x45, 0x52, 0x5f, 0x52, 0x4f, 0x4c,
-	0x45, 0x5f, 0x57, 0x4f, 0x52, 0x4b, 0x45, 0x52, 0x10, 0x04, 0x42, 0x2a, 0x5a, 0x28, 0x67, 0x6f,
-	0x2e, 0x74, 0x65, 0x6d, 0x70, 0x6f, 0x72, 0x61, 0x6c, 0x2e, 0x69, 0x6f, 0x2f, 0x73, 0x65, 0x72,
-	0x76, 0x65, 0x72, 0x2f, 0x61, 0x70, 0x69, 0x2f, 0x65, 0x6e, 0x75, 0x6d, 0x73, 0x2f, 0x76, 0x31,
-	0x3b, 0x65, 0x6e, 0x75, 0x6d, 0x73, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+	0x45, 0x5f, 0x57, 0x4f, 0x52, 0x4b, 0x45, 0x52, 0x10, 0x04, 0x2a, 0xeb, 0x01, 0x0a, 0x1f, 0x48,
+	0x69, 0x73, 0x74, 0x6f, 0x72, 0x79, 0x53, 0x68, 0x61, 0x72, 0x64, 0x43, 0x6f, 0x75, 0x6e, 0x74,
+	0x4d, 0x69, 0x67, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x53, 0x74, 0x61, 0x74, 0x65, 0x12, 0x33,
+	0x0a, 0x2f, 0x48, 0x49, 0x53, 0x54, 0x4f, 0x52, 0x59, 0x5f, 0x53, 0x48, 0x41, 0x52, 0x44, 0x5f,
+	0x43, 0x4f, 0x55, 0x4e, 0x54, 0x5f, 0x4d, 0x49, 0x47, 0x52, 0x41, 0x54, 0x49, 0x4f, 0x4e, 0x5f,
+	0x53, 0x54, 0x41, 0x54, 0x45, 0x5f, 0x55, 0x4e, 0x53, 0x50, 0x45, 0x43, 0x49, 0x46, 0x49, 0x45,
+	0x44, 0x10, 0x00, 0x12, 0x2f, 0x0a, 0x2b, 0x48, 0x49, 0x53, 0x54, 0x4f, 0x52, 0x59, 0x5f, 0x53,
+	0x48, 0x41, 0x52, 0x44, 0x5f, 0x43, 0x4f, 0x55, 0x4e, 0x54, 0x5f, 0x4d, 0x49, 0x47, 0x52, 0x41,
+	0x54, 0x49, 0x4f, 0x4e, 0x5f, 0x53, 0x54, 0x41, 0x54, 0x45, 0x5f, 0x43, 0x4f, 0x50, 0x59, 0x49,
+	0x4e, 0x47, 0x10, 0x01, 0x12, 0x2f, 0x0a, 0x2b, 0x48, 0x49, 0x53, 0x54, 0x4f, 0x52, 0x59, 0x5f,
+	0x53, 0x48, 0x41, 0x52, 0x44, 0x5f, 0x43, 0x4f, 0x55, 0x4e, 0x54, 0x5f, 0x4d, 0x49, 0x47, 0x52,
+	0x41, 0x54, 0x49, 0x4f, 0x4e, 0x5f, 0x53, 0x54, 0x41, 0x54, 0x45, 0x5f, 0x43, 0x55, 0x54, 0x4f,
+	0x56, 0x45, 0x52, 0x10, 0x02, 0x12, 0x31, 0x0a, 0x2d, 0x48, 0x49, 0x53, 0x54, 0x4f, 0x52, 0x59,
+	0x5f, 0x53, 0x48, 0x41, 0x52, 0x44, 0x5f, 0x43, 0x4f, 0x55, 0x4e, 0x54, 0x5f, 0x4d, 0x49, 0x47,
+	0x52, 0x41, 0x54, 0x49, 0x4f, 0x4e, 0x5f, 0x53, 0x54, 0x41, 0x54, 0x45, 0x5f, 0x43, 0x4f, 0x4d,
+	0x50, 0x4c, 0x45, 0x54, 0x45, 0x44, 0x10, 0x03, 0x2a, 0xb3, 0x01, 0x0a, 0x15, 0x48, 0x69, 0x73,
+	0x74, 0x6f, 0x72, 0x79, 0x48, 0x6f, 0x73, 0x74, 0x44, 0x72, 0x61, 0x69, 0x6e, 0x53, 0x74, 0x61,
+	0x74, 0x65, 0x12, 0x28, 0x0a, 0x24, 0x48, 0x49, 0x53, 0x54, 0x4f, 0x52, 0x59, 0x5f, 0x48, 0x4f,
+	0x53, 0x54, 0x5f, 0x44, 0x52, 0x41, 0x49, 0x4e, 0x5f, 0x53, 0x54, 0x41, 0x54, 0x45, 0x5f, 0x55,
+	0x4e, 0x53, 0x50, 0x45, 0x43, 0x49, 0x46, 0x49, 0x45, 0x44, 0x10, 0x00, 0x12, 0x25, 0x0a, 0x21,
+	0x48, 0x49, 0x53, 0x54, 0x4f, 0x52, 0x59, 0x5f, 0x48, 0x4f, 0x53, 0x54, 0x5f, 0x44, 0x52, 0x41,
+	0x49, 0x4e, 0x5f, 0x53, 0x54, 0x41, 0x54, 0x45, 0x5f, 0x44, 0x52, 0x41, 0x49, 0x4e, 0x49, 0x4e,
+	0x47, 0x10, 0x01, 0x12, 0x24, 0x0a, 0x20, 0x48, 0x49, 0x53, 0x54, 0x4f, 0x52, 0x59, 0x5f, 0x48,
+	0x4f, 0x53, 0x54, 0x5f, 0x44, 0x52, 0x41, 0x49, 0x4e, 0x5f, 0x53, 0x54, 0x41, 0x54, 0x45, 0x5f,
+	0x44, 0x52, 0x41, 0x49, 0x4e, 0x45, 0x44, 0x10, 0x02, 0x12, 0x23, 0x0a, 0x1f, 0x48, 0x49, 0x53,
+	0x54, 0x4f, 0x52, 0x59, 0x5f, 0x48, 0x4f, 0x53, 0x54, 0x5f, 0x44, 0x52, 0x41, 0x49, 0x4e, 0x5f,
+	0x53, 0x54, 0x41, 0x54, 0x45, 0x5f, 0x46, 0x41, 0x49, 0x4c, 0x45, 0x44, 0x10, 0x03, 0x42, 0x2a,
+	0x5a, 0x28, 0x67, 0x6f, 0x2e, 0x74, 0x65, 0x6d, 0x70, 0x6f, 0x72, 0x61, 0x6c, 0x2e, 0x69, 0x6f,
+	0x2f, 0x73, 0x65, 0x72, 0x76, 0x65, 0x72, 0x2f, 0x61, 0x70, 0x69, 0x2f, 0x65, 0x6e, 0x75, 0x6d,
+	0x73, 0x2f, 0x76, 0x31, 0x3b, 0x65, 0x6e, 0x75, 0x6d, 0x73, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74,
+	0x6f, 0x33,
 }
 
 var (
@@ -148,9 +311,11 @@ func file_temporal_server_api_enums_v1_cluster_proto_rawDescGZIP() []byte {
 	return file_temporal_server_api_enums_v1_cluster_proto_rawDescData
 }
 
-var file_temporal_server_api_enums_v1_cluster_proto_enumTypes = make([]protoimpl.EnumInfo, 1)
+var file_temporal_server_api_enums_v1_cluster_proto_enumTypes = make([]protoimpl.EnumInfo, 3)
 var file_temporal_server_api_enums_v1_cluster_proto_goTypes = []interface{}{
-	(ClusterMemberRole)(0), // 0: temporal.server.api.enums.v1.ClusterMemberRole
+	(ClusterMemberRole)(0),               // 0: temporal.server.api.enums.v1.ClusterMemberRole
+	(HistoryShardCountMigrationState)(0), // 1: temporal.server.api.enums.v1.HistoryShardCountMigrationState
+	(HistoryHostDrainState)(0),           // 2: temporal.server.api.enums.v1.HistoryHostDrainState
 }
 var file_temporal_server_api_enums_v1_cluster_proto_depIdxs = []int32{
 	0, // [0:0] is the sub-list for method output_type
@@ -170,7 +335,7 @@ func file_temporal_server_api_enums_v1_cluster_proto_init() {
 		File: protoimpl.DescBuilder{
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: file_temporal_server_api_enums_v1_cluster_proto_rawDesc,
-			NumEnums:      1,
+			NumEnums:      3,
 			NumMessages:   0,
 			NumExtensions: 0,
 			NumServices:   0,
@@ -2397,6 +2397,80 @@ func (this *DescribeHistoryHostResponse) Equal(that interface{}) bool {
 	return proto.Equal(this, that1)
 }
 
+// Marshal an object of type DrainHistoryHostRequest to the protobuf v3 wire format
+func (val *DrainHistoryHostRequest) Marshal() ([]byte, error) {
+	return proto.Marshal(val)
+}
+
+// Unmarshal an object of type DrainHistoryHostRequest from the protobuf v3 wire format
+func (val *DrainHistoryHostRequest) Unmarshal(buf []byte) error {
+	return proto.Unmarshal(buf, val)
+}
+
+// Size returns the size of the object, in bytes, once serialized
+func (val *DrainHistoryHostRequest) Size() int {
+	return proto.Size(val)
+}
+
+// Equal returns whether two DrainHistoryHostRequest values are equivalent by recursively
+// comparing the message's fields.
+// For more information see the documentation for
+// https://pkg.go.dev/google.golang.org/protobuf/proto#Equal
+func (this *DrainHistoryHostRequest) Equal(that interface{}) bool {
+	if that == nil {
+		return this == nil
+	}
+
+	var that1 *DrainHistoryHostRequest
+	switch t := that.(type) {
+	case *DrainHistoryHostRequest:
+		that1 = t
+	case DrainHistoryHostRequest:
+		that1 = &t
+	default:
+		return false
+	}
+
+	return proto.Equal(this, that1)
+}
+
+// Marshal an object of type DrainHistoryHostResponse to the protobuf v3 wire format
+func (val *DrainHistoryHostResponse) Marshal() ([]byte, error) {
+	return proto.Marshal(val)
+}
+
+// Unmarshal an object of type DrainHistoryHostResponse from the protobuf v3 wire format
+func (val *DrainHistoryHostResponse) Unmarshal(buf []byte) error {
+	return proto.Unmarshal(buf, val)
+}
+
+// Size returns the size of the object, in bytes, once serialized
+func (val *DrainHistoryHostResponse) Size() int {
+	return proto.Size(val)
+}
+
+// Equal returns whether two DrainHistoryHostResponse values are equivalent by recursively
+// comparing the message's fields.
+// For more information see the documentation for
+// https://pkg.go.dev/google.golang.org/protobuf/proto#Equal
+func (this *DrainHistoryHostResponse) Equal(that interface{}) bool {
+	if that == nil {
+		return this == nil
+	}
+
+	var that1 *DrainHistoryHostResponse
+	switch t := that.(type) {
+	case *DrainHistoryHostResponse:
+		that1 = t
+	case DrainHistoryHostResponse:
+		that1 = &t
+	default:
+		return false
+	}
+
+	return proto.Equal(this, that1)
+}
+
 // Marshal an object of type CloseShardRequest to the protobuf v3 wire format
 func (val *CloseShardRequest) Marshal() ([]byte, error) {
 	return proto.Marshal(val)
@@ -4505,3 +4579,77 @@ func (this *AddTasksResponse) Equal(that interface{}) bool {
 
 	return proto.Equal(this, that1)
 }
+
+// Marshal an object of type UndrainHistoryHostRequest to the protobuf v3 wire format
+func (val *UndrainHistoryHostRequest) Marshal() ([]byte, error) {
+	return proto.Marshal(val)
+}
+
+// Unmarshal an object of type UndrainHistoryHostRequest from the protobuf v3 wire format
+func (val *UndrainHistoryHostRequest) Unmarshal(buf []byte) error {
+	return proto.Unmarshal(buf, val)
+}
+
+// Size returns the size of the object, in bytes, once serialized
+func (val *UndrainHistoryHostRequest) Size() int {
+	return proto.Size(val)
+}
+
+// Equal returns whether two UndrainHistoryHostRequest values are equivalent by recursively
+// comparing the message's fields.
+// For more information see the documentation for
+// https://pkg.go.dev/google.golang.org/protobuf/proto#Equal
+func (this *UndrainHistoryHostRequest) Equal(that interface{}) bool {
+	if that == nil {
+		return this == nil
+	}
+
+	var that1 *UndrainHistoryHostRequest
+	switch t := that.(type) {
+	case *UndrainHistoryHostRequest:
+		that1 = t
+	case UndrainHistoryHostRequest:
+		that1 = &t
+	default:
+		return false
+	}
+
+	return proto.Equal(this, that1)
+}
+
+// Marshal an object of type UndrainHistoryHostResponse to the protobuf v3 wire format
+func (val *UndrainHistoryHostResponse) Marshal() ([]byte, error) {
+	return proto.Marshal(val)
+}
+
+// Unmarshal an object of type UndrainHistoryHostResponse from the protobuf v3 wire format
+func (val *UndrainHistoryHostResponse) Unmarshal(buf []byte) error {
+	return proto.Unmarshal(buf, val)
+}
+
+// Size returns the size of the object, in bytes, once serialized
+func (val *UndrainHistoryHostResponse) Size() int {
+	return proto.Size(val)
+}
+
+// Equal returns whether two UndrainHistoryHostResponse values are equivalent by recursively
+// comparing the message's fields.
+// For more information see the documentation for
+// https://pkg.go.dev/google.golang.org/protobuf/proto#Equal
+func (this *UndrainHistoryHostResponse) Equal(that interface{}) bool {
+	if that == nil {
+		return this == nil
+	}
+
+	var that1 *UndrainHistoryHostResponse
+	switch t := that.(type) {
+	case *UndrainHistoryHostResponse:
+		that1 = t
+	case UndrainHistoryHostResponse:
+		that1 = &t
+	default:
+		return false
+	}
+
+	return proto.Equal(this, that1)
+}
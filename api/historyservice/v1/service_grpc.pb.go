@@ -72,6 +72,7 @@ const (
 	HistoryService_SyncActivity_FullMethodName                           = "/temporal.server.api.historyservice.v1.HistoryService/SyncActivity"
 	HistoryService_DescribeMutableState_FullMethodName                   = "/temporal.server.api.historyservice.v1.HistoryService/DescribeMutableState"
 	HistoryService_DescribeHistoryHost_FullMethodName                    = "/temporal.server.api.historyservice.v1.HistoryService/DescribeHistoryHost"
+	HistoryService_DrainHistoryHost_FullMethodName                       = "/temporal.server.api.historyservice.v1.HistoryService/DrainHistoryHost"
 	HistoryService_CloseShard_FullMethodName                             = "/temporal.server.api.historyservice.v1.HistoryService/CloseShard"
 	HistoryService_GetShard_FullMethodName                               = "/temporal.server.api.historyservice.v1.HistoryService/GetShard"
 	HistoryService_RemoveTask_FullMethodName                             = "/temporal.server.api.historyservice.v1.HistoryService/RemoveTask"
@@ -99,6 +100,7 @@ const (
 	HistoryService_DeleteDLQTasks_FullMethodName                         = "/temporal.server.api.historyservice.v1.HistoryService/DeleteDLQTasks"
 	HistoryService_ListQueues_FullMethodName                             = "/temporal.server.api.historyservice.v1.HistoryService/ListQueues"
 	HistoryService_AddTasks_FullMethodName                               = "/temporal.server.api.historyservice.v1.HistoryService/AddTasks"
+	HistoryService_UndrainHistoryHost_FullMethodName                     = "/temporal.server.api.historyservice.v1.HistoryService/UndrainHistoryHost"
 )
 
 // HistoryServiceClient is the client API for HistoryService service.
@@ -238,6 +240,9 @@ type HistoryServiceClient interface {
 	DescribeMutableState(ctx context.Context, in *DescribeMutableStateRequest, opts ...grpc.CallOption) (*DescribeMutableStateResponse, error)
 	// DescribeHistoryHost returns information about the internal states of a history host.
 	DescribeHistoryHost(ctx context.Context, in *DescribeHistoryHostRequest, opts ...grpc.CallOption) (*DescribeHistoryHostResponse, error)
+	// DrainHistoryHost makes a history host hand off all its shards ahead of a shutdown.
+	// The drain runs in the background, its progress is returned by DescribeHistoryHost.
+	DrainHistoryHost(ctx context.Context, in *DrainHistoryHostRequest, opts ...grpc.CallOption) (*DrainHistoryHostResponse, error)
 	// CloseShard close the shard.
 	CloseShard(ctx context.Context, in *CloseShardRequest, opts ...grpc.CallOption) (*CloseShardResponse, error)
 	// GetShard gets the ShardInfo
@@ -302,6 +307,8 @@ type HistoryServiceClient interface {
 	// will fail with an InvalidArgument error. It is ok to have tasks for different workflow runs as long as they are
 	// in the same shard. Calls to the persistence API will be batched by workflow run.
 	AddTasks(ctx context.Context, in *AddTasksRequest, opts ...grpc.CallOption) (*AddTasksResponse, error)
+	// UndrainHistoryHost stops the drain of a history host, which rejoins the membership ring and acquires shards again.
+	UndrainHistoryHost(ctx context.Context, in *UndrainHistoryHostRequest, opts ...grpc.CallOption) (*UndrainHistoryHostResponse, error)
 }
 
 type historyServiceClient struct {
@@ -600,6 +607,15 @@ func (c *historyServiceClient) DescribeHistoryHost(ctx context.Context, in *Desc
 	return out, nil
 }
 
+func (c *historyServiceClient) DrainHistoryHost(ctx context.Context, in *DrainHistoryHostRequest, opts ...grpc.CallOption) (*DrainHistoryHostResponse, error) {
+	out := new(DrainHistoryHostResponse)
+	err := c.cc.Invoke(ctx, HistoryService_DrainHistoryHost_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *historyServiceClient) CloseShard(ctx context.Context, in *CloseShardRequest, opts ...grpc.CallOption) (*CloseShardResponse, error) {
 	out := new(CloseShardResponse)
 	err := c.cc.Invoke(ctx, HistoryService_CloseShard_FullMethodName, in, out, opts...)
@@ -865,6 +881,15 @@ func (c *historyServiceClient) AddTasks(ctx context.Context, in *AddTasksRequest
 	return out, nil
 }
 
+func (c *historyServiceClient) UndrainHistoryHost(ctx context.Context, in *UndrainHistoryHostRequest, opts ...grpc.CallOption) (*UndrainHistoryHostResponse, error) {
+	out := new(UndrainHistoryHostResponse)
+	err := c.cc.Invoke(ctx, HistoryService_UndrainHistoryHost_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // HistoryServiceServer is the server API for HistoryService service.
 // All implementations must embed UnimplementedHistoryServiceServer
 // for forward compatibility
@@ -1002,6 +1027,9 @@ type HistoryServiceServer interface {
 	DescribeMutableState(context.Context, *DescribeMutableStateRequest) (*DescribeMutableStateResponse, error)
 	// DescribeHistoryHost returns information about the internal states of a history host.
 	DescribeHistoryHost(context.Context, *DescribeHistoryHostRequest) (*DescribeHistoryHostResponse, error)
+	// DrainHistoryHost makes a history host hand off all its shards ahead of a shutdown.
+	// The drain runs in the background, its progress is returned by DescribeHistoryHost.
+	DrainHistoryHost(context.Context, *DrainHistoryHostRequest) (*DrainHistoryHostResponse, error)
 	// CloseShard close the shard.
 	CloseShard(context.Context, *CloseShardRequest) (*CloseShardResponse, error)
 	// GetShard gets the ShardInfo
@@ -1066,6 +1094,8 @@ type HistoryServiceServer interface {
 	// will fail with an InvalidArgument error. It is ok to have tasks for different workflow runs as long as they are
 	// in the same shard. Calls to the persistence API will be batched by workflow run.
 	AddTasks(context.Context, *AddTasksRequest) (*AddTasksResponse, error)
+	// UndrainHistoryHost stops the drain of a history host, which rejoins the membership ring and acquires shards again.
+	UndrainHistoryHost(context.Context, *UndrainHistoryHostRequest) (*UndrainHistoryHostResponse, error)
 	mustEmbedUnimplementedHistoryServiceServer()
 }
 
@@ -1169,6 +1199,9 @@ func (UnimplementedHistoryServiceServer) DescribeMutableState(context.Context, *
 func (UnimplementedHistoryServiceServer) DescribeHistoryHost(context.Context, *DescribeHistoryHostRequest) (*DescribeHistoryHostResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method DescribeHistoryHost not implemented")
 }
+func (UnimplementedHistoryServiceServer) DrainHistoryHost(context.Context, *DrainHistoryHostRequest) (*DrainHistoryHostResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DrainHistoryHost not implemented")
+}
 func (UnimplementedHistoryServiceServer) CloseShard(context.Context, *CloseShardRequest) (*CloseShardResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method CloseShard not implemented")
 }
@@ -1250,6 +1283,9 @@ func (UnimplementedHistoryServiceServer) ListQueues(context.Context, *ListQueues
 func (UnimplementedHistoryServiceServer) AddTasks(context.Context, *AddTasksRequest) (*AddTasksResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method AddTasks not implemented")
 }
+func (UnimplementedHistoryServiceServer) UndrainHistoryHost(context.Context, *UndrainHistoryHostRequest) (*UndrainHistoryHostResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UndrainHistoryHost not implemented")
+}
 func (UnimplementedHistoryServiceServer) mustEmbedUnimplementedHistoryServiceServer() {}
 
 // UnsafeHistoryServiceServer may be embedded to opt out of forward compatibility for this service.
@@ -1839,6 +1875,24 @@ func _HistoryService_DescribeHistoryHost_Handler(srv interface{}, ctx context.Co
 	return interceptor(ctx, in, info, handler)
 }
 
+func _HistoryService_DrainHistoryHost_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DrainHistoryHostRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(HistoryServiceServer).DrainHistoryHost(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: HistoryService_DrainHistoryHost_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(HistoryServiceServer).DrainHistoryHost(ctx, req.(*DrainHistoryHostRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _HistoryService_CloseShard_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(CloseShardRequest)
 	if err := dec(in); err != nil {
@@ -2333,6 +2387,24 @@ func _HistoryService_AddTasks_Handler(srv interface{}, ctx context.Context, dec
 	return interceptor(ctx, in, info, handler)
 }
 
+func _HistoryService_UndrainHistoryHost_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UndrainHistoryHostRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(HistoryServiceServer).UndrainHistoryHost(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: HistoryService_UndrainHistoryHost_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(HistoryServiceServer).UndrainHistoryHost(ctx, req.(*UndrainHistoryHostRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // HistoryService_ServiceDesc is the grpc.ServiceDesc for HistoryService service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -2468,6 +2540,10 @@ var HistoryService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "DescribeHistoryHost",
 			Handler:    _HistoryService_DescribeHistoryHost_Handler,
 		},
+		{
+			MethodName: "DrainHistoryHost",
+			Handler:    _HistoryService_DrainHistoryHost_Handler,
+		},
 		{
 			MethodName: "CloseShard",
 			Handler:    _HistoryService_CloseShard_Handler,
@@ -2572,6 +2648,10 @@ var HistoryService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "AddTasks",
 			Handler:    _HistoryService_AddTasks_Handler,
 		},
+		{
+			MethodName: "UndrainHistoryHost",
+			Handler:    _HistoryService_UndrainHistoryHost_Handler,
+		},
 	},
 	Streams: []grpc.StreamDesc{
 		{
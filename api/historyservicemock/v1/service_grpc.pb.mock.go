@@ -221,6 +221,26 @@ func (mr *MockHistoryServiceClientMockRecorder) DescribeWorkflowExecution(ctx, i
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DescribeWorkflowExecution", reflect.TypeOf((*MockHistoryServiceClient)(nil).DescribeWorkflowExecution), varargs...)
 }
 
+// DrainHistoryHost mocks base method.
+func (m *MockHistoryServiceClient) DrainHistoryHost(ctx context.Context, in *historyservice.DrainHistoryHostRequest, opts ...grpc.CallOption) (*historyservice.DrainHistoryHostResponse, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{ctx, in}
+	for _, a := range opts {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "DrainHistoryHost", varargs...)
+	ret0, _ := ret[0].(*historyservice.DrainHistoryHostResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DrainHistoryHost indicates an expected call of DrainHistoryHost.
+func (mr *MockHistoryServiceClientMockRecorder) DrainHistoryHost(ctx, in interface{}, opts ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{ctx, in}, opts...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DrainHistoryHost", reflect.TypeOf((*MockHistoryServiceClient)(nil).DrainHistoryHost), varargs...)
+}
+
 // ForceDeleteWorkflowExecution mocks base method.
 func (m *MockHistoryServiceClient) ForceDeleteWorkflowExecution(ctx context.Context, in *historyservice.ForceDeleteWorkflowExecutionRequest, opts ...grpc.CallOption) (*historyservice.ForceDeleteWorkflowExecutionResponse, error) {
 	m.ctrl.T.Helper()
@@ -1181,6 +1201,26 @@ func (mr *MockHistoryServiceClientMockRecorder) TerminateWorkflowExecution(ctx,
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TerminateWorkflowExecution", reflect.TypeOf((*MockHistoryServiceClient)(nil).TerminateWorkflowExecution), varargs...)
 }
 
+// UndrainHistoryHost mocks base method.
+func (m *MockHistoryServiceClient) UndrainHistoryHost(ctx context.Context, in *historyservice.UndrainHistoryHostRequest, opts ...grpc.CallOption) (*historyservice.UndrainHistoryHostResponse, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{ctx, in}
+	for _, a := range opts {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "UndrainHistoryHost", varargs...)
+	ret0, _ := ret[0].(*historyservice.UndrainHistoryHostResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UndrainHistoryHost indicates an expected call of UndrainHistoryHost.
+func (mr *MockHistoryServiceClientMockRecorder) UndrainHistoryHost(ctx, in interface{}, opts ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{ctx, in}, opts...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UndrainHistoryHost", reflect.TypeOf((*MockHistoryServiceClient)(nil).UndrainHistoryHost), varargs...)
+}
+
 // UpdateWorkflowExecution mocks base method.
 func (m *MockHistoryServiceClient) UpdateWorkflowExecution(ctx context.Context, in *historyservice.UpdateWorkflowExecutionRequest, opts ...grpc.CallOption) (*historyservice.UpdateWorkflowExecutionResponse, error) {
 	m.ctrl.T.Helper()
@@ -1521,6 +1561,21 @@ func (mr *MockHistoryServiceServerMockRecorder) DescribeWorkflowExecution(arg0,
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DescribeWorkflowExecution", reflect.TypeOf((*MockHistoryServiceServer)(nil).DescribeWorkflowExecution), arg0, arg1)
 }
 
+// DrainHistoryHost mocks base method.
+func (m *MockHistoryServiceServer) DrainHistoryHost(arg0 context.Context, arg1 *historyservice.DrainHistoryHostRequest) (*historyservice.DrainHistoryHostResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DrainHistoryHost", arg0, arg1)
+	ret0, _ := ret[0].(*historyservice.DrainHistoryHostResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DrainHistoryHost indicates an expected call of DrainHistoryHost.
+func (mr *MockHistoryServiceServerMockRecorder) DrainHistoryHost(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DrainHistoryHost", reflect.TypeOf((*MockHistoryServiceServer)(nil).DrainHistoryHost), arg0, arg1)
+}
+
 // ForceDeleteWorkflowExecution mocks base method.
 func (m *MockHistoryServiceServer) ForceDeleteWorkflowExecution(arg0 context.Context, arg1 *historyservice.ForceDeleteWorkflowExecutionRequest) (*historyservice.ForceDeleteWorkflowExecutionResponse, error) {
 	m.ctrl.T.Helper()
@@ -2240,6 +2295,21 @@ func (mr *MockHistoryServiceServerMockRecorder) TerminateWorkflowExecution(arg0,
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TerminateWorkflowExecution", reflect.TypeOf((*MockHistoryServiceServer)(nil).TerminateWorkflowExecution), arg0, arg1)
 }
 
+// UndrainHistoryHost mocks base method.
+func (m *MockHistoryServiceServer) UndrainHistoryHost(arg0 context.Context, arg1 *historyservice.UndrainHistoryHostRequest) (*historyservice.UndrainHistoryHostResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UndrainHistoryHost", arg0, arg1)
+	ret0, _ := ret[0].(*historyservice.UndrainHistoryHostResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UndrainHistoryHost indicates an expected call of UndrainHistoryHost.
+func (mr *MockHistoryServiceServerMockRecorder) UndrainHistoryHost(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UndrainHistoryHost", reflect.TypeOf((*MockHistoryServiceServer)(nil).UndrainHistoryHost), arg0, arg1)
+}
+
 // UpdateWorkflowExecution mocks base method.
 func (m *MockHistoryServiceServer) UpdateWorkflowExecution(arg0 context.Context, arg1 *historyservice.UpdateWorkflowExecutionRequest) (*historyservice.UpdateWorkflowExecutionResponse, error) {
 	m.ctrl.T.Helper()
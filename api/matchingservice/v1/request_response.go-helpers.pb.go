@@ -1286,3 +1286,77 @@ func (this *ReplicateTaskQueueUserDataResponse) Equal(that interface{}) bool {
 
 	return proto.Equal(this, that1)
 }
+
+// Marshal an object of type UpdateTaskQueuePauseStateRequest to the protobuf v3 wire format
+func (val *UpdateTaskQueuePauseStateRequest) Marshal() ([]byte, error) {
+	return proto.Marshal(val)
+}
+
+// Unmarshal an object of type UpdateTaskQueuePauseStateRequest from the protobuf v3 wire format
+func (val *UpdateTaskQueuePauseStateRequest) Unmarshal(buf []byte) error {
+	return proto.Unmarshal(buf, val)
+}
+
+// Size returns the size of the object, in bytes, once serialized
+func (val *UpdateTaskQueuePauseStateRequest) Size() int {
+	return proto.Size(val)
+}
+
+// Equal returns whether two UpdateTaskQueuePauseStateRequest values are equivalent by recursively
+// comparing the message's fields.
+// For more information see the documentation for
+// https://pkg.go.dev/google.golang.org/protobuf/proto#Equal
+func (this *UpdateTaskQueuePauseStateRequest) Equal(that interface{}) bool {
+	if that == nil {
+		return this == nil
+	}
+
+	var that1 *UpdateTaskQueuePauseStateRequest
+	switch t := that.(type) {
+	case *UpdateTaskQueuePauseStateRequest:
+		that1 = t
+	case UpdateTaskQueuePauseStateRequest:
+		that1 = &t
+	default:
+		return false
+	}
+
+	return proto.Equal(this, that1)
+}
+
+// Marshal an object of type UpdateTaskQueuePauseStateResponse to the protobuf v3 wire format
+func (val *UpdateTaskQueuePauseStateResponse) Marshal() ([]byte, error) {
+	return proto.Marshal(val)
+}
+
+// Unmarshal an object of type UpdateTaskQueuePauseStateResponse from the protobuf v3 wire format
+func (val *UpdateTaskQueuePauseStateResponse) Unmarshal(buf []byte) error {
+	return proto.Unmarshal(buf, val)
+}
+
+// Size returns the size of the object, in bytes, once serialized
+func (val *UpdateTaskQueuePauseStateResponse) Size() int {
+	return proto.Size(val)
+}
+
+// Equal returns whether two UpdateTaskQueuePauseStateResponse values are equivalent by recursively
+// comparing the message's fields.
+// For more information see the documentation for
+// https://pkg.go.dev/google.golang.org/protobuf/proto#Equal
+func (this *UpdateTaskQueuePauseStateResponse) Equal(that interface{}) bool {
+	if that == nil {
+		return this == nil
+	}
+
+	var that1 *UpdateTaskQueuePauseStateResponse
+	switch t := that.(type) {
+	case *UpdateTaskQueuePauseStateResponse:
+		that1 = t
+	case UpdateTaskQueuePauseStateResponse:
+		that1 = &t
+	default:
+		return false
+	}
+
+	return proto.Equal(this, that1)
+}
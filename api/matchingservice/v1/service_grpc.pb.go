@@ -57,6 +57,7 @@ const (
 	MatchingService_ForceUnloadTaskQueue_FullMethodName                   = "/temporal.server.api.matchingservice.v1.MatchingService/ForceUnloadTaskQueue"
 	MatchingService_UpdateTaskQueueUserData_FullMethodName                = "/temporal.server.api.matchingservice.v1.MatchingService/UpdateTaskQueueUserData"
 	MatchingService_ReplicateTaskQueueUserData_FullMethodName             = "/temporal.server.api.matchingservice.v1.MatchingService/ReplicateTaskQueueUserData"
+	MatchingService_UpdateTaskQueuePauseState_FullMethodName              = "/temporal.server.api.matchingservice.v1.MatchingService/UpdateTaskQueuePauseState"
 )
 
 // MatchingServiceClient is the client API for MatchingService service.
@@ -122,6 +123,16 @@ type MatchingServiceClient interface {
 	UpdateTaskQueueUserData(ctx context.Context, in *UpdateTaskQueueUserDataRequest, opts ...grpc.CallOption) (*UpdateTaskQueueUserDataResponse, error)
 	// Replicate task queue user data across clusters, must be done via the owning node for updates in namespace.
 	ReplicateTaskQueueUserData(ctx context.Context, in *ReplicateTaskQueueUserDataRequest, opts ...grpc.CallOption) (*ReplicateTaskQueueUserDataResponse, error)
+	// Pause or resume dispatch from a task queue. The pause state is stored in the task queue user data, so this
+	// request should always be routed to the node holding the root partition of the workflow task queue.
+	// (-- api-linter: core::0134::response-message-name=disabled
+	//
+	//	aip.dev/not-precedent: UpdateTaskQueuePauseState RPC doesn't follow Google API format. --)
+	//
+	// (-- api-linter: core::0134::method-signature=disabled
+	//
+	//	aip.dev/not-precedent: UpdateTaskQueuePauseState RPC doesn't follow Google API format. --)
+	UpdateTaskQueuePauseState(ctx context.Context, in *UpdateTaskQueuePauseStateRequest, opts ...grpc.CallOption) (*UpdateTaskQueuePauseStateResponse, error)
 }
 
 type matchingServiceClient struct {
@@ -285,6 +296,15 @@ func (c *matchingServiceClient) ReplicateTaskQueueUserData(ctx context.Context,
 	return out, nil
 }
 
+func (c *matchingServiceClient) UpdateTaskQueuePauseState(ctx context.Context, in *UpdateTaskQueuePauseStateRequest, opts ...grpc.CallOption) (*UpdateTaskQueuePauseStateResponse, error) {
+	out := new(UpdateTaskQueuePauseStateResponse)
+	err := c.cc.Invoke(ctx, MatchingService_UpdateTaskQueuePauseState_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // MatchingServiceServer is the server API for MatchingService service.
 // All implementations must embed UnimplementedMatchingServiceServer
 // for forward compatibility
@@ -348,6 +368,16 @@ type MatchingServiceServer interface {
 	UpdateTaskQueueUserData(context.Context, *UpdateTaskQueueUserDataRequest) (*UpdateTaskQueueUserDataResponse, error)
 	// Replicate task queue user data across clusters, must be done via the owning node for updates in namespace.
 	ReplicateTaskQueueUserData(context.Context, *ReplicateTaskQueueUserDataRequest) (*ReplicateTaskQueueUserDataResponse, error)
+	// Pause or resume dispatch from a task queue. The pause state is stored in the task queue user data, so this
+	// request should always be routed to the node holding the root partition of the workflow task queue.
+	// (-- api-linter: core::0134::response-message-name=disabled
+	//
+	//	aip.dev/not-precedent: UpdateTaskQueuePauseState RPC doesn't follow Google API format. --)
+	//
+	// (-- api-linter: core::0134::method-signature=disabled
+	//
+	//	aip.dev/not-precedent: UpdateTaskQueuePauseState RPC doesn't follow Google API format. --)
+	UpdateTaskQueuePauseState(context.Context, *UpdateTaskQueuePauseStateRequest) (*UpdateTaskQueuePauseStateResponse, error)
 	mustEmbedUnimplementedMatchingServiceServer()
 }
 
@@ -406,6 +436,9 @@ func (UnimplementedMatchingServiceServer) UpdateTaskQueueUserData(context.Contex
 func (UnimplementedMatchingServiceServer) ReplicateTaskQueueUserData(context.Context, *ReplicateTaskQueueUserDataRequest) (*ReplicateTaskQueueUserDataResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method ReplicateTaskQueueUserData not implemented")
 }
+func (UnimplementedMatchingServiceServer) UpdateTaskQueuePauseState(context.Context, *UpdateTaskQueuePauseStateRequest) (*UpdateTaskQueuePauseStateResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UpdateTaskQueuePauseState not implemented")
+}
 func (UnimplementedMatchingServiceServer) mustEmbedUnimplementedMatchingServiceServer() {}
 
 // UnsafeMatchingServiceServer may be embedded to opt out of forward compatibility for this service.
@@ -725,6 +758,24 @@ func _MatchingService_ReplicateTaskQueueUserData_Handler(srv interface{}, ctx co
 	return interceptor(ctx, in, info, handler)
 }
 
+func _MatchingService_UpdateTaskQueuePauseState_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UpdateTaskQueuePauseStateRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MatchingServiceServer).UpdateTaskQueuePauseState(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: MatchingService_UpdateTaskQueuePauseState_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MatchingServiceServer).UpdateTaskQueuePauseState(ctx, req.(*UpdateTaskQueuePauseStateRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // MatchingService_ServiceDesc is the grpc.ServiceDesc for MatchingService service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -800,6 +851,10 @@ var MatchingService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "ReplicateTaskQueueUserData",
 			Handler:    _MatchingService_ReplicateTaskQueueUserData_Handler,
 		},
+		{
+			MethodName: "UpdateTaskQueuePauseState",
+			Handler:    _MatchingService_UpdateTaskQueuePauseState_Handler,
+		},
 	},
 	Streams:  []grpc.StreamDesc{},
 	Metadata: "temporal/server/api/matchingservice/v1/service.proto",
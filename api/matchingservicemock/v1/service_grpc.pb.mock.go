@@ -360,6 +360,26 @@ func (mr *MockMatchingServiceClientMockRecorder) RespondQueryTaskCompleted(ctx,
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RespondQueryTaskCompleted", reflect.TypeOf((*MockMatchingServiceClient)(nil).RespondQueryTaskCompleted), varargs...)
 }
 
+// UpdateTaskQueuePauseState mocks base method.
+func (m *MockMatchingServiceClient) UpdateTaskQueuePauseState(ctx context.Context, in *matchingservice.UpdateTaskQueuePauseStateRequest, opts ...grpc.CallOption) (*matchingservice.UpdateTaskQueuePauseStateResponse, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{ctx, in}
+	for _, a := range opts {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "UpdateTaskQueuePauseState", varargs...)
+	ret0, _ := ret[0].(*matchingservice.UpdateTaskQueuePauseStateResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateTaskQueuePauseState indicates an expected call of UpdateTaskQueuePauseState.
+func (mr *MockMatchingServiceClientMockRecorder) UpdateTaskQueuePauseState(ctx, in interface{}, opts ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{ctx, in}, opts...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateTaskQueuePauseState", reflect.TypeOf((*MockMatchingServiceClient)(nil).UpdateTaskQueuePauseState), varargs...)
+}
+
 // UpdateTaskQueueUserData mocks base method.
 func (m *MockMatchingServiceClient) UpdateTaskQueueUserData(ctx context.Context, in *matchingservice.UpdateTaskQueueUserDataRequest, opts ...grpc.CallOption) (*matchingservice.UpdateTaskQueueUserDataResponse, error) {
 	m.ctrl.T.Helper()
@@ -648,6 +668,21 @@ func (mr *MockMatchingServiceServerMockRecorder) RespondQueryTaskCompleted(arg0,
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RespondQueryTaskCompleted", reflect.TypeOf((*MockMatchingServiceServer)(nil).RespondQueryTaskCompleted), arg0, arg1)
 }
 
+// UpdateTaskQueuePauseState mocks base method.
+func (m *MockMatchingServiceServer) UpdateTaskQueuePauseState(arg0 context.Context, arg1 *matchingservice.UpdateTaskQueuePauseStateRequest) (*matchingservice.UpdateTaskQueuePauseStateResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateTaskQueuePauseState", arg0, arg1)
+	ret0, _ := ret[0].(*matchingservice.UpdateTaskQueuePauseStateResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateTaskQueuePauseState indicates an expected call of UpdateTaskQueuePauseState.
+func (mr *MockMatchingServiceServerMockRecorder) UpdateTaskQueuePauseState(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateTaskQueuePauseState", reflect.TypeOf((*MockMatchingServiceServer)(nil).UpdateTaskQueuePauseState), arg0, arg1)
+}
+
 // UpdateTaskQueueUserData mocks base method.
 func (m *MockMatchingServiceServer) UpdateTaskQueueUserData(arg0 context.Context, arg1 *matchingservice.UpdateTaskQueueUserDataRequest) (*matchingservice.UpdateTaskQueueUserDataResponse, error) {
 	m.ctrl.T.Helper()
@@ -102,3 +102,40 @@ func (this *IndexSearchAttributes) Equal(that interface{}) bool {
 
 	return proto.Equal(this, that1)
 }
+
+// Marshal an object of type HistoryShardCountMigration to the protobuf v3 wire format
+func (val *HistoryShardCountMigration) Marshal() ([]byte, error) {
+	return proto.Marshal(val)
+}
+
+// Unmarshal an object of type HistoryShardCountMigration from the protobuf v3 wire format
+func (val *HistoryShardCountMigration) Unmarshal(buf []byte) error {
+	return proto.Unmarshal(buf, val)
+}
+
+// Size returns the size of the object, in bytes, once serialized
+func (val *HistoryShardCountMigration) Size() int {
+	return proto.Size(val)
+}
+
+// Equal returns whether two HistoryShardCountMigration values are equivalent by recursively
+// comparing the message's fields.
+// For more information see the documentation for
+// https://pkg.go.dev/google.golang.org/protobuf/proto#Equal
+func (this *HistoryShardCountMigration) Equal(that interface{}) bool {
+	if that == nil {
+		return this == nil
+	}
+
+	var that1 *HistoryShardCountMigration
+	switch t := that.(type) {
+	case *HistoryShardCountMigration:
+		that1 = t
+	case HistoryShardCountMigration:
+		that1 = &t
+	default:
+		return false
+	}
+
+	return proto.Equal(this, that1)
+}
@@ -65,3 +65,114 @@ func (this *TaskVersionDirective) Equal(that interface{}) bool {
 
 	return proto.Equal(this, that1)
 }
+
+// Marshal an object of type FairnessKeyBacklog to the protobuf v3 wire format
+func (val *FairnessKeyBacklog) Marshal() ([]byte, error) {
+	return proto.Marshal(val)
+}
+
+// Unmarshal an object of type FairnessKeyBacklog from the protobuf v3 wire format
+func (val *FairnessKeyBacklog) Unmarshal(buf []byte) error {
+	return proto.Unmarshal(buf, val)
+}
+
+// Size returns the size of the object, in bytes, once serialized
+func (val *FairnessKeyBacklog) Size() int {
+	return proto.Size(val)
+}
+
+// Equal returns whether two FairnessKeyBacklog values are equivalent by recursively
+// comparing the message's fields.
+// For more information see the documentation for
+// https://pkg.go.dev/google.golang.org/protobuf/proto#Equal
+func (this *FairnessKeyBacklog) Equal(that interface{}) bool {
+	if that == nil {
+		return this == nil
+	}
+
+	var that1 *FairnessKeyBacklog
+	switch t := that.(type) {
+	case *FairnessKeyBacklog:
+		that1 = t
+	case FairnessKeyBacklog:
+		that1 = &t
+	default:
+		return false
+	}
+
+	return proto.Equal(this, that1)
+}
+
+// Marshal an object of type TaskQueueBacklog to the protobuf v3 wire format
+func (val *TaskQueueBacklog) Marshal() ([]byte, error) {
+	return proto.Marshal(val)
+}
+
+// Unmarshal an object of type TaskQueueBacklog from the protobuf v3 wire format
+func (val *TaskQueueBacklog) Unmarshal(buf []byte) error {
+	return proto.Unmarshal(buf, val)
+}
+
+// Size returns the size of the object, in bytes, once serialized
+func (val *TaskQueueBacklog) Size() int {
+	return proto.Size(val)
+}
+
+// Equal returns whether two TaskQueueBacklog values are equivalent by recursively
+// comparing the message's fields.
+// For more information see the documentation for
+// https://pkg.go.dev/google.golang.org/protobuf/proto#Equal
+func (this *TaskQueueBacklog) Equal(that interface{}) bool {
+	if that == nil {
+		return this == nil
+	}
+
+	var that1 *TaskQueueBacklog
+	switch t := that.(type) {
+	case *TaskQueueBacklog:
+		that1 = t
+	case TaskQueueBacklog:
+		that1 = &t
+	default:
+		return false
+	}
+
+	return proto.Equal(this, that1)
+}
+
+// Marshal an object of type PriorityBacklog to the protobuf v3 wire format
+func (val *PriorityBacklog) Marshal() ([]byte, error) {
+	return proto.Marshal(val)
+}
+
+// Unmarshal an object of type PriorityBacklog from the protobuf v3 wire format
+func (val *PriorityBacklog) Unmarshal(buf []byte) error {
+	return proto.Unmarshal(buf, val)
+}
+
+// Size returns the size of the object, in bytes, once serialized
+func (val *PriorityBacklog) Size() int {
+	return proto.Size(val)
+}
+
+// Equal returns whether two PriorityBacklog values are equivalent by recursively
+// comparing the message's fields.
+// For more information see the documentation for
+// https://pkg.go.dev/google.golang.org/protobuf/proto#Equal
+func (this *PriorityBacklog) Equal(that interface{}) bool {
+	if that == nil {
+		return this == nil
+	}
+
+	var that1 *PriorityBacklog
+	switch t := that.(type) {
+	case *PriorityBacklog:
+		that1 = t
+	case PriorityBacklog:
+		that1 = &t
+	default:
+		return false
+	}
+
+	return proto.Equal(this, that1)
+}
@@ -83,6 +83,16 @@ func (c *clientImpl) CloseShard(
 	return c.client.CloseShard(ctx, request, opts...)
 }
 
+func (c *clientImpl) DeleteSavedVisibilityQuery(
+	ctx context.Context,
+	request *adminservice.DeleteSavedVisibilityQueryRequest,
+	opts ...grpc.CallOption,
+) (*adminservice.DeleteSavedVisibilityQueryResponse, error) {
+	ctx, cancel := c.createContext(ctx)
+	defer cancel()
+	return c.client.DeleteSavedVisibilityQuery(ctx, request, opts...)
+}
+
 func (c *clientImpl) DeleteWorkflowExecution(
 	ctx context.Context,
 	request *adminservice.DeleteWorkflowExecutionRequest,
@@ -123,6 +133,16 @@ func (c *clientImpl) DescribeHistoryHost(
 	return c.client.DescribeHistoryHost(ctx, request, opts...)
 }
 
+func (c *clientImpl) DescribeHistoryShardCountMigration(
+	ctx context.Context,
+	request *adminservice.DescribeHistoryShardCountMigrationRequest,
+	opts ...grpc.CallOption,
+) (*adminservice.DescribeHistoryShardCountMigrationResponse, error) {
+	ctx, cancel := c.createContext(ctx)
+	defer cancel()
+	return c.client.DescribeHistoryShardCountMigration(ctx, request, opts...)
+}
+
 func (c *clientImpl) DescribeMutableState(
 	ctx context.Context,
 	request *adminservice.DescribeMutableStateRequest,
@@ -133,6 +153,26 @@ func (c *clientImpl) DescribeMutableState(
 	return c.client.DescribeMutableState(ctx, request, opts...)
 }
 
+func (c *clientImpl) DescribeVisibilityReindex(
+	ctx context.Context,
+	request *adminservice.DescribeVisibilityReindexRequest,
+	opts ...grpc.CallOption,
+) (*adminservice.DescribeVisibilityReindexResponse, error) {
+	ctx, cancel := c.createContext(ctx)
+	defer cancel()
+	return c.client.DescribeVisibilityReindex(ctx, request, opts...)
+}
+
+func (c *clientImpl) DrainHistoryHost(
+	ctx context.Context,
+	request *adminservice.DrainHistoryHostRequest,
+	opts ...grpc.CallOption,
+) (*adminservice.DrainHistoryHostResponse, error) {
+	ctx, cancel := c.createContext(ctx)
+	defer cancel()
+	return c.client.DrainHistoryHost(ctx, request, opts...)
+}
+
 func (c *clientImpl) GetDLQMessages(
 	ctx context.Context,
 	request *adminservice.GetDLQMessagesRequest,
@@ -303,6 +343,16 @@ func (c *clientImpl) MergeDLQTasks(
 	return c.client.MergeDLQTasks(ctx, request, opts...)
 }
 
+func (c *clientImpl) PauseTaskQueue(
+	ctx context.Context,
+	request *adminservice.PauseTaskQueueRequest,
+	opts ...grpc.CallOption,
+) (*adminservice.PauseTaskQueueResponse, error) {
+	ctx, cancel := c.createContext(ctx)
+	defer cancel()
+	return c.client.PauseTaskQueue(ctx, request, opts...)
+}
+
 func (c *clientImpl) PurgeDLQMessages(
 	ctx context.Context,
 	request *adminservice.PurgeDLQMessagesRequest,
@@ -392,3 +442,53 @@ func (c *clientImpl) ResendReplicationTasks(
 	defer cancel()
 	return c.client.ResendReplicationTasks(ctx, request, opts...)
 }
+
+func (c *clientImpl) ResumeTaskQueue(
+	ctx context.Context,
+	request *adminservice.ResumeTaskQueueRequest,
+	opts ...grpc.CallOption,
+) (*adminservice.ResumeTaskQueueResponse, error) {
+	ctx, cancel := c.createContext(ctx)
+	defer cancel()
+	return c.client.ResumeTaskQueue(ctx, request, opts...)
+}
+
+func (c *clientImpl) SaveVisibilityQuery(
+	ctx context.Context,
+	request *adminservice.SaveVisibilityQueryRequest,
+	opts ...grpc.CallOption,
+) (*adminservice.SaveVisibilityQueryResponse, error) {
+	ctx, cancel := c.createContext(ctx)
+	defer cancel()
+	return c.client.SaveVisibilityQuery(ctx, request, opts...)
+}
+
+func (c *clientImpl) StartHistoryShardCountMigration(
+	ctx context.Context,
+	request *adminservice.StartHistoryShardCountMigrationRequest,
+	opts ...grpc.CallOption,
+) (*adminservice.StartHistoryShardCountMigrationResponse, error) {
+	ctx, cancel := c.createContext(ctx)
+	defer cancel()
+	return c.client.StartHistoryShardCountMigration(ctx, request, opts...)
+}
+
+func (c *clientImpl) StartVisibilityReindex(
+	ctx context.Context,
+	request *adminservice.StartVisibilityReindexRequest,
+	opts ...grpc.CallOption,
+) (*adminservice.StartVisibilityReindexResponse, error) {
+	ctx, cancel := c.createContext(ctx)
+	defer cancel()
+	return c.client.StartVisibilityReindex(ctx, request, opts...)
+}
+
+func (c *clientImpl) UndrainHistoryHost(
+	ctx context.Context,
+	request *adminservice.UndrainHistoryHostRequest,
+	opts ...grpc.CallOption,
+) (*adminservice.UndrainHistoryHostResponse, error) {
+	ctx, cancel := c.createContext(ctx)
+	defer cancel()
+	return c.client.UndrainHistoryHost(ctx, request, opts...)
+}
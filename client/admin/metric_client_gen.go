@@ -103,6 +103,20 @@ func (c *metricClient) CloseShard(
 	return c.client.CloseShard(ctx, request, opts...)
 }
 
+func (c *metricClient) DeleteSavedVisibilityQuery(
+	ctx context.Context,
+	request *adminservice.DeleteSavedVisibilityQueryRequest,
+	opts ...grpc.CallOption,
+) (_ *adminservice.DeleteSavedVisibilityQueryResponse, retError error) {
+
+	metricsHandler, startTime := c.startMetricsRecording(ctx, "AdminClientDeleteSavedVisibilityQuery")
+	defer func() {
+		c.finishMetricsRecording(metricsHandler, startTime, retError)
+	}()
+
+	return c.client.DeleteSavedVisibilityQuery(ctx, request, opts...)
+}
+
 func (c *metricClient) DeleteWorkflowExecution(
 	ctx context.Context,
 	request *adminservice.DeleteWorkflowExecutionRequest,
@@ -159,6 +173,20 @@ func (c *metricClient) DescribeHistoryHost(
 	return c.client.DescribeHistoryHost(ctx, request, opts...)
 }
 
+func (c *metricClient) DescribeHistoryShardCountMigration(
+	ctx context.Context,
+	request *adminservice.DescribeHistoryShardCountMigrationRequest,
+	opts ...grpc.CallOption,
+) (_ *adminservice.DescribeHistoryShardCountMigrationResponse, retError error) {
+
+	metricsHandler, startTime := c.startMetricsRecording(ctx, "AdminClientDescribeHistoryShardCountMigration")
+	defer func() {
+		c.finishMetricsRecording(metricsHandler, startTime, retError)
+	}()
+
+	return c.client.DescribeHistoryShardCountMigration(ctx, request, opts...)
+}
+
 func (c *metricClient) DescribeMutableState(
 	ctx context.Context,
 	request *adminservice.DescribeMutableStateRequest,
@@ -173,6 +201,34 @@ func (c *metricClient) DescribeMutableState(
 	return c.client.DescribeMutableState(ctx, request, opts...)
 }
 
+func (c *metricClient) DescribeVisibilityReindex(
+	ctx context.Context,
+	request *adminservice.DescribeVisibilityReindexRequest,
+	opts ...grpc.CallOption,
+) (_ *adminservice.DescribeVisibilityReindexResponse, retError error) {
+
+	metricsHandler, startTime := c.startMetricsRecording(ctx, "AdminClientDescribeVisibilityReindex")
+	defer func() {
+		c.finishMetricsRecording(metricsHandler, startTime, retError)
+	}()
+
+	return c.client.DescribeVisibilityReindex(ctx, request, opts...)
+}
+
+func (c *metricClient) DrainHistoryHost(
+	ctx context.Context,
+	request *adminservice.DrainHistoryHostRequest,
+	opts ...grpc.CallOption,
+) (_ *adminservice.DrainHistoryHostResponse, retError error) {
+
+	metricsHandler, startTime := c.startMetricsRecording(ctx, "AdminClientDrainHistoryHost")
+	defer func() {
+		c.finishMetricsRecording(metricsHandler, startTime, retError)
+	}()
+
+	return c.client.DrainHistoryHost(ctx, request, opts...)
+}
+
 func (c *metricClient) GetDLQMessages(
 	ctx context.Context,
 	request *adminservice.GetDLQMessagesRequest,
@@ -411,6 +467,20 @@ func (c *metricClient) MergeDLQTasks(
 	return c.client.MergeDLQTasks(ctx, request, opts...)
 }
 
+func (c *metricClient) PauseTaskQueue(
+	ctx context.Context,
+	request *adminservice.PauseTaskQueueRequest,
+	opts ...grpc.CallOption,
+) (_ *adminservice.PauseTaskQueueResponse, retError error) {
+
+	metricsHandler, startTime := c.startMetricsRecording(ctx, "AdminClientPauseTaskQueue")
+	defer func() {
+		c.finishMetricsRecording(metricsHandler, startTime, retError)
+	}()
+
+	return c.client.PauseTaskQueue(ctx, request, opts...)
+}
+
 func (c *metricClient) PurgeDLQMessages(
 	ctx context.Context,
 	request *adminservice.PurgeDLQMessagesRequest,
@@ -536,3 +606,73 @@ func (c *metricClient) ResendReplicationTasks(
 
 	return c.client.ResendReplicationTasks(ctx, request, opts...)
 }
+
+func (c *metricClient) ResumeTaskQueue(
+	ctx context.Context,
+	request *adminservice.ResumeTaskQueueRequest,
+	opts ...grpc.CallOption,
+) (_ *adminservice.ResumeTaskQueueResponse, retError error) {
+
+	metricsHandler, startTime := c.startMetricsRecording(ctx, "AdminClientResumeTaskQueue")
+	defer func() {
+		c.finishMetricsRecording(metricsHandler, startTime, retError)
+	}()
+
+	return c.client.ResumeTaskQueue(ctx, request, opts...)
+}
+
+func (c *metricClient) SaveVisibilityQuery(
+	ctx context.Context,
+	request *adminservice.SaveVisibilityQueryRequest,
+	opts ...grpc.CallOption,
+) (_ *adminservice.SaveVisibilityQueryResponse, retError error) {
+
+	metricsHandler, startTime := c.startMetricsRecording(ctx, "AdminClientSaveVisibilityQuery")
+	defer func() {
+		c.finishMetricsRecording(metricsHandler, startTime, retError)
+	}()
+
+	return c.client.SaveVisibilityQuery(ctx, request, opts...)
+}
+
+func (c *metricClient) StartHistoryShardCountMigration(
+	ctx context.Context,
+	request *adminservice.StartHistoryShardCountMigrationRequest,
+	opts ...grpc.CallOption,
+) (_ *adminservice.StartHistoryShardCountMigrationResponse, retError error) {
+
+	metricsHandler, startTime := c.startMetricsRecording(ctx, "AdminClientStartHistoryShardCountMigration")
+	defer func() {
+		c.finishMetricsRecording(metricsHandler, startTime, retError)
+	}()
+
+	return c.client.StartHistoryShardCountMigration(ctx, request, opts...)
+}
+
+func (c *metricClient) StartVisibilityReindex(
+	ctx context.Context,
+	request *adminservice.StartVisibilityReindexRequest,
+	opts ...grpc.CallOption,
+) (_ *adminservice.StartVisibilityReindexResponse, retError error) {
+
+	metricsHandler, startTime := c.startMetricsRecording(ctx, "AdminClientStartVisibilityReindex")
+	defer func() {
+		c.finishMetricsRecording(metricsHandler, startTime, retError)
+	}()
+
+	return c.client.StartVisibilityReindex(ctx, request, opts...)
+}
+
+func (c *metricClient) UndrainHistoryHost(
+	ctx context.Context,
+	request *adminservice.UndrainHistoryHostRequest,
+	opts ...grpc.CallOption,
+) (_ *adminservice.UndrainHistoryHostResponse, retError error) {
+
+	metricsHandler, startTime := c.startMetricsRecording(ctx, "AdminClientUndrainHistoryHost")
+	defer func() {
+		c.finishMetricsRecording(metricsHandler, startTime, retError)
+	}()
+
+	return c.client.UndrainHistoryHost(ctx, request, opts...)
+}
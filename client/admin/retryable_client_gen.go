@@ -110,6 +110,21 @@ func (c *retryableClient) CloseShard(
 	return resp, err
 }
 
+func (c *retryableClient) DeleteSavedVisibilityQuery(
+	ctx context.Context,
+	request *adminservice.DeleteSavedVisibilityQueryRequest,
+	opts ...grpc.CallOption,
+) (*adminservice.DeleteSavedVisibilityQueryResponse, error) {
+	var resp *adminservice.DeleteSavedVisibilityQueryResponse
+	op := func(ctx context.Context) error {
+		var err error
+		resp, err = c.client.DeleteSavedVisibilityQuery(ctx, request, opts...)
+		return err
+	}
+	err := backoff.ThrottleRetryContext(ctx, op, c.policy, c.isRetryable)
+	return resp, err
+}
+
 func (c *retryableClient) DeleteWorkflowExecution(
 	ctx context.Context,
 	request *adminservice.DeleteWorkflowExecutionRequest,
@@ -170,6 +185,21 @@ func (c *retryableClient) DescribeHistoryHost(
 	return resp, err
 }
 
+func (c *retryableClient) DescribeHistoryShardCountMigration(
+	ctx context.Context,
+	request *adminservice.DescribeHistoryShardCountMigrationRequest,
+	opts ...grpc.CallOption,
+) (*adminservice.DescribeHistoryShardCountMigrationResponse, error) {
+	var resp *adminservice.DescribeHistoryShardCountMigrationResponse
+	op := func(ctx context.Context) error {
+		var err error
+		resp, err = c.client.DescribeHistoryShardCountMigration(ctx, request, opts...)
+		return err
+	}
+	err := backoff.ThrottleRetryContext(ctx, op, c.policy, c.isRetryable)
+	return resp, err
+}
+
 func (c *retryableClient) DescribeMutableState(
 	ctx context.Context,
 	request *adminservice.DescribeMutableStateRequest,
@@ -185,6 +215,36 @@ func (c *retryableClient) DescribeMutableState(
 	return resp, err
 }
 
+func (c *retryableClient) DescribeVisibilityReindex(
+	ctx context.Context,
+	request *adminservice.DescribeVisibilityReindexRequest,
+	opts ...grpc.CallOption,
+) (*adminservice.DescribeVisibilityReindexResponse, error) {
+	var resp *adminservice.DescribeVisibilityReindexResponse
+	op := func(ctx context.Context) error {
+		var err error
+		resp, err = c.client.DescribeVisibilityReindex(ctx, request, opts...)
+		return err
+	}
+	err := backoff.ThrottleRetryContext(ctx, op, c.policy, c.isRetryable)
+	return resp, err
+}
+
+func (c *retryableClient) DrainHistoryHost(
+	ctx context.Context,
+	request *adminservice.DrainHistoryHostRequest,
+	opts ...grpc.CallOption,
+) (*adminservice.DrainHistoryHostResponse, error) {
+	var resp *adminservice.DrainHistoryHostResponse
+	op := func(ctx context.Context) error {
+		var err error
+		resp, err = c.client.DrainHistoryHost(ctx, request, opts...)
+		return err
+	}
+	err := backoff.ThrottleRetryContext(ctx, op, c.policy, c.isRetryable)
+	return resp, err
+}
+
 func (c *retryableClient) GetDLQMessages(
 	ctx context.Context,
 	request *adminservice.GetDLQMessagesRequest,
@@ -440,6 +500,21 @@ func (c *retryableClient) MergeDLQTasks(
 	return resp, err
 }
 
+func (c *retryableClient) PauseTaskQueue(
+	ctx context.Context,
+	request *adminservice.PauseTaskQueueRequest,
+	opts ...grpc.CallOption,
+) (*adminservice.PauseTaskQueueResponse, error) {
+	var resp *adminservice.PauseTaskQueueResponse
+	op := func(ctx context.Context) error {
+		var err error
+		resp, err = c.client.PauseTaskQueue(ctx, request, opts...)
+		return err
+	}
+	err := backoff.ThrottleRetryContext(ctx, op, c.policy, c.isRetryable)
+	return resp, err
+}
+
 func (c *retryableClient) PurgeDLQMessages(
 	ctx context.Context,
 	request *adminservice.PurgeDLQMessagesRequest,
@@ -574,3 +649,78 @@ func (c *retryableClient) ResendReplicationTasks(
 	err := backoff.ThrottleRetryContext(ctx, op, c.policy, c.isRetryable)
 	return resp, err
 }
+
+func (c *retryableClient) ResumeTaskQueue(
+	ctx context.Context,
+	request *adminservice.ResumeTaskQueueRequest,
+	opts ...grpc.CallOption,
+) (*adminservice.ResumeTaskQueueResponse, error) {
+	var resp *adminservice.ResumeTaskQueueResponse
+	op := func(ctx context.Context) error {
+		var err error
+		resp, err = c.client.ResumeTaskQueue(ctx, request, opts...)
+		return err
+	}
+	err := backoff.ThrottleRetryContext(ctx, op, c.policy, c.isRetryable)
+	return resp, err
+}
+
+func (c *retryableClient) SaveVisibilityQuery(
+	ctx context.Context,
+	request *adminservice.SaveVisibilityQueryRequest,
+	opts ...grpc.CallOption,
+) (*adminservice.SaveVisibilityQueryResponse, error) {
+	var resp *adminservice.SaveVisibilityQueryResponse
+	op := func(ctx context.Context) error {
+		var err error
+		resp, err = c.client.SaveVisibilityQuery(ctx, request, opts...)
+		return err
+	}
+	err := backoff.ThrottleRetryContext(ctx, op, c.policy, c.isRetryable)
+	return resp, err
+}
+
+func (c *retryableClient) StartHistoryShardCountMigration(
+	ctx context.Context,
+	request *adminservice.StartHistoryShardCountMigrationRequest,
+	opts ...grpc.CallOption,
+) (*adminservice.StartHistoryShardCountMigrationResponse, error) {
+	var resp *adminservice.StartHistoryShardCountMigrationResponse
+	op := func(ctx context.Context) error {
+		var err error
+		resp, err = c.client.StartHistoryShardCountMigration(ctx, request, opts...)
+		return err
+	}
+	err := backoff.ThrottleRetryContext(ctx, op, c.policy, c.isRetryable)
+	return resp, err
+}
+
+func (c *retryableClient) StartVisibilityReindex(
+	ctx context.Context,
+	request *adminservice.StartVisibilityReindexRequest,
+	opts ...grpc.CallOption,
+) (*adminservice.StartVisibilityReindexResponse, error) {
+	var resp *adminservice.StartVisibilityReindexResponse
+	op := func(ctx context.Context) error {
+		var err error
+		resp, err = c.client.StartVisibilityReindex(ctx, request, opts...)
+		return err
+	}
+	err := backoff.ThrottleRetryContext(ctx, op, c.policy, c.isRetryable)
+	return resp, err
+}
+
+func (c *retryableClient) UndrainHistoryHost(
+	ctx context.Context,
+	request *adminservice.UndrainHistoryHostRequest,
+	opts ...grpc.CallOption,
+) (*adminservice.UndrainHistoryHostResponse, error) {
+	var resp *adminservice.UndrainHistoryHostResponse
+	op := func(ctx context.Context) error {
+		var err error
+		resp, err = c.client.UndrainHistoryHost(ctx, request, opts...)
+		return err
+	}
+	err := backoff.ThrottleRetryContext(ctx, op, c.policy, c.isRetryable)
+	return resp, err
+}
@@ -131,6 +131,36 @@ func (c *clientImpl) DescribeHistoryHost(
 	return response, nil
 }
 
+// DrainHistoryHost is sent to the host given by the host address, it doesn't target a shard.
+func (c *clientImpl) DrainHistoryHost(
+	ctx context.Context,
+	request *historyservice.DrainHistoryHostRequest,
+	opts ...grpc.CallOption) (*historyservice.DrainHistoryHostResponse, error) {
+
+	if request.GetHostAddress() == "" {
+		return nil, serviceerror.NewInvalidArgument("host address is required to drain a history host")
+	}
+	clientConn := c.connections.getOrCreateClientConn(rpcAddress(request.GetHostAddress()))
+	ctx, cancel := c.createContext(ctx)
+	defer cancel()
+	return clientConn.historyClient.DrainHistoryHost(ctx, request, opts...)
+}
+
+// UndrainHistoryHost is sent to the host given by the host address, it doesn't target a shard.
+func (c *clientImpl) UndrainHistoryHost(
+	ctx context.Context,
+	request *historyservice.UndrainHistoryHostRequest,
+	opts ...grpc.CallOption) (*historyservice.UndrainHistoryHostResponse, error) {
+
+	if request.GetHostAddress() == "" {
+		return nil, serviceerror.NewInvalidArgument("host address is required to undrain a history host")
+	}
+	clientConn := c.connections.getOrCreateClientConn(rpcAddress(request.GetHostAddress()))
+	ctx, cancel := c.createContext(ctx)
+	defer cancel()
+	return clientConn.historyClient.UndrainHistoryHost(ctx, request, opts...)
+}
+
 func (c *clientImpl) GetReplicationMessages(
 	ctx context.Context,
 	request *historyservice.GetReplicationMessagesRequest,
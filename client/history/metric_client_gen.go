@@ -145,6 +145,20 @@ func (c *metricClient) DescribeWorkflowExecution(
 	return c.client.DescribeWorkflowExecution(ctx, request, opts...)
 }
 
+func (c *metricClient) DrainHistoryHost(
+	ctx context.Context,
+	request *historyservice.DrainHistoryHostRequest,
+	opts ...grpc.CallOption,
+) (_ *historyservice.DrainHistoryHostResponse, retError error) {
+
+	metricsHandler, startTime := c.startMetricsRecording(ctx, "HistoryClientDrainHistoryHost")
+	defer func() {
+		c.finishMetricsRecording(metricsHandler, startTime, retError)
+	}()
+
+	return c.client.DrainHistoryHost(ctx, request, opts...)
+}
+
 func (c *metricClient) ForceDeleteWorkflowExecution(
 	ctx context.Context,
 	request *historyservice.ForceDeleteWorkflowExecutionRequest,
@@ -803,6 +817,20 @@ func (c *metricClient) TerminateWorkflowExecution(
 	return c.client.TerminateWorkflowExecution(ctx, request, opts...)
 }
 
+func (c *metricClient) UndrainHistoryHost(
+	ctx context.Context,
+	request *historyservice.UndrainHistoryHostRequest,
+	opts ...grpc.CallOption,
+) (_ *historyservice.UndrainHistoryHostResponse, retError error) {
+
+	metricsHandler, startTime := c.startMetricsRecording(ctx, "HistoryClientUndrainHistoryHost")
+	defer func() {
+		c.finishMetricsRecording(metricsHandler, startTime, retError)
+	}()
+
+	return c.client.UndrainHistoryHost(ctx, request, opts...)
+}
+
 func (c *metricClient) UpdateWorkflowExecution(
 	ctx context.Context,
 	request *historyservice.UpdateWorkflowExecutionRequest,
@@ -155,6 +155,21 @@ func (c *retryableClient) DescribeWorkflowExecution(
 	return resp, err
 }
 
+func (c *retryableClient) DrainHistoryHost(
+	ctx context.Context,
+	request *historyservice.DrainHistoryHostRequest,
+	opts ...grpc.CallOption,
+) (*historyservice.DrainHistoryHostResponse, error) {
+	var resp *historyservice.DrainHistoryHostResponse
+	op := func(ctx context.Context) error {
+		var err error
+		resp, err = c.client.DrainHistoryHost(ctx, request, opts...)
+		return err
+	}
+	err := backoff.ThrottleRetryContext(ctx, op, c.policy, c.isRetryable)
+	return resp, err
+}
+
 func (c *retryableClient) ForceDeleteWorkflowExecution(
 	ctx context.Context,
 	request *historyservice.ForceDeleteWorkflowExecutionRequest,
@@ -860,6 +875,21 @@ func (c *retryableClient) TerminateWorkflowExecution(
 	return resp, err
 }
 
+func (c *retryableClient) UndrainHistoryHost(
+	ctx context.Context,
+	request *historyservice.UndrainHistoryHostRequest,
+	opts ...grpc.CallOption,
+) (*historyservice.UndrainHistoryHostResponse, error) {
+	var resp *historyservice.UndrainHistoryHostResponse
+	op := func(ctx context.Context) error {
+		var err error
+		resp, err = c.client.UndrainHistoryHost(ctx, request, opts...)
+		return err
+	}
+	err := backoff.ThrottleRetryContext(ctx, op, c.policy, c.isRetryable)
+	return resp, err
+}
+
 func (c *retryableClient) UpdateWorkflowExecution(
 	ctx context.Context,
 	request *historyservice.UpdateWorkflowExecutionRequest,
@@ -187,6 +187,21 @@ func (c *clientImpl) RespondQueryTaskCompleted(
 	return client.RespondQueryTaskCompleted(ctx, request, opts...)
 }
 
+func (c *clientImpl) UpdateTaskQueuePauseState(
+	ctx context.Context,
+	request *matchingservice.UpdateTaskQueuePauseStateRequest,
+	opts ...grpc.CallOption,
+) (*matchingservice.UpdateTaskQueuePauseStateResponse, error) {
+
+	client, err := c.getClientForTaskqueue(request.GetNamespaceId(), &taskqueuepb.TaskQueue{Name: request.GetTaskQueue()}, enumspb.TASK_QUEUE_TYPE_WORKFLOW)
+	if err != nil {
+		return nil, err
+	}
+	ctx, cancel := c.createContext(ctx)
+	defer cancel()
+	return client.UpdateTaskQueuePauseState(ctx, request, opts...)
+}
+
 func (c *clientImpl) UpdateTaskQueueUserData(
 	ctx context.Context,
 	request *matchingservice.UpdateTaskQueueUserDataRequest,
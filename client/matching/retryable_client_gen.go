@@ -260,6 +260,21 @@ func (c *retryableClient) RespondQueryTaskCompleted(
 	return resp, err
 }
 
+func (c *retryableClient) UpdateTaskQueuePauseState(
+	ctx context.Context,
+	request *matchingservice.UpdateTaskQueuePauseStateRequest,
+	opts ...grpc.CallOption,
+) (*matchingservice.UpdateTaskQueuePauseStateResponse, error) {
+	var resp *matchingservice.UpdateTaskQueuePauseStateResponse
+	op := func(ctx context.Context) error {
+		var err error
+		resp, err = c.client.UpdateTaskQueuePauseState(ctx, request, opts...)
+		return err
+	}
+	err := backoff.ThrottleRetryContext(ctx, op, c.policy, c.isRetryable)
+	return resp, err
+}
+
 func (c *retryableClient) UpdateTaskQueueUserData(
 	ctx context.Context,
 	request *matchingservice.UpdateTaskQueueUserDataRequest,
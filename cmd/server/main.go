@@ -35,6 +35,7 @@ import (
 	"github.com/urfave/cli/v2"
 	"go.uber.org/automaxprocs/maxprocs"
 
+	"go.temporal.io/server/common/api"
 	"go.temporal.io/server/common/authorization"
 	"go.temporal.io/server/common/build"
 	"go.temporal.io/server/common/config"
@@ -43,6 +44,7 @@ import (
 	"go.temporal.io/server/common/headers"
 	"go.temporal.io/server/common/log"
 	"go.temporal.io/server/common/log/tag"
+	"go.temporal.io/server/common/metrics"
 	_ "go.temporal.io/server/common/persistence/sql/sqlplugin/mysql"      // needed to load mysql plugin
 	_ "go.temporal.io/server/common/persistence/sql/sqlplugin/postgresql" // needed to load postgresql plugin
 	_ "go.temporal.io/server/common/persistence/sql/sqlplugin/sqlite"     // needed to load sqlite plugin
@@ -182,7 +184,12 @@ func buildCLI() *cli.App {
 					)
 				}
 
-				claimMapper, err := authorization.GetClaimMapperFromConfig(&cfg.Global.Authorization, logger)
+				metricsHandler, err := metrics.MetricsHandlerFromConfig(logger, cfg.Global.Metrics)
+				if err != nil {
+					return cli.Exit(fmt.Sprintf("Unable to create metrics handler: %v.", err), 1)
+				}
+
+				claimMapper, err := authorization.GetClaimMapperFromConfigWithMetrics(&cfg.Global.Authorization, logger, metricsHandler)
 				if err != nil {
 					return cli.Exit(fmt.Sprintf("Unable to instantiate claim mapper: %v.", err), 1)
 				}
@@ -191,6 +198,7 @@ func buildCLI() *cli.App {
 					temporal.WithConfig(cfg),
 					temporal.WithDynamicConfigClient(dynamicConfigClient),
 					temporal.WithLogger(logger),
+					temporal.WithCustomMetricsHandler(metricsHandler),
 					temporal.InterruptOn(temporal.InterruptCh()),
 					temporal.WithAuthorizer(authorizer),
 					temporal.WithClaimMapper(func(cfg *config.Config) authorization.ClaimMapper {
@@ -208,6 +216,165 @@ func buildCLI() *cli.App {
 				return cli.Exit("All services are stopped.", 0)
 			},
 		},
+		{
+			Name:  "config",
+			Usage: "Inspect Temporal server configuration",
+			Subcommands: []*cli.Command{
+				{
+					Name:      "validate-dynamic",
+					Usage:     "Validate dynamic config files against the known keys, value types and constraints",
+					ArgsUsage: "[file...]",
+					Action: func(c *cli.Context) error {
+						files := c.Args().Slice()
+						if len(files) == 0 {
+							// Fall back to the file configured in the static config.
+							env := c.String("env")
+							zone := c.String("zone")
+							configDir := path.Join(c.String("root"), c.String("config"))
+							cfg, err := config.LoadConfig(env, configDir, zone)
+							if err != nil {
+								return cli.Exit(fmt.Sprintf("Unable to load configuration: %v.", err), 1)
+							}
+							if cfg.DynamicConfigClient == nil {
+								return cli.Exit("Dynamic config client is not configured and no files were given.", 1)
+							}
+							files = []string{cfg.DynamicConfigClient.Filepath}
+						}
+						return validateDynamicConfigFiles(files)
+					},
+				},
+			},
+		},
+		{
+			Name:  "authz",
+			Usage: "Inspect Temporal server authorization",
+			Subcommands: []*cli.Command{
+				{
+					Name:      "explain",
+					Usage:     "Evaluate an authorization policy for a call made with the given claims",
+					ArgsUsage: " ",
+					Flags: []cli.Flag{
+						&cli.StringFlag{
+							Name:  "policy",
+							Usage: "authorization policy file, defaults to global.authorization.policyFile from the config",
+						},
+						&cli.StringFlag{
+							Name:     "claims",
+							Usage:    "YAML or JSON file with subject, groups, system and namespaces permissions",
+							Required: true,
+						},
+						&cli.StringFlag{
+							Name:     "api",
+							Usage:    "API method name, e.g. SignalWorkflowExecution, or full API name",
+							Required: true,
+						},
+						&cli.StringFlag{
+							Name:  "namespace",
+							Usage: "target namespace",
+						},
+						&cli.StringFlag{
+							Name:  "task-queue",
+							Usage: "task queue in the request",
+						},
+						&cli.StringFlag{
+							Name:  "workflow-type",
+							Usage: "workflow type in the request",
+						},
+					},
+					Action: func(c *cli.Context) error {
+						policyFile := c.String("policy")
+						if policyFile == "" {
+							env := c.String("env")
+							zone := c.String("zone")
+							configDir := path.Join(c.String("root"), c.String("config"))
+							cfg, err := config.LoadConfig(env, configDir, zone)
+							if err != nil {
+								return cli.Exit(fmt.Sprintf("Unable to load configuration: %v.", err), 1)
+							}
+							policyFile = cfg.Global.Authorization.PolicyFile
+						}
+						if policyFile == "" {
+							return cli.Exit("No authorization policy file given or configured.", 1)
+						}
+						return explainAuthorization(policyFile, c.String("claims"), authorization.PolicyTarget{
+							APIName:      fullAPIName(c.String("api")),
+							Namespace:    c.String("namespace"),
+							TaskQueue:    c.String("task-queue"),
+							WorkflowType: c.String("workflow-type"),
+						})
+					},
+				},
+			},
+		},
 	}
 	return app
 }
+
+func explainAuthorization(policyFile string, claimsFile string, target authorization.PolicyTarget) error {
+	policy, err := authorization.LoadAuthorizationPolicy(policyFile)
+	if err != nil {
+		return cli.Exit(err.Error(), 1)
+	}
+	authorizer, err := authorization.NewPolicyAuthorizer(policy)
+	if err != nil {
+		return cli.Exit(err.Error(), 1)
+	}
+	claims, err := authorization.LoadClaimsFile(claimsFile)
+	if err != nil {
+		return cli.Exit(err.Error(), 1)
+	}
+
+	explanation, err := authorizer.Explain(claims, target)
+	if err != nil {
+		return cli.Exit(fmt.Sprintf("Unable to evaluate policy: %v.", err), 1)
+	}
+	decision := "DENY"
+	if explanation.Result.Decision == authorization.DecisionAllow {
+		decision = "ALLOW"
+	}
+	fmt.Printf("API: %s\n", target.APIName)
+	fmt.Printf("Decision: %s\n", decision)
+	fmt.Printf("Reason: %s\n", explanation.Result.Reason)
+	if len(explanation.MatchedRules) > 0 {
+		fmt.Printf("Matched rules: %s\n", strings.Join(explanation.MatchedRules, ", "))
+	}
+	return nil
+}
+
+// fullAPIName resolves a bare method name to the full API name of the service that has it.
+func fullAPIName(name string) string {
+	if strings.HasPrefix(name, "/") {
+		return name
+	}
+	for _, prefix := range []string{api.WorkflowServicePrefix, api.OperatorServicePrefix} {
+		if api.GetMethodMetadata(prefix+name).Scope != api.ScopeUnknown {
+			return prefix + name
+		}
+	}
+	return api.AdminServicePrefix + name
+}
+
+func validateDynamicConfigFiles(files []string) error {
+	invalid := false
+	for _, file := range files {
+		contents, err := os.ReadFile(file)
+		if err != nil {
+			return cli.Exit(fmt.Sprintf("Unable to read %s: %v.", file, err), 1)
+		}
+		validationErrs, err := dynamicconfig.ValidateFile(contents)
+		if err != nil {
+			fmt.Printf("%s: %v\n", file, err)
+			invalid = true
+			continue
+		}
+		for _, validationErr := range validationErrs {
+			fmt.Printf("%s: %v\n", file, validationErr)
+			invalid = true
+		}
+	}
+	if invalid {
+		return cli.Exit("Dynamic config is invalid.", 1)
+	}
+	fmt.Println("Dynamic config is valid.")
+	return nil
+}
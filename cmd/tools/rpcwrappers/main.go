@@ -99,11 +99,13 @@ var (
 
 		// these are non-standard implementations. do not generate.
 		"client.history.DescribeHistoryHost":    true,
+		"client.history.DrainHistoryHost":       true,
 		"client.history.GetReplicationMessages": true,
 		"client.history.GetReplicationStatus":   true,
 		"client.history.GetDLQTasks":            true,
 		"client.history.DeleteDLQTasks":         true,
 		"client.history.ListQueues":             true,
+		"client.history.UndrainHistoryHost":     true,
 		// these need to pick a partition. too complicated.
 		"client.matching.AddActivityTask":       true,
 		"client.matching.AddWorkflowTask":       true,
@@ -238,6 +240,7 @@ func makeGetMatchingClient(reqType reflect.Type) string {
 		tqt = fieldWithPath{path: "enumspb.TASK_QUEUE_TYPE_UNSPECIFIED"}
 	case "GetWorkerBuildIdCompatibilityRequest",
 		"UpdateWorkerBuildIdCompatibilityRequest",
+		"UpdateTaskQueuePauseStateRequest",
 		"RespondQueryTaskCompletedRequest",
 		"ListTaskQueuePartitionsRequest",
 		"ApplyTaskQueueUserDataReplicationEventRequest":
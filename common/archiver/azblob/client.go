@@ -0,0 +1,459 @@
+// The MIT License
+//
+// Copyright (c) 2020 Temporal Technologies Inc.  All rights reserved.
+//
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package azblob
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"go.uber.org/multierr"
+
+	"go.temporal.io/server/common/config"
+)
+
+const (
+	storageAPIVersion     = "2021-08-06"
+	defaultEndpointFormat = "https://%s.blob.core.windows.net"
+	accountKeyEnvVar      = "AZURE_STORAGE_KEY"
+
+	errorCodeBlobNotFound      = "BlobNotFound"
+	errorCodeContainerNotFound = "ContainerNotFound"
+)
+
+var (
+	errNoAccountName = errors.New("azblob archiver requires accountName or endpoint")
+	errNoCredentials = errors.New("azblob archiver requires accountKey or sasToken")
+)
+
+type (
+	// Client is a minimal client of the Azure Blob Storage REST API, covering what the archivers
+	// need. Blob names are relative to the container.
+	Client interface {
+		Upload(ctx context.Context, container string, name string, data []byte) error
+		Download(ctx context.Context, container string, name string) ([]byte, error)
+		Exists(ctx context.Context, container string, name string) (bool, error)
+		ContainerExists(ctx context.Context, container string) (bool, error)
+		List(ctx context.Context, container string, params ListParams) (*ListResult, error)
+	}
+
+	// ListParams are the parameters of a List Blobs call
+	ListParams struct {
+		Prefix    string
+		Delimiter string
+		Marker    string
+		// MaxResults of 0 uses the service default of 5000
+		MaxResults int
+	}
+
+	// ListResult is a page of List Blobs results. Prefixes are only returned if a delimiter is
+	// used. NextMarker is empty on the last page.
+	ListResult struct {
+		Names      []string
+		Prefixes   []string
+		NextMarker string
+	}
+
+	// StorageError is an error response of the Blob service
+	StorageError struct {
+		StatusCode int
+		Code       string
+		Message    string
+	}
+
+	blobClient struct {
+		httpClient  *http.Client
+		endpoint    *url.URL
+		accountName string
+		accountKey  []byte
+		sasToken    url.Values
+	}
+
+	listBlobsResponse struct {
+		Blobs struct {
+			Blob []struct {
+				Name string `xml:"Name"`
+			} `xml:"Blob"`
+			BlobPrefix []struct {
+				Name string `xml:"Name"`
+			} `xml:"BlobPrefix"`
+		} `xml:"Blobs"`
+		NextMarker string `xml:"NextMarker"`
+	}
+
+	errorResponse struct {
+		Code    string `xml:"Code"`
+		Message string `xml:"Message"`
+	}
+)
+
+var _ Client = (*blobClient)(nil)
+
+// NewClient creates a Client from the archiver config. Requests are signed with the account
+// key, or authorized with the SAS token if there is no account key.
+func NewClient(cfg *config.AzblobArchiver) (Client, error) {
+	return newClient(cfg, http.DefaultClient)
+}
+
+func newClient(cfg *config.AzblobArchiver, httpClient *http.Client) (*blobClient, error) {
+	endpoint := cfg.Endpoint
+	if endpoint == "" {
+		if cfg.AccountName == "" {
+			return nil, errNoAccountName
+		}
+		endpoint = fmt.Sprintf(defaultEndpointFormat, cfg.AccountName)
+	}
+	endpointURL, err := url.Parse(strings.TrimRight(endpoint, "/"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid azblob endpoint %q: %w", endpoint, err)
+	}
+
+	client := &blobClient{
+		httpClient:  httpClient,
+		endpoint:    endpointURL,
+		accountName: cfg.AccountName,
+	}
+
+	accountKey := cfg.AccountKey
+	if accountKey == "" {
+		accountKey = os.Getenv(accountKeyEnvVar)
+	}
+	switch {
+	case accountKey != "":
+		if cfg.AccountName == "" {
+			return nil, errNoAccountName
+		}
+		client.accountKey, err = base64.StdEncoding.DecodeString(accountKey)
+		if err != nil {
+			return nil, fmt.Errorf("invalid azblob account key: %w", err)
+		}
+	case cfg.SASToken != "":
+		client.sasToken, err = url.ParseQuery(strings.TrimPrefix(cfg.SASToken, "?"))
+		if err != nil {
+			return nil, fmt.Errorf("invalid azblob SAS token: %w", err)
+		}
+	default:
+		return nil, errNoCredentials
+	}
+	return client, nil
+}
+
+// Upload creates or replaces a block blob
+func (c *blobClient) Upload(ctx context.Context, container string, name string, data []byte) error {
+	req, err := c.newRequest(ctx, http.MethodPut, container, name, nil, data)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("x-ms-blob-type", "BlockBlob")
+	req.Header.Set("Content-Type", "application/octet-stream")
+	resp, err := c.do(req)
+	if err != nil {
+		return err
+	}
+	return resp.Body.Close()
+}
+
+// Download returns the content of a blob
+func (c *blobClient) Download(ctx context.Context, container string, name string) (_ []byte, err error) {
+	req, err := c.newRequest(ctx, http.MethodGet, container, name, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := c.do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		err = multierr.Append(err, resp.Body.Close())
+	}()
+	return io.ReadAll(resp.Body)
+}
+
+// Exists checks if a blob exists
+func (c *blobClient) Exists(ctx context.Context, container string, name string) (bool, error) {
+	req, err := c.newRequest(ctx, http.MethodHead, container, name, nil, nil)
+	if err != nil {
+		return false, err
+	}
+	return c.exists(req)
+}
+
+// ContainerExists checks if a container exists
+func (c *blobClient) ContainerExists(ctx context.Context, container string) (bool, error) {
+	req, err := c.newRequest(ctx, http.MethodHead, container, "", url.Values{"restype": {"container"}}, nil)
+	if err != nil {
+		return false, err
+	}
+	return c.exists(req)
+}
+
+// List returns a page of blob names, in lexicographical order, that start with params.Prefix
+func (c *blobClient) List(ctx context.Context, container string, params ListParams) (_ *ListResult, err error) {
+	query := url.Values{
+		"restype": {"container"},
+		"comp":    {"list"},
+	}
+	if params.Prefix != "" {
+		query.Set("prefix", params.Prefix)
+	}
+	if params.Delimiter != "" {
+		query.Set("delimiter", params.Delimiter)
+	}
+	if params.Marker != "" {
+		query.Set("marker", params.Marker)
+	}
+	if params.MaxResults > 0 {
+		query.Set("maxresults", strconv.Itoa(params.MaxResults))
+	}
+	req, err := c.newRequest(ctx, http.MethodGet, container, "", query, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := c.do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		err = multierr.Append(err, resp.Body.Close())
+	}()
+
+	var listResponse listBlobsResponse
+	if err := xml.NewDecoder(resp.Body).Decode(&listResponse); err != nil {
+		return nil, fmt.Errorf("invalid list blobs response: %w", err)
+	}
+	result := &ListResult{NextMarker: listResponse.NextMarker}
+	for _, blob := range listResponse.Blobs.Blob {
+		result.Names = append(result.Names, blob.Name)
+	}
+	for _, prefix := range listResponse.Blobs.BlobPrefix {
+		result.Prefixes = append(result.Prefixes, prefix.Name)
+	}
+	return result, nil
+}
+
+func (c *blobClient) exists(req *http.Request) (bool, error) {
+	resp, err := c.do(req)
+	if err != nil {
+		if isNotFoundError(err) {
+			return false, nil
+		}
+		return false, err
+	}
+	return true, resp.Body.Close()
+}
+
+func (c *blobClient) newRequest(
+	ctx context.Context,
+	method string,
+	container string,
+	name string,
+	query url.Values,
+	body []byte,
+) (*http.Request, error) {
+	u := *c.endpoint
+	u.Path = u.Path + "/" + container
+	if name != "" {
+		u.Path += "/" + name
+	}
+	u.RawPath = ""
+	if len(c.sasToken) > 0 {
+		if query == nil {
+			query = url.Values{}
+		}
+		for k, v := range c.sasToken {
+			query[k] = v
+		}
+	}
+	u.RawQuery = query.Encode()
+
+	var bodyReader io.Reader
+	if body != nil {
+		bodyReader = bytes.NewReader(body)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, u.String(), bodyReader)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("x-ms-version", storageAPIVersion)
+	req.Header.Set("x-ms-date", time.Now().UTC().Format(http.TimeFormat))
+	return req, nil
+}
+
+func (c *blobClient) do(req *http.Request) (*http.Response, error) {
+	if c.accountKey != nil {
+		signRequest(req, c.accountName, c.accountKey)
+	}
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode >= http.StatusMultipleChoices {
+		defer func() { _ = resp.Body.Close() }()
+		return nil, newStorageError(resp)
+	}
+	return resp, nil
+}
+
+// signRequest adds a Shared Key authorization header to req, see
+// https://learn.microsoft.com/en-us/rest/api/storageservices/authorize-with-shared-key
+func signRequest(req *http.Request, accountName string, accountKey []byte) {
+	req.Header.Set("Authorization", "SharedKey "+accountName+":"+computeSignature(req, accountName, accountKey))
+}
+
+func computeSignature(req *http.Request, accountName string, accountKey []byte) string {
+	contentLength := ""
+	if req.ContentLength > 0 {
+		contentLength = strconv.FormatInt(req.ContentLength, 10)
+	}
+	stringToSign := strings.Join([]string{
+		req.Method,
+		req.Header.Get("Content-Encoding"),
+		req.Header.Get("Content-Language"),
+		contentLength,
+		req.Header.Get("Content-MD5"),
+		req.Header.Get("Content-Type"),
+		"", // Date, x-ms-date is used instead
+		req.Header.Get("If-Modified-Since"),
+		req.Header.Get("If-Match"),
+		req.Header.Get("If-None-Match"),
+		req.Header.Get("If-Unmodified-Since"),
+		req.Header.Get("Range"),
+		canonicalizedHeaders(req.Header) + canonicalizedResource(accountName, req.URL),
+	}, "\n")
+
+	mac := hmac.New(sha256.New, accountKey)
+	_, _ = mac.Write([]byte(stringToSign))
+	return base64.StdEncoding.EncodeToString(mac.Sum(nil))
+}
+
+func canonicalizedHeaders(header http.Header) string {
+	var names []string
+	values := make(map[string]string)
+	for name, v := range header {
+		name = strings.ToLower(name)
+		if strings.HasPrefix(name, "x-ms-") {
+			names = append(names, name)
+			values[name] = strings.TrimSpace(strings.Join(v, ","))
+		}
+	}
+	sort.Strings(names)
+
+	var sb strings.Builder
+	for _, name := range names {
+		sb.WriteString(name)
+		sb.WriteString(":")
+		sb.WriteString(values[name])
+		sb.WriteString("\n")
+	}
+	return sb.String()
+}
+
+func canonicalizedResource(accountName string, u *url.URL) string {
+	var sb strings.Builder
+	sb.WriteString("/")
+	sb.WriteString(accountName)
+	sb.WriteString(u.EscapedPath())
+
+	// Parameter names are case-insensitive; values of repeated names are merged.
+	query := make(map[string][]string)
+	for name, values := range u.Query() {
+		name = strings.ToLower(name)
+		query[name] = append(query[name], values...)
+	}
+	names := make([]string, 0, len(query))
+	for name := range query {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		values := query[name]
+		sort.Strings(values)
+		sb.WriteString("\n")
+		sb.WriteString(name)
+		sb.WriteString(":")
+		sb.WriteString(strings.Join(values, ","))
+	}
+	return sb.String()
+}
+
+func newStorageError(resp *http.Response) error {
+	storageErr := &StorageError{
+		StatusCode: resp.StatusCode,
+		Code:       resp.Header.Get("x-ms-error-code"),
+	}
+	var body errorResponse
+	if data, err := io.ReadAll(resp.Body); err == nil && len(data) > 0 && xml.Unmarshal(data, &body) == nil {
+		if body.Code != "" {
+			storageErr.Code = body.Code
+		}
+		storageErr.Message = body.Message
+	}
+	return storageErr
+}
+
+func (e *StorageError) Error() string {
+	if e.Message == "" {
+		return fmt.Sprintf("azblob: status %d, code %q", e.StatusCode, e.Code)
+	}
+	return fmt.Sprintf("azblob: status %d, code %q: %s", e.StatusCode, e.Code, e.Message)
+}
+
+func isNotFoundError(err error) bool {
+	var storageErr *StorageError
+	return errors.As(err, &storageErr) && storageErr.StatusCode == http.StatusNotFound
+}
+
+func isContainerNotFoundError(err error) bool {
+	var storageErr *StorageError
+	return errors.As(err, &storageErr) && storageErr.Code == errorCodeContainerNotFound
+}
+
+func isRetryableError(err error) bool {
+	if err == nil {
+		return false
+	}
+	var storageErr *StorageError
+	if errors.As(err, &storageErr) {
+		return storageErr.StatusCode == http.StatusTooManyRequests ||
+			storageErr.StatusCode == http.StatusRequestTimeout ||
+			(storageErr.StatusCode >= http.StatusInternalServerError && storageErr.StatusCode != http.StatusNotImplemented)
+	}
+	var netErr net.Error
+	return errors.As(err, &netErr)
+}
@@ -0,0 +1,177 @@
+// The MIT License
+//
+// Copyright (c) 2020 Temporal Technologies Inc.  All rights reserved.
+//
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package azblob
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"go.temporal.io/server/common/config"
+)
+
+const testContainer = "test-container"
+
+func TestClient_UploadDownload(t *testing.T) {
+	fake := newFakeBlobServer(t, testContainer)
+	client := fake.client()
+	ctx := context.Background()
+
+	name := "ns/history/workflow id?#/run-id/1/0"
+	exists, err := client.Exists(ctx, testContainer, name)
+	require.NoError(t, err)
+	require.False(t, exists)
+
+	require.NoError(t, client.Upload(ctx, testContainer, name, []byte("payload")))
+	require.Equal(t, []string{name}, fake.blobNames(testContainer))
+
+	exists, err = client.Exists(ctx, testContainer, name)
+	require.NoError(t, err)
+	require.True(t, exists)
+
+	data, err := client.Download(ctx, testContainer, name)
+	require.NoError(t, err)
+	require.Equal(t, []byte("payload"), data)
+
+	_, err = client.Download(ctx, testContainer, "missing")
+	require.True(t, isNotFoundError(err))
+	require.False(t, isContainerNotFoundError(err))
+}
+
+func TestClient_ContainerExists(t *testing.T) {
+	fake := newFakeBlobServer(t, testContainer)
+	client := fake.client()
+
+	exists, err := client.ContainerExists(context.Background(), testContainer)
+	require.NoError(t, err)
+	require.True(t, exists)
+
+	exists, err = client.ContainerExists(context.Background(), "other-container")
+	require.NoError(t, err)
+	require.False(t, exists)
+
+	err = client.Upload(context.Background(), "other-container", "blob", []byte("payload"))
+	require.True(t, isContainerNotFoundError(err))
+}
+
+func TestClient_List(t *testing.T) {
+	fake := newFakeBlobServer(t, testContainer)
+	for _, name := range []string{"a/1/0", "a/1/1", "a/2/0", "a/3", "b/1"} {
+		fake.put(testContainer, name, []byte(name))
+	}
+	client := fake.client()
+	ctx := context.Background()
+
+	result, err := client.List(ctx, testContainer, ListParams{Prefix: "a/", Delimiter: "/"})
+	require.NoError(t, err)
+	require.Equal(t, []string{"a/3"}, result.Names)
+	require.Equal(t, []string{"a/1/", "a/2/"}, result.Prefixes)
+	require.Empty(t, result.NextMarker)
+
+	var names []string
+	params := ListParams{Prefix: "a/", MaxResults: 3}
+	for {
+		result, err := client.List(ctx, testContainer, params)
+		require.NoError(t, err)
+		require.LessOrEqual(t, len(result.Names), 3)
+		names = append(names, result.Names...)
+		if result.NextMarker == "" {
+			break
+		}
+		params.Marker = result.NextMarker
+	}
+	require.Equal(t, []string{"a/1/0", "a/1/1", "a/2/0", "a/3"}, names)
+}
+
+func TestClient_Errors(t *testing.T) {
+	fake := newFakeBlobServer(t, testContainer)
+	client := fake.client()
+
+	fake.failNext(http.StatusServiceUnavailable, http.StatusBadRequest)
+	err := client.Upload(context.Background(), testContainer, "blob", []byte("payload"))
+	require.Error(t, err)
+	require.True(t, isRetryableError(err))
+	require.Equal(t, &StorageError{StatusCode: http.StatusServiceUnavailable, Code: "InjectedFailure", Message: "Service Unavailable"}, err)
+
+	_, err = client.Download(context.Background(), testContainer, "blob")
+	require.Error(t, err)
+	require.False(t, isRetryableError(err))
+
+	cfg := fake.config()
+	cfg.AccountKey = "b3RoZXIta2V5" // "other-key"
+	badClient, err := newClient(cfg, http.DefaultClient)
+	require.NoError(t, err)
+	_, err = badClient.Exists(context.Background(), testContainer, "blob")
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "AuthenticationFailed")
+}
+
+func TestClient_SASToken(t *testing.T) {
+	fake := newFakeBlobServer(t, testContainer)
+	cfg := &config.AzblobArchiver{
+		Endpoint: fake.server.URL + "/" + testAccountName,
+		SASToken: "?sv=2021-08-06&sig=test-sig",
+	}
+	client, err := newClient(cfg, http.DefaultClient)
+	require.NoError(t, err)
+	require.NoError(t, client.Upload(context.Background(), testContainer, "blob", []byte("payload")))
+
+	cfg.SASToken = "sv=2021-08-06&sig=wrong"
+	client, err = newClient(cfg, http.DefaultClient)
+	require.NoError(t, err)
+	require.Error(t, client.Upload(context.Background(), testContainer, "blob", []byte("payload")))
+}
+
+func TestNewClient_Config(t *testing.T) {
+	t.Setenv(accountKeyEnvVar, "")
+
+	client, err := newClient(&config.AzblobArchiver{AccountName: "account", AccountKey: testAccountKey}, http.DefaultClient)
+	require.NoError(t, err)
+	require.Equal(t, "https://account.blob.core.windows.net", client.endpoint.String())
+
+	_, err = newClient(&config.AzblobArchiver{AccountKey: testAccountKey}, http.DefaultClient)
+	require.ErrorIs(t, err, errNoAccountName)
+	_, err = newClient(&config.AzblobArchiver{AccountName: "account"}, http.DefaultClient)
+	require.ErrorIs(t, err, errNoCredentials)
+	_, err = newClient(&config.AzblobArchiver{AccountName: "account", AccountKey: "not base64!"}, http.DefaultClient)
+	require.Error(t, err)
+
+	t.Setenv(accountKeyEnvVar, testAccountKey)
+	client, err = newClient(&config.AzblobArchiver{AccountName: "account"}, http.DefaultClient)
+	require.NoError(t, err)
+	require.NotEmpty(t, client.accountKey)
+}
+
+func TestCanonicalizedResource(t *testing.T) {
+	u, err := url.Parse("https://account.blob.core.windows.net/container?restype=container&comp=list&prefix=a%2Fb&Include=metadata&include=snapshots")
+	require.NoError(t, err)
+	require.Equal(t,
+		"/account/container\ncomp:list\ninclude:metadata,snapshots\nprefix:a/b\nrestype:container",
+		canonicalizedResource("account", u),
+	)
+}
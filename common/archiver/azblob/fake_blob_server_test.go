@@ -0,0 +1,268 @@
+// The MIT License
+//
+// Copyright (c) 2020 Temporal Technologies Inc.  All rights reserved.
+//
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package azblob
+
+import (
+	"encoding/base64"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+
+	"go.temporal.io/server/common/config"
+)
+
+const (
+	testAccountName = "devstoreaccount1"
+	// base64 of "test-account-key"
+	testAccountKey = "dGVzdC1hY2NvdW50LWtleQ=="
+)
+
+type (
+	// fakeBlobServer is an in-process fake of the subset of the Blob service REST API used by
+	// Client. It uses path-style URLs like the storage emulator: /<account>/<container>/<blob>.
+	fakeBlobServer struct {
+		t      *testing.T
+		server *httptest.Server
+
+		lock       sync.Mutex
+		containers map[string]map[string][]byte
+		// failures are returned, in order, instead of handling the next requests
+		failures []int
+		requests int
+	}
+
+	fakeListResponse struct {
+		XMLName    xml.Name      `xml:"EnumerationResults"`
+		Prefix     string        `xml:"Prefix"`
+		Marker     string        `xml:"Marker"`
+		Blobs      fakeListBlobs `xml:"Blobs"`
+		NextMarker string        `xml:"NextMarker"`
+	}
+
+	fakeListBlobs struct {
+		Items []interface{}
+	}
+
+	fakeBlob struct {
+		XMLName xml.Name `xml:"Blob"`
+		Name    string   `xml:"Name"`
+	}
+
+	fakeBlobPrefix struct {
+		XMLName xml.Name `xml:"BlobPrefix"`
+		Name    string   `xml:"Name"`
+	}
+)
+
+func newFakeBlobServer(t *testing.T, containers ...string) *fakeBlobServer {
+	f := &fakeBlobServer{
+		t:          t,
+		containers: make(map[string]map[string][]byte),
+	}
+	for _, container := range containers {
+		f.containers[container] = make(map[string][]byte)
+	}
+	f.server = httptest.NewServer(http.HandlerFunc(f.handle))
+	t.Cleanup(f.server.Close)
+	return f
+}
+
+func (f *fakeBlobServer) config() *config.AzblobArchiver {
+	return &config.AzblobArchiver{
+		AccountName: testAccountName,
+		AccountKey:  testAccountKey,
+		Endpoint:    f.server.URL + "/" + testAccountName,
+	}
+}
+
+func (f *fakeBlobServer) client() Client {
+	client, err := newClient(f.config(), f.server.Client())
+	if err != nil {
+		f.t.Fatal(err)
+	}
+	return client
+}
+
+func (f *fakeBlobServer) failNext(statusCodes ...int) {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	f.failures = append(f.failures, statusCodes...)
+}
+
+func (f *fakeBlobServer) put(container string, name string, data []byte) {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	f.containers[container][name] = data
+}
+
+func (f *fakeBlobServer) blobNames(container string) []string {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	var names []string
+	for name := range f.containers[container] {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func (f *fakeBlobServer) requestCount() int {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	return f.requests
+}
+
+func (f *fakeBlobServer) handle(w http.ResponseWriter, r *http.Request) {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	f.requests++
+
+	if len(f.failures) > 0 {
+		statusCode := f.failures[0]
+		f.failures = f.failures[1:]
+		writeError(w, r, statusCode, "InjectedFailure")
+		return
+	}
+	if !f.authorized(r) {
+		writeError(w, r, http.StatusForbidden, "AuthenticationFailed")
+		return
+	}
+
+	path := strings.TrimPrefix(r.URL.Path, "/"+testAccountName+"/")
+	containerName, blobName, _ := strings.Cut(path, "/")
+	container, ok := f.containers[containerName]
+	if !ok {
+		writeError(w, r, http.StatusNotFound, errorCodeContainerNotFound)
+		return
+	}
+
+	query := r.URL.Query()
+	switch {
+	case blobName == "" && r.Method == http.MethodHead && query.Get("restype") == "container":
+		w.WriteHeader(http.StatusOK)
+	case blobName == "" && r.Method == http.MethodGet && query.Get("comp") == "list":
+		f.list(w, container, query.Get("prefix"), query.Get("delimiter"), query.Get("marker"), query.Get("maxresults"))
+	case blobName == "":
+		writeError(w, r, http.StatusBadRequest, "UnsupportedHttpVerb")
+	case r.Method == http.MethodPut:
+		if r.Header.Get("x-ms-blob-type") != "BlockBlob" {
+			writeError(w, r, http.StatusBadRequest, "InvalidBlobType")
+			return
+		}
+		data, err := io.ReadAll(r.Body)
+		if err != nil {
+			writeError(w, r, http.StatusInternalServerError, "InternalError")
+			return
+		}
+		container[blobName] = data
+		w.WriteHeader(http.StatusCreated)
+	case r.Method == http.MethodGet || r.Method == http.MethodHead:
+		data, ok := container[blobName]
+		if !ok {
+			writeError(w, r, http.StatusNotFound, errorCodeBlobNotFound)
+			return
+		}
+		w.Header().Set("Content-Length", strconv.Itoa(len(data)))
+		w.WriteHeader(http.StatusOK)
+		if r.Method == http.MethodGet {
+			_, _ = w.Write(data)
+		}
+	default:
+		writeError(w, r, http.StatusBadRequest, "UnsupportedHttpVerb")
+	}
+}
+
+func (f *fakeBlobServer) authorized(r *http.Request) bool {
+	if r.URL.Query().Get("sig") != "" {
+		return r.URL.Query().Get("sig") == "test-sig"
+	}
+	key, _ := decodeTestAccountKey()
+	expected := "SharedKey " + testAccountName + ":" + computeSignature(r, testAccountName, key)
+	return r.Header.Get("Authorization") == expected
+}
+
+func (f *fakeBlobServer) list(w http.ResponseWriter, container map[string][]byte, prefix, delimiter, marker, maxResults string) {
+	// entries are blob names and, if delimiter is set, virtual directory prefixes
+	entries := make(map[string]bool)
+	for name := range container {
+		if !strings.HasPrefix(name, prefix) {
+			continue
+		}
+		if delimiter != "" {
+			if idx := strings.Index(name[len(prefix):], delimiter); idx >= 0 {
+				entries[name[:len(prefix)+idx+len(delimiter)]] = true
+				continue
+			}
+		}
+		entries[name] = false
+	}
+	names := make([]string, 0, len(entries))
+	for name := range entries {
+		if name >= marker {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	response := fakeListResponse{Prefix: prefix, Marker: marker}
+	limit := len(names)
+	if maxResults != "" {
+		if n, err := strconv.Atoi(maxResults); err == nil && n < limit {
+			limit = n
+			response.NextMarker = names[n]
+		}
+	}
+	for _, name := range names[:limit] {
+		if entries[name] {
+			response.Blobs.Items = append(response.Blobs.Items, fakeBlobPrefix{Name: name})
+		} else {
+			response.Blobs.Items = append(response.Blobs.Items, fakeBlob{Name: name})
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/xml")
+	w.WriteHeader(http.StatusOK)
+	_, _ = w.Write([]byte(xml.Header))
+	_ = xml.NewEncoder(w).Encode(response)
+}
+
+func writeError(w http.ResponseWriter, r *http.Request, statusCode int, code string) {
+	w.Header().Set("x-ms-error-code", code)
+	w.WriteHeader(statusCode)
+	if r.Method != http.MethodHead {
+		_, _ = fmt.Fprintf(w, "%s<Error><Code>%s</Code><Message>%s</Message></Error>", xml.Header, code, http.StatusText(statusCode))
+	}
+}
+
+func decodeTestAccountKey() ([]byte, error) {
+	return base64.StdEncoding.DecodeString(testAccountKey)
+}
@@ -0,0 +1,432 @@
+// The MIT License
+//
+// Copyright (c) 2020 Temporal Technologies Inc.  All rights reserved.
+//
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package azblob
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"go.temporal.io/api/serviceerror"
+	workflowpb "go.temporal.io/api/workflow/v1"
+
+	archiverspb "go.temporal.io/server/api/archiver/v1"
+	"go.temporal.io/server/common/archiver"
+	"go.temporal.io/server/common/config"
+	"go.temporal.io/server/common/log/tag"
+	"go.temporal.io/server/common/metrics"
+	"go.temporal.io/server/common/primitives/timestamp"
+	"go.temporal.io/server/common/searchattribute"
+)
+
+type (
+	visibilityArchiver struct {
+		container   *archiver.VisibilityBootstrapContainer
+		client      Client
+		queryParser QueryParser
+	}
+
+	queryVisibilityRequest struct {
+		namespaceID   string
+		pageSize      int
+		nextPageToken []byte
+		parsedQuery   *archiver.IndexedVisibilityQuery
+	}
+
+	orderedQueryVisibilityToken struct {
+		Offset int
+	}
+
+	indexToArchive struct {
+		primaryIndex            string
+		primaryIndexValue       string
+		secondaryIndex          string
+		secondaryIndexTimestamp time.Time
+	}
+)
+
+const (
+	errEncodeVisibilityRecord       = "failed to encode visibility record"
+	secondaryIndexKeyStartTimeout   = "startTimeout"
+	secondaryIndexKeyCloseTimeout   = "closeTimeout"
+	primaryIndexKeyWorkflowTypeName = "workflowTypeName"
+	primaryIndexKeyWorkflowID       = "workflowID"
+)
+
+var (
+	// orderedQueryListPageSize is the number of blobs listed per call when reading all records of an ordered query.
+	orderedQueryListPageSize = 1000
+	// orderedQueryMaxBlobs is the maximum number of blobs an ordered query reads. Every page of an ordered query
+	// reads all of its blobs, so queries which list more blobs are rejected.
+	orderedQueryMaxBlobs = 10 * orderedQueryListPageSize
+)
+
+// NewVisibilityArchiver creates a new archiver.VisibilityArchiver based on azure blob storage
+func NewVisibilityArchiver(
+	container *archiver.VisibilityBootstrapContainer,
+	config *config.AzblobArchiver,
+) (archiver.VisibilityArchiver, error) {
+	client, err := NewClient(config)
+	if err != nil {
+		return nil, err
+	}
+	return newVisibilityArchiver(container, client), nil
+}
+
+func newVisibilityArchiver(
+	container *archiver.VisibilityBootstrapContainer,
+	client Client,
+) *visibilityArchiver {
+	return &visibilityArchiver{
+		container:   container,
+		client:      client,
+		queryParser: NewQueryParser(),
+	}
+}
+
+func (v *visibilityArchiver) Archive(
+	ctx context.Context,
+	URI archiver.URI,
+	request *archiverspb.VisibilityRecord,
+	opts ...archiver.ArchiveOption,
+) (err error) {
+	handler := v.container.MetricsHandler.WithTags(metrics.OperationTag(metrics.VisibilityArchiverScope), metrics.NamespaceTag(request.Namespace))
+	featureCatalog := archiver.GetFeatureCatalog(opts...)
+	startTime := time.Now().UTC()
+	logger := archiver.TagLoggerWithArchiveVisibilityRequestAndURI(v.container.Logger, request, URI.String())
+	archiveFailReason := ""
+	defer func() {
+		handler.Timer(metrics.ServiceLatency.Name()).Record(time.Since(startTime))
+		if err != nil {
+			if isRetryableError(err) {
+				handler.Counter(metrics.VisibilityArchiverArchiveTransientErrorCount.Name()).Record(1)
+				logger.Error(archiver.ArchiveTransientErrorMsg, tag.ArchivalArchiveFailReason(archiveFailReason), tag.Error(err))
+			} else {
+				handler.Counter(metrics.VisibilityArchiverArchiveNonRetryableErrorCount.Name()).Record(1)
+				logger.Error(archiver.ArchiveNonRetryableErrorMsg, tag.ArchivalArchiveFailReason(archiveFailReason), tag.Error(err))
+				if featureCatalog.NonRetryableError != nil {
+					err = featureCatalog.NonRetryableError()
+				}
+			}
+		}
+	}()
+
+	if err := softValidateURI(URI); err != nil {
+		archiveFailReason = archiver.ErrReasonInvalidURI
+		return err
+	}
+
+	if err := archiver.ValidateVisibilityArchivalRequest(request); err != nil {
+		archiveFailReason = archiver.ErrReasonInvalidArchiveRequest
+		return err
+	}
+
+	encodedVisibilityRecord, err := encode(request)
+	if err != nil {
+		archiveFailReason = errEncodeVisibilityRecord
+		return err
+	}
+	indexes := createIndexesToArchive(request)
+	// Upload archive to all indexes
+	for _, element := range indexes {
+		key := constructTimestampIndex(URI.Path(), request.GetNamespaceId(), element.primaryIndex, element.primaryIndexValue, element.secondaryIndex, element.secondaryIndexTimestamp, request.GetRunId())
+		if err := upload(ctx, v.client, URI, key, encodedVisibilityRecord); err != nil {
+			archiveFailReason = errWriteKey
+			return err
+		}
+	}
+	handler.Counter(metrics.VisibilityArchiveSuccessCount.Name()).Record(1)
+	return nil
+}
+
+func createIndexesToArchive(request *archiverspb.VisibilityRecord) []indexToArchive {
+	return []indexToArchive{
+		{primaryIndexKeyWorkflowTypeName, request.WorkflowTypeName, secondaryIndexKeyCloseTimeout, timestamp.TimeValue(request.CloseTime)},
+		{primaryIndexKeyWorkflowTypeName, request.WorkflowTypeName, secondaryIndexKeyStartTimeout, timestamp.TimeValue(request.StartTime)},
+		{primaryIndexKeyWorkflowID, request.GetWorkflowId(), secondaryIndexKeyCloseTimeout, timestamp.TimeValue(request.CloseTime)},
+		{primaryIndexKeyWorkflowID, request.GetWorkflowId(), secondaryIndexKeyStartTimeout, timestamp.TimeValue(request.StartTime)},
+	}
+}
+
+func (v *visibilityArchiver) Query(
+	ctx context.Context,
+	URI archiver.URI,
+	request *archiver.QueryVisibilityRequest,
+	saTypeMap searchattribute.NameTypeMap,
+) (*archiver.QueryVisibilityResponse, error) {
+
+	if err := softValidateURI(URI); err != nil {
+		return nil, serviceerror.NewInvalidArgument(archiver.ErrInvalidURI.Error())
+	}
+
+	if err := archiver.ValidateQueryRequest(request); err != nil {
+		return nil, serviceerror.NewInvalidArgument(archiver.ErrInvalidQueryVisibilityRequest.Error())
+	}
+
+	parsedQuery := &archiver.IndexedVisibilityQuery{}
+	if strings.TrimSpace(request.Query) != "" {
+		var err error
+		parsedQuery, err = v.queryParser.Parse(request.Query, saTypeMap)
+		if err != nil {
+			return nil, serviceerror.NewInvalidArgument(err.Error())
+		}
+	}
+
+	if parsedQuery.EmptyResult {
+		return &archiver.QueryVisibilityResponse{}, nil
+	}
+
+	queryRequest := &queryVisibilityRequest{
+		namespaceID:   request.NamespaceID,
+		pageSize:      request.PageSize,
+		nextPageToken: request.NextPageToken,
+		parsedQuery:   parsedQuery,
+	}
+	if parsedQuery.Query != nil && parsedQuery.Query.HasOrderBy() {
+		return v.queryOrdered(ctx, URI, queryRequest, saTypeMap)
+	}
+	return v.query(ctx, URI, queryRequest, saTypeMap)
+}
+
+func (v *visibilityArchiver) query(
+	ctx context.Context,
+	uri archiver.URI,
+	request *queryVisibilityRequest,
+	saTypeMap searchattribute.NameTypeMap,
+) (*archiver.QueryVisibilityResponse, error) {
+	prefix, keyFilter := constructQuerySearchPrefix(uri, request)
+	// remaining is the number of workflow executions left to return before we reach pageSize.
+	remaining := request.pageSize
+	nextPageToken := request.nextPageToken
+	var executions []*workflowpb.WorkflowExecutionInfo
+	// Like in s3store, we need to loop because records which don't match the query, or which are listed
+	// under more than one key of the prefix, are skipped after listing.
+	for {
+		records, token, err := v.queryPrefix(ctx, uri, &queryVisibilityRequest{
+			namespaceID:   request.namespaceID,
+			pageSize:      remaining,
+			nextPageToken: nextPageToken,
+			parsedQuery:   request.parsedQuery,
+		}, prefix, keyFilter)
+		if err != nil {
+			return nil, err
+		}
+		for _, record := range records {
+			executionInfo, err := convertToExecutionInfo(record, saTypeMap)
+			if err != nil {
+				return nil, serviceerror.NewInternal(err.Error())
+			}
+			executions = append(executions, executionInfo)
+		}
+		nextPageToken = token
+		remaining -= len(records)
+		if len(nextPageToken) == 0 || remaining <= 0 {
+			break
+		}
+	}
+	return &archiver.QueryVisibilityResponse{
+		Executions:    executions,
+		NextPageToken: nextPageToken,
+	}, nil
+}
+
+// queryOrdered handles queries with an order by clause. Blobs are listed in the order of the secondary index,
+// so all matching records have to be read and sorted before the requested page can be returned.
+func (v *visibilityArchiver) queryOrdered(
+	ctx context.Context,
+	uri archiver.URI,
+	request *queryVisibilityRequest,
+	saTypeMap searchattribute.NameTypeMap,
+) (*archiver.QueryVisibilityResponse, error) {
+	offset := 0
+	if request.nextPageToken != nil {
+		token, err := deserializeOrderedQueryVisibilityToken(request.nextPageToken)
+		if err != nil {
+			return nil, serviceerror.NewInvalidArgument(archiver.ErrNextPageTokenCorrupted.Error())
+		}
+		offset = token.Offset
+	}
+
+	prefix, keyFilter := constructQuerySearchPrefix(uri, request)
+	var records []*archiverspb.VisibilityRecord
+	var nextPageToken []byte
+	for listedBlobs := orderedQueryListPageSize; ; listedBlobs += orderedQueryListPageSize {
+		pageRecords, token, err := v.queryPrefix(ctx, uri, &queryVisibilityRequest{
+			namespaceID:   request.namespaceID,
+			pageSize:      orderedQueryListPageSize,
+			nextPageToken: nextPageToken,
+			parsedQuery:   request.parsedQuery,
+		}, prefix, keyFilter)
+		if err != nil {
+			return nil, err
+		}
+		records = append(records, pageRecords...)
+		if len(token) == 0 {
+			break
+		}
+		if listedBlobs >= orderedQueryMaxBlobs {
+			return nil, serviceerror.NewInvalidArgument(fmt.Sprintf(
+				"queries with order by can read at most %d records, narrow down the query with WorkflowId, WorkflowType or StartTime or CloseTime and SearchPrecision",
+				orderedQueryMaxBlobs,
+			))
+		}
+		nextPageToken = token
+	}
+	request.parsedQuery.Query.SortRecords(records)
+
+	response := &archiver.QueryVisibilityResponse{}
+	if offset >= len(records) {
+		return response, nil
+	}
+	end := min(offset+request.pageSize, len(records))
+	for _, record := range records[offset:end] {
+		executionInfo, err := convertToExecutionInfo(record, saTypeMap)
+		if err != nil {
+			return nil, serviceerror.NewInternal(err.Error())
+		}
+		response.Executions = append(response.Executions, executionInfo)
+	}
+	if end < len(records) {
+		encodedToken, err := serializeToken(&orderedQueryVisibilityToken{Offset: end})
+		if err != nil {
+			return nil, serviceerror.NewInternal(err.Error())
+		}
+		response.NextPageToken = encodedToken
+	}
+	return response, nil
+}
+
+// constructQuerySearchPrefix returns the prefix of the blobs to list for the query and an optional key filter.
+func constructQuerySearchPrefix(uri archiver.URI, request *queryVisibilityRequest) (string, func(key string) bool) {
+	var primaryIndex string
+	var primaryIndexValue string
+	switch {
+	case request.parsedQuery.WorkflowID != nil:
+		primaryIndex = primaryIndexKeyWorkflowID
+		primaryIndexValue = *request.parsedQuery.WorkflowID
+	case request.parsedQuery.WorkflowTypeName != nil:
+		primaryIndex = primaryIndexKeyWorkflowTypeName
+		primaryIndexValue = *request.parsedQuery.WorkflowTypeName
+	default:
+		// Every execution is stored under 4 indexes, so only the workflowTypeName/closeTimeout index is listed.
+		searchPrefix := constructVisibilitySearchPrefix(uri.Path(), request.namespaceID) + "/" + primaryIndexKeyWorkflowTypeName
+		return searchPrefix, func(key string) bool {
+			// Keys of the closeTimeout secondary index are of the form .../closeTimeout/<closeTimeout>/<runID>
+			elements := strings.Split(key, "/")
+			return len(elements) >= 3 && elements[len(elements)-3] == secondaryIndexKeyCloseTimeout
+		}
+	}
+
+	switch {
+	case request.parsedQuery.CloseTime != nil:
+		return constructTimeBasedSearchKey(
+			uri.Path(),
+			request.namespaceID,
+			primaryIndex,
+			primaryIndexValue,
+			secondaryIndexKeyCloseTimeout,
+			*request.parsedQuery.CloseTime,
+			*request.parsedQuery.SearchPrecision,
+		), nil
+	case request.parsedQuery.StartTime != nil:
+		return constructTimeBasedSearchKey(
+			uri.Path(),
+			request.namespaceID,
+			primaryIndex,
+			primaryIndexValue,
+			secondaryIndexKeyStartTimeout,
+			*request.parsedQuery.StartTime,
+			*request.parsedQuery.SearchPrecision,
+		), nil
+	default:
+		return constructIndexedVisibilitySearchPrefix(
+			uri.Path(),
+			request.namespaceID,
+			primaryIndex,
+			primaryIndexValue,
+			secondaryIndexKeyCloseTimeout,
+		) + "/", nil
+	}
+}
+
+// queryPrefix returns the visibility records in the archive that match the given prefix and the query. If keyFilter
+// returns false for a given key, that blob will not be downloaded or included in the results.
+func (v *visibilityArchiver) queryPrefix(
+	ctx context.Context,
+	uri archiver.URI,
+	request *queryVisibilityRequest,
+	prefix string,
+	keyFilter func(key string) bool,
+) ([]*archiverspb.VisibilityRecord, []byte, error) {
+	ctx, cancel := ensureContextTimeout(ctx)
+	defer cancel()
+
+	results, err := v.client.List(ctx, uri.Hostname(), ListParams{
+		Prefix:     prefix,
+		Marker:     string(request.nextPageToken),
+		MaxResults: request.pageSize,
+	})
+	if err != nil {
+		if isRetryableError(err) {
+			return nil, nil, serviceerror.NewUnavailable(err.Error())
+		}
+		return nil, nil, serviceerror.NewInvalidArgument(err.Error())
+	}
+
+	var nextPageToken []byte
+	if results.NextMarker != "" {
+		nextPageToken = []byte(results.NextMarker)
+	}
+	var records []*archiverspb.VisibilityRecord
+	for _, name := range results.Names {
+		if keyFilter != nil && !keyFilter(name) {
+			continue
+		}
+
+		encodedRecord, err := download(ctx, v.client, uri, name)
+		if err != nil {
+			return nil, nil, serviceerror.NewUnavailable(err.Error())
+		}
+
+		record, err := decodeVisibilityRecord(encodedRecord)
+		if err != nil {
+			return nil, nil, serviceerror.NewInternal(err.Error())
+		}
+		if request.parsedQuery.Query != nil && !request.parsedQuery.Query.Match(record) {
+			continue
+		}
+		records = append(records, record)
+	}
+	return records, nextPageToken, nil
+}
+
+func (v *visibilityArchiver) ValidateURI(URI archiver.URI) error {
+	err := softValidateURI(URI)
+	if err != nil {
+		return err
+	}
+	return containerExists(context.TODO(), v.client, URI)
+}
@@ -0,0 +1,373 @@
+// The MIT License
+//
+// Copyright (c) 2020 Temporal Technologies Inc.  All rights reserved.
+//
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+//go:generate mockgen -copyright_file ../../../LICENSE -package $GOPACKAGE -source $GOFILE -destination exporter_mock.go
+
+package export
+
+import (
+	"context"
+	"errors"
+	"io"
+	"path"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"go.temporal.io/api/serviceerror"
+
+	archiverspb "go.temporal.io/server/api/archiver/v1"
+	"go.temporal.io/server/common/archiver"
+	"go.temporal.io/server/common/archiver/filestore"
+	"go.temporal.io/server/common/archiver/s3store"
+	"go.temporal.io/server/common/backoff"
+	"go.temporal.io/server/common/clock"
+	"go.temporal.io/server/common/config"
+	"go.temporal.io/server/common/dynamicconfig"
+	"go.temporal.io/server/common/log"
+	"go.temporal.io/server/common/log/tag"
+	"go.temporal.io/server/common/metrics"
+	"go.temporal.io/server/common/persistence"
+)
+
+const (
+	historyDirName    = "history"
+	visibilityDirName = "visibility"
+
+	targetHistoryBlobSize = 2 * 1024 * 1024 // 2MB
+
+	// windowTimeFormat is the format of the start of the time window in the names of exported files
+	windowTimeFormat = "20060102T150405Z"
+	// writeTimeout bounds the time it takes to write the files of a batch, including retries
+	writeTimeout = 10 * time.Minute
+)
+
+var (
+	errUnknownScheme = errors.New("export URI scheme must be file or s3")
+
+	writeRetryPolicy = backoff.NewExponentialRetryPolicy(time.Second).
+				WithMaximumInterval(time.Minute).
+				WithExpirationInterval(backoff.NoInterval)
+)
+
+type (
+	// Request is a request to export a closed workflow execution.
+	Request struct {
+		Format Format
+		// History identifies the history of the workflow execution
+		History *archiver.ArchiveHistoryRequest
+		// Execution is the visibility record of the workflow execution
+		Execution *archiverspb.VisibilityRecord
+	}
+
+	// Exporter writes the histories and visibility records of closed workflow executions to files for analytics.
+	// Files are written to <URI>/history and <URI>/visibility, in directories partitioned by namespace ID and the
+	// UTC date the workflow execution closed on:
+	//
+	//	<URI>/history/namespace_id=<namespaceID>/close_date=<yyyy-mm-dd>/<window>-<uuid>.<format>
+	//	<URI>/visibility/namespace_id=<namespaceID>/close_date=<yyyy-mm-dd>/<window>-<uuid>.<format>
+	//
+	// The workflow executions exported to the same partition within a time window are batched into one history file
+	// and one visibility file, named after the UTC start of the window. A batch is written in the background when the
+	// window ends or when it reaches the max number of executions. History files have one HistoryEventRecord per
+	// event, visibility files have one ExecutionRecord per execution.
+	//
+	// Export is best effort, so that it never holds up archival: a batch that still can't be written after being
+	// retried for writeTimeout is dropped, and the batches that aren't written yet are lost when the host stops.
+	// Dropped executions are logged and counted by the export_failed_workflow_executions metric. An execution that is
+	// exported again may be in more than one file.
+	Exporter interface {
+		// Export adds the workflow execution to the batch of its partition, and returns without waiting for the
+		// batch to be written. It only fails if the execution can't be exported to the URI at all.
+		Export(URI archiver.URI, request *Request) error
+	}
+
+	exporter struct {
+		executionManager   persistence.ExecutionManager
+		fileStore          store
+		s3Store            store
+		timeSource         clock.TimeSource
+		logger             log.Logger
+		metricsHandler     metrics.Handler
+		batchInterval      dynamicconfig.DurationPropertyFn
+		maxBatchExecutions dynamicconfig.IntPropertyFn
+
+		lock    sync.Mutex
+		batches map[batchKey]*batch
+
+		// newHistoryIterator and retryPolicy are only replaced by tests
+		newHistoryIterator func(request *archiver.ArchiveHistoryRequest) archiver.HistoryIterator
+		retryPolicy        backoff.RetryPolicy
+	}
+
+	batchKey struct {
+		URI       string
+		format    Format
+		partition string
+	}
+
+	// batch is the workflow executions exported to a partition in a time window.
+	batch struct {
+		key         batchKey
+		URI         archiver.URI
+		store       store
+		windowStart time.Time
+		exports     []*Request
+	}
+
+	// exportError is the error of exporting one workflow execution of a batch.
+	exportError struct {
+		export *Request
+		err    error
+	}
+)
+
+// NewExporter creates a new Exporter. File and S3 URIs use the settings of the filestore and s3store history archival
+// providers when they are configured.
+func NewExporter(
+	executionManager persistence.ExecutionManager,
+	historyArchiverConfigs *config.HistoryArchiverProvider,
+	timeSource clock.TimeSource,
+	logger log.Logger,
+	metricsHandler metrics.Handler,
+	batchInterval dynamicconfig.DurationPropertyFn,
+	maxBatchExecutions dynamicconfig.IntPropertyFn,
+) (Exporter, error) {
+	var filestoreConfig *config.FilestoreArchiver
+	var s3Config *config.S3Archiver
+	if historyArchiverConfigs != nil {
+		filestoreConfig = historyArchiverConfigs.Filestore
+		s3Config = historyArchiverConfigs.S3store
+	}
+	fileStore, err := newFileStore(filestoreConfig)
+	if err != nil {
+		return nil, err
+	}
+	e := &exporter{
+		executionManager:   executionManager,
+		fileStore:          fileStore,
+		s3Store:            newS3Store(s3Config),
+		timeSource:         timeSource,
+		logger:             logger,
+		metricsHandler:     metricsHandler,
+		batchInterval:      batchInterval,
+		maxBatchExecutions: maxBatchExecutions,
+		batches:            make(map[batchKey]*batch),
+		retryPolicy:        writeRetryPolicy,
+	}
+	e.newHistoryIterator = func(request *archiver.ArchiveHistoryRequest) archiver.HistoryIterator {
+		return archiver.NewHistoryIterator(request, e.executionManager, targetHistoryBlobSize)
+	}
+	return e, nil
+}
+
+// ValidateURI returns an error if workflow executions can't be exported to the URI.
+func ValidateURI(URI archiver.URI) error {
+	switch URI.Scheme() {
+	case filestore.URIScheme, s3store.URIScheme:
+		return nil
+	default:
+		return errUnknownScheme
+	}
+}
+
+func (e *exporter) Export(URI archiver.URI, request *Request) error {
+	if err := ValidateURI(URI); err != nil {
+		return err
+	}
+	e.add(URI, request)
+	return nil
+}
+
+// add adds the export to the open batch of its partition, and starts a batch if there is none.
+func (e *exporter) add(URI archiver.URI, request *Request) {
+	key := batchKey{
+		URI:    URI.String(),
+		format: request.Format,
+		partition: path.Join(
+			"namespace_id="+request.Execution.GetNamespaceId(),
+			"close_date="+request.Execution.GetCloseTime().AsTime().UTC().Format(time.DateOnly),
+		),
+	}
+
+	e.lock.Lock()
+	defer e.lock.Unlock()
+
+	b, ok := e.batches[key]
+	if !ok {
+		store := e.fileStore
+		if URI.Scheme() == s3store.URIScheme {
+			store = e.s3Store
+		}
+		b = &batch{key: key, URI: URI, store: store, windowStart: e.timeSource.Now().UTC()}
+		e.batches[key] = b
+		// the timer isn't stopped when the batch is written early, it finds that the batch is no longer open
+		e.timeSource.AfterFunc(e.batchInterval(), func() {
+			e.closeBatch(b)
+		})
+	}
+	b.exports = append(b.exports, request)
+	if len(b.exports) >= e.maxBatchExecutions() {
+		delete(e.batches, key)
+		go e.writeBatch(b)
+	}
+}
+
+// closeBatch writes the batch at the end of its time window, unless it was already written because it was full.
+func (e *exporter) closeBatch(b *batch) {
+	e.lock.Lock()
+	defer e.lock.Unlock()
+
+	if e.batches[b.key] != b {
+		return
+	}
+	delete(e.batches, b.key)
+	go e.writeBatch(b)
+}
+
+// writeBatch writes the files of the batch, and retries until writeTimeout if they can't be written. Executions
+// whose history was already deleted are left out of the batch.
+func (e *exporter) writeBatch(b *batch) {
+	ctx, cancel := context.WithTimeout(context.Background(), writeTimeout)
+	defer cancel()
+
+	exports := b.exports
+	err := backoff.ThrottleRetryContext(ctx, func(ctx context.Context) error {
+		for len(exports) > 0 {
+			err := e.write(ctx, b, exports)
+			var exportErr *exportError
+			var notFound *serviceerror.NotFound
+			if !errors.As(err, &exportErr) || !errors.As(exportErr.err, &notFound) {
+				return err
+			}
+			// the workflow execution was already deleted, write the batch again without it
+			exports = removeExport(exports, exportErr.export)
+		}
+		return nil
+	}, e.retryPolicy, nil)
+
+	namespaceTag := metrics.NamespaceTag(b.exports[0].Execution.GetNamespace())
+	if err != nil {
+		e.metricsHandler.Counter(metrics.ExportFailedWorkflowExecutions.Name()).Record(int64(len(exports)), namespaceTag)
+		e.logger.Error("Failed to export workflow executions, they are dropped.",
+			tag.ArchivalURI(b.key.URI),
+			tag.NewStringTag("partition", b.key.partition),
+			tag.Counter(len(exports)),
+			tag.Error(err),
+		)
+		return
+	}
+	e.metricsHandler.Counter(metrics.ExportedWorkflowExecutions.Name()).Record(int64(len(exports)), namespaceTag)
+}
+
+// write writes the history file and the visibility file of the exports of the batch. A new file name is used for
+// every attempt, a failed attempt doesn't leave any file behind.
+func (e *exporter) write(ctx context.Context, b *batch, exports []*Request) error {
+	fileName := b.windowStart.Format(windowTimeFormat) + "-" + uuid.NewString() + "." + string(b.key.format)
+	// history goes first so that every exported visibility record has its history exported
+	err := b.store.Put(ctx, b.URI, path.Join(historyDirName, b.key.partition, fileName), func(w io.Writer) error {
+		return e.encodeHistories(ctx, b.key.format, exports, w)
+	})
+	if err != nil {
+		return err
+	}
+	return b.store.Put(ctx, b.URI, path.Join(visibilityDirName, b.key.partition, fileName), func(w io.Writer) error {
+		return encodeExecutions(b.key.format, exports, w)
+	})
+}
+
+// encodeHistories writes the histories of the workflow executions to w one history blob at a time, so that a whole
+// history is never kept in memory.
+func (e *exporter) encodeHistories(ctx context.Context, format Format, exports []*Request, w io.Writer) error {
+	encoder, err := newRecordEncoder(format, new(HistoryEventRecord), w)
+	if err != nil {
+		return err
+	}
+	for _, request := range exports {
+		if err := e.encodeHistory(ctx, request, encoder); err != nil {
+			return &exportError{export: request, err: err}
+		}
+	}
+	return encoder.Close()
+}
+
+func (e *exporter) encodeHistory(ctx context.Context, request *Request, encoder recordEncoder) error {
+	historyIterator := e.newHistoryIterator(request.History)
+	for historyIterator.HasNext() {
+		historyBlob, err := historyIterator.Next(ctx)
+		if err != nil {
+			return err
+		}
+		for _, history := range historyBlob.Body {
+			for _, event := range history.Events {
+				record, err := NewHistoryEventRecord(request.Execution, event)
+				if err != nil {
+					return err
+				}
+				if err := encoder.Encode(record); err != nil {
+					return err
+				}
+			}
+		}
+		if err := encoder.Flush(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func encodeExecutions(format Format, exports []*Request, w io.Writer) error {
+	encoder, err := newRecordEncoder(format, new(ExecutionRecord), w)
+	if err != nil {
+		return err
+	}
+	for _, request := range exports {
+		record, err := NewExecutionRecord(request.Execution)
+		if err != nil {
+			return err
+		}
+		if err := encoder.Encode(record); err != nil {
+			return err
+		}
+	}
+	return encoder.Close()
+}
+
+func removeExport(exports []*Request, export *Request) []*Request {
+	result := make([]*Request, 0, len(exports)-1)
+	for _, request := range exports {
+		if request != export {
+			result = append(result, request)
+		}
+	}
+	return result
+}
+
+func (e *exportError) Error() string {
+	return e.err.Error()
+}
+
+func (e *exportError) Unwrap() error {
+	return e.err
+}
@@ -0,0 +1,410 @@
+// The MIT License
+//
+// Copyright (c) 2020 Temporal Technologies Inc.  All rights reserved.
+//
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package export
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+	commonpb "go.temporal.io/api/common/v1"
+	enumspb "go.temporal.io/api/enums/v1"
+	historypb "go.temporal.io/api/history/v1"
+	"go.temporal.io/api/serviceerror"
+	taskqueuepb "go.temporal.io/api/taskqueue/v1"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	archiverspb "go.temporal.io/server/api/archiver/v1"
+	"go.temporal.io/server/common/archiver"
+	"go.temporal.io/server/common/backoff"
+	"go.temporal.io/server/common/clock"
+	"go.temporal.io/server/common/dynamicconfig"
+	"go.temporal.io/server/common/log"
+	"go.temporal.io/server/common/metrics"
+	"go.temporal.io/server/common/metrics/metricstest"
+	"go.temporal.io/server/common/payload"
+)
+
+const testBatchInterval = time.Minute
+
+var (
+	testStartTime   = time.Date(2024, 3, 4, 23, 59, 0, 0, time.UTC)
+	testCloseTime   = time.Date(2024, 3, 5, 0, 1, 0, 0, time.UTC)
+	testWindowStart = time.Date(2024, 3, 5, 0, 6, 0, 0, time.UTC)
+)
+
+func TestExport_JSONL(t *testing.T) {
+	dir := t.TempDir()
+	URI, err := archiver.NewURI("file://" + dir)
+	require.NoError(t, err)
+
+	e, _, capture := newTestExporter(t, 2, map[string]error{"run-1": nil, "run-2": nil})
+	exportAll(t, e, URI, testRequest(FormatJSONL, "run-1"), testRequest(FormatJSONL, "run-2"))
+	waitForExports(t, capture, metrics.ExportedWorkflowExecutions.Name(), 2)
+
+	// the two executions are batched into one file
+	var events []HistoryEventRecord
+	for _, line := range readLines(t, onlyFile(t, dir, "history", testWindowStart, FormatJSONL)) {
+		var event HistoryEventRecord
+		require.NoError(t, json.Unmarshal(line, &event))
+		events = append(events, event)
+	}
+	require.ElementsMatch(t, append(testHistoryEventRecords("run-1"), testHistoryEventRecords("run-2")...), events)
+
+	var executions []ExecutionRecord
+	for _, line := range readLines(t, onlyFile(t, dir, "visibility", testWindowStart, FormatJSONL)) {
+		var execution ExecutionRecord
+		require.NoError(t, json.Unmarshal(line, &execution))
+		executions = append(executions, execution)
+	}
+	require.ElementsMatch(t, []ExecutionRecord{testExecutionRecord("run-1"), testExecutionRecord("run-2")}, executions)
+}
+
+func TestExport_Parquet(t *testing.T) {
+	dir := t.TempDir()
+	URI, err := archiver.NewURI("file://" + dir)
+	require.NoError(t, err)
+
+	e, _, capture := newTestExporter(t, 1, map[string]error{"run-1": nil})
+	exportAll(t, e, URI, testRequest(FormatParquet, "run-1"))
+	waitForExports(t, capture, metrics.ExportedWorkflowExecutions.Name(), 1)
+
+	events, numRowGroups := readParquet[HistoryEventRecord](t, readFile(t, onlyFile(t, dir, "history", testWindowStart, FormatParquet)))
+	require.Equal(t, testHistoryEventRecords("run-1"), events)
+	// every history blob is written as a row group
+	require.Equal(t, 2, numRowGroups)
+
+	executions, _ := readParquet[ExecutionRecord](t, readFile(t, onlyFile(t, dir, "visibility", testWindowStart, FormatParquet)))
+	require.Equal(t, []ExecutionRecord{testExecutionRecord("run-1")}, executions)
+}
+
+func TestExport_BatchWindow(t *testing.T) {
+	dir := t.TempDir()
+	URI, err := archiver.NewURI("file://" + dir)
+	require.NoError(t, err)
+
+	e, timeSource, capture := newTestExporter(t, 100, map[string]error{"run-1": nil, "run-2": nil, "run-3": nil})
+	otherDay := testRequest(FormatJSONL, "run-3")
+	otherDay.Execution.CloseTime = timestamppb.New(testCloseTime.Add(24 * time.Hour))
+
+	// export returns without waiting for the end of the window
+	exportAll(t, e, URI, testRequest(FormatJSONL, "run-1"), testRequest(FormatJSONL, "run-2"), otherDay)
+	e.lock.Lock()
+	require.Len(t, e.batches, 2)
+	require.Len(t, e.batches[testBatchKey(URI, "2024-03-05")].exports, 2)
+	e.lock.Unlock()
+
+	// nothing is written before the end of the window
+	timeSource.Advance(testBatchInterval - time.Second)
+	require.Empty(t, listFiles(t, dir))
+	timeSource.Advance(time.Second)
+	waitForExports(t, capture, metrics.ExportedWorkflowExecutions.Name(), 3)
+
+	require.Len(t, readLines(t, onlyFile(t, dir, "visibility", testWindowStart, FormatJSONL)), 2)
+	otherDayFiles, err := filepath.Glob(path.Join(dir, "visibility/namespace_id=namespace-id/close_date=2024-03-06/*.jsonl"))
+	require.NoError(t, err)
+	require.Len(t, otherDayFiles, 1)
+	require.Len(t, readLines(t, otherDayFiles[0]), 1)
+}
+
+func TestExport_HistoryNotFound(t *testing.T) {
+	dir := t.TempDir()
+	URI, err := archiver.NewURI("file://" + dir)
+	require.NoError(t, err)
+
+	e, _, capture := newTestExporter(t, 2, map[string]error{
+		"run-1": serviceerror.NewNotFound("history not found"),
+		"run-2": nil,
+	})
+	exportAll(t, e, URI, testRequest(FormatJSONL, "run-1"), testRequest(FormatJSONL, "run-2"))
+	waitForExports(t, capture, metrics.ExportedWorkflowExecutions.Name(), 1)
+
+	// the batch is written without the execution that was already deleted
+	lines := readLines(t, onlyFile(t, dir, "visibility", testWindowStart, FormatJSONL))
+	require.Len(t, lines, 1)
+	var execution ExecutionRecord
+	require.NoError(t, json.Unmarshal(lines[0], &execution))
+	require.Equal(t, testExecutionRecord("run-2"), execution)
+	require.Len(t, listFiles(t, dir), 2)
+}
+
+func TestExport_InvalidURI(t *testing.T) {
+	URI, err := archiver.NewURI("gs://bucket/path")
+	require.NoError(t, err)
+
+	e, _, _ := newTestExporter(t, 1, nil)
+	require.ErrorIs(t, e.Export(URI, testRequest(FormatJSONL, "run-1")), errUnknownScheme)
+}
+
+func TestExport_WriteFailure(t *testing.T) {
+	// the export directory can't be created under a file
+	file := filepath.Join(t.TempDir(), "file")
+	require.NoError(t, os.WriteFile(file, nil, 0644))
+	URI, err := archiver.NewURI("file://" + file)
+	require.NoError(t, err)
+
+	e, _, capture := newTestExporter(t, 2, map[string]error{"run-1": nil, "run-2": nil})
+	e.retryPolicy = backoff.NewExponentialRetryPolicy(time.Millisecond).WithMaximumAttempts(2)
+	exportAll(t, e, URI, testRequest(FormatJSONL, "run-1"), testRequest(FormatJSONL, "run-2"))
+
+	// the batch is dropped once it was retried
+	waitForExports(t, capture, metrics.ExportFailedWorkflowExecutions.Name(), 2)
+	require.Empty(t, capture.Snapshot()[metrics.ExportedWorkflowExecutions.Name()])
+}
+
+func TestParseFormat(t *testing.T) {
+	format, err := ParseFormat("parquet")
+	require.NoError(t, err)
+	require.Equal(t, FormatParquet, format)
+
+	_, err = ParseFormat("csv")
+	require.Error(t, err)
+}
+
+// newTestExporter returns an exporter that writes a batch when it has maxBatchExecutions executions, and the histories
+// of the given runs, or their error. The returned capture records the metrics of the exporter.
+func newTestExporter(
+	t *testing.T,
+	maxBatchExecutions int,
+	runs map[string]error,
+) (*exporter, *clock.EventTimeSource, *metricstest.Capture) {
+	timeSource := clock.NewEventTimeSource().Update(testWindowStart)
+	metricsHandler := metricstest.NewCaptureHandler()
+	capture := metricsHandler.StartCapture()
+	t.Cleanup(func() {
+		metricsHandler.StopCapture(capture)
+	})
+	e, err := NewExporter(
+		nil,
+		nil,
+		timeSource,
+		log.NewTestLogger(),
+		metricsHandler,
+		dynamicconfig.GetDurationPropertyFn(testBatchInterval),
+		dynamicconfig.GetIntPropertyFn(maxBatchExecutions),
+	)
+	require.NoError(t, err)
+	e.(*exporter).newHistoryIterator = func(request *archiver.ArchiveHistoryRequest) archiver.HistoryIterator {
+		err, ok := runs[request.RunID]
+		require.True(t, ok, request.RunID)
+		return testHistoryIterator(t, err)
+	}
+	return e.(*exporter), timeSource, capture
+}
+
+func exportAll(t *testing.T, e *exporter, URI archiver.URI, requests ...*Request) {
+	for _, request := range requests {
+		require.NoError(t, e.Export(URI, request))
+	}
+}
+
+// waitForExports waits until the counter of the exporter adds up to the number of workflow executions, which happens
+// once their batches were written or dropped.
+func waitForExports(t *testing.T, capture *metricstest.Capture, counter string, executions int64) {
+	require.Eventually(t, func() bool {
+		var total int64
+		for _, recording := range capture.Snapshot()[counter] {
+			require.Equal(t, "namespace", recording.Tags["namespace"])
+			total += recording.Value.(int64)
+		}
+		return total == executions
+	}, 5*time.Second, time.Millisecond)
+}
+
+func testHistoryIterator(t *testing.T, err error) archiver.HistoryIterator {
+	historyIterator := archiver.NewMockHistoryIterator(gomock.NewController(t))
+	historyIterator.EXPECT().HasNext().Return(true)
+	if err != nil {
+		historyIterator.EXPECT().Next(gomock.Any()).Return(nil, err)
+		return historyIterator
+	}
+	historyIterator.EXPECT().HasNext().Return(true)
+	historyIterator.EXPECT().HasNext().Return(false)
+	historyIterator.EXPECT().Next(gomock.Any()).Return(&archiverspb.HistoryBlob{
+		Header: &archiverspb.HistoryBlobHeader{},
+		Body: []*historypb.History{{Events: []*historypb.HistoryEvent{
+			{
+				EventId:   1,
+				EventTime: timestamppb.New(testStartTime),
+				EventType: enumspb.EVENT_TYPE_WORKFLOW_EXECUTION_STARTED,
+				Version:   5,
+				TaskId:    100,
+				Attributes: &historypb.HistoryEvent_WorkflowExecutionStartedEventAttributes{
+					WorkflowExecutionStartedEventAttributes: &historypb.WorkflowExecutionStartedEventAttributes{
+						WorkflowType: &commonpb.WorkflowType{Name: "workflow-type"},
+						TaskQueue:    &taskqueuepb.TaskQueue{Name: "task-queue"},
+					},
+				},
+			},
+		}}},
+	}, nil)
+	historyIterator.EXPECT().Next(gomock.Any()).Return(&archiverspb.HistoryBlob{
+		Header: &archiverspb.HistoryBlobHeader{IsLast: true},
+		Body: []*historypb.History{{Events: []*historypb.HistoryEvent{
+			{
+				EventId:   2,
+				EventTime: timestamppb.New(testCloseTime),
+				EventType: enumspb.EVENT_TYPE_WORKFLOW_EXECUTION_COMPLETED,
+				Version:   5,
+				TaskId:    101,
+			},
+		}}},
+	}, nil)
+	return historyIterator
+}
+
+func testRequest(format Format, runID string) *Request {
+	return &Request{
+		Format: format,
+		History: &archiver.ArchiveHistoryRequest{
+			NamespaceID: "namespace-id",
+			Namespace:   "namespace",
+			WorkflowID:  "workflow-id",
+			RunID:       runID,
+		},
+		Execution: &archiverspb.VisibilityRecord{
+			NamespaceId:      "namespace-id",
+			Namespace:        "namespace",
+			WorkflowId:       "workflow-id",
+			RunId:            runID,
+			WorkflowTypeName: "workflow-type",
+			StartTime:        timestamppb.New(testStartTime),
+			CloseTime:        timestamppb.New(testCloseTime),
+			Status:           enumspb.WORKFLOW_EXECUTION_STATUS_COMPLETED,
+			HistoryLength:    2,
+			Memo: &commonpb.Memo{Fields: map[string]*commonpb.Payload{
+				"note": payload.EncodeString("hello"),
+			}},
+			SearchAttributes: map[string]string{"CustomKeywordField": "value"},
+		},
+	}
+}
+
+func testBatchKey(URI archiver.URI, closeDate string) batchKey {
+	return batchKey{
+		URI:       URI.String(),
+		format:    FormatJSONL,
+		partition: "namespace_id=namespace-id/close_date=" + closeDate,
+	}
+}
+
+func testHistoryEventRecords(runID string) []HistoryEventRecord {
+	return []HistoryEventRecord{
+		{
+			NamespaceID:  "namespace-id",
+			Namespace:    "namespace",
+			WorkflowID:   "workflow-id",
+			RunID:        runID,
+			WorkflowType: "workflow-type",
+			EventID:      1,
+			EventTime:    testStartTime.UnixMicro(),
+			EventType:    enumspb.EVENT_TYPE_WORKFLOW_EXECUTION_STARTED.String(),
+			Version:      5,
+			TaskID:       100,
+			Attributes:   `{"workflowType":{"name":"workflow-type"},"taskQueue":{"name":"task-queue"}}`,
+		},
+		{
+			NamespaceID:  "namespace-id",
+			Namespace:    "namespace",
+			WorkflowID:   "workflow-id",
+			RunID:        runID,
+			WorkflowType: "workflow-type",
+			EventID:      2,
+			EventTime:    testCloseTime.UnixMicro(),
+			EventType:    enumspb.EVENT_TYPE_WORKFLOW_EXECUTION_COMPLETED.String(),
+			Version:      5,
+			TaskID:       101,
+			Attributes:   "{}",
+		},
+	}
+}
+
+func testExecutionRecord(runID string) ExecutionRecord {
+	startTime := testStartTime.UnixMicro()
+	closeTime := testCloseTime.UnixMicro()
+	return ExecutionRecord{
+		NamespaceID:      "namespace-id",
+		Namespace:        "namespace",
+		WorkflowID:       "workflow-id",
+		RunID:            runID,
+		WorkflowType:     "workflow-type",
+		StartTime:        &startTime,
+		CloseTime:        &closeTime,
+		Status:           enumspb.WORKFLOW_EXECUTION_STATUS_COMPLETED.String(),
+		HistoryLength:    2,
+		Memo:             `{"note":"hello"}`,
+		SearchAttributes: `{"CustomKeywordField":"value"}`,
+	}
+}
+
+// onlyFile returns the path of the only file in the 2024-03-05 partition of dirName, and checks that it's named after
+// the start of its window.
+func onlyFile(t *testing.T, dir string, dirName string, windowStart time.Time, format Format) string {
+	files, err := filepath.Glob(path.Join(dir, dirName, "namespace_id=namespace-id/close_date=2024-03-05/*"))
+	require.NoError(t, err)
+	require.Len(t, files, 1)
+	name := filepath.Base(files[0])
+	require.True(t, strings.HasPrefix(name, windowStart.Format(windowTimeFormat)+"-"), name)
+	require.True(t, strings.HasSuffix(name, "."+string(format)), name)
+	return files[0]
+}
+
+func listFiles(t *testing.T, dir string) []string {
+	var files []string
+	err := filepath.WalkDir(dir, func(filePath string, entry os.DirEntry, err error) error {
+		require.NoError(t, err)
+		if !entry.IsDir() {
+			files = append(files, filePath)
+		}
+		return nil
+	})
+	require.NoError(t, err)
+	return files
+}
+
+func readLines(t *testing.T, filePath string) [][]byte {
+	data := readFile(t, filePath)
+	var lines [][]byte
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		lines = append(lines, bytes.Clone(scanner.Bytes()))
+	}
+	require.NoError(t, scanner.Err())
+	return lines
+}
+
+func readFile(t *testing.T, filePath string) []byte {
+	data, err := os.ReadFile(filePath)
+	require.NoError(t, err)
+	return data
+}
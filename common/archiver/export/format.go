@@ -0,0 +1,95 @@
+// The MIT License
+//
+// Copyright (c) 2020 Temporal Technologies Inc.  All rights reserved.
+//
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package export
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+)
+
+type (
+	// Format is the file format of exported records.
+	Format string
+
+	// recordEncoder encodes records of one type into a file of the export format, which is written to an io.Writer.
+	recordEncoder interface {
+		Encode(record any) error
+		// Flush writes the records encoded so far, so that they don't have to be kept in memory.
+		Flush() error
+		// Close flushes the encoder and writes the end of the file.
+		Close() error
+	}
+
+	jsonlEncoder struct {
+		writer  *bufio.Writer
+		encoder *json.Encoder
+	}
+)
+
+const (
+	// FormatJSONL writes one JSON object per line.
+	FormatJSONL Format = "jsonl"
+	// FormatParquet writes Apache Parquet files.
+	FormatParquet Format = "parquet"
+)
+
+// ParseFormat returns the Format with the given name.
+func ParseFormat(name string) (Format, error) {
+	switch format := Format(name); format {
+	case FormatJSONL, FormatParquet:
+		return format, nil
+	default:
+		return "", fmt.Errorf("unknown export format: %q", name)
+	}
+}
+
+// newRecordEncoder returns an encoder for records of the same type as schema, which writes to output.
+func newRecordEncoder(format Format, schema any, output io.Writer) (recordEncoder, error) {
+	switch format {
+	case FormatJSONL:
+		e := &jsonlEncoder{writer: bufio.NewWriter(output)}
+		e.encoder = json.NewEncoder(e.writer)
+		e.encoder.SetEscapeHTML(false)
+		return e, nil
+	case FormatParquet:
+		return newParquetEncoder(schema, output)
+	default:
+		return nil, fmt.Errorf("unknown export format: %q", format)
+	}
+}
+
+func (e *jsonlEncoder) Encode(record any) error {
+	return e.encoder.Encode(record)
+}
+
+func (e *jsonlEncoder) Flush() error {
+	return e.writer.Flush()
+}
+
+func (e *jsonlEncoder) Close() error {
+	return e.writer.Flush()
+}
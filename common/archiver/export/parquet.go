@@ -0,0 +1,422 @@
+// The MIT License
+//
+// Copyright (c) 2020 Temporal Technologies Inc.  All rights reserved.
+//
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package export
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"reflect"
+	"strings"
+
+	"github.com/klauspost/compress/s2"
+)
+
+// The exported records only have flat columns of strings, integers and timestamps, so Parquet files are written by
+// this minimal writer instead of a general purpose library: every column of a row group is a single PLAIN encoded,
+// snappy compressed data page. The values of the Parquet enums and the ids of the fields of its Thrift structures
+// are defined by https://github.com/apache/parquet-format/blob/master/src/main/thrift/parquet.thrift.
+
+const (
+	parquetMagic     = "PAR1"
+	parquetVersion   = 1
+	parquetCreatedBy = "temporal"
+
+	parquetTypeInt64     = 2
+	parquetTypeByteArray = 6
+
+	parquetRepetitionRequired = 0
+	parquetRepetitionOptional = 1
+
+	parquetConvertedTypeUTF8            = 0
+	parquetConvertedTypeTimestampMicros = 10
+
+	parquetEncodingPlain = 0
+	parquetEncodingRLE   = 3
+
+	parquetCodecSnappy = 1
+
+	parquetPageTypeData = 0
+
+	// types of the Thrift compact protocol
+	compactTypeI32    = 5
+	compactTypeI64    = 6
+	compactTypeBinary = 8
+	compactTypeList   = 9
+	compactTypeStruct = 12
+)
+
+type (
+	// parquetEncoder writes records of one struct type to a Parquet file. Every flush writes a row group, so only the
+	// records of the current row group are kept in memory.
+	parquetEncoder struct {
+		output     io.Writer
+		recordType reflect.Type
+		columns    []*parquetColumn
+		// offset is the number of bytes written to output
+		offset    int64
+		numRows   int64
+		rowGroups []*parquetRowGroup
+	}
+
+	// parquetColumn is a column of the file, and the values of the current row group in it.
+	parquetColumn struct {
+		name          string
+		fieldIndex    int
+		physicalType  int32
+		convertedType int32
+		optional      bool
+
+		numValues int32
+		// values are the PLAIN encoded values of the current row group, nulls are left out
+		values bytes.Buffer
+		// definitionLevels are 0 for null and 1 for set values of optional columns
+		definitionLevels []byte
+	}
+
+	parquetRowGroup struct {
+		numRows       int64
+		totalByteSize int64
+		columnChunks  []*parquetColumnChunk
+	}
+
+	parquetColumnChunk struct {
+		column           *parquetColumn
+		numValues        int64
+		offset           int64
+		uncompressedSize int64
+		compressedSize   int64
+	}
+
+	// compactWriter encodes Thrift structures with the compact protocol.
+	compactWriter struct {
+		buf         bytes.Buffer
+		lastFieldID int16
+	}
+)
+
+// newParquetEncoder returns an encoder for records of the same type as schema, which must be a pointer to a struct.
+// Every field of the struct is a column, named by its parquet tag. string fields are UTF8 columns, and int64 fields
+// are INT64 columns, or timestamps in microseconds if the tag has the timestamp option. Fields of pointer types are
+// optional columns.
+func newParquetEncoder(schema any, output io.Writer) (*parquetEncoder, error) {
+	recordType := reflect.TypeOf(schema)
+	if recordType.Kind() != reflect.Pointer || recordType.Elem().Kind() != reflect.Struct {
+		return nil, fmt.Errorf("parquet schema must be a pointer to a struct, got %v", recordType)
+	}
+	recordType = recordType.Elem()
+	e := &parquetEncoder{output: output, recordType: recordType}
+	for i := 0; i < recordType.NumField(); i++ {
+		column, err := newParquetColumn(recordType.Field(i), i)
+		if err != nil {
+			return nil, err
+		}
+		e.columns = append(e.columns, column)
+	}
+	if err := e.write([]byte(parquetMagic)); err != nil {
+		return nil, err
+	}
+	return e, nil
+}
+
+func newParquetColumn(field reflect.StructField, fieldIndex int) (*parquetColumn, error) {
+	name, option, _ := strings.Cut(field.Tag.Get("parquet"), ",")
+	if name == "" {
+		return nil, fmt.Errorf("field %s has no parquet column name", field.Name)
+	}
+	column := &parquetColumn{name: name, fieldIndex: fieldIndex, convertedType: -1}
+	fieldType := field.Type
+	if fieldType.Kind() == reflect.Pointer {
+		column.optional = true
+		fieldType = fieldType.Elem()
+	}
+	switch {
+	case fieldType.Kind() == reflect.String && option == "":
+		column.physicalType = parquetTypeByteArray
+		column.convertedType = parquetConvertedTypeUTF8
+	case fieldType.Kind() == reflect.Int64 && option == "":
+		column.physicalType = parquetTypeInt64
+	case fieldType.Kind() == reflect.Int64 && option == "timestamp":
+		column.physicalType = parquetTypeInt64
+		column.convertedType = parquetConvertedTypeTimestampMicros
+	default:
+		return nil, fmt.Errorf("field %s of type %v with option %q can't be a parquet column", field.Name, field.Type, option)
+	}
+	return column, nil
+}
+
+func (e *parquetEncoder) Encode(record any) error {
+	value := reflect.ValueOf(record)
+	if value.Kind() != reflect.Pointer || value.Type().Elem() != e.recordType {
+		return fmt.Errorf("parquet record must be a *%v, got %T", e.recordType, record)
+	}
+	value = value.Elem()
+	for _, column := range e.columns {
+		column.add(value.Field(column.fieldIndex))
+	}
+	e.numRows++
+	return nil
+}
+
+func (c *parquetColumn) add(value reflect.Value) {
+	c.numValues++
+	if c.optional {
+		if value.IsNil() {
+			c.definitionLevels = append(c.definitionLevels, 0)
+			return
+		}
+		c.definitionLevels = append(c.definitionLevels, 1)
+		value = value.Elem()
+	}
+	switch c.physicalType {
+	case parquetTypeByteArray:
+		_ = binary.Write(&c.values, binary.LittleEndian, uint32(value.Len()))
+		c.values.WriteString(value.String())
+	case parquetTypeInt64:
+		_ = binary.Write(&c.values, binary.LittleEndian, value.Int())
+	}
+}
+
+// Flush writes the records encoded since the last flush as a row group.
+func (e *parquetEncoder) Flush() error {
+	if e.numRows == 0 {
+		return nil
+	}
+	rowGroup := &parquetRowGroup{numRows: e.numRows}
+	for _, column := range e.columns {
+		columnChunk, err := e.writeColumnChunk(column)
+		if err != nil {
+			return err
+		}
+		rowGroup.totalByteSize += columnChunk.uncompressedSize
+		rowGroup.columnChunks = append(rowGroup.columnChunks, columnChunk)
+	}
+	e.rowGroups = append(e.rowGroups, rowGroup)
+	e.numRows = 0
+	return nil
+}
+
+// writeColumnChunk writes the values of the column in the current row group as a single data page.
+func (e *parquetEncoder) writeColumnChunk(column *parquetColumn) (*parquetColumnChunk, error) {
+	var page bytes.Buffer
+	if column.optional {
+		levels := encodeDefinitionLevels(column.definitionLevels)
+		_ = binary.Write(&page, binary.LittleEndian, uint32(len(levels)))
+		page.Write(levels)
+	}
+	page.Write(column.values.Bytes())
+	compressed := s2.EncodeSnappy(nil, page.Bytes())
+
+	var header compactWriter
+	header.i32(1, parquetPageTypeData)
+	header.i32(2, int32(page.Len()))
+	header.i32(3, int32(len(compressed)))
+	header.structField(5, func() {
+		header.i32(1, column.numValues)
+		header.i32(2, parquetEncodingPlain)
+		header.i32(3, parquetEncodingRLE)
+		header.i32(4, parquetEncodingRLE)
+	})
+	header.stop()
+
+	columnChunk := &parquetColumnChunk{
+		column:           column,
+		numValues:        int64(column.numValues),
+		offset:           e.offset,
+		uncompressedSize: int64(header.buf.Len() + page.Len()),
+		compressedSize:   int64(header.buf.Len() + len(compressed)),
+	}
+	if err := e.write(header.buf.Bytes()); err != nil {
+		return nil, err
+	}
+	if err := e.write(compressed); err != nil {
+		return nil, err
+	}
+	column.numValues = 0
+	column.values.Reset()
+	column.definitionLevels = column.definitionLevels[:0]
+	return columnChunk, nil
+}
+
+// encodeDefinitionLevels encodes levels of bit width 1 with the RLE/bit-packing hybrid encoding, as runs of repeated
+// values.
+func encodeDefinitionLevels(levels []byte) []byte {
+	var encoded []byte
+	for start := 0; start < len(levels); {
+		end := start + 1
+		for end < len(levels) && levels[end] == levels[start] {
+			end++
+		}
+		encoded = binary.AppendUvarint(encoded, uint64(end-start)<<1)
+		encoded = append(encoded, levels[start])
+		start = end
+	}
+	return encoded
+}
+
+// Close flushes the encoder and writes the footer of the file.
+func (e *parquetEncoder) Close() error {
+	if err := e.Flush(); err != nil {
+		return err
+	}
+
+	var numRows int64
+	for _, rowGroup := range e.rowGroups {
+		numRows += rowGroup.numRows
+	}
+	var footer compactWriter
+	footer.i32(1, parquetVersion)
+	footer.listField(2, compactTypeStruct, len(e.columns)+1)
+	footer.structValue(func() {
+		footer.binary(4, "schema")
+		footer.i32(5, int32(len(e.columns)))
+	})
+	for _, column := range e.columns {
+		footer.structValue(func() {
+			footer.i32(1, column.physicalType)
+			repetition := int32(parquetRepetitionRequired)
+			if column.optional {
+				repetition = parquetRepetitionOptional
+			}
+			footer.i32(3, repetition)
+			footer.binary(4, column.name)
+			if column.convertedType >= 0 {
+				footer.i32(6, column.convertedType)
+			}
+		})
+	}
+	footer.i64(3, numRows)
+	footer.listField(4, compactTypeStruct, len(e.rowGroups))
+	for _, rowGroup := range e.rowGroups {
+		footer.structValue(func() {
+			footer.listField(1, compactTypeStruct, len(rowGroup.columnChunks))
+			for _, columnChunk := range rowGroup.columnChunks {
+				footer.structValue(func() {
+					footer.i64(2, columnChunk.offset)
+					footer.structField(3, func() {
+						footer.i32(1, columnChunk.column.physicalType)
+						footer.listField(2, compactTypeI32, 2)
+						footer.varint(parquetEncodingPlain)
+						footer.varint(parquetEncodingRLE)
+						footer.listField(3, compactTypeBinary, 1)
+						footer.uvarint(uint64(len(columnChunk.column.name)))
+						footer.buf.WriteString(columnChunk.column.name)
+						footer.i32(4, parquetCodecSnappy)
+						footer.i64(5, columnChunk.numValues)
+						footer.i64(6, columnChunk.uncompressedSize)
+						footer.i64(7, columnChunk.compressedSize)
+						footer.i64(9, columnChunk.offset)
+					})
+				})
+			}
+			footer.i64(2, rowGroup.totalByteSize)
+			footer.i64(3, rowGroup.numRows)
+		})
+	}
+	footer.binary(6, parquetCreatedBy)
+	footer.stop()
+
+	if err := e.write(footer.buf.Bytes()); err != nil {
+		return err
+	}
+	if err := e.write(binary.LittleEndian.AppendUint32(nil, uint32(footer.buf.Len()))); err != nil {
+		return err
+	}
+	return e.write([]byte(parquetMagic))
+}
+
+func (e *parquetEncoder) write(data []byte) error {
+	n, err := e.output.Write(data)
+	e.offset += int64(n)
+	return err
+}
+
+func (w *compactWriter) fieldHeader(id int16, fieldType byte) {
+	if delta := id - w.lastFieldID; delta > 0 && delta <= 15 {
+		w.buf.WriteByte(byte(delta)<<4 | fieldType)
+	} else {
+		w.buf.WriteByte(fieldType)
+		w.varint(int64(id))
+	}
+	w.lastFieldID = id
+}
+
+func (w *compactWriter) i32(id int16, value int32) {
+	w.fieldHeader(id, compactTypeI32)
+	w.varint(int64(value))
+}
+
+func (w *compactWriter) i64(id int16, value int64) {
+	w.fieldHeader(id, compactTypeI64)
+	w.varint(value)
+}
+
+func (w *compactWriter) binary(id int16, value string) {
+	w.fieldHeader(id, compactTypeBinary)
+	w.uvarint(uint64(len(value)))
+	w.buf.WriteString(value)
+}
+
+// listField writes the header of a list field, its elements are written next.
+func (w *compactWriter) listField(id int16, elementType byte, size int) {
+	w.fieldHeader(id, compactTypeList)
+	if size < 15 {
+		w.buf.WriteByte(byte(size)<<4 | elementType)
+		return
+	}
+	w.buf.WriteByte(0xf0 | elementType)
+	w.uvarint(uint64(size))
+}
+
+// structField writes a struct field, whose fields are written by writeFields.
+func (w *compactWriter) structField(id int16, writeFields func()) {
+	w.fieldHeader(id, compactTypeStruct)
+	w.structValue(writeFields)
+}
+
+// structValue writes a struct that is an element of a list, whose fields are written by writeFields.
+func (w *compactWriter) structValue(writeFields func()) {
+	lastFieldID := w.lastFieldID
+	w.lastFieldID = 0
+	writeFields()
+	w.stop()
+	w.lastFieldID = lastFieldID
+}
+
+// stop ends the fields of a struct.
+func (w *compactWriter) stop() {
+	w.buf.WriteByte(0)
+}
+
+// varint writes a zigzag encoded integer.
+func (w *compactWriter) varint(value int64) {
+	w.uvarint(uint64(value<<1) ^ uint64(value>>63))
+}
+
+func (w *compactWriter) uvarint(value uint64) {
+	var buf [binary.MaxVarintLen64]byte
+	w.buf.Write(buf[:binary.PutUvarint(buf[:], value)])
+}
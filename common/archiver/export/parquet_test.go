@@ -0,0 +1,251 @@
+// The MIT License
+//
+// Copyright (c) 2020 Temporal Technologies Inc.  All rights reserved.
+//
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package export
+
+import (
+	"bytes"
+	"encoding/binary"
+	"reflect"
+	"testing"
+
+	"github.com/klauspost/compress/s2"
+	"github.com/stretchr/testify/require"
+)
+
+type (
+	// compactReader decodes Thrift structures encoded with the compact protocol into maps from field id to value.
+	compactReader struct {
+		t    *testing.T
+		data []byte
+		pos  int
+	}
+)
+
+func TestParquetEncoder(t *testing.T) {
+	var records []ExecutionRecord
+	for i := 0; i < 40; i++ {
+		record := testExecutionRecord("run-1")
+		record.HistoryLength = int64(i)
+		if i%3 == 0 {
+			record.StartTime = nil
+		}
+		if i < 20 {
+			record.CloseTime = nil
+		}
+		records = append(records, record)
+	}
+
+	var output bytes.Buffer
+	encoder, err := newParquetEncoder(new(ExecutionRecord), &output)
+	require.NoError(t, err)
+	for i := range records {
+		require.NoError(t, encoder.Encode(&records[i]))
+		if i == 9 {
+			require.NoError(t, encoder.Flush())
+		}
+	}
+	require.NoError(t, encoder.Close())
+
+	decoded, numRowGroups := readParquet[ExecutionRecord](t, output.Bytes())
+	require.Equal(t, records, decoded)
+	require.Equal(t, 2, numRowGroups)
+}
+
+func TestParquetEncoder_Empty(t *testing.T) {
+	var output bytes.Buffer
+	encoder, err := newParquetEncoder(new(HistoryEventRecord), &output)
+	require.NoError(t, err)
+	require.NoError(t, encoder.Flush())
+	require.NoError(t, encoder.Close())
+
+	decoded, numRowGroups := readParquet[HistoryEventRecord](t, output.Bytes())
+	require.Empty(t, decoded)
+	require.Zero(t, numRowGroups)
+}
+
+func TestParquetEncoder_InvalidSchema(t *testing.T) {
+	_, err := newParquetEncoder(ExecutionRecord{}, new(bytes.Buffer))
+	require.Error(t, err)
+
+	_, err = newParquetEncoder(new(struct {
+		Count int `parquet:"count"`
+	}), new(bytes.Buffer))
+	require.Error(t, err)
+
+	encoder, err := newParquetEncoder(new(ExecutionRecord), new(bytes.Buffer))
+	require.NoError(t, err)
+	require.Error(t, encoder.Encode(new(HistoryEventRecord)))
+}
+
+// readParquet decodes a file written by parquetEncoder into records of type T, and returns its number of row groups.
+func readParquet[T any](t *testing.T, data []byte) ([]T, int) {
+	require.True(t, bytes.HasPrefix(data, []byte(parquetMagic)))
+	require.True(t, bytes.HasSuffix(data, []byte(parquetMagic)))
+	footerEnd := len(data) - 8
+	footerLength := int(binary.LittleEndian.Uint32(data[footerEnd:]))
+	footer := (&compactReader{t: t, data: data[footerEnd-footerLength : footerEnd]}).readStruct()
+
+	// the schema of the file is the schema of T
+	encoder, err := newParquetEncoder(new(T), new(bytes.Buffer))
+	require.NoError(t, err)
+	schema := footer[2].([]any)
+	require.Len(t, schema, len(encoder.columns)+1)
+	for i, column := range encoder.columns {
+		element := schema[i+1].(map[int16]any)
+		require.Equal(t, column.name, element[4])
+		require.Equal(t, int64(column.physicalType), element[1])
+		require.Equal(t, column.optional, element[3] == int64(parquetRepetitionOptional))
+	}
+
+	var records []T
+	rowGroups := footer[4].([]any)
+	for _, rowGroup := range rowGroups {
+		numRows := int(rowGroup.(map[int16]any)[3].(int64))
+		first := len(records)
+		records = append(records, make([]T, numRows)...)
+		for i, columnChunk := range rowGroup.(map[int16]any)[1].([]any) {
+			column := encoder.columns[i]
+			metadata := columnChunk.(map[int16]any)[3].(map[int16]any)
+			require.Equal(t, []any{column.name}, metadata[3])
+			require.Equal(t, int64(parquetCodecSnappy), metadata[4])
+			require.Equal(t, int64(numRows), metadata[5])
+
+			pageReader := &compactReader{t: t, data: data[metadata[9].(int64):]}
+			pageHeader := pageReader.readStruct()
+			require.Equal(t, int64(numRows), pageHeader[5].(map[int16]any)[1])
+			compressedSize := int(pageHeader[3].(int64))
+			page, err := s2.Decode(nil, pageReader.data[pageReader.pos:pageReader.pos+compressedSize])
+			require.NoError(t, err)
+			require.Len(t, page, int(pageHeader[2].(int64)))
+
+			var definitionLevels []byte
+			if column.optional {
+				levelsLength := int(binary.LittleEndian.Uint32(page))
+				definitionLevels = decodeDefinitionLevels(t, page[4:4+levelsLength])
+				require.Len(t, definitionLevels, numRows)
+				page = page[4+levelsLength:]
+			}
+			values := bytes.NewReader(page)
+			for row := 0; row < numRows; row++ {
+				field := reflect.ValueOf(&records[first+row]).Elem().Field(column.fieldIndex)
+				if column.optional {
+					if definitionLevels[row] == 0 {
+						continue
+					}
+					field.Set(reflect.New(field.Type().Elem()))
+					field = field.Elem()
+				}
+				switch column.physicalType {
+				case parquetTypeByteArray:
+					var length uint32
+					require.NoError(t, binary.Read(values, binary.LittleEndian, &length))
+					value := make([]byte, length)
+					_, err := values.Read(value)
+					require.NoError(t, err)
+					field.SetString(string(value))
+				case parquetTypeInt64:
+					var value int64
+					require.NoError(t, binary.Read(values, binary.LittleEndian, &value))
+					field.SetInt(value)
+				}
+			}
+			require.Zero(t, values.Len())
+		}
+	}
+	require.Equal(t, int64(len(records)), footer[3])
+	return records, len(rowGroups)
+}
+
+// decodeDefinitionLevels decodes levels of bit width 1 encoded in runs of repeated values.
+func decodeDefinitionLevels(t *testing.T, encoded []byte) []byte {
+	var levels []byte
+	for len(encoded) > 0 {
+		header, n := binary.Uvarint(encoded)
+		require.Positive(t, n)
+		require.Zero(t, header&1, "bit-packed runs are not written")
+		levels = append(levels, bytes.Repeat(encoded[n:n+1], int(header>>1))...)
+		encoded = encoded[n+1:]
+	}
+	return levels
+}
+
+func (r *compactReader) readStruct() map[int16]any {
+	fields := make(map[int16]any)
+	var id int16
+	for {
+		header := r.data[r.pos]
+		r.pos++
+		if header == 0 {
+			return fields
+		}
+		if delta := int16(header >> 4); delta != 0 {
+			id += delta
+		} else {
+			id = int16(r.varint())
+		}
+		fields[id] = r.readValue(header & 0x0f)
+	}
+}
+
+func (r *compactReader) readValue(valueType byte) any {
+	switch valueType {
+	case compactTypeI32, compactTypeI64:
+		return r.varint()
+	case compactTypeBinary:
+		length := int(r.uvarint())
+		value := string(r.data[r.pos : r.pos+length])
+		r.pos += length
+		return value
+	case compactTypeList:
+		header := r.data[r.pos]
+		r.pos++
+		size := int(header >> 4)
+		if size == 15 {
+			size = int(r.uvarint())
+		}
+		list := make([]any, size)
+		for i := range list {
+			list[i] = r.readValue(header & 0x0f)
+		}
+		return list
+	case compactTypeStruct:
+		return r.readStruct()
+	default:
+		require.Failf(r.t, "unexpected thrift type", "type %d", valueType)
+		return nil
+	}
+}
+
+func (r *compactReader) varint() int64 {
+	value := r.uvarint()
+	return int64(value>>1) ^ -int64(value&1)
+}
+
+func (r *compactReader) uvarint() uint64 {
+	value, n := binary.Uvarint(r.data[r.pos:])
+	require.Positive(r.t, n)
+	r.pos += n
+	return value
+}
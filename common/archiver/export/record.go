@@ -0,0 +1,182 @@
+// The MIT License
+//
+// Copyright (c) 2020 Temporal Technologies Inc.  All rights reserved.
+//
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package export
+
+import (
+	"bytes"
+	"encoding/json"
+
+	commonpb "go.temporal.io/api/common/v1"
+	historypb "go.temporal.io/api/history/v1"
+	"go.temporal.io/sdk/converter"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	archiverspb "go.temporal.io/server/api/archiver/v1"
+	"go.temporal.io/server/common/payload"
+)
+
+type (
+	// HistoryEventRecord is an exported history event. Its columns are the same for all event types, the attributes
+	// that are specific to the type of the event are kept in Attributes as a JSON object.
+	HistoryEventRecord struct {
+		NamespaceID  string `json:"namespace_id" parquet:"namespace_id"`
+		Namespace    string `json:"namespace" parquet:"namespace"`
+		WorkflowID   string `json:"workflow_id" parquet:"workflow_id"`
+		RunID        string `json:"run_id" parquet:"run_id"`
+		WorkflowType string `json:"workflow_type" parquet:"workflow_type"`
+		EventID      int64  `json:"event_id" parquet:"event_id"`
+		// EventTime is in microseconds since the Unix epoch
+		EventTime  int64  `json:"event_time" parquet:"event_time,timestamp"`
+		EventType  string `json:"event_type" parquet:"event_type"`
+		Version    int64  `json:"version" parquet:"version"`
+		TaskID     int64  `json:"task_id" parquet:"task_id"`
+		Attributes string `json:"attributes" parquet:"attributes"`
+	}
+
+	// ExecutionRecord is the exported visibility record of a closed workflow execution. Times are in microseconds
+	// since the Unix epoch, times which are not set are null in Parquet and omitted in JSONL. Memo is a JSON object
+	// of the memo values, and SearchAttributes is a JSON object of the string representations of the search
+	// attribute values.
+	ExecutionRecord struct {
+		NamespaceID      string `json:"namespace_id" parquet:"namespace_id"`
+		Namespace        string `json:"namespace" parquet:"namespace"`
+		WorkflowID       string `json:"workflow_id" parquet:"workflow_id"`
+		RunID            string `json:"run_id" parquet:"run_id"`
+		WorkflowType     string `json:"workflow_type" parquet:"workflow_type"`
+		StartTime        *int64 `json:"start_time,omitempty" parquet:"start_time,timestamp"`
+		ExecutionTime    *int64 `json:"execution_time,omitempty" parquet:"execution_time,timestamp"`
+		CloseTime        *int64 `json:"close_time,omitempty" parquet:"close_time,timestamp"`
+		Status           string `json:"status" parquet:"status"`
+		HistoryLength    int64  `json:"history_length" parquet:"history_length"`
+		Memo             string `json:"memo" parquet:"memo"`
+		SearchAttributes string `json:"search_attributes" parquet:"search_attributes"`
+	}
+)
+
+// NewHistoryEventRecord converts a history event of a workflow execution to its exported record.
+func NewHistoryEventRecord(execution *archiverspb.VisibilityRecord, event *historypb.HistoryEvent) (*HistoryEventRecord, error) {
+	attributes, err := eventAttributes(event)
+	if err != nil {
+		return nil, err
+	}
+	return &HistoryEventRecord{
+		NamespaceID:  execution.GetNamespaceId(),
+		Namespace:    execution.GetNamespace(),
+		WorkflowID:   execution.GetWorkflowId(),
+		RunID:        execution.GetRunId(),
+		WorkflowType: execution.GetWorkflowTypeName(),
+		EventID:      event.GetEventId(),
+		EventTime:    event.GetEventTime().AsTime().UnixMicro(),
+		EventType:    event.GetEventType().String(),
+		Version:      event.GetVersion(),
+		TaskID:       event.GetTaskId(),
+		Attributes:   attributes,
+	}, nil
+}
+
+// NewExecutionRecord converts the visibility record of a closed workflow execution to its exported record.
+func NewExecutionRecord(execution *archiverspb.VisibilityRecord) (*ExecutionRecord, error) {
+	memo, err := memoJSON(execution.GetMemo())
+	if err != nil {
+		return nil, err
+	}
+	searchAttributes, err := json.Marshal(nonNilMap(execution.GetSearchAttributes()))
+	if err != nil {
+		return nil, err
+	}
+	return &ExecutionRecord{
+		NamespaceID:      execution.GetNamespaceId(),
+		Namespace:        execution.GetNamespace(),
+		WorkflowID:       execution.GetWorkflowId(),
+		RunID:            execution.GetRunId(),
+		WorkflowType:     execution.GetWorkflowTypeName(),
+		StartTime:        unixMicro(execution.GetStartTime()),
+		ExecutionTime:    unixMicro(execution.GetExecutionTime()),
+		CloseTime:        unixMicro(execution.GetCloseTime()),
+		Status:           execution.GetStatus().String(),
+		HistoryLength:    execution.GetHistoryLength(),
+		Memo:             memo,
+		SearchAttributes: string(searchAttributes),
+	}, nil
+}
+
+// eventAttributes returns the attributes of the event type as JSON, or an empty object if the event has none.
+func eventAttributes(event *historypb.HistoryEvent) (string, error) {
+	message := event.ProtoReflect()
+	field := message.WhichOneof(message.Descriptor().Oneofs().ByName("attributes"))
+	if field == nil {
+		return "{}", nil
+	}
+	data, err := protojson.Marshal(message.Get(field).Message().Interface())
+	if err != nil {
+		return "", err
+	}
+	// protojson output isn't stable, compact it so that the same event is always exported the same way
+	var compacted bytes.Buffer
+	if err := json.Compact(&compacted, data); err != nil {
+		return "", err
+	}
+	return compacted.String(), nil
+}
+
+// memoJSON returns the memo as a JSON object. JSON encoded values are embedded as they are, other values are replaced
+// by their string representation.
+func memoJSON(memo *commonpb.Memo) (string, error) {
+	fields := make(map[string]json.RawMessage, len(memo.GetFields()))
+	for key, value := range memo.GetFields() {
+		var compacted bytes.Buffer
+		if string(value.GetMetadata()[converter.MetadataEncoding]) == converter.MetadataEncodingJSON &&
+			json.Compact(&compacted, value.GetData()) == nil {
+			fields[key] = compacted.Bytes()
+			continue
+		}
+		data, err := json.Marshal(payload.ToString(value))
+		if err != nil {
+			return "", err
+		}
+		fields[key] = data
+	}
+	data, err := json.Marshal(fields)
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
+
+func nonNilMap(m map[string]string) map[string]string {
+	if m == nil {
+		return map[string]string{}
+	}
+	return m
+}
+
+func unixMicro(t *timestamppb.Timestamp) *int64 {
+	if t == nil {
+		return nil
+	}
+	micros := t.AsTime().UnixMicro()
+	return &micros
+}
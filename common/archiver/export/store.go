@@ -0,0 +1,174 @@
+// The MIT License
+//
+// Copyright (c) 2020 Temporal Technologies Inc.  All rights reserved.
+//
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package export
+
+import (
+	"context"
+	"errors"
+	"io"
+	"os"
+	"path"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/aws/aws-sdk-go/service/s3/s3manager"
+
+	"go.temporal.io/server/common/archiver"
+	"go.temporal.io/server/common/config"
+)
+
+const (
+	defaultFileMode = 0644
+	defaultDirMode  = 0755
+)
+
+var (
+	errNoS3Config   = errors.New("export to s3 requires the s3store archival provider to be configured")
+	errUploadFailed = errors.New("upload to s3 failed")
+)
+
+type (
+	// store writes exported files under the path of an export URI.
+	store interface {
+		// Put streams the content written by write to the file at key. The file is only created if write
+		// returns nil.
+		Put(ctx context.Context, URI archiver.URI, key string, write func(w io.Writer) error) error
+	}
+
+	fileStore struct {
+		fileMode os.FileMode
+		dirMode  os.FileMode
+	}
+
+	s3Store struct {
+		config *config.S3Archiver
+
+		once     sync.Once
+		uploader *s3manager.Uploader
+		err      error
+	}
+)
+
+// newFileStore returns a store for file URIs, which uses the file and directory modes of the filestore archival
+// provider when it's configured.
+func newFileStore(cfg *config.FilestoreArchiver) (*fileStore, error) {
+	s := &fileStore{fileMode: defaultFileMode, dirMode: defaultDirMode}
+	if cfg == nil {
+		return s, nil
+	}
+	fileMode, err := strconv.ParseUint(cfg.FileMode, 0, 32)
+	if err != nil {
+		return nil, err
+	}
+	dirMode, err := strconv.ParseUint(cfg.DirMode, 0, 32)
+	if err != nil {
+		return nil, err
+	}
+	s.fileMode = os.FileMode(fileMode)
+	s.dirMode = os.FileMode(dirMode)
+	return s, nil
+}
+
+func (s *fileStore) Put(_ context.Context, URI archiver.URI, key string, write func(w io.Writer) error) (retError error) {
+	filePath := path.Join(URI.Path(), key)
+	if err := os.MkdirAll(path.Dir(filePath), s.dirMode); err != nil {
+		return err
+	}
+	// write to a temporary file first so that readers never see a partially written file
+	tmpPath := filePath + ".tmp"
+	file, err := os.OpenFile(tmpPath, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, s.fileMode)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if retError != nil {
+			_ = file.Close()
+			_ = os.Remove(tmpPath)
+		}
+	}()
+	if err := write(file); err != nil {
+		return err
+	}
+	if err := file.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmpPath, filePath)
+}
+
+// newS3Store returns a store for s3 URIs, which uses the region and endpoint of the s3store archival provider.
+func newS3Store(cfg *config.S3Archiver) *s3Store {
+	return &s3Store{config: cfg}
+}
+
+func (s *s3Store) Put(ctx context.Context, URI archiver.URI, key string, write func(w io.Writer) error) error {
+	uploader, err := s.getUploader()
+	if err != nil {
+		return err
+	}
+	// the uploader reads the content as it's written and uploads it in parts, the upload is aborted if write fails
+	reader, writer := io.Pipe()
+	writeErrC := make(chan error, 1)
+	go func() {
+		err := write(writer)
+		_ = writer.CloseWithError(err)
+		writeErrC <- err
+	}()
+	_, err = uploader.UploadWithContext(ctx, &s3manager.UploadInput{
+		Bucket: aws.String(URI.Hostname()),
+		Key:    aws.String(strings.TrimLeft(path.Join(URI.Path(), key), "/")),
+		Body:   reader,
+	})
+	// unblock write if the upload failed before reading all of the content
+	_ = reader.CloseWithError(errUploadFailed)
+	if writeErr := <-writeErrC; writeErr != nil && !errors.Is(writeErr, errUploadFailed) {
+		return writeErr
+	}
+	return err
+}
+
+func (s *s3Store) getUploader() (*s3manager.Uploader, error) {
+	s.once.Do(func() {
+		if s.config == nil {
+			s.err = errNoS3Config
+			return
+		}
+		sess, err := session.NewSession(&aws.Config{
+			Endpoint:         s.config.Endpoint,
+			Region:           aws.String(s.config.Region),
+			S3ForcePathStyle: aws.Bool(s.config.S3ForcePathStyle),
+			LogLevel:         (*aws.LogLevelType)(&s.config.LogLevel),
+		})
+		if err != nil {
+			s.err = err
+			return
+		}
+		s.uploader = s3manager.NewUploaderWithClient(s3.New(sess))
+	})
+	return s.uploader, s.err
+}
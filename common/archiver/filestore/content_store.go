@@ -0,0 +1,70 @@
+// The MIT License
+//
+// Copyright (c) 2020 Temporal Technologies Inc.  All rights reserved.
+//
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package filestore
+
+import (
+	"context"
+	"os"
+	"path"
+
+	"go.temporal.io/server/common/archiver"
+)
+
+const payloadDirName = "payloads"
+
+type (
+	// contentStore stores deduplicated payloads of a namespace in <dir>/payloads/<namespaceID>/<yyyy-mm>/<hash>
+	contentStore struct {
+		dirPath  string
+		fileMode os.FileMode
+		dirMode  os.FileMode
+	}
+)
+
+var _ archiver.ContentStore = (*contentStore)(nil)
+
+func newContentStore(dirPath string, namespaceID string, fileMode os.FileMode, dirMode os.FileMode) *contentStore {
+	return &contentStore{
+		dirPath:  path.Join(dirPath, payloadDirName, namespaceID),
+		fileMode: fileMode,
+		dirMode:  dirMode,
+	}
+}
+
+func (s *contentStore) Exists(_ context.Context, key string) (bool, error) {
+	return fileExists(path.Join(s.dirPath, key))
+}
+
+func (s *contentStore) Put(_ context.Context, key string, data []byte) error {
+	filePath := path.Join(s.dirPath, key)
+	if err := mkdirAll(path.Dir(filePath), s.dirMode); err != nil {
+		return err
+	}
+	return writeFile(filePath, data, s.fileMode)
+}
+
+func (s *contentStore) Get(_ context.Context, key string) ([]byte, error) {
+	return readFile(path.Join(s.dirPath, key))
+}
@@ -26,7 +26,8 @@
 
 // Each Archive() request results in a file named in the format of
 // hash(namespaceID, workflowID, runID)_version.history being created in the specified
-// directory. Workflow histories stored in that file are encoded in JSON format.
+// directory. Workflow histories stored in that file are encoded in JSON format, optionally
+// compressed and with large payloads deduplicated by content hash, see archiver.HistoryBlobCodec.
 
 // The Get() method retrieves the archived histories from the directory specified in the
 // URI. It optionally takes in a NextPageToken which specifies the workflow close failover
@@ -77,6 +78,7 @@ type (
 		container *archiver.HistoryBootstrapContainer
 		fileMode  os.FileMode
 		dirMode   os.FileMode
+		blobCodec *archiver.HistoryBlobCodec
 
 		// only set in test code
 		historyIterator archiver.HistoryIterator
@@ -92,13 +94,15 @@ type (
 func NewHistoryArchiver(
 	container *archiver.HistoryBootstrapContainer,
 	config *config.FilestoreArchiver,
+	compression *config.HistoryArchiveCompression,
 ) (archiver.HistoryArchiver, error) {
-	return newHistoryArchiver(container, config, nil)
+	return newHistoryArchiver(container, config, compression, nil)
 }
 
 func newHistoryArchiver(
 	container *archiver.HistoryBootstrapContainer,
 	config *config.FilestoreArchiver,
+	compression *config.HistoryArchiveCompression,
 	historyIterator archiver.HistoryIterator,
 ) (*historyArchiver, error) {
 	fileMode, err := strconv.ParseUint(config.FileMode, 0, 32)
@@ -109,10 +113,15 @@ func newHistoryArchiver(
 	if err != nil {
 		return nil, errInvalidDirMode
 	}
+	blobCodec, err := archiver.NewHistoryBlobCodec(compression)
+	if err != nil {
+		return nil, err
+	}
 	return &historyArchiver{
 		container:       container,
 		fileMode:        os.FileMode(fileMode),
 		dirMode:         os.FileMode(dirMode),
+		blobCodec:       blobCodec,
 		historyIterator: historyIterator,
 	}, nil
 }
@@ -176,19 +185,19 @@ func (h *historyArchiver) Archive(
 		historyBatches = append(historyBatches, historyBlob.Body...)
 	}
 
-	encoder := codec.NewJSONPBEncoder()
-	encodedHistoryBatches, err := encoder.EncodeHistories(historyBatches)
-	if err != nil {
-		logger.Error(archiver.ArchiveNonRetryableErrorMsg, tag.ArchivalArchiveFailReason(errEncodeHistory), tag.Error(err))
-		return err
-	}
-
 	dirPath := URI.Path()
 	if err = mkdirAll(dirPath, h.dirMode); err != nil {
 		logger.Error(archiver.ArchiveNonRetryableErrorMsg, tag.ArchivalArchiveFailReason(errMakeDirectory), tag.Error(err))
 		return err
 	}
 
+	contentStore := newContentStore(dirPath, request.NamespaceID, h.fileMode, h.dirMode)
+	encodedHistoryBatches, err := h.blobCodec.EncodeHistory(ctx, contentStore, historyBatches, encodeHistories)
+	if err != nil {
+		logger.Error(archiver.ArchiveNonRetryableErrorMsg, tag.ArchivalArchiveFailReason(errEncodeHistory), tag.Error(err))
+		return err
+	}
+
 	filename := constructHistoryFilename(request.NamespaceID, request.WorkflowID, request.RunID, request.CloseFailoverVersion)
 	if err := writeFile(path.Join(dirPath, filename), encodedHistoryBatches, h.fileMode); err != nil {
 		logger.Error(archiver.ArchiveNonRetryableErrorMsg, tag.ArchivalArchiveFailReason(errWriteFile), tag.Error(err))
@@ -252,7 +261,12 @@ func (h *historyArchiver) Get(
 		return nil, serviceerror.NewNotFound(archiver.ErrHistoryNotExist.Error())
 	}
 
-	encodedHistoryBatches, err := readFile(filepath)
+	blob, err := readFile(filepath)
+	if err != nil {
+		return nil, serviceerror.NewInternal(err.Error())
+	}
+
+	manifest, encodedHistoryBatches, err := archiver.DecodeHistoryBlob(blob)
 	if err != nil {
 		return nil, serviceerror.NewInternal(err.Error())
 	}
@@ -276,6 +290,11 @@ func (h *historyArchiver) Get(
 		}
 	}
 
+	contentStore := newContentStore(dirPath, request.NamespaceID, h.fileMode, h.dirMode)
+	if err := archiver.ResolveHistoryPayloads(ctx, contentStore, manifest, response.HistoryBatches); err != nil {
+		return nil, serviceerror.NewInternal(err.Error())
+	}
+
 	if numOfBatches < len(historyBatches) {
 		token.NextBatchIdx += numOfBatches
 		nextToken, err := serializeToken(token)
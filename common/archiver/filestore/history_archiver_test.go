@@ -25,6 +25,7 @@
 package filestore
 
 import (
+	"bytes"
 	"context"
 	"errors"
 	"os"
@@ -33,6 +34,7 @@ import (
 	"time"
 
 	enumspb "go.temporal.io/api/enums/v1"
+	"google.golang.org/protobuf/proto"
 	"google.golang.org/protobuf/types/known/timestamppb"
 
 	"go.temporal.io/server/tests/testutils"
@@ -48,6 +50,7 @@ import (
 	"go.temporal.io/server/common/archiver"
 	"go.temporal.io/server/common/config"
 	"go.temporal.io/server/common/log"
+	"go.temporal.io/server/common/payloads"
 )
 
 const (
@@ -571,12 +574,128 @@ func (s *historyArchiverSuite) TestArchiveAndGet() {
 	s.Equal(s.historyBatchesV100, response.HistoryBatches)
 }
 
+func (s *historyArchiverSuite) TestArchiveAndGet_CompressedAndDeduplicated() {
+	mockCtrl := gomock.NewController(s.T())
+	defer mockCtrl.Finish()
+
+	largeInput := payloads.EncodeBytes(bytes.Repeat([]byte{1}, 4096))
+	historyBatches := []*historypb.History{
+		{
+			Events: []*historypb.HistoryEvent{
+				{
+					EventId:   common.FirstEventID,
+					EventTime: timestamppb.New(time.Date(2020, 8, 22, 1, 2, 3, 4, time.UTC)),
+					Version:   testCloseFailoverVersion,
+					Attributes: &historypb.HistoryEvent_WorkflowExecutionStartedEventAttributes{
+						WorkflowExecutionStartedEventAttributes: &historypb.WorkflowExecutionStartedEventAttributes{
+							Input: largeInput,
+						},
+					},
+				},
+			},
+		},
+		s.historyBatchesV100[1],
+	}
+
+	dir := testutils.MkdirTemp(s.T(), "", "TestArchiveAndGet_CompressedAndDeduplicated")
+	URI, err := archiver.NewURI("file://" + dir)
+	s.NoError(err)
+
+	// archive two runs sharing the same large input
+	for _, runID := range []string{testRunID, testRunID + "-2"} {
+		historyIterator := archiver.NewMockHistoryIterator(mockCtrl)
+		gomock.InOrder(
+			historyIterator.EXPECT().HasNext().Return(true),
+			historyIterator.EXPECT().Next(gomock.Any()).Return(&archiverspb.HistoryBlob{
+				Header: &archiverspb.HistoryBlobHeader{IsLast: true},
+				Body:   historyBatches,
+			}, nil),
+			historyIterator.EXPECT().HasNext().Return(false),
+		)
+		historyArchiver := s.newTestHistoryArchiverWithCompression(historyIterator, &config.HistoryArchiveCompression{
+			Codec:          archiver.CompressionZstd,
+			DedupThreshold: 1024,
+		})
+		err = historyArchiver.Archive(context.Background(), URI, &archiver.ArchiveHistoryRequest{
+			NamespaceID:          testNamespaceID,
+			Namespace:            testNamespace,
+			WorkflowID:           testWorkflowID,
+			RunID:                runID,
+			BranchToken:          testBranchToken,
+			NextEventID:          testNextEventID,
+			CloseFailoverVersion: testCloseFailoverVersion,
+		})
+		s.NoError(err)
+	}
+
+	// the payload is stored once, under the month it was archived in
+	payloadDir := path.Join(dir, payloadDirName, testNamespaceID, time.Now().UTC().Format("2006-01"))
+	payloadFiles, err := listFiles(payloadDir)
+	s.NoError(err)
+	s.Len(payloadFiles, 1)
+
+	// reading does not depend on the compression config
+	historyArchiver := s.newTestHistoryArchiver(nil)
+	getRequest := &archiver.GetHistoryRequest{
+		NamespaceID: testNamespaceID,
+		WorkflowID:  testWorkflowID,
+		RunID:       testRunID + "-2",
+		PageSize:    1,
+	}
+	var combinedHistory []*historypb.History
+	for {
+		response, err := historyArchiver.Get(context.Background(), URI, getRequest)
+		s.NoError(err)
+		combinedHistory = append(combinedHistory, response.HistoryBatches...)
+		if response.NextPageToken == nil {
+			break
+		}
+		getRequest.NextPageToken = response.NextPageToken
+	}
+	s.Len(combinedHistory, len(historyBatches))
+	for i := range historyBatches {
+		s.True(proto.Equal(historyBatches[i], combinedHistory[i]))
+	}
+}
+
+func (s *historyArchiverSuite) TestGet_Success_LegacyBlobWithCompressionEnabled() {
+	historyArchiver := s.newTestHistoryArchiverWithCompression(nil, &config.HistoryArchiveCompression{
+		Codec:          archiver.CompressionGzip,
+		DedupThreshold: 1024,
+	})
+	request := &archiver.GetHistoryRequest{
+		NamespaceID: testNamespaceID,
+		WorkflowID:  testWorkflowID,
+		RunID:       testRunID,
+		PageSize:    testPageSize,
+	}
+	URI, err := archiver.NewURI("file://" + s.testGetDirectory)
+	s.NoError(err)
+	response, err := historyArchiver.Get(context.Background(), URI, request)
+	s.NoError(err)
+	s.Nil(response.NextPageToken)
+	s.Equal(s.historyBatchesV100, response.HistoryBatches)
+}
+
 func (s *historyArchiverSuite) newTestHistoryArchiver(historyIterator archiver.HistoryIterator) *historyArchiver {
 	config := &config.FilestoreArchiver{
 		FileMode: testFileModeStr,
 		DirMode:  testDirModeStr,
 	}
-	archiver, err := newHistoryArchiver(s.container, config, historyIterator)
+	archiver, err := newHistoryArchiver(s.container, config, nil, historyIterator)
+	s.NoError(err)
+	return archiver
+}
+
+func (s *historyArchiverSuite) newTestHistoryArchiverWithCompression(
+	historyIterator archiver.HistoryIterator,
+	compression *config.HistoryArchiveCompression,
+) *historyArchiver {
+	config := &config.FilestoreArchiver{
+		FileMode: testFileModeStr,
+		DirMode:  testDirModeStr,
+	}
+	archiver, err := newHistoryArchiver(s.container, config, compression, historyIterator)
 	s.NoError(err)
 	return archiver
 }
@@ -27,24 +27,21 @@
 package filestore
 
 import (
-	"errors"
-	"fmt"
-	"strconv"
 	"strings"
 	"time"
 
 	"github.com/temporalio/sqlparser"
 	enumspb "go.temporal.io/api/enums/v1"
 
-	"go.temporal.io/server/common/convert"
-	"go.temporal.io/server/common/primitives/timestamp"
+	"go.temporal.io/server/common/archiver"
+	"go.temporal.io/server/common/searchattribute"
 	"go.temporal.io/server/common/util"
 )
 
 type (
-	// QueryParser parses a limited SQL where clause into a struct
+	// QueryParser parses a visibility query into a struct
 	QueryParser interface {
-		Parse(query string) (*parsedQuery, error)
+		Parse(query string, saTypeMap searchattribute.NameTypeMap) (*parsedQuery, error)
 	}
 
 	queryParser struct{}
@@ -57,10 +54,13 @@ type (
 		workflowTypeName  *string
 		status            *enumspb.WorkflowExecutionStatus
 		emptyResult       bool
+		// visibilityQuery is the full query which records are matched against.
+		// The fields above are derived from it and are only used to skip records early.
+		visibilityQuery *archiver.VisibilityQuery
 	}
 )
 
-// All allowed fields for filtering
+// Fields used to narrow down the records to read
 const (
 	WorkflowID   = "WorkflowId"
 	RunID        = "RunId"
@@ -70,18 +70,12 @@ const (
 	ExecutionStatus = "ExecutionStatus"
 )
 
-const (
-	queryTemplate = "select * from dummy where %s"
-
-	defaultDateTimeFormat = time.RFC3339
-)
-
 // NewQueryParser creates a new query parser for filestore
 func NewQueryParser() QueryParser {
 	return &queryParser{}
 }
 
-func (p *queryParser) Parse(query string) (*parsedQuery, error) {
+func (p *queryParser) Parse(query string, saTypeMap searchattribute.NameTypeMap) (*parsedQuery, error) {
 	parsedQuery := &parsedQuery{
 		earliestCloseTime: time.Time{},
 		latestCloseTime:   time.Now().UTC(),
@@ -89,191 +83,72 @@ func (p *queryParser) Parse(query string) (*parsedQuery, error) {
 	if strings.TrimSpace(query) == "" {
 		return parsedQuery, nil
 	}
-	stmt, err := sqlparser.Parse(fmt.Sprintf(queryTemplate, query))
+	visibilityQuery, err := archiver.ParseVisibilityQuery(query, saTypeMap)
 	if err != nil {
 		return nil, err
 	}
-	whereExpr := stmt.(*sqlparser.Select).Where.Expr
-	if err := p.convertWhereExpr(whereExpr, parsedQuery); err != nil {
-		return nil, err
+	parsedQuery.visibilityQuery = visibilityQuery
+	for _, condition := range visibilityQuery.Conditions() {
+		p.convertCondition(condition, parsedQuery)
 	}
 	return parsedQuery, nil
 }
 
-func (p *queryParser) convertWhereExpr(expr sqlparser.Expr, parsedQuery *parsedQuery) error {
-	if expr == nil {
-		return errors.New("where expression is nil")
-	}
-
-	switch expr := expr.(type) {
-	case *sqlparser.ComparisonExpr:
-		return p.convertComparisonExpr(expr, parsedQuery)
-	case *sqlparser.AndExpr:
-		return p.convertAndExpr(expr, parsedQuery)
-	case *sqlparser.ParenExpr:
-		return p.convertParenExpr(expr, parsedQuery)
-	default:
-		return errors.New("only comparison and \"and\" expression is supported")
-	}
-}
-
-func (p *queryParser) convertParenExpr(parenExpr *sqlparser.ParenExpr, parsedQuery *parsedQuery) error {
-	return p.convertWhereExpr(parenExpr.Expr, parsedQuery)
-}
-
-func (p *queryParser) convertAndExpr(andExpr *sqlparser.AndExpr, parsedQuery *parsedQuery) error {
-	if err := p.convertWhereExpr(andExpr.Left, parsedQuery); err != nil {
-		return err
-	}
-	return p.convertWhereExpr(andExpr.Right, parsedQuery)
-}
-
-func (p *queryParser) convertComparisonExpr(compExpr *sqlparser.ComparisonExpr, parsedQuery *parsedQuery) error {
-	colName, ok := compExpr.Left.(*sqlparser.ColName)
-	if !ok {
-		return fmt.Errorf("invalid filter name: %s", sqlparser.String(compExpr.Left))
-	}
-	colNameStr := sqlparser.String(colName)
-	op := compExpr.Operator
-	valExpr, ok := compExpr.Right.(*sqlparser.SQLVal)
-	if !ok {
-		return fmt.Errorf("invalid value: %s", sqlparser.String(compExpr.Right))
-	}
-	valStr := sqlparser.String(valExpr)
-
-	switch colNameStr {
+func (p *queryParser) convertCondition(condition *archiver.VisibilityCondition, parsedQuery *parsedQuery) {
+	switch condition.Name {
 	case WorkflowID:
-		val, err := extractStringValue(valStr)
-		if err != nil {
-			return err
+		if condition.Operator == sqlparser.EqualStr {
+			p.convertStringEquality(&parsedQuery.workflowID, condition.Values[0].(string), parsedQuery)
 		}
-		if op != "=" {
-			return fmt.Errorf("only operation = is support for %s", WorkflowID)
-		}
-		if parsedQuery.workflowID != nil && *parsedQuery.workflowID != val {
-			parsedQuery.emptyResult = true
-			return nil
-		}
-		parsedQuery.workflowID = convert.StringPtr(val)
 	case RunID:
-		val, err := extractStringValue(valStr)
-		if err != nil {
-			return err
-		}
-		if op != "=" {
-			return fmt.Errorf("only operation = is support for %s", RunID)
+		if condition.Operator == sqlparser.EqualStr {
+			p.convertStringEquality(&parsedQuery.runID, condition.Values[0].(string), parsedQuery)
 		}
-		if parsedQuery.runID != nil && *parsedQuery.runID != val {
-			parsedQuery.emptyResult = true
-			return nil
-		}
-		parsedQuery.runID = convert.StringPtr(val)
 	case WorkflowType:
-		val, err := extractStringValue(valStr)
-		if err != nil {
-			return err
-		}
-		if op != "=" {
-			return fmt.Errorf("only operation = is support for %s", WorkflowType)
+		if condition.Operator == sqlparser.EqualStr {
+			p.convertStringEquality(&parsedQuery.workflowTypeName, condition.Values[0].(string), parsedQuery)
 		}
-		if parsedQuery.workflowTypeName != nil && *parsedQuery.workflowTypeName != val {
-			parsedQuery.emptyResult = true
-			return nil
-		}
-		parsedQuery.workflowTypeName = convert.StringPtr(val)
 	case ExecutionStatus:
-		val, err := extractStringValue(valStr)
-		if err != nil {
-			// if failed to extract string value, it means user input close status as a number
-			val = valStr
-		}
-		if op != "=" {
-			return fmt.Errorf("only operation = is support for %s", ExecutionStatus)
-		}
-		status, err := convertStatusStr(val)
-		if err != nil {
-			return err
+		if condition.Operator == sqlparser.EqualStr {
+			status := condition.Values[0].(enumspb.WorkflowExecutionStatus)
+			if parsedQuery.status != nil && *parsedQuery.status != status {
+				parsedQuery.emptyResult = true
+				return
+			}
+			parsedQuery.status = &status
 		}
-		if parsedQuery.status != nil && *parsedQuery.status != status {
-			parsedQuery.emptyResult = true
-			return nil
-		}
-		parsedQuery.status = &status
 	case CloseTime:
-		timestamp, err := convertToTime(valStr)
-		if err != nil {
-			return err
+		if condition.Operator == sqlparser.BetweenStr {
+			p.convertCloseTime(condition.Values[0].(time.Time), sqlparser.GreaterEqualStr, parsedQuery)
+			p.convertCloseTime(condition.Values[1].(time.Time), sqlparser.LessEqualStr, parsedQuery)
+			return
+		}
+		if len(condition.Values) == 1 {
+			p.convertCloseTime(condition.Values[0].(time.Time), condition.Operator, parsedQuery)
 		}
-		return p.convertCloseTime(timestamp, op, parsedQuery)
-	default:
-		return fmt.Errorf("unknown filter name: %s", colNameStr)
 	}
+}
 
-	return nil
+func (p *queryParser) convertStringEquality(field **string, val string, parsedQuery *parsedQuery) {
+	if *field != nil && **field != val {
+		parsedQuery.emptyResult = true
+		return
+	}
+	*field = &val
 }
 
-func (p *queryParser) convertCloseTime(timestamp time.Time, op string, parsedQuery *parsedQuery) error {
+func (p *queryParser) convertCloseTime(timestamp time.Time, op string, parsedQuery *parsedQuery) {
 	switch op {
-	case "=":
-		if err := p.convertCloseTime(timestamp, ">=", parsedQuery); err != nil {
-			return err
-		}
-		if err := p.convertCloseTime(timestamp, "<=", parsedQuery); err != nil {
-			return err
-		}
-	case "<":
+	case sqlparser.EqualStr:
+		p.convertCloseTime(timestamp, sqlparser.GreaterEqualStr, parsedQuery)
+		p.convertCloseTime(timestamp, sqlparser.LessEqualStr, parsedQuery)
+	case sqlparser.LessThanStr:
 		parsedQuery.latestCloseTime = util.MinTime(parsedQuery.latestCloseTime, timestamp.Add(-1*time.Nanosecond))
-	case "<=":
+	case sqlparser.LessEqualStr:
 		parsedQuery.latestCloseTime = util.MinTime(parsedQuery.latestCloseTime, timestamp)
-	case ">":
+	case sqlparser.GreaterThanStr:
 		parsedQuery.earliestCloseTime = util.MaxTime(parsedQuery.earliestCloseTime, timestamp.Add(1*time.Nanosecond))
-	case ">=":
+	case sqlparser.GreaterEqualStr:
 		parsedQuery.earliestCloseTime = util.MaxTime(parsedQuery.earliestCloseTime, timestamp)
-	default:
-		return fmt.Errorf("operator %s is not supported for close time", op)
-	}
-	return nil
-}
-
-func convertToTime(timeStr string) (time.Time, error) {
-	ts, err := strconv.ParseInt(timeStr, 10, 64)
-	if err == nil {
-		return timestamp.UnixOrZeroTime(ts), nil
-	}
-	timestampStr, err := extractStringValue(timeStr)
-	if err != nil {
-		return time.Time{}, err
-	}
-	parsedTime, err := time.Parse(defaultDateTimeFormat, timestampStr)
-	if err != nil {
-		return time.Time{}, err
-	}
-	return parsedTime, nil
-}
-
-func convertStatusStr(statusStr string) (enumspb.WorkflowExecutionStatus, error) {
-	statusStr = strings.ToLower(strings.TrimSpace(statusStr))
-	switch statusStr {
-	case "completed", convert.Int32ToString(int32(enumspb.WORKFLOW_EXECUTION_STATUS_COMPLETED)):
-		return enumspb.WORKFLOW_EXECUTION_STATUS_COMPLETED, nil
-	case "failed", convert.Int32ToString(int32(enumspb.WORKFLOW_EXECUTION_STATUS_FAILED)):
-		return enumspb.WORKFLOW_EXECUTION_STATUS_FAILED, nil
-	case "canceled", convert.Int32ToString(int32(enumspb.WORKFLOW_EXECUTION_STATUS_CANCELED)):
-		return enumspb.WORKFLOW_EXECUTION_STATUS_CANCELED, nil
-	case "terminated", convert.Int32ToString(int32(enumspb.WORKFLOW_EXECUTION_STATUS_TERMINATED)):
-		return enumspb.WORKFLOW_EXECUTION_STATUS_TERMINATED, nil
-	case "continuedasnew", "continued_as_new", convert.Int32ToString(int32(enumspb.WORKFLOW_EXECUTION_STATUS_CONTINUED_AS_NEW)):
-		return enumspb.WORKFLOW_EXECUTION_STATUS_CONTINUED_AS_NEW, nil
-	case "timedout", "timed_out", convert.Int32ToString(int32(enumspb.WORKFLOW_EXECUTION_STATUS_TIMED_OUT)):
-		return enumspb.WORKFLOW_EXECUTION_STATUS_TIMED_OUT, nil
-	default:
-		return 0, fmt.Errorf("unknown workflow close status: %s", statusStr)
-	}
-}
-
-func extractStringValue(s string) (string, error) {
-	if len(s) >= 2 && s[0] == '\'' && s[len(s)-1] == '\'' {
-		return s[1 : len(s)-1], nil
 	}
-	return "", fmt.Errorf("value %s is not a string value", s)
 }
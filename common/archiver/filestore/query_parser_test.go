@@ -33,6 +33,7 @@ import (
 	enumspb "go.temporal.io/api/enums/v1"
 
 	"go.temporal.io/server/common/convert"
+	"go.temporal.io/server/common/searchattribute"
 )
 
 type queryParserSuite struct {
@@ -113,8 +114,16 @@ func (s *queryParserSuite) TestParseWorkflowID_RunID_WorkflowType() {
 			expectErr: true,
 		},
 		{
-			query:     "WorkflowId = \"random workflowID\" or WorkflowId = \"another workflowID\"",
-			expectErr: true,
+			query:       "WorkflowId = \"random workflowID\" or WorkflowId = \"another workflowID\"",
+			expectErr:   false,
+			parsedQuery: &parsedQuery{},
+		},
+		{
+			query:     "WorkflowId in (\"random workflowID\", \"another workflowID\") and RunId = 'random runID'",
+			expectErr: false,
+			parsedQuery: &parsedQuery{
+				runID: convert.StringPtr("random runID"),
+			},
 		},
 		{
 			query:     "WorkflowId = \"random workflowID\" or runId = \"random runID\"",
@@ -131,7 +140,7 @@ func (s *queryParserSuite) TestParseWorkflowID_RunID_WorkflowType() {
 	}
 
 	for _, tc := range testCases {
-		parsedQuery, err := s.parser.Parse(tc.query)
+		parsedQuery, err := s.parser.Parse(tc.query, searchattribute.TestNameTypeMap)
 		if tc.expectErr {
 			s.Error(err)
 			continue
@@ -213,8 +222,16 @@ func (s *queryParserSuite) TestParseCloseStatus() {
 			expectErr: true,
 		},
 		{
-			query:     "ExecutionStatus = \"Failed\" or ExecutionStatus = \"Failed\"",
-			expectErr: true,
+			query:       "ExecutionStatus = \"Failed\" or ExecutionStatus = \"Completed\"",
+			expectErr:   false,
+			parsedQuery: &parsedQuery{},
+		},
+		{
+			query:     "ExecutionStatus = 'Running'",
+			expectErr: false,
+			parsedQuery: &parsedQuery{
+				status: toWorkflowExecutionStatusPtr(enumspb.WORKFLOW_EXECUTION_STATUS_RUNNING),
+			},
 		},
 		{
 			query:     "ExecutionStatus = \"unknown\"",
@@ -238,7 +255,7 @@ func (s *queryParserSuite) TestParseCloseStatus() {
 	}
 
 	for _, tc := range testCases {
-		parsedQuery, err := s.parser.Parse(tc.query)
+		parsedQuery, err := s.parser.Parse(tc.query, searchattribute.TestNameTypeMap)
 		if tc.expectErr {
 			s.Error(err)
 			continue
@@ -289,6 +306,14 @@ func (s *queryParserSuite) TestParseCloseTime() {
 				latestCloseTime:   time.Date(2019, 01, 01, 11, 11, 11, 0, time.UTC),
 			},
 		},
+		{
+			query:     "CloseTime between 1000 and 2000 and StartTime > 100",
+			expectErr: false,
+			parsedQuery: &parsedQuery{
+				earliestCloseTime: time.Unix(0, 1000),
+				latestCloseTime:   time.Unix(0, 2000),
+			},
+		},
 		{
 			query:     "closeTime = 2000",
 			expectErr: true,
@@ -304,7 +329,7 @@ func (s *queryParserSuite) TestParseCloseTime() {
 	}
 
 	for i, tc := range testCases {
-		parsedQuery, err := s.parser.Parse(tc.query)
+		parsedQuery, err := s.parser.Parse(tc.query, searchattribute.TestNameTypeMap)
 		if tc.expectErr {
 			s.Error(err)
 			continue
@@ -343,6 +368,14 @@ func (s *queryParserSuite) TestParse() {
 				status:            toWorkflowExecutionStatusPtr(enumspb.WORKFLOW_EXECUTION_STATUS_FAILED),
 			},
 		},
+		{
+			query:     "CustomKeywordField = 'random value' and (CustomIntField > 10 or WorkflowType = 'random typeName') and CloseTime between 2000 and 9000 order by StartTime desc",
+			expectErr: false,
+			parsedQuery: &parsedQuery{
+				earliestCloseTime: time.Unix(0, 2000).UTC(),
+				latestCloseTime:   time.Unix(0, 9000).UTC(),
+			},
+		},
 		{
 			query:     "CloseTime > 2001 and CloseTime < 10000 and (RunId = 'random runID') and ExecutionStatus = 'Failed' and (RunId = 'another ID')",
 			expectErr: false,
@@ -353,7 +386,7 @@ func (s *queryParserSuite) TestParse() {
 	}
 
 	for i, tc := range testCases {
-		parsedQuery, err := s.parser.Parse(tc.query)
+		parsedQuery, err := s.parser.Parse(tc.query, searchattribute.TestNameTypeMap)
 		if tc.expectErr {
 			s.Error(err)
 			continue
@@ -361,6 +394,8 @@ func (s *queryParserSuite) TestParse() {
 		s.NoError(err, "case %d", i)
 		s.Equal(tc.parsedQuery.emptyResult, parsedQuery.emptyResult, "case %d", i)
 		if !tc.parsedQuery.emptyResult {
+			s.NotNil(parsedQuery.visibilityQuery, "case %d", i)
+			parsedQuery.visibilityQuery = nil
 			s.Equal(tc.parsedQuery, parsedQuery, "case %d", i)
 		}
 	}
@@ -50,6 +50,12 @@ const (
 	errEncodeVisibilityRecord = "failed to encode visibility record"
 )
 
+var (
+	// orderedQueryMaxRecords is the maximum number of records a query ordered by other fields than CloseTime
+	// reads. Every page of such a query reads all of its records, so queries which read more are rejected.
+	orderedQueryMaxRecords = 10000
+)
+
 type (
 	visibilityArchiver struct {
 		container   *archiver.VisibilityBootstrapContainer
@@ -61,6 +67,8 @@ type (
 	queryVisibilityToken struct {
 		LastCloseTime time.Time
 		LastRunID     string
+		// Offset is only used by queries with an order by clause.
+		Offset int
 	}
 
 	queryVisibilityRequest struct {
@@ -68,6 +76,8 @@ type (
 		pageSize      int
 		nextPageToken []byte
 		parsedQuery   *parsedQuery
+		// ascending returns records by ascending close time instead of descending
+		ascending bool
 	}
 )
 
@@ -154,7 +164,7 @@ func (v *visibilityArchiver) Query(
 		return nil, serviceerror.NewInvalidArgument(archiver.ErrInvalidQueryVisibilityRequest.Error())
 	}
 
-	parsedQuery, err := v.queryParser.Parse(request.Query)
+	parsedQuery, err := v.queryParser.Parse(request.Query, saTypeMap)
 	if err != nil {
 		return nil, serviceerror.NewInvalidArgument(err.Error())
 	}
@@ -182,6 +192,15 @@ func (v *visibilityArchiver) query(
 	request *queryVisibilityRequest,
 	saTypeMap searchattribute.NameTypeMap,
 ) (*archiver.QueryVisibilityResponse, error) {
+	if visibilityQuery := request.parsedQuery.visibilityQuery; visibilityQuery != nil && visibilityQuery.HasOrderBy() {
+		// records are stored sorted by close time, so queries ordered by it are paged through like the others
+		ordered, desc := visibilityQuery.OrderedByCloseTime()
+		if !ordered {
+			return v.queryOrdered(ctx, URI, request, saTypeMap)
+		}
+		request.ascending = !desc
+	}
+
 	var token *queryVisibilityToken
 	if request.nextPageToken != nil {
 		var err error
@@ -205,7 +224,7 @@ func (v *visibilityArchiver) query(
 		return nil, serviceerror.NewInternal(err.Error())
 	}
 
-	files, err = sortAndFilterFiles(files, token)
+	files, err = sortAndFilterFiles(files, token, request.ascending)
 	if err != nil {
 		return nil, serviceerror.NewInternal(err.Error())
 	}
@@ -215,17 +234,14 @@ func (v *visibilityArchiver) query(
 
 	response := &archiver.QueryVisibilityResponse{}
 	for idx, file := range files {
-		encodedRecord, err := readFile(path.Join(dirPath, file))
+		record, err := readVisibilityRecord(path.Join(dirPath, file))
 		if err != nil {
 			return nil, serviceerror.NewInternal(err.Error())
 		}
 
-		record, err := decodeVisibilityRecord(encodedRecord)
-		if err != nil {
-			return nil, serviceerror.NewInternal(err.Error())
-		}
-
-		if record.CloseTime.AsTime().Before(request.parsedQuery.earliestCloseTime) {
+		closeTime := record.CloseTime.AsTime()
+		if !request.ascending && closeTime.Before(request.parsedQuery.earliestCloseTime) ||
+			request.ascending && closeTime.After(request.parsedQuery.latestCloseTime) {
 			break
 		}
 
@@ -256,6 +272,88 @@ func (v *visibilityArchiver) query(
 	return response, nil
 }
 
+// queryOrdered handles queries ordered by other fields than CloseTime. Records are stored sorted by close time,
+// so all matching records have to be read and sorted before the requested page can be returned. Queries which
+// read more than orderedQueryMaxRecords records are rejected.
+func (v *visibilityArchiver) queryOrdered(
+	ctx context.Context,
+	URI archiver.URI,
+	request *queryVisibilityRequest,
+	saTypeMap searchattribute.NameTypeMap,
+) (*archiver.QueryVisibilityResponse, error) {
+	offset := 0
+	if request.nextPageToken != nil {
+		token, err := deserializeQueryVisibilityToken(request.nextPageToken)
+		if err != nil {
+			return nil, serviceerror.NewInvalidArgument(archiver.ErrNextPageTokenCorrupted.Error())
+		}
+		offset = token.Offset
+	}
+
+	dirPath := path.Join(URI.Path(), request.namespaceID)
+	exists, err := directoryExists(dirPath)
+	if err != nil {
+		return nil, serviceerror.NewInternal(err.Error())
+	}
+	if !exists {
+		return &archiver.QueryVisibilityResponse{}, nil
+	}
+
+	files, err := listFiles(dirPath)
+	if err != nil {
+		return nil, serviceerror.NewInternal(err.Error())
+	}
+
+	files, err = sortAndFilterFiles(files, nil, false)
+	if err != nil {
+		return nil, serviceerror.NewInternal(err.Error())
+	}
+
+	var records []*archiverspb.VisibilityRecord
+	for idx, file := range files {
+		if idx >= orderedQueryMaxRecords {
+			return nil, serviceerror.NewInvalidArgument(fmt.Sprintf(
+				"queries ordered by other fields than CloseTime can read at most %d records, narrow down the query with CloseTime",
+				orderedQueryMaxRecords,
+			))
+		}
+		record, err := readVisibilityRecord(path.Join(dirPath, file))
+		if err != nil {
+			return nil, serviceerror.NewInternal(err.Error())
+		}
+
+		if record.CloseTime.AsTime().Before(request.parsedQuery.earliestCloseTime) {
+			break
+		}
+
+		if matchQuery(record, request.parsedQuery) {
+			records = append(records, record)
+		}
+	}
+	request.parsedQuery.visibilityQuery.SortRecords(records)
+
+	response := &archiver.QueryVisibilityResponse{}
+	if offset >= len(records) {
+		return response, nil
+	}
+	end := min(offset+request.pageSize, len(records))
+	for _, record := range records[offset:end] {
+		executionInfo, err := convertToExecutionInfo(record, saTypeMap)
+		if err != nil {
+			return nil, serviceerror.NewInternal(err.Error())
+		}
+		response.Executions = append(response.Executions, executionInfo)
+	}
+	if end < len(records) {
+		encodedToken, err := serializeToken(&queryVisibilityToken{Offset: end})
+		if err != nil {
+			return nil, serviceerror.NewInternal(err.Error())
+		}
+		response.NextPageToken = encodedToken
+	}
+	return response, nil
+}
+
 func (v *visibilityArchiver) ValidateURI(URI archiver.URI) error {
 	if URI.Scheme() != URIScheme {
 		return archiver.ErrURISchemeMismatch
@@ -270,9 +368,9 @@ type parsedVisFilename struct {
 	hashedRunID string
 }
 
-// sortAndFilterFiles sort visibility record file names based on close timestamp (desc) and use hashed runID to break ties.
-// if a nextPageToken is give, it only returns filenames that have a smaller close timestamp
-func sortAndFilterFiles(filenames []string, token *queryVisibilityToken) ([]string, error) {
+// sortAndFilterFiles sort visibility record file names based on close timestamp (desc, or asc if ascending is set)
+// and use hashed runID to break ties. if a nextPageToken is give, it only returns filenames that come after it
+func sortAndFilterFiles(filenames []string, token *queryVisibilityToken, ascending bool) ([]string, error) {
 	var parsedFilenames []*parsedVisFilename
 	for _, name := range filenames {
 		pieces := strings.FieldsFunc(name, func(r rune) bool {
@@ -293,21 +391,24 @@ func sortAndFilterFiles(filenames []string, token *queryVisibilityToken) ([]stri
 		})
 	}
 
-	sort.Slice(parsedFilenames, func(i, j int) bool {
-		if parsedFilenames[i].closeTime.Equal(parsedFilenames[j].closeTime) {
-			return parsedFilenames[i].hashedRunID > parsedFilenames[j].hashedRunID
+	// before returns true if a comes before b in the requested order
+	before := func(a *parsedVisFilename, bCloseTime time.Time, bHashedRunID string) bool {
+		if a.closeTime.Equal(bCloseTime) {
+			return a.hashedRunID > bHashedRunID != ascending
 		}
-		return parsedFilenames[i].closeTime.After(parsedFilenames[j].closeTime)
+		return a.closeTime.After(bCloseTime) != ascending
+	}
+	sort.Slice(parsedFilenames, func(i, j int) bool {
+		return before(parsedFilenames[i], parsedFilenames[j].closeTime, parsedFilenames[j].hashedRunID)
 	})
 
 	startIdx := 0
 	if token != nil {
 		LastHashedRunID := hash(token.LastRunID)
 		startIdx = sort.Search(len(parsedFilenames), func(i int) bool {
-			if parsedFilenames[i].closeTime.Equal(token.LastCloseTime) {
-				return parsedFilenames[i].hashedRunID < LastHashedRunID
-			}
-			return parsedFilenames[i].closeTime.Before(token.LastCloseTime)
+			parsed := parsedFilenames[i]
+			return !before(parsed, token.LastCloseTime, LastHashedRunID) &&
+				!(parsed.closeTime.Equal(token.LastCloseTime) && parsed.hashedRunID == LastHashedRunID)
 		})
 	}
 
@@ -339,7 +440,15 @@ func matchQuery(record *archiverspb.VisibilityRecord, query *parsedQuery) bool {
 	if query.status != nil && record.Status != *query.status {
 		return false
 	}
-	return true
+	return query.visibilityQuery == nil || query.visibilityQuery.Match(record)
+}
+
+func readVisibilityRecord(filepath string) (*archiverspb.VisibilityRecord, error) {
+	encodedRecord, err := readFile(filepath)
+	if err != nil {
+		return nil, err
+	}
+	return decodeVisibilityRecord(encodedRecord)
 }
 
 func convertToExecutionInfo(record *archiverspb.VisibilityRecord, saTypeMap searchattribute.NameTypeMap) (*workflowpb.WorkflowExecutionInfo, error) {
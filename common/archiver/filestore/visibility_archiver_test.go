@@ -27,6 +27,7 @@ package filestore
 import (
 	"context"
 	"errors"
+	"fmt"
 	"os"
 	"path"
 	"testing"
@@ -328,10 +329,18 @@ func (s *visibilityArchiverSuite) TestSortAndFilterFiles() {
 	}
 
 	for i, tc := range testCases {
-		result, err := sortAndFilterFiles(tc.filenames, tc.token)
+		result, err := sortAndFilterFiles(tc.filenames, tc.token, false)
 		s.NoError(err, "case %d", i)
 		s.Equal(tc.expectedResult, result, "case %d", i)
 	}
+
+	filenames := []string{"9_12345.vis", "5_0.vis", "9_54321.vis", "1000_654.vis", "1000_78.vis"}
+	result, err := sortAndFilterFiles(filenames, nil, true)
+	s.NoError(err)
+	s.Equal([]string{"5_0.vis", "9_12345.vis", "9_54321.vis", "1000_654.vis", "1000_78.vis"}, result)
+	result, err = sortAndFilterFiles(filenames, &queryVisibilityToken{LastCloseTime: time.Unix(0, 8)}, true)
+	s.NoError(err)
+	s.Equal([]string{"9_12345.vis", "9_54321.vis", "1000_654.vis", "1000_78.vis"}, result)
 }
 
 func (s *visibilityArchiverSuite) TestQuery_Fail_InvalidURI() {
@@ -357,7 +366,7 @@ func (s *visibilityArchiverSuite) TestQuery_Fail_InvalidRequest() {
 func (s *visibilityArchiverSuite) TestQuery_Fail_InvalidQuery() {
 	visibilityArchiver := s.newTestVisibilityArchiver()
 	mockParser := NewMockQueryParser(s.controller)
-	mockParser.EXPECT().Parse(gomock.Any()).Return(nil, errors.New("invalid query"))
+	mockParser.EXPECT().Parse(gomock.Any(), gomock.Any()).Return(nil, errors.New("invalid query"))
 	visibilityArchiver.queryParser = mockParser
 	response, err := visibilityArchiver.Query(context.Background(), s.testArchivalURI, &archiver.QueryVisibilityRequest{
 		NamespaceID: "some random namespaceID",
@@ -371,7 +380,7 @@ func (s *visibilityArchiverSuite) TestQuery_Fail_InvalidQuery() {
 func (s *visibilityArchiverSuite) TestQuery_Success_DirectoryNotExist() {
 	visibilityArchiver := s.newTestVisibilityArchiver()
 	mockParser := NewMockQueryParser(s.controller)
-	mockParser.EXPECT().Parse(gomock.Any()).Return(&parsedQuery{
+	mockParser.EXPECT().Parse(gomock.Any(), gomock.Any()).Return(&parsedQuery{
 		earliestCloseTime: time.Unix(0, 1),
 		latestCloseTime:   time.Unix(0, 101),
 	}, nil)
@@ -391,7 +400,7 @@ func (s *visibilityArchiverSuite) TestQuery_Success_DirectoryNotExist() {
 func (s *visibilityArchiverSuite) TestQuery_Fail_InvalidToken() {
 	visibilityArchiver := s.newTestVisibilityArchiver()
 	mockParser := NewMockQueryParser(s.controller)
-	mockParser.EXPECT().Parse(gomock.Any()).Return(&parsedQuery{
+	mockParser.EXPECT().Parse(gomock.Any(), gomock.Any()).Return(&parsedQuery{
 		earliestCloseTime: time.Unix(0, 1),
 		latestCloseTime:   time.Unix(0, 101),
 	}, nil)
@@ -410,7 +419,7 @@ func (s *visibilityArchiverSuite) TestQuery_Fail_InvalidToken() {
 func (s *visibilityArchiverSuite) TestQuery_Success_NoNextPageToken() {
 	visibilityArchiver := s.newTestVisibilityArchiver()
 	mockParser := NewMockQueryParser(s.controller)
-	mockParser.EXPECT().Parse(gomock.Any()).Return(&parsedQuery{
+	mockParser.EXPECT().Parse(gomock.Any(), gomock.Any()).Return(&parsedQuery{
 		earliestCloseTime: time.Unix(0, 1),
 		latestCloseTime:   time.Unix(0, 10001),
 		workflowID:        convert.StringPtr(testWorkflowID),
@@ -436,7 +445,7 @@ func (s *visibilityArchiverSuite) TestQuery_Success_NoNextPageToken() {
 func (s *visibilityArchiverSuite) TestQuery_Success_SmallPageSize() {
 	visibilityArchiver := s.newTestVisibilityArchiver()
 	mockParser := NewMockQueryParser(s.controller)
-	mockParser.EXPECT().Parse(gomock.Any()).Return(&parsedQuery{
+	mockParser.EXPECT().Parse(gomock.Any(), gomock.Any()).Return(&parsedQuery{
 		earliestCloseTime: time.Unix(0, 1),
 		latestCloseTime:   time.Unix(0, 10001),
 		status:            toWorkflowExecutionStatusPtr(enumspb.WORKFLOW_EXECUTION_STATUS_FAILED),
@@ -477,7 +486,7 @@ func (s *visibilityArchiverSuite) TestArchiveAndQuery() {
 
 	visibilityArchiver := s.newTestVisibilityArchiver()
 	mockParser := NewMockQueryParser(s.controller)
-	mockParser.EXPECT().Parse(gomock.Any()).Return(&parsedQuery{
+	mockParser.EXPECT().Parse(gomock.Any(), gomock.Any()).Return(&parsedQuery{
 		earliestCloseTime: time.Unix(0, 10),
 		latestCloseTime:   time.Unix(0, 10001),
 		status:            toWorkflowExecutionStatusPtr(enumspb.WORKFLOW_EXECUTION_STATUS_FAILED),
@@ -512,12 +521,119 @@ func (s *visibilityArchiverSuite) TestArchiveAndQuery() {
 	s.Equal(ei, executions[1])
 }
 
+func (s *visibilityArchiverSuite) TestArchiveAndQuery_VisibilityQuery() {
+	dir := testutils.MkdirTemp(s.T(), "", "TestArchiveAndQuery_VisibilityQuery")
+
+	visibilityArchiver := s.newTestVisibilityArchiver()
+	URI, err := archiver.NewURI("file://" + dir)
+	s.NoError(err)
+	records := []*archiverspb.VisibilityRecord{
+		s.newSearchAttributesRecord("workflow-1", enumspb.WORKFLOW_EXECUTION_STATUS_FAILED, 10, "a"),
+		s.newSearchAttributesRecord("workflow-2", enumspb.WORKFLOW_EXECUTION_STATUS_COMPLETED, 500, "b"),
+		s.newSearchAttributesRecord("workflow-3", enumspb.WORKFLOW_EXECUTION_STATUS_COMPLETED, 20, "a"),
+		s.newSearchAttributesRecord("workflow-4", enumspb.WORKFLOW_EXECUTION_STATUS_FAILED, 30, "c"),
+		s.newSearchAttributesRecord("workflow-5", enumspb.WORKFLOW_EXECUTION_STATUS_FAILED, 40, "b"),
+	}
+	for _, record := range records {
+		err := visibilityArchiver.Archive(context.Background(), URI, record)
+		s.NoError(err)
+	}
+
+	request := &archiver.QueryVisibilityRequest{
+		NamespaceID: testNamespaceID,
+		PageSize:    1,
+		Query:       "(ExecutionStatus = 'Failed' or HistoryLength > 400) and CustomKeywordField in ('a', 'b') order by HistoryLength desc",
+	}
+	var workflowIDs []string
+	for len(workflowIDs) == 0 || request.NextPageToken != nil {
+		response, err := visibilityArchiver.Query(context.Background(), URI, request, searchattribute.TestNameTypeMap)
+		s.NoError(err)
+		s.NotNil(response)
+		for _, execution := range response.Executions {
+			workflowIDs = append(workflowIDs, execution.GetExecution().GetWorkflowId())
+		}
+		request.NextPageToken = response.NextPageToken
+	}
+	s.Equal([]string{"workflow-2", "workflow-5", "workflow-1"}, workflowIDs)
+
+	request = &archiver.QueryVisibilityRequest{
+		NamespaceID: testNamespaceID,
+		PageSize:    10,
+		Query:       "CustomKeywordField = 'a' limit 10",
+	}
+	_, err = visibilityArchiver.Query(context.Background(), URI, request, searchattribute.TestNameTypeMap)
+	var svcErr *serviceerror.InvalidArgument
+	s.ErrorAs(err, &svcErr)
+}
+
+func (s *visibilityArchiverSuite) TestArchiveAndQuery_OrderByCloseTime() {
+	dir := testutils.MkdirTemp(s.T(), "", "TestArchiveAndQuery_OrderByCloseTime")
+
+	visibilityArchiver := s.newTestVisibilityArchiver()
+	URI, err := archiver.NewURI("file://" + dir)
+	s.NoError(err)
+	for i, historyLength := range []int64{30, 10, 50, 20, 40} {
+		record := s.newSearchAttributesRecord(fmt.Sprintf("workflow-%d", i), enumspb.WORKFLOW_EXECUTION_STATUS_COMPLETED, historyLength, "a")
+		s.NoError(visibilityArchiver.Archive(context.Background(), URI, record))
+	}
+
+	for query, expected := range map[string][]string{
+		"CustomKeywordField = 'a' order by CloseTime":      {"workflow-1", "workflow-3", "workflow-0", "workflow-4", "workflow-2"},
+		"CustomKeywordField = 'a' order by CloseTime desc": {"workflow-2", "workflow-4", "workflow-0", "workflow-3", "workflow-1"},
+	} {
+		request := &archiver.QueryVisibilityRequest{
+			NamespaceID: testNamespaceID,
+			PageSize:    2,
+			Query:       query,
+		}
+		var workflowIDs []string
+		for len(workflowIDs) == 0 || request.NextPageToken != nil {
+			response, err := visibilityArchiver.Query(context.Background(), URI, request, searchattribute.TestNameTypeMap)
+			s.NoError(err)
+			for _, execution := range response.Executions {
+				workflowIDs = append(workflowIDs, execution.GetExecution().GetWorkflowId())
+			}
+			request.NextPageToken = response.NextPageToken
+		}
+		s.Equal(expected, workflowIDs, query)
+	}
+}
+
+func (s *visibilityArchiverSuite) TestQuery_OrderBy_TooManyRecords() {
+	defer func(maxRecords int) { orderedQueryMaxRecords = maxRecords }(orderedQueryMaxRecords)
+	orderedQueryMaxRecords = 2
+
+	dir := testutils.MkdirTemp(s.T(), "", "TestQuery_OrderBy_TooManyRecords")
+	visibilityArchiver := s.newTestVisibilityArchiver()
+	URI, err := archiver.NewURI("file://" + dir)
+	s.NoError(err)
+	for i, historyLength := range []int64{10, 20, 30} {
+		record := s.newSearchAttributesRecord(fmt.Sprintf("workflow-%d", i), enumspb.WORKFLOW_EXECUTION_STATUS_COMPLETED, historyLength, "a")
+		s.NoError(visibilityArchiver.Archive(context.Background(), URI, record))
+	}
+
+	// ordering by close time pages through records in storage order and is not limited
+	request := &archiver.QueryVisibilityRequest{
+		NamespaceID: testNamespaceID,
+		PageSize:    10,
+		Query:       "order by CloseTime",
+	}
+	response, err := visibilityArchiver.Query(context.Background(), URI, request, searchattribute.TestNameTypeMap)
+	s.NoError(err)
+	s.Len(response.Executions, 3)
+
+	request.Query = "order by HistoryLength"
+	_, err = visibilityArchiver.Query(context.Background(), URI, request, searchattribute.TestNameTypeMap)
+	var svcErr *serviceerror.InvalidArgument
+	s.ErrorAs(err, &svcErr)
+}
+
 func (s *visibilityArchiverSuite) TestQuery_EmptyQuery_InvalidNamespace() {
 	URI := s.testArchivalURI
 
 	visibilityArchiver := s.newTestVisibilityArchiver()
 	mockParser := NewMockQueryParser(s.controller)
-	mockParser.EXPECT().Parse(gomock.Any()).Return(&parsedQuery{
+	mockParser.EXPECT().Parse(gomock.Any(), gomock.Any()).Return(&parsedQuery{
 		earliestCloseTime: time.Unix(0, 10),
 		latestCloseTime:   time.Unix(0, 10001),
 		status:            toWorkflowExecutionStatusPtr(enumspb.WORKFLOW_EXECUTION_STATUS_FAILED),
@@ -657,6 +773,26 @@ func (s *visibilityArchiverSuite) setupVisibilityDirectory() {
 	}
 }
 
+func (s *visibilityArchiverSuite) newSearchAttributesRecord(
+	workflowID string,
+	status enumspb.WorkflowExecutionStatus,
+	historyLength int64,
+	keyword string,
+) *archiverspb.VisibilityRecord {
+	return &archiverspb.VisibilityRecord{
+		NamespaceId:      testNamespaceID,
+		Namespace:        testNamespace,
+		WorkflowId:       workflowID,
+		RunId:            workflowID + "-run",
+		WorkflowTypeName: testWorkflowTypeName,
+		StartTime:        timestamp.UnixOrZeroTimePtr(1),
+		CloseTime:        timestamp.UnixOrZeroTimePtr(historyLength * 100),
+		Status:           status,
+		HistoryLength:    historyLength,
+		SearchAttributes: map[string]string{"CustomKeywordField": keyword},
+	}
+}
+
 func (s *visibilityArchiverSuite) writeVisibilityRecordForQueryTest(record *archiverspb.VisibilityRecord) {
 	data, err := encode(record)
 	s.Require().NoError(err)
@@ -0,0 +1,432 @@
+// The MIT License
+//
+// Copyright (c) 2020 Temporal Technologies Inc.  All rights reserved.
+//
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package archiver
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+
+	"github.com/klauspost/compress/zstd"
+	commonpb "go.temporal.io/api/common/v1"
+	historypb "go.temporal.io/api/history/v1"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+
+	"go.temporal.io/server/common/clock"
+	"go.temporal.io/server/common/config"
+)
+
+// Archived history blobs are written either in the legacy format, which is the encoded history as is,
+// or in the manifest format:
+//
+//	magic | manifest length (4 bytes, big endian) | manifest (JSON) | body
+//
+// The manifest describes how the body is compressed and lists the keys of the payloads which were deduplicated
+// out of the history. Those payloads are replaced by references and stored separately in a ContentStore, once
+// per content hash and month, under the key <yyyy-mm>/<hash>. The magic starts with a zero byte, which never
+// starts a legacy (JSON) blob.
+
+const (
+	// CompressionNone writes history blobs without compression
+	CompressionNone = "none"
+	// CompressionGzip compresses history blobs with gzip
+	CompressionGzip = "gzip"
+	// CompressionZstd compresses history blobs with zstd
+	CompressionZstd = "zstd"
+
+	historyBlobFormatVersion = 1
+	payloadRefEncoding       = "archive/payload-ref"
+	maxManifestSize          = 64 * 1024 * 1024
+	payloadPeriodLayout      = "2006-01"
+)
+
+var (
+	historyBlobMagic = []byte{0, 'T', 'H', 'B'}
+
+	// ErrUnknownCompression is the error for an unknown compression codec
+	ErrUnknownCompression = errors.New("unknown history archive compression codec")
+	// ErrHistoryBlobCorrupted is the error for a history blob which can not be decoded
+	ErrHistoryBlobCorrupted = errors.New("archived history blob is corrupted")
+
+	zstdEncoder, _ = zstd.NewWriter(nil)
+	zstdDecoder, _ = zstd.NewReader(nil)
+)
+
+type (
+	// ContentStore stores blobs under keys of the form <yyyy-mm>/<hash>. It is used to store deduplicated payloads.
+	//
+	// Stored blobs are never deleted or rewritten by the server. A history only references blobs of the month it
+	// was archived in, which are created in that month too, so a blob is at most one month older than the newest
+	// history referencing it. This makes it safe to expire blobs by age, with a retention at least one month
+	// longer than the one of archived histories.
+	ContentStore interface {
+		Exists(ctx context.Context, key string) (bool, error)
+		Put(ctx context.Context, key string, data []byte) error
+		Get(ctx context.Context, key string) ([]byte, error)
+	}
+
+	// HistoryBlobManifest describes an archived history blob written in the manifest format
+	HistoryBlobManifest struct {
+		FormatVersion int    `json:"formatVersion"`
+		Compression   string `json:"compression"`
+		// Payloads are the ContentStore keys of the payloads referenced by the blob
+		Payloads []string `json:"payloads,omitempty"`
+	}
+
+	// HistoryBlobCodec compresses and deduplicates history before it is archived
+	HistoryBlobCodec struct {
+		compression    string
+		dedupThreshold int
+		timeSource     clock.TimeSource
+	}
+)
+
+// NewHistoryBlobCodec creates a HistoryBlobCodec from config. A nil config writes legacy blobs.
+func NewHistoryBlobCodec(cfg *config.HistoryArchiveCompression) (*HistoryBlobCodec, error) {
+	c := &HistoryBlobCodec{
+		compression: CompressionNone,
+		timeSource:  clock.NewRealTimeSource(),
+	}
+	if cfg == nil {
+		return c, nil
+	}
+	switch cfg.Codec {
+	case "", CompressionNone:
+	case CompressionGzip, CompressionZstd:
+		c.compression = cfg.Codec
+	default:
+		return nil, fmt.Errorf("%w: %q", ErrUnknownCompression, cfg.Codec)
+	}
+	if cfg.DedupThreshold < 0 {
+		return nil, fmt.Errorf("invalid history archive dedup threshold: %d", cfg.DedupThreshold)
+	}
+	c.dedupThreshold = cfg.DedupThreshold
+	return c, nil
+}
+
+// EncodeHistory deduplicates payloads of the given history batches into store, encodes the batches with encode
+// and wraps the result into a history blob. The given batches are not modified.
+// Without compression and deduplication the blob is written in the legacy format.
+func (c *HistoryBlobCodec) EncodeHistory(
+	ctx context.Context,
+	store ContentStore,
+	historyBatches []*historypb.History,
+	encode func([]*historypb.History) ([]byte, error),
+) ([]byte, error) {
+	var payloads []string
+	if c.dedupThreshold > 0 {
+		var err error
+		historyBatches, payloads, err = c.dedupPayloads(ctx, store, historyBatches)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	data, err := encode(historyBatches)
+	if err != nil {
+		return nil, err
+	}
+	if c.compression == CompressionNone && len(payloads) == 0 {
+		return data, nil
+	}
+	return c.encodeBlob(data, payloads)
+}
+
+func (c *HistoryBlobCodec) dedupPayloads(
+	ctx context.Context,
+	store ContentStore,
+	historyBatches []*historypb.History,
+) ([]*historypb.History, []string, error) {
+	var keys []string
+	period := c.timeSource.Now().UTC().Format(payloadPeriodLayout)
+	stored := make(map[string]struct{})
+	result := make([]*historypb.History, 0, len(historyBatches))
+	for _, batch := range historyBatches {
+		if !c.hasLargePayload(batch) {
+			result = append(result, batch)
+			continue
+		}
+
+		// payloads are replaced in place, do not modify the caller's batch
+		batch = proto.Clone(batch).(*historypb.History)
+		err := visitPayloads(batch.ProtoReflect(), func(payload *commonpb.Payload) error {
+			if proto.Size(payload) < c.dedupThreshold {
+				return nil
+			}
+			data, err := proto.MarshalOptions{Deterministic: true}.Marshal(payload)
+			if err != nil {
+				return err
+			}
+			key := period + "/" + contentHash(data)
+			if _, ok := stored[key]; !ok {
+				if err := c.putContent(ctx, store, key, data); err != nil {
+					return err
+				}
+				stored[key] = struct{}{}
+				keys = append(keys, key)
+			}
+			proto.Reset(payload)
+			payload.Metadata = map[string][]byte{"encoding": []byte(payloadRefEncoding)}
+			payload.Data = []byte(key)
+			return nil
+		})
+		if err != nil {
+			return nil, nil, err
+		}
+		result = append(result, batch)
+	}
+	return result, keys, nil
+}
+
+func (c *HistoryBlobCodec) hasLargePayload(batch *historypb.History) bool {
+	found := false
+	_ = visitPayloads(batch.ProtoReflect(), func(payload *commonpb.Payload) error {
+		if proto.Size(payload) >= c.dedupThreshold {
+			found = true
+			return errStopVisit
+		}
+		return nil
+	})
+	return found
+}
+
+func (c *HistoryBlobCodec) putContent(ctx context.Context, store ContentStore, key string, data []byte) error {
+	exists, err := store.Exists(ctx, key)
+	if err != nil || exists {
+		return err
+	}
+	blob, err := c.encodeBlob(data, nil)
+	if err != nil {
+		return err
+	}
+	return store.Put(ctx, key, blob)
+}
+
+func (c *HistoryBlobCodec) encodeBlob(data []byte, payloads []string) ([]byte, error) {
+	manifest, err := json.Marshal(&HistoryBlobManifest{
+		FormatVersion: historyBlobFormatVersion,
+		Compression:   c.compression,
+		Payloads:      payloads,
+	})
+	if err != nil {
+		return nil, err
+	}
+	body, err := compress(c.compression, data)
+	if err != nil {
+		return nil, err
+	}
+
+	blob := make([]byte, 0, len(historyBlobMagic)+4+len(manifest)+len(body))
+	blob = append(blob, historyBlobMagic...)
+	blob = binary.BigEndian.AppendUint32(blob, uint32(len(manifest)))
+	blob = append(blob, manifest...)
+	return append(blob, body...), nil
+}
+
+// DecodeHistoryBlob returns the decompressed content of a history blob and its manifest.
+// The manifest is nil for blobs written in the legacy format, which are returned as is.
+func DecodeHistoryBlob(blob []byte) (*HistoryBlobManifest, []byte, error) {
+	if !bytes.HasPrefix(blob, historyBlobMagic) {
+		return nil, blob, nil
+	}
+	blob = blob[len(historyBlobMagic):]
+	if len(blob) < 4 {
+		return nil, nil, ErrHistoryBlobCorrupted
+	}
+	manifestSize := binary.BigEndian.Uint32(blob)
+	blob = blob[4:]
+	if manifestSize > maxManifestSize || int(manifestSize) > len(blob) {
+		return nil, nil, ErrHistoryBlobCorrupted
+	}
+	var manifest HistoryBlobManifest
+	if err := json.Unmarshal(blob[:manifestSize], &manifest); err != nil {
+		return nil, nil, fmt.Errorf("%w: %v", ErrHistoryBlobCorrupted, err)
+	}
+	if manifest.FormatVersion > historyBlobFormatVersion {
+		return nil, nil, fmt.Errorf("%w: unsupported format version %d", ErrHistoryBlobCorrupted, manifest.FormatVersion)
+	}
+	data, err := decompress(manifest.Compression, blob[manifestSize:])
+	if err != nil {
+		return nil, nil, err
+	}
+	return &manifest, data, nil
+}
+
+// ResolveHistoryPayloads replaces in place the payload references in history batches decoded from a blob
+// with the given manifest by the payloads they refer to.
+func ResolveHistoryPayloads(
+	ctx context.Context,
+	store ContentStore,
+	manifest *HistoryBlobManifest,
+	historyBatches []*historypb.History,
+) error {
+	if manifest == nil || len(manifest.Payloads) == 0 {
+		return nil
+	}
+	referenced := make(map[string]struct{}, len(manifest.Payloads))
+	for _, key := range manifest.Payloads {
+		referenced[key] = struct{}{}
+	}
+	resolved := make(map[string]*commonpb.Payload)
+	for _, batch := range historyBatches {
+		err := visitPayloads(batch.ProtoReflect(), func(payload *commonpb.Payload) error {
+			if string(payload.GetMetadata()["encoding"]) != payloadRefEncoding {
+				return nil
+			}
+			key := string(payload.GetData())
+			if _, ok := referenced[key]; !ok {
+				return nil
+			}
+			content, ok := resolved[key]
+			if !ok {
+				var err error
+				if content, err = getContent(ctx, store, key); err != nil {
+					return err
+				}
+				resolved[key] = content
+			}
+			proto.Reset(payload)
+			proto.Merge(payload, content)
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func getContent(ctx context.Context, store ContentStore, key string) (*commonpb.Payload, error) {
+	blob, err := store.Get(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+	_, data, err := DecodeHistoryBlob(blob)
+	if err != nil {
+		return nil, err
+	}
+	if contentHash(data) != path.Base(key) {
+		return nil, fmt.Errorf("%w: payload %v does not match its content hash", ErrHistoryBlobCorrupted, key)
+	}
+	payload := &commonpb.Payload{}
+	if err := proto.Unmarshal(data, payload); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrHistoryBlobCorrupted, err)
+	}
+	return payload, nil
+}
+
+func contentHash(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+func compress(compression string, data []byte) ([]byte, error) {
+	switch compression {
+	case CompressionNone:
+		return data, nil
+	case CompressionGzip:
+		var buf bytes.Buffer
+		w := gzip.NewWriter(&buf)
+		if _, err := w.Write(data); err != nil {
+			return nil, err
+		}
+		if err := w.Close(); err != nil {
+			return nil, err
+		}
+		return buf.Bytes(), nil
+	case CompressionZstd:
+		return zstdEncoder.EncodeAll(data, nil), nil
+	default:
+		return nil, fmt.Errorf("%w: %q", ErrUnknownCompression, compression)
+	}
+}
+
+func decompress(compression string, data []byte) ([]byte, error) {
+	switch compression {
+	case CompressionNone:
+		return data, nil
+	case CompressionGzip:
+		r, err := gzip.NewReader(bytes.NewReader(data))
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrHistoryBlobCorrupted, err)
+		}
+		result, err := io.ReadAll(r)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrHistoryBlobCorrupted, err)
+		}
+		return result, nil
+	case CompressionZstd:
+		result, err := zstdDecoder.DecodeAll(data, nil)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrHistoryBlobCorrupted, err)
+		}
+		return result, nil
+	default:
+		return nil, fmt.Errorf("%w: %q", ErrUnknownCompression, compression)
+	}
+}
+
+var errStopVisit = errors.New("stop visit")
+
+// visitPayloads calls fn for every Payload message reachable from m
+func visitPayloads(m protoreflect.Message, fn func(*commonpb.Payload) error) error {
+	if payload, ok := m.Interface().(*commonpb.Payload); ok {
+		return fn(payload)
+	}
+	var err error
+	m.Range(func(fd protoreflect.FieldDescriptor, v protoreflect.Value) bool {
+		switch {
+		case fd.IsList():
+			if fd.Message() == nil {
+				return true
+			}
+			list := v.List()
+			for i := 0; i < list.Len() && err == nil; i++ {
+				err = visitPayloads(list.Get(i).Message(), fn)
+			}
+		case fd.IsMap():
+			if fd.MapValue().Message() == nil {
+				return true
+			}
+			v.Map().Range(func(_ protoreflect.MapKey, value protoreflect.Value) bool {
+				err = visitPayloads(value.Message(), fn)
+				return err == nil
+			})
+		case fd.Message() != nil:
+			err = visitPayloads(v.Message(), fn)
+		}
+		return err == nil
+	})
+	return err
+}
@@ -0,0 +1,254 @@
+// The MIT License
+//
+// Copyright (c) 2020 Temporal Technologies Inc.  All rights reserved.
+//
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package archiver
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"path"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	commonpb "go.temporal.io/api/common/v1"
+	historypb "go.temporal.io/api/history/v1"
+	"google.golang.org/protobuf/proto"
+
+	"go.temporal.io/server/common/clock"
+	"go.temporal.io/server/common/codec"
+	"go.temporal.io/server/common/config"
+	"go.temporal.io/server/common/payloads"
+)
+
+type memContentStore struct {
+	blobs map[string][]byte
+	puts  int
+}
+
+func newMemContentStore() *memContentStore {
+	return &memContentStore{blobs: make(map[string][]byte)}
+}
+
+func (s *memContentStore) Exists(_ context.Context, key string) (bool, error) {
+	_, ok := s.blobs[key]
+	return ok, nil
+}
+
+func (s *memContentStore) Put(_ context.Context, key string, data []byte) error {
+	s.puts++
+	s.blobs[key] = data
+	return nil
+}
+
+func (s *memContentStore) Get(_ context.Context, key string) ([]byte, error) {
+	data, ok := s.blobs[key]
+	if !ok {
+		return nil, errors.New("not found")
+	}
+	return data, nil
+}
+
+func testHistoryBatches(input string) []*historypb.History {
+	return []*historypb.History{
+		{
+			Events: []*historypb.HistoryEvent{
+				{
+					EventId: 1,
+					Attributes: &historypb.HistoryEvent_WorkflowExecutionStartedEventAttributes{
+						WorkflowExecutionStartedEventAttributes: &historypb.WorkflowExecutionStartedEventAttributes{
+							Input: payloads.EncodeString(input),
+							Memo: &commonpb.Memo{Fields: map[string]*commonpb.Payload{
+								"memo": payloads.EncodeString(input).Payloads[0],
+							}},
+						},
+					},
+				},
+			},
+		},
+		{
+			Events: []*historypb.HistoryEvent{
+				{
+					EventId: 2,
+					Attributes: &historypb.HistoryEvent_WorkflowExecutionCompletedEventAttributes{
+						WorkflowExecutionCompletedEventAttributes: &historypb.WorkflowExecutionCompletedEventAttributes{
+							Result: payloads.EncodeString("small"),
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func encodeHistories(histories []*historypb.History) ([]byte, error) {
+	encoder := codec.NewJSONPBEncoder()
+	return encoder.EncodeHistories(histories)
+}
+
+func decodeHistories(t *testing.T, store ContentStore, blob []byte) (*HistoryBlobManifest, []*historypb.History) {
+	manifest, data, err := DecodeHistoryBlob(blob)
+	require.NoError(t, err)
+	encoder := codec.NewJSONPBEncoder()
+	histories, err := encoder.DecodeHistories(data)
+	require.NoError(t, err)
+	require.NoError(t, ResolveHistoryPayloads(context.Background(), store, manifest, histories))
+	return manifest, histories
+}
+
+func TestNewHistoryBlobCodec(t *testing.T) {
+	for _, cfg := range []*config.HistoryArchiveCompression{
+		nil,
+		{},
+		{Codec: CompressionNone},
+		{Codec: CompressionGzip, DedupThreshold: 1024},
+		{Codec: CompressionZstd},
+	} {
+		_, err := NewHistoryBlobCodec(cfg)
+		require.NoError(t, err)
+	}
+
+	_, err := NewHistoryBlobCodec(&config.HistoryArchiveCompression{Codec: "lz4"})
+	require.ErrorIs(t, err, ErrUnknownCompression)
+	_, err = NewHistoryBlobCodec(&config.HistoryArchiveCompression{DedupThreshold: -1})
+	require.Error(t, err)
+}
+
+func TestHistoryBlobCodec_Legacy(t *testing.T) {
+	blobCodec, err := NewHistoryBlobCodec(nil)
+	require.NoError(t, err)
+	store := newMemContentStore()
+	historyBatches := testHistoryBatches(strings.Repeat("a", 4096))
+
+	blob, err := blobCodec.EncodeHistory(context.Background(), store, historyBatches, encodeHistories)
+	require.NoError(t, err)
+	legacyBlob, err := encodeHistories(historyBatches)
+	require.NoError(t, err)
+	require.Equal(t, legacyBlob, blob)
+
+	manifest, decoded := decodeHistories(t, store, blob)
+	require.Nil(t, manifest)
+	require.Len(t, decoded, len(historyBatches))
+	for i := range historyBatches {
+		require.True(t, proto.Equal(historyBatches[i], decoded[i]))
+	}
+}
+
+func TestHistoryBlobCodec_Compression(t *testing.T) {
+	for _, compression := range []string{CompressionGzip, CompressionZstd} {
+		t.Run(compression, func(t *testing.T) {
+			blobCodec, err := NewHistoryBlobCodec(&config.HistoryArchiveCompression{Codec: compression})
+			require.NoError(t, err)
+			store := newMemContentStore()
+			historyBatches := testHistoryBatches(strings.Repeat("a", 4096))
+
+			blob, err := blobCodec.EncodeHistory(context.Background(), store, historyBatches, encodeHistories)
+			require.NoError(t, err)
+			legacyBlob, err := encodeHistories(historyBatches)
+			require.NoError(t, err)
+			require.Less(t, len(blob), len(legacyBlob)/2)
+			require.Empty(t, store.blobs)
+
+			manifest, decoded := decodeHistories(t, store, blob)
+			require.Equal(t, compression, manifest.Compression)
+			require.Empty(t, manifest.Payloads)
+			require.Len(t, decoded, len(historyBatches))
+			for i := range historyBatches {
+				require.True(t, proto.Equal(historyBatches[i], decoded[i]))
+			}
+		})
+	}
+}
+
+func TestHistoryBlobCodec_Dedup(t *testing.T) {
+	blobCodec, err := NewHistoryBlobCodec(&config.HistoryArchiveCompression{Codec: CompressionZstd, DedupThreshold: 1024})
+	require.NoError(t, err)
+	timeSource := clock.NewEventTimeSource().Update(time.Date(2026, 1, 31, 12, 0, 0, 0, time.UTC))
+	blobCodec.timeSource = timeSource
+	store := newMemContentStore()
+	largeInput := strings.Repeat("b", 4096)
+
+	// three runs of the same workflow with the same input, the last one in the next month
+	var blobs [][]byte
+	for i := 0; i < 3; i++ {
+		if i == 2 {
+			timeSource.Advance(24 * time.Hour)
+		}
+		historyBatches := testHistoryBatches(largeInput)
+		original := proto.Clone(historyBatches[0])
+		blob, err := blobCodec.EncodeHistory(context.Background(), store, historyBatches, encodeHistories)
+		require.NoError(t, err)
+		require.True(t, proto.Equal(original, historyBatches[0]), "encoding must not modify the given batches")
+		blobs = append(blobs, blob)
+	}
+	// input and memo hold the same payload, which is stored once per month
+	require.Len(t, store.blobs, 2)
+	require.Equal(t, 2, store.puts)
+	var keys []string
+	for key := range store.blobs {
+		keys = append(keys, path.Dir(key))
+	}
+	require.ElementsMatch(t, []string{"2026-01", "2026-02"}, keys)
+
+	for _, blob := range blobs {
+		_, data, err := DecodeHistoryBlob(blob)
+		require.NoError(t, err)
+		require.False(t, bytes.Contains(data, []byte(largeInput)))
+
+		manifest, decoded := decodeHistories(t, store, blob)
+		require.Len(t, manifest.Payloads, 1)
+		expected := testHistoryBatches(largeInput)
+		require.Len(t, decoded, len(expected))
+		for i := range expected {
+			require.True(t, proto.Equal(expected[i], decoded[i]))
+		}
+	}
+}
+
+func TestHistoryBlobCodec_Corrupted(t *testing.T) {
+	blobCodec, err := NewHistoryBlobCodec(&config.HistoryArchiveCompression{Codec: CompressionGzip, DedupThreshold: 1024})
+	require.NoError(t, err)
+	store := newMemContentStore()
+	blob, err := blobCodec.EncodeHistory(context.Background(), store, testHistoryBatches(strings.Repeat("c", 4096)), encodeHistories)
+	require.NoError(t, err)
+
+	_, _, err = DecodeHistoryBlob(blob[:len(historyBlobMagic)+2])
+	require.ErrorIs(t, err, ErrHistoryBlobCorrupted)
+	_, _, err = DecodeHistoryBlob(blob[:len(blob)-8])
+	require.ErrorIs(t, err, ErrHistoryBlobCorrupted)
+
+	manifest, data, err := DecodeHistoryBlob(blob)
+	require.NoError(t, err)
+	encoder := codec.NewJSONPBEncoder()
+	histories, err := encoder.DecodeHistories(data)
+	require.NoError(t, err)
+	for hash := range store.blobs {
+		store.blobs[hash], err = blobCodec.encodeBlob([]byte("tampered"), nil)
+		require.NoError(t, err)
+	}
+	err = ResolveHistoryPayloads(context.Background(), store, manifest, histories)
+	require.ErrorIs(t, err, ErrHistoryBlobCorrupted)
+}
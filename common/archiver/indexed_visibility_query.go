@@ -0,0 +1,275 @@
+// The MIT License
+//
+// Copyright (c) 2020 Temporal Technologies Inc.  All rights reserved.
+//
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package archiver
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/temporalio/sqlparser"
+
+	"go.temporal.io/server/common/primitives/timestamp"
+	"go.temporal.io/server/common/searchattribute"
+)
+
+type (
+	// IndexedVisibilityQuery is a VisibilityQuery for archivers which store every record under its
+	// WorkflowId and WorkflowType, keyed by StartTime and CloseTime, such as s3store and azblob.
+	// Besides the visibility query syntax, it accepts WorkflowTypeName as an alias of WorkflowType and
+	// a SearchPrecision condition which turns StartTime and CloseTime equality conditions into the
+	// time range covered by the precision.
+	IndexedVisibilityQuery struct {
+		WorkflowTypeName *string
+		WorkflowID       *string
+		StartTime        *time.Time
+		CloseTime        *time.Time
+		SearchPrecision  *string
+		// EmptyResult is true if the conditions on the indexes contradict each other.
+		EmptyResult bool
+		// Query is the full query which records are matched against. The fields above are derived
+		// from it and are only used to choose the keys to list.
+		Query *VisibilityQuery
+	}
+)
+
+// Fields used to choose the keys to list. WorkflowTypeName is an alias of the WorkflowType search attribute.
+const (
+	WorkflowTypeName = "WorkflowTypeName"
+	SearchPrecision  = "SearchPrecision"
+)
+
+// Precision specific values
+const (
+	PrecisionDay    = "Day"
+	PrecisionHour   = "Hour"
+	PrecisionMinute = "Minute"
+	PrecisionSecond = "Second"
+)
+
+var precisionDurations = map[string]time.Duration{
+	PrecisionDay:    24 * time.Hour,
+	PrecisionHour:   time.Hour,
+	PrecisionMinute: time.Minute,
+	PrecisionSecond: time.Second,
+}
+
+// ParseIndexedVisibilityQuery parses a visibility query and extracts the conditions on the indexes.
+func ParseIndexedVisibilityQuery(queryStr string, saTypeMap searchattribute.NameTypeMap) (*IndexedVisibilityQuery, error) {
+	sel, err := ParseVisibilityQueryStatement(queryStr)
+	if err != nil {
+		return nil, err
+	}
+	if err := sqlparser.Walk(renameWorkflowTypeName, sel); err != nil {
+		return nil, err
+	}
+
+	indexedQuery := &IndexedVisibilityQuery{}
+	if sel.Where != nil {
+		whereExpr, err := convertSearchPrecision(sel.Where.Expr, indexedQuery)
+		if err != nil {
+			return nil, err
+		}
+		if whereExpr == nil {
+			sel.Where = nil
+		} else {
+			sel.Where.Expr = whereExpr
+		}
+	}
+
+	visibilityQuery, err := NewVisibilityQuery(sel, saTypeMap)
+	if err != nil {
+		return nil, err
+	}
+	indexedQuery.Query = visibilityQuery
+	for _, condition := range visibilityQuery.Conditions() {
+		convertIndexCondition(condition, indexedQuery)
+	}
+	return indexedQuery, nil
+}
+
+// convertSearchPrecision removes SearchPrecision from the top level conditions and replaces StartTime
+// and CloseTime equality conditions with the time range covered by the precision.
+func convertSearchPrecision(expr sqlparser.Expr, indexedQuery *IndexedVisibilityQuery) (sqlparser.Expr, error) {
+	var conjuncts []sqlparser.Expr
+	for _, conjunct := range splitConjuncts(expr) {
+		compExpr, ok := conjunct.(*sqlparser.ComparisonExpr)
+		if !ok || !isColName(compExpr.Left, SearchPrecision) {
+			conjuncts = append(conjuncts, conjunct)
+			continue
+		}
+		if err := convertPrecision(compExpr, indexedQuery); err != nil {
+			return nil, err
+		}
+	}
+
+	var nestedPrecision bool
+	_ = sqlparser.Walk(func(node sqlparser.SQLNode) (bool, error) {
+		if colName, ok := node.(*sqlparser.ColName); ok && colName.Name.String() == SearchPrecision {
+			nestedPrecision = true
+		}
+		return !nestedPrecision, nil
+	}, joinConjuncts(conjuncts))
+	if nestedPrecision {
+		return nil, fmt.Errorf("%s can only be combined with other conditions using and", SearchPrecision)
+	}
+	if indexedQuery.SearchPrecision == nil {
+		return joinConjuncts(conjuncts), nil
+	}
+
+	for i, conjunct := range conjuncts {
+		compExpr, ok := conjunct.(*sqlparser.ComparisonExpr)
+		if !ok || compExpr.Operator != sqlparser.EqualStr {
+			continue
+		}
+		var timeField **time.Time
+		switch {
+		case isColName(compExpr.Left, searchattribute.StartTime):
+			timeField = &indexedQuery.StartTime
+		case isColName(compExpr.Left, searchattribute.CloseTime):
+			timeField = &indexedQuery.CloseTime
+		default:
+			continue
+		}
+		t, err := convertToIndexTime(sqlparser.String(compExpr.Right))
+		if err != nil {
+			return nil, err
+		}
+		t = t.UTC()
+		*timeField = &t
+
+		precision := precisionDurations[*indexedQuery.SearchPrecision]
+		start := t.Truncate(precision)
+		end := start.Add(precision - time.Nanosecond)
+		conjuncts[i] = &sqlparser.RangeCond{
+			Operator: sqlparser.BetweenStr,
+			Left:     compExpr.Left,
+			From:     sqlparser.NewStrVal([]byte(start.Format(time.RFC3339Nano))),
+			To:       sqlparser.NewStrVal([]byte(end.Format(time.RFC3339Nano))),
+		}
+	}
+	if indexedQuery.CloseTime == nil && indexedQuery.StartTime == nil {
+		return nil, errors.New("SearchPrecision requires a StartTime or CloseTime")
+	}
+	return joinConjuncts(conjuncts), nil
+}
+
+func convertPrecision(compExpr *sqlparser.ComparisonExpr, indexedQuery *IndexedVisibilityQuery) error {
+	valExpr, ok := compExpr.Right.(*sqlparser.SQLVal)
+	if !ok {
+		return fmt.Errorf("invalid value: %s", sqlparser.String(compExpr.Right))
+	}
+	val, err := extractStringValue(sqlparser.String(valExpr))
+	if err != nil {
+		return err
+	}
+	if compExpr.Operator != sqlparser.EqualStr {
+		return fmt.Errorf("only operation = is support for %s", SearchPrecision)
+	}
+	if indexedQuery.SearchPrecision != nil && *indexedQuery.SearchPrecision != val {
+		return fmt.Errorf("only one expression is allowed for %s", SearchPrecision)
+	}
+	if _, ok := precisionDurations[val]; !ok {
+		return fmt.Errorf("invalid value for %s: %s", SearchPrecision, val)
+	}
+	indexedQuery.SearchPrecision = &val
+	return nil
+}
+
+func convertIndexCondition(condition *VisibilityCondition, indexedQuery *IndexedVisibilityQuery) {
+	if condition.Operator != sqlparser.EqualStr {
+		return
+	}
+	var field **string
+	switch condition.Name {
+	case searchattribute.WorkflowID:
+		field = &indexedQuery.WorkflowID
+	case searchattribute.WorkflowType:
+		field = &indexedQuery.WorkflowTypeName
+	default:
+		return
+	}
+	val := condition.Values[0].(string)
+	if *field != nil && **field != val {
+		indexedQuery.EmptyResult = true
+		return
+	}
+	*field = &val
+}
+
+func renameWorkflowTypeName(node sqlparser.SQLNode) (bool, error) {
+	if colName, ok := node.(*sqlparser.ColName); ok && colName.Name.String() == WorkflowTypeName {
+		colName.Name = sqlparser.NewColIdent(searchattribute.WorkflowType)
+	}
+	return true, nil
+}
+
+func splitConjuncts(expr sqlparser.Expr) []sqlparser.Expr {
+	switch e := expr.(type) {
+	case *sqlparser.AndExpr:
+		return append(splitConjuncts(e.Left), splitConjuncts(e.Right)...)
+	case *sqlparser.ParenExpr:
+		return splitConjuncts(e.Expr)
+	default:
+		return []sqlparser.Expr{expr}
+	}
+}
+
+func joinConjuncts(conjuncts []sqlparser.Expr) sqlparser.Expr {
+	var expr sqlparser.Expr
+	for _, conjunct := range conjuncts {
+		if expr == nil {
+			expr = conjunct
+			continue
+		}
+		expr = &sqlparser.AndExpr{Left: expr, Right: conjunct}
+	}
+	return expr
+}
+
+func isColName(expr sqlparser.Expr, name string) bool {
+	colName, ok := expr.(*sqlparser.ColName)
+	return ok && colName.Name.String() == name
+}
+
+func convertToIndexTime(timeStr string) (time.Time, error) {
+	ts, err := strconv.ParseInt(timeStr, 10, 64)
+	if err == nil {
+		return timestamp.UnixOrZeroTime(ts), nil
+	}
+	timestampStr, err := extractStringValue(timeStr)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return time.Parse(time.RFC3339, timestampStr)
+}
+
+func extractStringValue(s string) (string, error) {
+	if len(s) >= 2 && s[0] == '\'' && s[len(s)-1] == '\'' {
+		return s[1 : len(s)-1], nil
+	}
+	return "", fmt.Errorf("value %s is not a string value", s)
+}
@@ -22,7 +22,7 @@
 // OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
 // THE SOFTWARE.
 
-package s3store
+package archiver
 
 import (
 	"testing"
@@ -32,68 +32,88 @@ import (
 	"github.com/stretchr/testify/suite"
 
 	"go.temporal.io/server/common/convert"
+	"go.temporal.io/server/common/searchattribute"
 )
 
-type queryParserSuite struct {
+type indexedVisibilityQuerySuite struct {
 	*require.Assertions
 	suite.Suite
-
-	parser QueryParser
 }
 
-func TestQueryParserSuite(t *testing.T) {
-	suite.Run(t, new(queryParserSuite))
+func TestIndexedVisibilityQuerySuite(t *testing.T) {
+	suite.Run(t, new(indexedVisibilityQuerySuite))
 }
 
-func (s *queryParserSuite) SetupTest() {
+func (s *indexedVisibilityQuerySuite) SetupTest() {
 	s.Assertions = require.New(s.T())
-	s.parser = NewQueryParser()
 }
 
-func (s *queryParserSuite) TestParseWorkflowIDAndWorkflowTypeName() {
+func (s *indexedVisibilityQuerySuite) TestParseWorkflowIDAndWorkflowTypeName() {
 	testCases := []struct {
 		query       string
 		expectErr   bool
-		parsedQuery *parsedQuery
+		parsedQuery *IndexedVisibilityQuery
 	}{
 		{
 			query:     "WorkflowId = \"random workflowID\"",
 			expectErr: false,
-			parsedQuery: &parsedQuery{
-				workflowID: convert.StringPtr("random workflowID"),
+			parsedQuery: &IndexedVisibilityQuery{
+				WorkflowID: convert.StringPtr("random workflowID"),
 			},
 		},
 		{
 			query:     "WorkflowTypeName = \"random workflowTypeName\"",
 			expectErr: false,
-			parsedQuery: &parsedQuery{
-				workflowTypeName: convert.StringPtr("random workflowTypeName"),
+			parsedQuery: &IndexedVisibilityQuery{
+				WorkflowTypeName: convert.StringPtr("random workflowTypeName"),
 			},
 		},
 		{
 			query:     "WorkflowId = \"random workflowID\" and WorkflowTypeName = \"random workflowTypeName\"",
-			expectErr: true,
+			expectErr: false,
+			parsedQuery: &IndexedVisibilityQuery{
+				WorkflowID:       convert.StringPtr("random workflowID"),
+				WorkflowTypeName: convert.StringPtr("random workflowTypeName"),
+			},
 		},
 		{
 			query:     "WorkflowId = \"random workflowID\" and WorkflowId = \"random workflowID\"",
-			expectErr: true,
+			expectErr: false,
+			parsedQuery: &IndexedVisibilityQuery{
+				WorkflowID: convert.StringPtr("random workflowID"),
+			},
 		},
 		{
-			query:     "RunId = \"random runID\"",
-			expectErr: true,
+			query:     "WorkflowId = \"random workflowID\" and WorkflowId = \"another workflowID\"",
+			expectErr: false,
+			parsedQuery: &IndexedVisibilityQuery{
+				EmptyResult: true,
+			},
+		},
+		{
+			query:       "RunId = \"random runID\"",
+			expectErr:   false,
+			parsedQuery: &IndexedVisibilityQuery{},
+		},
+		{
+			query:     "WorkflowType = \"random workflowTypeName\" and CustomKeywordField in ('a', 'b') order by CloseTime",
+			expectErr: false,
+			parsedQuery: &IndexedVisibilityQuery{
+				WorkflowTypeName: convert.StringPtr("random workflowTypeName"),
+			},
 		},
 		{
 			query:     "WorkflowId = 'random workflowID'",
 			expectErr: false,
-			parsedQuery: &parsedQuery{
-				workflowID: convert.StringPtr("random workflowID"),
+			parsedQuery: &IndexedVisibilityQuery{
+				WorkflowID: convert.StringPtr("random workflowID"),
 			},
 		},
 		{
 			query:     "(WorkflowId = \"random workflowID\")",
 			expectErr: false,
-			parsedQuery: &parsedQuery{
-				workflowID: convert.StringPtr("random workflowID"),
+			parsedQuery: &IndexedVisibilityQuery{
+				WorkflowID: convert.StringPtr("random workflowID"),
 			},
 		},
 		{
@@ -101,8 +121,9 @@ func (s *queryParserSuite) TestParseWorkflowIDAndWorkflowTypeName() {
 			expectErr: true,
 		},
 		{
-			query:     "WorkflowId = \"random workflowID\" or WorkflowId = \"another workflowID\"",
-			expectErr: true,
+			query:       "WorkflowId = \"random workflowID\" or WorkflowId = \"another workflowID\"",
+			expectErr:   false,
+			parsedQuery: &IndexedVisibilityQuery{},
 		},
 		{
 			query:     "WorkflowId = \"random workflowID\" or runId = \"random runID\"",
@@ -119,51 +140,54 @@ func (s *queryParserSuite) TestParseWorkflowIDAndWorkflowTypeName() {
 	}
 
 	for _, tc := range testCases {
-		parsedQuery, err := s.parser.Parse(tc.query)
+		parsedQuery, err := ParseIndexedVisibilityQuery(tc.query, searchattribute.TestNameTypeMap)
 		if tc.expectErr {
 			s.Error(err)
 			continue
 		}
 		s.NoError(err)
-		s.Equal(tc.parsedQuery.workflowID, parsedQuery.workflowID)
-		s.Equal(tc.parsedQuery.workflowTypeName, parsedQuery.workflowTypeName)
+		s.Equal(tc.parsedQuery.EmptyResult, parsedQuery.EmptyResult)
+		if !tc.parsedQuery.EmptyResult {
+			s.Equal(tc.parsedQuery.WorkflowID, parsedQuery.WorkflowID)
+			s.Equal(tc.parsedQuery.WorkflowTypeName, parsedQuery.WorkflowTypeName)
+		}
 
 	}
 }
 
-func (s *queryParserSuite) TestParsePrecision() {
+func (s *indexedVisibilityQuerySuite) TestParsePrecision() {
 	commonQueryPart := "WorkflowId = \"random workflowID\" AND "
 	testCases := []struct {
 		query       string
 		expectErr   bool
-		parsedQuery *parsedQuery
+		parsedQuery *IndexedVisibilityQuery
 	}{
 		{
 			query:     commonQueryPart + "CloseTime = 1000 and SearchPrecision = 'Day'",
 			expectErr: false,
-			parsedQuery: &parsedQuery{
-				searchPrecision: convert.StringPtr(PrecisionDay),
+			parsedQuery: &IndexedVisibilityQuery{
+				SearchPrecision: convert.StringPtr(PrecisionDay),
 			},
 		},
 		{
 			query:     commonQueryPart + "CloseTime = 1000 and SearchPrecision = 'Hour'",
 			expectErr: false,
-			parsedQuery: &parsedQuery{
-				searchPrecision: convert.StringPtr(PrecisionHour),
+			parsedQuery: &IndexedVisibilityQuery{
+				SearchPrecision: convert.StringPtr(PrecisionHour),
 			},
 		},
 		{
 			query:     commonQueryPart + "CloseTime = 1000 and SearchPrecision = 'Minute'",
 			expectErr: false,
-			parsedQuery: &parsedQuery{
-				searchPrecision: convert.StringPtr(PrecisionMinute),
+			parsedQuery: &IndexedVisibilityQuery{
+				SearchPrecision: convert.StringPtr(PrecisionMinute),
 			},
 		},
 		{
 			query:     commonQueryPart + "StartTime = 1000 and SearchPrecision = 'Second'",
 			expectErr: false,
-			parsedQuery: &parsedQuery{
-				searchPrecision: convert.StringPtr(PrecisionSecond),
+			parsedQuery: &IndexedVisibilityQuery{
+				SearchPrecision: convert.StringPtr(PrecisionSecond),
 			},
 		},
 		{
@@ -174,16 +198,20 @@ func (s *queryParserSuite) TestParsePrecision() {
 			query:     commonQueryPart + "SearchPrecision = 'Invalid string'",
 			expectErr: true,
 		},
+		{
+			query:     commonQueryPart + "(CloseTime = 1000 or SearchPrecision = 'Day')",
+			expectErr: true,
+		},
 	}
 
 	for _, tc := range testCases {
-		parsedQuery, err := s.parser.Parse(tc.query)
+		parsedQuery, err := ParseIndexedVisibilityQuery(tc.query, searchattribute.TestNameTypeMap)
 		if tc.expectErr {
 			s.Error(err)
 			continue
 		}
 		s.NoError(err)
-		s.Equal(tc.parsedQuery.searchPrecision, parsedQuery.searchPrecision)
+		s.Equal(tc.parsedQuery.SearchPrecision, parsedQuery.SearchPrecision)
 	}
 }
 
@@ -191,26 +219,26 @@ func ptr[T any](v T) *T {
 	return &v
 }
 
-func (s *queryParserSuite) TestParseCloseTime() {
+func (s *indexedVisibilityQuerySuite) TestParseCloseTime() {
 	commonQueryPart := "WorkflowId = \"random workflowID\" AND SearchPrecision = 'Day' AND "
 
 	testCases := []struct {
 		query       string
 		expectErr   bool
-		parsedQuery *parsedQuery
+		parsedQuery *IndexedVisibilityQuery
 	}{
 		{
 			query:     commonQueryPart + "CloseTime = 1000",
 			expectErr: false,
-			parsedQuery: &parsedQuery{
-				closeTime: ptr(time.Unix(0, 1000).UTC()),
+			parsedQuery: &IndexedVisibilityQuery{
+				CloseTime: ptr(time.Unix(0, 1000).UTC()),
 			},
 		},
 		{
 			query:     commonQueryPart + "CloseTime = \"2019-01-01T11:11:11Z\"",
 			expectErr: false,
-			parsedQuery: &parsedQuery{
-				closeTime: ptr(time.Date(2019, 1, 1, 11, 11, 11, 0, time.UTC)),
+			parsedQuery: &IndexedVisibilityQuery{
+				CloseTime: ptr(time.Date(2019, 1, 1, 11, 11, 11, 0, time.UTC)),
 			},
 		},
 		{
@@ -224,37 +252,37 @@ func (s *queryParserSuite) TestParseCloseTime() {
 	}
 
 	for _, tc := range testCases {
-		parsedQuery, err := s.parser.Parse(tc.query)
+		parsedQuery, err := ParseIndexedVisibilityQuery(tc.query, searchattribute.TestNameTypeMap)
 		if tc.expectErr {
 			s.Error(err)
 			continue
 		}
 		s.NoError(err)
-		s.Equal(tc.parsedQuery.closeTime, parsedQuery.closeTime)
+		s.Equal(tc.parsedQuery.CloseTime, parsedQuery.CloseTime)
 
 	}
 }
 
-func (s *queryParserSuite) TestParseStartTime() {
+func (s *indexedVisibilityQuerySuite) TestParseStartTime() {
 	commonQueryPart := "WorkflowId = \"random workflowID\" AND SearchPrecision = 'Day' AND "
 
 	testCases := []struct {
 		query       string
 		expectErr   bool
-		parsedQuery *parsedQuery
+		parsedQuery *IndexedVisibilityQuery
 	}{
 		{
 			query:     commonQueryPart + "StartTime = 1000",
 			expectErr: false,
-			parsedQuery: &parsedQuery{
-				startTime: ptr(time.Unix(0, 1000)),
+			parsedQuery: &IndexedVisibilityQuery{
+				StartTime: ptr(time.Unix(0, 1000)),
 			},
 		},
 		{
 			query:     commonQueryPart + "StartTime = \"2019-01-01T11:11:11Z\"",
 			expectErr: false,
-			parsedQuery: &parsedQuery{
-				startTime: ptr(time.Date(2019, 1, 1, 11, 11, 11, 0, time.UTC)),
+			parsedQuery: &IndexedVisibilityQuery{
+				StartTime: ptr(time.Date(2019, 1, 1, 11, 11, 11, 0, time.UTC)),
 			},
 		},
 		{
@@ -268,12 +296,12 @@ func (s *queryParserSuite) TestParseStartTime() {
 	}
 
 	for _, tc := range testCases {
-		parsedQuery, err := s.parser.Parse(tc.query)
+		parsedQuery, err := ParseIndexedVisibilityQuery(tc.query, searchattribute.TestNameTypeMap)
 		if tc.expectErr {
 			s.Error(err)
 			continue
 		}
 		s.NoError(err)
-		s.Equal(tc.parsedQuery.closeTime, parsedQuery.closeTime)
+		s.Equal(tc.parsedQuery.CloseTime, parsedQuery.CloseTime)
 	}
 }
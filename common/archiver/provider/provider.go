@@ -33,6 +33,7 @@ import (
 	"go.temporal.io/server/common/archiver/gcloud"
 
 	"go.temporal.io/server/common/archiver"
+	"go.temporal.io/server/common/archiver/azblob"
 	"go.temporal.io/server/common/archiver/filestore"
 	"go.temporal.io/server/common/archiver/s3store"
 	"go.temporal.io/server/common/config"
@@ -69,6 +70,7 @@ type (
 
 		historyArchiverConfigs    *config.HistoryArchiverProvider
 		visibilityArchiverConfigs *config.VisibilityArchiverProvider
+		historyCompression        *config.HistoryArchiveCompression
 
 		// Key for the container is just serviceName
 		historyContainers    map[string]*archiver.HistoryBootstrapContainer
@@ -84,10 +86,12 @@ type (
 func NewArchiverProvider(
 	historyArchiverConfigs *config.HistoryArchiverProvider,
 	visibilityArchiverConfigs *config.VisibilityArchiverProvider,
+	historyCompression *config.HistoryArchiveCompression,
 ) ArchiverProvider {
 	return &archiverProvider{
 		historyArchiverConfigs:    historyArchiverConfigs,
 		visibilityArchiverConfigs: visibilityArchiverConfigs,
+		historyCompression:        historyCompression,
 		historyContainers:         make(map[string]*archiver.HistoryBootstrapContainer),
 		visibilityContainers:      make(map[string]*archiver.VisibilityBootstrapContainer),
 		historyArchivers:          make(map[string]archiver.HistoryArchiver),
@@ -143,7 +147,7 @@ func (p *archiverProvider) GetHistoryArchiver(scheme, serviceName string) (histo
 		if p.historyArchiverConfigs.Filestore == nil {
 			return nil, ErrArchiverConfigNotFound
 		}
-		historyArchiver, err = filestore.NewHistoryArchiver(container, p.historyArchiverConfigs.Filestore)
+		historyArchiver, err = filestore.NewHistoryArchiver(container, p.historyArchiverConfigs.Filestore, p.historyCompression)
 
 	case gcloud.URIScheme:
 		if p.historyArchiverConfigs.Gstorage == nil {
@@ -156,7 +160,12 @@ func (p *archiverProvider) GetHistoryArchiver(scheme, serviceName string) (histo
 		if p.historyArchiverConfigs.S3store == nil {
 			return nil, ErrArchiverConfigNotFound
 		}
-		historyArchiver, err = s3store.NewHistoryArchiver(container, p.historyArchiverConfigs.S3store)
+		historyArchiver, err = s3store.NewHistoryArchiver(container, p.historyArchiverConfigs.S3store, p.historyCompression)
+	case azblob.URIScheme:
+		if p.historyArchiverConfigs.Azblob == nil {
+			return nil, ErrArchiverConfigNotFound
+		}
+		historyArchiver, err = azblob.NewHistoryArchiver(container, p.historyArchiverConfigs.Azblob)
 	default:
 		return nil, ErrUnknownScheme
 	}
@@ -207,6 +216,11 @@ func (p *archiverProvider) GetVisibilityArchiver(scheme, serviceName string) (ar
 			return nil, ErrArchiverConfigNotFound
 		}
 		visibilityArchiver, err = gcloud.NewVisibilityArchiver(container, p.visibilityArchiverConfigs.Gstorage)
+	case azblob.URIScheme:
+		if p.visibilityArchiverConfigs.Azblob == nil {
+			return nil, ErrArchiverConfigNotFound
+		}
+		visibilityArchiver, err = azblob.NewVisibilityArchiver(container, p.visibilityArchiverConfigs.Azblob)
 
 	default:
 		return nil, ErrUnknownScheme
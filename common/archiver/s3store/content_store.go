@@ -0,0 +1,65 @@
+// The MIT License
+//
+// Copyright (c) 2020 Temporal Technologies Inc.  All rights reserved.
+//
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package s3store
+
+import (
+	"context"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/service/s3/s3iface"
+
+	"go.temporal.io/server/common/archiver"
+)
+
+type (
+	// contentStore stores deduplicated payloads of a namespace under <path>/<namespaceID>/payloads/<yyyy-mm>/<hash>
+	contentStore struct {
+		s3cli     s3iface.S3API
+		URI       archiver.URI
+		keyPrefix string
+	}
+)
+
+var _ archiver.ContentStore = (*contentStore)(nil)
+
+func newContentStore(s3cli s3iface.S3API, URI archiver.URI, namespaceID string) *contentStore {
+	return &contentStore{
+		s3cli:     s3cli,
+		URI:       URI,
+		keyPrefix: strings.TrimLeft(strings.Join([]string{URI.Path(), namespaceID, "payloads"}, "/"), "/") + "/",
+	}
+}
+
+func (s *contentStore) Exists(ctx context.Context, key string) (bool, error) {
+	return KeyExists(ctx, s.s3cli, s.URI, s.keyPrefix+key)
+}
+
+func (s *contentStore) Put(ctx context.Context, key string, data []byte) error {
+	return Upload(ctx, s.s3cli, s.URI, s.keyPrefix+key, data)
+}
+
+func (s *contentStore) Get(ctx context.Context, key string) ([]byte, error) {
+	return Download(ctx, s.s3cli, s.URI, s.keyPrefix+key)
+}
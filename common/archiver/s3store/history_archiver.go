@@ -40,6 +40,7 @@ import (
 	"github.com/aws/aws-sdk-go/aws/session"
 	"github.com/aws/aws-sdk-go/service/s3"
 	"github.com/aws/aws-sdk-go/service/s3/s3iface"
+	historypb "go.temporal.io/api/history/v1"
 	"go.temporal.io/api/serviceerror"
 
 	archiverspb "go.temporal.io/server/api/archiver/v1"
@@ -72,6 +73,7 @@ type (
 	historyArchiver struct {
 		container *archiver.HistoryBootstrapContainer
 		s3cli     s3iface.S3API
+		blobCodec *archiver.HistoryBlobCodec
 		// only set in test code
 		historyIterator archiver.HistoryIterator
 	}
@@ -93,13 +95,15 @@ type (
 func NewHistoryArchiver(
 	container *archiver.HistoryBootstrapContainer,
 	config *config.S3Archiver,
+	compression *config.HistoryArchiveCompression,
 ) (archiver.HistoryArchiver, error) {
-	return newHistoryArchiver(container, config, nil)
+	return newHistoryArchiver(container, config, compression, nil)
 }
 
 func newHistoryArchiver(
 	container *archiver.HistoryBootstrapContainer,
 	config *config.S3Archiver,
+	compression *config.HistoryArchiveCompression,
 	historyIterator archiver.HistoryIterator,
 ) (*historyArchiver, error) {
 	if len(config.Region) == 0 {
@@ -115,10 +119,15 @@ func newHistoryArchiver(
 	if err != nil {
 		return nil, err
 	}
+	blobCodec, err := archiver.NewHistoryBlobCodec(compression)
+	if err != nil {
+		return nil, err
+	}
 
 	return &historyArchiver{
 		container:       container,
 		s3cli:           s3.New(sess),
+		blobCodec:       blobCodec,
 		historyIterator: historyIterator,
 	}, nil
 }
@@ -157,6 +166,7 @@ func (h *historyArchiver) Archive(
 		return err
 	}
 
+	contentStore := newContentStore(h.s3cli, URI, request.NamespaceID)
 	var progress uploadProgress
 	historyIterator := h.historyIterator
 	if historyIterator == nil { // will only be set by testing code
@@ -189,7 +199,9 @@ func (h *historyArchiver) Archive(
 		}
 
 		encoder := codec.NewJSONPBEncoder()
-		encodedHistoryBlob, err := encoder.Encode(historyBlob)
+		encodedHistoryBlob, err := h.blobCodec.EncodeHistory(ctx, contentStore, historyBlob.Body, func(body []*historypb.History) ([]byte, error) {
+			return encoder.Encode(&archiverspb.HistoryBlob{Header: historyBlob.Header, Body: body})
+		})
 		if err != nil {
 			logger.Error(archiver.ArchiveNonRetryableErrorMsg, tag.ArchivalArchiveFailReason(errEncodeHistory), tag.Error(err))
 			return err
@@ -303,6 +315,7 @@ func (h *historyArchiver) Get(
 		}
 	}
 	encoder := codec.NewJSONPBEncoder()
+	contentStore := newContentStore(h.s3cli, URI, request.NamespaceID)
 	response := &archiver.GetHistoryResponse{}
 	numOfEvents := 0
 	isTruncated := false
@@ -313,17 +326,14 @@ func (h *historyArchiver) Get(
 		}
 		key := constructHistoryKey(URI.Path(), request.NamespaceID, request.WorkflowID, request.RunID, token.CloseFailoverVersion, token.BatchIdx)
 
-		encodedRecord, err := Download(ctx, h.s3cli, URI, key)
+		blob, err := Download(ctx, h.s3cli, URI, key)
 		if err != nil {
-			if isRetryableError(err) {
-				return nil, serviceerror.NewUnavailable(err.Error())
-			}
-			switch err.(type) {
-			case *serviceerror.InvalidArgument, *serviceerror.Unavailable, *serviceerror.NotFound:
-				return nil, err
-			default:
-				return nil, serviceerror.NewInternal(err.Error())
-			}
+			return nil, convertReadError(err)
+		}
+
+		manifest, encodedRecord, err := archiver.DecodeHistoryBlob(blob)
+		if err != nil {
+			return nil, serviceerror.NewInternal(err.Error())
 		}
 
 		historyBlob := archiverspb.HistoryBlob{}
@@ -331,6 +341,9 @@ func (h *historyArchiver) Get(
 		if err != nil {
 			return nil, serviceerror.NewInternal(err.Error())
 		}
+		if err := archiver.ResolveHistoryPayloads(ctx, contentStore, manifest, historyBlob.Body); err != nil {
+			return nil, convertReadError(err)
+		}
 
 		for _, batch := range historyBlob.Body {
 			response.HistoryBatches = append(response.HistoryBatches, batch)
@@ -395,6 +408,18 @@ func (h *historyArchiver) getHighestVersion(ctx context.Context, URI archiver.UR
 	return highestVersion, nil
 }
 
+func convertReadError(err error) error {
+	if isRetryableError(err) {
+		return serviceerror.NewUnavailable(err.Error())
+	}
+	switch err.(type) {
+	case *serviceerror.InvalidArgument, *serviceerror.Unavailable, *serviceerror.NotFound:
+		return err
+	default:
+		return serviceerror.NewInternal(err.Error())
+	}
+}
+
 func isRetryableError(err error) bool {
 	if err == nil {
 		return false
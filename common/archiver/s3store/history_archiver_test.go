@@ -46,6 +46,7 @@ import (
 	enumspb "go.temporal.io/api/enums/v1"
 	historypb "go.temporal.io/api/history/v1"
 	"go.temporal.io/api/serviceerror"
+	"google.golang.org/protobuf/proto"
 	"google.golang.org/protobuf/types/known/timestamppb"
 
 	archiverspb "go.temporal.io/server/api/archiver/v1"
@@ -53,9 +54,11 @@ import (
 	"go.temporal.io/server/common/archiver"
 	"go.temporal.io/server/common/archiver/s3store/mocks"
 	"go.temporal.io/server/common/codec"
+	"go.temporal.io/server/common/config"
 	"go.temporal.io/server/common/convert"
 	"go.temporal.io/server/common/log"
 	"go.temporal.io/server/common/metrics"
+	"go.temporal.io/server/common/payloads"
 )
 
 const (
@@ -687,12 +690,84 @@ func (s *historyArchiverSuite) TestArchiveAndGet() {
 	s.Equal(append(s.historyBatchesV100[0].Body, s.historyBatchesV100[1].Body...), response.HistoryBatches)
 }
 
+func (s *historyArchiverSuite) TestArchiveAndGet_CompressedAndDeduplicated() {
+	largeInput := payloads.EncodeBytes(bytes.Repeat([]byte{1}, 4096))
+	firstBlob := proto.Clone(s.historyBatchesV100[0]).(*archiverspb.HistoryBlob)
+	firstBlob.Body[0].Events[0].Attributes = &historypb.HistoryEvent_WorkflowExecutionStartedEventAttributes{
+		WorkflowExecutionStartedEventAttributes: &historypb.WorkflowExecutionStartedEventAttributes{
+			Input: largeInput,
+		},
+	}
+	historyIterator := archiver.NewMockHistoryIterator(s.controller)
+	gomock.InOrder(
+		historyIterator.EXPECT().HasNext().Return(true),
+		historyIterator.EXPECT().Next(gomock.Any()).Return(firstBlob, nil),
+		historyIterator.EXPECT().HasNext().Return(true),
+		historyIterator.EXPECT().Next(gomock.Any()).Return(s.historyBatchesV100[1], nil),
+		historyIterator.EXPECT().HasNext().Return(false),
+	)
+
+	historyArchiver := s.newTestHistoryArchiver(historyIterator)
+	var err error
+	historyArchiver.blobCodec, err = archiver.NewHistoryBlobCodec(&config.HistoryArchiveCompression{
+		Codec:          archiver.CompressionZstd,
+		DedupThreshold: 1024,
+	})
+	s.NoError(err)
+	archiveRequest := &archiver.ArchiveHistoryRequest{
+		NamespaceID:          testNamespaceID,
+		Namespace:            testNamespace,
+		WorkflowID:           testWorkflowID,
+		RunID:                testRunID,
+		BranchToken:          testBranchToken,
+		NextEventID:          testNextEventID,
+		CloseFailoverVersion: testCloseFailoverVersion,
+	}
+	URI, err := archiver.NewURI(testBucketURI + "/TestArchiveAndGet_CompressedAndDeduplicated")
+	s.NoError(err)
+	err = historyArchiver.Archive(context.Background(), URI, archiveRequest)
+	s.NoError(err)
+
+	payloadKey := strings.Join([]string{"/TestArchiveAndGet_CompressedAndDeduplicated", testNamespaceID, "payloads", ""}, "/")
+	results, err := s.s3cli.ListObjectsV2WithContext(context.Background(), &s3.ListObjectsV2Input{
+		Bucket: aws.String(testBucket),
+		Prefix: aws.String(strings.TrimLeft(payloadKey, "/")),
+	})
+	s.NoError(err)
+	s.Len(results.Contents, 1)
+
+	getRequest := &archiver.GetHistoryRequest{
+		NamespaceID: testNamespaceID,
+		WorkflowID:  testWorkflowID,
+		RunID:       testRunID,
+		PageSize:    1,
+	}
+	var combinedHistory []*historypb.History
+	for {
+		response, err := s.newTestHistoryArchiver(nil).Get(context.Background(), URI, getRequest)
+		s.NoError(err)
+		combinedHistory = append(combinedHistory, response.HistoryBatches...)
+		if response.NextPageToken == nil {
+			break
+		}
+		getRequest.NextPageToken = response.NextPageToken
+	}
+	expected := append(firstBlob.Body, s.historyBatchesV100[1].Body...)
+	s.Len(combinedHistory, len(expected))
+	for i := range expected {
+		s.True(proto.Equal(expected[i], combinedHistory[i]))
+	}
+}
+
 func (s *historyArchiverSuite) newTestHistoryArchiver(historyIterator archiver.HistoryIterator) *historyArchiver {
 	// config := &config.S3Archiver{}
 	// archiver, err := newHistoryArchiver(s.container, config, historyIterator)
+	blobCodec, err := archiver.NewHistoryBlobCodec(nil)
+	s.Require().NoError(err)
 	archiver := &historyArchiver{
 		container:       s.container,
 		s3cli:           s.s3cli,
+		blobCodec:       blobCodec,
 		historyIterator: historyIterator,
 	}
 	return archiver
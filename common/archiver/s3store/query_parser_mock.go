@@ -32,6 +32,8 @@ import (
 	reflect "reflect"
 
 	gomock "github.com/golang/mock/gomock"
+	archiver "go.temporal.io/server/common/archiver"
+	searchattribute "go.temporal.io/server/common/searchattribute"
 )
 
 // MockQueryParser is a mock of QueryParser interface.
@@ -58,16 +60,16 @@ func (m *MockQueryParser) EXPECT() *MockQueryParserMockRecorder {
 }
 
 // Parse mocks base method.
-func (m *MockQueryParser) Parse(query string) (*parsedQuery, error) {
+func (m *MockQueryParser) Parse(query string, saTypeMap searchattribute.NameTypeMap) (*archiver.IndexedVisibilityQuery, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Parse", query)
-	ret0, _ := ret[0].(*parsedQuery)
+	ret := m.ctrl.Call(m, "Parse", query, saTypeMap)
+	ret0, _ := ret[0].(*archiver.IndexedVisibilityQuery)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // Parse indicates an expected call of Parse.
-func (mr *MockQueryParserMockRecorder) Parse(query interface{}) *gomock.Call {
+func (mr *MockQueryParserMockRecorder) Parse(query, saTypeMap interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Parse", reflect.TypeOf((*MockQueryParser)(nil).Parse), query)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Parse", reflect.TypeOf((*MockQueryParser)(nil).Parse), query, saTypeMap)
 }
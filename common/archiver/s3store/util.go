@@ -88,6 +88,12 @@ func serializeQueryVisibilityToken(token string) []byte {
 	return []byte(token)
 }
 
+func deserializeOrderedQueryVisibilityToken(bytes []byte) (*orderedQueryVisibilityToken, error) {
+	token := &orderedQueryVisibilityToken{}
+	err := json.Unmarshal(bytes, token)
+	return token, err
+}
+
 // Only validates the scheme and buckets are passed
 func SoftValidateURI(URI archiver.URI) error {
 	if URI.Scheme() != URIScheme {
@@ -153,16 +159,16 @@ func constructHistoryKeyPrefix(path, namespaceID, workflowID, runID string) stri
 func constructTimeBasedSearchKey(path, namespaceID, primaryIndexKey, primaryIndexValue, secondaryIndexKey string, t time.Time, precision string) string {
 	var timeFormat = ""
 	switch precision {
-	case PrecisionSecond:
+	case archiver.PrecisionSecond:
 		timeFormat = ":05"
 		fallthrough
-	case PrecisionMinute:
+	case archiver.PrecisionMinute:
 		timeFormat = ":04" + timeFormat
 		fallthrough
-	case PrecisionHour:
+	case archiver.PrecisionHour:
 		timeFormat = "15" + timeFormat
 		fallthrough
-	case PrecisionDay:
+	case archiver.PrecisionDay:
 		timeFormat = "2006-01-02T" + timeFormat
 	}
 
@@ -26,6 +26,7 @@ package s3store
 
 import (
 	"context"
+	"fmt"
 	"strings"
 	"time"
 
@@ -57,7 +58,11 @@ type (
 		namespaceID   string
 		pageSize      int
 		nextPageToken []byte
-		parsedQuery   *parsedQuery
+		parsedQuery   *archiver.IndexedVisibilityQuery
+	}
+
+	orderedQueryVisibilityToken struct {
+		Offset int
 	}
 
 	indexToArchive struct {
@@ -76,6 +81,14 @@ const (
 	primaryIndexKeyWorkflowID       = "workflowID"
 )
 
+var (
+	// orderedQueryListPageSize is the number of keys listed per call when reading all records of an ordered query.
+	orderedQueryListPageSize = 1000
+	// orderedQueryMaxKeys is the maximum number of keys an ordered query reads. Every page of an ordered query
+	// reads all of its keys, so queries which list more keys are rejected.
+	orderedQueryMaxKeys = 10 * orderedQueryListPageSize
+)
+
 // NewVisibilityArchiver creates a new archiver.VisibilityArchiver based on s3
 func NewVisibilityArchiver(
 	container *archiver.VisibilityBootstrapContainer,
@@ -183,79 +196,74 @@ func (v *visibilityArchiver) Query(
 		return nil, serviceerror.NewInvalidArgument(archiver.ErrInvalidQueryVisibilityRequest.Error())
 	}
 
-	if strings.TrimSpace(request.Query) == "" {
-		return v.queryAll(ctx, URI, request, saTypeMap)
+	parsedQuery := &archiver.IndexedVisibilityQuery{}
+	if strings.TrimSpace(request.Query) != "" {
+		var err error
+		parsedQuery, err = v.queryParser.Parse(request.Query, saTypeMap)
+		if err != nil {
+			return nil, serviceerror.NewInvalidArgument(err.Error())
+		}
 	}
 
-	parsedQuery, err := v.queryParser.Parse(request.Query)
-	if err != nil {
-		return nil, serviceerror.NewInvalidArgument(err.Error())
+	if parsedQuery.EmptyResult {
+		return &archiver.QueryVisibilityResponse{}, nil
 	}
 
-	return v.query(
-		ctx,
-		URI,
-		&queryVisibilityRequest{
-			namespaceID:   request.NamespaceID,
-			pageSize:      request.PageSize,
-			nextPageToken: request.NextPageToken,
-			parsedQuery:   parsedQuery,
-		},
-		saTypeMap,
-	)
+	queryRequest := &queryVisibilityRequest{
+		namespaceID:   request.NamespaceID,
+		pageSize:      request.PageSize,
+		nextPageToken: request.NextPageToken,
+		parsedQuery:   parsedQuery,
+	}
+	if parsedQuery.Query != nil && parsedQuery.Query.HasOrderBy() {
+		return v.queryOrdered(ctx, URI, queryRequest, saTypeMap)
+	}
+	return v.query(ctx, URI, queryRequest, saTypeMap)
 }
 
-// queryAll returns all workflow executions in the archive.
-func (v *visibilityArchiver) queryAll(
+func (v *visibilityArchiver) query(
 	ctx context.Context,
 	uri archiver.URI,
-	request *archiver.QueryVisibilityRequest,
+	request *queryVisibilityRequest,
 	saTypeMap searchattribute.NameTypeMap,
 ) (*archiver.QueryVisibilityResponse, error) {
+	prefix, keyFilter := constructQuerySearchPrefix(uri, request)
 	// remaining is the number of workflow executions left to return before we reach pageSize.
-	remaining := request.PageSize
-	nextPageToken := request.NextPageToken
+	remaining := request.pageSize
+	nextPageToken := request.nextPageToken
 	var executions []*workflowpb.WorkflowExecutionInfo
-	// We need to loop because the number of workflow executions returned by each call to query may be fewer than
-	// pageSize. This is because we may have to skip some workflow executions after querying S3 (client-side filtering)
-	// because there are 2 entries in S3 for each workflow execution indexed by workflowTypeName (one for closeTimeout
-	// and one for startTimeout), and we only want to return one entry per workflow execution. See
-	// createIndexesToArchive for a list of all indexes.
+	// We need to loop because the number of workflow executions returned by each call to queryPrefix may be fewer
+	// than pageSize. This is because we may have to skip some workflow executions after querying S3 (client-side
+	// filtering), either because they don't match the query or because there is more than one entry in S3 for
+	// them under the prefix we list. See createIndexesToArchive for a list of all indexes.
 	for {
-		searchPrefix := constructVisibilitySearchPrefix(uri.Path(), request.NamespaceID)
-		// We suffix searchPrefix with workflowTypeName because the data in S3 is duplicated across combinations of 2
-		// different primary indices (workflowID and workflowTypeName) and 2 different secondary indices (closeTimeout
-		// and startTimeout). We only want to return one entry per workflow execution, but the full path to the S3 key
-		// is <primaryIndexKey>/<primaryIndexValue>/<secondaryIndexKey>/<secondaryIndexValue>/<runID>, and we don't have
-		// the primaryIndexValue when we make the call to query, so we can only specify the primaryIndexKey.
-		searchPrefix += "/" + primaryIndexKeyWorkflowTypeName
-		// The pageSize we supply here is actually the maximum number of keys to fetch from S3. For each execution,
-		// there should be 2 keys in S3 for this prefix, so you might think that we should multiply the pageSize by 2.
-		// However, if we do that, we may end up returning more than pageSize workflow executions to the end user of
-		// this API. This is because we aren't guaranteed that both keys for a given workflow execution will be returned
-		// in the same call. For example, if the user supplies a pageSize of 1, and we specify a maximum number of keys
-		// of 2 to S3, we may get back entries from S3 for 2 different workflow executions. You might think that we can
-		// just truncate this result to 1 workflow execution, but then the nextPageToken would be incorrect. So, we may
-		// need to make multiple calls to S3 to get the correct number of workflow executions, which will probably make
-		// this API call slower.
-		res, err := v.queryPrefix(ctx, uri, &queryVisibilityRequest{
-			namespaceID:   request.NamespaceID,
+		// The pageSize we supply here is actually the maximum number of keys to fetch from S3. When listing all
+		// workflow executions, there are 2 keys in S3 for each execution under the prefix, so you might think that
+		// we should multiply the pageSize by 2. However, if we do that, we may end up returning more than pageSize
+		// workflow executions to the end user of this API. This is because we aren't guaranteed that both keys for
+		// a given workflow execution will be returned in the same call. For example, if the user supplies a pageSize
+		// of 1, and we specify a maximum number of keys of 2 to S3, we may get back entries from S3 for 2 different
+		// workflow executions. You might think that we can just truncate this result to 1 workflow execution, but
+		// then the nextPageToken would be incorrect. So, we may need to make multiple calls to S3 to get the correct
+		// number of workflow executions, which will probably make this API call slower.
+		records, token, err := v.queryPrefix(ctx, uri, &queryVisibilityRequest{
+			namespaceID:   request.namespaceID,
 			pageSize:      remaining,
 			nextPageToken: nextPageToken,
-			parsedQuery:   &parsedQuery{},
-		}, saTypeMap, searchPrefix, func(key string) bool {
-			// We only want to return entries for the closeTimeout secondary index, which will always be of the form:
-			// .../closeTimeout/<closeTimeout>/<runID>, so we split the key on "/" and check that the third-to-last
-			// element is "closeTimeout".
-			elements := strings.Split(key, "/")
-			return len(elements) >= 3 && elements[len(elements)-3] == secondaryIndexKeyCloseTimeout
-		})
+			parsedQuery:   request.parsedQuery,
+		}, prefix, keyFilter)
 		if err != nil {
 			return nil, err
 		}
-		nextPageToken = res.NextPageToken
-		executions = append(executions, res.Executions...)
-		remaining -= len(res.Executions)
+		for _, record := range records {
+			executionInfo, err := convertToExecutionInfo(record, saTypeMap)
+			if err != nil {
+				return nil, serviceerror.NewInternal(err.Error())
+			}
+			executions = append(executions, executionInfo)
+		}
+		nextPageToken = token
+		remaining -= len(records)
 		if len(nextPageToken) == 0 || remaining <= 0 {
 			break
 		}
@@ -266,63 +274,141 @@ func (v *visibilityArchiver) queryAll(
 	}, nil
 }
 
-func (v *visibilityArchiver) query(
+// queryOrdered handles queries with an order by clause. Keys are listed in the order of the secondary index,
+// so all matching records have to be read and sorted before the requested page can be returned.
+func (v *visibilityArchiver) queryOrdered(
 	ctx context.Context,
-	URI archiver.URI,
+	uri archiver.URI,
 	request *queryVisibilityRequest,
 	saTypeMap searchattribute.NameTypeMap,
 ) (*archiver.QueryVisibilityResponse, error) {
-	primaryIndex := primaryIndexKeyWorkflowTypeName
-	primaryIndexValue := request.parsedQuery.workflowTypeName
-	if request.parsedQuery.workflowID != nil {
+	offset := 0
+	if request.nextPageToken != nil {
+		token, err := deserializeOrderedQueryVisibilityToken(request.nextPageToken)
+		if err != nil {
+			return nil, serviceerror.NewInvalidArgument(archiver.ErrNextPageTokenCorrupted.Error())
+		}
+		offset = token.Offset
+	}
+
+	prefix, keyFilter := constructQuerySearchPrefix(uri, request)
+	var records []*archiverspb.VisibilityRecord
+	var nextPageToken []byte
+	for listedKeys := orderedQueryListPageSize; ; listedKeys += orderedQueryListPageSize {
+		pageRecords, token, err := v.queryPrefix(ctx, uri, &queryVisibilityRequest{
+			namespaceID:   request.namespaceID,
+			pageSize:      orderedQueryListPageSize,
+			nextPageToken: nextPageToken,
+			parsedQuery:   request.parsedQuery,
+		}, prefix, keyFilter)
+		if err != nil {
+			return nil, err
+		}
+		records = append(records, pageRecords...)
+		if len(token) == 0 {
+			break
+		}
+		if listedKeys >= orderedQueryMaxKeys {
+			return nil, serviceerror.NewInvalidArgument(fmt.Sprintf(
+				"queries with order by can read at most %d records, narrow down the query with WorkflowId, WorkflowType or StartTime or CloseTime and SearchPrecision",
+				orderedQueryMaxKeys,
+			))
+		}
+		nextPageToken = token
+	}
+	request.parsedQuery.Query.SortRecords(records)
+
+	response := &archiver.QueryVisibilityResponse{}
+	if offset >= len(records) {
+		return response, nil
+	}
+	end := min(offset+request.pageSize, len(records))
+	for _, record := range records[offset:end] {
+		executionInfo, err := convertToExecutionInfo(record, saTypeMap)
+		if err != nil {
+			return nil, serviceerror.NewInternal(err.Error())
+		}
+		response.Executions = append(response.Executions, executionInfo)
+	}
+	if end < len(records) {
+		encodedToken, err := SerializeToken(&orderedQueryVisibilityToken{Offset: end})
+		if err != nil {
+			return nil, serviceerror.NewInternal(err.Error())
+		}
+		response.NextPageToken = encodedToken
+	}
+	return response, nil
+}
+
+// constructQuerySearchPrefix returns the prefix of the keys to list for the query and an optional key filter.
+func constructQuerySearchPrefix(uri archiver.URI, request *queryVisibilityRequest) (string, func(key string) bool) {
+	var primaryIndex string
+	var primaryIndexValue string
+	switch {
+	case request.parsedQuery.WorkflowID != nil:
 		primaryIndex = primaryIndexKeyWorkflowID
-		primaryIndexValue = request.parsedQuery.workflowID
+		primaryIndexValue = *request.parsedQuery.WorkflowID
+	case request.parsedQuery.WorkflowTypeName != nil:
+		primaryIndex = primaryIndexKeyWorkflowTypeName
+		primaryIndexValue = *request.parsedQuery.WorkflowTypeName
+	default:
+		// We suffix searchPrefix with workflowTypeName because the data in S3 is duplicated across combinations of 2
+		// different primary indices (workflowID and workflowTypeName) and 2 different secondary indices (closeTimeout
+		// and startTimeout). We only want to return one entry per workflow execution, but the full path to the S3 key
+		// is <primaryIndexKey>/<primaryIndexValue>/<secondaryIndexKey>/<secondaryIndexValue>/<runID>, and we don't have
+		// the primaryIndexValue when we make the call to query, so we can only specify the primaryIndexKey.
+		searchPrefix := constructVisibilitySearchPrefix(uri.Path(), request.namespaceID) + "/" + primaryIndexKeyWorkflowTypeName
+		return searchPrefix, func(key string) bool {
+			// We only want to return entries for the closeTimeout secondary index, which will always be of the form:
+			// .../closeTimeout/<closeTimeout>/<runID>, so we split the key on "/" and check that the third-to-last
+			// element is "closeTimeout".
+			elements := strings.Split(key, "/")
+			return len(elements) >= 3 && elements[len(elements)-3] == secondaryIndexKeyCloseTimeout
+		}
 	}
 
-	prefix := constructIndexedVisibilitySearchPrefix(
-		URI.Path(),
-		request.namespaceID,
-		primaryIndex,
-		*primaryIndexValue,
-		secondaryIndexKeyCloseTimeout,
-	) + "/"
-	if request.parsedQuery.closeTime != nil {
-		prefix = constructTimeBasedSearchKey(
-			URI.Path(),
+	switch {
+	case request.parsedQuery.CloseTime != nil:
+		return constructTimeBasedSearchKey(
+			uri.Path(),
 			request.namespaceID,
 			primaryIndex,
-			*primaryIndexValue,
+			primaryIndexValue,
 			secondaryIndexKeyCloseTimeout,
-			*request.parsedQuery.closeTime,
-			*request.parsedQuery.searchPrecision,
-		)
-	}
-	if request.parsedQuery.startTime != nil {
-		prefix = constructTimeBasedSearchKey(
-			URI.Path(),
+			*request.parsedQuery.CloseTime,
+			*request.parsedQuery.SearchPrecision,
+		), nil
+	case request.parsedQuery.StartTime != nil:
+		return constructTimeBasedSearchKey(
+			uri.Path(),
 			request.namespaceID,
 			primaryIndex,
-			*primaryIndexValue,
+			primaryIndexValue,
 			secondaryIndexKeyStartTimeout,
-			*request.parsedQuery.startTime,
-			*request.parsedQuery.searchPrecision,
-		)
+			*request.parsedQuery.StartTime,
+			*request.parsedQuery.SearchPrecision,
+		), nil
+	default:
+		return constructIndexedVisibilitySearchPrefix(
+			uri.Path(),
+			request.namespaceID,
+			primaryIndex,
+			primaryIndexValue,
+			secondaryIndexKeyCloseTimeout,
+		) + "/", nil
 	}
-
-	return v.queryPrefix(ctx, URI, request, saTypeMap, prefix, nil)
 }
 
-// queryPrefix returns all workflow executions in the archive that match the given prefix. The keyFilter function is an
-// optional filter that can be used to further filter the results. If keyFilter returns false for a given key, that key
-// will be skipped, and the object will not be downloaded from S3 or included in the results.
+// queryPrefix returns the visibility records in the archive that match the given prefix and the query. The keyFilter
+// function is an optional filter that can be used to further filter the results. If keyFilter returns false for a
+// given key, that key will be skipped, and the object will not be downloaded from S3 or included in the results.
 func (v *visibilityArchiver) queryPrefix(
 	ctx context.Context,
 	uri archiver.URI,
 	request *queryVisibilityRequest,
-	saTypeMap searchattribute.NameTypeMap,
 	prefix string,
 	keyFilter func(key string) bool,
-) (*archiver.QueryVisibilityResponse, error) {
+) ([]*archiverspb.VisibilityRecord, []byte, error) {
 	ctx, cancel := ensureContextTimeout(ctx)
 	defer cancel()
 
@@ -339,18 +425,19 @@ func (v *visibilityArchiver) queryPrefix(
 	})
 	if err != nil {
 		if isRetryableError(err) {
-			return nil, serviceerror.NewUnavailable(err.Error())
+			return nil, nil, serviceerror.NewUnavailable(err.Error())
 		}
-		return nil, serviceerror.NewInvalidArgument(err.Error())
+		return nil, nil, serviceerror.NewInvalidArgument(err.Error())
 	}
 	if len(results.Contents) == 0 {
-		return &archiver.QueryVisibilityResponse{}, nil
+		return nil, nil, nil
 	}
 
-	response := &archiver.QueryVisibilityResponse{}
+	var nextPageToken []byte
 	if *results.IsTruncated {
-		response.NextPageToken = serializeQueryVisibilityToken(*results.NextContinuationToken)
+		nextPageToken = serializeQueryVisibilityToken(*results.NextContinuationToken)
 	}
+	var records []*archiverspb.VisibilityRecord
 	for _, item := range results.Contents {
 		if keyFilter != nil && !keyFilter(*item.Key) {
 			continue
@@ -358,20 +445,19 @@ func (v *visibilityArchiver) queryPrefix(
 
 		encodedRecord, err := Download(ctx, v.s3cli, uri, *item.Key)
 		if err != nil {
-			return nil, serviceerror.NewUnavailable(err.Error())
+			return nil, nil, serviceerror.NewUnavailable(err.Error())
 		}
 
 		record, err := decodeVisibilityRecord(encodedRecord)
 		if err != nil {
-			return nil, serviceerror.NewInternal(err.Error())
+			return nil, nil, serviceerror.NewInternal(err.Error())
 		}
-		executionInfo, err := convertToExecutionInfo(record, saTypeMap)
-		if err != nil {
-			return nil, serviceerror.NewInternal(err.Error())
+		if request.parsedQuery.Query != nil && !request.parsedQuery.Query.Match(record) {
+			continue
 		}
-		response.Executions = append(response.Executions, executionInfo)
+		records = append(records, record)
 	}
-	return response, nil
+	return records, nextPageToken, nil
 }
 
 func (v *visibilityArchiver) ValidateURI(URI archiver.URI) error {
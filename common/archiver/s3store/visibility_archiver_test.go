@@ -118,6 +118,10 @@ func (s *visibilityArchiverSuite) TestValidateURI() {
 	}
 }
 
+func ptr[T any](v T) *T {
+	return &v
+}
+
 func (s *visibilityArchiverSuite) newTestVisibilityArchiver() *visibilityArchiver {
 	return &visibilityArchiver{
 		container:   s.container,
@@ -259,7 +263,7 @@ func (s *visibilityArchiverSuite) TestQuery_Fail_InvalidRequest() {
 func (s *visibilityArchiverSuite) TestQuery_Fail_InvalidQuery() {
 	visibilityArchiver := s.newTestVisibilityArchiver()
 	mockParser := NewMockQueryParser(s.controller)
-	mockParser.EXPECT().Parse(gomock.Any()).Return(nil, errors.New("invalid query"))
+	mockParser.EXPECT().Parse(gomock.Any(), gomock.Any()).Return(nil, errors.New("invalid query"))
 	visibilityArchiver.queryParser = mockParser
 	response, err := visibilityArchiver.Query(context.Background(), s.testArchivalURI, &archiver.QueryVisibilityRequest{
 		NamespaceID: "some random namespaceID",
@@ -273,10 +277,10 @@ func (s *visibilityArchiverSuite) TestQuery_Fail_InvalidQuery() {
 func (s *visibilityArchiverSuite) TestQuery_Success_DirectoryNotExist() {
 	visibilityArchiver := s.newTestVisibilityArchiver()
 	mockParser := NewMockQueryParser(s.controller)
-	mockParser.EXPECT().Parse(gomock.Any()).Return(&parsedQuery{
-		workflowID:      convert.StringPtr(testWorkflowID),
-		closeTime:       &time.Time{},
-		searchPrecision: convert.StringPtr(PrecisionSecond),
+	mockParser.EXPECT().Parse(gomock.Any(), gomock.Any()).Return(&archiver.IndexedVisibilityQuery{
+		WorkflowID:      convert.StringPtr(testWorkflowID),
+		CloseTime:       &time.Time{},
+		SearchPrecision: convert.StringPtr(archiver.PrecisionSecond),
 	}, nil)
 	visibilityArchiver.queryParser = mockParser
 	request := &archiver.QueryVisibilityRequest{
@@ -294,10 +298,10 @@ func (s *visibilityArchiverSuite) TestQuery_Success_DirectoryNotExist() {
 func (s *visibilityArchiverSuite) TestQuery_Success_NoNextPageToken() {
 	visibilityArchiver := s.newTestVisibilityArchiver()
 	mockParser := NewMockQueryParser(s.controller)
-	mockParser.EXPECT().Parse(gomock.Any()).Return(&parsedQuery{
-		closeTime:       ptr(time.Unix(0, int64(1*time.Hour)).UTC()),
-		searchPrecision: convert.StringPtr(PrecisionHour),
-		workflowID:      convert.StringPtr(testWorkflowID),
+	mockParser.EXPECT().Parse(gomock.Any(), gomock.Any()).Return(&archiver.IndexedVisibilityQuery{
+		CloseTime:       ptr(time.Unix(0, int64(1*time.Hour)).UTC()),
+		SearchPrecision: convert.StringPtr(archiver.PrecisionHour),
+		WorkflowID:      convert.StringPtr(testWorkflowID),
 	}, nil)
 	visibilityArchiver.queryParser = mockParser
 	request := &archiver.QueryVisibilityRequest{
@@ -320,10 +324,10 @@ func (s *visibilityArchiverSuite) TestQuery_Success_NoNextPageToken() {
 func (s *visibilityArchiverSuite) TestQuery_Success_SmallPageSize() {
 	visibilityArchiver := s.newTestVisibilityArchiver()
 	mockParser := NewMockQueryParser(s.controller)
-	mockParser.EXPECT().Parse(gomock.Any()).Return(&parsedQuery{
-		closeTime:       ptr(time.Unix(0, 0).UTC()),
-		searchPrecision: convert.StringPtr(PrecisionDay),
-		workflowID:      convert.StringPtr(testWorkflowID),
+	mockParser.EXPECT().Parse(gomock.Any(), gomock.Any()).Return(&archiver.IndexedVisibilityQuery{
+		CloseTime:       ptr(time.Unix(0, 0).UTC()),
+		SearchPrecision: convert.StringPtr(archiver.PrecisionDay),
+		WorkflowID:      convert.StringPtr(testWorkflowID),
 	}, nil).AnyTimes()
 	visibilityArchiver.queryParser = mockParser
 	request := &archiver.QueryVisibilityRequest{
@@ -442,70 +446,70 @@ func (s *visibilityArchiverSuite) TestArchiveAndQueryPrecisions() {
 			hour:      0,
 			minute:    0,
 			second:    0,
-			precision: PrecisionDay,
+			precision: archiver.PrecisionDay,
 		},
 		{
 			day:       1,
 			hour:      1,
 			minute:    0,
 			second:    0,
-			precision: PrecisionDay,
+			precision: archiver.PrecisionDay,
 		},
 		{
 			day:       2,
 			hour:      1,
 			minute:    0,
 			second:    0,
-			precision: PrecisionHour,
+			precision: archiver.PrecisionHour,
 		},
 		{
 			day:       2,
 			hour:      1,
 			minute:    30,
 			second:    0,
-			precision: PrecisionHour,
+			precision: archiver.PrecisionHour,
 		},
 		{
 			day:       3,
 			hour:      2,
 			minute:    1,
 			second:    0,
-			precision: PrecisionMinute,
+			precision: archiver.PrecisionMinute,
 		},
 		{
 			day:       3,
 			hour:      2,
 			minute:    1,
 			second:    30,
-			precision: PrecisionMinute,
+			precision: archiver.PrecisionMinute,
 		},
 		{
 			day:       4,
 			hour:      3,
 			minute:    2,
 			second:    1,
-			precision: PrecisionSecond,
+			precision: archiver.PrecisionSecond,
 		},
 		{
 			day:       4,
 			hour:      3,
 			minute:    2,
 			second:    1,
-			precision: PrecisionSecond,
+			precision: archiver.PrecisionSecond,
 		},
 		{
 			day:       4,
 			hour:      3,
 			minute:    2,
 			second:    2,
-			precision: PrecisionSecond,
+			precision: archiver.PrecisionSecond,
 		},
 		{
 			day:       4,
 			hour:      3,
 			minute:    2,
 			second:    2,
-			precision: PrecisionSecond,
+			precision: archiver.PrecisionSecond,
 		},
 	}
 	visibilityArchiver := s.newTestVisibilityArchiver()
@@ -536,10 +540,10 @@ func (s *visibilityArchiverSuite) TestArchiveAndQueryPrecisions() {
 
 	for i, testData := range precisionTests {
 		mockParser := NewMockQueryParser(s.controller)
-		mockParser.EXPECT().Parse(gomock.Any()).Return(&parsedQuery{
-			closeTime:       ptr(time.Date(2000, 1, testData.day, testData.hour, testData.minute, testData.second, 0, time.UTC)),
-			searchPrecision: convert.StringPtr(testData.precision),
-			workflowID:      convert.StringPtr(testWorkflowID),
+		mockParser.EXPECT().Parse(gomock.Any(), gomock.Any()).Return(&archiver.IndexedVisibilityQuery{
+			CloseTime:       ptr(time.Date(2000, 1, testData.day, testData.hour, testData.minute, testData.second, 0, time.UTC)),
+			SearchPrecision: convert.StringPtr(testData.precision),
+			WorkflowID:      convert.StringPtr(testWorkflowID),
 		}, nil).AnyTimes()
 		visibilityArchiver.queryParser = mockParser
 
@@ -549,10 +553,10 @@ func (s *visibilityArchiverSuite) TestArchiveAndQueryPrecisions() {
 		s.Len(response.Executions, 2, "Iteration ", i)
 
 		mockParser = NewMockQueryParser(s.controller)
-		mockParser.EXPECT().Parse(gomock.Any()).Return(&parsedQuery{
-			startTime:       ptr(time.Date(2000, 1, testData.day, testData.hour, testData.minute, testData.second, 0, time.UTC)),
-			searchPrecision: convert.StringPtr(testData.precision),
-			workflowID:      convert.StringPtr(testWorkflowID),
+		mockParser.EXPECT().Parse(gomock.Any(), gomock.Any()).Return(&archiver.IndexedVisibilityQuery{
+			StartTime:       ptr(time.Date(2000, 1, testData.day, testData.hour, testData.minute, testData.second, 0, time.UTC)),
+			SearchPrecision: convert.StringPtr(testData.precision),
+			WorkflowID:      convert.StringPtr(testWorkflowID),
 		}, nil).AnyTimes()
 		visibilityArchiver.queryParser = mockParser
 
@@ -562,10 +566,10 @@ func (s *visibilityArchiverSuite) TestArchiveAndQueryPrecisions() {
 		s.Len(response.Executions, 2, "Iteration ", i)
 
 		mockParser = NewMockQueryParser(s.controller)
-		mockParser.EXPECT().Parse(gomock.Any()).Return(&parsedQuery{
-			closeTime:        ptr(time.Date(2000, 1, testData.day, testData.hour, testData.minute, testData.second, 0, time.UTC)),
-			searchPrecision:  convert.StringPtr(testData.precision),
-			workflowTypeName: convert.StringPtr(testWorkflowTypeName),
+		mockParser.EXPECT().Parse(gomock.Any(), gomock.Any()).Return(&archiver.IndexedVisibilityQuery{
+			CloseTime:        ptr(time.Date(2000, 1, testData.day, testData.hour, testData.minute, testData.second, 0, time.UTC)),
+			SearchPrecision:  convert.StringPtr(testData.precision),
+			WorkflowTypeName: convert.StringPtr(testWorkflowTypeName),
 		}, nil).AnyTimes()
 		visibilityArchiver.queryParser = mockParser
 
@@ -575,10 +579,10 @@ func (s *visibilityArchiverSuite) TestArchiveAndQueryPrecisions() {
 		s.Len(response.Executions, 2, "Iteration ", i)
 
 		mockParser = NewMockQueryParser(s.controller)
-		mockParser.EXPECT().Parse(gomock.Any()).Return(&parsedQuery{
-			startTime:        ptr(time.Date(2000, 1, testData.day, testData.hour, testData.minute, testData.second, 0, time.UTC)),
-			searchPrecision:  convert.StringPtr(testData.precision),
-			workflowTypeName: convert.StringPtr(testWorkflowTypeName),
+		mockParser.EXPECT().Parse(gomock.Any(), gomock.Any()).Return(&archiver.IndexedVisibilityQuery{
+			StartTime:        ptr(time.Date(2000, 1, testData.day, testData.hour, testData.minute, testData.second, 0, time.UTC)),
+			SearchPrecision:  convert.StringPtr(testData.precision),
+			WorkflowTypeName: convert.StringPtr(testWorkflowTypeName),
 		}, nil).AnyTimes()
 		visibilityArchiver.queryParser = mockParser
 
@@ -599,8 +603,8 @@ func (s *visibilityArchiverSuite) TestArchiveAndQuery() {
 	}
 
 	mockParser := NewMockQueryParser(s.controller)
-	mockParser.EXPECT().Parse(gomock.Any()).Return(&parsedQuery{
-		workflowID: convert.StringPtr(testWorkflowID),
+	mockParser.EXPECT().Parse(gomock.Any(), gomock.Any()).Return(&archiver.IndexedVisibilityQuery{
+		WorkflowID: convert.StringPtr(testWorkflowID),
 	}, nil).AnyTimes()
 	visibilityArchiver.queryParser = mockParser
 	request := &archiver.QueryVisibilityRequest{
@@ -630,8 +634,8 @@ func (s *visibilityArchiverSuite) TestArchiveAndQuery() {
 	s.Equal(ei, executions[2])
 
 	mockParser = NewMockQueryParser(s.controller)
-	mockParser.EXPECT().Parse(gomock.Any()).Return(&parsedQuery{
-		workflowTypeName: convert.StringPtr(testWorkflowTypeName),
+	mockParser.EXPECT().Parse(gomock.Any(), gomock.Any()).Return(&archiver.IndexedVisibilityQuery{
+		WorkflowTypeName: convert.StringPtr(testWorkflowTypeName),
 	}, nil).AnyTimes()
 	visibilityArchiver.queryParser = mockParser
 	request = &archiver.QueryVisibilityRequest{
@@ -661,6 +665,109 @@ func (s *visibilityArchiverSuite) TestArchiveAndQuery() {
 	s.Equal(ei, executions[2])
 }
 
+func (s *visibilityArchiverSuite) TestArchiveAndQuery_VisibilityQuery() {
+	visibilityArchiver := s.newTestVisibilityArchiver()
+	URI, err := archiver.NewURI(testBucketURI + "/visibility-query")
+	s.NoError(err)
+	records := []*archiverspb.VisibilityRecord{
+		s.newSearchAttributesRecord("workflow-1", "type-a", 10, "a"),
+		s.newSearchAttributesRecord("workflow-2", "type-b", 500, "b"),
+		s.newSearchAttributesRecord("workflow-3", "type-a", 20, "c"),
+		s.newSearchAttributesRecord("workflow-4", "type-b", 40, "b"),
+	}
+	for _, record := range records {
+		err := visibilityArchiver.Archive(context.Background(), URI, record)
+		s.NoError(err)
+	}
+
+	request := &archiver.QueryVisibilityRequest{
+		NamespaceID: testNamespaceID,
+		PageSize:    1,
+		Query:       "(WorkflowType = 'type-a' or HistoryLength > 100) and CustomKeywordField != 'c' order by HistoryLength desc",
+	}
+	var workflowIDs []string
+	for len(workflowIDs) == 0 || request.NextPageToken != nil {
+		response, err := visibilityArchiver.Query(context.Background(), URI, request, searchattribute.TestNameTypeMap)
+		s.NoError(err)
+		s.NotNil(response)
+		for _, execution := range response.Executions {
+			workflowIDs = append(workflowIDs, execution.GetExecution().GetWorkflowId())
+		}
+		request.NextPageToken = response.NextPageToken
+	}
+	s.Equal([]string{"workflow-2", "workflow-1"}, workflowIDs)
+
+	request = &archiver.QueryVisibilityRequest{
+		NamespaceID: testNamespaceID,
+		PageSize:    10,
+		Query:       "WorkflowTypeName = 'type-b' and CloseTime = '1970-01-01T00:00:00Z' and SearchPrecision = 'Minute'",
+	}
+	response, err := visibilityArchiver.Query(context.Background(), URI, request, searchattribute.TestNameTypeMap)
+	s.NoError(err)
+	s.Len(response.Executions, 1)
+	s.Equal("workflow-4", response.Executions[0].GetExecution().GetWorkflowId())
+
+	request = &archiver.QueryVisibilityRequest{
+		NamespaceID: testNamespaceID,
+		PageSize:    10,
+		Query:       "CustomKeywordField = 'a' group by WorkflowType",
+	}
+	_, err = visibilityArchiver.Query(context.Background(), URI, request, searchattribute.TestNameTypeMap)
+	var svcErr *serviceerror.InvalidArgument
+	s.ErrorAs(err, &svcErr)
+}
+
+func (s *visibilityArchiverSuite) TestQuery_OrderBy_TooManyRecords() {
+	defer func(listPageSize, maxKeys int) {
+		orderedQueryListPageSize, orderedQueryMaxKeys = listPageSize, maxKeys
+	}(orderedQueryListPageSize, orderedQueryMaxKeys)
+	orderedQueryListPageSize, orderedQueryMaxKeys = 1, 2
+
+	visibilityArchiver := s.newTestVisibilityArchiver()
+	URI, err := archiver.NewURI(testBucketURI + "/order-by-limit")
+	s.NoError(err)
+	for _, workflowID := range []string{"workflow-1", "workflow-2"} {
+		err := visibilityArchiver.Archive(context.Background(), URI, s.newSearchAttributesRecord(workflowID, "type-a", 10, "a"))
+		s.NoError(err)
+	}
+
+	// A single key is stored under the closeTimeout index of each workflow ID, but a query without a
+	// WorkflowId or WorkflowType lists all keys of the namespace.
+	request := &archiver.QueryVisibilityRequest{
+		NamespaceID: testNamespaceID,
+		PageSize:    10,
+		Query:       "WorkflowId = 'workflow-1' order by CloseTime",
+	}
+	response, err := visibilityArchiver.Query(context.Background(), URI, request, searchattribute.TestNameTypeMap)
+	s.NoError(err)
+	s.Len(response.Executions, 1)
+
+	request.Query = "order by CloseTime"
+	_, err = visibilityArchiver.Query(context.Background(), URI, request, searchattribute.TestNameTypeMap)
+	var svcErr *serviceerror.InvalidArgument
+	s.ErrorAs(err, &svcErr)
+}
+
+func (s *visibilityArchiverSuite) newSearchAttributesRecord(
+	workflowID string,
+	workflowTypeName string,
+	historyLength int64,
+	keyword string,
+) *archiverspb.VisibilityRecord {
+	return &archiverspb.VisibilityRecord{
+		NamespaceId:      testNamespaceID,
+		Namespace:        testNamespace,
+		WorkflowId:       workflowID,
+		RunId:            workflowID + "-run",
+		WorkflowTypeName: workflowTypeName,
+		StartTime:        timestamp.UnixOrZeroTimePtr(1),
+		CloseTime:        timestamp.UnixOrZeroTimePtr(historyLength * int64(time.Second)),
+		Status:           enumspb.WORKFLOW_EXECUTION_STATUS_COMPLETED,
+		HistoryLength:    historyLength,
+		SearchAttributes: map[string]string{"CustomKeywordField": keyword},
+	}
+}
+
 func (s *visibilityArchiverSuite) setupVisibilityDirectory() {
 	s.visibilityRecords = []*archiverspb.VisibilityRecord{
 		{
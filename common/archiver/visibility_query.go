@@ -0,0 +1,783 @@
+// The MIT License
+//
+// Copyright (c) 2020 Temporal Technologies Inc.  All rights reserved.
+//
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package archiver
+
+import (
+	"cmp"
+	"fmt"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/temporalio/sqlparser"
+	enumspb "go.temporal.io/api/enums/v1"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	archiverspb "go.temporal.io/server/api/archiver/v1"
+	"go.temporal.io/server/common/persistence/visibility/store/query"
+	"go.temporal.io/server/common/primitives/timestamp"
+	"go.temporal.io/server/common/searchattribute"
+)
+
+type (
+	// VisibilityQuery is a visibility query which can be evaluated against archived visibility records.
+	// It supports the same subset of SQL as the SQL visibility stores: AND, OR, comparison, IN, BETWEEN
+	// and IS NULL expressions on system and custom search attributes. Results can also be sorted
+	// with an ORDER BY clause.
+	VisibilityQuery struct {
+		filter     visibilityFilter
+		orderBy    []*visibilityOrder
+		conditions []*VisibilityCondition
+	}
+
+	// VisibilityCondition is a condition on a single field which is combined with the rest of the query
+	// using AND. Every record matched by the query satisfies all of its conditions, so archivers can use
+	// them to narrow down the records they need to read.
+	VisibilityCondition struct {
+		Name     string
+		Operator string
+		// Values are converted to the type of the field: string, int64, float64, bool, time.Time or
+		// enumspb.WorkflowExecutionStatus for ExecutionStatus. Between conditions have two values.
+		Values []interface{}
+	}
+
+	visibilityFilter func(record *archiverspb.VisibilityRecord) bool
+
+	visibilityOrder struct {
+		field *visibilityField
+		desc  bool
+	}
+
+	visibilityField struct {
+		name      string
+		saType    enumspb.IndexedValueType
+		saTypeMap searchattribute.NameTypeMap
+	}
+
+	visibilityQueryConverter struct {
+		saTypeMap searchattribute.NameTypeMap
+	}
+)
+
+const visibilityQueryTemplate = "select * from dummy %s"
+
+var (
+	// archivedSystemFields are the system search attributes which are stored in archived visibility records.
+	archivedSystemFields = []string{
+		searchattribute.WorkflowID,
+		searchattribute.RunID,
+		searchattribute.WorkflowType,
+		searchattribute.StartTime,
+		searchattribute.ExecutionTime,
+		searchattribute.CloseTime,
+		searchattribute.ExecutionStatus,
+		searchattribute.HistoryLength,
+	}
+
+	equalityOperators = []string{
+		sqlparser.EqualStr,
+		sqlparser.NotEqualStr,
+	}
+	setOperators = []string{
+		sqlparser.EqualStr,
+		sqlparser.NotEqualStr,
+		sqlparser.InStr,
+		sqlparser.NotInStr,
+	}
+	rangeOperators = []string{
+		sqlparser.EqualStr,
+		sqlparser.NotEqualStr,
+		sqlparser.InStr,
+		sqlparser.NotInStr,
+		sqlparser.LessThanStr,
+		sqlparser.GreaterThanStr,
+		sqlparser.LessEqualStr,
+		sqlparser.GreaterEqualStr,
+	}
+	keywordOperators = []string{
+		sqlparser.EqualStr,
+		sqlparser.NotEqualStr,
+		sqlparser.InStr,
+		sqlparser.NotInStr,
+		sqlparser.LessThanStr,
+		sqlparser.GreaterThanStr,
+		sqlparser.LessEqualStr,
+		sqlparser.GreaterEqualStr,
+		sqlparser.StartsWithStr,
+		sqlparser.NotStartsWithStr,
+	}
+)
+
+// ParseVisibilityQuery parses a visibility query which consists of an optional where clause
+// followed by an optional order by clause.
+func ParseVisibilityQuery(queryStr string, saTypeMap searchattribute.NameTypeMap) (*VisibilityQuery, error) {
+	sel, err := ParseVisibilityQueryStatement(queryStr)
+	if err != nil {
+		return nil, err
+	}
+	return NewVisibilityQuery(sel, saTypeMap)
+}
+
+// ParseVisibilityQueryStatement parses a visibility query into a select statement without validating it.
+// It allows archivers to handle archiver specific conditions before calling NewVisibilityQuery.
+func ParseVisibilityQueryStatement(queryStr string) (*sqlparser.Select, error) {
+	queryStr = strings.TrimSpace(queryStr)
+	lowerQueryStr := strings.ToLower(queryStr)
+	if queryStr != "" &&
+		!strings.HasPrefix(lowerQueryStr, "order by ") &&
+		!strings.HasPrefix(lowerQueryStr, "group by ") {
+		queryStr = "where " + queryStr
+	}
+	stmt, err := sqlparser.Parse(fmt.Sprintf(visibilityQueryTemplate, queryStr))
+	if err != nil {
+		return nil, query.NewConverterError("%s: %v", query.MalformedSqlQueryErrMessage, err)
+	}
+	sel, isSelect := stmt.(*sqlparser.Select)
+	if !isSelect {
+		return nil, query.NewConverterError("%s: statement must be 'select' not %T", query.NotSupportedErrMessage, stmt)
+	}
+	return sel, nil
+}
+
+// NewVisibilityQuery validates the select statement and converts it to a VisibilityQuery.
+func NewVisibilityQuery(sel *sqlparser.Select, saTypeMap searchattribute.NameTypeMap) (*VisibilityQuery, error) {
+	if sel.Limit != nil {
+		return nil, query.NewConverterError("%s: 'limit' clause", query.NotSupportedErrMessage)
+	}
+	if len(sel.GroupBy) > 0 {
+		return nil, query.NewConverterError("%s: 'group by' clause", query.NotSupportedErrMessage)
+	}
+	if sel.Having != nil {
+		return nil, query.NewConverterError("%s: 'having' clause", query.NotSupportedErrMessage)
+	}
+
+	c := &visibilityQueryConverter{saTypeMap: saTypeMap}
+	visibilityQuery := &VisibilityQuery{}
+	if sel.Where != nil {
+		filter, err := c.convertWhereExpr(sel.Where.Expr, &visibilityQuery.conditions)
+		if err != nil {
+			return nil, err
+		}
+		visibilityQuery.filter = filter
+	}
+
+	for _, order := range sel.OrderBy {
+		field, err := c.convertColName(order.Expr)
+		if err != nil {
+			return nil, err
+		}
+		if field.saType == enumspb.INDEXED_VALUE_TYPE_TEXT || field.saType == enumspb.INDEXED_VALUE_TYPE_KEYWORD_LIST {
+			return nil, query.NewConverterError(
+				"%s: cannot order by search attribute '%s' of type %s",
+				query.NotSupportedErrMessage,
+				field.name,
+				field.saType.String(),
+			)
+		}
+		visibilityQuery.orderBy = append(visibilityQuery.orderBy, &visibilityOrder{
+			field: field,
+			desc:  order.Direction == sqlparser.DescScr,
+		})
+	}
+	return visibilityQuery, nil
+}
+
+// Match returns true if the record satisfies the where clause of the query.
+func (q *VisibilityQuery) Match(record *archiverspb.VisibilityRecord) bool {
+	return q.filter == nil || q.filter(record)
+}
+
+// HasOrderBy returns true if the query has an order by clause.
+func (q *VisibilityQuery) HasOrderBy() bool {
+	return len(q.orderBy) > 0
+}
+
+// OrderedByCloseTime returns true if the query is ordered by CloseTime only, and whether the order is
+// descending. Archivers which store records sorted by close time can return such queries in storage order.
+func (q *VisibilityQuery) OrderedByCloseTime() (ordered bool, desc bool) {
+	if len(q.orderBy) != 1 || q.orderBy[0].field.name != searchattribute.CloseTime {
+		return false, false
+	}
+	return true, q.orderBy[0].desc
+}
+
+// Conditions returns the conditions which are combined with AND at the top level of the where clause.
+func (q *VisibilityQuery) Conditions() []*VisibilityCondition {
+	return q.conditions
+}
+
+// SortRecords sorts records by the order by clause of the query. Records which are equal keep their
+// original order and records without a value for a sort field are placed last.
+func (q *VisibilityQuery) SortRecords(records []*archiverspb.VisibilityRecord) {
+	if len(q.orderBy) == 0 {
+		return
+	}
+
+	type sortableRecord struct {
+		record *archiverspb.VisibilityRecord
+		keys   []interface{}
+	}
+	sortableRecords := make([]sortableRecord, len(records))
+	for i, record := range records {
+		keys := make([]interface{}, len(q.orderBy))
+		for j, order := range q.orderBy {
+			if value, ok := order.field.recordValue(record); ok {
+				keys[j] = value
+			}
+		}
+		sortableRecords[i] = sortableRecord{record: record, keys: keys}
+	}
+
+	sort.SliceStable(sortableRecords, func(i, j int) bool {
+		return q.compareKeys(sortableRecords[i].keys, sortableRecords[j].keys) < 0
+	})
+	for i, sortableRecord := range sortableRecords {
+		records[i] = sortableRecord.record
+	}
+}
+
+func (q *VisibilityQuery) compareKeys(left []interface{}, right []interface{}) int {
+	for i, order := range q.orderBy {
+		switch {
+		case left[i] == nil && right[i] == nil:
+			continue
+		case left[i] == nil:
+			return 1
+		case right[i] == nil:
+			return -1
+		}
+		result := compareValues(left[i], right[i])
+		if order.desc {
+			result = -result
+		}
+		if result != 0 {
+			return result
+		}
+	}
+	return 0
+}
+
+// convertWhereExpr converts the expression to a filter. Conditions are collected only for expressions
+// which are combined with AND at the top level, in which case conditions is not nil.
+func (c *visibilityQueryConverter) convertWhereExpr(
+	expr sqlparser.Expr,
+	conditions *[]*VisibilityCondition,
+) (visibilityFilter, error) {
+	switch e := expr.(type) {
+	case *sqlparser.AndExpr:
+		left, err := c.convertWhereExpr(e.Left, conditions)
+		if err != nil {
+			return nil, err
+		}
+		right, err := c.convertWhereExpr(e.Right, conditions)
+		if err != nil {
+			return nil, err
+		}
+		return func(record *archiverspb.VisibilityRecord) bool {
+			return left(record) && right(record)
+		}, nil
+	case *sqlparser.OrExpr:
+		left, err := c.convertWhereExpr(e.Left, nil)
+		if err != nil {
+			return nil, err
+		}
+		right, err := c.convertWhereExpr(e.Right, nil)
+		if err != nil {
+			return nil, err
+		}
+		return func(record *archiverspb.VisibilityRecord) bool {
+			return left(record) || right(record)
+		}, nil
+	case *sqlparser.ParenExpr:
+		return c.convertWhereExpr(e.Expr, conditions)
+	case *sqlparser.ComparisonExpr:
+		return c.convertComparisonExpr(e, conditions)
+	case *sqlparser.RangeCond:
+		return c.convertRangeCond(e, conditions)
+	case *sqlparser.IsExpr:
+		return c.convertIsExpr(e)
+	case *sqlparser.NotExpr:
+		return nil, query.NewConverterError("%s: 'not' expression", query.NotSupportedErrMessage)
+	case *sqlparser.FuncExpr:
+		return nil, query.NewConverterError("%s: function expression", query.NotSupportedErrMessage)
+	case *sqlparser.ColName:
+		return nil, query.NewConverterError("%s: incomplete expression", query.InvalidExpressionErrMessage)
+	default:
+		return nil, query.NewConverterError("%s: expression of type %T", query.NotSupportedErrMessage, expr)
+	}
+}
+
+func (c *visibilityQueryConverter) convertComparisonExpr(
+	expr *sqlparser.ComparisonExpr,
+	conditions *[]*VisibilityCondition,
+) (visibilityFilter, error) {
+	field, err := c.convertColName(expr.Left)
+	if err != nil {
+		return nil, err
+	}
+	if !slices.Contains(field.allowedOperators(), expr.Operator) {
+		return nil, query.NewConverterError(
+			"%s: operator '%s' not allowed for search attribute '%s' of type %s",
+			query.InvalidExpressionErrMessage,
+			expr.Operator,
+			field.name,
+			field.saType.String(),
+		)
+	}
+	if expr.Escape != nil {
+		return nil, query.NewConverterError("%s: 'escape' clause", query.NotSupportedErrMessage)
+	}
+
+	var values []interface{}
+	switch expr.Operator {
+	case sqlparser.InStr, sqlparser.NotInStr:
+		tuple, isTuple := expr.Right.(sqlparser.ValTuple)
+		if !isTuple {
+			return nil, query.NewConverterError(
+				"%s: right-hand side of '%s' must be a tuple of values",
+				query.InvalidExpressionErrMessage,
+				expr.Operator,
+			)
+		}
+		for _, valueExpr := range tuple {
+			value, err := field.convertValue(valueExpr)
+			if err != nil {
+				return nil, err
+			}
+			values = append(values, value)
+		}
+	default:
+		value, err := field.convertValue(expr.Right)
+		if err != nil {
+			return nil, err
+		}
+		values = append(values, value)
+	}
+
+	if conditions != nil {
+		*conditions = append(*conditions, &VisibilityCondition{
+			Name:     field.name,
+			Operator: expr.Operator,
+			Values:   values,
+		})
+	}
+
+	operator := expr.Operator
+	return func(record *archiverspb.VisibilityRecord) bool {
+		recordValue, ok := field.recordValue(record)
+		if !ok {
+			return false
+		}
+		return field.compare(operator, recordValue, values)
+	}, nil
+}
+
+func (c *visibilityQueryConverter) convertRangeCond(
+	expr *sqlparser.RangeCond,
+	conditions *[]*VisibilityCondition,
+) (visibilityFilter, error) {
+	field, err := c.convertColName(expr.Left)
+	if err != nil {
+		return nil, err
+	}
+	switch expr.Operator {
+	case sqlparser.BetweenStr:
+	case sqlparser.NotBetweenStr:
+		return nil, query.NewConverterError("%s: 'not between' expression", query.NotSupportedErrMessage)
+	default:
+		return nil, query.NewConverterError(
+			"%s: range condition operator must be 'between'",
+			query.InvalidExpressionErrMessage,
+		)
+	}
+	if !slices.Contains(field.allowedOperators(), sqlparser.LessThanStr) {
+		return nil, query.NewConverterError(
+			"%s: cannot do range condition on search attribute '%s' of type %s",
+			query.InvalidExpressionErrMessage,
+			field.name,
+			field.saType.String(),
+		)
+	}
+
+	from, err := field.convertValue(expr.From)
+	if err != nil {
+		return nil, err
+	}
+	to, err := field.convertValue(expr.To)
+	if err != nil {
+		return nil, err
+	}
+
+	if conditions != nil {
+		*conditions = append(*conditions, &VisibilityCondition{
+			Name:     field.name,
+			Operator: sqlparser.BetweenStr,
+			Values:   []interface{}{from, to},
+		})
+	}
+
+	return func(record *archiverspb.VisibilityRecord) bool {
+		recordValue, ok := field.recordValue(record)
+		if !ok {
+			return false
+		}
+		return compareValues(recordValue, from) >= 0 && compareValues(recordValue, to) <= 0
+	}, nil
+}
+
+func (c *visibilityQueryConverter) convertIsExpr(expr *sqlparser.IsExpr) (visibilityFilter, error) {
+	field, err := c.convertColName(expr.Expr)
+	if err != nil {
+		return nil, err
+	}
+	var isNull bool
+	switch expr.Operator {
+	case sqlparser.IsNullStr:
+		isNull = true
+	case sqlparser.IsNotNullStr:
+		isNull = false
+	default:
+		return nil, query.NewConverterError(
+			"%s: 'is' operator can only be used with 'null' or 'not null'",
+			query.InvalidExpressionErrMessage,
+		)
+	}
+	return func(record *archiverspb.VisibilityRecord) bool {
+		_, ok := field.recordValue(record)
+		return ok != isNull
+	}, nil
+}
+
+func (c *visibilityQueryConverter) convertColName(expr sqlparser.Expr) (*visibilityField, error) {
+	colName, isColName := expr.(*sqlparser.ColName)
+	if !isColName {
+		return nil, query.NewConverterError(
+			"%s: must be a column name but was %T",
+			query.InvalidExpressionErrMessage,
+			expr,
+		)
+	}
+	name := strings.ReplaceAll(sqlparser.String(colName), "`", "")
+	if searchattribute.IsSystem(name) && !slices.Contains(archivedSystemFields, name) {
+		return nil, query.NewConverterError(
+			"%s: search attribute '%s' is not stored in archived visibility records",
+			query.NotSupportedErrMessage,
+			name,
+		)
+	}
+	saType, err := c.saTypeMap.GetType(name)
+	if err != nil {
+		return nil, query.NewConverterError(
+			"%s: column name '%s' is not a valid search attribute",
+			query.InvalidExpressionErrMessage,
+			name,
+		)
+	}
+	return &visibilityField{
+		name:      name,
+		saType:    saType,
+		saTypeMap: c.saTypeMap,
+	}, nil
+}
+
+func (f *visibilityField) isExecutionStatus() bool {
+	return f.name == searchattribute.ExecutionStatus
+}
+
+func (f *visibilityField) allowedOperators() []string {
+	if f.isExecutionStatus() {
+		return setOperators
+	}
+	switch f.saType {
+	case enumspb.INDEXED_VALUE_TYPE_KEYWORD:
+		return keywordOperators
+	case enumspb.INDEXED_VALUE_TYPE_KEYWORD_LIST:
+		return setOperators
+	case enumspb.INDEXED_VALUE_TYPE_TEXT, enumspb.INDEXED_VALUE_TYPE_BOOL:
+		return equalityOperators
+	default:
+		return rangeOperators
+	}
+}
+
+// convertValue converts a literal from the query to the type of the field.
+func (f *visibilityField) convertValue(expr sqlparser.Expr) (interface{}, error) {
+	var value interface{}
+	switch e := expr.(type) {
+	case *sqlparser.SQLVal:
+		var err error
+		switch e.Type {
+		case sqlparser.StrVal:
+			value = string(e.Val)
+		case sqlparser.IntVal:
+			value, err = strconv.ParseInt(string(e.Val), 10, 64)
+		case sqlparser.FloatVal:
+			value, err = strconv.ParseFloat(string(e.Val), 64)
+		default:
+			err = fmt.Errorf("unsupported literal type %v", e.Type)
+		}
+		if err != nil {
+			return nil, query.NewConverterError(
+				"%s: unable to parse %s",
+				query.InvalidExpressionErrMessage,
+				sqlparser.String(e),
+			)
+		}
+	case sqlparser.BoolVal:
+		value = bool(e)
+	case *sqlparser.ColName:
+		return nil, query.NewConverterError(
+			"%s: column name on the right side of comparison expression (did you forget to quote '%s'?)",
+			query.NotSupportedErrMessage,
+			sqlparser.String(e),
+		)
+	default:
+		return nil, query.NewConverterError(
+			"%s: unexpected value type %T",
+			query.InvalidExpressionErrMessage,
+			expr,
+		)
+	}
+
+	if converted, ok := f.convertTypedValue(value); ok {
+		return converted, nil
+	}
+	return nil, query.NewConverterError(
+		"%s: invalid value %s for search attribute '%s' of type %s",
+		query.InvalidExpressionErrMessage,
+		sqlparser.String(expr),
+		f.name,
+		f.saType.String(),
+	)
+}
+
+func (f *visibilityField) convertTypedValue(value interface{}) (interface{}, bool) {
+	if f.isExecutionStatus() {
+		switch v := value.(type) {
+		case string:
+			return parseExecutionStatus(v)
+		case int64:
+			return parseExecutionStatus(strconv.FormatInt(v, 10))
+		}
+		return nil, false
+	}
+
+	switch f.saType {
+	case enumspb.INDEXED_VALUE_TYPE_KEYWORD, enumspb.INDEXED_VALUE_TYPE_KEYWORD_LIST, enumspb.INDEXED_VALUE_TYPE_TEXT:
+		v, ok := value.(string)
+		return v, ok
+	case enumspb.INDEXED_VALUE_TYPE_INT:
+		v, ok := value.(int64)
+		return v, ok
+	case enumspb.INDEXED_VALUE_TYPE_DOUBLE:
+		switch v := value.(type) {
+		case int64:
+			return float64(v), true
+		case float64:
+			return v, true
+		}
+	case enumspb.INDEXED_VALUE_TYPE_BOOL:
+		v, ok := value.(bool)
+		return v, ok
+	case enumspb.INDEXED_VALUE_TYPE_DATETIME:
+		switch v := value.(type) {
+		case int64:
+			return timestamp.UnixOrZeroTime(v), true
+		case string:
+			t, err := time.Parse(time.RFC3339Nano, v)
+			return t, err == nil
+		}
+	}
+	return nil, false
+}
+
+// recordValue returns the value of the field in the record and false if the record doesn't have it.
+func (f *visibilityField) recordValue(record *archiverspb.VisibilityRecord) (interface{}, bool) {
+	switch f.name {
+	case searchattribute.WorkflowID:
+		return record.GetWorkflowId(), true
+	case searchattribute.RunID:
+		return record.GetRunId(), true
+	case searchattribute.WorkflowType:
+		return record.GetWorkflowTypeName(), true
+	case searchattribute.StartTime:
+		return timeValue(record.GetStartTime())
+	case searchattribute.ExecutionTime:
+		return timeValue(record.GetExecutionTime())
+	case searchattribute.CloseTime:
+		return timeValue(record.GetCloseTime())
+	case searchattribute.ExecutionStatus:
+		return record.GetStatus(), true
+	case searchattribute.HistoryLength:
+		return record.GetHistoryLength(), true
+	}
+
+	valueStr, ok := record.GetSearchAttributes()[f.name]
+	if !ok {
+		return nil, false
+	}
+	searchAttributes, err := searchattribute.Parse(map[string]string{f.name: valueStr}, &f.saTypeMap)
+	if err != nil {
+		return nil, false
+	}
+	valuePayload := searchAttributes.GetIndexedFields()[f.name]
+	if f.saType == enumspb.INDEXED_VALUE_TYPE_KEYWORD_LIST {
+		// A keyword list with a single value is stringified as a plain string.
+		value, err := searchattribute.DecodeValue(valuePayload, enumspb.INDEXED_VALUE_TYPE_KEYWORD, true)
+		if err != nil || value == nil {
+			return nil, false
+		}
+		if v, isString := value.(string); isString {
+			return []string{v}, true
+		}
+		return value, true
+	}
+	value, err := searchattribute.DecodeValue(valuePayload, f.saType, false)
+	if err != nil || value == nil {
+		return nil, false
+	}
+	return value, true
+}
+
+func (f *visibilityField) compare(operator string, recordValue interface{}, values []interface{}) bool {
+	switch operator {
+	case sqlparser.EqualStr:
+		return f.equal(recordValue, values[0])
+	case sqlparser.NotEqualStr:
+		return !f.equal(recordValue, values[0])
+	case sqlparser.InStr:
+		return slices.ContainsFunc(values, func(value interface{}) bool { return f.equal(recordValue, value) })
+	case sqlparser.NotInStr:
+		return !slices.ContainsFunc(values, func(value interface{}) bool { return f.equal(recordValue, value) })
+	case sqlparser.LessThanStr:
+		return compareValues(recordValue, values[0]) < 0
+	case sqlparser.GreaterThanStr:
+		return compareValues(recordValue, values[0]) > 0
+	case sqlparser.LessEqualStr:
+		return compareValues(recordValue, values[0]) <= 0
+	case sqlparser.GreaterEqualStr:
+		return compareValues(recordValue, values[0]) >= 0
+	case sqlparser.StartsWithStr:
+		return strings.HasPrefix(recordValue.(string), values[0].(string))
+	case sqlparser.NotStartsWithStr:
+		return !strings.HasPrefix(recordValue.(string), values[0].(string))
+	default:
+		return false
+	}
+}
+
+func (f *visibilityField) equal(recordValue interface{}, value interface{}) bool {
+	switch f.saType {
+	case enumspb.INDEXED_VALUE_TYPE_KEYWORD_LIST:
+		return slices.Contains(recordValue.([]string), value.(string))
+	case enumspb.INDEXED_VALUE_TYPE_TEXT:
+		return matchText(recordValue.(string), value.(string))
+	default:
+		return compareValues(recordValue, value) == 0
+	}
+}
+
+// matchText returns true if every token of the phrase is also a token of the text. It mirrors
+// the full text search of the SQL visibility stores.
+func matchText(text string, phrase string) bool {
+	phraseTokens := tokenizeText(phrase)
+	if len(phraseTokens) == 0 {
+		return false
+	}
+	textTokens := tokenizeText(text)
+	for _, token := range phraseTokens {
+		if !slices.Contains(textTokens, token) {
+			return false
+		}
+	}
+	return true
+}
+
+func tokenizeText(text string) []string {
+	return strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+}
+
+// compareValues compares two values of the same type.
+func compareValues(left interface{}, right interface{}) int {
+	switch l := left.(type) {
+	case string:
+		return strings.Compare(l, right.(string))
+	case int64:
+		return cmp.Compare(l, right.(int64))
+	case float64:
+		return cmp.Compare(l, right.(float64))
+	case bool:
+		r := right.(bool)
+		switch {
+		case l == r:
+			return 0
+		case r:
+			return -1
+		default:
+			return 1
+		}
+	case time.Time:
+		return l.Compare(right.(time.Time))
+	case enumspb.WorkflowExecutionStatus:
+		return cmp.Compare(l, right.(enumspb.WorkflowExecutionStatus))
+	default:
+		return 0
+	}
+}
+
+func timeValue(ts *timestamppb.Timestamp) (interface{}, bool) {
+	if ts == nil {
+		return nil, false
+	}
+	return ts.AsTime(), true
+}
+
+// parseExecutionStatus accepts status names in any case, with or without underscores, and status numbers.
+func parseExecutionStatus(statusStr string) (enumspb.WorkflowExecutionStatus, bool) {
+	statusStr = strings.TrimSpace(statusStr)
+	if status, err := enumspb.WorkflowExecutionStatusFromString(statusStr); err == nil &&
+		status != enumspb.WORKFLOW_EXECUTION_STATUS_UNSPECIFIED {
+		return status, true
+	}
+	if statusNum, err := strconv.ParseInt(statusStr, 10, 32); err == nil {
+		if _, ok := enumspb.WorkflowExecutionStatus_name[int32(statusNum)]; ok &&
+			statusNum != int64(enumspb.WORKFLOW_EXECUTION_STATUS_UNSPECIFIED) {
+			return enumspb.WorkflowExecutionStatus(statusNum), true
+		}
+		return 0, false
+	}
+	normalized := strings.ReplaceAll(strings.ToLower(statusStr), "_", "")
+	for name, value := range enumspb.WorkflowExecutionStatus_shorthandValue {
+		if strings.ToLower(name) == normalized && value != int32(enumspb.WORKFLOW_EXECUTION_STATUS_UNSPECIFIED) {
+			return enumspb.WorkflowExecutionStatus(value), true
+		}
+	}
+	return 0, false
+}
@@ -0,0 +1,245 @@
+// The MIT License
+//
+// Copyright (c) 2020 Temporal Technologies Inc.  All rights reserved.
+//
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package archiver
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+	enumspb "go.temporal.io/api/enums/v1"
+
+	archiverspb "go.temporal.io/server/api/archiver/v1"
+	"go.temporal.io/server/common/primitives/timestamp"
+	"go.temporal.io/server/common/searchattribute"
+)
+
+type (
+	visibilityQuerySuite struct {
+		*require.Assertions
+		suite.Suite
+
+		records []*archiverspb.VisibilityRecord
+	}
+)
+
+func TestVisibilityQuerySuite(t *testing.T) {
+	suite.Run(t, new(visibilityQuerySuite))
+}
+
+func (s *visibilityQuerySuite) SetupTest() {
+	s.Assertions = require.New(s.T())
+	s.records = []*archiverspb.VisibilityRecord{
+		{
+			WorkflowId:       "workflow-1",
+			RunId:            "run-1",
+			WorkflowTypeName: "type-a",
+			StartTime:        timestamp.TimePtr(time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)),
+			CloseTime:        timestamp.TimePtr(time.Date(2020, 1, 2, 0, 0, 0, 0, time.UTC)),
+			Status:           enumspb.WORKFLOW_EXECUTION_STATUS_COMPLETED,
+			HistoryLength:    10,
+			SearchAttributes: map[string]string{
+				"CustomKeywordField":  "alpha",
+				"CustomIntField":      "5",
+				"CustomDoubleField":   "1.5",
+				"CustomBoolField":     "true",
+				"CustomTextField":     "The quick brown fox",
+				"CustomDatetimeField": "2020-01-01T12:00:00Z",
+				"KeywordList01":       `["red","green"]`,
+			},
+		},
+		{
+			WorkflowId:       "workflow-2",
+			RunId:            "run-2",
+			WorkflowTypeName: "type-b",
+			StartTime:        timestamp.TimePtr(time.Date(2020, 1, 3, 0, 0, 0, 0, time.UTC)),
+			ExecutionTime:    timestamp.TimePtr(time.Date(2020, 1, 3, 0, 0, 0, 0, time.UTC)),
+			CloseTime:        timestamp.TimePtr(time.Date(2020, 1, 4, 0, 0, 0, 0, time.UTC)),
+			Status:           enumspb.WORKFLOW_EXECUTION_STATUS_FAILED,
+			HistoryLength:    30,
+			SearchAttributes: map[string]string{
+				"CustomKeywordField": "beta",
+				"CustomIntField":     "50",
+				"CustomBoolField":    "false",
+				"CustomTextField":    "lazy dog",
+				"KeywordList01":      "blue",
+			},
+		},
+		{
+			WorkflowId:       "other-3",
+			RunId:            "run-3",
+			WorkflowTypeName: "type-a",
+			StartTime:        timestamp.TimePtr(time.Date(2020, 1, 5, 0, 0, 0, 0, time.UTC)),
+			CloseTime:        timestamp.TimePtr(time.Date(2020, 1, 6, 0, 0, 0, 0, time.UTC)),
+			Status:           enumspb.WORKFLOW_EXECUTION_STATUS_TIMED_OUT,
+			HistoryLength:    20,
+		},
+	}
+}
+
+func (s *visibilityQuerySuite) TestMatch() {
+	testCases := []struct {
+		query   string
+		matches []string
+	}{
+		{query: "", matches: []string{"workflow-1", "workflow-2", "other-3"}},
+		{query: "WorkflowId = 'workflow-1'", matches: []string{"workflow-1"}},
+		{query: "WorkflowId != 'workflow-1'", matches: []string{"workflow-2", "other-3"}},
+		{query: "WorkflowId = 'workflow-1' or WorkflowType = 'type-b'", matches: []string{"workflow-1", "workflow-2"}},
+		{query: "WorkflowType = 'type-a' and (HistoryLength > 15 or RunId = 'run-1')", matches: []string{"workflow-1", "other-3"}},
+		{query: "WorkflowId in ('workflow-2', 'other-3')", matches: []string{"workflow-2", "other-3"}},
+		{query: "WorkflowId not in ('workflow-2', 'other-3')", matches: []string{"workflow-1"}},
+		{query: "WorkflowId starts_with 'workflow'", matches: []string{"workflow-1", "workflow-2"}},
+		{query: "WorkflowId not starts_with 'workflow'", matches: []string{"other-3"}},
+		{query: "WorkflowId >= 'workflow-2'", matches: []string{"workflow-2"}},
+		{query: "HistoryLength between 15 and 30", matches: []string{"workflow-2", "other-3"}},
+		{query: "HistoryLength <= 20", matches: []string{"workflow-1", "other-3"}},
+		{query: "ExecutionStatus = 'Completed'", matches: []string{"workflow-1"}},
+		{query: "ExecutionStatus in ('Failed', 'timed_out')", matches: []string{"workflow-2", "other-3"}},
+		{query: "ExecutionStatus != 2", matches: []string{"workflow-2", "other-3"}},
+		{query: "CloseTime > '2020-01-03T00:00:00Z'", matches: []string{"workflow-2", "other-3"}},
+		{query: "StartTime between '2020-01-01T00:00:00Z' and '2020-01-03T00:00:00Z'", matches: []string{"workflow-1", "workflow-2"}},
+		{query: "ExecutionTime is null", matches: []string{"workflow-1", "other-3"}},
+		{query: "ExecutionTime is not null", matches: []string{"workflow-2"}},
+		{query: "CustomKeywordField = 'alpha'", matches: []string{"workflow-1"}},
+		{query: "CustomKeywordField != 'alpha'", matches: []string{"workflow-2"}},
+		{query: "CustomKeywordField is null", matches: []string{"other-3"}},
+		{query: "CustomIntField > 10", matches: []string{"workflow-2"}},
+		{query: "CustomIntField in (5, 6)", matches: []string{"workflow-1"}},
+		{query: "CustomDoubleField < 2", matches: []string{"workflow-1"}},
+		{query: "CustomBoolField = true", matches: []string{"workflow-1"}},
+		{query: "CustomBoolField = false", matches: []string{"workflow-2"}},
+		{query: "CustomTextField = 'Brown FOX'", matches: []string{"workflow-1"}},
+		{query: "CustomTextField = 'brown dog'", matches: nil},
+		{query: "CustomTextField != 'fox'", matches: []string{"workflow-2"}},
+		{query: "CustomDatetimeField >= '2020-01-01T00:00:00Z'", matches: []string{"workflow-1"}},
+		{query: "KeywordList01 = 'green'", matches: []string{"workflow-1"}},
+		{query: "KeywordList01 = 'blue'", matches: []string{"workflow-2"}},
+		{query: "KeywordList01 in ('red', 'blue')", matches: []string{"workflow-1", "workflow-2"}},
+		{query: "KeywordList01 not in ('red')", matches: []string{"workflow-2"}},
+		{query: "`CustomKeywordField` = 'beta'", matches: []string{"workflow-2"}},
+		{query: "order by CloseTime desc", matches: []string{"workflow-1", "workflow-2", "other-3"}},
+	}
+
+	for _, tc := range testCases {
+		visibilityQuery, err := ParseVisibilityQuery(tc.query, searchattribute.TestNameTypeMap)
+		s.NoError(err, tc.query)
+		var matches []string
+		for _, record := range s.records {
+			if visibilityQuery.Match(record) {
+				matches = append(matches, record.GetWorkflowId())
+			}
+		}
+		s.Equal(tc.matches, matches, tc.query)
+	}
+}
+
+func (s *visibilityQuerySuite) TestParseErrors() {
+	testCases := []struct {
+		query string
+		err   string
+	}{
+		{query: "WorkflowId = ", err: "malformed SQL query"},
+		{query: "WorkflowId = 'a' limit 10", err: "'limit' clause"},
+		{query: "group by ExecutionStatus", err: "'group by' clause"},
+		{query: "not WorkflowId = 'a'", err: "'not' expression"},
+		{query: "WorkflowId like 'a%'", err: "operator 'like' not allowed"},
+		{query: "CustomTextField > 'a'", err: "operator '>' not allowed"},
+		{query: "ExecutionStatus > 'Failed'", err: "operator '>' not allowed"},
+		{query: "KeywordList01 starts_with 'a'", err: "operator 'starts_with' not allowed"},
+		{query: "CustomBoolField between true and false", err: "cannot do range condition"},
+		{query: "HistoryLength not between 1 and 2", err: "'not between' expression"},
+		{query: "UnknownField = 'a'", err: "column name 'UnknownField' is not a valid search attribute"},
+		{query: "workflowid = 'a'", err: "column name 'workflowid' is not a valid search attribute"},
+		{query: "TaskQueue = 'a'", err: "search attribute 'TaskQueue' is not stored in archived visibility records"},
+		{query: "HistoryLength = 'a'", err: "invalid value 'a'"},
+		{query: "CloseTime > '2020-01-01 00:00:00'", err: "invalid value"},
+		{query: "ExecutionStatus = 'Unknown'", err: "invalid value"},
+		{query: "WorkflowId = RunId", err: "did you forget to quote 'RunId'?"},
+		{query: "WorkflowId", err: "incomplete expression"},
+		{query: "order by CustomTextField", err: "cannot order by search attribute 'CustomTextField'"},
+		{query: "order by KeywordList01", err: "cannot order by search attribute 'KeywordList01'"},
+	}
+
+	for _, tc := range testCases {
+		_, err := ParseVisibilityQuery(tc.query, searchattribute.TestNameTypeMap)
+		s.Error(err, tc.query)
+		s.Contains(err.Error(), tc.err, tc.query)
+	}
+}
+
+func (s *visibilityQuerySuite) TestConditions() {
+	visibilityQuery, err := ParseVisibilityQuery(
+		"WorkflowId = 'workflow-1' and (CloseTime between 1000 and 2000 and ExecutionStatus in ('Failed')) and (RunId = 'a' or RunId = 'b')",
+		searchattribute.TestNameTypeMap,
+	)
+	s.NoError(err)
+	s.Equal([]*VisibilityCondition{
+		{
+			Name:     searchattribute.WorkflowID,
+			Operator: "=",
+			Values:   []interface{}{"workflow-1"},
+		},
+		{
+			Name:     searchattribute.CloseTime,
+			Operator: "between",
+			Values:   []interface{}{time.Unix(0, 1000).UTC(), time.Unix(0, 2000).UTC()},
+		},
+		{
+			Name:     searchattribute.ExecutionStatus,
+			Operator: "in",
+			Values:   []interface{}{enumspb.WORKFLOW_EXECUTION_STATUS_FAILED},
+		},
+	}, visibilityQuery.Conditions())
+}
+
+func (s *visibilityQuerySuite) TestSortRecords() {
+	testCases := []struct {
+		query      string
+		hasOrderBy bool
+		order      []string
+	}{
+		{query: "WorkflowId != ''", hasOrderBy: false, order: []string{"workflow-1", "workflow-2", "other-3"}},
+		{query: "order by HistoryLength", hasOrderBy: true, order: []string{"workflow-1", "other-3", "workflow-2"}},
+		{query: "order by HistoryLength desc", hasOrderBy: true, order: []string{"workflow-2", "other-3", "workflow-1"}},
+		{query: "WorkflowId != 'a' order by WorkflowType desc, CloseTime desc", hasOrderBy: true, order: []string{"workflow-2", "other-3", "workflow-1"}},
+		{query: "order by CustomIntField desc", hasOrderBy: true, order: []string{"workflow-2", "workflow-1", "other-3"}},
+		{query: "order by ExecutionTime", hasOrderBy: true, order: []string{"workflow-2", "workflow-1", "other-3"}},
+	}
+
+	for _, tc := range testCases {
+		visibilityQuery, err := ParseVisibilityQuery(tc.query, searchattribute.TestNameTypeMap)
+		s.NoError(err, tc.query)
+		s.Equal(tc.hasOrderBy, visibilityQuery.HasOrderBy(), tc.query)
+		records := append([]*archiverspb.VisibilityRecord(nil), s.records...)
+		visibilityQuery.SortRecords(records)
+		var order []string
+		for _, record := range records {
+			order = append(order, record.GetWorkflowId())
+		}
+		s.Equal(tc.order, order, tc.query)
+	}
+}
@@ -0,0 +1,82 @@
+// The MIT License
+//
+// Copyright (c) 2020 Temporal Technologies Inc.  All rights reserved.
+//
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+// Package audit records who called which frontend API, on what, and with what result.
+package audit
+
+import (
+	"time"
+
+	"go.temporal.io/server/common/masker"
+)
+
+const (
+	DecisionAllow = "allow"
+	DecisionDeny  = "deny"
+)
+
+type (
+	// Record is a single audit log entry for one API call.
+	Record struct {
+		Time time.Time `json:"time"`
+		// Caller is the subject of the caller's claims, empty if the call had no auth info.
+		Caller string `json:"caller,omitempty"`
+		// Identity is the identity field of the request, if it has one.
+		Identity   string `json:"identity,omitempty"`
+		Namespace  string `json:"namespace,omitempty"`
+		API        string `json:"api"`
+		WorkflowID string `json:"workflowId,omitempty"`
+		RunID      string `json:"runId,omitempty"`
+		// ReadOnly is set for sampled read-only calls.
+		ReadOnly bool `json:"readOnly,omitempty"`
+		// Decision is DecisionAllow or DecisionDeny, or empty if authorization was not reached
+		// or failed.
+		Decision string `json:"decision,omitempty"`
+		Reason   string `json:"reason,omitempty"`
+		// Outcome is the gRPC status code name of the call, e.g. "OK" or "PermissionDenied".
+		Outcome string `json:"outcome"`
+		Error   string `json:"error,omitempty"`
+	}
+
+	// Sink is a destination of audit records. Implementations must be safe for concurrent use.
+	Sink interface {
+		Write(record *Record) error
+		Close() error
+	}
+
+	// Redactor returns the record to write in place of the given one. It must not modify its
+	// argument.
+	Redactor func(record *Record) *Record
+)
+
+// NewMaskerRedactor returns a Redactor that masks the given string fields of a Record, e.g.
+// "Caller" or "WorkflowID".
+func NewMaskerRedactor(fieldNames []string) Redactor {
+	if len(fieldNames) == 0 {
+		return nil
+	}
+	return func(record *Record) *Record {
+		return masker.MaskStruct(record, fieldNames).(*Record)
+	}
+}
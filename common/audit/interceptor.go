@@ -0,0 +1,214 @@
+// The MIT License
+//
+// Copyright (c) 2020 Temporal Technologies Inc.  All rights reserved.
+//
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package audit
+
+import (
+	"context"
+	"errors"
+	"math/rand"
+	"strings"
+	"time"
+
+	commonpb "go.temporal.io/api/common/v1"
+	"go.temporal.io/api/serviceerror"
+	"google.golang.org/grpc"
+
+	"go.temporal.io/server/common/api"
+	"go.temporal.io/server/common/authorization"
+	"go.temporal.io/server/common/clock"
+	"go.temporal.io/server/common/config"
+	"go.temporal.io/server/common/log"
+	"go.temporal.io/server/common/log/tag"
+	"go.temporal.io/server/common/metrics"
+)
+
+type (
+	// Interceptor writes an audit record for every mutating frontend API call and for a sample
+	// of read-only calls, long polls and activity heartbeats. It must run before the namespace
+	// validation and redirection interceptors in the chain to record the calls they reject or
+	// forward, and before the authorization interceptor to see the authorization decision.
+	// Written and dropped records are counted by the Sink created by NewAsyncSink.
+	Interceptor struct {
+		sink           Sink
+		redactor       Redactor
+		readSampleRate float64
+		excludeAPIs    map[string]struct{}
+		metricsHandler metrics.Handler
+		logger         log.Logger
+		timeSource     clock.TimeSource
+		sample         func() float64
+	}
+
+	hasNamespace interface {
+		GetNamespace() string
+	}
+	hasIdentity interface {
+		GetIdentity() string
+	}
+	hasWorkflowID interface {
+		GetWorkflowId() string
+	}
+	hasRunID interface {
+		GetRunId() string
+	}
+	hasExecution interface {
+		GetExecution() *commonpb.WorkflowExecution
+	}
+	hasWorkflowExecution interface {
+		GetWorkflowExecution() *commonpb.WorkflowExecution
+	}
+)
+
+// NewInterceptor creates an audit Interceptor that writes to sink.
+func NewInterceptor(
+	sink Sink,
+	cfg *config.Audit,
+	metricsHandler metrics.Handler,
+	logger log.Logger,
+) *Interceptor {
+	excludeAPIs := make(map[string]struct{}, len(cfg.ExcludeAPIs))
+	for _, name := range cfg.ExcludeAPIs {
+		excludeAPIs[api.MethodName(name)] = struct{}{}
+	}
+	return &Interceptor{
+		sink:           sink,
+		redactor:       NewMaskerRedactor(cfg.RedactFields),
+		readSampleRate: cfg.ReadSampleRate,
+		excludeAPIs:    excludeAPIs,
+		metricsHandler: metricsHandler.WithTags(metrics.OperationTag(metrics.AuditScope)),
+		logger:         logger,
+		timeSource:     clock.NewRealTimeSource(),
+		sample:         rand.Float64,
+	}
+}
+
+// Intercept a grpc request
+func (i *Interceptor) Intercept(
+	ctx context.Context,
+	req interface{},
+	info *grpc.UnaryServerInfo,
+	handler grpc.UnaryHandler,
+) (interface{}, error) {
+	if _, ok := i.excludeAPIs[api.MethodName(info.FullMethod)]; ok {
+		return handler(ctx, req)
+	}
+	metadata := api.GetMethodMetadata(info.FullMethod)
+	readOnly := metadata.Access == api.AccessReadOnly || isWorkerTraffic(info.FullMethod)
+	if metadata.Access == api.AccessUnknown || (readOnly && !i.sampled()) {
+		return handler(ctx, req)
+	}
+
+	ctx, trace := authorization.WithAuthorizationTrace(ctx)
+	startTime := i.timeSource.Now().UTC()
+	resp, err := handler(ctx, req)
+
+	record := newRecord(startTime, info.FullMethod, req, trace, err)
+	record.ReadOnly = readOnly
+	i.write(record)
+	return resp, err
+}
+
+// isWorkerTraffic returns true for long polls and activity heartbeats. Workers call them all the
+// time and they don't change workflows on behalf of a user, so they are sampled like read-only
+// calls instead of flooding the audit log.
+func isWorkerTraffic(fullMethod string) bool {
+	methodName := api.MethodName(fullMethod)
+	return strings.HasPrefix(methodName, "Poll") || strings.HasPrefix(methodName, "RecordActivityTaskHeartbeat")
+}
+
+func (i *Interceptor) sampled() bool {
+	return i.readSampleRate > 0 && i.sample() < i.readSampleRate
+}
+
+func (i *Interceptor) write(record *Record) {
+	if i.redactor != nil {
+		record = i.redactor(record)
+	}
+	if err := i.sink.Write(record); err != nil && !errors.Is(err, ErrRecordDropped) {
+		i.metricsHandler.Counter(metrics.AuditSinkErrorCount.Name()).Record(1, namespaceTag(record))
+		i.logger.Error("Unable to write audit record", tag.Error(err), tag.Operation(record.API))
+	}
+}
+
+func namespaceTag(record *Record) metrics.Tag {
+	if record.Namespace == "" {
+		return metrics.NamespaceUnknownTag()
+	}
+	return metrics.NamespaceTag(record.Namespace)
+}
+
+func newRecord(
+	startTime time.Time,
+	fullMethod string,
+	req interface{},
+	trace *authorization.AuthorizationTrace,
+	err error,
+) *Record {
+	record := &Record{
+		Time:    startTime,
+		API:     fullMethod,
+		Outcome: serviceerror.ToStatus(err).Code().String(),
+	}
+	if err != nil {
+		record.Error = err.Error()
+	}
+	if trace.Claims != nil {
+		record.Caller = trace.Claims.Subject
+	}
+	if trace.Result != nil {
+		switch trace.Result.Decision {
+		case authorization.DecisionAllow:
+			record.Decision = DecisionAllow
+		case authorization.DecisionDeny:
+			record.Decision = DecisionDeny
+		}
+		record.Reason = trace.Result.Reason
+	}
+
+	if r, ok := req.(hasNamespace); ok {
+		record.Namespace = r.GetNamespace()
+	}
+	if r, ok := req.(hasIdentity); ok {
+		record.Identity = r.GetIdentity()
+	}
+	var execution *commonpb.WorkflowExecution
+	switch r := req.(type) {
+	case hasExecution:
+		execution = r.GetExecution()
+	case hasWorkflowExecution:
+		execution = r.GetWorkflowExecution()
+	}
+	if execution != nil {
+		record.WorkflowID = execution.GetWorkflowId()
+		record.RunID = execution.GetRunId()
+	}
+	if r, ok := req.(hasWorkflowID); ok && record.WorkflowID == "" {
+		record.WorkflowID = r.GetWorkflowId()
+	}
+	if r, ok := req.(hasRunID); ok && record.RunID == "" {
+		record.RunID = r.GetRunId()
+	}
+	return record
+}
@@ -0,0 +1,282 @@
+// The MIT License
+//
+// Copyright (c) 2020 Temporal Technologies Inc.  All rights reserved.
+//
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package audit
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+	commonpb "go.temporal.io/api/common/v1"
+	"go.temporal.io/api/serviceerror"
+	"go.temporal.io/api/workflowservice/v1"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+
+	"go.temporal.io/server/api/adminservice/v1"
+	"go.temporal.io/server/common/authorization"
+	"go.temporal.io/server/common/clock"
+	"go.temporal.io/server/common/config"
+	"go.temporal.io/server/common/log"
+	"go.temporal.io/server/common/metrics"
+	"go.temporal.io/server/common/metrics/metricstest"
+)
+
+const (
+	startWorkflowAPI    = "/temporal.api.workflowservice.v1.WorkflowService/StartWorkflowExecution"
+	describeWorkflowAPI = "/temporal.api.workflowservice.v1.WorkflowService/DescribeWorkflowExecution"
+	pollWorkflowTaskAPI = "/temporal.api.workflowservice.v1.WorkflowService/PollWorkflowTaskQueue"
+	heartbeatAPI        = "/temporal.api.workflowservice.v1.WorkflowService/RecordActivityTaskHeartbeat"
+	describeMSAPI       = "/temporal.server.api.adminservice.v1.AdminService/DescribeMutableState"
+	healthCheckAPI      = "/grpc.health.v1.Health/Check"
+)
+
+type (
+	interceptorSuite struct {
+		suite.Suite
+		*require.Assertions
+
+		controller     *gomock.Controller
+		claimMapper    *authorization.MockClaimMapper
+		authorizer     *authorization.MockAuthorizer
+		sink           *memorySink
+		metricsHandler *metricstest.CaptureHandler
+		capture        *metricstest.Capture
+		timeSource     *clock.EventTimeSource
+		config         *config.Audit
+	}
+
+	memorySink struct {
+		lock    sync.Mutex
+		records []*Record
+		err     error
+	}
+)
+
+func TestInterceptorSuite(t *testing.T) {
+	s := new(interceptorSuite)
+	suite.Run(t, s)
+}
+
+func (s *interceptorSuite) SetupTest() {
+	s.Assertions = require.New(s.T())
+	s.controller = gomock.NewController(s.T())
+	s.claimMapper = authorization.NewMockClaimMapper(s.controller)
+	s.authorizer = authorization.NewMockAuthorizer(s.controller)
+	s.sink = &memorySink{}
+	s.metricsHandler = metricstest.NewCaptureHandler()
+	s.capture = s.metricsHandler.StartCapture()
+	s.timeSource = clock.NewEventTimeSource().Update(time.Date(2023, 1, 2, 3, 4, 5, 0, time.UTC))
+	s.config = &config.Audit{}
+}
+
+func (s *interceptorSuite) TearDownTest() {
+	s.controller.Finish()
+}
+
+// call runs req through the audit interceptor followed by the authorization interceptor
+func (s *interceptorSuite) call(fullMethod string, req interface{}, handlerErr error) error {
+	auditInterceptor := NewInterceptor(s.sink, s.config, s.metricsHandler, log.NewNoopLogger())
+	auditInterceptor.timeSource = s.timeSource
+	auditInterceptor.sample = func() float64 { return 0.5 }
+	authInterceptor := authorization.NewAuthorizationInterceptor(
+		s.claimMapper,
+		s.authorizer,
+		metrics.NoopMetricsHandler,
+		log.NewNoopLogger(),
+		nil,
+		"",
+		"",
+	)
+
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs("authorization", "Bearer token"))
+	info := &grpc.UnaryServerInfo{FullMethod: fullMethod}
+	_, err := auditInterceptor.Intercept(ctx, req, info, func(ctx context.Context, req interface{}) (interface{}, error) {
+		return authInterceptor(ctx, req, info, func(ctx context.Context, req interface{}) (interface{}, error) {
+			return nil, handlerErr
+		})
+	})
+	return err
+}
+
+func (s *interceptorSuite) expectAuthorization(decision authorization.Decision, reason string) {
+	s.claimMapper.EXPECT().GetClaims(gomock.Any()).Return(&authorization.Claims{Subject: "alice"}, nil)
+	s.authorizer.EXPECT().Authorize(gomock.Any(), gomock.Any(), gomock.Any()).
+		Return(authorization.Result{Decision: decision, Reason: reason}, nil)
+}
+
+func (s *interceptorSuite) TestMutatingCallAllowed() {
+	s.expectAuthorization(authorization.DecisionAllow, "")
+	err := s.call(startWorkflowAPI, &workflowservice.StartWorkflowExecutionRequest{
+		Namespace:  "test-namespace",
+		WorkflowId: "wid",
+		Identity:   "worker@host",
+	}, nil)
+	s.NoError(err)
+
+	s.Equal([]*Record{{
+		Time:       s.timeSource.Now(),
+		Caller:     "alice",
+		Identity:   "worker@host",
+		Namespace:  "test-namespace",
+		API:        startWorkflowAPI,
+		WorkflowID: "wid",
+		Decision:   DecisionAllow,
+		Outcome:    "OK",
+	}}, s.sink.records)
+}
+
+func (s *interceptorSuite) TestMutatingCallDenied() {
+	s.expectAuthorization(authorization.DecisionDeny, "not allowed")
+	err := s.call(startWorkflowAPI, &workflowservice.StartWorkflowExecutionRequest{
+		Namespace:  "test-namespace",
+		WorkflowId: "wid",
+	}, nil)
+	s.Error(err)
+
+	s.Len(s.sink.records, 1)
+	record := s.sink.records[0]
+	s.Equal("alice", record.Caller)
+	s.Equal(DecisionDeny, record.Decision)
+	s.Equal("not allowed", record.Reason)
+	s.Equal("PermissionDenied", record.Outcome)
+	s.NotEmpty(record.Error)
+}
+
+func (s *interceptorSuite) TestHandlerError() {
+	s.expectAuthorization(authorization.DecisionAllow, "")
+	err := s.call(describeMSAPI, &adminservice.DescribeMutableStateRequest{
+		Namespace: "test-namespace",
+		Execution: &commonpb.WorkflowExecution{WorkflowId: "wid", RunId: "rid"},
+	}, serviceerror.NewNotFound("workflow not found"))
+	s.Error(err)
+
+	s.Len(s.sink.records, 1)
+	record := s.sink.records[0]
+	s.Equal("wid", record.WorkflowID)
+	s.Equal("rid", record.RunID)
+	s.Equal(DecisionAllow, record.Decision)
+	s.Equal("NotFound", record.Outcome)
+	s.Equal("workflow not found", record.Error)
+	s.False(record.ReadOnly)
+}
+
+func (s *interceptorSuite) TestReadSampling() {
+	req := &workflowservice.DescribeWorkflowExecutionRequest{
+		Namespace: "test-namespace",
+		Execution: &commonpb.WorkflowExecution{WorkflowId: "wid"},
+	}
+
+	s.claimMapper.EXPECT().GetClaims(gomock.Any()).Return(&authorization.Claims{}, nil).Times(2)
+	s.authorizer.EXPECT().Authorize(gomock.Any(), gomock.Any(), gomock.Any()).
+		Return(authorization.Result{Decision: authorization.DecisionAllow}, nil).Times(2)
+
+	s.config.ReadSampleRate = 0.4
+	s.NoError(s.call(describeWorkflowAPI, req, nil))
+	s.Empty(s.sink.records)
+
+	s.config.ReadSampleRate = 0.6
+	s.NoError(s.call(describeWorkflowAPI, req, nil))
+	s.Len(s.sink.records, 1)
+	s.True(s.sink.records[0].ReadOnly)
+	s.Equal("wid", s.sink.records[0].WorkflowID)
+}
+
+func (s *interceptorSuite) TestWorkerTrafficSampled() {
+	s.claimMapper.EXPECT().GetClaims(gomock.Any()).Return(&authorization.Claims{}, nil).Times(4)
+	s.authorizer.EXPECT().Authorize(gomock.Any(), gomock.Any(), gomock.Any()).
+		Return(authorization.Result{Decision: authorization.DecisionAllow}, nil).Times(4)
+
+	s.config.ReadSampleRate = 0.4
+	s.NoError(s.call(pollWorkflowTaskAPI, &workflowservice.PollWorkflowTaskQueueRequest{}, nil))
+	s.NoError(s.call(heartbeatAPI, &workflowservice.RecordActivityTaskHeartbeatRequest{}, nil))
+	s.Empty(s.sink.records)
+
+	s.config.ReadSampleRate = 0.6
+	s.NoError(s.call(pollWorkflowTaskAPI, &workflowservice.PollWorkflowTaskQueueRequest{}, nil))
+	s.NoError(s.call(heartbeatAPI, &workflowservice.RecordActivityTaskHeartbeatRequest{}, nil))
+	s.Len(s.sink.records, 2)
+	s.True(s.sink.records[0].ReadOnly)
+	s.True(s.sink.records[1].ReadOnly)
+}
+
+func (s *interceptorSuite) TestNotRecorded() {
+	s.claimMapper.EXPECT().GetClaims(gomock.Any()).Return(&authorization.Claims{}, nil).Times(2)
+	s.authorizer.EXPECT().Authorize(gomock.Any(), gomock.Any(), gomock.Any()).
+		Return(authorization.Result{Decision: authorization.DecisionAllow}, nil).Times(2)
+
+	s.config.ExcludeAPIs = []string{"PollWorkflowTaskQueue"}
+	s.NoError(s.call(pollWorkflowTaskAPI, &workflowservice.PollWorkflowTaskQueueRequest{}, nil))
+	s.NoError(s.call(healthCheckAPI, nil, nil))
+	s.Empty(s.sink.records)
+}
+
+func (s *interceptorSuite) TestRedaction() {
+	s.expectAuthorization(authorization.DecisionAllow, "")
+	s.config.RedactFields = []string{"Caller", "WorkflowID"}
+	s.NoError(s.call(startWorkflowAPI, &workflowservice.StartWorkflowExecutionRequest{
+		Namespace:  "test-namespace",
+		WorkflowId: "wid",
+	}, nil))
+
+	s.Len(s.sink.records, 1)
+	s.Equal("******", s.sink.records[0].Caller)
+	s.Equal("******", s.sink.records[0].WorkflowID)
+	s.Equal("test-namespace", s.sink.records[0].Namespace)
+}
+
+func (s *interceptorSuite) TestSinkError() {
+	s.expectAuthorization(authorization.DecisionAllow, "")
+	s.sink.err = errors.New("disk full")
+	s.NoError(s.call(startWorkflowAPI, &workflowservice.StartWorkflowExecutionRequest{}, nil))
+	s.Len(s.capture.Snapshot()[metrics.AuditSinkErrorCount.Name()], 1)
+}
+
+func (s *interceptorSuite) TestRecordDropped() {
+	s.expectAuthorization(authorization.DecisionAllow, "")
+	s.sink.err = ErrRecordDropped
+	s.NoError(s.call(startWorkflowAPI, &workflowservice.StartWorkflowExecutionRequest{}, nil))
+	// the sink counts dropped records
+	s.Empty(s.capture.Snapshot()[metrics.AuditSinkErrorCount.Name()])
+}
+
+func (s *memorySink) Write(record *Record) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	if s.err != nil {
+		return s.err
+	}
+	s.records = append(s.records, record)
+	return nil
+}
+
+func (s *memorySink) Close() error {
+	return nil
+}
@@ -0,0 +1,317 @@
+// The MIT License
+//
+// Copyright (c) 2020 Temporal Technologies Inc.  All rights reserved.
+//
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package audit
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+
+	"go.uber.org/multierr"
+
+	"go.temporal.io/server/common/config"
+	"go.temporal.io/server/common/log"
+	"go.temporal.io/server/common/log/tag"
+	"go.temporal.io/server/common/metrics"
+)
+
+const (
+	defaultMaxBackups = 5
+	defaultQueueSize  = 10000
+
+	fileMode = 0640
+)
+
+type (
+	// writerSink writes records as JSON lines to an io.Writer
+	writerSink struct {
+		lock   sync.Mutex
+		writer io.Writer
+	}
+
+	// fileSink writes records as JSON lines to a file and rotates it when it reaches maxSize,
+	// keeping maxBackups rotated files named path.1 (newest) to path.<maxBackups> (oldest)
+	fileSink struct {
+		lock       sync.Mutex
+		path       string
+		maxSize    int64
+		maxBackups int
+		file       *os.File
+		size       int64
+	}
+
+	multiSink []Sink
+
+	// asyncSink queues records and writes them to sink from a single goroutine, so that API
+	// calls don't wait for the sink. Records that don't fit in the queue are dropped.
+	asyncSink struct {
+		sink           Sink
+		queue          chan *Record
+		metricsHandler metrics.Handler
+		logger         log.Logger
+		done           chan struct{}
+
+		lock   sync.RWMutex
+		closed bool
+	}
+)
+
+// ErrRecordDropped is returned by the Sink created by NewAsyncSink when its queue is full.
+var ErrRecordDropped = errors.New("audit record dropped because the audit queue is full")
+
+var _ Sink = (*writerSink)(nil)
+var _ Sink = (*fileSink)(nil)
+var _ Sink = (multiSink)(nil)
+var _ Sink = (*asyncSink)(nil)
+
+// NewSinkFromConfig creates a Sink that writes to all configured sinks. It returns nil if there
+// are no sinks configured.
+func NewSinkFromConfig(cfgs []config.AuditSink) (Sink, error) {
+	var sinks multiSink
+	for _, cfg := range cfgs {
+		var sink Sink
+		switch strings.ToLower(cfg.Type) {
+		case "stdout":
+			sink = NewStdoutSink()
+		case "file":
+			var err error
+			sink, err = NewFileSink(cfg.Path, cfg.MaxSizeMB, cfg.MaxBackups)
+			if err != nil {
+				return nil, multierr.Append(err, sinks.Close())
+			}
+		default:
+			return nil, multierr.Append(fmt.Errorf("unknown audit sink type: %q", cfg.Type), sinks.Close())
+		}
+		sinks = append(sinks, sink)
+	}
+	switch len(sinks) {
+	case 0:
+		return nil, nil
+	case 1:
+		return sinks[0], nil
+	}
+	return sinks, nil
+}
+
+// NewMultiSink creates a Sink that writes every record to all sinks.
+func NewMultiSink(sinks ...Sink) Sink {
+	return multiSink(sinks)
+}
+
+// NewStdoutSink creates a Sink that writes JSON lines to stdout.
+func NewStdoutSink() Sink {
+	return &writerSink{writer: os.Stdout}
+}
+
+func (s *writerSink) Write(record *Record) error {
+	line, err := marshalRecord(record)
+	if err != nil {
+		return err
+	}
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	_, err = s.writer.Write(line)
+	return err
+}
+
+func (s *writerSink) Close() error {
+	return nil
+}
+
+// NewFileSink creates a Sink that appends JSON lines to the file at path, rotating it once it
+// grows over maxSizeMB megabytes. maxSizeMB 0 disables rotation.
+func NewFileSink(path string, maxSizeMB int, maxBackups int) (Sink, error) {
+	if path == "" {
+		return nil, fmt.Errorf("audit file sink requires a path")
+	}
+	if maxBackups <= 0 {
+		maxBackups = defaultMaxBackups
+	}
+	s := &fileSink{
+		path:       path,
+		maxSize:    int64(maxSizeMB) * 1024 * 1024,
+		maxBackups: maxBackups,
+	}
+	if err := s.open(); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+func (s *fileSink) Write(record *Record) error {
+	line, err := marshalRecord(record)
+	if err != nil {
+		return err
+	}
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if s.file == nil {
+		return fmt.Errorf("audit file sink %s is closed", s.path)
+	}
+	if s.maxSize > 0 && s.size > 0 && s.size+int64(len(line)) > s.maxSize {
+		if err := s.rotate(); err != nil {
+			return err
+		}
+	}
+	n, err := s.file.Write(line)
+	s.size += int64(n)
+	return err
+}
+
+func (s *fileSink) Close() error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if s.file == nil {
+		return nil
+	}
+	err := s.file.Close()
+	s.file = nil
+	return err
+}
+
+func (s *fileSink) open() error {
+	file, err := os.OpenFile(s.path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, fileMode)
+	if err != nil {
+		return err
+	}
+	info, err := file.Stat()
+	if err != nil {
+		return multierr.Append(err, file.Close())
+	}
+	s.file = file
+	s.size = info.Size()
+	return nil
+}
+
+func (s *fileSink) rotate() error {
+	if err := s.file.Close(); err != nil {
+		return err
+	}
+	s.file = nil
+
+	if err := os.Remove(s.backupPath(s.maxBackups)); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	for i := s.maxBackups - 1; i >= 1; i-- {
+		if err := os.Rename(s.backupPath(i), s.backupPath(i+1)); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+	}
+	if err := os.Rename(s.path, s.backupPath(1)); err != nil {
+		return err
+	}
+	return s.open()
+}
+
+func (s *fileSink) backupPath(i int) string {
+	return fmt.Sprintf("%s.%d", s.path, i)
+}
+
+func (s multiSink) Write(record *Record) error {
+	var errs error
+	for _, sink := range s {
+		errs = multierr.Append(errs, sink.Write(record))
+	}
+	return errs
+}
+
+func (s multiSink) Close() error {
+	var errs error
+	for _, sink := range s {
+		errs = multierr.Append(errs, sink.Close())
+	}
+	return errs
+}
+
+// NewAsyncSink creates a Sink that queues up to queueSize records, or a default number if it is 0,
+// and writes them to sink in the background. Write returns ErrRecordDropped when the queue is
+// full. Written and dropped records and errors of sink are counted, errors are also logged.
+// Close returns once the queued records are written, it doesn't close sink.
+func NewAsyncSink(sink Sink, queueSize int, metricsHandler metrics.Handler, logger log.Logger) Sink {
+	if queueSize <= 0 {
+		queueSize = defaultQueueSize
+	}
+	s := &asyncSink{
+		sink:           sink,
+		queue:          make(chan *Record, queueSize),
+		metricsHandler: metricsHandler.WithTags(metrics.OperationTag(metrics.AuditScope)),
+		logger:         logger,
+		done:           make(chan struct{}),
+	}
+	go s.run()
+	return s
+}
+
+func (s *asyncSink) Write(record *Record) error {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+	if s.closed {
+		return errors.New("audit sink is closed")
+	}
+	select {
+	case s.queue <- record:
+		return nil
+	default:
+		s.metricsHandler.Counter(metrics.AuditRecordDroppedCount.Name()).Record(1, namespaceTag(record))
+		return ErrRecordDropped
+	}
+}
+
+func (s *asyncSink) Close() error {
+	s.lock.Lock()
+	if !s.closed {
+		s.closed = true
+		close(s.queue)
+	}
+	s.lock.Unlock()
+	<-s.done
+	return nil
+}
+
+func (s *asyncSink) run() {
+	defer close(s.done)
+	for record := range s.queue {
+		if err := s.sink.Write(record); err != nil {
+			s.metricsHandler.Counter(metrics.AuditSinkErrorCount.Name()).Record(1, namespaceTag(record))
+			s.logger.Error("Unable to write audit record", tag.Error(err), tag.Operation(record.API))
+			continue
+		}
+		s.metricsHandler.Counter(metrics.AuditRecordCount.Name()).Record(1, namespaceTag(record))
+	}
+}
+
+func marshalRecord(record *Record) ([]byte, error) {
+	line, err := json.Marshal(record)
+	if err != nil {
+		return nil, err
+	}
+	return append(line, '\n'), nil
+}
@@ -0,0 +1,162 @@
+// The MIT License
+//
+// Copyright (c) 2020 Temporal Technologies Inc.  All rights reserved.
+//
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package audit
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"go.temporal.io/server/common/config"
+	"go.temporal.io/server/common/log"
+	"go.temporal.io/server/common/metrics"
+	"go.temporal.io/server/common/metrics/metricstest"
+)
+
+func TestFileSink_Rotation(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "audit.log")
+	sink, err := NewFileSink(path, 1, 2)
+	require.NoError(t, err)
+	// a tiny max size makes every record rotate the file
+	sink.(*fileSink).maxSize = 10
+
+	for _, api := range []string{"a", "b", "c", "d"} {
+		require.NoError(t, sink.Write(&Record{API: api, Outcome: "OK"}))
+	}
+	require.NoError(t, sink.Close())
+
+	require.Equal(t, []string{"d"}, readAPIs(t, path))
+	require.Equal(t, []string{"c"}, readAPIs(t, path+".1"))
+	require.Equal(t, []string{"b"}, readAPIs(t, path+".2"))
+	_, err = os.Stat(path + ".3")
+	require.True(t, os.IsNotExist(err))
+
+	require.Error(t, sink.Write(&Record{API: "e"}))
+}
+
+func TestFileSink_Append(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "audit.log")
+	for _, api := range []string{"a", "b"} {
+		sink, err := NewFileSink(path, 0, 0)
+		require.NoError(t, err)
+		require.NoError(t, sink.Write(&Record{API: api, Outcome: "OK"}))
+		require.NoError(t, sink.Close())
+	}
+	require.Equal(t, []string{"a", "b"}, readAPIs(t, path))
+}
+
+func TestNewSinkFromConfig(t *testing.T) {
+	sink, err := NewSinkFromConfig(nil)
+	require.NoError(t, err)
+	require.Nil(t, sink)
+
+	path := filepath.Join(t.TempDir(), "audit.log")
+	sink, err = NewSinkFromConfig([]config.AuditSink{{Type: "stdout"}, {Type: "file", Path: path}})
+	require.NoError(t, err)
+	require.IsType(t, multiSink{}, sink)
+	require.NoError(t, sink.Close())
+
+	_, err = NewSinkFromConfig([]config.AuditSink{{Type: "syslog"}})
+	require.Error(t, err)
+	_, err = NewSinkFromConfig([]config.AuditSink{{Type: "file"}})
+	require.Error(t, err)
+}
+
+// blockingSink blocks writes until unblock is closed
+type blockingSink struct {
+	memorySink
+	unblock chan struct{}
+}
+
+func (s *blockingSink) Write(record *Record) error {
+	<-s.unblock
+	return s.memorySink.Write(record)
+}
+
+func TestAsyncSink(t *testing.T) {
+	inner := &blockingSink{unblock: make(chan struct{})}
+	metricsHandler := metricstest.NewCaptureHandler()
+	capture := metricsHandler.StartCapture()
+	sink := NewAsyncSink(inner, 1, metricsHandler, log.NewNoopLogger())
+
+	// the first record is taken by the writer, the second one waits in the queue
+	require.NoError(t, sink.Write(&Record{API: "a"}))
+	require.Eventually(t, func() bool {
+		return sink.Write(&Record{API: "b"}) == nil
+	}, time.Second, time.Millisecond)
+	require.ErrorIs(t, sink.Write(&Record{API: "c"}), ErrRecordDropped)
+
+	close(inner.unblock)
+	require.NoError(t, sink.Close())
+	require.Equal(t, []string{"a", "b"}, recordAPIs(inner.records))
+	require.Error(t, sink.Write(&Record{API: "d"}))
+	require.Len(t, capture.Snapshot()[metrics.AuditRecordCount.Name()], 2)
+	require.Len(t, capture.Snapshot()[metrics.AuditRecordDroppedCount.Name()], 1)
+	require.Empty(t, capture.Snapshot()[metrics.AuditSinkErrorCount.Name()])
+}
+
+func TestAsyncSink_Error(t *testing.T) {
+	metricsHandler := metricstest.NewCaptureHandler()
+	capture := metricsHandler.StartCapture()
+	sink := NewAsyncSink(&memorySink{err: errors.New("disk full")}, 0, metricsHandler, log.NewNoopLogger())
+
+	require.NoError(t, sink.Write(&Record{API: "a", Namespace: "test-namespace"}))
+	require.NoError(t, sink.Close())
+	recordings := capture.Snapshot()[metrics.AuditSinkErrorCount.Name()]
+	require.Len(t, recordings, 1)
+	require.Equal(t, "test-namespace", recordings[0].Tags["namespace"])
+	require.Empty(t, capture.Snapshot()[metrics.AuditRecordCount.Name()])
+}
+
+func recordAPIs(records []*Record) []string {
+	apis := make([]string, len(records))
+	for i, record := range records {
+		apis[i] = record.API
+	}
+	return apis
+}
+
+func readAPIs(t *testing.T, path string) []string {
+	file, err := os.Open(path)
+	require.NoError(t, err)
+	defer file.Close()
+
+	var apis []string
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		var record Record
+		require.NoError(t, json.NewDecoder(strings.NewReader(scanner.Text())).Decode(&record))
+		apis = append(apis, record.API)
+	}
+	require.NoError(t, scanner.Err())
+	return apis
+}
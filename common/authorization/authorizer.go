@@ -87,6 +87,16 @@ func GetAuthorizerFromConfig(config *config.Authorization) (Authorizer, error) {
 		return NewNoopAuthorizer(), nil
 	case "default":
 		return NewDefaultAuthorizer(), nil
+	case "policy":
+		policy, err := LoadAuthorizationPolicy(config.PolicyFile)
+		if err != nil {
+			return nil, err
+		}
+		authorizer, err := NewPolicyAuthorizer(policy)
+		if err != nil {
+			return nil, err
+		}
+		return authorizer, nil
 	}
 	return nil, fmt.Errorf("unknown authorizer: %s", config.Authorizer)
 }
@@ -35,6 +35,7 @@ import (
 
 	"go.temporal.io/server/common/config"
 	"go.temporal.io/server/common/log"
+	"go.temporal.io/server/common/metrics"
 )
 
 // @@@SNIPSTART temporal-common-authorization-authinfo
@@ -84,12 +85,38 @@ func (*noopClaimMapper) AuthInfoRequired() bool {
 }
 
 func GetClaimMapperFromConfig(config *config.Authorization, logger log.Logger) (ClaimMapper, error) {
+	return GetClaimMapperFromConfigWithMetrics(config, logger, metrics.NoopMetricsHandler)
+}
+
+// GetClaimMapperFromConfigWithMetrics is like GetClaimMapperFromConfig, but the token key
+// provider reports key source health to metricsHandler.
+func GetClaimMapperFromConfigWithMetrics(config *config.Authorization, logger log.Logger, metricsHandler metrics.Handler) (ClaimMapper, error) {
 
 	switch strings.ToLower(config.ClaimMapper) {
 	case "":
 		return NewNoopClaimMapper(), nil
 	case "default":
-		return NewDefaultJWTClaimMapper(NewDefaultTokenKeyProvider(config, logger), config, logger), nil
+		keyProvider, err := GetTokenKeyProviderFromConfig(config, logger, metricsHandler)
+		if err != nil {
+			return nil, err
+		}
+		return NewDefaultJWTClaimMapper(keyProvider, config, logger), nil
+	case "configurable":
+		keyProvider, err := GetTokenKeyProviderFromConfig(config, logger, metricsHandler)
+		if err != nil {
+			return nil, err
+		}
+		return NewConfigurableJWTClaimMapper(keyProvider, &config.ClaimMapping, logger)
 	}
 	return nil, fmt.Errorf("unknown claim mapper: %s", config.ClaimMapper)
 }
+
+func GetTokenKeyProviderFromConfig(config *config.Authorization, logger log.Logger, metricsHandler metrics.Handler) (TokenKeyProvider, error) {
+	switch strings.ToLower(config.JWTKeyProvider.Type) {
+	case "", "default":
+		return NewDefaultTokenKeyProvider(config, logger), nil
+	case "oidc":
+		return NewOIDCTokenKeyProvider(config, logger, metricsHandler), nil
+	}
+	return nil, fmt.Errorf("unknown token key provider: %s", config.JWTKeyProvider.Type)
+}
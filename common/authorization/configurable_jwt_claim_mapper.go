@@ -0,0 +1,294 @@
+// The MIT License
+//
+// Copyright (c) 2020 Temporal Technologies Inc.  All rights reserved.
+//
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package authorization
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"go.temporal.io/api/serviceerror"
+
+	"go.temporal.io/server/common/config"
+	"go.temporal.io/server/common/log"
+)
+
+type (
+	// Claim mapper that reads subject, groups and permissions from configurable claim paths and
+	// maps groups to roles according to config.ClaimMapping
+	configurableJWTClaimMapper struct {
+		keyProvider       TokenKeyProvider
+		logger            log.Logger
+		subjectPath       claimPath
+		groupPaths        []claimPath
+		permissionPaths   []claimPath
+		systemAdminGroups map[string]struct{}
+		defaultRole       Role
+		groupRoles        []groupRoleRule
+	}
+
+	groupRoleRule struct {
+		group      string
+		groupRegex *regexp.Regexp
+		namespace  string
+		role       Role
+	}
+
+	// claimPath is a parsed claim path such as "realm_access.roles", one element per nested key
+	claimPath []string
+)
+
+var _ ClaimMapper = (*configurableJWTClaimMapper)(nil)
+
+func NewConfigurableJWTClaimMapper(provider TokenKeyProvider, cfg *config.ClaimMapping, logger log.Logger) (ClaimMapper, error) {
+	subjectClaim := cfg.SubjectClaim
+	if subjectClaim == "" {
+		subjectClaim = headerSubject
+	}
+	subjectPath, err := parseClaimPath(subjectClaim)
+	if err != nil {
+		return nil, err
+	}
+	groupClaims := cfg.GroupClaims
+	if len(groupClaims) == 0 {
+		groupClaims = []string{defaultGroupsClaimName}
+	}
+	groupPaths, err := parseClaimPaths(groupClaims)
+	if err != nil {
+		return nil, err
+	}
+	permissionPaths, err := parseClaimPaths(cfg.PermissionClaims)
+	if err != nil {
+		return nil, err
+	}
+
+	var defaultRole Role
+	if cfg.DefaultRole != "" {
+		if defaultRole = permissionToRole(cfg.DefaultRole); defaultRole == RoleUndefined {
+			return nil, fmt.Errorf("claim mapping: unknown default role %q", cfg.DefaultRole)
+		}
+	}
+
+	systemAdminGroups := make(map[string]struct{}, len(cfg.SystemAdminGroups))
+	for _, group := range cfg.SystemAdminGroups {
+		systemAdminGroups[group] = struct{}{}
+	}
+
+	groupRoles := make([]groupRoleRule, 0, len(cfg.GroupRoles))
+	for i, r := range cfg.GroupRoles {
+		rule, err := newGroupRoleRule(r)
+		if err != nil {
+			return nil, fmt.Errorf("claim mapping: group role %d: %w", i, err)
+		}
+		groupRoles = append(groupRoles, rule)
+	}
+
+	return &configurableJWTClaimMapper{
+		keyProvider:       provider,
+		logger:            logger,
+		subjectPath:       subjectPath,
+		groupPaths:        groupPaths,
+		permissionPaths:   permissionPaths,
+		systemAdminGroups: systemAdminGroups,
+		defaultRole:       defaultRole,
+		groupRoles:        groupRoles,
+	}, nil
+}
+
+func newGroupRoleRule(cfg config.ClaimMappingGroupRole) (groupRoleRule, error) {
+	rule := groupRoleRule{group: cfg.Group, namespace: cfg.Namespace}
+	switch {
+	case cfg.Group == "" && cfg.GroupRegex == "":
+		return rule, fmt.Errorf("either group or groupRegex is required")
+	case cfg.Group != "" && cfg.GroupRegex != "":
+		return rule, fmt.Errorf("only one of group and groupRegex can be set")
+	case cfg.GroupRegex != "":
+		re, err := regexp.Compile("^(?:" + cfg.GroupRegex + ")$")
+		if err != nil {
+			return rule, fmt.Errorf("invalid groupRegex %q: %w", cfg.GroupRegex, err)
+		}
+		rule.groupRegex = re
+	}
+	if cfg.Namespace == "" {
+		return rule, fmt.Errorf("namespace is required")
+	}
+	if len(cfg.Roles) == 0 {
+		return rule, fmt.Errorf("at least one role is required")
+	}
+	for _, r := range cfg.Roles {
+		role := permissionToRole(r)
+		if role == RoleUndefined {
+			return rule, fmt.Errorf("unknown role %q", r)
+		}
+		rule.role |= role
+	}
+	return rule, nil
+}
+
+func (a *configurableJWTClaimMapper) GetClaims(authInfo *AuthInfo) (*Claims, error) {
+
+	claims := Claims{}
+
+	if authInfo.AuthToken == "" {
+		return &claims, nil
+	}
+
+	jwtClaims, err := parseAuthToken(authInfo, a.keyProvider)
+	if err != nil {
+		return nil, err
+	}
+	subject, ok := a.subjectPath.lookup(jwtClaims).(string)
+	if !ok {
+		return nil, serviceerror.NewPermissionDenied(fmt.Sprintf("unexpected value type of %q claim", a.subjectPath), "")
+	}
+	claims.Subject = subject
+	claims.System = a.defaultRole
+
+	for _, path := range a.permissionPaths {
+		if permissions, ok := path.lookup(jwtClaims).([]interface{}); ok {
+			if err := extractPermissions(permissions, &claims, a.logger); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	claims.Groups = a.extractGroups(jwtClaims)
+	for _, group := range claims.Groups {
+		if _, ok := a.systemAdminGroups[group]; ok {
+			claims.System |= RoleAdmin
+		}
+		for _, rule := range a.groupRoles {
+			namespace, ok := rule.match(group)
+			if !ok {
+				continue
+			}
+			if namespace == permissionScopeSystem {
+				claims.System |= rule.role
+				continue
+			}
+			if claims.Namespaces == nil {
+				claims.Namespaces = make(map[string]Role)
+			}
+			claims.Namespaces[namespace] |= rule.role
+		}
+	}
+	return &claims, nil
+}
+
+// extractGroups collects group names from all group claims, without duplicates
+func (a *configurableJWTClaimMapper) extractGroups(jwtClaims map[string]interface{}) []string {
+	var groups []string
+	for _, path := range a.groupPaths {
+		groups = appendGroups(groups, path.lookup(jwtClaims), path.String(), a.logger)
+	}
+	return groups
+}
+
+// match returns the namespace the rule applies to if group matches the rule
+func (r *groupRoleRule) match(group string) (string, bool) {
+	if r.groupRegex == nil {
+		return r.namespace, group == r.group
+	}
+	submatches := r.groupRegex.FindStringSubmatchIndex(group)
+	if submatches == nil {
+		return "", false
+	}
+	return string(r.groupRegex.ExpandString(nil, r.namespace, group, submatches)), true
+}
+
+func parseClaimPaths(paths []string) ([]claimPath, error) {
+	result := make([]claimPath, 0, len(paths))
+	for _, p := range paths {
+		path, err := parseClaimPath(p)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, path)
+	}
+	return result, nil
+}
+
+// parseClaimPath parses paths like "groups", "$.realm_access.roles" or
+// "resource_access[\"temporal\"].roles" into the list of nested keys
+func parseClaimPath(path string) (claimPath, error) {
+	rest := strings.TrimPrefix(strings.TrimPrefix(path, "$"), ".")
+	if rest == "" {
+		return nil, fmt.Errorf("claim path %q: empty path", path)
+	}
+
+	var result claimPath
+	for len(rest) > 0 {
+		var key string
+		if rest[0] == '[' {
+			if len(rest) < 4 || (rest[1] != '"' && rest[1] != '\'') {
+				return nil, fmt.Errorf("claim path %q: expected quoted key after '['", path)
+			}
+			end := strings.Index(rest[2:], string(rest[1])+"]")
+			if end < 0 {
+				return nil, fmt.Errorf("claim path %q: unterminated '['", path)
+			}
+			key = rest[2 : 2+end]
+			rest = rest[2+end+2:]
+		} else {
+			end := strings.IndexAny(rest, ".[")
+			if end < 0 {
+				end = len(rest)
+			}
+			key = rest[:end]
+			rest = rest[end:]
+		}
+		if key == "" {
+			return nil, fmt.Errorf("claim path %q: empty key", path)
+		}
+		result = append(result, key)
+
+		if strings.HasPrefix(rest, ".") {
+			rest = rest[1:]
+			if rest == "" {
+				return nil, fmt.Errorf("claim path %q: trailing '.'", path)
+			}
+		} else if rest != "" && rest[0] != '[' {
+			return nil, fmt.Errorf("claim path %q: unexpected %q", path, rest)
+		}
+	}
+	return result, nil
+}
+
+// lookup returns the value at the path, or nil if there is none
+func (p claimPath) lookup(claims map[string]interface{}) interface{} {
+	var value interface{} = claims
+	for _, key := range p {
+		m, ok := value.(map[string]interface{})
+		if !ok {
+			return nil
+		}
+		value = m[key]
+	}
+	return value
+}
+
+func (p claimPath) String() string {
+	return strings.Join(p, ".")
+}
@@ -0,0 +1,238 @@
+// The MIT License
+//
+// Copyright (c) 2020 Temporal Technologies Inc.  All rights reserved.
+//
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package authorization
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v4"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+
+	"go.temporal.io/server/common/config"
+	"go.temporal.io/server/common/log"
+)
+
+type (
+	configurableClaimMapperSuite struct {
+		suite.Suite
+		*require.Assertions
+
+		tokenGenerator *tokenGenerator
+		config         *config.ClaimMapping
+	}
+)
+
+func TestConfigurableClaimMapperSuite(t *testing.T) {
+	s := new(configurableClaimMapperSuite)
+	suite.Run(t, s)
+}
+
+func (s *configurableClaimMapperSuite) SetupTest() {
+	s.Assertions = require.New(s.T())
+	s.tokenGenerator = newTokenGenerator()
+	s.config = &config.ClaimMapping{
+		SubjectClaim:      "$.preferred_username",
+		GroupClaims:       []string{"groups", "realm_access.roles", `resource_access["temporal.io"].roles`},
+		PermissionClaims:  []string{"permissions"},
+		SystemAdminGroups: []string{"temporal-admins"},
+		DefaultRole:       "read",
+		GroupRoles: []config.ClaimMappingGroupRole{
+			{Group: "payments-team", Namespace: "payments", Roles: []string{"write", "worker"}},
+			{GroupRegex: `ns-(.+)-admin`, Namespace: "$1", Roles: []string{"admin"}},
+			{Group: "operators", Namespace: permissionScopeSystem, Roles: []string{"write"}},
+		},
+	}
+}
+
+func (s *configurableClaimMapperSuite) newClaimMapper() ClaimMapper {
+	claimMapper, err := NewConfigurableJWTClaimMapper(s.tokenGenerator, s.config, log.NewNoopLogger())
+	s.NoError(err)
+	return claimMapper
+}
+
+func (s *configurableClaimMapperSuite) generateToken(claims jwt.MapClaims) string {
+	claims["exp"] = time.Now().Add(time.Hour).Unix()
+	token := jwt.NewWithClaims(jwt.SigningMethodRS256, claims)
+	token.Header["kid"] = "test-key"
+	tokenString, err := token.SignedString(s.tokenGenerator.rsaPrivateKey)
+	s.NoError(err)
+	return "Bearer " + tokenString
+}
+
+func (s *configurableClaimMapperSuite) TestGroupsFromNestedClaims() {
+	token := s.generateToken(jwt.MapClaims{
+		"preferred_username": "alice",
+		"groups":             []string{"payments-team", "ns-orders-admin"},
+		"realm_access":       map[string]interface{}{"roles": []string{"operators", "payments-team"}},
+		"resource_access":    map[string]interface{}{"temporal.io": map[string]interface{}{"roles": "ns-billing-admin"}},
+		"permissions":        []string{"default:read"},
+	})
+	claims, err := s.newClaimMapper().GetClaims(&AuthInfo{AuthToken: token})
+	s.NoError(err)
+	s.Equal("alice", claims.Subject)
+	s.Equal([]string{"payments-team", "ns-orders-admin", "operators", "ns-billing-admin"}, claims.Groups)
+	s.Equal(RoleReader|RoleWriter, claims.System)
+	s.Equal(map[string]Role{
+		"payments": RoleWriter | RoleWorker,
+		"orders":   RoleAdmin,
+		"billing":  RoleAdmin,
+		"default":  RoleReader,
+	}, claims.Namespaces)
+}
+
+func (s *configurableClaimMapperSuite) TestDefaultGroupClaim() {
+	s.config.GroupClaims = nil
+	token := s.generateToken(jwt.MapClaims{
+		"preferred_username": "alice",
+		"groups":             []string{"payments-team"},
+		"realm_access":       map[string]interface{}{"roles": []string{"operators"}},
+	})
+	claims, err := s.newClaimMapper().GetClaims(&AuthInfo{AuthToken: token})
+	s.NoError(err)
+	s.Equal([]string{"payments-team"}, claims.Groups)
+	s.Equal(map[string]Role{"payments": RoleWriter | RoleWorker}, claims.Namespaces)
+}
+
+func (s *configurableClaimMapperSuite) TestSystemAdminGroup() {
+	token := s.generateToken(jwt.MapClaims{
+		"preferred_username": "bob",
+		"groups":             []string{"temporal-admins"},
+	})
+	claims, err := s.newClaimMapper().GetClaims(&AuthInfo{AuthToken: token})
+	s.NoError(err)
+	s.Equal(RoleAdmin|RoleReader, claims.System)
+	s.Nil(claims.Namespaces)
+}
+
+func (s *configurableClaimMapperSuite) TestRegexMustMatchWholeGroup() {
+	token := s.generateToken(jwt.MapClaims{
+		"preferred_username": "carol",
+		"groups":             []string{"ns-orders-admin-readonly", "payments-team-2"},
+	})
+	claims, err := s.newClaimMapper().GetClaims(&AuthInfo{AuthToken: token})
+	s.NoError(err)
+	s.Equal(RoleReader, claims.System)
+	s.Nil(claims.Namespaces)
+}
+
+func (s *configurableClaimMapperSuite) TestNoDefaultRole() {
+	s.config.DefaultRole = ""
+	token := s.generateToken(jwt.MapClaims{"preferred_username": "dave"})
+	claims, err := s.newClaimMapper().GetClaims(&AuthInfo{AuthToken: token})
+	s.NoError(err)
+	s.Equal(RoleUndefined, claims.System)
+	s.Empty(claims.Groups)
+}
+
+func (s *configurableClaimMapperSuite) TestMissingSubject() {
+	token := s.generateToken(jwt.MapClaims{"sub": "dave"})
+	_, err := s.newClaimMapper().GetClaims(&AuthInfo{AuthToken: token})
+	s.Error(err)
+}
+
+func (s *configurableClaimMapperSuite) TestDefaultSubjectClaim() {
+	s.config.SubjectClaim = ""
+	token := s.generateToken(jwt.MapClaims{"sub": "dave"})
+	claims, err := s.newClaimMapper().GetClaims(&AuthInfo{AuthToken: token})
+	s.NoError(err)
+	s.Equal("dave", claims.Subject)
+}
+
+func (s *configurableClaimMapperSuite) TestNoToken() {
+	claims, err := s.newClaimMapper().GetClaims(&AuthInfo{})
+	s.NoError(err)
+	s.Equal(&Claims{}, claims)
+}
+
+func (s *configurableClaimMapperSuite) TestInvalidConfig() {
+	testCases := []struct {
+		name   string
+		modify func(*config.ClaimMapping)
+	}{
+		{"BadDefaultRole", func(c *config.ClaimMapping) { c.DefaultRole = "owner" }},
+		{"BadGroupClaim", func(c *config.ClaimMapping) { c.GroupClaims = []string{"realm_access..roles"} }},
+		{"BadSubjectClaim", func(c *config.ClaimMapping) { c.SubjectClaim = `a["b` }},
+		{"NoGroup", func(c *config.ClaimMapping) {
+			c.GroupRoles = []config.ClaimMappingGroupRole{{Namespace: "a", Roles: []string{"read"}}}
+		}},
+		{"BothGroupAndRegex", func(c *config.ClaimMapping) {
+			c.GroupRoles = []config.ClaimMappingGroupRole{{Group: "a", GroupRegex: "a", Namespace: "a", Roles: []string{"read"}}}
+		}},
+		{"BadRegex", func(c *config.ClaimMapping) {
+			c.GroupRoles = []config.ClaimMappingGroupRole{{GroupRegex: "(", Namespace: "a", Roles: []string{"read"}}}
+		}},
+		{"NoNamespace", func(c *config.ClaimMapping) {
+			c.GroupRoles = []config.ClaimMappingGroupRole{{Group: "a", Roles: []string{"read"}}}
+		}},
+		{"BadRole", func(c *config.ClaimMapping) {
+			c.GroupRoles = []config.ClaimMappingGroupRole{{Group: "a", Namespace: "a", Roles: []string{"owner"}}}
+		}},
+	}
+	for _, tc := range testCases {
+		s.SetupTest()
+		tc.modify(s.config)
+		claimMapper, err := NewConfigurableJWTClaimMapper(s.tokenGenerator, s.config, log.NewNoopLogger())
+		s.Error(err, tc.name)
+		s.Nil(claimMapper, tc.name)
+	}
+}
+
+func (s *configurableClaimMapperSuite) TestParseClaimPath() {
+	testCases := []struct {
+		path     string
+		expected claimPath
+	}{
+		{"groups", claimPath{"groups"}},
+		{"$.groups", claimPath{"groups"}},
+		{"realm_access.roles", claimPath{"realm_access", "roles"}},
+		{`resource_access["temporal.io"].roles`, claimPath{"resource_access", "temporal.io", "roles"}},
+		{`$['https://example.com/groups']`, claimPath{"https://example.com/groups"}},
+	}
+	for _, tc := range testCases {
+		path, err := parseClaimPath(tc.path)
+		s.NoError(err, tc.path)
+		s.Equal(tc.expected, path, tc.path)
+	}
+
+	for _, path := range []string{"", "$", "a.", ".a.b.", `a[b]`, `a["b"]c`, `a[""]`} {
+		_, err := parseClaimPath(path)
+		s.Error(err, path)
+	}
+}
+
+func (s *configurableClaimMapperSuite) TestGetClaimMapperFromConfig() {
+	cfg := &config.Authorization{ClaimMapper: "configurable", ClaimMapping: *s.config}
+	claimMapper, err := GetClaimMapperFromConfig(cfg, log.NewNoopLogger())
+	s.NoError(err)
+	s.Equal(reflect.TypeOf(&configurableJWTClaimMapper{}), reflect.TypeOf(claimMapper))
+
+	cfg.ClaimMapping.DefaultRole = "owner"
+	claimMapper, err = GetClaimMapperFromConfig(cfg, log.NewNoopLogger())
+	s.Error(err)
+	s.Nil(claimMapper)
+}
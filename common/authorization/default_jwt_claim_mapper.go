@@ -27,6 +27,7 @@ package authorization
 import (
 	"context"
 	"fmt"
+	"slices"
 	"strings"
 
 	"github.com/golang-jwt/jwt/v4"
@@ -39,6 +40,7 @@ import (
 
 const (
 	defaultPermissionsClaimName = "permissions"
+	defaultGroupsClaimName      = "groups"
 	authorizationBearer         = "bearer"
 	headerSubject               = "sub"
 	permissionScopeSystem       = primitives.SystemLocalNamespace
@@ -53,6 +55,7 @@ type defaultJWTClaimMapper struct {
 	keyProvider          TokenKeyProvider
 	logger               log.Logger
 	permissionsClaimName string
+	groupsClaimName      string
 }
 
 func NewDefaultJWTClaimMapper(provider TokenKeyProvider, cfg *config.Authorization, logger log.Logger) ClaimMapper {
@@ -60,7 +63,11 @@ func NewDefaultJWTClaimMapper(provider TokenKeyProvider, cfg *config.Authorizati
 	if claimName == "" {
 		claimName = defaultPermissionsClaimName
 	}
-	return &defaultJWTClaimMapper{keyProvider: provider, logger: logger, permissionsClaimName: claimName}
+	groupsClaimName := cfg.GroupsClaimName
+	if groupsClaimName == "" {
+		groupsClaimName = defaultGroupsClaimName
+	}
+	return &defaultJWTClaimMapper{keyProvider: provider, logger: logger, permissionsClaimName: claimName, groupsClaimName: groupsClaimName}
 }
 
 var _ ClaimMapper = (*defaultJWTClaimMapper)(nil)
@@ -73,14 +80,7 @@ func (a *defaultJWTClaimMapper) GetClaims(authInfo *AuthInfo) (*Claims, error) {
 		return &claims, nil
 	}
 
-	parts := strings.Split(authInfo.AuthToken, " ")
-	if len(parts) != 2 {
-		return nil, serviceerror.NewPermissionDenied("unexpected authorization token format", "")
-	}
-	if !strings.EqualFold(parts[0], authorizationBearer) {
-		return nil, serviceerror.NewPermissionDenied("unexpected name in authorization token", "")
-	}
-	jwtClaims, err := parseJWTWithAudience(parts[1], a.keyProvider, authInfo.Audience)
+	jwtClaims, err := parseAuthToken(authInfo, a.keyProvider)
 	if err != nil {
 		return nil, err
 	}
@@ -96,19 +96,25 @@ func (a *defaultJWTClaimMapper) GetClaims(authInfo *AuthInfo) (*Claims, error) {
 			return nil, err
 		}
 	}
+	claims.Groups = appendGroups(nil, jwtClaims[a.groupsClaimName], a.groupsClaimName, a.logger)
 	return &claims, nil
 }
 
 func (a *defaultJWTClaimMapper) extractPermissions(permissions []interface{}, claims *Claims) error {
+	return extractPermissions(permissions, claims, a.logger)
+}
+
+// extractPermissions adds roles from "namespace:permission" strings to claims
+func extractPermissions(permissions []interface{}, claims *Claims, logger log.Logger) error {
 	for _, permission := range permissions {
 		p, ok := permission.(string)
 		if !ok {
-			a.logger.Warn(fmt.Sprintf("ignoring permission that is not a string: %v", permission))
+			logger.Warn(fmt.Sprintf("ignoring permission that is not a string: %v", permission))
 			continue
 		}
 		parts := strings.Split(p, ":")
 		if len(parts) != 2 {
-			a.logger.Warn(fmt.Sprintf("ignoring permission in unexpected format: %v", permission))
+			logger.Warn(fmt.Sprintf("ignoring permission in unexpected format: %v", permission))
 			continue
 		}
 		namespace := parts[0]
@@ -126,6 +132,45 @@ func (a *defaultJWTClaimMapper) extractPermissions(permissions []interface{}, cl
 	return nil
 }
 
+// appendGroups appends the group names held by the value of a claim, either a string or a list of
+// strings, to groups, skipping the ones already in groups
+func appendGroups(groups []string, value interface{}, claimName string, logger log.Logger) []string {
+	add := func(group string) {
+		if !slices.Contains(groups, group) {
+			groups = append(groups, group)
+		}
+	}
+	switch value := value.(type) {
+	case nil:
+	case string:
+		add(value)
+	case []interface{}:
+		for _, v := range value {
+			group, ok := v.(string)
+			if !ok {
+				logger.Warn(fmt.Sprintf("ignoring group that is not a string: %v", v))
+				continue
+			}
+			add(group)
+		}
+	default:
+		logger.Warn(fmt.Sprintf("ignoring %q claim of unexpected type: %T", claimName, value))
+	}
+	return groups
+}
+
+// parseAuthToken validates a "Bearer <JWT>" authorization token and returns its claims
+func parseAuthToken(authInfo *AuthInfo, keyProvider TokenKeyProvider) (jwt.MapClaims, error) {
+	parts := strings.Split(authInfo.AuthToken, " ")
+	if len(parts) != 2 {
+		return nil, serviceerror.NewPermissionDenied("unexpected authorization token format", "")
+	}
+	if !strings.EqualFold(parts[0], authorizationBearer) {
+		return nil, serviceerror.NewPermissionDenied("unexpected name in authorization token", "")
+	}
+	return parseJWTWithAudience(parts[1], keyProvider, authInfo.Audience)
+}
+
 func parseJWT(tokenString string, keyProvider TokenKeyProvider) (jwt.MapClaims, error) {
 	return parseJWTWithAudience(tokenString, keyProvider, "")
 }
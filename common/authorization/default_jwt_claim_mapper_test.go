@@ -210,6 +210,32 @@ func (s *defaultClaimMapperSuite) testTokenWithReaderWriterWorkerPermissions(alg
 	defaultRole := claims.Namespaces[defaultNamespace]
 	s.Equal(RoleReader|RoleWriter|RoleWorker, defaultRole)
 }
+func (s *defaultClaimMapperSuite) TestGroups() {
+	generateToken := func(claims jwt.MapClaims) string {
+		claims["sub"] = testSubject
+		claims["exp"] = time.Now().Add(time.Hour).Unix()
+		token := jwt.NewWithClaims(jwt.SigningMethodRS256, claims)
+		token.Header["kid"] = "test-key"
+		tokenString, err := token.SignedString(s.tokenGenerator.rsaPrivateKey)
+		s.NoError(err)
+		return AddBearer(tokenString)
+	}
+
+	claims, err := s.claimMapper.GetClaims(&AuthInfo{AuthToken: generateToken(jwt.MapClaims{
+		"groups": []string{"team-a", "team-b", "team-a"},
+	})})
+	s.NoError(err)
+	s.Equal([]string{"team-a", "team-b"}, claims.Groups)
+
+	claimMapper := NewDefaultJWTClaimMapper(s.tokenGenerator, &config.Authorization{GroupsClaimName: "teams"}, s.logger)
+	claims, err = claimMapper.GetClaims(&AuthInfo{AuthToken: generateToken(jwt.MapClaims{
+		"groups": []string{"team-a"},
+		"teams":  "team-c",
+	})})
+	s.NoError(err)
+	s.Equal([]string{"team-c"}, claims.Groups)
+}
+
 func (s *defaultClaimMapperSuite) TestGetClaimMapperFromConfigNoop() {
 	s.testGetClaimMapperFromConfig("", true, reflect.TypeOf(&noopClaimMapper{}))
 }
@@ -43,8 +43,9 @@ import (
 )
 
 type (
-	contextKeyMappedClaims struct{}
-	contextKeyAuthHeader   struct{}
+	contextKeyMappedClaims       struct{}
+	contextKeyAuthHeader         struct{}
+	contextKeyAuthorizationTrace struct{}
 )
 
 type (
@@ -52,6 +53,16 @@ type (
 	JWTAudienceMapper interface {
 		Audience(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo) string
 	}
+
+	// AuthorizationTrace is filled in by the authorization interceptor with the claims and the
+	// authorization result of a request, so that interceptors that run before it can learn
+	// about them. See WithAuthorizationTrace.
+	AuthorizationTrace struct {
+		// Claims of the caller, nil if there was no auth info
+		Claims *Claims
+		// Result of the authorizer, nil if the authorizer was not called or failed
+		Result *Result
+	}
 )
 
 const (
@@ -125,6 +136,9 @@ func (a *interceptor) Interceptor(
 				return nil, errUnauthorized // return a generic error to the caller without disclosing details
 			}
 			claims = mappedClaims
+			if trace := authorizationTraceFromContext(ctx); trace != nil {
+				trace.Claims = mappedClaims
+			}
 			ctx = context.WithValue(ctx, MappedClaims, mappedClaims)
 			if authHeader != "" {
 				ctx = context.WithValue(ctx, AuthHeader, authHeader)
@@ -150,6 +164,9 @@ func (a *interceptor) Interceptor(
 			a.logAuthError(err)
 			return nil, errUnauthorized // return a generic error to the caller without disclosing details
 		}
+		if trace := authorizationTraceFromContext(ctx); trace != nil {
+			trace.Result = &result
+		}
 		if result.Decision != DecisionAllow {
 			handler.Counter(metrics.ServiceErrUnauthorizedCounter.Name()).Record(1)
 			// if a reason is included in the result, include it in the error message
@@ -174,6 +191,18 @@ func (a *interceptor) authorize(
 	return a.authorizer.Authorize(ctx, claims, callTarget)
 }
 
+// WithAuthorizationTrace returns a context with an empty AuthorizationTrace that the
+// authorization interceptor fills in when the request passes through it.
+func WithAuthorizationTrace(ctx context.Context) (context.Context, *AuthorizationTrace) {
+	trace := &AuthorizationTrace{}
+	return context.WithValue(ctx, contextKeyAuthorizationTrace{}, trace), trace
+}
+
+func authorizationTraceFromContext(ctx context.Context) *AuthorizationTrace {
+	trace, _ := ctx.Value(contextKeyAuthorizationTrace{}).(*AuthorizationTrace)
+	return trace
+}
+
 func (a *interceptor) logAuthError(err error) {
 	a.logger.Error("Authorization error", tag.Error(err))
 }
@@ -0,0 +1,436 @@
+// The MIT License
+//
+// Copyright (c) 2020 Temporal Technologies Inc.  All rights reserved.
+//
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package authorization
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt/v4"
+	"go.uber.org/multierr"
+	"gopkg.in/square/go-jose.v2"
+
+	"go.temporal.io/server/common/clock"
+	"go.temporal.io/server/common/config"
+	"go.temporal.io/server/common/log"
+	"go.temporal.io/server/common/log/tag"
+	"go.temporal.io/server/common/metrics"
+)
+
+const (
+	oidcDiscoveryPath             = "/.well-known/openid-configuration"
+	defaultKeyMissRefreshInterval = time.Minute
+	keySourceHTTPTimeout          = 10 * time.Second
+	keySourceTagName              = "key_source"
+)
+
+type (
+	// oidcTokenKeyProvider is a key provider for JWKS key sets that are either configured
+	// directly or discovered from OIDC issuers. Unlike defaultTokenKeyProvider it supports
+	// Ed25519 and symmetric keys, and it refreshes on demand when a token arrives with a key ID
+	// that it doesn't know yet, e.g. right after the issuer rotated its keys.
+	oidcTokenKeyProvider struct {
+		config         config.JWTKeyProvider
+		logger         log.Logger
+		metricsHandler metrics.Handler
+		httpClient     *http.Client
+		timeSource     clock.TimeSource
+
+		keysLock sync.RWMutex
+		// key ID -> *rsa.PublicKey, *ecdsa.PublicKey, ed25519.PublicKey or []byte
+		keys map[string]any
+		// JWKS URI -> keys from the last successful fetch of that URI. Keys from a URI that
+		// fails to refresh are kept until it succeeds again.
+		keysBySource map[string]map[string]any
+
+		refreshLock     sync.Mutex
+		lastRefreshTime time.Time
+		// issuer URL -> JWKS URI from the last successful discovery
+		discoveredURIs map[string]string
+
+		stopCh chan struct{}
+		wg     sync.WaitGroup
+	}
+
+	oidcDiscoveryDocument struct {
+		Issuer  string `json:"issuer"`
+		JWKSURI string `json:"jwks_uri"`
+	}
+)
+
+var _ TokenKeyProvider = (*oidcTokenKeyProvider)(nil)
+var _ RawTokenKeyProvider = (*oidcTokenKeyProvider)(nil)
+
+var oidcSupportedMethods = []string{
+	jwt.SigningMethodRS256.Name,
+	jwt.SigningMethodRS384.Name,
+	jwt.SigningMethodRS512.Name,
+	jwt.SigningMethodPS256.Name,
+	jwt.SigningMethodPS384.Name,
+	jwt.SigningMethodPS512.Name,
+	jwt.SigningMethodES256.Name,
+	jwt.SigningMethodES384.Name,
+	jwt.SigningMethodES512.Name,
+	jwt.SigningMethodEdDSA.Alg(),
+	jwt.SigningMethodHS256.Name,
+	jwt.SigningMethodHS384.Name,
+	jwt.SigningMethodHS512.Name,
+}
+
+func NewOIDCTokenKeyProvider(
+	cfg *config.Authorization,
+	logger log.Logger,
+	metricsHandler metrics.Handler,
+) *oidcTokenKeyProvider {
+	return newOIDCTokenKeyProvider(cfg, logger, metricsHandler, &http.Client{Timeout: keySourceHTTPTimeout}, clock.NewRealTimeSource())
+}
+
+func newOIDCTokenKeyProvider(
+	cfg *config.Authorization,
+	logger log.Logger,
+	metricsHandler metrics.Handler,
+	httpClient *http.Client,
+	timeSource clock.TimeSource,
+) *oidcTokenKeyProvider {
+	provider := &oidcTokenKeyProvider{
+		config:         cfg.JWTKeyProvider,
+		logger:         logger,
+		metricsHandler: metricsHandler.WithTags(metrics.OperationTag(metrics.AuthorizationScope)),
+		httpClient:     httpClient,
+		timeSource:     timeSource,
+		keys:           make(map[string]any),
+		keysBySource:   make(map[string]map[string]any),
+		discoveredURIs: make(map[string]string),
+		stopCh:         make(chan struct{}),
+	}
+	provider.initialize()
+	return provider
+}
+
+func (a *oidcTokenKeyProvider) initialize() {
+	if a.hasSourcesConfigured() {
+		if err := a.refresh(context.Background()); err != nil {
+			a.logger.Error("error during initial retrieval of token keys", tag.Error(err))
+		}
+	}
+	if a.config.RefreshInterval > 0 {
+		a.wg.Add(1)
+		go a.refreshLoop()
+	}
+}
+
+func (a *oidcTokenKeyProvider) Close() {
+	close(a.stopCh)
+	a.wg.Wait()
+}
+
+func (a *oidcTokenKeyProvider) SupportedMethods() []string {
+	return oidcSupportedMethods
+}
+
+// GetKey returns the key for the "kid" header of the token, refreshing the key sets if the key
+// ID is unknown. Refreshes triggered this way are rate limited by MinRefreshInterval.
+func (a *oidcTokenKeyProvider) GetKey(ctx context.Context, token *jwt.Token) (interface{}, error) {
+	kid, ok := token.Header["kid"].(string)
+	if !ok {
+		return nil, fmt.Errorf("malformed token - no \"kid\" header")
+	}
+	key, err := a.lookup(ctx, kid)
+	if err != nil {
+		return nil, err
+	}
+
+	switch token.Method.(type) {
+	case *jwt.SigningMethodRSA, *jwt.SigningMethodRSAPSS:
+		if _, ok := key.(*rsa.PublicKey); ok {
+			return key, nil
+		}
+	case *jwt.SigningMethodECDSA:
+		if _, ok := key.(*ecdsa.PublicKey); ok {
+			return key, nil
+		}
+	case *jwt.SigningMethodEd25519:
+		if _, ok := key.(ed25519.PublicKey); ok {
+			return key, nil
+		}
+	case *jwt.SigningMethodHMAC:
+		if _, ok := key.([]byte); ok {
+			return key, nil
+		}
+	default:
+		return nil, fmt.Errorf("unexpected signing method: %v", token.Method.Alg())
+	}
+	return nil, fmt.Errorf("key %s of type %T can't be used with signing method %s", kid, key, token.Method.Alg())
+}
+
+func (a *oidcTokenKeyProvider) RsaKey(alg string, kid string) (*rsa.PublicKey, error) {
+	return oidcTypedKey[*rsa.PublicKey](a, "RSA", kid)
+}
+
+func (a *oidcTokenKeyProvider) EcdsaKey(alg string, kid string) (*ecdsa.PublicKey, error) {
+	return oidcTypedKey[*ecdsa.PublicKey](a, "ECDSA", kid)
+}
+
+func (a *oidcTokenKeyProvider) HmacKey(alg string, kid string) ([]byte, error) {
+	return oidcTypedKey[[]byte](a, "HMAC", kid)
+}
+
+func oidcTypedKey[T any](a *oidcTokenKeyProvider, keyType string, kid string) (T, error) {
+	var zero T
+	key, err := a.lookup(context.Background(), kid)
+	if err != nil {
+		return zero, err
+	}
+	typedKey, ok := key.(T)
+	if !ok {
+		return zero, fmt.Errorf("%s key not found for key ID: %s", keyType, kid)
+	}
+	return typedKey, nil
+}
+
+func (a *oidcTokenKeyProvider) lookup(ctx context.Context, kid string) (any, error) {
+	if key, ok := a.getKey(kid); ok {
+		return key, nil
+	}
+
+	a.metricsHandler.Counter(metrics.JWTKeyMissCount.Name()).Record(1)
+	if a.hasSourcesConfigured() && a.tryRefreshOnMiss(ctx) {
+		if key, ok := a.getKey(kid); ok {
+			return key, nil
+		}
+	}
+	return nil, fmt.Errorf("key not found for key ID: %s", kid)
+}
+
+func (a *oidcTokenKeyProvider) getKey(kid string) (any, bool) {
+	a.keysLock.RLock()
+	defer a.keysLock.RUnlock()
+	key, ok := a.keys[kid]
+	return key, ok
+}
+
+// tryRefreshOnMiss refreshes the key sets unless they were refreshed less than
+// MinRefreshInterval ago. Concurrent misses wait for a single refresh. Returns true if the
+// keys may have changed.
+func (a *oidcTokenKeyProvider) tryRefreshOnMiss(ctx context.Context) bool {
+	minInterval := a.config.MinRefreshInterval
+	if minInterval <= 0 {
+		minInterval = defaultKeyMissRefreshInterval
+	}
+	requestTime := a.timeSource.Now()
+
+	a.refreshLock.Lock()
+	defer a.refreshLock.Unlock()
+
+	if a.lastRefreshTime.After(requestTime) {
+		// another caller refreshed while we were waiting for the lock
+		return true
+	}
+	if requestTime.Sub(a.lastRefreshTime) < minInterval {
+		a.metricsHandler.Counter(metrics.JWTKeyMissRefreshThrottled.Name()).Record(1)
+		return false
+	}
+	if err := a.refreshLocked(ctx); err != nil {
+		a.logger.Warn("error while refreshing token keys for unknown key ID", tag.Error(err))
+	}
+	return true
+}
+
+func (a *oidcTokenKeyProvider) refreshLoop() {
+	defer a.wg.Done()
+
+	ticker := time.NewTicker(a.config.RefreshInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-a.stopCh:
+			return
+		case <-ticker.C:
+		}
+		if a.hasSourcesConfigured() {
+			if err := a.refresh(context.Background()); err != nil {
+				a.logger.Error("error while refreshing token keys", tag.Error(err))
+			}
+		}
+	}
+}
+
+func (a *oidcTokenKeyProvider) refresh(ctx context.Context) error {
+	a.refreshLock.Lock()
+	defer a.refreshLock.Unlock()
+	return a.refreshLocked(ctx)
+}
+
+func (a *oidcTokenKeyProvider) refreshLocked(ctx context.Context) error {
+	defer func() {
+		a.lastRefreshTime = a.timeSource.Now()
+	}()
+
+	jwksURIs, err := a.resolveJWKSURIs(ctx)
+
+	keysBySource := make(map[string]map[string]any, len(jwksURIs))
+	for _, uri := range jwksURIs {
+		keys, fetchErr := a.fetchKeySet(ctx, uri)
+		if fetchErr != nil {
+			err = multierr.Append(err, fmt.Errorf("%s: %w", uri, fetchErr))
+			if previous, ok := a.keysBySource[uri]; ok {
+				keysBySource[uri] = previous
+			}
+			continue
+		}
+		keysBySource[uri] = keys
+	}
+
+	merged := make(map[string]any)
+	for uri, keys := range keysBySource {
+		for kid, key := range keys {
+			if _, ok := merged[kid]; ok {
+				a.logger.Warn("duplicate JWKS key ID, ignoring", tag.NewStringTag("kid", kid), tag.NewStringTag(keySourceTagName, uri))
+				continue
+			}
+			merged[kid] = key
+		}
+	}
+
+	a.keysLock.Lock()
+	a.keys = merged
+	a.keysLock.Unlock()
+	a.keysBySource = keysBySource
+	return err
+}
+
+// resolveJWKSURIs returns the configured KeySourceURIs plus the JWKS URIs discovered from the
+// configured IssuerURLs. Must be called with refreshLock held.
+func (a *oidcTokenKeyProvider) resolveJWKSURIs(ctx context.Context) ([]string, error) {
+	var uris []string
+	for _, uri := range a.config.KeySourceURIs {
+		if strings.TrimSpace(uri) != "" {
+			uris = append(uris, uri)
+		}
+	}
+
+	var err error
+	for _, issuer := range a.config.IssuerURLs {
+		if strings.TrimSpace(issuer) == "" {
+			continue
+		}
+		uri, discoveryErr := a.discover(ctx, issuer)
+		if discoveryErr != nil {
+			err = multierr.Append(err, fmt.Errorf("%s: %w", issuer, discoveryErr))
+			// keep using what we discovered last time
+			if uri, ok := a.discoveredURIs[issuer]; ok {
+				uris = append(uris, uri)
+			}
+			continue
+		}
+		a.discoveredURIs[issuer] = uri
+		uris = append(uris, uri)
+	}
+	return uris, err
+}
+
+func (a *oidcTokenKeyProvider) discover(ctx context.Context, issuer string) (string, error) {
+	discoveryURI := strings.TrimSuffix(issuer, "/") + oidcDiscoveryPath
+	var doc oidcDiscoveryDocument
+	if err := a.getJSON(ctx, discoveryURI, &doc); err != nil {
+		return "", err
+	}
+	if doc.Issuer != "" && strings.TrimSuffix(doc.Issuer, "/") != strings.TrimSuffix(issuer, "/") {
+		return "", fmt.Errorf("issuer mismatch in discovery document: %s", doc.Issuer)
+	}
+	if doc.JWKSURI == "" {
+		return "", errors.New("no jwks_uri in discovery document")
+	}
+	return doc.JWKSURI, nil
+}
+
+func (a *oidcTokenKeyProvider) fetchKeySet(ctx context.Context, uri string) (map[string]any, error) {
+	handler := a.metricsHandler.WithTags(metrics.StringTag(keySourceTagName, uri))
+	startTime := a.timeSource.Now()
+	defer func() {
+		handler.Timer(metrics.JWTKeySourceRefreshLatency.Name()).Record(a.timeSource.Now().Sub(startTime))
+	}()
+	handler.Counter(metrics.JWTKeySourceRefreshRequests.Name()).Record(1)
+
+	jwks := jose.JSONWebKeySet{}
+	if err := a.getJSON(ctx, uri, &jwks); err != nil {
+		handler.Counter(metrics.JWTKeySourceRefreshFailures.Name()).Record(1)
+		return nil, err
+	}
+
+	keys := make(map[string]any, len(jwks.Keys))
+	for _, k := range jwks.Keys {
+		if k.Use != "" && k.Use != "sig" {
+			continue
+		}
+		switch key := k.Key.(type) {
+		case *rsa.PublicKey, *ecdsa.PublicKey, ed25519.PublicKey, []byte:
+			keys[k.KeyID] = key
+		default:
+			a.logger.Warn(fmt.Sprintf("unexpected type of JWKS key %T for key ID %s", k.Key, k.KeyID))
+		}
+	}
+	handler.Gauge(metrics.JWTKeySourceKeys.Name()).Record(float64(len(keys)))
+	return keys, nil
+}
+
+func (a *oidcTokenKeyProvider) getJSON(ctx context.Context, uri string, v any) (err error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, uri, nil)
+	if err != nil {
+		return err
+	}
+	resp, err := a.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		err = multierr.Combine(err, resp.Body.Close())
+	}()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("unexpected status code %d", resp.StatusCode)
+	}
+	return json.NewDecoder(resp.Body).Decode(v)
+}
+
+func (a *oidcTokenKeyProvider) hasSourcesConfigured() bool {
+	if a.config.HasSourceURIsConfigured() {
+		return true
+	}
+	for _, issuer := range a.config.IssuerURLs {
+		if strings.TrimSpace(issuer) != "" {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,253 @@
+// The MIT License
+//
+// Copyright (c) 2020 Temporal Technologies Inc.  All rights reserved.
+//
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package authorization
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v4"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+	"gopkg.in/square/go-jose.v2"
+
+	"go.temporal.io/server/common/clock"
+	"go.temporal.io/server/common/config"
+	"go.temporal.io/server/common/log"
+	"go.temporal.io/server/common/metrics"
+	"go.temporal.io/server/common/metrics/metricstest"
+)
+
+type (
+	oidcTokenKeyProviderSuite struct {
+		suite.Suite
+		*require.Assertions
+
+		server     *httptest.Server
+		jwksServer *fakeJWKSServer
+		timeSource *clock.EventTimeSource
+		provider   *oidcTokenKeyProvider
+	}
+
+	fakeJWKSServer struct {
+		sync.Mutex
+		issuer       string
+		keys         jose.JSONWebKeySet
+		failing      bool
+		jwksRequests int
+	}
+)
+
+func TestOIDCTokenKeyProviderSuite(t *testing.T) {
+	suite.Run(t, new(oidcTokenKeyProviderSuite))
+}
+
+func (s *oidcTokenKeyProviderSuite) SetupTest() {
+	s.Assertions = require.New(s.T())
+	s.jwksServer = &fakeJWKSServer{}
+	s.server = httptest.NewServer(s.jwksServer)
+	s.jwksServer.issuer = s.server.URL
+	s.timeSource = clock.NewEventTimeSource().Update(time.Now())
+	s.provider = nil
+}
+
+func (s *oidcTokenKeyProviderSuite) TearDownTest() {
+	if s.provider != nil {
+		s.provider.Close()
+	}
+	s.server.Close()
+}
+
+func (s *oidcTokenKeyProviderSuite) newProvider(metricsHandler metrics.Handler) {
+	s.provider = newOIDCTokenKeyProvider(
+		&config.Authorization{
+			JWTKeyProvider: config.JWTKeyProvider{
+				Type:               "oidc",
+				IssuerURLs:         []string{s.server.URL},
+				MinRefreshInterval: time.Minute,
+			},
+		},
+		log.NewNoopLogger(),
+		metricsHandler,
+		s.server.Client(),
+		s.timeSource,
+	)
+}
+
+func (s *oidcTokenKeyProviderSuite) TestDiscoveryAndEd25519() {
+	publicKey, privateKey, err := ed25519.GenerateKey(rand.Reader)
+	s.NoError(err)
+	s.jwksServer.setKeys(jose.JSONWebKey{Key: publicKey, KeyID: "ed-key", Algorithm: "EdDSA", Use: "sig"})
+	s.newProvider(metrics.NoopMetricsHandler)
+
+	token := s.signToken(jwt.SigningMethodEdDSA, "ed-key", privateKey)
+	claims, err := parseJWT(token, s.provider)
+	s.NoError(err)
+	s.Equal(testSubject, claims["sub"])
+}
+
+func (s *oidcTokenKeyProviderSuite) TestRefreshOnUnknownKeyID() {
+	oldKey := s.newRSAKey()
+	s.jwksServer.setKeys(jose.JSONWebKey{Key: &oldKey.PublicKey, KeyID: "old", Use: "sig"})
+	s.newProvider(metrics.NoopMetricsHandler)
+	s.Equal(1, s.jwksServer.requests())
+
+	// the issuer rotates its keys
+	newKey := s.newRSAKey()
+	s.jwksServer.setKeys(jose.JSONWebKey{Key: &newKey.PublicKey, KeyID: "new", Use: "sig"})
+	s.timeSource.Advance(2 * time.Minute)
+
+	_, err := parseJWT(s.signToken(jwt.SigningMethodRS256, "new", newKey), s.provider)
+	s.NoError(err)
+	s.Equal(2, s.jwksServer.requests())
+
+	// the old key is gone after the refresh
+	_, err = parseJWT(s.signToken(jwt.SigningMethodRS256, "old", oldKey), s.provider)
+	s.Error(err)
+}
+
+func (s *oidcTokenKeyProviderSuite) TestRefreshOnUnknownKeyID_RateLimited() {
+	key := s.newRSAKey()
+	s.jwksServer.setKeys(jose.JSONWebKey{Key: &key.PublicKey, KeyID: "known", Use: "sig"})
+	captureHandler := metricstest.NewCaptureHandler()
+	capture := captureHandler.StartCapture()
+	s.newProvider(captureHandler)
+
+	s.timeSource.Advance(2 * time.Minute)
+	for i := 0; i < 5; i++ {
+		_, err := parseJWT(s.signToken(jwt.SigningMethodRS256, "unknown", key), s.provider)
+		s.Error(err)
+	}
+	// one refresh at startup and one for the first miss
+	s.Equal(2, s.jwksServer.requests())
+	s.Len(capture.Snapshot()[metrics.JWTKeyMissRefreshThrottled.Name()], 4)
+}
+
+func (s *oidcTokenKeyProviderSuite) TestRefreshFailureKeepsKeys() {
+	key := s.newRSAKey()
+	s.jwksServer.setKeys(jose.JSONWebKey{Key: &key.PublicKey, KeyID: "known", Use: "sig"})
+	captureHandler := metricstest.NewCaptureHandler()
+	capture := captureHandler.StartCapture()
+	s.newProvider(captureHandler)
+
+	s.jwksServer.setFailing(true)
+	s.Error(s.provider.refresh(context.Background()))
+
+	_, err := parseJWT(s.signToken(jwt.SigningMethodRS256, "known", key), s.provider)
+	s.NoError(err)
+
+	failures := capture.Snapshot()[metrics.JWTKeySourceRefreshFailures.Name()]
+	s.Len(failures, 1)
+	s.Equal(s.server.URL+"/jwks", failures[0].Tags[keySourceTagName])
+}
+
+func (s *oidcTokenKeyProviderSuite) TestKeyTypeMustMatchMethod() {
+	key := s.newRSAKey()
+	s.jwksServer.setKeys(jose.JSONWebKey{Key: &key.PublicKey, KeyID: "rsa", Use: "sig"})
+	s.newProvider(metrics.NoopMetricsHandler)
+
+	// an HMAC token that claims to be signed with the RSA key must not verify
+	_, err := parseJWT(s.signToken(jwt.SigningMethodHS256, "rsa", []byte("secret")), s.provider)
+	s.Error(err)
+}
+
+func (s *oidcTokenKeyProviderSuite) TestGetTokenKeyProviderFromConfig() {
+	cfg := &config.Authorization{}
+	provider, err := GetTokenKeyProviderFromConfig(cfg, log.NewNoopLogger(), metrics.NoopMetricsHandler)
+	s.NoError(err)
+	s.IsType(&defaultTokenKeyProvider{}, provider)
+
+	cfg.JWTKeyProvider.Type = "oidc"
+	provider, err = GetTokenKeyProviderFromConfig(cfg, log.NewNoopLogger(), metrics.NoopMetricsHandler)
+	s.NoError(err)
+	s.IsType(&oidcTokenKeyProvider{}, provider)
+	provider.Close()
+
+	cfg.JWTKeyProvider.Type = "unknown"
+	_, err = GetTokenKeyProviderFromConfig(cfg, log.NewNoopLogger(), metrics.NoopMetricsHandler)
+	s.Error(err)
+}
+
+func (s *oidcTokenKeyProviderSuite) newRSAKey() *rsa.PrivateKey {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	s.NoError(err)
+	return key
+}
+
+func (s *oidcTokenKeyProviderSuite) signToken(method jwt.SigningMethod, kid string, key any) string {
+	token := jwt.NewWithClaims(method, jwt.RegisteredClaims{
+		Subject:   testSubject,
+		ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Hour)),
+	})
+	token.Header["kid"] = kid
+	tokenString, err := token.SignedString(key)
+	s.NoError(err)
+	return tokenString
+}
+
+func (f *fakeJWKSServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.Lock()
+	defer f.Unlock()
+
+	switch r.URL.Path {
+	case oidcDiscoveryPath:
+		_ = json.NewEncoder(w).Encode(oidcDiscoveryDocument{Issuer: f.issuer, JWKSURI: f.issuer + "/jwks"})
+	case "/jwks":
+		f.jwksRequests++
+		if f.failing {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		_ = json.NewEncoder(w).Encode(f.keys)
+	default:
+		w.WriteHeader(http.StatusNotFound)
+	}
+}
+
+func (f *fakeJWKSServer) setKeys(keys ...jose.JSONWebKey) {
+	f.Lock()
+	defer f.Unlock()
+	f.keys = jose.JSONWebKeySet{Keys: keys}
+}
+
+func (f *fakeJWKSServer) setFailing(failing bool) {
+	f.Lock()
+	defer f.Unlock()
+	f.failing = failing
+}
+
+func (f *fakeJWKSServer) requests() int {
+	f.Lock()
+	defer f.Unlock()
+	return f.jwksRequests
+}
@@ -0,0 +1,404 @@
+// The MIT License
+//
+// Copyright (c) 2020 Temporal Technologies Inc.  All rights reserved.
+//
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package authorization
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+
+	commonpb "go.temporal.io/api/common/v1"
+	taskqueuepb "go.temporal.io/api/taskqueue/v1"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+	"gopkg.in/yaml.v3"
+
+	"go.temporal.io/server/common/api"
+)
+
+const (
+	PolicyEffectAllow = "allow"
+	PolicyEffectDeny  = "deny"
+
+	// PolicyDefaultDecisionRoles falls back to the role-based default authorizer when no rule
+	// matches.
+	PolicyDefaultDecisionRoles = "roles"
+)
+
+type (
+	// AuthorizationPolicy is an ordered list of rules evaluated by the policy authorizer. The
+	// first rule that matches a call decides it. If no rule matches, DefaultDecision applies:
+	// "deny" (the default), "allow" or "roles".
+	AuthorizationPolicy struct {
+		DefaultDecision string                    `yaml:"defaultDecision"`
+		Rules           []AuthorizationPolicyRule `yaml:"rules"`
+	}
+
+	// AuthorizationPolicyRule matches a call if every non-empty field matches. Within a field,
+	// any one pattern has to match. Subjects and Groups together describe the caller: if either
+	// is set, the caller must match one of the subjects or belong to one of the groups. A rule
+	// without Subjects and Groups matches every caller, including unauthenticated ones.
+	//
+	// Patterns may contain "*" to match any sequence of characters. API patterns without a "/"
+	// match the method name (e.g. "SignalWorkflowExecution"), otherwise the full API name
+	// (e.g. "/temporal.api.operatorservice.v1.OperatorService/*"). Rules with TaskQueues or
+	// WorkflowTypes only apply to APIs whose request carries a task queue or workflow type. If such
+	// a request leaves the field empty, deny rules match it and allow rules don't, so that rules
+	// scoped to them fail closed.
+	AuthorizationPolicyRule struct {
+		Name          string   `yaml:"name"`
+		Effect        string   `yaml:"effect"`
+		Subjects      []string `yaml:"subjects"`
+		Groups        []string `yaml:"groups"`
+		Namespaces    []string `yaml:"namespaces"`
+		APIs          []string `yaml:"apis"`
+		TaskQueues    []string `yaml:"taskQueues"`
+		WorkflowTypes []string `yaml:"workflowTypes"`
+	}
+
+	// PolicyTarget is the part of a call that policy rules can match on.
+	PolicyTarget struct {
+		APIName      string
+		Namespace    string
+		TaskQueue    string
+		WorkflowType string
+	}
+
+	// requestFields tells which of the fields that policy rules can match on the request of an
+	// API carries.
+	requestFields struct {
+		taskQueue    bool
+		workflowType bool
+	}
+
+	// PolicyExplanation is the outcome of evaluating a policy for a single call.
+	PolicyExplanation struct {
+		Result Result
+		// Names of all rules that matched the call, in policy order. The first one decided it.
+		MatchedRules []string
+	}
+
+	// claimsFile is the YAML form of Claims used to evaluate a policy offline. Roles are lists
+	// of permission names: "read", "write", "worker" or "admin".
+	claimsFile struct {
+		Subject    string              `yaml:"subject"`
+		Groups     []string            `yaml:"groups"`
+		System     []string            `yaml:"system"`
+		Namespaces map[string][]string `yaml:"namespaces"`
+	}
+
+	policyAuthorizer struct {
+		rules           []compiledPolicyRule
+		defaultDecision string
+		fallback        Authorizer
+	}
+
+	compiledPolicyRule struct {
+		name       string
+		effect     Decision
+		subjects   []*regexp.Regexp
+		groups     []*regexp.Regexp
+		namespaces []*regexp.Regexp
+		// API patterns that contain a "/" are matched against the full name, others against the
+		// method name only
+		apiFullNames  []*regexp.Regexp
+		apiMethods    []*regexp.Regexp
+		taskQueues    []*regexp.Regexp
+		workflowTypes []*regexp.Regexp
+	}
+
+	hasTaskQueue interface {
+		GetTaskQueue() *taskqueuepb.TaskQueue
+	}
+
+	hasWorkflowType interface {
+		GetWorkflowType() *commonpb.WorkflowType
+	}
+)
+
+var _ Authorizer = (*policyAuthorizer)(nil)
+
+// LoadAuthorizationPolicy reads an AuthorizationPolicy from a YAML file.
+func LoadAuthorizationPolicy(path string) (*AuthorizationPolicy, error) {
+	contents, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read authorization policy: %w", err)
+	}
+	var policy AuthorizationPolicy
+	if err := yaml.Unmarshal(contents, &policy); err != nil {
+		return nil, fmt.Errorf("unable to decode authorization policy %s: %w", path, err)
+	}
+	return &policy, nil
+}
+
+// LoadClaimsFile reads Claims from a YAML (or JSON) file, for evaluating a policy offline.
+func LoadClaimsFile(path string) (*Claims, error) {
+	contents, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read claims: %w", err)
+	}
+	var file claimsFile
+	if err := yaml.Unmarshal(contents, &file); err != nil {
+		return nil, fmt.Errorf("unable to decode claims %s: %w", path, err)
+	}
+
+	claims := &Claims{
+		Subject: file.Subject,
+		Groups:  file.Groups,
+	}
+	for _, permission := range file.System {
+		claims.System |= permissionToRole(permission)
+	}
+	for namespace, permissions := range file.Namespaces {
+		if claims.Namespaces == nil {
+			claims.Namespaces = make(map[string]Role)
+		}
+		for _, permission := range permissions {
+			claims.Namespaces[namespace] |= permissionToRole(permission)
+		}
+	}
+	return claims, nil
+}
+
+// NewPolicyAuthorizer creates an authorizer that evaluates the given policy.
+func NewPolicyAuthorizer(policy *AuthorizationPolicy) (*policyAuthorizer, error) {
+	a := &policyAuthorizer{
+		defaultDecision: strings.ToLower(policy.DefaultDecision),
+		fallback:        NewDefaultAuthorizer(),
+	}
+	switch a.defaultDecision {
+	case "":
+		a.defaultDecision = PolicyEffectDeny
+	case PolicyEffectDeny, PolicyEffectAllow, PolicyDefaultDecisionRoles:
+	default:
+		return nil, fmt.Errorf("invalid defaultDecision %q in authorization policy", policy.DefaultDecision)
+	}
+
+	for i, rule := range policy.Rules {
+		compiled, err := compilePolicyRule(rule)
+		if err != nil {
+			return nil, fmt.Errorf("authorization policy rule %d (%s): %w", i, rule.Name, err)
+		}
+		if compiled.name == "" {
+			compiled.name = fmt.Sprintf("#%d", i)
+		}
+		a.rules = append(a.rules, compiled)
+	}
+	return a, nil
+}
+
+// Authorize evaluates the policy for a call. Health check APIs are always allowed.
+func (a *policyAuthorizer) Authorize(ctx context.Context, claims *Claims, target *CallTarget) (Result, error) {
+	if IsHealthCheckAPI(target.APIName) {
+		return resultAllow, nil
+	}
+
+	policyTarget := PolicyTarget{
+		APIName:   target.APIName,
+		Namespace: target.Namespace,
+	}
+	var fields requestFields
+	if req, ok := target.Request.(hasTaskQueue); ok {
+		policyTarget.TaskQueue = req.GetTaskQueue().GetName()
+		fields.taskQueue = true
+	}
+	if req, ok := target.Request.(hasWorkflowType); ok {
+		policyTarget.WorkflowType = req.GetWorkflowType().GetName()
+		fields.workflowType = true
+	}
+
+	explanation, err := a.explain(ctx, claims, target, policyTarget, fields)
+	return explanation.Result, err
+}
+
+// Explain evaluates the policy for a call described by target, without a request object. The
+// fields the request of the API carries are looked up in the registered API definitions.
+func (a *policyAuthorizer) Explain(claims *Claims, target PolicyTarget) (PolicyExplanation, error) {
+	return a.explain(context.Background(), claims, &CallTarget{
+		APIName:   target.APIName,
+		Namespace: target.Namespace,
+	}, target, requestFieldsOf(target.APIName))
+}
+
+func (a *policyAuthorizer) explain(
+	ctx context.Context,
+	claims *Claims,
+	callTarget *CallTarget,
+	target PolicyTarget,
+	fields requestFields,
+) (PolicyExplanation, error) {
+	var explanation PolicyExplanation
+	var decidingRule *compiledPolicyRule
+	for i := range a.rules {
+		rule := &a.rules[i]
+		if !rule.matches(claims, target, fields) {
+			continue
+		}
+		explanation.MatchedRules = append(explanation.MatchedRules, rule.name)
+		if decidingRule == nil {
+			decidingRule = rule
+		}
+	}
+
+	switch {
+	case decidingRule != nil && decidingRule.effect == DecisionAllow:
+		explanation.Result = Result{Decision: DecisionAllow, Reason: fmt.Sprintf("allowed by policy rule %q", decidingRule.name)}
+	case decidingRule != nil:
+		explanation.Result = Result{Decision: DecisionDeny, Reason: fmt.Sprintf("denied by policy rule %q", decidingRule.name)}
+	case a.defaultDecision == PolicyEffectAllow:
+		explanation.Result = Result{Decision: DecisionAllow, Reason: "no policy rule matched, allowed by default"}
+	case a.defaultDecision == PolicyDefaultDecisionRoles:
+		result, err := a.fallback.Authorize(ctx, claims, callTarget)
+		if err != nil {
+			return explanation, err
+		}
+		if result.Decision == DecisionAllow {
+			result.Reason = "no policy rule matched, allowed by role"
+		} else {
+			result.Reason = "no policy rule matched, denied by role"
+		}
+		explanation.Result = result
+	default:
+		explanation.Result = Result{Decision: DecisionDeny, Reason: "no policy rule matched, denied by default"}
+	}
+	return explanation, nil
+}
+
+func compilePolicyRule(rule AuthorizationPolicyRule) (compiledPolicyRule, error) {
+	compiled := compiledPolicyRule{name: rule.Name}
+	switch strings.ToLower(rule.Effect) {
+	case PolicyEffectAllow:
+		compiled.effect = DecisionAllow
+	case PolicyEffectDeny:
+		compiled.effect = DecisionDeny
+	default:
+		return compiled, fmt.Errorf("invalid effect %q, must be %q or %q", rule.Effect, PolicyEffectAllow, PolicyEffectDeny)
+	}
+
+	compiled.subjects = compileGlobs(rule.Subjects)
+	compiled.groups = compileGlobs(rule.Groups)
+	compiled.namespaces = compileGlobs(rule.Namespaces)
+	for _, pattern := range rule.APIs {
+		if strings.Contains(pattern, "/") {
+			compiled.apiFullNames = append(compiled.apiFullNames, compileGlobs([]string{pattern})...)
+		} else {
+			compiled.apiMethods = append(compiled.apiMethods, compileGlobs([]string{pattern})...)
+		}
+	}
+	compiled.taskQueues = compileGlobs(rule.TaskQueues)
+	compiled.workflowTypes = compileGlobs(rule.WorkflowTypes)
+	return compiled, nil
+}
+
+func compileGlobs(patterns []string) []*regexp.Regexp {
+	var res []*regexp.Regexp
+	for _, pattern := range patterns {
+		parts := strings.Split(pattern, "*")
+		for i := range parts {
+			parts[i] = regexp.QuoteMeta(parts[i])
+		}
+		res = append(res, regexp.MustCompile("^"+strings.Join(parts, ".*")+"$"))
+	}
+	return res
+}
+
+func matchesAny(patterns []*regexp.Regexp, value string) bool {
+	for _, pattern := range patterns {
+		if pattern.MatchString(value) {
+			return true
+		}
+	}
+	return false
+}
+
+// requestFieldsOf looks up the request message of a full API name in the proto registry, to
+// tell which fields it carries.
+func requestFieldsOf(apiName string) requestFields {
+	var fields requestFields
+	service, method, found := strings.Cut(strings.TrimPrefix(apiName, "/"), "/")
+	if !found {
+		return fields
+	}
+	descriptor, err := protoregistry.GlobalFiles.FindDescriptorByName(protoreflect.FullName(service))
+	if err != nil {
+		return fields
+	}
+	serviceDescriptor, ok := descriptor.(protoreflect.ServiceDescriptor)
+	if !ok {
+		return fields
+	}
+	methodDescriptor := serviceDescriptor.Methods().ByName(protoreflect.Name(method))
+	if methodDescriptor == nil {
+		return fields
+	}
+	input := methodDescriptor.Input().Fields()
+	fields.taskQueue = input.ByName("task_queue") != nil
+	fields.workflowType = input.ByName("workflow_type") != nil
+	return fields
+}
+
+func (r *compiledPolicyRule) matches(claims *Claims, target PolicyTarget, fields requestFields) bool {
+	if len(r.subjects) > 0 || len(r.groups) > 0 {
+		if claims == nil {
+			return false
+		}
+		principalMatch := len(r.subjects) > 0 && matchesAny(r.subjects, claims.Subject)
+		for _, group := range claims.Groups {
+			if principalMatch {
+				break
+			}
+			principalMatch = matchesAny(r.groups, group)
+		}
+		if !principalMatch {
+			return false
+		}
+	}
+	if len(r.namespaces) > 0 && !matchesAny(r.namespaces, target.Namespace) {
+		return false
+	}
+	if len(r.apiFullNames)+len(r.apiMethods) > 0 &&
+		!matchesAny(r.apiFullNames, target.APIName) &&
+		!matchesAny(r.apiMethods, api.MethodName(target.APIName)) {
+		return false
+	}
+	if len(r.taskQueues) > 0 && (!fields.taskQueue || !r.matchesRequestField(r.taskQueues, target.TaskQueue)) {
+		return false
+	}
+	if len(r.workflowTypes) > 0 && (!fields.workflowType || !r.matchesRequestField(r.workflowTypes, target.WorkflowType)) {
+		return false
+	}
+	return true
+}
+
+// matchesRequestField matches a field read from a request that carries it. If the request left
+// the field empty, only deny rules match.
+func (r *compiledPolicyRule) matchesRequestField(patterns []*regexp.Regexp, value string) bool {
+	if value == "" {
+		return r.effect == DecisionDeny
+	}
+	return matchesAny(patterns, value)
+}
@@ -0,0 +1,258 @@
+// The MIT License
+//
+// Copyright (c) 2020 Temporal Technologies Inc.  All rights reserved.
+//
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package authorization
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+	commonpb "go.temporal.io/api/common/v1"
+	taskqueuepb "go.temporal.io/api/taskqueue/v1"
+	"go.temporal.io/api/workflowservice/v1"
+
+	"go.temporal.io/server/common/api"
+	"go.temporal.io/server/common/config"
+)
+
+const testPolicy = `
+defaultDecision: roles
+rules:
+  - name: ci-reset
+    effect: allow
+    subjects: [ci-bot]
+    apis: [ResetWorkflowExecution]
+  - name: reset-ci-only
+    effect: deny
+    apis: [ResetWorkflowExecution]
+  - name: team-a-no-terminate
+    effect: deny
+    groups: [team-a]
+    namespaces: [team-a-*]
+    apis: [TerminateWorkflowExecution]
+  - name: team-a-signal
+    effect: allow
+    groups: [team-a]
+    namespaces: [team-a-*]
+    apis: [SignalWorkflowExecution, SignalWithStartWorkflowExecution]
+  - name: no-operator
+    effect: deny
+    apis: [/temporal.api.operatorservice.v1.OperatorService/*]
+  - name: billing-workflows
+    effect: allow
+    groups: [billing]
+    workflowTypes: [Billing*]
+  - name: no-payroll-for-contractors
+    effect: deny
+    groups: [contractors]
+    workflowTypes: [Payroll*]
+  - name: contractors
+    effect: allow
+    groups: [contractors]
+`
+
+type (
+	policyAuthorizerSuite struct {
+		suite.Suite
+		*require.Assertions
+
+		authorizer *policyAuthorizer
+	}
+)
+
+func TestPolicyAuthorizerSuite(t *testing.T) {
+	suite.Run(t, new(policyAuthorizerSuite))
+}
+
+func (s *policyAuthorizerSuite) SetupTest() {
+	s.Assertions = require.New(s.T())
+
+	policyFile := filepath.Join(s.T().TempDir(), "policy.yaml")
+	s.NoError(os.WriteFile(policyFile, []byte(testPolicy), 0644))
+	authorizer, err := GetAuthorizerFromConfig(&config.Authorization{Authorizer: "policy", PolicyFile: policyFile})
+	s.NoError(err)
+	s.authorizer = authorizer.(*policyAuthorizer)
+}
+
+func (s *policyAuthorizerSuite) TestFirstMatchingRuleDecides() {
+	ciBot := &Claims{Subject: "ci-bot"}
+	result, err := s.authorize(ciBot, "ResetWorkflowExecution", "any", nil)
+	s.NoError(err)
+	s.Equal(DecisionAllow, result.Decision)
+	s.Equal(`allowed by policy rule "ci-reset"`, result.Reason)
+
+	result, err = s.authorize(&claimsSystemAdmin, "ResetWorkflowExecution", "any", nil)
+	s.NoError(err)
+	s.Equal(DecisionDeny, result.Decision)
+	s.Equal(`denied by policy rule "reset-ci-only"`, result.Reason)
+}
+
+func (s *policyAuthorizerSuite) TestGroupsAndNamespaceGlob() {
+	teamA := &Claims{Subject: "alice", Groups: []string{"team-a"}}
+
+	result, err := s.authorize(teamA, "SignalWorkflowExecution", "team-a-prod", nil)
+	s.NoError(err)
+	s.Equal(DecisionAllow, result.Decision)
+
+	result, err = s.authorize(teamA, "TerminateWorkflowExecution", "team-a-prod", nil)
+	s.NoError(err)
+	s.Equal(DecisionDeny, result.Decision)
+
+	// no rule matches another namespace, so it falls back to roles
+	result, err = s.authorize(teamA, "SignalWorkflowExecution", "team-b", nil)
+	s.NoError(err)
+	s.Equal(DecisionDeny, result.Decision)
+	s.Equal("no policy rule matched, denied by role", result.Reason)
+}
+
+func (s *policyAuthorizerSuite) TestFullAPINamePattern() {
+	result, err := s.authorize(&claimsSystemAdmin, "/temporal.api.operatorservice.v1.OperatorService/ListSearchAttributes", testNamespace, nil)
+	s.NoError(err)
+	s.Equal(DecisionDeny, result.Decision)
+}
+
+func (s *policyAuthorizerSuite) TestWorkflowTypeFromRequest() {
+	billing := &Claims{Subject: "bob", Groups: []string{"billing"}}
+	request := &workflowservice.StartWorkflowExecutionRequest{
+		Namespace:    testNamespace,
+		WorkflowType: &commonpb.WorkflowType{Name: "BillingMonthly"},
+		TaskQueue:    &taskqueuepb.TaskQueue{Name: "billing"},
+	}
+	result, err := s.authorize(billing, "StartWorkflowExecution", testNamespace, request)
+	s.NoError(err)
+	s.Equal(DecisionAllow, result.Decision)
+
+	request.WorkflowType.Name = "Other"
+	result, err = s.authorize(billing, "StartWorkflowExecution", testNamespace, request)
+	s.NoError(err)
+	s.Equal(DecisionDeny, result.Decision)
+}
+
+func (s *policyAuthorizerSuite) TestRequestFieldScopedRules() {
+	billing := &Claims{Subject: "bob", Groups: []string{"billing"}}
+	contractor := &Claims{Subject: "carol", Groups: []string{"contractors"}}
+	signal := &workflowservice.SignalWorkflowExecutionRequest{Namespace: testNamespace}
+
+	// rules scoped to workflow types don't apply to APIs without a workflow type
+	result, err := s.authorize(billing, "SignalWorkflowExecution", testNamespace, signal)
+	s.NoError(err)
+	s.Equal(DecisionDeny, result.Decision)
+	s.Equal("no policy rule matched, denied by role", result.Reason)
+
+	result, err = s.authorize(contractor, "SignalWorkflowExecution", testNamespace, signal)
+	s.NoError(err)
+	s.Equal(DecisionAllow, result.Decision)
+	s.Equal(`allowed by policy rule "contractors"`, result.Reason)
+
+	// a deny rule scoped to workflow types matches a request that leaves the workflow type empty
+	result, err = s.authorize(contractor, "StartWorkflowExecution", testNamespace, &workflowservice.StartWorkflowExecutionRequest{
+		Namespace: testNamespace,
+	})
+	s.NoError(err)
+	s.Equal(DecisionDeny, result.Decision)
+	s.Equal(`denied by policy rule "no-payroll-for-contractors"`, result.Reason)
+
+	result, err = s.authorize(contractor, "StartWorkflowExecution", testNamespace, &workflowservice.StartWorkflowExecutionRequest{
+		Namespace:    testNamespace,
+		WorkflowType: &commonpb.WorkflowType{Name: "Onboarding"},
+	})
+	s.NoError(err)
+	s.Equal(DecisionAllow, result.Decision)
+	s.Equal(`allowed by policy rule "contractors"`, result.Reason)
+}
+
+func (s *policyAuthorizerSuite) TestHealthCheckAlwaysAllowed() {
+	result, err := s.authorizer.Authorize(context.Background(), nil, &targetGrpcHealthCheck)
+	s.NoError(err)
+	s.Equal(DecisionAllow, result.Decision)
+}
+
+func (s *policyAuthorizerSuite) TestExplain() {
+	explanation, err := s.authorizer.Explain(&Claims{Subject: "ci-bot"}, PolicyTarget{
+		APIName:   api.WorkflowServicePrefix + "ResetWorkflowExecution",
+		Namespace: "team-a-prod",
+	})
+	s.NoError(err)
+	s.Equal(DecisionAllow, explanation.Result.Decision)
+	s.Equal([]string{"ci-reset", "reset-ci-only"}, explanation.MatchedRules)
+
+	// the fields an API carries are known without a request
+	contractor := &Claims{Subject: "carol", Groups: []string{"contractors"}}
+	explanation, err = s.authorizer.Explain(contractor, PolicyTarget{
+		APIName:   api.WorkflowServicePrefix + "SignalWorkflowExecution",
+		Namespace: testNamespace,
+	})
+	s.NoError(err)
+	s.Equal([]string{"contractors"}, explanation.MatchedRules)
+
+	explanation, err = s.authorizer.Explain(contractor, PolicyTarget{
+		APIName:      api.WorkflowServicePrefix + "StartWorkflowExecution",
+		Namespace:    testNamespace,
+		WorkflowType: "PayrollMonthly",
+	})
+	s.NoError(err)
+	s.Equal(DecisionDeny, explanation.Result.Decision)
+	s.Equal([]string{"no-payroll-for-contractors", "contractors"}, explanation.MatchedRules)
+}
+
+func (s *policyAuthorizerSuite) TestInvalidPolicy() {
+	_, err := NewPolicyAuthorizer(&AuthorizationPolicy{DefaultDecision: "maybe"})
+	s.Error(err)
+	_, err = NewPolicyAuthorizer(&AuthorizationPolicy{Rules: []AuthorizationPolicyRule{{Name: "r", Effect: "permit"}}})
+	s.Error(err)
+}
+
+func (s *policyAuthorizerSuite) TestLoadClaimsFile() {
+	claimsFile := filepath.Join(s.T().TempDir(), "claims.yaml")
+	s.NoError(os.WriteFile(claimsFile, []byte(`
+subject: alice
+groups: [team-a]
+system: [read]
+namespaces:
+  team-a-prod: [write, worker]
+`), 0644))
+	claims, err := LoadClaimsFile(claimsFile)
+	s.NoError(err)
+	s.Equal(&Claims{
+		Subject:    "alice",
+		Groups:     []string{"team-a"},
+		System:     RoleReader,
+		Namespaces: map[string]Role{"team-a-prod": RoleWriter | RoleWorker},
+	}, claims)
+}
+
+func (s *policyAuthorizerSuite) authorize(claims *Claims, apiName string, namespace string, request any) (Result, error) {
+	if apiName[0] != '/' {
+		apiName = api.WorkflowServicePrefix + apiName
+	}
+	return s.authorizer.Authorize(context.Background(), claims, &CallTarget{
+		APIName:   apiName,
+		Namespace: namespace,
+		Request:   request,
+	})
+}
@@ -53,6 +53,8 @@ type Claims struct {
 	System Role
 	// Roles within specific namespaces
 	Namespaces map[string]Role
+	// Groups the subject belongs to, if the claim mapper provides them
+	Groups []string
 	// Free form bucket for extra data
 	Extensions interface{}
 }
@@ -120,6 +120,38 @@ type (
 		Metrics *metrics.Config `yaml:"metrics"`
 		// Settings for authentication and authorization
 		Authorization Authorization `yaml:"authorization"`
+		// Audit is the audit log configuration of the frontend service
+		Audit Audit `yaml:"audit"`
+	}
+
+	// Audit configures the audit log, which records caller, namespace, API, workflow ID,
+	// authorization decision and outcome of frontend API calls
+	Audit struct {
+		// Sinks to write audit records to. Audit log is disabled if there are none.
+		Sinks []AuditSink `yaml:"sinks"`
+		// ReadSampleRate is the fraction, between 0 and 1, of read-only API calls, long polls and
+		// activity heartbeats to record. Other mutating calls are always recorded.
+		ReadSampleRate float64 `yaml:"readSampleRate"`
+		// ExcludeAPIs are API method names, e.g. "PollWorkflowTaskQueue", that are never recorded
+		ExcludeAPIs []string `yaml:"excludeAPIs"`
+		// RedactFields are names of audit record fields, e.g. "Caller", to mask
+		RedactFields []string `yaml:"redactFields"`
+		// QueueSize is the number of audit records waiting to be written to the sinks. Records are
+		// dropped, and counted by the audit_records_dropped metric, while the queue is full.
+		// Defaults to 10000.
+		QueueSize int `yaml:"queueSize"`
+	}
+
+	// AuditSink is an audit log destination
+	AuditSink struct {
+		// Type is "stdout" or "file"
+		Type string `yaml:"type"`
+		// Path of the audit log file, for the "file" sink
+		Path string `yaml:"path"`
+		// MaxSizeMB is the size at which the audit log file is rotated. 0 disables rotation.
+		MaxSizeMB int `yaml:"maxSizeMB"`
+		// MaxBackups is the number of rotated files to keep. Defaults to 5.
+		MaxBackups int `yaml:"maxBackups"`
 	}
 
 	// RootTLS contains all TLS settings for the Temporal server
@@ -233,6 +265,31 @@ type (
 		// This is generally used when BindOnIP would be the same across several nodes (ie: `0.0.0.0` or `::`)
 		// and for nat traversal scenarios. Check net.ParseIP for supported syntax
 		BroadcastAddress string `yaml:"broadcastAddress"`
+		// Provider selects the membership implementation: "ringpop" (default), "static" or "dns".
+		// The static and dns providers don't gossip and don't heartbeat into the cluster_membership
+		// table, they build the service rings from the Services lists below. Their only liveness
+		// detection is a TCP connection to the gRPC port of every member on each refresh: a member
+		// which doesn't accept it is left out of the rings, unless no other member does. A host which
+		// hangs but still accepts connections keeps its keys until it is removed from the lists.
+		Provider string `yaml:"provider"`
+		// RefreshInterval is how often the static and dns providers resolve and probe the members again.
+		RefreshInterval time.Duration `yaml:"refreshInterval"`
+		// ProbeTimeout is how long the static and dns providers wait for a member to accept a TCP
+		// connection before leaving it out of the rings. Defaults to 2s.
+		ProbeTimeout time.Duration `yaml:"probeTimeout"`
+		// Services contains the members of every service for the static and dns providers,
+		// keyed by service name
+		Services map[string]MembershipService `yaml:"services"`
+	}
+
+	// MembershipService contains the members of a single service for the static and dns membership providers
+	MembershipService struct {
+		// Hosts is the list of host:port gRPC addresses of the service, used by the static provider
+		Hosts []string `yaml:"hosts"`
+		// DNSNames is the list of DNS names resolved by the dns provider. A name with a port (host:port)
+		// is resolved to A/AAAA records which all use that port, a name without a port is resolved as
+		// an SRV record (e.g. _grpc._tcp.history.temporal.svc.cluster.local).
+		DNSNames []string `yaml:"dnsNames"`
 	}
 
 	// Persistence contains the configuration for data store / persistence layer
@@ -455,6 +512,9 @@ type (
 		EnableRead bool `yaml:"enableRead"`
 		// Provider contains the config for all history archivers
 		Provider *HistoryArchiverProvider `yaml:"provider"`
+		// Compression is the config for compressing and deduplicating history, used by the filestore and
+		// s3store history archivers
+		Compression *HistoryArchiveCompression `yaml:"compression"`
 	}
 
 	// HistoryArchiverProvider contains the config for all history archivers
@@ -462,6 +522,7 @@ type (
 		Filestore *FilestoreArchiver `yaml:"filestore"`
 		Gstorage  *GstorageArchiver  `yaml:"gstorage"`
 		S3store   *S3Archiver        `yaml:"s3store"`
+		Azblob    *AzblobArchiver    `yaml:"azblob"`
 	}
 
 	// VisibilityArchival contains the config for visibility archival
@@ -479,6 +540,7 @@ type (
 		Filestore *FilestoreArchiver `yaml:"filestore"`
 		S3store   *S3Archiver        `yaml:"s3store"`
 		Gstorage  *GstorageArchiver  `yaml:"gstorage"`
+		Azblob    *AzblobArchiver    `yaml:"azblob"`
 	}
 
 	// FilestoreArchiver contain the config for filestore archiver
@@ -500,6 +562,31 @@ type (
 		LogLevel         uint    `yaml:"logLevel"`
 	}
 
+	// HistoryArchiveCompression contains the config for compressing and deduplicating archived history.
+	// Histories archived without compression can always be read back, whatever this config is set to.
+	HistoryArchiveCompression struct {
+		// Codec is the compression codec for new history blobs: none (default), gzip or zstd
+		Codec string `yaml:"codec"`
+		// DedupThreshold is the minimum size in bytes of a payload to be stored once by its content hash
+		// and shared between archived runs. Zero disables deduplication.
+		DedupThreshold int `yaml:"dedupThreshold"`
+	}
+
+	// AzblobArchiver contains the config for Azure Blob Storage archiver
+	AzblobArchiver struct {
+		// AccountName is the storage account name
+		AccountName string `yaml:"accountName"`
+		// AccountKey is the base64 encoded shared key of the storage account. It can also be set
+		// with the AZURE_STORAGE_KEY environment variable.
+		AccountKey string `yaml:"accountKey"`
+		// SASToken is a shared access signature used instead of the account key
+		SASToken string `yaml:"sasToken"`
+		// Endpoint overrides the blob service endpoint, which defaults to
+		// https://<accountName>.blob.core.windows.net. Use it for sovereign clouds or emulators,
+		// e.g. http://127.0.0.1:10000/devstoreaccount1.
+		Endpoint string `yaml:"endpoint"`
+	}
+
 	// PublicClient is the config for internal nodes (history/matching/worker) connecting to
 	// frontend. There are three methods of connecting:
 	// 1. Use membership to locate "internal-frontend" and connect to them using the Internode
@@ -560,21 +647,69 @@ type (
 		// Signing key provider for validating JWT tokens
 		JWTKeyProvider       JWTKeyProvider `yaml:"jwtKeyProvider"`
 		PermissionsClaimName string         `yaml:"permissionsClaimName"`
-		// Empty string for noopAuthorizer or "default" for defaultAuthorizer
+		// Name of the claim that holds the group names of the caller, for the "default" claim
+		// mapper. Defaults to "groups".
+		GroupsClaimName string `yaml:"groupsClaimName"`
+		// Empty string for noopAuthorizer, "default" for defaultAuthorizer or "policy" for policyAuthorizer
 		Authorizer string `yaml:"authorizer"`
-		// Empty string for noopClaimMapper or "default" for defaultJWTClaimMapper
+		// Path to the YAML rules file used by the "policy" authorizer
+		PolicyFile string `yaml:"policyFile"`
+		// Empty string for noopClaimMapper, "default" for defaultJWTClaimMapper or "configurable"
+		// for a JWT claim mapper configured by ClaimMapping
 		ClaimMapper string `yaml:"claimMapper"`
+		// Configuration of the "configurable" claim mapper
+		ClaimMapping ClaimMapping `yaml:"claimMapping"`
 		// Name of main auth header to pass to ClaimMapper (as `AuthToken`). Defaults to `authorization`.
 		AuthHeaderName string `yaml:"authHeaderName"`
 		// Name of extra auth header to pass to ClaimMapper (as `ExtraData`). Defaults to `authorization-extras`.
 		AuthExtraHeaderName string `yaml:"authExtraHeaderName"`
 	}
 
+	// ClaimMapping describes where to find the subject, groups and permissions in a JWT and how
+	// groups map to roles. Claim paths are dot-separated, optionally prefixed with "$.", and may
+	// use ["key"] for keys that contain dots, e.g. "realm_access.roles" or
+	// "resource_access[\"temporal\"].roles".
+	ClaimMapping struct {
+		// Claim that holds the subject. Defaults to "sub".
+		SubjectClaim string `yaml:"subjectClaim"`
+		// Claims that hold group names, either a string or a list of strings. Defaults to "groups".
+		GroupClaims []string `yaml:"groupClaims"`
+		// Claims that hold "namespace:permission" strings, as read by the default claim mapper.
+		PermissionClaims []string `yaml:"permissionClaims"`
+		// Groups whose members are system admins.
+		SystemAdminGroups []string `yaml:"systemAdminGroups"`
+		// System-level role ("read", "write", "worker" or "admin") given to every caller with a
+		// valid token. Empty for none.
+		DefaultRole string `yaml:"defaultRole"`
+		// Rules that map groups to roles in namespaces.
+		GroupRoles []ClaimMappingGroupRole `yaml:"groupRoles"`
+	}
+
+	// ClaimMappingGroupRole gives Roles in Namespace to members of a group. The group is either
+	// matched exactly by Group, or by the regular expression GroupRegex that must match the whole
+	// group name, in which case Namespace may refer to capture groups, e.g. "$1". A Namespace of
+	// "temporal-system" gives system-level roles.
+	ClaimMappingGroupRole struct {
+		Group      string   `yaml:"group"`
+		GroupRegex string   `yaml:"groupRegex"`
+		Namespace  string   `yaml:"namespace"`
+		Roles      []string `yaml:"roles"`
+	}
+
 	// @@@SNIPSTART temporal-common-service-config-jwtkeyprovider
 	// Contains the config for signing key provider for validating JWT tokens
 	JWTKeyProvider struct {
 		KeySourceURIs   []string      `yaml:"keySourceURIs"`
 		RefreshInterval time.Duration `yaml:"refreshInterval"`
+		// Empty string or "default" for defaultTokenKeyProvider, or "oidc" for a provider that
+		// supports OIDC discovery, Ed25519 keys and refreshing when an unknown key ID is seen
+		Type string `yaml:"type"`
+		// OIDC issuers whose JWKS URIs are discovered from <issuer>/.well-known/openid-configuration.
+		// Only used by the "oidc" provider.
+		IssuerURLs []string `yaml:"issuerURLs"`
+		// Minimum time between refreshes triggered by tokens with an unknown key ID. Defaults to
+		// one minute. Only used by the "oidc" provider.
+		MinRefreshInterval time.Duration `yaml:"minRefreshInterval"`
 	}
 	// @@@SNIPEND
 )
@@ -589,6 +724,12 @@ const (
 	ClusterMDStoreName DataStoreName = "ClusterMDStore"
 )
 
+const (
+	MembershipProviderRingpop = "ringpop"
+	MembershipProviderStatic  = "static"
+	MembershipProviderDNS     = "dns"
+)
+
 const (
 	ForceTLSConfigAuto      = ""
 	ForceTLSConfigInternode = "internode"
@@ -616,6 +757,42 @@ func (c *Config) Validate() error {
 		return fmt.Errorf("invalid value for publicClient.forceTLSConfig: %q", c.PublicClient.ForceTLSConfig)
 	}
 
+	if err := c.Global.Membership.Validate(); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// Validate validates the membership config
+func (m *Membership) Validate() error {
+	switch m.Provider {
+	case "", MembershipProviderRingpop:
+		return nil
+	case MembershipProviderStatic:
+		for service, members := range m.Services {
+			if len(members.Hosts) == 0 {
+				return fmt.Errorf("membership service %q has no hosts for the static provider", service)
+			}
+		}
+	case MembershipProviderDNS:
+		for service, members := range m.Services {
+			if len(members.DNSNames) == 0 {
+				return fmt.Errorf("membership service %q has no dnsNames for the dns provider", service)
+			}
+		}
+	default:
+		return fmt.Errorf("invalid value for global.membership.provider: %q", m.Provider)
+	}
+	if m.ProbeTimeout < 0 {
+		return fmt.Errorf("invalid value for global.membership.probeTimeout: %v", m.ProbeTimeout)
+	}
+	if m.RefreshInterval > 0 && m.ProbeTimeout >= m.RefreshInterval {
+		return fmt.Errorf("global.membership.probeTimeout must be shorter than global.membership.refreshInterval")
+	}
+	if len(m.Services) == 0 {
+		return fmt.Errorf("membership provider %q requires global.membership.services", m.Provider)
+	}
 	return nil
 }
 
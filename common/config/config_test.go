@@ -26,6 +26,7 @@ package config
 
 import (
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 )
@@ -36,3 +37,29 @@ func TestToString(t *testing.T) {
 	assert.NoError(t, err)
 	assert.NotEmpty(t, cfg.String())
 }
+
+func TestMembershipValidate(t *testing.T) {
+	assert.NoError(t, (&Membership{}).Validate())
+	assert.NoError(t, (&Membership{Provider: MembershipProviderRingpop}).Validate())
+	assert.NoError(t, (&Membership{
+		Provider: MembershipProviderStatic,
+		Services: map[string]MembershipService{"history": {Hosts: []string{"10.0.0.1:7234"}}},
+	}).Validate())
+	assert.NoError(t, (&Membership{
+		Provider: MembershipProviderDNS,
+		Services: map[string]MembershipService{"history": {DNSNames: []string{"_grpc._tcp.history"}}},
+	}).Validate())
+
+	assert.Error(t, (&Membership{Provider: "etcd"}).Validate())
+	assert.Error(t, (&Membership{Provider: MembershipProviderStatic}).Validate())
+	assert.Error(t, (&Membership{
+		Provider: MembershipProviderDNS,
+		Services: map[string]MembershipService{"history": {Hosts: []string{"10.0.0.1:7234"}}},
+	}).Validate())
+	assert.Error(t, (&Membership{
+		Provider:        MembershipProviderStatic,
+		Services:        map[string]MembershipService{"history": {Hosts: []string{"10.0.0.1:7234"}}},
+		RefreshInterval: time.Second,
+		ProbeTimeout:    time.Second,
+	}).Validate())
+}
@@ -107,3 +107,15 @@ const (
 	// DefaultOperatorRPSRatio is the default percentage of rate limit that should be used for operator priority requests
 	DefaultOperatorRPSRatio float64 = 0.2
 )
+
+const (
+	// TaskPriorityHeaderKey is the header field of workflow start requests and activity schedule commands
+	// that sets the dispatch priority of their tasks. The value is an integer payload.
+	TaskPriorityHeaderKey = "temporal-task-priority"
+	// HighestTaskPriority is the most urgent task priority
+	HighestTaskPriority int32 = 1
+	// LowestTaskPriority is the least urgent task priority
+	LowestTaskPriority int32 = 5
+	// DefaultTaskPriority is the priority of tasks that don't set one
+	DefaultTaskPriority int32 = 3
+)
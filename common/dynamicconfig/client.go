@@ -51,6 +51,22 @@ type (
 		GetValue(key Key) []ConstrainedValue
 	}
 
+	// NotifyingClient is a Client that can also push notifications when its values change.
+	// Collection uses it to implement subscriptions. A Client that does not implement
+	// NotifyingClient still works for lookups, but subscriptions on it will never fire.
+	NotifyingClient interface {
+		Client
+		// Subscribe registers a callback that is called after each refresh of the client's
+		// values, with the set of keys whose ConstrainedValues differ from the previous
+		// refresh. Keys may be passed in any case. The callback is called synchronously from
+		// the refresh path, so it should not block. The returned function removes the
+		// subscription.
+		Subscribe(callback ClientUpdateFn) (cancel func())
+	}
+
+	// ClientUpdateFn is called by a NotifyingClient with the set of changed keys.
+	ClientUpdateFn func(changedKeys map[Key]struct{})
+
 	// Key is a key/property stored in dynamic config. For convenience, it is recommended that
 	// you treat keys as case-insensitive.
 	Key string
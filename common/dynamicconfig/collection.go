@@ -27,6 +27,9 @@ package dynamicconfig
 import (
 	"errors"
 	"fmt"
+	"reflect"
+	"strings"
+	"sync"
 	"sync/atomic"
 	"time"
 
@@ -46,8 +49,25 @@ type (
 		client   Client
 		logger   log.Logger
 		errCount int64
+
+		subscriptionLock sync.Mutex
+		subscriptionIdx  int
+		// lower-cased key -> subscription id -> subscription
+		subscriptions map[string]map[int]*subscription
+		// cancels the subscription on client, set while there is at least one subscription
+		cancelClientSubscription func()
+	}
+
+	// subscription is refreshed when its key changes. Typed subscriptions re-evaluate a single
+	// key/constraint set and call back if the typed value has changed since the last evaluation.
+	subscription struct {
+		lock    sync.Mutex
+		refresh func()
 	}
 
+	// SubscriptionCancelFn removes a subscription created by one of the Subscribe methods.
+	SubscriptionCancelFn func()
+
 	// These function types follow a similar pattern:
 	//   {X}PropertyFn - returns a value of type X that is global (no filters)
 	//   {X}PropertyFnWith{Y}Filter - returns a value of type X with the given filters
@@ -83,6 +103,7 @@ type (
 	IntPropertyFnWithTaskQueueInfoFilters      func(namespace string, taskQueue string, taskType enumspb.TaskQueueType) int
 	MapPropertyFn                              func() map[string]any
 	MapPropertyFnWithNamespaceFilter           func(namespace string) map[string]any
+	MapPropertyFnWithTaskQueueInfoFilters      func(namespace string, taskQueue string, taskType enumspb.TaskQueueType) map[string]any
 	StringPropertyFn                           func() string
 	StringPropertyFnWithNamespaceFilter        func(namespace string) string
 	StringPropertyFnWithNamespaceIDFilter      func(namespaceID string) string
@@ -374,6 +395,19 @@ func (c *Collection) GetMapPropertyFnWithNamespaceFilter(key Key, defaultValue a
 	}
 }
 
+// GetMapPropertyFilteredByTaskQueueInfo gets property with taskQueueInfo as filters and asserts that it's a map
+func (c *Collection) GetMapPropertyFilteredByTaskQueueInfo(key Key, defaultValue any) MapPropertyFnWithTaskQueueInfoFilters {
+	return func(namespace string, taskQueue string, taskType enumspb.TaskQueueType) map[string]interface{} {
+		return matchAndConvert(
+			c,
+			key,
+			defaultValue,
+			taskQueuePrecedence(namespace, taskQueue, taskType),
+			convertMap,
+		)
+	}
+}
+
 // GetBoolPropertyFnWithNamespaceFilter gets property with namespace filter and asserts that it's a bool
 func (c *Collection) GetBoolPropertyFnWithNamespaceFilter(key Key, defaultValue any) BoolPropertyFnWithNamespaceFilter {
 	return func(namespace string) bool {
@@ -423,6 +457,198 @@ func (c *Collection) HasKey(key Key) bool {
 	return len(cvs) > 0
 }
 
+// SubscribeIntProperty calls callback whenever the value of an int property changes
+func (c *Collection) SubscribeIntProperty(key Key, defaultValue any, callback func(oldValue, newValue int)) SubscriptionCancelFn {
+	return subscribe(c, key, defaultValue, globalPrecedence(), convertInt, callback)
+}
+
+// SubscribeIntPropertyFilteredByNamespace calls callback whenever the value of an int property
+// for the given namespace changes
+func (c *Collection) SubscribeIntPropertyFilteredByNamespace(key Key, defaultValue any, namespace string, callback func(oldValue, newValue int)) SubscriptionCancelFn {
+	return subscribe(c, key, defaultValue, namespacePrecedence(namespace), convertInt, callback)
+}
+
+// SubscribeIntPropertyFilteredByTaskQueueInfo calls callback whenever the value of an int
+// property for the given task queue changes
+func (c *Collection) SubscribeIntPropertyFilteredByTaskQueueInfo(key Key, defaultValue any, namespace string, taskQueue string, taskType enumspb.TaskQueueType, callback func(oldValue, newValue int)) SubscriptionCancelFn {
+	return subscribe(c, key, defaultValue, taskQueuePrecedence(namespace, taskQueue, taskType), convertInt, callback)
+}
+
+// SubscribeIntPropertyFilteredByShardID calls callback whenever the value of an int property
+// for the given shard changes
+func (c *Collection) SubscribeIntPropertyFilteredByShardID(key Key, defaultValue any, shardID int32, callback func(oldValue, newValue int)) SubscriptionCancelFn {
+	return subscribe(c, key, defaultValue, shardIDPrecedence(shardID), convertInt, callback)
+}
+
+// SubscribeFloat64Property calls callback whenever the value of a float64 property changes
+func (c *Collection) SubscribeFloat64Property(key Key, defaultValue any, callback func(oldValue, newValue float64)) SubscriptionCancelFn {
+	return subscribe(c, key, defaultValue, globalPrecedence(), convertFloat, callback)
+}
+
+// SubscribeFloatPropertyFilteredByNamespace calls callback whenever the value of a float64
+// property for the given namespace changes
+func (c *Collection) SubscribeFloatPropertyFilteredByNamespace(key Key, defaultValue any, namespace string, callback func(oldValue, newValue float64)) SubscriptionCancelFn {
+	return subscribe(c, key, defaultValue, namespacePrecedence(namespace), convertFloat, callback)
+}
+
+// SubscribeFloatPropertyFilteredByTaskQueueInfo calls callback whenever the value of a float64
+// property for the given task queue changes
+func (c *Collection) SubscribeFloatPropertyFilteredByTaskQueueInfo(key Key, defaultValue any, namespace string, taskQueue string, taskType enumspb.TaskQueueType, callback func(oldValue, newValue float64)) SubscriptionCancelFn {
+	return subscribe(c, key, defaultValue, taskQueuePrecedence(namespace, taskQueue, taskType), convertFloat, callback)
+}
+
+// SubscribeDurationProperty calls callback whenever the value of a duration property changes
+func (c *Collection) SubscribeDurationProperty(key Key, defaultValue any, callback func(oldValue, newValue time.Duration)) SubscriptionCancelFn {
+	return subscribe(c, key, defaultValue, globalPrecedence(), convertDuration, callback)
+}
+
+// SubscribeDurationPropertyFilteredByNamespace calls callback whenever the value of a duration
+// property for the given namespace changes
+func (c *Collection) SubscribeDurationPropertyFilteredByNamespace(key Key, defaultValue any, namespace string, callback func(oldValue, newValue time.Duration)) SubscriptionCancelFn {
+	return subscribe(c, key, defaultValue, namespacePrecedence(namespace), convertDuration, callback)
+}
+
+// SubscribeDurationPropertyFilteredByTaskQueueInfo calls callback whenever the value of a
+// duration property for the given task queue changes
+func (c *Collection) SubscribeDurationPropertyFilteredByTaskQueueInfo(key Key, defaultValue any, namespace string, taskQueue string, taskType enumspb.TaskQueueType, callback func(oldValue, newValue time.Duration)) SubscriptionCancelFn {
+	return subscribe(c, key, defaultValue, taskQueuePrecedence(namespace, taskQueue, taskType), convertDuration, callback)
+}
+
+// SubscribeBoolProperty calls callback whenever the value of a bool property changes
+func (c *Collection) SubscribeBoolProperty(key Key, defaultValue any, callback func(oldValue, newValue bool)) SubscriptionCancelFn {
+	return subscribe(c, key, defaultValue, globalPrecedence(), convertBool, callback)
+}
+
+// SubscribeBoolPropertyFilteredByNamespace calls callback whenever the value of a bool property
+// for the given namespace changes
+func (c *Collection) SubscribeBoolPropertyFilteredByNamespace(key Key, defaultValue any, namespace string, callback func(oldValue, newValue bool)) SubscriptionCancelFn {
+	return subscribe(c, key, defaultValue, namespacePrecedence(namespace), convertBool, callback)
+}
+
+// SubscribeStringProperty calls callback whenever the value of a string property changes
+func (c *Collection) SubscribeStringProperty(key Key, defaultValue any, callback func(oldValue, newValue string)) SubscriptionCancelFn {
+	return subscribe(c, key, defaultValue, globalPrecedence(), convertString, callback)
+}
+
+// SubscribeMapProperty calls callback whenever the value of a map property changes
+func (c *Collection) SubscribeMapProperty(key Key, defaultValue any, callback func(oldValue, newValue map[string]any)) SubscriptionCancelFn {
+	return subscribe(c, key, defaultValue, globalPrecedence(), convertMap, callback)
+}
+
+// SubscribeMapPropertyFilteredByNamespace calls callback whenever the value of a map property
+// for the given namespace changes
+func (c *Collection) SubscribeMapPropertyFilteredByNamespace(key Key, defaultValue any, namespace string, callback func(oldValue, newValue map[string]any)) SubscriptionCancelFn {
+	return subscribe(c, key, defaultValue, namespacePrecedence(namespace), convertMap, callback)
+}
+
+// subscribe can't be a method of Collection because methods can't be generic.
+// The callback is only called when the client implements NotifyingClient, and only when the
+// converted value for the given precedence actually changes.
+func subscribe[T any](
+	c *Collection,
+	key Key,
+	defaultValue any,
+	precedence []Constraints,
+	converter func(value any) (T, error),
+	callback func(oldValue, newValue T),
+) SubscriptionCancelFn {
+	sub := &subscription{}
+	var prev T
+	sub.refresh = func() {
+		sub.lock.Lock()
+		defer sub.lock.Unlock()
+
+		cur := matchAndConvert(c, key, defaultValue, precedence, converter)
+		if reflect.DeepEqual(prev, cur) {
+			return
+		}
+		old := prev
+		prev = cur
+		callback(old, cur)
+	}
+
+	lowerKey := strings.ToLower(key.String())
+
+	// The subscription is registered before the initial value is read, so that a change made in
+	// between isn't lost. Holding sub.lock makes a refresh triggered by that change wait for the
+	// initial value.
+	sub.lock.Lock()
+	defer sub.lock.Unlock()
+	id := c.addSubscription(lowerKey, sub)
+	prev = matchAndConvert(c, key, defaultValue, precedence, converter)
+
+	return func() {
+		c.removeSubscription(lowerKey, id)
+	}
+}
+
+// SubscribeKey calls callback whenever any value of key changes, whatever its constraints. It is
+// meant for components that look up a key with many different constraints, such as per-namespace
+// weights of a host level scheduler, and re-read the values they need when called back.
+func (c *Collection) SubscribeKey(key Key, callback func()) SubscriptionCancelFn {
+	lowerKey := strings.ToLower(key.String())
+	id := c.addSubscription(lowerKey, &subscription{refresh: callback})
+	return func() {
+		c.removeSubscription(lowerKey, id)
+	}
+}
+
+// addSubscription registers sub for the lower-cased key and subscribes to the client on the first
+// subscription.
+func (c *Collection) addSubscription(lowerKey string, sub *subscription) int {
+	c.subscriptionLock.Lock()
+	defer c.subscriptionLock.Unlock()
+
+	if c.cancelClientSubscription == nil {
+		if notifyingClient, ok := c.client.(NotifyingClient); ok {
+			c.cancelClientSubscription = notifyingClient.Subscribe(c.keysChanged)
+		} else {
+			c.cancelClientSubscription = func() {}
+		}
+	}
+	if c.subscriptions == nil {
+		c.subscriptions = make(map[string]map[int]*subscription)
+	}
+	subs, ok := c.subscriptions[lowerKey]
+	if !ok {
+		subs = make(map[int]*subscription)
+		c.subscriptions[lowerKey] = subs
+	}
+	c.subscriptionIdx++
+	subs[c.subscriptionIdx] = sub
+	return c.subscriptionIdx
+}
+
+func (c *Collection) removeSubscription(lowerKey string, id int) {
+	c.subscriptionLock.Lock()
+	defer c.subscriptionLock.Unlock()
+	delete(c.subscriptions[lowerKey], id)
+	if len(c.subscriptions[lowerKey]) == 0 {
+		delete(c.subscriptions, lowerKey)
+	}
+	if len(c.subscriptions) == 0 && c.cancelClientSubscription != nil {
+		c.cancelClientSubscription()
+		c.cancelClientSubscription = nil
+	}
+}
+
+// keysChanged is registered with a NotifyingClient and re-evaluates all subscriptions on the
+// changed keys.
+func (c *Collection) keysChanged(changedKeys map[Key]struct{}) {
+	c.subscriptionLock.Lock()
+	var subs []*subscription
+	for key := range changedKeys {
+		for _, sub := range c.subscriptions[strings.ToLower(key.String())] {
+			subs = append(subs, sub)
+		}
+	}
+	c.subscriptionLock.Unlock()
+
+	for _, sub := range subs {
+		sub.refresh()
+	}
+}
+
 func findMatch(cvs, defaultCVs []ConstrainedValue, precedence []Constraints) (any, error) {
 	if len(cvs)+len(defaultCVs) == 0 {
 		return nil, errKeyNotPresent
@@ -25,9 +25,11 @@
 package dynamicconfig
 
 import (
+	"sync"
 	"testing"
 	"time"
 
+	"github.com/stretchr/testify/require"
 	"github.com/stretchr/testify/suite"
 
 	enumsspb "go.temporal.io/server/api/enums/v1"
@@ -45,6 +47,7 @@ const (
 	testGetBoolPropertyKey                            = "testGetBoolPropertyKey"
 	testGetStringPropertyKey                          = "testGetStringPropertyKey"
 	testGetMapPropertyKey                             = "testGetMapPropertyKey"
+	testGetMapPropertyFilteredByTaskQueueInfoKey      = "testGetMapPropertyFilteredByTaskQueueInfoKey"
 	testGetIntPropertyFilteredByNamespaceKey          = "testGetIntPropertyFilteredByNamespaceKey"
 	testGetDurationPropertyFilteredByNamespaceKey     = "testGetDurationPropertyFilteredByNamespaceKey"
 	testGetIntPropertyFilteredByTaskQueueInfoKey      = "testGetIntPropertyFilteredByTaskQueueInfoKey"
@@ -262,6 +265,21 @@ func (s *collectionSuite) TestGetMapProperty() {
 	s.Equal("321", value()["testKey"])
 }
 
+func (s *collectionSuite) TestGetMapPropertyFilteredByTaskQueueInfo() {
+	namespace := "testNamespace"
+	taskQueue := "testTaskQueue"
+	value := s.cln.GetMapPropertyFilteredByTaskQueueInfo(testGetMapPropertyFilteredByTaskQueueInfoKey, map[string]any{})
+	s.Empty(value(namespace, taskQueue, 0))
+	s.client[testGetMapPropertyFilteredByTaskQueueInfoKey] = []ConstrainedValue{
+		{
+			Constraints: Constraints{Namespace: namespace, TaskQueueName: taskQueue},
+			Value:       map[string]any{"testKey": 1.5},
+		},
+	}
+	s.Equal(map[string]any{"testKey": 1.5}, value(namespace, taskQueue, 0))
+	s.Empty(value(namespace, "otherTaskQueue", 0))
+}
+
 func (s *collectionSuite) TestFindMatch() {
 	testCases := []struct {
 		v       []ConstrainedValue
@@ -314,6 +332,54 @@ func (s *collectionSuite) TestFindMatch() {
 	}
 }
 
+// racingClient changes a value while the first subscription reads its initial value, like a
+// file update racing with Subscribe.
+type racingClient struct {
+	lock     sync.Mutex
+	value    int
+	read     bool
+	updateFn ClientUpdateFn
+}
+
+func (c *racingClient) GetValue(key Key) []ConstrainedValue {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	value := c.value
+	if !c.read {
+		c.read = true
+		c.value = 2
+		if updateFn := c.updateFn; updateFn != nil {
+			go updateFn(map[Key]struct{}{key: {}})
+		}
+	}
+	return []ConstrainedValue{{Value: value}}
+}
+
+func (c *racingClient) Subscribe(updateFn ClientUpdateFn) func() {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	c.updateFn = updateFn
+	return func() {}
+}
+
+func TestSubscribe_UpdateWhileSubscribing(t *testing.T) {
+	client := &racingClient{value: 1}
+	collection := NewCollection(client, log.NewNoopLogger())
+
+	changes := make(chan [2]int, 1)
+	cancel := collection.SubscribeIntProperty(testGetIntPropertyKey, 0, func(oldValue, newValue int) {
+		changes <- [2]int{oldValue, newValue}
+	})
+	defer cancel()
+
+	select {
+	case change := <-changes:
+		require.Equal(t, [2]int{1, 2}, change)
+	case <-time.After(5 * time.Second):
+		require.Fail(t, "update made while subscribing was lost")
+	}
+}
+
 func BenchmarkCollection(b *testing.B) {
 	// client with just one value
 	client1 := StaticClient(map[Key]any{
@@ -242,6 +242,13 @@ const (
 	FrontendPersistenceDynamicRateLimitingParams = "frontend.persistenceDynamicRateLimitingParams"
 	// FrontendVisibilityMaxPageSize is default max size for ListWorkflowExecutions in one page
 	FrontendVisibilityMaxPageSize = "frontend.visibilityMaxPageSize"
+	// FrontendVisibilityMaxCountGroups is the max number of groups returned by a CountWorkflowExecutions 'group by' query
+	FrontendVisibilityMaxCountGroups = "frontend.visibilityMaxCountGroups"
+	// FrontendVisibilityMaxQueryCost is the max estimated cost of a visibility query, 0 means no limit
+	FrontendVisibilityMaxQueryCost = "frontend.visibilityMaxQueryCost"
+	// FrontendVisibilityQueryCostPerSecond is the estimated cost of visibility queries a namespace
+	// can spend per second per instance, 0 means no limit
+	FrontendVisibilityQueryCostPerSecond = "frontend.visibilityQueryCostPerSecond"
 	// FrontendHistoryMaxPageSize is default max size for GetWorkflowExecutionHistory in one page
 	FrontendHistoryMaxPageSize = "frontend.historyMaxPageSize"
 	// FrontendRPS is workflow rate limit per second per-instance
@@ -469,6 +476,50 @@ const (
 	// MatchingMaxWaitForPollerBeforeFwd in presence of a non-negligible backlog, we resume forwarding tasks if the
 	// duration since last poll exceeds this threshold.
 	MatchingMaxWaitForPollerBeforeFwd = "matching.maxWaitForPollerBeforeFwd"
+	// MatchingFairnessKeySource is where the fairness key of a task is taken from: "workflowId", "searchAttribute"
+	// or "header". Backlog tasks with different keys are interleaved by weighted round robin when dispatched.
+	// Empty (the default) disables fairness and backlog is dispatched in order. History reads this key as well,
+	// since it attaches the fairness key when it adds tasks to matching.
+	MatchingFairnessKeySource = "matching.fairnessKeySource"
+	// MatchingFairnessKeyName is the name of the search attribute or workflow start header used as fairness key
+	// when MatchingFairnessKeySource is "searchAttribute" or "header".
+	MatchingFairnessKeyName = "matching.fairnessKeyName"
+	// MatchingFairnessKeyWeights maps fairness keys to their dispatch weight. Keys not listed have a weight of 1.
+	MatchingFairnessKeyWeights = "matching.fairnessKeyWeights"
+	// MatchingFairnessKeyBuckets is the number of round robin buckets fairness keys without an explicit weight are
+	// hashed into. This bounds the memory used by fairness when there are many distinct keys.
+	MatchingFairnessKeyBuckets = "matching.fairnessKeyBuckets"
+	// MatchingFairnessBacklogBufferSize is the max number of backlog tasks loaded into memory for fair dispatch.
+	// Tasks of keys that are at their share of it are skipped, see MatchingFairnessMaxBufferedTasksPerKey.
+	MatchingFairnessBacklogBufferSize = "matching.fairnessBacklogBufferSize"
+	// MatchingFairnessMaxBufferedTasksPerKey is the max number of tasks of one fairness key in the fairness buffer,
+	// multiplied by the weight of the key. Further tasks of the key are skipped so that the tasks of other keys
+	// behind them are loaded, and read again from persistence once the key has room in the buffer.
+	MatchingFairnessMaxBufferedTasksPerKey = "matching.fairnessMaxBufferedTasksPerKey"
+	// MatchingFairnessMaxDeferredTasks is the max number of skipped tasks a partition keeps track of. When it is
+	// reached the skipped tasks are loaded before the reader moves on, and 0 disables skipping.
+	MatchingFairnessMaxDeferredTasks = "matching.fairnessMaxDeferredTasks"
+	// MatchingEnableTaskPriority enables dispatching tasks by priority instead of in creation order. The backlog
+	// of each priority is persisted in a subqueue of the task queue partition, so that urgent tasks are read before
+	// the less urgent tasks in front of them. For the backlog it is only evaluated when the partition is loaded, and
+	// the subqueues are only read while it's enabled: disable it once the backlog of other priorities than the
+	// default one is drained.
+	MatchingEnableTaskPriority = "matching.enableTaskPriority"
+	// MatchingTaskPriorityAgingInterval is how long a task waits before its priority is raised by one level, so
+	// that less urgent tasks are not starved by a steady stream of more urgent ones. Zero disables aging.
+	MatchingTaskPriorityAgingInterval = "matching.taskPriorityAgingInterval"
+	// MatchingWorkflowTypeDispatchRPS maps workflow type names to the max rate, in tasks per second, at which workflow
+	// tasks of that type are dispatched from a task queue. The rate applies to the whole task queue and is divided
+	// among its read partitions. Types not in the map are only limited by the task queue's dispatch rate.
+	MatchingWorkflowTypeDispatchRPS = "matching.workflowTypeDispatchRPS"
+	// MatchingActivityTypeDispatchRPS maps activity type names to the max rate, in tasks per second, at which activity
+	// tasks of that type are dispatched from a task queue. The rate applies to the whole task queue and is divided
+	// among its read partitions. Types not in the map are only limited by the task queue's dispatch rate.
+	MatchingActivityTypeDispatchRPS = "matching.activityTypeDispatchRPS"
+	// MatchingThrottledBacklogBufferSize is the max number of backlog tasks of a rate limited workflow or activity
+	// type a partition holds in memory while they wait for the type's rate limit. When it's full, further tasks of the
+	// type are left in persistence and read again later, so the partition keeps dispatching other types.
+	MatchingThrottledBacklogBufferSize = "matching.throttledBacklogBufferSize"
 
 	// for matching testing only:
 
@@ -528,6 +579,9 @@ const (
 	HistoryStartupMembershipJoinDelay = "history.startupMembershipJoinDelay"
 	// HistoryShutdownDrainDuration is the duration of traffic drain during shutdown
 	HistoryShutdownDrainDuration = "history.shutdownDrainDuration"
+	// HistoryShutdownDrainShardsTimeout is the max duration a history instance waits during shutdown
+	// for its shards to be flushed and handed off to the other instances. Zero disables the drain on shutdown.
+	HistoryShutdownDrainShardsTimeout = "history.shutdownDrainShardsTimeout"
 	// XDCCacheMaxSizeBytes is max size of events cache in bytes
 	XDCCacheMaxSizeBytes = "history.xdcCacheMaxSizeBytes"
 	// EventsCacheMaxSizeBytes is max size of events cache in bytes
@@ -732,6 +786,17 @@ const (
 	ArchivalProcessorArchiveDelay = "history.archivalProcessorArchiveDelay"
 	// ArchivalBackendMaxRPS is the maximum rate of requests per second to the archival backend
 	ArchivalBackendMaxRPS = "history.archivalBackendMaxRPS"
+	// HistoryExportURI is the file:// or s3:// URI that the histories and visibility records of the closed workflow
+	// executions of a namespace are exported to for analytics. Export is disabled when it's empty.
+	HistoryExportURI = "history.exportURI"
+	// HistoryExportFormat is the format of exported histories and visibility records, either "jsonl" or "parquet"
+	HistoryExportFormat = "history.exportFormat"
+	// HistoryExportBatchInterval is the time window in which the workflow executions exported to the same partition
+	// are batched into one file
+	HistoryExportBatchInterval = "history.exportBatchInterval"
+	// HistoryExportBatchMaxExecutions is the max number of workflow executions in a batch of exported files, a batch
+	// is written before the end of its time window when it's full
+	HistoryExportBatchMaxExecutions = "history.exportBatchMaxExecutions"
 
 	// WorkflowExecutionMaxInFlightUpdates is the max number of updates that can be in-flight (admitted but not yet completed) for any given workflow execution.
 	WorkflowExecutionMaxInFlightUpdates = "history.maxInFlightUpdates"
@@ -925,6 +990,32 @@ const (
 	// HistoryScannerVerifyRetention indicates the history scanner verify data retention.
 	// If the service configures with archival feature enabled, update worker.historyScannerVerifyRetention to be double of the data retention.
 	HistoryScannerVerifyRetention = "worker.historyScannerVerifyRetention"
+	// ArchivalVerifierEnabled indicates if the archival verifier should be started as part of worker.Scanner
+	ArchivalVerifierEnabled = "worker.archivalVerifierEnabled"
+	// ArchivalVerifierSampleRate is the fraction of closed executions whose archived history is verified in each run.
+	// A value of 1.0 verifies every archived execution.
+	ArchivalVerifierSampleRate = "worker.archivalVerifierSampleRate"
+	// ArchivalVerifierRPS is the rate limit of executions verified per second by the archival verifier
+	ArchivalVerifierRPS = "worker.archivalVerifierRPS"
+	// ArchivalVerifierRepairEnabled indicates if the archival verifier should re-archive missing or corrupt histories
+	// from primary storage while they still exist there
+	ArchivalVerifierRepairEnabled = "worker.archivalVerifierRepairEnabled"
+	// ArchivalVerifierMinCloseAge is the minimum time since close before an execution is expected to be archived
+	ArchivalVerifierMinCloseAge = "worker.archivalVerifierMinCloseAge"
+	// VisibilityScavengerEnabled indicates if the visibility scavenger should be started as part of worker.Scanner
+	VisibilityScavengerEnabled = "worker.visibilityScavengerEnabled"
+	// VisibilityScavengerSampleRate is the fraction of visibility records checked against mutable state in each run.
+	// Each namespace lists a random window of start times covering that fraction of its retention, so the load on the
+	// visibility store scales with it. A value of 1.0 checks every record.
+	VisibilityScavengerSampleRate = "worker.visibilityScavengerSampleRate"
+	// VisibilityScavengerRPS is the rate limit of visibility records checked per second by the visibility scavenger
+	VisibilityScavengerRPS = "worker.visibilityScavengerRPS"
+	// VisibilityScavengerRepairEnabled indicates if the visibility scavenger should fix the records that don't match
+	// mutable state, by re-enqueuing visibility tasks or deleting orphaned records
+	VisibilityScavengerRepairEnabled = "worker.visibilityScavengerRepairEnabled"
+	// VisibilityScavengerMinAge is the minimum time since the last update of an execution before its visibility
+	// record is expected to match mutable state
+	VisibilityScavengerMinAge = "worker.visibilityScavengerMinAge"
 	// EnableBatcher decides whether start batcher in our worker
 	EnableBatcher = "worker.enableBatcher"
 	// BatcherRPS controls number the rps of batch operations
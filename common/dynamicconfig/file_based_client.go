@@ -32,10 +32,12 @@ import (
 	"os"
 	"reflect"
 	"strings"
+	"sync"
 	"sync/atomic"
 	"time"
 
 	enumspb "go.temporal.io/api/enums/v1"
+	"go.uber.org/multierr"
 	"gopkg.in/yaml.v3"
 
 	enumsspb "go.temporal.io/server/api/enums/v1"
@@ -43,7 +45,7 @@ import (
 	"go.temporal.io/server/common/log/tag"
 )
 
-var _ Client = (*fileBasedClient)(nil)
+var _ NotifyingClient = (*fileBasedClient)(nil)
 
 const (
 	minPollInterval = time.Second * 5
@@ -62,6 +64,9 @@ type (
 	FileBasedClientConfig struct {
 		Filepath     string        `yaml:"filepath"`
 		PollInterval time.Duration `yaml:"pollInterval"`
+		// StrictValidation rejects a config file that has unknown keys, values of the wrong
+		// type or unsupported constraints. Otherwise each problem is logged as a warning.
+		StrictValidation bool `yaml:"strictValidation"`
 	}
 
 	configValueMap map[string][]ConstrainedValue
@@ -73,6 +78,10 @@ type (
 		lastUpdatedTime time.Time
 		config          *FileBasedClientConfig
 		doneCh          <-chan interface{}
+
+		subscriptionLock sync.Mutex
+		subscriptionIdx  int
+		subscriptions    map[int]ClientUpdateFn
 	}
 
 	osReader struct {
@@ -117,6 +126,24 @@ func (fc *fileBasedClient) GetValue(key Key) []ConstrainedValue {
 	return values[strings.ToLower(key.String())]
 }
 
+func (fc *fileBasedClient) Subscribe(callback ClientUpdateFn) (cancel func()) {
+	fc.subscriptionLock.Lock()
+	defer fc.subscriptionLock.Unlock()
+
+	if fc.subscriptions == nil {
+		fc.subscriptions = make(map[int]ClientUpdateFn)
+	}
+	fc.subscriptionIdx++
+	id := fc.subscriptionIdx
+	fc.subscriptions[id] = callback
+
+	return func() {
+		fc.subscriptionLock.Lock()
+		defer fc.subscriptionLock.Unlock()
+		delete(fc.subscriptions, id)
+	}
+}
+
 func (fc *fileBasedClient) init() error {
 	if err := fc.validateConfig(fc.config); err != nil {
 		return fmt.Errorf("unable to validate dynamic config: %w", err)
@@ -163,38 +190,64 @@ func (fc *fileBasedClient) update() error {
 		return fmt.Errorf("dynamic config file: %s: %w", fc.config.Filepath, err)
 	}
 
+	values, err := loadYamlFile(confContent)
+	if err != nil {
+		return err
+	}
+
+	if validationErrs := validateValues(values); len(validationErrs) > 0 {
+		if fc.config.StrictValidation {
+			return fmt.Errorf("dynamic config file: %s: %w", fc.config.Filepath, multierr.Combine(validationErrs...))
+		}
+		for _, validationErr := range validationErrs {
+			fc.logger.Warn("Invalid dynamic config value, it may be ignored.", tag.Error(validationErr))
+		}
+	}
+
+	newValues := make(configValueMap, len(values))
+	for key, cvs := range values {
+		newValues[strings.ToLower(key)] = cvs
+	}
+
+	prev := fc.values.Swap(newValues)
+	oldValues, _ := prev.(configValueMap)
+	fc.logDiff(oldValues, newValues)
+	fc.logger.Info("Updated dynamic config")
+	fc.notifySubscribers(oldValues, newValues)
+
+	return nil
+}
+
+// loadYamlFile parses the contents of a dynamic config file. Keys are returned in their
+// original case.
+func loadYamlFile(contents []byte) (map[string][]ConstrainedValue, error) {
 	var yamlValues map[string][]struct {
 		Constraints map[string]any
 		Value       any
 	}
-	if err = yaml.Unmarshal(confContent, &yamlValues); err != nil {
-		return fmt.Errorf("unable to decode dynamic config: %w", err)
+	if err := yaml.Unmarshal(contents, &yamlValues); err != nil {
+		return nil, fmt.Errorf("unable to decode dynamic config: %w", err)
 	}
 
-	newValues := make(configValueMap, len(yamlValues))
+	values := make(map[string][]ConstrainedValue, len(yamlValues))
 	for key, yamlCV := range yamlValues {
 		cvs := make([]ConstrainedValue, len(yamlCV))
 		for i, cv := range yamlCV {
+			var err error
 			// yaml will unmarshal map into map[interface{}]interface{} instead of map[string]interface{}
 			// manually convert key type to string for all values here
 			cvs[i].Value, err = convertKeyTypeToString(cv.Value)
 			if err != nil {
-				return err
+				return nil, err
 			}
 			cvs[i].Constraints, err = convertYamlConstraints(cv.Constraints)
 			if err != nil {
-				return err
+				return nil, err
 			}
 		}
-		newValues[strings.ToLower(key)] = cvs
+		values[key] = cvs
 	}
-
-	prev := fc.values.Swap(newValues)
-	oldValues, _ := prev.(configValueMap)
-	fc.logDiff(oldValues, newValues)
-	fc.logger.Info("Updated dynamic config")
-
-	return nil
+	return values, nil
 }
 
 func (fc *fileBasedClient) validateConfig(config *FileBasedClientConfig) error {
@@ -234,6 +287,62 @@ func (fc *fileBasedClient) logDiff(old configValueMap, new configValueMap) {
 	}
 }
 
+func (fc *fileBasedClient) notifySubscribers(old configValueMap, new configValueMap) {
+	fc.subscriptionLock.Lock()
+	callbacks := make([]ClientUpdateFn, 0, len(fc.subscriptions))
+	for _, callback := range fc.subscriptions {
+		callbacks = append(callbacks, callback)
+	}
+	fc.subscriptionLock.Unlock()
+
+	if len(callbacks) == 0 {
+		return
+	}
+	changedKeys := diffKeys(old, new)
+	if len(changedKeys) == 0 {
+		return
+	}
+	for _, callback := range callbacks {
+		callback(changedKeys)
+	}
+}
+
+// diffKeys returns the keys whose set of ConstrainedValues differs between old and new.
+// Order of ConstrainedValues within a key is ignored.
+func diffKeys(old configValueMap, new configValueMap) map[Key]struct{} {
+	changedKeys := make(map[Key]struct{})
+	for key, newValues := range new {
+		if !constrainedValuesEqual(old[key], newValues) {
+			changedKeys[Key(key)] = struct{}{}
+		}
+	}
+	for key := range old {
+		if _, ok := new[key]; !ok {
+			changedKeys[Key(key)] = struct{}{}
+		}
+	}
+	return changedKeys
+}
+
+func constrainedValuesEqual(a []ConstrainedValue, b []ConstrainedValue) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for _, aValue := range a {
+		matchFound := false
+		for _, bValue := range b {
+			if aValue.Constraints == bValue.Constraints && reflect.DeepEqual(aValue.Value, bValue.Value) {
+				matchFound = true
+				break
+			}
+		}
+		if !matchFound {
+			return false
+		}
+	}
+	return true
+}
+
 func (fc *fileBasedClient) logConstraintsDiff(key string, oldValues []ConstrainedValue, newValues []ConstrainedValue) {
 	for _, oldValue := range oldValues {
 		matchFound := false
@@ -280,28 +389,32 @@ func (fc *fileBasedClient) appendConstrainedValue(logLine *strings.Builder, valu
 		logLine.WriteString("nil")
 	} else {
 		logLine.WriteString("{ constraints: {")
-		if value.Constraints.Namespace != "" {
-			logLine.WriteString(fmt.Sprintf("{Namespace:%s}", value.Constraints.Namespace))
-		}
-		if value.Constraints.NamespaceID != "" {
-			logLine.WriteString(fmt.Sprintf("{NamespaceID:%s}", value.Constraints.NamespaceID))
-		}
-		if value.Constraints.TaskQueueName != "" {
-			logLine.WriteString(fmt.Sprintf("{TaskQueueName:%s}", value.Constraints.TaskQueueName))
-		}
-		if value.Constraints.TaskQueueType != enumspb.TASK_QUEUE_TYPE_UNSPECIFIED {
-			logLine.WriteString(fmt.Sprintf("{TaskQueueType:%s}", value.Constraints.TaskQueueType))
-		}
-		if value.Constraints.ShardID != 0 {
-			logLine.WriteString(fmt.Sprintf("{ShardID:%d}", value.Constraints.ShardID))
-		}
-		if value.Constraints.TaskType != enumsspb.TASK_TYPE_UNSPECIFIED {
-			logLine.WriteString(fmt.Sprintf("{HistoryTaskType:%s}", value.Constraints.TaskType))
-		}
+		appendConstraints(logLine, value.Constraints)
 		logLine.WriteString(fmt.Sprint("} value: ", value.Value, " }"))
 	}
 }
 
+func appendConstraints(logLine *strings.Builder, cs Constraints) {
+	if cs.Namespace != "" {
+		logLine.WriteString(fmt.Sprintf("{Namespace:%s}", cs.Namespace))
+	}
+	if cs.NamespaceID != "" {
+		logLine.WriteString(fmt.Sprintf("{NamespaceID:%s}", cs.NamespaceID))
+	}
+	if cs.TaskQueueName != "" {
+		logLine.WriteString(fmt.Sprintf("{TaskQueueName:%s}", cs.TaskQueueName))
+	}
+	if cs.TaskQueueType != enumspb.TASK_QUEUE_TYPE_UNSPECIFIED {
+		logLine.WriteString(fmt.Sprintf("{TaskQueueType:%s}", cs.TaskQueueType))
+	}
+	if cs.ShardID != 0 {
+		logLine.WriteString(fmt.Sprintf("{ShardID:%d}", cs.ShardID))
+	}
+	if cs.TaskType != enumsspb.TASK_TYPE_UNSPECIFIED {
+		logLine.WriteString(fmt.Sprintf("{HistoryTaskType:%s}", cs.TaskType))
+	}
+}
+
 func convertKeyTypeToString(v interface{}) (interface{}, error) {
 	switch v := v.(type) {
 	case map[interface{}]interface{}:
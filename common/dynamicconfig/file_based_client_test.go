@@ -285,6 +285,8 @@ func (s *fileBasedClientSuite) TestUpdate_ChangedValue() {
 	doneCh := make(chan interface{})
 	reader := NewMockfileReader(ctrl)
 	mockLogger := log.NewMockLogger(ctrl)
+	// test keys are not registered in keyDefinitions
+	mockLogger.EXPECT().Warn(gomock.Any(), gomock.Any()).AnyTimes()
 
 	updateInterval := time.Minute * 5
 	originFileInfo := &MockFileInfo{ModTimeValue: time.Now()}
@@ -360,6 +362,8 @@ func (s *fileBasedClientSuite) TestUpdate_ChangedMapValue() {
 	doneCh := make(chan interface{})
 	reader := NewMockfileReader(ctrl)
 	mockLogger := log.NewMockLogger(ctrl)
+	// test keys are not registered in keyDefinitions
+	mockLogger.EXPECT().Warn(gomock.Any(), gomock.Any()).AnyTimes()
 
 	updateInterval := time.Minute * 5
 	originFileInfo := &MockFileInfo{ModTimeValue: time.Now()}
@@ -410,6 +414,8 @@ func (s *fileBasedClientSuite) TestUpdate_NewEntry() {
 	doneCh := make(chan interface{})
 	reader := NewMockfileReader(ctrl)
 	mockLogger := log.NewMockLogger(ctrl)
+	// test keys are not registered in keyDefinitions
+	mockLogger.EXPECT().Warn(gomock.Any(), gomock.Any()).AnyTimes()
 
 	updateInterval := time.Minute * 5
 	originFileInfo := &MockFileInfo{ModTimeValue: time.Now()}
@@ -463,6 +469,8 @@ func (s *fileBasedClientSuite) TestUpdate_ChangeOrder_ShouldNotWriteLog() {
 	doneCh := make(chan interface{})
 	reader := NewMockfileReader(ctrl)
 	mockLogger := log.NewMockLogger(ctrl)
+	// test keys are not registered in keyDefinitions
+	mockLogger.EXPECT().Warn(gomock.Any(), gomock.Any()).AnyTimes()
 
 	updateInterval := time.Minute * 5
 	originFileInfo := &MockFileInfo{ModTimeValue: time.Now()}
@@ -512,3 +520,204 @@ testGetFloat64PropertyKey:
 	s.NoError(err)
 	close(doneCh)
 }
+
+func (s *fileBasedClientSuite) TestSubscribe_OnlyChangedValues() {
+	ctrl := gomock.NewController(s.T())
+	defer ctrl.Finish()
+
+	doneCh := make(chan interface{})
+	defer close(doneCh)
+	reader := NewMockfileReader(ctrl)
+
+	updateInterval := time.Minute * 5
+	originFileInfo := &MockFileInfo{ModTimeValue: time.Now()}
+	updatedFileInfo := &MockFileInfo{ModTimeValue: originFileInfo.ModTimeValue.Add(updateInterval + time.Second)}
+
+	originFileData := []byte(`
+testGetIntPropertyKey:
+- value: 1000
+  constraints: {}
+
+testGetBoolPropertyKey:
+- value: false
+  constraints: {}
+- value: true
+  constraints:
+    namespace: samples-namespace
+`)
+	updatedFileData := []byte(`
+testGetIntPropertyKey:
+- value: 2000
+  constraints: {}
+
+testGetBoolPropertyKey:
+- value: false
+  constraints: {}
+- value: false
+  constraints:
+    namespace: samples-namespace
+`)
+
+	reader.EXPECT().Stat(gomock.Any()).Return(originFileInfo, nil).Times(2)
+	reader.EXPECT().ReadFile(gomock.Any()).Return(originFileData, nil)
+
+	client, err := NewFileBasedClientWithReader(reader,
+		&FileBasedClientConfig{
+			Filepath:     "anyValue",
+			PollInterval: updateInterval,
+		}, log.NewNoopLogger(), doneCh)
+	s.NoError(err)
+	collection := NewCollection(client, log.NewNoopLogger())
+
+	var intChanges [][2]int
+	collection.SubscribeIntProperty(testGetIntPropertyKey, 1, func(oldValue, newValue int) {
+		intChanges = append(intChanges, [2]int{oldValue, newValue})
+	})
+	var nsBoolChanges [][2]bool
+	collection.SubscribeBoolPropertyFilteredByNamespace(testGetBoolPropertyKey, false, "samples-namespace", func(oldValue, newValue bool) {
+		nsBoolChanges = append(nsBoolChanges, [2]bool{oldValue, newValue})
+	})
+	// the key changes, but the resolved value for this namespace does not
+	otherNsBoolCalled := false
+	collection.SubscribeBoolPropertyFilteredByNamespace(testGetBoolPropertyKey, false, "other-namespace", func(oldValue, newValue bool) {
+		otherNsBoolCalled = true
+	})
+	cancelledCalled := false
+	cancel := collection.SubscribeIntProperty(testGetIntPropertyKey, 1, func(oldValue, newValue int) {
+		cancelledCalled = true
+	})
+	cancel()
+
+	reader.EXPECT().Stat(gomock.Any()).Return(updatedFileInfo, nil)
+	reader.EXPECT().ReadFile(gomock.Any()).Return(updatedFileData, nil)
+	s.NoError(client.update())
+
+	s.Equal([][2]int{{1000, 2000}}, intChanges)
+	s.Equal([][2]bool{{true, false}}, nsBoolChanges)
+	s.False(otherNsBoolCalled)
+	s.False(cancelledCalled)
+}
+
+func (s *fileBasedClientSuite) TestSubscribeKey() {
+	ctrl := gomock.NewController(s.T())
+	defer ctrl.Finish()
+
+	doneCh := make(chan interface{})
+	defer close(doneCh)
+	reader := NewMockfileReader(ctrl)
+
+	originFileInfo := &MockFileInfo{ModTimeValue: time.Now()}
+	originFileData := []byte(`
+testGetMapPropertyKey:
+- value:
+    key1: 1
+  constraints:
+    namespace: samples-namespace
+testGetIntPropertyKey:
+- value: 1000
+  constraints: {}
+`)
+	reader.EXPECT().Stat(gomock.Any()).Return(originFileInfo, nil).Times(2)
+	reader.EXPECT().ReadFile(gomock.Any()).Return(originFileData, nil)
+
+	client, err := NewFileBasedClientWithReader(reader,
+		&FileBasedClientConfig{
+			Filepath:     "anyValue",
+			PollInterval: time.Minute * 5,
+		}, log.NewNoopLogger(), doneCh)
+	s.NoError(err)
+	collection := NewCollection(client, log.NewNoopLogger())
+
+	calls := 0
+	cancel := collection.SubscribeKey(testGetMapPropertyKey, func() {
+		calls++
+	})
+	defer cancel()
+
+	// only another key changes
+	reader.EXPECT().Stat(gomock.Any()).Return(&MockFileInfo{ModTimeValue: originFileInfo.ModTimeValue.Add(time.Minute)}, nil)
+	reader.EXPECT().ReadFile(gomock.Any()).Return([]byte(`
+testGetMapPropertyKey:
+- value:
+    key1: 1
+  constraints:
+    namespace: samples-namespace
+testGetIntPropertyKey:
+- value: 2000
+  constraints: {}
+`), nil)
+	s.NoError(client.update())
+	s.Equal(0, calls)
+
+	// the value of the key changes for a single namespace
+	reader.EXPECT().Stat(gomock.Any()).Return(&MockFileInfo{ModTimeValue: originFileInfo.ModTimeValue.Add(2 * time.Minute)}, nil)
+	reader.EXPECT().ReadFile(gomock.Any()).Return([]byte(`
+testGetMapPropertyKey:
+- value:
+    key1: 2
+  constraints:
+    namespace: samples-namespace
+testGetIntPropertyKey:
+- value: 2000
+  constraints: {}
+`), nil)
+	s.NoError(client.update())
+	s.Equal(1, calls)
+}
+
+func (s *fileBasedClientSuite) TestSubscribe_CancelReleasesClientSubscription() {
+	ctrl := gomock.NewController(s.T())
+	defer ctrl.Finish()
+
+	doneCh := make(chan interface{})
+	defer close(doneCh)
+	reader := NewMockfileReader(ctrl)
+
+	fileData := []byte(`
+testGetIntPropertyKey:
+- value: 1000
+  constraints: {}
+`)
+	reader.EXPECT().Stat(gomock.Any()).Return(&MockFileInfo{ModTimeValue: time.Now()}, nil).Times(2)
+	reader.EXPECT().ReadFile(gomock.Any()).Return(fileData, nil)
+
+	client, err := NewFileBasedClientWithReader(reader,
+		&FileBasedClientConfig{
+			Filepath:     "anyValue",
+			PollInterval: time.Minute * 5,
+		}, log.NewNoopLogger(), doneCh)
+	s.NoError(err)
+	collection := NewCollection(client, log.NewNoopLogger())
+
+	cancel1 := collection.SubscribeIntProperty(testGetIntPropertyKey, 1, func(oldValue, newValue int) {})
+	cancel2 := collection.SubscribeBoolProperty(testGetBoolPropertyKey, false, func(oldValue, newValue bool) {})
+	s.Len(client.subscriptions, 1)
+
+	cancel1()
+	s.Len(client.subscriptions, 1)
+	cancel2()
+	s.Empty(client.subscriptions)
+
+	cancel3 := collection.SubscribeIntProperty(testGetIntPropertyKey, 1, func(oldValue, newValue int) {})
+	s.Len(client.subscriptions, 1)
+	cancel3()
+	s.Empty(client.subscriptions)
+}
+
+func (s *fileBasedClientSuite) TestDiffKeys() {
+	old := configValueMap{
+		"unchanged": {{Value: 1}, {Constraints: Constraints{Namespace: "ns"}, Value: 2}},
+		"changed":   {{Value: 1}},
+		"removed":   {{Value: 1}},
+	}
+	new := configValueMap{
+		"unchanged": {{Constraints: Constraints{Namespace: "ns"}, Value: 2}, {Value: 1}},
+		"changed":   {{Value: 2}},
+		"added":     {{Value: 1}},
+	}
+	s.Equal(map[Key]struct{}{
+		"changed": {},
+		"removed": {},
+		"added":   {},
+	}, diffKeys(old, new))
+}
@@ -0,0 +1,427 @@
+// The MIT License
+//
+// Copyright (c) 2020 Temporal Technologies Inc.  All rights reserved.
+//
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package dynamicconfig
+
+// keyDefinitions lists every key in constants.go with the value type and constraints that
+// the server reads it with. When adding a key to constants.go, add it here too.
+var keyDefinitions = []KeyDefinition{
+	{Key: AdminMatchingNamespaceToPartitionDispatchRate, Type: ValueTypeFloat, Constraints: ConstraintNamespace, Description: "The max qps of any task queue partition for a given namespace"},
+	{Key: AdminMatchingNamespaceTaskqueueToPartitionDispatchRate, Type: ValueTypeFloat, Constraints: ConstraintNamespace | ConstraintTaskQueue, Description: "The max qps of a task queue partition for a given namespace & task queue"},
+	{Key: StandardVisibilityPersistenceMaxReadQPS, Type: ValueTypeInt, Constraints: ConstraintNone, Description: "The max QPC system host can query standard visibility DB (SQL or Cassandra) for read."},
+	{Key: StandardVisibilityPersistenceMaxWriteQPS, Type: ValueTypeInt, Constraints: ConstraintNone, Description: "The max QPC system host can query standard visibility DB (SQL or Cassandra) for write."},
+	{Key: AdvancedVisibilityPersistenceMaxReadQPS, Type: ValueTypeInt, Constraints: ConstraintNone, Description: "The max QPC system host can query advanced visibility DB (Elasticsearch) for read."},
+	{Key: AdvancedVisibilityPersistenceMaxWriteQPS, Type: ValueTypeInt, Constraints: ConstraintNone, Description: "The max QPC system host can query advanced visibility DB (Elasticsearch) for write."},
+	{Key: AdvancedVisibilityWritingMode, Type: ValueTypeString, Constraints: ConstraintNone, Description: "Key for how to write to advanced visibility"},
+	{Key: EnableWriteToSecondaryAdvancedVisibility, Type: ValueTypeBool, Constraints: ConstraintNone, Description: "The config to enable write to secondary visibility for Elasticsearch"},
+	{Key: EnableReadVisibilityFromES, Type: ValueTypeBool, Constraints: ConstraintNamespace, Description: "Key for enable read from Elasticsearch"},
+	{Key: EnableReadFromSecondaryAdvancedVisibility, Type: ValueTypeBool, Constraints: ConstraintNamespace, Description: "The config to enable read from secondary Elasticsearch"},
+	{Key: VisibilityPersistenceMaxReadQPS, Type: ValueTypeInt, Constraints: ConstraintNone, Description: "The max QPC system host can query visibility DB for read."},
+	{Key: VisibilityPersistenceMaxWriteQPS, Type: ValueTypeInt, Constraints: ConstraintNone, Description: "The max QPC system host can query visibility DB for write."},
+	{Key: EnableReadFromSecondaryVisibility, Type: ValueTypeBool, Constraints: ConstraintNamespace, Description: "The config to enable read from secondary visibility"},
+	{Key: SecondaryVisibilityWritingMode, Type: ValueTypeString, Constraints: ConstraintNone, Description: "Key for how to write to secondary visibility"},
+	{Key: VisibilityDisableOrderByClause, Type: ValueTypeBool, Constraints: ConstraintNamespace, Description: "The config to disable ORDERY BY clause for Elasticsearch"},
+	{Key: VisibilityEnableManualPagination, Type: ValueTypeBool, Constraints: ConstraintNamespace, Description: "The config to enable manual pagination for Elasticsearch"},
+	{Key: HistoryArchivalState, Type: ValueTypeString, Constraints: ConstraintNone, Description: "Key for the state of history archival"},
+	{Key: EnableReadFromHistoryArchival, Type: ValueTypeBool, Constraints: ConstraintNone, Description: "Key for enabling reading history from archival store"},
+	{Key: VisibilityArchivalState, Type: ValueTypeString, Constraints: ConstraintNone, Description: "Key for the state of visibility archival"},
+	{Key: EnableReadFromVisibilityArchival, Type: ValueTypeBool, Constraints: ConstraintNone, Description: "Key for enabling reading visibility from archival store"},
+	{Key: EnableNamespaceNotActiveAutoForwarding, Type: ValueTypeBool, Constraints: ConstraintNamespace, Description: "Whether enabling DC auto forwarding to active cluster for signal / start / signal with start API if namespace is not active"},
+	{Key: TransactionSizeLimit, Type: ValueTypeInt, Constraints: ConstraintNone, Description: "The largest allowed transaction size to persistence"},
+	{Key: DisallowQuery, Type: ValueTypeBool, Constraints: ConstraintNamespace, Description: "The key to disallow query for a namespace"},
+	{Key: EnableAuthorization, Type: ValueTypeBool, Constraints: ConstraintNamespace, Description: "The key to enable authorization for a namespace"},
+	{Key: EnableCrossNamespaceCommands, Type: ValueTypeBool, Constraints: ConstraintNone, Description: "The key to enable commands for external namespaces"},
+	{Key: ClusterMetadataRefreshInterval, Type: ValueTypeDuration, Constraints: ConstraintNone, Description: "Config to manage cluster metadata table refresh interval"},
+	{Key: ForceSearchAttributesCacheRefreshOnRead, Type: ValueTypeBool, Constraints: ConstraintNone, Description: "Forces refreshing search attributes cache on a read operation, so we always get the latest data from DB. This effectively bypasses cache value and is used to facilitate testing of changes in search attributes. This should not be turned on in production."},
+	{Key: EnableRingpopTLS, Type: ValueTypeBool, Constraints: ConstraintNone, Description: "Enables TLS for ringpop membership traffic"},
+	{Key: EnableParentClosePolicyWorker, Type: ValueTypeBool, Constraints: ConstraintNone, Description: "Decides whether or not enable system workers for processing parent close policy task"},
+	{Key: EnableStickyQuery, Type: ValueTypeBool, Constraints: ConstraintNamespace, Description: "Indicates if sticky query should be enabled per namespace"},
+	{Key: EnableActivityEagerExecution, Type: ValueTypeBool, Constraints: ConstraintNamespace, Description: "Indicates if activity eager execution is enabled per namespace"},
+	{Key: EnableEagerWorkflowStart, Type: ValueTypeBool, Constraints: ConstraintNamespace, Description: "Toggles \"eager workflow start\" - returning the first workflow task inline in the response to a StartWorkflowExecution request and skipping the trip through matching."},
+	{Key: NamespaceCacheRefreshInterval, Type: ValueTypeDuration, Constraints: ConstraintNone, Description: "The key for namespace cache refresh interval dynamic config"},
+	{Key: PersistenceHealthSignalMetricsEnabled, Type: ValueTypeBool, Constraints: ConstraintNone, Description: "Determines whether persistence shard RPS metrics are emitted"},
+	{Key: PersistenceHealthSignalAggregationEnabled, Type: ValueTypeBool, Constraints: ConstraintNone, Description: "Determines whether persistence latency and error averages are tracked"},
+	{Key: PersistenceHealthSignalWindowSize, Type: ValueTypeDuration, Constraints: ConstraintNone, Description: "The time window size in seconds for aggregating persistence signals"},
+	{Key: PersistenceHealthSignalBufferSize, Type: ValueTypeInt, Constraints: ConstraintNone, Description: "The maximum number of persistence signals to buffer in memory per signal key"},
+	{Key: ShardRPSWarnLimit, Type: ValueTypeInt, Constraints: ConstraintNone, Description: "The per-shard RPS limit for warning"},
+	{Key: ShardPerNsRPSWarnPercent, Type: ValueTypeFloat, Constraints: ConstraintNone, Description: "The per-shard per-namespace RPS limit for warning as a percentage of ShardRPSWarnLimit these warning are not emitted if the value is set to 0 or less"},
+	{Key: OperatorRPSRatio, Type: ValueTypeFloat, Constraints: ConstraintNone, Description: "The percentage of the rate limit provided to priority rate limiters that should be used for operator API calls (highest priority). Should be >0.0 and <= 1.0 (defaults to 20% if not specified)"},
+	{Key: DeadlockDumpGoroutines, Type: ValueTypeBool, Constraints: ConstraintNone, Description: "Whether the deadlock detector should dump goroutines"},
+	{Key: DeadlockFailHealthCheck, Type: ValueTypeBool, Constraints: ConstraintNone, Description: "Whether the deadlock detector should cause the grpc server to fail health checks"},
+	{Key: DeadlockAbortProcess, Type: ValueTypeBool, Constraints: ConstraintNone, Description: "Whether the deadlock detector should abort the process"},
+	{Key: DeadlockInterval, Type: ValueTypeDuration, Constraints: ConstraintNone, Description: "How often the detector checks each root."},
+	{Key: DeadlockMaxWorkersPerRoot, Type: ValueTypeInt, Constraints: ConstraintNone, Description: "How many extra goroutines can be created per root."},
+	{Key: BlobSizeLimitError, Type: ValueTypeInt, Constraints: ConstraintNamespace, Description: "The per event blob size limit"},
+	{Key: BlobSizeLimitWarn, Type: ValueTypeInt, Constraints: ConstraintNamespace, Description: "The per event blob size limit for warning"},
+	{Key: MemoSizeLimitError, Type: ValueTypeInt, Constraints: ConstraintNamespace, Description: "The per event memo size limit"},
+	{Key: MemoSizeLimitWarn, Type: ValueTypeInt, Constraints: ConstraintNamespace, Description: "The per event memo size limit for warning"},
+	{Key: NumPendingChildExecutionsLimitError, Type: ValueTypeInt, Constraints: ConstraintNamespace, Description: "The maximum number of pending child workflows a workflow can have before StartChildWorkflowExecution commands will fail."},
+	{Key: NumPendingActivitiesLimitError, Type: ValueTypeInt, Constraints: ConstraintNamespace, Description: "The maximum number of pending activities a workflow can have before ScheduleActivityTask will fail."},
+	{Key: NumPendingSignalsLimitError, Type: ValueTypeInt, Constraints: ConstraintNamespace, Description: "The maximum number of pending signals a workflow can have before SignalExternalWorkflowExecution commands from this workflow will fail."},
+	{Key: NumPendingCancelRequestsLimitError, Type: ValueTypeInt, Constraints: ConstraintNamespace, Description: "The maximum number of pending requests to cancel other workflows a workflow can have before RequestCancelExternalWorkflowExecution commands will fail."},
+	{Key: HistorySizeLimitError, Type: ValueTypeInt, Constraints: ConstraintNamespace, Description: "The per workflow execution history size limit"},
+	{Key: HistorySizeLimitWarn, Type: ValueTypeInt, Constraints: ConstraintNamespace, Description: "The per workflow execution history size limit for warning"},
+	{Key: HistorySizeSuggestContinueAsNew, Type: ValueTypeInt, Constraints: ConstraintNamespace, Description: "The workflow execution history size limit to suggest continue-as-new (in workflow task started event)"},
+	{Key: HistoryCountLimitError, Type: ValueTypeInt, Constraints: ConstraintNamespace, Description: "The per workflow execution history event count limit"},
+	{Key: HistoryCountLimitWarn, Type: ValueTypeInt, Constraints: ConstraintNamespace, Description: "The per workflow execution history event count limit for warning"},
+	{Key: MutableStateActivityFailureSizeLimitError, Type: ValueTypeInt, Constraints: ConstraintNamespace, Description: "The per activity failure size limit for workflow mutable state. If exceeded, failure will be truncated before being stored in mutable state."},
+	{Key: MutableStateActivityFailureSizeLimitWarn, Type: ValueTypeInt, Constraints: ConstraintNamespace, Description: "The per activity failure size warning limit for workflow mutable state"},
+	{Key: MutableStateSizeLimitError, Type: ValueTypeInt, Constraints: ConstraintNone, Description: "The per workflow execution mutable state size limit in bytes"},
+	{Key: MutableStateSizeLimitWarn, Type: ValueTypeInt, Constraints: ConstraintNone, Description: "The per workflow execution mutable state size limit in bytes for warning"},
+	{Key: HistoryCountSuggestContinueAsNew, Type: ValueTypeInt, Constraints: ConstraintNamespace, Description: "The workflow execution history event count limit to suggest continue-as-new (in workflow task started event)"},
+	{Key: HistoryMaxPageSize, Type: ValueTypeInt, Constraints: ConstraintNamespace, Description: "Default max size for GetWorkflowExecutionHistory in one page"},
+	{Key: MaxIDLengthLimit, Type: ValueTypeInt, Constraints: ConstraintNone, Description: "The length limit for various IDs, including: Namespace, TaskQueue, WorkflowID, ActivityID, TimerID, WorkflowType, ActivityType, SignalName, MarkerName, ErrorReason/FailureReason/CancelCause, Identity, RequestID"},
+	{Key: WorkerBuildIdSizeLimit, Type: ValueTypeInt, Constraints: ConstraintNone, Description: "The byte length limit for a worker build id as used in the rpc methods for updating the version sets for a task queue. Do not set this to a value higher than 255 for clusters using SQL based persistence due to predefined VARCHAR column width."},
+	{Key: VersionCompatibleSetLimitPerQueue, Type: ValueTypeInt, Constraints: ConstraintNamespace, Description: "The max number of compatible sets allowed in the versioning data for a task queue. Update requests which would cause the versioning data to exceed this number will fail with a FailedPrecondition error."},
+	{Key: VersionBuildIdLimitPerQueue, Type: ValueTypeInt, Constraints: ConstraintNamespace, Description: "The max number of build IDs allowed to be defined in the versioning data for a task queue. Update requests which would cause the versioning data to exceed this number will fail with a FailedPrecondition error."},
+	{Key: ReachabilityTaskQueueScanLimit, Type: ValueTypeInt, Constraints: ConstraintNone, Description: "Limits the number of task queues to scan when responding to a GetWorkerTaskReachability query."},
+	{Key: ReachabilityQueryBuildIdLimit, Type: ValueTypeInt, Constraints: ConstraintNone, Description: "Limits the number of build ids that can be requested in a single call to the GetWorkerTaskReachability API."},
+	{Key: ReachabilityQuerySetDurationSinceDefault, Type: ValueTypeDuration, Constraints: ConstraintNone, Description: "The minimum period since a version set was demoted from being the queue default before it is considered unreachable by new workflows. This setting allows some propogation delay of versioning data for the reachability queries, which may happen for the following reasons: 1. There are no workflows currently marked as open in the visibility store but a worker for the demoted version is currently processing a task. 2. There are delays in the visibility task processor (which is asynchronous). 3. There's propagation delay of the versioning data between matching nodes."},
+	{Key: TaskQueuesPerBuildIdLimit, Type: ValueTypeInt, Constraints: ConstraintNamespace, Description: "Limits the number of task queue names that can be mapped to a single build id."},
+	{Key: RemovableBuildIdDurationSinceDefault, Type: ValueTypeDuration, Constraints: ConstraintNone, Description: "The minimum duration since a build id was last default in its containing set for it to be considered for removal, used by the build id scavenger. This setting allows some propogation delay of versioning data, which may happen for the following reasons: 1. There are no workflows currently marked as open in the visibility store but a worker for the demoted version is currently processing a task. 2. There are delays in the visibility task processor (which is asynchronous). 3. There's propagation delay of the versioning data between matching nodes."},
+	{Key: BuildIdScavenengerVisibilityRPS, Type: ValueTypeFloat, Constraints: ConstraintNone, Description: "BuildIdScavengerVisibilityRPS is the rate limit for visibility calls from the build id scavenger"},
+	{Key: FrontendPersistenceMaxQPS, Type: ValueTypeInt, Constraints: ConstraintNone, Description: "The max qps frontend host can query DB"},
+	{Key: FrontendPersistenceGlobalMaxQPS, Type: ValueTypeInt, Constraints: ConstraintNone, Description: "The max qps frontend cluster can query DB"},
+	{Key: FrontendPersistenceNamespaceMaxQPS, Type: ValueTypeInt, Constraints: ConstraintNamespace, Description: "The max qps each namespace on frontend host can query DB"},
+	{Key: FrontendPersistenceGlobalNamespaceMaxQPS, Type: ValueTypeInt, Constraints: ConstraintNamespace, Description: "FrontendPersistenceNamespaceMaxQPS is the max qps each namespace in frontend cluster can query DB"},
+	{Key: FrontendEnablePersistencePriorityRateLimiting, Type: ValueTypeBool, Constraints: ConstraintNone, Description: "Indicates if priority rate limiting is enabled in frontend persistence client"},
+	{Key: FrontendPersistenceDynamicRateLimitingParams, Type: ValueTypeMap, Constraints: ConstraintNone, Description: "A map that contains all adjustable dynamic rate limiting params see DefaultDynamicRateLimitingParams for available options and defaults"},
+	{Key: FrontendVisibilityMaxPageSize, Type: ValueTypeInt, Constraints: ConstraintNamespace, Description: "Default max size for ListWorkflowExecutions in one page"},
+	{Key: FrontendVisibilityMaxCountGroups, Type: ValueTypeInt, Constraints: ConstraintNamespace, Description: "Max number of groups returned by a CountWorkflowExecutions 'group by' query"},
+	{Key: FrontendVisibilityMaxQueryCost, Type: ValueTypeInt, Constraints: ConstraintNamespace, Description: "Max estimated cost of a visibility query, queries above it are rejected unless they are saved by operators in the namespace. 0 means no limit"},
+	{Key: FrontendVisibilityQueryCostPerSecond, Type: ValueTypeInt, Constraints: ConstraintNamespace, Description: "Estimated cost of visibility queries a namespace can spend per second per frontend instance, queries above it are throttled. 0 means no limit"},
+	{Key: FrontendHistoryMaxPageSize, Type: ValueTypeInt, Constraints: ConstraintNamespace, Description: "Default max size for GetWorkflowExecutionHistory in one page"},
+	{Key: FrontendRPS, Type: ValueTypeInt, Constraints: ConstraintNone, Description: "Workflow rate limit per second per-instance"},
+	{Key: FrontendGlobalRPS, Type: ValueTypeInt, Constraints: ConstraintNone, Description: "Workflow rate limit per second for the whole cluster"},
+	{Key: FrontendNamespaceReplicationInducingAPIsRPS, Type: ValueTypeInt, Constraints: ConstraintNone, Description: "Limits the per second request rate for namespace replication inducing APIs (e.g. RegisterNamespace, UpdateNamespace, UpdateWorkerBuildIdCompatibility). This config is EXPERIMENTAL and may be changed or removed in a later release."},
+	{Key: FrontendMaxNamespaceRPSPerInstance, Type: ValueTypeInt, Constraints: ConstraintNamespace, Description: "Workflow namespace rate limit per second"},
+	{Key: FrontendMaxNamespaceBurstPerInstance, Type: ValueTypeInt, Constraints: ConstraintNamespace, Description: "Workflow namespace burst limit"},
+	{Key: FrontendMaxConcurrentLongRunningRequestsPerInstance, Type: ValueTypeInt, Constraints: ConstraintNamespace, Description: "Limits concurrent long-running requests per-instance, per-API. Example requests include long-poll requests, and `Query` requests (which need to wait for WFTs). The limit is applied individually to each API method. This value is ignored if FrontendGlobalMaxConcurrentLongRunningRequests is greater than zero. Warning: setting this to zero will cause all long-running requests to fail. The name `frontend.namespaceCount` is kept for backwards compatibility with existing deployments even though it is a bit of a misnomer. This does not limit the number of namespaces; it is a per-_namespace_ limit on the _count_ of long-running requests. Requests are only throttled when the limit is exceeded, not when it is only reached."},
+	{Key: FrontendGlobalMaxConcurrentLongRunningRequests, Type: ValueTypeInt, Constraints: ConstraintNamespace, Description: "Limits concurrent long-running requests across all frontend instances in the cluster, for a given namespace, per-API method. If this is set to 0 (the default), then it is ignored. The name `frontend.globalNamespaceCount` is kept for consistency with the per-instance limit name, `frontend.namespaceCount`."},
+	{Key: FrontendMaxNamespaceVisibilityRPSPerInstance, Type: ValueTypeInt, Constraints: ConstraintNamespace, Description: "Namespace rate limit per second for visibility APIs. This config is EXPERIMENTAL and may be changed or removed in a later release."},
+	{Key: FrontendMaxNamespaceNamespaceReplicationInducingAPIsRPSPerInstance, Type: ValueTypeInt, Constraints: ConstraintNamespace, Description: "A per host/per namespace RPS limit for namespace replication inducing APIs (e.g. RegisterNamespace, UpdateNamespace, UpdateWorkerBuildIdCompatibility). This config is EXPERIMENTAL and may be changed or removed in a later release."},
+	{Key: FrontendMaxNamespaceVisibilityBurstPerInstance, Type: ValueTypeInt, Constraints: ConstraintNamespace, Description: "Namespace burst limit for visibility APIs. This config is EXPERIMENTAL and may be changed or removed in a later release."},
+	{Key: FrontendMaxNamespaceNamespaceReplicationInducingAPIsBurstPerInstance, Type: ValueTypeInt, Constraints: ConstraintNamespace, Description: "A per host/per namespace burst limit for namespace replication inducing APIs (e.g. RegisterNamespace, UpdateNamespace, UpdateWorkerBuildIdCompatibility). This config is EXPERIMENTAL and may be changed or removed in a later release."},
+	{Key: FrontendGlobalNamespaceRPS, Type: ValueTypeInt, Constraints: ConstraintNamespace, Description: "Workflow namespace rate limit per second for the whole cluster. The limit is evenly distributed among available frontend service instances. If this is set, it overwrites per instance limit \"frontend.namespaceRPS\"."},
+	{Key: InternalFrontendGlobalNamespaceRPS, Type: ValueTypeInt, Constraints: ConstraintNamespace, Description: "Workflow namespace rate limit per second across all internal-frontends."},
+	{Key: FrontendGlobalNamespaceVisibilityRPS, Type: ValueTypeInt, Constraints: ConstraintNamespace, Description: "Workflow namespace rate limit per second for the whole cluster for visibility API. The limit is evenly distributed among available frontend service instances. If this is set, it overwrites per instance limit \"frontend.namespaceRPS.visibility\". This config is EXPERIMENTAL and may be changed or removed in a later release."},
+	{Key: FrontendGlobalNamespaceNamespaceReplicationInducingAPIsRPS, Type: ValueTypeInt, Constraints: ConstraintNamespace, Description: "A cluster global, per namespace RPS limit for namespace replication inducing APIs (e.g. RegisterNamespace, UpdateNamespace, UpdateWorkerBuildIdCompatibility). The limit is evenly distributed among available frontend service instances. If this is set, it overwrites the per instance limit configured with \"frontend.namespaceRPS.namespaceReplicationInducingAPIs\". This config is EXPERIMENTAL and may be changed or removed in a later release."},
+	{Key: InternalFrontendGlobalNamespaceVisibilityRPS, Type: ValueTypeInt, Constraints: ConstraintNamespace, Description: "Workflow namespace rate limit per second across all internal-frontends. This config is EXPERIMENTAL and may be changed or removed in a later release."},
+	{Key: FrontendThrottledLogRPS, Type: ValueTypeInt, Constraints: ConstraintNone, Description: "The rate limit on number of log messages emitted per second for throttled logger"},
+	{Key: FrontendShutdownDrainDuration, Type: ValueTypeDuration, Constraints: ConstraintNone, Description: "The duration of traffic drain during shutdown"},
+	{Key: FrontendShutdownFailHealthCheckDuration, Type: ValueTypeDuration, Constraints: ConstraintNone, Description: "The duration of shutdown failure detection"},
+	{Key: FrontendMaxBadBinaries, Type: ValueTypeInt, Constraints: ConstraintNamespace, Description: "The max number of bad binaries in namespace config"},
+	{Key: SendRawWorkflowHistory, Type: ValueTypeBool, Constraints: ConstraintNamespace, Description: "Whether to enable raw history retrieving"},
+	{Key: SearchAttributesNumberOfKeysLimit, Type: ValueTypeInt, Constraints: ConstraintNamespace, Description: "The limit of number of keys"},
+	{Key: SearchAttributesSizeOfValueLimit, Type: ValueTypeInt, Constraints: ConstraintNamespace, Description: "The size limit of each value"},
+	{Key: SearchAttributesTotalSizeLimit, Type: ValueTypeInt, Constraints: ConstraintNamespace, Description: "The size limit of the whole map"},
+	{Key: VisibilityArchivalQueryMaxPageSize, Type: ValueTypeInt, Constraints: ConstraintNone, Description: "The maximum page size for a visibility archival query"},
+	{Key: EnableServerVersionCheck, Type: ValueTypeBool, Constraints: ConstraintNone, Description: "A flag that controls whether or not periodic version checking is enabled"},
+	{Key: EnableTokenNamespaceEnforcement, Type: ValueTypeBool, Constraints: ConstraintNone, Description: "Enables enforcement that namespace in completion token matches namespace of the request"},
+	{Key: DisableListVisibilityByFilter, Type: ValueTypeBool, Constraints: ConstraintNamespace, Description: "Config to disable list open/close workflow using filter"},
+	{Key: KeepAliveMinTime, Type: ValueTypeDuration, Constraints: ConstraintNone, Description: "The minimum amount of time a client should wait before sending a keepalive ping."},
+	{Key: KeepAlivePermitWithoutStream, Type: ValueTypeBool, Constraints: ConstraintNone, Description: "If true, server allows keepalive pings even when there are no active streams(RPCs). If false, and client sends ping when there are no active streams, server will send GOAWAY and close the connection."},
+	{Key: KeepAliveMaxConnectionIdle, Type: ValueTypeDuration, Constraints: ConstraintNone, Description: "A duration for the amount of time after which an idle connection would be closed by sending a GoAway. Idleness duration is defined since the most recent time the number of outstanding RPCs became zero or the connection establishment."},
+	{Key: KeepAliveMaxConnectionAge, Type: ValueTypeDuration, Constraints: ConstraintNone, Description: "A duration for the maximum amount of time a connection may exist before it will be closed by sending a GoAway. A random jitter of +/-10% will be added to MaxConnectionAge to spread out connection storms."},
+	{Key: KeepAliveMaxConnectionAgeGrace, Type: ValueTypeDuration, Constraints: ConstraintNone, Description: "An additive period after MaxConnectionAge after which the connection will be forcibly closed."},
+	{Key: KeepAliveTime, Type: ValueTypeDuration, Constraints: ConstraintNone, Description: "After a duration of this time if the server doesn't see any activity it pings the client to see if the transport is still alive. If set below 1s, a minimum value of 1s will be used instead."},
+	{Key: KeepAliveTimeout, Type: ValueTypeDuration, Constraints: ConstraintNone, Description: "After having pinged for keepalive check, the server waits for a duration of Timeout and if no activity is seen even after that the connection is closed."},
+	{Key: FrontendEnableSchedules, Type: ValueTypeBool, Constraints: ConstraintNamespace, Description: "Enables schedule-related RPCs in the frontend"},
+	{Key: FrontendMaxConcurrentBatchOperationPerNamespace, Type: ValueTypeInt, Constraints: ConstraintNamespace, Description: "The max concurrent batch operation job count per namespace"},
+	{Key: FrontendMaxExecutionCountBatchOperationPerNamespace, Type: ValueTypeInt, Constraints: ConstraintNamespace, Description: "The max execution count batch operation supports per namespace"},
+	{Key: FrontendEnableBatcher, Type: ValueTypeBool, Constraints: ConstraintNamespace, Description: "Enables batcher-related RPCs in the frontend"},
+	{Key: FrontendAccessHistoryFraction, Type: ValueTypeFloat, Constraints: ConstraintNone, Description: "(0.0~1.0) is the fraction of history operations that are sent to the history service using the new RPCs. The remaining access history via the existing implementation."},
+	{Key: FrontendEnableUpdateWorkflowExecution, Type: ValueTypeBool, Constraints: ConstraintNamespace, Description: "Enables UpdateWorkflowExecution API in the frontend. The UpdateWorkflowExecution API has gone through rigorous testing efforts but this config's default is `false` until the feature gets more time in production."},
+	{Key: FrontendEnableUpdateWorkflowExecutionAsyncAccepted, Type: ValueTypeBool, Constraints: ConstraintNamespace, Description: "Enables the form of asynchronous workflow execution update that waits on the \"Accepted\" lifecycle stage. Default value is `false`."},
+	{Key: FrontendEnableWorkerVersioningDataAPIs, Type: ValueTypeBool, Constraints: ConstraintNamespace, Description: "Enables worker versioning data read / write APIs."},
+	{Key: FrontendEnableWorkerVersioningWorkflowAPIs, Type: ValueTypeBool, Constraints: ConstraintNamespace, Description: "Enables worker versioning in workflow progress APIs."},
+	{Key: DeleteNamespaceDeleteActivityRPS, Type: ValueTypeInt, Constraints: ConstraintNone, Description: "An RPS per every parallel delete executions activity. Total RPS is equal to DeleteNamespaceDeleteActivityRPS * DeleteNamespaceConcurrentDeleteExecutionsActivities. Default value is 100."},
+	{Key: DeleteNamespacePageSize, Type: ValueTypeInt, Constraints: ConstraintNone, Description: "A page size to read executions from visibility for delete executions activity. Default value is 1000."},
+	{Key: DeleteNamespacePagesPerExecution, Type: ValueTypeInt, Constraints: ConstraintNone, Description: "A number of pages before returning ContinueAsNew from delete executions activity. Default value is 256."},
+	{Key: DeleteNamespaceConcurrentDeleteExecutionsActivities, Type: ValueTypeInt, Constraints: ConstraintNone, Description: "A number of concurrent delete executions activities. Must be not greater than 256 and number of worker cores in the cluster. Default is 4."},
+	{Key: DeleteNamespaceNamespaceDeleteDelay, Type: ValueTypeDuration, Constraints: ConstraintNone, Description: "A duration for how long namespace stays in database after all namespace resources (i.e. workflow executions) are deleted. Default is 0, means, namespace will be deleted immediately."},
+	{Key: MatchingRPS, Type: ValueTypeInt, Constraints: ConstraintNone, Description: "Request rate per second for each matching host"},
+	{Key: MatchingPersistenceMaxQPS, Type: ValueTypeInt, Constraints: ConstraintNone, Description: "The max qps matching host can query DB"},
+	{Key: MatchingPersistenceGlobalMaxQPS, Type: ValueTypeInt, Constraints: ConstraintNone, Description: "The max qps matching cluster can query DB"},
+	{Key: MatchingPersistenceNamespaceMaxQPS, Type: ValueTypeInt, Constraints: ConstraintNamespace, Description: "The max qps each namespace on matching host can query DB"},
+	{Key: MatchingPersistenceGlobalNamespaceMaxQPS, Type: ValueTypeInt, Constraints: ConstraintNamespace, Description: "MatchingPersistenceNamespaceMaxQPS is the max qps each namespace in matching cluster can query DB"},
+	{Key: MatchingEnablePersistencePriorityRateLimiting, Type: ValueTypeBool, Constraints: ConstraintNone, Description: "Indicates if priority rate limiting is enabled in matching persistence client"},
+	{Key: MatchingPersistenceDynamicRateLimitingParams, Type: ValueTypeMap, Constraints: ConstraintNone, Description: "A map that contains all adjustable dynamic rate limiting params see DefaultDynamicRateLimitingParams for available options and defaults"},
+	{Key: MatchingMinTaskThrottlingBurstSize, Type: ValueTypeInt, Constraints: ConstraintNamespace | ConstraintTaskQueue, Description: "The minimum burst size for task queue throttling"},
+	{Key: MatchingGetTasksBatchSize, Type: ValueTypeInt, Constraints: ConstraintNamespace | ConstraintTaskQueue, Description: "The maximum batch size to fetch from the task buffer"},
+	{Key: MatchingLongPollExpirationInterval, Type: ValueTypeDuration, Constraints: ConstraintNamespace | ConstraintTaskQueue, Description: "The long poll expiration interval in the matching service"},
+	{Key: MatchingSyncMatchWaitDuration, Type: ValueTypeDuration, Constraints: ConstraintNamespace | ConstraintTaskQueue, Description: "To wait time for sync match"},
+	{Key: MatchingHistoryMaxPageSize, Type: ValueTypeInt, Constraints: ConstraintNamespace, Description: "The maximum page size of history events returned on PollWorkflowTaskQueue requests"},
+	{Key: MatchingLoadUserData, Type: ValueTypeBool, Constraints: ConstraintNamespace | ConstraintTaskQueue, Description: "Can be used to entirely disable loading user data from persistence (and the inter node RPCs that propoagate it). When turned off, features that rely on user data (e.g. worker versioning) will essentially be disabled. When disabled, matching will drop tasks for versioned workflows and activities to avoid breaking versioning semantics. Operator intervention will be required to reschedule the dropped tasks."},
+	{Key: MatchingUpdateAckInterval, Type: ValueTypeDuration, Constraints: ConstraintNamespace | ConstraintTaskQueue, Description: "The interval for update ack"},
+	{Key: MatchingMaxTaskQueueIdleTime, Type: ValueTypeDuration, Constraints: ConstraintNamespace | ConstraintTaskQueue, Description: "The time after which an idle task queue will be unloaded"},
+	{Key: MatchingOutstandingTaskAppendsThreshold, Type: ValueTypeInt, Constraints: ConstraintNamespace | ConstraintTaskQueue, Description: "The threshold for outstanding task appends"},
+	{Key: MatchingMaxTaskBatchSize, Type: ValueTypeInt, Constraints: ConstraintNamespace | ConstraintTaskQueue, Description: "Max batch size for task writer"},
+	{Key: MatchingMaxTaskDeleteBatchSize, Type: ValueTypeInt, Constraints: ConstraintNamespace | ConstraintTaskQueue, Description: "The max batch size for range deletion of tasks"},
+	{Key: MatchingThrottledLogRPS, Type: ValueTypeInt, Constraints: ConstraintNone, Description: "The rate limit on number of log messages emitted per second for throttled logger"},
+	{Key: MatchingNumTaskqueueWritePartitions, Type: ValueTypeInt, Constraints: ConstraintNamespace | ConstraintTaskQueue, Description: "The number of write partitions for a task queue"},
+	{Key: MatchingNumTaskqueueReadPartitions, Type: ValueTypeInt, Constraints: ConstraintNamespace | ConstraintTaskQueue, Description: "The number of read partitions for a task queue"},
+	{Key: MatchingForwarderMaxOutstandingPolls, Type: ValueTypeInt, Constraints: ConstraintNamespace | ConstraintTaskQueue, Description: "The max number of inflight polls from the forwarder"},
+	{Key: MatchingForwarderMaxOutstandingTasks, Type: ValueTypeInt, Constraints: ConstraintNamespace | ConstraintTaskQueue, Description: "The max number of inflight addTask/queryTask from the forwarder"},
+	{Key: MatchingForwarderMaxRatePerSecond, Type: ValueTypeInt, Constraints: ConstraintNamespace | ConstraintTaskQueue, Description: "The max rate at which add/query can be forwarded"},
+	{Key: MatchingForwarderMaxChildrenPerNode, Type: ValueTypeInt, Constraints: ConstraintNamespace | ConstraintTaskQueue, Description: "The max number of children per node in the task queue partition tree"},
+	{Key: MatchingShutdownDrainDuration, Type: ValueTypeDuration, Constraints: ConstraintNone, Description: "The duration of traffic drain during shutdown"},
+	{Key: MatchingGetUserDataLongPollTimeout, Type: ValueTypeDuration, Constraints: ConstraintNone, Description: "The max length of long polls for GetUserData calls between partitions."},
+	{Key: MatchingBacklogNegligibleAge, Type: ValueTypeDuration, Constraints: ConstraintNamespace | ConstraintTaskQueue, Description: "If the head of backlog gets older than this we stop sync match and forwarding to ensure more equal dispatch order among partitions."},
+	{Key: MatchingMaxWaitForPollerBeforeFwd, Type: ValueTypeDuration, Constraints: ConstraintNamespace | ConstraintTaskQueue, Description: "In presence of a non-negligible backlog, we resume forwarding tasks if the duration since last poll exceeds this threshold."},
+	{Key: MatchingFairnessKeySource, Type: ValueTypeString, Constraints: ConstraintNamespace, Description: "Where history derives the fairness key of matching tasks from: workflowId, searchAttribute or header. Empty disables fair dispatch of backlog"},
+	{Key: MatchingFairnessKeyName, Type: ValueTypeString, Constraints: ConstraintNamespace, Description: "The search attribute or header name used as fairness key when MatchingFairnessKeySource is searchAttribute or header"},
+	{Key: MatchingFairnessKeyWeights, Type: ValueTypeMap, Constraints: ConstraintNamespace, Description: "A map from fairness key to its dispatch weight. Keys not in the map have weight 1"},
+	{Key: MatchingFairnessKeyBuckets, Type: ValueTypeInt, Constraints: ConstraintNamespace | ConstraintTaskQueue, Description: "The number of buckets fairness keys without an explicit weight are hashed into"},
+	{Key: MatchingFairnessBacklogBufferSize, Type: ValueTypeInt, Constraints: ConstraintNamespace | ConstraintTaskQueue, Description: "The max number of backlog tasks a partition holds in memory for fair dispatch"},
+	{Key: MatchingFairnessMaxBufferedTasksPerKey, Type: ValueTypeInt, Constraints: ConstraintNamespace | ConstraintTaskQueue, Description: "The max number of backlog tasks of one fairness key held for fair dispatch, times the key weight. Further tasks of the key are skipped and read again later"},
+	{Key: MatchingFairnessMaxDeferredTasks, Type: ValueTypeInt, Constraints: ConstraintNamespace | ConstraintTaskQueue, Description: "The max number of skipped backlog tasks a partition tracks for fair dispatch. 0 disables skipping"},
+	{Key: MatchingEnableTaskPriority, Type: ValueTypeBool, Constraints: ConstraintNamespace | ConstraintTaskQueue, Description: "Enables dispatching tasks by priority instead of in creation order. The backlog of each priority is persisted in a subqueue of its own, which is only read while this is enabled"},
+	{Key: MatchingTaskPriorityAgingInterval, Type: ValueTypeDuration, Constraints: ConstraintNamespace | ConstraintTaskQueue, Description: "How long a task waits before its priority is raised by one level. Zero disables aging"},
+	{Key: MatchingWorkflowTypeDispatchRPS, Type: ValueTypeMap, Constraints: ConstraintNamespace | ConstraintTaskQueue, Description: "A map from workflow type to the max rate at which its workflow tasks are dispatched from a task queue"},
+	{Key: MatchingActivityTypeDispatchRPS, Type: ValueTypeMap, Constraints: ConstraintNamespace | ConstraintTaskQueue, Description: "A map from activity type to the max rate at which its activity tasks are dispatched from a task queue"},
+	{Key: MatchingThrottledBacklogBufferSize, Type: ValueTypeInt, Constraints: ConstraintNamespace | ConstraintTaskQueue, Description: "The max number of backlog tasks of a rate limited type a partition holds in memory while they wait for the type's rate limit"},
+	{Key: TestMatchingDisableSyncMatch, Type: ValueTypeBool, Constraints: ConstraintNone, Description: "Forces tasks to go through the db once"},
+	{Key: TestMatchingLBForceReadPartition, Type: ValueTypeInt, Constraints: ConstraintNone, Description: "Forces polls to go to a specific partition"},
+	{Key: TestMatchingLBForceWritePartition, Type: ValueTypeInt, Constraints: ConstraintNone, Description: "Forces adds to go to a specific partition"},
+	{Key: EnableReplicationStream, Type: ValueTypeBool, Constraints: ConstraintNone, Description: "Turn on replication stream"},
+	{Key: EnableHistoryReplicationDLQV2, Type: ValueTypeBool, Constraints: ConstraintNone, Description: "Switches to the DLQ v2 implementation for history replication. See details in [go.temporal.io/server/common/persistence.QueueV2]. This feature is currently in development. Do NOT use it in production."},
+	{Key: HistoryRPS, Type: ValueTypeInt, Constraints: ConstraintNone, Description: "Request rate per second for each history host"},
+	{Key: HistoryPersistenceMaxQPS, Type: ValueTypeInt, Constraints: ConstraintNone, Description: "The max qps history host can query DB"},
+	{Key: HistoryPersistenceGlobalMaxQPS, Type: ValueTypeInt, Constraints: ConstraintNone, Description: "The max qps history cluster can query DB"},
+	{Key: HistoryPersistenceNamespaceMaxQPS, Type: ValueTypeInt, Constraints: ConstraintNamespace, Description: "The max qps each namespace on history host can query DB If value less or equal to 0, will fall back to HistoryPersistenceMaxQPS"},
+	{Key: HistoryPersistenceGlobalNamespaceMaxQPS, Type: ValueTypeInt, Constraints: ConstraintNamespace, Description: "HistoryPersistenceNamespaceMaxQPS is the max qps each namespace in history cluster can query DB"},
+	{Key: HistoryPersistencePerShardNamespaceMaxQPS, Type: ValueTypeInt, Constraints: ConstraintNamespace, Description: "The max qps each namespace on a shard can query DB"},
+	{Key: HistoryEnablePersistencePriorityRateLimiting, Type: ValueTypeBool, Constraints: ConstraintNone, Description: "Indicates if priority rate limiting is enabled in history persistence client"},
+	{Key: HistoryPersistenceDynamicRateLimitingParams, Type: ValueTypeMap, Constraints: ConstraintNone, Description: "A map that contains all adjustable dynamic rate limiting params see DefaultDynamicRateLimitingParams for available options and defaults"},
+	{Key: HistoryLongPollExpirationInterval, Type: ValueTypeDuration, Constraints: ConstraintNamespace, Description: "The long poll expiration interval in the history service"},
+	{Key: HistoryCacheInitialSize, Type: ValueTypeInt, Constraints: ConstraintNone, Description: "Initial size of history cache"},
+	{Key: HistoryCacheMaxSize, Type: ValueTypeInt, Constraints: ConstraintNone, Description: "Max size of history cache"},
+	{Key: HistoryCacheTTL, Type: ValueTypeDuration, Constraints: ConstraintNone, Description: "TTL of history cache"},
+	{Key: HistoryCacheNonUserContextLockTimeout, Type: ValueTypeDuration, Constraints: ConstraintNone, Description: "Controls how long non-user call (callerType != API or Operator) will wait on workflow lock acquisition. Requires service restart to take effect."},
+	{Key: EnableHostHistoryCache, Type: ValueTypeBool, Constraints: ConstraintNone, Description: "Controls if the history cache is host level"},
+	{Key: HistoryCacheShardLevelMaxSize, Type: ValueTypeInt, Constraints: ConstraintNone, Description: "Max size of history shard level cache"},
+	{Key: EnableAPIGetCurrentRunIDLock, Type: ValueTypeBool, Constraints: ConstraintNone, Description: "Controls if a lock should be acquired before getting current run ID for API requests"},
+	{Key: HistoryStartupMembershipJoinDelay, Type: ValueTypeDuration, Constraints: ConstraintNone, Description: "The duration a history instance waits before joining membership after starting."},
+	{Key: HistoryShutdownDrainDuration, Type: ValueTypeDuration, Constraints: ConstraintNone, Description: "The duration of traffic drain during shutdown"},
+	{Key: HistoryShutdownDrainShardsTimeout, Type: ValueTypeDuration, Constraints: ConstraintNone, Description: "The max duration a history instance waits during shutdown for its shards to be flushed and handed off to the other instances. Zero disables the drain on shutdown"},
+	{Key: XDCCacheMaxSizeBytes, Type: ValueTypeInt, Constraints: ConstraintNone, Description: "Max size of events cache in bytes"},
+	{Key: EventsCacheMaxSizeBytes, Type: ValueTypeInt, Constraints: ConstraintNone, Description: "Max size of events cache in bytes"},
+	{Key: EventsCacheTTL, Type: ValueTypeDuration, Constraints: ConstraintNone, Description: "TTL of events cache"},
+	{Key: AcquireShardInterval, Type: ValueTypeDuration, Constraints: ConstraintNone, Description: "Interval that timer used to acquire shard"},
+	{Key: AcquireShardConcurrency, Type: ValueTypeInt, Constraints: ConstraintNone, Description: "Number of goroutines that can be used to acquire shards in the shard controller."},
+	{Key: ShardLingerOwnershipCheckQPS, Type: ValueTypeInt, Constraints: ConstraintNone, Description: "The frequency to perform shard ownership checks while a shard is lingering."},
+	{Key: ShardLingerTimeLimit, Type: ValueTypeDuration, Constraints: ConstraintNone, Description: "Configures if and for how long the shard controller will temporarily delay closing shards after a membership update, awaiting a shard ownership lost error from persistence. Not recommended with persistence layers that are missing AssertShardOwnership support. If set to zero, shards will not delay closing."},
+	{Key: ShardOwnershipAssertionEnabled, Type: ValueTypeBool, Constraints: ConstraintNone, Description: "Configures if the shard ownership is asserted for API requests when a NotFound or NamespaceNotFound error is returned from persistence. NOTE: Shard ownership assertion is not implemented by any persistence implementation in this codebase, because assertion is not needed for persistence implementation that guarantees read after write consistency. As a result, even if this config is enabled, it's a no-op."},
+	{Key: HistoryClientOwnershipCachingEnabled, Type: ValueTypeBool, Constraints: ConstraintNone, Description: "Configures if history clients try to cache shard ownership information, instead of checking membership for each request. Only inspected when an instance first creates a history client, so changes to this require a restart to take effect."},
+	{Key: ShardIOConcurrency, Type: ValueTypeInt, Constraints: ConstraintNone, Description: "Controls the concurrency of persistence operations in shard context"},
+	{Key: StandbyClusterDelay, Type: ValueTypeDuration, Constraints: ConstraintNone, Description: "The artificial delay added to standby cluster's view of active cluster's time"},
+	{Key: StandbyTaskMissingEventsResendDelay, Type: ValueTypeDuration, Constraints: ConstraintHistoryTaskType, Description: "The amount of time standby cluster's will wait (if events are missing) before calling remote for missing events"},
+	{Key: StandbyTaskMissingEventsDiscardDelay, Type: ValueTypeDuration, Constraints: ConstraintHistoryTaskType, Description: "The amount of time standby cluster's will wait (if events are missing) before discarding the task"},
+	{Key: QueuePendingTaskCriticalCount, Type: ValueTypeInt, Constraints: ConstraintNone, Description: "The max number of pending task in one queue before triggering queue slice splitting and unloading"},
+	{Key: QueueReaderStuckCriticalAttempts, Type: ValueTypeInt, Constraints: ConstraintNone, Description: "The max number of task loading attempts for a certain task range before that task range is split into a separate slice to unblock loading for later range. currently only work for scheduled queues and the task range is 1s."},
+	{Key: QueueCriticalSlicesCount, Type: ValueTypeInt, Constraints: ConstraintNone, Description: "The max number of slices in one queue before force compacting slices"},
+	{Key: QueuePendingTaskMaxCount, Type: ValueTypeInt, Constraints: ConstraintNone, Description: "The max number of task pending tasks in one queue before stop loading new tasks into memory. While QueuePendingTaskCriticalCount won't stop task loading for the entire queue but only trigger a queue action to unload tasks. Ideally this max count limit should not be hit and task unloading should happen once critical count is exceeded. But since queue action is async, we need this hard limit."},
+	{Key: QueueMaxReaderCount, Type: ValueTypeInt, Constraints: ConstraintNone, Description: "The max number of readers in one multi-cursor queue"},
+	{Key: ContinueAsNewMinInterval, Type: ValueTypeDuration, Constraints: ConstraintNamespace, Description: "The minimal interval between continue_as_new executions. This is needed to prevent tight loop continue_as_new spin. Default is 1s."},
+	{Key: TaskSchedulerEnableRateLimiter, Type: ValueTypeBool, Constraints: ConstraintNone, Description: "Indicates if task scheduler rate limiter should be enabled"},
+	{Key: TaskSchedulerEnableRateLimiterShadowMode, Type: ValueTypeBool, Constraints: ConstraintNone, Description: "Indicates if task scheduler rate limiter should run in shadow mode i.e. through rate limiter and emit metrics but do not actually block/throttle task scheduling"},
+	{Key: TaskSchedulerRateLimiterStartupDelay, Type: ValueTypeDuration, Constraints: ConstraintNone, Description: "The duration to wait after startup before enforcing task scheduler rate limiting"},
+	{Key: TaskSchedulerGlobalMaxQPS, Type: ValueTypeInt, Constraints: ConstraintNone, Description: "The max qps all task schedulers in the cluster can schedule tasks If value less or equal to 0, will fall back to TaskSchedulerMaxQPS"},
+	{Key: TaskSchedulerMaxQPS, Type: ValueTypeInt, Constraints: ConstraintNone, Description: "The max qps task schedulers on a host can schedule tasks If value less or equal to 0, will fall back to HistoryPersistenceMaxQPS"},
+	{Key: TaskSchedulerGlobalNamespaceMaxQPS, Type: ValueTypeInt, Constraints: ConstraintNamespace, Description: "The max qps all task schedulers in the cluster can schedule tasks for a certain namespace If value less or equal to 0, will fall back to TaskSchedulerNamespaceMaxQPS"},
+	{Key: TaskSchedulerNamespaceMaxQPS, Type: ValueTypeInt, Constraints: ConstraintNamespace, Description: "The max qps task schedulers on a host can schedule tasks for a certain namespace If value less or equal to 0, will fall back to HistoryPersistenceNamespaceMaxQPS"},
+	{Key: TimerTaskBatchSize, Type: ValueTypeInt, Constraints: ConstraintNone, Description: "Batch size for timer processor to process tasks"},
+	{Key: TimerProcessorSchedulerWorkerCount, Type: ValueTypeInt, Constraints: ConstraintNone, Description: "The number of workers in the host level task scheduler for timer processor"},
+	{Key: TimerProcessorSchedulerActiveRoundRobinWeights, Type: ValueTypeMap, Constraints: ConstraintNamespace, Description: "The priority round robin weights used by timer task scheduler for active namespaces"},
+	{Key: TimerProcessorSchedulerStandbyRoundRobinWeights, Type: ValueTypeMap, Constraints: ConstraintNamespace, Description: "The priority round robin weights used by timer task scheduler for standby namespaces"},
+	{Key: TimerProcessorUpdateAckInterval, Type: ValueTypeDuration, Constraints: ConstraintNone, Description: "Update interval for timer processor"},
+	{Key: TimerProcessorUpdateAckIntervalJitterCoefficient, Type: ValueTypeFloat, Constraints: ConstraintNone, Description: "The update interval jitter coefficient"},
+	{Key: TimerProcessorCompleteTimerInterval, Type: ValueTypeDuration, Constraints: ConstraintNone, Description: "Complete timer interval for timer processor"},
+	{Key: TimerProcessorFailoverMaxPollRPS, Type: ValueTypeInt, Constraints: ConstraintNone, Description: "Max poll rate per second for timer processor"},
+	{Key: TimerProcessorMaxPollRPS, Type: ValueTypeInt, Constraints: ConstraintNone, Description: "Max poll rate per second for timer processor"},
+	{Key: TimerProcessorMaxPollHostRPS, Type: ValueTypeInt, Constraints: ConstraintNone, Description: "Max poll rate per second for all timer processor on a host"},
+	{Key: TimerProcessorMaxPollInterval, Type: ValueTypeDuration, Constraints: ConstraintNone, Description: "Max poll interval for timer processor"},
+	{Key: TimerProcessorMaxPollIntervalJitterCoefficient, Type: ValueTypeFloat, Constraints: ConstraintNone, Description: "The max poll interval jitter coefficient"},
+	{Key: TimerProcessorPollBackoffInterval, Type: ValueTypeDuration, Constraints: ConstraintNone, Description: "The poll backoff interval if task redispatcher's size exceeds limit for timer processor"},
+	{Key: TimerProcessorMaxTimeShift, Type: ValueTypeDuration, Constraints: ConstraintNone, Description: "The max shift timer processor can have"},
+	{Key: RetentionTimerJitterDuration, Type: ValueTypeDuration, Constraints: ConstraintNone, Description: "A time duration jitter to distribute timer from T0 to T0 + jitter duration"},
+	{Key: MemoryTimerProcessorSchedulerWorkerCount, Type: ValueTypeInt, Constraints: ConstraintNone, Description: "The number of workers in the task scheduler for in memory timer processor."},
+	{Key: TransferTaskBatchSize, Type: ValueTypeInt, Constraints: ConstraintNone, Description: "Batch size for transferQueueProcessor"},
+	{Key: TransferProcessorFailoverMaxPollRPS, Type: ValueTypeInt, Constraints: ConstraintNone, Description: "Max poll rate per second for transferQueueProcessor"},
+	{Key: TransferProcessorMaxPollRPS, Type: ValueTypeInt, Constraints: ConstraintNone, Description: "Max poll rate per second for transferQueueProcessor"},
+	{Key: TransferProcessorMaxPollHostRPS, Type: ValueTypeInt, Constraints: ConstraintNone, Description: "Max poll rate per second for all transferQueueProcessor on a host"},
+	{Key: TransferProcessorSchedulerWorkerCount, Type: ValueTypeInt, Constraints: ConstraintNone, Description: "The number of workers in the host level task scheduler for transferQueueProcessor"},
+	{Key: TransferProcessorSchedulerActiveRoundRobinWeights, Type: ValueTypeMap, Constraints: ConstraintNamespace, Description: "The priority round robin weights used by transfer task scheduler for active namespaces"},
+	{Key: TransferProcessorSchedulerStandbyRoundRobinWeights, Type: ValueTypeMap, Constraints: ConstraintNamespace, Description: "The priority round robin weights used by transfer task scheduler for standby namespaces"},
+	{Key: TransferProcessorUpdateShardTaskCount, Type: ValueTypeInt, Constraints: ConstraintNone, Description: "Update shard count for transferQueueProcessor"},
+	{Key: TransferProcessorMaxPollInterval, Type: ValueTypeDuration, Constraints: ConstraintNone, Description: "Max poll interval for transferQueueProcessor"},
+	{Key: TransferProcessorMaxPollIntervalJitterCoefficient, Type: ValueTypeFloat, Constraints: ConstraintNone, Description: "The max poll interval jitter coefficient"},
+	{Key: TransferProcessorUpdateAckInterval, Type: ValueTypeDuration, Constraints: ConstraintNone, Description: "Update interval for transferQueueProcessor"},
+	{Key: TransferProcessorUpdateAckIntervalJitterCoefficient, Type: ValueTypeFloat, Constraints: ConstraintNone, Description: "The update interval jitter coefficient"},
+	{Key: TransferProcessorCompleteTransferInterval, Type: ValueTypeDuration, Constraints: ConstraintNone, Description: "Complete timer interval for transferQueueProcessor"},
+	{Key: TransferProcessorPollBackoffInterval, Type: ValueTypeDuration, Constraints: ConstraintNone, Description: "The poll backoff interval if task redispatcher's size exceeds limit for transferQueueProcessor"},
+	{Key: TransferProcessorEnsureCloseBeforeDelete, Type: ValueTypeBool, Constraints: ConstraintNone, Description: "Means we ensure the execution is closed before we delete it"},
+	{Key: VisibilityTaskBatchSize, Type: ValueTypeInt, Constraints: ConstraintNone, Description: "Batch size for visibilityQueueProcessor"},
+	{Key: VisibilityProcessorMaxPollRPS, Type: ValueTypeInt, Constraints: ConstraintNone, Description: "Max poll rate per second for visibilityQueueProcessor"},
+	{Key: VisibilityProcessorMaxPollHostRPS, Type: ValueTypeInt, Constraints: ConstraintNone, Description: "Max poll rate per second for all visibilityQueueProcessor on a host"},
+	{Key: VisibilityProcessorSchedulerWorkerCount, Type: ValueTypeInt, Constraints: ConstraintNone, Description: "The number of workers in the host level task scheduler for visibilityQueueProcessor"},
+	{Key: VisibilityProcessorSchedulerActiveRoundRobinWeights, Type: ValueTypeMap, Constraints: ConstraintNamespace, Description: "The priority round robin weights by visibility task scheduler for active namespaces"},
+	{Key: VisibilityProcessorSchedulerStandbyRoundRobinWeights, Type: ValueTypeMap, Constraints: ConstraintNamespace, Description: "The priority round robin weights by visibility task scheduler for standby namespaces"},
+	{Key: VisibilityProcessorMaxPollInterval, Type: ValueTypeDuration, Constraints: ConstraintNone, Description: "Max poll interval for visibilityQueueProcessor"},
+	{Key: VisibilityProcessorMaxPollIntervalJitterCoefficient, Type: ValueTypeFloat, Constraints: ConstraintNone, Description: "The max poll interval jitter coefficient"},
+	{Key: VisibilityProcessorUpdateAckInterval, Type: ValueTypeDuration, Constraints: ConstraintNone, Description: "Update interval for visibilityQueueProcessor"},
+	{Key: VisibilityProcessorUpdateAckIntervalJitterCoefficient, Type: ValueTypeFloat, Constraints: ConstraintNone, Description: "The update interval jitter coefficient"},
+	{Key: VisibilityProcessorCompleteTaskInterval, Type: ValueTypeDuration, Constraints: ConstraintNone, Description: "Complete timer interval for visibilityQueueProcessor"},
+	{Key: VisibilityProcessorPollBackoffInterval, Type: ValueTypeDuration, Constraints: ConstraintNone, Description: "The poll backoff interval if task redispatcher's size exceeds limit for visibilityQueueProcessor"},
+	{Key: VisibilityProcessorEnsureCloseBeforeDelete, Type: ValueTypeBool, Constraints: ConstraintNone, Description: "Means we ensure the visibility of an execution is closed before we delete its visibility records"},
+	{Key: VisibilityProcessorEnableCloseWorkflowCleanup, Type: ValueTypeBool, Constraints: ConstraintNamespace, Description: "To clean up the mutable state after visibility close task has been processed. Must use Elasticsearch as visibility store, otherwise workflow data (eg: search attributes) will be lost after workflow is closed."},
+	{Key: ArchivalTaskBatchSize, Type: ValueTypeInt, Constraints: ConstraintNone, Description: "Batch size for archivalQueueProcessor"},
+	{Key: ArchivalProcessorMaxPollRPS, Type: ValueTypeInt, Constraints: ConstraintNone, Description: "Max poll rate per second for archivalQueueProcessor"},
+	{Key: ArchivalProcessorMaxPollHostRPS, Type: ValueTypeInt, Constraints: ConstraintNone, Description: "Max poll rate per second for all archivalQueueProcessor on a host"},
+	{Key: ArchivalProcessorSchedulerWorkerCount, Type: ValueTypeInt, Constraints: ConstraintNone, Description: "The number of workers in the host level task scheduler for archivalQueueProcessor"},
+	{Key: ArchivalProcessorMaxPollInterval, Type: ValueTypeDuration, Constraints: ConstraintNone, Description: "Max poll interval for archivalQueueProcessor"},
+	{Key: ArchivalProcessorMaxPollIntervalJitterCoefficient, Type: ValueTypeFloat, Constraints: ConstraintNone, Description: "The max poll interval jitter coefficient"},
+	{Key: ArchivalProcessorUpdateAckInterval, Type: ValueTypeDuration, Constraints: ConstraintNone, Description: "Update interval for archivalQueueProcessor"},
+	{Key: ArchivalProcessorUpdateAckIntervalJitterCoefficient, Type: ValueTypeFloat, Constraints: ConstraintNone, Description: "The update interval jitter coefficient"},
+	{Key: ArchivalProcessorPollBackoffInterval, Type: ValueTypeDuration, Constraints: ConstraintNone, Description: "The poll backoff interval if task redispatcher's size exceeds limit for archivalQueueProcessor"},
+	{Key: ArchivalProcessorArchiveDelay, Type: ValueTypeDuration, Constraints: ConstraintNone, Description: "The delay before archivalQueueProcessor starts to process archival tasks"},
+	{Key: ArchivalBackendMaxRPS, Type: ValueTypeFloat, Constraints: ConstraintNone, Description: "The maximum rate of requests per second to the archival backend"},
+	{Key: HistoryExportURI, Type: ValueTypeString, Constraints: ConstraintNamespace, Description: "The file:// or s3:// URI that closed workflow histories and visibility records of a namespace are exported to", Validate: validateExportURI},
+	{Key: HistoryExportFormat, Type: ValueTypeString, Constraints: ConstraintNamespace, Description: "The format of exported histories and visibility records, jsonl or parquet", Validate: validateExportFormat},
+	{Key: HistoryExportBatchInterval, Type: ValueTypeDuration, Constraints: ConstraintNone, Description: "The time window in which the workflow executions exported to the same partition are batched into one file"},
+	{Key: HistoryExportBatchMaxExecutions, Type: ValueTypeInt, Constraints: ConstraintNone, Description: "The max number of workflow executions in a batch of exported files"},
+	{Key: WorkflowExecutionMaxInFlightUpdates, Type: ValueTypeInt, Constraints: ConstraintNamespace, Description: "The max number of updates that can be in-flight (admitted but not yet completed) for any given workflow execution."},
+	{Key: WorkflowExecutionMaxTotalUpdates, Type: ValueTypeInt, Constraints: ConstraintNamespace, Description: "The max number of updates that any given workflow execution can receive."},
+	{Key: ReplicatorTaskBatchSize, Type: ValueTypeInt, Constraints: ConstraintNone, Description: "Batch size for ReplicatorProcessor"},
+	{Key: ReplicatorMaxSkipTaskCount, Type: ValueTypeInt, Constraints: ConstraintNone, Description: "Maximum number of tasks that can be skipped during tasks pagination due to not meeting filtering conditions (e.g. missed namespace)."},
+	{Key: ReplicatorTaskWorkerCount, Type: ValueTypeInt, Constraints: ConstraintNone, Description: "Number of worker for ReplicatorProcessor"},
+	{Key: ReplicatorProcessorMaxPollRPS, Type: ValueTypeInt, Constraints: ConstraintNone, Description: "Max poll rate per second for ReplicatorProcessor"},
+	{Key: ReplicatorProcessorMaxPollInterval, Type: ValueTypeDuration, Constraints: ConstraintNone, Description: "Max poll interval for ReplicatorProcessor"},
+	{Key: ReplicatorProcessorMaxPollIntervalJitterCoefficient, Type: ValueTypeFloat, Constraints: ConstraintNone, Description: "The max poll interval jitter coefficient"},
+	{Key: ReplicatorProcessorUpdateAckInterval, Type: ValueTypeDuration, Constraints: ConstraintNone, Description: "Update interval for ReplicatorProcessor"},
+	{Key: ReplicatorProcessorUpdateAckIntervalJitterCoefficient, Type: ValueTypeFloat, Constraints: ConstraintNone, Description: "The update interval jitter coefficient"},
+	{Key: ReplicatorProcessorEnablePriorityTaskProcessor, Type: ValueTypeBool, Constraints: ConstraintNone, Description: "Indicates whether priority task processor should be used for ReplicatorProcessor"},
+	{Key: MaximumBufferedEventsBatch, Type: ValueTypeInt, Constraints: ConstraintNone, Description: "The maximum permissible number of buffered events for any given mutable state."},
+	{Key: MaximumBufferedEventsSizeInBytes, Type: ValueTypeInt, Constraints: ConstraintNone, Description: "The maximum permissible size of all buffered events for any given mutable state. The total size is determined by the sum of the size, in bytes, of each HistoryEvent proto."},
+	{Key: MaximumSignalsPerExecution, Type: ValueTypeInt, Constraints: ConstraintNamespace, Description: "Max number of signals supported by single execution"},
+	{Key: ShardUpdateMinInterval, Type: ValueTypeDuration, Constraints: ConstraintNone, Description: "The minimal time interval which the shard info can be updated"},
+	{Key: ShardSyncMinInterval, Type: ValueTypeDuration, Constraints: ConstraintNone, Description: "The minimal time interval which the shard info should be sync to remote"},
+	{Key: EmitShardLagLog, Type: ValueTypeBool, Constraints: ConstraintNone, Description: "Whether emit the shard lag log"},
+	{Key: DefaultEventEncoding, Type: ValueTypeString, Constraints: ConstraintNamespace, Description: "The encoding type for history events"},
+	{Key: DefaultActivityRetryPolicy, Type: ValueTypeMap, Constraints: ConstraintNamespace, Description: "Represents the out-of-box retry policy for activities where the user has not specified an explicit RetryPolicy"},
+	{Key: DefaultWorkflowRetryPolicy, Type: ValueTypeMap, Constraints: ConstraintNamespace, Description: "Represents the out-of-box retry policy for unset fields where the user has set an explicit RetryPolicy, but not specified all the fields"},
+	{Key: HistoryMaxAutoResetPoints, Type: ValueTypeInt, Constraints: ConstraintNamespace, Description: "The key for max number of auto reset points stored in mutableState"},
+	{Key: EnableParentClosePolicy, Type: ValueTypeBool, Constraints: ConstraintNamespace, Description: "Whether to  ParentClosePolicy"},
+	{Key: ParentClosePolicyThreshold, Type: ValueTypeInt, Constraints: ConstraintNamespace, Description: "Decides that parent close policy will be processed by sys workers(if enabled) if the number of children greater than or equal to this threshold"},
+	{Key: NumParentClosePolicySystemWorkflows, Type: ValueTypeInt, Constraints: ConstraintNone, Description: "Key for number of parentClosePolicy system workflows running in total"},
+	{Key: HistoryThrottledLogRPS, Type: ValueTypeInt, Constraints: ConstraintNone, Description: "The rate limit on number of log messages emitted per second for throttled logger"},
+	{Key: StickyTTL, Type: ValueTypeDuration, Constraints: ConstraintNamespace, Description: "To expire a sticky taskqueue if no update more than this duration"},
+	{Key: WorkflowTaskHeartbeatTimeout, Type: ValueTypeDuration, Constraints: ConstraintNamespace, Description: "For workflow task heartbeat"},
+	{Key: WorkflowTaskCriticalAttempts, Type: ValueTypeInt, Constraints: ConstraintNone, Description: "The number of attempts for a workflow task that's regarded as critical"},
+	{Key: WorkflowTaskRetryMaxInterval, Type: ValueTypeDuration, Constraints: ConstraintNone, Description: "The maximum interval added to a workflow task's startToClose timeout for slowing down retry"},
+	{Key: DefaultWorkflowTaskTimeout, Type: ValueTypeDuration, Constraints: ConstraintNamespace, Description: "For a workflow task"},
+	{Key: SkipReapplicationByNamespaceID, Type: ValueTypeBool, Constraints: ConstraintNamespaceID, Description: "Whether skipping a event re-application for a namespace"},
+	{Key: StandbyTaskReReplicationContextTimeout, Type: ValueTypeDuration, Constraints: ConstraintNamespaceID, Description: "The context timeout for standby task re-replication"},
+	{Key: MaxBufferedQueryCount, Type: ValueTypeInt, Constraints: ConstraintNone, Description: "Indicates max buffer query count"},
+	{Key: MutableStateChecksumGenProbability, Type: ValueTypeInt, Constraints: ConstraintNamespace, Description: "The probability [0-100] that checksum will be generated for mutable state"},
+	{Key: MutableStateChecksumVerifyProbability, Type: ValueTypeInt, Constraints: ConstraintNamespace, Description: "The probability [0-100] that checksum will be verified for mutable state"},
+	{Key: MutableStateChecksumInvalidateBefore, Type: ValueTypeFloat, Constraints: ConstraintNone, Description: "The epoch timestamp before which all checksums are to be discarded"},
+	{Key: ReplicationTaskFetcherParallelism, Type: ValueTypeInt, Constraints: ConstraintNone, Description: "Determines how many go routines we spin up for fetching tasks"},
+	{Key: ReplicationTaskFetcherAggregationInterval, Type: ValueTypeDuration, Constraints: ConstraintNone, Description: "Determines how frequently the fetch requests are sent"},
+	{Key: ReplicationTaskFetcherTimerJitterCoefficient, Type: ValueTypeFloat, Constraints: ConstraintNone, Description: "The jitter for fetcher timer"},
+	{Key: ReplicationTaskFetcherErrorRetryWait, Type: ValueTypeDuration, Constraints: ConstraintNone, Description: "The wait time when fetcher encounters error"},
+	{Key: ReplicationTaskProcessorErrorRetryWait, Type: ValueTypeDuration, Constraints: ConstraintShardID, Description: "The initial retry wait when we see errors in applying replication tasks"},
+	{Key: ReplicationTaskProcessorErrorRetryBackoffCoefficient, Type: ValueTypeFloat, Constraints: ConstraintShardID, Description: "The retry wait backoff time coefficient"},
+	{Key: ReplicationTaskProcessorErrorRetryMaxInterval, Type: ValueTypeDuration, Constraints: ConstraintShardID, Description: "The retry wait backoff max duration"},
+	{Key: ReplicationTaskProcessorErrorRetryMaxAttempts, Type: ValueTypeInt, Constraints: ConstraintShardID, Description: "The max retry attempts for applying replication tasks"},
+	{Key: ReplicationTaskProcessorErrorRetryExpiration, Type: ValueTypeDuration, Constraints: ConstraintShardID, Description: "The max retry duration for applying replication tasks"},
+	{Key: ReplicationTaskProcessorNoTaskInitialWait, Type: ValueTypeDuration, Constraints: ConstraintShardID, Description: "The wait time when not ask is returned"},
+	{Key: ReplicationTaskProcessorCleanupInterval, Type: ValueTypeDuration, Constraints: ConstraintShardID, Description: "Determines how frequently the cleanup replication queue"},
+	{Key: ReplicationTaskProcessorCleanupJitterCoefficient, Type: ValueTypeFloat, Constraints: ConstraintShardID, Description: "The jitter for cleanup timer"},
+	{Key: ReplicationTaskProcessorStartWait, Type: ValueTypeDuration, Constraints: ConstraintShardID, Description: "The wait time before each task processing batch"},
+	{Key: ReplicationTaskProcessorHostQPS, Type: ValueTypeFloat, Constraints: ConstraintNone, Description: "The qps of task processing rate limiter on host level"},
+	{Key: ReplicationTaskProcessorShardQPS, Type: ValueTypeFloat, Constraints: ConstraintNone, Description: "The qps of task processing rate limiter on shard level"},
+	{Key: ReplicationBypassCorruptedData, Type: ValueTypeBool, Constraints: ConstraintNamespaceID, Description: "The flag to bypass corrupted workflow data in source cluster"},
+	{Key: ReplicationEnableDLQMetrics, Type: ValueTypeBool, Constraints: ConstraintNone, Description: "The flag to emit DLQ metrics"},
+	{Key: HistoryTaskDLQEnabled, Type: ValueTypeBool, Constraints: ConstraintNone, Description: "Enables the history task DLQ. This applies to internal tasks like transfer and timer tasks. Do not turn this on if you aren't using Cassandra as the history task DLQ is not implemented for other databases."},
+	{Key: ReplicationStreamSyncStatusDuration, Type: ValueTypeDuration, Constraints: ConstraintNone, Description: "Sync replication status duration"},
+	{Key: ReplicationStreamMinReconnectDuration, Type: ValueTypeDuration, Constraints: ConstraintNone, Description: "Minimal replication stream reconnection duration"},
+	{Key: ReplicationProcessorSchedulerQueueSize, Type: ValueTypeInt, Constraints: ConstraintNone, Description: "The replication task executor queue size"},
+	{Key: ReplicationProcessorSchedulerWorkerCount, Type: ValueTypeInt, Constraints: ConstraintNone, Description: "The replication task executor worker count"},
+	{Key: EnableEagerNamespaceRefresher, Type: ValueTypeBool, Constraints: ConstraintNone, Description: "A feature flag for eagerly refresh namespace during processing replication task"},
+	{Key: EnableReplicationTaskBatching, Type: ValueTypeBool, Constraints: ConstraintNone, Description: "A feature flag for batching replicate history event task"},
+	{Key: WorkerPersistenceMaxQPS, Type: ValueTypeInt, Constraints: ConstraintNone, Description: "The max qps worker host can query DB"},
+	{Key: WorkerPersistenceGlobalMaxQPS, Type: ValueTypeInt, Constraints: ConstraintNone, Description: "The max qps worker cluster can query DB"},
+	{Key: WorkerPersistenceNamespaceMaxQPS, Type: ValueTypeInt, Constraints: ConstraintNamespace, Description: "The max qps each namespace on worker host can query DB"},
+	{Key: WorkerPersistenceGlobalNamespaceMaxQPS, Type: ValueTypeInt, Constraints: ConstraintNamespace, Description: "WorkerPersistenceNamespaceMaxQPS is the max qps each namespace in worker cluster can query DB"},
+	{Key: WorkerEnablePersistencePriorityRateLimiting, Type: ValueTypeBool, Constraints: ConstraintNone, Description: "Indicates if priority rate limiting is enabled in worker persistence client"},
+	{Key: WorkerPersistenceDynamicRateLimitingParams, Type: ValueTypeMap, Constraints: ConstraintNone, Description: "A map that contains all adjustable dynamic rate limiting params see DefaultDynamicRateLimitingParams for available options and defaults"},
+	{Key: WorkerIndexerConcurrency, Type: ValueTypeInt, Constraints: ConstraintNone, Description: "The max concurrent messages to be processed at any given time"},
+	{Key: WorkerESProcessorNumOfWorkers, Type: ValueTypeInt, Constraints: ConstraintNone, Description: "Num of workers for esProcessor"},
+	{Key: WorkerESProcessorBulkActions, Type: ValueTypeInt, Constraints: ConstraintNone, Description: "Max number of requests in bulk for esProcessor"},
+	{Key: WorkerESProcessorBulkSize, Type: ValueTypeInt, Constraints: ConstraintNone, Description: "Max total size of bulk in bytes for esProcessor"},
+	{Key: WorkerESProcessorFlushInterval, Type: ValueTypeDuration, Constraints: ConstraintNone, Description: "Flush interval for esProcessor"},
+	{Key: WorkerESProcessorAckTimeout, Type: ValueTypeDuration, Constraints: ConstraintNone, Description: "The timeout that store will wait to get ack signal from ES processor. Should be at least WorkerESProcessorFlushInterval+<time to process request>."},
+	{Key: WorkerThrottledLogRPS, Type: ValueTypeInt, Constraints: ConstraintNone, Description: "The rate limit on number of log messages emitted per second for throttled logger"},
+	{Key: WorkerScannerMaxConcurrentActivityExecutionSize, Type: ValueTypeInt, Constraints: ConstraintNone, Description: "Indicates worker scanner max concurrent activity execution size"},
+	{Key: WorkerScannerMaxConcurrentWorkflowTaskExecutionSize, Type: ValueTypeInt, Constraints: ConstraintNone, Description: "Indicates worker scanner max concurrent workflow execution size"},
+	{Key: WorkerScannerMaxConcurrentActivityTaskPollers, Type: ValueTypeInt, Constraints: ConstraintNone, Description: "Indicates worker scanner max concurrent activity pollers"},
+	{Key: WorkerScannerMaxConcurrentWorkflowTaskPollers, Type: ValueTypeInt, Constraints: ConstraintNone, Description: "Indicates worker scanner max concurrent workflow pollers"},
+	{Key: ScannerPersistenceMaxQPS, Type: ValueTypeInt, Constraints: ConstraintNone, Description: "The maximum rate of persistence calls from worker.Scanner"},
+	{Key: ExecutionScannerPerHostQPS, Type: ValueTypeInt, Constraints: ConstraintNone, Description: "The maximum rate of calls per host from executions.Scanner"},
+	{Key: ExecutionScannerPerShardQPS, Type: ValueTypeInt, Constraints: ConstraintNone, Description: "The maximum rate of calls per shard from executions.Scanner"},
+	{Key: ExecutionDataDurationBuffer, Type: ValueTypeDuration, Constraints: ConstraintNone, Description: "The data TTL duration buffer of execution data"},
+	{Key: ExecutionScannerWorkerCount, Type: ValueTypeInt, Constraints: ConstraintNone, Description: "The execution scavenger worker count"},
+	{Key: ExecutionScannerHistoryEventIdValidator, Type: ValueTypeBool, Constraints: ConstraintNone, Description: "The flag to enable history event id validator"},
+	{Key: TaskQueueScannerEnabled, Type: ValueTypeBool, Constraints: ConstraintNone, Description: "Indicates if task queue scanner should be started as part of worker.Scanner"},
+	{Key: BuildIdScavengerEnabled, Type: ValueTypeBool, Constraints: ConstraintNone, Description: "Indicates if the build id scavenger should be started as part of worker.Scanner"},
+	{Key: HistoryScannerEnabled, Type: ValueTypeBool, Constraints: ConstraintNone, Description: "Indicates if history scanner should be started as part of worker.Scanner"},
+	{Key: ExecutionsScannerEnabled, Type: ValueTypeBool, Constraints: ConstraintNone, Description: "Indicates if executions scanner should be started as part of worker.Scanner"},
+	{Key: HistoryScannerDataMinAge, Type: ValueTypeDuration, Constraints: ConstraintNone, Description: "Indicates the history scanner cleanup minimum age."},
+	{Key: HistoryScannerVerifyRetention, Type: ValueTypeBool, Constraints: ConstraintNone, Description: "Indicates the history scanner verify data retention. If the service configures with archival feature enabled, update worker.historyScannerVerifyRetention to be double of the data retention."},
+	{Key: ArchivalVerifierEnabled, Type: ValueTypeBool, Constraints: ConstraintNone, Description: "Indicates if the archival verifier should be started as part of worker.Scanner"},
+	{Key: ArchivalVerifierSampleRate, Type: ValueTypeFloat, Constraints: ConstraintNone, Description: "The fraction of closed executions whose archived history is verified in each run. A value of 1.0 verifies every archived execution."},
+	{Key: ArchivalVerifierRPS, Type: ValueTypeFloat, Constraints: ConstraintNone, Description: "The rate limit of executions verified per second by the archival verifier"},
+	{Key: ArchivalVerifierRepairEnabled, Type: ValueTypeBool, Constraints: ConstraintNone, Description: "Indicates if the archival verifier should re-archive missing or corrupt histories from primary storage while they still exist there"},
+	{Key: ArchivalVerifierMinCloseAge, Type: ValueTypeDuration, Constraints: ConstraintNone, Description: "The minimum time since close before an execution is expected to be archived"},
+	{Key: VisibilityScavengerEnabled, Type: ValueTypeBool, Constraints: ConstraintNone, Description: "Indicates if the visibility scavenger should be started as part of worker.Scanner"},
+	{Key: VisibilityScavengerSampleRate, Type: ValueTypeFloat, Constraints: ConstraintNone, Description: "The fraction of visibility records checked against mutable state in each run. Each namespace lists a random window of start times covering that fraction of its retention. A value of 1.0 checks every record."},
+	{Key: VisibilityScavengerRPS, Type: ValueTypeFloat, Constraints: ConstraintNone, Description: "The rate limit of visibility records checked per second by the visibility scavenger"},
+	{Key: VisibilityScavengerRepairEnabled, Type: ValueTypeBool, Constraints: ConstraintNone, Description: "Indicates if the visibility scavenger should fix the records that don't match mutable state, by re-enqueuing visibility tasks or deleting orphaned records"},
+	{Key: VisibilityScavengerMinAge, Type: ValueTypeDuration, Constraints: ConstraintNone, Description: "The minimum time since the last update of an execution before its visibility record is expected to match mutable state"},
+	{Key: EnableBatcher, Type: ValueTypeBool, Constraints: ConstraintNamespace, Description: "Decides whether start batcher in our worker"},
+	{Key: BatcherRPS, Type: ValueTypeInt, Constraints: ConstraintNamespace, Description: "Controls number the rps of batch operations"},
+	{Key: BatcherConcurrency, Type: ValueTypeInt, Constraints: ConstraintNamespace, Description: "Controls the concurrency of one batch operation"},
+	{Key: WorkerParentCloseMaxConcurrentActivityExecutionSize, Type: ValueTypeInt, Constraints: ConstraintNone, Description: "Indicates worker parent close worker max concurrent activity execution size"},
+	{Key: WorkerParentCloseMaxConcurrentWorkflowTaskExecutionSize, Type: ValueTypeInt, Constraints: ConstraintNone, Description: "Indicates worker parent close worker max concurrent workflow execution size"},
+	{Key: WorkerParentCloseMaxConcurrentActivityTaskPollers, Type: ValueTypeInt, Constraints: ConstraintNone, Description: "Indicates worker parent close worker max concurrent activity pollers"},
+	{Key: WorkerParentCloseMaxConcurrentWorkflowTaskPollers, Type: ValueTypeInt, Constraints: ConstraintNone, Description: "Indicates worker parent close worker max concurrent workflow pollers"},
+	{Key: WorkerPerNamespaceWorkerCount, Type: ValueTypeInt, Constraints: ConstraintNamespace, Description: "Controls number of per-ns (scheduler, batcher, etc.) workers to run per namespace"},
+	{Key: WorkerPerNamespaceWorkerOptions, Type: ValueTypeMap, Constraints: ConstraintNamespace, Description: "SDK worker options for per-namespace worker"},
+	{Key: WorkerEnableScheduler, Type: ValueTypeBool, Constraints: ConstraintNamespace, Description: "Controls whether to start the worker for scheduled workflows"},
+	{Key: WorkerStickyCacheSize, Type: ValueTypeInt, Constraints: ConstraintNone, Description: "Controls the sticky cache size for SDK workers on worker nodes (shared between all workers in the process, cannot be changed after startup)"},
+	{Key: SchedulerNamespaceStartWorkflowRPS, Type: ValueTypeFloat, Constraints: ConstraintNamespace, Description: "The per-namespace limit for starting workflows by schedules"},
+	{Key: WorkerDeleteNamespaceActivityLimitsConfig, Type: ValueTypeMap, Constraints: ConstraintNone, Description: "A map that contains a copy of relevant sdkworker.Options settings for controlling remote activity concurrency for delete namespace workflows."},
+}
@@ -0,0 +1,255 @@
+// The MIT License
+//
+// Copyright (c) 2020 Temporal Technologies Inc.  All rights reserved.
+//
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package dynamicconfig
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"sort"
+	"strings"
+
+	enumspb "go.temporal.io/api/enums/v1"
+
+	enumsspb "go.temporal.io/server/api/enums/v1"
+)
+
+type (
+	// ValueType is the type of value that the server expects for a key.
+	ValueType int
+
+	// ConstraintKind is a bit set of the Constraints fields that a key may be constrained by.
+	ConstraintKind int
+
+	// KeyDefinition describes a known dynamic config key.
+	KeyDefinition struct {
+		Key         Key
+		Type        ValueType
+		Constraints ConstraintKind
+		Description string
+		// Validate checks a value of the right type further, it's nil if every value of the type is valid.
+		Validate func(value any) error
+	}
+)
+
+const (
+	ValueTypeBool ValueType = iota + 1
+	ValueTypeInt
+	ValueTypeFloat
+	ValueTypeString
+	ValueTypeDuration
+	ValueTypeMap
+)
+
+const (
+	ConstraintNone      ConstraintKind = 0
+	ConstraintNamespace ConstraintKind = 1 << iota
+	ConstraintNamespaceID
+	// ConstraintTaskQueue covers both TaskQueueName and TaskQueueType.
+	ConstraintTaskQueue
+	ConstraintShardID
+	ConstraintHistoryTaskType
+)
+
+// lower-cased key -> definition
+var keyDefinitionsByKey = func() map[string]KeyDefinition {
+	m := make(map[string]KeyDefinition, len(keyDefinitions))
+	for _, def := range keyDefinitions {
+		m[strings.ToLower(def.Key.String())] = def
+	}
+	return m
+}()
+
+// GetKeyDefinition returns the definition of a known key. Keys are case-insensitive.
+func GetKeyDefinition(key Key) (KeyDefinition, bool) {
+	def, ok := keyDefinitionsByKey[strings.ToLower(key.String())]
+	return def, ok
+}
+
+// GetKeyDefinitions returns the definitions of all known keys.
+func GetKeyDefinitions() []KeyDefinition {
+	return append([]KeyDefinition(nil), keyDefinitions...)
+}
+
+func (t ValueType) String() string {
+	switch t {
+	case ValueTypeBool:
+		return "bool"
+	case ValueTypeInt:
+		return "int"
+	case ValueTypeFloat:
+		return "float"
+	case ValueTypeString:
+		return "string"
+	case ValueTypeDuration:
+		return "duration"
+	case ValueTypeMap:
+		return "map"
+	default:
+		return "unknown"
+	}
+}
+
+func (k ConstraintKind) String() string {
+	if k == ConstraintNone {
+		return "none"
+	}
+	var names []string
+	if k&ConstraintNamespace != 0 {
+		names = append(names, "namespace")
+	}
+	if k&ConstraintNamespaceID != 0 {
+		names = append(names, "namespaceID")
+	}
+	if k&ConstraintTaskQueue != 0 {
+		names = append(names, "taskQueueName", "taskType")
+	}
+	if k&ConstraintShardID != 0 {
+		names = append(names, "shardID")
+	}
+	if k&ConstraintHistoryTaskType != 0 {
+		names = append(names, "historyTaskType")
+	}
+	return strings.Join(names, ",")
+}
+
+// ValidateFile parses the contents of a dynamic config file and checks every key and value
+// against the known key definitions. It returns an error if the file can't be parsed at all,
+// and otherwise the list of problems found, which is empty for a valid file.
+func ValidateFile(contents []byte) ([]error, error) {
+	values, err := loadYamlFile(contents)
+	if err != nil {
+		return nil, err
+	}
+	return validateValues(values), nil
+}
+
+// validateValues reports unknown keys, values that can't be converted to the key's type and
+// constraints that the key is never read with. Errors are sorted by key.
+func validateValues(values map[string][]ConstrainedValue) []error {
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	var errs []error
+	for _, key := range keys {
+		def, ok := GetKeyDefinition(Key(key))
+		if !ok {
+			errs = append(errs, fmt.Errorf("%s: unknown key", key))
+			continue
+		}
+		for _, cv := range values[key] {
+			err := validateValueType(def.Type, cv.Value)
+			if err == nil && def.Validate != nil {
+				err = def.Validate(cv.Value)
+			}
+			if err != nil {
+				errs = append(errs, fmt.Errorf("%s: invalid value %v for constraints %s: %w", key, cv.Value, formatConstraints(cv.Constraints), err))
+			}
+			if used := constraintKinds(cv.Constraints); used&^def.Constraints != 0 {
+				errs = append(errs, fmt.Errorf("%s: unsupported constraints %s, key supports: %s", key, formatConstraints(cv.Constraints), def.Constraints))
+			}
+		}
+	}
+	return errs
+}
+
+func validateValueType(valueType ValueType, value any) error {
+	var err error
+	switch valueType {
+	case ValueTypeBool:
+		_, err = convertBool(value)
+	case ValueTypeInt:
+		_, err = convertInt(value)
+	case ValueTypeFloat:
+		_, err = convertFloat(value)
+	case ValueTypeString:
+		_, err = convertString(value)
+	case ValueTypeDuration:
+		_, err = convertDuration(value)
+	case ValueTypeMap:
+		_, err = convertMap(value)
+	}
+	return err
+}
+
+// validateExportURI accepts the URIs of HistoryExportURI. It checks the same as export.ValidateURI, which can't be used
+// here since the export package depends on this one.
+func validateExportURI(value any) error {
+	s, err := convertString(value)
+	if err != nil || s == "" {
+		return err
+	}
+	uri, err := url.ParseRequestURI(s)
+	if err != nil {
+		return err
+	}
+	if uri.Scheme != "file" && uri.Scheme != "s3" {
+		return errors.New("export URI scheme must be file or s3")
+	}
+	return nil
+}
+
+// validateExportFormat accepts the formats of HistoryExportFormat, like export.ParseFormat.
+func validateExportFormat(value any) error {
+	s, err := convertString(value)
+	if err != nil {
+		return err
+	}
+	if s != "jsonl" && s != "parquet" {
+		return fmt.Errorf("unknown export format: %q", s)
+	}
+	return nil
+}
+
+func constraintKinds(cs Constraints) ConstraintKind {
+	kinds := ConstraintNone
+	if cs.Namespace != "" {
+		kinds |= ConstraintNamespace
+	}
+	if cs.NamespaceID != "" {
+		kinds |= ConstraintNamespaceID
+	}
+	if cs.TaskQueueName != "" || cs.TaskQueueType != enumspb.TASK_QUEUE_TYPE_UNSPECIFIED {
+		kinds |= ConstraintTaskQueue
+	}
+	if cs.ShardID != 0 {
+		kinds |= ConstraintShardID
+	}
+	if cs.TaskType != enumsspb.TASK_TYPE_UNSPECIFIED {
+		kinds |= ConstraintHistoryTaskType
+	}
+	return kinds
+}
+
+func formatConstraints(cs Constraints) string {
+	var b strings.Builder
+	b.WriteString("{")
+	appendConstraints(&b, cs)
+	b.WriteString("}")
+	return b.String()
+}
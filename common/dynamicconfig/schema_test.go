@@ -0,0 +1,147 @@
+// The MIT License
+//
+// Copyright (c) 2020 Temporal Technologies Inc.  All rights reserved.
+//
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package dynamicconfig
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestKeyDefinitions_CoverAllKeys(t *testing.T) {
+	fset := token.NewFileSet()
+	f, err := parser.ParseFile(fset, "constants.go", nil, 0)
+	require.NoError(t, err)
+
+	var keys []string
+	ast.Inspect(f, func(n ast.Node) bool {
+		spec, ok := n.(*ast.ValueSpec)
+		if !ok {
+			return true
+		}
+		for _, value := range spec.Values {
+			if lit, ok := value.(*ast.BasicLit); ok && lit.Kind == token.STRING {
+				key, err := strconv.Unquote(lit.Value)
+				require.NoError(t, err)
+				keys = append(keys, key)
+			}
+		}
+		return true
+	})
+	require.NotEmpty(t, keys)
+
+	for _, key := range keys {
+		_, ok := GetKeyDefinition(Key(key))
+		require.Truef(t, ok, "key %q has no entry in keyDefinitions", key)
+	}
+	require.Len(t, keyDefinitions, len(keyDefinitionsByKey), "duplicate entries in keyDefinitions")
+}
+
+func TestValidateFile_RepoConfigs(t *testing.T) {
+	files, err := filepath.Glob("../../config/dynamicconfig/*.yaml")
+	require.NoError(t, err)
+	require.NotEmpty(t, files)
+
+	for _, file := range files {
+		contents, err := os.ReadFile(file)
+		require.NoError(t, err)
+		validationErrs, err := ValidateFile(contents)
+		require.NoError(t, err, file)
+		require.Empty(t, validationErrs, file)
+	}
+}
+
+func TestValidateFile(t *testing.T) {
+	validationErrs, err := ValidateFile([]byte(`
+frontend.rps:
+- value: 1000
+  constraints: {}
+frontend.namespaceRPS:
+- value: 10
+  constraints:
+    namespace: ns
+- value: ten
+  constraints: {}
+history.persistenceMaxQPS:
+- value: 10
+  constraints:
+    namespace: ns
+matching.numTaskqueueReadPartitions:
+- value: 2
+  constraints:
+    namespace: ns
+    taskQueueName: tq
+    taskType: Activity
+limit.blobSize.warn:
+- value: 1
+  constraints:
+    shardID: 3
+frontend.unknownKey:
+- value: true
+`))
+	require.NoError(t, err)
+	require.Len(t, validationErrs, 4)
+	require.ErrorContains(t, validationErrs[0], "frontend.namespaceRPS: invalid value ten")
+	require.ErrorContains(t, validationErrs[1], "frontend.unknownKey: unknown key")
+	require.ErrorContains(t, validationErrs[2], "history.persistenceMaxQPS: unsupported constraints {{Namespace:ns}}")
+	require.ErrorContains(t, validationErrs[3], "limit.blobSize.warn: unsupported constraints {{ShardID:3}}")
+
+	_, err = ValidateFile([]byte(`not: [valid`))
+	require.Error(t, err)
+}
+
+func TestValidateFile_ExportConfig(t *testing.T) {
+	validationErrs, err := ValidateFile([]byte(`
+history.exportURI:
+- value: s3://bucket/export
+  constraints:
+    namespace: ns1
+- value: file:///tmp/export
+  constraints:
+    namespace: ns2
+- value: gs://bucket/export
+  constraints:
+    namespace: ns3
+- value: not a URI
+  constraints: {}
+history.exportFormat:
+- value: parquet
+  constraints: {}
+- value: csv
+  constraints:
+    namespace: ns3
+`))
+	require.NoError(t, err)
+	require.Len(t, validationErrs, 3)
+	require.ErrorContains(t, validationErrs[0], "history.exportFormat: invalid value csv")
+	require.ErrorContains(t, validationErrs[1], "history.exportURI: invalid value gs://bucket/export")
+	require.ErrorContains(t, validationErrs[2], "history.exportURI: invalid value not a URI")
+}
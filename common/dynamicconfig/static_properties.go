@@ -87,6 +87,11 @@ func GetStringPropertyFn(value string) func() string {
 	return func() string { return value }
 }
 
+// GetStringPropertyFnFilteredByNamespace returns value as StringPropertyFnWithNamespaceFilters
+func GetStringPropertyFnFilteredByNamespace(value string) func(namespace string) string {
+	return func(namespace string) string { return value }
+}
+
 // GetMapPropertyFn returns value as MapPropertyFn
 func GetMapPropertyFn(value map[string]interface{}) func() map[string]interface{} {
 	return func() map[string]interface{} { return value }
@@ -96,3 +101,10 @@ func GetMapPropertyFn(value map[string]interface{}) func() map[string]interface{
 func GetMapPropertyFnWithNamespaceFilter(value map[string]interface{}) func(namespace string) map[string]interface{} {
 	return func(namespace string) map[string]interface{} { return value }
 }
+
+// GetMapPropertyFnFilteredByTaskQueueInfo returns value as MapPropertyFnWithTaskQueueInfoFilters
+func GetMapPropertyFnFilteredByTaskQueueInfo(value map[string]interface{}) func(namespace string, taskQueue string, taskType enumspb.TaskQueueType) map[string]interface{} {
+	return func(namespace string, taskQueue string, taskType enumspb.TaskQueueType) map[string]interface{} {
+		return value
+	}
+}
@@ -295,6 +295,11 @@ func WorkflowTaskQueueType(taskQueueType enumspb.TaskQueueType) ZapTag {
 	return NewStringTag("wf-task-queue-type", taskQueueType.String())
 }
 
+// TaskPriority returns tag for the task priority of a task queue backlog
+func TaskPriority(priority int32) ZapTag {
+	return NewInt32("task-priority", priority)
+}
+
 // WorkflowTaskQueueName returns tag for WorkflowTaskQueueName
 func WorkflowTaskQueueName(taskQueueName string) ZapTag {
 	return NewStringTag("wf-task-queue-name", taskQueueName)
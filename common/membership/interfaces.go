@@ -44,6 +44,9 @@ var ErrInsufficientHosts = serviceerror.NewUnavailable("Not enough hosts to serv
 // ErrListenerAlreadyExist is thrown on a duplicate AddListener call from the same listener
 var ErrListenerAlreadyExist = errors.New("listener already exist for the service")
 
+// ErrDrainingNotSupported is returned when the membership provider can't announce draining to the other hosts
+var ErrDrainingNotSupported = serviceerror.NewFailedPrecondition("membership provider doesn't support draining hosts")
+
 // ErrIncorrectAddressFormat is thrown on incorrect address format
 var ErrIncorrectAddressFormat = errors.New("incorrect address format")
 
@@ -66,6 +69,13 @@ type (
 		// called, other members will discover that this node is no longer part of the
 		// ring. This primitive is useful to carry out graceful host shutdown during deployments.
 		EvictSelf() error
+		// SetDraining announces whether this member is draining. Draining members stay reachable
+		// but are left out of the service rings, so the keys they own move to the other members
+		// while they are still up. This is used to hand off ownership before a graceful shutdown.
+		// Returns ErrDrainingNotSupported if SupportsDraining is false.
+		SetDraining(draining bool) error
+		// SupportsDraining returns whether SetDraining is announced to the other members.
+		SupportsDraining() bool
 		// GetResolver returns the service resolver for a service in the cluster.
 		GetResolver(service primitives.ServiceName) (ServiceResolver, error)
 		// GetReachableMembers returns addresses of all members of the ring.
@@ -93,6 +103,9 @@ type (
 		MemberCount() int
 		// Members returns all known hosts available for this service.
 		Members() []HostInfo
+		// DrainingMembers returns the hosts of this service that announced they are draining.
+		// These hosts are not part of Members and never returned by Lookup.
+		DrainingMembers() []HostInfo
 		// RequestRefresh requests that the membership information be refreshed.
 		RequestRefresh()
 	}
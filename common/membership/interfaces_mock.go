@@ -103,6 +103,20 @@ func (mr *MockMonitorMockRecorder) GetResolver(service interface{}) *gomock.Call
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetResolver", reflect.TypeOf((*MockMonitor)(nil).GetResolver), service)
 }
 
+// SetDraining mocks base method.
+func (m *MockMonitor) SetDraining(draining bool) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetDraining", draining)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetDraining indicates an expected call of SetDraining.
+func (mr *MockMonitorMockRecorder) SetDraining(draining interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetDraining", reflect.TypeOf((*MockMonitor)(nil).SetDraining), draining)
+}
+
 // Start mocks base method.
 func (m *MockMonitor) Start() {
 	m.ctrl.T.Helper()
@@ -115,6 +129,20 @@ func (mr *MockMonitorMockRecorder) Start() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Start", reflect.TypeOf((*MockMonitor)(nil).Start))
 }
 
+// SupportsDraining mocks base method.
+func (m *MockMonitor) SupportsDraining() bool {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SupportsDraining")
+	ret0, _ := ret[0].(bool)
+	return ret0
+}
+
+// SupportsDraining indicates an expected call of SupportsDraining.
+func (mr *MockMonitorMockRecorder) SupportsDraining() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SupportsDraining", reflect.TypeOf((*MockMonitor)(nil).SupportsDraining))
+}
+
 // WaitUntilInitialized mocks base method.
 func (m *MockMonitor) WaitUntilInitialized(arg0 context.Context) error {
 	m.ctrl.T.Helper()
@@ -166,6 +194,20 @@ func (mr *MockServiceResolverMockRecorder) AddListener(name, notifyChannel inter
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddListener", reflect.TypeOf((*MockServiceResolver)(nil).AddListener), name, notifyChannel)
 }
 
+// DrainingMembers mocks base method.
+func (m *MockServiceResolver) DrainingMembers() []HostInfo {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DrainingMembers")
+	ret0, _ := ret[0].([]HostInfo)
+	return ret0
+}
+
+// DrainingMembers indicates an expected call of DrainingMembers.
+func (mr *MockServiceResolverMockRecorder) DrainingMembers() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DrainingMembers", reflect.TypeOf((*MockServiceResolver)(nil).DrainingMembers))
+}
+
 // Lookup mocks base method.
 func (m *MockServiceResolver) Lookup(key string) (HostInfo, error) {
 	m.ctrl.T.Helper()
@@ -397,6 +397,33 @@ func (rpo *monitor) EvictSelf() error {
 	return rpo.rp.SelfEvict()
 }
 
+func (rpo *monitor) SetDraining(draining bool) error {
+	labels, err := rpo.rp.Labels()
+	if err != nil {
+		return err
+	}
+	if draining {
+		err = labels.Set(drainingKey, drainingValue)
+	} else {
+		_, err = labels.Remove(drainingKey)
+	}
+	if err != nil {
+		return err
+	}
+
+	// Don't wait for the label change to be gossiped back, our own rings must reflect it right away.
+	for _, ring := range rpo.rings {
+		if err := ring.refresh(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (rpo *monitor) SupportsDraining() bool {
+	return true
+}
+
 func (rpo *monitor) GetResolver(service primitives.ServiceName) (membership.ServiceResolver, error) {
 	ring, found := rpo.rings[service]
 	if !found {
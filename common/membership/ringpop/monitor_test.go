@@ -99,6 +99,46 @@ func (s *RpoSuite) TestMonitor() {
 	testService.Stop()
 }
 
+func (s *RpoSuite) TestSetDraining() {
+	serviceName := primitives.HistoryService
+	testService := newTestCluster(s.T(), "rpm-drain-test", 3, "127.0.0.1", "", serviceName, "127.0.0.1")
+	s.NotNil(testService, "Failed to create test service")
+	defer testService.Stop()
+
+	time.Sleep(time.Second)
+
+	r, err := testService.rings[0].GetResolver(serviceName)
+	s.NoError(err)
+	s.Equal(3, r.MemberCount())
+	listenCh := make(chan *membership.ChangedEvent, 5)
+	s.NoError(r.AddListener("test-listener", listenCh))
+
+	s.NoError(testService.rings[1].SetDraining(true))
+
+	// The draining host is still reachable but other members leave it out of their ring.
+	timer := time.NewTimer(time.Minute)
+	select {
+	case e := <-listenCh:
+		timer.Stop()
+		s.Equal([]string{testService.hostAddrs[1]}, hostAddresses(e.HostsRemoved))
+	case <-timer.C:
+		s.Fail("Timed out waiting for the drain to be gossiped")
+	}
+	s.Equal([]string{testService.hostAddrs[1]}, hostAddresses(r.DrainingMembers()))
+	s.Equal(2, r.MemberCount())
+	s.NotContains(hostAddresses(r.Members()), testService.hostAddrs[1])
+
+	// The draining host doesn't own any key in its own view either.
+	drainingResolver, err := testService.rings[1].GetResolver(serviceName)
+	s.NoError(err)
+	s.Equal(2, drainingResolver.MemberCount())
+
+	s.NoError(testService.rings[1].SetDraining(false))
+	s.Eventually(func() bool { return r.MemberCount() == 3 && len(r.DrainingMembers()) == 0 }, time.Minute, 100*time.Millisecond)
+
+	s.NoError(r.RemoveListener("test-listener"))
+}
+
 func (s *RpoSuite) TestCompareMembers() {
 	s.verifyMemberDiff([]string{}, []string{"a"}, []string{"+a"})
 	s.verifyMemberDiff([]string{}, []string{"a", "b"}, []string{"+a", "+b"})
@@ -133,6 +173,14 @@ func (s *RpoSuite) verifyMemberDiff(curr []string, new []string, expectedDiff []
 	}
 }
 
+func hostAddresses(hosts []membership.HostInfo) []string {
+	var addresses []string
+	for _, host := range hosts {
+		addresses = append(addresses, host.GetAddress())
+	}
+	return addresses
+}
+
 func drainChannel[T any](ch <-chan T) {
 	for {
 		select {
@@ -58,6 +58,11 @@ const (
 	// the service can be accessed.
 	rolePort = "servicePort"
 
+	// drainingKey label is set by a member that is draining. Draining members are
+	// reachable but left out of the service ring.
+	drainingKey   = "draining"
+	drainingValue = "true"
+
 	minRefreshInternal     = time.Second * 4
 	defaultRefreshInterval = time.Second * 10
 	replicaPoints          = 100
@@ -72,7 +77,8 @@ type serviceResolver struct {
 	shutdownWG  sync.WaitGroup
 	logger      log.Logger
 
-	ringValue atomic.Value // this stores the current hashring
+	ringValue     atomic.Value // this stores the current hashring
+	drainingValue atomic.Value // this stores the addresses of the draining members
 
 	refreshLock     sync.Mutex
 	lastRefreshTime time.Time
@@ -101,6 +107,7 @@ func newServiceResolver(
 		listeners:   make(map[string]chan<- *membership.ChangedEvent),
 	}
 	resolver.ringValue.Store(newHashRing())
+	resolver.drainingValue.Store([]string(nil))
 	return resolver
 }
 
@@ -125,6 +132,7 @@ func (r *serviceResolver) Stop() {
 	defer r.listenerLock.Unlock()
 	r.rp.RemoveListener(r)
 	r.ringValue.Store(newHashRing())
+	r.drainingValue.Store([]string(nil))
 	r.listeners = make(map[string]chan<- *membership.ChangedEvent)
 	close(r.shutdownCh)
 
@@ -204,12 +212,19 @@ func (r *serviceResolver) Members() []membership.HostInfo {
 	return servers
 }
 
+func (r *serviceResolver) DrainingMembers() []membership.HostInfo {
+	labels := r.getLabelsMap()
+	return util.MapSlice(r.drainingValue.Load().([]string), func(address string) membership.HostInfo {
+		return newHostInfo(address, labels)
+	})
+}
+
 // HandleEvent handles updates from ringpop
 func (r *serviceResolver) HandleEvent(
 	event events.Event,
 ) {
-	// We only care about RingChangedEvent
-	if _, ok := event.(events.RingChangedEvent); ok {
+	switch event := event.(type) {
+	case events.RingChangedEvent:
 		r.logger.Debug("Received a ring changed event")
 		// Note that we receive events asynchronously, possibly out of order.
 		// We cannot rely on the content of the event, rather we load everything
@@ -217,6 +232,18 @@ func (r *serviceResolver) HandleEvent(
 		if err := r.refresh(); err != nil {
 			r.logger.Error("error refreshing ring when receiving a ring changed event", tag.Error(err))
 		}
+	case swim.MemberlistChangesAppliedEvent:
+		// Label changes don't change the ringpop ring, but a member of this service
+		// starting or stopping to drain changes our ring.
+		for _, change := range event.Changes {
+			if change.Labels[roleKey] != string(r.service) {
+				continue
+			}
+			if err := r.refresh(); err != nil {
+				r.logger.Error("error refreshing ring when receiving a member change event", tag.Error(err))
+			}
+			return
+		}
 	}
 }
 
@@ -253,10 +280,11 @@ func (r *serviceResolver) refreshWithBackoff() error {
 }
 
 func (r *serviceResolver) refreshNoLock() (*membership.ChangedEvent, error) {
-	addrs, err := r.getReachableMembers()
+	addrs, drainingAddrs, err := r.getReachableMembers()
 	if err != nil {
 		return nil, err
 	}
+	r.drainingValue.Store(drainingAddrs)
 
 	newMembersMap, changedEvent := r.compareMembers(addrs)
 	if changedEvent == nil {
@@ -277,13 +305,16 @@ func (r *serviceResolver) refreshNoLock() (*membership.ChangedEvent, error) {
 	return changedEvent, nil
 }
 
-func (r *serviceResolver) getReachableMembers() ([]string, error) {
+// getReachableMembers returns the addresses of the reachable members of this service,
+// split into the members of the ring and the draining members.
+func (r *serviceResolver) getReachableMembers() ([]string, []string, error) {
 	members, err := r.rp.GetReachableMemberObjects(swim.MemberWithLabelAndValue(roleKey, string(r.service)))
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
 	var hostPorts []string
+	var drainingHostPorts []string
 	for _, member := range members {
 		servicePort := r.port
 
@@ -294,7 +325,7 @@ func (r *serviceResolver) getReachableMembers() ([]string, error) {
 		if ok {
 			servicePort, err = strconv.Atoi(servicePortLabel)
 			if err != nil {
-				return nil, err
+				return nil, nil, err
 			}
 		} else {
 			r.logger.Debug("unable to find roleport label for ringpop member. using local service's port", tag.Service(r.service))
@@ -302,13 +333,17 @@ func (r *serviceResolver) getReachableMembers() ([]string, error) {
 
 		hostPort, err := replaceServicePort(member.Address, servicePort)
 		if err != nil {
-			return nil, err
+			return nil, nil, err
 		}
 
+		if draining, ok := member.Label(drainingKey); ok && draining == drainingValue {
+			drainingHostPorts = append(drainingHostPorts, hostPort)
+			continue
+		}
 		hostPorts = append(hostPorts, hostPort)
 	}
 
-	return hostPorts, nil
+	return hostPorts, drainingHostPorts, nil
 }
 
 func (r *serviceResolver) emitEvent(event *membership.ChangedEvent) {
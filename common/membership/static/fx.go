@@ -0,0 +1,113 @@
+// The MIT License
+//
+// Copyright (c) 2020 Temporal Technologies Inc.  All rights reserved.
+//
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package static
+
+import (
+	"net"
+
+	"go.uber.org/fx"
+
+	"go.temporal.io/server/common/config"
+	"go.temporal.io/server/common/convert"
+	"go.temporal.io/server/common/log"
+	"go.temporal.io/server/common/membership"
+	"go.temporal.io/server/common/primitives"
+	"go.temporal.io/server/common/rpc"
+)
+
+// Module provides membership objects for the static and dns membership providers.
+var Module = fx.Provide(
+	provideMembership,
+	provideHostInfoProvider,
+)
+
+type params struct {
+	fx.In
+
+	Config         *config.Membership
+	ServiceName    primitives.ServiceName
+	ServicePortMap config.ServicePortMap
+	RPCConfig      *config.RPC
+	Logger         log.Logger
+	// HostResolver overrides the resolver selected by Config.Provider.
+	HostResolver HostResolver `optional:"true"`
+	// HostProber overrides the TCP prober.
+	HostProber HostProber `optional:"true"`
+}
+
+func provideMembership(lc fx.Lifecycle, p params) (membership.Monitor, error) {
+	selfAddress, err := getSelfAddress(p)
+	if err != nil {
+		return nil, err
+	}
+
+	resolver := p.HostResolver
+	if resolver == nil {
+		if p.Config.Provider == config.MembershipProviderDNS {
+			resolver = NewDNSResolver(p.Config.Services, net.DefaultResolver)
+		} else {
+			resolver = NewConfigResolver(p.Config.Services)
+		}
+	}
+
+	services := make([]primitives.ServiceName, 0, len(p.Config.Services))
+	for service := range p.Config.Services {
+		services = append(services, primitives.ServiceName(service))
+	}
+
+	prober := p.HostProber
+	if prober == nil {
+		prober = NewTCPProber(p.Config.ProbeTimeout)
+	}
+
+	m := newMonitor(p.ServiceName, services, selfAddress, resolver, prober, p.Config.RefreshInterval, p.Logger)
+	lc.Append(fx.StopHook(m.Stop))
+	return m, nil
+}
+
+func provideHostInfoProvider(p params) (membership.HostInfoProvider, error) {
+	selfAddress, err := getSelfAddress(p)
+	if err != nil {
+		return nil, err
+	}
+	return membership.NewHostInfoProvider(membership.NewHostInfoFromAddress(selfAddress)), nil
+}
+
+// getSelfAddress returns the gRPC address of this host as it appears in the resolved members:
+// the broadcast address if configured, the listen address otherwise.
+func getSelfAddress(p params) (string, error) {
+	port, ok := p.ServicePortMap[p.ServiceName]
+	if !ok {
+		return "", membership.ErrUnknownService
+	}
+
+	host := p.Config.BroadcastAddress
+	if host == "" {
+		host = rpc.GetListenIP(p.RPCConfig, p.Logger).String()
+	} else if net.ParseIP(host) == nil {
+		return "", membership.ErrIncorrectAddressFormat
+	}
+	return net.JoinHostPort(host, convert.IntToString(port)), nil
+}
@@ -0,0 +1,254 @@
+// The MIT License
+//
+// Copyright (c) 2020 Temporal Technologies Inc.  All rights reserved.
+//
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+// Package static provides a membership monitor which builds the service rings from static host
+// lists or from periodically resolved DNS records instead of ringpop gossip.
+package static
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"go.temporal.io/server/common"
+	"go.temporal.io/server/common/future"
+	"go.temporal.io/server/common/headers"
+	"go.temporal.io/server/common/log"
+	"go.temporal.io/server/common/log/tag"
+	"go.temporal.io/server/common/membership"
+	"go.temporal.io/server/common/primitives"
+	"go.temporal.io/server/common/util"
+)
+
+const resolveTimeout = 10 * time.Second
+
+type monitor struct {
+	stateLock sync.Mutex
+	status    int32
+
+	lifecycleCtx    context.Context
+	lifecycleCancel context.CancelFunc
+
+	serviceName primitives.ServiceName
+	selfAddress string
+	resolver    HostResolver
+	prober      HostProber
+	rings       map[primitives.ServiceName]*serviceResolver
+	logger      log.Logger
+	evicted     atomic.Bool
+	initialized *future.FutureImpl[struct{}]
+}
+
+var _ membership.Monitor = (*monitor)(nil)
+
+// newMonitor returns a membership monitor whose rings are built from the addresses returned by
+// the given HostResolver. Unlike the ringpop monitor it doesn't gossip with other members: every
+// host resolves the members on its own, so all hosts converge on the same rings once the resolver
+// returns the same addresses to all of them. Resolved members which fail the probe of prober, if
+// set, are left out of the rings until they pass it again.
+func newMonitor(
+	serviceName primitives.ServiceName,
+	services []primitives.ServiceName,
+	selfAddress string,
+	resolver HostResolver,
+	prober HostProber,
+	refreshInterval time.Duration,
+	logger log.Logger,
+) *monitor {
+	lifecycleCtx, lifecycleCancel := context.WithCancel(context.Background())
+	lifecycleCtx = headers.SetCallerInfo(
+		lifecycleCtx,
+		headers.SystemBackgroundCallerInfo,
+	)
+	if refreshInterval <= 0 {
+		refreshInterval = defaultRefreshInterval
+	}
+
+	m := &monitor{
+		status: common.DaemonStatusInitialized,
+
+		lifecycleCtx:    lifecycleCtx,
+		lifecycleCancel: lifecycleCancel,
+
+		serviceName: serviceName,
+		selfAddress: selfAddress,
+		resolver:    resolver,
+		prober:      prober,
+		rings:       make(map[primitives.ServiceName]*serviceResolver, len(services)),
+		logger:      logger,
+		initialized: future.NewFuture[struct{}](),
+	}
+	for _, service := range services {
+		service := service
+		m.rings[service] = newServiceResolver(
+			service,
+			func() ([]string, error) { return m.resolve(service) },
+			refreshInterval,
+			logger,
+		)
+	}
+	return m
+}
+
+// Start resolves the members of all services and starts refreshing them periodically
+func (m *monitor) Start() {
+	m.stateLock.Lock()
+	defer m.stateLock.Unlock()
+	if m.status != common.DaemonStatusInitialized {
+		return
+	}
+	m.status = common.DaemonStatusStarted
+
+	for _, ring := range m.rings {
+		ring.Start()
+	}
+
+	m.initialized.Set(struct{}{}, nil)
+}
+
+// Stop the membership monitor and all associated rings
+func (m *monitor) Stop() {
+	m.stateLock.Lock()
+	defer m.stateLock.Unlock()
+	if m.status != common.DaemonStatusStarted {
+		return
+	}
+	m.status = common.DaemonStatusStopped
+
+	m.lifecycleCancel()
+
+	for _, ring := range m.rings {
+		ring.Stop()
+	}
+}
+
+// EvictSelf removes this host from the local view of its service ring. Other hosts stop routing
+// to this host only once their resolver no longer returns it (e.g. when the host is removed from
+// the DNS records because it stopped being ready).
+func (m *monitor) EvictSelf() error {
+	m.evicted.Store(true)
+	if ring, ok := m.rings[m.serviceName]; ok {
+		return ring.refresh()
+	}
+	return nil
+}
+
+// SetDraining can't announce draining, as there is no gossip between the hosts: other hosts would
+// keep routing to this host as long as their resolver returns it.
+func (m *monitor) SetDraining(draining bool) error {
+	if draining {
+		return membership.ErrDrainingNotSupported
+	}
+	return nil
+}
+
+func (m *monitor) SupportsDraining() bool {
+	return false
+}
+
+func (m *monitor) WaitUntilInitialized(ctx context.Context) error {
+	_, err := m.initialized.Get(ctx)
+	return err
+}
+
+func (m *monitor) GetResolver(service primitives.ServiceName) (membership.ServiceResolver, error) {
+	ring, found := m.rings[service]
+	if !found {
+		return nil, membership.ErrUnknownService
+	}
+	return ring, nil
+}
+
+func (m *monitor) GetReachableMembers() ([]string, error) {
+	var members []string
+	for _, ring := range m.rings {
+		members = append(members, util.MapSlice(ring.Members(), membership.HostInfo.GetAddress)...)
+	}
+	return dedupeAddresses(members), nil
+}
+
+func (m *monitor) resolve(service primitives.ServiceName) ([]string, error) {
+	ctx, cancel := context.WithTimeout(m.lifecycleCtx, resolveTimeout)
+	defer cancel()
+
+	addresses, err := m.resolver.Resolve(ctx, service)
+	if err != nil {
+		return nil, err
+	}
+	if service == m.serviceName && m.evicted.Load() {
+		addresses = util.FilterSlice(addresses, func(address string) bool { return address != m.selfAddress })
+	}
+	addresses = m.probe(ctx, service, addresses)
+	sort.Strings(addresses)
+	if len(addresses) == 0 {
+		m.logger.Warn("no members resolved for service", tag.Service(service))
+	}
+	return addresses, nil
+}
+
+// probe returns the addresses which pass the probe of the prober. This host is not probed, as it may
+// not listen yet while it starts. If no other member passes the probe, it's more likely that this
+// host can't reach the others than that they are all down, so all addresses are kept.
+func (m *monitor) probe(ctx context.Context, service primitives.ServiceName, addresses []string) []string {
+	if m.prober == nil {
+		return addresses
+	}
+
+	alive := make([]bool, len(addresses))
+	var wg sync.WaitGroup
+	for i, address := range addresses {
+		if address == m.selfAddress {
+			alive[i] = true
+			continue
+		}
+		wg.Add(1)
+		go func(i int, address string) {
+			defer wg.Done()
+			alive[i] = m.prober.Probe(ctx, address)
+		}(i, address)
+	}
+	wg.Wait()
+
+	var result, unreachable []string
+	for i, address := range addresses {
+		if alive[i] {
+			result = append(result, address)
+		} else {
+			unreachable = append(unreachable, address)
+		}
+	}
+	if len(unreachable) == 0 {
+		return addresses
+	}
+	if len(result) == 0 || len(result) == 1 && result[0] == m.selfAddress {
+		m.logger.Warn("no other member of the service passed the probe, keeping all members",
+			tag.Service(service), tag.Addresses(unreachable))
+		return addresses
+	}
+	m.logger.Warn("leaving members which failed the probe out of the ring",
+		tag.Service(service), tag.Addresses(unreachable))
+	return result
+}
@@ -0,0 +1,251 @@
+// The MIT License
+//
+// Copyright (c) 2020 Temporal Technologies Inc.  All rights reserved.
+//
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package static
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/dgryski/go-farm"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+	"github.com/temporalio/ringpop-go/hashring"
+
+	"go.temporal.io/server/common/log"
+	"go.temporal.io/server/common/membership"
+	"go.temporal.io/server/common/primitives"
+)
+
+type (
+	monitorSuite struct {
+		*require.Assertions
+		suite.Suite
+
+		resolver *fakeHostResolver
+		prober   *fakeHostProber
+		monitor  *monitor
+	}
+
+	fakeHostProber struct {
+		sync.Mutex
+		dead map[string]bool
+	}
+
+	fakeHostResolver struct {
+		sync.Mutex
+		hosts map[primitives.ServiceName][]string
+		err   error
+	}
+)
+
+func TestMonitorSuite(t *testing.T) {
+	suite.Run(t, new(monitorSuite))
+}
+
+func (s *monitorSuite) SetupTest() {
+	s.Assertions = require.New(s.T())
+
+	s.resolver = &fakeHostResolver{
+		hosts: map[primitives.ServiceName][]string{
+			primitives.HistoryService:  {"10.0.0.1:7234", "10.0.0.2:7234", "10.0.0.3:7234"},
+			primitives.MatchingService: {"10.0.1.1:7235"},
+		},
+	}
+	s.prober = &fakeHostProber{dead: make(map[string]bool)}
+	s.monitor = newMonitor(
+		primitives.HistoryService,
+		[]primitives.ServiceName{primitives.HistoryService, primitives.MatchingService},
+		"10.0.0.1:7234",
+		s.resolver,
+		s.prober,
+		time.Hour,
+		log.NewTestLogger(),
+	)
+	s.monitor.Start()
+}
+
+func (s *monitorSuite) TearDownTest() {
+	s.monitor.Stop()
+}
+
+func (s *monitorSuite) TestLookup() {
+	s.NoError(s.monitor.WaitUntilInitialized(context.Background()))
+
+	r, err := s.monitor.GetResolver(primitives.HistoryService)
+	s.NoError(err)
+	s.Equal(3, r.MemberCount())
+
+	// The ring is the same as the one ringpop builds for the same hosts.
+	ring := hashring.New(farm.Fingerprint32, replicaPoints)
+	for _, host := range s.resolver.hosts[primitives.HistoryService] {
+		ring.AddMembers(hostInfo(host))
+	}
+	for _, key := range []string{"1", "2", "3", "4", "5", "workflow-id", "task-queue"} {
+		expected, ok := ring.Lookup(key)
+		s.True(ok)
+		host, err := r.Lookup(key)
+		s.NoError(err)
+		s.Equal(expected, host.GetAddress())
+		s.Equal(ring.LookupN(key, 2), hostAddresses(r.LookupN(key, 2)))
+	}
+
+	_, err = s.monitor.GetResolver(primitives.WorkerService)
+	s.ErrorIs(err, membership.ErrUnknownService)
+
+	members, err := s.monitor.GetReachableMembers()
+	s.NoError(err)
+	s.Equal([]string{"10.0.0.1:7234", "10.0.0.2:7234", "10.0.0.3:7234", "10.0.1.1:7235"}, members)
+}
+
+func (s *monitorSuite) TestListener() {
+	r, err := s.monitor.GetResolver(primitives.HistoryService)
+	s.NoError(err)
+	listenCh := make(chan *membership.ChangedEvent, 1)
+	s.NoError(r.AddListener("test-listener", listenCh))
+	s.ErrorIs(r.AddListener("test-listener", listenCh), membership.ErrListenerAlreadyExist)
+
+	s.resolver.set(primitives.HistoryService, []string{"10.0.0.1:7234", "10.0.0.3:7234", "10.0.0.4:7234"})
+	s.NoError(r.(*serviceResolver).refresh())
+
+	event := <-listenCh
+	s.Equal([]string{"10.0.0.4:7234"}, hostAddresses(event.HostsAdded))
+	s.Equal([]string{"10.0.0.2:7234"}, hostAddresses(event.HostsRemoved))
+	s.Equal([]string{"10.0.0.1:7234", "10.0.0.3:7234", "10.0.0.4:7234"}, hostAddresses(r.Members()))
+
+	// No event when the members didn't change.
+	s.NoError(r.(*serviceResolver).refresh())
+	s.Empty(listenCh)
+
+	// A failed resolution keeps the current ring.
+	s.resolver.setErr(context.DeadlineExceeded)
+	s.ErrorIs(r.(*serviceResolver).refresh(), context.DeadlineExceeded)
+	s.Equal(3, r.MemberCount())
+
+	s.NoError(r.RemoveListener("test-listener"))
+}
+
+func (s *monitorSuite) TestInsufficientHosts() {
+	s.resolver.set(primitives.MatchingService, nil)
+	r, err := s.monitor.GetResolver(primitives.MatchingService)
+	s.NoError(err)
+	s.NoError(r.(*serviceResolver).refresh())
+
+	_, err = r.Lookup("key")
+	s.ErrorIs(err, membership.ErrInsufficientHosts)
+	s.Empty(r.LookupN("key", 1))
+}
+
+func (s *monitorSuite) TestEvictSelf() {
+	s.NoError(s.monitor.EvictSelf())
+
+	r, err := s.monitor.GetResolver(primitives.HistoryService)
+	s.NoError(err)
+	s.Equal([]string{"10.0.0.2:7234", "10.0.0.3:7234"}, hostAddresses(r.Members()))
+
+	// Other services are not affected.
+	r, err = s.monitor.GetResolver(primitives.MatchingService)
+	s.NoError(err)
+	s.Equal(1, r.MemberCount())
+}
+
+func (s *monitorSuite) TestSetDraining() {
+	s.False(s.monitor.SupportsDraining())
+	s.ErrorIs(s.monitor.SetDraining(true), membership.ErrDrainingNotSupported)
+	s.NoError(s.monitor.SetDraining(false))
+
+	r, err := s.monitor.GetResolver(primitives.HistoryService)
+	s.NoError(err)
+	s.Equal(3, r.MemberCount())
+	s.Empty(r.DrainingMembers())
+}
+
+func (s *monitorSuite) TestProbe() {
+	r, err := s.monitor.GetResolver(primitives.HistoryService)
+	s.NoError(err)
+	listenCh := make(chan *membership.ChangedEvent, 1)
+	s.NoError(r.AddListener("test-listener", listenCh))
+
+	// a member which fails the probe leaves the ring and comes back once it passes it again
+	s.prober.setDead("10.0.0.2:7234", true)
+	s.NoError(r.(*serviceResolver).refresh())
+	event := <-listenCh
+	s.Equal([]string{"10.0.0.2:7234"}, hostAddresses(event.HostsRemoved))
+	s.Equal([]string{"10.0.0.1:7234", "10.0.0.3:7234"}, hostAddresses(r.Members()))
+
+	s.prober.setDead("10.0.0.2:7234", false)
+	s.NoError(r.(*serviceResolver).refresh())
+	event = <-listenCh
+	s.Equal([]string{"10.0.0.2:7234"}, hostAddresses(event.HostsAdded))
+
+	// if no other member passes the probe, this host is more likely cut off, so the ring is kept
+	s.prober.setDead("10.0.0.2:7234", true)
+	s.prober.setDead("10.0.0.3:7234", true)
+	s.NoError(r.(*serviceResolver).refresh())
+	s.Empty(listenCh)
+	s.Equal(3, r.MemberCount())
+}
+
+func (p *fakeHostProber) Probe(_ context.Context, address string) bool {
+	p.Lock()
+	defer p.Unlock()
+	return !p.dead[address]
+}
+
+func (p *fakeHostProber) setDead(address string, dead bool) {
+	p.Lock()
+	defer p.Unlock()
+	p.dead[address] = dead
+}
+
+func (r *fakeHostResolver) Resolve(_ context.Context, service primitives.ServiceName) ([]string, error) {
+	r.Lock()
+	defer r.Unlock()
+	if r.err != nil {
+		return nil, r.err
+	}
+	return append([]string(nil), r.hosts[service]...), nil
+}
+
+func (r *fakeHostResolver) set(service primitives.ServiceName, hosts []string) {
+	r.Lock()
+	defer r.Unlock()
+	r.hosts[service] = hosts
+}
+
+func (r *fakeHostResolver) setErr(err error) {
+	r.Lock()
+	defer r.Unlock()
+	r.err = err
+}
+
+func hostAddresses(hosts []membership.HostInfo) []string {
+	var addresses []string
+	for _, host := range hosts {
+		addresses = append(addresses, host.GetAddress())
+	}
+	return addresses
+}
@@ -0,0 +1,65 @@
+// The MIT License
+//
+// Copyright (c) 2020 Temporal Technologies Inc.  All rights reserved.
+//
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package static
+
+import (
+	"context"
+	"net"
+	"time"
+)
+
+const defaultProbeTimeout = 2 * time.Second
+
+type (
+	// HostProber checks whether a member resolved by a HostResolver is alive.
+	HostProber interface {
+		Probe(ctx context.Context, address string) bool
+	}
+
+	tcpProber struct {
+		timeout time.Duration
+	}
+)
+
+var _ HostProber = (*tcpProber)(nil)
+
+// NewTCPProber returns a HostProber which considers a member alive if it accepts a TCP connection on
+// its gRPC address within timeout, or a default timeout if it is 0.
+func NewTCPProber(timeout time.Duration) HostProber {
+	if timeout <= 0 {
+		timeout = defaultProbeTimeout
+	}
+	return &tcpProber{timeout: timeout}
+}
+
+func (p *tcpProber) Probe(ctx context.Context, address string) bool {
+	dialer := net.Dialer{Timeout: p.timeout}
+	conn, err := dialer.DialContext(ctx, "tcp", address)
+	if err != nil {
+		return false
+	}
+	_ = conn.Close()
+	return true
+}
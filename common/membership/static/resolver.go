@@ -0,0 +1,145 @@
+// The MIT License
+//
+// Copyright (c) 2020 Temporal Technologies Inc.  All rights reserved.
+//
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package static
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"sort"
+
+	"go.temporal.io/server/common/config"
+	"go.temporal.io/server/common/convert"
+	"go.temporal.io/server/common/primitives"
+)
+
+type (
+	// HostResolver resolves the current gRPC addresses (host:port) of the members of a service.
+	HostResolver interface {
+		Resolve(ctx context.Context, service primitives.ServiceName) ([]string, error)
+	}
+
+	// DNSLookup is the subset of *net.Resolver used by the dns resolver.
+	DNSLookup interface {
+		LookupHost(ctx context.Context, host string) ([]string, error)
+		LookupSRV(ctx context.Context, service, proto, name string) (string, []*net.SRV, error)
+	}
+
+	configResolver struct {
+		services map[string]config.MembershipService
+	}
+
+	dnsResolver struct {
+		services map[string]config.MembershipService
+		lookup   DNSLookup
+	}
+)
+
+var _ HostResolver = (*configResolver)(nil)
+var _ HostResolver = (*dnsResolver)(nil)
+
+// NewConfigResolver returns a HostResolver which returns the static host lists from config.
+func NewConfigResolver(services map[string]config.MembershipService) HostResolver {
+	return &configResolver{services: services}
+}
+
+// NewDNSResolver returns a HostResolver which resolves the DNS names from config using the given lookup.
+func NewDNSResolver(services map[string]config.MembershipService, lookup DNSLookup) HostResolver {
+	return &dnsResolver{
+		services: services,
+		lookup:   lookup,
+	}
+}
+
+func (r *configResolver) Resolve(_ context.Context, service primitives.ServiceName) ([]string, error) {
+	members, ok := r.services[string(service)]
+	if !ok {
+		return nil, fmt.Errorf("no membership hosts configured for service %q", service)
+	}
+	return dedupeAddresses(members.Hosts), nil
+}
+
+func (r *dnsResolver) Resolve(ctx context.Context, service primitives.ServiceName) ([]string, error) {
+	members, ok := r.services[string(service)]
+	if !ok {
+		return nil, fmt.Errorf("no membership dns names configured for service %q", service)
+	}
+
+	var addresses []string
+	for _, name := range members.DNSNames {
+		resolved, err := r.resolveName(ctx, name)
+		if err != nil {
+			return nil, err
+		}
+		addresses = append(addresses, resolved...)
+	}
+	return dedupeAddresses(addresses), nil
+}
+
+// resolveName resolves host:port names to A/AAAA records and names without a port to SRV records.
+func (r *dnsResolver) resolveName(ctx context.Context, name string) ([]string, error) {
+	if host, port, err := net.SplitHostPort(name); err == nil {
+		ips, err := r.lookup.LookupHost(ctx, host)
+		if err != nil {
+			return nil, fmt.Errorf("unable to resolve %q: %w", name, err)
+		}
+		addresses := make([]string, 0, len(ips))
+		for _, ip := range ips {
+			addresses = append(addresses, net.JoinHostPort(ip, port))
+		}
+		return addresses, nil
+	}
+
+	_, records, err := r.lookup.LookupSRV(ctx, "", "", name)
+	if err != nil {
+		return nil, fmt.Errorf("unable to resolve SRV record %q: %w", name, err)
+	}
+	var addresses []string
+	for _, record := range records {
+		ips, err := r.lookup.LookupHost(ctx, record.Target)
+		if err != nil {
+			return nil, fmt.Errorf("unable to resolve SRV target %q: %w", record.Target, err)
+		}
+		for _, ip := range ips {
+			addresses = append(addresses, net.JoinHostPort(ip, convert.Uint16ToString(record.Port)))
+		}
+	}
+	return addresses, nil
+}
+
+// dedupeAddresses returns the sorted unique addresses.
+func dedupeAddresses(addresses []string) []string {
+	set := make(map[string]struct{}, len(addresses))
+	result := make([]string, 0, len(addresses))
+	for _, address := range addresses {
+		if _, ok := set[address]; ok {
+			continue
+		}
+		set[address] = struct{}{}
+		result = append(result, address)
+	}
+	sort.Strings(result)
+	return result
+}
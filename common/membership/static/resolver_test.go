@@ -0,0 +1,106 @@
+// The MIT License
+//
+// Copyright (c) 2020 Temporal Technologies Inc.  All rights reserved.
+//
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package static
+
+import (
+	"context"
+	"errors"
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"go.temporal.io/server/common/config"
+	"go.temporal.io/server/common/primitives"
+)
+
+type fakeDNSLookup struct {
+	hosts map[string][]string
+	srv   map[string][]*net.SRV
+}
+
+func (l *fakeDNSLookup) LookupHost(_ context.Context, host string) ([]string, error) {
+	addrs, ok := l.hosts[host]
+	if !ok {
+		return nil, errors.New("no such host")
+	}
+	return addrs, nil
+}
+
+func (l *fakeDNSLookup) LookupSRV(_ context.Context, _, _, name string) (string, []*net.SRV, error) {
+	records, ok := l.srv[name]
+	if !ok {
+		return "", nil, errors.New("no such host")
+	}
+	return name, records, nil
+}
+
+func TestConfigResolver(t *testing.T) {
+	r := NewConfigResolver(map[string]config.MembershipService{
+		"history": {Hosts: []string{"10.0.0.2:7234", "10.0.0.1:7234", "10.0.0.2:7234"}},
+	})
+
+	hosts, err := r.Resolve(context.Background(), primitives.HistoryService)
+	require.NoError(t, err)
+	require.Equal(t, []string{"10.0.0.1:7234", "10.0.0.2:7234"}, hosts)
+
+	_, err = r.Resolve(context.Background(), primitives.MatchingService)
+	require.Error(t, err)
+}
+
+func TestDNSResolver(t *testing.T) {
+	lookup := &fakeDNSLookup{
+		hosts: map[string][]string{
+			"history.temporal":   {"10.0.0.1", "10.0.0.2"},
+			"matching-0.cluster": {"10.0.1.1"},
+			"matching-1.cluster": {"10.0.1.2", "fd00::1"},
+		},
+		srv: map[string][]*net.SRV{
+			"_grpc._tcp.matching.cluster": {
+				{Target: "matching-0.cluster", Port: 7235},
+				{Target: "matching-1.cluster", Port: 7235},
+			},
+		},
+	}
+	r := NewDNSResolver(map[string]config.MembershipService{
+		"history":  {DNSNames: []string{"history.temporal:7234"}},
+		"matching": {DNSNames: []string{"_grpc._tcp.matching.cluster"}},
+		"worker":   {DNSNames: []string{"worker.temporal:7239"}},
+	}, lookup)
+
+	hosts, err := r.Resolve(context.Background(), primitives.HistoryService)
+	require.NoError(t, err)
+	require.Equal(t, []string{"10.0.0.1:7234", "10.0.0.2:7234"}, hosts)
+
+	hosts, err = r.Resolve(context.Background(), primitives.MatchingService)
+	require.NoError(t, err)
+	require.Equal(t, []string{"10.0.1.1:7235", "10.0.1.2:7235", "[fd00::1]:7235"}, hosts)
+
+	_, err = r.Resolve(context.Background(), primitives.WorkerService)
+	require.Error(t, err)
+
+	_, err = r.Resolve(context.Background(), primitives.FrontendService)
+	require.Error(t, err)
+}
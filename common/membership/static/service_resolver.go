@@ -0,0 +1,324 @@
+// The MIT License
+//
+// Copyright (c) 2020 Temporal Technologies Inc.  All rights reserved.
+//
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package static
+
+import (
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/dgryski/go-farm"
+	"github.com/temporalio/ringpop-go/hashring"
+
+	"go.temporal.io/server/common"
+	"go.temporal.io/server/common/log"
+	"go.temporal.io/server/common/log/tag"
+	"go.temporal.io/server/common/membership"
+	"go.temporal.io/server/common/primitives"
+	"go.temporal.io/server/common/util"
+)
+
+const (
+	minRefreshInterval     = time.Second * 4
+	defaultRefreshInterval = time.Second * 10
+	// replicaPoints and the hash function match the ringpop service resolver so that
+	// the same set of hosts produces the same key placement with either provider.
+	replicaPoints = 100
+)
+
+type serviceResolver struct {
+	service         primitives.ServiceName
+	resolve         func() ([]string, error)
+	refreshInterval time.Duration
+	refreshChan     chan struct{}
+	shutdownCh      chan struct{}
+	shutdownWG      sync.WaitGroup
+	logger          log.Logger
+
+	ringValue atomic.Value // this stores the current hashring
+
+	refreshLock     sync.Mutex
+	lastRefreshTime time.Time
+	membersMap      map[string]struct{} // for de-duping change notifications
+
+	listenerLock sync.RWMutex
+	listeners    map[string]chan<- *membership.ChangedEvent
+}
+
+// hostInfo implements both membership.HostInfo and the ringpop hashring member interface.
+type hostInfo string
+
+var _ membership.ServiceResolver = (*serviceResolver)(nil)
+
+func newServiceResolver(
+	service primitives.ServiceName,
+	resolve func() ([]string, error),
+	refreshInterval time.Duration,
+	logger log.Logger,
+) *serviceResolver {
+	resolver := &serviceResolver{
+		service:         service,
+		resolve:         resolve,
+		refreshInterval: refreshInterval,
+		refreshChan:     make(chan struct{}),
+		shutdownCh:      make(chan struct{}),
+		logger:          log.With(logger, tag.ComponentServiceResolver, tag.Service(service)),
+		membersMap:      make(map[string]struct{}),
+		listeners:       make(map[string]chan<- *membership.ChangedEvent),
+	}
+	resolver.ringValue.Store(newHashRing())
+	return resolver
+}
+
+func newHashRing() *hashring.HashRing {
+	return hashring.New(farm.Fingerprint32, replicaPoints)
+}
+
+// Start resolves the initial members and starts the periodic refresh
+func (r *serviceResolver) Start() {
+	if err := r.refresh(); err != nil {
+		// Members might not be resolvable yet (e.g. DNS records are published once pods are ready),
+		// the refresh worker keeps retrying.
+		r.logger.Error("unable to resolve initial members", tag.Error(err))
+	}
+
+	r.shutdownWG.Add(1)
+	go r.refreshRingWorker()
+}
+
+// Stop stops the resolver
+func (r *serviceResolver) Stop() {
+	r.listenerLock.Lock()
+	defer r.listenerLock.Unlock()
+	r.ringValue.Store(newHashRing())
+	r.listeners = make(map[string]chan<- *membership.ChangedEvent)
+	close(r.shutdownCh)
+
+	if success := common.AwaitWaitGroup(&r.shutdownWG, time.Minute); !success {
+		r.logger.Warn("service resolver timed out on shutdown.")
+	}
+}
+
+func (r *serviceResolver) RequestRefresh() {
+	select {
+	case r.refreshChan <- struct{}{}:
+	default:
+	}
+}
+
+// Lookup finds the host in the ring responsible for serving the given key
+func (r *serviceResolver) Lookup(key string) (membership.HostInfo, error) {
+	addr, found := r.ring().Lookup(key)
+	if !found {
+		r.RequestRefresh()
+		return nil, membership.ErrInsufficientHosts
+	}
+	return hostInfo(addr), nil
+}
+
+func (r *serviceResolver) LookupN(key string, n int) []membership.HostInfo {
+	if n <= 0 {
+		return nil
+	}
+	addresses := r.ring().LookupN(key, n)
+	if len(addresses) == 0 {
+		r.RequestRefresh()
+		return nil
+	}
+	return util.MapSlice(addresses, func(address string) membership.HostInfo { return hostInfo(address) })
+}
+
+func (r *serviceResolver) AddListener(
+	name string,
+	notifyChannel chan<- *membership.ChangedEvent,
+) error {
+	r.listenerLock.Lock()
+	defer r.listenerLock.Unlock()
+	_, ok := r.listeners[name]
+	if ok {
+		return membership.ErrListenerAlreadyExist
+	}
+	r.listeners[name] = notifyChannel
+	return nil
+}
+
+func (r *serviceResolver) RemoveListener(
+	name string,
+) error {
+	r.listenerLock.Lock()
+	defer r.listenerLock.Unlock()
+	delete(r.listeners, name)
+	return nil
+}
+
+func (r *serviceResolver) MemberCount() int {
+	return r.ring().ServerCount()
+}
+
+func (r *serviceResolver) Members() []membership.HostInfo {
+	servers := r.ring().Servers()
+	sort.Strings(servers)
+	return util.MapSlice(servers, func(address string) membership.HostInfo { return hostInfo(address) })
+}
+
+// DrainingMembers returns no hosts, as hosts can't announce draining without gossip.
+func (r *serviceResolver) DrainingMembers() []membership.HostInfo {
+	return nil
+}
+
+func (r *serviceResolver) refresh() error {
+	var event *membership.ChangedEvent
+	var err error
+	defer func() {
+		if event != nil {
+			r.emitEvent(event)
+		}
+	}()
+	r.refreshLock.Lock()
+	defer r.refreshLock.Unlock()
+	event, err = r.refreshNoLock()
+	return err
+}
+
+func (r *serviceResolver) refreshWithBackoff() error {
+	var event *membership.ChangedEvent
+	var err error
+	defer func() {
+		if event != nil {
+			r.emitEvent(event)
+		}
+	}()
+	r.refreshLock.Lock()
+	defer r.refreshLock.Unlock()
+	if r.lastRefreshTime.After(time.Now().UTC().Add(-minRefreshInterval)) {
+		// refresh too frequently
+		return nil
+	}
+	event, err = r.refreshNoLock()
+	return err
+}
+
+func (r *serviceResolver) refreshNoLock() (*membership.ChangedEvent, error) {
+	addrs, err := r.resolve()
+	if err != nil {
+		return nil, err
+	}
+	r.lastRefreshTime = time.Now().UTC()
+
+	newMembersMap, changedEvent := r.compareMembers(addrs)
+	if changedEvent == nil {
+		return nil, nil
+	}
+
+	ring := newHashRing()
+	for _, addr := range addrs {
+		ring.AddMembers(hostInfo(addr))
+	}
+
+	r.membersMap = newMembersMap
+	r.ringValue.Store(ring)
+	r.logger.Info("Current resolved members", tag.Addresses(addrs))
+
+	return changedEvent, nil
+}
+
+func (r *serviceResolver) emitEvent(event *membership.ChangedEvent) {
+	// Notify listeners
+	r.listenerLock.RLock()
+	defer r.listenerLock.RUnlock()
+
+	for name, ch := range r.listeners {
+		select {
+		case ch <- event:
+		default:
+			r.logger.Error("Failed to send listener notification, channel full", tag.ListenerName(name))
+		}
+	}
+}
+
+func (r *serviceResolver) refreshRingWorker() {
+	defer r.shutdownWG.Done()
+
+	refreshTicker := time.NewTicker(r.refreshInterval)
+	defer refreshTicker.Stop()
+
+	for {
+		select {
+		case <-r.shutdownCh:
+			return
+		case <-r.refreshChan:
+			if err := r.refreshWithBackoff(); err != nil {
+				r.logger.Error("error refreshing ring by request", tag.Error(err))
+			}
+		case <-refreshTicker.C:
+			if err := r.refresh(); err != nil {
+				r.logger.Error("error periodically refreshing ring", tag.Error(err))
+			}
+		}
+	}
+}
+
+func (r *serviceResolver) ring() *hashring.HashRing {
+	return r.ringValue.Load().(*hashring.HashRing)
+}
+
+func (r *serviceResolver) compareMembers(addrs []string) (map[string]struct{}, *membership.ChangedEvent) {
+	event := &membership.ChangedEvent{}
+	changed := false
+	newMembersMap := make(map[string]struct{}, len(addrs))
+	for _, addr := range addrs {
+		newMembersMap[addr] = struct{}{}
+		if _, ok := r.membersMap[addr]; !ok {
+			event.HostsAdded = append(event.HostsAdded, hostInfo(addr))
+			changed = true
+		}
+	}
+	for addr := range r.membersMap {
+		if _, ok := newMembersMap[addr]; !ok {
+			event.HostsRemoved = append(event.HostsRemoved, hostInfo(addr))
+			changed = true
+		}
+	}
+	if changed {
+		return newMembersMap, event
+	}
+	return newMembersMap, nil
+}
+
+// GetAddress returns the ip:port address
+func (h hostInfo) GetAddress() string {
+	return string(h)
+}
+
+// Identity returns the address, which keeps the ring placement identical to ringpop
+func (h hostInfo) Identity() string {
+	return string(h)
+}
+
+// Label implements the ringpop hashring member interface, static members have no labels
+func (h hostInfo) Label(string) (string, bool) {
+	return "", false
+}
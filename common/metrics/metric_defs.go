@@ -58,6 +58,7 @@ const (
 
 	InvalidHistoryURITagValue    = "invalid_history_uri"
 	InvalidVisibilityURITagValue = "invalid_visibility_uri"
+	InvalidExportURITagValue     = "invalid_export_uri"
 )
 
 // Common service base metrics
@@ -106,6 +107,10 @@ const (
 	TaskQueueScavengerScope = "TaskQueueScavenger"
 	// ExecutionsScavengerScope is scope used by all metrics emitted by worker.executions.Scavenger module
 	ExecutionsScavengerScope = "ExecutionsScavenger"
+	// ArchivalVerifierScope is scope used by all metrics emitted by worker.scanner.archival verifier
+	ArchivalVerifierScope = "ArchivalVerifier"
+	// VisibilityScavengerScope is scope used by all metrics emitted by worker.scanner.visibility scavenger
+	VisibilityScavengerScope = "VisibilityScavenger"
 )
 
 const (
@@ -317,6 +322,8 @@ const (
 	ServerTlsScope = "ServerTls"
 	// AuthorizationScope is the scope used by all metric emitted by authorization code
 	AuthorizationScope = "Authorization"
+	// AuditScope is the scope used by all metric emitted by audit log code
+	AuditScope = "Audit"
 	// NamespaceCacheScope tracks namespace cache callbacks
 	NamespaceCacheScope = "NamespaceCache"
 )
@@ -604,6 +611,15 @@ var (
 	TlsCertsExpired                          = NewGaugeDef("certificates_expired")
 	TlsCertsExpiring                         = NewGaugeDef("certificates_expiring")
 	ServiceAuthorizationLatency              = NewTimerDef("service_authorization_latency")
+	JWTKeyMissCount                          = NewCounterDef("jwt_key_miss")
+	JWTKeyMissRefreshThrottled               = NewCounterDef("jwt_key_miss_refresh_throttled")
+	JWTKeySourceRefreshRequests              = NewCounterDef("jwt_key_source_refresh_requests")
+	JWTKeySourceRefreshFailures              = NewCounterDef("jwt_key_source_refresh_errors")
+	JWTKeySourceRefreshLatency               = NewTimerDef("jwt_key_source_refresh_latency")
+	JWTKeySourceKeys                         = NewGaugeDef("jwt_key_source_keys")
+	AuditRecordCount                         = NewCounterDef("audit_records")
+	AuditSinkErrorCount                      = NewCounterDef("audit_sink_errors")
+	AuditRecordDroppedCount                  = NewCounterDef("audit_records_dropped")
 	EventBlobSize                            = NewBytesHistogramDef("event_blob_size")
 	LockRequests                             = NewCounterDef("lock_requests")
 	LockLatency                              = NewTimerDef("lock_latency")
@@ -670,6 +686,8 @@ var (
 	ArchivalTaskInvalidURI              = NewCounterDef("archival_task_invalid_uri")
 	ArchiverArchiveLatency              = NewTimerDef("archiver_archive_latency")
 	ArchiverArchiveTargetLatency        = NewTimerDef("archiver_archive_target_latency")
+	ExportedWorkflowExecutions          = NewCounterDef("exported_workflow_executions")
+	ExportFailedWorkflowExecutions      = NewCounterDef("export_failed_workflow_executions")
 	ShardContextClosedCounter           = NewCounterDef("shard_closed_count")
 	ShardContextCreatedCounter          = NewCounterDef("sharditem_created_count")
 	ShardContextRemovedCounter          = NewCounterDef("sharditem_removed_count")
@@ -740,6 +758,7 @@ var (
 	)
 	TaskNotActiveCounter         = NewCounterDef("task_errors_not_active_counter")
 	TaskNamespaceHandoverCounter = NewCounterDef("task_errors_namespace_handover")
+	TaskWorkflowMovingCounter    = NewCounterDef("task_errors_workflow_moving")
 	TaskThrottledCounter         = NewCounterDef(
 		"task_errors_throttled",
 		WithDescription("The number of history task processing errors caused by resource exhausted errors, excluding workflow busy case."),
@@ -913,6 +932,8 @@ var (
 	InorderBufferedEventsCounter                   = NewCounterDef("inordered_buffered_events")
 	ShardLingerSuccess                             = NewTimerDef("shard_linger_success")
 	ShardLingerTimeouts                            = NewCounterDef("shard_linger_timeouts")
+	HostDrainLatency                               = NewTimerDef("host_drain_latency")
+	ShardDrainFlushFailures                        = NewCounterDef("shard_drain_flush_failures")
 	DynamicRateLimiterMultiplier                   = NewGaugeDef("dynamic_rate_limit_multiplier")
 	DLQWrites                                      = NewCounterDef(
 		"dlq_writes",
@@ -959,52 +980,70 @@ var (
 	UnknownBuildPollsCounter                  = NewCounterDef("unknown_build_polls")
 	UnknownBuildTasksCounter                  = NewCounterDef("unknown_build_tasks")
 	TaskDispatchLatencyPerTaskQueue           = NewTimerDef("task_dispatch_latency")
+	FairnessKeysPerTaskQueueGauge             = NewGaugeDef("fairness_keys_per_tl")
+	FairnessBufferedTasksPerTaskQueueGauge    = NewGaugeDef("fairness_buffered_tasks_per_tl")
+	FairnessDeferredTasksPerTaskQueueGauge    = NewGaugeDef("fairness_deferred_tasks_per_tl")
+	ApproximateBacklogCount                   = NewGaugeDef("approximate_backlog_count")
+	ApproximateBacklogAgeSeconds              = NewGaugeDef("approximate_backlog_age_seconds")
+	TypeThrottlePerTaskQueueCounter           = NewCounterDef("type_throttle_count")
+	ThrottledBacklogTasksPerTaskQueueGauge    = NewGaugeDef("throttled_backlog_tasks_per_tl")
 
 	// Worker
-	ExecutorTasksDoneCount                          = NewCounterDef("executor_done")
-	ExecutorTasksErrCount                           = NewCounterDef("executor_err")
-	ExecutorTasksDeferredCount                      = NewCounterDef("executor_deferred")
-	ExecutorTasksDroppedCount                       = NewCounterDef("executor_dropped")
-	StartedCount                                    = NewCounterDef("started")
-	StoppedCount                                    = NewCounterDef("stopped")
-	TaskProcessedCount                              = NewGaugeDef("task_processed")
-	TaskDeletedCount                                = NewGaugeDef("task_deleted")
-	TaskQueueProcessedCount                         = NewGaugeDef("taskqueue_processed")
-	TaskQueueDeletedCount                           = NewGaugeDef("taskqueue_deleted")
-	TaskQueueOutstandingCount                       = NewGaugeDef("taskqueue_outstanding")
-	HistoryArchiverArchiveNonRetryableErrorCount    = NewCounterDef("history_archiver_archive_non_retryable_error")
-	HistoryArchiverArchiveTransientErrorCount       = NewCounterDef("history_archiver_archive_transient_error")
-	HistoryArchiverArchiveSuccessCount              = NewCounterDef("history_archiver_archive_success")
-	HistoryArchiverTotalUploadSize                  = NewBytesHistogramDef("history_archiver_total_upload_size")
-	HistoryArchiverHistorySize                      = NewBytesHistogramDef("history_archiver_history_size")
-	HistoryArchiverDuplicateArchivalsCount          = NewCounterDef("history_archiver_duplicate_archivals")
-	HistoryArchiverBlobExistsCount                  = NewCounterDef("history_archiver_blob_exists")
-	HistoryArchiverBlobSize                         = NewBytesHistogramDef("history_archiver_blob_size")
-	HistoryWorkflowExecutionCacheLatency            = NewTimerDef("history_workflow_execution_cache_latency")
-	VisibilityArchiverArchiveNonRetryableErrorCount = NewCounterDef("visibility_archiver_archive_non_retryable_error")
-	VisibilityArchiverArchiveTransientErrorCount    = NewCounterDef("visibility_archiver_archive_transient_error")
-	VisibilityArchiveSuccessCount                   = NewCounterDef("visibility_archiver_archive_success")
-	HistoryScavengerSuccessCount                    = NewCounterDef("scavenger_success")
-	HistoryScavengerErrorCount                      = NewCounterDef("scavenger_errors")
-	HistoryScavengerSkipCount                       = NewCounterDef("scavenger_skips")
-	ExecutionsOutstandingCount                      = NewGaugeDef("executions_outstanding")
-	ScavengerValidationRequestsCount                = NewCounterDef("scavenger_validation_requests")
-	ScavengerValidationFailuresCount                = NewCounterDef("scavenger_validation_failures")
-	ScavengerValidationSkipsCount                   = NewCounterDef("scavenger_validation_skips")
-	AddSearchAttributesFailuresCount                = NewCounterDef("add_search_attributes_failures")
-	DeleteNamespaceSuccessCount                     = NewCounterDef("delete_namespace_success")
-	RenameNamespaceSuccessCount                     = NewCounterDef("rename_namespace_success")
-	DeleteExecutionsSuccessCount                    = NewCounterDef("delete_executions_success")
-	DeleteNamespaceFailuresCount                    = NewCounterDef("delete_namespace_failures")
-	UpdateNamespaceFailuresCount                    = NewCounterDef("update_namespace_failures")
-	RenameNamespaceFailuresCount                    = NewCounterDef("rename_namespace_failures")
-	ReadNamespaceFailuresCount                      = NewCounterDef("read_namespace_failures")
-	ListExecutionsFailuresCount                     = NewCounterDef("list_executions_failures")
-	CountExecutionsFailuresCount                    = NewCounterDef("count_executions_failures")
-	DeleteExecutionFailuresCount                    = NewCounterDef("delete_execution_failures")
-	DeleteExecutionNotFoundCount                    = NewCounterDef("delete_execution_not_found")
-	RateLimiterFailuresCount                        = NewCounterDef("rate_limiter_failures")
-	BatcherProcessorSuccess                         = NewCounterDef(
+	ExecutorTasksDoneCount                           = NewCounterDef("executor_done")
+	ExecutorTasksErrCount                            = NewCounterDef("executor_err")
+	ExecutorTasksDeferredCount                       = NewCounterDef("executor_deferred")
+	ExecutorTasksDroppedCount                        = NewCounterDef("executor_dropped")
+	StartedCount                                     = NewCounterDef("started")
+	StoppedCount                                     = NewCounterDef("stopped")
+	TaskProcessedCount                               = NewGaugeDef("task_processed")
+	TaskDeletedCount                                 = NewGaugeDef("task_deleted")
+	TaskQueueProcessedCount                          = NewGaugeDef("taskqueue_processed")
+	TaskQueueDeletedCount                            = NewGaugeDef("taskqueue_deleted")
+	TaskQueueOutstandingCount                        = NewGaugeDef("taskqueue_outstanding")
+	HistoryArchiverArchiveNonRetryableErrorCount     = NewCounterDef("history_archiver_archive_non_retryable_error")
+	HistoryArchiverArchiveTransientErrorCount        = NewCounterDef("history_archiver_archive_transient_error")
+	HistoryArchiverArchiveSuccessCount               = NewCounterDef("history_archiver_archive_success")
+	HistoryArchiverTotalUploadSize                   = NewBytesHistogramDef("history_archiver_total_upload_size")
+	HistoryArchiverHistorySize                       = NewBytesHistogramDef("history_archiver_history_size")
+	HistoryArchiverDuplicateArchivalsCount           = NewCounterDef("history_archiver_duplicate_archivals")
+	HistoryArchiverBlobExistsCount                   = NewCounterDef("history_archiver_blob_exists")
+	HistoryArchiverBlobSize                          = NewBytesHistogramDef("history_archiver_blob_size")
+	HistoryWorkflowExecutionCacheLatency             = NewTimerDef("history_workflow_execution_cache_latency")
+	VisibilityArchiverArchiveNonRetryableErrorCount  = NewCounterDef("visibility_archiver_archive_non_retryable_error")
+	VisibilityArchiverArchiveTransientErrorCount     = NewCounterDef("visibility_archiver_archive_transient_error")
+	VisibilityArchiveSuccessCount                    = NewCounterDef("visibility_archiver_archive_success")
+	HistoryScavengerSuccessCount                     = NewCounterDef("scavenger_success")
+	HistoryScavengerErrorCount                       = NewCounterDef("scavenger_errors")
+	HistoryScavengerSkipCount                        = NewCounterDef("scavenger_skips")
+	ExecutionsOutstandingCount                       = NewGaugeDef("executions_outstanding")
+	ScavengerValidationRequestsCount                 = NewCounterDef("scavenger_validation_requests")
+	ScavengerValidationFailuresCount                 = NewCounterDef("scavenger_validation_failures")
+	ScavengerValidationSkipsCount                    = NewCounterDef("scavenger_validation_skips")
+	ArchivalVerifierVerifiedCount                    = NewCounterDef("archival_verifier_verified")
+	ArchivalVerifierMissingCount                     = NewCounterDef("archival_verifier_missing")
+	ArchivalVerifierCorruptCount                     = NewCounterDef("archival_verifier_corrupt")
+	ArchivalVerifierRepairedCount                    = NewCounterDef("archival_verifier_repaired")
+	ArchivalVerifierRepairFailedCount                = NewCounterDef("archival_verifier_repair_failed")
+	VisibilityScavengerConsistentCount               = NewCounterDef("visibility_scavenger_consistent")
+	VisibilityScavengerOrphanedCount                 = NewCounterDef("visibility_scavenger_orphaned")
+	VisibilityScavengerStatusMismatchCount           = NewCounterDef("visibility_scavenger_status_mismatch")
+	VisibilityScavengerSearchAttributesMismatchCount = NewCounterDef("visibility_scavenger_search_attributes_mismatch")
+	VisibilityScavengerFixedCount                    = NewCounterDef("visibility_scavenger_fixed")
+	VisibilityScavengerFixFailedCount                = NewCounterDef("visibility_scavenger_fix_failed")
+	AddSearchAttributesFailuresCount                 = NewCounterDef("add_search_attributes_failures")
+	DeleteNamespaceSuccessCount                      = NewCounterDef("delete_namespace_success")
+	RenameNamespaceSuccessCount                      = NewCounterDef("rename_namespace_success")
+	DeleteExecutionsSuccessCount                     = NewCounterDef("delete_executions_success")
+	DeleteNamespaceFailuresCount                     = NewCounterDef("delete_namespace_failures")
+	UpdateNamespaceFailuresCount                     = NewCounterDef("update_namespace_failures")
+	RenameNamespaceFailuresCount                     = NewCounterDef("rename_namespace_failures")
+	ReadNamespaceFailuresCount                       = NewCounterDef("read_namespace_failures")
+	ListExecutionsFailuresCount                      = NewCounterDef("list_executions_failures")
+	CountExecutionsFailuresCount                     = NewCounterDef("count_executions_failures")
+	DeleteExecutionFailuresCount                     = NewCounterDef("delete_execution_failures")
+	DeleteExecutionNotFoundCount                     = NewCounterDef("delete_execution_not_found")
+	RateLimiterFailuresCount                         = NewCounterDef("rate_limiter_failures")
+	BatcherProcessorSuccess                          = NewCounterDef(
 		"batcher_processor_requests",
 		WithDescription("The number of individual workflow execution tasks successfully processed by the batch request processor"),
 	)
@@ -38,4 +38,10 @@ const (
 
 	// MaxBadBinaries is the maximal number of bad client binaries stored in a namespace
 	MaxBadBinaries = 10
+
+	// SavedVisibilityQueryKeyPrefix is the prefix of the namespace data keys of saved visibility queries.
+	// Operators save a query under "temporal.visibility.savedQuery.<name>" with the admin
+	// SaveVisibilityQuery API, and users run it by passing "@<name>" as the query of
+	// ListWorkflowExecutions. RegisterNamespace and UpdateNamespace reject these keys.
+	SavedVisibilityQueryKeyPrefix = "temporal.visibility.savedQuery."
 )
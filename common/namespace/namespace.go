@@ -309,6 +309,16 @@ func (ns *Namespace) GetCustomData(key string) string {
 	return ns.info.Data[key]
 }
 
+// SavedVisibilityQuery returns the visibility query saved in the namespace data under
+// SavedVisibilityQueryKeyPrefix+name and false if there is no such query.
+func (ns *Namespace) SavedVisibilityQuery(name string) (string, bool) {
+	if name == "" {
+		return "", false
+	}
+	query, ok := ns.info.Data[SavedVisibilityQueryKeyPrefix+name]
+	return query, ok
+}
+
 // Retention returns retention duration for this namespace.
 func (ns *Namespace) Retention() time.Duration {
 	if ns.config.Retention == nil {
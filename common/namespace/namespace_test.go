@@ -142,3 +142,17 @@ func TestNamespace_GetCustomData(t *testing.T) {
 	data2 := ns.GetCustomData("fake")
 	assert.Equal(t, "", data2)
 }
+
+func TestNamespace_SavedVisibilityQuery(t *testing.T) {
+	base := base(t)
+	ns := base.Clone(namespace.WithData(namespace.SavedVisibilityQueryKeyPrefix+"stuck", "ExecutionStatus = 'Running'"))
+
+	query, ok := ns.SavedVisibilityQuery("stuck")
+	assert.True(t, ok)
+	assert.Equal(t, "ExecutionStatus = 'Running'", query)
+
+	_, ok = ns.SavedVisibilityQuery("missing")
+	assert.False(t, ok)
+	_, ok = ns.SavedVisibilityQuery("")
+	assert.False(t, ok)
+}
@@ -31,6 +31,7 @@ import (
 	enumspb "go.temporal.io/api/enums/v1"
 	"go.temporal.io/api/serviceerror"
 
+	enumsspb "go.temporal.io/server/api/enums/v1"
 	persistencespb "go.temporal.io/server/api/persistence/v1"
 	"go.temporal.io/server/common/log"
 	"go.temporal.io/server/common/persistence/serialization"
@@ -234,7 +235,7 @@ func (m *clusterMetadataManagerImpl) convertInternalGetClusterMetadataResponse(
 func immutableFieldsChanged(old *persistencespb.ClusterMetadata, cur *persistencespb.ClusterMetadata) bool {
 	if (old.ClusterName != "" && old.ClusterName != cur.ClusterName) ||
 		(old.ClusterId != "" && old.ClusterId != cur.ClusterId) ||
-		(old.HistoryShardCount != 0 && old.HistoryShardCount != cur.HistoryShardCount) ||
+		(old.HistoryShardCount != 0 && old.HistoryShardCount != cur.HistoryShardCount && !isHistoryShardCountCutover(old, cur)) ||
 		(old.IsGlobalNamespaceEnabled && !cur.IsGlobalNamespaceEnabled) {
 		return true
 	}
@@ -246,3 +247,12 @@ func immutableFieldsChanged(old *persistencespb.ClusterMetadata, cur *persistenc
 	}
 	return false
 }
+
+// isHistoryShardCountCutover allows the history shard count to change only as part of the cut-over step of a
+// history shard count migration.
+func isHistoryShardCountCutover(old *persistencespb.ClusterMetadata, cur *persistencespb.ClusterMetadata) bool {
+	migration := cur.GetHistoryShardCountMigration()
+	return migration.GetState() == enumsspb.HISTORY_SHARD_COUNT_MIGRATION_STATE_CUTOVER &&
+		migration.GetSourceShardCount() == old.HistoryShardCount &&
+		migration.GetTargetShardCount() == cur.HistoryShardCount
+}
@@ -0,0 +1,543 @@
+// The MIT License
+//
+// Copyright (c) 2020 Temporal Technologies Inc.  All rights reserved.
+//
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package shardmigration
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+
+	"go.temporal.io/api/serviceerror"
+	"google.golang.org/protobuf/proto"
+
+	enumsspb "go.temporal.io/server/api/enums/v1"
+	historyspb "go.temporal.io/server/api/history/v1"
+	persistencespb "go.temporal.io/server/api/persistence/v1"
+	"go.temporal.io/server/common"
+	"go.temporal.io/server/common/log"
+	"go.temporal.io/server/common/log/tag"
+	"go.temporal.io/server/common/persistence"
+	"go.temporal.io/server/common/persistence/versionhistory"
+)
+
+const (
+	listExecutionsPageSize = 100
+	readHistoryPageSize    = 100
+)
+
+type (
+	// Copier copies executions and their history from a source shard to the shards they map to under the target
+	// shard count of a migration.
+	Copier struct {
+		executionManager persistence.ExecutionManager
+		shardManager     persistence.ShardManager
+		logger           log.Logger
+	}
+
+	// shardCopy holds the state of copying a single source shard.
+	shardCopy struct {
+		*Copier
+		migration     *persistencespb.HistoryShardCountMigration
+		sourceShardID int32
+		// targetRangeIDs caches the range ID of target shards, which is required for every write to them.
+		targetRangeIDs map[int32]int64
+	}
+
+	executionKey struct {
+		namespaceID string
+		workflowID  string
+		runID       string
+	}
+)
+
+// NewCopier creates a new Copier.
+func NewCopier(
+	executionManager persistence.ExecutionManager,
+	shardManager persistence.ShardManager,
+	logger log.Logger,
+) *Copier {
+	return &Copier{
+		executionManager: executionManager,
+		shardManager:     shardManager,
+		logger:           logger,
+	}
+}
+
+// CopyShard copies every execution of sourceShardID which maps to another shard under the target shard count of the
+// migration, while the source shard keeps serving traffic. Executions which are already up-to-date in their target
+// shard are skipped, so CopyShard can be called again after a failure. The number of copied executions is reported
+// to heartbeat after each page and returned.
+func (c *Copier) CopyShard(
+	ctx context.Context,
+	migration *persistencespb.HistoryShardCountMigration,
+	sourceShardID int32,
+	heartbeat func(copied int64),
+) (int64, error) {
+	sc := c.newShardCopy(migration, sourceShardID)
+	var copied int64
+	err := sc.forEachMovedExecution(ctx, func(key executionKey, targetShardID int32) error {
+		ok, err := sc.copyExecution(ctx, key, targetShardID)
+		if err != nil {
+			return err
+		}
+		if ok {
+			copied++
+		}
+		return nil
+	}, func() {
+		if heartbeat != nil {
+			heartbeat(copied)
+		}
+	})
+	return copied, err
+}
+
+func (c *Copier) newShardCopy(
+	migration *persistencespb.HistoryShardCountMigration,
+	sourceShardID int32,
+) *shardCopy {
+	return &shardCopy{
+		Copier:         c,
+		migration:      migration,
+		sourceShardID:  sourceShardID,
+		targetRangeIDs: make(map[int32]int64),
+	}
+}
+
+// forEachMovedExecution calls fn for every execution of the source shard which moves to another shard, and
+// pageDone after every page of executions.
+func (sc *shardCopy) forEachMovedExecution(
+	ctx context.Context,
+	fn func(key executionKey, targetShardID int32) error,
+	pageDone func(),
+) error {
+	var pageToken []byte
+	for {
+		resp, err := sc.executionManager.ListConcreteExecutions(ctx, &persistence.ListConcreteExecutionsRequest{
+			ShardID:   sc.sourceShardID,
+			PageSize:  listExecutionsPageSize,
+			PageToken: pageToken,
+		})
+		if err != nil {
+			return err
+		}
+		for _, state := range resp.States {
+			key := executionKey{
+				namespaceID: state.ExecutionInfo.NamespaceId,
+				workflowID:  state.ExecutionInfo.WorkflowId,
+				runID:       state.ExecutionState.RunId,
+			}
+			targetShardID := TargetShardID(sc.migration, key.namespaceID, key.workflowID)
+			if targetShardID == sc.sourceShardID {
+				continue
+			}
+			if err := fn(key, targetShardID); err != nil {
+				return err
+			}
+		}
+		if pageDone != nil {
+			pageDone()
+		}
+		if len(resp.PageToken) == 0 {
+			return nil
+		}
+		pageToken = resp.PageToken
+	}
+}
+
+func (sc *shardCopy) targetRangeID(ctx context.Context, targetShardID int32) (int64, error) {
+	if rangeID, ok := sc.targetRangeIDs[targetShardID]; ok {
+		return rangeID, nil
+	}
+	resp, err := sc.shardManager.GetOrCreateShard(ctx, &persistence.GetOrCreateShardRequest{
+		ShardID:          targetShardID,
+		InitialShardInfo: &persistencespb.ShardInfo{ShardId: targetShardID},
+	})
+	if err != nil {
+		return 0, err
+	}
+	sc.targetRangeIDs[targetShardID] = resp.ShardInfo.GetRangeId()
+	return resp.ShardInfo.GetRangeId(), nil
+}
+
+// copyExecution copies a single execution to its target shard, unless the target shard already has an up-to-date
+// copy of it. It returns true if the execution was copied.
+func (sc *shardCopy) copyExecution(
+	ctx context.Context,
+	key executionKey,
+	targetShardID int32,
+) (bool, error) {
+	source, err := sc.getExecution(ctx, sc.sourceShardID, key)
+	if err != nil || source == nil {
+		// The execution was deleted since it was listed.
+		return false, err
+	}
+	target, err := sc.getExecution(ctx, targetShardID, key)
+	if err != nil {
+		return false, err
+	}
+	if target != nil && isUpToDate(source, target) {
+		return false, nil
+	}
+	isCurrent, err := sc.isCurrentExecution(ctx, sc.sourceShardID, key)
+	if err != nil {
+		return false, err
+	}
+	rangeID, err := sc.targetRangeID(ctx, targetShardID)
+	if err != nil {
+		return false, err
+	}
+
+	sourceBranches := make(map[string]struct{})
+	for _, versionHistory := range source.State.ExecutionInfo.GetVersionHistories().GetHistories() {
+		sourceBranches[string(versionHistory.BranchToken)] = struct{}{}
+		if err := sc.copyHistory(ctx, targetShardID, key, versionHistory); err != nil {
+			return false, err
+		}
+	}
+	if target != nil {
+		// Drop branches which only exist in the stale copy, e.g. after the workflow was reset.
+		for _, versionHistory := range target.State.ExecutionInfo.GetVersionHistories().GetHistories() {
+			if _, ok := sourceBranches[string(versionHistory.BranchToken)]; ok {
+				continue
+			}
+			if err := sc.executionManager.DeleteHistoryBranch(ctx, &persistence.DeleteHistoryBranchRequest{
+				ShardID:     targetShardID,
+				BranchToken: versionHistory.BranchToken,
+			}); err != nil {
+				return false, err
+			}
+		}
+		if err := sc.executionManager.DeleteWorkflowExecution(ctx, &persistence.DeleteWorkflowExecutionRequest{
+			ShardID:     targetShardID,
+			NamespaceID: key.namespaceID,
+			WorkflowID:  key.workflowID,
+			RunID:       key.runID,
+		}); err != nil {
+			return false, err
+		}
+	}
+
+	targetCurrent, err := sc.getCurrentExecution(ctx, targetShardID, key)
+	if err != nil {
+		return false, err
+	}
+	if targetCurrent != nil && (isCurrent || targetCurrent.RunID == key.runID) {
+		if err := sc.executionManager.DeleteCurrentWorkflowExecution(ctx, &persistence.DeleteCurrentWorkflowExecutionRequest{
+			ShardID:     targetShardID,
+			NamespaceID: key.namespaceID,
+			WorkflowID:  key.workflowID,
+			RunID:       targetCurrent.RunID,
+		}); err != nil {
+			return false, err
+		}
+	}
+
+	if err := sc.createExecution(ctx, targetShardID, rangeID, source, isCurrent); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+func (sc *shardCopy) createExecution(
+	ctx context.Context,
+	targetShardID int32,
+	rangeID int64,
+	source *persistence.GetWorkflowExecutionResponse,
+	isCurrent bool,
+) error {
+	state := source.State
+	executionInfo := state.ExecutionInfo
+	if executionInfo.ExecutionStats == nil {
+		executionInfo.ExecutionStats = &persistencespb.ExecutionStats{}
+	}
+
+	createMode := persistence.CreateWorkflowModeBrandNew
+	updateMode := persistence.UpdateWorkflowModeUpdateCurrent
+	if !isCurrent {
+		switch state.ExecutionState.State {
+		case enumsspb.WORKFLOW_EXECUTION_STATE_CREATED, enumsspb.WORKFLOW_EXECUTION_STATE_RUNNING:
+			return serviceerror.NewInternal(fmt.Sprintf(
+				"unable to copy running execution %v/%v which is not the current run", executionInfo.WorkflowId, state.ExecutionState.RunId,
+			))
+		}
+		createMode = persistence.CreateWorkflowModeBypassCurrent
+		updateMode = persistence.UpdateWorkflowModeBypassCurrent
+	}
+
+	// Buffered events can only be written by an update, so the execution is created one record version behind and
+	// brought to the source record version by the update.
+	dbRecordVersion := source.DBRecordVersion
+	hasBufferedEvents := len(state.BufferedEvents) > 0
+	createDBRecordVersion := dbRecordVersion
+	if hasBufferedEvents && dbRecordVersion > 0 {
+		createDBRecordVersion--
+	}
+
+	signalRequestedIDs := make(map[string]struct{}, len(state.SignalRequestedIds))
+	for _, id := range state.SignalRequestedIds {
+		signalRequestedIDs[id] = struct{}{}
+	}
+	if _, err := sc.executionManager.CreateWorkflowExecution(ctx, &persistence.CreateWorkflowExecutionRequest{
+		ShardID: targetShardID,
+		RangeID: rangeID,
+		Mode:    createMode,
+		NewWorkflowSnapshot: persistence.WorkflowSnapshot{
+			ExecutionInfo:       executionInfo,
+			ExecutionState:      state.ExecutionState,
+			NextEventID:         state.NextEventId,
+			ActivityInfos:       state.ActivityInfos,
+			TimerInfos:          state.TimerInfos,
+			ChildExecutionInfos: state.ChildExecutionInfos,
+			RequestCancelInfos:  state.RequestCancelInfos,
+			SignalInfos:         state.SignalInfos,
+			SignalRequestedIDs:  signalRequestedIDs,
+			Condition:           state.NextEventId,
+			DBRecordVersion:     createDBRecordVersion,
+			Checksum:            state.Checksum,
+		},
+	}); err != nil {
+		return err
+	}
+	if !hasBufferedEvents {
+		return nil
+	}
+
+	_, err := sc.executionManager.UpdateWorkflowExecution(ctx, &persistence.UpdateWorkflowExecutionRequest{
+		ShardID: targetShardID,
+		RangeID: rangeID,
+		Mode:    updateMode,
+		UpdateWorkflowMutation: persistence.WorkflowMutation{
+			ExecutionInfo:     executionInfo,
+			ExecutionState:    state.ExecutionState,
+			NextEventID:       state.NextEventId,
+			NewBufferedEvents: state.BufferedEvents,
+			Condition:         state.NextEventId,
+			DBRecordVersion:   dbRecordVersion,
+			Checksum:          state.Checksum,
+		},
+	})
+	return err
+}
+
+// copyHistory copies the events of a version history branch, including the events owned by its ancestor branches.
+// Nodes are written to the branch that owns them in the source shard, so the history tree of the target shard has
+// the same shape as the source one.
+func (sc *shardCopy) copyHistory(
+	ctx context.Context,
+	targetShardID int32,
+	key executionKey,
+	versionHistory *historyspb.VersionHistory,
+) error {
+	lastItem, err := versionhistory.GetLastVersionHistoryItem(versionHistory)
+	if err != nil {
+		// Nothing was written to this branch yet.
+		return nil
+	}
+	branchUtil := sc.executionManager.GetHistoryBranchUtil()
+	branch, err := branchUtil.ParseHistoryBranchInfo(versionHistory.BranchToken)
+	if err != nil {
+		return err
+	}
+	ancestors := append([]*persistencespb.HistoryBranchRange(nil), branch.Ancestors...)
+	sort.Slice(ancestors, func(i, j int) bool {
+		return ancestors[i].EndNodeId < ancestors[j].EndNodeId
+	})
+	ancestorTokens := make([][]byte, len(ancestors))
+	for i, ancestor := range ancestors {
+		ancestorTokens[i], err = branchUtil.UpdateHistoryBranchInfo(versionHistory.BranchToken, &persistencespb.HistoryBranch{
+			TreeId:    branch.TreeId,
+			BranchId:  ancestor.BranchId,
+			Ancestors: ancestors[:i],
+		})
+		if err != nil {
+			return err
+		}
+	}
+	ownerToken := func(nodeID int64) ([]byte, bool) {
+		for i, ancestor := range ancestors {
+			if nodeID < ancestor.EndNodeId {
+				return ancestorTokens[i], false
+			}
+		}
+		return versionHistory.BranchToken, true
+	}
+
+	info := persistence.BuildHistoryGarbageCleanupInfo(key.namespaceID, key.workflowID, key.runID)
+	isNewBranch := true
+	var prevTxnID int64
+	var pageToken []byte
+	for {
+		resp, err := sc.executionManager.ReadHistoryBranchByBatch(ctx, &persistence.ReadHistoryBranchRequest{
+			ShardID:       sc.sourceShardID,
+			BranchToken:   versionHistory.BranchToken,
+			MinEventID:    common.FirstEventID,
+			MaxEventID:    lastItem.GetEventId() + 1,
+			PageSize:      readHistoryPageSize,
+			NextPageToken: pageToken,
+		})
+		var notFound *serviceerror.NotFound
+		if errors.As(err, &notFound) {
+			sc.logger.Warn("History branch not found while copying execution",
+				tag.WorkflowNamespaceID(key.namespaceID),
+				tag.WorkflowID(key.workflowID),
+				tag.WorkflowRunID(key.runID),
+			)
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		for i, batch := range resp.History {
+			if len(batch.Events) == 0 {
+				continue
+			}
+			branchToken, isLeaf := ownerToken(batch.Events[0].EventId)
+			_, err := sc.executionManager.AppendHistoryNodes(ctx, &persistence.AppendHistoryNodesRequest{
+				ShardID:           targetShardID,
+				IsNewBranch:       isLeaf && isNewBranch,
+				Info:              info,
+				BranchToken:       branchToken,
+				Events:            batch.Events,
+				PrevTransactionID: prevTxnID,
+				TransactionID:     resp.TransactionIDs[i],
+			})
+			var conditionFailed *persistence.ConditionFailedError
+			if err != nil && !errors.As(err, &conditionFailed) {
+				// Nodes copied by a previous attempt are reported as condition failures by some stores.
+				return err
+			}
+			if isLeaf {
+				isNewBranch = false
+			}
+			prevTxnID = resp.TransactionIDs[i]
+		}
+		if len(resp.NextPageToken) == 0 {
+			return nil
+		}
+		pageToken = resp.NextPageToken
+	}
+}
+
+// deleteExecution removes an execution, its current record if it points to it, and its history from a shard.
+func (sc *shardCopy) deleteExecution(
+	ctx context.Context,
+	shardID int32,
+	key executionKey,
+	state *persistencespb.WorkflowMutableState,
+) error {
+	current, err := sc.getCurrentExecution(ctx, shardID, key)
+	if err != nil {
+		return err
+	}
+	if current != nil && current.RunID == key.runID {
+		if err := sc.executionManager.DeleteCurrentWorkflowExecution(ctx, &persistence.DeleteCurrentWorkflowExecutionRequest{
+			ShardID:     shardID,
+			NamespaceID: key.namespaceID,
+			WorkflowID:  key.workflowID,
+			RunID:       key.runID,
+		}); err != nil {
+			return err
+		}
+	}
+	if err := sc.executionManager.DeleteWorkflowExecution(ctx, &persistence.DeleteWorkflowExecutionRequest{
+		ShardID:     shardID,
+		NamespaceID: key.namespaceID,
+		WorkflowID:  key.workflowID,
+		RunID:       key.runID,
+	}); err != nil {
+		return err
+	}
+	for _, versionHistory := range state.ExecutionInfo.GetVersionHistories().GetHistories() {
+		if err := sc.executionManager.DeleteHistoryBranch(ctx, &persistence.DeleteHistoryBranchRequest{
+			ShardID:     shardID,
+			BranchToken: versionHistory.BranchToken,
+		}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (sc *shardCopy) getExecution(
+	ctx context.Context,
+	shardID int32,
+	key executionKey,
+) (*persistence.GetWorkflowExecutionResponse, error) {
+	resp, err := sc.executionManager.GetWorkflowExecution(ctx, &persistence.GetWorkflowExecutionRequest{
+		ShardID:     shardID,
+		NamespaceID: key.namespaceID,
+		WorkflowID:  key.workflowID,
+		RunID:       key.runID,
+	})
+	var notFound *serviceerror.NotFound
+	if errors.As(err, &notFound) {
+		return nil, nil
+	}
+	return resp, err
+}
+
+func (sc *shardCopy) getCurrentExecution(
+	ctx context.Context,
+	shardID int32,
+	key executionKey,
+) (*persistence.GetCurrentExecutionResponse, error) {
+	resp, err := sc.executionManager.GetCurrentExecution(ctx, &persistence.GetCurrentExecutionRequest{
+		ShardID:     shardID,
+		NamespaceID: key.namespaceID,
+		WorkflowID:  key.workflowID,
+	})
+	var notFound *serviceerror.NotFound
+	if errors.As(err, &notFound) {
+		return nil, nil
+	}
+	return resp, err
+}
+
+func (sc *shardCopy) isCurrentExecution(
+	ctx context.Context,
+	shardID int32,
+	key executionKey,
+) (bool, error) {
+	current, err := sc.getCurrentExecution(ctx, shardID, key)
+	if err != nil {
+		return false, err
+	}
+	return current != nil && current.RunID == key.runID, nil
+}
+
+// isUpToDate returns true if the target copy of an execution matches the source one. Records written before
+// DBRecordVersion was introduced can't be compared reliably and are always copied again.
+func isUpToDate(
+	source *persistence.GetWorkflowExecutionResponse,
+	target *persistence.GetWorkflowExecutionResponse,
+) bool {
+	return source.DBRecordVersion != 0 &&
+		source.DBRecordVersion == target.DBRecordVersion &&
+		source.State.NextEventId == target.State.NextEventId &&
+		proto.Equal(source.State.ExecutionState, target.State.ExecutionState) &&
+		proto.Equal(source.State.ExecutionInfo.GetVersionHistories(), target.State.ExecutionInfo.GetVersionHistories())
+}
@@ -0,0 +1,546 @@
+// The MIT License
+//
+// Copyright (c) 2020 Temporal Technologies Inc.  All rights reserved.
+//
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package shardmigration
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+	enumspb "go.temporal.io/api/enums/v1"
+	historypb "go.temporal.io/api/history/v1"
+	"go.temporal.io/api/serviceerror"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	enumsspb "go.temporal.io/server/api/enums/v1"
+	historyspb "go.temporal.io/server/api/history/v1"
+	persistencespb "go.temporal.io/server/api/persistence/v1"
+	"go.temporal.io/server/common"
+	"go.temporal.io/server/common/config"
+	"go.temporal.io/server/common/definition"
+	"go.temporal.io/server/common/dynamicconfig"
+	"go.temporal.io/server/common/log"
+	"go.temporal.io/server/common/persistence"
+	"go.temporal.io/server/common/persistence/serialization"
+	"go.temporal.io/server/common/persistence/sql"
+	_ "go.temporal.io/server/common/persistence/sql/sqlplugin/sqlite"
+	"go.temporal.io/server/common/resolver"
+	"go.temporal.io/server/service/history/tasks"
+)
+
+const (
+	testClusterName       = "active"
+	testSourceShardID     = int32(1)
+	testSourceRangeID     = int64(5)
+	testNumberOfWorkflows = 24
+)
+
+type (
+	copierSuite struct {
+		suite.Suite
+		*require.Assertions
+
+		ctx                    context.Context
+		cancel                 context.CancelFunc
+		factory                *sql.Factory
+		executionManager       persistence.ExecutionManager
+		shardManager           persistence.ShardManager
+		clusterMetadataManager persistence.ClusterMetadataManager
+		copier                 *Copier
+		categories             []tasks.Category
+
+		namespaceID string
+		migration   *persistencespb.HistoryShardCountMigration
+		nextTaskID  int64
+	}
+)
+
+func TestCopierSuite(t *testing.T) {
+	suite.Run(t, new(copierSuite))
+}
+
+func (s *copierSuite) SetupTest() {
+	s.Assertions = require.New(s.T())
+	s.ctx, s.cancel = context.WithTimeout(context.Background(), 30*time.Second)
+
+	logger := log.NewNoopLogger()
+	serializer := serialization.NewSerializer()
+	s.factory = sql.NewFactory(
+		config.SQL{
+			PluginName:        "sqlite",
+			DatabaseName:      "shard_migration_" + uuid.NewString(),
+			ConnectAttributes: map[string]string{"mode": "memory", "cache": "private"},
+		},
+		resolver.NewNoopResolver(),
+		testClusterName,
+		logger,
+	)
+	shardStore, err := s.factory.NewShardStore()
+	s.NoError(err)
+	executionStore, err := s.factory.NewExecutionStore()
+	s.NoError(err)
+	clusterMetadataStore, err := s.factory.NewClusterMetadataStore()
+	s.NoError(err)
+	s.shardManager = persistence.NewShardManager(shardStore, serializer)
+	s.executionManager = persistence.NewExecutionManager(
+		executionStore,
+		serializer,
+		nil,
+		logger,
+		dynamicconfig.GetIntPropertyFn(4*1024*1024),
+	)
+	s.clusterMetadataManager = persistence.NewClusterMetadataManagerImpl(clusterMetadataStore, serializer, testClusterName, logger)
+	s.copier = NewCopier(s.executionManager, s.shardManager, logger)
+	s.categories = nil
+	for _, category := range tasks.NewDefaultTaskCategoryRegistry().GetCategories() {
+		s.categories = append(s.categories, category)
+	}
+
+	s.namespaceID = uuid.NewString()
+	s.migration = &persistencespb.HistoryShardCountMigration{
+		SourceShardCount: 1,
+		TargetShardCount: 4,
+		State:            enumsspb.HISTORY_SHARD_COUNT_MIGRATION_STATE_COPYING,
+	}
+	s.nextTaskID = 1
+
+	_, err = s.shardManager.GetOrCreateShard(s.ctx, &persistence.GetOrCreateShardRequest{
+		ShardID: testSourceShardID,
+		InitialShardInfo: &persistencespb.ShardInfo{
+			ShardId: testSourceShardID,
+			RangeId: testSourceRangeID,
+		},
+	})
+	s.NoError(err)
+}
+
+func (s *copierSuite) TearDownTest() {
+	s.cancel()
+	s.factory.Close()
+}
+
+func (s *copierSuite) TestCopyShard() {
+	keys := s.createExecutions()
+
+	copied, err := s.copier.CopyShard(s.ctx, s.migration, testSourceShardID, nil)
+	s.NoError(err)
+	s.Equal(int64(len(s.movedKeys(keys))), copied)
+	for _, key := range keys {
+		targetShardID := TargetShardID(s.migration, key.namespaceID, key.workflowID)
+		if targetShardID == testSourceShardID {
+			s.Nil(s.getExecution(s.targetShardIDs(), key))
+			continue
+		}
+		s.assertCopied(targetShardID, key)
+	}
+
+	// Nothing changed, so copying again is a no-op.
+	copied, err = s.copier.CopyShard(s.ctx, s.migration, testSourceShardID, nil)
+	s.NoError(err)
+	s.Zero(copied)
+}
+
+func (s *copierSuite) TestFinalizeAndCleanUpShard() {
+	keys := s.createExecutions()
+	moved := s.movedKeys(keys)
+	_, err := s.copier.CopyShard(s.ctx, s.migration, testSourceShardID, nil)
+	s.NoError(err)
+
+	// Change an execution and delete another one after they were copied.
+	updated, deleted := moved[0], moved[1]
+	s.updateExecution(updated)
+	s.deleteExecution(deleted)
+
+	sourceShardInfo := s.getShardInfo(testSourceShardID)
+	sourceShardInfo.QueueStates = map[int32]*persistencespb.QueueState{
+		int32(tasks.CategoryIDTransfer): {
+			ExclusiveReaderHighWatermark: &persistencespb.TaskKey{FireTime: timestamppb.New(tasks.DefaultFireTime), TaskId: 1},
+		},
+	}
+	// Finalizing twice must give the same result.
+	for i := 0; i < 2; i++ {
+		stats, err := s.copier.FinalizeShard(s.ctx, s.migration, sourceShardInfo, s.categories)
+		s.NoError(err)
+		// Tasks of the deleted execution are moved as well, and dropped by the target shard when processed.
+		s.Equal(int64(4*len(moved)), stats.TasksMoved)
+	}
+
+	for _, targetShardID := range s.targetShardIDs() {
+		targetShardInfo := s.getShardInfo(targetShardID)
+		s.Greater(targetShardInfo.RangeId, testSourceRangeID)
+		s.Contains(targetShardInfo.QueueStates, int32(tasks.CategoryIDTransfer))
+	}
+	for _, key := range moved {
+		targetShardID := TargetShardID(s.migration, key.namespaceID, key.workflowID)
+		s.Len(s.getTasks(targetShardID, key), 4)
+		if key == deleted {
+			s.Nil(s.getExecution([]int32{targetShardID}, key))
+			continue
+		}
+		s.assertCopied(targetShardID, key)
+		// Tasks stay in the source shard until it is cleaned up.
+		s.Len(s.getTasks(testSourceShardID, key), 4)
+	}
+
+	s.NoError(s.copier.CleanUpShard(s.ctx, s.migration, sourceShardInfo, s.categories))
+	for _, key := range keys {
+		targetShardID := TargetShardID(s.migration, key.namespaceID, key.workflowID)
+		if targetShardID == testSourceShardID {
+			s.NotNil(s.getExecution([]int32{testSourceShardID}, key))
+			s.Len(s.getTasks(testSourceShardID, key), 4)
+			continue
+		}
+		s.Nil(s.getExecution([]int32{testSourceShardID}, key))
+		s.Empty(s.getTasks(testSourceShardID, key))
+	}
+}
+
+func (s *copierSuite) TestGate() {
+	keys := s.createExecutions()
+	_, err := s.copier.CopyShard(s.ctx, s.migration, testSourceShardID, nil)
+	s.NoError(err)
+
+	applied, err := s.clusterMetadataManager.SaveClusterMetadata(s.ctx, &persistence.SaveClusterMetadataRequest{
+		ClusterMetadata: &persistencespb.ClusterMetadata{
+			ClusterName:                testClusterName,
+			HistoryShardCount:          s.migration.SourceShardCount,
+			HistoryShardCountMigration: s.migration,
+		},
+	})
+	s.NoError(err)
+	s.True(applied)
+
+	// The shard count can only change together with the cut-over.
+	_, err = UpdateMigration(s.ctx, s.clusterMetadataManager, func(metadata *persistencespb.ClusterMetadata) error {
+		metadata.HistoryShardCount = s.migration.TargetShardCount
+		return nil
+	})
+	s.Error(err)
+	_, err = UpdateMigration(s.ctx, s.clusterMetadataManager, func(metadata *persistencespb.ClusterMetadata) error {
+		metadata.HistoryShardCountMigration.State = enumsspb.HISTORY_SHARD_COUNT_MIGRATION_STATE_CUTOVER
+		metadata.HistoryShardCountMigration.CopiedShardIds = []int32{testSourceShardID}
+		metadata.HistoryShardCount = s.migration.TargetShardCount
+		return nil
+	})
+	s.NoError(err)
+
+	registry := tasks.NewDefaultTaskCategoryRegistry()
+	oldGate := NewGate(s.migration.SourceShardCount, s.clusterMetadataManager, s.executionManager, s.shardManager, registry, log.NewNoopLogger())
+	newGate := NewGate(s.migration.TargetShardCount, s.clusterMetadataManager, s.executionManager, s.shardManager, registry, log.NewNoopLogger())
+
+	var unavailable *serviceerror.Unavailable
+	s.ErrorAs(newGate.BeforeAcquire(s.ctx, 2), &unavailable)
+	s.NoError(oldGate.BeforeAcquire(s.ctx, testSourceShardID))
+	s.NoError(newGate.BeforeAcquire(s.ctx, testSourceShardID))
+
+	fence, err := newGate.AfterAcquire(s.ctx, testSourceShardID)
+	s.NoError(err)
+	s.NotNil(fence)
+	s.False(fence.Finalized())
+	for _, key := range keys {
+		targetShardID, moved := fence.Moved(key.namespaceID, key.workflowID)
+		s.Equal(TargetShardID(s.migration, key.namespaceID, key.workflowID), targetShardID)
+		s.Equal(targetShardID != testSourceShardID, moved)
+	}
+	s.NoError(newGate.Settle(s.ctx, fence, s.getShardInfo(testSourceShardID)))
+	s.True(fence.Finalized())
+
+	migration, historyShardCount, err := GetMigration(s.ctx, s.clusterMetadataManager)
+	s.NoError(err)
+	s.Equal(s.migration.TargetShardCount, historyShardCount)
+	s.Equal(enumsspb.HISTORY_SHARD_COUNT_MIGRATION_STATE_COMPLETED, migration.State)
+	s.Equal([]int32{testSourceShardID}, migration.FinalizedShardIds)
+	s.Equal([]int32{testSourceShardID}, migration.CleanedUpShardIds)
+	s.Equal(int64(4*len(s.movedKeys(keys))), migration.TasksMoved)
+	s.NotNil(migration.CompleteTime)
+
+	// There's nothing left to fence off once the shard is cleaned up.
+	fence, err = newGate.AfterAcquire(s.ctx, testSourceShardID)
+	s.NoError(err)
+	s.Nil(fence)
+
+	for _, shardID := range s.targetShardIDs() {
+		s.NoError(newGate.BeforeAcquire(s.ctx, shardID))
+	}
+	var ownershipLost *persistence.ShardOwnershipLostError
+	s.ErrorAs(oldGate.BeforeAcquire(s.ctx, testSourceShardID), &ownershipLost)
+}
+
+// createExecutions creates executions in the source shard. Every third workflow has a closed run in addition to its
+// current run.
+func (s *copierSuite) createExecutions() []executionKey {
+	var keys []executionKey
+	for i := 0; i < testNumberOfWorkflows; i++ {
+		workflowID := fmt.Sprintf("workflow-%d", i)
+		previousRunID := ""
+		if i%3 == 0 {
+			closed := s.createExecution(workflowID, enumsspb.WORKFLOW_EXECUTION_STATE_COMPLETED, "")
+			keys = append(keys, closed)
+			previousRunID = closed.runID
+		}
+		keys = append(keys, s.createExecution(workflowID, enumsspb.WORKFLOW_EXECUTION_STATE_RUNNING, previousRunID))
+	}
+	s.GreaterOrEqual(len(s.movedKeys(keys)), 2)
+	return keys
+}
+
+func (s *copierSuite) createExecution(
+	workflowID string,
+	state enumsspb.WorkflowExecutionState,
+	previousRunID string,
+) executionKey {
+	key := executionKey{
+		namespaceID: s.namespaceID,
+		workflowID:  workflowID,
+		runID:       uuid.NewString(),
+	}
+	branchID := uuid.NewString()
+	branchToken, err := s.executionManager.GetHistoryBranchUtil().NewHistoryBranch(
+		key.namespaceID,
+		key.workflowID,
+		key.runID,
+		key.runID,
+		&branchID,
+		nil,
+		0,
+		0,
+		0,
+	)
+	s.NoError(err)
+	s.appendEvents(branchToken, 1, 3, 0)
+	s.appendEvents(branchToken, 4, 5, 1)
+
+	status := enumspb.WORKFLOW_EXECUTION_STATUS_RUNNING
+	if state == enumsspb.WORKFLOW_EXECUTION_STATE_COMPLETED {
+		status = enumspb.WORKFLOW_EXECUTION_STATUS_COMPLETED
+	}
+	mode := persistence.CreateWorkflowModeBrandNew
+	if previousRunID != "" {
+		mode = persistence.CreateWorkflowModeUpdateCurrent
+	}
+	_, err = s.executionManager.CreateWorkflowExecution(s.ctx, &persistence.CreateWorkflowExecutionRequest{
+		ShardID:       testSourceShardID,
+		RangeID:       testSourceRangeID,
+		Mode:          mode,
+		PreviousRunID: previousRunID,
+		NewWorkflowSnapshot: persistence.WorkflowSnapshot{
+			ExecutionInfo: &persistencespb.WorkflowExecutionInfo{
+				NamespaceId: key.namespaceID,
+				WorkflowId:  key.workflowID,
+				VersionHistories: &historyspb.VersionHistories{
+					Histories: []*historyspb.VersionHistory{{
+						BranchToken: branchToken,
+						Items:       []*historyspb.VersionHistoryItem{{EventId: 5, Version: common.EmptyVersion}},
+					}},
+				},
+				ExecutionStats: &persistencespb.ExecutionStats{},
+			},
+			ExecutionState: &persistencespb.WorkflowExecutionState{
+				CreateRequestId: uuid.NewString(),
+				RunId:           key.runID,
+				State:           state,
+				Status:          status,
+			},
+			NextEventID:     6,
+			DBRecordVersion: 1,
+			Tasks:           s.newTasks(key),
+		},
+	})
+	s.NoError(err)
+	return key
+}
+
+// updateExecution appends events to an execution and bumps its record version.
+func (s *copierSuite) updateExecution(key executionKey) {
+	resp, err := s.executionManager.GetWorkflowExecution(s.ctx, &persistence.GetWorkflowExecutionRequest{
+		ShardID:     testSourceShardID,
+		NamespaceID: key.namespaceID,
+		WorkflowID:  key.workflowID,
+		RunID:       key.runID,
+	})
+	s.NoError(err)
+	state := resp.State
+	versionHistory := state.ExecutionInfo.VersionHistories.Histories[0]
+	s.appendEvents(versionHistory.BranchToken, 6, 7, 2)
+	versionHistory.Items[0].EventId = 7
+
+	mode := persistence.UpdateWorkflowModeUpdateCurrent
+	if state.ExecutionState.State == enumsspb.WORKFLOW_EXECUTION_STATE_COMPLETED {
+		mode = persistence.UpdateWorkflowModeBypassCurrent
+	}
+	_, err = s.executionManager.UpdateWorkflowExecution(s.ctx, &persistence.UpdateWorkflowExecutionRequest{
+		ShardID: testSourceShardID,
+		RangeID: testSourceRangeID,
+		Mode:    mode,
+		UpdateWorkflowMutation: persistence.WorkflowMutation{
+			ExecutionInfo:   state.ExecutionInfo,
+			ExecutionState:  state.ExecutionState,
+			NextEventID:     8,
+			Condition:       state.NextEventId,
+			DBRecordVersion: resp.DBRecordVersion + 1,
+		},
+	})
+	s.NoError(err)
+}
+
+func (s *copierSuite) deleteExecution(key executionKey) {
+	execution := s.getExecution([]int32{testSourceShardID}, key)
+	s.NotNil(execution)
+	s.NoError(s.copier.newShardCopy(s.migration, testSourceShardID).deleteExecution(s.ctx, testSourceShardID, key, execution.State))
+}
+
+func (s *copierSuite) appendEvents(branchToken []byte, firstEventID int64, lastEventID int64, prevTxnID int64) {
+	var events []*historypb.HistoryEvent
+	for eventID := firstEventID; eventID <= lastEventID; eventID++ {
+		events = append(events, &historypb.HistoryEvent{
+			EventId:   eventID,
+			EventTime: timestamppb.New(time.Unix(0, eventID).UTC()),
+			Version:   common.EmptyVersion,
+		})
+	}
+	_, err := s.executionManager.AppendHistoryNodes(s.ctx, &persistence.AppendHistoryNodesRequest{
+		ShardID:           testSourceShardID,
+		IsNewBranch:       firstEventID == common.FirstEventID,
+		BranchToken:       branchToken,
+		Events:            events,
+		PrevTransactionID: prevTxnID,
+		TransactionID:     prevTxnID + 1,
+	})
+	s.NoError(err)
+}
+
+func (s *copierSuite) newTasks(key executionKey) map[tasks.Category][]tasks.Task {
+	workflowKey := definition.NewWorkflowKey(key.namespaceID, key.workflowID, key.runID)
+	fireTime := time.Now().Add(time.Hour).UTC().Truncate(time.Millisecond)
+	newTaskID := func() int64 {
+		s.nextTaskID++
+		return s.nextTaskID
+	}
+	return map[tasks.Category][]tasks.Task{
+		tasks.CategoryTransfer: {
+			&tasks.ActivityTask{WorkflowKey: workflowKey, TaskID: newTaskID(), ScheduledEventID: 2},
+			&tasks.ActivityTask{WorkflowKey: workflowKey, TaskID: newTaskID(), ScheduledEventID: 3},
+		},
+		tasks.CategoryTimer: {
+			&tasks.UserTimerTask{WorkflowKey: workflowKey, TaskID: newTaskID(), VisibilityTimestamp: fireTime, EventID: 4},
+			&tasks.UserTimerTask{WorkflowKey: workflowKey, TaskID: newTaskID(), VisibilityTimestamp: fireTime, EventID: 5},
+		},
+	}
+}
+
+// getExecution returns the execution from the first of the given shards that has it, or nil.
+func (s *copierSuite) getExecution(shardIDs []int32, key executionKey) *persistence.GetWorkflowExecutionResponse {
+	for _, shardID := range shardIDs {
+		resp, err := s.executionManager.GetWorkflowExecution(s.ctx, &persistence.GetWorkflowExecutionRequest{
+			ShardID:     shardID,
+			NamespaceID: key.namespaceID,
+			WorkflowID:  key.workflowID,
+			RunID:       key.runID,
+		})
+		var notFound *serviceerror.NotFound
+		if errors.As(err, &notFound) {
+			continue
+		}
+		s.NoError(err)
+		return resp
+	}
+	return nil
+}
+
+// getTasks returns the transfer and timer tasks of an execution in a shard.
+func (s *copierSuite) getTasks(shardID int32, key executionKey) []tasks.Task {
+	var result []tasks.Task
+	for _, category := range []tasks.Category{tasks.CategoryTransfer, tasks.CategoryTimer} {
+		minKey, maxKey := taskRange(category, tasks.MinimumKey)
+		resp, err := s.executionManager.GetHistoryTasks(s.ctx, &persistence.GetHistoryTasksRequest{
+			ShardID:             shardID,
+			TaskCategory:        category,
+			InclusiveMinTaskKey: minKey,
+			ExclusiveMaxTaskKey: maxKey,
+			BatchSize:           1000,
+		})
+		s.NoError(err)
+		for _, task := range resp.Tasks {
+			if task.GetRunID() == key.runID {
+				result = append(result, task)
+			}
+		}
+	}
+	return result
+}
+
+func (s *copierSuite) getShardInfo(shardID int32) *persistencespb.ShardInfo {
+	resp, err := s.shardManager.GetOrCreateShard(s.ctx, &persistence.GetOrCreateShardRequest{
+		ShardID:          shardID,
+		InitialShardInfo: &persistencespb.ShardInfo{ShardId: shardID},
+	})
+	s.NoError(err)
+	return resp.ShardInfo
+}
+
+func (s *copierSuite) targetShardIDs() []int32 {
+	return TargetShardIDs(testSourceShardID, s.migration.SourceShardCount, s.migration.TargetShardCount)
+}
+
+func (s *copierSuite) movedKeys(keys []executionKey) []executionKey {
+	var moved []executionKey
+	for _, key := range keys {
+		if TargetShardID(s.migration, key.namespaceID, key.workflowID) != testSourceShardID {
+			moved = append(moved, key)
+		}
+	}
+	return moved
+}
+
+// assertCopied checks that the execution, its current record and its history in the target shard match the source.
+func (s *copierSuite) assertCopied(targetShardID int32, key executionKey) {
+	source := s.getExecution([]int32{testSourceShardID}, key)
+	s.NotNil(source)
+	target := s.getExecution([]int32{targetShardID}, key)
+	s.NotNil(target)
+	s.True(isUpToDate(source, target))
+
+	sourceCurrent, err := s.copier.newShardCopy(s.migration, testSourceShardID).isCurrentExecution(s.ctx, testSourceShardID, key)
+	s.NoError(err)
+	targetCurrent, err := s.copier.newShardCopy(s.migration, testSourceShardID).isCurrentExecution(s.ctx, targetShardID, key)
+	s.NoError(err)
+	s.Equal(sourceCurrent, targetCurrent)
+
+	versionHistory := target.State.ExecutionInfo.VersionHistories.Histories[0]
+	resp, err := s.executionManager.ReadHistoryBranch(s.ctx, &persistence.ReadHistoryBranchRequest{
+		ShardID:     targetShardID,
+		BranchToken: versionHistory.BranchToken,
+		MinEventID:  common.FirstEventID,
+		MaxEventID:  versionHistory.Items[0].EventId + 1,
+		PageSize:    100,
+	})
+	s.NoError(err)
+	s.Len(resp.HistoryEvents, int(versionHistory.Items[0].EventId))
+}
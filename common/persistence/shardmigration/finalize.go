@@ -0,0 +1,390 @@
+// The MIT License
+//
+// Copyright (c) 2020 Temporal Technologies Inc.  All rights reserved.
+//
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package shardmigration
+
+import (
+	"context"
+	"math"
+	"time"
+
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	enumsspb "go.temporal.io/server/api/enums/v1"
+	persistencespb "go.temporal.io/server/api/persistence/v1"
+	"go.temporal.io/server/common/log/tag"
+	"go.temporal.io/server/common/persistence"
+	"go.temporal.io/server/common/primitives/timestamp"
+	"go.temporal.io/server/service/history/tasks"
+)
+
+const (
+	readTasksPageSize = 100
+)
+
+type (
+	// FinalizeStats reports what was moved by FinalizeShard.
+	FinalizeStats struct {
+		ExecutionsMoved int64
+		TasksMoved      int64
+	}
+)
+
+// FinalizeShard moves a source shard to its target shards. It must only be called by the owner of the source shard,
+// with the shard info read after its range ID was renewed, while the moved executions are fenced off and none of the
+// target shards are owned, which is guaranteed by the Gate. In order:
+//
+//  1. the range ID of the target shards is raised above the source one, so that new task IDs in the target shards
+//     are larger than the IDs of the moved tasks, and the source queue states are carried over;
+//  2. executions which changed since they were copied are copied again and stale copies are removed;
+//  3. pending tasks of the moved executions are added to the target shards.
+//
+// Nothing is removed from the source shard, so FinalizeShard can be retried until the shard is marked as finalized.
+// CleanUpShard then removes the moved data from the source shard.
+func (c *Copier) FinalizeShard(
+	ctx context.Context,
+	migration *persistencespb.HistoryShardCountMigration,
+	sourceShardInfo *persistencespb.ShardInfo,
+	categories []tasks.Category,
+) (FinalizeStats, error) {
+	var stats FinalizeStats
+	sc := c.newShardCopy(migration, sourceShardInfo.GetShardId())
+	targetShardIDs := TargetShardIDs(sc.sourceShardID, migration.GetSourceShardCount(), migration.GetTargetShardCount())
+
+	for _, targetShardID := range targetShardIDs {
+		if err := sc.prepareTargetShard(ctx, targetShardID, sourceShardInfo, categories); err != nil {
+			return stats, err
+		}
+	}
+
+	moved := make(map[executionKey]struct{})
+	if err := sc.forEachMovedExecution(ctx, func(key executionKey, targetShardID int32) error {
+		moved[key] = struct{}{}
+		ok, err := sc.copyExecution(ctx, key, targetShardID)
+		if err != nil {
+			return err
+		}
+		if ok {
+			stats.ExecutionsMoved++
+		}
+		return nil
+	}, nil); err != nil {
+		return stats, err
+	}
+	for _, targetShardID := range targetShardIDs {
+		if err := sc.removeStaleCopies(ctx, targetShardID, moved); err != nil {
+			return stats, err
+		}
+	}
+
+	for _, category := range categories {
+		if category.ID() == tasks.CategoryIDMemoryTimer {
+			continue
+		}
+		count, err := sc.moveTasks(ctx, category, sourceShardInfo.GetQueueStates()[int32(category.ID())])
+		if err != nil {
+			return stats, err
+		}
+		stats.TasksMoved += count
+	}
+	return stats, nil
+}
+
+// CleanUpShard removes the executions and tasks which were moved by FinalizeShard from the source shard. It is
+// idempotent.
+func (c *Copier) CleanUpShard(
+	ctx context.Context,
+	migration *persistencespb.HistoryShardCountMigration,
+	sourceShardInfo *persistencespb.ShardInfo,
+	categories []tasks.Category,
+) error {
+	sc := c.newShardCopy(migration, sourceShardInfo.GetShardId())
+
+	for _, category := range categories {
+		if category.ID() == tasks.CategoryIDMemoryTimer {
+			continue
+		}
+		queueState := sourceShardInfo.GetQueueStates()[int32(category.ID())]
+		if err := sc.forEachMovedTask(ctx, category, queueState, func(task tasks.Task, _ int32) error {
+			return sc.executionManager.CompleteHistoryTask(ctx, &persistence.CompleteHistoryTaskRequest{
+				ShardID:      sc.sourceShardID,
+				TaskCategory: category,
+				TaskKey:      task.GetKey(),
+			})
+		}); err != nil {
+			return err
+		}
+	}
+
+	var moved []executionKey
+	if err := sc.forEachMovedExecution(ctx, func(key executionKey, _ int32) error {
+		moved = append(moved, key)
+		return nil
+	}, nil); err != nil {
+		return err
+	}
+	for _, key := range moved {
+		source, err := sc.getExecution(ctx, sc.sourceShardID, key)
+		if err != nil {
+			return err
+		}
+		if source == nil {
+			continue
+		}
+		if err := sc.deleteExecution(ctx, sc.sourceShardID, key, source.State); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (sc *shardCopy) prepareTargetShard(
+	ctx context.Context,
+	targetShardID int32,
+	sourceShardInfo *persistencespb.ShardInfo,
+	categories []tasks.Category,
+) error {
+	resp, err := sc.shardManager.GetOrCreateShard(ctx, &persistence.GetOrCreateShardRequest{
+		ShardID:          targetShardID,
+		InitialShardInfo: &persistencespb.ShardInfo{ShardId: targetShardID},
+	})
+	if err != nil {
+		return err
+	}
+	targetShardInfo := resp.ShardInfo
+	previousRangeID := targetShardInfo.GetRangeId()
+	targetShardInfo.RangeId = max(previousRangeID, sourceShardInfo.GetRangeId()) + 1
+	targetShardInfo.QueueStates = make(map[int32]*persistencespb.QueueState, len(sourceShardInfo.GetQueueStates()))
+	for categoryID, queueState := range sourceShardInfo.GetQueueStates() {
+		targetShardInfo.QueueStates[categoryID] = clonedQueueState(queueState)
+	}
+	if err := sc.shardManager.UpdateShard(ctx, &persistence.UpdateShardRequest{
+		ShardInfo:       targetShardInfo,
+		PreviousRangeID: previousRangeID,
+	}); err != nil {
+		return err
+	}
+	sc.targetRangeIDs[targetShardID] = targetShardInfo.RangeId
+
+	// The target shard was never owned, so any task in it was added by a previous attempt to finalize the source
+	// shard. Remove them so that they can be added again without conflicts.
+	for _, category := range categories {
+		if category.ID() == tasks.CategoryIDMemoryTimer {
+			continue
+		}
+		minKey, maxKey := taskRange(category, tasks.MinimumKey)
+		if err := sc.executionManager.RangeCompleteHistoryTasks(ctx, &persistence.RangeCompleteHistoryTasksRequest{
+			ShardID:             targetShardID,
+			TaskCategory:        category,
+			InclusiveMinTaskKey: minKey,
+			ExclusiveMaxTaskKey: maxKey,
+		}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// removeStaleCopies deletes executions which were copied to the target shard but no longer exist in the source
+// shard, e.g. because they were deleted by retention after they had been copied.
+func (sc *shardCopy) removeStaleCopies(
+	ctx context.Context,
+	targetShardID int32,
+	moved map[executionKey]struct{},
+) error {
+	var stale []*persistencespb.WorkflowMutableState
+	var pageToken []byte
+	for {
+		resp, err := sc.executionManager.ListConcreteExecutions(ctx, &persistence.ListConcreteExecutionsRequest{
+			ShardID:   targetShardID,
+			PageSize:  listExecutionsPageSize,
+			PageToken: pageToken,
+		})
+		if err != nil {
+			return err
+		}
+		for _, state := range resp.States {
+			key := executionKey{
+				namespaceID: state.ExecutionInfo.NamespaceId,
+				workflowID:  state.ExecutionInfo.WorkflowId,
+				runID:       state.ExecutionState.RunId,
+			}
+			if _, ok := moved[key]; !ok {
+				stale = append(stale, state)
+			}
+		}
+		if len(resp.PageToken) == 0 {
+			break
+		}
+		pageToken = resp.PageToken
+	}
+
+	for _, state := range stale {
+		key := executionKey{
+			namespaceID: state.ExecutionInfo.NamespaceId,
+			workflowID:  state.ExecutionInfo.WorkflowId,
+			runID:       state.ExecutionState.RunId,
+		}
+		sc.logger.Info("Removing stale copy of execution",
+			tag.ShardID(targetShardID),
+			tag.WorkflowNamespaceID(key.namespaceID),
+			tag.WorkflowID(key.workflowID),
+			tag.WorkflowRunID(key.runID),
+		)
+		if err := sc.deleteExecution(ctx, targetShardID, key, state); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// moveTasks adds the pending tasks of moved executions to their target shards.
+func (sc *shardCopy) moveTasks(
+	ctx context.Context,
+	category tasks.Category,
+	queueState *persistencespb.QueueState,
+) (int64, error) {
+	var moved int64
+	err := sc.forEachMovedTask(ctx, category, queueState, func(task tasks.Task, targetShardID int32) error {
+		rangeID, err := sc.targetRangeID(ctx, targetShardID)
+		if err != nil {
+			return err
+		}
+		if err := sc.executionManager.AddHistoryTasks(ctx, &persistence.AddHistoryTasksRequest{
+			ShardID:     targetShardID,
+			RangeID:     rangeID,
+			NamespaceID: task.GetNamespaceID(),
+			WorkflowID:  task.GetWorkflowID(),
+			RunID:       task.GetRunID(),
+			Tasks:       map[tasks.Category][]tasks.Task{category: {task}},
+		}); err != nil {
+			return err
+		}
+		moved++
+		return nil
+	})
+	return moved, err
+}
+
+// forEachMovedTask calls fn for every pending task of the source shard that belongs to a moved execution. Tasks
+// below the persisted ack level of the source queue have already been processed and are skipped.
+func (sc *shardCopy) forEachMovedTask(
+	ctx context.Context,
+	category tasks.Category,
+	queueState *persistencespb.QueueState,
+	fn func(task tasks.Task, targetShardID int32) error,
+) error {
+	minKey, maxKey := taskRange(category, minPendingTaskKey(queueState))
+	var pageToken []byte
+	for {
+		resp, err := sc.executionManager.GetHistoryTasks(ctx, &persistence.GetHistoryTasksRequest{
+			ShardID:             sc.sourceShardID,
+			TaskCategory:        category,
+			InclusiveMinTaskKey: minKey,
+			ExclusiveMaxTaskKey: maxKey,
+			BatchSize:           readTasksPageSize,
+			NextPageToken:       pageToken,
+		})
+		if err != nil {
+			return err
+		}
+		for _, task := range resp.Tasks {
+			targetShardID := TargetShardID(sc.migration, task.GetNamespaceID(), task.GetWorkflowID())
+			if targetShardID == sc.sourceShardID {
+				continue
+			}
+			if err := fn(task, targetShardID); err != nil {
+				return err
+			}
+		}
+		if len(resp.NextPageToken) == 0 {
+			return nil
+		}
+		pageToken = resp.NextPageToken
+	}
+}
+
+// taskRange returns the range of task keys, starting at minKey, in the form expected by the task category.
+func taskRange(category tasks.Category, minKey tasks.Key) (tasks.Key, tasks.Key) {
+	if category.Type() == tasks.CategoryTypeScheduled {
+		return tasks.NewKey(minKey.FireTime, 0), tasks.NewKey(tasks.MaximumKey.FireTime, 0)
+	}
+	return tasks.NewImmediateKey(minKey.TaskID), tasks.NewImmediateKey(math.MaxInt64)
+}
+
+// minPendingTaskKey returns the smallest task key that may not have been processed according to the queue state.
+func minPendingTaskKey(queueState *persistencespb.QueueState) tasks.Key {
+	if queueState == nil {
+		return tasks.MinimumKey
+	}
+	minKey := fromPersistenceTaskKey(queueState.ExclusiveReaderHighWatermark)
+	for _, readerState := range queueState.ReaderStates {
+		for _, scope := range readerState.Scopes {
+			minKey = tasks.MinKey(minKey, fromPersistenceTaskKey(scope.GetRange().GetInclusiveMin()))
+		}
+	}
+	return minKey
+}
+
+func fromPersistenceTaskKey(key *persistencespb.TaskKey) tasks.Key {
+	if key == nil {
+		return tasks.MinimumKey
+	}
+	return tasks.NewKey(timestamp.TimeValue(key.FireTime), key.TaskId)
+}
+
+func clonedQueueState(queueState *persistencespb.QueueState) *persistencespb.QueueState {
+	return proto.Clone(queueState).(*persistencespb.QueueState)
+}
+
+// markFinalized records that the source shard was finalized.
+func markFinalized(
+	migration *persistencespb.HistoryShardCountMigration,
+	shardID int32,
+	stats FinalizeStats,
+) {
+	if containsShard(migration.FinalizedShardIds, shardID) {
+		return
+	}
+	migration.FinalizedShardIds = append(migration.FinalizedShardIds, shardID)
+	migration.ExecutionsMoved += stats.ExecutionsMoved
+	migration.TasksMoved += stats.TasksMoved
+}
+
+// markCleanedUp records that the source shard was cleaned up, and completes the migration once every source shard
+// is cleaned up.
+func markCleanedUp(
+	migration *persistencespb.HistoryShardCountMigration,
+	shardID int32,
+	now time.Time,
+) {
+	if !containsShard(migration.CleanedUpShardIds, shardID) {
+		migration.CleanedUpShardIds = append(migration.CleanedUpShardIds, shardID)
+	}
+	if int32(len(migration.CleanedUpShardIds)) == migration.SourceShardCount {
+		migration.State = enumsspb.HISTORY_SHARD_COUNT_MIGRATION_STATE_COMPLETED
+		migration.CompleteTime = timestamppb.New(now)
+	}
+}
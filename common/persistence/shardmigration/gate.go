@@ -0,0 +1,221 @@
+// The MIT License
+//
+// Copyright (c) 2020 Temporal Technologies Inc.  All rights reserved.
+//
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package shardmigration
+
+import (
+	"context"
+	"fmt"
+	"sync/atomic"
+	"time"
+
+	"go.temporal.io/api/serviceerror"
+
+	enumsspb "go.temporal.io/server/api/enums/v1"
+	persistencespb "go.temporal.io/server/api/persistence/v1"
+	"go.temporal.io/server/common/log"
+	"go.temporal.io/server/common/log/tag"
+	"go.temporal.io/server/common/persistence"
+	"go.temporal.io/server/service/history/tasks"
+)
+
+type (
+	// Gate is consulted by history shards while they are acquired, and implements the cut-over of a history shard
+	// count migration.
+	Gate interface {
+		// BeforeAcquire is called before a shard renews its range ID. It returns an error if the shard must not be
+		// acquired by this host at this point of the migration.
+		BeforeAcquire(ctx context.Context, shardID int32) error
+		// AfterAcquire is called after a shard renewed its range ID and before its engine is created. If the shard is
+		// a source shard of a migration in the cut-over state which isn't cleaned up yet, it returns the Fence of the
+		// executions moved out of the shard, and Settle must then be run while the shard serves the other executions.
+		AfterAcquire(ctx context.Context, shardID int32) (*Fence, error)
+		// Settle finalizes and cleans up the source shard of the fence. shardInfo is the shard as it was when the
+		// fence was returned by AfterAcquire.
+		Settle(ctx context.Context, fence *Fence, shardInfo *persistencespb.ShardInfo) error
+	}
+
+	// Fence tells a source shard which of its executions are moved by a migration in the cut-over state. The shard
+	// must not touch moved executions: until the shard is finalized they may still be copied again, and afterwards
+	// they live in their target shards.
+	Fence struct {
+		migration *persistencespb.HistoryShardCountMigration
+		shardID   int32
+		finalized atomic.Bool
+	}
+
+	gateImpl struct {
+		numberOfShards         int32
+		clusterMetadataManager persistence.ClusterMetadataManager
+		copier                 *Copier
+		categories             []tasks.Category
+		logger                 log.Logger
+	}
+)
+
+// NewGate creates a Gate for a history host configured with numberOfShards shards.
+func NewGate(
+	numberOfShards int32,
+	clusterMetadataManager persistence.ClusterMetadataManager,
+	executionManager persistence.ExecutionManager,
+	shardManager persistence.ShardManager,
+	taskCategoryRegistry tasks.TaskCategoryRegistry,
+	logger log.Logger,
+) Gate {
+	categories := make([]tasks.Category, 0, len(taskCategoryRegistry.GetCategories()))
+	for _, category := range taskCategoryRegistry.GetCategories() {
+		categories = append(categories, category)
+	}
+	return &gateImpl{
+		numberOfShards:         numberOfShards,
+		clusterMetadataManager: clusterMetadataManager,
+		copier:                 NewCopier(executionManager, shardManager, logger),
+		categories:             categories,
+		logger:                 logger,
+	}
+}
+
+func (g *gateImpl) BeforeAcquire(ctx context.Context, shardID int32) error {
+	migration, _, err := GetMigration(ctx, g.clusterMetadataManager)
+	if err != nil {
+		return err
+	}
+	switch migration.GetState() {
+	case enumsspb.HISTORY_SHARD_COUNT_MIGRATION_STATE_CUTOVER,
+		enumsspb.HISTORY_SHARD_COUNT_MIGRATION_STATE_COMPLETED:
+	default:
+		return nil
+	}
+
+	switch g.numberOfShards {
+	case migration.SourceShardCount:
+		// A host which hasn't been restarted with the new shard count yet must not take the shard back after it was
+		// finalized, as some of its executions now live in other shards.
+		if containsShard(migration.FinalizedShardIds, shardID) {
+			return &persistence.ShardOwnershipLostError{
+				ShardID: shardID,
+				Msg:     fmt.Sprintf("shard was moved to %d history shards", migration.TargetShardCount),
+			}
+		}
+	case migration.TargetShardCount:
+		// A new shard can only be used once its executions were moved from the source shard.
+		if migration.State == enumsspb.HISTORY_SHARD_COUNT_MIGRATION_STATE_CUTOVER && shardID > migration.SourceShardCount {
+			sourceShardID := SourceShardID(shardID, migration.SourceShardCount)
+			if !containsShard(migration.FinalizedShardIds, sourceShardID) {
+				return serviceerror.NewUnavailable(fmt.Sprintf(
+					"shard %d is waiting for source shard %d to be finalized", shardID, sourceShardID,
+				))
+			}
+		}
+	}
+	return nil
+}
+
+func (g *gateImpl) AfterAcquire(ctx context.Context, shardID int32) (*Fence, error) {
+	migration, _, err := GetMigration(ctx, g.clusterMetadataManager)
+	if err != nil {
+		return nil, err
+	}
+	if migration.GetState() != enumsspb.HISTORY_SHARD_COUNT_MIGRATION_STATE_CUTOVER ||
+		g.numberOfShards != migration.TargetShardCount ||
+		shardID > migration.SourceShardCount ||
+		containsShard(migration.CleanedUpShardIds, shardID) {
+		return nil, nil
+	}
+
+	fence := &Fence{
+		migration: migration,
+		shardID:   shardID,
+	}
+	fence.finalized.Store(containsShard(migration.FinalizedShardIds, shardID))
+	return fence, nil
+}
+
+func (g *gateImpl) Settle(ctx context.Context, fence *Fence, shardInfo *persistencespb.ShardInfo) error {
+	shardID := fence.shardID
+	migration := fence.migration
+	if !fence.Finalized() {
+		g.logger.Info("Finalizing history shard count migration", tag.ShardID(shardID))
+		stats, err := g.copier.FinalizeShard(ctx, migration, shardInfo, g.categories)
+		if err != nil {
+			return err
+		}
+		if migration, err = g.updateMigration(ctx, migration, func(m *persistencespb.HistoryShardCountMigration) {
+			markFinalized(m, shardID, stats)
+		}); err != nil {
+			return err
+		}
+		fence.finalized.Store(true)
+		g.logger.Info("Finalized history shard count migration",
+			tag.ShardID(shardID),
+			tag.NewInt64("executions-moved", stats.ExecutionsMoved),
+			tag.NewInt64("tasks-moved", stats.TasksMoved),
+		)
+	}
+
+	if err := g.copier.CleanUpShard(ctx, migration, shardInfo, g.categories); err != nil {
+		return err
+	}
+	migration, err := g.updateMigration(ctx, migration, func(m *persistencespb.HistoryShardCountMigration) {
+		markCleanedUp(m, shardID, time.Now().UTC())
+	})
+	if err != nil {
+		return err
+	}
+	if migration.GetState() == enumsspb.HISTORY_SHARD_COUNT_MIGRATION_STATE_COMPLETED {
+		g.logger.Info("Completed history shard count migration",
+			tag.NewInt32("source-shard-count", migration.SourceShardCount),
+			tag.NewInt32("target-shard-count", migration.TargetShardCount),
+		)
+	}
+	return nil
+}
+
+// Moved returns the shard that the workflow is moved to, and whether it is moved out of the fenced shard at all.
+func (f *Fence) Moved(namespaceID string, workflowID string) (int32, bool) {
+	targetShardID := TargetShardID(f.migration, namespaceID, workflowID)
+	return targetShardID, targetShardID != f.shardID
+}
+
+// Finalized returns true once the moved executions were handed over to their target shards.
+func (f *Fence) Finalized() bool {
+	return f.finalized.Load()
+}
+
+// updateMigration applies updateFn to the persisted migration, provided it is still the given one.
+func (g *gateImpl) updateMigration(
+	ctx context.Context,
+	migration *persistencespb.HistoryShardCountMigration,
+	updateFn func(m *persistencespb.HistoryShardCountMigration),
+) (*persistencespb.HistoryShardCountMigration, error) {
+	return UpdateMigration(ctx, g.clusterMetadataManager, func(metadata *persistencespb.ClusterMetadata) error {
+		current := metadata.GetHistoryShardCountMigration()
+		if current.GetState() != enumsspb.HISTORY_SHARD_COUNT_MIGRATION_STATE_CUTOVER ||
+			current.GetTargetShardCount() != migration.GetTargetShardCount() {
+			return serviceerror.NewFailedPrecondition("history shard count migration is no longer in the cut-over state")
+		}
+		updateFn(current)
+		return nil
+	})
+}
@@ -0,0 +1,160 @@
+// The MIT License
+//
+// Copyright (c) 2020 Temporal Technologies Inc.  All rights reserved.
+//
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+// Package shardmigration implements raising the number of history shards of a cluster.
+//
+// A migration goes through the following states, which are persisted in the cluster metadata record of the current
+// cluster:
+//
+//  1. COPYING: every execution which maps to a different shard under the target shard count is copied, together with
+//     its history, to that shard. The cluster keeps serving from the source shards while this happens.
+//  2. CUTOVER: the persisted history shard count is switched to the target shard count, which history hosts pick up
+//     on their next restart. A host running with the target shard count finalizes a source shard in the background
+//     after it acquires it: executions which changed since they were copied are copied again, pending tasks are
+//     moved, queue states are carried over and the moved data is removed from the source shard. The shard serves
+//     the executions which stay meanwhile, and only the moved ones are fenced off.
+//     Target shards can't be acquired until their source shard is finalized, and hosts still running with the source
+//     shard count give up finalized shards.
+//  3. COMPLETED: every source shard has been finalized and cleaned up.
+//
+// The target shard count must be a multiple of the source shard count. With N source shards, executions of source
+// shard s then only ever move to shards s+N, s+2N, ..., none of which exist before the cut-over.
+package shardmigration
+
+import (
+	"context"
+	"fmt"
+	"slices"
+
+	"go.temporal.io/api/serviceerror"
+
+	enumsspb "go.temporal.io/server/api/enums/v1"
+	persistencespb "go.temporal.io/server/api/persistence/v1"
+	"go.temporal.io/server/common"
+	"go.temporal.io/server/common/persistence"
+)
+
+const (
+	// maxUpdateAttempts bounds the compare-and-swap loop in UpdateMigration.
+	maxUpdateAttempts = 10
+)
+
+// ValidateTargetShardCount checks that a cluster with sourceShardCount history shards can be migrated to
+// targetShardCount shards.
+func ValidateTargetShardCount(sourceShardCount int32, targetShardCount int32) error {
+	if targetShardCount <= sourceShardCount {
+		return serviceerror.NewInvalidArgument(fmt.Sprintf(
+			"target shard count %d must be greater than the current shard count %d", targetShardCount, sourceShardCount,
+		))
+	}
+	if targetShardCount%sourceShardCount != 0 {
+		return serviceerror.NewInvalidArgument(fmt.Sprintf(
+			"target shard count %d must be a multiple of the current shard count %d", targetShardCount, sourceShardCount,
+		))
+	}
+	return nil
+}
+
+// IsActive returns true if the migration has been started and isn't completed yet.
+func IsActive(migration *persistencespb.HistoryShardCountMigration) bool {
+	switch migration.GetState() {
+	case enumsspb.HISTORY_SHARD_COUNT_MIGRATION_STATE_COPYING,
+		enumsspb.HISTORY_SHARD_COUNT_MIGRATION_STATE_CUTOVER:
+		return true
+	default:
+		return false
+	}
+}
+
+// SourceShardID returns the source shard whose executions may move to the given target shard.
+func SourceShardID(targetShardID int32, sourceShardCount int32) int32 {
+	return (targetShardID-1)%sourceShardCount + 1
+}
+
+// TargetShardIDs returns the shards, other than sourceShardID itself, that executions of sourceShardID may move to.
+func TargetShardIDs(sourceShardID int32, sourceShardCount int32, targetShardCount int32) []int32 {
+	var shardIDs []int32
+	for shardID := sourceShardID + sourceShardCount; shardID <= targetShardCount; shardID += sourceShardCount {
+		shardIDs = append(shardIDs, shardID)
+	}
+	return shardIDs
+}
+
+// TargetShardID returns the shard that the given workflow maps to under the target shard count.
+func TargetShardID(migration *persistencespb.HistoryShardCountMigration, namespaceID string, workflowID string) int32 {
+	return common.WorkflowIDToHistoryShard(namespaceID, workflowID, migration.GetTargetShardCount())
+}
+
+// GetMigration returns the history shard count migration of the current cluster, or nil if none was ever started,
+// together with the persisted history shard count.
+func GetMigration(
+	ctx context.Context,
+	clusterMetadataManager persistence.ClusterMetadataManager,
+) (*persistencespb.HistoryShardCountMigration, int32, error) {
+	resp, err := clusterMetadataManager.GetCurrentClusterMetadata(ctx)
+	if err != nil {
+		return nil, 0, err
+	}
+	return resp.GetHistoryShardCountMigration(), resp.GetHistoryShardCount(), nil
+}
+
+// UpdateMigration applies updateFn to the cluster metadata record of the current cluster and saves it. The update is
+// retried if the record was concurrently modified, so updateFn must be safe to call more than once.
+func UpdateMigration(
+	ctx context.Context,
+	clusterMetadataManager persistence.ClusterMetadataManager,
+	updateFn func(metadata *persistencespb.ClusterMetadata) error,
+) (*persistencespb.HistoryShardCountMigration, error) {
+	var lastErr error
+	for attempt := 0; attempt < maxUpdateAttempts; attempt++ {
+		resp, err := clusterMetadataManager.GetCurrentClusterMetadata(ctx)
+		if err != nil {
+			return nil, err
+		}
+		if err := updateFn(resp.ClusterMetadata); err != nil {
+			return nil, err
+		}
+		applied, err := clusterMetadataManager.SaveClusterMetadata(ctx, &persistence.SaveClusterMetadataRequest{
+			ClusterMetadata: resp.ClusterMetadata,
+			Version:         resp.Version,
+		})
+		if err != nil {
+			// SQL stores report a version mismatch as an error rather than as not applied.
+			if ctx.Err() != nil {
+				return nil, err
+			}
+			lastErr = err
+			continue
+		}
+		if !applied {
+			return nil, serviceerror.NewInternal("cluster metadata update rejected: immutable fields changed")
+		}
+		return resp.GetHistoryShardCountMigration(), nil
+	}
+	return nil, fmt.Errorf("unable to update history shard count migration: %w", lastErr)
+}
+
+func containsShard(shardIDs []int32, shardID int32) bool {
+	return slices.Contains(shardIDs, shardID)
+}
@@ -0,0 +1,65 @@
+// The MIT License
+//
+// Copyright (c) 2020 Temporal Technologies Inc.  All rights reserved.
+//
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package shardmigration
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	persistencespb "go.temporal.io/server/api/persistence/v1"
+	"go.temporal.io/server/common"
+)
+
+func TestValidateTargetShardCount(t *testing.T) {
+	require.NoError(t, ValidateTargetShardCount(4, 8))
+	require.NoError(t, ValidateTargetShardCount(4, 16))
+	require.Error(t, ValidateTargetShardCount(4, 4))
+	require.Error(t, ValidateTargetShardCount(4, 2))
+	require.Error(t, ValidateTargetShardCount(4, 10))
+}
+
+func TestTargetShardIDs(t *testing.T) {
+	require.Equal(t, []int32{5, 9}, TargetShardIDs(1, 4, 12))
+	require.Equal(t, []int32{8, 12}, TargetShardIDs(4, 4, 12))
+	for _, targetShardID := range TargetShardIDs(3, 4, 12) {
+		require.Equal(t, int32(3), SourceShardID(targetShardID, 4))
+	}
+}
+
+// TestTargetShardID checks that executions only ever move to a target shard of the source shard they're in.
+func TestTargetShardID(t *testing.T) {
+	migration := &persistencespb.HistoryShardCountMigration{
+		SourceShardCount: 4,
+		TargetShardCount: 12,
+	}
+	for i := 0; i < 1000; i++ {
+		workflowID := fmt.Sprintf("workflow-%d", i)
+		sourceShardID := common.WorkflowIDToHistoryShard("namespace", workflowID, migration.SourceShardCount)
+		targetShardID := TargetShardID(migration, "namespace", workflowID)
+		require.Equal(t, sourceShardID, SourceShardID(targetShardID, migration.SourceShardCount))
+	}
+}
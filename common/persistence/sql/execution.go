@@ -28,6 +28,7 @@ import (
 	"bytes"
 	"context"
 	"database/sql"
+	"encoding/json"
 	"fmt"
 
 	"go.temporal.io/api/serviceerror"
@@ -751,9 +752,68 @@ func (m *sqlExecutionStore) setWorkflowExecutionTx(
 	)
 }
 
+// listConcreteExecutionsPaginationToken represents the primary key of the last row in the executions table that we
+// returned.
+type listConcreteExecutionsPaginationToken struct {
+	NamespaceID primitives.UUID
+	WorkflowID  string
+	RunID       primitives.UUID
+}
+
 func (m *sqlExecutionStore) ListConcreteExecutions(
-	_ context.Context,
-	_ *p.ListConcreteExecutionsRequest,
+	ctx context.Context,
+	request *p.ListConcreteExecutionsRequest,
 ) (*p.InternalListConcreteExecutionsResponse, error) {
-	return nil, serviceerror.NewUnimplemented("ListConcreteExecutions is not implemented")
+	pageSize := request.PageSize
+	if pageSize <= 0 {
+		return nil, serviceerror.NewInvalidArgument(fmt.Sprintf("PageSize must be greater than 0, but was %d", pageSize))
+	}
+
+	page := sqlplugin.ExecutionsPage{
+		ShardID: request.ShardID,
+		// Empty rather than nil, so that the first page starts before any execution.
+		NamespaceID: primitives.UUID{},
+		RunID:       primitives.UUID{},
+		Limit:       pageSize,
+	}
+	if len(request.PageToken) != 0 {
+		var token listConcreteExecutionsPaginationToken
+		if err := json.Unmarshal(request.PageToken, &token); err != nil {
+			return nil, serviceerror.NewInvalidArgument(fmt.Sprintf("ListConcreteExecutions: invalid page token: %v", err))
+		}
+		page.NamespaceID = token.NamespaceID
+		page.WorkflowID = token.WorkflowID
+		page.RunID = token.RunID
+	}
+
+	rows, err := m.Db.PaginateFromExecutions(ctx, page)
+	if err != nil {
+		return nil, serviceerror.NewUnavailable(fmt.Sprintf("ListConcreteExecutions: failed. Error: %v", err))
+	}
+	response := &p.InternalListConcreteExecutionsResponse{
+		States: make([]*p.InternalWorkflowMutableState, 0, len(rows)),
+	}
+	for _, row := range rows {
+		response.States = append(response.States, &p.InternalWorkflowMutableState{
+			ExecutionInfo:   p.NewDataBlob(row.Data, row.DataEncoding),
+			ExecutionState:  p.NewDataBlob(row.State, row.StateEncoding),
+			NextEventID:     row.NextEventID,
+			DBRecordVersion: row.DBRecordVersion,
+		})
+	}
+	if len(rows) < pageSize {
+		// no next page token because there are no more results
+		return response, nil
+	}
+
+	lastRow := rows[len(rows)-1]
+	response.NextPageToken, err = json.Marshal(listConcreteExecutionsPaginationToken{
+		NamespaceID: lastRow.NamespaceID,
+		WorkflowID:  lastRow.WorkflowID,
+		RunID:       lastRow.RunID,
+	})
+	if err != nil {
+		return nil, serviceerror.NewInternal(fmt.Sprintf("ListConcreteExecutions: unable to encode page token: %v", err))
+	}
+	return response, nil
 }
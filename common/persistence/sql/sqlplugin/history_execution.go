@@ -59,6 +59,16 @@ type (
 		RunID       primitives.UUID
 	}
 
+	// ExecutionsPage is a page of executions of a shard to query, sorted by their primary key and starting after the
+	// given execution.
+	ExecutionsPage struct {
+		ShardID     int32
+		NamespaceID primitives.UUID
+		WorkflowID  string
+		RunID       primitives.UUID
+		Limit       int
+	}
+
 	// CurrentExecutionsRow represents a row in current_executions table
 	CurrentExecutionsRow struct {
 		ShardID          int32
@@ -92,6 +102,7 @@ type (
 		UpdateExecutions(ctx context.Context, row *ExecutionsRow) (sql.Result, error)
 		SelectFromExecutions(ctx context.Context, filter ExecutionsFilter) (*ExecutionsRow, error)
 		DeleteFromExecutions(ctx context.Context, filter ExecutionsFilter) (sql.Result, error)
+		PaginateFromExecutions(ctx context.Context, page ExecutionsPage) ([]ExecutionsRow, error)
 		ReadLockExecutions(ctx context.Context, filter ExecutionsFilter) (int64, int64, error)
 		WriteLockExecutions(ctx context.Context, filter ExecutionsFilter) (int64, int64, error)
 
@@ -44,6 +44,11 @@ const (
 	getExecutionQuery = `SELECT ` + executionsColumns + ` FROM executions
  WHERE shard_id = ? AND namespace_id = ? AND workflow_id = ? AND run_id = ?`
 
+	paginateExecutionsQuery = `SELECT ` + executionsColumns + ` FROM executions
+ WHERE shard_id = ? AND (namespace_id > ? OR (namespace_id = ? AND (workflow_id > ? OR (workflow_id = ? AND run_id > ?))))
+ ORDER BY namespace_id, workflow_id, run_id
+ LIMIT ?`
+
 	deleteExecutionQuery = `DELETE FROM executions 
  WHERE shard_id = ? AND namespace_id = ? AND workflow_id = ? AND run_id = ?`
 
@@ -227,6 +232,27 @@ func (mdb *db) SelectFromExecutions(
 	return &row, err
 }
 
+// PaginateFromExecutions reads up to page.Limit rows of a shard from executions table, sorted by their primary key
+// and starting after the given execution
+func (mdb *db) PaginateFromExecutions(
+	ctx context.Context,
+	page sqlplugin.ExecutionsPage,
+) ([]sqlplugin.ExecutionsRow, error) {
+	var rows []sqlplugin.ExecutionsRow
+	err := mdb.conn.SelectContext(ctx,
+		&rows,
+		paginateExecutionsQuery,
+		page.ShardID,
+		page.NamespaceID,
+		page.NamespaceID,
+		page.WorkflowID,
+		page.WorkflowID,
+		page.RunID,
+		page.Limit,
+	)
+	return rows, err
+}
+
 // DeleteFromExecutions deletes a single row from executions table
 func (mdb *db) DeleteFromExecutions(
 	ctx context.Context,
@@ -45,8 +45,8 @@ var (
 
 	templateInsertCustomSearchAttributes = `
 		INSERT INTO custom_search_attributes (
-			namespace_id, run_id, search_attributes
-		) VALUES (:namespace_id, :run_id, :search_attributes)
+			namespace_id, run_id, search_attributes, text01_tokens, text02_tokens, text03_tokens
+		) VALUES (:namespace_id, :run_id, :search_attributes, :text01_tokens, :text02_tokens, :text03_tokens)
 		ON DUPLICATE KEY UPDATE run_id = VALUES(run_id)`
 
 	templateUpsertWorkflowExecution = fmt.Sprintf(
@@ -60,9 +60,13 @@ var (
 
 	templateUpsertCustomSearchAttributes = `
 		INSERT INTO custom_search_attributes (
-			namespace_id, run_id, search_attributes
-		) VALUES (:namespace_id, :run_id, :search_attributes)
-		ON DUPLICATE KEY UPDATE search_attributes = VALUES(search_attributes)`
+			namespace_id, run_id, search_attributes, text01_tokens, text02_tokens, text03_tokens
+		) VALUES (:namespace_id, :run_id, :search_attributes, :text01_tokens, :text02_tokens, :text03_tokens)
+		ON DUPLICATE KEY UPDATE
+			search_attributes = VALUES(search_attributes),
+			text01_tokens = VALUES(text01_tokens),
+			text02_tokens = VALUES(text02_tokens),
+			text03_tokens = VALUES(text03_tokens)`
 
 	templateDeleteWorkflowExecution_v8 = `
 		DELETE FROM executions_visibility
@@ -258,17 +262,17 @@ func (mdb *dbV8) CountGroupByFromVisibility(
 	return sqlplugin.ParseCountGroupByRows(rows, filter.GroupBy)
 }
 
-func (mdb *dbV8) prepareRowForDB(row *sqlplugin.VisibilityRow) *sqlplugin.VisibilityRow {
+func (mdb *dbV8) prepareRowForDB(row *sqlplugin.VisibilityRow) *sqlplugin.TextSearchTokensRow {
 	if row == nil {
 		return nil
 	}
-	finalRow := *row
+	finalRow := sqlplugin.NewTextSearchTokensRow(row, sqlplugin.FormatMySQLTextSearchTokens)
 	finalRow.StartTime = mdb.converter.ToMySQLDateTime(finalRow.StartTime)
 	finalRow.ExecutionTime = mdb.converter.ToMySQLDateTime(finalRow.ExecutionTime)
 	if finalRow.CloseTime != nil {
 		*finalRow.CloseTime = mdb.converter.ToMySQLDateTime(*finalRow.CloseTime)
 	}
-	return &finalRow
+	return finalRow
 }
 
 func (mdb *dbV8) processRowFromDB(row *sqlplugin.VisibilityRow) error {
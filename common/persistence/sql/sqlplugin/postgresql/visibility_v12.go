@@ -34,21 +34,24 @@ import (
 )
 
 var (
+	// the tokens of Text search attributes are written along with the row, but never read back
+	insertFields = append(append([]string{}, sqlplugin.DbFields...), sqlplugin.TextSearchTokensFields...)
+
 	templateInsertWorkflowExecution = fmt.Sprintf(
 		`INSERT INTO executions_visibility (%s)
 		VALUES (%s)
 		ON CONFLICT (namespace_id, run_id) DO NOTHING`,
-		strings.Join(sqlplugin.DbFields, ", "),
-		sqlplugin.BuildNamedPlaceholder(sqlplugin.DbFields...),
+		strings.Join(insertFields, ", "),
+		sqlplugin.BuildNamedPlaceholder(insertFields...),
 	)
 
 	templateUpsertWorkflowExecution = fmt.Sprintf(
 		`INSERT INTO executions_visibility (%s)
 		VALUES (%s)
 		%s`,
-		strings.Join(sqlplugin.DbFields, ", "),
-		sqlplugin.BuildNamedPlaceholder(sqlplugin.DbFields...),
-		buildOnDuplicateKeyUpdate(sqlplugin.DbFields...),
+		strings.Join(insertFields, ", "),
+		sqlplugin.BuildNamedPlaceholder(insertFields...),
+		buildOnDuplicateKeyUpdate(insertFields...),
 	)
 
 	templateDeleteWorkflowExecution_v12 = `
@@ -176,17 +179,17 @@ func (pdb *dbV12) CountGroupByFromVisibility(
 	return sqlplugin.ParseCountGroupByRows(rows, filter.GroupBy)
 }
 
-func (pdb *dbV12) prepareRowForDB(row *sqlplugin.VisibilityRow) *sqlplugin.VisibilityRow {
+func (pdb *dbV12) prepareRowForDB(row *sqlplugin.VisibilityRow) *sqlplugin.TextSearchTokensRow {
 	if row == nil {
 		return nil
 	}
-	finalRow := *row
+	finalRow := sqlplugin.NewTextSearchTokensRow(row, sqlplugin.FormatPostgreSQLTextSearchTokens)
 	finalRow.StartTime = pdb.converter.ToPostgreSQLDateTime(finalRow.StartTime)
 	finalRow.ExecutionTime = pdb.converter.ToPostgreSQLDateTime(finalRow.ExecutionTime)
 	if finalRow.CloseTime != nil {
 		*finalRow.CloseTime = pdb.converter.ToPostgreSQLDateTime(*finalRow.CloseTime)
 	}
-	return &finalRow
+	return finalRow
 }
 
 func (pdb *dbV12) processRowFromDB(row *sqlplugin.VisibilityRow) error {
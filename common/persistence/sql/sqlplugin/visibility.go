@@ -66,6 +66,20 @@ type (
 		SearchAttributes     *VisibilitySearchAttributes
 		ParentWorkflowID     *string
 		ParentRunID          *string
+		// TextSearchTokens are the tokens of the Text search attributes by column name. MySQL and
+		// PostgreSQL index them instead of the values, see TextSearchTokensRow.
+		TextSearchTokens map[string][]string `db:"-"`
+	}
+
+	// TextSearchTokensRow is a visibility row with the tokens of its Text search attributes formatted
+	// for the token columns of MySQL or PostgreSQL. Those stores index the tokens produced by the
+	// server rather than tokenizing the values themselves, so that text search follows the same
+	// tokenization rules on every visibility store.
+	TextSearchTokensRow struct {
+		VisibilityRow
+		Text01Tokens *string `db:"text01_tokens"`
+		Text02Tokens *string `db:"text02_tokens"`
+		Text03Tokens *string `db:"text03_tokens"`
 	}
 
 	// VisibilitySelectFilter contains the column names within executions_visibility table that
@@ -127,6 +141,61 @@ var _ driver.Valuer = (*VisibilitySearchAttributes)(nil)
 
 var DbFields = getDbFields()
 
+// TextSearchTokensFields are the token columns of the Text search attribute columns Text01 to Text03.
+var TextSearchTokensFields = []string{"text01_tokens", "text02_tokens", "text03_tokens"}
+
+// mysqlTextSearchTokenSuffix pads tokens stored for MySQL. MySQL doesn't index words shorter than
+// innodb_ft_min_token_size (3 by default) or stop words, and underscores are word characters for it.
+const mysqlTextSearchTokenSuffix = "__"
+
+// NewTextSearchTokensRow formats the tokens of the Text search attributes of the row with format.
+// Token columns of Text search attributes without a value are NULL.
+func NewTextSearchTokensRow(row *VisibilityRow, format func(tokens []string) string) *TextSearchTokensRow {
+	tokensRow := &TextSearchTokensRow{VisibilityRow: *row}
+	for colName, tokenCol := range map[string]**string{
+		"Text01": &tokensRow.Text01Tokens,
+		"Text02": &tokensRow.Text02Tokens,
+		"Text03": &tokensRow.Text03Tokens,
+	} {
+		if tokens, ok := row.TextSearchTokens[colName]; ok {
+			value := format(tokens)
+			*tokenCol = &value
+		}
+	}
+	return tokensRow
+}
+
+// TextSearchTokensColName returns the token column of a Text search attribute column.
+func TextSearchTokensColName(colName string) string {
+	return strings.ToLower(colName) + "_tokens"
+}
+
+// MySQLTextSearchToken returns a token as it is stored in the MySQL token columns, and as it must be
+// searched for in them.
+func MySQLTextSearchToken(token string) string {
+	return token + mysqlTextSearchTokenSuffix
+}
+
+// FormatMySQLTextSearchTokens formats tokens for the MySQL token columns.
+func FormatMySQLTextSearchTokens(tokens []string) string {
+	padded := make([]string, len(tokens))
+	for i, token := range tokens {
+		padded[i] = MySQLTextSearchToken(token)
+	}
+	return strings.Join(padded, " ")
+}
+
+// FormatPostgreSQLTextSearchTokens formats tokens as a tsvector literal with their positions, for
+// the PostgreSQL token columns. Tokens only contain letters, digits and marks, so they don't need
+// to be escaped.
+func FormatPostgreSQLTextSearchTokens(tokens []string) string {
+	lexemes := make([]string, len(tokens))
+	for i, token := range tokens {
+		lexemes[i] = fmt.Sprintf("'%s':%d", token, i+1)
+	}
+	return strings.Join(lexemes, " ")
+}
+
 func (vsa *VisibilitySearchAttributes) Scan(src interface{}) error {
 	if src == nil {
 		return nil
@@ -199,19 +268,27 @@ func parseCountGroupByGroupValue(fieldName string, value any) (any, error) {
 			)
 		}
 	default:
+		// MySQL returns text columns as bytes.
+		if bytesValue, isBytes := value.([]byte); isBytes {
+			return string(bytesValue), nil
+		}
 		return value, nil
 	}
 }
 
 func getDbFields() []string {
 	t := reflect.TypeOf(VisibilityRow{})
-	dbFields := make([]string, t.NumField())
+	dbFields := make([]string, 0, t.NumField())
 	for i := 0; i < t.NumField(); i++ {
 		f := t.Field(i)
-		dbFields[i] = f.Tag.Get("db")
-		if dbFields[i] == "" {
-			dbFields[i] = strcase.ToSnake(f.Name)
+		dbField := f.Tag.Get("db")
+		if dbField == "-" {
+			continue
+		}
+		if dbField == "" {
+			dbField = strcase.ToSnake(f.Name)
 		}
+		dbFields = append(dbFields, dbField)
 	}
 	return dbFields
 }
@@ -0,0 +1,122 @@
+// The MIT License
+//
+// Copyright (c) 2020 Temporal Technologies Inc.  All rights reserved.
+//
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package tests
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+
+	"go.temporal.io/server/common/config"
+	"go.temporal.io/server/common/dynamicconfig"
+	"go.temporal.io/server/common/future"
+	"go.temporal.io/server/common/log"
+	"go.temporal.io/server/common/metrics"
+	persistencetests "go.temporal.io/server/common/persistence/persistence-tests"
+	"go.temporal.io/server/common/persistence/visibility/store"
+	"go.temporal.io/server/common/persistence/visibility/store/elasticsearch"
+	esclient "go.temporal.io/server/common/persistence/visibility/store/elasticsearch/client"
+	"go.temporal.io/server/common/persistence/visibility/store/elasticsearch/client/clienttest"
+	"go.temporal.io/server/common/resolver"
+	"go.temporal.io/server/common/searchattribute"
+)
+
+const (
+	testFakeElasticsearchStoreName = "fake-elasticsearch"
+	testFakeElasticsearchIndex     = "temporal_visibility_v1_test"
+)
+
+type (
+	// fakeElasticsearchTestCluster configures the visibility store created by
+	// fakeElasticsearchStoreFactory, there is no database to set up.
+	fakeElasticsearchTestCluster struct{}
+
+	fakeElasticsearchStoreFactory struct {
+		client *clienttest.FakeClient
+	}
+
+	// fakeElasticsearchProcessor applies bulk requests to the fake client synchronously.
+	fakeElasticsearchProcessor struct {
+		client *clienttest.FakeClient
+	}
+)
+
+func (c fakeElasticsearchTestCluster) SetupTestDatabase() {}
+
+func (c fakeElasticsearchTestCluster) TearDownTestDatabase() {}
+
+func (c fakeElasticsearchTestCluster) Config() config.Persistence {
+	return config.Persistence{
+		DefaultStore:     testFakeElasticsearchStoreName,
+		VisibilityStore:  testFakeElasticsearchStoreName,
+		NumHistoryShards: 1,
+		DataStores: map[string]config.DataStore{
+			testFakeElasticsearchStoreName: {
+				CustomDataStoreConfig: &config.CustomDatastoreConfig{Name: testFakeElasticsearchStoreName},
+			},
+		},
+	}
+}
+
+func (f *fakeElasticsearchStoreFactory) NewVisibilityStore(
+	_ config.CustomDatastoreConfig,
+	_ resolver.ServiceResolver,
+	_ log.Logger,
+	metricsHandler metrics.Handler,
+) (store.VisibilityStore, error) {
+	return elasticsearch.NewVisibilityStore(
+		f.client,
+		testFakeElasticsearchIndex,
+		searchattribute.NewTestProvider(),
+		searchattribute.NewTestMapperProvider(nil),
+		&fakeElasticsearchProcessor{client: f.client},
+		dynamicconfig.GetDurationPropertyFn(time.Second),
+		dynamicconfig.GetBoolPropertyFnFilteredByNamespace(false),
+		dynamicconfig.GetBoolPropertyFnFilteredByNamespace(true),
+		metricsHandler,
+	), nil
+}
+
+func (p *fakeElasticsearchProcessor) Add(request *esclient.BulkableRequest, _ string) *future.FutureImpl[bool] {
+	f := future.NewFuture[bool]()
+	err := p.client.ApplyBulkRequest(request)
+	f.Set(err == nil, err)
+	return f
+}
+
+func (p *fakeElasticsearchProcessor) Start() {}
+
+func (p *fakeElasticsearchProcessor) Stop() {}
+
+func TestFakeElasticsearchVisibilityTextSearchSuite(t *testing.T) {
+	s := &VisibilityTextSearchSuite{
+		TestBase: persistencetests.NewTestBaseForCluster(fakeElasticsearchTestCluster{}, log.NewTestLogger()),
+		CustomVisibilityStoreFactory: &fakeElasticsearchStoreFactory{
+			client: clienttest.NewFakeClient(searchattribute.TestNameTypeMap),
+		},
+	}
+	suite.Run(t, s)
+}
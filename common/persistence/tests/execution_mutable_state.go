@@ -1684,6 +1684,51 @@ func (s *ExecutionMutableStateSuite) TestDelete_NotExists() {
 	s.AssertMissingFromDB(s.NamespaceID, s.WorkflowID, s.RunID)
 }
 
+func (s *ExecutionMutableStateSuite) TestListConcreteExecutions() {
+	runIDs := make(map[string]struct{})
+	for i := 0; i < 5; i++ {
+		runID := uuid.New().String()
+		newSnapshot := RandomSnapshot(
+			s.NamespaceID,
+			uuid.New().String(),
+			runID,
+			rand.Int63(),
+			enumsspb.WORKFLOW_EXECUTION_STATE_ZOMBIE,
+			enumspb.WORKFLOW_EXECUTION_STATUS_RUNNING,
+			rand.Int63(),
+		)
+		_, err := s.ExecutionManager.CreateWorkflowExecution(s.Ctx, &p.CreateWorkflowExecutionRequest{
+			ShardID: s.ShardID,
+			RangeID: s.RangeID,
+			Mode:    p.CreateWorkflowModeBypassCurrent,
+
+			NewWorkflowSnapshot: *newSnapshot,
+		})
+		s.NoError(err)
+		runIDs[runID] = struct{}{}
+	}
+
+	listed := make(map[string]struct{})
+	var pageToken []byte
+	for {
+		resp, err := s.ExecutionManager.ListConcreteExecutions(s.Ctx, &p.ListConcreteExecutionsRequest{
+			ShardID:   s.ShardID,
+			PageSize:  2,
+			PageToken: pageToken,
+		})
+		s.NoError(err)
+		for _, state := range resp.States {
+			s.Equal(s.NamespaceID, state.ExecutionInfo.NamespaceId)
+			listed[state.ExecutionState.RunId] = struct{}{}
+		}
+		if len(resp.PageToken) == 0 {
+			break
+		}
+		pageToken = resp.PageToken
+	}
+	s.Equal(runIDs, listed)
+}
+
 func (s *ExecutionMutableStateSuite) CreateWorkflow(
 	lastWriteVersion int64,
 	state enumsspb.WorkflowExecutionState,
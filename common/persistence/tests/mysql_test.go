@@ -159,6 +159,13 @@ func TestMySQL8VisibilityPersistenceSuite(t *testing.T) {
 	suite.Run(t, s)
 }
 
+func TestMySQL8VisibilityTextSearchSuite(t *testing.T) {
+	s := &VisibilityTextSearchSuite{
+		TestBase: persistencetests.NewTestBaseWithSQL(persistencetests.GetMySQL8TestClusterOption()),
+	}
+	suite.Run(t, s)
+}
+
 // TODO: Merge persistence-tests into the tests directory.
 
 func TestMySQLHistoryV2PersistenceSuite(t *testing.T) {
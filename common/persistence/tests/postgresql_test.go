@@ -160,6 +160,13 @@ func (p *PostgreSQLSuite) TestPostgreSQL12VisibilityPersistenceSuite() {
 	suite.Run(p.T(), s)
 }
 
+func (p *PostgreSQLSuite) TestPostgreSQL12VisibilityTextSearchSuite() {
+	s := &VisibilityTextSearchSuite{
+		TestBase: persistencetests.NewTestBaseWithSQL(persistencetests.GetPostgreSQL12TestClusterOption()),
+	}
+	suite.Run(p.T(), s)
+}
+
 // TODO: Merge persistence-tests into the tests directory.
 
 func (p *PostgreSQLSuite) TestPostgreSQLHistoryV2PersistenceSuite() {
@@ -386,6 +386,12 @@ func TestSQLiteVisibilityPersistenceSuite(t *testing.T) {
 	suite.Run(t, s)
 }
 
+func TestSQLiteVisibilityTextSearchSuite(t *testing.T) {
+	s := new(VisibilityTextSearchSuite)
+	s.TestBase = persistencetests.NewTestBaseWithSQL(persistencetests.GetSQLiteMemoryTestClusterOption())
+	suite.Run(t, s)
+}
+
 func TestSQLiteHistoryV2PersistenceSuite(t *testing.T) {
 	s := new(persistencetests.HistoryV2PersistenceSuite)
 	s.TestBase = persistencetests.NewTestBaseWithSQL(persistencetests.GetSQLiteMemoryTestClusterOption())
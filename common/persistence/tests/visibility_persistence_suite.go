@@ -34,6 +34,7 @@ import (
 	"github.com/stretchr/testify/require"
 	commonpb "go.temporal.io/api/common/v1"
 	enumspb "go.temporal.io/api/enums/v1"
+	"go.temporal.io/api/serviceerror"
 	workflowpb "go.temporal.io/api/workflow/v1"
 	"go.temporal.io/api/workflowservice/v1"
 
@@ -878,6 +879,63 @@ func (s *VisibilityPersistenceSuite) TestCountGroupByWorkflowExecutions() {
 	s.Equal(int64(5), resp.Count)
 }
 
+func (s *VisibilityPersistenceSuite) TestCountGroupByWorkflowExecutions_MultipleFields() {
+	switch s.VisibilityMgr.GetStoreNames()[0] {
+	case mysql.PluginName, postgresql.PluginName, postgresql.PluginNamePGX, cassandra.CassandraPersistenceName:
+		s.T().Skip("Not supported by standard visibility")
+	}
+
+	testNamespaceUUID := namespace.ID(uuid.New())
+	day := time.Date(2024, 5, 14, 0, 0, 0, 0, time.UTC)
+	for i := 0; i < 3; i++ {
+		s.createOpenWorkflowRecord(testNamespaceUUID, "visibility-workflow-test", "type-a", day.Add(time.Hour), "test-queue")
+	}
+	s.createOpenWorkflowRecord(testNamespaceUUID, "visibility-workflow-test", "type-a", day.Add(30*time.Hour), "test-queue")
+	s.createOpenWorkflowRecord(testNamespaceUUID, "visibility-workflow-test", "type-b", day.Add(2*time.Hour), "test-queue")
+
+	typeAPayload, _ := searchattribute.EncodeValue("type-a", enumspb.INDEXED_VALUE_TYPE_KEYWORD)
+	typeBPayload, _ := searchattribute.EncodeValue("type-b", enumspb.INDEXED_VALUE_TYPE_KEYWORD)
+	dayPayload, _ := searchattribute.EncodeValue(day, enumspb.INDEXED_VALUE_TYPE_DATETIME)
+	nextDayPayload, _ := searchattribute.EncodeValue(day.AddDate(0, 0, 1), enumspb.INDEXED_VALUE_TYPE_DATETIME)
+	resp, err := s.VisibilityMgr.CountWorkflowExecutions(
+		s.ctx,
+		&manager.CountWorkflowExecutionsRequest{
+			NamespaceID: testNamespaceUUID,
+			Query:       "GROUP BY WorkflowType, date_trunc('day', StartTime)",
+		},
+	)
+	s.NoError(err)
+	s.Equal(int64(5), resp.Count)
+	s.Equal(
+		[]*workflowservice.CountWorkflowExecutionsResponse_AggregationGroup{
+			{
+				GroupValues: []*commonpb.Payload{typeAPayload, dayPayload},
+				Count:       int64(3),
+			},
+			{
+				GroupValues: []*commonpb.Payload{typeAPayload, nextDayPayload},
+				Count:       int64(1),
+			},
+			{
+				GroupValues: []*commonpb.Payload{typeBPayload, dayPayload},
+				Count:       int64(1),
+			},
+		},
+		resp.Groups,
+	)
+
+	_, err = s.VisibilityMgr.CountWorkflowExecutions(
+		s.ctx,
+		&manager.CountWorkflowExecutionsRequest{
+			NamespaceID: testNamespaceUUID,
+			Query:       "GROUP BY WorkflowType, date_trunc('day', StartTime)",
+			GroupsLimit: 2,
+		},
+	)
+	var invalidArgumentErr *serviceerror.InvalidArgument
+	s.ErrorAs(err, &invalidArgumentErr)
+}
+
 func (s *VisibilityPersistenceSuite) listWithPagination(namespaceID namespace.ID, pageSize int) []*workflowpb.WorkflowExecutionInfo {
 	var executions []*workflowpb.WorkflowExecutionInfo
 	resp, err := s.VisibilityMgr.ListWorkflowExecutions(s.ctx, &manager.ListWorkflowExecutionsRequestV2{
@@ -0,0 +1,320 @@
+// The MIT License
+//
+// Copyright (c) 2020 Temporal Technologies Inc.  All rights reserved.
+//
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package tests
+
+import (
+	"context"
+	"time"
+
+	"github.com/pborman/uuid"
+	"github.com/stretchr/testify/require"
+	commonpb "go.temporal.io/api/common/v1"
+	enumspb "go.temporal.io/api/enums/v1"
+	"go.temporal.io/api/serviceerror"
+
+	"go.temporal.io/server/common/debug"
+	"go.temporal.io/server/common/dynamicconfig"
+	"go.temporal.io/server/common/log/tag"
+	"go.temporal.io/server/common/metrics"
+	"go.temporal.io/server/common/namespace"
+	"go.temporal.io/server/common/payload"
+	persistencetests "go.temporal.io/server/common/persistence/persistence-tests"
+	"go.temporal.io/server/common/persistence/visibility"
+	"go.temporal.io/server/common/persistence/visibility/manager"
+	"go.temporal.io/server/common/resolver"
+	"go.temporal.io/server/common/searchattribute"
+)
+
+type (
+	// VisibilityTextSearchSuite runs the same queries on Text search attributes against every
+	// visibility store that supports them, so all stores follow the same tokenization rules.
+	VisibilityTextSearchSuite struct {
+		*require.Assertions
+
+		*persistencetests.TestBase
+		VisibilityMgr                manager.VisibilityManager
+		CustomVisibilityStoreFactory visibility.VisibilityStoreFactory
+
+		namespaceID namespace.ID
+		ctx         context.Context
+		cancel      context.CancelFunc
+	}
+)
+
+var textSearchTestRecords = map[string]string{
+	"text-search-1": "Payment service timed out",
+	"text-search-2": "payment-service: retry scheduled",
+	"text-search-3": "Service payment reconciliation",
+	"text-search-4": "Timeout while calling INVENTORY",
+	"text-search-5": "",
+	"text-search-6": "order_id=42 in com.example.Billing",
+	"text-search-7": "Go to DB v2: step 5 of a migration",
+}
+
+// SetupSuite implementation
+func (s *VisibilityTextSearchSuite) SetupSuite() {
+	s.DefaultTestCluster.SetupTestDatabase()
+
+	var err error
+	s.VisibilityMgr, err = visibility.NewManager(
+		s.DefaultTestCluster.Config(),
+		resolver.NewNoopResolver(),
+		s.CustomVisibilityStoreFactory,
+		nil,
+		nil,
+		searchattribute.NewTestProvider(),
+		searchattribute.NewTestMapperProvider(nil),
+		dynamicconfig.GetIntPropertyFn(1000),
+		dynamicconfig.GetIntPropertyFn(1000),
+		dynamicconfig.GetFloatPropertyFn(0.2),
+		dynamicconfig.GetBoolPropertyFnFilteredByNamespace(false),
+		dynamicconfig.GetStringPropertyFn(visibility.SecondaryVisibilityWritingModeOff),
+		dynamicconfig.GetBoolPropertyFnFilteredByNamespace(false),
+		dynamicconfig.GetBoolPropertyFnFilteredByNamespace(true),
+		metrics.NoopMetricsHandler,
+		s.Logger,
+	)
+	if err != nil {
+		// s.NoError doesn't work here.
+		s.Logger.Fatal("Unable to create visibility manager", tag.Error(err))
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second*debug.TimeoutMultiplier)
+	defer cancel()
+	s.namespaceID = namespace.ID(uuid.New())
+	startTime := time.Now().UTC()
+	for workflowID, text := range textSearchTestRecords {
+		request := &manager.RecordWorkflowExecutionStartedRequest{
+			VisibilityRequestBase: &manager.VisibilityRequestBase{
+				NamespaceID: s.namespaceID,
+				Execution: &commonpb.WorkflowExecution{
+					WorkflowId: workflowID,
+					RunId:      uuid.New(),
+				},
+				WorkflowTypeName: "text-search-workflow",
+				StartTime:        startTime,
+				ExecutionTime:    startTime,
+				Status:           enumspb.WORKFLOW_EXECUTION_STATUS_RUNNING,
+				TaskQueue:        "text-search-queue",
+			},
+		}
+		if text != "" {
+			request.SearchAttributes = &commonpb.SearchAttributes{
+				IndexedFields: map[string]*commonpb.Payload{
+					"Text01": payload.EncodeString(text),
+				},
+			}
+		}
+		if err := s.VisibilityMgr.RecordWorkflowExecutionStarted(ctx, request); err != nil {
+			s.Logger.Fatal("Unable to record workflow execution", tag.Error(err))
+		}
+	}
+}
+
+// SetupTest implementation
+func (s *VisibilityTextSearchSuite) SetupTest() {
+	// Have to define our overridden assertions in the test setup. If we did it earlier, s.T() will return nil
+	s.Assertions = require.New(s.T())
+	s.ctx, s.cancel = context.WithTimeout(context.Background(), 30*time.Second*debug.TimeoutMultiplier)
+}
+
+func (s *VisibilityTextSearchSuite) TearDownTest() {
+	s.cancel()
+}
+
+// TearDownSuite implementation
+func (s *VisibilityTextSearchSuite) TearDownSuite() {
+	s.VisibilityMgr.Close()
+	s.DefaultTestCluster.TearDownTestDatabase()
+}
+
+func (s *VisibilityTextSearchSuite) TestMatch() {
+	testCases := []struct {
+		name     string
+		query    string
+		expected []string
+	}{
+		{
+			name:     "token",
+			query:    `Text01 = 'payment'`,
+			expected: []string{"text-search-1", "text-search-2", "text-search-3"},
+		},
+		{
+			name:     "case insensitive",
+			query:    `Text01 = 'Inventory'`,
+			expected: []string{"text-search-4"},
+		},
+		{
+			name:     "any token",
+			query:    `Text01 = 'inventory retry'`,
+			expected: []string{"text-search-2", "text-search-4"},
+		},
+		{
+			name:     "punctuation separates tokens",
+			query:    `Text01 = 'scheduled.reconciliation'`,
+			expected: []string{"text-search-2", "text-search-3"},
+		},
+		{
+			name:     "whole tokens only",
+			query:    `Text01 = 'time'`,
+			expected: nil,
+		},
+		{
+			name:     "underscore separates tokens",
+			query:    `Text01 = 'order'`,
+			expected: []string{"text-search-6"},
+		},
+		{
+			name:     "underscored name",
+			query:    `Text01 = 'order_id'`,
+			expected: []string{"text-search-6"},
+		},
+		{
+			name:     "underscored name phrase",
+			query:    `Text01 = '"order_id"'`,
+			expected: []string{"text-search-6"},
+		},
+		{
+			name:     "dot separates tokens",
+			query:    `Text01 = 'example'`,
+			expected: []string{"text-search-6"},
+		},
+		{
+			name:     "dotted name phrase",
+			query:    `Text01 = '"com.example.billing"'`,
+			expected: []string{"text-search-6"},
+		},
+		{
+			name:     "number",
+			query:    `Text01 = '42'`,
+			expected: []string{"text-search-6"},
+		},
+		{
+			name:     "two character token",
+			query:    `Text01 = 'db'`,
+			expected: []string{"text-search-7"},
+		},
+		{
+			name:     "two character stopword",
+			query:    `Text01 = 'to'`,
+			expected: []string{"text-search-7"},
+		},
+		{
+			name:     "two character alphanumeric token",
+			query:    `Text01 = 'v2'`,
+			expected: []string{"text-search-7"},
+		},
+		{
+			name:     "one character token",
+			query:    `Text01 = 'a'`,
+			expected: []string{"text-search-7"},
+		},
+		{
+			name:     "one digit token",
+			query:    `Text01 = '5'`,
+			expected: []string{"text-search-7"},
+		},
+		{
+			name:     "short token phrase",
+			query:    `Text01 = '"to db"'`,
+			expected: []string{"text-search-7"},
+		},
+		{
+			name:     "phrase",
+			query:    `Text01 = '"payment service"'`,
+			expected: []string{"text-search-1", "text-search-2"},
+		},
+		{
+			name:     "phrase and token",
+			query:    `Text01 = '"service payment" timeout'`,
+			expected: []string{"text-search-3", "text-search-4"},
+		},
+		{
+			name:     "not match",
+			query:    `Text01 != 'payment'`,
+			expected: []string{"text-search-4", "text-search-5", "text-search-6", "text-search-7"},
+		},
+		{
+			name:     "not phrase",
+			query:    `Text01 != '"payment service"'`,
+			expected: []string{"text-search-3", "text-search-4", "text-search-5", "text-search-6", "text-search-7"},
+		},
+		{
+			name:     "combined with other filters",
+			query:    `Text01 = 'payment' AND WorkflowId != 'text-search-1'`,
+			expected: []string{"text-search-2", "text-search-3"},
+		},
+		{
+			name:     "or",
+			query:    `Text01 = '"timed out"' OR Text01 = 'inventory'`,
+			expected: []string{"text-search-1", "text-search-4"},
+		},
+	}
+
+	for _, tc := range testCases {
+		s.Run(tc.name, func() {
+			resp, err := s.VisibilityMgr.ListWorkflowExecutions(s.ctx, &manager.ListWorkflowExecutionsRequestV2{
+				NamespaceID: s.namespaceID,
+				PageSize:    len(textSearchTestRecords) + 1,
+				Query:       tc.query,
+			})
+			s.NoError(err)
+			var workflowIDs []string
+			for _, execution := range resp.Executions {
+				workflowIDs = append(workflowIDs, execution.GetExecution().GetWorkflowId())
+			}
+			s.ElementsMatch(tc.expected, workflowIDs)
+
+			countResp, err := s.VisibilityMgr.CountWorkflowExecutions(s.ctx, &manager.CountWorkflowExecutionsRequest{
+				NamespaceID: s.namespaceID,
+				Query:       tc.query,
+			})
+			s.NoError(err)
+			s.Equal(int64(len(tc.expected)), countResp.Count)
+		})
+	}
+}
+
+func (s *VisibilityTextSearchSuite) TestInvalidQuery() {
+	queries := []string{
+		`Text01 = '"payment service'`,
+		`Text01 = ' -- '`,
+		`Text01 > 'payment'`,
+		`Text01 IN ('payment', 'service')`,
+		`Text01 BETWEEN 'a' AND 'b'`,
+	}
+
+	for _, query := range queries {
+		s.Run(query, func() {
+			_, err := s.VisibilityMgr.ListWorkflowExecutions(s.ctx, &manager.ListWorkflowExecutionsRequestV2{
+				NamespaceID: s.namespaceID,
+				PageSize:    len(textSearchTestRecords),
+				Query:       query,
+			})
+			var invalidArgument *serviceerror.InvalidArgument
+			s.ErrorAs(err, &invalidArgument)
+		})
+	}
+}
@@ -143,6 +143,8 @@ type (
 		NamespaceID namespace.ID
 		Namespace   namespace.Name // namespace.Name is not persisted.
 		Query       string
+		// GroupsLimit is the maximum number of groups of a 'group by' query, the store default when zero.
+		GroupsLimit int
 	}
 
 	// CountWorkflowExecutionsResponse is response to CountWorkflowExecutions
@@ -0,0 +1,75 @@
+// The MIT License
+//
+// Copyright (c) 2020 Temporal Technologies Inc.  All rights reserved.
+//
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package store
+
+import (
+	"bytes"
+	"fmt"
+	"slices"
+
+	"go.temporal.io/api/serviceerror"
+	"go.temporal.io/api/workflowservice/v1"
+
+	"go.temporal.io/server/common/persistence/visibility/manager"
+	"go.temporal.io/server/common/persistence/visibility/store/query"
+)
+
+// GroupsLimit returns the maximum number of groups of a 'group by' CountWorkflowExecutions request. Stores query
+// one more group than the limit, to tell whether it was exceeded.
+func GroupsLimit(request *manager.CountWorkflowExecutionsRequest) int {
+	if request.GroupsLimit <= 0 {
+		return query.DefaultGroupsLimit
+	}
+	return request.GroupsLimit
+}
+
+// FinishCountGroups fails if a 'group by' query has more groups than the limit, and otherwise sorts the groups by
+// descending count, then by their values, so that every visibility store returns the same response.
+func FinishCountGroups(
+	groups []*workflowservice.CountWorkflowExecutionsResponse_AggregationGroup,
+	groupsLimit int,
+) error {
+	if len(groups) > groupsLimit {
+		return serviceerror.NewInvalidArgument(fmt.Sprintf(
+			"query has more than %d groups, add filters to the query or group by fewer fields",
+			groupsLimit,
+		))
+	}
+	slices.SortStableFunc(groups, func(a, b *workflowservice.CountWorkflowExecutionsResponse_AggregationGroup) int {
+		if a.GetCount() != b.GetCount() {
+			if a.GetCount() > b.GetCount() {
+				return -1
+			}
+			return 1
+		}
+		for i := range a.GetGroupValues() {
+			if c := bytes.Compare(a.GetGroupValues()[i].GetData(), b.GetGroupValues()[i].GetData()); c != 0 {
+				return c
+			}
+		}
+		return 0
+	})
+	return nil
+}
@@ -0,0 +1,568 @@
+// The MIT License
+//
+// Copyright (c) 2020 Temporal Technologies Inc.  All rights reserved.
+//
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package clienttest
+
+import (
+	"cmp"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/olivere/elastic/v7"
+	enumspb "go.temporal.io/api/enums/v1"
+
+	"go.temporal.io/server/common/persistence/visibility/store/elasticsearch/client"
+	"go.temporal.io/server/common/persistence/visibility/store/query"
+	"go.temporal.io/server/common/searchattribute"
+)
+
+type (
+	// FakeClient is an in-memory client.Client for tests. It evaluates the subset of the query DSL
+	// used by the visibility store: bool, term, terms, match, match_phrase, prefix, range, exists
+	// and match_all queries. Text fields are tokenized with query.TokenizeText, other fields
+	// match exact values. Fields which aren't in the type map, like NamespaceId, are keywords. Search returns hits sorted by document ID and doesn't paginate.
+	FakeClient struct {
+		typeMap searchattribute.NameTypeMap
+
+		mu      sync.RWMutex
+		indices map[string]map[string]fakeDoc
+	}
+
+	fakeDoc struct {
+		version int64
+		source  json.RawMessage
+		fields  map[string]any
+	}
+)
+
+var _ client.Client = (*FakeClient)(nil)
+
+var errNotSupported = errors.New("not supported by fake Elasticsearch client")
+
+// NewFakeClient returns a new FakeClient which uses typeMap to resolve types of document fields.
+func NewFakeClient(typeMap searchattribute.NameTypeMap) *FakeClient {
+	return &FakeClient{
+		typeMap: typeMap,
+		indices: make(map[string]map[string]fakeDoc),
+	}
+}
+
+// ApplyBulkRequest indexes or deletes a document. Like external versioning in Elasticsearch,
+// a request with a version lower than the version of the stored document is ignored.
+func (c *FakeClient) ApplyBulkRequest(request *client.BulkableRequest) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	docs, ok := c.indices[request.Index]
+	if !ok {
+		docs = make(map[string]fakeDoc)
+		c.indices[request.Index] = docs
+	}
+	if doc, ok := docs[request.ID]; ok && doc.version > request.Version {
+		return nil
+	}
+
+	switch request.RequestType {
+	case client.BulkableRequestTypeIndex:
+		// Round trip the document through JSON to store the values Elasticsearch would return.
+		source, err := json.Marshal(request.Doc)
+		if err != nil {
+			return err
+		}
+		var fields map[string]any
+		if err := json.Unmarshal(source, &fields); err != nil {
+			return err
+		}
+		docs[request.ID] = fakeDoc{version: request.Version, source: source, fields: fields}
+	case client.BulkableRequestTypeDelete:
+		delete(docs, request.ID)
+	default:
+		return fmt.Errorf("unknown bulkable request type: %v", request.RequestType)
+	}
+	return nil
+}
+
+func (c *FakeClient) Get(_ context.Context, index string, docID string) (*elastic.GetResult, error) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	result := &elastic.GetResult{Index: index, Id: docID}
+	if doc, ok := c.indices[index][docID]; ok {
+		result.Found = true
+		result.Source = doc.source
+		result.Version = &doc.version
+	}
+	return result, nil
+}
+
+func (c *FakeClient) Search(_ context.Context, p *client.SearchParameters) (*elastic.SearchResult, error) {
+	if p.ScrollID != "" || p.PointInTime != nil || len(p.SearchAfter) > 0 {
+		return nil, fmt.Errorf("pagination is %w", errNotSupported)
+	}
+	ids, err := c.search(p.Index, p.Query)
+	if err != nil {
+		return nil, err
+	}
+	totalHits := int64(len(ids))
+	if p.PageSize > 0 && len(ids) > p.PageSize {
+		ids = ids[:p.PageSize]
+	}
+
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	hits := make([]*elastic.SearchHit, 0, len(ids))
+	for _, id := range ids {
+		hits = append(hits, &elastic.SearchHit{
+			Index:  p.Index,
+			Id:     id,
+			Source: c.indices[p.Index][id].source,
+		})
+	}
+	return &elastic.SearchResult{
+		Hits: &elastic.SearchHits{
+			TotalHits: &elastic.TotalHits{Value: totalHits, Relation: "eq"},
+			Hits:      hits,
+		},
+	}, nil
+}
+
+func (c *FakeClient) Count(_ context.Context, index string, q elastic.Query) (int64, error) {
+	ids, err := c.search(index, q)
+	if err != nil {
+		return 0, err
+	}
+	return int64(len(ids)), nil
+}
+
+func (c *FakeClient) CountGroupBy(
+	_ context.Context,
+	_ string,
+	_ elastic.Query,
+	_ string,
+	_ elastic.Aggregation,
+) (*elastic.SearchResult, error) {
+	return nil, fmt.Errorf("aggregations are %w", errNotSupported)
+}
+
+func (c *FakeClient) RunBulkProcessor(_ context.Context, _ *client.BulkProcessorParameters) (client.BulkProcessor, error) {
+	return nil, fmt.Errorf("bulk processor is %w, use ApplyBulkRequest", errNotSupported)
+}
+
+func (c *FakeClient) PutMapping(_ context.Context, _ string, _ map[string]enumspb.IndexedValueType) (bool, error) {
+	return false, fmt.Errorf("mappings are %w", errNotSupported)
+}
+
+func (c *FakeClient) WaitForYellowStatus(_ context.Context, _ string) (string, error) {
+	return "green", nil
+}
+
+func (c *FakeClient) GetMapping(_ context.Context, _ string) (map[string]string, error) {
+	return nil, fmt.Errorf("mappings are %w", errNotSupported)
+}
+
+func (c *FakeClient) OpenScroll(_ context.Context, _ *client.SearchParameters, _ string) (*elastic.SearchResult, error) {
+	return nil, fmt.Errorf("scroll is %w", errNotSupported)
+}
+
+func (c *FakeClient) Scroll(_ context.Context, _ string, _ string) (*elastic.SearchResult, error) {
+	return nil, fmt.Errorf("scroll is %w", errNotSupported)
+}
+
+func (c *FakeClient) CloseScroll(_ context.Context, _ string) error {
+	return fmt.Errorf("scroll is %w", errNotSupported)
+}
+
+func (c *FakeClient) IsPointInTimeSupported(_ context.Context) bool {
+	return false
+}
+
+func (c *FakeClient) OpenPointInTime(_ context.Context, _ string, _ string) (string, error) {
+	return "", fmt.Errorf("point in time is %w", errNotSupported)
+}
+
+func (c *FakeClient) ClosePointInTime(_ context.Context, _ string) (bool, error) {
+	return false, fmt.Errorf("point in time is %w", errNotSupported)
+}
+
+// search returns IDs of documents in the index matching the query, sorted by ID.
+func (c *FakeClient) search(index string, q elastic.Query) ([]string, error) {
+	var source any = map[string]any{"match_all": map[string]any{}}
+	if q != nil {
+		var err error
+		if source, err = q.Source(); err != nil {
+			return nil, err
+		}
+	}
+	// Evaluate the JSON representation, which is what Elasticsearch receives.
+	data, err := json.Marshal(source)
+	if err != nil {
+		return nil, err
+	}
+	var jsonQuery map[string]any
+	if err := json.Unmarshal(data, &jsonQuery); err != nil {
+		return nil, err
+	}
+
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	var ids []string
+	for id, doc := range c.indices[index] {
+		match, err := c.evaluate(jsonQuery, doc.fields)
+		if err != nil {
+			return nil, err
+		}
+		if match {
+			ids = append(ids, id)
+		}
+	}
+	sort.Strings(ids)
+	return ids, nil
+}
+
+func (c *FakeClient) evaluate(q map[string]any, doc map[string]any) (bool, error) {
+	if len(q) != 1 {
+		return false, fmt.Errorf("query must have exactly one type: %v", q)
+	}
+	for queryType, body := range q {
+		switch queryType {
+		case "match_all":
+			return true, nil
+		case "bool":
+			return c.evaluateBool(body, doc)
+		case "exists":
+			params, err := asObject(body)
+			if err != nil {
+				return false, err
+			}
+			field, _ := params["field"].(string)
+			_, ok := doc[field]
+			return ok, nil
+		case "term", "match", "match_phrase", "prefix":
+			field, value, err := fieldQuery(body, queryType)
+			if err != nil {
+				return false, err
+			}
+			return c.evaluateFieldValue(queryType, field, value, doc[field])
+		case "terms":
+			params, err := asObject(body)
+			if err != nil {
+				return false, err
+			}
+			for field, values := range params {
+				list, ok := values.([]any)
+				if !ok {
+					return false, fmt.Errorf("terms query on field %q must have a list of values", field)
+				}
+				for _, value := range list {
+					if match, err := c.evaluateFieldValue("term", field, value, doc[field]); err != nil || match {
+						return match, err
+					}
+				}
+			}
+			return false, nil
+		case "range":
+			return c.evaluateRange(body, doc)
+		default:
+			return false, fmt.Errorf("query type %q is %w", queryType, errNotSupported)
+		}
+	}
+	return false, nil
+}
+
+func (c *FakeClient) evaluateBool(body any, doc map[string]any) (bool, error) {
+	params, err := asObject(body)
+	if err != nil {
+		return false, err
+	}
+	clauses := func(name string) ([]map[string]any, error) {
+		switch v := params[name].(type) {
+		case nil:
+			return nil, nil
+		case map[string]any:
+			return []map[string]any{v}, nil
+		case []any:
+			result := make([]map[string]any, 0, len(v))
+			for _, clause := range v {
+				q, err := asObject(clause)
+				if err != nil {
+					return nil, err
+				}
+				result = append(result, q)
+			}
+			return result, nil
+		default:
+			return nil, fmt.Errorf("invalid %q clause of bool query: %v", name, v)
+		}
+	}
+
+	var required []map[string]any
+	for _, name := range []string{"must", "filter"} {
+		qs, err := clauses(name)
+		if err != nil {
+			return false, err
+		}
+		required = append(required, qs...)
+	}
+	for _, q := range required {
+		if match, err := c.evaluate(q, doc); err != nil || !match {
+			return false, err
+		}
+	}
+
+	mustNot, err := clauses("must_not")
+	if err != nil {
+		return false, err
+	}
+	for _, q := range mustNot {
+		if match, err := c.evaluate(q, doc); err != nil || match {
+			return false, err
+		}
+	}
+
+	should, err := clauses("should")
+	if err != nil {
+		return false, err
+	}
+	// Without must or filter clauses, at least one should clause must match.
+	minimumShouldMatch := 0
+	if len(required) == 0 && len(should) > 0 {
+		minimumShouldMatch = 1
+	}
+	if v, ok := params["minimum_should_match"]; ok {
+		if minimumShouldMatch, err = strconv.Atoi(fmt.Sprint(v)); err != nil {
+			return false, fmt.Errorf("minimum_should_match %v is %w", v, errNotSupported)
+		}
+	}
+	matched := 0
+	for _, q := range should {
+		match, err := c.evaluate(q, doc)
+		if err != nil {
+			return false, err
+		}
+		if match {
+			matched++
+		}
+	}
+	return matched >= minimumShouldMatch, nil
+}
+
+func (c *FakeClient) evaluateRange(body any, doc map[string]any) (bool, error) {
+	params, err := asObject(body)
+	if err != nil {
+		return false, err
+	}
+	for field, v := range params {
+		bounds, err := asObject(v)
+		if err != nil {
+			return false, err
+		}
+		fieldType := c.fieldType(field)
+		docValue, ok := doc[field]
+		if !ok {
+			return false, nil
+		}
+		if from := bounds["from"]; from != nil {
+			order, err := compareValues(fieldType, docValue, from)
+			if err != nil {
+				return false, err
+			}
+			if order < 0 || order == 0 && bounds["include_lower"] == false {
+				return false, nil
+			}
+		}
+		if to := bounds["to"]; to != nil {
+			order, err := compareValues(fieldType, docValue, to)
+			if err != nil {
+				return false, err
+			}
+			if order > 0 || order == 0 && bounds["include_upper"] == false {
+				return false, nil
+			}
+		}
+	}
+	return true, nil
+}
+
+// evaluateFieldValue evaluates a term, match, match_phrase or prefix query on a field value.
+// Each value of a list field is matched separately.
+func (c *FakeClient) evaluateFieldValue(queryType string, field string, value any, docValue any) (bool, error) {
+	if docValue == nil {
+		return false, nil
+	}
+	if list, ok := docValue.([]any); ok {
+		for _, item := range list {
+			if match, err := c.evaluateFieldValue(queryType, field, value, item); err != nil || match {
+				return match, err
+			}
+		}
+		return false, nil
+	}
+
+	fieldType := c.fieldType(field)
+	if fieldType == enumspb.INDEXED_VALUE_TYPE_TEXT && queryType != "term" {
+		text, ok := value.(string)
+		if !ok {
+			return false, fmt.Errorf("%s query on Text field %q must have a string value", queryType, field)
+		}
+		docText, _ := docValue.(string)
+		docTokens := query.TokenizeText(docText)
+		queryTokens := query.TokenizeText(text)
+		switch queryType {
+		case "match":
+			for _, token := range queryTokens {
+				if slices.Contains(docTokens, token) {
+					return true, nil
+				}
+			}
+			return false, nil
+		case "match_phrase":
+			return containsPhrase(docTokens, queryTokens), nil
+		}
+		return false, fmt.Errorf("%s query on Text field %q is %w", queryType, field, errNotSupported)
+	}
+
+	if queryType == "prefix" {
+		prefix, ok := value.(string)
+		if !ok {
+			return false, fmt.Errorf("prefix query on field %q must have a string value", field)
+		}
+		docString, _ := docValue.(string)
+		return strings.HasPrefix(docString, prefix), nil
+	}
+	// Term and match queries on fields which aren't analyzed match exact values.
+	order, err := compareValues(fieldType, docValue, value)
+	if err != nil {
+		return false, err
+	}
+	return order == 0, nil
+}
+
+func (c *FakeClient) fieldType(field string) enumspb.IndexedValueType {
+	fieldType, err := c.typeMap.GetType(field)
+	if err != nil {
+		return enumspb.INDEXED_VALUE_TYPE_KEYWORD
+	}
+	return fieldType
+}
+
+func containsPhrase(tokens []string, phrase []string) bool {
+	if len(phrase) == 0 {
+		return false
+	}
+	for i := 0; i+len(phrase) <= len(tokens); i++ {
+		if slices.Equal(tokens[i:i+len(phrase)], phrase) {
+			return true
+		}
+	}
+	return false
+}
+
+func compareValues(fieldType enumspb.IndexedValueType, a any, b any) (int, error) {
+	switch fieldType {
+	case enumspb.INDEXED_VALUE_TYPE_INT, enumspb.INDEXED_VALUE_TYPE_DOUBLE:
+		x, err := toFloat(a)
+		if err != nil {
+			return 0, err
+		}
+		y, err := toFloat(b)
+		if err != nil {
+			return 0, err
+		}
+		return cmp.Compare(x, y), nil
+	case enumspb.INDEXED_VALUE_TYPE_DATETIME:
+		x, err := toTime(a)
+		if err != nil {
+			return 0, err
+		}
+		y, err := toTime(b)
+		if err != nil {
+			return 0, err
+		}
+		return x.Compare(y), nil
+	case enumspb.INDEXED_VALUE_TYPE_BOOL:
+		return strings.Compare(strings.ToLower(fmt.Sprint(a)), strings.ToLower(fmt.Sprint(b))), nil
+	default:
+		return strings.Compare(fmt.Sprint(a), fmt.Sprint(b)), nil
+	}
+}
+
+func toFloat(v any) (float64, error) {
+	switch v := v.(type) {
+	case float64:
+		return v, nil
+	case string:
+		return strconv.ParseFloat(v, 64)
+	default:
+		return 0, fmt.Errorf("invalid numeric value: %v", v)
+	}
+}
+
+func toTime(v any) (time.Time, error) {
+	s, ok := v.(string)
+	if !ok {
+		return time.Time{}, fmt.Errorf("invalid datetime value: %v", v)
+	}
+	return time.Parse(time.RFC3339Nano, s)
+}
+
+func asObject(v any) (map[string]any, error) {
+	obj, ok := v.(map[string]any)
+	if !ok {
+		return nil, fmt.Errorf("expected JSON object, got: %v", v)
+	}
+	return obj, nil
+}
+
+// fieldQuery returns the field and the value of queries like {"field": value} and
+// {"field": {"query": value}}.
+func fieldQuery(body any, queryType string) (string, any, error) {
+	params, err := asObject(body)
+	if err != nil {
+		return "", nil, err
+	}
+	if len(params) != 1 {
+		return "", nil, fmt.Errorf("%s query must have exactly one field: %v", queryType, params)
+	}
+	for field, v := range params {
+		obj, ok := v.(map[string]any)
+		if !ok {
+			return field, v, nil
+		}
+		for _, key := range []string{"query", "value", queryType} {
+			if value, ok := obj[key]; ok {
+				return field, value, nil
+			}
+		}
+		return "", nil, fmt.Errorf("%s query on field %q has no value: %v", queryType, field, obj)
+	}
+	return "", nil, nil
+}
@@ -47,7 +47,10 @@ var errorCases = map[string]string{
 	"select * from a where 1 = 1":            query.InvalidExpressionErrMessage,
 	"select * from a where 1=a":              query.InvalidExpressionErrMessage,
 	"select * from a where zz(k=2)":          query.NotSupportedErrMessage,
-	"select * from a group by k, m":          query.NotSupportedErrMessage,
+	"select * from a group by k, l, m, n":    query.NotSupportedErrMessage,
+	"select * from a group by k, k":          query.InvalidExpressionErrMessage,
+	"select * from a group by max(k)":        query.NotSupportedErrMessage,
+	"select * from a group by date_trunc(k)": query.InvalidExpressionErrMessage,
 	"select * from a group by k order by id": query.NotSupportedErrMessage,
 	"invalid query":                          query.MalformedSqlQueryErrMessage,
 	"select * from a where  a= 1 and multi_match(zz=1, query='this is a test', fields=(title,title.origin), type=phrase)": query.NotSupportedErrMessage,
@@ -116,8 +119,9 @@ var supportedWhereOrderCases = map[string]struct {
 }
 
 var supportedWhereGroupByCases = map[string]struct {
-	query   string
-	groupBy []string
+	query       string
+	groupBy     []string
+	timeBuckets map[string]string
 }{
 	"group by status": {
 		query:   ``,
@@ -127,6 +131,11 @@ var supportedWhereGroupByCases = map[string]struct {
 		query:   `{"bool":{"filter":{"match":{"id":{"query":1}}}}}`,
 		groupBy: []string{"status"},
 	},
+	"group by status, type, date_trunc('Day', start)": {
+		query:       ``,
+		groupBy:     []string{"status", "type", "start"},
+		timeBuckets: map[string]string{"start": "day"},
+	},
 }
 
 func TestSupportedSelectWhere(t *testing.T) {
@@ -196,6 +205,7 @@ func TestSupportedSelectWhereGroupBy(t *testing.T) {
 			assert.Nil(t, queryParams.Query)
 		}
 		assert.Equal(t, expectedJson.groupBy, queryParams.GroupBy)
+		assert.Equal(t, expectedJson.timeBuckets, queryParams.TimeBuckets)
 	}
 }
 
@@ -111,12 +111,9 @@ func (ni *nameInterceptor) Name(name string, usage query.FieldNameUsage) (string
 				enumspb.INDEXED_VALUE_TYPE_KEYWORD.String(),
 			)
 		}
-	case query.FieldNameGroupBy:
-		if fieldName != searchattribute.ExecutionStatus {
-			return "", query.NewConverterError(
-				"'group by' clause is only supported for %s search attribute",
-				searchattribute.ExecutionStatus,
-			)
+	case query.FieldNameGroupBy, query.FieldNameGroupByTimeBucket:
+		if err := query.ValidateGroupByFieldType(name, fieldType, usage); err != nil {
+			return "", err
 		}
 	}
 
@@ -213,6 +210,13 @@ func validateValueType(name string, value any, fieldType enumspb.IndexedValueTyp
 			return nil, query.NewConverterError(
 				"invalid value for search attribute %s of type %s: %#v", name, fieldType.String(), value)
 		}
+	case enumspb.INDEXED_VALUE_TYPE_TEXT:
+		v, isString := value.(string)
+		if !isString {
+			return nil, query.NewConverterError(
+				"invalid value for search attribute %s of type %s: %#v", name, fieldType.String(), value)
+		}
+		return query.ParseTextQuery(v)
 	case enumspb.INDEXED_VALUE_TYPE_DATETIME:
 		switch v := value.(type) {
 		case int64:
@@ -60,6 +60,10 @@ const (
 	delimiter                    = "~"
 	scrollKeepAliveInterval      = "1m"
 	pointInTimeKeepAliveInterval = "1m"
+	// timeBucketFormat is the format of date_histogram keys, parsed as RFC 3339.
+	timeBucketFormat = "strict_date_optional_time"
+	// groupByAggregationName is the name of the aggregation counting the groups of a 'group by' query.
+	groupByAggregationName = "group_by"
 )
 
 type (
@@ -621,7 +625,7 @@ func (s *visibilityStore) CountWorkflowExecutions(
 	}
 
 	if len(queryParams.GroupBy) > 0 {
-		return s.countGroupByWorkflowExecutions(ctx, queryParams)
+		return s.countGroupByWorkflowExecutions(ctx, queryParams, store.GroupsLimit(request))
 	}
 
 	count, err := s.esClient.Count(ctx, s.index, queryParams.Query)
@@ -636,44 +640,59 @@ func (s *visibilityStore) CountWorkflowExecutions(
 func (s *visibilityStore) countGroupByWorkflowExecutions(
 	ctx context.Context,
 	queryParams *query.QueryParams,
+	groupsLimit int,
 ) (*manager.CountWorkflowExecutionsResponse, error) {
 	groupByFields := queryParams.GroupBy
 
-	// Elasticsearch aggregation is nested. so need to loop backwards to build it.
-	// Example: when grouping by (field1, field2), the object looks like
+	// Groups are counted with a composite aggregation, which returns one bucket per group, so the number of
+	// buckets is bounded by its size however many fields are grouped by, and doesn't hit the search.max_buckets
+	// setting of the cluster. The size is one more than the limit, to tell whether it was exceeded.
+	// Example: when grouping by (field1, date_trunc('day', field2)), the object looks like
 	// {
 	//   "aggs": {
-	//     "field1": {
-	//       "terms": {
-	//         "field": "field1"
-	//       },
-	//       "aggs": {
-	//         "field2": {
-	//           "terms": {
-	//             "field": "field2"
-	//           }
-	//         }
+	//     "group_by": {
+	//       "composite": {
+	//         "size": 1001,
+	//         "sources": [
+	//           { "field1": { "terms": { "field": "field1" } } },
+	//           { "field2": { "date_histogram": { "field": "field2", "calendar_interval": "day" } } }
+	//         ]
 	//       }
 	//     }
 	//   }
 	// }
-	termsAgg := elastic.NewTermsAggregation().Field(groupByFields[len(groupByFields)-1])
-	for i := len(groupByFields) - 2; i >= 0; i-- {
-		termsAgg = elastic.NewTermsAggregation().
-			Field(groupByFields[i]).
-			SubAggregation(groupByFields[i+1], termsAgg)
+	sources := make([]elastic.CompositeAggregationValuesSource, len(groupByFields))
+	for i, field := range groupByFields {
+		if timeBucket, ok := queryParams.TimeBuckets[field]; ok {
+			sources[i] = elastic.NewCompositeAggregationDateHistogramValuesSource(field).
+				Field(field).
+				CalendarInterval(timeBucket).
+				Format(timeBucketFormat)
+			continue
+		}
+		sources[i] = elastic.NewCompositeAggregationTermsValuesSource(field).Field(field)
 	}
+	agg := elastic.NewCompositeAggregation().
+		Sources(sources...).
+		Size(groupsLimit + 1)
 	esResponse, err := s.esClient.CountGroupBy(
 		ctx,
 		s.index,
 		queryParams.Query,
-		groupByFields[0],
-		termsAgg,
+		groupByAggregationName,
+		agg,
 	)
 	if err != nil {
 		return nil, err
 	}
-	return s.parseCountGroupByResponse(esResponse, groupByFields)
+	response, err := s.parseCountGroupByResponse(esResponse, groupByFields)
+	if err != nil {
+		return nil, err
+	}
+	if err := store.FinishCountGroups(response.Groups, groupsLimit); err != nil {
+		return nil, err
+	}
+	return response, nil
 }
 
 func (s *visibilityStore) GetWorkflowExecution(
@@ -1197,10 +1216,7 @@ func (s *visibilityStore) parseESDoc(docID string, docSource json.RawMessage, sa
 	return record, nil
 }
 
-// Elasticsearch aggregation groups are returned as nested object.
-// This function flattens the response into rows.
-//
-//nolint:revive // cognitive complexity 27 (> max enabled 25)
+// parseCountGroupByResponse turns the buckets of the composite aggregation into groups.
 func (s *visibilityStore) parseCountGroupByResponse(
 	searchResult *elastic.SearchResult,
 	groupByFields []string,
@@ -1221,65 +1237,42 @@ func (s *visibilityStore) parseCountGroupByResponse(
 		groupByTypes[i] = tp
 	}
 
-	parseJsonNumber := func(val any) (int64, error) {
-		numberVal, isNumber := val.(json.Number)
-		if !isNumber {
-			return 0, fmt.Errorf("%w: expected json.Number, got %T", errUnexpectedJSONFieldType, val)
-		}
-		return numberVal.Int64()
+	var aggResult struct {
+		Buckets []struct {
+			Key      map[string]any `json:"key"`
+			DocCount json.Number    `json:"doc_count"`
+		} `json:"buckets"`
+	}
+	dec := json.NewDecoder(bytes.NewReader(searchResult.Aggregations[groupByAggregationName]))
+	dec.UseNumber()
+	if err := dec.Decode(&aggResult); err != nil {
+		return nil, serviceerror.NewInternal(fmt.Sprintf("unable to unmarshal json response: %v", err))
 	}
 
-	var parseInternal func(map[string]any, []*commonpb.Payload) error
-	parseInternal = func(aggs map[string]any, bucketValues []*commonpb.Payload) error {
-		if len(bucketValues) == len(groupByFields) {
-			cnt, err := parseJsonNumber(aggs["doc_count"])
-			if err != nil {
-				return fmt.Errorf("Unable to parse 'doc_count' field: %w", err)
-			}
-			groupValues := make([]*commonpb.Payload, len(groupByFields))
-			for i := range bucketValues {
-				groupValues[i] = bucketValues[i]
-			}
-			response.Groups = append(
-				response.Groups,
-				&workflowservice.CountWorkflowExecutionsResponse_AggregationGroup{
-					GroupValues: groupValues,
-					Count:       cnt,
-				},
-			)
-			response.Count += cnt
-			return nil
+	for _, bucket := range aggResult.Buckets {
+		cnt, err := bucket.DocCount.Int64()
+		if err != nil {
+			return nil, fmt.Errorf("Unable to parse 'doc_count' field: %w", err)
 		}
-
-		index := len(bucketValues)
-		fieldName := groupByFields[index]
-		buckets := aggs[fieldName].(map[string]any)["buckets"].([]any)
-		for i := range buckets {
-			bucket := buckets[i].(map[string]any)
-			value, err := finishParseJSONValue(bucket["key"], groupByTypes[index])
-			if err != nil {
-				return fmt.Errorf("Failed to parse value %v: %w", bucket["key"], err)
-			}
-			payload, err := searchattribute.EncodeValue(value, groupByTypes[index])
+		groupValues := make([]*commonpb.Payload, len(groupByFields))
+		for i, fieldName := range groupByFields {
+			value, err := finishParseJSONValue(bucket.Key[fieldName], groupByTypes[i])
 			if err != nil {
-				return fmt.Errorf("Failed to encode value %v: %w", value, err)
+				return nil, fmt.Errorf("Failed to parse value %v: %w", bucket.Key[fieldName], err)
 			}
-			err = parseInternal(bucket, append(bucketValues, payload))
+			groupValues[i], err = searchattribute.EncodeValue(value, groupByTypes[i])
 			if err != nil {
-				return err
+				return nil, fmt.Errorf("Failed to encode value %v: %w", value, err)
 			}
 		}
-		return nil
-	}
-
-	var bucketsJson map[string]any
-	dec := json.NewDecoder(bytes.NewReader(searchResult.Aggregations[groupByFields[0]]))
-	dec.UseNumber()
-	if err := dec.Decode(&bucketsJson); err != nil {
-		return nil, serviceerror.NewInternal(fmt.Sprintf("unable to unmarshal json response: %v", err))
-	}
-	if err := parseInternal(map[string]any{groupByFields[0]: bucketsJson}, nil); err != nil {
-		return nil, err
+		response.Groups = append(
+			response.Groups,
+			&workflowservice.CountWorkflowExecutionsResponse_AggregationGroup{
+				GroupValues: groupValues,
+				Count:       cnt,
+			},
+		)
+		response.Count += cnt
 	}
 	return response, nil
 }
@@ -696,6 +696,24 @@ func (s *ESVisibilitySuite) Test_convertQuery() {
 	s.IsType(&serviceerror.InvalidArgument{}, err)
 	s.Equal(err.(*serviceerror.InvalidArgument).Error(), "invalid query: unable to convert 'order by' column name: unable to sort by field of Text type, use field of type Keyword")
 
+	query = `CustomTextField = 'Timeout, "payment service"'`
+	queryParams, err = s.visibilityStore.convertQuery(testNamespace, testNamespaceID, query)
+	s.NoError(err)
+	s.Equal(`{"bool":{"filter":[{"term":{"NamespaceId":"bfd5c907-f899-4baf-a7b2-2ab85e623ebd"}},{"bool":{"minimum_should_match":"1","should":[{"match":{"CustomTextField":{"query":"timeout"}}},{"match_phrase":{"CustomTextField":{"query":"payment service"}}}]}}],"must_not":{"exists":{"field":"TemporalNamespaceDivision"}}}}`, s.queryToJSON(queryParams.Query))
+	s.Nil(queryParams.Sorter)
+
+	query = `CustomTextField != 'payment-service'`
+	queryParams, err = s.visibilityStore.convertQuery(testNamespace, testNamespaceID, query)
+	s.NoError(err)
+	s.Equal(`{"bool":{"filter":[{"term":{"NamespaceId":"bfd5c907-f899-4baf-a7b2-2ab85e623ebd"}},{"bool":{"must_not":{"match":{"CustomTextField":{"query":"payment service"}}}}}],"must_not":{"exists":{"field":"TemporalNamespaceDivision"}}}}`, s.queryToJSON(queryParams.Query))
+	s.Nil(queryParams.Sorter)
+
+	query = `CustomTextField = '"payment service'`
+	_, err = s.visibilityStore.convertQuery(testNamespace, testNamespaceID, query)
+	s.Error(err)
+	s.IsType(&serviceerror.InvalidArgument{}, err)
+	s.Contains(err.Error(), "unbalanced double quotes in value of Text type search attribute")
+
 	query = `order by CustomIntField asc`
 	queryParams, err = s.visibilityStore.convertQuery(testNamespace, testNamespaceID, query)
 	s.NoError(err)
@@ -1402,14 +1420,16 @@ func (s *ESVisibilitySuite) TestCountWorkflowExecutions_GroupBy() {
 			elastic.NewBoolQuery().
 				Filter(elastic.NewTermQuery(searchattribute.NamespaceID, testNamespaceID.String())).
 				MustNot(namespaceDivisionExists),
-			searchattribute.ExecutionStatus,
-			elastic.NewTermsAggregation().Field(searchattribute.ExecutionStatus),
+			groupByAggregationName,
+			elastic.NewCompositeAggregation().
+				Sources(elastic.NewCompositeAggregationTermsValuesSource(searchattribute.ExecutionStatus).Field(searchattribute.ExecutionStatus)).
+				Size(query.DefaultGroupsLimit+1),
 		).
 		Return(
 			&elastic.SearchResult{
 				Aggregations: map[string]json.RawMessage{
-					searchattribute.ExecutionStatus: json.RawMessage(
-						`{"buckets":[{"key":"Completed","doc_count":100},{"key":"Running","doc_count":10}]}`,
+					groupByAggregationName: json.RawMessage(
+						`{"buckets":[{"key":{"ExecutionStatus":"Completed"},"doc_count":100},{"key":{"ExecutionStatus":"Running"},"doc_count":10}]}`,
 					),
 				},
 			},
@@ -1442,18 +1462,32 @@ func (s *ESVisibilitySuite) TestCountWorkflowExecutions_GroupBy() {
 		resp),
 	)
 
-	// test only allowed to group by a single field
-	request.Query = "GROUP BY ExecutionStatus, WorkflowType"
+	// test group by is limited to MaxGroupByFields fields
+	request.Query = "GROUP BY ExecutionStatus, WorkflowType, TaskQueue, WorkflowId"
 	resp, err = s.visibilityStore.CountWorkflowExecutions(context.Background(), request)
 	s.Error(err)
-	s.Contains(err.Error(), "'group by' clause supports only a single field")
+	s.Contains(err.Error(), "'group by' clause supports at most 3 fields")
 	s.Nil(resp)
 
-	// test only allowed to group by ExecutionStatus
-	request.Query = "GROUP BY WorkflowType"
+	// test only allowed to group by keyword search attributes
+	request.Query = "GROUP BY StartTime"
 	resp, err = s.visibilityStore.CountWorkflowExecutions(context.Background(), request)
 	s.Error(err)
-	s.Contains(err.Error(), "'group by' clause is only supported for ExecutionStatus search attribute")
+	s.Contains(err.Error(), "'group by' clause is only supported for search attributes of type Keyword")
+	s.Nil(resp)
+
+	// test time buckets are only allowed for datetime search attributes
+	request.Query = "GROUP BY date_trunc('day', WorkflowType)"
+	resp, err = s.visibilityStore.CountWorkflowExecutions(context.Background(), request)
+	s.Error(err)
+	s.Contains(err.Error(), "'date_trunc' is only supported for search attributes of type Datetime")
+	s.Nil(resp)
+
+	// test time bucket units
+	request.Query = "GROUP BY date_trunc('fortnight', StartTime)"
+	resp, err = s.visibilityStore.CountWorkflowExecutions(context.Background(), request)
+	s.Error(err)
+	s.Contains(err.Error(), "unsupported time unit 'fortnight'")
 	s.Nil(resp)
 }
 
@@ -1474,10 +1508,15 @@ func (s *ESVisibilitySuite) TestCountGroupByWorkflowExecutions() {
 	wfId4Payload, _ := searchattribute.EncodeValue("wf-id-4", enumspb.INDEXED_VALUE_TYPE_KEYWORD)
 	wfId5Payload, _ := searchattribute.EncodeValue("wf-id-5", enumspb.INDEXED_VALUE_TYPE_KEYWORD)
 
+	day1 := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+	day2 := time.Date(2024, 3, 2, 0, 0, 0, 0, time.UTC)
+	day1Payload, _ := searchattribute.EncodeValue(day1, enumspb.INDEXED_VALUE_TYPE_DATETIME)
+	day2Payload, _ := searchattribute.EncodeValue(day2, enumspb.INDEXED_VALUE_TYPE_DATETIME)
+
 	testCases := []struct {
 		name         string
 		groupBy      []string
-		aggName      string
+		timeBuckets  map[string]string
 		agg          elastic.Aggregation
 		mockResponse *elastic.SearchResult
 		response     *manager.CountWorkflowExecutionsResponse
@@ -1485,19 +1524,21 @@ func (s *ESVisibilitySuite) TestCountGroupByWorkflowExecutions() {
 		{
 			name:    "group by one field",
 			groupBy: []string{searchattribute.ExecutionStatus},
-			aggName: searchattribute.ExecutionStatus,
-			agg:     elastic.NewTermsAggregation().Field(searchattribute.ExecutionStatus),
+			agg: elastic.NewCompositeAggregation().
+				Sources(elastic.NewCompositeAggregationTermsValuesSource(searchattribute.ExecutionStatus).Field(searchattribute.ExecutionStatus)).
+				Size(query.DefaultGroupsLimit + 1),
 			mockResponse: &elastic.SearchResult{
 				Aggregations: map[string]json.RawMessage{
-					searchattribute.ExecutionStatus: json.RawMessage(
+					groupByAggregationName: json.RawMessage(
 						`{
+							"after_key": {"ExecutionStatus": "Running"},
 							"buckets":[
 								{
-									"key": "Completed",
+									"key": {"ExecutionStatus": "Completed"},
 									"doc_count": 100
 								},
 								{
-									"key": "Running",
+									"key": {"ExecutionStatus": "Running"},
 									"doc_count": 10
 								}
 							]
@@ -1523,47 +1564,32 @@ func (s *ESVisibilitySuite) TestCountGroupByWorkflowExecutions() {
 		{
 			name:    "group by two fields",
 			groupBy: []string{searchattribute.ExecutionStatus, searchattribute.WorkflowType},
-			aggName: searchattribute.ExecutionStatus,
-			agg: elastic.NewTermsAggregation().Field(searchattribute.ExecutionStatus).SubAggregation(
-				searchattribute.WorkflowType,
-				elastic.NewTermsAggregation().Field(searchattribute.WorkflowType),
-			),
+			agg: elastic.NewCompositeAggregation().
+				Sources(
+					elastic.NewCompositeAggregationTermsValuesSource(searchattribute.ExecutionStatus).Field(searchattribute.ExecutionStatus),
+					elastic.NewCompositeAggregationTermsValuesSource(searchattribute.WorkflowType).Field(searchattribute.WorkflowType),
+				).
+				Size(query.DefaultGroupsLimit + 1),
 			mockResponse: &elastic.SearchResult{
 				Aggregations: map[string]json.RawMessage{
-					searchattribute.ExecutionStatus: json.RawMessage(
+					groupByAggregationName: json.RawMessage(
 						`{
 							"buckets":[
 								{
-									"key": "Completed",
-									"doc_count": 100,
-									"WorkflowType": {
-										"buckets": [
-											{
-												"key": "wf-type-1",
-												"doc_count": 75
-											},
-											{
-												"key": "wf-type-2",
-												"doc_count": 25
-											}
-										]
-									}
+									"key": {"ExecutionStatus": "Completed", "WorkflowType": "wf-type-1"},
+									"doc_count": 75
 								},
 								{
-									"key": "Running",
-									"doc_count": 10,
-									"WorkflowType": {
-										"buckets": [
-											{
-												"key": "wf-type-1",
-												"doc_count": 7
-											},
-											{
-												"key": "wf-type-2",
-												"doc_count": 3
-											}
-										]
-									}
+									"key": {"ExecutionStatus": "Completed", "WorkflowType": "wf-type-2"},
+									"doc_count": 25
+								},
+								{
+									"key": {"ExecutionStatus": "Running", "WorkflowType": "wf-type-1"},
+									"doc_count": 7
+								},
+								{
+									"key": {"ExecutionStatus": "Running", "WorkflowType": "wf-type-2"},
+									"doc_count": 3
 								}
 							]
 						}`,
@@ -1600,86 +1626,37 @@ func (s *ESVisibilitySuite) TestCountGroupByWorkflowExecutions() {
 				searchattribute.WorkflowType,
 				searchattribute.WorkflowID,
 			},
-			aggName: searchattribute.ExecutionStatus,
-			agg: elastic.NewTermsAggregation().Field(searchattribute.ExecutionStatus).SubAggregation(
-				searchattribute.WorkflowType,
-				elastic.NewTermsAggregation().Field(searchattribute.WorkflowType).SubAggregation(
-					searchattribute.WorkflowID,
-					elastic.NewTermsAggregation().Field(searchattribute.WorkflowID),
-				),
-			),
+			agg: elastic.NewCompositeAggregation().
+				Sources(
+					elastic.NewCompositeAggregationTermsValuesSource(searchattribute.ExecutionStatus).Field(searchattribute.ExecutionStatus),
+					elastic.NewCompositeAggregationTermsValuesSource(searchattribute.WorkflowType).Field(searchattribute.WorkflowType),
+					elastic.NewCompositeAggregationTermsValuesSource(searchattribute.WorkflowID).Field(searchattribute.WorkflowID),
+				).
+				Size(query.DefaultGroupsLimit + 1),
 			mockResponse: &elastic.SearchResult{
 				Aggregations: map[string]json.RawMessage{
-					searchattribute.ExecutionStatus: json.RawMessage(
+					groupByAggregationName: json.RawMessage(
 						`{
 							"buckets":[
 								{
-									"key": "Completed",
-									"doc_count": 100,
-									"WorkflowType": {
-										"buckets": [
-											{
-												"key": "wf-type-1",
-												"doc_count": 75,
-												"WorkflowId": {
-													"buckets": [
-														{
-															"key": "wf-id-1",
-															"doc_count": 75
-														}
-													]
-												}
-											},
-											{
-												"key": "wf-type-2",
-												"doc_count": 25,
-												"WorkflowId": {
-													"buckets": [
-														{
-															"key": "wf-id-2",
-															"doc_count": 20
-														},
-														{
-															"key": "wf-id-3",
-															"doc_count": 5
-														}
-													]
-												}
-											}
-										]
-									}
+									"key": {"ExecutionStatus": "Completed", "WorkflowType": "wf-type-1", "WorkflowId": "wf-id-1"},
+									"doc_count": 75
+								},
+								{
+									"key": {"ExecutionStatus": "Completed", "WorkflowType": "wf-type-2", "WorkflowId": "wf-id-2"},
+									"doc_count": 20
+								},
+								{
+									"key": {"ExecutionStatus": "Completed", "WorkflowType": "wf-type-2", "WorkflowId": "wf-id-3"},
+									"doc_count": 5
 								},
 								{
-									"key": "Running",
-									"doc_count": 10,
-									"WorkflowType": {
-										"buckets": [
-											{
-												"key": "wf-type-1",
-												"doc_count": 7,
-												"WorkflowId": {
-													"buckets": [
-														{
-															"key": "wf-id-4",
-															"doc_count": 7
-														}
-													]
-												}
-											},
-											{
-												"key": "wf-type-2",
-												"doc_count": 3,
-												"WorkflowId": {
-													"buckets": [
-														{
-															"key": "wf-id-5",
-															"doc_count": 3
-														}
-													]
-												}
-											}
-										]
-									}
+									"key": {"ExecutionStatus": "Running", "WorkflowType": "wf-type-1", "WorkflowId": "wf-id-4"},
+									"doc_count": 7
+								},
+								{
+									"key": {"ExecutionStatus": "Running", "WorkflowType": "wf-type-2", "WorkflowId": "wf-id-5"},
+									"doc_count": 3
 								}
 							]
 						}`,
@@ -1697,14 +1674,14 @@ func (s *ESVisibilitySuite) TestCountGroupByWorkflowExecutions() {
 						GroupValues: []*commonpb.Payload{statusCompletedPayload, wfType2Payload, wfId2Payload},
 						Count:       20,
 					},
-					{
-						GroupValues: []*commonpb.Payload{statusCompletedPayload, wfType2Payload, wfId3Payload},
-						Count:       5,
-					},
 					{
 						GroupValues: []*commonpb.Payload{statusRunningPayload, wfType1Payload, wfId4Payload},
 						Count:       7,
 					},
+					{
+						GroupValues: []*commonpb.Payload{statusCompletedPayload, wfType2Payload, wfId3Payload},
+						Count:       5,
+					},
 					{
 						GroupValues: []*commonpb.Payload{statusRunningPayload, wfType2Payload, wfId5Payload},
 						Count:       3,
@@ -1712,6 +1689,52 @@ func (s *ESVisibilitySuite) TestCountGroupByWorkflowExecutions() {
 				},
 			},
 		},
+
+		{
+			name:        "group by field and time bucket",
+			groupBy:     []string{searchattribute.WorkflowType, searchattribute.StartTime},
+			timeBuckets: map[string]string{searchattribute.StartTime: query.TimeBucketDay},
+			agg: elastic.NewCompositeAggregation().
+				Sources(
+					elastic.NewCompositeAggregationTermsValuesSource(searchattribute.WorkflowType).Field(searchattribute.WorkflowType),
+					elastic.NewCompositeAggregationDateHistogramValuesSource(searchattribute.StartTime).
+						Field(searchattribute.StartTime).
+						CalendarInterval(query.TimeBucketDay).
+						Format(timeBucketFormat),
+				).
+				Size(query.DefaultGroupsLimit + 1),
+			mockResponse: &elastic.SearchResult{
+				Aggregations: map[string]json.RawMessage{
+					groupByAggregationName: json.RawMessage(
+						`{
+							"buckets":[
+								{
+									"key": {"WorkflowType": "wf-type-1", "StartTime": "2024-03-01T00:00:00.000Z"},
+									"doc_count": 2
+								},
+								{
+									"key": {"WorkflowType": "wf-type-1", "StartTime": "2024-03-02T00:00:00.000Z"},
+									"doc_count": 10
+								}
+							]
+						}`,
+					),
+				},
+			},
+			response: &manager.CountWorkflowExecutionsResponse{
+				Count: 12,
+				Groups: []*workflowservice.CountWorkflowExecutionsResponse_AggregationGroup{
+					{
+						GroupValues: []*commonpb.Payload{wfType1Payload, day2Payload},
+						Count:       10,
+					},
+					{
+						GroupValues: []*commonpb.Payload{wfType1Payload, day1Payload},
+						Count:       2,
+					},
+				},
+			},
+		},
 	}
 
 	for _, tc := range testCases {
@@ -1720,7 +1743,8 @@ func (s *ESVisibilitySuite) TestCountGroupByWorkflowExecutions() {
 				Query: elastic.NewBoolQuery().
 					Filter(elastic.NewTermQuery(searchattribute.NamespaceID, testNamespaceID.String())).
 					MustNot(namespaceDivisionExists),
-				GroupBy: tc.groupBy,
+				GroupBy:     tc.groupBy,
+				TimeBuckets: tc.timeBuckets,
 			}
 			s.mockESClient.EXPECT().
 				CountGroupBy(
@@ -1729,17 +1753,63 @@ func (s *ESVisibilitySuite) TestCountGroupByWorkflowExecutions() {
 					elastic.NewBoolQuery().
 						Filter(elastic.NewTermQuery(searchattribute.NamespaceID, testNamespaceID.String())).
 						MustNot(namespaceDivisionExists),
-					tc.aggName,
+					groupByAggregationName,
 					tc.agg,
 				).
 				Return(tc.mockResponse, nil)
-			resp, err := s.visibilityStore.countGroupByWorkflowExecutions(context.Background(), searchParams)
+			resp, err := s.visibilityStore.countGroupByWorkflowExecutions(context.Background(), searchParams, query.DefaultGroupsLimit)
 			s.NoError(err)
 			s.True(temporalproto.DeepEqual(tc.response, resp))
 		})
 	}
 }
 
+func (s *ESVisibilitySuite) TestCountGroupByWorkflowExecutions_GroupsLimitExceeded() {
+	request := &manager.CountWorkflowExecutionsRequest{
+		NamespaceID: testNamespaceID,
+		Namespace:   testNamespace,
+		Query:       "GROUP BY WorkflowType, ExecutionStatus, date_trunc('day', StartTime)",
+		GroupsLimit: 2,
+	}
+	// The number of buckets is bounded by the limit across all fields and the time bucket.
+	s.mockESClient.EXPECT().
+		CountGroupBy(
+			gomock.Any(),
+			testIndex,
+			gomock.Any(),
+			groupByAggregationName,
+			elastic.NewCompositeAggregation().
+				Sources(
+					elastic.NewCompositeAggregationTermsValuesSource(searchattribute.WorkflowType).Field(searchattribute.WorkflowType),
+					elastic.NewCompositeAggregationTermsValuesSource(searchattribute.ExecutionStatus).Field(searchattribute.ExecutionStatus),
+					elastic.NewCompositeAggregationDateHistogramValuesSource(searchattribute.StartTime).
+						Field(searchattribute.StartTime).
+						CalendarInterval(query.TimeBucketDay).
+						Format(timeBucketFormat),
+				).
+				Size(3),
+		).
+		Return(
+			&elastic.SearchResult{
+				Aggregations: map[string]json.RawMessage{
+					groupByAggregationName: json.RawMessage(
+						`{"buckets":[
+							{"key":{"WorkflowType":"wf-type-1","ExecutionStatus":"Running","StartTime":"2024-03-01T00:00:00.000Z"},"doc_count":100},
+							{"key":{"WorkflowType":"wf-type-1","ExecutionStatus":"Running","StartTime":"2024-03-02T00:00:00.000Z"},"doc_count":10},
+							{"key":{"WorkflowType":"wf-type-2","ExecutionStatus":"Running","StartTime":"2024-03-01T00:00:00.000Z"},"doc_count":1}
+						]}`,
+					),
+				},
+			},
+			nil,
+		)
+	resp, err := s.visibilityStore.CountWorkflowExecutions(context.Background(), request)
+	var invalidArgument *serviceerror.InvalidArgument
+	s.ErrorAs(err, &invalidArgument)
+	s.Contains(err.Error(), "query has more than 2 groups")
+	s.Nil(resp)
+}
+
 func (s *ESVisibilitySuite) TestGetWorkflowExecution() {
 	now := timestamppb.New(time.Now())
 	s.mockESClient.EXPECT().Get(gomock.Any(), testIndex, gomock.Any()).DoAndReturn(
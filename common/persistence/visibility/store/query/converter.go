@@ -85,6 +85,8 @@ type (
 		Query   elastic.Query
 		Sorter  []elastic.Sorter
 		GroupBy []string
+		// TimeBuckets maps the GroupBy fields grouped by time bucket to the bucket unit.
+		TimeBuckets map[string]string
 	}
 )
 
@@ -194,6 +196,10 @@ func NewNotSupportedExprConverter() ExprConverter {
 // ConvertWhereOrderBy transforms WHERE SQL statement to Elasticsearch query.
 // It also supports ORDER BY clause.
 func (c *Converter) ConvertWhereOrderBy(whereOrderBy string) (*QueryParams, error) {
+	return c.ConvertSql(whereOrderByToSql(whereOrderBy))
+}
+
+func whereOrderByToSql(whereOrderBy string) string {
 	whereOrderBy = strings.TrimSpace(whereOrderBy)
 
 	if whereOrderBy != "" &&
@@ -202,8 +208,7 @@ func (c *Converter) ConvertWhereOrderBy(whereOrderBy string) (*QueryParams, erro
 		whereOrderBy = "where " + whereOrderBy
 	}
 	// sqlparser can't parse just WHERE clause but instead accepts only valid SQL statement.
-	sql := fmt.Sprintf("select * from table1 %s", whereOrderBy)
-	return c.ConvertSql(sql)
+	return fmt.Sprintf("select * from table1 %s", whereOrderBy)
 }
 
 // ConvertSql transforms SQL to Elasticsearch query.
@@ -239,15 +244,32 @@ func (c *Converter) convertSelect(sel *sqlparser.Select) (*QueryParams, error) {
 		queryParams.Query = query
 	}
 
-	if len(sel.GroupBy) > 1 {
-		return nil, NewConverterError("%s: 'group by' clause supports only a single field", NotSupportedErrMessage)
-	}
 	for _, groupByExpr := range sel.GroupBy {
-		colName, err := convertColName(c.fnInterceptor, groupByExpr, FieldNameGroupBy)
+		colNameExpr := groupByExpr
+		usage := FieldNameGroupBy
+		var timeBucket string
+		if funcExpr, isFuncExpr := groupByExpr.(*sqlparser.FuncExpr); isFuncExpr {
+			var err error
+			timeBucket, colNameExpr, err = ParseTimeBucketExpr(funcExpr)
+			if err != nil {
+				return nil, err
+			}
+			usage = FieldNameGroupByTimeBucket
+		}
+		colName, err := convertColName(c.fnInterceptor, colNameExpr, usage)
 		if err != nil {
 			return nil, wrapConverterError("unable to convert 'group by' column name", err)
 		}
 		queryParams.GroupBy = append(queryParams.GroupBy, colName)
+		if timeBucket != "" {
+			if queryParams.TimeBuckets == nil {
+				queryParams.TimeBuckets = make(map[string]string)
+			}
+			queryParams.TimeBuckets[colName] = timeBucket
+		}
+	}
+	if err := ValidateGroupByFields(queryParams.GroupBy); err != nil {
+		return nil, err
 	}
 
 	for _, orderByExpr := range sel.OrderBy {
@@ -393,6 +415,13 @@ func (r *rangeCondConverter) Convert(expr sqlparser.Expr) (elastic.Query, error)
 	}
 	fromValue = values[0]
 	toValue = values[1]
+	if _, isTextQuery := fromValue.(*TextQuery); isTextQuery {
+		return nil, NewConverterError(
+			"%s: cannot do range condition on search attribute of type Text in `%s`",
+			InvalidExpressionErrMessage,
+			sqlparser.String(expr),
+		)
+	}
 
 	var query elastic.Query
 	switch rangeCond.Operator {
@@ -477,6 +506,10 @@ func (c *comparisonExprConverter) Convert(expr sqlparser.Expr) (elastic.Query, e
 		return nil, NewConverterError("operator '%v' not allowed in comparison expression", comparisonExpr.Operator)
 	}
 
+	if textQuery, isTextQuery := colValues[0].(*TextQuery); isTextQuery {
+		return convertTextComparisonExpr(comparisonExpr, colName, textQuery)
+	}
+
 	var query elastic.Query
 	switch comparisonExpr.Operator {
 	case sqlparser.GreaterEqualStr:
@@ -514,6 +547,24 @@ func (c *comparisonExprConverter) Convert(expr sqlparser.Expr) (elastic.Query, e
 	return query, nil
 }
 
+// convertTextComparisonExpr converts a filter on a Text search attribute, which values interceptor
+// replaced with a TextQuery.
+func convertTextComparisonExpr(expr *sqlparser.ComparisonExpr, colName string, textQuery *TextQuery) (elastic.Query, error) {
+	switch expr.Operator {
+	case sqlparser.EqualStr, sqlparser.LikeStr:
+		return textQuery.elasticQuery(colName), nil
+	case sqlparser.NotEqualStr, sqlparser.NotLikeStr:
+		return elastic.NewBoolQuery().MustNot(textQuery.elasticQuery(colName)), nil
+	default:
+		return nil, NewConverterError(
+			"%s: operator '%s' not supported for Text type search attribute in `%s`",
+			InvalidExpressionErrMessage,
+			expr.Operator,
+			sqlparser.String(expr),
+		)
+	}
+}
+
 // convertComparisonExprValue returns a string, int64, float64, bool or
 // a slice with each value of one of those types.
 func convertComparisonExprValue(expr sqlparser.Expr) (interface{}, error) {
@@ -0,0 +1,271 @@
+// The MIT License
+//
+// Copyright (c) 2020 Temporal Technologies Inc.  All rights reserved.
+//
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package query
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/temporalio/sqlparser"
+	enumspb "go.temporal.io/api/enums/v1"
+)
+
+type (
+	// QueryCost is the estimated cost of a visibility query, computed before the query is executed.
+	QueryCost struct {
+		// UnboundedRanges is the number of range filters which are open on one side, or which
+		// span more than LongTimeRange on a datetime field.
+		UnboundedRanges int
+		// OrFanOut is the number of extra branches of 'or' expressions and extra values of 'in' lists.
+		OrFanOut int
+		// TextFilters is the number of filters on Text fields and of unanchored 'like' filters.
+		TextFilters int
+	}
+
+	// FieldTypeFunc returns the type of the search attribute with the given name
+	// and false if the search attribute is unknown.
+	FieldTypeFunc func(name string) (enumspb.IndexedValueType, bool)
+
+	costEstimator struct {
+		fieldType FieldTypeFunc
+		now       time.Time
+		cost      QueryCost
+	}
+
+	rangeBounds struct {
+		lower interface{}
+		upper interface{}
+		// open is set when the range can't be bounded by the filters of the same 'and' chain.
+		open bool
+	}
+)
+
+// Weights of QueryCost components.
+const (
+	BaseQueryCost      = 1
+	UnboundedRangeCost = 10
+	OrFanOutCost       = 1
+	TextFilterCost     = 20
+
+	// LongTimeRange is the width above which a datetime range is counted as unbounded.
+	LongTimeRange = 90 * 24 * time.Hour
+)
+
+// Total returns the weighted cost of the query.
+func (c QueryCost) Total() int {
+	return BaseQueryCost +
+		c.UnboundedRanges*UnboundedRangeCost +
+		c.OrFanOut*OrFanOutCost +
+		c.TextFilters*TextFilterCost
+}
+
+func (c QueryCost) String() string {
+	return fmt.Sprintf(
+		"%d (unbounded ranges: %d, 'or' fan-out: %d, text filters: %d)",
+		c.Total(),
+		c.UnboundedRanges,
+		c.OrFanOut,
+		c.TextFilters,
+	)
+}
+
+// EstimateQueryCost returns the estimated cost of a visibility query in the same format
+// as Converter.ConvertWhereOrderBy accepts. Only the filter expression is taken into account.
+// Unknown search attributes and unsupported expressions don't add to the cost: they are
+// reported by the converter when the query is executed.
+func EstimateQueryCost(whereOrderBy string, fieldType FieldTypeFunc, now time.Time) (QueryCost, error) {
+	stmt, err := sqlparser.Parse(whereOrderByToSql(whereOrderBy))
+	if err != nil {
+		return QueryCost{}, NewConverterError("%s: %v", MalformedSqlQueryErrMessage, err)
+	}
+	selectStmt, isSelect := stmt.(*sqlparser.Select)
+	if !isSelect {
+		return QueryCost{}, NewConverterError("%s: statement must be 'select' not %T", NotSupportedErrMessage, stmt)
+	}
+	if selectStmt.Where == nil {
+		return QueryCost{}, nil
+	}
+
+	e := &costEstimator{
+		fieldType: fieldType,
+		now:       now,
+	}
+	e.estimateAnd(selectStmt.Where.Expr)
+	return e.cost, nil
+}
+
+// estimateAnd estimates a chain of 'and' expressions. Range filters on the same field are
+// combined, so `StartTime > a and StartTime < b` is a bounded range.
+func (e *costEstimator) estimateAnd(expr sqlparser.Expr) {
+	var fields []string
+	ranges := make(map[string]*rangeBounds)
+	getBounds := func(colName string) *rangeBounds {
+		if _, ok := ranges[colName]; !ok {
+			fields = append(fields, colName)
+			ranges[colName] = &rangeBounds{}
+		}
+		return ranges[colName]
+	}
+
+	for _, term := range flattenAnd(expr) {
+		switch t := term.(type) {
+		case *sqlparser.ComparisonExpr:
+			colName, ok := costColName(t.Left)
+			if !ok {
+				continue
+			}
+			e.estimateTextFilter(colName, t)
+			value, _ := convertComparisonExprValue(t.Right)
+			switch t.Operator {
+			case sqlparser.GreaterThanStr, sqlparser.GreaterEqualStr:
+				getBounds(colName).lower = value
+			case sqlparser.LessThanStr, sqlparser.LessEqualStr:
+				getBounds(colName).upper = value
+			case sqlparser.InStr, sqlparser.NotInStr:
+				if values, isSlice := value.([]interface{}); isSlice && len(values) > 1 {
+					e.cost.OrFanOut += len(values) - 1
+				}
+			}
+		case *sqlparser.RangeCond:
+			colName, ok := costColName(t.Left)
+			if !ok {
+				continue
+			}
+			bounds := getBounds(colName)
+			if t.Operator != sqlparser.BetweenStr {
+				bounds.open = true
+				continue
+			}
+			bounds.lower, _ = ParseSqlValue(sqlparser.String(t.From))
+			bounds.upper, _ = ParseSqlValue(sqlparser.String(t.To))
+		default:
+			e.estimateOr(term)
+		}
+	}
+
+	for _, colName := range fields {
+		if e.isUnbounded(colName, ranges[colName]) {
+			e.cost.UnboundedRanges++
+		}
+	}
+}
+
+func (e *costEstimator) estimateOr(expr sqlparser.Expr) {
+	orExpr, isOr := unwrapParens(expr).(*sqlparser.OrExpr)
+	if !isOr {
+		return
+	}
+	branches := flattenOr(orExpr)
+	e.cost.OrFanOut += len(branches) - 1
+	for _, branch := range branches {
+		e.estimateAnd(branch)
+	}
+}
+
+// estimateTextFilter counts filters which can't use an index on a keyword or a number:
+// any filter on a Text field and 'like' filters starting with a wildcard.
+func (e *costEstimator) estimateTextFilter(colName string, expr *sqlparser.ComparisonExpr) {
+	if fieldType, ok := e.fieldType(colName); ok && fieldType == enumspb.INDEXED_VALUE_TYPE_TEXT {
+		e.cost.TextFilters++
+		return
+	}
+	if expr.Operator != sqlparser.LikeStr && expr.Operator != sqlparser.NotLikeStr {
+		return
+	}
+	value, err := convertComparisonExprValue(expr.Right)
+	if err != nil {
+		return
+	}
+	if v, isString := value.(string); isString && strings.HasPrefix(v, "%") {
+		e.cost.TextFilters++
+	}
+}
+
+func (e *costEstimator) isUnbounded(colName string, bounds *rangeBounds) bool {
+	if bounds.open || bounds.lower == nil {
+		return true
+	}
+	if fieldType, ok := e.fieldType(colName); !ok || fieldType != enumspb.INDEXED_VALUE_TYPE_DATETIME {
+		return bounds.upper == nil
+	}
+
+	from, ok := parseCostTime(bounds.lower)
+	if !ok {
+		return bounds.upper == nil
+	}
+	to := e.now
+	if bounds.upper != nil {
+		if to, ok = parseCostTime(bounds.upper); !ok {
+			return false
+		}
+	}
+	return to.Sub(from) > LongTimeRange
+}
+
+func parseCostTime(value interface{}) (time.Time, bool) {
+	s, isString := value.(string)
+	if !isString {
+		return time.Time{}, false
+	}
+	t, err := time.Parse(time.RFC3339Nano, s)
+	return t, err == nil
+}
+
+func costColName(expr sqlparser.Expr) (string, bool) {
+	colName, isColName := expr.(*sqlparser.ColName)
+	if !isColName {
+		return "", false
+	}
+	return strings.ReplaceAll(sqlparser.String(colName), "`", ""), true
+}
+
+func flattenAnd(expr sqlparser.Expr) []sqlparser.Expr {
+	expr = unwrapParens(expr)
+	andExpr, isAnd := expr.(*sqlparser.AndExpr)
+	if !isAnd {
+		return []sqlparser.Expr{expr}
+	}
+	return append(flattenAnd(andExpr.Left), flattenAnd(andExpr.Right)...)
+}
+
+func flattenOr(expr sqlparser.Expr) []sqlparser.Expr {
+	expr = unwrapParens(expr)
+	orExpr, isOr := expr.(*sqlparser.OrExpr)
+	if !isOr {
+		return []sqlparser.Expr{expr}
+	}
+	return append(flattenOr(orExpr.Left), flattenOr(orExpr.Right)...)
+}
+
+func unwrapParens(expr sqlparser.Expr) sqlparser.Expr {
+	for {
+		parenExpr, isParen := expr.(*sqlparser.ParenExpr)
+		if !isParen {
+			return expr
+		}
+		expr = parenExpr.Expr
+	}
+}
@@ -0,0 +1,183 @@
+// The MIT License
+//
+// Copyright (c) 2020 Temporal Technologies Inc.  All rights reserved.
+//
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package query
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	enumspb "go.temporal.io/api/enums/v1"
+)
+
+func testFieldType(name string) (enumspb.IndexedValueType, bool) {
+	switch name {
+	case "StartTime", "CloseTime":
+		return enumspb.INDEXED_VALUE_TYPE_DATETIME, true
+	case "WorkflowType", "WorkflowId", "ExecutionStatus":
+		return enumspb.INDEXED_VALUE_TYPE_KEYWORD, true
+	case "HistoryLength":
+		return enumspb.INDEXED_VALUE_TYPE_INT, true
+	case "CustomTextField":
+		return enumspb.INDEXED_VALUE_TYPE_TEXT, true
+	default:
+		return enumspb.INDEXED_VALUE_TYPE_UNSPECIFIED, false
+	}
+}
+
+func TestEstimateQueryCost(t *testing.T) {
+	now := time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)
+
+	testCases := []struct {
+		name     string
+		query    string
+		expected QueryCost
+	}{
+		{
+			name:     "empty",
+			query:    "",
+			expected: QueryCost{},
+		},
+		{
+			name:     "order by only",
+			query:    "order by StartTime desc",
+			expected: QueryCost{},
+		},
+		{
+			name:     "keyword equality",
+			query:    "WorkflowType = 'foo' and ExecutionStatus = 'Running'",
+			expected: QueryCost{},
+		},
+		{
+			name:     "bounded range",
+			query:    "HistoryLength > 10 and HistoryLength < 100",
+			expected: QueryCost{},
+		},
+		{
+			name:     "range open on one side",
+			query:    "HistoryLength > 10",
+			expected: QueryCost{UnboundedRanges: 1},
+		},
+		{
+			name:     "datetime lower bound only is bounded by now",
+			query:    "StartTime > '2024-05-01T00:00:00Z'",
+			expected: QueryCost{},
+		},
+		{
+			name:     "datetime lower bound only too far in the past",
+			query:    "StartTime >= '2023-01-01T00:00:00Z'",
+			expected: QueryCost{UnboundedRanges: 1},
+		},
+		{
+			name:     "datetime upper bound only",
+			query:    "CloseTime < '2024-05-01T00:00:00Z'",
+			expected: QueryCost{UnboundedRanges: 1},
+		},
+		{
+			name:     "short datetime between",
+			query:    "StartTime between '2024-01-01T00:00:00Z' and '2024-01-02T00:00:00Z'",
+			expected: QueryCost{},
+		},
+		{
+			name:     "long datetime between",
+			query:    "StartTime between '2020-01-01T00:00:00Z' and '2024-01-01T00:00:00Z'",
+			expected: QueryCost{UnboundedRanges: 1},
+		},
+		{
+			name:     "not between",
+			query:    "HistoryLength not between 10 and 100",
+			expected: QueryCost{UnboundedRanges: 1},
+		},
+		{
+			name:     "bounds in parentheses",
+			query:    "(StartTime > '2024-05-01T00:00:00Z' and WorkflowType = 'foo') and StartTime < '2024-05-02T00:00:00Z'",
+			expected: QueryCost{},
+		},
+		{
+			name:     "or fan-out",
+			query:    "WorkflowType = 'a' or WorkflowType = 'b' or (WorkflowType = 'c' or WorkflowType = 'd')",
+			expected: QueryCost{OrFanOut: 3},
+		},
+		{
+			name:     "in fan-out",
+			query:    "WorkflowId in ('a', 'b', 'c')",
+			expected: QueryCost{OrFanOut: 2},
+		},
+		{
+			name:     "ranges in or branches are not combined",
+			query:    "HistoryLength > 10 or HistoryLength < 5",
+			expected: QueryCost{UnboundedRanges: 2, OrFanOut: 1},
+		},
+		{
+			name:     "unanchored like",
+			query:    "WorkflowType like '%foo'",
+			expected: QueryCost{TextFilters: 1},
+		},
+		{
+			name:     "anchored like",
+			query:    "WorkflowType like 'foo%' and WorkflowId starts_with 'bar'",
+			expected: QueryCost{},
+		},
+		{
+			name:     "text field",
+			query:    "CustomTextField = 'some words'",
+			expected: QueryCost{TextFilters: 1},
+		},
+		{
+			name:     "unknown field",
+			query:    "Unknown like '%foo' and Unknown > 10 and Unknown < 100",
+			expected: QueryCost{TextFilters: 1},
+		},
+		{
+			name:     "everything",
+			query:    "(CustomTextField = 'foo' or WorkflowType like '%bar') and StartTime > '2020-01-01T00:00:00Z' order by StartTime",
+			expected: QueryCost{UnboundedRanges: 1, OrFanOut: 1, TextFilters: 2},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			cost, err := EstimateQueryCost(tc.query, testFieldType, now)
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected, cost)
+		})
+	}
+}
+
+func TestEstimateQueryCost_Malformed(t *testing.T) {
+	_, err := EstimateQueryCost("WorkflowType = ", testFieldType, time.Now())
+	var converterErr *ConverterError
+	assert.ErrorAs(t, err, &converterErr)
+}
+
+func TestQueryCost_Total(t *testing.T) {
+	assert.Equal(t, BaseQueryCost, QueryCost{}.Total())
+	assert.Equal(
+		t,
+		BaseQueryCost+2*UnboundedRangeCost+3*OrFanOutCost+TextFilterCost,
+		QueryCost{UnboundedRanges: 2, OrFanOut: 3, TextFilters: 1}.Total(),
+	)
+}
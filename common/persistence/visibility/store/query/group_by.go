@@ -0,0 +1,165 @@
+// The MIT License
+//
+// Copyright (c) 2020 Temporal Technologies Inc.  All rights reserved.
+//
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package query
+
+import (
+	"slices"
+	"strings"
+
+	"github.com/temporalio/sqlparser"
+	enumspb "go.temporal.io/api/enums/v1"
+)
+
+const (
+	// GroupByTimeBucketFunc truncates a datetime search attribute to a time bucket in a 'group by' clause,
+	// for example `GROUP BY WorkflowType, date_trunc('day', StartTime)`.
+	GroupByTimeBucketFunc = "date_trunc"
+
+	// MaxGroupByFields is the maximum number of fields in a 'group by' clause.
+	MaxGroupByFields = 3
+
+	// DefaultGroupsLimit is the maximum number of groups of a 'group by' query when the request doesn't set one.
+	DefaultGroupsLimit = 1000
+)
+
+// Time bucket units of GroupByTimeBucketFunc. Buckets are in UTC and weeks start on Monday.
+const (
+	TimeBucketMinute = "minute"
+	TimeBucketHour   = "hour"
+	TimeBucketDay    = "day"
+	TimeBucketWeek   = "week"
+	TimeBucketMonth  = "month"
+	TimeBucketYear   = "year"
+)
+
+var supportedTimeBuckets = []string{
+	TimeBucketMinute,
+	TimeBucketHour,
+	TimeBucketDay,
+	TimeBucketWeek,
+	TimeBucketMonth,
+	TimeBucketYear,
+}
+
+// ParseTimeBucketExpr returns the unit and the column name of a 'group by' expression like
+// `date_trunc('<unit>', <column>)`.
+func ParseTimeBucketExpr(expr *sqlparser.FuncExpr) (string, sqlparser.Expr, error) {
+	if expr.Name.Lowered() != GroupByTimeBucketFunc {
+		return "", nil, NewConverterError(
+			"%s: function '%s' in 'group by' clause, only '%s' is supported",
+			NotSupportedErrMessage,
+			expr.Name.String(),
+			GroupByTimeBucketFunc,
+		)
+	}
+	if expr.Distinct || len(expr.Exprs) != 2 {
+		return "", nil, NewConverterError(
+			"%s: '%s' expects a time unit and a column name, for example %s('day', StartTime)",
+			InvalidExpressionErrMessage,
+			GroupByTimeBucketFunc,
+			GroupByTimeBucketFunc,
+		)
+	}
+	unitExpr, unitOk := expr.Exprs[0].(*sqlparser.AliasedExpr)
+	colExpr, colOk := expr.Exprs[1].(*sqlparser.AliasedExpr)
+	if !unitOk || !colOk {
+		return "", nil, NewConverterError(
+			"%s: unexpected arguments in `%s`",
+			InvalidExpressionErrMessage,
+			sqlparser.String(expr),
+		)
+	}
+	unitVal, isSQLVal := unitExpr.Expr.(*sqlparser.SQLVal)
+	if !isSQLVal || unitVal.Type != sqlparser.StrVal {
+		return "", nil, NewConverterError(
+			"%s: time unit of '%s' must be a string literal",
+			InvalidExpressionErrMessage,
+			GroupByTimeBucketFunc,
+		)
+	}
+	unit := strings.ToLower(string(unitVal.Val))
+	if !slices.Contains(supportedTimeBuckets, unit) {
+		return "", nil, NewConverterError(
+			"%s: unsupported time unit '%s', supported units are: %s",
+			InvalidExpressionErrMessage,
+			unitVal.Val,
+			strings.Join(supportedTimeBuckets, ", "),
+		)
+	}
+	return unit, colExpr.Expr, nil
+}
+
+// ValidateGroupByFieldType checks that a search attribute can be used in a 'group by' clause: fields grouped by
+// value must be keywords, and fields grouped by time bucket must be datetimes.
+func ValidateGroupByFieldType(name string, fieldType enumspb.IndexedValueType, usage FieldNameUsage) error {
+	switch usage {
+	case FieldNameGroupBy:
+		if fieldType != enumspb.INDEXED_VALUE_TYPE_KEYWORD {
+			return NewConverterError(
+				"%s: 'group by' clause is only supported for search attributes of type %s, "+
+					"use %s to group %s search attributes (got '%s' of type %s)",
+				NotSupportedErrMessage,
+				enumspb.INDEXED_VALUE_TYPE_KEYWORD.String(),
+				GroupByTimeBucketFunc,
+				enumspb.INDEXED_VALUE_TYPE_DATETIME.String(),
+				name,
+				fieldType.String(),
+			)
+		}
+	case FieldNameGroupByTimeBucket:
+		if fieldType != enumspb.INDEXED_VALUE_TYPE_DATETIME {
+			return NewConverterError(
+				"%s: '%s' is only supported for search attributes of type %s (got '%s' of type %s)",
+				NotSupportedErrMessage,
+				GroupByTimeBucketFunc,
+				enumspb.INDEXED_VALUE_TYPE_DATETIME.String(),
+				name,
+				fieldType.String(),
+			)
+		}
+	}
+	return nil
+}
+
+// ValidateGroupByFields checks the number of fields of a 'group by' clause and that none is repeated.
+func ValidateGroupByFields(fieldNames []string) error {
+	if len(fieldNames) > MaxGroupByFields {
+		return NewConverterError(
+			"%s: 'group by' clause supports at most %d fields",
+			NotSupportedErrMessage,
+			MaxGroupByFields,
+		)
+	}
+	for i, fieldName := range fieldNames {
+		if slices.Contains(fieldNames[:i], fieldName) {
+			return NewConverterError(
+				"%s: field '%s' is repeated in 'group by' clause",
+				InvalidExpressionErrMessage,
+				fieldName,
+			)
+		}
+	}
+	return nil
+}
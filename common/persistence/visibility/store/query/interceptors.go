@@ -43,6 +43,8 @@ const (
 	FieldNameFilter FieldNameUsage = iota
 	FieldNameSorter
 	FieldNameGroupBy
+	// FieldNameGroupByTimeBucket is a datetime field grouped by time bucket with GroupByTimeBucketFunc.
+	FieldNameGroupByTimeBucket
 )
 
 func (n *NopFieldNameInterceptor) Name(name string, _ FieldNameUsage) (string, error) {
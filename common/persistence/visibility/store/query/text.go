@@ -0,0 +1,107 @@
+// The MIT License
+//
+// Copyright (c) 2020 Temporal Technologies Inc.  All rights reserved.
+//
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package query
+
+import (
+	"strings"
+	"unicode"
+
+	"github.com/olivere/elastic/v7"
+)
+
+type (
+	// TextQuery is the value of a filter on a Text search attribute. It matches text containing
+	// any of its tokens or any of its phrases. The value is tokenized with TokenizeText and
+	// double-quoted parts of it are phrases, for example `timeout "payment service"` matches
+	// text containing the token "timeout" or the tokens "payment" and "service" next to each other.
+	// All visibility stores follow these rules, so the same query returns the same executions.
+	TextQuery struct {
+		Tokens  []string
+		Phrases [][]string
+	}
+)
+
+// TokenizeText splits text into lowercase tokens: sequences of letters, digits and combining marks.
+// Everything else, including punctuation and underscores, separates tokens.
+func TokenizeText(text string) []string {
+	return strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r) && !unicode.IsMark(r)
+	})
+}
+
+// ParseTextQuery parses the value of a filter on a Text search attribute.
+func ParseTextQuery(value string) (*TextQuery, error) {
+	parts := strings.Split(value, `"`)
+	if len(parts)%2 == 0 {
+		return nil, NewConverterError(
+			"%s: unbalanced double quotes in value of Text type search attribute: %q",
+			InvalidExpressionErrMessage,
+			value,
+		)
+	}
+
+	q := &TextQuery{}
+	for i, part := range parts {
+		tokens := TokenizeText(part)
+		// Even parts are outside of double quotes. A phrase of a single token is just a token.
+		if i%2 == 0 || len(tokens) == 1 {
+			q.Tokens = append(q.Tokens, tokens...)
+		} else if len(tokens) > 1 {
+			q.Phrases = append(q.Phrases, tokens)
+		}
+	}
+	if len(q.Tokens) == 0 && len(q.Phrases) == 0 {
+		return nil, NewConverterError(
+			"%s: no tokens found in value of Text type search attribute: %q",
+			InvalidExpressionErrMessage,
+			value,
+		)
+	}
+	return q, nil
+}
+
+// Terms returns the tokens followed by the phrases, with tokens of a phrase joined by spaces.
+func (q *TextQuery) Terms() []string {
+	terms := make([]string, 0, len(q.Tokens)+len(q.Phrases))
+	terms = append(terms, q.Tokens...)
+	for _, phrase := range q.Phrases {
+		terms = append(terms, strings.Join(phrase, " "))
+	}
+	return terms
+}
+
+func (q *TextQuery) elasticQuery(field string) elastic.Query {
+	var queries []elastic.Query
+	if len(q.Tokens) > 0 {
+		queries = append(queries, elastic.NewMatchQuery(field, strings.Join(q.Tokens, " ")))
+	}
+	for _, phrase := range q.Phrases {
+		queries = append(queries, elastic.NewMatchPhraseQuery(field, strings.Join(phrase, " ")))
+	}
+	if len(queries) == 1 {
+		return queries[0]
+	}
+	return elastic.NewBoolQuery().Should(queries...).MinimumNumberShouldMatch(1)
+}
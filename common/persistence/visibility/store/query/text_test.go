@@ -0,0 +1,110 @@
+// The MIT License
+//
+// Copyright (c) 2020 Temporal Technologies Inc.  All rights reserved.
+//
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package query
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTokenizeText(t *testing.T) {
+	testCases := []struct {
+		text     string
+		expected []string
+	}{
+		{text: "", expected: []string{}},
+		{text: "Payment service", expected: []string{"payment", "service"}},
+		{text: "payment-service: timed_out!", expected: []string{"payment", "service", "timed", "out"}},
+		{text: "  retry #42 ", expected: []string{"retry", "42"}},
+		{text: "Größe café", expected: []string{"größe", "café"}},
+		{text: "--- ...", expected: []string{}},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.text, func(t *testing.T) {
+			assert.Equal(t, tc.expected, TokenizeText(tc.text))
+		})
+	}
+}
+
+func TestParseTextQuery(t *testing.T) {
+	testCases := []struct {
+		name     string
+		value    string
+		expected *TextQuery
+		err      string
+	}{
+		{
+			name:     "tokens",
+			value:    "Payment, Timeout",
+			expected: &TextQuery{Tokens: []string{"payment", "timeout"}},
+		},
+		{
+			name:  "phrases",
+			value: `retry "payment service" "Baz-Qux"`,
+			expected: &TextQuery{
+				Tokens:  []string{"retry"},
+				Phrases: [][]string{{"payment", "service"}, {"baz", "qux"}},
+			},
+		},
+		{
+			name:     "single token phrase",
+			value:    `"payment" ""`,
+			expected: &TextQuery{Tokens: []string{"payment"}},
+		},
+		{
+			name:  "unbalanced double quotes",
+			value: `"payment service`,
+			err:   "unbalanced double quotes",
+		},
+		{
+			name:  "no tokens",
+			value: ` -- "" `,
+			err:   "no tokens found",
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			q, err := ParseTextQuery(tc.value)
+			if tc.err != "" {
+				var converterErr *ConverterError
+				require.ErrorAs(t, err, &converterErr)
+				assert.Contains(t, err.Error(), tc.err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected, q)
+		})
+	}
+}
+
+func TestTextQuery_Terms(t *testing.T) {
+	q := &TextQuery{
+		Tokens:  []string{"retry", "timeout"},
+		Phrases: [][]string{{"payment", "service"}},
+	}
+	assert.Equal(t, []string{"retry", "timeout", "payment service"}, q.Terms())
+}
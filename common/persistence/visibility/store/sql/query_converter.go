@@ -56,6 +56,10 @@ type (
 
 		buildCountStmt(namespaceID namespace.ID, queryString string, groupBy []string) (string, []any)
 
+		// buildTimeBucketExpr returns the expression truncating a datetime column to the start of its time bucket in
+		// UTC, one of the query.TimeBucket* units.
+		buildTimeBucketExpr(colName string, unit string) string
+
 		getDatetimeFormat() string
 
 		getCoalesceCloseTimeExpr() sqlparser.Expr
@@ -76,6 +80,8 @@ type (
 		queryString string
 		// List of search attributes to group by (field name, not db name).
 		groupBy []string
+		// Maps the groupBy fields grouped by time bucket to the bucket unit.
+		timeBuckets map[string]string
 	}
 )
 
@@ -184,11 +190,14 @@ func (c *QueryConverter) BuildCountStmt() (*sqlplugin.VisibilitySelectFilter, er
 	if err != nil {
 		return nil, err
 	}
-	groupByDbNames := make([]string, len(qp.groupBy))
+	groupByExprs := make([]string, len(qp.groupBy))
 	for i, fieldName := range qp.groupBy {
-		groupByDbNames[i] = searchattribute.GetSqlDbColName(fieldName)
+		groupByExprs[i] = searchattribute.GetSqlDbColName(fieldName)
+		if unit, ok := qp.timeBuckets[fieldName]; ok {
+			groupByExprs[i] = c.buildTimeBucketExpr(groupByExprs[i], unit)
+		}
 	}
-	queryString, queryArgs := c.buildCountStmt(c.namespaceID, qp.queryString, groupByDbNames)
+	queryString, queryArgs := c.buildCountStmt(c.namespaceID, qp.queryString, groupByExprs)
 	return &sqlplugin.VisibilitySelectFilter{
 		Query:     queryString,
 		QueryArgs: queryArgs,
@@ -221,9 +230,17 @@ func (c *QueryConverter) convertWhereString(queryString string) (*queryParams, e
 		res.queryString = sqlparser.String(selectStmt.Where.Expr)
 	}
 	for _, groupByExpr := range selectStmt.GroupBy {
-		// The parser already ensures the type is saColName.
-		colName := groupByExpr.(*saColName)
-		res.groupBy = append(res.groupBy, colName.fieldName)
+		// The parser already ensures the type is saColName or timeBucketColName.
+		switch e := groupByExpr.(type) {
+		case *saColName:
+			res.groupBy = append(res.groupBy, e.fieldName)
+		case *timeBucketColName:
+			res.groupBy = append(res.groupBy, e.fieldName)
+			if res.timeBuckets == nil {
+				res.timeBuckets = make(map[string]string)
+			}
+			res.timeBuckets[e.fieldName] = e.unit
+		}
 	}
 	return res, nil
 }
@@ -282,27 +299,47 @@ func (c *QueryConverter) convertSelectStmt(sel *sqlparser.Select) error {
 		}
 	}
 
-	if len(sel.GroupBy) > 1 {
-		return query.NewConverterError(
-			"%s: 'group by' clause supports only a single field",
-			query.NotSupportedErrMessage,
-		)
-	}
+	return c.convertGroupBy(sel)
+}
+
+// convertGroupBy converts the 'group by' fields to saColName or timeBucketColName. Executions without a value for
+// any of the fields aren't counted, like in Elasticsearch.
+func (c *QueryConverter) convertGroupBy(sel *sqlparser.Select) error {
+	fieldNames := make([]string, len(sel.GroupBy))
 	for k := range sel.GroupBy {
+		usage := query.FieldNameGroupBy
+		var unit string
+		if funcExpr, isFuncExpr := sel.GroupBy[k].(*sqlparser.FuncExpr); isFuncExpr {
+			var err error
+			unit, sel.GroupBy[k], err = query.ParseTimeBucketExpr(funcExpr)
+			if err != nil {
+				return err
+			}
+			usage = query.FieldNameGroupByTimeBucket
+		}
 		colName, err := c.convertColName(&sel.GroupBy[k])
 		if err != nil {
 			return err
 		}
-		if colName.fieldName != searchattribute.ExecutionStatus {
-			return query.NewConverterError(
-				"%s: 'group by' clause is only supported for %s search attribute",
-				query.NotSupportedErrMessage,
-				searchattribute.ExecutionStatus,
-			)
+		if err := query.ValidateGroupByFieldType(colName.alias, colName.valueType, usage); err != nil {
+			return err
+		}
+		if unit != "" {
+			sel.GroupBy[k] = newTimeBucketColName(colName, unit)
+		} else {
+			sel.GroupBy[k] = colName
+		}
+		fieldNames[k] = colName.fieldName
+
+		sel.Where.Expr = &sqlparser.AndExpr{
+			Left: sel.Where.Expr,
+			Right: &sqlparser.IsExpr{
+				Operator: sqlparser.IsNotNullStr,
+				Expr:     colName.dbColName,
+			},
 		}
 	}
-
-	return nil
+	return query.ValidateGroupByFields(fieldNames)
 }
 
 func (c *QueryConverter) convertWhereExpr(expr *sqlparser.Expr) error {
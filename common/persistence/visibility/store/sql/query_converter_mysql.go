@@ -196,19 +196,53 @@ func (c *mysqlQueryConverter) convertTextComparisonExpr(
 			formatComparisonExprStringForError(*expr),
 		)
 	}
-	// build the following expression:
-	// `match ({expr.Left}) against ({expr.Right} in natural language mode)`
-	var newExpr sqlparser.Expr = &sqlparser.MatchExpr{
-		Columns: []sqlparser.SelectExpr{&sqlparser.AliasedExpr{Expr: expr.Left}},
-		Expr:    expr.Right,
-		Option:  sqlparser.NaturalLanguageModeStr,
+	textQuery, err := parseTextQueryValue(expr)
+	if err != nil {
+		return nil, err
+	}
+	tokensColName, err := textSearchTokensColName(expr.Left)
+	if err != nil {
+		return nil, err
 	}
+	// build the following expression:
+	// `(match ({tokens column}) against ('token1__ token2__ "phrase__ tokens__"' in boolean mode) or
+	//   ({tokens column} is null and match ({expr.Left}) against ('token1 token2 "phrase tokens"' in boolean mode)))`
+	// Boolean mode without operators matches any of the terms, and it doesn't apply
+	// the 50% threshold of natural language mode.
+	newExpr := newTextMatchWithFallbackExpr(
+		tokensColName,
+		newMySQLMatchExpr(tokensColName, textQuery, sqlplugin.MySQLTextSearchToken),
+		newMySQLMatchExpr(expr.Left, textQuery, func(token string) string { return token }),
+	)
 	if expr.Operator == sqlparser.NotEqualStr {
-		newExpr = &sqlparser.NotExpr{Expr: newExpr}
+		newExpr = newTextNotMatchExpr(expr.Left, newExpr)
 	}
 	return newExpr, nil
 }
 
+func newMySQLMatchExpr(
+	colName sqlparser.Expr,
+	textQuery *query.TextQuery,
+	formatToken func(token string) string,
+) sqlparser.Expr {
+	terms := make([]string, 0, len(textQuery.Tokens)+len(textQuery.Phrases))
+	for _, token := range textQuery.Tokens {
+		terms = append(terms, formatToken(token))
+	}
+	for _, phrase := range textQuery.Phrases {
+		phraseTokens := make([]string, len(phrase))
+		for i, token := range phrase {
+			phraseTokens[i] = formatToken(token)
+		}
+		terms = append(terms, fmt.Sprintf(`"%s"`, strings.Join(phraseTokens, " ")))
+	}
+	return &sqlparser.MatchExpr{
+		Columns: []sqlparser.SelectExpr{&sqlparser.AliasedExpr{Expr: colName}},
+		Expr:    newUnsafeSQLString(strings.Join(terms, " ")),
+		Option:  sqlparser.BooleanModeStr,
+	}
+}
+
 func (c *mysqlQueryConverter) buildSelectStmt(
 	namespaceID namespace.ID,
 	queryString string,
@@ -309,3 +343,20 @@ func (c *mysqlQueryConverter) buildCountStmt(
 		groupByClause,
 	), queryArgs
 }
+
+func (c *mysqlQueryConverter) buildTimeBucketExpr(colName string, unit string) string {
+	switch unit {
+	case query.TimeBucketMinute:
+		return fmt.Sprintf("CAST(DATE_FORMAT(%s, '%%Y-%%m-%%d %%H:%%i:00') AS DATETIME)", colName)
+	case query.TimeBucketHour:
+		return fmt.Sprintf("CAST(DATE_FORMAT(%s, '%%Y-%%m-%%d %%H:00:00') AS DATETIME)", colName)
+	case query.TimeBucketDay:
+		return fmt.Sprintf("CAST(DATE(%s) AS DATETIME)", colName)
+	case query.TimeBucketWeek:
+		return fmt.Sprintf("CAST(DATE_SUB(DATE(%s), INTERVAL WEEKDAY(%s) DAY) AS DATETIME)", colName, colName)
+	case query.TimeBucketMonth:
+		return fmt.Sprintf("CAST(DATE_FORMAT(%s, '%%Y-%%m-01') AS DATETIME)", colName)
+	default:
+		return fmt.Sprintf("CAST(DATE_FORMAT(%s, '%%Y-01-01') AS DATETIME)", colName)
+	}
+}
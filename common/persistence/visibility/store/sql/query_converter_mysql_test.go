@@ -130,15 +130,41 @@ func (s *mysqlQueryConverterSuite) TestConvertTextComparisonExpr() {
 		{
 			name:   "valid equal expression",
 			input:  "AliasForText01 = 'foo bar'",
-			output: "match(Text01) against ('foo bar' in natural language mode)",
+			output: "(match(text01_tokens) against ('foo__ bar__' in boolean mode) or text01_tokens is null and match(Text01) against ('foo bar' in boolean mode))",
 			err:    nil,
 		},
 		{
 			name:   "valid not equal expression",
 			input:  "AliasForText01 != 'foo bar'",
-			output: "not match(Text01) against ('foo bar' in natural language mode)",
+			output: "(Text01 is null or not (match(text01_tokens) against ('foo__ bar__' in boolean mode) or text01_tokens is null and match(Text01) against ('foo bar' in boolean mode)))",
 			err:    nil,
 		},
+		{
+			name:   "phrases and punctuation",
+			input:  `AliasForText01 = 'Quux, "foo bar" "Baz-Qux"'`,
+			output: `(match(text01_tokens) against ('quux__ "foo__ bar__" "baz__ qux__"' in boolean mode) or text01_tokens is null and match(Text01) against ('quux "foo bar" "baz qux"' in boolean mode))`,
+			err:    nil,
+		},
+		{
+			name:   "no tokens",
+			input:  "AliasForText01 = ' -- '",
+			output: "",
+			err: query.NewConverterError(
+				"%s: no tokens found in value of Text type search attribute: %q",
+				query.InvalidExpressionErrMessage,
+				" -- ",
+			),
+		},
+		{
+			name:   "unbalanced double quotes",
+			input:  `AliasForText01 = '"foo bar'`,
+			output: "",
+			err: query.NewConverterError(
+				"%s: unbalanced double quotes in value of Text type search attribute: %q",
+				query.InvalidExpressionErrMessage,
+				`\"foo bar`,
+			),
+		},
 	}
 
 	for _, tc := range tests {
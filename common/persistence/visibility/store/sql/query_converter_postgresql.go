@@ -26,6 +26,7 @@ package sql
 
 import (
 	"fmt"
+	"slices"
 	"strings"
 
 	"github.com/temporalio/sqlparser"
@@ -174,35 +175,34 @@ func (c *pgQueryConverter) convertTextComparisonExpr(
 			formatComparisonExprStringForError(*expr),
 		)
 	}
-	valueExpr, ok := expr.Right.(*unsafeSQLString)
-	if !ok {
-		return nil, query.NewConverterError(
-			"%s: unexpected value type (expected string, got %s)",
-			query.InvalidExpressionErrMessage,
-			sqlparser.String(expr.Right),
-		)
+	textQuery, err := parseTextQueryValue(expr)
+	if err != nil {
+		return nil, err
 	}
-	tokens := tokenizeTextQueryString(valueExpr.Val)
-	if len(tokens) == 0 {
-		return nil, query.NewConverterError(
-			"%s: unexpected value for Text type search attribute (no tokens found in %s)",
-			query.InvalidExpressionErrMessage,
-			sqlparser.String(expr.Right),
-		)
+	tokensColName, err := textSearchTokensColName(expr.Left)
+	if err != nil {
+		return nil, err
+	}
+	// build the following expression:
+	// `({tokens column} @@ 'token1 | token2 | (phrase <-> tokens)'::tsquery or
+	//   ({tokens column} is null and {expr.Left} @@ 'token1 | token2 | (phrase <-> tokens)'::tsquery))`
+	// The tokens are lexemes of the tsquery as they are, like in the tsvector of the tokens column.
+	terms := slices.Clone(textQuery.Tokens)
+	for _, phrase := range textQuery.Phrases {
+		terms = append(terms, fmt.Sprintf("(%s)", strings.Join(phrase, " <-> ")))
 	}
-	valueExpr.Val = strings.Join(tokens, " | ")
-	var newExpr sqlparser.Expr = &sqlparser.ComparisonExpr{
-		Operator: ftsMatchOp,
-		Left:     expr.Left,
-		Right: &pgCastExpr{
-			Value: expr.Right,
-			Type:  convertTypeTSQuery,
-		},
+	tsQuery := &pgCastExpr{
+		Value: newUnsafeSQLString(strings.Join(terms, " | ")),
+		Type:  convertTypeTSQuery,
 	}
+	tokensMatchExpr := &sqlparser.ComparisonExpr{Operator: ftsMatchOp, Left: tokensColName, Right: tsQuery}
+	matchExpr := &sqlparser.ComparisonExpr{Operator: ftsMatchOp, Left: expr.Left, Right: tsQuery}
 	if expr.Operator == sqlparser.NotEqualStr {
-		newExpr = &sqlparser.NotExpr{Expr: newExpr}
+		// The match with fallback is null rather than false for rows without tokens that don't
+		// match, so the negation uses coalesce instead.
+		return newTextNotMatchExpr(expr.Left, newFuncExpr(coalesceFuncName, tokensMatchExpr, matchExpr)), nil
 	}
-	return newExpr, nil
+	return newTextMatchWithFallbackExpr(tokensColName, tokensMatchExpr, matchExpr), nil
 }
 
 func (c *pgQueryConverter) newJsonContainsExpr(
@@ -305,3 +305,7 @@ func (c *pgQueryConverter) buildCountStmt(
 		groupByClause,
 	), queryArgs
 }
+
+func (c *pgQueryConverter) buildTimeBucketExpr(colName string, unit string) string {
+	return fmt.Sprintf("date_trunc('%s', %s)", unit, colName)
+}
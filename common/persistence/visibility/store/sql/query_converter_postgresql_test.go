@@ -130,15 +130,41 @@ func (s *postgresqlQueryConverterSuite) TestConvertTextComparisonExpr() {
 		{
 			name:   "valid equal expression",
 			input:  "AliasForText01 = 'foo bar'",
-			output: "Text01 @@ 'foo | bar'::tsquery",
+			output: "(text01_tokens @@ 'foo | bar'::tsquery or text01_tokens is null and Text01 @@ 'foo | bar'::tsquery)",
 			err:    nil,
 		},
 		{
 			name:   "valid not equal expression",
 			input:  "AliasForText01 != 'foo bar'",
-			output: "not Text01 @@ 'foo | bar'::tsquery",
+			output: "(Text01 is null or not coalesce(text01_tokens @@ 'foo | bar'::tsquery, Text01 @@ 'foo | bar'::tsquery))",
 			err:    nil,
 		},
+		{
+			name:   "phrases and punctuation",
+			input:  `AliasForText01 = 'Quux, "foo bar" "Baz-Qux"'`,
+			output: `(text01_tokens @@ 'quux | (foo <-> bar) | (baz <-> qux)'::tsquery or text01_tokens is null and Text01 @@ 'quux | (foo <-> bar) | (baz <-> qux)'::tsquery)`,
+			err:    nil,
+		},
+		{
+			name:   "no tokens",
+			input:  "AliasForText01 = ' -- '",
+			output: "",
+			err: query.NewConverterError(
+				"%s: no tokens found in value of Text type search attribute: %q",
+				query.InvalidExpressionErrMessage,
+				" -- ",
+			),
+		},
+		{
+			name:   "unbalanced double quotes",
+			input:  `AliasForText01 = '"foo bar'`,
+			output: "",
+			err: query.NewConverterError(
+				"%s: unbalanced double quotes in value of Text type search attribute: %q",
+				query.InvalidExpressionErrMessage,
+				`\"foo bar`,
+			),
+		},
 	}
 
 	for _, tc := range tests {
@@ -182,21 +182,9 @@ func (c *sqliteQueryConverter) convertTextComparisonExpr(
 		)
 	}
 
-	valueExpr, ok := expr.Right.(*unsafeSQLString)
-	if !ok {
-		return nil, query.NewConverterError(
-			"%s: unexpected value type (expected string, got %s)",
-			query.InvalidExpressionErrMessage,
-			sqlparser.String(expr.Right),
-		)
-	}
-	tokens := tokenizeTextQueryString(valueExpr.Val)
-	if len(tokens) == 0 {
-		return nil, query.NewConverterError(
-			"%s: unexpected value for Text type search attribute (no tokens found in %s)",
-			query.InvalidExpressionErrMessage,
-			sqlparser.String(expr.Right),
-		)
+	textQuery, err := parseTextQueryValue(expr)
+	if err != nil {
+		return nil, err
 	}
 
 	var oper string
@@ -215,7 +203,8 @@ func (c *sqliteQueryConverter) convertTextComparisonExpr(
 		)
 	}
 
-	ftsQuery := buildFtsQueryString(saColNameExpr.dbColName.Name, tokens...)
+	// Quoted terms with several tokens are phrases in FTS queries.
+	ftsQuery := buildFtsQueryString(saColNameExpr.dbColName.Name, textQuery.Terms()...)
 	newExpr := sqlparser.ComparisonExpr{
 		Operator: oper,
 		Left:     newColName("rowid"),
@@ -347,6 +336,24 @@ func (c *sqliteQueryConverter) buildCountStmt(
 	), queryArgs
 }
 
+func (c *sqliteQueryConverter) buildTimeBucketExpr(colName string, unit string) string {
+	switch unit {
+	case query.TimeBucketMinute:
+		return fmt.Sprintf("strftime('%%Y-%%m-%%d %%H:%%M:00', %s)", colName)
+	case query.TimeBucketHour:
+		return fmt.Sprintf("strftime('%%Y-%%m-%%d %%H:00:00', %s)", colName)
+	case query.TimeBucketDay:
+		return fmt.Sprintf("strftime('%%Y-%%m-%%d 00:00:00', %s)", colName)
+	case query.TimeBucketWeek:
+		// Moves to the next Sunday unless it's a Sunday already, then back to Monday.
+		return fmt.Sprintf("strftime('%%Y-%%m-%%d 00:00:00', %s, 'weekday 0', '-6 days')", colName)
+	case query.TimeBucketMonth:
+		return fmt.Sprintf("strftime('%%Y-%%m-01 00:00:00', %s)", colName)
+	default:
+		return fmt.Sprintf("strftime('%%Y-01-01 00:00:00', %s)", colName)
+	}
+}
+
 func buildFtsQueryString(colname string, values ...string) string {
 	// FTS query format: 'colname : ("token1" OR "token2" OR ...)'
 	return fmt.Sprintf(`%s : ("%s")`, colname, strings.Join(values, `" OR "`))
@@ -139,6 +139,32 @@ func (s *sqliteQueryConverterSuite) TestConvertTextComparisonExpr() {
 			output: `rowid not in (select rowid from executions_visibility_fts_text where executions_visibility_fts_text = 'Text01 : ("foo" OR "bar")')`,
 			err:    nil,
 		},
+		{
+			name:   "phrases and punctuation",
+			input:  `AliasForText01 = 'Quux, "foo bar" "Baz-Qux"'`,
+			output: `rowid in (select rowid from executions_visibility_fts_text where executions_visibility_fts_text = 'Text01 : ("quux" OR "foo bar" OR "baz qux")')`,
+			err:    nil,
+		},
+		{
+			name:   "no tokens",
+			input:  "AliasForText01 = ' -- '",
+			output: "",
+			err: query.NewConverterError(
+				"%s: no tokens found in value of Text type search attribute: %q",
+				query.InvalidExpressionErrMessage,
+				" -- ",
+			),
+		},
+		{
+			name:   "unbalanced double quotes",
+			input:  `AliasForText01 = '"foo bar'`,
+			output: "",
+			err: query.NewConverterError(
+				"%s: unbalanced double quotes in value of Text type search attribute: %q",
+				query.InvalidExpressionErrMessage,
+				`\"foo bar`,
+			),
+		},
 	}
 
 	for _, tc := range tests {
@@ -120,28 +120,88 @@ func (s *queryConverterSuite) TestConvertWhereString() {
 			name:  "group by one field",
 			input: "GROUP BY ExecutionStatus",
 			output: &queryParams{
-				queryString: "TemporalNamespaceDivision is null",
+				queryString: "TemporalNamespaceDivision is null and status is not null",
 				groupBy:     []string{searchattribute.ExecutionStatus},
 			},
 			err: nil,
 		},
 		{
-			name:   "group by two fields not supported",
-			input:  "GROUP BY ExecutionStatus, WorkflowType",
+			name:  "group by multiple fields",
+			input: "GROUP BY ExecutionStatus, WorkflowType",
+			output: &queryParams{
+				queryString: "TemporalNamespaceDivision is null and status is not null and workflow_type_name is not null",
+				groupBy:     []string{searchattribute.ExecutionStatus, searchattribute.WorkflowType},
+			},
+			err: nil,
+		},
+		{
+			name:  "group by field and time bucket",
+			input: "WorkflowType = 'foo' GROUP BY WorkflowType, date_trunc('day', StartTime)",
+			output: &queryParams{
+				queryString: "(workflow_type_name = 'foo') and TemporalNamespaceDivision is null and " +
+					"workflow_type_name is not null and start_time is not null",
+				groupBy:     []string{searchattribute.WorkflowType, searchattribute.StartTime},
+				timeBuckets: map[string]string{searchattribute.StartTime: query.TimeBucketDay},
+			},
+			err: nil,
+		},
+		{
+			name:   "group by too many fields",
+			input:  "GROUP BY ExecutionStatus, WorkflowType, TaskQueue, RunId",
+			output: nil,
+			err: query.NewConverterError(
+				"%s: 'group by' clause supports at most %d fields",
+				query.NotSupportedErrMessage,
+				query.MaxGroupByFields,
+			),
+		},
+		{
+			name:   "group by repeated field",
+			input:  "GROUP BY WorkflowType, WorkflowType",
+			output: nil,
+			err: query.NewConverterError(
+				"%s: field '%s' is repeated in 'group by' clause",
+				query.InvalidExpressionErrMessage,
+				searchattribute.WorkflowType,
+			),
+		},
+		{
+			name:   "group by non keyword field",
+			input:  "GROUP BY StartTime",
 			output: nil,
 			err: query.NewConverterError(
-				"%s: 'group by' clause supports only a single field",
+				"%s: 'group by' clause is only supported for search attributes of type %s, "+
+					"use %s to group %s search attributes (got '%s' of type %s)",
 				query.NotSupportedErrMessage,
+				enumspb.INDEXED_VALUE_TYPE_KEYWORD.String(),
+				query.GroupByTimeBucketFunc,
+				enumspb.INDEXED_VALUE_TYPE_DATETIME.String(),
+				searchattribute.StartTime,
+				enumspb.INDEXED_VALUE_TYPE_DATETIME.String(),
 			),
 		},
 		{
-			name:   "group by non ExecutionStatus",
-			input:  "GROUP BY WorkflowType",
+			name:   "group by time bucket of non datetime field",
+			input:  "GROUP BY date_trunc('day', WorkflowType)",
 			output: nil,
 			err: query.NewConverterError(
-				"%s: 'group by' clause is only supported for %s search attribute",
+				"%s: '%s' is only supported for search attributes of type %s (got '%s' of type %s)",
 				query.NotSupportedErrMessage,
-				searchattribute.ExecutionStatus,
+				query.GroupByTimeBucketFunc,
+				enumspb.INDEXED_VALUE_TYPE_DATETIME.String(),
+				searchattribute.WorkflowType,
+				enumspb.INDEXED_VALUE_TYPE_KEYWORD.String(),
+			),
+		},
+		{
+			name:   "group by unsupported time unit",
+			input:  "GROUP BY date_trunc('fortnight', StartTime)",
+			output: nil,
+			err: query.NewConverterError(
+				"%s: unsupported time unit '%s', supported units are: %s",
+				query.InvalidExpressionErrMessage,
+				"fortnight",
+				"minute, hour, day, week, month, year",
 			),
 		},
 		{
@@ -25,12 +25,12 @@
 package sql
 
 import (
-	"strings"
 	"time"
 
 	"github.com/temporalio/sqlparser"
 
 	enumspb "go.temporal.io/api/enums/v1"
+	"go.temporal.io/server/common/persistence/sql/sqlplugin"
 	"go.temporal.io/server/common/persistence/visibility/store/query"
 	"go.temporal.io/server/common/searchattribute"
 )
@@ -55,6 +55,12 @@ type (
 		fieldName string
 		valueType enumspb.IndexedValueType
 	}
+
+	// timeBucketColName is a datetime search attribute grouped by time bucket.
+	timeBucketColName struct {
+		*saColName
+		unit string
+	}
 )
 
 const (
@@ -64,6 +70,7 @@ const (
 var _ sqlparser.Expr = (*unsafeSQLString)(nil)
 var _ sqlparser.Expr = (*colName)(nil)
 var _ sqlparser.Expr = (*saColName)(nil)
+var _ sqlparser.Expr = (*timeBucketColName)(nil)
 
 var (
 	maxDatetimeValue = getMaxDatetimeValue()
@@ -110,6 +117,13 @@ func newSAColName(
 	}
 }
 
+func newTimeBucketColName(colName *saColName, unit string) *timeBucketColName {
+	return &timeBucketColName{
+		saColName: colName,
+		unit:      unit,
+	}
+}
+
 func newFuncExpr(name string, exprs ...sqlparser.Expr) *sqlparser.FuncExpr {
 	args := make([]sqlparser.SelectExpr, len(exprs))
 	for i := range exprs {
@@ -144,17 +158,60 @@ func formatComparisonExprStringForError(expr sqlparser.ComparisonExpr) string {
 	return sqlparser.String(&expr)
 }
 
-// Simple tokenizer by spaces. It's a temporary solution as it doesn't cover tokenizer used by
-// PostgreSQL or SQLite.
-func tokenizeTextQueryString(s string) []string {
-	tokens := strings.Split(s, " ")
-	nonEmptyTokens := make([]string, 0, len(tokens))
-	for _, token := range tokens {
-		if token != "" {
-			nonEmptyTokens = append(nonEmptyTokens, token)
-		}
+// parseTextQueryValue parses the value of a comparison expression on a Text search attribute
+// with the tokenization rules shared by all visibility stores.
+func parseTextQueryValue(expr *sqlparser.ComparisonExpr) (*query.TextQuery, error) {
+	valueExpr, ok := expr.Right.(*unsafeSQLString)
+	if !ok {
+		return nil, query.NewConverterError(
+			"%s: unexpected value type (expected string, got %s)",
+			query.InvalidExpressionErrMessage,
+			sqlparser.String(expr.Right),
+		)
+	}
+	return query.ParseTextQuery(valueExpr.Val)
+}
+
+// textSearchTokensColName returns the token column of a Text search attribute, see sqlplugin.TextSearchTokensRow.
+func textSearchTokensColName(expr sqlparser.Expr) (*colName, error) {
+	saColNameExpr, ok := expr.(*saColName)
+	if !ok {
+		return nil, query.NewConverterError(
+			"%s: unexpected Text type search attribute column %s",
+			query.InvalidExpressionErrMessage,
+			sqlparser.String(expr),
+		)
+	}
+	return newColName(sqlplugin.TextSearchTokensColName(saColNameExpr.dbColName.Name)), nil
+}
+
+// newTextMatchWithFallbackExpr matches the token column of a Text search attribute. Rows written before
+// the token columns were added have no tokens, and are matched against the search attribute column.
+func newTextMatchWithFallbackExpr(
+	tokensColName sqlparser.Expr,
+	tokensMatchExpr sqlparser.Expr,
+	matchExpr sqlparser.Expr,
+) sqlparser.Expr {
+	return &sqlparser.ParenExpr{
+		Expr: &sqlparser.OrExpr{
+			Left: tokensMatchExpr,
+			Right: &sqlparser.AndExpr{
+				Left:  &sqlparser.IsExpr{Operator: sqlparser.IsNullStr, Expr: tokensColName},
+				Right: matchExpr,
+			},
+		},
+	}
+}
+
+// newTextNotMatchExpr negates the full text match expression on a Text search attribute column.
+// Executions without a value match the negated expression like they do in Elasticsearch.
+func newTextNotMatchExpr(colName sqlparser.Expr, matchExpr sqlparser.Expr) sqlparser.Expr {
+	return &sqlparser.ParenExpr{
+		Expr: &sqlparser.OrExpr{
+			Left:  &sqlparser.IsExpr{Operator: sqlparser.IsNullStr, Expr: colName},
+			Right: &sqlparser.NotExpr{Expr: matchExpr},
+		},
 	}
-	return nonEmptyTokens
 }
 
 func getUnsafeStringTupleValues(valTuple sqlparser.ValTuple) ([]string, error) {
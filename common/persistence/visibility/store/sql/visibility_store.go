@@ -439,7 +439,7 @@ func (s *VisibilityStore) CountWorkflowExecutions(
 	}
 
 	if len(selectFilter.GroupBy) > 0 {
-		return s.countGroupByWorkflowExecutions(ctx, selectFilter, saTypeMap)
+		return s.countGroupByWorkflowExecutions(ctx, selectFilter, saTypeMap, store.GroupsLimit(request))
 	}
 
 	count, err := s.sqlStore.Db.CountFromVisibility(ctx, *selectFilter)
@@ -455,6 +455,7 @@ func (s *VisibilityStore) countGroupByWorkflowExecutions(
 	ctx context.Context,
 	selectFilter *sqlplugin.VisibilitySelectFilter,
 	saTypeMap searchattribute.NameTypeMap,
+	groupsLimit int,
 ) (*manager.CountWorkflowExecutionsResponse, error) {
 	var err error
 	groupByTypes := make([]enumspb.IndexedValueType, len(selectFilter.GroupBy))
@@ -465,6 +466,8 @@ func (s *VisibilityStore) countGroupByWorkflowExecutions(
 		}
 	}
 
+	// Query one more group than the limit, to tell whether it was exceeded.
+	selectFilter.Query = fmt.Sprintf("%s LIMIT %d", selectFilter.Query, groupsLimit+1)
 	rows, err := s.sqlStore.Db.CountGroupByFromVisibility(ctx, *selectFilter)
 	if err != nil {
 		return nil, serviceerror.NewUnavailable(
@@ -477,6 +480,12 @@ func (s *VisibilityStore) countGroupByWorkflowExecutions(
 	for _, row := range rows {
 		groupValues := make([]*common.Payload, len(row.GroupValues))
 		for i, val := range row.GroupValues {
+			if groupByTypes[i] == enumspb.INDEXED_VALUE_TYPE_DATETIME {
+				val, err = parseTimeBucket(val)
+				if err != nil {
+					return nil, err
+				}
+			}
 			groupValues[i], err = searchattribute.EncodeValue(val, groupByTypes[i])
 			if err != nil {
 				return nil, err
@@ -491,9 +500,28 @@ func (s *VisibilityStore) countGroupByWorkflowExecutions(
 		)
 		resp.Count += row.Count
 	}
+	if err := store.FinishCountGroups(resp.Groups, groupsLimit); err != nil {
+		return nil, err
+	}
 	return resp, nil
 }
 
+// parseTimeBucket parses the start of a time bucket, which database drivers return either as time or as text.
+func parseTimeBucket(value any) (time.Time, error) {
+	switch v := value.(type) {
+	case time.Time:
+		return v.UTC(), nil
+	case string:
+		return time.ParseInLocation(time.DateTime, v, time.UTC)
+	case []byte:
+		return time.ParseInLocation(time.DateTime, string(v), time.UTC)
+	default:
+		return time.Time{}, serviceerror.NewInternal(
+			fmt.Sprintf("Unable to parse time bucket from DB (got: %v of type: %T)", value, value),
+		)
+	}
+}
+
 func (s *VisibilityStore) GetWorkflowExecution(
 	ctx context.Context,
 	request *manager.GetWorkflowExecutionRequest,
@@ -518,7 +546,7 @@ func (s *VisibilityStore) GetWorkflowExecution(
 func (s *VisibilityStore) generateVisibilityRow(
 	request *store.InternalVisibilityRequestBase,
 ) (*sqlplugin.VisibilityRow, error) {
-	searchAttributes, err := s.prepareSearchAttributesForDb(request)
+	searchAttributes, textSearchTokens, err := s.prepareSearchAttributesForDb(request)
 	if err != nil {
 		return nil, err
 	}
@@ -537,14 +565,15 @@ func (s *VisibilityStore) generateVisibilityRow(
 		SearchAttributes: searchAttributes,
 		ParentWorkflowID: request.ParentWorkflowID,
 		ParentRunID:      request.ParentRunID,
+		TextSearchTokens: textSearchTokens,
 	}, nil
 }
 
 func (s *VisibilityStore) prepareSearchAttributesForDb(
 	request *store.InternalVisibilityRequestBase,
-) (*sqlplugin.VisibilitySearchAttributes, error) {
+) (*sqlplugin.VisibilitySearchAttributes, map[string][]string, error) {
 	if request.SearchAttributes == nil {
-		return nil, nil
+		return nil, nil, nil
 	}
 
 	saTypeMap, err := s.searchAttributesProvider.GetSearchAttributes(
@@ -552,21 +581,22 @@ func (s *VisibilityStore) prepareSearchAttributesForDb(
 		false,
 	)
 	if err != nil {
-		return nil, serviceerror.NewUnavailable(
+		return nil, nil, serviceerror.NewUnavailable(
 			fmt.Sprintf("Unable to read search attributes types: %v", err))
 	}
 
 	var searchAttributes sqlplugin.VisibilitySearchAttributes
+	var textSearchTokens map[string][]string
 	searchAttributes, err = searchattribute.Decode(request.SearchAttributes, &saTypeMap, false)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 	// This is to prevent existing tasks to fail indefinitely.
 	// If it's only invalid values error, then silently continue without them.
 	searchAttributes, err = s.ValidateCustomSearchAttributes(searchAttributes)
 	if err != nil {
 		if _, ok := err.(*store.VisibilityStoreInvalidValuesError); !ok {
-			return nil, err
+			return nil, nil, err
 		}
 	}
 
@@ -577,15 +607,24 @@ func (s *VisibilityStore) prepareSearchAttributesForDb(
 		}
 		tp, err := saTypeMap.GetType(name)
 		if err != nil {
-			return nil, err
+			return nil, nil, err
 		}
-		if tp == enumspb.INDEXED_VALUE_TYPE_DATETIME {
+		switch tp {
+		case enumspb.INDEXED_VALUE_TYPE_DATETIME:
 			if dt, ok := value.(time.Time); ok {
 				searchAttributes[name] = dt.Format(time.RFC3339Nano)
 			}
+		case enumspb.INDEXED_VALUE_TYPE_TEXT:
+			// MySQL and PostgreSQL index the tokens instead of the value
+			if text, ok := value.(string); ok {
+				if textSearchTokens == nil {
+					textSearchTokens = make(map[string][]string)
+				}
+				textSearchTokens[name] = query.TokenizeText(text)
+			}
 		}
 	}
-	return &searchAttributes, nil
+	return &searchAttributes, textSearchTokens, nil
 }
 
 func (s *VisibilityStore) rowToInfo(
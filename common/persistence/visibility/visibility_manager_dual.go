@@ -26,6 +26,9 @@ package visibility
 
 import (
 	"context"
+	"fmt"
+
+	"go.temporal.io/api/serviceerror"
 
 	"go.temporal.io/server/common/namespace"
 	"go.temporal.io/server/common/persistence/visibility/manager"
@@ -55,6 +58,31 @@ func NewVisibilityManagerDual(
 	}
 }
 
+// ManagerForStore returns the manager which writes to the visibility store with the given name, looking into
+// both stores of a dual visibility manager. An empty name selects the secondary store of a dual visibility
+// manager, or the only store otherwise.
+func ManagerForStore(
+	visibilityManager manager.VisibilityManager,
+	storeName string,
+) (manager.VisibilityManager, error) {
+	dual, isDual := visibilityManager.(*visibilityManagerDual)
+	switch {
+	case isDual && storeName == "":
+		return dual.secondaryVisibilityManager, nil
+	case isDual && dual.visibilityManager.HasStoreName(storeName):
+		return dual.visibilityManager, nil
+	case isDual && dual.secondaryVisibilityManager.HasStoreName(storeName):
+		return dual.secondaryVisibilityManager, nil
+	case !isDual && (storeName == "" || visibilityManager.HasStoreName(storeName)):
+		return visibilityManager, nil
+	}
+	return nil, serviceerror.NewInvalidArgument(fmt.Sprintf(
+		"visibility store %q is not configured, configured stores are %v",
+		storeName,
+		visibilityManager.GetStoreNames(),
+	))
+}
+
 func (v *visibilityManagerDual) Close() {
 	v.visibilityManager.Close()
 	v.secondaryVisibilityManager.Close()
@@ -0,0 +1,71 @@
+// The MIT License
+//
+// Copyright (c) 2020 Temporal Technologies Inc.  All rights reserved.
+//
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package visibility
+
+import (
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+	"go.temporal.io/api/serviceerror"
+
+	"go.temporal.io/server/common/persistence/visibility/manager"
+)
+
+func TestManagerForStore(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	newManager := func(storeName string) *manager.MockVisibilityManager {
+		m := manager.NewMockVisibilityManager(ctrl)
+		m.EXPECT().HasStoreName(gomock.Any()).DoAndReturn(func(name string) bool {
+			return name == storeName
+		}).AnyTimes()
+		m.EXPECT().GetStoreNames().Return([]string{storeName}).AnyTimes()
+		return m
+	}
+	primary := newManager("mysql")
+	secondary := newManager("elasticsearch")
+	dual := NewVisibilityManagerDual(primary, secondary, nil)
+
+	m, err := ManagerForStore(dual, "")
+	require.NoError(t, err)
+	require.Same(t, secondary, m)
+	m, err = ManagerForStore(dual, "mysql")
+	require.NoError(t, err)
+	require.Same(t, primary, m)
+	m, err = ManagerForStore(dual, "elasticsearch")
+	require.NoError(t, err)
+	require.Same(t, secondary, m)
+
+	m, err = ManagerForStore(primary, "")
+	require.NoError(t, err)
+	require.Same(t, primary, m)
+	m, err = ManagerForStore(primary, "mysql")
+	require.NoError(t, err)
+	require.Same(t, primary, m)
+
+	_, err = ManagerForStore(primary, "elasticsearch")
+	var invalidArgument *serviceerror.InvalidArgument
+	require.ErrorAs(t, err, &invalidArgument)
+}
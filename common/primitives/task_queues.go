@@ -33,4 +33,6 @@ const (
 	AddSearchAttributesActivityTQ = "temporal-sys-add-search-attributes-activity-tq"
 	DeleteNamespaceActivityTQ     = "temporal-sys-delete-namespace-activity-tq"
 	DLQActivityTQ                 = "temporal-sys-dlq-activity-tq"
+	ShardMigrationActivityTQ      = "temporal-sys-shard-migration-activity-tq"
+	VisibilityReindexActivityTQ   = "temporal-sys-visibility-reindex-activity-tq"
 )
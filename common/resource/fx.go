@@ -55,6 +55,7 @@ import (
 	"go.temporal.io/server/common/log/tag"
 	"go.temporal.io/server/common/membership"
 	"go.temporal.io/server/common/membership/ringpop"
+	"go.temporal.io/server/common/membership/static"
 	"go.temporal.io/server/common/metrics"
 	"go.temporal.io/server/common/namespace"
 	"go.temporal.io/server/common/persistence"
@@ -130,7 +131,6 @@ var Module = fx.Options(
 )
 
 var DefaultOptions = fx.Options(
-	ringpop.Module,
 	fx.Provide(RPCFactoryProvider),
 	fx.Provide(ArchivalMetadataProvider),
 	fx.Provide(ArchiverProviderProvider),
@@ -139,6 +139,17 @@ var DefaultOptions = fx.Options(
 	fx.Provide(DCRedirectionPolicyProvider),
 )
 
+// MembershipModule returns the module providing membership.Monitor and membership.HostInfoProvider
+// for the provider selected in the membership config.
+func MembershipModule(cfg *config.Membership) fx.Option {
+	switch cfg.Provider {
+	case config.MembershipProviderStatic, config.MembershipProviderDNS:
+		return static.Module
+	default:
+		return ringpop.Module
+	}
+}
+
 func DefaultSnTaggedLoggerProvider(logger log.Logger, sn primitives.ServiceName) log.SnTaggedLogger {
 	return log.With(logger, tag.Service(sn))
 }
@@ -353,7 +364,7 @@ func ArchivalMetadataProvider(dc *dynamicconfig.Collection, cfg *config.Config)
 }
 
 func ArchiverProviderProvider(cfg *config.Config) provider.ArchiverProvider {
-	return provider.NewArchiverProvider(cfg.Archival.History.Provider, cfg.Archival.Visibility.Provider)
+	return provider.NewArchiverProvider(cfg.Archival.History.Provider, cfg.Archival.Visibility.Provider, cfg.Archival.History.Compression)
 }
 
 func SdkClientFactoryProvider(
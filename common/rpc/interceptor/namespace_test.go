@@ -74,6 +74,8 @@ var (
 		"DeleteDLQTasks":            {},
 		"AddTasks":                  {},
 		"ListQueues":                {},
+		"DrainHistoryHost":          {},
+		"UndrainHistoryHost":        {},
 	}
 )
 
@@ -142,7 +142,7 @@ func (d *RPCFactory) GetInternodeClientTlsConfig() (*tls.Config, error) {
 // GetGRPCListener returns cached dispatcher for gRPC inbound or creates one
 func (d *RPCFactory) GetGRPCListener() net.Listener {
 	d.initListener.Do(func() {
-		hostAddress := net.JoinHostPort(getListenIP(d.config, d.logger).String(), convert.IntToString(d.config.GRPCPort))
+		hostAddress := net.JoinHostPort(GetListenIP(d.config, d.logger).String(), convert.IntToString(d.config.GRPCPort))
 		var err error
 		d.grpcListener, err = net.Listen("tcp", hostAddress)
 
@@ -156,7 +156,8 @@ func (d *RPCFactory) GetGRPCListener() net.Listener {
 	return d.grpcListener
 }
 
-func getListenIP(cfg *config.RPC, logger log.Logger) net.IP {
+// GetListenIP returns the IP the gRPC listener binds on for the given config
+func GetListenIP(cfg *config.RPC, logger log.Logger) net.IP {
 	if cfg.BindOnLocalHost && len(cfg.BindOnIP) > 0 {
 		logger.Fatal("ListenIP failed, bindOnLocalHost and bindOnIP are mutually exclusive")
 		return nil
@@ -177,6 +177,13 @@ func IsReserved(name string) bool {
 	return strings.HasPrefix(name, ReservedPrefix)
 }
 
+// IsSystem returns true if name is a system search attribute which is stored as a separate field
+// rather than in the SearchAttributes object.
+func IsSystem(name string) bool {
+	_, ok := system[name]
+	return ok
+}
+
 // IsMappable returns true if name can have be mapped tho the alias.
 func IsMappable(name string) bool {
 	if _, ok := system[name]; ok {
@@ -37,6 +37,7 @@ const (
 
 	suffixDelimiter     = "/"
 	versionSetDelimiter = ":"
+	priorityDelimiter   = "#"
 )
 
 type (
@@ -68,10 +69,18 @@ type (
 	//
 	// For backward compatibility, unversioned low-level task queues with partition 0 do not
 	// use mangled names, they use the bare base name.
+	//
+	// The backlog of a low-level task queue can be split into subqueues by task priority, so
+	// that urgent tasks are read from persistence before the tasks in front of them. Subqueues
+	// only exist in persistence, they are managed by the task queue manager of their low-level
+	// task queue. They always use mangled names, with the priority appended to the partition id:
+	//
+	//   /_sys/<base name>/[<version set id>:]<partition id>#<priority>
 	Name struct {
 		baseName   string // base name of the task queue as specified by user
 		partition  int    // partition of task queue
 		versionSet string // version set id
+		priority   int    // task priority of the backlog subqueue, 0 for the task queue itself
 	}
 )
 
@@ -86,6 +95,7 @@ func Parse(name string) (Name, error) {
 	baseName := name
 	partition := 0
 	versionSet := ""
+	priority := 0
 
 	if strings.HasPrefix(name, mangledTaskQueuePrefix) {
 		suffixOff := strings.LastIndex(name, suffixDelimiter)
@@ -95,6 +105,15 @@ func Parse(name string) (Name, error) {
 		baseName = name[len(mangledTaskQueuePrefix):suffixOff]
 
 		suffix := name[suffixOff+1:]
+		if priorityOff := strings.LastIndex(suffix, priorityDelimiter); priorityOff >= 0 {
+			// pull out priority
+			var err error
+			priority, err = strconv.Atoi(suffix[priorityOff+1:])
+			if err != nil || priority <= 0 {
+				return Name{}, fmt.Errorf("invalid task queue name %q", name)
+			}
+			suffix = suffix[:priorityOff]
+		}
 		if partitionOff := strings.LastIndex(suffix, versionSetDelimiter); partitionOff == 0 {
 			return Name{}, fmt.Errorf("invalid task queue name %q", name)
 		} else if partitionOff > 0 {
@@ -104,7 +123,7 @@ func Parse(name string) (Name, error) {
 
 		var err error
 		partition, err = strconv.Atoi(suffix)
-		if err != nil || partition < 0 || partition == 0 && len(versionSet) == 0 {
+		if err != nil || partition < 0 || partition == 0 && len(versionSet) == 0 && priority == 0 {
 			return Name{}, fmt.Errorf("invalid task queue name %q", name)
 		}
 	}
@@ -113,6 +132,7 @@ func Parse(name string) (Name, error) {
 		baseName:   baseName,
 		partition:  partition,
 		versionSet: versionSet,
+		priority:   priority,
 	}, nil
 }
 
@@ -152,6 +172,14 @@ func (tn Name) WithVersionSet(versionSet string) Name {
 // BaseNameString returns the base name for a task queue. This should be used when looking up
 // settings in dynamic config, and pretty much nowhere else. To get the name of the root
 // partition, use tn.Root().FullName().
+// WithPriority returns the name of the backlog subqueue of the given task priority. A priority
+// of 0 returns the name of the low-level task queue itself.
+func (tn Name) WithPriority(priority int) Name {
+	nn := tn
+	nn.priority = priority
+	return nn
+}
+
 func (tn Name) BaseNameString() string {
 	return tn.baseName
 }
@@ -166,6 +194,10 @@ func (tn Name) VersionSet() string {
 	return tn.versionSet
 }
 
+func (tn Name) Priority() int {
+	return tn.priority
+}
+
 // Parent returns a Name for the parent partition, using the given branching degree.
 func (tn Name) Parent(degree int) (Name, error) {
 	if tn.IsRoot() {
@@ -179,12 +211,18 @@ func (tn Name) Parent(degree int) (Name, error) {
 
 // FullName returns the mangled name of the task queue, to be used in RPCs and persistence.
 func (tn Name) FullName() string {
+	var fullName string
 	if len(tn.versionSet) == 0 {
-		if tn.partition == 0 {
+		if tn.partition == 0 && tn.priority == 0 {
 			return tn.baseName
 		}
-		return fmt.Sprintf("%s%s%s%d", mangledTaskQueuePrefix, tn.baseName, suffixDelimiter, tn.partition)
+		fullName = fmt.Sprintf("%s%s%s%d", mangledTaskQueuePrefix, tn.baseName, suffixDelimiter, tn.partition)
+	} else {
+		// versioned always use prefix
+		fullName = fmt.Sprintf("%s%s%s%s%s%d", mangledTaskQueuePrefix, tn.baseName, suffixDelimiter, tn.versionSet, versionSetDelimiter, tn.partition)
+	}
+	if tn.priority == 0 {
+		return fullName
 	}
-	// versioned always use prefix
-	return fmt.Sprintf("%s%s%s%s%s%d", mangledTaskQueuePrefix, tn.baseName, suffixDelimiter, tn.versionSet, versionSetDelimiter, tn.partition)
+	return fmt.Sprintf("%s%s%d", fullName, priorityDelimiter, tn.priority)
 }
@@ -61,6 +61,14 @@ func TestParse(t *testing.T) {
 	a.Equal(23, n.Partition())
 	a.Equal("verxyz", n.VersionSet())
 	a.Equal("/_sys/my-basic-tq-name/verxyz:23", n.FullName())
+
+	n, err = Parse("/_sys/my-basic-tq-name/verxyz:23#1")
+	a.NoError(err)
+	a.Equal("my-basic-tq-name", n.BaseNameString())
+	a.Equal(23, n.Partition())
+	a.Equal("verxyz", n.VersionSet())
+	a.Equal(1, n.Priority())
+	a.Equal("/_sys/my-basic-tq-name/verxyz:23#1", n.FullName())
 }
 
 func TestFromBaseName(t *testing.T) {
@@ -111,6 +119,22 @@ func TestWithPartitionAndVersionSet(t *testing.T) {
 	a.Equal("/_sys/tq/abc3:11", n.FullName())
 }
 
+func TestWithPriority(t *testing.T) {
+	a := assert.New(t)
+
+	n, err := FromBaseName("tq")
+	a.NoError(err)
+	n = n.WithPriority(5)
+	a.Equal("tq", n.BaseNameString())
+	a.Equal(0, n.Partition())
+	a.Equal(5, n.Priority())
+	a.True(n.IsRoot())
+	a.Equal("/_sys/tq/0#5", n.FullName())
+	a.Equal("/_sys/tq/3#5", n.WithPartition(3).FullName())
+	a.Equal("/_sys/tq/abc3:3#5", n.WithPartition(3).WithVersionSet("abc3").FullName())
+	a.Equal("tq", n.WithPriority(0).FullName())
+}
+
 func TestValidTaskQueueNames(t *testing.T) {
 	testCases := []struct {
 		input     string
@@ -127,6 +151,8 @@ func TestValidTaskQueueNames(t *testing.T) {
 		{"/_sys/list0/1", "list0", 1},
 		{"/_sys//list0//41", "/list0/", 41},
 		{"/_sys//_sys/sys/0/41", "/_sys/sys/0", 41},
+		{"/_sys/list0/0#1", "list0", 0},
+		{"/_sys/list#0/1#5", "list#0", 1},
 	}
 
 	for _, tc := range testCases {
@@ -199,6 +225,10 @@ func TestInvalidTaskqueueNames(t *testing.T) {
 		"/_sys/list0/-1",
 		"/_sys/list0/abc",
 		"/_sys/list0:verxyz:23",
+		"/_sys/list0/#1",
+		"/_sys/list0/1#0",
+		"/_sys/list0/1#-1",
+		"/_sys/list0/1#abc",
 	}
 	for _, name := range inputs {
 		t.Run(name, func(t *testing.T) {
@@ -121,6 +121,12 @@ CREATE TABLE custom_search_attributes (
   KeywordList02     JSON            GENERATED ALWAYS AS (search_attributes->"$.KeywordList02"),
   KeywordList03     JSON            GENERATED ALWAYS AS (search_attributes->"$.KeywordList03"),
 
+  -- Text search attributes tokenized by the server, so every visibility store splits text the same way.
+  -- They are NULL for rows written before v1.5, which are still matched against the Text columns.
+  text01_tokens     TEXT            NULL,
+  text02_tokens     TEXT            NULL,
+  text03_tokens     TEXT            NULL,
+
   PRIMARY KEY (namespace_id, run_id)
 );
 
@@ -149,6 +155,9 @@ CREATE INDEX by_keyword_10        ON custom_search_attributes (namespace_id, Key
 CREATE FULLTEXT INDEX by_text_01  ON custom_search_attributes (Text01);
 CREATE FULLTEXT INDEX by_text_02  ON custom_search_attributes (Text02);
 CREATE FULLTEXT INDEX by_text_03  ON custom_search_attributes (Text03);
+CREATE FULLTEXT INDEX by_text_01_tokens ON custom_search_attributes (text01_tokens);
+CREATE FULLTEXT INDEX by_text_02_tokens ON custom_search_attributes (text02_tokens);
+CREATE FULLTEXT INDEX by_text_03_tokens ON custom_search_attributes (text03_tokens);
 CREATE INDEX by_keyword_list_01   ON custom_search_attributes (namespace_id, (CAST(KeywordList01 AS CHAR(255) ARRAY)));
 CREATE INDEX by_keyword_list_02   ON custom_search_attributes (namespace_id, (CAST(KeywordList02 AS CHAR(255) ARRAY)));
 CREATE INDEX by_keyword_list_03   ON custom_search_attributes (namespace_id, (CAST(KeywordList03 AS CHAR(255) ARRAY)));
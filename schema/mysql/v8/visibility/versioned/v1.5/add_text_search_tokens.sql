@@ -0,0 +1,10 @@
+-- Building a FULLTEXT index blocks writes to custom_search_attributes until it completes, and
+-- visibility updates fail in the meantime. On large tables, run this during a maintenance window.
+-- Rows written before this version keep NULL tokens and are matched against the Text columns
+-- until they are updated or reindexed.
+ALTER TABLE custom_search_attributes ADD COLUMN text01_tokens TEXT NULL;
+ALTER TABLE custom_search_attributes ADD COLUMN text02_tokens TEXT NULL;
+ALTER TABLE custom_search_attributes ADD COLUMN text03_tokens TEXT NULL;
+CREATE FULLTEXT INDEX by_text_01_tokens ON custom_search_attributes (text01_tokens);
+CREATE FULLTEXT INDEX by_text_02_tokens ON custom_search_attributes (text02_tokens);
+CREATE FULLTEXT INDEX by_text_03_tokens ON custom_search_attributes (text03_tokens);
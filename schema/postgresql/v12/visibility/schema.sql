@@ -72,6 +72,12 @@ CREATE TABLE executions_visibility (
   KeywordList02   JSONB           GENERATED ALWAYS AS (search_attributes->'KeywordList02')            STORED,
   KeywordList03   JSONB           GENERATED ALWAYS AS (search_attributes->'KeywordList03')            STORED,
 
+  -- Text search attributes tokenized by the server, so every visibility store splits text the same way.
+  -- They are NULL for rows written before v1.5, which are still matched against the Text columns.
+  text01_tokens   TSVECTOR        NULL,
+  text02_tokens   TSVECTOR        NULL,
+  text03_tokens   TSVECTOR        NULL,
+
   PRIMARY KEY  (namespace_id, run_id)
 );
 
@@ -124,6 +130,9 @@ CREATE INDEX by_keyword_10      ON executions_visibility (namespace_id, Keyword1
 CREATE INDEX by_text_01         ON executions_visibility USING GIN (namespace_id, Text01);
 CREATE INDEX by_text_02         ON executions_visibility USING GIN (namespace_id, Text02);
 CREATE INDEX by_text_03         ON executions_visibility USING GIN (namespace_id, Text03);
+CREATE INDEX by_text_01_tokens  ON executions_visibility USING GIN (namespace_id, text01_tokens);
+CREATE INDEX by_text_02_tokens  ON executions_visibility USING GIN (namespace_id, text02_tokens);
+CREATE INDEX by_text_03_tokens  ON executions_visibility USING GIN (namespace_id, text03_tokens);
 CREATE INDEX by_keyword_list_01 ON executions_visibility USING GIN (namespace_id, KeywordList01 jsonb_path_ops);
 CREATE INDEX by_keyword_list_02 ON executions_visibility USING GIN (namespace_id, KeywordList02 jsonb_path_ops);
 CREATE INDEX by_keyword_list_03 ON executions_visibility USING GIN (namespace_id, KeywordList03 jsonb_path_ops);
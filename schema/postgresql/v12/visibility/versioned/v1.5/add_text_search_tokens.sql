@@ -0,0 +1,10 @@
+-- Adding nullable columns without a default doesn't rewrite the table, so ALTER TABLE only holds its
+-- ACCESS EXCLUSIVE lock briefly. The indexes are built concurrently and don't block writes.
+-- Rows written before this version keep NULL tokens and are matched against the Text columns
+-- until they are updated or reindexed.
+ALTER TABLE executions_visibility ADD COLUMN text01_tokens TSVECTOR NULL;
+ALTER TABLE executions_visibility ADD COLUMN text02_tokens TSVECTOR NULL;
+ALTER TABLE executions_visibility ADD COLUMN text03_tokens TSVECTOR NULL;
+CREATE INDEX CONCURRENTLY by_text_01_tokens ON executions_visibility USING GIN (namespace_id, text01_tokens);
+CREATE INDEX CONCURRENTLY by_text_02_tokens ON executions_visibility USING GIN (namespace_id, text02_tokens);
+CREATE INDEX CONCURRENTLY by_text_03_tokens ON executions_visibility USING GIN (namespace_id, text03_tokens);
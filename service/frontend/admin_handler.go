@@ -40,6 +40,7 @@ import (
 
 	"go.temporal.io/server/api/adminservice/v1"
 	"go.temporal.io/server/api/historyservice/v1"
+	"go.temporal.io/server/api/matchingservice/v1"
 	"go.temporal.io/server/client/history"
 	"go.temporal.io/server/common/channel"
 	"go.temporal.io/server/common/clock"
@@ -67,6 +68,8 @@ import (
 	"go.temporal.io/server/client/admin"
 	"go.temporal.io/server/client/frontend"
 	"go.temporal.io/server/common"
+	"go.temporal.io/server/common/archiver"
+	"go.temporal.io/server/common/archiver/provider"
 	"go.temporal.io/server/common/cluster"
 	"go.temporal.io/server/common/config"
 	"go.temporal.io/server/common/convert"
@@ -79,6 +82,8 @@ import (
 	"go.temporal.io/server/common/namespace"
 	"go.temporal.io/server/common/persistence"
 	"go.temporal.io/server/common/persistence/serialization"
+	"go.temporal.io/server/common/persistence/shardmigration"
+	"go.temporal.io/server/common/persistence/visibility"
 	"go.temporal.io/server/common/persistence/visibility/manager"
 	"go.temporal.io/server/common/persistence/visibility/store/elasticsearch"
 	esclient "go.temporal.io/server/common/persistence/visibility/store/elasticsearch/client"
@@ -88,6 +93,8 @@ import (
 	"go.temporal.io/server/common/xdc"
 	"go.temporal.io/server/service/history/tasks"
 	"go.temporal.io/server/service/worker/addsearchattributes"
+	workershardmigration "go.temporal.io/server/service/worker/shardmigration"
+	"go.temporal.io/server/service/worker/visibilityreindex"
 )
 
 const (
@@ -117,6 +124,7 @@ type (
 		clientFactory              serverClient.Factory
 		clientBean                 serverClient.Bean
 		historyClient              historyservice.HistoryServiceClient
+		matchingClient             matchingservice.MatchingServiceClient
 		sdkClientFactory           sdk.ClientFactory
 		membershipMonitor          membership.Monitor
 		hostInfoProvider           membership.HostInfoProvider
@@ -126,6 +134,7 @@ type (
 		saManager                  searchattribute.Manager
 		clusterMetadata            cluster.Metadata
 		healthServer               *health.Server
+		namespaceHandler           *namespaceHandler
 
 		// DEPRECATED
 		persistenceExecutionManager persistence.ExecutionManager
@@ -147,6 +156,7 @@ type (
 		ClientFactory                       serverClient.Factory
 		ClientBean                          serverClient.Bean
 		HistoryClient                       historyservice.HistoryServiceClient
+		MatchingClient                      matchingservice.MatchingServiceClient
 		sdkClientFactory                    sdk.ClientFactory
 		MembershipMonitor                   membership.Monitor
 		HostInfoProvider                    membership.HostInfoProvider
@@ -163,6 +173,8 @@ type (
 		PersistenceExecutionManager persistence.ExecutionManager
 
 		CategoryRegistry tasks.TaskCategoryRegistry
+		ArchivalMetadata archiver.ArchivalMetadata
+		ArchiverProvider provider.ArchiverProvider
 	}
 )
 
@@ -203,6 +215,7 @@ func NewAdminHandler(
 		clientFactory:               args.ClientFactory,
 		clientBean:                  args.ClientBean,
 		historyClient:               args.HistoryClient,
+		matchingClient:              args.MatchingClient,
 		sdkClientFactory:            args.sdkClientFactory,
 		membershipMonitor:           args.MembershipMonitor,
 		hostInfoProvider:            args.HostInfoProvider,
@@ -212,7 +225,18 @@ func NewAdminHandler(
 		saManager:                   args.SaManager,
 		clusterMetadata:             args.ClusterMetadata,
 		healthServer:                args.HealthServer,
-		taskCategoryRegistry:        args.CategoryRegistry,
+		namespaceHandler: newNamespaceHandler(
+			args.Config.MaxBadBinaries,
+			args.Logger,
+			args.PersistenceMetadataManager,
+			args.ClusterMetadata,
+			namespace.NewNamespaceReplicator(args.NamespaceReplicationQueue, args.Logger),
+			args.ArchivalMetadata,
+			args.ArchiverProvider,
+			args.Config.EnableSchedules,
+			args.TimeSource,
+		),
+		taskCategoryRegistry: args.CategoryRegistry,
 	}
 }
 
@@ -926,9 +950,54 @@ func (adh *AdminHandler) DescribeHistoryHost(ctx context.Context, request *admin
 		ShardIds:       resp.GetShardIds(),
 		NamespaceCache: resp.GetNamespaceCache(),
 		Address:        resp.GetAddress(),
+		DrainInfo:      resp.GetDrainInfo(),
 	}, err
 }
 
+// DrainHistoryHost makes a history host hand off its shards to the other history hosts
+func (adh *AdminHandler) DrainHistoryHost(ctx context.Context, request *adminservice.DrainHistoryHostRequest) (_ *adminservice.DrainHistoryHostResponse, retError error) {
+	defer log.CapturePanic(adh.logger, &retError)
+
+	if request == nil {
+		return nil, errRequestNotSet
+	}
+	if len(request.GetHostAddress()) == 0 {
+		return nil, serviceerror.NewInvalidArgument("host address is not set on request.")
+	}
+
+	resp, err := adh.historyClient.DrainHistoryHost(ctx, &historyservice.DrainHistoryHostRequest{
+		HostAddress: request.GetHostAddress(),
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &adminservice.DrainHistoryHostResponse{
+		DrainInfo: resp.GetDrainInfo(),
+	}, nil
+}
+
+// UndrainHistoryHost stops the drain of a history host, which acquires shards again
+func (adh *AdminHandler) UndrainHistoryHost(ctx context.Context, request *adminservice.UndrainHistoryHostRequest) (_ *adminservice.UndrainHistoryHostResponse, retError error) {
+	defer log.CapturePanic(adh.logger, &retError)
+
+	if request == nil {
+		return nil, errRequestNotSet
+	}
+	if len(request.GetHostAddress()) == 0 {
+		return nil, serviceerror.NewInvalidArgument("host address is not set on request.")
+	}
+
+	resp, err := adh.historyClient.UndrainHistoryHost(ctx, &historyservice.UndrainHistoryHostRequest{
+		HostAddress: request.GetHostAddress(),
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &adminservice.UndrainHistoryHostResponse{
+		DrainInfo: resp.GetDrainInfo(),
+	}, nil
+}
+
 // GetWorkflowExecutionRawHistoryV2 - retrieves the history of workflow execution
 func (adh *AdminHandler) GetWorkflowExecutionRawHistoryV2(ctx context.Context, request *adminservice.GetWorkflowExecutionRawHistoryV2Request) (_ *adminservice.GetWorkflowExecutionRawHistoryV2Response, retError error) {
 	defer log.CapturePanic(adh.logger, &retError)
@@ -996,10 +1065,18 @@ func (adh *AdminHandler) DescribeCluster(
 				})
 			}
 
+			var drainingServers []*clusterspb.HostInfo
+			for _, server := range resolver.DrainingMembers() {
+				drainingServers = append(drainingServers, &clusterspb.HostInfo{
+					Identity: server.Identity(),
+				})
+			}
+
 			rings = append(rings, &clusterspb.RingInfo{
-				Role:        string(role),
-				MemberCount: int32(resolver.MemberCount()),
-				Members:     servers,
+				Role:            string(role),
+				MemberCount:     int32(resolver.MemberCount()),
+				Members:         servers,
+				DrainingMembers: drainingServers,
 			})
 		}
 		membershipInfo.Rings = rings
@@ -1986,6 +2063,331 @@ func (adh *AdminHandler) ListQueues(
 	}, nil
 }
 
+// StartHistoryShardCountMigration starts copying the executions of the cluster to a larger number of history shards.
+func (adh *AdminHandler) StartHistoryShardCountMigration(
+	ctx context.Context,
+	request *adminservice.StartHistoryShardCountMigrationRequest,
+) (_ *adminservice.StartHistoryShardCountMigrationResponse, retError error) {
+	defer log.CapturePanic(adh.logger, &retError)
+
+	if request == nil {
+		return nil, errRequestNotSet
+	}
+	if request.GetConcurrency() < 0 {
+		return nil, serviceerror.NewInvalidArgument("concurrency must not be negative")
+	}
+	for clusterName, clusterInfo := range adh.clusterMetadata.GetAllClusterInfo() {
+		if clusterInfo.Enabled && clusterName != adh.clusterMetadata.GetCurrentClusterName() {
+			return nil, serviceerror.NewFailedPrecondition(
+				"history shard count migration is not supported in a multi-cluster setup",
+			)
+		}
+	}
+
+	migration, err := shardmigration.UpdateMigration(ctx, adh.clusterMetadataManager, func(metadata *persistencespb.ClusterMetadata) error {
+		current := metadata.GetHistoryShardCountMigration()
+		if current.GetState() == enumsspb.HISTORY_SHARD_COUNT_MIGRATION_STATE_COPYING &&
+			current.GetTargetShardCount() == request.GetTargetShardCount() {
+			// Resume a migration whose workflow couldn't be started.
+			return nil
+		}
+		if shardmigration.IsActive(current) {
+			return serviceerror.NewFailedPrecondition("a history shard count migration is already in progress")
+		}
+		if err := shardmigration.ValidateTargetShardCount(metadata.HistoryShardCount, request.GetTargetShardCount()); err != nil {
+			return err
+		}
+		metadata.HistoryShardCountMigration = &persistencespb.HistoryShardCountMigration{
+			SourceShardCount: metadata.HistoryShardCount,
+			TargetShardCount: request.GetTargetShardCount(),
+			State:            enumsspb.HISTORY_SHARD_COUNT_MIGRATION_STATE_COPYING,
+			StartTime:        timestamppb.New(time.Now().UTC()),
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = adh.sdkClientFactory.GetSystemClient().ExecuteWorkflow(
+		ctx,
+		sdkclient.StartWorkflowOptions{
+			ID:        workershardmigration.WorkflowID,
+			TaskQueue: primitives.DefaultWorkerTaskQueue,
+		},
+		workershardmigration.WorkflowName,
+		workershardmigration.WorkflowParams{
+			Concurrency: int(request.GetConcurrency()),
+		},
+	)
+	var alreadyStartedErr *serviceerror.WorkflowExecutionAlreadyStarted
+	if err != nil && !errors.As(err, &alreadyStartedErr) {
+		return nil, serviceerror.NewUnavailable(
+			fmt.Sprintf(errUnableToStartWorkflowMessage, workershardmigration.WorkflowName, err),
+		)
+	}
+	return &adminservice.StartHistoryShardCountMigrationResponse{
+		Migration: migration,
+	}, nil
+}
+
+// DescribeHistoryShardCountMigration returns the progress of the last history shard count migration.
+func (adh *AdminHandler) DescribeHistoryShardCountMigration(
+	ctx context.Context,
+	request *adminservice.DescribeHistoryShardCountMigrationRequest,
+) (_ *adminservice.DescribeHistoryShardCountMigrationResponse, retError error) {
+	defer log.CapturePanic(adh.logger, &retError)
+
+	if request == nil {
+		return nil, errRequestNotSet
+	}
+	migration, historyShardCount, err := shardmigration.GetMigration(ctx, adh.clusterMetadataManager)
+	if err != nil {
+		return nil, err
+	}
+	return &adminservice.DescribeHistoryShardCountMigrationResponse{
+		Migration:         migration,
+		HistoryShardCount: historyShardCount,
+	}, nil
+}
+
+// PauseTaskQueue stops dispatching the tasks of a task queue until it's resumed.
+func (adh *AdminHandler) PauseTaskQueue(
+	ctx context.Context,
+	request *adminservice.PauseTaskQueueRequest,
+) (_ *adminservice.PauseTaskQueueResponse, retError error) {
+	defer log.CapturePanic(adh.logger, &retError)
+
+	if request == nil {
+		return nil, errRequestNotSet
+	}
+	err := adh.setTaskQueuePauseState(ctx, request.GetNamespace(), &matchingservice.UpdateTaskQueuePauseStateRequest{
+		TaskQueue:     request.GetTaskQueue(),
+		TaskQueueType: request.GetTaskQueueType(),
+		Paused:        true,
+		Reason:        request.GetReason(),
+		Identity:      request.GetIdentity(),
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &adminservice.PauseTaskQueueResponse{}, nil
+}
+
+// ResumeTaskQueue resumes dispatching the tasks of a paused task queue.
+func (adh *AdminHandler) ResumeTaskQueue(
+	ctx context.Context,
+	request *adminservice.ResumeTaskQueueRequest,
+) (_ *adminservice.ResumeTaskQueueResponse, retError error) {
+	defer log.CapturePanic(adh.logger, &retError)
+
+	if request == nil {
+		return nil, errRequestNotSet
+	}
+	err := adh.setTaskQueuePauseState(ctx, request.GetNamespace(), &matchingservice.UpdateTaskQueuePauseStateRequest{
+		TaskQueue:     request.GetTaskQueue(),
+		TaskQueueType: request.GetTaskQueueType(),
+		Paused:        false,
+		Identity:      request.GetIdentity(),
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &adminservice.ResumeTaskQueueResponse{}, nil
+}
+
+// setTaskQueuePauseState updates the pause state in the user data of the task queue, which is
+// owned by its root workflow partition and propagated from there to all partitions.
+func (adh *AdminHandler) setTaskQueuePauseState(
+	ctx context.Context,
+	namespaceName string,
+	request *matchingservice.UpdateTaskQueuePauseStateRequest,
+) error {
+	if request.GetTaskQueue() == "" {
+		return errTaskQueueNotSet
+	}
+	switch request.GetTaskQueueType() {
+	case enumspb.TASK_QUEUE_TYPE_UNSPECIFIED, enumspb.TASK_QUEUE_TYPE_WORKFLOW, enumspb.TASK_QUEUE_TYPE_ACTIVITY:
+	default:
+		return serviceerror.NewInvalidArgument(fmt.Sprintf("unsupported task queue type: %v", request.GetTaskQueueType()))
+	}
+	namespaceID, err := adh.namespaceRegistry.GetNamespaceID(namespace.Name(namespaceName))
+	if err != nil {
+		return err
+	}
+	request.NamespaceId = namespaceID.String()
+	_, err = adh.matchingClient.UpdateTaskQueuePauseState(ctx, request)
+	return err
+}
+
+// StartVisibilityReindex starts writing the visibility records of all existing executions to a visibility store.
+func (adh *AdminHandler) StartVisibilityReindex(
+	ctx context.Context,
+	request *adminservice.StartVisibilityReindexRequest,
+) (_ *adminservice.StartVisibilityReindexResponse, retError error) {
+	defer log.CapturePanic(adh.logger, &retError)
+
+	if request == nil {
+		return nil, errRequestNotSet
+	}
+	if request.GetConcurrency() < 0 {
+		return nil, serviceerror.NewInvalidArgument("concurrency must not be negative")
+	}
+	if request.GetRps() < 0 {
+		return nil, serviceerror.NewInvalidArgument("rps must not be negative")
+	}
+	if _, err := visibility.ManagerForStore(adh.visibilityMgr, request.GetTargetStore()); err != nil {
+		return nil, err
+	}
+	var namespaceID namespace.ID
+	if request.GetNamespace() != "" {
+		var err error
+		namespaceID, err = adh.namespaceRegistry.GetNamespaceID(namespace.Name(request.GetNamespace()))
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	run, err := adh.sdkClientFactory.GetSystemClient().ExecuteWorkflow(
+		ctx,
+		sdkclient.StartWorkflowOptions{
+			ID:                                       visibilityreindex.WorkflowID,
+			TaskQueue:                                primitives.DefaultWorkerTaskQueue,
+			WorkflowExecutionErrorWhenAlreadyStarted: true,
+		},
+		visibilityreindex.WorkflowName,
+		visibilityreindex.WorkflowParams{
+			TargetStore: request.GetTargetStore(),
+			NamespaceID: namespaceID.String(),
+			Concurrency: int(request.GetConcurrency()),
+			RPS:         request.GetRps(),
+		},
+	)
+	var alreadyStartedErr *serviceerror.WorkflowExecutionAlreadyStarted
+	if errors.As(err, &alreadyStartedErr) {
+		return nil, serviceerror.NewFailedPrecondition("a visibility reindex is already in progress")
+	}
+	if err != nil {
+		return nil, serviceerror.NewUnavailable(
+			fmt.Sprintf(errUnableToStartWorkflowMessage, visibilityreindex.WorkflowName, err),
+		)
+	}
+	return &adminservice.StartVisibilityReindexResponse{
+		WorkflowId: run.GetID(),
+		RunId:      run.GetRunID(),
+	}, nil
+}
+
+// DescribeVisibilityReindex returns the progress of the last visibility reindex.
+func (adh *AdminHandler) DescribeVisibilityReindex(
+	ctx context.Context,
+	request *adminservice.DescribeVisibilityReindexRequest,
+) (_ *adminservice.DescribeVisibilityReindexResponse, retError error) {
+	defer log.CapturePanic(adh.logger, &retError)
+
+	if request == nil {
+		return nil, errRequestNotSet
+	}
+	client := adh.sdkClientFactory.GetSystemClient()
+	execution, err := client.DescribeWorkflowExecution(ctx, visibilityreindex.WorkflowID, "")
+	if err != nil {
+		return nil, err
+	}
+	response, err := client.QueryWorkflow(ctx, visibilityreindex.WorkflowID, "", visibilityreindex.ProgressQueryType)
+	if err != nil {
+		return nil, err
+	}
+	var params visibilityreindex.WorkflowParams
+	if err = response.Get(&params); err != nil {
+		return nil, err
+	}
+	var namespaceName namespace.Name
+	if params.NamespaceID != "" {
+		namespaceName, err = adh.namespaceRegistry.GetNamespaceName(namespace.ID(params.NamespaceID))
+		if err != nil {
+			return nil, err
+		}
+	}
+	return &adminservice.DescribeVisibilityReindexResponse{
+		Status:              execution.WorkflowExecutionInfo.Status,
+		StartTime:           execution.WorkflowExecutionInfo.StartTime,
+		CloseTime:           execution.WorkflowExecutionInfo.CloseTime,
+		TargetStore:         params.TargetStore,
+		Namespace:           namespaceName.String(),
+		ShardCount:          params.Progress.ShardCount,
+		ShardsCompleted:     params.Progress.ShardsCompleted,
+		ExecutionsReindexed: params.Progress.ExecutionsReindexed,
+		ExecutionsSkipped:   params.Progress.ExecutionsSkipped,
+	}, nil
+}
+
+// SaveVisibilityQuery saves a visibility query in the namespace metadata. Saved queries are not
+// checked against the namespace max visibility query cost.
+func (adh *AdminHandler) SaveVisibilityQuery(
+	ctx context.Context,
+	request *adminservice.SaveVisibilityQueryRequest,
+) (_ *adminservice.SaveVisibilityQueryResponse, retError error) {
+	defer log.CapturePanic(adh.logger, &retError)
+
+	if request == nil {
+		return nil, errRequestNotSet
+	}
+	if request.GetNamespace() == "" {
+		return nil, errNamespaceNotSet
+	}
+	if request.GetName() == "" {
+		return nil, errQueryNameNotSet
+	}
+	if strings.TrimSpace(request.GetQuery()) == "" {
+		return nil, errVisibilityQueryNotSet
+	}
+
+	// The query is run once for a single execution, so that the query converter of the visibility
+	// store rejects a query which would fail every time it's used.
+	namespaceName := namespace.Name(request.GetNamespace())
+	namespaceID, err := adh.namespaceRegistry.GetNamespaceID(namespaceName)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := adh.visibilityMgr.ListWorkflowExecutions(ctx, &manager.ListWorkflowExecutionsRequestV2{
+		NamespaceID: namespaceID,
+		Namespace:   namespaceName,
+		PageSize:    1,
+		Query:       request.GetQuery(),
+	}); err != nil {
+		return nil, err
+	}
+
+	err = adh.namespaceHandler.UpdateSavedVisibilityQuery(ctx, request.GetNamespace(), request.GetName(), request.GetQuery())
+	if err != nil {
+		return nil, err
+	}
+	return &adminservice.SaveVisibilityQueryResponse{}, nil
+}
+
+// DeleteSavedVisibilityQuery removes a saved visibility query from the namespace metadata.
+func (adh *AdminHandler) DeleteSavedVisibilityQuery(
+	ctx context.Context,
+	request *adminservice.DeleteSavedVisibilityQueryRequest,
+) (_ *adminservice.DeleteSavedVisibilityQueryResponse, retError error) {
+	defer log.CapturePanic(adh.logger, &retError)
+
+	if request == nil {
+		return nil, errRequestNotSet
+	}
+	if request.GetNamespace() == "" {
+		return nil, errNamespaceNotSet
+	}
+	if request.GetName() == "" {
+		return nil, errQueryNameNotSet
+	}
+	err := adh.namespaceHandler.UpdateSavedVisibilityQuery(ctx, request.GetNamespace(), request.GetName(), "")
+	if err != nil {
+		return nil, err
+	}
+	return &adminservice.DeleteSavedVisibilityQueryResponse{}, nil
+}
+
 func (adh *AdminHandler) getDLQWorkflowID(key *persistence.QueueKey) string {
 	return fmt.Sprintf("manage-dlq-tasks-%s", key.GetQueueName())
 }
@@ -157,6 +157,7 @@ func (s *adminHandlerSuite) SetupTest() {
 		s.mockResource.GetClientFactory(),
 		s.mockResource.GetClientBean(),
 		s.mockResource.GetHistoryClient(),
+		s.mockResource.GetMatchingClient(),
 		s.mockResource.GetSDKClientFactory(),
 		s.mockResource.GetMembershipMonitor(),
 		s.mockResource.GetHostInfoProvider(),
@@ -170,6 +171,8 @@ func (s *adminHandlerSuite) SetupTest() {
 		clock.NewRealTimeSource(),
 		s.mockResource.GetExecutionManager(),
 		tasks.NewDefaultTaskCategoryRegistry(),
+		s.mockResource.GetArchivalMetadata(),
+		s.mockResource.GetArchiverProvider(),
 	}
 	s.mockMetadata.EXPECT().GetCurrentClusterName().Return(uuid.New()).AnyTimes()
 	s.handler = NewAdminHandler(args)
@@ -877,12 +880,16 @@ func (s *adminHandlerSuite) Test_DescribeCluster_CurrentCluster_Success() {
 	s.mockResource.MembershipMonitor.EXPECT().GetReachableMembers().Return(nil, nil)
 	s.mockResource.HistoryServiceResolver.EXPECT().Members().Return([]membership.HostInfo{})
 	s.mockResource.HistoryServiceResolver.EXPECT().MemberCount().Return(0)
+	s.mockResource.HistoryServiceResolver.EXPECT().DrainingMembers().Return([]membership.HostInfo{membership.NewHostInfoFromAddress("draining")})
 	s.mockResource.FrontendServiceResolver.EXPECT().Members().Return([]membership.HostInfo{})
 	s.mockResource.FrontendServiceResolver.EXPECT().MemberCount().Return(0)
+	s.mockResource.FrontendServiceResolver.EXPECT().DrainingMembers().Return(nil)
 	s.mockResource.MatchingServiceResolver.EXPECT().Members().Return([]membership.HostInfo{})
 	s.mockResource.MatchingServiceResolver.EXPECT().MemberCount().Return(0)
+	s.mockResource.MatchingServiceResolver.EXPECT().DrainingMembers().Return(nil)
 	s.mockResource.WorkerServiceResolver.EXPECT().Members().Return([]membership.HostInfo{})
 	s.mockResource.WorkerServiceResolver.EXPECT().MemberCount().Return(0)
+	s.mockResource.WorkerServiceResolver.EXPECT().DrainingMembers().Return(nil)
 	s.mockResource.ExecutionMgr.EXPECT().GetName().Return("")
 	s.mockVisibilityMgr.EXPECT().GetStoreNames().Return([]string{elasticsearch.PersistenceName})
 	s.mockClusterMetadataManager.EXPECT().GetClusterMetadata(gomock.Any(), &persistence.GetClusterMetadataRequest{ClusterName: clusterName}).Return(
@@ -906,6 +913,14 @@ func (s *adminHandlerSuite) Test_DescribeCluster_CurrentCluster_Success() {
 	s.Equal(resp.GetFailoverVersionIncrement(), int64(0))
 	s.Equal(resp.GetInitialFailoverVersion(), int64(0))
 	s.True(resp.GetIsGlobalNamespaceEnabled())
+	for _, ring := range resp.GetMembershipInfo().GetRings() {
+		if ring.GetRole() == string(primitives.HistoryService) {
+			s.Len(ring.GetDrainingMembers(), 1)
+			s.Equal("draining", ring.GetDrainingMembers()[0].GetIdentity())
+		} else {
+			s.Empty(ring.GetDrainingMembers())
+		}
+	}
 }
 
 func (s *adminHandlerSuite) Test_DescribeCluster_NonCurrentCluster_Success() {
@@ -916,12 +931,16 @@ func (s *adminHandlerSuite) Test_DescribeCluster_NonCurrentCluster_Success() {
 	s.mockResource.MembershipMonitor.EXPECT().GetReachableMembers().Return(nil, nil)
 	s.mockResource.HistoryServiceResolver.EXPECT().Members().Return([]membership.HostInfo{})
 	s.mockResource.HistoryServiceResolver.EXPECT().MemberCount().Return(0)
+	s.mockResource.HistoryServiceResolver.EXPECT().DrainingMembers().Return(nil)
 	s.mockResource.FrontendServiceResolver.EXPECT().Members().Return([]membership.HostInfo{})
 	s.mockResource.FrontendServiceResolver.EXPECT().MemberCount().Return(0)
+	s.mockResource.FrontendServiceResolver.EXPECT().DrainingMembers().Return(nil)
 	s.mockResource.MatchingServiceResolver.EXPECT().Members().Return([]membership.HostInfo{})
 	s.mockResource.MatchingServiceResolver.EXPECT().MemberCount().Return(0)
+	s.mockResource.MatchingServiceResolver.EXPECT().DrainingMembers().Return(nil)
 	s.mockResource.WorkerServiceResolver.EXPECT().Members().Return([]membership.HostInfo{})
 	s.mockResource.WorkerServiceResolver.EXPECT().MemberCount().Return(0)
+	s.mockResource.WorkerServiceResolver.EXPECT().DrainingMembers().Return(nil)
 	s.mockResource.ExecutionMgr.EXPECT().GetName().Return("")
 	s.mockVisibilityMgr.EXPECT().GetStoreNames().Return([]string{elasticsearch.PersistenceName})
 	s.mockClusterMetadataManager.EXPECT().GetClusterMetadata(gomock.Any(), &persistence.GetClusterMetadataRequest{ClusterName: clusterName}).Return(
@@ -1634,3 +1653,61 @@ func (s *adminHandlerSuite) TestListQueues_Err() {
 	_, err := s.handler.ListQueues(context.Background(), &adminservice.ListQueuesRequest{})
 	s.ErrorIs(err, assert.AnError)
 }
+
+func (s *adminHandlerSuite) TestSaveVisibilityQuery_InvalidRequest() {
+	_, err := s.handler.SaveVisibilityQuery(context.Background(), &adminservice.SaveVisibilityQueryRequest{Name: "stuck", Query: "ExecutionStatus = 'Running'"})
+	s.Equal(errNamespaceNotSet, err)
+	_, err = s.handler.SaveVisibilityQuery(context.Background(), &adminservice.SaveVisibilityQueryRequest{Namespace: s.namespace.String(), Query: "ExecutionStatus = 'Running'"})
+	s.Equal(errQueryNameNotSet, err)
+	_, err = s.handler.SaveVisibilityQuery(context.Background(), &adminservice.SaveVisibilityQueryRequest{Namespace: s.namespace.String(), Name: "stuck", Query: " "})
+	s.Equal(errVisibilityQueryNotSet, err)
+	_, err = s.handler.DeleteSavedVisibilityQuery(context.Background(), &adminservice.DeleteSavedVisibilityQueryRequest{Namespace: s.namespace.String()})
+	s.Equal(errQueryNameNotSet, err)
+}
+
+func (s *adminHandlerSuite) TestSaveVisibilityQuery_InvalidQuery() {
+	query := "Unknown = 'Running'"
+	s.mockNamespaceCache.EXPECT().GetNamespaceID(s.namespace).Return(s.namespaceID, nil)
+	s.mockVisibilityMgr.EXPECT().ListWorkflowExecutions(gomock.Any(), &manager.ListWorkflowExecutionsRequestV2{
+		NamespaceID: s.namespaceID,
+		Namespace:   s.namespace,
+		PageSize:    1,
+		Query:       query,
+	}).Return(nil, serviceerror.NewInvalidArgument("invalid query"))
+
+	_, err := s.handler.SaveVisibilityQuery(context.Background(), &adminservice.SaveVisibilityQueryRequest{
+		Namespace: s.namespace.String(),
+		Name:      "stuck",
+		Query:     query,
+	})
+	var invalidArgument *serviceerror.InvalidArgument
+	s.ErrorAs(err, &invalidArgument)
+}
+
+func (s *adminHandlerSuite) TestSaveVisibilityQuery_Ok() {
+	query := "ExecutionStatus = 'Running'"
+	s.mockNamespaceCache.EXPECT().GetNamespaceID(s.namespace).Return(s.namespaceID, nil)
+	s.mockVisibilityMgr.EXPECT().ListWorkflowExecutions(gomock.Any(), gomock.Any()).Return(&manager.ListWorkflowExecutionsResponse{}, nil)
+	s.mockResource.MetadataMgr.EXPECT().GetMetadata(gomock.Any()).Return(&persistence.GetMetadataResponse{NotificationVersion: 1}, nil)
+	s.mockResource.MetadataMgr.EXPECT().GetNamespace(gomock.Any(), &persistence.GetNamespaceRequest{Name: s.namespace.String()}).Return(&persistence.GetNamespaceResponse{
+		Namespace: &persistencespb.NamespaceDetail{
+			Info:              &persistencespb.NamespaceInfo{Id: s.namespaceID.String(), Name: s.namespace.String()},
+			Config:            &persistencespb.NamespaceConfig{},
+			ReplicationConfig: &persistencespb.NamespaceReplicationConfig{},
+		},
+	}, nil)
+	s.mockResource.MetadataMgr.EXPECT().UpdateNamespace(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ context.Context, request *persistence.UpdateNamespaceRequest) error {
+			s.Equal(query, request.Namespace.Info.Data[namespace.SavedVisibilityQueryKeyPrefix+"stuck"])
+			s.Equal(int64(1), request.Namespace.ConfigVersion)
+			return nil
+		},
+	)
+
+	_, err := s.handler.SaveVisibilityQuery(context.Background(), &adminservice.SaveVisibilityQueryRequest{
+		Namespace: s.namespace.String(),
+		Name:      "stuck",
+		Query:     query,
+	})
+	s.NoError(err)
+}
@@ -24,7 +24,10 @@
 
 package frontend
 
-import "go.temporal.io/api/serviceerror"
+import (
+	enumspb "go.temporal.io/api/enums/v1"
+	"go.temporal.io/api/serviceerror"
+)
 
 var (
 	errInvalidTaskToken                                   = serviceerror.NewInvalidArgument("Invalid TaskToken.")
@@ -93,9 +96,16 @@ var (
 	errSourceClusterNotSet    = serviceerror.NewInvalidArgument("SourceCluster is not set on request.")
 	errTargetClusterNotSet    = serviceerror.NewInvalidArgument("TargetCluster is not set on request.")
 	errInvalidDLQJobToken     = serviceerror.NewInvalidArgument("Invalid DLQ job token.")
+	errVisibilityQueryNotSet  = serviceerror.NewInvalidArgument("Query is not set on request.")
+	errQueryNameNotSet        = serviceerror.NewInvalidArgument("Name is not set on request.")
+
+	errVisibilityQueryCostLimitExceeded = serviceerror.NewResourceExhausted(enumspb.RESOURCE_EXHAUSTED_CAUSE_RPS_LIMIT, "Namespace visibility query cost limit exceeded.")
 
 	errPageSizeTooBigMessage = "PageSize is larger than allowed %d."
 
+	errSavedVisibilityQueryNotFoundMessage = "Saved visibility query %s is not found in namespace %s."
+	errVisibilityQueryTooExpensiveMessage  = "Visibility query is too expensive: estimated cost %v is above the namespace limit of %d. Narrow the time range, anchor 'like' filters or ask an operator to save the query."
+
 	errSearchAttributeIsReservedMessage               = "Search attribute %s is reserved by system."
 	errSearchAttributeAlreadyExistsMessage            = "Search attribute %s already exists."
 	errSearchAttributeDoesntExistMessage              = "Search attribute %s doesn't exist."
@@ -37,6 +37,7 @@ import (
 	"go.temporal.io/server/common"
 	"go.temporal.io/server/common/archiver"
 	"go.temporal.io/server/common/archiver/provider"
+	"go.temporal.io/server/common/audit"
 	"go.temporal.io/server/common/authorization"
 	"go.temporal.io/server/common/clock"
 	"go.temporal.io/server/common/cluster"
@@ -84,6 +85,7 @@ var Module = fx.Options(
 	fx.Provide(NamespaceRateLimitInterceptorProvider),
 	fx.Provide(SDKVersionInterceptorProvider),
 	fx.Provide(CallerInfoInterceptorProvider),
+	fx.Provide(AuditInterceptorProvider),
 	fx.Provide(GrpcServerOptionsProvider),
 	fx.Provide(VisibilityManagerProvider),
 	fx.Provide(ThrottledLoggerRpsFnProvider),
@@ -159,6 +161,7 @@ func GrpcServerOptionsProvider(
 	traceInterceptor telemetry.ServerTraceInterceptor,
 	sdkVersionInterceptor *interceptor.SDKVersionInterceptor,
 	callerInfoInterceptor *interceptor.CallerInfoInterceptor,
+	auditInterceptor *audit.Interceptor,
 	authorizer authorization.Authorizer,
 	claimMapper authorization.ClaimMapper,
 	audienceGetter authorization.JWTAudienceMapper,
@@ -192,6 +195,14 @@ func GrpcServerOptionsProvider(
 	unaryInterceptors := []grpc.UnaryServerInterceptor{
 		// Service Error Interceptor should be the most outer interceptor on error handling
 		rpc.ServiceErrorInterceptor,
+	}
+	if auditInterceptor != nil {
+		// audit interceptor must run before namespace validation and redirection to record
+		// rejected and forwarded calls, and before authorization to record denied calls
+		unaryInterceptors = append(unaryInterceptors, auditInterceptor.Intercept)
+	}
+	unaryInterceptors = append(
+		unaryInterceptors,
 		namespaceValidatorInterceptor.NamespaceValidateIntercept,
 		namespaceLogInterceptor.Intercept, // TODO: Deprecate this with a outer custom interceptor
 		grpc.UnaryServerInterceptor(traceInterceptor),
@@ -213,7 +224,7 @@ func GrpcServerOptionsProvider(
 		rateLimitInterceptor.Intercept,
 		sdkVersionInterceptor.Intercept,
 		callerInfoInterceptor.Intercept,
-	}
+	)
 	if len(customInterceptors) > 0 {
 		// TODO: Deprecate WithChainedFrontendGrpcInterceptors and provide a inner custom interceptor
 		unaryInterceptors = append(unaryInterceptors, customInterceptors...)
@@ -404,6 +415,40 @@ func NamespaceValidatorInterceptorProvider(
 	)
 }
 
+// AuditInterceptorProvider returns nil if audit log is not configured. Only the external
+// frontend is audited.
+func AuditInterceptorProvider(
+	lc fx.Lifecycle,
+	cfg *config.Config,
+	serviceName primitives.ServiceName,
+	customSink audit.Sink,
+	logger log.Logger,
+	metricsHandler metrics.Handler,
+) (*audit.Interceptor, error) {
+	if serviceName != primitives.FrontendService {
+		return nil, nil
+	}
+	sink, err := audit.NewSinkFromConfig(cfg.Global.Audit.Sinks)
+	if err != nil {
+		return nil, err
+	}
+	if sink != nil {
+		lc.Append(fx.StopHook(sink.Close))
+	}
+	switch {
+	case sink == nil && customSink == nil:
+		return nil, nil
+	case sink == nil:
+		sink = customSink
+	case customSink != nil:
+		sink = audit.NewMultiSink(sink, customSink)
+	}
+	// stop hooks run in reverse order, so queued records are written before the sinks are closed
+	asyncSink := audit.NewAsyncSink(sink, cfg.Global.Audit.QueueSize, metricsHandler, logger)
+	lc.Append(fx.StopHook(asyncSink.Close))
+	return audit.NewInterceptor(asyncSink, &cfg.Global.Audit, metricsHandler, logger), nil
+}
+
 func SDKVersionInterceptorProvider() *interceptor.SDKVersionInterceptor {
 	return interceptor.NewSDKVersionInterceptor()
 }
@@ -495,6 +540,7 @@ func AdminHandlerProvider(
 	clientFactory client.Factory,
 	clientBean client.Bean,
 	historyClient resource.HistoryClient,
+	matchingClient resource.MatchingClient,
 	sdkClientFactory sdk.ClientFactory,
 	membershipMonitor membership.Monitor,
 	hostInfoProvider membership.HostInfoProvider,
@@ -507,6 +553,8 @@ func AdminHandlerProvider(
 	eventSerializer serialization.Serializer,
 	timeSource clock.TimeSource,
 	taskCategoryRegistry tasks.TaskCategoryRegistry,
+	archivalMetadata archiver.ArchivalMetadata,
+	archiverProvider provider.ArchiverProvider,
 ) *AdminHandler {
 	args := NewAdminHandlerArgs{
 		persistenceConfig,
@@ -522,6 +570,7 @@ func AdminHandlerProvider(
 		clientFactory,
 		clientBean,
 		historyClient,
+		matchingClient,
 		sdkClientFactory,
 		membershipMonitor,
 		hostInfoProvider,
@@ -535,6 +584,8 @@ func AdminHandlerProvider(
 		timeSource,
 		persistenceExecutionManager,
 		taskCategoryRegistry,
+		archivalMetadata,
+		archiverProvider,
 	}
 	return NewAdminHandler(args)
 }
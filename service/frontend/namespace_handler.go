@@ -29,6 +29,7 @@ package frontend
 import (
 	"context"
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/pborman/uuid"
@@ -85,6 +86,7 @@ var (
 	errInvalidNamespaceStateUpdate        = serviceerror.NewInvalidArgument("Invalid namespace state update.")
 
 	errCustomSearchAttributeFieldAlreadyAllocated = serviceerror.NewInvalidArgument("Custom search attribute field name already allocated.")
+	errSavedVisibilityQueryDataKey                = serviceerror.NewInvalidArgument("Namespace data keys starting with " + namespace.SavedVisibilityQueryKeyPrefix + " are reserved for saved visibility queries.")
 )
 
 // newNamespaceHandler create a new namespace handler
@@ -140,6 +142,9 @@ func (d *namespaceHandler) RegisterNamespace(
 	); err != nil {
 		return nil, err
 	}
+	if err := validateNamespaceData(registerRequest.Data); err != nil {
+		return nil, err
+	}
 
 	// first check if the name is already registered as the local namespace
 	_, err := d.metadataMgr.GetNamespace(ctx, &persistence.GetNamespaceRequest{Name: registerRequest.GetNamespace()})
@@ -370,6 +375,10 @@ func (d *namespaceHandler) UpdateNamespace(
 	updateRequest *workflowservice.UpdateNamespaceRequest,
 ) (*workflowservice.UpdateNamespaceResponse, error) {
 
+	if err := validateNamespaceData(updateRequest.GetUpdateInfo().GetData()); err != nil {
+		return nil, err
+	}
+
 	// must get the metadata (notificationVersion) first
 	// this version can be regarded as the lock on the v2 namespace table
 	// and since we do not know which table will return the namespace afterwards
@@ -686,6 +695,77 @@ func (d *namespaceHandler) DeprecateNamespace(
 	return nil, nil
 }
 
+// UpdateSavedVisibilityQuery saves the visibility query under the name in the namespace data, or
+// removes the saved query if the visibility query is empty. It's only called by the admin API,
+// RegisterNamespace and UpdateNamespace reject the data keys of saved queries. Global namespaces
+// are only updated in their active cluster, which replicates the update to the other clusters.
+func (d *namespaceHandler) UpdateSavedVisibilityQuery(
+	ctx context.Context,
+	namespaceName string,
+	name string,
+	visibilityQuery string,
+) error {
+
+	metadata, err := d.metadataMgr.GetMetadata(ctx)
+	if err != nil {
+		return err
+	}
+	notificationVersion := metadata.NotificationVersion
+	getResponse, err := d.metadataMgr.GetNamespace(ctx, &persistence.GetNamespaceRequest{Name: namespaceName})
+	if err != nil {
+		return err
+	}
+
+	if getResponse.IsGlobalNamespace {
+		currentClusterName := d.clusterMetadata.GetCurrentClusterName()
+		activeClusterName := getResponse.Namespace.ReplicationConfig.GetActiveClusterName()
+		if activeClusterName != currentClusterName {
+			return serviceerror.NewNamespaceNotActive(namespaceName, currentClusterName, activeClusterName)
+		}
+	}
+
+	info := getResponse.Namespace.Info
+	key := namespace.SavedVisibilityQueryKeyPrefix + name
+	if visibilityQuery == "" {
+		if _, ok := info.Data[key]; !ok {
+			return serviceerror.NewNotFound(fmt.Sprintf(errSavedVisibilityQueryNotFoundMessage, name, namespaceName))
+		}
+		delete(info.Data, key)
+	} else {
+		info.Data = d.mergeNamespaceData(info.Data, map[string]string{key: visibilityQuery})
+	}
+
+	configVersion := getResponse.Namespace.ConfigVersion + 1
+	updateReq := &persistence.UpdateNamespaceRequest{
+		Namespace: &persistencespb.NamespaceDetail{
+			Info:                        info,
+			Config:                      getResponse.Namespace.Config,
+			ReplicationConfig:           getResponse.Namespace.ReplicationConfig,
+			ConfigVersion:               configVersion,
+			FailoverVersion:             getResponse.Namespace.FailoverVersion,
+			FailoverNotificationVersion: getResponse.Namespace.FailoverNotificationVersion,
+		},
+		IsGlobalNamespace:   getResponse.IsGlobalNamespace,
+		NotificationVersion: notificationVersion,
+	}
+	if err := d.metadataMgr.UpdateNamespace(ctx, updateReq); err != nil {
+		return err
+	}
+
+	return d.namespaceReplicator.HandleTransmissionTask(
+		ctx,
+		enumsspb.NAMESPACE_OPERATION_UPDATE,
+		info,
+		getResponse.Namespace.Config,
+		getResponse.Namespace.ReplicationConfig,
+		false,
+		configVersion,
+		getResponse.Namespace.FailoverVersion,
+		getResponse.IsGlobalNamespace,
+		getResponse.Namespace.ReplicationConfig.FailoverHistory,
+	)
+}
+
 func (d *namespaceHandler) createResponse(
 	info *persistencespb.NamespaceInfo,
 	config *persistencespb.NamespaceConfig,
@@ -767,6 +847,17 @@ func (d *namespaceHandler) mergeNamespaceData(
 	return old
 }
 
+// validateNamespaceData rejects the data keys of saved visibility queries, only operators can
+// save them with the admin API.
+func validateNamespaceData(data map[string]string) error {
+	for key := range data {
+		if strings.HasPrefix(key, namespace.SavedVisibilityQueryKeyPrefix) {
+			return errSavedVisibilityQueryDataKey
+		}
+	}
+	return nil
+}
+
 func (d *namespaceHandler) upsertCustomSearchAttributesAliases(
 	current map[string]string,
 	upsert map[string]string,
@@ -1672,6 +1672,95 @@ func (s *namespaceHandlerCommonSuite) TestFailoverGlobalNamespace_NotMaster() {
 	s.NoError(err)
 }
 
+func (s *namespaceHandlerCommonSuite) TestRegisterNamespace_SavedVisibilityQueryData() {
+	s.mockClusterMetadata.EXPECT().IsGlobalNamespaceEnabled().Return(false).AnyTimes()
+
+	resp, err := s.handler.RegisterNamespace(context.Background(), &workflowservice.RegisterNamespaceRequest{
+		Namespace:                        s.getRandomNamespace(),
+		WorkflowExecutionRetentionPeriod: durationpb.New(24 * time.Hour),
+		Data:                             map[string]string{namespace.SavedVisibilityQueryKeyPrefix + "stuck": "ExecutionStatus = 'Running'"},
+	})
+	s.Equal(errSavedVisibilityQueryDataKey, err)
+	s.Nil(resp)
+}
+
+func (s *namespaceHandlerCommonSuite) TestUpdateNamespace_SavedVisibilityQueryData() {
+	resp, err := s.handler.UpdateNamespace(context.Background(), &workflowservice.UpdateNamespaceRequest{
+		Namespace: s.getRandomNamespace(),
+		UpdateInfo: &namespacepb.UpdateNamespaceInfo{
+			Data: map[string]string{namespace.SavedVisibilityQueryKeyPrefix + "stuck": "ExecutionStatus = 'Running'"},
+		},
+	})
+	s.Equal(errSavedVisibilityQueryDataKey, err)
+	s.Nil(resp)
+}
+
+func (s *namespaceHandlerCommonSuite) TestUpdateSavedVisibilityQuery() {
+	namespaceName := s.getRandomNamespace()
+	nid := uuid.New()
+	version := int64(100)
+	key := namespace.SavedVisibilityQueryKeyPrefix + "stuck"
+	query := "ExecutionStatus = 'Running'"
+	detail := func(configVersion int64, data map[string]string) *persistencespb.NamespaceDetail {
+		return &persistencespb.NamespaceDetail{
+			Info: &persistencespb.NamespaceInfo{
+				Id:   nid,
+				Name: namespaceName,
+				Data: data,
+			},
+			Config: &persistencespb.NamespaceConfig{},
+			ReplicationConfig: &persistencespb.NamespaceReplicationConfig{
+				ActiveClusterName: cluster.TestCurrentClusterName,
+				Clusters:          []string{cluster.TestCurrentClusterName},
+			},
+			ConfigVersion: configVersion,
+		}
+	}
+	s.mockMetadataMgr.EXPECT().GetMetadata(gomock.Any()).Return(&persistence.GetMetadataResponse{
+		NotificationVersion: version,
+	}, nil).AnyTimes()
+
+	// save
+	s.mockMetadataMgr.EXPECT().GetNamespace(gomock.Any(), &persistence.GetNamespaceRequest{Name: namespaceName}).Return(&persistence.GetNamespaceResponse{
+		Namespace: detail(1, map[string]string{"owner": "team"}),
+	}, nil)
+	s.mockMetadataMgr.EXPECT().UpdateNamespace(gomock.Any(), &persistence.UpdateNamespaceRequest{
+		Namespace:           detail(2, map[string]string{"owner": "team", key: query}),
+		NotificationVersion: version,
+	}).Return(nil)
+	s.NoError(s.handler.UpdateSavedVisibilityQuery(context.Background(), namespaceName, "stuck", query))
+
+	// remove
+	s.mockMetadataMgr.EXPECT().GetNamespace(gomock.Any(), &persistence.GetNamespaceRequest{Name: namespaceName}).Return(&persistence.GetNamespaceResponse{
+		Namespace: detail(2, map[string]string{"owner": "team", key: query}),
+	}, nil)
+	s.mockMetadataMgr.EXPECT().UpdateNamespace(gomock.Any(), &persistence.UpdateNamespaceRequest{
+		Namespace:           detail(3, map[string]string{"owner": "team"}),
+		NotificationVersion: version,
+	}).Return(nil)
+	s.NoError(s.handler.UpdateSavedVisibilityQuery(context.Background(), namespaceName, "stuck", ""))
+
+	// remove a query which isn't saved
+	s.mockMetadataMgr.EXPECT().GetNamespace(gomock.Any(), &persistence.GetNamespaceRequest{Name: namespaceName}).Return(&persistence.GetNamespaceResponse{
+		Namespace: detail(3, map[string]string{"owner": "team"}),
+	}, nil)
+	err := s.handler.UpdateSavedVisibilityQuery(context.Background(), namespaceName, "stuck", "")
+	var notFound *serviceerror.NotFound
+	s.ErrorAs(err, &notFound)
+
+	// global namespaces are only updated in their active cluster
+	s.mockClusterMetadata.EXPECT().GetCurrentClusterName().Return(cluster.TestCurrentClusterName).AnyTimes()
+	standbyDetail := detail(3, map[string]string{"owner": "team"})
+	standbyDetail.ReplicationConfig.ActiveClusterName = cluster.TestAlternativeClusterName
+	s.mockMetadataMgr.EXPECT().GetNamespace(gomock.Any(), &persistence.GetNamespaceRequest{Name: namespaceName}).Return(&persistence.GetNamespaceResponse{
+		Namespace:         standbyDetail,
+		IsGlobalNamespace: true,
+	}, nil)
+	err = s.handler.UpdateSavedVisibilityQuery(context.Background(), namespaceName, "stuck", query)
+	var notActive *serviceerror.NamespaceNotActive
+	s.ErrorAs(err, &notActive)
+}
+
 func (s *namespaceHandlerCommonSuite) getRandomNamespace() string {
 	return "namespace" + uuid.New()
 }
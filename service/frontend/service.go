@@ -65,6 +65,9 @@ type Config struct {
 	VisibilityPersistenceMaxReadQPS   dynamicconfig.IntPropertyFn
 	VisibilityPersistenceMaxWriteQPS  dynamicconfig.IntPropertyFn
 	VisibilityMaxPageSize             dynamicconfig.IntPropertyFnWithNamespaceFilter
+	VisibilityMaxCountGroups          dynamicconfig.IntPropertyFnWithNamespaceFilter
+	VisibilityMaxQueryCost            dynamicconfig.IntPropertyFnWithNamespaceFilter
+	VisibilityQueryCostPerSecond      dynamicconfig.IntPropertyFnWithNamespaceFilter
 	EnableReadFromSecondaryVisibility dynamicconfig.BoolPropertyFnWithNamespaceFilter
 	VisibilityDisableOrderByClause    dynamicconfig.BoolPropertyFnWithNamespaceFilter
 	VisibilityEnableManualPagination  dynamicconfig.BoolPropertyFnWithNamespaceFilter
@@ -207,6 +210,9 @@ func NewConfig(
 		VisibilityPersistenceMaxReadQPS:   visibility.GetVisibilityPersistenceMaxReadQPS(dc, enableReadFromES),
 		VisibilityPersistenceMaxWriteQPS:  visibility.GetVisibilityPersistenceMaxWriteQPS(dc, enableReadFromES),
 		VisibilityMaxPageSize:             dc.GetIntPropertyFilteredByNamespace(dynamicconfig.FrontendVisibilityMaxPageSize, 1000),
+		VisibilityMaxCountGroups:          dc.GetIntPropertyFilteredByNamespace(dynamicconfig.FrontendVisibilityMaxCountGroups, 1000),
+		VisibilityMaxQueryCost:            dc.GetIntPropertyFilteredByNamespace(dynamicconfig.FrontendVisibilityMaxQueryCost, 0),
+		VisibilityQueryCostPerSecond:      dc.GetIntPropertyFilteredByNamespace(dynamicconfig.FrontendVisibilityQueryCostPerSecond, 0),
 		EnableReadFromSecondaryVisibility: visibility.GetEnableReadFromSecondaryVisibilityConfig(dc, visibilityStoreConfigExist, enableReadFromES),
 		VisibilityDisableOrderByClause:    dc.GetBoolPropertyFnWithNamespaceFilter(dynamicconfig.VisibilityDisableOrderByClause, true),
 		VisibilityEnableManualPagination:  dc.GetBoolPropertyFnWithNamespaceFilter(dynamicconfig.VisibilityEnableManualPagination, true),
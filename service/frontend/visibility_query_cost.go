@@ -0,0 +1,177 @@
+// The MIT License
+//
+// Copyright (c) 2020 Temporal Technologies Inc.  All rights reserved.
+//
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package frontend
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	enumspb "go.temporal.io/api/enums/v1"
+	"go.temporal.io/api/serviceerror"
+
+	"go.temporal.io/server/common/clock"
+	"go.temporal.io/server/common/dynamicconfig"
+	"go.temporal.io/server/common/namespace"
+	"go.temporal.io/server/common/persistence/visibility/manager"
+	"go.temporal.io/server/common/persistence/visibility/store/query"
+	"go.temporal.io/server/common/quotas"
+	"go.temporal.io/server/common/searchattribute"
+)
+
+const (
+	// savedVisibilityQueryRefPrefix marks a visibility query which references a saved query by name.
+	savedVisibilityQueryRefPrefix = "@"
+
+	visibilityQueryCostRefreshInterval = time.Minute
+)
+
+type (
+	// visibilityQueryCostLimiter estimates the cost of visibility queries before they are
+	// executed. Queries above the namespace max cost are rejected, and the cost of accepted
+	// queries is taken from a per-namespace budget replenished every second.
+	visibilityQueryCostLimiter struct {
+		maxCost          dynamicconfig.IntPropertyFnWithNamespaceFilter
+		costPerSecond    dynamicconfig.IntPropertyFnWithNamespaceFilter
+		visibilityMgr    manager.VisibilityManager
+		saProvider       searchattribute.Provider
+		saMapperProvider searchattribute.MapperProvider
+		timeSource       clock.TimeSource
+		rateLimiter      quotas.RequestRateLimiter
+	}
+)
+
+func newVisibilityQueryCostLimiter(
+	maxCost dynamicconfig.IntPropertyFnWithNamespaceFilter,
+	costPerSecond dynamicconfig.IntPropertyFnWithNamespaceFilter,
+	visibilityMgr manager.VisibilityManager,
+	saProvider searchattribute.Provider,
+	saMapperProvider searchattribute.MapperProvider,
+	timeSource clock.TimeSource,
+) *visibilityQueryCostLimiter {
+	return &visibilityQueryCostLimiter{
+		maxCost:          maxCost,
+		costPerSecond:    costPerSecond,
+		visibilityMgr:    visibilityMgr,
+		saProvider:       saProvider,
+		saMapperProvider: saMapperProvider,
+		timeSource:       timeSource,
+		rateLimiter: quotas.NewNamespaceRequestRateLimiter(func(req quotas.Request) quotas.RequestRateLimiter {
+			budgetFn := func() int { return costPerSecond(req.Caller) }
+			return quotas.NewRequestRateLimiterAdapter(
+				quotas.NewDynamicRateLimiter(
+					quotas.NewRateBurst(func() float64 { return float64(budgetFn()) }, budgetFn),
+					visibilityQueryCostRefreshInterval,
+				),
+			)
+		}),
+	}
+}
+
+// resolveSavedVisibilityQuery returns the saved query referenced by a query like "@<name>"
+// and true, or the query itself and false if it doesn't reference a saved query. Queries are
+// saved in the namespace data by operators with the admin API, namespace admins can't save
+// them to bypass the max cost.
+func resolveSavedVisibilityQuery(
+	namespaceRegistry namespace.Registry,
+	namespaceName namespace.Name,
+	visibilityQuery string,
+) (string, bool, error) {
+	name, isRef := strings.CutPrefix(strings.TrimSpace(visibilityQuery), savedVisibilityQueryRefPrefix)
+	if !isRef {
+		return visibilityQuery, false, nil
+	}
+	namespaceEntry, err := namespaceRegistry.GetNamespace(namespaceName)
+	if err != nil {
+		return "", false, err
+	}
+	savedQuery, ok := namespaceEntry.SavedVisibilityQuery(name)
+	if !ok {
+		return "", false, serviceerror.NewInvalidArgument(fmt.Sprintf(errSavedVisibilityQueryNotFoundMessage, name, namespaceName))
+	}
+	return savedQuery, true, nil
+}
+
+// Allow returns an error if the query must not be executed. Saved queries are pre-approved
+// by operators and are not checked against the max cost, but they still spend the budget.
+// Every page of a query spends the budget, page tokens come from the caller and can't be
+// trusted to mark a query which was already charged.
+func (l *visibilityQueryCostLimiter) Allow(
+	namespaceName namespace.Name,
+	visibilityQuery string,
+	saved bool,
+) error {
+	maxCost := l.maxCost(namespaceName.String())
+	costPerSecond := l.costPerSecond(namespaceName.String())
+	if (saved || maxCost <= 0) && costPerSecond <= 0 {
+		return nil
+	}
+
+	fieldType, err := l.fieldTypeFunc(namespaceName)
+	if err != nil {
+		return err
+	}
+	cost, err := query.EstimateQueryCost(visibilityQuery, fieldType, l.timeSource.Now())
+	if err != nil {
+		// Malformed queries are reported by the visibility store with a detailed error.
+		return nil
+	}
+
+	if !saved && maxCost > 0 && cost.Total() > maxCost {
+		return serviceerror.NewInvalidArgument(fmt.Sprintf(errVisibilityQueryTooExpensiveMessage, cost, maxCost))
+	}
+	if costPerSecond > 0 {
+		// Queries which cost more than the whole budget are charged the whole budget, so they
+		// are rejected unless none of it has been spent.
+		token := min(cost.Total(), costPerSecond)
+		if !l.rateLimiter.Allow(l.timeSource.Now(), quotas.NewRequest("", token, namespaceName.String(), "", 0, "")) {
+			return errVisibilityQueryCostLimitExceeded
+		}
+	}
+	return nil
+}
+
+func (l *visibilityQueryCostLimiter) fieldTypeFunc(namespaceName namespace.Name) (query.FieldTypeFunc, error) {
+	saTypeMap, err := l.saProvider.GetSearchAttributes(l.visibilityMgr.GetIndexName(), false)
+	if err != nil {
+		return nil, serviceerror.NewUnavailable(fmt.Sprintf(errUnableToGetSearchAttributesMessage, err))
+	}
+	mapper, err := l.saMapperProvider.GetMapper(namespaceName)
+	if err != nil {
+		return nil, err
+	}
+
+	return func(name string) (enumspb.IndexedValueType, bool) {
+		fieldName := name
+		if mapper != nil && searchattribute.IsMappable(name) {
+			var err error
+			if fieldName, err = mapper.GetFieldName(name, namespaceName.String()); err != nil {
+				return enumspb.INDEXED_VALUE_TYPE_UNSPECIFIED, false
+			}
+		}
+		fieldType, err := saTypeMap.GetType(fieldName)
+		return fieldType, err == nil
+	}, nil
+}
@@ -126,6 +126,7 @@ type (
 		saMapperProvider                searchattribute.MapperProvider
 		saProvider                      searchattribute.Provider
 		saValidator                     *searchattribute.Validator
+		visibilityQueryCostLimiter      *visibilityQueryCostLimiter
 		archivalMetadata                archiver.ArchivalMetadata
 		healthServer                    *health.Server
 		overrides                       *Overrides
@@ -198,6 +199,14 @@ func NewWorkflowHandler(
 			visibilityMrg,
 			visibility.AllowListForValidation(visibilityMrg.GetStoreNames()),
 		),
+		visibilityQueryCostLimiter: newVisibilityQueryCostLimiter(
+			config.VisibilityMaxQueryCost,
+			config.VisibilityQueryCostPerSecond,
+			visibilityMrg,
+			saProvider,
+			saMapperProvider,
+			timeSource,
+		),
 		archivalMetadata:  archivalMetadata,
 		healthServer:      healthServer,
 		overrides:         NewOverrides(),
@@ -2011,12 +2020,20 @@ func (wh *WorkflowHandler) ListWorkflowExecutions(ctx context.Context, request *
 		return nil, err
 	}
 
+	query, saved, err := resolveSavedVisibilityQuery(wh.namespaceRegistry, namespaceName, request.GetQuery())
+	if err != nil {
+		return nil, err
+	}
+	if err := wh.visibilityQueryCostLimiter.Allow(namespaceName, query, saved); err != nil {
+		return nil, err
+	}
+
 	req := &manager.ListWorkflowExecutionsRequestV2{
 		NamespaceID:   namespaceID,
 		Namespace:     namespaceName,
 		PageSize:      int(request.GetPageSize()),
 		NextPageToken: request.NextPageToken,
-		Query:         request.GetQuery(),
+		Query:         query,
 	}
 	persistenceResp, err := wh.visibilityMrg.ListWorkflowExecutions(ctx, req)
 	if err != nil {
@@ -2123,6 +2140,9 @@ func (wh *WorkflowHandler) ScanWorkflowExecutions(ctx context.Context, request *
 	if err != nil {
 		return nil, err
 	}
+	if err := wh.visibilityQueryCostLimiter.Allow(namespaceName, request.GetQuery(), false); err != nil {
+		return nil, err
+	}
 
 	req := &manager.ListWorkflowExecutionsRequestV2{
 		NamespaceID:   namespaceID,
@@ -2156,11 +2176,15 @@ func (wh *WorkflowHandler) CountWorkflowExecutions(ctx context.Context, request
 	if err != nil {
 		return nil, err
 	}
+	if err := wh.visibilityQueryCostLimiter.Allow(namespaceName, request.GetQuery(), false); err != nil {
+		return nil, err
+	}
 
 	req := &manager.CountWorkflowExecutionsRequest{
 		NamespaceID: namespaceID,
 		Namespace:   namespaceName,
 		Query:       request.GetQuery(),
+		GroupsLimit: wh.config.VisibilityMaxCountGroups(namespaceName.String()),
 	}
 	persistenceResp, err := wh.visibilityMrg.CountWorkflowExecutions(ctx, req)
 	if err != nil {
@@ -70,6 +70,7 @@ import (
 	"go.temporal.io/server/common/persistence/visibility/manager"
 	"go.temporal.io/server/common/persistence/visibility/store"
 	"go.temporal.io/server/common/persistence/visibility/store/elasticsearch"
+	"go.temporal.io/server/common/persistence/visibility/store/query"
 	"go.temporal.io/server/common/primitives"
 	"go.temporal.io/server/common/primitives/timestamp"
 	"go.temporal.io/server/common/resourcetest"
@@ -1548,6 +1549,104 @@ func (s *workflowHandlerSuite) TestListWorkflowExecutions() {
 	s.Equal(query, listRequest.GetQuery())
 }
 
+func (s *workflowHandlerSuite) TestListWorkflowExecutions_SavedQuery() {
+	config := s.newConfig()
+	wh := s.getWorkflowHandler(config)
+	savedQuery := "ExecutionStatus = 'Running' and CustomTextField = 'stuck'"
+	namespaceEntry := namespace.NewLocalNamespaceForTest(
+		&persistencespb.NamespaceInfo{
+			Id:   s.testNamespaceID.String(),
+			Name: s.testNamespace.String(),
+			Data: map[string]string{namespace.SavedVisibilityQueryKeyPrefix + "stuck": savedQuery},
+		},
+		nil,
+		"",
+	)
+	s.mockNamespaceCache.EXPECT().GetNamespaceID(s.testNamespace).Return(s.testNamespaceID, nil).AnyTimes()
+	s.mockNamespaceCache.EXPECT().GetNamespace(s.testNamespace).Return(namespaceEntry, nil).AnyTimes()
+
+	s.mockVisibilityMgr.EXPECT().ListWorkflowExecutions(
+		gomock.Any(),
+		&manager.ListWorkflowExecutionsRequestV2{
+			NamespaceID: s.testNamespaceID,
+			Namespace:   s.testNamespace,
+			PageSize:    config.VisibilityMaxPageSize(s.testNamespace.String()),
+			Query:       savedQuery,
+		},
+	).Return(&manager.ListWorkflowExecutionsResponse{}, nil)
+	_, err := wh.ListWorkflowExecutions(context.Background(), &workflowservice.ListWorkflowExecutionsRequest{
+		Namespace: s.testNamespace.String(),
+		Query:     " @stuck",
+	})
+	s.NoError(err)
+
+	_, err = wh.ListWorkflowExecutions(context.Background(), &workflowservice.ListWorkflowExecutionsRequest{
+		Namespace: s.testNamespace.String(),
+		Query:     "@missing",
+	})
+	var invalidArgument *serviceerror.InvalidArgument
+	s.ErrorAs(err, &invalidArgument)
+	s.Contains(err.Error(), "missing")
+}
+
+func (s *workflowHandlerSuite) TestListWorkflowExecutions_QueryCostLimits() {
+	config := s.newConfig()
+	config.VisibilityMaxQueryCost = dc.GetIntPropertyFilteredByNamespace(query.TextFilterCost)
+	config.VisibilityQueryCostPerSecond = dc.GetIntPropertyFilteredByNamespace(3 * query.TextFilterCost)
+	wh := s.getWorkflowHandler(config)
+	expensiveQuery := "CustomTextField = 'foo' and CustomKeywordField like '%bar'"
+	namespaceEntry := namespace.NewLocalNamespaceForTest(
+		&persistencespb.NamespaceInfo{
+			Id:   s.testNamespaceID.String(),
+			Name: s.testNamespace.String(),
+			Data: map[string]string{namespace.SavedVisibilityQueryKeyPrefix + "expensive": expensiveQuery},
+		},
+		nil,
+		"",
+	)
+	s.mockNamespaceCache.EXPECT().GetNamespaceID(s.testNamespace).Return(s.testNamespaceID, nil).AnyTimes()
+	s.mockNamespaceCache.EXPECT().GetNamespace(s.testNamespace).Return(namespaceEntry, nil).AnyTimes()
+	s.mockSearchAttributesProvider.EXPECT().GetSearchAttributes(esIndexName, false).Return(searchattribute.TestNameTypeMap, nil).AnyTimes()
+	s.mockSearchAttributesMapperProvider.EXPECT().GetMapper(s.testNamespace).Return(nil, nil).AnyTimes()
+	s.mockVisibilityMgr.EXPECT().ListWorkflowExecutions(gomock.Any(), gomock.Any()).Return(&manager.ListWorkflowExecutionsResponse{}, nil).AnyTimes()
+
+	listPage := func(q string, nextPageToken []byte) error {
+		_, err := wh.ListWorkflowExecutions(context.Background(), &workflowservice.ListWorkflowExecutionsRequest{
+			Namespace:     s.testNamespace.String(),
+			Query:         q,
+			NextPageToken: nextPageToken,
+		})
+		return err
+	}
+	list := func(q string) error {
+		return listPage(q, nil)
+	}
+
+	// Above the max cost.
+	err := list(expensiveQuery)
+	var invalidArgument *serviceerror.InvalidArgument
+	s.ErrorAs(err, &invalidArgument)
+	s.Contains(err.Error(), "too expensive")
+
+	// Saved queries are pre-approved, but still spend the namespace budget of 3 text filters:
+	// the first saved query spends 2 of them and the second one is throttled.
+	s.NoError(list("@expensive"))
+	err = list("@expensive")
+	var resourceExhausted *serviceerror.ResourceExhausted
+	s.ErrorAs(err, &resourceExhausted)
+
+	// Next pages spend the budget too, a page token doesn't skip it.
+	err = listPage("@expensive", []byte("token"))
+	s.ErrorAs(err, &resourceExhausted)
+
+	// The max cost still applies to the next pages.
+	err = listPage(expensiveQuery, []byte("token"))
+	s.ErrorAs(err, &invalidArgument)
+
+	// Malformed queries are left to the visibility store.
+	s.NoError(list("WorkflowId = "))
+}
+
 func (s *workflowHandlerSuite) TestScanWorkflowExecutions() {
 	config := s.newConfig()
 	wh := s.getWorkflowHandler(config)
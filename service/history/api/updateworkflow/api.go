@@ -83,6 +83,9 @@ func Invoke(
 		scheduledEventID       int64
 		scheduleToStartTimeout time.Duration
 		directive              *taskqueuespb.TaskVersionDirective
+		fairnessKey            string
+		priority               int32
+		workflowTypeName       string
 	)
 
 	err := api.GetAndUpdateWorkflowWithNew(
@@ -177,6 +180,12 @@ func Invoke(
 				ms.GetWorkerVersionStamp(),
 				ms.GetLastWorkflowTaskStartedEventID(),
 			)
+			fairnessKey, err = workflow.GetFairnessKey(ctx, shardCtx, ms)
+			if err != nil {
+				return nil, err
+			}
+			priority = workflow.GetWorkflowTaskPriority(ms)
+			workflowTypeName = ms.GetExecutionInfo().GetWorkflowTypeName()
 
 			return &api.UpdateWorkflowAction{
 				Noop:               true,
@@ -199,7 +208,7 @@ func Invoke(
 	// TODO (alex): This code is copied from transferQueueActiveTaskExecutor.processWorkflowTask.
 	//   Helper function needs to be extracted to avoid code duplication.
 	if scheduledEventID != common.EmptyEventID {
-		err = addWorkflowTaskToMatching(ctx, wfKey, taskQueue, scheduledEventID, scheduleToStartTimeout, namespace.ID(req.GetNamespaceId()), directive, shardCtx, matchingClient)
+		err = addWorkflowTaskToMatching(ctx, wfKey, taskQueue, scheduledEventID, scheduleToStartTimeout, namespace.ID(req.GetNamespaceId()), directive, fairnessKey, priority, workflowTypeName, shardCtx, matchingClient)
 
 		if _, isStickyWorkerUnavailable := err.(*serviceerrors.StickyWorkerUnavailable); isStickyWorkerUnavailable {
 			// If sticky worker is unavailable, switch to original normal task queue.
@@ -207,7 +216,7 @@ func Invoke(
 				Name: normalTaskQueueName,
 				Kind: enumspb.TASK_QUEUE_KIND_NORMAL,
 			}
-			err = addWorkflowTaskToMatching(ctx, wfKey, taskQueue, scheduledEventID, scheduleToStartTimeout, namespace.ID(req.GetNamespaceId()), directive, shardCtx, matchingClient)
+			err = addWorkflowTaskToMatching(ctx, wfKey, taskQueue, scheduledEventID, scheduleToStartTimeout, namespace.ID(req.GetNamespaceId()), directive, fairnessKey, priority, workflowTypeName, shardCtx, matchingClient)
 		}
 
 		if err != nil {
@@ -266,6 +275,9 @@ func addWorkflowTaskToMatching(
 	wtScheduleToStartTimeout time.Duration,
 	nsID namespace.ID,
 	directive *taskqueuespb.TaskVersionDirective,
+	fairnessKey string,
+	priority int32,
+	workflowTypeName string,
 	shardCtx shard.Context,
 	matchingClient matchingservice.MatchingServiceClient,
 ) error {
@@ -285,6 +297,9 @@ func addWorkflowTaskToMatching(
 		ScheduleToStartTimeout: durationpb.New(wtScheduleToStartTimeout),
 		Clock:                  clock,
 		VersionDirective:       directive,
+		FairnessKey:            fairnessKey,
+		Priority:               priority,
+		WorkflowTypeName:       workflowTypeName,
 	})
 	if err != nil {
 		return err
@@ -42,6 +42,7 @@ import (
 
 	archiverspb "go.temporal.io/server/api/archiver/v1"
 	carchiver "go.temporal.io/server/common/archiver"
+	"go.temporal.io/server/common/archiver/export"
 	"go.temporal.io/server/common/archiver/provider"
 	"go.temporal.io/server/common/log"
 	"go.temporal.io/server/common/log/tag"
@@ -77,6 +78,11 @@ type (
 		// VisibilityURI is the URI of the visibility archival backend.
 		VisibilityURI carchiver.URI
 
+		// export
+		// ExportURI is the URI that history and visibility are exported to for analytics.
+		ExportURI    carchiver.URI
+		ExportFormat export.Format
+
 		// archival targets: history and/or visibility
 		Targets       []Target
 		CallerService string
@@ -91,6 +97,9 @@ type (
 	// archiver.Client, which will try to signal an archival workflow whenever an error occurs.
 	Archiver interface {
 		Archive(context.Context, *Request) (*Response, error)
+		// Export hands the workflow execution over to the exporter, which writes it to ExportURI in the background.
+		// Export is best effort and isn't an archival target: its failures are logged and never fail archival.
+		Export(*Request)
 	}
 
 	archiver struct {
@@ -100,6 +109,7 @@ type (
 		rateLimiter             quotas.RateLimiter
 		searchAttributeProvider searchattribute.Provider
 		visibilityManager       manager.VisibilityManager
+		exporter                export.Exporter
 	}
 )
 
@@ -116,6 +126,7 @@ func NewArchiver(
 	rateLimiter quotas.RateLimiter,
 	searchAttributeProvider searchattribute.Provider,
 	visibilityManger manager.VisibilityManager,
+	exporter export.Exporter,
 ) Archiver {
 	return &archiver{
 		archiverProvider:        archiverProvider,
@@ -124,19 +135,12 @@ func NewArchiver(
 		rateLimiter:             rateLimiter,
 		searchAttributeProvider: searchAttributeProvider,
 		visibilityManager:       visibilityManger,
+		exporter:                exporter,
 	}
 }
 
 func (a *archiver) Archive(ctx context.Context, request *Request) (res *Response, err error) {
-	logger := log.With(
-		a.logger,
-		tag.ShardID(request.ShardID),
-		tag.ArchivalCallerServiceName(request.CallerService),
-		tag.ArchivalRequestNamespaceID(request.NamespaceID),
-		tag.ArchivalRequestNamespace(request.Namespace),
-		tag.ArchivalRequestWorkflowID(request.WorkflowID),
-		tag.ArchivalRequestRunID(request.RunID),
-	)
+	logger := a.requestLogger(request)
 
 	defer func(start time.Time) {
 		metricsScope := a.metricsHandler
@@ -212,16 +216,7 @@ func (a *archiver) archiveHistory(ctx context.Context, request *Request, logger
 		return err
 	}
 
-	return historyArchiver.Archive(ctx, request.HistoryURI, &carchiver.ArchiveHistoryRequest{
-		ShardID:              request.ShardID,
-		NamespaceID:          request.NamespaceID,
-		Namespace:            request.Namespace,
-		WorkflowID:           request.WorkflowID,
-		RunID:                request.RunID,
-		BranchToken:          request.BranchToken,
-		NextEventID:          request.NextEventID,
-		CloseFailoverVersion: request.CloseFailoverVersion,
-	})
+	return historyArchiver.Archive(ctx, request.HistoryURI, historyArchiveRequest(request))
 }
 
 func (a *archiver) archiveVisibility(ctx context.Context, request *Request, logger log.Logger) (err error) {
@@ -236,16 +231,72 @@ func (a *archiver) archiveVisibility(ctx context.Context, request *Request, logg
 		return err
 	}
 
+	record, err := a.visibilityRecord(request)
+	if err != nil {
+		return err
+	}
+
+	return visibilityArchiver.Archive(ctx, request.VisibilityURI, record)
+}
+
+func (a *archiver) Export(request *Request) {
+	record, err := a.visibilityRecord(request)
+	if err == nil {
+		err = a.exporter.Export(request.ExportURI, &export.Request{
+			Format:    request.ExportFormat,
+			History:   historyArchiveRequest(request),
+			Execution: record,
+		})
+	}
+	if err != nil {
+		a.metricsHandler.Counter(metrics.ExportFailedWorkflowExecutions.Name()).Record(
+			1,
+			metrics.NamespaceTag(request.Namespace),
+		)
+		a.requestLogger(request).Error(
+			"failed to export workflow",
+			tag.ArchivalURI(request.ExportURI.String()),
+			tag.Error(err),
+		)
+	}
+}
+
+func (a *archiver) requestLogger(request *Request) log.Logger {
+	return log.With(
+		a.logger,
+		tag.ShardID(request.ShardID),
+		tag.ArchivalCallerServiceName(request.CallerService),
+		tag.ArchivalRequestNamespaceID(request.NamespaceID),
+		tag.ArchivalRequestNamespace(request.Namespace),
+		tag.ArchivalRequestWorkflowID(request.WorkflowID),
+		tag.ArchivalRequestRunID(request.RunID),
+	)
+}
+
+func historyArchiveRequest(request *Request) *carchiver.ArchiveHistoryRequest {
+	return &carchiver.ArchiveHistoryRequest{
+		ShardID:              request.ShardID,
+		NamespaceID:          request.NamespaceID,
+		Namespace:            request.Namespace,
+		WorkflowID:           request.WorkflowID,
+		RunID:                request.RunID,
+		BranchToken:          request.BranchToken,
+		NextEventID:          request.NextEventID,
+		CloseFailoverVersion: request.CloseFailoverVersion,
+	}
+}
+
+func (a *archiver) visibilityRecord(request *Request) (*archiverspb.VisibilityRecord, error) {
 	// The types of the search attributes may not be embedded in the request,
 	// so we fetch them from the search attributes provider here.
 	saTypeMap, err := a.searchAttributeProvider.GetSearchAttributes(a.visibilityManager.GetIndexName(), false)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	searchAttributes, err := searchattribute.Stringify(request.SearchAttributes, &saTypeMap)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	var historyArchivalUri string
@@ -253,7 +304,7 @@ func (a *archiver) archiveVisibility(ctx context.Context, request *Request, logg
 		historyArchivalUri = request.HistoryURI.String()
 	}
 
-	return visibilityArchiver.Archive(ctx, request.VisibilityURI, &archiverspb.VisibilityRecord{
+	return &archiverspb.VisibilityRecord{
 		NamespaceId:        request.NamespaceID,
 		Namespace:          request.Namespace,
 		WorkflowId:         request.WorkflowID,
@@ -267,7 +318,7 @@ func (a *archiver) archiveVisibility(ctx context.Context, request *Request, logg
 		Memo:               request.Memo,
 		SearchAttributes:   searchAttributes,
 		HistoryArchivalUri: historyArchivalUri,
-	})
+	}, nil
 }
 
 // recordArchiveTargetResult takes an error pointer as an argument so that it isn't passed-by-value when used in a defer
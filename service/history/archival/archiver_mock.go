@@ -72,3 +72,15 @@ func (mr *MockArchiverMockRecorder) Archive(arg0, arg1 interface{}) *gomock.Call
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Archive", reflect.TypeOf((*MockArchiver)(nil).Archive), arg0, arg1)
 }
+
+// Export mocks base method.
+func (m *MockArchiver) Export(arg0 *Request) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "Export", arg0)
+}
+
+// Export indicates an expected call of Export.
+func (mr *MockArchiverMockRecorder) Export(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Export", reflect.TypeOf((*MockArchiver)(nil).Export), arg0)
+}
@@ -37,10 +37,14 @@ import (
 
 	"go.temporal.io/api/common/v1"
 	carchiver "go.temporal.io/server/common/archiver"
+	"go.temporal.io/server/common/archiver/export"
 	"go.temporal.io/server/common/archiver/provider"
+	"go.temporal.io/server/common/clock"
+	"go.temporal.io/server/common/config"
 	"go.temporal.io/server/common/log"
 	"go.temporal.io/server/common/metrics"
 	"go.temporal.io/server/common/payload"
+	"go.temporal.io/server/common/persistence"
 	"go.temporal.io/server/common/persistence/visibility/manager"
 	"go.temporal.io/server/common/quotas"
 	"go.temporal.io/server/common/sdk"
@@ -54,6 +58,7 @@ func TestArchiver(t *testing.T) {
 		Name                 string
 		ArchiveHistoryErr    error
 		ArchiveVisibilityErr error
+		ExportErr            error
 		RateLimiterWaitErr   error
 		Targets              []Target
 		SearchAttributes     *common.SearchAttributes
@@ -64,6 +69,7 @@ func TestArchiver(t *testing.T) {
 
 		ExpectArchiveHistory    bool
 		ExpectArchiveVisibility bool
+		ExpectExport            bool
 		ExpectedReturnErrors    []string
 	}{
 		{
@@ -131,6 +137,21 @@ func TestArchiver(t *testing.T) {
 			ExpectArchiveHistory:    true,
 			ExpectArchiveVisibility: true,
 		},
+		{
+			Name: "Export succeeds",
+
+			ExpectExport: true,
+		},
+		{
+			// export failures are only logged, they don't fail archival
+			Name:      "Export fails",
+			Targets:   []Target{TargetHistory, TargetVisibility},
+			ExportErr: errors.New("example export error"),
+
+			ExpectArchiveHistory:    true,
+			ExpectArchiveVisibility: true,
+			ExpectExport:            true,
+		},
 		{
 			Name:               "Rate limit hit",
 			Targets:            []Target{TargetHistory, TargetVisibility},
@@ -211,6 +232,20 @@ func TestArchiver(t *testing.T) {
 					Return(c.ArchiveVisibilityErr)
 			}
 
+			exportURI, err := carchiver.NewURI("file:///export")
+			require.NoError(t, err)
+			exporter := export.NewMockExporter(controller)
+			if c.ExpectExport {
+				exporter.EXPECT().Export(exportURI, gomock.Any()).DoAndReturn(
+					func(_ carchiver.URI, request *export.Request) error {
+						assert.Equal(t, export.FormatParquet, request.Format)
+						assert.Equal(t, "run-id", request.History.RunID)
+						assert.Equal(t, "run-id", request.Execution.GetRunId())
+						return c.ExportErr
+					},
+				)
+			}
+
 			rateLimiter := quotas.NewMockRateLimiter(controller)
 			rateLimiter.EXPECT().WaitN(gomock.Any(), len(c.Targets)).Return(c.RateLimiterWaitErr)
 
@@ -231,6 +266,9 @@ func TestArchiver(t *testing.T) {
 				fx.Supply(fx.Annotate(metricsHandler, fx.As(new(metrics.Handler)))),
 				fx.Supply(fx.Annotate(searchAttributeProvider, fx.As(new(searchattribute.Provider)))),
 				fx.Supply(fx.Annotate(visibilityManager, fx.As(new(manager.VisibilityManager)))),
+				fx.Supply(fx.Annotate(persistence.NewMockExecutionManager(controller), fx.As(new(persistence.ExecutionManager)))),
+				fx.Supply(fx.Annotate(clock.NewRealTimeSource(), fx.As(new(clock.TimeSource)))),
+				fx.Supply(&config.Config{}),
 				fx.Supply(&configs.Config{
 					ArchivalBackendMaxRPS: func() float64 {
 						return 42.0
@@ -243,6 +281,9 @@ func TestArchiver(t *testing.T) {
 					assert.Equal(t, 42.0, rl.Rate())
 					return rateLimiter
 				}),
+				fx.Decorate(func(export.Exporter) export.Exporter {
+					return exporter
+				}),
 				fx.Invoke(func(a Archiver) {
 					// after all parameters are provided, we get the Archiver and put it in the channel
 					// so that we can use it in the test
@@ -259,12 +300,19 @@ func TestArchiver(t *testing.T) {
 
 			archiver := <-archivers
 			searchAttributes := c.SearchAttributes
-			_, err = archiver.Archive(ctx, &Request{
+			request := &Request{
+				RunID:            "run-id",
 				HistoryURI:       historyURI,
 				VisibilityURI:    visibilityURI,
+				ExportURI:        exportURI,
+				ExportFormat:     export.FormatParquet,
 				Targets:          c.Targets,
 				SearchAttributes: searchAttributes,
-			})
+			}
+			_, err = archiver.Archive(ctx, request)
+			if c.ExpectExport {
+				archiver.Export(request)
+			}
 
 			if len(c.ExpectedReturnErrors) > 0 {
 				require.Error(t, err)
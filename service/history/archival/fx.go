@@ -27,12 +27,36 @@ package archival
 import (
 	"go.uber.org/fx"
 
+	"go.temporal.io/server/common/archiver/export"
+	"go.temporal.io/server/common/clock"
+	"go.temporal.io/server/common/config"
+	"go.temporal.io/server/common/log"
+	"go.temporal.io/server/common/metrics"
+	"go.temporal.io/server/common/persistence"
 	"go.temporal.io/server/common/quotas"
 	"go.temporal.io/server/service/history/configs"
 )
 
 var Module = fx.Options(
 	fx.Provide(NewArchiver),
+	fx.Provide(func(
+		cfg *config.Config,
+		historyConfig *configs.Config,
+		executionManager persistence.ExecutionManager,
+		timeSource clock.TimeSource,
+		logger log.Logger,
+		metricsHandler metrics.Handler,
+	) (export.Exporter, error) {
+		return export.NewExporter(
+			executionManager,
+			cfg.Archival.History.Provider,
+			timeSource,
+			logger,
+			metricsHandler,
+			historyConfig.ExportBatchInterval,
+			historyConfig.ExportBatchMaxExecutions,
+		)
+	}),
 	fx.Provide(func(config *configs.Config) quotas.RateLimiter {
 		return quotas.NewDefaultOutgoingRateLimiter(quotas.RateFn(config.ArchivalBackendMaxRPS))
 	}),
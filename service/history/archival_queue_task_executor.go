@@ -34,9 +34,11 @@ import (
 	"google.golang.org/protobuf/types/known/timestamppb"
 
 	carchiver "go.temporal.io/server/common/archiver"
+	"go.temporal.io/server/common/archiver/export"
 	"go.temporal.io/server/common/log"
 	"go.temporal.io/server/common/log/tag"
 	"go.temporal.io/server/common/metrics"
+	"go.temporal.io/server/common/namespace"
 	"go.temporal.io/server/common/persistence"
 	"go.temporal.io/server/common/primitives"
 	"go.temporal.io/server/service/history/archival"
@@ -49,8 +51,9 @@ import (
 
 // NewArchivalQueueTaskExecutor creates a new queue task executor for the archival queue.
 // If you use this executor, you must monitor for any metrics.ArchivalTaskInvalidURI errors.
-// If this metric is emitted, it means that an archival URI is invalid and the task will never succeed, which is a
-// serious problem because the archival queue retries tasks forever.
+// If this metric is emitted for a history or visibility URI, it means that the URI is invalid and the task will never
+// succeed, which is a serious problem because the archival queue retries tasks forever. An invalid export URI only
+// disables export.
 func NewArchivalQueueTaskExecutor(
 	archiver archival.Archiver,
 	shardContext shard.Context,
@@ -128,6 +131,10 @@ func (e *archivalQueueTaskExecutor) processArchiveExecutionTask(ctx context.Cont
 			return err
 		}
 	}
+	// export only starts once archival succeeded, so that the retries of a failed archival don't export again
+	if request.ExportURI != nil {
+		e.archiver.Export(request)
+	}
 	return e.addDeletionTask(ctx, logger, task, request.CloseTime.AsTime())
 }
 
@@ -206,6 +213,7 @@ func (e *archivalQueueTaskExecutor) getArchiveTaskRequest(
 		}
 		targets = append(targets, archival.TargetHistory)
 	}
+	exportURI, exportFormat := e.getExportURI(logger, namespaceName)
 
 	workflowAttributes, err := e.relocatableAttributesFetcher.Fetch(ctx, mutableState)
 	if err != nil {
@@ -223,6 +231,8 @@ func (e *archivalQueueTaskExecutor) getArchiveTaskRequest(
 		CloseFailoverVersion: mutableState.LastWriteVersion,
 		HistoryURI:           historyURI,
 		VisibilityURI:        visibilityURI,
+		ExportURI:            exportURI,
+		ExportFormat:         exportFormat,
 		WorkflowTypeName:     executionInfo.GetWorkflowTypeName(),
 		StartTime:            executionInfo.GetStartTime(),
 		ExecutionTime:        executionInfo.GetExecutionTime(),
@@ -237,6 +247,37 @@ func (e *archivalQueueTaskExecutor) getArchiveTaskRequest(
 	return request, nil
 }
 
+// getExportURI returns the URI and format that the workflow executions of the namespace are exported to, or a nil URI if
+// export is disabled. Export is best effort, so an invalid export config disables it instead of failing the task.
+func (e *archivalQueueTaskExecutor) getExportURI(logger log.Logger, namespaceName namespace.Name) (carchiver.URI, export.Format) {
+	exportURIString := e.shardContext.GetConfig().ExportURI(namespaceName.String())
+	if exportURIString == "" {
+		return nil, ""
+	}
+	exportURI, err := carchiver.NewURI(exportURIString)
+	if err == nil {
+		err = export.ValidateURI(exportURI)
+	}
+	var exportFormat export.Format
+	if err == nil {
+		exportFormat, err = export.ParseFormat(e.shardContext.GetConfig().ExportFormat(namespaceName.String()))
+	}
+	if err != nil {
+		e.metricsHandler.Counter(metrics.ArchivalTaskInvalidURI.Name()).Record(
+			1,
+			metrics.NamespaceTag(namespaceName.String()),
+			metrics.FailureTag(metrics.InvalidExportURITagValue),
+		)
+		logger.Error(
+			"Failed to parse export URI, the workflow isn't exported.",
+			tag.ArchivalURI(exportURIString),
+			tag.Error(err),
+		)
+		return nil, ""
+	}
+	return exportURI, exportFormat
+}
+
 // addDeletionTask adds a task to delete workflow history events from primary storage.
 func (e *archivalQueueTaskExecutor) addDeletionTask(
 	ctx context.Context,
@@ -202,9 +202,57 @@ func TestArchivalQueueTaskExecutor(t *testing.T) {
 				p.MetricsHandler.EXPECT().Counter("archival_task_invalid_uri").Return(mockCounter)
 			},
 		},
+		{
+			Name: "export enabled",
+			Configure: func(p *params) {
+				p.HistoryConfig.ClusterEnabled = false
+				p.VisibilityConfig.ClusterEnabled = false
+				p.ExportURI = "s3://bucket/export"
+				p.ExportFormat = "parquet"
+				p.ExpectArchive = false
+				p.ExpectExport = true
+			},
+		},
+		{
+			Name: "export and archival enabled",
+			Configure: func(p *params) {
+				p.ExportURI = "file:///export"
+				p.ExpectExport = true
+			},
+		},
+		{
+			// export is best effort, an invalid export URI only disables it
+			Name: "invalid export URI",
+			Configure: func(p *params) {
+				p.ExportURI = "gs://bucket/export"
+				mockCounter := metrics.NewMockCounterIface(p.Controller)
+				mockCounter.EXPECT().Record(
+					int64(1),
+					metrics.NamespaceTag(tests.Namespace.String()),
+					metrics.FailureTag("invalid_export_uri"),
+				)
+				p.MetricsHandler.EXPECT().Counter("archival_task_invalid_uri").Return(mockCounter)
+			},
+		},
+		{
+			Name: "invalid export format",
+			Configure: func(p *params) {
+				p.ExportURI = "file:///export"
+				p.ExportFormat = "csv"
+				mockCounter := metrics.NewMockCounterIface(p.Controller)
+				mockCounter.EXPECT().Record(
+					int64(1),
+					metrics.NamespaceTag(tests.Namespace.String()),
+					metrics.FailureTag("invalid_export_uri"),
+				)
+				p.MetricsHandler.EXPECT().Counter("archival_task_invalid_uri").Return(mockCounter)
+			},
+		},
 		{
 			Name: "archiver error",
 			Configure: func(p *params) {
+				// the workflow is exported once archival succeeds
+				p.ExportURI = "file:///export"
 				p.ArchiveError = errors.New("archiver error")
 				p.ExpectedErrorSubstrings = []string{"archiver error"}
 				p.ExpectAddTask = false
@@ -329,6 +377,7 @@ func TestArchivalQueueTaskExecutor(t *testing.T) {
 			}
 			p.HistoryURI = "test://history/archival"
 			p.VisibilityURI = "test://visibility/archival"
+			p.ExportFormat = "jsonl"
 			p.ExpectedTargets = []archival.Target{
 				archival.TargetHistory,
 				archival.TargetVisibility,
@@ -354,6 +403,12 @@ func TestArchivalQueueTaskExecutor(t *testing.T) {
 			cfg.RetentionTimerJitterDuration = func() time.Duration {
 				return 0
 			}
+			cfg.ExportURI = func(string) string {
+				return p.ExportURI
+			}
+			cfg.ExportFormat = func(string) string {
+				return p.ExportFormat
+			}
 			shardContext.EXPECT().GetConfig().Return(cfg).AnyTimes()
 			mockMetadata := cluster.NewMockMetadata(p.Controller)
 			mockMetadata.EXPECT().IsGlobalNamespaceEnabled().Return(true).AnyTimes()
@@ -491,6 +546,13 @@ func TestArchivalQueueTaskExecutor(t *testing.T) {
 					return &archival.Response{}, p.ArchiveError
 				})
 			}
+			if p.ExpectExport {
+				a.EXPECT().Export(gomock.Any()).Do(func(request *archival.Request) {
+					assert.Equal(t, p.CloseTime, request.CloseTime.AsTime())
+					assert.Equal(t, p.ExportURI, request.ExportURI.String())
+					assert.Equal(t, p.ExportFormat, string(request.ExportFormat))
+				})
+			}
 
 			visibilityManager := manager.NewMockVisibilityManager(p.Controller)
 			if p.CloseVisibilityTaskCompleted {
@@ -554,6 +616,7 @@ type params struct {
 	ExpectedDeleteTime                     time.Time
 	ExpectedErrorSubstrings                []string
 	ExpectArchive                          bool
+	ExpectExport                           bool
 	ExpectAddTask                          bool
 	ExpectedTargets                        []archival.Target
 	HistoryConfig                          archivalConfig
@@ -565,6 +628,8 @@ type params struct {
 	GetNamespaceByIDError                  error
 	HistoryURI                             string
 	VisibilityURI                          string
+	ExportURI                              string
+	ExportFormat                           string
 	MetricsHandler                         *metrics.MockHandler
 	MutableStateExists                     bool
 	ArchiveError                           error
@@ -65,6 +65,7 @@ type Config struct {
 	ThrottledLogRPS            dynamicconfig.IntPropertyFn
 	EnableStickyQuery          dynamicconfig.BoolPropertyFnWithNamespaceFilter
 	ShutdownDrainDuration      dynamicconfig.DurationPropertyFn
+	ShutdownDrainShardsTimeout dynamicconfig.DurationPropertyFn
 	StartupMembershipJoinDelay dynamicconfig.DurationPropertyFn
 
 	// HistoryCache settings
@@ -132,6 +133,10 @@ type Config struct {
 	TimerProcessorMaxTimeShift                       dynamicconfig.DurationPropertyFn
 	RetentionTimerJitterDuration                     dynamicconfig.DurationPropertyFn
 
+	// SubscribeTimerProcessorSchedulerRoundRobinWeights calls back when the active or standby
+	// timer scheduler weights change for any namespace.
+	SubscribeTimerProcessorSchedulerRoundRobinWeights func(callback func()) dynamicconfig.SubscriptionCancelFn
+
 	MemoryTimerProcessorSchedulerWorkerCount dynamicconfig.IntPropertyFn
 
 	// TransferQueueProcessor settings
@@ -151,6 +156,10 @@ type Config struct {
 	TransferProcessorPollBackoffInterval                dynamicconfig.DurationPropertyFn
 	TransferProcessorEnsureCloseBeforeDelete            dynamicconfig.BoolPropertyFn
 
+	// SubscribeTransferProcessorSchedulerRoundRobinWeights calls back when the active or standby
+	// transfer scheduler weights change for any namespace.
+	SubscribeTransferProcessorSchedulerRoundRobinWeights func(callback func()) dynamicconfig.SubscriptionCancelFn
+
 	// ReplicatorQueueProcessor settings
 	// TODO: clean up unused replicator settings
 	ReplicatorTaskBatchSize                               dynamicconfig.IntPropertyFn
@@ -181,6 +190,9 @@ type Config struct {
 
 	// encoding the history events
 	EventEncodingType dynamicconfig.StringPropertyFnWithNamespaceFilter
+	// where the fairness key attached to tasks added to matching is taken from
+	MatchingFairnessKeySource dynamicconfig.StringPropertyFnWithNamespaceFilter
+	MatchingFairnessKeyName   dynamicconfig.StringPropertyFnWithNamespaceFilter
 	// whether or not using ParentClosePolicy
 	EnableParentClosePolicy dynamicconfig.BoolPropertyFnWithNamespaceFilter
 	// whether or not enable system workers for processing parent close policy task
@@ -287,6 +299,10 @@ type Config struct {
 	VisibilityProcessorEnsureCloseBeforeDelete            dynamicconfig.BoolPropertyFn
 	VisibilityProcessorEnableCloseWorkflowCleanup         dynamicconfig.BoolPropertyFnWithNamespaceFilter
 
+	// SubscribeVisibilityProcessorSchedulerRoundRobinWeights calls back when the active or standby
+	// visibility scheduler weights change for any namespace.
+	SubscribeVisibilityProcessorSchedulerRoundRobinWeights func(callback func()) dynamicconfig.SubscriptionCancelFn
+
 	SearchAttributesNumberOfKeysLimit dynamicconfig.IntPropertyFnWithNamespaceFilter
 	SearchAttributesSizeOfValueLimit  dynamicconfig.IntPropertyFnWithNamespaceFilter
 	SearchAttributesTotalSizeLimit    dynamicconfig.IntPropertyFnWithNamespaceFilter
@@ -314,6 +330,10 @@ type Config struct {
 	ArchivalProcessorUpdateAckIntervalJitterCoefficient dynamicconfig.FloatPropertyFn
 	ArchivalProcessorArchiveDelay                       dynamicconfig.DurationPropertyFn
 	ArchivalBackendMaxRPS                               dynamicconfig.FloatPropertyFn
+	ExportURI                                           dynamicconfig.StringPropertyFnWithNamespaceFilter
+	ExportFormat                                        dynamicconfig.StringPropertyFnWithNamespaceFilter
+	ExportBatchInterval                                 dynamicconfig.DurationPropertyFn
+	ExportBatchMaxExecutions                            dynamicconfig.IntPropertyFn
 
 	WorkflowExecutionMaxInFlightUpdates dynamicconfig.IntPropertyFnWithNamespaceFilter
 	WorkflowExecutionMaxTotalUpdates    dynamicconfig.IntPropertyFnWithNamespaceFilter
@@ -352,6 +372,7 @@ func NewConfig(
 		EnablePersistencePriorityRateLimiting: dc.GetBoolProperty(dynamicconfig.HistoryEnablePersistencePriorityRateLimiting, true),
 		PersistenceDynamicRateLimitingParams:  dc.GetMapProperty(dynamicconfig.HistoryPersistenceDynamicRateLimitingParams, dynamicconfig.DefaultDynamicRateLimitingParams),
 		ShutdownDrainDuration:                 dc.GetDurationProperty(dynamicconfig.HistoryShutdownDrainDuration, 0*time.Second),
+		ShutdownDrainShardsTimeout:            dc.GetDurationProperty(dynamicconfig.HistoryShutdownDrainShardsTimeout, 0*time.Second),
 		StartupMembershipJoinDelay:            dc.GetDurationProperty(dynamicconfig.HistoryStartupMembershipJoinDelay, 0*time.Second),
 		MaxAutoResetPoints:                    dc.GetIntPropertyFilteredByNamespace(dynamicconfig.HistoryMaxAutoResetPoints, DefaultHistoryMaxAutoResetPoints),
 		DefaultWorkflowTaskTimeout:            dc.GetDurationPropertyFilteredByNamespace(dynamicconfig.DefaultWorkflowTaskTimeout, common.DefaultWorkflowTaskTimeout),
@@ -422,6 +443,8 @@ func NewConfig(
 		TimerProcessorMaxTimeShift:                       dc.GetDurationProperty(dynamicconfig.TimerProcessorMaxTimeShift, 1*time.Second),
 		RetentionTimerJitterDuration:                     dc.GetDurationProperty(dynamicconfig.RetentionTimerJitterDuration, 30*time.Minute),
 
+		SubscribeTimerProcessorSchedulerRoundRobinWeights: subscribeKeys(dc, dynamicconfig.TimerProcessorSchedulerActiveRoundRobinWeights, dynamicconfig.TimerProcessorSchedulerStandbyRoundRobinWeights),
+
 		MemoryTimerProcessorSchedulerWorkerCount: dc.GetIntProperty(dynamicconfig.MemoryTimerProcessorSchedulerWorkerCount, 64),
 
 		TransferTaskBatchSize:                               dc.GetIntProperty(dynamicconfig.TransferTaskBatchSize, 100),
@@ -439,6 +462,8 @@ func NewConfig(
 		TransferProcessorPollBackoffInterval:                dc.GetDurationProperty(dynamicconfig.TransferProcessorPollBackoffInterval, 5*time.Second),
 		TransferProcessorEnsureCloseBeforeDelete:            dc.GetBoolProperty(dynamicconfig.TransferProcessorEnsureCloseBeforeDelete, true),
 
+		SubscribeTransferProcessorSchedulerRoundRobinWeights: subscribeKeys(dc, dynamicconfig.TransferProcessorSchedulerActiveRoundRobinWeights, dynamicconfig.TransferProcessorSchedulerStandbyRoundRobinWeights),
+
 		ReplicatorTaskBatchSize:                               dc.GetIntProperty(dynamicconfig.ReplicatorTaskBatchSize, 100),
 		ReplicatorTaskWorkerCount:                             dc.GetIntProperty(dynamicconfig.ReplicatorTaskWorkerCount, 10),
 		ReplicatorProcessorMaxPollRPS:                         dc.GetIntProperty(dynamicconfig.ReplicatorProcessorMaxPollRPS, 20),
@@ -471,6 +496,8 @@ func NewConfig(
 		// TODO: Return this value to the client: go.temporal.io/server/issues/294
 		LongPollExpirationInterval:          dc.GetDurationPropertyFilteredByNamespace(dynamicconfig.HistoryLongPollExpirationInterval, time.Second*20),
 		EventEncodingType:                   dc.GetStringPropertyFnWithNamespaceFilter(dynamicconfig.DefaultEventEncoding, enumspb.ENCODING_TYPE_PROTO3.String()),
+		MatchingFairnessKeySource:           dc.GetStringPropertyFnWithNamespaceFilter(dynamicconfig.MatchingFairnessKeySource, ""),
+		MatchingFairnessKeyName:             dc.GetStringPropertyFnWithNamespaceFilter(dynamicconfig.MatchingFairnessKeyName, ""),
 		EnableParentClosePolicy:             dc.GetBoolPropertyFnWithNamespaceFilter(dynamicconfig.EnableParentClosePolicy, true),
 		NumParentClosePolicySystemWorkflows: dc.GetIntProperty(dynamicconfig.NumParentClosePolicySystemWorkflows, 10),
 		EnableParentClosePolicyWorker:       dc.GetBoolProperty(dynamicconfig.EnableParentClosePolicyWorker, true),
@@ -544,6 +571,8 @@ func NewConfig(
 		VisibilityProcessorEnsureCloseBeforeDelete:            dc.GetBoolProperty(dynamicconfig.VisibilityProcessorEnsureCloseBeforeDelete, false),
 		VisibilityProcessorEnableCloseWorkflowCleanup:         dc.GetBoolPropertyFnWithNamespaceFilter(dynamicconfig.VisibilityProcessorEnableCloseWorkflowCleanup, false),
 
+		SubscribeVisibilityProcessorSchedulerRoundRobinWeights: subscribeKeys(dc, dynamicconfig.VisibilityProcessorSchedulerActiveRoundRobinWeights, dynamicconfig.VisibilityProcessorSchedulerStandbyRoundRobinWeights),
+
 		SearchAttributesNumberOfKeysLimit: dc.GetIntPropertyFilteredByNamespace(dynamicconfig.SearchAttributesNumberOfKeysLimit, 100),
 		SearchAttributesSizeOfValueLimit:  dc.GetIntPropertyFilteredByNamespace(dynamicconfig.SearchAttributesSizeOfValueLimit, 2*1024),
 		SearchAttributesTotalSizeLimit:    dc.GetIntPropertyFilteredByNamespace(dynamicconfig.SearchAttributesTotalSizeLimit, 40*1024),
@@ -577,6 +606,10 @@ func NewConfig(
 		ArchivalProcessorPollBackoffInterval: dc.GetDurationProperty(dynamicconfig.ArchivalProcessorPollBackoffInterval, 5*time.Second),
 		ArchivalProcessorArchiveDelay:        dc.GetDurationProperty(dynamicconfig.ArchivalProcessorArchiveDelay, 5*time.Minute),
 		ArchivalBackendMaxRPS:                dc.GetFloat64Property(dynamicconfig.ArchivalBackendMaxRPS, 10000.0),
+		ExportURI:                            dc.GetStringPropertyFnWithNamespaceFilter(dynamicconfig.HistoryExportURI, ""),
+		ExportFormat:                         dc.GetStringPropertyFnWithNamespaceFilter(dynamicconfig.HistoryExportFormat, "jsonl"),
+		ExportBatchInterval:                  dc.GetDurationProperty(dynamicconfig.HistoryExportBatchInterval, 10*time.Second),
+		ExportBatchMaxExecutions:             dc.GetIntProperty(dynamicconfig.HistoryExportBatchMaxExecutions, 100),
 
 		// workflow update related
 		WorkflowExecutionMaxInFlightUpdates: dc.GetIntPropertyFilteredByNamespace(dynamicconfig.WorkflowExecutionMaxInFlightUpdates, 10),
@@ -594,3 +627,18 @@ func NewConfig(
 func (config *Config) GetShardID(namespaceID namespace.ID, workflowID string) int32 {
 	return common.WorkflowIDToHistoryShard(namespaceID.String(), workflowID, config.NumberOfShards)
 }
+
+// subscribeKeys returns a function that subscribes a callback to changes of any of keys.
+func subscribeKeys(dc *dynamicconfig.Collection, keys ...dynamicconfig.Key) func(callback func()) dynamicconfig.SubscriptionCancelFn {
+	return func(callback func()) dynamicconfig.SubscriptionCancelFn {
+		cancels := make([]dynamicconfig.SubscriptionCancelFn, len(keys))
+		for i, key := range keys {
+			cancels[i] = dc.SubscribeKey(key, callback)
+		}
+		return func() {
+			for _, cancel := range cancels {
+				cancel()
+			}
+		}
+	}
+}
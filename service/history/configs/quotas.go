@@ -43,6 +43,8 @@ var (
 		"DescribeHistoryHost":                    1,
 		"DescribeMutableState":                   1,
 		"DescribeWorkflowExecution":              1,
+		"DrainHistoryHost":                       1,
+		"UndrainHistoryHost":                     1,
 		"GetDLQMessages":                         1,
 		"GetDLQReplicationMessages":              1,
 		"GetMutableState":                        1,
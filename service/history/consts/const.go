@@ -97,6 +97,8 @@ var (
 	ErrWorkflowTaskNotScheduled = serviceerror.NewWorkflowNotReady("Workflow task is not scheduled yet.")
 	// ErrNamespaceHandover is error indicating namespace is in handover state and cannot process request.
 	ErrNamespaceHandover = common.ErrNamespaceHandover
+	// ErrWorkflowExecutionMoving is error indicating workflow execution is being moved to another shard by a history shard count migration.
+	ErrWorkflowExecutionMoving = serviceerror.NewUnavailable("Workflow execution is being moved to another history shard.")
 	// ErrWorkflowTaskStateInconsistent is error indicating workflow task state is inconsistent, for example there was no workflow task scheduled but buffered events are present.
 	ErrWorkflowTaskStateInconsistent = serviceerror.NewUnavailable("Workflow task state is inconsistent.")
 	// ErrResourceExhaustedBusyWorkflow is an error indicating workflow resource is exhausted and should not be retried by service handler and client
@@ -622,11 +622,36 @@ func (h *Handler) DescribeHistoryHost(_ context.Context, _ *historyservice.Descr
 			ItemsInCacheByIdCount:   itemsInCacheByIDCount,
 			ItemsInCacheByNameCount: itemsInCacheByNameCount,
 		},
-		Address: h.hostInfoProvider.HostInfo().GetAddress(),
+		Address:   h.hostInfoProvider.HostInfo().GetAddress(),
+		DrainInfo: h.controller.DrainInfo(),
 	}
 	return resp, nil
 }
 
+// DrainHistoryHost starts handing off the shards of this instance to the other history instances
+func (h *Handler) DrainHistoryHost(_ context.Context, _ *historyservice.DrainHistoryHostRequest) (_ *historyservice.DrainHistoryHostResponse, retError error) {
+	defer metrics.CapturePanic(h.logger, h.metricsHandler, &retError)
+	h.startWG.Wait()
+
+	drainInfo, err := h.controller.StartDrain()
+	if err != nil {
+		return nil, err
+	}
+	return &historyservice.DrainHistoryHostResponse{DrainInfo: drainInfo}, nil
+}
+
+// UndrainHistoryHost stops the drain of this instance, which acquires shards again
+func (h *Handler) UndrainHistoryHost(_ context.Context, _ *historyservice.UndrainHistoryHostRequest) (_ *historyservice.UndrainHistoryHostResponse, retError error) {
+	defer metrics.CapturePanic(h.logger, h.metricsHandler, &retError)
+	h.startWG.Wait()
+
+	drainInfo, err := h.controller.Undrain()
+	if err != nil {
+		return nil, err
+	}
+	return &historyservice.UndrainHistoryHostResponse{DrainInfo: drainInfo}, nil
+}
+
 // RemoveTask returns information about the internal states of a history host
 func (h *Handler) RemoveTask(ctx context.Context, request *historyservice.RemoveTaskRequest) (_ *historyservice.RemoveTaskResponse, retError error) {
 	var err error
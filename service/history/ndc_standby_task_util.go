@@ -112,6 +112,9 @@ type (
 		taskQueue                          string
 		activityTaskScheduleToStartTimeout time.Duration
 		versionDirective                   *taskqueuespb.TaskVersionDirective
+		fairnessKey                        string
+		priority                           int32
+		activityTypeName                   string
 	}
 
 	workflowTaskPostActionInfo struct {
@@ -120,6 +123,9 @@ type (
 		workflowTaskScheduleToStartTimeout time.Duration
 		taskqueue                          *taskqueuepb.TaskQueue
 		versionDirective                   *taskqueuespb.TaskVersionDirective
+		fairnessKey                        string
+		priority                           int32
+		workflowTypeName                   string
 	}
 )
 
@@ -137,6 +143,9 @@ func newActivityTaskPostActionInfo(
 	mutableState workflow.MutableState,
 	activityScheduleToStartTimeout time.Duration,
 	useCompatibleVersion bool,
+	fairnessKey string,
+	priority int32,
+	activityTypeName string,
 ) (*activityTaskPostActionInfo, error) {
 	resendInfo, err := getHistoryResendInfo(mutableState)
 	if err != nil {
@@ -149,6 +158,9 @@ func newActivityTaskPostActionInfo(
 		historyResendInfo:                  resendInfo,
 		activityTaskScheduleToStartTimeout: activityScheduleToStartTimeout,
 		versionDirective:                   directive,
+		fairnessKey:                        fairnessKey,
+		priority:                           priority,
+		activityTypeName:                   activityTypeName,
 	}, nil
 }
 
@@ -157,6 +169,9 @@ func newActivityRetryTimePostActionInfo(
 	taskQueue string,
 	activityScheduleToStartTimeout time.Duration,
 	useCompatibleVersion bool,
+	fairnessKey string,
+	priority int32,
+	activityTypeName string,
 ) (*activityTaskPostActionInfo, error) {
 	resendInfo, err := getHistoryResendInfo(mutableState)
 	if err != nil {
@@ -170,6 +185,9 @@ func newActivityRetryTimePostActionInfo(
 		taskQueue:                          taskQueue,
 		activityTaskScheduleToStartTimeout: activityScheduleToStartTimeout,
 		versionDirective:                   directive,
+		fairnessKey:                        fairnessKey,
+		priority:                           priority,
+		activityTypeName:                   activityTypeName,
 	}, nil
 }
 
@@ -177,6 +195,8 @@ func newWorkflowTaskPostActionInfo(
 	mutableState workflow.MutableState,
 	workflowTaskScheduleToStartTimeout time.Duration,
 	taskqueue *taskqueuepb.TaskQueue,
+	fairnessKey string,
+	priority int32,
 ) (*workflowTaskPostActionInfo, error) {
 	resendInfo, err := getHistoryResendInfo(mutableState)
 	if err != nil {
@@ -193,6 +213,9 @@ func newWorkflowTaskPostActionInfo(
 		workflowTaskScheduleToStartTimeout: workflowTaskScheduleToStartTimeout,
 		taskqueue:                          taskqueue,
 		versionDirective:                   directive,
+		fairnessKey:                        fairnessKey,
+		priority:                           priority,
+		workflowTypeName:                   mutableState.GetExecutionInfo().GetWorkflowTypeName(),
 	}, nil
 }
 
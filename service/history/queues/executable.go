@@ -376,6 +376,12 @@ func (e *executableImpl) HandleErr(err error) (retErr error) {
 		return err
 	}
 
+	if err.Error() == consts.ErrWorkflowExecutionMoving.Error() {
+		e.taggedMetricsHandler.Counter(metrics.TaskWorkflowMovingCounter.Name()).Record(1)
+		err = consts.ErrWorkflowExecutionMoving
+		return err
+	}
+
 	if _, ok := err.(*serviceerror.NamespaceNotActive); ok {
 		// error is expected when there's namespace failover,
 		// so don't count it into task failures.
@@ -556,7 +562,8 @@ func (e *executableImpl) shouldResubmitOnNack(attempt int, err error) bool {
 
 	return err != consts.ErrTaskRetry &&
 		err != consts.ErrDependencyTaskNotCompleted &&
-		err != consts.ErrNamespaceHandover
+		err != consts.ErrNamespaceHandover &&
+		err != consts.ErrWorkflowExecutionMoving
 }
 
 func (e *executableImpl) backoffDuration(
@@ -568,6 +575,7 @@ func (e *executableImpl) backoffDuration(
 
 	if err == consts.ErrTaskRetry ||
 		err == consts.ErrNamespaceHandover ||
+		err == consts.ErrWorkflowExecutionMoving ||
 		common.IsInternalError(err) {
 		// using a different reschedule policy to slow down retry
 		// as immediate retry typically won't resolve the issue.
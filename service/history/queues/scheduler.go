@@ -73,6 +73,9 @@ type (
 		WorkerCount             dynamicconfig.IntPropertyFn
 		ActiveNamespaceWeights  dynamicconfig.MapPropertyFnWithNamespaceFilter
 		StandbyNamespaceWeights dynamicconfig.MapPropertyFnWithNamespaceFilter
+		// SubscribeWeights, if set, calls back when ActiveNamespaceWeights or
+		// StandbyNamespaceWeights change, so that channel weights are updated right away.
+		SubscribeWeights func(callback func()) dynamicconfig.SubscriptionCancelFn
 	}
 
 	RateLimitedSchedulerOptions struct {
@@ -87,6 +90,9 @@ type (
 		taskChannelKeyFn      TaskChannelKeyFn
 		channelWeightFn       ChannelWeightFn
 		channelWeightUpdateCh chan struct{}
+
+		subscribeWeights          func(callback func()) dynamicconfig.SubscriptionCancelFn
+		cancelWeightsSubscription dynamicconfig.SubscriptionCancelFn
 	}
 
 	rateLimitedSchedulerImpl struct {
@@ -159,24 +165,35 @@ func NewScheduler(
 		taskChannelKeyFn:      taskChannelKeyFn,
 		channelWeightFn:       channelWeightFn,
 		channelWeightUpdateCh: channelWeightUpdateCh,
+		subscribeWeights:      options.SubscribeWeights,
 	}
 }
 
 func (s *schedulerImpl) Start() {
 	if s.channelWeightUpdateCh != nil {
 		s.namespaceRegistry.RegisterStateChangeCallback(s, func(ns *namespace.Namespace, deletedFromDb bool) {
-			select {
-			case s.channelWeightUpdateCh <- struct{}{}:
-			default:
-			}
+			s.notifyChannelWeightUpdate()
 		})
+		if s.subscribeWeights != nil {
+			s.cancelWeightsSubscription = s.subscribeWeights(s.notifyChannelWeightUpdate)
+		}
 	}
 	s.Scheduler.Start()
 }
 
+func (s *schedulerImpl) notifyChannelWeightUpdate() {
+	select {
+	case s.channelWeightUpdateCh <- struct{}{}:
+	default:
+	}
+}
+
 func (s *schedulerImpl) Stop() {
 	if s.channelWeightUpdateCh != nil {
 		s.namespaceRegistry.UnregisterStateChangeCallback(s)
+		if s.cancelWeightsSubscription != nil {
+			s.cancelWeightsSubscription()
+		}
 
 		// note we can't close the channelWeightUpdateCh here
 		// as callback may still be triggered even after unregister returns
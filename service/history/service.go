@@ -25,6 +25,7 @@
 package history
 
 import (
+	"context"
 	"net"
 	"time"
 
@@ -121,6 +122,10 @@ func (s *Service) Start() {
 
 // Stop stops the service
 func (s *Service) Stop() {
+	if timeout := s.config.ShutdownDrainShardsTimeout(); timeout > 0 {
+		s.drainShards(timeout)
+	}
+
 	s.logger.Info("ShutdownHandler: Evicting self from membership ring")
 	_ = s.membershipMonitor.EvictSelf()
 
@@ -144,6 +149,22 @@ func (s *Service) Stop() {
 	s.logger.Info("history stopped")
 }
 
+// drainShards hands off the shards of this instance to the other history instances, so they
+// acquire them right away instead of stealing them once membership notices this instance is gone.
+func (s *Service) drainShards(timeout time.Duration) {
+	s.logger.Info("ShutdownHandler: Draining shards", tag.NewDurationTag("shutdownDrainShardsTimeout", timeout))
+	if _, err := s.handler.controller.StartDrain(); err != nil {
+		s.logger.Warn("ShutdownHandler: Unable to drain shards", tag.Error(err))
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	if err := s.handler.controller.WaitDrained(ctx); err != nil {
+		s.logger.Warn("ShutdownHandler: Timed out draining shards", tag.Error(err))
+	}
+}
+
 func (s *Service) GetFaultInjection() *client.FaultInjectionDataStoreFactory {
 	return s.faultInjectionDataStoreFactory
 }
@@ -128,5 +128,8 @@ type (
 
 		IsValid() bool
 		FinishStop()
+		// FlushShardInfo persists the shard info, including the queue ack levels, without
+		// waiting for ShardUpdateMinInterval to elapse.
+		FlushShardInfo() error
 	}
 )
@@ -38,6 +38,7 @@ import (
 	"go.temporal.io/server/common/namespace"
 	"go.temporal.io/server/common/persistence"
 	"go.temporal.io/server/common/persistence/serialization"
+	"go.temporal.io/server/common/persistence/shardmigration"
 	"go.temporal.io/server/common/resource"
 	"go.temporal.io/server/common/searchattribute"
 	"go.temporal.io/server/service/history/configs"
@@ -76,6 +77,7 @@ type (
 		ThrottledLogger             log.ThrottledLogger
 		TimeSource                  clock.TimeSource
 		TaskCategoryRegistry        tasks.TaskCategoryRegistry
+		ShardMigrationGate          shardmigration.Gate `optional:"true"`
 	}
 
 	contextFactoryImpl struct {
@@ -115,6 +117,7 @@ func (c *contextFactoryImpl) CreateContext(
 		c.ArchivalMetadata,
 		c.HostInfoProvider,
 		c.TaskCategoryRegistry,
+		c.ShardMigrationGate,
 	)
 	if err != nil {
 		return nil, err
@@ -66,6 +66,7 @@ import (
 	"go.temporal.io/server/common/namespace"
 	"go.temporal.io/server/common/persistence"
 	"go.temporal.io/server/common/persistence/serialization"
+	"go.temporal.io/server/common/persistence/shardmigration"
 	"go.temporal.io/server/common/primitives/timestamp"
 	"go.temporal.io/server/common/rpc"
 	"go.temporal.io/server/common/searchattribute"
@@ -95,6 +96,8 @@ const (
 
 var (
 	shardContextSequenceID int64 = 0
+
+	shardMigrationRetryPolicy = backoff.NewExponentialRetryPolicy(time.Second).WithMaximumInterval(time.Minute).WithExpirationInterval(backoff.NoInterval)
 )
 
 type (
@@ -127,6 +130,9 @@ type (
 		archivalMetadata        archiver.ArchivalMetadata
 		hostInfoProvider        membership.HostInfoProvider
 		taskCategoryRegistry    tasks.TaskCategoryRegistry
+		shardMigrationGate      shardmigration.Gate
+		// shardMigrationFence is set before the engine is created, and never changes afterwards.
+		shardMigrationFence *shardmigration.Fence
 
 		// Context that lives for the lifetime of the shard context
 		lifecycleCtx    context.Context
@@ -595,6 +601,11 @@ func (s *ContextImpl) CreateWorkflowExecution(
 		return nil, err
 	}
 
+	if err := s.errorByShardMigration(request.NewWorkflowSnapshot.ExecutionInfo.NamespaceId, request.NewWorkflowSnapshot.ExecutionInfo.WorkflowId); err != nil {
+		s.wUnlock()
+		return nil, err
+	}
+
 	requestCompletionFn, err := s.taskKeyManager.setAndTrackTaskKeys(
 		request.NewWorkflowSnapshot.Tasks,
 	)
@@ -653,6 +664,11 @@ func (s *ContextImpl) UpdateWorkflowExecution(
 		return nil, err
 	}
 
+	if err := s.errorByShardMigration(request.UpdateWorkflowMutation.ExecutionInfo.NamespaceId, request.UpdateWorkflowMutation.ExecutionInfo.WorkflowId); err != nil {
+		s.wUnlock()
+		return nil, err
+	}
+
 	taskMaps := make([]map[tasks.Category][]tasks.Task, 0, 2)
 	taskMaps = append(taskMaps, request.UpdateWorkflowMutation.Tasks)
 	if request.NewWorkflowSnapshot != nil {
@@ -730,6 +746,11 @@ func (s *ContextImpl) ConflictResolveWorkflowExecution(
 		return nil, err
 	}
 
+	if err := s.errorByShardMigration(request.ResetWorkflowSnapshot.ExecutionInfo.NamespaceId, request.ResetWorkflowSnapshot.ExecutionInfo.WorkflowId); err != nil {
+		s.wUnlock()
+		return nil, err
+	}
+
 	taskMaps := make([]map[tasks.Category][]tasks.Task, 0, 3)
 	if request.CurrentWorkflowMutation != nil {
 		taskMaps = append(taskMaps, request.CurrentWorkflowMutation.Tasks)
@@ -792,6 +813,11 @@ func (s *ContextImpl) SetWorkflowExecution(
 		return nil, err
 	}
 
+	if err := s.errorByShardMigration(request.SetWorkflowSnapshot.ExecutionInfo.NamespaceId, request.SetWorkflowSnapshot.ExecutionInfo.WorkflowId); err != nil {
+		s.wUnlock()
+		return nil, err
+	}
+
 	snapShotRequestCompletionFn, err := s.taskKeyManager.setAndTrackTaskKeys(
 		request.SetWorkflowSnapshot.Tasks,
 	)
@@ -818,6 +844,11 @@ func (s *ContextImpl) GetCurrentExecution(
 	if err := s.errorByState(); err != nil {
 		return nil, err
 	}
+	if s.shardMigrationFence != nil {
+		if err := s.errorByShardMigration(request.NamespaceID, request.WorkflowID); err != nil {
+			return nil, err
+		}
+	}
 
 	resp, err := s.executionManager.GetCurrentExecution(ctx, request)
 	if err = s.handleReadError(err); err != nil {
@@ -834,6 +865,11 @@ func (s *ContextImpl) GetWorkflowExecution(
 	if err := s.errorByState(); err != nil {
 		return nil, err
 	}
+	if s.shardMigrationFence != nil {
+		if err := s.errorByShardMigration(request.NamespaceID, request.WorkflowID); err != nil {
+			return nil, err
+		}
+	}
 
 	resp, err := s.executionManager.GetWorkflowExecution(ctx, request)
 	if err = s.handleReadError(err); err != nil {
@@ -874,6 +910,11 @@ func (s *ContextImpl) addTasksSemaphoreAcquired(
 		return err
 	}
 
+	if err := s.errorByShardMigration(request.NamespaceID, request.WorkflowID); err != nil {
+		s.wUnlock()
+		return err
+	}
+
 	requestCompletionFn, err := s.taskKeyManager.setAndTrackTaskKeys(
 		request.Tasks,
 	)
@@ -1144,6 +1185,25 @@ func (s *ContextImpl) errorByNamespaceStateLocked(
 	return nil
 }
 
+// errorByShardMigration returns an error if the execution is moved to another shard by a history shard count
+// migration: the execution can't be accessed until the shard is finalized, and doesn't exist here afterwards.
+func (s *ContextImpl) errorByShardMigration(
+	namespaceID string,
+	workflowID string,
+) error {
+	if s.shardMigrationFence == nil {
+		return nil
+	}
+	targetShardID, moved := s.shardMigrationFence.Moved(namespaceID, workflowID)
+	if !moved {
+		return nil
+	}
+	if s.shardMigrationFence.Finalized() {
+		return serviceerror.NewNotFound(fmt.Sprintf("Workflow execution was moved to history shard %d.", targetShardID))
+	}
+	return consts.ErrWorkflowExecutionMoving
+}
+
 func (s *ContextImpl) generateTaskIDLocked() (int64, error) {
 	taskKey, err := s.taskKeyManager.generateTaskKey(tasks.CategoryTransfer)
 	if err != nil {
@@ -1198,8 +1258,19 @@ func (s *ContextImpl) renewRangeLocked(isStealing bool) error {
 	return nil
 }
 
+func (s *ContextImpl) FlushShardInfo() error {
+	return s.updateShardInfoWithMinInterval(func() {}, 0)
+}
+
 func (s *ContextImpl) updateShardInfo(
 	updateFnLocked func(),
+) error {
+	return s.updateShardInfoWithMinInterval(updateFnLocked, s.config.ShardUpdateMinInterval())
+}
+
+func (s *ContextImpl) updateShardInfoWithMinInterval(
+	updateFnLocked func(),
+	minInterval time.Duration,
 ) error {
 	s.wLock()
 	if err := s.errorByState(); err != nil {
@@ -1211,7 +1282,7 @@ func (s *ContextImpl) updateShardInfo(
 	s.shardInfo.StolenSinceRenew = 0
 
 	now := cclock.NewRealTimeSource().Now()
-	if s.lastUpdated.Add(s.config.ShardUpdateMinInterval()).After(now) {
+	if s.lastUpdated.Add(minInterval).After(now) {
 		s.wUnlock()
 		return nil
 	}
@@ -1733,6 +1804,53 @@ func (s *ContextImpl) notifyReplicationQueueProcessor(taskID int64) {
 	})
 }
 
+func (s *ContextImpl) checkShardMigrationBeforeAcquire() error {
+	if s.shardMigrationGate == nil {
+		return nil
+	}
+	ctx, cancel := s.newIOContext()
+	defer cancel()
+	return s.shardMigrationGate.BeforeAcquire(ctx, s.shardID)
+}
+
+// fenceShardMigration fences off the executions which a history shard count migration moves out of the shard, and
+// returns the function that hands them over to their target shards, or nil if there's nothing to hand over.
+func (s *ContextImpl) fenceShardMigration() (func(), error) {
+	if s.shardMigrationGate == nil {
+		return nil, nil
+	}
+	ctx, cancel := s.newIOContext()
+	defer cancel()
+	fence, err := s.shardMigrationGate.AfterAcquire(ctx, s.shardID)
+	if err != nil || fence == nil {
+		return nil, err
+	}
+	s.shardMigrationFence = fence
+
+	s.rLock()
+	shardInfo := copyShardInfo(s.shardInfo)
+	s.rUnlock()
+	return func() { s.settleShardMigration(fence, shardInfo) }, nil
+}
+
+// settleShardMigration finalizes and cleans up the shard for a history shard count migration. It is retried until it
+// succeeds or the shard is closed.
+func (s *ContextImpl) settleShardMigration(fence *shardmigration.Fence, shardInfo *persistencespb.ShardInfo) {
+	// Moving a shard takes much longer than a single IO, so only the shard lifecycle bounds it.
+	ctx := headers.SetCallerInfo(s.lifecycleCtx, headers.SystemBackgroundCallerInfo)
+	op := func(ctx context.Context) error {
+		return s.shardMigrationGate.Settle(ctx, fence, shardInfo)
+	}
+	isRetryable := func(err error) bool {
+		if s.lifecycleCtx.Err() != nil {
+			return false
+		}
+		s.contextTaggedLogger.Error("Failed to settle history shard count migration", tag.Error(err))
+		return true
+	}
+	_ = backoff.ThrottleRetryContext(ctx, op, shardMigrationRetryPolicy, isRetryable)
+}
+
 func (s *ContextImpl) loadShardMetadata(ownershipChanged *bool) error {
 	// Only have to do this once, we can just re-acquire the rangeid lock after that
 	s.rLock()
@@ -1904,6 +2022,10 @@ func (s *ContextImpl) acquireShard() {
 			return s.newShardClosedErrorWithShardID()
 		}
 
+		if err := s.checkShardMigrationBeforeAcquire(); err != nil {
+			return err
+		}
+
 		// Initial load of shard metadata
 		err := s.loadShardMetadata(&ownershipChanged)
 		if err != nil {
@@ -1931,7 +2053,13 @@ func (s *ContextImpl) acquireShard() {
 
 		// The first time we get the shard, we have to create the engine
 		var engine Engine
+		var settleShardMigration func()
 		if !s.engineFuture.Ready() {
+			// Executions moved by a history shard count migration must be fenced off before the engine starts
+			// processing the queues.
+			if settleShardMigration, err = s.fenceShardMigration(); err != nil {
+				return err
+			}
 			s.maybeRecordShardAcquisitionLatency(ownershipChanged)
 			engine = s.createEngine()
 		}
@@ -1956,6 +2084,12 @@ func (s *ContextImpl) acquireShard() {
 
 		s.updateHandoverNamespacePendingTaskID()
 
+		if settleShardMigration != nil {
+			// Moving the executions can take long for a large shard, so the shard serves the executions which stay
+			// meanwhile.
+			go settleShardMigration()
+		}
+
 		return nil
 	}
 
@@ -2014,6 +2148,7 @@ func newContext(
 	archivalMetadata archiver.ArchivalMetadata,
 	hostInfoProvider membership.HostInfoProvider,
 	taskCategoryRegistry tasks.TaskCategoryRegistry,
+	shardMigrationGate shardmigration.Gate,
 ) (*ContextImpl, error) {
 	hostIdentity := hostInfoProvider.HostInfo().Identity()
 	sequenceID := atomic.AddInt64(&shardContextSequenceID, 1)
@@ -2053,6 +2188,7 @@ func newContext(
 		archivalMetadata:        archivalMetadata,
 		hostInfoProvider:        hostInfoProvider,
 		taskCategoryRegistry:    taskCategoryRegistry,
+		shardMigrationGate:      shardMigrationGate,
 		handoverNamespaces:      make(map[namespace.Name]*namespaceHandOverInfo),
 		lifecycleCtx:            lifecycleCtx,
 		lifecycleCancel:         lifecycleCancel,
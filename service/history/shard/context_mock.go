@@ -893,6 +893,20 @@ func (mr *MockControllableContextMockRecorder) FinishStop() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FinishStop", reflect.TypeOf((*MockControllableContext)(nil).FinishStop))
 }
 
+// FlushShardInfo mocks base method.
+func (m *MockControllableContext) FlushShardInfo() error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FlushShardInfo")
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// FlushShardInfo indicates an expected call of FlushShardInfo.
+func (mr *MockControllableContextMockRecorder) FlushShardInfo() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FlushShardInfo", reflect.TypeOf((*MockControllableContext)(nil).FlushShardInfo))
+}
+
 // GenerateTaskID mocks base method.
 func (m *MockControllableContext) GenerateTaskID() (int64, error) {
 	m.ctrl.T.Helper()
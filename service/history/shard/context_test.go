@@ -38,15 +38,20 @@ import (
 	"go.temporal.io/api/enums/v1"
 	"google.golang.org/protobuf/types/known/timestamppb"
 
+	enumsspb "go.temporal.io/server/api/enums/v1"
 	"go.temporal.io/server/api/historyservice/v1"
 	persistencespb "go.temporal.io/server/api/persistence/v1"
+	"go.temporal.io/server/common"
 	"go.temporal.io/server/common/backoff"
 	"go.temporal.io/server/common/clock"
 	"go.temporal.io/server/common/cluster"
 	"go.temporal.io/server/common/definition"
+	"go.temporal.io/server/common/future"
 	"go.temporal.io/server/common/namespace"
 	"go.temporal.io/server/common/persistence"
+	"go.temporal.io/server/common/persistence/shardmigration"
 	"go.temporal.io/server/common/primitives/timestamp"
+	"go.temporal.io/server/service/history/consts"
 	"go.temporal.io/server/service/history/tasks"
 	"go.temporal.io/server/service/history/tests"
 )
@@ -731,3 +736,85 @@ func (s *contextSuite) TestShardStopReasonCloseShard() {
 	s.False(s.mockShard.IsValid())
 	s.False(s.mockShard.stoppedForOwnershipLost())
 }
+
+func (s *contextSuite) TestAcquireShard_ShardMigrationDoesNotBlockAcquire() {
+	migration := &persistencespb.HistoryShardCountMigration{
+		SourceShardCount: 1,
+		TargetShardCount: 4,
+		State:            enumsspb.HISTORY_SHARD_COUNT_MIGRATION_STATE_CUTOVER,
+		CopiedShardIds:   []int32{s.shardID},
+	}
+	clusterMetadataManager := s.mockShard.Resource.ClusterMetadataMgr
+	clusterMetadataManager.EXPECT().GetCurrentClusterMetadata(gomock.Any()).Return(&persistence.GetClusterMetadataResponse{
+		ClusterMetadata: &persistencespb.ClusterMetadata{
+			HistoryShardCount:          migration.TargetShardCount,
+			HistoryShardCountMigration: migration,
+		},
+	}, nil).AnyTimes()
+	s.mockShard.shardMigrationGate = shardmigration.NewGate(
+		migration.TargetShardCount,
+		clusterMetadataManager,
+		s.mockExecutionManager,
+		s.mockShardManager,
+		s.mockShard.taskCategoryRegistry,
+		s.mockShard.GetLogger(),
+	)
+
+	// Moving a large shard takes long, which is simulated by finalization blocking until the shard is closed.
+	settleStarted := make(chan struct{})
+	settleStopped := make(chan struct{})
+	s.mockShardManager.EXPECT().GetOrCreateShard(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(ctx context.Context, _ *persistence.GetOrCreateShardRequest) (*persistence.GetOrCreateShardResponse, error) {
+			close(settleStarted)
+			<-ctx.Done()
+			close(settleStopped)
+			return nil, ctx.Err()
+		},
+	).Times(1)
+
+	engineFactory := NewMockEngineFactory(s.controller)
+	engineFactory.EXPECT().CreateEngine(gomock.Any()).Return(s.mockHistoryEngine).Times(1)
+	s.mockHistoryEngine.EXPECT().Start().Times(1)
+	s.mockHistoryEngine.EXPECT().NotifyNewTasks(gomock.Any()).AnyTimes()
+	s.mockShard.engineFactory = engineFactory
+	s.mockShard.engineFuture = future.NewFuture[Engine]()
+	s.mockShard.state = contextStateAcquiring
+	s.mockShard.acquireShardRetryPolicy = backoff.NewExponentialRetryPolicy(time.Nanosecond).
+		WithMaximumAttempts(5)
+	s.mockShardManager.EXPECT().UpdateShard(gomock.Any(), gomock.Any()).Return(nil).Times(1)
+
+	s.mockShard.acquireShard()
+	<-settleStarted
+
+	// The shard serves the executions which stay while the moved ones are fenced off.
+	s.Equal(contextStateAcquired, s.mockShard.state)
+	var stayingWorkflowID, movedWorkflowID string
+	for i := 0; stayingWorkflowID == "" || movedWorkflowID == ""; i++ {
+		workflowID := fmt.Sprintf("workflow-%d", i)
+		if common.WorkflowIDToHistoryShard(tests.NamespaceID.String(), workflowID, migration.TargetShardCount) == s.shardID {
+			stayingWorkflowID = workflowID
+		} else {
+			movedWorkflowID = workflowID
+		}
+	}
+	stayingRequest := &persistence.GetWorkflowExecutionRequest{
+		NamespaceID: tests.NamespaceID.String(),
+		WorkflowID:  stayingWorkflowID,
+		RunID:       tests.RunID,
+	}
+	stayingResponse := &persistence.GetWorkflowExecutionResponse{}
+	s.mockExecutionManager.EXPECT().GetWorkflowExecution(gomock.Any(), stayingRequest).Return(stayingResponse, nil).Times(1)
+	resp, err := s.mockShard.GetWorkflowExecution(context.Background(), stayingRequest)
+	s.NoError(err)
+	s.Equal(stayingResponse, resp)
+
+	_, err = s.mockShard.GetWorkflowExecution(context.Background(), &persistence.GetWorkflowExecutionRequest{
+		NamespaceID: tests.NamespaceID.String(),
+		WorkflowID:  movedWorkflowID,
+		RunID:       tests.RunID,
+	})
+	s.Equal(consts.ErrWorkflowExecutionMoving, err)
+
+	s.mockShard.lifecycleCancel()
+	<-settleStopped
+}
@@ -25,6 +25,9 @@
 package shard
 
 import (
+	"context"
+
+	clusterspb "go.temporal.io/server/api/cluster/v1"
 	"go.temporal.io/server/common"
 	"go.temporal.io/server/common/namespace"
 )
@@ -41,5 +44,15 @@ type (
 		ShardIDs() []int32
 		Start()
 		Stop()
+
+		// StartDrain makes the controller hand off all its shards to the other hosts in the
+		// background. It is a no-op if a drain was already started and didn't fail.
+		StartDrain() (*clusterspb.HistoryHostDrainInfo, error)
+		// WaitDrained blocks until the drain started by StartDrain is done.
+		WaitDrained(ctx context.Context) error
+		// Undrain stops the drain and makes the controller acquire shards again.
+		Undrain() (*clusterspb.HistoryHostDrainInfo, error)
+		// DrainInfo returns the progress of the drain, or nil if no drain was started.
+		DrainInfo() *clusterspb.HistoryHostDrainInfo
 	}
 )
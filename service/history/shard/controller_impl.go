@@ -26,6 +26,7 @@ package shard
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"math/rand"
 	"sync"
@@ -35,8 +36,12 @@ import (
 	"go.temporal.io/api/serviceerror"
 	"golang.org/x/sync/semaphore"
 	"golang.org/x/time/rate"
+	"google.golang.org/protobuf/types/known/timestamppb"
 
+	clusterspb "go.temporal.io/server/api/cluster/v1"
+	enumsspb "go.temporal.io/server/api/enums/v1"
 	"go.temporal.io/server/common"
+	"go.temporal.io/server/common/backoff"
 	"go.temporal.io/server/common/headers"
 	"go.temporal.io/server/common/log"
 	"go.temporal.io/server/common/log/tag"
@@ -50,11 +55,15 @@ import (
 
 const (
 	shardLingerMaxTimeLimit = 1 * time.Minute
+
+	drainAnnounceExpiration = 1 * time.Minute
 )
 
 var (
 	invalidShardIdLowerBound = serviceerror.NewInvalidArgument("shard Id cannot be equal or lower than zero")
 	invalidShardIdUpperBound = serviceerror.NewInvalidArgument("shard Id cannot be larger than max shard count")
+	errNoHostToDrainTo       = serviceerror.NewFailedPrecondition("no other history host to hand off the shards to")
+	errNotDraining           = serviceerror.NewFailedPrecondition("history host is not draining")
 )
 
 type (
@@ -67,10 +76,20 @@ type (
 			shards map[ControllableContext]struct{}
 		}
 
+		// draining is set once a drain starts, from then on the controller doesn't acquire shards anymore.
+		draining   atomic.Bool
+		drainState struct {
+			sync.Mutex
+			info   *clusterspb.HistoryHostDrainInfo
+			doneCh chan struct{}
+			cancel context.CancelFunc
+		}
+
 		config               *configs.Config
 		contextFactory       ContextFactory
 		contextTaggedLogger  log.Logger
 		hostInfoProvider     membership.HostInfoProvider
+		membershipMonitor    membership.Monitor
 		ownership            *ownership
 		status               int32
 		taggedMetricsHandler metrics.Handler
@@ -91,6 +110,7 @@ func ControllerProvider(
 	config *configs.Config,
 	logger log.Logger,
 	historyServiceResolver membership.ServiceResolver,
+	membershipMonitor membership.Monitor,
 	metricsHandler metrics.Handler,
 	hostInfoProvider membership.HostInfoProvider,
 	contextFactory ContextFactory,
@@ -113,6 +133,7 @@ func ControllerProvider(
 		contextTaggedLogger:     contextTaggedLogger,
 		historyShards:           make(map[int32]ControllableContext),
 		hostInfoProvider:        hostInfoProvider,
+		membershipMonitor:       membershipMonitor,
 		ownership:               ownership,
 		taggedMetricsHandler:    taggedMetricsHandler,
 		shardCountSubscriptions: map[*shardCountSubscription]struct{}{},
@@ -144,6 +165,12 @@ func (c *ControllerImpl) Stop() {
 		return
 	}
 
+	c.drainState.Lock()
+	if c.drainState.cancel != nil {
+		c.drainState.cancel()
+	}
+	c.drainState.Unlock()
+
 	c.ownership.stop()
 
 	c.doShutdown()
@@ -267,11 +294,20 @@ func (c *ControllerImpl) getOrCreateShardContext(shardID int32) (ControllableCon
 		_ = c.removeShardLocked(shardID, shard)
 	}
 
+	hostInfo := c.hostInfoProvider.HostInfo()
+	if c.draining.Load() {
+		// Shards are not acquired anymore while draining, point the caller to the host taking the shard over.
+		owner, err := c.ownership.successor(shardID)
+		if err != nil {
+			return nil, err
+		}
+		return nil, serviceerrors.NewShardOwnershipLost(owner.Identity(), hostInfo.GetAddress())
+	}
+
 	if err := c.ownership.verifyOwnership(shardID); err != nil {
 		return nil, err
 	}
 
-	hostInfo := c.hostInfoProvider.HostInfo()
 	if atomic.LoadInt32(&c.status) == common.DaemonStatusStopped {
 		return nil, fmt.Errorf("ControllerImpl for host '%v' shutting down", hostInfo.Identity())
 	}
@@ -402,6 +438,10 @@ func (c *ControllerImpl) acquireShards(ctx context.Context) {
 	ctx = headers.SetCallerInfo(ctx, headers.SystemBackgroundCallerInfo)
 
 	tryAcquire := func(shardID int32) {
+		if c.draining.Load() {
+			// The drain flushes and closes the shards itself once the host left the ring.
+			return
+		}
 		if err := c.ownership.verifyOwnership(shardID); err != nil {
 			if IsShardOwnershipLostError(err) {
 				// current host is not owner of shard, unload it if it is already loaded.
@@ -470,6 +510,228 @@ func (c *ControllerImpl) publishShardCountUpdate(shardCount int) {
 	}
 }
 
+// StartDrain hands off the shards of this host to the other history hosts ahead of a shutdown.
+// Unlike a plain shutdown, which leaves the other hosts to steal the shards once membership notices
+// this host is gone, the drain first announces that the host is leaving, so the new owners acquire
+// the shards right away, and then flushes and closes each shard. Shards keep serving requests until
+// they are closed or their new owner fences them off by acquiring them with a higher rangeID.
+func (c *ControllerImpl) StartDrain() (*clusterspb.HistoryHostDrainInfo, error) {
+	c.drainState.Lock()
+	defer c.drainState.Unlock()
+
+	if c.drainState.info != nil && c.drainState.info.State != enumsspb.HISTORY_HOST_DRAIN_STATE_FAILED {
+		return common.CloneProto(c.drainState.info), nil
+	}
+	if atomic.LoadInt32(&c.status) != common.DaemonStatusStarted {
+		return nil, serviceerror.NewUnavailable(fmt.Sprintf("ControllerImpl for host '%v' is not running", c.hostInfoProvider.HostInfo().Identity()))
+	}
+	if !c.membershipMonitor.SupportsDraining() {
+		// The other hosts would keep routing to this host, and nobody would take over the shards.
+		return nil, membership.ErrDrainingNotSupported
+	}
+	if c.ownership.historyServiceResolver.MemberCount() < 2 {
+		return nil, errNoHostToDrainTo
+	}
+
+	c.draining.Store(true)
+	ctx, cancel := context.WithCancel(headers.SetCallerInfo(context.Background(), headers.SystemBackgroundCallerInfo))
+	c.drainState.cancel = cancel
+	c.drainState.doneCh = make(chan struct{})
+	c.drainState.info = &clusterspb.HistoryHostDrainInfo{
+		State:     enumsspb.HISTORY_HOST_DRAIN_STATE_DRAINING,
+		StartTime: timestamppb.New(time.Now().UTC()),
+	}
+	go c.drain(ctx, c.drainState.doneCh)
+
+	return common.CloneProto(c.drainState.info), nil
+}
+
+// WaitDrained blocks until the drain started by StartDrain is done or aborted
+// because the controller stopped. It returns an error if the drain failed.
+func (c *ControllerImpl) WaitDrained(ctx context.Context) error {
+	c.drainState.Lock()
+	doneCh := c.drainState.doneCh
+	c.drainState.Unlock()
+	if doneCh == nil {
+		return errNotDraining
+	}
+
+	select {
+	case <-doneCh:
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+
+	if info := c.DrainInfo(); info != nil && info.State == enumsspb.HISTORY_HOST_DRAIN_STATE_FAILED {
+		return serviceerror.NewUnavailable(fmt.Sprintf("history host drain failed: %v", info.Failure))
+	}
+	return nil
+}
+
+// Undrain stops the drain started by StartDrain and puts this host back into the membership
+// ring, from then on the controller acquires shards again. Shards which were already closed by
+// the drain are acquired again once their new owner releases them. It returns the drain info
+// as of when the drain stopped.
+func (c *ControllerImpl) Undrain() (*clusterspb.HistoryHostDrainInfo, error) {
+	c.drainState.Lock()
+	if c.drainState.info == nil {
+		c.drainState.Unlock()
+		return nil, errNotDraining
+	}
+	cancel, doneCh := c.drainState.cancel, c.drainState.doneCh
+	c.drainState.Unlock()
+
+	cancel()
+	<-doneCh
+	if err := c.membershipMonitor.SetDraining(false); err != nil {
+		return nil, err
+	}
+
+	c.drainState.Lock()
+	defer c.drainState.Unlock()
+	info := c.drainState.info
+	if c.drainState.doneCh == doneCh {
+		// A concurrent Undrain may already have reset the drain, or a new drain may have started after the failed one.
+		c.draining.Store(false)
+		c.drainState.info = nil
+		c.drainState.doneCh = nil
+		c.drainState.cancel = nil
+	}
+	c.contextTaggedLogger.Info("History host undrained")
+	return common.CloneProto(info), nil
+}
+
+func (c *ControllerImpl) DrainInfo() *clusterspb.HistoryHostDrainInfo {
+	c.drainState.Lock()
+	defer c.drainState.Unlock()
+	if c.drainState.info == nil {
+		return nil
+	}
+	return common.CloneProto(c.drainState.info)
+}
+
+func (c *ControllerImpl) drain(ctx context.Context, doneCh chan struct{}) {
+	defer close(doneCh)
+
+	startTime := time.Now()
+	c.RLock()
+	shards := make([]ControllableContext, 0, len(c.historyShards))
+	for _, shard := range c.historyShards {
+		shards = append(shards, shard)
+	}
+	c.RUnlock()
+	c.contextTaggedLogger.Info("Draining history host", numShardsTag(len(shards)))
+
+	newOwners := make(map[int32]string, len(shards))
+	for _, shard := range shards {
+		owner, err := c.ownership.successor(shard.GetShardID())
+		if err != nil {
+			c.contextTaggedLogger.Warn("Unable to find the host taking over shard", tag.ShardID(shard.GetShardID()), tag.Error(err))
+			continue
+		}
+		newOwners[shard.GetShardID()] = owner.Identity()
+	}
+	c.updateDrainInfo(func(info *clusterspb.HistoryHostDrainInfo) {
+		info.ShardNewOwners = newOwners
+	})
+
+	// Ack levels are flushed while this host still owns every shard. Once the drain is announced
+	// the new owners acquire the shards and their rangeID fences off this host's writes.
+	if err := c.forEachShard(ctx, shards, c.flushShardInfo); err != nil {
+		c.contextTaggedLogger.Info("Drain aborted", tag.Error(err))
+		return
+	}
+
+	// Leaving the ring notifies the other hosts of a membership change, which makes them acquire
+	// the shards of this host immediately instead of on their next acquire interval. The shards
+	// are still open and keep serving requests until they are closed below.
+	announce := func(context.Context) error {
+		return c.membershipMonitor.SetDraining(true)
+	}
+	policy := backoff.NewExponentialRetryPolicy(time.Second).WithExpirationInterval(drainAnnounceExpiration)
+	isRetryable := func(err error) bool {
+		var failedPrecondition *serviceerror.FailedPrecondition
+		return !errors.As(err, &failedPrecondition)
+	}
+	if err := backoff.ThrottleRetryContext(ctx, announce, policy, isRetryable); err != nil {
+		if ctx.Err() != nil {
+			c.contextTaggedLogger.Info("Drain aborted", tag.Error(err))
+			return
+		}
+		c.contextTaggedLogger.Error("Unable to announce drain through membership", tag.Error(err), tag.OperationFailed)
+		c.failDrain(err)
+		return
+	}
+
+	if err := c.forEachShard(ctx, shards, c.closeDrainedShard); err != nil {
+		c.contextTaggedLogger.Info("Drain aborted", tag.Error(err))
+		return
+	}
+
+	c.updateDrainInfo(func(info *clusterspb.HistoryHostDrainInfo) {
+		info.State = enumsspb.HISTORY_HOST_DRAIN_STATE_DRAINED
+		info.CompleteTime = timestamppb.New(time.Now().UTC())
+	})
+	c.taggedMetricsHandler.Timer(metrics.HostDrainLatency.Name()).Record(time.Since(startTime))
+	c.contextTaggedLogger.Info("History host drained", numShardsTag(len(shards)))
+}
+
+// failDrain puts this host back into the membership ring, in case the announcement got through
+// partially, and makes the controller acquire shards again.
+func (c *ControllerImpl) failDrain(cause error) {
+	if err := c.membershipMonitor.SetDraining(false); err != nil {
+		c.contextTaggedLogger.Error("Unable to announce drain failure through membership", tag.Error(err), tag.OperationFailed)
+	}
+
+	c.drainState.Lock()
+	defer c.drainState.Unlock()
+	c.draining.Store(false)
+	c.drainState.info.State = enumsspb.HISTORY_HOST_DRAIN_STATE_FAILED
+	c.drainState.info.Failure = cause.Error()
+	c.drainState.info.CompleteTime = timestamppb.New(time.Now().UTC())
+}
+
+// forEachShard calls fn for every shard, at most AcquireShardConcurrency at a time,
+// and waits for all calls to return.
+func (c *ControllerImpl) forEachShard(ctx context.Context, shards []ControllableContext, fn func(ControllableContext)) error {
+	concurrency := int64(max(c.config.AcquireShardConcurrency(), 1))
+	sem := semaphore.NewWeighted(concurrency)
+	for _, shard := range shards {
+		if err := sem.Acquire(ctx, 1); err != nil {
+			break
+		}
+		go func(shard ControllableContext) {
+			defer sem.Release(1)
+			fn(shard)
+		}(shard)
+	}
+	return sem.Acquire(ctx, concurrency)
+}
+
+// flushShardInfo persists the latest queue ack levels of the shard, so the new owner doesn't
+// reprocess the tasks this host already completed.
+func (c *ControllerImpl) flushShardInfo(shard ControllableContext) {
+	if err := shard.FlushShardInfo(); err != nil {
+		c.taggedMetricsHandler.Counter(metrics.ShardDrainFlushFailures.Name()).Record(1)
+		c.contextTaggedLogger.Warn("Unable to flush shard info before handing off shard", tag.ShardID(shard.GetShardID()), tag.Error(err))
+	}
+}
+
+func (c *ControllerImpl) closeDrainedShard(shard ControllableContext) {
+	shardID := shard.GetShardID()
+	c.shardRemoveAndStop(shard)
+
+	c.updateDrainInfo(func(info *clusterspb.HistoryHostDrainInfo) {
+		info.DrainedShardIds = append(info.DrainedShardIds, shardID)
+	})
+}
+
+func (c *ControllerImpl) updateDrainInfo(fn func(info *clusterspb.HistoryHostDrainInfo)) {
+	c.drainState.Lock()
+	defer c.drainState.Unlock()
+	fn(c.drainState.info)
+}
+
 func (c *ControllerImpl) doShutdown() {
 	c.contextTaggedLogger.Info("", tag.LifeCycleStopping)
 	c.Lock()
@@ -29,9 +29,11 @@
 package shard
 
 import (
+	context "context"
 	reflect "reflect"
 
 	gomock "github.com/golang/mock/gomock"
+	v1 "go.temporal.io/server/api/cluster/v1"
 	common "go.temporal.io/server/common"
 	namespace "go.temporal.io/server/common/namespace"
 )
@@ -71,6 +73,20 @@ func (mr *MockControllerMockRecorder) CloseShardByID(shardID interface{}) *gomoc
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CloseShardByID", reflect.TypeOf((*MockController)(nil).CloseShardByID), shardID)
 }
 
+// DrainInfo mocks base method.
+func (m *MockController) DrainInfo() *v1.HistoryHostDrainInfo {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DrainInfo")
+	ret0, _ := ret[0].(*v1.HistoryHostDrainInfo)
+	return ret0
+}
+
+// DrainInfo indicates an expected call of DrainInfo.
+func (mr *MockControllerMockRecorder) DrainInfo() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DrainInfo", reflect.TypeOf((*MockController)(nil).DrainInfo))
+}
+
 // GetPingChecks mocks base method.
 func (m *MockController) GetPingChecks() []common.PingCheck {
 	m.ctrl.T.Helper()
@@ -141,6 +157,21 @@ func (mr *MockControllerMockRecorder) Start() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Start", reflect.TypeOf((*MockController)(nil).Start))
 }
 
+// StartDrain mocks base method.
+func (m *MockController) StartDrain() (*v1.HistoryHostDrainInfo, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "StartDrain")
+	ret0, _ := ret[0].(*v1.HistoryHostDrainInfo)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// StartDrain indicates an expected call of StartDrain.
+func (mr *MockControllerMockRecorder) StartDrain() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "StartDrain", reflect.TypeOf((*MockController)(nil).StartDrain))
+}
+
 // Stop mocks base method.
 func (m *MockController) Stop() {
 	m.ctrl.T.Helper()
@@ -152,3 +183,32 @@ func (mr *MockControllerMockRecorder) Stop() *gomock.Call {
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Stop", reflect.TypeOf((*MockController)(nil).Stop))
 }
+
+// Undrain mocks base method.
+func (m *MockController) Undrain() (*v1.HistoryHostDrainInfo, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Undrain")
+	ret0, _ := ret[0].(*v1.HistoryHostDrainInfo)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Undrain indicates an expected call of Undrain.
+func (mr *MockControllerMockRecorder) Undrain() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Undrain", reflect.TypeOf((*MockController)(nil).Undrain))
+}
+
+// WaitDrained mocks base method.
+func (m *MockController) WaitDrained(ctx context.Context) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "WaitDrained", ctx)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// WaitDrained indicates an expected call of WaitDrained.
+func (mr *MockControllerMockRecorder) WaitDrained(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WaitDrained", reflect.TypeOf((*MockController)(nil).WaitDrained), ctx)
+}
@@ -56,6 +56,7 @@ import (
 	"go.temporal.io/server/common/primitives"
 	"go.temporal.io/server/common/primitives/timestamp"
 	"go.temporal.io/server/common/resourcetest"
+	serviceerrors "go.temporal.io/server/common/serviceerror"
 	"go.temporal.io/server/internal/goro"
 	"go.temporal.io/server/service/history/configs"
 	"go.temporal.io/server/service/history/tasks"
@@ -118,6 +119,7 @@ func NewTestController(
 		config,
 		resource.GetLogger(),
 		resource.GetHistoryServiceResolver(),
+		resource.GetMembershipMonitor(),
 		metricsTestHandler,
 		resource.GetHostInfoProvider(),
 		contextFactory,
@@ -416,6 +418,80 @@ func (s *controllerSuite) TestHistoryEngineClosed() {
 	s.shardController.Stop()
 }
 
+func (s *controllerSuite) TestDrain_ShardsServeRequestsUntilClosed() {
+	numShards := int32(2)
+	s.config.NumberOfShards = numShards
+	s.shardController = NewTestController(
+		s.mockEngineFactory,
+		s.config,
+		s.mockResource,
+		s.mockHostInfoProvider,
+		s.metricsTestHandler,
+	)
+	for shardID := int32(1); shardID <= numShards; shardID++ {
+		mockEngine := NewMockEngine(s.controller)
+		mockEngine.EXPECT().Stop().Return()
+		s.setupMocksForAcquireShard(shardID, mockEngine, 5, 6, true)
+	}
+	// The shard info is flushed while the host still owns the shards, before the drain is announced.
+	var flushed atomic.Int32
+	s.mockShardManager.EXPECT().UpdateShard(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(context.Context, *persistence.UpdateShardRequest) error {
+			flushed.Add(1)
+			return nil
+		},
+	).AnyTimes()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	s.mockServiceResolver.EXPECT().AddListener(shardControllerMembershipUpdateListenerName, gomock.Any()).Return(nil).AnyTimes()
+	s.mockServiceResolver.EXPECT().RemoveListener(shardControllerMembershipUpdateListenerName).Return(nil).AnyTimes()
+	s.shardController.Start()
+	defer s.shardController.Stop()
+	s.shardController.acquireShards(context.Background())
+	for shardID := int32(1); shardID <= numShards; shardID++ {
+		shard, err := s.shardController.GetShardByID(shardID)
+		s.NoError(err)
+		_, err = shard.GetEngine(ctx)
+		s.NoError(err)
+	}
+
+	newOwner := membership.NewHostInfoFromAddress("new-owner")
+	s.mockResource.MembershipMonitor.EXPECT().SupportsDraining().Return(true)
+	s.mockServiceResolver.EXPECT().MemberCount().Return(2)
+	s.mockServiceResolver.EXPECT().LookupN(gomock.Any(), 2).Return([]membership.HostInfo{s.hostInfo, newOwner}).AnyTimes()
+	// The host leaves the ring before its shards are closed, and they keep serving requests meanwhile.
+	s.mockResource.MembershipMonitor.EXPECT().SetDraining(true).DoAndReturn(func(bool) error {
+		s.GreaterOrEqual(flushed.Load(), numShards)
+		s.Len(s.shardController.ShardIDs(), int(numShards))
+		for shardID := int32(1); shardID <= numShards; shardID++ {
+			shard, err := s.shardController.GetShardByID(shardID)
+			s.NoError(err)
+			engine, err := shard.GetEngine(ctx)
+			s.NoError(err)
+			s.NotNil(engine)
+		}
+		return nil
+	})
+
+	flushed.Store(0)
+	_, err := s.shardController.StartDrain()
+	s.NoError(err)
+	s.NoError(s.shardController.WaitDrained(ctx))
+
+	drainInfo := s.shardController.DrainInfo()
+	s.Equal(enums.HISTORY_HOST_DRAIN_STATE_DRAINED, drainInfo.GetState())
+	s.ElementsMatch([]int32{1, 2}, drainInfo.GetDrainedShardIds())
+	s.Empty(s.shardController.ShardIDs())
+
+	// Requests for closed shards are redirected to the new owner.
+	_, err = s.shardController.GetShardByID(1)
+	var ownershipLost *serviceerrors.ShardOwnershipLost
+	s.ErrorAs(err, &ownershipLost)
+	s.Equal(newOwner.Identity(), ownershipLost.OwnerHost)
+}
+
 func (s *controllerSuite) TestShardControllerClosed() {
 	numShards := int32(4)
 	s.config.NumberOfShards = numShards
@@ -33,8 +33,11 @@ import (
 	"go.temporal.io/server/common"
 	"go.temporal.io/server/common/log"
 	"go.temporal.io/server/common/log/tag"
+	"go.temporal.io/server/common/persistence"
+	"go.temporal.io/server/common/persistence/shardmigration"
 	"go.temporal.io/server/common/primitives"
 	"go.temporal.io/server/service/history/configs"
+	"go.temporal.io/server/service/history/tasks"
 )
 
 var Module = fx.Options(
@@ -42,6 +45,7 @@ var Module = fx.Options(
 		ControllerProvider,
 		func(impl *ControllerImpl) Controller { return impl },
 		ContextFactoryProvider,
+		ShardMigrationGateProvider,
 		fx.Annotate(
 			func(p Controller) common.Pingable { return p },
 			fx.ResultTags(`group:"deadlockDetectorRoots"`),
@@ -50,6 +54,24 @@ var Module = fx.Options(
 	ownershipBasedQuotaScalerModule,
 )
 
+func ShardMigrationGateProvider(
+	cfg *configs.Config,
+	clusterMetadataManager persistence.ClusterMetadataManager,
+	executionManager persistence.ExecutionManager,
+	shardManager persistence.ShardManager,
+	taskCategoryRegistry tasks.TaskCategoryRegistry,
+	logger log.Logger,
+) shardmigration.Gate {
+	return shardmigration.NewGate(
+		cfg.NumberOfShards,
+		clusterMetadataManager,
+		executionManager,
+		shardManager,
+		taskCategoryRegistry,
+		logger,
+	)
+}
+
 var ownershipBasedQuotaScalerModule = fx.Options(
 	fx.Provide(func(
 		impl *ControllerImpl,
@@ -168,3 +168,16 @@ func (o *ownership) verifyOwnership(shardID int32) error {
 
 	return nil
 }
+
+// successor returns the host that owns the shard once this host leaves the ring.
+// Consistent hashing moves the keys of a departing host to the next host on the ring,
+// which is the first host other than this one returned by LookupN.
+func (o *ownership) successor(shardID int32) (membership.HostInfo, error) {
+	hostIdentity := o.hostInfoProvider.HostInfo().Identity()
+	for _, host := range o.historyServiceResolver.LookupN(convert.Int32ToString(shardID), 2) {
+		if host.Identity() != hostIdentity {
+			return host, nil
+		}
+	}
+	return nil, membership.ErrInsufficientHosts
+}
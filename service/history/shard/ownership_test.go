@@ -25,6 +25,7 @@
 package shard
 
 import (
+	"context"
 	"sync"
 	"testing"
 	"time"
@@ -33,6 +34,7 @@ import (
 	"github.com/stretchr/testify/require"
 	"github.com/stretchr/testify/suite"
 
+	enumsspb "go.temporal.io/server/api/enums/v1"
 	"go.temporal.io/server/common/convert"
 	"go.temporal.io/server/common/membership"
 	"go.temporal.io/server/common/primitives"
@@ -76,6 +78,7 @@ func (s *ownershipSuite) newController(contextFactory ContextFactory) *Controlle
 		s.config,
 		s.resource.GetLogger(),
 		s.resource.GetHistoryServiceResolver(),
+		s.resource.GetMembershipMonitor(),
 		s.resource.GetMetricsHandler(),
 		s.resource.GetHostInfoProvider(),
 		contextFactory,
@@ -226,3 +229,230 @@ func (s *ownershipSuite) TestAttemptAcquireUnowned() {
 
 	shardController.Stop()
 }
+
+func (s *ownershipSuite) TestDrain() {
+	s.config.NumberOfShards = 2
+	newOwners := map[int32]string{1: "otherHost1", 2: "otherHost2"}
+
+	cf := NewMockContextFactory(s.controller)
+	for shardID := range newOwners {
+		shard := NewMockControllableContext(s.controller)
+		shard.EXPECT().GetShardID().Return(shardID).AnyTimes()
+		shard.EXPECT().GetEngine(gomock.Any()).Return(nil, nil).AnyTimes()
+		shard.EXPECT().AssertOwnership(gomock.Any()).Return(nil).AnyTimes()
+		shard.EXPECT().IsValid().Return(true).AnyTimes()
+		gomock.InOrder(
+			shard.EXPECT().FlushShardInfo().Return(nil).Times(1),
+			shard.EXPECT().FinishStop().Times(1),
+		)
+		cf.EXPECT().CreateContext(shardID, gomock.Any()).Return(shard, nil).Times(1)
+
+		s.resource.HistoryServiceResolver.EXPECT().
+			Lookup(convert.Int32ToString(shardID)).
+			Return(s.resource.GetHostInfo(), nil).AnyTimes()
+		s.resource.HistoryServiceResolver.EXPECT().
+			LookupN(convert.Int32ToString(shardID), 2).
+			Return([]membership.HostInfo{s.resource.GetHostInfo(), membership.NewHostInfoFromAddress(newOwners[shardID])}).
+			AnyTimes()
+	}
+	s.resource.HistoryServiceResolver.EXPECT().MemberCount().Return(3).Times(1)
+	s.resource.HistoryServiceResolver.EXPECT().
+		AddListener(shardControllerMembershipUpdateListenerName, gomock.Any()).
+		Return(nil).Times(1)
+	s.resource.MembershipMonitor.EXPECT().SupportsDraining().Return(true).Times(1)
+	s.resource.MembershipMonitor.EXPECT().SetDraining(true).Return(nil).Times(1)
+
+	shardController := s.newController(cf)
+	shardController.Start()
+	s.Nil(shardController.DrainInfo())
+	s.ErrorIs(shardController.WaitDrained(context.Background()), errNotDraining)
+
+	for shardID := range newOwners {
+		_, err := shardController.GetShardByID(shardID)
+		s.NoError(err)
+	}
+
+	info, err := shardController.StartDrain()
+	s.NoError(err)
+	s.Equal(enumsspb.HISTORY_HOST_DRAIN_STATE_DRAINING, info.GetState())
+	s.NoError(shardController.WaitDrained(context.Background()))
+
+	info = shardController.DrainInfo()
+	s.Equal(enumsspb.HISTORY_HOST_DRAIN_STATE_DRAINED, info.GetState())
+	s.NotNil(info.GetCompleteTime())
+	s.Equal(newOwners, info.GetShardNewOwners())
+	s.ElementsMatch([]int32{1, 2}, info.GetDrainedShardIds())
+	s.Empty(shardController.ShardIDs())
+
+	// Shards are not acquired again, callers are sent to the new owner.
+	_, err = shardController.GetShardByID(1)
+	var solErr *serviceerrors.ShardOwnershipLost
+	s.ErrorAs(err, &solErr)
+	s.Equal(newOwners[1], solErr.OwnerHost)
+
+	// Starting the drain again returns its progress.
+	info, err = shardController.StartDrain()
+	s.NoError(err)
+	s.Equal(enumsspb.HISTORY_HOST_DRAIN_STATE_DRAINED, info.GetState())
+
+	s.resource.HistoryServiceResolver.EXPECT().
+		RemoveListener(shardControllerMembershipUpdateListenerName).
+		Return(nil).Times(1)
+	shardController.Stop()
+}
+
+func (s *ownershipSuite) TestDrainWithoutOtherHost() {
+	s.resource.MembershipMonitor.EXPECT().SupportsDraining().Return(true).Times(1)
+	s.resource.HistoryServiceResolver.EXPECT().MemberCount().Return(1).Times(1)
+	s.resource.HistoryServiceResolver.EXPECT().
+		AddListener(shardControllerMembershipUpdateListenerName, gomock.Any()).
+		Return(nil).Times(1)
+
+	shardController := s.newController(NewMockContextFactory(s.controller))
+	shardController.Start()
+
+	_, err := shardController.StartDrain()
+	s.ErrorIs(err, errNoHostToDrainTo)
+	s.Nil(shardController.DrainInfo())
+
+	s.resource.HistoryServiceResolver.EXPECT().
+		RemoveListener(shardControllerMembershipUpdateListenerName).
+		Return(nil).Times(1)
+	shardController.Stop()
+}
+
+func (s *ownershipSuite) TestDrainNotSupported() {
+	s.resource.MembershipMonitor.EXPECT().SupportsDraining().Return(false).Times(1)
+	s.resource.HistoryServiceResolver.EXPECT().
+		AddListener(shardControllerMembershipUpdateListenerName, gomock.Any()).
+		Return(nil).Times(1)
+
+	shardController := s.newController(NewMockContextFactory(s.controller))
+	shardController.Start()
+
+	_, err := shardController.StartDrain()
+	s.ErrorIs(err, membership.ErrDrainingNotSupported)
+	s.Nil(shardController.DrainInfo())
+
+	s.resource.HistoryServiceResolver.EXPECT().
+		RemoveListener(shardControllerMembershipUpdateListenerName).
+		Return(nil).Times(1)
+	shardController.Stop()
+}
+
+func (s *ownershipSuite) TestDrainFailed() {
+	s.config.NumberOfShards = 2
+	cf := NewMockContextFactory(s.controller)
+	for shardID := int32(1); shardID <= 2; shardID++ {
+		shard := NewMockControllableContext(s.controller)
+		shard.EXPECT().GetShardID().Return(shardID).AnyTimes()
+		shard.EXPECT().GetEngine(gomock.Any()).Return(nil, nil).AnyTimes()
+		shard.EXPECT().AssertOwnership(gomock.Any()).Return(nil).AnyTimes()
+		shard.EXPECT().IsValid().Return(true).AnyTimes()
+		shard.EXPECT().FlushShardInfo().Return(nil).AnyTimes()
+		shard.EXPECT().FinishStop().AnyTimes()
+		cf.EXPECT().CreateContext(shardID, gomock.Any()).Return(shard, nil).Times(1)
+
+		s.resource.HistoryServiceResolver.EXPECT().
+			Lookup(convert.Int32ToString(shardID)).
+			Return(s.resource.GetHostInfo(), nil).AnyTimes()
+		s.resource.HistoryServiceResolver.EXPECT().
+			LookupN(convert.Int32ToString(shardID), 2).
+			Return([]membership.HostInfo{s.resource.GetHostInfo(), membership.NewHostInfoFromAddress("otherHost")}).
+			AnyTimes()
+	}
+	s.resource.HistoryServiceResolver.EXPECT().MemberCount().Return(2).Times(1)
+	s.resource.HistoryServiceResolver.EXPECT().
+		AddListener(shardControllerMembershipUpdateListenerName, gomock.Any()).
+		Return(nil).Times(1)
+	s.resource.MembershipMonitor.EXPECT().SupportsDraining().Return(true).Times(1)
+	gomock.InOrder(
+		s.resource.MembershipMonitor.EXPECT().SetDraining(true).Return(membership.ErrDrainingNotSupported).Times(1),
+		s.resource.MembershipMonitor.EXPECT().SetDraining(false).Return(nil).Times(1),
+	)
+
+	shardController := s.newController(cf)
+	shardController.Start()
+	_, err := shardController.GetShardByID(1)
+	s.NoError(err)
+
+	_, err = shardController.StartDrain()
+	s.NoError(err)
+	s.Error(shardController.WaitDrained(context.Background()))
+
+	info := shardController.DrainInfo()
+	s.Equal(enumsspb.HISTORY_HOST_DRAIN_STATE_FAILED, info.GetState())
+	s.NotEmpty(info.GetFailure())
+	s.NotNil(info.GetCompleteTime())
+	s.Empty(info.GetDrainedShardIds())
+
+	// The shards of this host are served and acquired again.
+	s.ElementsMatch([]int32{1}, shardController.ShardIDs())
+	_, err = shardController.GetShardByID(2)
+	s.NoError(err)
+
+	s.resource.HistoryServiceResolver.EXPECT().
+		RemoveListener(shardControllerMembershipUpdateListenerName).
+		Return(nil).Times(1)
+	shardController.Stop()
+}
+
+func (s *ownershipSuite) TestUndrain() {
+	s.config.NumberOfShards = 1
+	shardID := int32(1)
+
+	cf := NewMockContextFactory(s.controller)
+	for i := 0; i < 2; i++ {
+		shard := NewMockControllableContext(s.controller)
+		shard.EXPECT().GetShardID().Return(shardID).AnyTimes()
+		shard.EXPECT().GetEngine(gomock.Any()).Return(nil, nil).AnyTimes()
+		shard.EXPECT().AssertOwnership(gomock.Any()).Return(nil).AnyTimes()
+		shard.EXPECT().IsValid().Return(true).AnyTimes()
+		shard.EXPECT().FlushShardInfo().Return(nil).AnyTimes()
+		shard.EXPECT().FinishStop().AnyTimes()
+		cf.EXPECT().CreateContext(shardID, gomock.Any()).Return(shard, nil).Times(1)
+	}
+	s.resource.HistoryServiceResolver.EXPECT().
+		Lookup(convert.Int32ToString(shardID)).
+		Return(s.resource.GetHostInfo(), nil).AnyTimes()
+	s.resource.HistoryServiceResolver.EXPECT().
+		LookupN(convert.Int32ToString(shardID), 2).
+		Return([]membership.HostInfo{s.resource.GetHostInfo(), membership.NewHostInfoFromAddress("otherHost")}).
+		AnyTimes()
+	s.resource.HistoryServiceResolver.EXPECT().MemberCount().Return(2).Times(1)
+	s.resource.HistoryServiceResolver.EXPECT().
+		AddListener(shardControllerMembershipUpdateListenerName, gomock.Any()).
+		Return(nil).Times(1)
+	s.resource.MembershipMonitor.EXPECT().SupportsDraining().Return(true).Times(1)
+	gomock.InOrder(
+		s.resource.MembershipMonitor.EXPECT().SetDraining(true).Return(nil).Times(1),
+		s.resource.MembershipMonitor.EXPECT().SetDraining(false).Return(nil).Times(1),
+	)
+
+	shardController := s.newController(cf)
+	shardController.Start()
+	_, err := shardController.Undrain()
+	s.ErrorIs(err, errNotDraining)
+
+	_, err = shardController.GetShardByID(shardID)
+	s.NoError(err)
+	_, err = shardController.StartDrain()
+	s.NoError(err)
+	s.NoError(shardController.WaitDrained(context.Background()))
+	s.Empty(shardController.ShardIDs())
+
+	info, err := shardController.Undrain()
+	s.NoError(err)
+	s.Equal(enumsspb.HISTORY_HOST_DRAIN_STATE_DRAINED, info.GetState())
+	s.Nil(shardController.DrainInfo())
+
+	// The shard closed by the drain is acquired again.
+	_, err = shardController.GetShardByID(shardID)
+	s.NoError(err)
+	s.Equal([]int32{shardID}, shardController.ShardIDs())
+
+	s.resource.HistoryServiceResolver.EXPECT().
+		RemoveListener(shardControllerMembershipUpdateListenerName).
+		Return(nil).Times(1)
+	shardController.Stop()
+}
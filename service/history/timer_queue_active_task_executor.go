@@ -487,6 +487,12 @@ func (t *timerQueueActiveTaskExecutor) executeActivityRetryTimerTask(
 	}
 	scheduleToStartTimeout := timestamp.DurationValue(activityInfo.ScheduleToStartTimeout)
 	directive := worker_versioning.MakeDirectiveForActivityTask(mutableState.GetWorkerVersionStamp(), activityInfo.UseCompatibleVersion)
+	fairnessKey, err := workflow.GetFairnessKey(ctx, t.shardContext, mutableState)
+	if err != nil {
+		return err
+	}
+	priority := workflow.GetActivityTaskPriority(mutableState, activityInfo)
+	activityTypeName := activityInfo.GetActivityType().GetName()
 
 	// NOTE: do not access anything related mutable state after this lock release
 	release(nil) // release earlier as we don't need the lock anymore
@@ -502,6 +508,9 @@ func (t *timerQueueActiveTaskExecutor) executeActivityRetryTimerTask(
 		ScheduleToStartTimeout: durationpb.New(scheduleToStartTimeout),
 		Clock:                  vclock.NewVectorClock(t.shardContext.GetClusterMetadata().GetClusterID(), t.shardContext.GetShardID(), task.TaskID),
 		VersionDirective:       directive,
+		FairnessKey:            fairnessKey,
+		Priority:               priority,
+		ActivityTypeName:       activityTypeName,
 	})
 
 	return retError
@@ -77,6 +77,7 @@ func NewTimerQueueFactory(
 					WorkerCount:             params.Config.TimerProcessorSchedulerWorkerCount,
 					ActiveNamespaceWeights:  params.Config.TimerProcessorSchedulerActiveRoundRobinWeights,
 					StandbyNamespaceWeights: params.Config.TimerProcessorSchedulerStandbyRoundRobinWeights,
+					SubscribeWeights:        params.Config.SubscribeTimerProcessorSchedulerRoundRobinWeights,
 				},
 				params.NamespaceRegistry,
 				params.Logger,
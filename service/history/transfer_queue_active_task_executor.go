@@ -214,12 +214,18 @@ func (t *transferQueueActiveTaskExecutor) processActivityTask(
 
 	timeout := timestamp.DurationValue(ai.ScheduleToStartTimeout)
 	directive := worker_versioning.MakeDirectiveForActivityTask(mutableState.GetWorkerVersionStamp(), ai.UseCompatibleVersion)
+	fairnessKey, err := workflow.GetFairnessKey(ctx, t.shardContext, mutableState)
+	if err != nil {
+		return err
+	}
+	priority := workflow.GetActivityTaskPriority(mutableState, ai)
+	activityTypeName := ai.GetActivityType().GetName()
 
 	// NOTE: do not access anything related mutable state after this lock release
 	// release the context lock since we no longer need mutable state and
 	// the rest of logic is making RPC call, which takes time.
 	release(nil)
-	return t.pushActivity(ctx, task, timeout, directive)
+	return t.pushActivity(ctx, task, timeout, directive, fairnessKey, priority, activityTypeName)
 }
 
 func (t *transferQueueActiveTaskExecutor) processWorkflowTask(
@@ -264,13 +270,19 @@ func (t *transferQueueActiveTaskExecutor) processWorkflowTask(
 		mutableState.GetWorkerVersionStamp(),
 		mutableState.GetLastWorkflowTaskStartedEventID(),
 	)
+	fairnessKey, err := workflow.GetFairnessKey(ctx, t.shardContext, mutableState)
+	if err != nil {
+		return err
+	}
+	priority := workflow.GetWorkflowTaskPriority(mutableState)
+	workflowTypeName := mutableState.GetExecutionInfo().GetWorkflowTypeName()
 
 	// NOTE: Do not access mutableState after this lock is released.
 	// It is important to release the workflow lock here, because pushWorkflowTask will call matching,
 	// which will call history back (with RecordWorkflowTaskStarted), and it will try to get workflow lock again.
 	release(nil)
 
-	err = t.pushWorkflowTask(ctx, transferTask, taskQueue, scheduleToStartTimeout.AsDuration(), directive)
+	err = t.pushWorkflowTask(ctx, transferTask, taskQueue, scheduleToStartTimeout.AsDuration(), directive, fairnessKey, priority, workflowTypeName)
 
 	if _, ok := err.(*serviceerrors.StickyWorkerUnavailable); ok {
 		// sticky worker is unavailable, switch to original normal task queue
@@ -285,7 +297,7 @@ func (t *transferQueueActiveTaskExecutor) processWorkflowTask(
 		// There is no need to reset sticky, because if this task is picked by new worker, the new worker will reset
 		// the sticky queue to a new one. However, if worker is completely down, that schedule_to_start timeout task
 		// will re-create a new non-sticky task and reset sticky.
-		err = t.pushWorkflowTask(ctx, transferTask, taskQueue, scheduleToStartTimeout.AsDuration(), directive)
+		err = t.pushWorkflowTask(ctx, transferTask, taskQueue, scheduleToStartTimeout.AsDuration(), directive, fairnessKey, priority, workflowTypeName)
 	}
 	return err
 }
@@ -79,6 +79,7 @@ func NewTransferQueueFactory(
 					WorkerCount:             params.Config.TransferProcessorSchedulerWorkerCount,
 					ActiveNamespaceWeights:  params.Config.TransferProcessorSchedulerActiveRoundRobinWeights,
 					StandbyNamespaceWeights: params.Config.TransferProcessorSchedulerStandbyRoundRobinWeights,
+					SubscribeWeights:        params.Config.SubscribeTransferProcessorSchedulerRoundRobinWeights,
 				},
 				params.NamespaceRegistry,
 				params.Logger,
@@ -146,7 +146,7 @@ func (t *transferQueueStandbyTaskExecutor) processActivityTask(
 	transferTask *tasks.ActivityTask,
 ) error {
 	processTaskIfClosed := false
-	actionFn := func(_ context.Context, wfContext workflow.Context, mutableState workflow.MutableState) (interface{}, error) {
+	actionFn := func(ctx context.Context, wfContext workflow.Context, mutableState workflow.MutableState) (interface{}, error) {
 		activityInfo, ok := mutableState.GetActivityInfo(transferTask.ScheduledEventID)
 		if !ok {
 			return nil, nil
@@ -158,7 +158,11 @@ func (t *transferQueueStandbyTaskExecutor) processActivityTask(
 		}
 
 		if activityInfo.StartedEventId == common.EmptyEventID {
-			return newActivityTaskPostActionInfo(mutableState, activityInfo.ScheduleToStartTimeout.AsDuration(), activityInfo.UseCompatibleVersion)
+			fairnessKey, err := workflow.GetFairnessKey(ctx, t.shardContext, mutableState)
+			if err != nil {
+				return nil, err
+			}
+			return newActivityTaskPostActionInfo(mutableState, activityInfo.ScheduleToStartTimeout.AsDuration(), activityInfo.UseCompatibleVersion, fairnessKey, workflow.GetActivityTaskPriority(mutableState, activityInfo), activityInfo.GetActivityType().GetName())
 		}
 
 		return nil, nil
@@ -184,7 +188,7 @@ func (t *transferQueueStandbyTaskExecutor) processWorkflowTask(
 	ctx context.Context,
 	transferTask *tasks.WorkflowTask,
 ) error {
-	actionFn := func(_ context.Context, wfContext workflow.Context, mutableState workflow.MutableState) (interface{}, error) {
+	actionFn := func(ctx context.Context, wfContext workflow.Context, mutableState workflow.MutableState) (interface{}, error) {
 		wtInfo := mutableState.GetWorkflowTaskByID(transferTask.ScheduledEventID)
 		if wtInfo == nil {
 			return nil, nil
@@ -207,10 +211,16 @@ func (t *transferQueueStandbyTaskExecutor) processWorkflowTask(
 		}
 
 		if wtInfo.StartedEventID == common.EmptyEventID {
+			fairnessKey, err := workflow.GetFairnessKey(ctx, t.shardContext, mutableState)
+			if err != nil {
+				return nil, err
+			}
 			return newWorkflowTaskPostActionInfo(
 				mutableState,
 				scheduleToStartTimeout.AsDuration(),
 				taskQueue,
+				fairnessKey,
+				workflow.GetWorkflowTaskPriority(mutableState),
 			)
 		}
 
@@ -542,6 +552,9 @@ func (t *transferQueueStandbyTaskExecutor) pushActivity(
 		task.(*tasks.ActivityTask),
 		timeout,
 		pushActivityInfo.versionDirective,
+		pushActivityInfo.fairnessKey,
+		pushActivityInfo.priority,
+		pushActivityInfo.activityTypeName,
 	)
 }
 
@@ -562,6 +575,9 @@ func (t *transferQueueStandbyTaskExecutor) pushWorkflowTask(
 		pushwtInfo.taskqueue,
 		pushwtInfo.workflowTaskScheduleToStartTimeout,
 		pushwtInfo.versionDirective,
+		pushwtInfo.fairnessKey,
+		pushwtInfo.priority,
+		pushwtInfo.workflowTypeName,
 	)
 }
 
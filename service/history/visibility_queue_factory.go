@@ -68,6 +68,7 @@ func NewVisibilityQueueFactory(
 					WorkerCount:             params.Config.VisibilityProcessorSchedulerWorkerCount,
 					ActiveNamespaceWeights:  params.Config.VisibilityProcessorSchedulerActiveRoundRobinWeights,
 					StandbyNamespaceWeights: params.Config.VisibilityProcessorSchedulerStandbyRoundRobinWeights,
+					SubscribeWeights:        params.Config.SubscribeVisibilityProcessorSchedulerRoundRobinWeights,
 				},
 				params.NamespaceRegistry,
 				params.Logger,
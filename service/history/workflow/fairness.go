@@ -0,0 +1,95 @@
+// The MIT License
+//
+// Copyright (c) 2020 Temporal Technologies Inc.  All rights reserved.
+//
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package workflow
+
+import (
+	"context"
+
+	commonpb "go.temporal.io/api/common/v1"
+
+	"go.temporal.io/server/common/payload"
+	"go.temporal.io/server/service/history/shard"
+)
+
+const (
+	// FairnessKeySourceWorkflowID uses the workflow ID as fairness key.
+	FairnessKeySourceWorkflowID = "workflowId"
+	// FairnessKeySourceSearchAttribute uses the value of a search attribute of the workflow as fairness key.
+	FairnessKeySourceSearchAttribute = "searchAttribute"
+	// FairnessKeySourceHeader uses the value of a header of the workflow start request as fairness key.
+	FairnessKeySourceHeader = "header"
+)
+
+// GetFairnessKey returns the key matching uses to interleave the backlog tasks of this workflow with the
+// tasks of other workflows on the same task queue. The key is empty when fairness is disabled for the
+// namespace or the workflow has no value for the configured source.
+func GetFairnessKey(
+	ctx context.Context,
+	shardContext shard.Context,
+	mutableState MutableState,
+) (string, error) {
+	config := shardContext.GetConfig()
+	namespaceName := mutableState.GetNamespaceEntry().Name()
+	name := config.MatchingFairnessKeyName(namespaceName.String())
+
+	switch config.MatchingFairnessKeySource(namespaceName.String()) {
+	case FairnessKeySourceWorkflowID:
+		return mutableState.GetExecutionInfo().GetWorkflowId(), nil
+	case FairnessKeySourceSearchAttribute:
+		searchAttributes := mutableState.GetExecutionInfo().GetSearchAttributes()
+		value, ok := searchAttributes[name]
+		if !ok {
+			// search attributes are stored by field name, the configured name may be an alias
+			mapper, err := shardContext.GetSearchAttributesMapperProvider().GetMapper(namespaceName)
+			if err != nil {
+				return "", err
+			}
+			if fieldName, err := mapper.GetFieldName(name, namespaceName.String()); err == nil {
+				value = searchAttributes[fieldName]
+			}
+		}
+		return fairnessKeyFromPayload(value), nil
+	case FairnessKeySourceHeader:
+		startEvent, err := mutableState.GetStartEvent(ctx)
+		if err != nil {
+			return "", err
+		}
+		header := startEvent.GetWorkflowExecutionStartedEventAttributes().GetHeader()
+		return fairnessKeyFromPayload(header.GetFields()[name]), nil
+	default:
+		return "", nil
+	}
+}
+
+func fairnessKeyFromPayload(p *commonpb.Payload) string {
+	if p == nil {
+		return ""
+	}
+	var value string
+	if err := payload.Decode(p, &value); err == nil {
+		return value
+	}
+	return payload.ToString(p)
+}
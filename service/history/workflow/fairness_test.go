@@ -0,0 +1,97 @@
+// The MIT License
+//
+// Copyright (c) 2020 Temporal Technologies Inc.  All rights reserved.
+//
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package workflow
+
+import (
+	"context"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+	commonpb "go.temporal.io/api/common/v1"
+	historypb "go.temporal.io/api/history/v1"
+
+	persistencespb "go.temporal.io/server/api/persistence/v1"
+	"go.temporal.io/server/common/dynamicconfig"
+	"go.temporal.io/server/common/payload"
+	"go.temporal.io/server/common/searchattribute"
+	"go.temporal.io/server/service/history/shard"
+	"go.temporal.io/server/service/history/tests"
+)
+
+func TestGetFairnessKey(t *testing.T) {
+	executionInfo := &persistencespb.WorkflowExecutionInfo{
+		WorkflowId: tests.WorkflowID,
+		SearchAttributes: map[string]*commonpb.Payload{
+			"CustomKeywordField": payload.EncodeString("tenant-1"),
+		},
+	}
+	startEvent := &historypb.HistoryEvent{
+		Attributes: &historypb.HistoryEvent_WorkflowExecutionStartedEventAttributes{
+			WorkflowExecutionStartedEventAttributes: &historypb.WorkflowExecutionStartedEventAttributes{
+				Header: &commonpb.Header{Fields: map[string]*commonpb.Payload{
+					"tenant": payload.EncodeString("tenant-2"),
+				}},
+			},
+		},
+	}
+
+	for _, c := range []struct {
+		name        string
+		source      string
+		keyName     string
+		expectedKey string
+	}{
+		{name: "Disabled", source: "", expectedKey: ""},
+		{name: "WorkflowID", source: FairnessKeySourceWorkflowID, expectedKey: tests.WorkflowID},
+		{name: "SearchAttribute", source: FairnessKeySourceSearchAttribute, keyName: "CustomKeywordField", expectedKey: "tenant-1"},
+		{name: "SearchAttributeAlias", source: FairnessKeySourceSearchAttribute, keyName: "AliasForCustomKeywordField", expectedKey: "tenant-1"},
+		{name: "MissingSearchAttribute", source: FairnessKeySourceSearchAttribute, keyName: "CustomTextField", expectedKey: ""},
+		{name: "Header", source: FairnessKeySourceHeader, keyName: "tenant", expectedKey: "tenant-2"},
+		{name: "MissingHeader", source: FairnessKeySourceHeader, keyName: "other", expectedKey: ""},
+		{name: "UnknownSource", source: "unknown", expectedKey: ""},
+	} {
+		t.Run(c.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			config := tests.NewDynamicConfig()
+			config.MatchingFairnessKeySource = dynamicconfig.GetStringPropertyFnFilteredByNamespace(c.source)
+			config.MatchingFairnessKeyName = dynamicconfig.GetStringPropertyFnFilteredByNamespace(c.keyName)
+
+			shardContext := shard.NewMockContext(ctrl)
+			shardContext.EXPECT().GetConfig().Return(config).AnyTimes()
+			shardContext.EXPECT().GetSearchAttributesMapperProvider().Return(
+				searchattribute.NewTestMapperProvider(&searchattribute.TestMapper{Namespace: tests.Namespace.String()}),
+			).AnyTimes()
+			mutableState := NewMockMutableState(ctrl)
+			mutableState.EXPECT().GetNamespaceEntry().Return(tests.GlobalNamespaceEntry).AnyTimes()
+			mutableState.EXPECT().GetExecutionInfo().Return(executionInfo).AnyTimes()
+			mutableState.EXPECT().GetStartEvent(gomock.Any()).Return(startEvent, nil).AnyTimes()
+
+			fairnessKey, err := GetFairnessKey(context.Background(), shardContext, mutableState)
+			require.NoError(t, err)
+			require.Equal(t, c.expectedKey, fairnessKey)
+		})
+	}
+}
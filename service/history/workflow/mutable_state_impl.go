@@ -1931,6 +1931,7 @@ func (ms *MutableStateImpl) ReplicateWorkflowExecutionStartedEvent(
 	}
 
 	ms.executionInfo.WorkerVersionStamp = event.SourceVersionStamp
+	ms.executionInfo.TaskPriority = taskPriorityFromHeader(event.GetHeader())
 
 	ms.approximateSize += ms.executionInfo.Size()
 
@@ -2375,6 +2376,7 @@ func (ms *MutableStateImpl) ReplicateActivityTaskScheduledEvent(
 		Attempt:                 1,
 		UseCompatibleVersion:    attributes.UseCompatibleVersion,
 		ActivityType:            attributes.GetActivityType(),
+		TaskPriority:            taskPriorityFromHeader(attributes.GetHeader()),
 	}
 	if ai.HasRetryPolicy {
 		ai.RetryInitialInterval = attributes.RetryPolicy.GetInitialInterval()
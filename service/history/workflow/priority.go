@@ -0,0 +1,62 @@
+// The MIT License
+//
+// Copyright (c) 2020 Temporal Technologies Inc.  All rights reserved.
+//
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package workflow
+
+import (
+	commonpb "go.temporal.io/api/common/v1"
+
+	persistencespb "go.temporal.io/server/api/persistence/v1"
+	"go.temporal.io/server/common"
+	"go.temporal.io/server/common/payload"
+)
+
+// GetWorkflowTaskPriority returns the priority matching uses to order the workflow tasks of this workflow
+// with the other tasks of the task queue. Zero means the default priority.
+func GetWorkflowTaskPriority(mutableState MutableState) int32 {
+	return mutableState.GetExecutionInfo().GetTaskPriority()
+}
+
+// GetActivityTaskPriority returns the priority matching uses to order the tasks of this activity with the
+// other tasks of the task queue. Activities without a priority of their own inherit the workflow priority.
+func GetActivityTaskPriority(mutableState MutableState, ai *persistencespb.ActivityInfo) int32 {
+	if priority := ai.GetTaskPriority(); priority != 0 {
+		return priority
+	}
+	return GetWorkflowTaskPriority(mutableState)
+}
+
+// taskPriorityFromHeader returns the task priority set in the header, clamped to the valid range.
+// Zero means the header doesn't set a valid priority.
+func taskPriorityFromHeader(header *commonpb.Header) int32 {
+	p, ok := header.GetFields()[common.TaskPriorityHeaderKey]
+	if !ok {
+		return 0
+	}
+	var priority int32
+	if err := payload.Decode(p, &priority); err != nil {
+		return 0
+	}
+	return max(common.HighestTaskPriority, min(priority, common.LowestTaskPriority))
+}
@@ -0,0 +1,72 @@
+// The MIT License
+//
+// Copyright (c) 2020 Temporal Technologies Inc.  All rights reserved.
+//
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package workflow
+
+import (
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+	commonpb "go.temporal.io/api/common/v1"
+
+	persistencespb "go.temporal.io/server/api/persistence/v1"
+	"go.temporal.io/server/common"
+	"go.temporal.io/server/common/payload"
+)
+
+func TestTaskPriorityFromHeader(t *testing.T) {
+	for _, c := range []struct {
+		name     string
+		header   *commonpb.Header
+		expected int32
+	}{
+		{name: "NoHeader", header: nil, expected: 0},
+		{name: "NoPriority", header: &commonpb.Header{Fields: map[string]*commonpb.Payload{"other": payload.EncodeString("1")}}, expected: 0},
+		{name: "Priority", header: priorityHeader(t, 2), expected: 2},
+		{name: "TooUrgent", header: priorityHeader(t, -3), expected: common.HighestTaskPriority},
+		{name: "TooLow", header: priorityHeader(t, 42), expected: common.LowestTaskPriority},
+		{name: "NotAnInteger", header: &commonpb.Header{Fields: map[string]*commonpb.Payload{common.TaskPriorityHeaderKey: payload.EncodeString("high")}}, expected: 0},
+	} {
+		t.Run(c.name, func(t *testing.T) {
+			require.Equal(t, c.expected, taskPriorityFromHeader(c.header))
+		})
+	}
+}
+
+func TestGetActivityTaskPriority(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mutableState := NewMockMutableState(ctrl)
+	mutableState.EXPECT().GetExecutionInfo().Return(&persistencespb.WorkflowExecutionInfo{TaskPriority: 4}).AnyTimes()
+
+	require.Equal(t, int32(4), GetWorkflowTaskPriority(mutableState))
+	require.Equal(t, int32(4), GetActivityTaskPriority(mutableState, &persistencespb.ActivityInfo{}))
+	require.Equal(t, int32(1), GetActivityTaskPriority(mutableState, &persistencespb.ActivityInfo{TaskPriority: 1}))
+}
+
+func priorityHeader(t *testing.T, priority int) *commonpb.Header {
+	p, err := payload.Encode(priority)
+	require.NoError(t, err)
+	return &commonpb.Header{Fields: map[string]*commonpb.Payload{common.TaskPriorityHeaderKey: p}}
+}
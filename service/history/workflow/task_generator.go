@@ -706,12 +706,13 @@ func (r *TaskGeneratorImpl) getTargetNamespaceID(
 	return namespace.ID(r.mutableState.GetExecutionInfo().NamespaceId), nil
 }
 
-// archivalEnabled returns true if archival is enabled for either history or visibility.
+// archivalEnabled returns true if archival is enabled for either history or visibility, or if export is enabled.
 // For both history and visibility, we check that archival is enabled for both the cluster and the namespace.
 func (r *TaskGeneratorImpl) archivalEnabled() bool {
 	namespaceEntry := r.mutableState.GetNamespaceEntry()
 	return r.archivalMetadata.GetHistoryConfig().ClusterConfiguredForArchival() &&
 		namespaceEntry.HistoryArchivalState().State == enumspb.ARCHIVAL_STATE_ENABLED ||
 		r.archivalMetadata.GetVisibilityConfig().ClusterConfiguredForArchival() &&
-			namespaceEntry.VisibilityArchivalState().State == enumspb.ARCHIVAL_STATE_ENABLED
+			namespaceEntry.VisibilityArchivalState().State == enumspb.ARCHIVAL_STATE_ENABLED ||
+		r.config.ExportURI(namespaceEntry.Name().String()) != ""
 }
@@ -84,6 +84,7 @@ type testParams struct {
 	HistoryArchivalEnabledInNamespace    bool
 	VisibilityArchivalEnabledForCluster  bool
 	VisibilityArchivalEnabledInNamespace bool
+	ExportURI                            string
 
 	ExpectCloseExecutionVisibilityTask              bool
 	ExpectArchiveExecutionTask                      bool
@@ -188,6 +189,17 @@ func TestTaskGeneratorImpl_GenerateWorkflowCloseTasks(t *testing.T) {
 				p.ExpectArchiveExecutionTask = false
 			},
 		},
+		{
+			Name: "archival disabled but export enabled",
+			ConfigFn: func(p *testParams) {
+				p.HistoryArchivalEnabledInCluster = false
+				p.VisibilityArchivalEnabledForCluster = false
+				p.ExportURI = "file:///export"
+
+				p.ExpectCloseExecutionVisibilityTask = true
+				p.ExpectArchiveExecutionTask = true
+			},
+		},
 	} {
 		c := c
 		t.Run(c.Name, func(t *testing.T) {
@@ -262,6 +274,9 @@ func TestTaskGeneratorImpl_GenerateWorkflowCloseTasks(t *testing.T) {
 				ArchivalProcessorArchiveDelay: func() time.Duration {
 					return p.ArchivalProcessorArchiveDelay
 				},
+				ExportURI: func(string) string {
+					return p.ExportURI
+				},
 			}
 			closeTime := time.Unix(0, 0)
 			var allTasks []tasks.Task
@@ -0,0 +1,84 @@
+// The MIT License
+//
+// Copyright (c) 2020 Temporal Technologies Inc.  All rights reserved.
+//
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package matching
+
+import (
+	"sync"
+	"time"
+
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// backlogAgeTracker tracks the creation times of the backlog tasks that were loaded by the task
+// reader and not yet completed, to report the age of the oldest one.
+type backlogAgeTracker struct {
+	sync.Mutex
+	createTimes map[int64]int // unix nanos -> number of tasks created at that time
+}
+
+func newBacklogAgeTracker() *backlogAgeTracker {
+	return &backlogAgeTracker{createTimes: make(map[int64]int)}
+}
+
+// record adds delta tasks with the given creation time. Tasks without a creation time are ignored.
+func (b *backlogAgeTracker) record(createTime *timestamppb.Timestamp, delta int) {
+	if createTime == nil {
+		return
+	}
+	b.Lock()
+	defer b.Unlock()
+	key := createTime.AsTime().UnixNano()
+	if count := b.createTimes[key] + delta; count > 0 {
+		b.createTimes[key] = count
+	} else {
+		delete(b.createTimes, key)
+	}
+}
+
+// oldestCreateTime returns the creation time of the oldest tracked task, or the zero time when
+// no tasks are tracked.
+func (b *backlogAgeTracker) oldestCreateTime() time.Time {
+	b.Lock()
+	defer b.Unlock()
+	var oldest int64
+	for key := range b.createTimes {
+		if oldest == 0 || key < oldest {
+			oldest = key
+		}
+	}
+	if oldest == 0 {
+		return time.Time{}
+	}
+	return time.Unix(0, oldest)
+}
+
+// age returns the age of the oldest tracked task relative to now, or zero when no tasks are tracked.
+func (b *backlogAgeTracker) age(now time.Time) time.Duration {
+	oldest := b.oldestCreateTime()
+	if oldest.IsZero() {
+		return 0
+	}
+	return max(0, now.Sub(oldest))
+}
@@ -0,0 +1,58 @@
+// The MIT License
+//
+// Copyright (c) 2020 Temporal Technologies Inc.  All rights reserved.
+//
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package matching
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+func TestBacklogAgeTracker(t *testing.T) {
+	tracker := newBacklogAgeTracker()
+	now := time.Now()
+	require.Zero(t, tracker.age(now))
+
+	tracker.record(timestamppb.New(now.Add(-time.Minute)), 1)
+	tracker.record(timestamppb.New(now.Add(-time.Hour)), 1)
+	tracker.record(timestamppb.New(now.Add(-time.Hour)), 1)
+	tracker.record(nil, 1)
+	require.Equal(t, time.Hour, tracker.age(now))
+
+	tracker.record(timestamppb.New(now.Add(-time.Hour)), -1)
+	require.Equal(t, time.Hour, tracker.age(now))
+	tracker.record(timestamppb.New(now.Add(-time.Hour)), -1)
+	require.Equal(t, time.Minute, tracker.age(now))
+
+	// tasks created in the future, e.g. due to clock skew, don't make the age negative
+	tracker.record(timestamppb.New(now.Add(-time.Minute)), -1)
+	tracker.record(timestamppb.New(now.Add(time.Minute)), 1)
+	require.Zero(t, tracker.age(now))
+
+	tracker.record(timestamppb.New(now.Add(time.Minute)), -1)
+	require.True(t, tracker.oldestCreateTime().IsZero())
+}
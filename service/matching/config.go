@@ -27,12 +27,15 @@ package matching
 import (
 	"time"
 
+	enumspb "go.temporal.io/api/enums/v1"
+
 	"go.temporal.io/server/common"
 	"go.temporal.io/server/common/backoff"
 	"go.temporal.io/server/common/dynamicconfig"
 	"go.temporal.io/server/common/namespace"
 	"go.temporal.io/server/common/persistence/visibility"
 	"go.temporal.io/server/common/primitives"
+	"go.temporal.io/server/common/tasks"
 )
 
 type (
@@ -70,6 +73,17 @@ type (
 		GetUserDataLongPollTimeout        dynamicconfig.DurationPropertyFn
 		BacklogNegligibleAge              dynamicconfig.DurationPropertyFnWithTaskQueueInfoFilters
 		MaxWaitForPollerBeforeFwd         dynamicconfig.DurationPropertyFnWithTaskQueueInfoFilters
+		FairnessKeySource                 dynamicconfig.StringPropertyFnWithNamespaceFilter
+		FairnessKeyWeights                dynamicconfig.MapPropertyFnWithNamespaceFilter
+		FairnessKeyBuckets                dynamicconfig.IntPropertyFnWithTaskQueueInfoFilters
+		FairnessBacklogBufferSize         dynamicconfig.IntPropertyFnWithTaskQueueInfoFilters
+		FairnessMaxBufferedTasksPerKey    dynamicconfig.IntPropertyFnWithTaskQueueInfoFilters
+		FairnessMaxDeferredTasks          dynamicconfig.IntPropertyFnWithTaskQueueInfoFilters
+		EnableTaskPriority                dynamicconfig.BoolPropertyFnWithTaskQueueInfoFilters
+		TaskPriorityAgingInterval         dynamicconfig.DurationPropertyFnWithTaskQueueInfoFilters
+		WorkflowTypeDispatchRPS           dynamicconfig.MapPropertyFnWithTaskQueueInfoFilters
+		ActivityTypeDispatchRPS           dynamicconfig.MapPropertyFnWithTaskQueueInfoFilters
+		ThrottledBacklogBufferSize        dynamicconfig.IntPropertyFnWithTaskQueueInfoFilters
 
 		// Time to hold a poll request before returning an empty response if there are no tasks
 		LongPollExpirationInterval dynamicconfig.DurationPropertyFnWithTaskQueueInfoFilters
@@ -84,6 +98,9 @@ type (
 
 		AdminNamespaceToPartitionDispatchRate          dynamicconfig.FloatPropertyFnWithNamespaceFilter
 		AdminNamespaceTaskqueueToPartitionDispatchRate dynamicconfig.FloatPropertyFnWithTaskQueueInfoFilters
+		// SubscribeAdminNamespaceTaskqueueToPartitionDispatchRate calls back when AdminNamespaceTaskqueueToPartitionDispatchRate
+		// of the task queue changes.
+		SubscribeAdminNamespaceTaskqueueToPartitionDispatchRate func(namespace string, taskQueue string, taskType enumspb.TaskQueueType, callback func(oldValue, newValue float64)) dynamicconfig.SubscriptionCancelFn
 
 		VisibilityPersistenceMaxReadQPS   dynamicconfig.IntPropertyFn
 		VisibilityPersistenceMaxWriteQPS  dynamicconfig.IntPropertyFn
@@ -119,6 +136,25 @@ type (
 		MinTaskThrottlingBurstSize func() int
 		MaxTaskDeleteBatchSize     func() int
 
+		// Backlog tasks are interleaved by fairness key when enabled. This is only evaluated when the task queue
+		// partition is loaded.
+		EnableFairness                 func() bool
+		FairnessKeyWeight              func(fairnessKey string) (int, bool)
+		FairnessKeyBuckets             func() int
+		FairnessBacklogBufferSize      func() int
+		FairnessMaxBufferedTasksPerKey func() int
+		FairnessMaxDeferredTasks       func() int
+
+		// Tasks are dispatched by priority when enabled. For the backlog this is only evaluated when the task queue
+		// partition is loaded.
+		EnableTaskPriority        func() bool
+		TaskPriorityAgingInterval func() time.Duration
+
+		// Dispatch of the workflow types (workflow task queues) or activity types (activity task queues) that have
+		// a rate here is limited to that many tasks per second across all partitions of the task queue.
+		TypeDispatchRPS            func(typeName string) (float64, bool)
+		ThrottledBacklogBufferSize func() int
+
 		GetUserDataLongPollTimeout dynamicconfig.DurationPropertyFn
 		GetUserDataMinWaitTime     time.Duration
 
@@ -132,6 +168,8 @@ type (
 		AdminNamespaceToPartitionDispatchRate func() float64
 		// partition qps = AdminNamespaceTaskQueueToPartitionDispatchRate(namespace, task_queue)
 		AdminNamespaceTaskQueueToPartitionDispatchRate func() float64
+		// SubscribeAdminNamespaceTaskQueueToPartitionDispatchRate calls back when AdminNamespaceTaskQueueToPartitionDispatchRate changes.
+		SubscribeAdminNamespaceTaskQueueToPartitionDispatchRate func(callback func()) dynamicconfig.SubscriptionCancelFn
 
 		// If set to false, matching does not load user data from DB for root partitions or fetch it via RPC from the
 		// root. When disabled, features that rely on user data (e.g. worker versioning) will essentially be disabled.
@@ -199,9 +237,23 @@ func NewConfig(
 		GetUserDataLongPollTimeout:            dc.GetDurationProperty(dynamicconfig.MatchingGetUserDataLongPollTimeout, 5*time.Minute),
 		BacklogNegligibleAge:                  dc.GetDurationPropertyFilteredByTaskQueueInfo(dynamicconfig.MatchingBacklogNegligibleAge, 24*365*10*time.Hour),
 		MaxWaitForPollerBeforeFwd:             dc.GetDurationPropertyFilteredByTaskQueueInfo(dynamicconfig.MatchingMaxWaitForPollerBeforeFwd, 200*time.Millisecond),
+		FairnessKeySource:                     dc.GetStringPropertyFnWithNamespaceFilter(dynamicconfig.MatchingFairnessKeySource, ""),
+		FairnessKeyWeights:                    dc.GetMapPropertyFnWithNamespaceFilter(dynamicconfig.MatchingFairnessKeyWeights, map[string]any{}),
+		FairnessKeyBuckets:                    dc.GetIntPropertyFilteredByTaskQueueInfo(dynamicconfig.MatchingFairnessKeyBuckets, 64),
+		FairnessBacklogBufferSize:             dc.GetIntPropertyFilteredByTaskQueueInfo(dynamicconfig.MatchingFairnessBacklogBufferSize, 1000),
+		FairnessMaxBufferedTasksPerKey:        dc.GetIntPropertyFilteredByTaskQueueInfo(dynamicconfig.MatchingFairnessMaxBufferedTasksPerKey, 100),
+		FairnessMaxDeferredTasks:              dc.GetIntPropertyFilteredByTaskQueueInfo(dynamicconfig.MatchingFairnessMaxDeferredTasks, 10000),
+		EnableTaskPriority:                    dc.GetBoolPropertyFilteredByTaskQueueInfo(dynamicconfig.MatchingEnableTaskPriority, false),
+		TaskPriorityAgingInterval:             dc.GetDurationPropertyFilteredByTaskQueueInfo(dynamicconfig.MatchingTaskPriorityAgingInterval, time.Minute),
+		WorkflowTypeDispatchRPS:               dc.GetMapPropertyFilteredByTaskQueueInfo(dynamicconfig.MatchingWorkflowTypeDispatchRPS, map[string]any{}),
+		ActivityTypeDispatchRPS:               dc.GetMapPropertyFilteredByTaskQueueInfo(dynamicconfig.MatchingActivityTypeDispatchRPS, map[string]any{}),
+		ThrottledBacklogBufferSize:            dc.GetIntPropertyFilteredByTaskQueueInfo(dynamicconfig.MatchingThrottledBacklogBufferSize, 1000),
 
 		AdminNamespaceToPartitionDispatchRate:          dc.GetFloatPropertyFilteredByNamespace(dynamicconfig.AdminMatchingNamespaceToPartitionDispatchRate, 10000),
 		AdminNamespaceTaskqueueToPartitionDispatchRate: dc.GetFloatPropertyFilteredByTaskQueueInfo(dynamicconfig.AdminMatchingNamespaceTaskqueueToPartitionDispatchRate, 1000),
+		SubscribeAdminNamespaceTaskqueueToPartitionDispatchRate: func(namespace string, taskQueue string, taskType enumspb.TaskQueueType, callback func(oldValue, newValue float64)) dynamicconfig.SubscriptionCancelFn {
+			return dc.SubscribeFloatPropertyFilteredByTaskQueueInfo(dynamicconfig.AdminMatchingNamespaceTaskqueueToPartitionDispatchRate, 1000, namespace, taskQueue, taskType, callback)
+		},
 
 		VisibilityPersistenceMaxReadQPS:   visibility.GetVisibilityPersistenceMaxReadQPS(dc, enableReadFromES),
 		VisibilityPersistenceMaxWriteQPS:  visibility.GetVisibilityPersistenceMaxWriteQPS(dc, enableReadFromES),
@@ -240,6 +292,62 @@ func newTaskQueueConfig(id *taskQueueID, config *Config, namespace namespace.Nam
 		MaxWaitForPollerBeforeFwd: func() time.Duration {
 			return config.MaxWaitForPollerBeforeFwd(namespace.String(), taskQueueName, taskType)
 		},
+		EnableFairness: func() bool {
+			return config.FairnessKeySource(namespace.String()) != ""
+		},
+		FairnessKeyWeight: func(fairnessKey string) (int, bool) {
+			weight, ok := config.FairnessKeyWeights(namespace.String())[fairnessKey]
+			if !ok {
+				return 0, false
+			}
+			switch w := weight.(type) {
+			case int:
+				return max(1, w), true
+			case float64:
+				return max(1, int(w)), true
+			default:
+				return 0, false
+			}
+		},
+		FairnessKeyBuckets: func() int {
+			return max(1, config.FairnessKeyBuckets(namespace.String(), taskQueueName, taskType))
+		},
+		FairnessBacklogBufferSize: func() int {
+			return min(max(1, config.FairnessBacklogBufferSize(namespace.String(), taskQueueName, taskType)), tasks.WeightedChannelDefaultSize)
+		},
+		FairnessMaxBufferedTasksPerKey: func() int {
+			return max(1, config.FairnessMaxBufferedTasksPerKey(namespace.String(), taskQueueName, taskType))
+		},
+		FairnessMaxDeferredTasks: func() int {
+			return config.FairnessMaxDeferredTasks(namespace.String(), taskQueueName, taskType)
+		},
+		EnableTaskPriority: func() bool {
+			return config.EnableTaskPriority(namespace.String(), taskQueueName, taskType)
+		},
+		TaskPriorityAgingInterval: func() time.Duration {
+			return config.TaskPriorityAgingInterval(namespace.String(), taskQueueName, taskType)
+		},
+		TypeDispatchRPS: func(typeName string) (float64, bool) {
+			typeDispatchRPS := config.ActivityTypeDispatchRPS
+			if taskType == enumspb.TASK_QUEUE_TYPE_WORKFLOW {
+				typeDispatchRPS = config.WorkflowTypeDispatchRPS
+			}
+			rps, ok := typeDispatchRPS(namespace.String(), taskQueueName, taskType)[typeName]
+			if !ok {
+				return 0, false
+			}
+			switch r := rps.(type) {
+			case int:
+				return max(0, float64(r)), true
+			case float64:
+				return max(0, r), true
+			default:
+				return 0, false
+			}
+		},
+		ThrottledBacklogBufferSize: func() int {
+			return max(1, config.ThrottledBacklogBufferSize(namespace.String(), taskQueueName, taskType))
+		},
 		TestDisableSyncMatch: config.TestDisableSyncMatch,
 		LoadUserData: func() bool {
 			return config.LoadUserData(namespace.String(), taskQueueName, taskType)
@@ -270,6 +378,11 @@ func newTaskQueueConfig(id *taskQueueID, config *Config, namespace namespace.Nam
 		AdminNamespaceTaskQueueToPartitionDispatchRate: func() float64 {
 			return config.AdminNamespaceTaskqueueToPartitionDispatchRate(namespace.String(), taskQueueName, taskType)
 		},
+		SubscribeAdminNamespaceTaskQueueToPartitionDispatchRate: func(callback func()) dynamicconfig.SubscriptionCancelFn {
+			return config.SubscribeAdminNamespaceTaskqueueToPartitionDispatchRate(namespace.String(), taskQueueName, taskType, func(_, _ float64) {
+				callback()
+			})
+		},
 		forwarderConfig: forwarderConfig{
 			ForwarderMaxOutstandingPolls: func() int {
 				return config.ForwarderMaxOutstandingPolls(namespace.String(), taskQueueName, taskType)
@@ -54,6 +54,7 @@ var (
 		"ForceUnloadTaskQueue":                   1,
 		"UpdateTaskQueueUserData":                1,
 		"ReplicateTaskQueueUserData":             1,
+		"UpdateTaskQueuePauseState":              1,
 	}
 
 	APIPrioritiesOrdered = []int{0, 1}
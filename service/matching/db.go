@@ -82,6 +82,10 @@ type (
 		store           persistence.TaskManager
 		logger          log.Logger
 		matchingClient  matchingservice.MatchingServiceClient
+		// approximateBacklogCount is incremented when tasks are written and decremented when
+		// they are completed or expire. It's persisted with the task queue state so that it
+		// survives ownership changes, and reset by the task reader when it catches up.
+		approximateBacklogCount int64
 	}
 	taskQueueState struct {
 		rangeID  int64
@@ -180,6 +184,7 @@ func (db *taskQueueDB) takeOverTaskQueueLocked(
 			return err
 		}
 		db.ackLevel = response.TaskQueueInfo.AckLevel
+		db.approximateBacklogCount = response.TaskQueueInfo.ApproximateBacklogCount
 		db.rangeID = response.RangeID + 1
 		return nil
 
@@ -241,7 +246,7 @@ func (db *taskQueueDB) CreateTasks(
 ) (*persistence.CreateTasksResponse, error) {
 	db.Lock()
 	defer db.Unlock()
-	return db.store.CreateTasks(
+	resp, err := db.store.CreateTasks(
 		ctx,
 		&persistence.CreateTasksRequest{
 			TaskQueueInfo: &persistence.PersistedTaskQueueInfo{
@@ -250,6 +255,32 @@ func (db *taskQueueDB) CreateTasks(
 			},
 			Tasks: tasks,
 		})
+	if err == nil {
+		db.approximateBacklogCount += int64(len(tasks))
+	}
+	return resp, err
+}
+
+// getApproximateBacklogCount returns the approximate number of tasks in the backlog of this task queue.
+func (db *taskQueueDB) getApproximateBacklogCount() int64 {
+	db.Lock()
+	defer db.Unlock()
+	return db.approximateBacklogCount
+}
+
+// updateApproximateBacklogCount adds delta to the approximate backlog count, which never goes below zero.
+func (db *taskQueueDB) updateApproximateBacklogCount(delta int64) {
+	db.Lock()
+	defer db.Unlock()
+	db.approximateBacklogCount = max(0, db.approximateBacklogCount+delta)
+}
+
+// setApproximateBacklogCount overwrites the approximate backlog count. It's used to correct drift
+// once the exact backlog is known.
+func (db *taskQueueDB) setApproximateBacklogCount(count int64) {
+	db.Lock()
+	defer db.Unlock()
+	db.approximateBacklogCount = max(0, count)
 }
 
 // GetTasks returns a batch of tasks between the given range
@@ -495,5 +526,7 @@ func (db *taskQueueDB) cachedQueueInfo() *persistencespb.TaskQueueInfo {
 		AckLevel:       db.ackLevel,
 		ExpiryTime:     db.expiryTime(),
 		LastUpdateTime: timestamp.TimeNowPtrUtc(),
+
+		ApproximateBacklogCount: db.approximateBacklogCount,
 	}
 }
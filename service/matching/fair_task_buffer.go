@@ -0,0 +1,454 @@
+// The MIT License
+//
+// Copyright (c) 2020 Temporal Technologies Inc.  All rights reserved.
+//
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package matching
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/dgryski/go-farm"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	persistencespb "go.temporal.io/server/api/persistence/v1"
+	taskqueuespb "go.temporal.io/server/api/taskqueue/v1"
+	"go.temporal.io/server/common"
+	"go.temporal.io/server/common/backoff"
+	"go.temporal.io/server/common/log"
+	"go.temporal.io/server/common/tasks"
+)
+
+type (
+	// fairTaskBuffer sits between the taskReader pump and the dispatch loop when fairness is enabled.
+	// Loaded backlog tasks are grouped by fairness key and released to the dispatch loop by weighted
+	// round robin, so that a key with a large backlog can't starve the other keys of the partition.
+	// Keys without an explicit weight share a fixed number of hash buckets to bound memory usage.
+	//
+	// A key holds at most its share of the buffer. The reader skips the further tasks of a key that is at its
+	// share and records them as deferred, so that the tasks of other keys behind them in persistence are
+	// loaded. Deferred tasks are read from persistence again once their key is below its share.
+	fairTaskBuffer struct {
+		config     *taskQueueConfig
+		scheduler  *tasks.InterleavedWeightedRoundRobinScheduler[*fairTask, fairnessChannelKey]
+		dispatcher *fairTaskDispatcher
+		// slots bounds the number of tasks held by the scheduler
+		slots chan struct{}
+
+		backlogsLock sync.Mutex
+		backlogs     map[string]*fairnessKeyBacklog
+		// deferred tasks by task ID. They stay outstanding in the ack manager so they are not deleted.
+		deferred map[int64]deferredTask
+		// IDs of deferred tasks in increasing order, may contain IDs that are no longer deferred
+		deferredIDs    []int64
+		deferredPerKey map[string]int
+	}
+
+	deferredTask struct {
+		fairnessKey string
+		createTime  *timestamppb.Timestamp
+	}
+
+	fairnessChannelKey struct {
+		// keys with an explicit weight get a channel of their own, marked by a negative bucket
+		fairnessKey string
+		bucket      int
+	}
+
+	fairnessKeyBacklog struct {
+		// creation time of buffered tasks, in the order they were loaded
+		createTimes []time.Time
+	}
+
+	// fairTask adapts a backlog task to the task interface of the scheduler. Only Abort is used:
+	// aborted tasks stay in persistence and are loaded again with the partition.
+	fairTask struct {
+		info *persistencespb.AllocatedTaskInfo
+	}
+
+	// fairTaskDispatcher is the downstream scheduler of the round robin scheduler. Submit blocks
+	// until the dispatch loop takes the task.
+	fairTaskDispatcher struct {
+		buffer       *fairTaskBuffer
+		outC         chan<- *persistencespb.AllocatedTaskInfo
+		shutdownC    chan struct{}
+		shutdownOnce sync.Once
+	}
+)
+
+var _ tasks.Scheduler[*fairTask] = (*fairTaskDispatcher)(nil)
+
+func newFairTaskBuffer(
+	config *taskQueueConfig,
+	outC chan<- *persistencespb.AllocatedTaskInfo,
+	logger log.Logger,
+) *fairTaskBuffer {
+	buffer := &fairTaskBuffer{
+		config:         config,
+		slots:          make(chan struct{}, config.FairnessBacklogBufferSize()),
+		backlogs:       make(map[string]*fairnessKeyBacklog),
+		deferred:       make(map[int64]deferredTask),
+		deferredPerKey: make(map[string]int),
+	}
+	buffer.dispatcher = &fairTaskDispatcher{
+		buffer:    buffer,
+		outC:      outC,
+		shutdownC: make(chan struct{}),
+	}
+	buckets := config.FairnessKeyBuckets()
+	buffer.scheduler = tasks.NewInterleavedWeightedRoundRobinScheduler[*fairTask, fairnessChannelKey](
+		tasks.InterleavedWeightedRoundRobinSchedulerOptions[*fairTask, fairnessChannelKey]{
+			TaskChannelKeyFn: func(task *fairTask) fairnessChannelKey {
+				fairnessKey := task.info.GetData().GetFairnessKey()
+				if _, ok := config.FairnessKeyWeight(fairnessKey); ok {
+					return fairnessChannelKey{fairnessKey: fairnessKey, bucket: -1}
+				}
+				return fairnessChannelKey{bucket: int(farm.Fingerprint32([]byte(fairnessKey)) % uint32(buckets))}
+			},
+			ChannelWeightFn: func(key fairnessChannelKey) int {
+				if key.bucket < 0 {
+					if weight, ok := config.FairnessKeyWeight(key.fairnessKey); ok {
+						return weight
+					}
+				}
+				return 1
+			},
+		},
+		buffer.dispatcher,
+		logger,
+	)
+	return buffer
+}
+
+func (b *fairTaskBuffer) Start() {
+	b.scheduler.Start()
+}
+
+func (b *fairTaskBuffer) Stop() {
+	b.scheduler.Stop()
+}
+
+// add blocks until there is room for the task in the buffer.
+func (b *fairTaskBuffer) add(ctx context.Context, info *persistencespb.AllocatedTaskInfo) error {
+	select {
+	case b.slots <- struct{}{}:
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+
+	b.trackTask(info)
+	// Slots never exceed the capacity of a scheduler channel, so this only fails after the scheduler is
+	// stopped. TrySubmit is used because Submit may hand the task directly to the dispatch loop and
+	// block until a poller takes it.
+	if !b.scheduler.TrySubmit(&fairTask{info: info}) {
+		b.untrackTask(info)
+		<-b.slots
+		return ctx.Err()
+	}
+	return nil
+}
+
+func (b *fairTaskBuffer) trackTask(info *persistencespb.AllocatedTaskInfo) {
+	b.backlogsLock.Lock()
+	defer b.backlogsLock.Unlock()
+
+	fairnessKey := info.GetData().GetFairnessKey()
+	backlog, ok := b.backlogs[fairnessKey]
+	if !ok {
+		backlog = &fairnessKeyBacklog{}
+		b.backlogs[fairnessKey] = backlog
+	}
+	backlog.createTimes = append(backlog.createTimes, info.GetData().GetCreateTime().AsTime())
+}
+
+func (b *fairTaskBuffer) untrackTask(info *persistencespb.AllocatedTaskInfo) {
+	b.backlogsLock.Lock()
+	defer b.backlogsLock.Unlock()
+
+	fairnessKey := info.GetData().GetFairnessKey()
+	backlog, ok := b.backlogs[fairnessKey]
+	if !ok {
+		return
+	}
+	// tasks of one key are released in the order they were loaded
+	backlog.createTimes = backlog.createTimes[1:]
+	if len(backlog.createTimes) == 0 {
+		delete(b.backlogs, fairnessKey)
+	}
+}
+
+// keyLimit returns the max number of buffered tasks of a key. Weighted keys get a proportionally larger share.
+func (b *fairTaskBuffer) keyLimit(fairnessKey string) int {
+	limit := b.config.FairnessMaxBufferedTasksPerKey()
+	if weight, ok := b.config.FairnessKeyWeight(fairnessKey); ok {
+		limit *= weight
+	}
+	return min(limit, b.config.FairnessBacklogBufferSize())
+}
+
+func (b *fairTaskBuffer) bufferedLocked(fairnessKey string) int {
+	if backlog, ok := b.backlogs[fairnessKey]; ok {
+		return len(backlog.createTimes)
+	}
+	return 0
+}
+
+// deferTask records the task as deferred instead of buffering it when its key is at its share of the buffer.
+// Once a key has deferred tasks its newer tasks are deferred as well, so the tasks of a key keep their order
+// as long as FairnessMaxDeferredTasks isn't reached.
+// Returns false when the task should be buffered.
+func (b *fairTaskBuffer) deferTask(info *persistencespb.AllocatedTaskInfo) bool {
+	b.backlogsLock.Lock()
+	defer b.backlogsLock.Unlock()
+
+	fairnessKey := info.GetData().GetFairnessKey()
+	if b.deferredPerKey[fairnessKey] == 0 && b.bufferedLocked(fairnessKey) < b.keyLimit(fairnessKey) {
+		return false
+	}
+	if len(b.deferred) >= b.config.FairnessMaxDeferredTasks() {
+		return false
+	}
+	b.deferred[info.GetTaskId()] = deferredTask{fairnessKey: fairnessKey, createTime: info.GetData().GetCreateTime()}
+	b.deferredIDs = append(b.deferredIDs, info.GetTaskId())
+	b.deferredPerKey[fairnessKey]++
+	return true
+}
+
+// takeDeferred removes the task from the deferred tasks if its key is below its share of the buffer, or
+// unconditionally when force is set. isDeferred reports whether the task is still deferred afterwards.
+func (b *fairTaskBuffer) takeDeferred(info *persistencespb.AllocatedTaskInfo, force bool) (taken bool, isDeferred bool) {
+	b.backlogsLock.Lock()
+	defer b.backlogsLock.Unlock()
+
+	deferred, ok := b.deferred[info.GetTaskId()]
+	if !ok {
+		return false, false
+	}
+	if !force && b.bufferedLocked(deferred.fairnessKey) >= b.keyLimit(deferred.fairnessKey) {
+		return false, true
+	}
+	b.removeDeferredLocked(info.GetTaskId(), deferred)
+	return true, false
+}
+
+// takeDeferredInRange removes the deferred tasks with IDs in [minID, maxID] except the given ones, and returns
+// their creation times by task ID.
+func (b *fairTaskBuffer) takeDeferredInRange(minID, maxID int64, except map[int64]struct{}) map[int64]*timestamppb.Timestamp {
+	b.backlogsLock.Lock()
+	defer b.backlogsLock.Unlock()
+
+	var result map[int64]*timestamppb.Timestamp
+	for _, taskID := range b.deferredIDs {
+		if taskID < minID || taskID > maxID {
+			continue
+		}
+		deferred, ok := b.deferred[taskID]
+		if !ok {
+			continue
+		}
+		if _, ok := except[taskID]; ok {
+			continue
+		}
+		if result == nil {
+			result = make(map[int64]*timestamppb.Timestamp)
+		}
+		result[taskID] = deferred.createTime
+		b.removeDeferredLocked(taskID, deferred)
+	}
+	return result
+}
+
+func (b *fairTaskBuffer) removeDeferredLocked(taskID int64, deferred deferredTask) {
+	delete(b.deferred, taskID)
+	b.deferredPerKey[deferred.fairnessKey]--
+	if b.deferredPerKey[deferred.fairnessKey] == 0 {
+		delete(b.deferredPerKey, deferred.fairnessKey)
+	}
+	// drop the IDs that are no longer deferred from both ends
+	for len(b.deferredIDs) > 0 {
+		if _, ok := b.deferred[b.deferredIDs[0]]; ok {
+			break
+		}
+		b.deferredIDs = b.deferredIDs[1:]
+	}
+	for len(b.deferredIDs) > 0 {
+		if _, ok := b.deferred[b.deferredIDs[len(b.deferredIDs)-1]]; ok {
+			break
+		}
+		b.deferredIDs = b.deferredIDs[:len(b.deferredIDs)-1]
+	}
+}
+
+// deferredRange returns the smallest and largest deferred task ID, and false if no task is deferred.
+func (b *fairTaskBuffer) deferredRange() (int64, int64, bool) {
+	b.backlogsLock.Lock()
+	defer b.backlogsLock.Unlock()
+
+	if len(b.deferredIDs) == 0 {
+		return 0, 0, false
+	}
+	return b.deferredIDs[0], b.deferredIDs[len(b.deferredIDs)-1], true
+}
+
+// shouldLoadDeferred returns true when there are deferred tasks and either the reader has read all
+// of the backlog, no more tasks can be deferred, or a key with deferred tasks is under-served.
+func (b *fairTaskBuffer) shouldLoadDeferred(caughtUp bool) bool {
+	b.backlogsLock.Lock()
+	defer b.backlogsLock.Unlock()
+
+	if len(b.deferred) == 0 {
+		return false
+	}
+	if caughtUp || len(b.deferred) >= b.config.FairnessMaxDeferredTasks() {
+		return true
+	}
+	return b.hasUnderservedKeyLocked()
+}
+
+// hasUnderservedKey returns true if a key with deferred tasks has used no more than half of its share of the
+// buffer, so that deferred tasks are loaded in batches rather than one by one.
+func (b *fairTaskBuffer) hasUnderservedKey() bool {
+	b.backlogsLock.Lock()
+	defer b.backlogsLock.Unlock()
+	return b.hasUnderservedKeyLocked()
+}
+
+func (b *fairTaskBuffer) hasUnderservedKeyLocked() bool {
+	for fairnessKey := range b.deferredPerKey {
+		if b.bufferedLocked(fairnessKey) <= b.keyLimit(fairnessKey)/2 {
+			return true
+		}
+	}
+	return false
+}
+
+// numDeferred returns the number of deferred tasks.
+func (b *fairTaskBuffer) numDeferred() int {
+	b.backlogsLock.Lock()
+	defer b.backlogsLock.Unlock()
+	return len(b.deferred)
+}
+
+// size returns the number of distinct keys and the number of tasks in the buffer.
+func (b *fairTaskBuffer) size() (int, int) {
+	b.backlogsLock.Lock()
+	defer b.backlogsLock.Unlock()
+
+	numTasks := 0
+	for _, backlog := range b.backlogs {
+		numTasks += len(backlog.createTimes)
+	}
+	return len(b.backlogs), numTasks
+}
+
+// fairnessKeyBacklogs returns the buffered backlog per fairness key, largest first.
+func (b *fairTaskBuffer) fairnessKeyBacklogs() []*taskqueuespb.FairnessKeyBacklog {
+	b.backlogsLock.Lock()
+	defer b.backlogsLock.Unlock()
+
+	result := make([]*taskqueuespb.FairnessKeyBacklog, 0, len(b.backlogs))
+	for fairnessKey, backlog := range b.backlogs {
+		result = append(result, &taskqueuespb.FairnessKeyBacklog{
+			FairnessKey:                  fairnessKey,
+			BufferedTaskCount:            int64(len(backlog.createTimes)),
+			OldestBufferedTaskCreateTime: timestamppb.New(backlog.createTimes[0]),
+		})
+	}
+	sortFairnessKeyBacklogs(result)
+	return result
+}
+
+// mergeFairnessKeyBacklogs merges the loaded backlogs of the same fairness key from several buffers.
+func mergeFairnessKeyBacklogs(backlogs ...[]*taskqueuespb.FairnessKeyBacklog) []*taskqueuespb.FairnessKeyBacklog {
+	if len(backlogs) == 1 {
+		return backlogs[0]
+	}
+	byKey := make(map[string]*taskqueuespb.FairnessKeyBacklog)
+	var result []*taskqueuespb.FairnessKeyBacklog
+	for _, keyBacklogs := range backlogs {
+		for _, backlog := range keyBacklogs {
+			merged, ok := byKey[backlog.FairnessKey]
+			if !ok {
+				merged = common.CloneProto(backlog)
+				byKey[backlog.FairnessKey] = merged
+				result = append(result, merged)
+				continue
+			}
+			merged.BufferedTaskCount += backlog.BufferedTaskCount
+			if backlog.OldestBufferedTaskCreateTime.AsTime().Before(merged.OldestBufferedTaskCreateTime.AsTime()) {
+				merged.OldestBufferedTaskCreateTime = backlog.OldestBufferedTaskCreateTime
+			}
+		}
+	}
+	sortFairnessKeyBacklogs(result)
+	return result
+}
+
+func sortFairnessKeyBacklogs(backlogs []*taskqueuespb.FairnessKeyBacklog) {
+	sort.Slice(backlogs, func(i, j int) bool {
+		if backlogs[i].BufferedTaskCount != backlogs[j].BufferedTaskCount {
+			return backlogs[i].BufferedTaskCount > backlogs[j].BufferedTaskCount
+		}
+		return backlogs[i].FairnessKey < backlogs[j].FairnessKey
+	})
+}
+
+func (d *fairTaskDispatcher) Submit(task *fairTask) {
+	select {
+	case d.outC <- task.info:
+		d.buffer.untrackTask(task.info)
+		<-d.buffer.slots
+	case <-d.shutdownC:
+		task.Abort()
+	}
+}
+
+func (d *fairTaskDispatcher) TrySubmit(task *fairTask) bool {
+	select {
+	case d.outC <- task.info:
+		d.buffer.untrackTask(task.info)
+		<-d.buffer.slots
+		return true
+	default:
+		return false
+	}
+}
+
+func (d *fairTaskDispatcher) Start() {}
+
+func (d *fairTaskDispatcher) Stop() {
+	d.shutdownOnce.Do(func() { close(d.shutdownC) })
+}
+
+func (t *fairTask) Execute() error                   { return nil }
+func (t *fairTask) HandleErr(err error) error        { return err }
+func (t *fairTask) IsRetryableError(_ error) bool    { return false }
+func (t *fairTask) RetryPolicy() backoff.RetryPolicy { return backoff.DisabledRetryPolicy }
+func (t *fairTask) Abort()                           {}
+func (t *fairTask) Cancel()                          {}
+func (t *fairTask) Ack()                             {}
+func (t *fairTask) Nack(_ error)                     {}
+func (t *fairTask) Reschedule()                      {}
+func (t *fairTask) State() tasks.State               { return tasks.TaskStatePending }
@@ -0,0 +1,194 @@
+// The MIT License
+//
+// Copyright (c) 2020 Temporal Technologies Inc.  All rights reserved.
+//
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package matching
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	persistencespb "go.temporal.io/server/api/persistence/v1"
+	"go.temporal.io/server/common/dynamicconfig"
+	"go.temporal.io/server/common/log"
+)
+
+func newFairTaskBufferForTest(t *testing.T, weights map[string]any) (*fairTaskBuffer, chan *persistencespb.AllocatedTaskInfo) {
+	cfg := NewConfig(dynamicconfig.NewNoopCollection(), false, false)
+	cfg.FairnessKeySource = dynamicconfig.GetStringPropertyFnFilteredByNamespace("workflowId")
+	cfg.FairnessKeyWeights = dynamicconfig.GetMapPropertyFnWithNamespaceFilter(weights)
+	tqCfg := newTaskQueueConfig(newTestTaskQueueID("ns-id", "tq", 0), cfg, "ns")
+	require.True(t, tqCfg.EnableFairness())
+
+	outC := make(chan *persistencespb.AllocatedTaskInfo)
+	buffer := newFairTaskBuffer(tqCfg, outC, log.NewNoopLogger())
+	buffer.Start()
+	t.Cleanup(buffer.Stop)
+	return buffer, outC
+}
+
+func newFairTaskForTest(taskID int64, fairnessKey string, createTime time.Time) *persistencespb.AllocatedTaskInfo {
+	return &persistencespb.AllocatedTaskInfo{
+		TaskId: taskID,
+		Data: &persistencespb.TaskInfo{
+			FairnessKey: fairnessKey,
+			CreateTime:  timestamppb.New(createTime),
+		},
+	}
+}
+
+func TestFairTaskBuffer_InterleavesKeys(t *testing.T) {
+	buffer, outC := newFairTaskBufferForTest(t, map[string]any{"a": 1, "b": 1})
+
+	ctx := context.Background()
+	now := time.Now()
+	for i := int64(0); i < 6; i++ {
+		require.NoError(t, buffer.add(ctx, newFairTaskForTest(i, "a", now)))
+	}
+	for i := int64(6); i < 8; i++ {
+		require.NoError(t, buffer.add(ctx, newFairTaskForTest(i, "b", now)))
+	}
+
+	var keys []string
+	for i := 0; i < 8; i++ {
+		select {
+		case task := <-outC:
+			keys = append(keys, task.Data.FairnessKey)
+		case <-time.After(5 * time.Second):
+			require.FailNow(t, "timed out waiting for task")
+		}
+	}
+	// without fairness both "b" tasks would be dispatched last
+	require.Contains(t, keys[:5], "b")
+	require.NotContains(t, keys[5:], "b")
+	require.Eventually(t, func() bool {
+		numKeys, numTasks := buffer.size()
+		return numKeys == 0 && numTasks == 0
+	}, 5*time.Second, 10*time.Millisecond)
+}
+
+func TestFairTaskBuffer_WeightedKeys(t *testing.T) {
+	buffer, outC := newFairTaskBufferForTest(t, map[string]any{"a": 1, "b": 3})
+
+	ctx := context.Background()
+	now := time.Now()
+	for i := int64(0); i < 8; i++ {
+		require.NoError(t, buffer.add(ctx, newFairTaskForTest(i, "a", now)))
+	}
+	for i := int64(8); i < 16; i++ {
+		require.NoError(t, buffer.add(ctx, newFairTaskForTest(i, "b", now)))
+	}
+
+	counts := make(map[string]int)
+	for i := 0; i < 9; i++ {
+		task := <-outC
+		counts[task.Data.FairnessKey]++
+	}
+	// the first task may have been released before "b" tasks were added, the rest is split 1:3
+	require.GreaterOrEqual(t, counts["b"], 5)
+}
+
+func TestFairTaskBuffer_FairnessKeyBacklogs(t *testing.T) {
+	buffer, _ := newFairTaskBufferForTest(t, map[string]any{})
+
+	ctx := context.Background()
+	now := time.Now().UTC()
+	require.NoError(t, buffer.add(ctx, newFairTaskForTest(1, "a", now.Add(-time.Minute))))
+	require.NoError(t, buffer.add(ctx, newFairTaskForTest(2, "b", now.Add(-time.Second))))
+	require.NoError(t, buffer.add(ctx, newFairTaskForTest(3, "a", now)))
+
+	backlogs := buffer.fairnessKeyBacklogs()
+	require.Len(t, backlogs, 2)
+	require.Equal(t, "a", backlogs[0].FairnessKey)
+	require.Equal(t, int64(2), backlogs[0].BufferedTaskCount)
+	require.Equal(t, now.Add(-time.Minute), backlogs[0].OldestBufferedTaskCreateTime.AsTime())
+	require.Equal(t, "b", backlogs[1].FairnessKey)
+	require.Equal(t, int64(1), backlogs[1].BufferedTaskCount)
+}
+
+func TestFairTaskBuffer_AddBlocksWhenFull(t *testing.T) {
+	buffer, _ := newFairTaskBufferForTest(t, map[string]any{})
+	buffer.slots = make(chan struct{}, 1)
+
+	require.NoError(t, buffer.add(context.Background(), newFairTaskForTest(1, "a", time.Now())))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	require.ErrorIs(t, buffer.add(ctx, newFairTaskForTest(2, "a", time.Now())), context.DeadlineExceeded)
+}
+
+func TestFairTaskBuffer_DeferTask(t *testing.T) {
+	buffer, outC := newFairTaskBufferForTest(t, map[string]any{"a": 1, "b": 2})
+	buffer.config.FairnessMaxBufferedTasksPerKey = func() int { return 2 }
+	buffer.config.FairnessMaxDeferredTasks = func() int { return 3 }
+
+	ctx := context.Background()
+	now := time.Now()
+	require.NoError(t, buffer.add(ctx, newFairTaskForTest(1, "a", now)))
+	require.NoError(t, buffer.add(ctx, newFairTaskForTest(2, "a", now)))
+
+	// "a" is at its share of the buffer, "b" has twice the share of "a"
+	require.True(t, buffer.deferTask(newFairTaskForTest(3, "a", now)))
+	require.False(t, buffer.deferTask(newFairTaskForTest(4, "b", now)))
+	require.True(t, buffer.deferTask(newFairTaskForTest(5, "a", now)))
+	require.True(t, buffer.deferTask(newFairTaskForTest(6, "a", now)))
+	// no more tasks can be deferred
+	require.False(t, buffer.deferTask(newFairTaskForTest(7, "a", now)))
+	require.True(t, buffer.shouldLoadDeferred(false))
+
+	buffer.config.FairnessMaxDeferredTasks = func() int { return 10 }
+	require.False(t, buffer.shouldLoadDeferred(false))
+	require.True(t, buffer.shouldLoadDeferred(true))
+	minID, maxID, ok := buffer.deferredRange()
+	require.True(t, ok)
+	require.Equal(t, int64(3), minID)
+	require.Equal(t, int64(6), maxID)
+
+	taken, isDeferred := buffer.takeDeferred(newFairTaskForTest(3, "a", now), false)
+	require.False(t, taken)
+	require.True(t, isDeferred)
+	taken, isDeferred = buffer.takeDeferred(newFairTaskForTest(4, "b", now), false)
+	require.False(t, taken)
+	require.False(t, isDeferred)
+
+	<-outC
+	require.Eventually(t, func() bool {
+		_, numTasks := buffer.size()
+		return numTasks == 1
+	}, 5*time.Second, 10*time.Millisecond)
+	require.True(t, buffer.shouldLoadDeferred(false))
+	taken, _ = buffer.takeDeferred(newFairTaskForTest(3, "a", now), false)
+	require.True(t, taken)
+
+	missing := buffer.takeDeferredInRange(0, 10, map[int64]struct{}{6: {}})
+	require.Len(t, missing, 1)
+	require.Contains(t, missing, int64(5))
+	require.Equal(t, 1, buffer.numDeferred())
+	minID, maxID, _ = buffer.deferredRange()
+	require.Equal(t, int64(6), minID)
+	require.Equal(t, int64(6), maxID)
+}
@@ -154,6 +154,9 @@ func (fwdr *Forwarder) ForwardTask(ctx context.Context, task *internalTask) erro
 			ScheduleToStartTimeout: expirationDuration,
 			ForwardedSource:        fwdr.taskQueueID.FullName(),
 			VersionDirective:       task.event.Data.GetVersionDirective(),
+			FairnessKey:            task.event.Data.GetFairnessKey(),
+			Priority:               task.event.Data.GetPriority(),
+			WorkflowTypeName:       task.event.Data.GetWorkflowTypeName(),
 		})
 	case enumspb.TASK_QUEUE_TYPE_ACTIVITY:
 		_, err = fwdr.client.AddActivityTask(ctx, &matchingservice.AddActivityTaskRequest{
@@ -169,6 +172,9 @@ func (fwdr *Forwarder) ForwardTask(ctx context.Context, task *internalTask) erro
 			ScheduleToStartTimeout: expirationDuration,
 			ForwardedSource:        fwdr.taskQueueID.FullName(),
 			VersionDirective:       task.event.Data.GetVersionDirective(),
+			FairnessKey:            task.event.Data.GetFairnessKey(),
+			Priority:               task.event.Data.GetPriority(),
+			ActivityTypeName:       task.event.Data.GetActivityTypeName(),
 		})
 	default:
 		return errInvalidTaskQueueType
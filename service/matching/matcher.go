@@ -35,6 +35,7 @@ import (
 
 	enumsspb "go.temporal.io/server/api/enums/v1"
 	"go.temporal.io/server/api/matchingservice/v1"
+	"go.temporal.io/server/common"
 	"go.temporal.io/server/common/metrics"
 	"go.temporal.io/server/common/primitives/timestamp"
 	"go.temporal.io/server/common/quotas"
@@ -46,8 +47,9 @@ import (
 type TaskMatcher struct {
 	config *taskQueueConfig
 
-	// synchronous task channel to match producer/consumer
-	taskC chan *internalTask
+	// synchronous task channels to match producer/consumer, one per task priority with the most urgent first.
+	// Pollers take a task from the most urgent channel that has one.
+	taskCs [numTaskPriorities]chan *internalTask
 	// synchronous task channel to match query task - the reason to have a
 	// separate channel for this is that there are cases where consumers
 	// are interested in queryTasks but not others. One example is when a
@@ -60,8 +62,12 @@ type TaskMatcher struct {
 	dynamicRateLimiter *quotas.DynamicRateLimiterImpl
 	// forceRefreshRateOnce is used to force refresh rate limit for first time
 	forceRefreshRateOnce sync.Once
+	// adminRateLimiter limits the dispatch rate to AdminNamespaceTaskQueueToPartitionDispatchRate.
+	adminRateLimiter *quotas.DynamicRateLimiterImpl
 	// rateLimiter that limits the rate at which tasks can be dispatched to consumers
 	rateLimiter quotas.RateLimiter
+	// typeRateLimiter limits the rate at which tasks of each workflow or activity type can be dispatched
+	typeRateLimiter *typeRateLimiter
 
 	fwdr                   *Forwarder
 	metricsHandler         metrics.Handler // namespace metric scope
@@ -75,11 +81,19 @@ const (
 	defaultTaskDispatchRPS                  = 100000.0
 	defaultTaskDispatchRPSTTL               = time.Minute
 	emptyBacklogAge           time.Duration = -1
+
+	numTaskPriorities = int(common.LowestTaskPriority - common.HighestTaskPriority + 1)
+	// the blocking selects in poll have a case for the channel of each of the 5 priorities,
+	// the constant below overflows and fails to compile if the number of priorities changes
+	// without updating them
+	_ = uint(numTaskPriorities-5) + uint(5-numTaskPriorities)
 )
 
 var (
 	// Sentinel error to redirect while blocked in matcher.
 	errInterrupted = errors.New("interrupted offer")
+	// Sentinel error returned when a backlog task can't be dispatched yet because of the rate limit of its type.
+	errTaskTypeThrottled = errors.New("task type dispatch rate exceeded")
 )
 
 // newTaskMatcher returns a task matcher instance. The returned instance can be used by task producers and consumers to
@@ -93,27 +107,33 @@ func newTaskMatcher(config *taskQueueConfig, fwdr *Forwarder, metricsHandler met
 		dynamicRateBurst,
 		defaultTaskDispatchRPSTTL,
 	)
+	adminRateLimiter := quotas.NewDefaultOutgoingRateLimiter(
+		config.AdminNamespaceTaskQueueToPartitionDispatchRate,
+	)
 	limiter := quotas.NewMultiRateLimiter([]quotas.RateLimiter{
 		dynamicRateLimiter,
-		quotas.NewDefaultOutgoingRateLimiter(
-			config.AdminNamespaceTaskQueueToPartitionDispatchRate,
-		),
+		adminRateLimiter,
 		quotas.NewDefaultOutgoingRateLimiter(
 			config.AdminNamespaceToPartitionDispatchRate,
 		),
 	})
-	return &TaskMatcher{
+	tm := &TaskMatcher{
 		config:                 config,
 		dynamicRateBurst:       dynamicRateBurst,
 		dynamicRateLimiter:     dynamicRateLimiter,
+		adminRateLimiter:       adminRateLimiter,
 		rateLimiter:            limiter,
+		typeRateLimiter:        newTypeRateLimiter(config),
 		metricsHandler:         metricsHandler,
 		fwdr:                   fwdr,
-		taskC:                  make(chan *internalTask),
 		queryTaskC:             make(chan *internalTask),
 		numPartitions:          config.NumReadPartitions,
 		backlogTasksCreateTime: make(map[int64]int),
 	}
+	for i := range tm.taskCs {
+		tm.taskCs[i] = make(chan *internalTask)
+	}
+	return tm
 }
 
 // Offer offers a task to a potential consumer (poller)
@@ -132,7 +152,9 @@ func newTaskMatcher(config *taskQueueConfig, fwdr *Forwarder, metricsHandler met
 // Ratelimit:
 // When a ratelimit token is not available, this method might block
 // waiting for a token until the provided context timeout. Rate limits are
-// not enforced for forwarded tasks from child partition.
+// not enforced for forwarded tasks from child partition. Tasks of a workflow
+// or activity type that is over its rate limit are not matched, so that they
+// go to the backlog.
 //
 // Forwarded tasks that originated from db backlog:
 // When this method is called with a task that is forwarded from a
@@ -155,6 +177,24 @@ func (tm *TaskMatcher) Offer(ctx context.Context, task *internalTask) (bool, err
 		return false, nil
 	}
 
+	if !task.isForwarded() {
+		// the token of the task type is given back if the task isn't matched, so that it isn't taken a second
+		// time when the task is dispatched from the backlog
+		cancelTypeToken, ok := tm.typeRateLimiter.reserve(task)
+		if !ok {
+			tm.metricsHandler.Counter(metrics.TypeThrottlePerTaskQueueCounter.Name()).Record(1)
+			return false, nil
+		}
+		matched, err := tm.offer(ctx, task)
+		if !matched {
+			cancelTypeToken()
+		}
+		return matched, err
+	}
+	return tm.offer(ctx, task)
+}
+
+func (tm *TaskMatcher) offer(ctx context.Context, task *internalTask) (bool, error) {
 	if !task.isForwarded() {
 		if err := tm.rateLimiter.Wait(ctx); err != nil {
 			tm.metricsHandler.Counter(metrics.SyncThrottlePerTaskQueueCounter.Name()).Record(1)
@@ -163,7 +203,7 @@ func (tm *TaskMatcher) Offer(ctx context.Context, task *internalTask) (bool, err
 	}
 
 	select {
-	case tm.taskC <- task: // poller picked up the task
+	case tm.taskChan(task) <- task: // poller picked up the task
 		if task.responseC != nil {
 			// if there is a response channel, block until resp is received
 			// and return error if the response contains error
@@ -203,7 +243,7 @@ func (tm *TaskMatcher) Offer(ctx context.Context, task *internalTask) (bool, err
 
 func (tm *TaskMatcher) offerOrTimeout(ctx context.Context, task *internalTask) (bool, error) {
 	select {
-	case tm.taskC <- task: // poller picked up the task
+	case tm.taskChan(task) <- task: // poller picked up the task
 		if task.responseC != nil {
 			select {
 			case err := <-task.responseC:
@@ -256,7 +296,8 @@ func (tm *TaskMatcher) OfferQuery(ctx context.Context, task *internalTask) (*mat
 }
 
 // MustOffer blocks until a consumer is found to handle this task
-// Returns error only when context is canceled or the ratelimit is set to zero (allow nothing)
+// Returns error only when context is canceled or the ratelimit is set to zero (allow nothing),
+// or errTaskTypeThrottled without blocking when the type of the task is over its rate limit
 // The passed in context MUST NOT have a deadline associated with it
 // Note that calling MustOffer is the only way that matcher knows there are spooled tasks in the
 // backlog, in absence of a pending MustOffer call, the forwarding logic assumes that backlog is empty.
@@ -264,6 +305,10 @@ func (tm *TaskMatcher) MustOffer(ctx context.Context, task *internalTask, interr
 	tm.registerBacklogTask(task)
 	defer tm.unregisterBacklogTask(task)
 
+	if !tm.typeRateLimiter.allow(task) {
+		tm.metricsHandler.Counter(metrics.TypeThrottlePerTaskQueueCounter.Name()).Record(1)
+		return errTaskTypeThrottled
+	}
 	if err := tm.rateLimiter.Wait(ctx); err != nil {
 		return err
 	}
@@ -271,7 +316,7 @@ func (tm *TaskMatcher) MustOffer(ctx context.Context, task *internalTask, interr
 	// attempt a match with local poller first. When that
 	// doesn't succeed, try both local match and remote match
 	select {
-	case tm.taskC <- task:
+	case tm.taskChan(task) <- task:
 		tm.emitDispatchLatency(task, false)
 		return nil
 	case <-ctx.Done():
@@ -288,6 +333,8 @@ func (tm *TaskMatcher) MustOffer(ctx context.Context, task *internalTask, interr
 
 forLoop:
 	for {
+		// the priority of the task may have aged since the last iteration
+		taskC := tm.taskChan(task)
 		fwdTokenC := tm.fwdrAddReqTokenC()
 		reconsiderFwdTimer = nil
 		var reconsiderFwdTimerC <-chan time.Time
@@ -313,7 +360,7 @@ forLoop:
 		}
 
 		select {
-		case tm.taskC <- task:
+		case taskC <- task:
 			tm.emitDispatchLatency(task, false)
 			return nil
 		case token := <-fwdTokenC:
@@ -327,7 +374,7 @@ forLoop:
 				// the next forwarded call after this childCtx expires. Till then, we block
 				// hoping for a local poller match
 				select {
-				case tm.taskC <- task:
+				case taskC <- task:
 					cancel()
 					tm.emitDispatchLatency(task, false)
 					return nil
@@ -421,6 +468,12 @@ func (tm *TaskMatcher) UpdateRatelimit(rpsPtr *float64) {
 	})
 }
 
+// RefreshAdminRatelimit applies the current AdminNamespaceTaskQueueToPartitionDispatchRate without waiting for the
+// periodic refresh of the rate limiter.
+func (tm *TaskMatcher) RefreshAdminRatelimit() {
+	tm.adminRateLimiter.Refresh()
+}
+
 // Rate returns the current rate at which tasks are dispatched
 func (tm *TaskMatcher) Rate() float64 {
 	return tm.rateLimiter.Rate()
@@ -429,9 +482,9 @@ func (tm *TaskMatcher) Rate() float64 {
 func (tm *TaskMatcher) poll(
 	ctx context.Context, pollMetadata *pollMetadata, queryOnly bool,
 ) (task *internalTask, forwardedPoll bool, err error) {
-	taskC, queryTaskC := tm.taskC, tm.queryTaskC
+	taskCs, queryTaskC := tm.taskCs, tm.queryTaskC
 	if queryOnly {
-		taskC = nil
+		taskCs = [numTaskPriorities]chan *internalTask{}
 	}
 
 	start := time.Now()
@@ -453,7 +506,7 @@ func (tm *TaskMatcher) poll(
 	// if multiple cases are ready, so split into multiple selects.
 	// The priority order is:
 	// 1. ctx.Done
-	// 2. taskC and queryTaskC
+	// 2. queryTaskC, then taskCs from the most urgent task priority
 	// 3. forwarding
 	// 4. block looking locally for remainder of context lifetime
 	// To correctly handle priorities and allow any case to succeed, all select
@@ -468,20 +521,19 @@ func (tm *TaskMatcher) poll(
 	default:
 	}
 
-	// 2. taskC and queryTaskC
+	// 2. queryTaskC and taskCs
 	select {
-	case task := <-taskC:
-		if task.responseC != nil {
-			tm.metricsHandler.Counter(metrics.PollSuccessWithSyncPerTaskQueueCounter.Name()).Record(1)
-		}
-		tm.metricsHandler.Counter(metrics.PollSuccessPerTaskQueueCounter.Name()).Record(1)
-		return task, false, nil
 	case task := <-queryTaskC:
-		tm.metricsHandler.Counter(metrics.PollSuccessWithSyncPerTaskQueueCounter.Name()).Record(1)
-		tm.metricsHandler.Counter(metrics.PollSuccessPerTaskQueueCounter.Name()).Record(1)
-		return task, false, nil
+		return tm.polledQueryTask(task), false, nil
 	default:
 	}
+	for _, taskC := range taskCs {
+		select {
+		case task := <-taskC:
+			return tm.polledTask(task), false, nil
+		default:
+		}
+	}
 
 	if tm.isBacklogNegligible() {
 		// 3. forwarding (and all other clauses repeated)
@@ -490,16 +542,18 @@ func (tm *TaskMatcher) poll(
 		case <-ctx.Done():
 			tm.metricsHandler.Counter(metrics.PollTimeoutPerTaskQueueCounter.Name()).Record(1)
 			return nil, false, errNoTasks
-		case task := <-taskC:
-			if task.responseC != nil {
-				tm.metricsHandler.Counter(metrics.PollSuccessWithSyncPerTaskQueueCounter.Name()).Record(1)
-			}
-			tm.metricsHandler.Counter(metrics.PollSuccessPerTaskQueueCounter.Name()).Record(1)
-			return task, false, nil
+		case task := <-taskCs[0]:
+			return tm.polledTask(task), false, nil
+		case task := <-taskCs[1]:
+			return tm.polledTask(task), false, nil
+		case task := <-taskCs[2]:
+			return tm.polledTask(task), false, nil
+		case task := <-taskCs[3]:
+			return tm.polledTask(task), false, nil
+		case task := <-taskCs[4]:
+			return tm.polledTask(task), false, nil
 		case task := <-queryTaskC:
-			tm.metricsHandler.Counter(metrics.PollSuccessWithSyncPerTaskQueueCounter.Name()).Record(1)
-			tm.metricsHandler.Counter(metrics.PollSuccessPerTaskQueueCounter.Name()).Record(1)
-			return task, false, nil
+			return tm.polledQueryTask(task), false, nil
 		case token := <-tm.fwdrPollReqTokenC():
 			if task, err := tm.fwdr.ForwardPoll(ctx, pollMetadata); err == nil {
 				token.release()
@@ -514,17 +568,42 @@ func (tm *TaskMatcher) poll(
 	case <-ctx.Done():
 		tm.metricsHandler.Counter(metrics.PollTimeoutPerTaskQueueCounter.Name()).Record(1)
 		return nil, false, errNoTasks
-	case task := <-taskC:
-		if task.responseC != nil {
-			tm.metricsHandler.Counter(metrics.PollSuccessWithSyncPerTaskQueueCounter.Name()).Record(1)
-		}
-		tm.metricsHandler.Counter(metrics.PollSuccessPerTaskQueueCounter.Name()).Record(1)
-		return task, false, nil
+	case task := <-taskCs[0]:
+		return tm.polledTask(task), false, nil
+	case task := <-taskCs[1]:
+		return tm.polledTask(task), false, nil
+	case task := <-taskCs[2]:
+		return tm.polledTask(task), false, nil
+	case task := <-taskCs[3]:
+		return tm.polledTask(task), false, nil
+	case task := <-taskCs[4]:
+		return tm.polledTask(task), false, nil
 	case task := <-queryTaskC:
+		return tm.polledQueryTask(task), false, nil
+	}
+}
+
+func (tm *TaskMatcher) polledTask(task *internalTask) *internalTask {
+	if task.responseC != nil {
 		tm.metricsHandler.Counter(metrics.PollSuccessWithSyncPerTaskQueueCounter.Name()).Record(1)
-		tm.metricsHandler.Counter(metrics.PollSuccessPerTaskQueueCounter.Name()).Record(1)
-		return task, false, nil
 	}
+	tm.metricsHandler.Counter(metrics.PollSuccessPerTaskQueueCounter.Name()).Record(1)
+	return task
+}
+
+func (tm *TaskMatcher) polledQueryTask(task *internalTask) *internalTask {
+	tm.metricsHandler.Counter(metrics.PollSuccessWithSyncPerTaskQueueCounter.Name()).Record(1)
+	tm.metricsHandler.Counter(metrics.PollSuccessPerTaskQueueCounter.Name()).Record(1)
+	return task
+}
+
+// taskChan returns the channel to offer the task to pollers on, by the aged priority of the task.
+func (tm *TaskMatcher) taskChan(task *internalTask) chan *internalTask {
+	priority := common.DefaultTaskPriority
+	if task.event != nil && tm.config.EnableTaskPriority() {
+		priority = agedTaskPriority(task.event.Data, tm.config.TaskPriorityAgingInterval(), time.Now())
+	}
+	return tm.taskCs[priority-common.HighestTaskPriority]
 }
 
 func (tm *TaskMatcher) fwdrPollReqTokenC() <-chan *ForwarderReqToken {
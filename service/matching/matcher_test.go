@@ -33,6 +33,7 @@ import (
 
 	"github.com/golang/mock/gomock"
 	"github.com/pborman/uuid"
+	"github.com/stretchr/testify/require"
 	"github.com/stretchr/testify/suite"
 	enumspb "go.temporal.io/api/enums/v1"
 	querypb "go.temporal.io/api/query/v1"
@@ -45,6 +46,7 @@ import (
 	"go.temporal.io/server/api/matchingservicemock/v1"
 	persistencespb "go.temporal.io/server/api/persistence/v1"
 	"go.temporal.io/server/common/dynamicconfig"
+	"go.temporal.io/server/common/log"
 	"go.temporal.io/server/common/metrics"
 	"go.temporal.io/server/common/namespace"
 	"go.temporal.io/server/common/payloads"
@@ -248,6 +250,55 @@ func (t *MatcherTestSuite) TestRejectSyncMatchWhenBacklog() {
 	cancel()
 }
 
+func (t *MatcherTestSuite) TestPollPrefersUrgentTasks() {
+	cfg := NewConfig(dynamicconfig.NewNoopCollection(), false, false)
+	cfg.EnableTaskPriority = dynamicconfig.GetBoolPropertyFnFilteredByTaskQueueInfo(true)
+	cfg.TaskPriorityAgingInterval = dynamicconfig.GetDurationPropertyFnFilteredByTaskQueueInfo(time.Hour)
+	rootTaskQueue := newTestTaskQueueID(t.taskQueue.namespaceID, mustParent(t.taskQueue.Name, 20).FullName(), enumspb.TASK_QUEUE_TYPE_WORKFLOW)
+	matcher := newTaskMatcher(newTaskQueueConfig(rootTaskQueue, cfg, "test-namespace"), nil, metrics.NoopMetricsHandler)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	intruptC := make(chan struct{})
+
+	newBacklogTask := func(priority int32, age time.Duration) *internalTask {
+		info := randomTaskInfoWithAge(age)
+		info.Data.Priority = priority
+		return newInternalTask(info, nil, enumsspb.TASK_SOURCE_DB_BACKLOG, "", false)
+	}
+	bulkTask := newBacklogTask(5, time.Minute)
+	urgentTask := newBacklogTask(1, time.Second)
+	// a low priority task that waited long enough ages to the highest priority
+	agedTask := newBacklogTask(4, 4*time.Hour)
+	awaitBlockedOffers := func(count int) {
+		t.Eventually(func() bool {
+			matcher.backlogTasksLock.Lock()
+			defer matcher.backlogTasksLock.Unlock()
+			return len(matcher.backlogTasksCreateTime) == count
+		}, time.Second, time.Millisecond)
+		// tasks are registered just before they are offered
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	go matcher.MustOffer(ctx, bulkTask, intruptC)   //nolint:errcheck
+	go matcher.MustOffer(ctx, urgentTask, intruptC) //nolint:errcheck
+	awaitBlockedOffers(2)
+
+	task, err := matcher.Poll(ctx, &pollMetadata{})
+	t.NoError(err)
+	t.Equal(urgentTask, task)
+
+	go matcher.MustOffer(ctx, agedTask, intruptC) //nolint:errcheck
+	awaitBlockedOffers(2)
+
+	task, err = matcher.Poll(ctx, &pollMetadata{})
+	t.NoError(err)
+	t.Equal(agedTask, task)
+	task, err = matcher.Poll(ctx, &pollMetadata{})
+	t.NoError(err)
+	t.Equal(bulkTask, task)
+}
+
 func (t *MatcherTestSuite) TestForwardingWhenBacklogIsYoung() {
 	historyTask := newInternalTask(randomTaskInfo(), nil, enumsspb.TASK_SOURCE_HISTORY, "", true)
 
@@ -579,6 +630,99 @@ func (t *MatcherTestSuite) TestMustOfferRemoteMatch() {
 	t.Equal(mustParent(t.taskQueue.Name, 20).FullName(), req.GetTaskQueue().GetName())
 }
 
+func (t *MatcherTestSuite) TestTypeRateLimit() {
+	cfg := newTaskQueueConfig(t.taskQueue, NewConfig(dynamicconfig.NewNoopCollection(), false, false), "test-namespace")
+	cfg.NumReadPartitions = func() int { return 1 }
+	cfg.TypeDispatchRPS = func(typeName string) (float64, bool) {
+		return 1, typeName == "throttled"
+	}
+	matcher := newTaskMatcher(cfg, nil, metrics.NoopMetricsHandler)
+
+	newTypedTask := func(typeName string) *internalTask {
+		info := randomTaskInfoWithAge(0)
+		info.Data.WorkflowTypeName = typeName
+		return newInternalTask(info, nil, enumsspb.TASK_SOURCE_DB_BACKLOG, "", false)
+	}
+	mustOfferToPoller := func(task *internalTask) error {
+		go func() {
+			ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+			defer cancel()
+			if polled, err := matcher.Poll(ctx, &pollMetadata{}); err == nil {
+				polled.finish(nil)
+			}
+		}()
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+		defer cancel()
+		return matcher.MustOffer(ctx, task, nil)
+	}
+
+	// the first task uses up the burst of its type
+	t.NoError(mustOfferToPoller(newTypedTask("throttled")))
+	// the next task of the type is not dispatched, without blocking
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	t.ErrorIs(matcher.MustOffer(ctx, newTypedTask("throttled"), nil), errTaskTypeThrottled)
+	t.Positive(matcher.typeRateLimiter.retryDelay(newTypedTask("throttled")))
+	// sync match doesn't match the type either, so that its tasks go to the backlog
+	syncMatch, err := matcher.Offer(ctx, newTypedTask("throttled"))
+	t.NoError(err)
+	t.False(syncMatch)
+
+	// other types are not limited
+	t.Zero(matcher.typeRateLimiter.retryDelay(newTypedTask("other")))
+	for i := 0; i < 3; i++ {
+		t.NoError(mustOfferToPoller(newTypedTask("other")))
+	}
+}
+
+func (t *MatcherTestSuite) TestTypeRateLimitNotChargedForUnmatchedOffer() {
+	cfg := newTaskQueueConfig(t.taskQueue, NewConfig(dynamicconfig.NewNoopCollection(), false, false), "test-namespace")
+	cfg.NumReadPartitions = func() int { return 1 }
+	cfg.TypeDispatchRPS = func(typeName string) (float64, bool) {
+		return 5, true
+	}
+	matcher := newTaskMatcher(cfg, nil, metrics.NoopMetricsHandler)
+
+	newTypedTask := func() *internalTask {
+		info := randomTaskInfoWithAge(0)
+		info.Data.WorkflowTypeName = "throttled"
+		return newInternalTask(info, nil, enumsspb.TASK_SOURCE_DB_BACKLOG, "", false)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	// every task first fails to sync match and is then dispatched from the backlog, which takes a single token of
+	// its type, so the whole burst of the configured rate is dispatched
+	for i := 0; i < 5; i++ {
+		syncMatch, err := matcher.Offer(ctx, newTypedTask())
+		t.NoError(err)
+		t.False(syncMatch)
+
+		go func() {
+			if polled, err := matcher.Poll(ctx, &pollMetadata{}); err == nil {
+				polled.finish(nil)
+			}
+		}()
+		t.NoError(matcher.MustOffer(ctx, newTypedTask(), nil), "iteration %d", i)
+	}
+	t.ErrorIs(matcher.MustOffer(ctx, newTypedTask(), nil), errTaskTypeThrottled)
+}
+
+func (t *MatcherTestSuite) TestAdminRatelimitSubscription() {
+	client := &notifyingStaticClient{StaticClient: dynamicconfig.StaticClient{}}
+	cfg := newTaskQueueConfig(t.taskQueue, NewConfig(dynamicconfig.NewCollection(client, log.NewNoopLogger()), false, false), "test-namespace")
+	matcher := newTaskMatcher(cfg, nil, metrics.NoopMetricsHandler)
+	t.Equal(1000.0, matcher.Rate())
+
+	cancel := cfg.SubscribeAdminNamespaceTaskQueueToPartitionDispatchRate(matcher.RefreshAdminRatelimit)
+	client.set(dynamicconfig.AdminMatchingNamespaceTaskqueueToPartitionDispatchRate, 10.0)
+	t.Equal(10.0, matcher.Rate())
+
+	cancel()
+	client.set(dynamicconfig.AdminMatchingNamespaceTaskqueueToPartitionDispatchRate, 20.0)
+	t.Equal(10.0, matcher.Rate())
+}
+
 func (t *MatcherTestSuite) TestRemotePoll() {
 	pollToken := <-t.fwdr.PollReqTokenC()
 
@@ -661,3 +805,45 @@ func randomTaskInfoWithAge(age time.Duration) *persistencespb.AllocatedTaskInfo
 		TaskId: rand.Int63(),
 	}
 }
+
+// notifyingStaticClient is a dynamicconfig.StaticClient which notifies its subscriber when a value is set.
+type notifyingStaticClient struct {
+	dynamicconfig.StaticClient
+	callback dynamicconfig.ClientUpdateFn
+}
+
+func (c *notifyingStaticClient) Subscribe(callback dynamicconfig.ClientUpdateFn) func() {
+	c.callback = callback
+	return func() { c.callback = nil }
+}
+
+func (c *notifyingStaticClient) set(key dynamicconfig.Key, value any) {
+	c.StaticClient[key] = value
+	if c.callback != nil {
+		c.callback(map[dynamicconfig.Key]struct{}{key: {}})
+	}
+}
+
+func TestAgedTaskPriority(t *testing.T) {
+	now := time.Now()
+	for _, c := range []struct {
+		name          string
+		priority      int32
+		age           time.Duration
+		agingInterval time.Duration
+		expected      int32
+	}{
+		{name: "Default", priority: 0, age: 0, agingInterval: time.Minute, expected: 3},
+		{name: "OutOfRange", priority: 9, age: 0, agingInterval: time.Minute, expected: 5},
+		{name: "NotAgedYet", priority: 4, age: 59 * time.Second, agingInterval: time.Minute, expected: 4},
+		{name: "AgedOneLevel", priority: 4, age: 90 * time.Second, agingInterval: time.Minute, expected: 3},
+		{name: "AgedToHighest", priority: 5, age: time.Hour, agingInterval: time.Minute, expected: 1},
+		{name: "CreatedInFuture", priority: 2, age: -time.Hour, agingInterval: time.Minute, expected: 2},
+		{name: "AgingDisabled", priority: 5, age: time.Hour, agingInterval: 0, expected: 5},
+	} {
+		t.Run(c.name, func(t *testing.T) {
+			data := &persistencespb.TaskInfo{Priority: c.priority, CreateTime: timestamppb.New(now.Add(-c.age))}
+			require.Equal(t, c.expected, agedTaskPriority(data, c.agingInterval, now))
+		})
+	}
+}
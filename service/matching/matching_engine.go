@@ -49,6 +49,7 @@ import (
 	enumsspb "go.temporal.io/server/api/enums/v1"
 	persistencespb "go.temporal.io/server/api/persistence/v1"
 	replicationspb "go.temporal.io/server/api/replication/v1"
+	taskqueuespb "go.temporal.io/server/api/taskqueue/v1"
 	tokenspb "go.temporal.io/server/api/token/v1"
 	"go.temporal.io/server/common"
 	"go.temporal.io/server/common/clock"
@@ -263,9 +264,10 @@ func (e *matchingEngineImpl) String() string {
 // getTaskQueueManager is called twice with the same taskQueue but different stickyInfo, the
 // properties of the taskQueueManager will depend on which call came first. In general, we can
 // rely on kind being the same for all calls now, but normalName was a later addition to the
-// protocol and is not always set consistently. normalName is only required when using
-// versioning, and SDKs that support versioning will always set it. The current server version
-// will also set it when adding tasks from history. So that particular inconsistency is okay.
+// protocol and is not always set consistently. normalName is required to read the user data of
+// the normal queue, for versioning and its pause state. SDKs that support versioning will always
+// set it, and the current server version will also set it when adding tasks from history, so a
+// sticky queue loaded without it is loaded again once it's known.
 func (e *matchingEngineImpl) getTaskQueueManager(
 	ctx context.Context,
 	taskQueue *taskQueueID,
@@ -293,6 +295,12 @@ func (e *matchingEngineImpl) getTaskQueueManagerNoWait(
 	e.taskQueuesLock.RLock()
 	tqm, ok := e.taskQueues[*taskQueue]
 	e.taskQueuesLock.RUnlock()
+	if ok && missesNormalQueueName(tqm, stickyInfo) {
+		// The sticky queue was loaded by a poll of an older SDK and doesn't get the user data of
+		// its normal queue, so that it isn't paused with it. Load it again with the normal queue.
+		e.unloadTaskQueue(tqm)
+		ok, create = false, true
+	}
 	if !ok {
 		if !create {
 			return nil, nil
@@ -320,6 +328,13 @@ func (e *matchingEngineImpl) getTaskQueueManagerNoWait(
 	return tqm, nil
 }
 
+// missesNormalQueueName returns whether tqm is a sticky queue that was loaded without the name of
+// its normal queue, while stickyInfo has it.
+func missesNormalQueueName(tqm taskQueueManager, stickyInfo stickyInfo) bool {
+	impl, ok := tqm.(*taskQueueManagerImpl)
+	return ok && impl.kind == enumspb.TASK_QUEUE_KIND_STICKY && impl.normalName == "" && stickyInfo.normalName != ""
+}
+
 // For use in tests
 func (e *matchingEngineImpl) updateTaskQueue(taskQueue *taskQueueID, mgr taskQueueManager) {
 	e.taskQueuesLock.Lock()
@@ -374,6 +389,9 @@ func (e *matchingEngineImpl) AddWorkflowTask(
 		ExpiryTime:       expirationTime,
 		CreateTime:       timestamppb.New(now),
 		VersionDirective: addRequest.VersionDirective,
+		FairnessKey:      addRequest.GetFairnessKey(),
+		Priority:         addRequest.GetPriority(),
+		WorkflowTypeName: addRequest.GetWorkflowTypeName(),
 	}
 
 	return tqm.AddTask(ctx, addTaskParams{
@@ -426,6 +444,9 @@ func (e *matchingEngineImpl) AddActivityTask(
 		CreateTime:       timestamppb.New(now),
 		ExpiryTime:       expirationTime,
 		VersionDirective: addRequest.VersionDirective,
+		FairnessKey:      addRequest.GetFairnessKey(),
+		Priority:         addRequest.GetPriority(),
+		ActivityTypeName: addRequest.GetActivityTypeName(),
 	}
 
 	return tqm.AddTask(ctx, addTaskParams{
@@ -847,7 +868,66 @@ func (e *matchingEngineImpl) DescribeTaskQueue(
 		return nil, err
 	}
 
-	return tlMgr.DescribeTaskQueue(request.DescRequest.GetIncludeTaskQueueStatus()), nil
+	includeStatus := request.DescRequest.GetIncludeTaskQueueStatus()
+	response := tlMgr.DescribeTaskQueue(includeStatus)
+	if includeStatus && taskQueue.IsRoot() && taskQueue.VersionSet() == "" && stickyInfo.kind != enumspb.TASK_QUEUE_KIND_STICKY {
+		e.aggregatePartitionBacklogs(ctx, request, taskQueue, response)
+	}
+	return response, nil
+}
+
+// aggregatePartitionBacklogs replaces the backlog of the root partition in the response with
+// the aggregate backlog of all partitions: counts are summed and the age is the maximum age.
+// Partitions that fail to respond are logged and left out of the aggregate.
+func (e *matchingEngineImpl) aggregatePartitionBacklogs(
+	ctx context.Context,
+	request *matchingservice.DescribeTaskQueueRequest,
+	taskQueue *taskQueueID,
+	response *matchingservice.DescribeTaskQueueResponse,
+) {
+	nsName, err := e.namespaceRegistry.GetNamespaceName(taskQueue.namespaceID)
+	if err != nil {
+		return
+	}
+	numPartitions := max(
+		e.config.NumTaskqueueReadPartitions(nsName.String(), taskQueue.BaseNameString(), taskQueue.taskType),
+		e.config.NumTaskqueueWritePartitions(nsName.String(), taskQueue.BaseNameString(), taskQueue.taskType),
+	)
+	total := &taskqueuespb.TaskQueueBacklog{
+		ApproximateBacklogCount: response.GetBacklog().GetApproximateBacklogCount(),
+		ApproximateBacklogAge:   response.GetBacklog().GetApproximateBacklogAge(),
+	}
+	partitionBacklogs := map[string]*taskqueuespb.TaskQueueBacklog{taskQueue.FullName(): response.GetBacklog()}
+	for i := 1; i < numPartitions; i++ {
+		partition := taskQueue.WithPartition(i).FullName()
+		resp, err := e.matchingRawClient.DescribeTaskQueue(ctx, &matchingservice.DescribeTaskQueueRequest{
+			NamespaceId: request.GetNamespaceId(),
+			DescRequest: &workflowservice.DescribeTaskQueueRequest{
+				Namespace: request.DescRequest.GetNamespace(),
+				TaskQueue: &taskqueuepb.TaskQueue{
+					Name: partition,
+					Kind: enumspb.TASK_QUEUE_KIND_NORMAL,
+				},
+				TaskQueueType:          taskQueue.taskType,
+				IncludeTaskQueueStatus: true,
+			},
+		})
+		if err != nil {
+			e.logger.Info("Failed to describe task queue partition for backlog aggregation",
+				tag.WorkflowTaskQueueName(partition),
+				tag.WorkflowTaskQueueType(taskQueue.taskType),
+				tag.Error(err))
+			continue
+		}
+		backlog := resp.GetBacklog()
+		partitionBacklogs[partition] = backlog
+		total.ApproximateBacklogCount += backlog.GetApproximateBacklogCount()
+		if backlog.GetApproximateBacklogAge().AsDuration() > total.GetApproximateBacklogAge().AsDuration() {
+			total.ApproximateBacklogAge = backlog.GetApproximateBacklogAge()
+		}
+	}
+	response.Backlog = total
+	response.PartitionBacklogs = partitionBacklogs
 }
 
 func (e *matchingEngineImpl) ListTaskQueuePartitions(
@@ -993,6 +1073,36 @@ func (e *matchingEngineImpl) UpdateWorkerBuildIdCompatibility(
 	return &matchingservice.UpdateWorkerBuildIdCompatibilityResponse{}, nil
 }
 
+func (e *matchingEngineImpl) UpdateTaskQueuePauseState(
+	ctx context.Context,
+	req *matchingservice.UpdateTaskQueuePauseStateRequest,
+) (*matchingservice.UpdateTaskQueuePauseStateResponse, error) {
+	namespaceID := namespace.ID(req.GetNamespaceId())
+	taskQueue, err := newTaskQueueID(namespaceID, req.GetTaskQueue(), enumspb.TASK_QUEUE_TYPE_WORKFLOW)
+	if err != nil {
+		return nil, err
+	}
+	tqMgr, err := e.getTaskQueueManager(ctx, taskQueue, normalStickyInfo, true)
+	if err != nil {
+		return nil, err
+	}
+	err = tqMgr.UpdateUserData(ctx, UserDataUpdateOptions{}, func(data *persistencespb.TaskQueueUserData) (*persistencespb.TaskQueueUserData, bool, error) {
+		clk := data.GetClock()
+		if clk == nil {
+			clk = hlc.Zero(e.clusterMeta.GetClusterID())
+		}
+		updatedClock := hlc.Next(clk, e.timeSource)
+		ret := setPauseState(updatedClock, data, req)
+		ret.Clock = updatedClock
+		// Pause state is local to the cluster and not replicated.
+		return ret, false, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &matchingservice.UpdateTaskQueuePauseStateResponse{}, nil
+}
+
 func (e *matchingEngineImpl) GetWorkerBuildIdCompatibility(
 	ctx context.Context,
 	req *matchingservice.GetWorkerBuildIdCompatibilityRequest,
@@ -1291,9 +1401,42 @@ func (e *matchingEngineImpl) pollTask(
 		defer baseTqm.UpdatePollerInfo(pollerIdentity(identity), pollMetadata)
 	}
 
+	if err := waitUntilResumed(ctx, cancel, baseTqm); err != nil {
+		return nil, err
+	}
 	return tqm.PollTask(ctx, pollMetadata)
 }
 
+// waitUntilResumed blocks while dispatch from the task queue is paused, and returns errNoTasks if
+// ctx is done before it's resumed. Once resumed, cancel is called if the task queue is paused
+// again before ctx is done, so that the poll ends without a task.
+func waitUntilResumed(ctx context.Context, cancel context.CancelFunc, tqm taskQueueManager) error {
+	paused, pauseChanged := tqm.IsPaused()
+	for paused {
+		select {
+		case <-ctx.Done():
+			return errNoTasks
+		case <-pauseChanged:
+			paused, pauseChanged = tqm.IsPaused()
+		}
+	}
+	go func(pauseChanged chan struct{}) {
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-pauseChanged:
+				var paused bool
+				if paused, pauseChanged = tqm.IsPaused(); paused {
+					cancel()
+					return
+				}
+			}
+		}
+	}(pauseChanged)
+	return nil
+}
+
 func (e *matchingEngineImpl) unloadTaskQueue(unloadTQM taskQueueManager) {
 	queueID := unloadTQM.QueueID()
 	e.taskQueuesLock.Lock()
@@ -53,5 +53,6 @@ type (
 		ForceUnloadTaskQueue(ctx context.Context, request *matchingservice.ForceUnloadTaskQueueRequest) (*matchingservice.ForceUnloadTaskQueueResponse, error)
 		UpdateTaskQueueUserData(ctx context.Context, request *matchingservice.UpdateTaskQueueUserDataRequest) (*matchingservice.UpdateTaskQueueUserDataResponse, error)
 		ReplicateTaskQueueUserData(ctx context.Context, request *matchingservice.ReplicateTaskQueueUserDataRequest) (*matchingservice.ReplicateTaskQueueUserDataResponse, error)
+		UpdateTaskQueuePauseState(ctx context.Context, request *matchingservice.UpdateTaskQueuePauseStateRequest) (*matchingservice.UpdateTaskQueuePauseStateResponse, error)
 	}
 )
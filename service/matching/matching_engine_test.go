@@ -39,6 +39,7 @@ import (
 	"github.com/stretchr/testify/require"
 	"github.com/stretchr/testify/suite"
 	"github.com/uber-go/tally/v4"
+	"google.golang.org/grpc"
 	"google.golang.org/protobuf/types/known/durationpb"
 	"google.golang.org/protobuf/types/known/emptypb"
 	"google.golang.org/protobuf/types/known/timestamppb"
@@ -79,6 +80,7 @@ import (
 	"go.temporal.io/server/common/primitives/timestamp"
 	"go.temporal.io/server/common/quotas"
 	serviceerrors "go.temporal.io/server/common/serviceerror"
+	"go.temporal.io/server/common/tqname"
 )
 
 type (
@@ -127,6 +129,8 @@ func (s *matchingEngineSuite) SetupTest() {
 		Return(&matchingservice.UpdateTaskQueueUserDataResponse{}, nil).AnyTimes()
 	s.mockMatchingClient.EXPECT().ReplicateTaskQueueUserData(gomock.Any(), gomock.Any()).
 		Return(&matchingservice.ReplicateTaskQueueUserDataResponse{}, nil).AnyTimes()
+	s.mockMatchingClient.EXPECT().DescribeTaskQueue(gomock.Any(), gomock.Any()).
+		Return(&matchingservice.DescribeTaskQueueResponse{}, nil).AnyTimes()
 	s.taskManager = newTestTaskManager(s.logger)
 	s.mockNamespaceCache = namespace.NewMockRegistry(s.controller)
 	ns := namespace.NewLocalNamespaceForTest(&persistencespb.NamespaceInfo{Name: matchingTestNamespace}, nil, "")
@@ -914,6 +918,179 @@ func (s *matchingEngineSuite) TestAddThenConsumeActivities() {
 	s.True(expectedRange <= s.taskManager.getTaskQueueManager(tlID).rangeID)
 }
 
+func (s *matchingEngineSuite) TestAddThenConsumeActivities_Fairness() {
+	s.matchingEngine.config.LongPollExpirationInterval = dynamicconfig.GetDurationPropertyFnFilteredByTaskQueueInfo(10 * time.Millisecond)
+	s.matchingEngine.config.FairnessKeySource = dynamicconfig.GetStringPropertyFnFilteredByNamespace("workflowId")
+	s.matchingEngine.config.FairnessKeyWeights = dynamicconfig.GetMapPropertyFnWithNamespaceFilter(map[string]any{
+		"noisy": 1,
+		"quiet": 1,
+	})
+
+	const noisyTaskCount = 20
+	const quietTaskCount = 2
+
+	namespaceID := namespace.ID(uuid.New())
+	taskQueue := &taskqueuepb.TaskQueue{
+		Name: "fairTaskQueue",
+		Kind: enumspb.TASK_QUEUE_KIND_NORMAL,
+	}
+	addTask := func(workflowID string, scheduledEventID int64) {
+		_, err := s.matchingEngine.AddActivityTask(context.Background(), &matchingservice.AddActivityTaskRequest{
+			NamespaceId:            namespaceID.String(),
+			Execution:              &commonpb.WorkflowExecution{RunId: uuid.NewRandom().String(), WorkflowId: workflowID},
+			ScheduledEventId:       scheduledEventID,
+			TaskQueue:              taskQueue,
+			ScheduleToStartTimeout: timestamp.DurationFromSeconds(100),
+			FairnessKey:            workflowID,
+		})
+		s.NoError(err)
+	}
+	for i := int64(0); i < noisyTaskCount; i++ {
+		addTask("noisy", i)
+	}
+	for i := int64(0); i < quietTaskCount; i++ {
+		addTask("quiet", i)
+	}
+
+	describeRequest := &matchingservice.DescribeTaskQueueRequest{
+		NamespaceId: namespaceID.String(),
+		DescRequest: &workflowservice.DescribeTaskQueueRequest{
+			TaskQueue:              taskQueue,
+			TaskQueueType:          enumspb.TASK_QUEUE_TYPE_ACTIVITY,
+			IncludeTaskQueueStatus: true,
+		},
+	}
+	s.Eventually(func() bool {
+		descResp, err := s.matchingEngine.DescribeTaskQueue(context.Background(), describeRequest)
+		s.NoError(err)
+		var buffered int64
+		for _, backlog := range descResp.GetFairnessKeyBacklogs() {
+			buffered += backlog.GetBufferedTaskCount()
+		}
+		// one task has been taken by the dispatch loop, which waits for a poller
+		return buffered == noisyTaskCount+quietTaskCount-1
+	}, 5*time.Second, 10*time.Millisecond)
+
+	var dispatchedWorkflowIDs []string
+	s.mockHistoryClient.EXPECT().RecordActivityTaskStarted(gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(
+		func(ctx context.Context, taskRequest *historyservice.RecordActivityTaskStartedRequest, arg2 ...interface{}) (*historyservice.RecordActivityTaskStartedResponse, error) {
+			dispatchedWorkflowIDs = append(dispatchedWorkflowIDs, taskRequest.WorkflowExecution.GetWorkflowId())
+			return &historyservice.RecordActivityTaskStartedResponse{
+				Attempt: 1,
+				ScheduledEvent: newActivityTaskScheduledEvent(taskRequest.ScheduledEventId, 0,
+					&commandpb.ScheduleActivityTaskCommandAttributes{
+						ActivityId:   "activity",
+						TaskQueue:    taskQueue,
+						ActivityType: &commonpb.ActivityType{Name: "activity"},
+					}),
+				StartedTime: timestamp.TimeNowPtrUtc(),
+			}, nil
+		}).AnyTimes()
+
+	for len(dispatchedWorkflowIDs) < noisyTaskCount+quietTaskCount {
+		_, err := s.matchingEngine.PollActivityTaskQueue(context.Background(), &matchingservice.PollActivityTaskQueueRequest{
+			NamespaceId: namespaceID.String(),
+			PollRequest: &workflowservice.PollActivityTaskQueueRequest{
+				TaskQueue: taskQueue,
+				Identity:  "nobody",
+			},
+		}, metrics.NoopMetricsHandler)
+		s.NoError(err)
+	}
+
+	// quiet tasks are interleaved with the noisy backlog instead of waiting behind it
+	s.Contains(dispatchedWorkflowIDs[:6], "quiet")
+	s.NotContains(dispatchedWorkflowIDs[6:], "quiet")
+}
+
+func (s *matchingEngineSuite) TestAddThenConsumeActivities_FairnessSkipsAhead() {
+	s.matchingEngine.config.LongPollExpirationInterval = dynamicconfig.GetDurationPropertyFnFilteredByTaskQueueInfo(10 * time.Millisecond)
+	s.matchingEngine.config.FairnessKeySource = dynamicconfig.GetStringPropertyFnFilteredByNamespace("workflowId")
+	s.matchingEngine.config.FairnessBacklogBufferSize = dynamicconfig.GetIntPropertyFilteredByTaskQueueInfo(4)
+	s.matchingEngine.config.FairnessMaxBufferedTasksPerKey = dynamicconfig.GetIntPropertyFilteredByTaskQueueInfo(2)
+
+	const noisyTaskCount = 20
+	const quietTaskCount = 2
+
+	namespaceID := namespace.ID(uuid.New())
+	taskQueue := &taskqueuepb.TaskQueue{
+		Name: "fairTaskQueue",
+		Kind: enumspb.TASK_QUEUE_KIND_NORMAL,
+	}
+	tlID := newTestTaskQueueID(namespaceID, taskQueue.Name, enumspb.TASK_QUEUE_TYPE_ACTIVITY)
+	addTask := func(workflowID string, scheduledEventID int64) {
+		_, err := s.matchingEngine.AddActivityTask(context.Background(), &matchingservice.AddActivityTaskRequest{
+			NamespaceId:            namespaceID.String(),
+			Execution:              &commonpb.WorkflowExecution{RunId: uuid.NewRandom().String(), WorkflowId: workflowID},
+			ScheduledEventId:       scheduledEventID,
+			TaskQueue:              taskQueue,
+			ScheduleToStartTimeout: timestamp.DurationFromSeconds(100),
+			FairnessKey:            workflowID,
+		})
+		s.NoError(err)
+	}
+	for i := int64(0); i < noisyTaskCount; i++ {
+		addTask("noisy", i)
+	}
+	for i := int64(0); i < quietTaskCount; i++ {
+		addTask("quiet", i)
+	}
+
+	describeRequest := &matchingservice.DescribeTaskQueueRequest{
+		NamespaceId: namespaceID.String(),
+		DescRequest: &workflowservice.DescribeTaskQueueRequest{
+			TaskQueue:              taskQueue,
+			TaskQueueType:          enumspb.TASK_QUEUE_TYPE_ACTIVITY,
+			IncludeTaskQueueStatus: true,
+		},
+	}
+	s.Eventually(func() bool {
+		descResp, err := s.matchingEngine.DescribeTaskQueue(context.Background(), describeRequest)
+		s.NoError(err)
+		for _, backlog := range descResp.GetFairnessKeyBacklogs() {
+			if backlog.GetFairnessKey() == "quiet" {
+				// the quiet tasks are loaded although the noisy backlog is larger than the buffer
+				return true
+			}
+		}
+		return false
+	}, 5*time.Second, 10*time.Millisecond)
+
+	var dispatchedWorkflowIDs []string
+	s.mockHistoryClient.EXPECT().RecordActivityTaskStarted(gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(
+		func(ctx context.Context, taskRequest *historyservice.RecordActivityTaskStartedRequest, arg2 ...interface{}) (*historyservice.RecordActivityTaskStartedResponse, error) {
+			dispatchedWorkflowIDs = append(dispatchedWorkflowIDs, taskRequest.WorkflowExecution.GetWorkflowId())
+			return &historyservice.RecordActivityTaskStartedResponse{
+				Attempt: 1,
+				ScheduledEvent: newActivityTaskScheduledEvent(taskRequest.ScheduledEventId, 0,
+					&commandpb.ScheduleActivityTaskCommandAttributes{
+						ActivityId:   "activity",
+						TaskQueue:    taskQueue,
+						ActivityType: &commonpb.ActivityType{Name: "activity"},
+					}),
+				StartedTime: timestamp.TimeNowPtrUtc(),
+			}, nil
+		}).AnyTimes()
+
+	for len(dispatchedWorkflowIDs) < noisyTaskCount+quietTaskCount {
+		_, err := s.matchingEngine.PollActivityTaskQueue(context.Background(), &matchingservice.PollActivityTaskQueueRequest{
+			NamespaceId: namespaceID.String(),
+			PollRequest: &workflowservice.PollActivityTaskQueueRequest{
+				TaskQueue: taskQueue,
+				Identity:  "nobody",
+			},
+		}, metrics.NoopMetricsHandler)
+		s.NoError(err)
+	}
+
+	s.Contains(dispatchedWorkflowIDs[:5], "quiet")
+	s.NotContains(dispatchedWorkflowIDs[5:], "quiet")
+	// the skipped noisy tasks were read again and completed, so nothing is left behind the ack level
+	s.Eventually(func() bool {
+		return s.taskManager.getTaskCount(tlID) == 0
+	}, 5*time.Second, 10*time.Millisecond)
+}
+
 func (s *matchingEngineSuite) TestSyncMatchActivities() {
 	// Set a short long poll expiration so that we don't have to wait too long for 0 throttling cases
 	s.matchingEngine.config.LongPollExpirationInterval = dynamicconfig.GetDurationPropertyFnFilteredByTaskQueueInfo(2 * time.Second)
@@ -1105,6 +1282,75 @@ func (s *matchingEngineSuite) TestSyncMatchActivities() {
 	s.True(descResp.GetTaskQueueStatus().GetRatePerSecond()*numPartitions >= (defaultTaskDispatchRPS - 1))
 }
 
+func (s *matchingEngineSuite) TestDescribeTaskQueue_AggregatesPartitionBacklogs() {
+	// use a separate matching client to control the responses of the other partitions
+	matchingClient := matchingservicemock.NewMockMatchingServiceClient(s.controller)
+	matchingClient.EXPECT().GetTaskQueueUserData(gomock.Any(), gomock.Any()).
+		Return(&matchingservice.GetTaskQueueUserDataResponse{}, nil).AnyTimes()
+	config := defaultTestConfig()
+	config.NumTaskqueueReadPartitions = dynamicconfig.GetIntPropertyFilteredByTaskQueueInfo(4)
+	config.NumTaskqueueWritePartitions = dynamicconfig.GetIntPropertyFilteredByTaskQueueInfo(3)
+	engine := newMatchingEngine(config, s.taskManager, s.mockHistoryClient, s.logger, s.mockNamespaceCache, matchingClient, s.mockVisibilityManager)
+	engine.Start()
+	defer engine.Stop()
+
+	namespaceID := namespace.ID(uuid.New())
+	taskQueue := &taskqueuepb.TaskQueue{Name: "makeToast", Kind: enumspb.TASK_QUEUE_KIND_NORMAL}
+	for i := 0; i < 2; i++ {
+		_, err := engine.AddActivityTask(context.Background(), &matchingservice.AddActivityTaskRequest{
+			NamespaceId:            namespaceID.String(),
+			Execution:              &commonpb.WorkflowExecution{RunId: uuid.NewRandom().String(), WorkflowId: "workflow1"},
+			ScheduledEventId:       int64(i),
+			TaskQueue:              taskQueue,
+			ScheduleToStartTimeout: timestamp.DurationFromSeconds(100),
+		})
+		s.NoError(err)
+	}
+
+	tqID := newTestTaskQueueID(namespaceID, taskQueue.Name, enumspb.TASK_QUEUE_TYPE_ACTIVITY)
+	partitionBacklogs := map[string]*taskqueue.TaskQueueBacklog{
+		tqID.WithPartition(1).FullName(): {ApproximateBacklogCount: 5, ApproximateBacklogAge: durationpb.New(time.Hour)},
+		tqID.WithPartition(2).FullName(): {ApproximateBacklogCount: 7, ApproximateBacklogAge: durationpb.New(time.Minute)},
+	}
+	matchingClient.EXPECT().DescribeTaskQueue(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ context.Context, request *matchingservice.DescribeTaskQueueRequest, _ ...interface{}) (*matchingservice.DescribeTaskQueueResponse, error) {
+			s.True(request.DescRequest.GetIncludeTaskQueueStatus())
+			s.Equal(enumspb.TASK_QUEUE_TYPE_ACTIVITY, request.DescRequest.GetTaskQueueType())
+			backlog, ok := partitionBacklogs[request.DescRequest.TaskQueue.GetName()]
+			if !ok {
+				return nil, serviceerror.NewUnavailable("partition unavailable")
+			}
+			return &matchingservice.DescribeTaskQueueResponse{Backlog: backlog}, nil
+		}).Times(3)
+
+	descResp, err := engine.DescribeTaskQueue(context.Background(), &matchingservice.DescribeTaskQueueRequest{
+		NamespaceId: namespaceID.String(),
+		DescRequest: &workflowservice.DescribeTaskQueueRequest{
+			TaskQueue:              taskQueue,
+			TaskQueueType:          enumspb.TASK_QUEUE_TYPE_ACTIVITY,
+			IncludeTaskQueueStatus: true,
+		},
+	})
+	s.NoError(err)
+	s.Equal(int64(2+5+7), descResp.GetBacklog().GetApproximateBacklogCount())
+	s.Equal(time.Hour, descResp.GetBacklog().GetApproximateBacklogAge().AsDuration())
+	s.Len(descResp.GetPartitionBacklogs(), 3)
+	s.Equal(int64(2), descResp.GetPartitionBacklogs()[tqID.FullName()].GetApproximateBacklogCount())
+
+	// only the root partition aggregates
+	descResp, err = engine.DescribeTaskQueue(context.Background(), &matchingservice.DescribeTaskQueueRequest{
+		NamespaceId: namespaceID.String(),
+		DescRequest: &workflowservice.DescribeTaskQueueRequest{
+			TaskQueue:              &taskqueuepb.TaskQueue{Name: tqID.WithPartition(1).FullName(), Kind: enumspb.TASK_QUEUE_KIND_NORMAL},
+			TaskQueueType:          enumspb.TASK_QUEUE_TYPE_ACTIVITY,
+			IncludeTaskQueueStatus: true,
+		},
+	})
+	s.NoError(err)
+	s.Zero(descResp.GetBacklog().GetApproximateBacklogCount())
+	s.Empty(descResp.GetPartitionBacklogs())
+}
+
 func (s *matchingEngineSuite) TestConcurrentPublishConsumeActivities() {
 	dispatchLimitFn := func(int, int64) float64 {
 		return defaultTaskDispatchRPS
@@ -1813,6 +2059,203 @@ func (s *matchingEngineSuite) TestAddTaskAfterStartFailure() {
 	s.EqualValues(0, s.taskManager.getTaskCount(tlID))
 }
 
+func (s *matchingEngineSuite) TestPauseAndResumeTaskQueue() {
+	s.matchingEngine.config.LongPollExpirationInterval = dynamicconfig.GetDurationPropertyFnFilteredByTaskQueueInfo(10 * time.Second)
+
+	namespaceID := namespace.ID(uuid.New())
+	tl := "makeToast"
+	tlID := newTestTaskQueueID(namespaceID, tl, enumspb.TASK_QUEUE_TYPE_WORKFLOW)
+	taskQueue := &taskqueuepb.TaskQueue{Name: tl, Kind: enumspb.TASK_QUEUE_KIND_NORMAL}
+
+	setPaused := func(paused bool) {
+		_, err := s.matchingEngine.UpdateTaskQueuePauseState(context.Background(), &matchingservice.UpdateTaskQueuePauseStateRequest{
+			NamespaceId:   namespaceID.String(),
+			TaskQueue:     tl,
+			TaskQueueType: enumspb.TASK_QUEUE_TYPE_WORKFLOW,
+			Paused:        paused,
+			Reason:        "maintenance",
+			Identity:      "operator",
+		})
+		s.NoError(err)
+	}
+
+	// a poll in flight when the task queue is paused returns without a task
+	pollResult := make(chan error, 1)
+	go func() {
+		pollCtx := context.WithValue(context.Background(), identityKey, "worker")
+		_, err := s.matchingEngine.pollTask(pollCtx, tlID, normalStickyInfo, &pollMetadata{})
+		pollResult <- err
+	}()
+	tlMgr, err := s.matchingEngine.getTaskQueueManager(context.Background(), tlID, normalStickyInfo, true)
+	s.NoError(err)
+	s.Eventually(func() bool {
+		return len(tlMgr.GetAllPollerInfo()) > 0
+	}, 5*time.Second, time.Millisecond)
+	setPaused(true)
+	select {
+	case err := <-pollResult:
+		s.ErrorIs(err, errNoTasks)
+	case <-time.After(5 * time.Second):
+		s.FailNow("poll was not interrupted by pause")
+	}
+
+	// tasks added while paused are persisted and not dispatched
+	_, err = s.matchingEngine.AddWorkflowTask(context.Background(), &matchingservice.AddWorkflowTaskRequest{
+		NamespaceId:            namespaceID.String(),
+		Execution:              &commonpb.WorkflowExecution{RunId: uuid.NewRandom().String(), WorkflowId: "workflow1"},
+		ScheduledEventId:       0,
+		TaskQueue:              taskQueue,
+		ScheduleToStartTimeout: timestamp.DurationFromSeconds(100),
+	})
+	s.NoError(err)
+	s.EqualValues(1, s.taskManager.getTaskCount(tlID))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+	defer cancel()
+	_, err = s.matchingEngine.pollTask(ctx, tlID, normalStickyInfo, &pollMetadata{})
+	s.ErrorIs(err, errNoTasks)
+
+	// resuming dispatches the backlog
+	setPaused(false)
+	task, err := s.matchingEngine.pollTask(context.Background(), tlID, normalStickyInfo, &pollMetadata{})
+	s.NoError(err)
+	task.finish(nil)
+	s.EqualValues(0, s.taskManager.getTaskCount(tlID))
+}
+
+func (s *matchingEngineSuite) TestPauseTaskQueueWithStickyTasks() {
+	s.matchingEngine.config.LongPollExpirationInterval = dynamicconfig.GetDurationPropertyFnFilteredByTaskQueueInfo(10 * time.Second)
+	// sticky queues get the user data of their normal queue from the engine
+	s.matchingEngine.matchingRawClient = userDataFromEngineClient{
+		MatchingServiceClient: s.mockMatchingClient,
+		engine:                s.matchingEngine,
+	}
+
+	namespaceID := namespace.ID(uuid.New())
+	tl := "makeToast"
+	stickyTl := "makeToast-sticky"
+	stickyID := newTestTaskQueueID(namespaceID, stickyTl, enumspb.TASK_QUEUE_TYPE_WORKFLOW)
+	stickyTaskQueue := &taskqueuepb.TaskQueue{Name: stickyTl, Kind: enumspb.TASK_QUEUE_KIND_STICKY, NormalName: tl}
+	stickyInfo := stickyInfoFromTaskQueue(stickyTaskQueue)
+
+	setPaused := func(paused bool) {
+		_, err := s.matchingEngine.UpdateTaskQueuePauseState(context.Background(), &matchingservice.UpdateTaskQueuePauseStateRequest{
+			NamespaceId:   namespaceID.String(),
+			TaskQueue:     tl,
+			TaskQueueType: enumspb.TASK_QUEUE_TYPE_WORKFLOW,
+			Paused:        paused,
+			Reason:        "maintenance",
+			Identity:      "operator",
+		})
+		s.NoError(err)
+	}
+	setPaused(true)
+
+	// a sticky poller doesn't get tasks of a paused task queue
+	ctx, cancel := context.WithTimeout(context.WithValue(context.Background(), identityKey, "worker"), 200*time.Millisecond)
+	defer cancel()
+	_, err := s.matchingEngine.pollTask(ctx, stickyID, stickyInfo, &pollMetadata{})
+	s.ErrorIs(err, errNoTasks)
+
+	// sticky workflow tasks added while paused are persisted and not dispatched
+	_, err = s.matchingEngine.AddWorkflowTask(context.Background(), &matchingservice.AddWorkflowTaskRequest{
+		NamespaceId:            namespaceID.String(),
+		Execution:              &commonpb.WorkflowExecution{RunId: uuid.NewRandom().String(), WorkflowId: "workflow1"},
+		ScheduledEventId:       0,
+		TaskQueue:              stickyTaskQueue,
+		ScheduleToStartTimeout: timestamp.DurationFromSeconds(100),
+	})
+	s.NoError(err)
+	s.EqualValues(1, s.taskManager.getTaskCount(stickyID))
+
+	ctx, cancel = context.WithTimeout(context.Background(), 200*time.Millisecond)
+	defer cancel()
+	_, err = s.matchingEngine.pollTask(ctx, stickyID, stickyInfo, &pollMetadata{})
+	s.ErrorIs(err, errNoTasks)
+
+	// resuming the normal queue dispatches the sticky backlog
+	setPaused(false)
+	task, err := s.matchingEngine.pollTask(context.Background(), stickyID, stickyInfo, &pollMetadata{})
+	s.NoError(err)
+	task.finish(nil)
+	s.EqualValues(0, s.taskManager.getTaskCount(stickyID))
+}
+
+func (s *matchingEngineSuite) TestStickyQueueReloadedWithNormalQueueName() {
+	namespaceID := namespace.ID(uuid.New())
+	stickyID := newTestTaskQueueID(namespaceID, "makeToast-sticky", enumspb.TASK_QUEUE_TYPE_WORKFLOW)
+
+	// older SDKs poll sticky queues without the name of the normal queue
+	tqm, err := s.matchingEngine.getTaskQueueManager(context.Background(), stickyID,
+		stickyInfo{kind: enumspb.TASK_QUEUE_KIND_STICKY}, true)
+	s.NoError(err)
+	s.Empty(tqm.(*taskQueueManagerImpl).normalName)
+
+	// the sticky queue is loaded again once a request has the name, so that it gets the user data
+	// of the normal queue
+	reloaded, err := s.matchingEngine.getTaskQueueManager(context.Background(), stickyID,
+		stickyInfo{kind: enumspb.TASK_QUEUE_KIND_STICKY, normalName: "makeToast"}, false)
+	s.NoError(err)
+	s.NotSame(tqm, reloaded)
+	s.Equal("makeToast", reloaded.(*taskQueueManagerImpl).normalName)
+}
+
+func (s *matchingEngineSuite) TestActivityTypeRateLimit() {
+	var throttledRPS atomic.Value
+	throttledRPS.Store(0.0)
+	s.matchingEngine.config.ActivityTypeDispatchRPS = func(string, string, enumspb.TaskQueueType) map[string]any {
+		return map[string]any{"throttled": throttledRPS.Load()}
+	}
+	// the throttled type's tasks don't fit in the buffer, so some of them are deferred and read again
+	s.matchingEngine.config.ThrottledBacklogBufferSize = dynamicconfig.GetIntPropertyFilteredByTaskQueueInfo(1)
+
+	namespaceID := namespace.ID(uuid.New())
+	tl := "makeToast"
+	tlID := newTestTaskQueueID(namespaceID, tl, enumspb.TASK_QUEUE_TYPE_ACTIVITY)
+	taskQueue := &taskqueuepb.TaskQueue{Name: tl, Kind: enumspb.TASK_QUEUE_KIND_NORMAL}
+
+	for i := 0; i < 6; i++ {
+		activityTypeName := "throttled"
+		if i%2 == 1 {
+			activityTypeName = "other"
+		}
+		_, err := s.matchingEngine.AddActivityTask(context.Background(), &matchingservice.AddActivityTaskRequest{
+			NamespaceId:            namespaceID.String(),
+			Execution:              &commonpb.WorkflowExecution{RunId: uuid.NewRandom().String(), WorkflowId: "workflow1"},
+			ScheduledEventId:       int64(i),
+			TaskQueue:              taskQueue,
+			ScheduleToStartTimeout: timestamp.DurationFromSeconds(100),
+			ActivityTypeName:       activityTypeName,
+		})
+		s.NoError(err)
+	}
+	s.EqualValues(6, s.taskManager.getTaskCount(tlID))
+
+	pollActivityTypes := func(count int) []string {
+		var activityTypeNames []string
+		deadline := time.Now().Add(5 * time.Second)
+		for len(activityTypeNames) < count && time.Now().Before(deadline) {
+			ctx, cancel := context.WithTimeout(context.Background(), returnEmptyTaskTimeBudget+time.Second)
+			task, err := s.matchingEngine.pollTask(ctx, tlID, normalStickyInfo, &pollMetadata{})
+			cancel()
+			if err == errNoTasks {
+				continue
+			}
+			s.NoError(err)
+			activityTypeNames = append(activityTypeNames, task.event.Data.GetActivityTypeName())
+			task.finish(nil)
+		}
+		return activityTypeNames
+	}
+
+	// the throttled type doesn't block the rest of the backlog
+	s.Equal([]string{"other", "other", "other"}, pollActivityTypes(3))
+
+	// its tasks are dispatched once its rate allows
+	throttledRPS.Store(1000.0)
+	s.Equal([]string{"throttled", "throttled", "throttled"}, pollActivityTypes(3))
+}
+
 func (s *matchingEngineSuite) TestTaskQueueManagerGetTaskBatch() {
 	runID := uuid.NewRandom().String()
 	workflowID := "workflow1"
@@ -2788,6 +3231,7 @@ type testTaskQueueManager struct {
 	sync.Mutex
 	rangeID          int64
 	ackLevel         int64
+	backlogCount     int64
 	createTaskCount  int
 	getTasksCount    int
 	getUserDataCount int
@@ -2827,12 +3271,22 @@ func newTestTaskQueueID(namespaceID namespace.ID, name string, taskType enumspb.
 	return result
 }
 
+// persistedTestTaskQueueID returns the id of a task queue as it is named in persistence, which includes
+// the backlog subqueues of task priorities.
+func persistedTestTaskQueueID(namespaceID namespace.ID, name string, taskType enumspb.TaskQueueType) *taskQueueID {
+	result, err := tqname.Parse(name)
+	if err != nil {
+		panic(fmt.Sprintf("tqname.Parse failed with error %v", err))
+	}
+	return &taskQueueID{Name: result, namespaceID: namespaceID, taskType: taskType}
+}
+
 func (m *testTaskManager) CreateTaskQueue(
 	_ context.Context,
 	request *persistence.CreateTaskQueueRequest,
 ) (*persistence.CreateTaskQueueResponse, error) {
 	tli := request.TaskQueueInfo
-	tlm := m.getTaskQueueManager(newTestTaskQueueID(namespace.ID(tli.GetNamespaceId()), tli.Name, tli.TaskType))
+	tlm := m.getTaskQueueManager(persistedTestTaskQueueID(namespace.ID(tli.GetNamespaceId()), tli.Name, tli.TaskType))
 	tlm.Lock()
 	defer tlm.Unlock()
 
@@ -2844,6 +3298,7 @@ func (m *testTaskManager) CreateTaskQueue(
 
 	tlm.rangeID = request.RangeID
 	tlm.ackLevel = tli.AckLevel
+	tlm.backlogCount = tli.ApproximateBacklogCount
 	return &persistence.CreateTaskQueueResponse{}, nil
 }
 
@@ -2853,7 +3308,7 @@ func (m *testTaskManager) UpdateTaskQueue(
 	request *persistence.UpdateTaskQueueRequest,
 ) (*persistence.UpdateTaskQueueResponse, error) {
 	tli := request.TaskQueueInfo
-	tlm := m.getTaskQueueManager(newTestTaskQueueID(namespace.ID(tli.GetNamespaceId()), tli.Name, tli.TaskType))
+	tlm := m.getTaskQueueManager(persistedTestTaskQueueID(namespace.ID(tli.GetNamespaceId()), tli.Name, tli.TaskType))
 	tlm.Lock()
 	defer tlm.Unlock()
 	tlm.updateCount++
@@ -2864,6 +3319,7 @@ func (m *testTaskManager) UpdateTaskQueue(
 		}
 	}
 	tlm.ackLevel = tli.AckLevel
+	tlm.backlogCount = tli.ApproximateBacklogCount
 	tlm.rangeID = request.RangeID
 	return &persistence.UpdateTaskQueueResponse{}, nil
 }
@@ -2872,7 +3328,7 @@ func (m *testTaskManager) GetTaskQueue(
 	_ context.Context,
 	request *persistence.GetTaskQueueRequest,
 ) (*persistence.GetTaskQueueResponse, error) {
-	tlm := m.getTaskQueueManager(newTestTaskQueueID(namespace.ID(request.NamespaceID), request.TaskQueue, request.TaskType))
+	tlm := m.getTaskQueueManager(persistedTestTaskQueueID(namespace.ID(request.NamespaceID), request.TaskQueue, request.TaskType))
 	tlm.Lock()
 	defer tlm.Unlock()
 
@@ -2888,6 +3344,8 @@ func (m *testTaskManager) GetTaskQueue(
 			AckLevel:       tlm.ackLevel,
 			ExpiryTime:     nil,
 			LastUpdateTime: timestamp.TimeNowPtrUtc(),
+
+			ApproximateBacklogCount: tlm.backlogCount,
 		},
 		RangeID: tlm.rangeID,
 	}, nil
@@ -2904,7 +3362,7 @@ func (m *testTaskManager) CompleteTask(
 	}
 
 	tli := request.TaskQueue
-	tlm := m.getTaskQueueManager(newTestTaskQueueID(namespace.ID(tli.NamespaceID), tli.TaskQueueName, tli.TaskQueueType))
+	tlm := m.getTaskQueueManager(persistedTestTaskQueueID(namespace.ID(tli.NamespaceID), tli.TaskQueueName, tli.TaskQueueType))
 
 	tlm.Lock()
 	defer tlm.Unlock()
@@ -2917,7 +3375,7 @@ func (m *testTaskManager) CompleteTasksLessThan(
 	_ context.Context,
 	request *persistence.CompleteTasksLessThanRequest,
 ) (int, error) {
-	tlm := m.getTaskQueueManager(newTestTaskQueueID(namespace.ID(request.NamespaceID), request.TaskQueueName, request.TaskType))
+	tlm := m.getTaskQueueManager(persistedTestTaskQueueID(namespace.ID(request.NamespaceID), request.TaskQueueName, request.TaskType))
 	tlm.Lock()
 	defer tlm.Unlock()
 	keys := tlm.tasks.Keys()
@@ -2943,7 +3401,7 @@ func (m *testTaskManager) DeleteTaskQueue(
 ) error {
 	m.Lock()
 	defer m.Unlock()
-	key := newTestTaskQueueID(namespace.ID(request.TaskQueue.NamespaceID), request.TaskQueue.TaskQueueName, request.TaskQueue.TaskQueueType)
+	key := persistedTestTaskQueueID(namespace.ID(request.TaskQueue.NamespaceID), request.TaskQueue.TaskQueueName, request.TaskQueue.TaskQueueType)
 	delete(m.taskQueues, *key)
 	return nil
 }
@@ -2958,7 +3416,7 @@ func (m *testTaskManager) CreateTasks(
 	taskType := request.TaskQueueInfo.Data.TaskType
 	rangeID := request.TaskQueueInfo.RangeID
 
-	tlm := m.getTaskQueueManager(newTestTaskQueueID(namespaceID, taskQueue, taskType))
+	tlm := m.getTaskQueueManager(persistedTestTaskQueueID(namespaceID, taskQueue, taskType))
 	tlm.Lock()
 	defer tlm.Unlock()
 
@@ -3003,7 +3461,7 @@ func (m *testTaskManager) GetTasks(
 ) (*persistence.GetTasksResponse, error) {
 	m.logger.Debug("testTaskManager.GetTasks", tag.MinLevel(request.InclusiveMinTaskID), tag.MaxLevel(request.ExclusiveMaxTaskID))
 
-	tlm := m.getTaskQueueManager(newTestTaskQueueID(namespace.ID(request.NamespaceID), request.TaskQueue, request.TaskType))
+	tlm := m.getTaskQueueManager(persistedTestTaskQueueID(namespace.ID(request.NamespaceID), request.TaskQueue, request.TaskType))
 	tlm.Lock()
 	defer tlm.Unlock()
 	var tasks []*persistencespb.AllocatedTaskInfo
@@ -3092,7 +3550,7 @@ func (m *testTaskManager) String() string {
 
 // GetTaskQueueData implements persistence.TaskManager
 func (m *testTaskManager) GetTaskQueueUserData(_ context.Context, request *persistence.GetTaskQueueUserDataRequest) (*persistence.GetTaskQueueUserDataResponse, error) {
-	tlm := m.getTaskQueueManager(newTestTaskQueueID(namespace.ID(request.NamespaceID), request.TaskQueue, enumspb.TASK_QUEUE_TYPE_WORKFLOW))
+	tlm := m.getTaskQueueManager(persistedTestTaskQueueID(namespace.ID(request.NamespaceID), request.TaskQueue, enumspb.TASK_QUEUE_TYPE_WORKFLOW))
 	tlm.Lock()
 	defer tlm.Unlock()
 	tlm.getUserDataCount++
@@ -3103,7 +3561,7 @@ func (m *testTaskManager) GetTaskQueueUserData(_ context.Context, request *persi
 
 // UpdateTaskQueueUserData implements persistence.TaskManager
 func (m *testTaskManager) UpdateTaskQueueUserData(_ context.Context, request *persistence.UpdateTaskQueueUserDataRequest) error {
-	tlm := m.getTaskQueueManager(newTestTaskQueueID(namespace.ID(request.NamespaceID), request.TaskQueue, enumspb.TASK_QUEUE_TYPE_WORKFLOW))
+	tlm := m.getTaskQueueManager(persistedTestTaskQueueID(namespace.ID(request.NamespaceID), request.TaskQueue, enumspb.TASK_QUEUE_TYPE_WORKFLOW))
 	tlm.Lock()
 	defer tlm.Unlock()
 	newData := common.CloneProto(request.UserData)
@@ -3172,3 +3630,17 @@ func (d *dynamicRateBurstWrapper) Rate() float64 {
 func (d *dynamicRateBurstWrapper) Burst() int {
 	return d.RateLimiterImpl.Burst()
 }
+
+// userDataFromEngineClient serves the user data of task queues from the matching engine.
+type userDataFromEngineClient struct {
+	matchingservice.MatchingServiceClient
+	engine *matchingEngineImpl
+}
+
+func (c userDataFromEngineClient) GetTaskQueueUserData(
+	ctx context.Context,
+	request *matchingservice.GetTaskQueueUserDataRequest,
+	_ ...grpc.CallOption,
+) (*matchingservice.GetTaskQueueUserDataResponse, error) {
+	return c.engine.GetTaskQueueUserData(ctx, request)
+}
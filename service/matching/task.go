@@ -25,11 +25,14 @@
 package matching
 
 import (
+	"time"
+
 	commonpb "go.temporal.io/api/common/v1"
 
 	enumsspb "go.temporal.io/server/api/enums/v1"
 	"go.temporal.io/server/api/matchingservice/v1"
 	persistencespb "go.temporal.io/server/api/persistence/v1"
+	"go.temporal.io/server/common"
 	"go.temporal.io/server/common/namespace"
 )
 
@@ -168,3 +171,29 @@ func (task *internalTask) finish(err error) {
 		task.event.completionFunc(task.event.AllocatedTaskInfo, err)
 	}
 }
+
+// taskPriority returns the dispatch priority of the task, or the default priority when it doesn't set one.
+func taskPriority(data *persistencespb.TaskInfo) int32 {
+	priority := data.GetPriority()
+	if priority == 0 {
+		return common.DefaultTaskPriority
+	}
+	return max(common.HighestTaskPriority, min(priority, common.LowestTaskPriority))
+}
+
+// agedTaskPriority returns the dispatch priority of the task raised by one level for every agingInterval
+// the task has waited since it was created. Aging is disabled when agingInterval is not positive.
+func agedTaskPriority(data *persistencespb.TaskInfo, agingInterval time.Duration, now time.Time) int32 {
+	priority := taskPriority(data)
+	if agingInterval <= 0 || data.GetCreateTime() == nil {
+		return priority
+	}
+	levels := now.Sub(data.GetCreateTime().AsTime()) / agingInterval
+	if levels <= 0 {
+		return priority
+	}
+	if levels >= time.Duration(priority-common.HighestTaskPriority) {
+		return common.HighestTaskPriority
+	}
+	return priority - int32(levels)
+}
@@ -29,6 +29,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"sort"
 	"sync/atomic"
 	"time"
 
@@ -38,6 +39,7 @@ import (
 	enumspb "go.temporal.io/api/enums/v1"
 	"go.temporal.io/api/serviceerror"
 	taskqueuepb "go.temporal.io/api/taskqueue/v1"
+	"google.golang.org/protobuf/types/known/durationpb"
 
 	enumsspb "go.temporal.io/server/api/enums/v1"
 	"go.temporal.io/server/api/matchingservice/v1"
@@ -47,6 +49,7 @@ import (
 	"go.temporal.io/server/common/backoff"
 	"go.temporal.io/server/common/cluster"
 	"go.temporal.io/server/common/debug"
+	"go.temporal.io/server/common/dynamicconfig"
 	"go.temporal.io/server/common/future"
 	"go.temporal.io/server/common/headers"
 	"go.temporal.io/server/common/log"
@@ -137,6 +140,9 @@ type (
 		DispatchQueryTask(ctx context.Context, taskID string, request *matchingservice.QueryWorkflowRequest) (*matchingservice.QueryWorkflowResponse, error)
 		// GetUserData returns the versioned user data for this task queue
 		GetUserData() (*persistencespb.VersionedTaskQueueUserData, chan struct{}, error)
+		// IsPaused returns whether dispatch from this task queue is paused by an operator, and a
+		// channel that is closed when the pause state may have changed.
+		IsPaused() (bool, chan struct{})
 		// UpdateUserData updates user data for this task queue and replicates across clusters if necessary.
 		// Extra care should be taken to avoid mutating the existing data in the update function.
 		UpdateUserData(ctx context.Context, options UserDataUpdateOptions, updateFn UserDataUpdateFunc) error
@@ -165,8 +171,9 @@ type (
 		taskReader           *taskReader // reads tasks from db and async matches it with poller
 		liveness             *liveness
 		taskGC               *taskGC
-		taskAckManager       ackManager   // tracks ackLevel for delivered messages
-		matcher              *TaskMatcher // for matching a task producer with a poller
+		taskAckManager       ackManager         // tracks ackLevel for delivered messages
+		matcher              *TaskMatcher       // for matching a task producer with a poller
+		backlogAge           *backlogAgeTracker // creation times of loaded backlog tasks
 		namespaceRegistry    namespace.Registry
 		logger               log.Logger
 		throttledLogger      log.ThrottledLogger
@@ -186,6 +193,14 @@ type (
 		// skipFinalUpdate controls behavior on Stop: if it's false, we try to write one final
 		// update before unloading
 		skipFinalUpdate atomic.Bool
+		// cancelRatelimitSubscription stops applying changes of the admin dispatch rate to the matcher
+		cancelRatelimitSubscription dynamicconfig.SubscriptionCancelFn
+		// prioritySubqueues persist and read the backlog tasks of the non-default task priorities, most urgent
+		// first. Nil when task priorities are disabled, then all tasks are in the backlog of this manager.
+		prioritySubqueues []*taskQueueManagerImpl
+		// parent is the manager of the task queue when this manager only handles the backlog of one of its
+		// priority subqueues, nil otherwise.
+		parent *taskQueueManagerImpl
 	}
 )
 
@@ -254,6 +269,7 @@ func newTaskQueueManager(
 		throttledLogger:      throttledLogger,
 		db:                   db,
 		taskAckManager:       newAckManager(e.logger),
+		backlogAge:           newBacklogAgeTracker(),
 		taskGC:               newTaskGC(db, taskQueueConfig),
 		config:               taskQueueConfig,
 		namespace:            nsName,
@@ -282,14 +298,62 @@ func newTaskQueueManager(
 		fwdr = newForwarder(&taskQueueConfig.forwarderConfig, forwardTaskQueue, stickyInfo.kind, e.matchingRawClient)
 	}
 	tlMgr.matcher = newTaskMatcher(taskQueueConfig, fwdr, tlMgr.taggedMetricsHandler)
+	if taskQueueConfig.EnableTaskPriority() {
+		for priority := common.HighestTaskPriority; priority <= common.LowestTaskPriority; priority++ {
+			if priority != common.DefaultTaskPriority {
+				tlMgr.prioritySubqueues = append(tlMgr.prioritySubqueues, newPrioritySubqueueManager(tlMgr, priority))
+			}
+		}
+	}
 	for _, opt := range opts {
 		opt(tlMgr)
 	}
 	return tlMgr, nil
 }
 
+// newPrioritySubqueueManager returns a manager for the backlog subqueue of the given task priority of
+// the task queue managed by parent. It persists, reads and dispatches the backlog tasks of the priority
+// through the matcher of parent. Pollers, user data and liveness are left to parent, which starts and
+// stops it.
+func newPrioritySubqueueManager(parent *taskQueueManagerImpl, priority int32) *taskQueueManagerImpl {
+	e := parent.engine
+	db := newTaskQueueDB(e.taskManager, e.matchingRawClient, parent.taskQueueID.namespaceID,
+		newTaskQueueIDWithPriority(parent.taskQueueID, priority), parent.stickyInfo.kind, e.logger)
+	subqueue := &taskQueueManagerImpl{
+		status:               common.DaemonStatusInitialized,
+		engine:               e,
+		namespaceRegistry:    parent.namespaceRegistry,
+		matchingClient:       parent.matchingClient,
+		metricsHandler:       parent.metricsHandler,
+		clusterMeta:          parent.clusterMeta,
+		taskQueueID:          parent.taskQueueID,
+		stickyInfo:           parent.stickyInfo,
+		logger:               log.With(parent.logger, tag.TaskPriority(priority)),
+		throttledLogger:      log.With(parent.throttledLogger, tag.TaskPriority(priority)),
+		db:                   db,
+		taskAckManager:       newAckManager(e.logger),
+		backlogAge:           newBacklogAgeTracker(),
+		taskGC:               newTaskGC(db, parent.config),
+		matcher:              parent.matcher,
+		config:               parent.config,
+		namespace:            parent.namespace,
+		taggedMetricsHandler: parent.taggedMetricsHandler,
+		initializedError:     future.NewFuture[struct{}](),
+		userDataReady:        parent.userDataReady,
+		parent:               parent,
+	}
+	subqueue.taskWriter = newTaskWriter(subqueue)
+	subqueue.taskReader = newTaskReader(subqueue)
+	return subqueue
+}
+
 // unloadFromEngine asks the MatchingEngine to unload this task queue. It will cause Stop to be called.
 func (c *taskQueueManagerImpl) unloadFromEngine() {
+	if c.parent != nil {
+		// a priority subqueue can't be reloaded on its own
+		c.parent.unloadFromEngine()
+		return
+	}
 	c.engine.unloadTaskQueue(c)
 }
 
@@ -319,9 +383,12 @@ func (c *taskQueueManagerImpl) Start() {
 	) {
 		return
 	}
+	c.cancelRatelimitSubscription = c.config.SubscribeAdminNamespaceTaskQueueToPartitionDispatchRate(c.matcher.RefreshAdminRatelimit)
 	c.liveness.Start()
-	c.taskWriter.Start()
-	c.taskReader.Start()
+	for _, backlog := range c.backlogs() {
+		backlog.taskWriter.Start()
+		backlog.taskReader.Start()
+	}
 	if c.db.DbStoresUserData() {
 		c.goroGroup.Go(c.loadUserData)
 	} else {
@@ -339,6 +406,22 @@ func (c *taskQueueManagerImpl) Stop() {
 	) {
 		return
 	}
+	for _, backlog := range c.backlogs() {
+		backlog.stopBacklog()
+	}
+	c.cancelRatelimitSubscription()
+	c.liveness.Stop()
+	c.goroGroup.Cancel()
+	// Set user data state on stop to wake up anyone blocked on the user data changed channel.
+	c.db.setUserDataState(userDataClosed)
+	c.logger.Info("", tag.LifeCycleStopped)
+	c.taggedMetricsHandler.Counter(metrics.TaskQueueStoppedCounter.Name()).Record(1)
+	// This may call Stop again, but the status check above makes that a no-op.
+	c.unloadFromEngine()
+}
+
+// stopBacklog stops writing and reading the backlog of this manager.
+func (c *taskQueueManagerImpl) stopBacklog() {
 	// Maybe try to write one final update of ack level and GC some tasks.
 	// Skip the update if we never initialized (ackLevel will be -1 in that case).
 	// Also skip if we're stopping due to lost ownership (the update will fail in that case).
@@ -353,16 +436,26 @@ func (c *taskQueueManagerImpl) Stop() {
 		_ = c.db.UpdateState(ctx, ackLevel)
 		c.taskGC.RunNow(ctx, ackLevel)
 	}
-	c.liveness.Stop()
 	c.taskWriter.Stop()
 	c.taskReader.Stop()
-	c.goroGroup.Cancel()
-	// Set user data state on stop to wake up anyone blocked on the user data changed channel.
-	c.db.setUserDataState(userDataClosed)
-	c.logger.Info("", tag.LifeCycleStopped)
-	c.taggedMetricsHandler.Counter(metrics.TaskQueueStoppedCounter.Name()).Record(1)
-	// This may call Stop again, but the status check above makes that a no-op.
-	c.unloadFromEngine()
+}
+
+// backlogs returns the managers of the backlog of this task queue: this manager, followed by the
+// managers of its priority subqueues.
+func (c *taskQueueManagerImpl) backlogs() []*taskQueueManagerImpl {
+	return append([]*taskQueueManagerImpl{c}, c.prioritySubqueues...)
+}
+
+// backlogFor returns the manager of the backlog the task is persisted in: the priority subqueue of
+// the task's priority, or this manager for tasks of the default priority.
+func (c *taskQueueManagerImpl) backlogFor(taskInfo *persistencespb.TaskInfo) *taskQueueManagerImpl {
+	priority := int(taskPriority(taskInfo))
+	for _, subqueue := range c.prioritySubqueues {
+		if subqueue.db.taskQueue.Priority() == priority {
+			return subqueue
+		}
+	}
+	return c
 }
 
 // managesSpecificVersionSet returns true if this is a tqm for a specific version set in the build-id-based versioning
@@ -441,7 +534,12 @@ func (c *taskQueueManagerImpl) AddTask(
 
 	// If this is the versioned task dlq, skip sync match since we know we have no pollers.
 	isDlq := c.taskQueueID.VersionSet() == dlqVersionSet
-	if namespaceEntry.ActiveInCluster(c.clusterMeta.GetCurrentClusterName()) && !isDlq {
+	// Tasks of a paused task queue are spooled so that they're dispatched once it's resumed.
+	var isPaused bool
+	if params.baseTqm != nil {
+		isPaused, _ = params.baseTqm.IsPaused()
+	}
+	if namespaceEntry.ActiveInCluster(c.clusterMeta.GetCurrentClusterName()) && !isDlq && !isPaused {
 		syncMatch, err := c.trySyncMatch(ctx, params)
 		if syncMatch {
 			return syncMatch, err
@@ -470,10 +568,11 @@ func (c *taskQueueManagerImpl) AddTask(
 }
 
 func (c *taskQueueManagerImpl) SpoolTask(params addTaskParams) error {
-	_, err := c.taskWriter.appendTask(params.execution, params.taskInfo)
-	c.signalIfFatal(err)
+	backlog := c.backlogFor(params.taskInfo)
+	_, err := backlog.taskWriter.appendTask(params.execution, params.taskInfo)
+	backlog.signalIfFatal(err)
 	if err == nil {
-		c.taskReader.Signal()
+		backlog.taskReader.Signal()
 	}
 	return err
 }
@@ -513,7 +612,7 @@ func (c *taskQueueManagerImpl) PollTask(
 	}
 
 	task.namespace = c.namespace
-	task.backlogCountHint = c.taskAckManager.getBacklogCountHint
+	task.backlogCountHint = c.backlogCountHint
 	return task, nil
 }
 
@@ -549,6 +648,20 @@ func (c *taskQueueManagerImpl) GetUserData() (*persistencespb.VersionedTaskQueue
 	return c.db.GetUserData()
 }
 
+// IsPaused returns whether dispatch from the task queue is paused. The pause state is read from
+// the user data, which sticky queues get from their normal queue. The task queue counts as paused
+// while its user data can't be read, so that tasks aren't dispatched around a pause, unless user
+// data is disabled and the task queue can't be paused at all.
+func (c *taskQueueManagerImpl) IsPaused() (bool, chan struct{}) {
+	userData, userDataChanged, err := c.GetUserData()
+	if errors.Is(err, errUserDataDisabled) {
+		return false, userDataChanged
+	} else if err != nil {
+		return true, userDataChanged
+	}
+	return isTaskQueuePaused(userData.GetData(), c.taskQueueID.taskType), userDataChanged
+}
+
 // UpdateUserData updates user data for this task queue and replicates across clusters if necessary.
 func (c *taskQueueManagerImpl) UpdateUserData(ctx context.Context, options UserDataUpdateOptions, updateFn UserDataUpdateFunc) error {
 	newData, shouldReplicate, err := c.db.UpdateUserData(ctx, updateFn, options.KnownVersion, options.TaskQueueLimitPerBuildId)
@@ -618,17 +731,77 @@ func (c *taskQueueManagerImpl) DescribeTaskQueue(includeTaskQueueStatus bool) *m
 	response.TaskQueueStatus = &taskqueuepb.TaskQueueStatus{
 		ReadLevel:        c.taskAckManager.getReadLevel(),
 		AckLevel:         c.taskAckManager.getAckLevel(),
-		BacklogCountHint: c.taskAckManager.getBacklogCountHint(),
+		BacklogCountHint: c.backlogCountHint(),
 		RatePerSecond:    c.matcher.Rate(),
 		TaskIdBlock: &taskqueuepb.TaskIdBlock{
 			StartId: taskIDBlock.start,
 			EndId:   taskIDBlock.end,
 		},
 	}
+	var fairnessKeyBacklogs [][]*taskqueuespb.FairnessKeyBacklog
+	for _, backlog := range c.backlogs() {
+		fairnessKeyBacklogs = append(fairnessKeyBacklogs, backlog.taskReader.fairnessKeyBacklogs())
+	}
+	response.FairnessKeyBacklogs = mergeFairnessKeyBacklogs(fairnessKeyBacklogs...)
+	response.PriorityBacklogs = c.priorityBacklogs()
+	response.Backlog = c.backlog()
 
 	return response
 }
 
+// backlog returns the approximate backlog count and age of this task queue partition, including its
+// priority subqueues.
+func (c *taskQueueManagerImpl) backlog() *taskqueuespb.TaskQueueBacklog {
+	var count int64
+	var age time.Duration
+	now := time.Now()
+	for _, backlog := range c.backlogs() {
+		count += backlog.db.getApproximateBacklogCount()
+		age = max(age, backlog.backlogAge.age(now))
+	}
+	return &taskqueuespb.TaskQueueBacklog{
+		ApproximateBacklogCount: count,
+		ApproximateBacklogAge:   durationpb.New(age),
+	}
+}
+
+// priorityBacklogs returns the approximate backlog count and age of each task priority, most urgent
+// first, or nil when task priorities are disabled.
+func (c *taskQueueManagerImpl) priorityBacklogs() []*taskqueuespb.PriorityBacklog {
+	if len(c.prioritySubqueues) == 0 {
+		return nil
+	}
+	now := time.Now()
+	result := make([]*taskqueuespb.PriorityBacklog, 0, len(c.prioritySubqueues)+1)
+	for _, backlog := range c.backlogs() {
+		priority := int32(backlog.db.taskQueue.Priority())
+		if backlog == c {
+			priority = common.DefaultTaskPriority
+		}
+		result = append(result, &taskqueuespb.PriorityBacklog{
+			Priority: priority,
+			Backlog: &taskqueuespb.TaskQueueBacklog{
+				ApproximateBacklogCount: backlog.db.getApproximateBacklogCount(),
+				ApproximateBacklogAge:   durationpb.New(backlog.backlogAge.age(now)),
+			},
+		})
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Priority < result[j].Priority
+	})
+	return result
+}
+
+// backlogCountHint returns the number of loaded backlog tasks that are not yet complete, including
+// those of the priority subqueues.
+func (c *taskQueueManagerImpl) backlogCountHint() int64 {
+	var count int64
+	for _, backlog := range c.backlogs() {
+		count += backlog.taskAckManager.getBacklogCountHint()
+	}
+	return count
+}
+
 func (c *taskQueueManagerImpl) String() string {
 	buf := new(bytes.Buffer)
 	if c.taskQueueID.taskType == enumspb.TASK_QUEUE_TYPE_ACTIVITY {
@@ -683,6 +856,8 @@ func (c *taskQueueManagerImpl) completeTask(task *persistencespb.AllocatedTaskIn
 	}
 
 	ackLevel := c.taskAckManager.completeTask(task.GetTaskId())
+	c.db.updateApproximateBacklogCount(-1)
+	c.backlogAge.record(task.GetData().GetCreateTime(), -1)
 
 	// TODO: completeTaskFunc and task.finish() should take in a context
 	ctx, cancel := c.newIOContext()
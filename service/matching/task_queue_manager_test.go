@@ -505,6 +505,123 @@ func TestDescribeTaskQueue(t *testing.T) {
 	require.Zero(t, taskQueueStatus.GetBacklogCountHint())
 }
 
+func TestDescribeTaskQueue_Backlog(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	tlm := mustCreateTestTaskQueueManager(t, controller)
+	tlm.db.rangeID = int64(1)
+	tlm.db.ackLevel = int64(0)
+	tlm.taskAckManager.setAckLevel(tlm.db.ackLevel)
+	tlm.taskAckManager.setReadLevel(tlm.db.ackLevel)
+	tlm.db.setApproximateBacklogCount(3)
+
+	tasks := []*persistencespb.AllocatedTaskInfo{
+		{
+			Data:   &persistencespb.TaskInfo{CreateTime: timestamp.TimeNowPtrUtcAddSeconds(-60 * 60)},
+			TaskId: 11,
+		},
+		{
+			Data:   &persistencespb.TaskInfo{CreateTime: timestamp.TimeNowPtrUtcAddSeconds(-60)},
+			TaskId: 12,
+		},
+		{
+			Data: &persistencespb.TaskInfo{
+				ExpiryTime: timestamp.TimeNowPtrUtcAddSeconds(-60),
+				CreateTime: timestamp.TimeNowPtrUtcAddSeconds(-2 * 60 * 60),
+			},
+			TaskId: 13,
+		},
+	}
+	require.NoError(t, tlm.taskReader.addTasksToBuffer(context.TODO(), tasks))
+
+	// the expired task is no longer part of the backlog
+	backlog := tlm.DescribeTaskQueue(true).GetBacklog()
+	require.Equal(t, int64(2), backlog.GetApproximateBacklogCount())
+	require.InDelta(t, time.Hour, backlog.GetApproximateBacklogAge().AsDuration(), float64(time.Minute))
+
+	tlm.completeTask(tasks[0], nil)
+	backlog = tlm.DescribeTaskQueue(true).GetBacklog()
+	require.Equal(t, int64(1), backlog.GetApproximateBacklogCount())
+	require.InDelta(t, time.Minute, backlog.GetApproximateBacklogAge().AsDuration(), float64(10*time.Second))
+
+	tlm.completeTask(tasks[1], nil)
+	backlog = tlm.DescribeTaskQueue(true).GetBacklog()
+	require.Zero(t, backlog.GetApproximateBacklogCount())
+	require.Zero(t, backlog.GetApproximateBacklogAge().AsDuration())
+}
+
+func TestApproximateBacklogCount_PersistedWithTaskQueue(t *testing.T) {
+	logger := log.NewTestLogger()
+	tm := newTestTaskManager(logger)
+	tqID := defaultTqId()
+	newDB := func() *taskQueueDB {
+		return newTaskQueueDB(tm, nil, defaultNamespaceId, tqID, enumspb.TASK_QUEUE_KIND_NORMAL, logger)
+	}
+
+	db := newDB()
+	_, err := db.RenewLease(context.Background())
+	require.NoError(t, err)
+	_, err = db.CreateTasks(context.Background(), []*persistencespb.AllocatedTaskInfo{
+		{Data: &persistencespb.TaskInfo{}, TaskId: 1},
+		{Data: &persistencespb.TaskInfo{}, TaskId: 2},
+		{Data: &persistencespb.TaskInfo{}, TaskId: 3},
+	})
+	require.NoError(t, err)
+	require.Equal(t, int64(3), db.getApproximateBacklogCount())
+	db.updateApproximateBacklogCount(-1)
+	require.NoError(t, db.UpdateState(context.Background(), 1))
+
+	// a new owner starts from the persisted count
+	db = newDB()
+	_, err = db.RenewLease(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, int64(2), db.getApproximateBacklogCount())
+
+	db.updateApproximateBacklogCount(-5)
+	require.Zero(t, db.getApproximateBacklogCount())
+}
+
+func TestPrioritySubqueues(t *testing.T) {
+	t.Parallel()
+
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	cfg := defaultTestConfig()
+	cfg.EnableTaskPriority = dynamicconfig.GetBoolPropertyFnFilteredByTaskQueueInfo(true)
+	tqCfg := defaultTqmTestOpts(controller)
+	tqCfg.config = cfg
+
+	tqm := mustCreateTestTaskQueueManagerWithConfig(t, controller, tqCfg)
+	tm := tqm.engine.taskManager.(*testTaskManager)
+	tqm.Start()
+	defer tqm.Stop()
+	require.NoError(t, tqm.WaitUntilInitialized(context.Background()))
+
+	for _, priority := range []int32{0, 1, 1, 5} {
+		require.NoError(t, tqm.SpoolTask(addTaskParams{
+			execution: &commonpb.WorkflowExecution{WorkflowId: "wid", RunId: "rid"},
+			taskInfo:  &persistencespb.TaskInfo{CreateTime: timestamp.TimeNowPtrUtc(), Priority: priority},
+			source:    enumsspb.TASK_SOURCE_HISTORY,
+		}))
+	}
+
+	// tasks of the default priority stay in the task queue, the others are written to the subqueue of their priority
+	require.Equal(t, 1, tm.getTaskCount(tqCfg.tqId))
+	for priority, count := range map[int32]int{1: 2, 2: 0, 4: 0, 5: 1} {
+		require.Equal(t, count, tm.getTaskCount(newTaskQueueIDWithPriority(tqCfg.tqId, priority)), "priority %d", priority)
+	}
+
+	desc := tqm.DescribeTaskQueue(true)
+	require.Equal(t, int64(4), desc.GetBacklog().GetApproximateBacklogCount())
+	var counts []int64
+	for _, backlog := range desc.GetPriorityBacklogs() {
+		counts = append(counts, backlog.GetBacklog().GetApproximateBacklogCount())
+	}
+	require.Equal(t, []int64{2, 0, 1, 0, 1}, counts)
+}
+
 func TestCheckIdleTaskQueue(t *testing.T) {
 	controller := gomock.NewController(t)
 	defer controller.Finish()
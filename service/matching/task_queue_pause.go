@@ -0,0 +1,81 @@
+// The MIT License
+//
+// Copyright (c) 2020 Temporal Technologies Inc.  All rights reserved.
+//
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package matching
+
+import (
+	enumspb "go.temporal.io/api/enums/v1"
+
+	"go.temporal.io/server/api/matchingservice/v1"
+	persistencespb "go.temporal.io/server/api/persistence/v1"
+	"go.temporal.io/server/common"
+	hlc "go.temporal.io/server/common/clock/hybrid_logical_clock"
+)
+
+// pausableTaskQueueTypes are the task queue types that a request without a type pauses or resumes.
+var pausableTaskQueueTypes = []enumspb.TaskQueueType{
+	enumspb.TASK_QUEUE_TYPE_WORKFLOW,
+	enumspb.TASK_QUEUE_TYPE_ACTIVITY,
+}
+
+// isTaskQueuePaused returns whether dispatch from the given type of the task queue is paused.
+func isTaskQueuePaused(data *persistencespb.TaskQueueUserData, taskType enumspb.TaskQueueType) bool {
+	return data.GetPerType()[int32(taskType)].GetPauseInfo().GetPaused()
+}
+
+// setPauseState returns a copy of the user data with the pause state of the task queue types selected
+// by the request updated.
+func setPauseState(
+	clock *hlc.Clock,
+	data *persistencespb.TaskQueueUserData,
+	req *matchingservice.UpdateTaskQueuePauseStateRequest,
+) *persistencespb.TaskQueueUserData {
+	taskTypes := pausableTaskQueueTypes
+	if req.GetTaskQueueType() != enumspb.TASK_QUEUE_TYPE_UNSPECIFIED {
+		taskTypes = []enumspb.TaskQueueType{req.GetTaskQueueType()}
+	}
+
+	// Avoid mutation
+	ret := common.CloneProto(data)
+	if ret == nil {
+		ret = &persistencespb.TaskQueueUserData{}
+	}
+	if ret.PerType == nil {
+		ret.PerType = make(map[int32]*persistencespb.TaskQueueTypeUserData, len(taskTypes))
+	}
+	for _, taskType := range taskTypes {
+		typeData := ret.PerType[int32(taskType)]
+		if typeData == nil {
+			typeData = &persistencespb.TaskQueueTypeUserData{}
+			ret.PerType[int32(taskType)] = typeData
+		}
+		typeData.PauseInfo = &persistencespb.TaskQueuePauseInfo{
+			Paused:          req.GetPaused(),
+			Reason:          req.GetReason(),
+			Identity:        req.GetIdentity(),
+			UpdateTimestamp: clock,
+		}
+	}
+	return ret
+}
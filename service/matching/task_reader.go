@@ -33,6 +33,7 @@ import (
 
 	enumsspb "go.temporal.io/server/api/enums/v1"
 	persistencespb "go.temporal.io/server/api/persistence/v1"
+	taskqueuespb "go.temporal.io/server/api/taskqueue/v1"
 	"go.temporal.io/server/common"
 	"go.temporal.io/server/common/backoff"
 	"go.temporal.io/server/common/log"
@@ -54,6 +55,10 @@ type (
 		tlMgr         *taskQueueManagerImpl
 		taskValidator taskValidator
 		gorogrp       goro.Group
+		// fairBuffer interleaves backlog tasks by fairness key, nil when fairness is disabled
+		fairBuffer *fairTaskBuffer
+		// throttledBuffer holds backlog tasks of workflow and activity types that are over their dispatch rate limit
+		throttledBuffer *throttledTaskBuffer
 
 		backoffTimerLock sync.Mutex
 		backoffTimer     *time.Timer
@@ -62,7 +67,7 @@ type (
 )
 
 func newTaskReader(tlMgr *taskQueueManagerImpl) *taskReader {
-	return &taskReader{
+	tr := &taskReader{
 		status:        common.DaemonStatusInitialized,
 		tlMgr:         tlMgr,
 		taskValidator: newTaskValidator(tlMgr.newIOContext, tlMgr.clusterMeta, tlMgr.namespaceRegistry, tlMgr.engine.historyClient),
@@ -75,6 +80,15 @@ func newTaskReader(tlMgr *taskQueueManagerImpl) *taskReader {
 			backoff.SystemClock,
 		),
 	}
+	if tlMgr.config.EnableFairness() {
+		// the dispatch order is decided by the fair buffer, so tasks must not queue up again after it
+		tr.taskBuffer = make(chan *persistencespb.AllocatedTaskInfo)
+		tr.fairBuffer = newFairTaskBuffer(tlMgr.config, tr.taskBuffer, tlMgr.logger)
+	}
+	tr.throttledBuffer = newThrottledTaskBuffer(tlMgr.config, &tr.gorogrp, tr.dispatchThrottledTask, func(task *internalTask) time.Duration {
+		return tlMgr.matcher.typeRateLimiter.retryDelay(task)
+	}, tr.Signal)
+	return tr
 }
 
 // Start reading pump for the given task queue.
@@ -88,6 +102,9 @@ func (tr *taskReader) Start() {
 		return
 	}
 
+	if tr.fairBuffer != nil {
+		tr.fairBuffer.Start()
+	}
 	tr.gorogrp.Go(tr.dispatchBufferedTasks)
 	tr.gorogrp.Go(tr.getTasksPump)
 }
@@ -103,6 +120,9 @@ func (tr *taskReader) Stop() {
 	}
 
 	tr.gorogrp.Cancel()
+	if tr.fairBuffer != nil {
+		tr.fairBuffer.Stop()
+	}
 }
 
 func (tr *taskReader) Signal() {
@@ -131,6 +151,11 @@ dispatchLoop:
 					// Don't try to set read level here because it may have been advanced already.
 					continue dispatchLoop
 				}
+				if tr.throttledBuffer.hasTasks(task) {
+					// the task must wait behind the earlier tasks of its type
+					tr.throttledBuffer.add(task)
+					continue dispatchLoop
+				}
 
 				taskCtx, cancel := context.WithTimeout(ctx, taskReaderOfferTimeout)
 				err := tr.tlMgr.engine.DispatchSpooledTask(taskCtx, task, tr.tlMgr.taskQueueID, tr.tlMgr.stickyInfo)
@@ -138,6 +163,11 @@ dispatchLoop:
 				if err == nil {
 					continue dispatchLoop
 				}
+				if errors.Is(err, errTaskTypeThrottled) {
+					// set the task aside so that the tasks of other types aren't blocked by its type's rate limit
+					tr.throttledBuffer.add(task)
+					continue dispatchLoop
+				}
 
 				// if task is still valid (truly valid or unable to verify if task is valid)
 				tr.taggedMetricsHandler().Counter(metrics.BufferThrottlePerTaskQueueCounter.Name()).Record(1)
@@ -155,6 +185,27 @@ dispatchLoop:
 	return ctx.Err()
 }
 
+// dispatchThrottledTask makes one attempt to dispatch a backlog task of a type that was over its rate limit.
+func (tr *taskReader) dispatchThrottledTask(ctx context.Context, task *internalTask) error {
+	ctx = tr.tlMgr.callerInfoContext(ctx)
+	if IsTaskExpired(task.event.AllocatedTaskInfo) {
+		task.finish(nil)
+		tr.taggedMetricsHandler().Counter(metrics.ExpiredTasksPerTaskQueueCounter.Name()).Record(1)
+		return nil
+	}
+
+	taskCtx, cancel := context.WithTimeout(ctx, taskReaderOfferTimeout)
+	defer cancel()
+	err := tr.tlMgr.engine.DispatchSpooledTask(taskCtx, task, tr.tlMgr.taskQueueID, tr.tlMgr.stickyInfo)
+	if err != nil && !errors.Is(err, errTaskTypeThrottled) {
+		tr.taggedMetricsHandler().Counter(metrics.BufferThrottlePerTaskQueueCounter.Name()).Record(1)
+		if !errors.Is(err, errUserDataDisabled) && !errors.Is(err, context.DeadlineExceeded) && !errors.Is(err, context.Canceled) {
+			tr.throttledLogger().Error("taskReader: unexpected error dispatching throttled task", tag.Error(err))
+		}
+	}
+	return err
+}
+
 func (tr *taskReader) getTasksPump(ctx context.Context) error {
 	ctx = tr.tlMgr.callerInfoContext(ctx)
 
@@ -180,6 +231,37 @@ Loop:
 			return nil
 
 		case <-tr.notifyC:
+			if tr.throttledBuffer.shouldLoadDeferred() {
+				err := tr.loadThrottledTasks(ctx)
+				tr.tlMgr.signalIfFatal(err)
+				if err != nil {
+					if common.IsResourceExhausted(err) {
+						tr.reEnqueueAfterDelay(taskReaderThrottleRetryDelay)
+					} else {
+						tr.reEnqueueAfterDelay(tr.retrier.NextBackOff())
+					}
+					continue Loop
+				}
+				tr.retrier.Reset()
+			}
+
+			caughtUp := tr.tlMgr.taskAckManager.getReadLevel() == tr.tlMgr.taskWriter.GetMaxReadLevel()
+			if tr.fairBuffer != nil && tr.fairBuffer.shouldLoadDeferred(caughtUp) {
+				err := tr.loadDeferredTasks(ctx, caughtUp || tr.fairBuffer.numDeferred() >= tr.tlMgr.config.FairnessMaxDeferredTasks())
+				tr.tlMgr.signalIfFatal(err)
+				if err != nil {
+					if common.IsResourceExhausted(err) {
+						tr.reEnqueueAfterDelay(taskReaderThrottleRetryDelay)
+					} else {
+						tr.reEnqueueAfterDelay(tr.retrier.NextBackOff())
+					}
+					continue Loop
+				}
+				tr.retrier.Reset()
+				tr.Signal()
+				continue Loop
+			}
+
 			batch, err := tr.getTaskBatch(ctx)
 			tr.tlMgr.signalIfFatal(err)
 			if err != nil {
@@ -195,6 +277,10 @@ Loop:
 
 			if len(batch.tasks) == 0 {
 				tr.tlMgr.taskAckManager.setReadLevelAfterGap(batch.readLevel)
+				if batch.isReadBatchDone && batch.readLevel == tr.tlMgr.taskWriter.GetMaxReadLevel() {
+					// All tasks in the db have been loaded, so the outstanding ones are the whole backlog.
+					tr.tlMgr.db.setApproximateBacklogCount(tr.tlMgr.taskAckManager.getBacklogCountHint())
+				}
 				if !batch.isReadBatchDone {
 					tr.Signal()
 				}
@@ -285,6 +371,7 @@ func (tr *taskReader) addTasksToBuffer(
 			// Also increment readLevel for expired tasks otherwise it could result in
 			// looping over the same tasks if all tasks read in the batch are expired
 			tr.tlMgr.taskAckManager.setReadLevel(t.GetTaskId())
+			tr.tlMgr.db.updateApproximateBacklogCount(-1)
 			continue
 		}
 		if err := tr.addSingleTaskToBuffer(ctx, t); err != nil {
@@ -299,6 +386,14 @@ func (tr *taskReader) addSingleTaskToBuffer(
 	task *persistencespb.AllocatedTaskInfo,
 ) error {
 	tr.tlMgr.taskAckManager.addTask(task.GetTaskId())
+	tr.tlMgr.backlogAge.record(task.GetData().GetCreateTime(), 1)
+	if tr.fairBuffer != nil {
+		if tr.fairBuffer.deferTask(task) {
+			// the task is read again by loadDeferredTasks
+			return nil
+		}
+		return tr.fairBuffer.add(ctx, task)
+	}
 	select {
 	case tr.taskBuffer <- task:
 		return nil
@@ -307,9 +402,114 @@ func (tr *taskReader) addSingleTaskToBuffer(
 	}
 }
 
+// loadDeferredTasks reads the backlog again from the oldest deferred task and buffers the deferred tasks of
+// the keys that are below their share of the fair buffer. All deferred tasks are buffered when force is set,
+// which blocks until there is room for them.
+func (tr *taskReader) loadDeferredTasks(ctx context.Context, force bool) error {
+	minID, maxID, ok := tr.fairBuffer.deferredRange()
+	if !ok {
+		return nil
+	}
+
+	// once a deferred task of a key stays deferred, the later tasks of the key stay deferred too
+	skippedKeys := make(map[string]struct{})
+	found := make(map[int64]struct{})
+	readLevel := minID - 1
+	for readLevel < maxID {
+		batchSize := tr.tlMgr.config.GetTasksBatchSize()
+		response, err := tr.tlMgr.db.GetTasks(ctx, readLevel+1, maxID+1, batchSize)
+		if err != nil {
+			return err
+		}
+		for _, t := range response.Tasks {
+			readLevel = t.GetTaskId()
+			found[t.GetTaskId()] = struct{}{}
+			if _, ok := skippedKeys[t.GetData().GetFairnessKey()]; ok {
+				continue
+			}
+			taken, isDeferred := tr.fairBuffer.takeDeferred(t, force)
+			if !taken {
+				if isDeferred {
+					skippedKeys[t.GetData().GetFairnessKey()] = struct{}{}
+				}
+				continue
+			}
+			if IsTaskExpired(t) {
+				tr.taggedMetricsHandler().Counter(metrics.ExpiredTasksPerTaskQueueCounter.Name()).Record(1)
+				tr.tlMgr.completeTask(t, nil)
+				continue
+			}
+			if err := tr.fairBuffer.add(ctx, t); err != nil {
+				return err
+			}
+		}
+		if len(response.Tasks) < batchSize {
+			break
+		}
+		if !force && !tr.fairBuffer.hasUnderservedKey() {
+			return nil
+		}
+	}
+
+	// Deferred tasks that are gone from persistence must not hold back the ack level.
+	for taskID, createTime := range tr.fairBuffer.takeDeferredInRange(minID, maxID, found) {
+		tr.tlMgr.completeTask(&persistencespb.AllocatedTaskInfo{
+			TaskId: taskID,
+			Data:   &persistencespb.TaskInfo{CreateTime: createTime},
+		}, nil)
+	}
+	return nil
+}
+
+// loadThrottledTasks reads the backlog again from the oldest deferred task of the types whose throttled queue has
+// room, and queues their deferred tasks in order.
+func (tr *taskReader) loadThrottledTasks(ctx context.Context) error {
+	minID, maxID, ok := tr.throttledBuffer.deferredRange()
+	if !ok {
+		return nil
+	}
+
+	found := make(map[int64]struct{})
+	readLevel := minID - 1
+	for readLevel < maxID {
+		batchSize := tr.tlMgr.config.GetTasksBatchSize()
+		response, err := tr.tlMgr.db.GetTasks(ctx, readLevel+1, maxID+1, batchSize)
+		if err != nil {
+			return err
+		}
+		for _, t := range response.Tasks {
+			readLevel = t.GetTaskId()
+			found[t.GetTaskId()] = struct{}{}
+			// expired tasks are completed when they are dispatched from the queue
+			tr.throttledBuffer.takeDeferred(newInternalTask(t, tr.tlMgr.completeTask, enumsspb.TASK_SOURCE_DB_BACKLOG, "", false))
+		}
+		if len(response.Tasks) < batchSize {
+			break
+		}
+		if !tr.throttledBuffer.shouldLoadDeferred() {
+			return nil
+		}
+	}
+
+	// Deferred tasks that are gone from persistence must not hold back the ack level.
+	for taskID, createTime := range tr.throttledBuffer.takeDeferredInRange(minID, maxID, found) {
+		tr.tlMgr.completeTask(&persistencespb.AllocatedTaskInfo{
+			TaskId: taskID,
+			Data:   &persistencespb.TaskInfo{CreateTime: createTime},
+		}, nil)
+	}
+	return nil
+}
+
 func (tr *taskReader) persistAckLevel(ctx context.Context) error {
 	ackLevel := tr.tlMgr.taskAckManager.getAckLevel()
-	tr.emitTaskLagMetric(ackLevel)
+	if tr.tlMgr.parent == nil {
+		// the gauges of a task queue include its priority subqueues
+		tr.emitTaskLagMetric()
+		tr.emitBacklogMetrics()
+		tr.emitFairnessMetrics()
+		tr.emitThrottledBacklogMetric()
+	}
 	return tr.tlMgr.db.UpdateState(ctx, ackLevel)
 }
 
@@ -325,11 +525,53 @@ func (tr *taskReader) taggedMetricsHandler() metrics.Handler {
 	return tr.tlMgr.metricsHandler
 }
 
-func (tr *taskReader) emitTaskLagMetric(ackLevel int64) {
+func (tr *taskReader) emitTaskLagMetric() {
 	// note: this metric is only an estimation for the lag.
 	// taskID in DB may not be continuous, especially when task list ownership changes.
-	maxReadLevel := tr.tlMgr.taskWriter.GetMaxReadLevel()
-	tr.taggedMetricsHandler().Gauge(metrics.TaskLagPerTaskQueueGauge.Name()).Record(float64(maxReadLevel - ackLevel))
+	var lag int64
+	for _, backlog := range tr.tlMgr.backlogs() {
+		lag += backlog.taskWriter.GetMaxReadLevel() - backlog.taskAckManager.getAckLevel()
+	}
+	tr.taggedMetricsHandler().Gauge(metrics.TaskLagPerTaskQueueGauge.Name()).Record(float64(lag))
+}
+
+func (tr *taskReader) emitBacklogMetrics() {
+	backlog := tr.tlMgr.backlog()
+	tr.taggedMetricsHandler().Gauge(metrics.ApproximateBacklogCount.Name()).Record(float64(backlog.GetApproximateBacklogCount()))
+	tr.taggedMetricsHandler().Gauge(metrics.ApproximateBacklogAgeSeconds.Name()).Record(backlog.GetApproximateBacklogAge().AsDuration().Seconds())
+}
+
+func (tr *taskReader) emitFairnessMetrics() {
+	if tr.fairBuffer == nil {
+		return
+	}
+	// a key that has tasks of several priorities is counted once per priority
+	var numKeys, numTasks, numDeferred int
+	for _, backlog := range tr.tlMgr.backlogs() {
+		keys, tasks := backlog.taskReader.fairBuffer.size()
+		numKeys += keys
+		numTasks += tasks
+		numDeferred += backlog.taskReader.fairBuffer.numDeferred()
+	}
+	tr.taggedMetricsHandler().Gauge(metrics.FairnessKeysPerTaskQueueGauge.Name()).Record(float64(numKeys))
+	tr.taggedMetricsHandler().Gauge(metrics.FairnessBufferedTasksPerTaskQueueGauge.Name()).Record(float64(numTasks))
+	tr.taggedMetricsHandler().Gauge(metrics.FairnessDeferredTasksPerTaskQueueGauge.Name()).Record(float64(numDeferred))
+}
+
+func (tr *taskReader) emitThrottledBacklogMetric() {
+	var size int
+	for _, backlog := range tr.tlMgr.backlogs() {
+		size += backlog.taskReader.throttledBuffer.size()
+	}
+	tr.taggedMetricsHandler().Gauge(metrics.ThrottledBacklogTasksPerTaskQueueGauge.Name()).Record(float64(size))
+}
+
+// fairnessKeyBacklogs returns the loaded backlog per fairness key, or nil when fairness is disabled.
+func (tr *taskReader) fairnessKeyBacklogs() []*taskqueuespb.FairnessKeyBacklog {
+	if tr.fairBuffer == nil {
+		return nil
+	}
+	return tr.fairBuffer.fairnessKeyBacklogs()
 }
 
 func (tr *taskReader) reEnqueueAfterDelay(duration time.Duration) {
@@ -54,6 +54,10 @@ func newTaskQueueIDWithPartition(
 	if err != nil {
 		return nil, err
 	}
+	if name.Priority() != 0 {
+		// priority subqueues are only addressed by the task queue manager of their task queue
+		return nil, fmt.Errorf("invalid task queue name %q", taskQueueName)
+	}
 	if partition >= 0 {
 		name = name.WithPartition(partition)
 	}
@@ -72,8 +76,17 @@ func newTaskQueueIDWithVersionSet(id *taskQueueID, versionSet string) *taskQueue
 	}
 }
 
+// newTaskQueueIDWithPriority returns the id of the backlog subqueue of the given task priority of id.
+func newTaskQueueIDWithPriority(id *taskQueueID, priority int32) *taskQueueID {
+	return &taskQueueID{
+		Name:        id.Name.WithPriority(int(priority)),
+		namespaceID: id.namespaceID,
+		taskType:    id.taskType,
+	}
+}
+
 func (tid *taskQueueID) OwnsUserData() bool {
-	return tid.IsRoot() && tid.VersionSet() == "" && tid.taskType == enumspb.TASK_QUEUE_TYPE_WORKFLOW
+	return tid.IsRoot() && tid.VersionSet() == "" && tid.Priority() == 0 && tid.taskType == enumspb.TASK_QUEUE_TYPE_WORKFLOW
 }
 
 func (tid *taskQueueID) String() string {
@@ -0,0 +1,286 @@
+// The MIT License
+//
+// Copyright (c) 2020 Temporal Technologies Inc.  All rights reserved.
+//
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package matching
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"sync"
+	"time"
+
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"go.temporal.io/server/common"
+	"go.temporal.io/server/internal/goro"
+)
+
+type (
+	// throttledTaskBuffer holds the backlog tasks of workflow and activity types that are over their dispatch rate
+	// limit, so that the tasks of other types keep being dispatched. Each type has its own queue, dispatched in
+	// order by its own goroutine as the rate limit allows. The queue and goroutine of a type are removed once it
+	// has no tasks left.
+	//
+	// A queue holds at most ThrottledBacklogBufferSize tasks. Further tasks of the type are recorded as deferred
+	// instead of blocking the task reader: they stay in persistence and are read again once the queue of their
+	// type is at most half full.
+	throttledTaskBuffer struct {
+		config *taskQueueConfig
+		// dispatch makes one attempt to dispatch a task, it returns errTaskTypeThrottled if the type is still over its
+		// rate limit
+		dispatch func(ctx context.Context, task *internalTask) error
+		// retryDelay returns how long to wait before a task whose type was over its rate limit is offered again
+		retryDelay func(task *internalTask) time.Duration
+		// signal asks the task reader to load deferred tasks
+		signal  func()
+		gorogrp *goro.Group
+
+		lock   sync.Mutex
+		queues map[string]*throttledTypeQueue
+	}
+
+	throttledTypeQueue struct {
+		typeName string
+		tasks    chan *internalTask
+		// number of tasks added and not dispatched yet, including the one being dispatched
+		pending int
+		// deferred tasks of the type in the order they were deferred. They stay outstanding in the ack manager so
+		// they are not deleted.
+		deferred []throttledDeferredTask
+	}
+
+	throttledDeferredTask struct {
+		taskID     int64
+		createTime *timestamppb.Timestamp
+	}
+)
+
+func newThrottledTaskBuffer(
+	config *taskQueueConfig,
+	gorogrp *goro.Group,
+	dispatch func(ctx context.Context, task *internalTask) error,
+	retryDelay func(task *internalTask) time.Duration,
+	signal func(),
+) *throttledTaskBuffer {
+	return &throttledTaskBuffer{
+		config:     config,
+		dispatch:   dispatch,
+		retryDelay: retryDelay,
+		signal:     signal,
+		gorogrp:    gorogrp,
+		queues:     make(map[string]*throttledTypeQueue),
+	}
+}
+
+// add queues a task behind the other throttled tasks of its type without blocking. When the queue of the type is
+// full, or earlier tasks of the type are deferred, the task is deferred as well so that the tasks of a type keep
+// their order.
+func (b *throttledTaskBuffer) add(task *internalTask) {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	queue := b.queueLocked(taskTypeName(task))
+	if len(queue.deferred) == 0 && queue.tryPushLocked(task) {
+		return
+	}
+	queue.deferred = append(queue.deferred, throttledDeferredTask{
+		taskID:     task.event.GetTaskId(),
+		createTime: task.event.GetData().GetCreateTime(),
+	})
+}
+
+// takeDeferred queues a task read from persistence again if it's the next deferred task of its type and the queue of
+// the type has room. It returns whether the task was taken.
+func (b *throttledTaskBuffer) takeDeferred(task *internalTask) bool {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	queue, ok := b.queues[taskTypeName(task)]
+	if !ok || len(queue.deferred) == 0 || queue.deferred[0].taskID != task.event.GetTaskId() {
+		return false
+	}
+	if !queue.tryPushLocked(task) {
+		return false
+	}
+	queue.deferred = queue.deferred[1:]
+	return true
+}
+
+// takeDeferredInRange removes the deferred tasks with IDs in [minID, maxID] except the given ones, and returns
+// their creation times by task ID.
+func (b *throttledTaskBuffer) takeDeferredInRange(minID, maxID int64, except map[int64]struct{}) map[int64]*timestamppb.Timestamp {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	var result map[int64]*timestamppb.Timestamp
+	for _, queue := range b.queues {
+		queue.deferred = slices.DeleteFunc(queue.deferred, func(d throttledDeferredTask) bool {
+			if d.taskID < minID || d.taskID > maxID {
+				return false
+			}
+			if _, ok := except[d.taskID]; ok {
+				return false
+			}
+			if result == nil {
+				result = make(map[int64]*timestamppb.Timestamp)
+			}
+			result[d.taskID] = d.createTime
+			return true
+		})
+	}
+	return result
+}
+
+// deferredRange returns the smallest and largest ID of the deferred tasks of the types whose queue has room for
+// them, and false if there are none.
+func (b *throttledTaskBuffer) deferredRange() (int64, int64, bool) {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	var minID, maxID int64
+	found := false
+	for _, queue := range b.queues {
+		if !queue.shouldLoadDeferredLocked() {
+			continue
+		}
+		for _, d := range queue.deferred {
+			if !found || d.taskID < minID {
+				minID = d.taskID
+			}
+			if !found || d.taskID > maxID {
+				maxID = d.taskID
+			}
+			found = true
+		}
+	}
+	return minID, maxID, found
+}
+
+// shouldLoadDeferred returns true when the queue of a type with deferred tasks is at most half full, so that
+// deferred tasks are loaded in batches rather than one by one.
+func (b *throttledTaskBuffer) shouldLoadDeferred() bool {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	for _, queue := range b.queues {
+		if queue.shouldLoadDeferredLocked() {
+			return true
+		}
+	}
+	return false
+}
+
+// hasTasks returns whether tasks of the task's type are waiting for the type's rate limit. Later tasks of the type
+// must be added to the buffer as well to keep them in order.
+func (b *throttledTaskBuffer) hasTasks(task *internalTask) bool {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	_, ok := b.queues[taskTypeName(task)]
+	return ok
+}
+
+// size returns the number of tasks in the buffer, including the deferred ones.
+func (b *throttledTaskBuffer) size() int {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	numTasks := 0
+	for _, queue := range b.queues {
+		numTasks += queue.pending + len(queue.deferred)
+	}
+	return numTasks
+}
+
+func (b *throttledTaskBuffer) queueLocked(typeName string) *throttledTypeQueue {
+	queue, ok := b.queues[typeName]
+	if !ok {
+		queue = &throttledTypeQueue{
+			typeName: typeName,
+			tasks:    make(chan *internalTask, b.config.ThrottledBacklogBufferSize()),
+		}
+		b.queues[typeName] = queue
+		b.gorogrp.Go(func(ctx context.Context) error {
+			return b.dispatchLoop(ctx, queue)
+		})
+	}
+	return queue
+}
+
+// done records that a task of the queue was dispatched. It removes the queue once the type has no tasks left, and
+// returns false when the queue was removed.
+func (b *throttledTaskBuffer) done(queue *throttledTypeQueue) bool {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	queue.pending--
+	if queue.pending == 0 && len(queue.deferred) == 0 {
+		delete(b.queues, queue.typeName)
+		return false
+	}
+	if queue.shouldLoadDeferredLocked() {
+		b.signal()
+	}
+	return true
+}
+
+func (b *throttledTaskBuffer) dispatchLoop(ctx context.Context, queue *throttledTypeQueue) error {
+	for {
+		select {
+		case task := <-queue.tasks:
+			for {
+				err := b.dispatch(ctx, task)
+				if err == nil {
+					break
+				}
+				if ctx.Err() != nil {
+					return ctx.Err()
+				}
+				delay := taskReaderOfferThrottleWait
+				if errors.Is(err, errTaskTypeThrottled) {
+					delay = b.retryDelay(task)
+				}
+				common.InterruptibleSleep(ctx, delay)
+			}
+			if !b.done(queue) {
+				return nil
+			}
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+func (q *throttledTypeQueue) tryPushLocked(task *internalTask) bool {
+	select {
+	case q.tasks <- task:
+		q.pending++
+		return true
+	default:
+		return false
+	}
+}
+
+func (q *throttledTypeQueue) shouldLoadDeferredLocked() bool {
+	return len(q.deferred) > 0 && q.pending <= cap(q.tasks)/2
+}
@@ -0,0 +1,156 @@
+// The MIT License
+//
+// Copyright (c) 2020 Temporal Technologies Inc.  All rights reserved.
+//
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package matching
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	enumsspb "go.temporal.io/server/api/enums/v1"
+	persistencespb "go.temporal.io/server/api/persistence/v1"
+	"go.temporal.io/server/common/dynamicconfig"
+	"go.temporal.io/server/internal/goro"
+)
+
+type throttledTaskBufferTest struct {
+	buffer *throttledTaskBuffer
+	// dispatch of tasks is allowed while open is set
+	lock       sync.Mutex
+	open       bool
+	dispatched []int64
+	signals    int
+}
+
+func newThrottledTaskBufferForTest(t *testing.T, size int) *throttledTaskBufferTest {
+	cfg := NewConfig(dynamicconfig.NewNoopCollection(), false, false)
+	cfg.ThrottledBacklogBufferSize = dynamicconfig.GetIntPropertyFilteredByTaskQueueInfo(size)
+	tqCfg := newTaskQueueConfig(newTestTaskQueueID("ns-id", "tq", 0), cfg, "ns")
+
+	bt := &throttledTaskBufferTest{}
+	var gorogrp goro.Group
+	t.Cleanup(func() {
+		gorogrp.Cancel()
+		gorogrp.Wait()
+	})
+	bt.buffer = newThrottledTaskBuffer(tqCfg, &gorogrp, func(ctx context.Context, task *internalTask) error {
+		bt.lock.Lock()
+		defer bt.lock.Unlock()
+		if !bt.open {
+			return errTaskTypeThrottled
+		}
+		bt.dispatched = append(bt.dispatched, task.event.GetTaskId())
+		return nil
+	}, func(*internalTask) time.Duration {
+		return time.Millisecond
+	}, func() {
+		bt.lock.Lock()
+		defer bt.lock.Unlock()
+		bt.signals++
+	})
+	return bt
+}
+
+func (bt *throttledTaskBufferTest) setOpen(open bool) {
+	bt.lock.Lock()
+	defer bt.lock.Unlock()
+	bt.open = open
+}
+
+func (bt *throttledTaskBufferTest) getDispatched() []int64 {
+	bt.lock.Lock()
+	defer bt.lock.Unlock()
+	return append([]int64(nil), bt.dispatched...)
+}
+
+func newThrottledTaskForTest(taskID int64, typeName string) *internalTask {
+	return newInternalTask(&persistencespb.AllocatedTaskInfo{
+		TaskId: taskID,
+		Data:   &persistencespb.TaskInfo{ActivityTypeName: typeName},
+	}, nil, enumsspb.TASK_SOURCE_DB_BACKLOG, "", false)
+}
+
+func TestThrottledTaskBuffer_DefersTasksWhenFull(t *testing.T) {
+	bt := newThrottledTaskBufferForTest(t, 2)
+	b := bt.buffer
+
+	for i := int64(1); i <= 5; i++ {
+		b.add(newThrottledTaskForTest(i, "a"))
+	}
+	require.Equal(t, 5, b.size())
+	minID, maxID, ok := b.deferredRange()
+	require.False(t, ok, "the queue is full, deferred tasks can't be loaded yet")
+	require.False(t, b.shouldLoadDeferred())
+
+	// the tasks of the queue are dispatched, and the reader is asked to load the deferred ones
+	bt.setOpen(true)
+	require.Eventually(t, func() bool {
+		return b.shouldLoadDeferred()
+	}, 5*time.Second, time.Millisecond)
+	minID, maxID, ok = b.deferredRange()
+	require.True(t, ok)
+	require.Equal(t, int64(3), minID)
+	require.Equal(t, int64(5), maxID)
+
+	// deferred tasks are only taken in order
+	require.False(t, b.takeDeferred(newThrottledTaskForTest(4, "a")))
+	require.True(t, b.takeDeferred(newThrottledTaskForTest(3, "a")))
+	require.True(t, b.takeDeferred(newThrottledTaskForTest(4, "a")))
+	// task 5 is gone from persistence
+	gone := b.takeDeferredInRange(minID, maxID, map[int64]struct{}{3: {}, 4: {}})
+	require.Len(t, gone, 1)
+	require.Contains(t, gone, int64(5))
+
+	require.Eventually(t, func() bool {
+		return len(bt.getDispatched()) == 4
+	}, 5*time.Second, time.Millisecond)
+	require.Equal(t, []int64{1, 2, 3, 4}, bt.getDispatched())
+}
+
+func TestThrottledTaskBuffer_RemovesIdleTypes(t *testing.T) {
+	bt := newThrottledTaskBufferForTest(t, 10)
+	b := bt.buffer
+
+	b.add(newThrottledTaskForTest(1, "a"))
+	b.add(newThrottledTaskForTest(2, "b"))
+	require.True(t, b.hasTasks(newThrottledTaskForTest(3, "a")))
+	require.True(t, b.hasTasks(newThrottledTaskForTest(3, "b")))
+	require.False(t, b.hasTasks(newThrottledTaskForTest(3, "c")))
+
+	bt.setOpen(true)
+	require.Eventually(t, func() bool {
+		return !b.hasTasks(newThrottledTaskForTest(3, "a")) && !b.hasTasks(newThrottledTaskForTest(3, "b"))
+	}, 5*time.Second, time.Millisecond)
+	require.Zero(t, b.size())
+
+	b.lock.Lock()
+	require.Empty(t, b.queues)
+	b.lock.Unlock()
+	b.gorogrp.Cancel()
+	b.gorogrp.Wait() // the goroutines of the idle types are gone as well
+}
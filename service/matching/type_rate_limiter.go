@@ -0,0 +1,141 @@
+// The MIT License
+//
+// Copyright (c) 2020 Temporal Technologies Inc.  All rights reserved.
+//
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package matching
+
+import (
+	"sync"
+	"time"
+
+	"go.temporal.io/server/common/quotas"
+)
+
+// maxTypeThrottleRetryDelay bounds how long a backlog task of a rate limited type waits before it's offered again,
+// so that a rate raised by dynamic config takes effect promptly.
+const maxTypeThrottleRetryDelay = time.Second
+
+// typeRateLimiter limits the rate at which the tasks of a workflow type or activity type are dispatched from a task
+// queue partition. Types without a configured rate are not limited.
+type typeRateLimiter struct {
+	config *taskQueueConfig
+
+	lock     sync.Mutex
+	limiters map[string]*typeLimiter
+}
+
+type typeLimiter struct {
+	*quotas.DynamicRateLimiterImpl
+	// rps is the configured rate the limiter was last refreshed with
+	rps float64
+}
+
+func newTypeRateLimiter(config *taskQueueConfig) *typeRateLimiter {
+	return &typeRateLimiter{
+		config:   config,
+		limiters: make(map[string]*typeLimiter),
+	}
+}
+
+// allow returns whether the task can be dispatched now, taking a token of its type if the type is rate limited.
+func (l *typeRateLimiter) allow(task *internalTask) bool {
+	limiter := l.limiter(task)
+	return limiter == nil || limiter.Allow()
+}
+
+// reserve takes a token of the task's type if one is available now, and returns false without taking one if the
+// type is over its rate limit. The returned function gives the token back, the caller must call it if the task
+// isn't dispatched after all.
+func (l *typeRateLimiter) reserve(task *internalTask) (func(), bool) {
+	limiter := l.limiter(task)
+	if limiter == nil {
+		return func() {}, true
+	}
+	// the reservation is cancelled at the time it was made, as cancelling a reservation that already took effect
+	// doesn't restore its token
+	now := time.Now()
+	reservation := limiter.ReserveN(now, 1)
+	if !reservation.OK() || reservation.DelayFrom(now) > 0 {
+		reservation.CancelAt(now)
+		return nil, false
+	}
+	return func() { reservation.CancelAt(now) }, true
+}
+
+// retryDelay returns how long to wait before offering a task that wasn't allowed again. It's about the time until
+// the next token of the task's type is available.
+func (l *typeRateLimiter) retryDelay(task *internalTask) time.Duration {
+	limiter := l.limiter(task)
+	if limiter == nil {
+		return 0
+	}
+	rate := limiter.Rate()
+	if rate <= 0 {
+		return maxTypeThrottleRetryDelay
+	}
+	return min(time.Duration(float64(time.Second)/rate), maxTypeThrottleRetryDelay)
+}
+
+func (l *typeRateLimiter) limiter(task *internalTask) quotas.RateLimiter {
+	typeName := taskTypeName(task)
+	if typeName == "" {
+		return nil
+	}
+	rps, ok := l.config.TypeDispatchRPS(typeName)
+	if !ok {
+		return nil
+	}
+
+	l.lock.Lock()
+	defer l.lock.Unlock()
+	limiter, ok := l.limiters[typeName]
+	if !ok {
+		limiter = &typeLimiter{
+			DynamicRateLimiterImpl: quotas.NewDefaultOutgoingRateLimiter(func() float64 {
+				rps, _ := l.config.TypeDispatchRPS(typeName)
+				// divide the rate equally across all partitions
+				return rps / float64(l.config.NumReadPartitions())
+			}),
+			rps: rps,
+		}
+		l.limiters[typeName] = limiter
+	} else if limiter.rps != rps {
+		// the dynamic rate limiter only refreshes its rate every minute, apply a new rate right away
+		limiter.rps = rps
+		limiter.Refresh()
+	}
+	return limiter
+}
+
+// taskTypeName returns the workflow type of a workflow task or the activity type of an activity task. It's empty
+// for query tasks and for tasks added by history hosts that don't send the type.
+func taskTypeName(task *internalTask) string {
+	if task.event == nil {
+		return ""
+	}
+	data := task.event.GetData()
+	if typeName := data.GetActivityTypeName(); typeName != "" {
+		return typeName
+	}
+	return data.GetWorkflowTypeName()
+}
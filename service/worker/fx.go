@@ -54,6 +54,8 @@ import (
 	"go.temporal.io/server/service/worker/dlq"
 	"go.temporal.io/server/service/worker/migration"
 	"go.temporal.io/server/service/worker/scheduler"
+	"go.temporal.io/server/service/worker/shardmigration"
+	"go.temporal.io/server/service/worker/visibilityreindex"
 )
 
 var Module = fx.Options(
@@ -64,6 +66,8 @@ var Module = fx.Options(
 	scheduler.Module,
 	batcher.Module,
 	dlq.Module,
+	shardmigration.Module,
+	visibilityreindex.Module,
 	fx.Provide(
 		func(c resource.HistoryClient) dlq.HistoryClient {
 			return c
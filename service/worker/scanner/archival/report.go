@@ -0,0 +1,97 @@
+// The MIT License
+//
+// Copyright (c) 2020 Temporal Technologies Inc.  All rights reserved.
+//
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package archival
+
+type (
+	// ProblemType classifies an archived history that failed verification.
+	ProblemType string
+
+	// Problem describes a single archived history that failed verification.
+	Problem struct {
+		NamespaceID string
+		Namespace   string
+		WorkflowID  string
+		RunID       string
+		Type        ProblemType
+		Details     string
+		// Repaired is true if the history was re-archived from primary storage and verified again.
+		Repaired bool
+	}
+
+	// NamespaceReport aggregates the verification results of a single namespace.
+	NamespaceReport struct {
+		// Scanned is the number of closed executions listed from visibility.
+		Scanned int64
+		// Sampled is the number of scanned executions selected for verification.
+		Sampled  int64
+		Verified int64
+		Missing  int64
+		Corrupt  int64
+		// Repaired is the number of missing or corrupt histories that were re-archived and verified again.
+		Repaired     int64
+		RepairFailed int64
+		// Errors is the number of executions that could not be verified, e.g. because primary storage was unavailable.
+		Errors int64
+	}
+
+	// Report is the result of an archival verifier run. It lists at most MaxReportedProblems problems,
+	// but the per namespace counters always cover every sampled execution.
+	Report struct {
+		Namespaces        map[string]*NamespaceReport
+		Problems          []Problem
+		ProblemsTruncated bool
+	}
+)
+
+const (
+	// ProblemMissing means the archiver has no history for the execution.
+	ProblemMissing ProblemType = "Missing"
+	// ProblemUnreadable means the archived history exists but can't be read back.
+	ProblemUnreadable ProblemType = "Unreadable"
+	// ProblemEventCountMismatch means the archived history has a different number of events than primary storage.
+	ProblemEventCountMismatch ProblemType = "EventCountMismatch"
+	// ProblemChecksumMismatch means the archived events differ from the events in primary storage.
+	ProblemChecksumMismatch ProblemType = "ChecksumMismatch"
+)
+
+func (r *Report) namespace(name string) *NamespaceReport {
+	if r.Namespaces == nil {
+		r.Namespaces = make(map[string]*NamespaceReport)
+	}
+	nsReport, ok := r.Namespaces[name]
+	if !ok {
+		nsReport = &NamespaceReport{}
+		r.Namespaces[name] = nsReport
+	}
+	return nsReport
+}
+
+func (r *Report) addProblem(problem Problem, maxProblems int) {
+	if len(r.Problems) >= maxProblems {
+		r.ProblemsTruncated = true
+		return
+	}
+	r.Problems = append(r.Problems, problem)
+}
@@ -0,0 +1,576 @@
+// The MIT License
+//
+// Copyright (c) 2020 Temporal Technologies Inc.  All rights reserved.
+//
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package archival
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand"
+	"time"
+
+	enumspb "go.temporal.io/api/enums/v1"
+	historypb "go.temporal.io/api/history/v1"
+	"go.temporal.io/api/serviceerror"
+	"go.temporal.io/sdk/activity"
+	"go.temporal.io/sdk/client"
+	"go.temporal.io/sdk/temporal"
+	"go.temporal.io/sdk/workflow"
+	"google.golang.org/protobuf/proto"
+
+	persistencespb "go.temporal.io/server/api/persistence/v1"
+	"go.temporal.io/server/common"
+	carchiver "go.temporal.io/server/common/archiver"
+	"go.temporal.io/server/common/archiver/provider"
+	"go.temporal.io/server/common/checksum"
+	"go.temporal.io/server/common/dynamicconfig"
+	"go.temporal.io/server/common/log"
+	"go.temporal.io/server/common/log/tag"
+	"go.temporal.io/server/common/metrics"
+	"go.temporal.io/server/common/namespace"
+	"go.temporal.io/server/common/persistence"
+	"go.temporal.io/server/common/persistence/versionhistory"
+	"go.temporal.io/server/common/persistence/visibility/manager"
+	"go.temporal.io/server/common/primitives"
+	"go.temporal.io/server/common/quotas"
+	"go.temporal.io/server/common/searchattribute"
+)
+
+const (
+	ArchivalVerifierWorkflowName = "archival-verifier"
+	ArchivalVerifierActivityName = "verify-archived-histories"
+
+	ArchivalVerifierWFID          = "temporal-sys-archival-verifier"
+	ArchivalVerifierTaskQueueName = "temporal-sys-archival-verifier-taskqueue-0"
+
+	checksumVersion = 1
+)
+
+var (
+	ArchivalVerifierWFStartOptions = client.StartWorkflowOptions{
+		ID:                    ArchivalVerifierWFID,
+		TaskQueue:             ArchivalVerifierTaskQueueName,
+		WorkflowIDReusePolicy: enumspb.WORKFLOW_ID_REUSE_POLICY_ALLOW_DUPLICATE,
+		CronSchedule:          "0 */12 * * *",
+	}
+)
+
+type (
+	ArchivalVerifierInput struct {
+		NamespaceListPageSize int
+		ExecutionListPageSize int
+		HistoryPageSize       int
+		// MaxReportedProblems caps the number of problems listed in the report.
+		MaxReportedProblems int
+	}
+
+	Activities struct {
+		logger             log.Logger
+		metricsHandler     metrics.Handler
+		metadataManager    persistence.MetadataManager
+		visibilityManager  manager.VisibilityManager
+		executionManager   persistence.ExecutionManager
+		namespaceRegistry  namespace.Registry
+		archivalMetadata   carchiver.ArchivalMetadata
+		archiverProvider   provider.ArchiverProvider
+		currentClusterName string
+		numHistoryShards   int32
+
+		sampleRate    dynamicconfig.FloatPropertyFn
+		rps           dynamicconfig.FloatPropertyFn
+		repairEnabled dynamicconfig.BoolPropertyFn
+		// Executions closed more recently than minCloseAge may still be waiting in the archival queue.
+		minCloseAge dynamicconfig.DurationPropertyFn
+	}
+
+	heartbeatDetails struct {
+		NamespaceIdx           int
+		ExecutionIdx           int
+		NamespaceNextPageToken []byte
+		ExecutionNextPageToken []byte
+		// CloseTimeCutoff is fixed for the whole run so that visibility page tokens stay valid across retries.
+		CloseTimeCutoff time.Time
+		Report          Report
+	}
+
+	// primaryHistory is the history of a closed execution as read from primary storage.
+	primaryHistory struct {
+		request    *carchiver.ArchiveHistoryRequest
+		eventCount int
+		checksum   *persistencespb.Checksum
+	}
+
+	// deterministicHistory marshals a history with deterministic map ordering, so that checksums
+	// of equal histories always match.
+	deterministicHistory struct {
+		*historypb.History
+	}
+)
+
+func NewActivities(
+	logger log.Logger,
+	metricsHandler metrics.Handler,
+	metadataManager persistence.MetadataManager,
+	visibilityManager manager.VisibilityManager,
+	executionManager persistence.ExecutionManager,
+	namespaceRegistry namespace.Registry,
+	archivalMetadata carchiver.ArchivalMetadata,
+	archiverProvider provider.ArchiverProvider,
+	currentClusterName string,
+	numHistoryShards int32,
+	sampleRate dynamicconfig.FloatPropertyFn,
+	rps dynamicconfig.FloatPropertyFn,
+	repairEnabled dynamicconfig.BoolPropertyFn,
+	minCloseAge dynamicconfig.DurationPropertyFn,
+) *Activities {
+	return &Activities{
+		logger:             logger,
+		metricsHandler:     metricsHandler.WithTags(metrics.OperationTag(metrics.ArchivalVerifierScope)),
+		metadataManager:    metadataManager,
+		visibilityManager:  visibilityManager,
+		executionManager:   executionManager,
+		namespaceRegistry:  namespaceRegistry,
+		archivalMetadata:   archivalMetadata,
+		archiverProvider:   archiverProvider,
+		currentClusterName: currentClusterName,
+		numHistoryShards:   numHistoryShards,
+		sampleRate:         sampleRate,
+		rps:                rps,
+		repairEnabled:      repairEnabled,
+		minCloseAge:        minCloseAge,
+	}
+}
+
+// ArchivalVerifierWorkflow checks that archived histories of closed executions can be read back and match primary
+// storage, re-archiving the ones that don't while the source still exists. The report of the run is the workflow
+// result. This workflow is a wrapper around the long running VerifyArchivedHistories activity.
+func ArchivalVerifierWorkflow(ctx workflow.Context, input ArchivalVerifierInput) (*Report, error) {
+	activityCtx := workflow.WithActivityOptions(ctx, workflow.ActivityOptions{
+		// Give the activity enough time to verify all namespaces
+		StartToCloseTimeout: 6 * time.Hour,
+		HeartbeatTimeout:    30 * time.Second,
+	})
+	var report Report
+	if err := workflow.ExecuteActivity(activityCtx, ArchivalVerifierActivityName, input).Get(ctx, &report); err != nil {
+		return nil, err
+	}
+	return &report, nil
+}
+
+func (a *Activities) setDefaults(input *ArchivalVerifierInput) {
+	if input.NamespaceListPageSize == 0 {
+		input.NamespaceListPageSize = 100
+	}
+	if input.ExecutionListPageSize == 0 {
+		input.ExecutionListPageSize = 100
+	}
+	if input.HistoryPageSize == 0 {
+		input.HistoryPageSize = 250
+	}
+	if input.MaxReportedProblems == 0 {
+		input.MaxReportedProblems = 100
+	}
+}
+
+func (a *Activities) recordHeartbeat(ctx context.Context, heartbeat *heartbeatDetails) {
+	activity.RecordHeartbeat(ctx, *heartbeat)
+}
+
+// VerifyArchivedHistories verifies a sample of archived histories in all namespaces with history archival enabled.
+func (a *Activities) VerifyArchivedHistories(ctx context.Context, input ArchivalVerifierInput) (*Report, error) {
+	a.setDefaults(&input)
+
+	var heartbeat heartbeatDetails
+	if activity.HasHeartbeatDetails(ctx) {
+		if err := activity.GetHeartbeatDetails(ctx, &heartbeat); err != nil {
+			return nil, temporal.NewNonRetryableApplicationError("failed to load previous heartbeat details", "TypeError", err)
+		}
+	}
+	if !a.archivalMetadata.GetHistoryConfig().ClusterConfiguredForArchival() {
+		return &heartbeat.Report, nil
+	}
+	if heartbeat.CloseTimeCutoff.IsZero() {
+		heartbeat.CloseTimeCutoff = time.Now().Add(-a.minCloseAge()).UTC()
+	}
+
+	rateLimiter := quotas.NewDefaultOutgoingRateLimiter(quotas.RateFn(a.rps))
+	for {
+		nsResponse, err := a.metadataManager.ListNamespaces(ctx, &persistence.ListNamespacesRequest{
+			PageSize:       input.NamespaceListPageSize,
+			NextPageToken:  heartbeat.NamespaceNextPageToken,
+			IncludeDeleted: false, // Archival of deleted namespaces is not verified.
+		})
+		if err != nil {
+			return nil, err
+		}
+		for heartbeat.NamespaceIdx < len(nsResponse.Namespaces) {
+			nsId := nsResponse.Namespaces[heartbeat.NamespaceIdx].Namespace.Info.Id
+			if err := a.processNamespace(ctx, rateLimiter, input, &heartbeat, nsId); err != nil {
+				return nil, err
+			}
+			heartbeat.NamespaceIdx++
+			a.recordHeartbeat(ctx, &heartbeat)
+		}
+		heartbeat.NamespaceIdx = 0
+		heartbeat.NamespaceNextPageToken = nsResponse.NextPageToken
+		if len(heartbeat.NamespaceNextPageToken) == 0 {
+			break
+		}
+		a.recordHeartbeat(ctx, &heartbeat)
+	}
+
+	for nsName, nsReport := range heartbeat.Report.Namespaces {
+		a.logger.Info("Archival verification finished for namespace",
+			tag.WorkflowNamespace(nsName),
+			tag.NewInt64("sampled", nsReport.Sampled),
+			tag.NewInt64("verified", nsReport.Verified),
+			tag.NewInt64("missing", nsReport.Missing),
+			tag.NewInt64("corrupt", nsReport.Corrupt),
+			tag.NewInt64("repaired", nsReport.Repaired),
+			tag.NewInt64("repair-failed", nsReport.RepairFailed),
+		)
+	}
+	return &heartbeat.Report, nil
+}
+
+func (a *Activities) processNamespace(
+	ctx context.Context,
+	rateLimiter quotas.RateLimiter,
+	input ArchivalVerifierInput,
+	heartbeat *heartbeatDetails,
+	nsId string,
+) error {
+	ns, err := a.namespaceRegistry.GetNamespaceByID(namespace.ID(nsId))
+	if err != nil {
+		return err
+	}
+	// Only the active cluster for this namespace archives its histories.
+	if !ns.ActiveInCluster(a.currentClusterName) {
+		return nil
+	}
+	if ns.HistoryArchivalState().State != enumspb.ARCHIVAL_STATE_ENABLED {
+		return nil
+	}
+	uri, err := carchiver.NewURI(ns.HistoryArchivalState().URI)
+	if err != nil {
+		a.logger.Error("Failed to parse history archival URI",
+			tag.WorkflowNamespace(ns.Name().String()),
+			tag.ArchivalURI(ns.HistoryArchivalState().URI),
+			tag.Error(err))
+		return nil
+	}
+	historyArchiver, err := a.archiverProvider.GetHistoryArchiver(uri.Scheme(), string(primitives.WorkerService))
+	if err != nil {
+		a.logger.Error("Failed to get history archiver",
+			tag.WorkflowNamespace(ns.Name().String()),
+			tag.ArchivalURI(uri.String()),
+			tag.Error(err))
+		return nil
+	}
+
+	nsReport := heartbeat.Report.namespace(ns.Name().String())
+	query := fmt.Sprintf("%s != 'Running' AND %s < '%s'",
+		searchattribute.ExecutionStatus,
+		searchattribute.CloseTime,
+		heartbeat.CloseTimeCutoff.Format(time.RFC3339Nano),
+	)
+	for {
+		listResponse, err := a.visibilityManager.ListWorkflowExecutions(ctx, &manager.ListWorkflowExecutionsRequestV2{
+			NamespaceID:   ns.ID(),
+			Namespace:     ns.Name(),
+			PageSize:      input.ExecutionListPageSize,
+			NextPageToken: heartbeat.ExecutionNextPageToken,
+			Query:         query,
+		})
+		if err != nil {
+			return err
+		}
+		for heartbeat.ExecutionIdx < len(listResponse.Executions) {
+			execution := listResponse.Executions[heartbeat.ExecutionIdx]
+			nsReport.Scanned++
+			if rand.Float64() < a.sampleRate() {
+				nsReport.Sampled++
+				if err := rateLimiter.Wait(ctx); err != nil {
+					return context.DeadlineExceeded
+				}
+				problem := Problem{
+					NamespaceID: ns.ID().String(),
+					Namespace:   ns.Name().String(),
+					WorkflowID:  execution.GetExecution().GetWorkflowId(),
+					RunID:       execution.GetExecution().GetRunId(),
+				}
+				if err := a.verifyExecution(ctx, input, heartbeat, nsReport, historyArchiver, uri, problem); err != nil {
+					if common.IsContextDeadlineExceededErr(err) {
+						return err
+					} else if ctx.Err() != nil {
+						return ctx.Err()
+					}
+					// Intentionally don't fail the activity on single execution errors.
+					nsReport.Errors++
+					a.logger.Error("Failed to verify archived history",
+						tag.WorkflowNamespace(problem.Namespace),
+						tag.WorkflowID(problem.WorkflowID),
+						tag.WorkflowRunID(problem.RunID),
+						tag.Error(err))
+				}
+			}
+			heartbeat.ExecutionIdx++
+			a.recordHeartbeat(ctx, heartbeat)
+		}
+		heartbeat.ExecutionIdx = 0
+		heartbeat.ExecutionNextPageToken = listResponse.NextPageToken
+		if len(heartbeat.ExecutionNextPageToken) == 0 {
+			break
+		}
+		a.recordHeartbeat(ctx, heartbeat)
+	}
+	return nil
+}
+
+// verifyExecution checks the archived history of a single execution and repairs it if needed. The given problem
+// carries the identity of the execution and is added to the report if verification fails.
+func (a *Activities) verifyExecution(
+	ctx context.Context,
+	input ArchivalVerifierInput,
+	heartbeat *heartbeatDetails,
+	nsReport *NamespaceReport,
+	historyArchiver carchiver.HistoryArchiver,
+	uri carchiver.URI,
+	problem Problem,
+) error {
+	handler := a.metricsHandler.WithTags(metrics.NamespaceTag(problem.Namespace))
+
+	primary, err := a.readPrimaryHistory(ctx, input, problem)
+	if err != nil {
+		var notFound *serviceerror.NotFound
+		if !errors.As(err, &notFound) {
+			return err
+		}
+		// The source is already deleted, only check that the archived history is readable.
+		primary = nil
+	}
+
+	problemType, details, err := a.checkArchivedHistory(ctx, input, historyArchiver, uri, problem, primary)
+	if err != nil {
+		return err
+	}
+	if problemType == "" {
+		nsReport.Verified++
+		handler.Counter(metrics.ArchivalVerifierVerifiedCount.Name()).Record(1)
+		return nil
+	}
+	problem.Type = problemType
+	problem.Details = details
+	if problemType == ProblemMissing {
+		nsReport.Missing++
+		handler.Counter(metrics.ArchivalVerifierMissingCount.Name()).Record(1)
+	} else {
+		nsReport.Corrupt++
+		handler.Counter(metrics.ArchivalVerifierCorruptCount.Name()).Record(1)
+	}
+
+	if primary != nil && a.repairEnabled() {
+		if err := a.repair(ctx, input, historyArchiver, uri, problem, primary); err != nil {
+			nsReport.RepairFailed++
+			handler.Counter(metrics.ArchivalVerifierRepairFailedCount.Name()).Record(1)
+			problem.Details = fmt.Sprintf("%s; repair failed: %v", problem.Details, err)
+		} else {
+			nsReport.Repaired++
+			handler.Counter(metrics.ArchivalVerifierRepairedCount.Name()).Record(1)
+			problem.Repaired = true
+		}
+	}
+
+	a.logger.Warn("Archived history failed verification",
+		tag.WorkflowNamespace(problem.Namespace),
+		tag.WorkflowID(problem.WorkflowID),
+		tag.WorkflowRunID(problem.RunID),
+		tag.ArchivalURI(uri.String()),
+		tag.NewStringTag("problem", string(problem.Type)),
+		tag.NewStringTag("details", problem.Details),
+		tag.NewBoolTag("repaired", problem.Repaired),
+	)
+	heartbeat.Report.addProblem(problem, input.MaxReportedProblems)
+	return nil
+}
+
+// repair re-archives the history from primary storage and verifies the result.
+func (a *Activities) repair(
+	ctx context.Context,
+	input ArchivalVerifierInput,
+	historyArchiver carchiver.HistoryArchiver,
+	uri carchiver.URI,
+	problem Problem,
+	primary *primaryHistory,
+) error {
+	if err := historyArchiver.Archive(ctx, uri, primary.request); err != nil {
+		return err
+	}
+	problemType, details, err := a.checkArchivedHistory(ctx, input, historyArchiver, uri, problem, primary)
+	if err != nil {
+		return err
+	}
+	if problemType != "" {
+		return fmt.Errorf("%s after re-archival: %s", problemType, details)
+	}
+	return nil
+}
+
+func (a *Activities) readPrimaryHistory(
+	ctx context.Context,
+	input ArchivalVerifierInput,
+	problem Problem,
+) (*primaryHistory, error) {
+	shardID := common.WorkflowIDToHistoryShard(problem.NamespaceID, problem.WorkflowID, a.numHistoryShards)
+	resp, err := a.executionManager.GetWorkflowExecution(ctx, &persistence.GetWorkflowExecutionRequest{
+		ShardID:     shardID,
+		NamespaceID: problem.NamespaceID,
+		WorkflowID:  problem.WorkflowID,
+		RunID:       problem.RunID,
+	})
+	if err != nil {
+		return nil, err
+	}
+	currentVersionHistory, err := versionhistory.GetCurrentVersionHistory(resp.State.ExecutionInfo.VersionHistories)
+	if err != nil {
+		return nil, err
+	}
+	lastItem, err := versionhistory.GetLastVersionHistoryItem(currentVersionHistory)
+	if err != nil {
+		return nil, err
+	}
+
+	request := &carchiver.ArchiveHistoryRequest{
+		ShardID:              shardID,
+		NamespaceID:          problem.NamespaceID,
+		Namespace:            problem.Namespace,
+		WorkflowID:           problem.WorkflowID,
+		RunID:                problem.RunID,
+		BranchToken:          currentVersionHistory.BranchToken,
+		NextEventID:          resp.State.NextEventId,
+		CloseFailoverVersion: lastItem.GetVersion(),
+	}
+	var events []*historypb.HistoryEvent
+	var pageToken []byte
+	for {
+		var page []*historypb.HistoryEvent
+		page, _, pageToken, err = persistence.ReadFullPageEvents(ctx, a.executionManager, &persistence.ReadHistoryBranchRequest{
+			ShardID:       shardID,
+			BranchToken:   request.BranchToken,
+			MinEventID:    common.FirstEventID,
+			MaxEventID:    request.NextEventID,
+			PageSize:      input.HistoryPageSize,
+			NextPageToken: pageToken,
+		})
+		if err != nil {
+			return nil, err
+		}
+		events = append(events, page...)
+		if len(pageToken) == 0 {
+			break
+		}
+	}
+	historyChecksum, err := checksum.GenerateCRC32(deterministicHistory{&historypb.History{Events: events}}, checksumVersion)
+	if err != nil {
+		return nil, err
+	}
+	return &primaryHistory{
+		request:    request,
+		eventCount: len(events),
+		checksum:   historyChecksum,
+	}, nil
+}
+
+// checkArchivedHistory reads the archived history and compares it with primary storage, if the source still exists.
+// It returns an empty problem type if the archived history is valid, and an error only if the check itself failed.
+func (a *Activities) checkArchivedHistory(
+	ctx context.Context,
+	input ArchivalVerifierInput,
+	historyArchiver carchiver.HistoryArchiver,
+	uri carchiver.URI,
+	problem Problem,
+	primary *primaryHistory,
+) (ProblemType, string, error) {
+	request := &carchiver.GetHistoryRequest{
+		NamespaceID: problem.NamespaceID,
+		WorkflowID:  problem.WorkflowID,
+		RunID:       problem.RunID,
+		PageSize:    input.HistoryPageSize,
+	}
+	if primary != nil {
+		closeFailoverVersion := primary.request.CloseFailoverVersion
+		request.CloseFailoverVersion = &closeFailoverVersion
+	}
+
+	var events []*historypb.HistoryEvent
+	for {
+		resp, err := historyArchiver.Get(ctx, uri, request)
+		if err != nil {
+			var notFound *serviceerror.NotFound
+			switch {
+			case errors.As(err, &notFound):
+				return ProblemMissing, err.Error(), nil
+			case isTransientError(err):
+				return "", "", err
+			default:
+				return ProblemUnreadable, err.Error(), nil
+			}
+		}
+		for _, batch := range resp.HistoryBatches {
+			events = append(events, batch.Events...)
+		}
+		if len(resp.NextPageToken) == 0 {
+			break
+		}
+		request.NextPageToken = resp.NextPageToken
+	}
+
+	if primary == nil {
+		if len(events) == 0 {
+			return ProblemUnreadable, "archived history has no events", nil
+		}
+		return "", "", nil
+	}
+	if len(events) != primary.eventCount {
+		return ProblemEventCountMismatch, fmt.Sprintf("archived history has %d events, primary storage has %d", len(events), primary.eventCount), nil
+	}
+	if err := checksum.Verify(deterministicHistory{&historypb.History{Events: events}}, primary.checksum); err != nil {
+		return ProblemChecksumMismatch, err.Error(), nil
+	}
+	return "", "", nil
+}
+
+func (h deterministicHistory) Marshal() ([]byte, error) {
+	return proto.MarshalOptions{Deterministic: true}.Marshal(h.History)
+}
+
+// isTransientError returns true if the archiver failed for a reason unrelated to the archived data itself.
+func isTransientError(err error) bool {
+	switch err.(type) {
+	case *serviceerror.Unavailable, *serviceerror.ResourceExhausted:
+		return true
+	}
+	return common.IsContextDeadlineExceededErr(err) || common.IsContextCanceledErr(err)
+}
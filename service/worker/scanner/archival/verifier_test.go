@@ -0,0 +1,403 @@
+// The MIT License
+//
+// Copyright (c) 2020 Temporal Technologies Inc.  All rights reserved.
+//
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package archival
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+	commonpb "go.temporal.io/api/common/v1"
+	enumspb "go.temporal.io/api/enums/v1"
+	historypb "go.temporal.io/api/history/v1"
+	"go.temporal.io/api/serviceerror"
+	workflowpb "go.temporal.io/api/workflow/v1"
+	"go.temporal.io/sdk/activity"
+	"go.temporal.io/sdk/testsuite"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	historyspb "go.temporal.io/server/api/history/v1"
+	persistencespb "go.temporal.io/server/api/persistence/v1"
+	"go.temporal.io/server/common"
+	carchiver "go.temporal.io/server/common/archiver"
+	"go.temporal.io/server/common/archiver/provider"
+	"go.temporal.io/server/common/dynamicconfig"
+	"go.temporal.io/server/common/log"
+	"go.temporal.io/server/common/metrics"
+	"go.temporal.io/server/common/namespace"
+	"go.temporal.io/server/common/persistence"
+	"go.temporal.io/server/common/persistence/versionhistory"
+	"go.temporal.io/server/common/persistence/visibility/manager"
+	"go.temporal.io/server/common/primitives"
+)
+
+const (
+	testNamespaceID      = "test-namespace-id"
+	testNamespace        = "test-namespace"
+	testWorkflowID       = "test-workflow-id"
+	testRunID            = "test-run-id"
+	testArchivalURI      = "file:///tmp/archival"
+	testClusterName      = "active"
+	testCloseVersion     = int64(100)
+	testNextEventID      = int64(4)
+	testNumHistoryShards = 4
+)
+
+type (
+	verifierSuite struct {
+		suite.Suite
+		testsuite.WorkflowTestSuite
+
+		controller        *gomock.Controller
+		metadataManager   *persistence.MockMetadataManager
+		visibilityManager *manager.MockVisibilityManager
+		executionManager  *persistence.MockExecutionManager
+		namespaceRegistry *namespace.MockRegistry
+		archivalMetadata  *carchiver.MockArchivalMetadata
+		archiverProvider  *provider.MockArchiverProvider
+		historyArchiver   *carchiver.MockHistoryArchiver
+
+		sampleRate    float64
+		repairEnabled bool
+		activities    *Activities
+	}
+)
+
+func TestVerifierSuite(t *testing.T) {
+	suite.Run(t, new(verifierSuite))
+}
+
+func (s *verifierSuite) SetupTest() {
+	s.controller = gomock.NewController(s.T())
+	s.metadataManager = persistence.NewMockMetadataManager(s.controller)
+	s.visibilityManager = manager.NewMockVisibilityManager(s.controller)
+	s.executionManager = persistence.NewMockExecutionManager(s.controller)
+	s.namespaceRegistry = namespace.NewMockRegistry(s.controller)
+	s.archivalMetadata = carchiver.NewMockArchivalMetadata(s.controller)
+	s.archiverProvider = provider.NewMockArchiverProvider(s.controller)
+	s.historyArchiver = carchiver.NewMockHistoryArchiver(s.controller)
+
+	s.sampleRate = 1.0
+	s.repairEnabled = true
+	s.activities = NewActivities(
+		log.NewTestLogger(),
+		metrics.NoopMetricsHandler,
+		s.metadataManager,
+		s.visibilityManager,
+		s.executionManager,
+		s.namespaceRegistry,
+		s.archivalMetadata,
+		s.archiverProvider,
+		testClusterName,
+		testNumHistoryShards,
+		func() float64 { return s.sampleRate },
+		dynamicconfig.GetFloatPropertyFn(1000),
+		func() bool { return s.repairEnabled },
+		dynamicconfig.GetDurationPropertyFn(time.Hour),
+	)
+}
+
+func (s *verifierSuite) TearDownTest() {
+	s.controller.Finish()
+}
+
+func (s *verifierSuite) TestVerify_ClusterNotConfiguredForArchival() {
+	archivalConfig := carchiver.NewMockArchivalConfig(s.controller)
+	archivalConfig.EXPECT().ClusterConfiguredForArchival().Return(false)
+	s.archivalMetadata.EXPECT().GetHistoryConfig().Return(archivalConfig)
+
+	report := s.runActivity()
+	s.Empty(report.Namespaces)
+	s.Empty(report.Problems)
+}
+
+func (s *verifierSuite) TestVerify_ArchivalDisabledForNamespace() {
+	s.expectArchivalConfigured()
+	s.metadataManager.EXPECT().ListNamespaces(gomock.Any(), gomock.Any()).Return(&persistence.ListNamespacesResponse{
+		Namespaces: []*persistence.GetNamespaceResponse{s.namespaceResponse()},
+	}, nil)
+	s.namespaceRegistry.EXPECT().GetNamespaceByID(namespace.ID(testNamespaceID)).Return(
+		namespace.NewLocalNamespaceForTest(
+			&persistencespb.NamespaceInfo{Id: testNamespaceID, Name: testNamespace},
+			&persistencespb.NamespaceConfig{HistoryArchivalState: enumspb.ARCHIVAL_STATE_DISABLED},
+			testClusterName,
+		), nil)
+
+	report := s.runActivity()
+	s.Empty(report.Namespaces)
+}
+
+func (s *verifierSuite) TestVerify_Success() {
+	s.expectExecution()
+	s.expectPrimaryHistory(testEvents())
+	s.historyArchiver.EXPECT().Get(gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ context.Context, uri carchiver.URI, request *carchiver.GetHistoryRequest) (*carchiver.GetHistoryResponse, error) {
+			s.Equal(testArchivalURI, uri.String())
+			s.Equal(testCloseVersion, *request.CloseFailoverVersion)
+			return archivedHistory(testEvents()), nil
+		})
+
+	report := s.runActivity()
+	s.Equal(&NamespaceReport{Scanned: 1, Sampled: 1, Verified: 1}, report.Namespaces[testNamespace])
+	s.Empty(report.Problems)
+}
+
+func (s *verifierSuite) TestVerify_Missing_Repaired() {
+	s.expectExecution()
+	s.expectPrimaryHistory(testEvents())
+	gomock.InOrder(
+		s.historyArchiver.EXPECT().Get(gomock.Any(), gomock.Any(), gomock.Any()).
+			Return(nil, serviceerror.NewNotFound(carchiver.ErrHistoryNotExist.Error())),
+		s.historyArchiver.EXPECT().Archive(gomock.Any(), gomock.Any(), &carchiver.ArchiveHistoryRequest{
+			ShardID:              s.shardID(),
+			NamespaceID:          testNamespaceID,
+			Namespace:            testNamespace,
+			WorkflowID:           testWorkflowID,
+			RunID:                testRunID,
+			BranchToken:          []byte("branch-token"),
+			NextEventID:          testNextEventID,
+			CloseFailoverVersion: testCloseVersion,
+		}).Return(nil),
+		s.historyArchiver.EXPECT().Get(gomock.Any(), gomock.Any(), gomock.Any()).Return(archivedHistory(testEvents()), nil),
+	)
+
+	report := s.runActivity()
+	s.Equal(&NamespaceReport{Scanned: 1, Sampled: 1, Missing: 1, Repaired: 1}, report.Namespaces[testNamespace])
+	s.Len(report.Problems, 1)
+	s.Equal(ProblemMissing, report.Problems[0].Type)
+	s.Equal(testWorkflowID, report.Problems[0].WorkflowID)
+	s.Equal(testRunID, report.Problems[0].RunID)
+	s.True(report.Problems[0].Repaired)
+}
+
+func (s *verifierSuite) TestVerify_ChecksumMismatch_RepairFailed() {
+	s.expectExecution()
+	s.expectPrimaryHistory(testEvents())
+	corrupted := testEvents()
+	corrupted[1].Version = testCloseVersion + 1
+	s.historyArchiver.EXPECT().Get(gomock.Any(), gomock.Any(), gomock.Any()).Return(archivedHistory(corrupted), nil).Times(2)
+	s.historyArchiver.EXPECT().Archive(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil)
+
+	report := s.runActivity()
+	s.Equal(&NamespaceReport{Scanned: 1, Sampled: 1, Corrupt: 1, RepairFailed: 1}, report.Namespaces[testNamespace])
+	s.Len(report.Problems, 1)
+	s.Equal(ProblemChecksumMismatch, report.Problems[0].Type)
+	s.False(report.Problems[0].Repaired)
+}
+
+func (s *verifierSuite) TestVerify_EventCountMismatch_RepairDisabled() {
+	s.repairEnabled = false
+	s.expectExecution()
+	s.expectPrimaryHistory(testEvents())
+	s.historyArchiver.EXPECT().Get(gomock.Any(), gomock.Any(), gomock.Any()).Return(archivedHistory(testEvents()[:2]), nil)
+
+	report := s.runActivity()
+	s.Equal(&NamespaceReport{Scanned: 1, Sampled: 1, Corrupt: 1}, report.Namespaces[testNamespace])
+	s.Len(report.Problems, 1)
+	s.Equal(ProblemEventCountMismatch, report.Problems[0].Type)
+}
+
+func (s *verifierSuite) TestVerify_Unreadable_SourceDeleted() {
+	s.expectExecution()
+	s.executionManager.EXPECT().GetWorkflowExecution(gomock.Any(), gomock.Any()).Return(nil, serviceerror.NewNotFound("workflow not found"))
+	s.historyArchiver.EXPECT().Get(gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ context.Context, _ carchiver.URI, request *carchiver.GetHistoryRequest) (*carchiver.GetHistoryResponse, error) {
+			s.Nil(request.CloseFailoverVersion)
+			return nil, serviceerror.NewInternal(carchiver.ErrHistoryBlobCorrupted.Error())
+		})
+
+	report := s.runActivity()
+	s.Equal(&NamespaceReport{Scanned: 1, Sampled: 1, Corrupt: 1}, report.Namespaces[testNamespace])
+	s.Len(report.Problems, 1)
+	s.Equal(ProblemUnreadable, report.Problems[0].Type)
+	s.False(report.Problems[0].Repaired)
+}
+
+func (s *verifierSuite) TestVerify_TransientArchiverError() {
+	s.expectExecution()
+	s.expectPrimaryHistory(testEvents())
+	s.historyArchiver.EXPECT().Get(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil, serviceerror.NewUnavailable("try again"))
+
+	report := s.runActivity()
+	s.Equal(&NamespaceReport{Scanned: 1, Sampled: 1, Errors: 1}, report.Namespaces[testNamespace])
+	s.Empty(report.Problems)
+}
+
+func (s *verifierSuite) TestVerify_NotSampled() {
+	s.sampleRate = 0
+	s.expectExecution()
+
+	report := s.runActivity()
+	s.Equal(&NamespaceReport{Scanned: 1}, report.Namespaces[testNamespace])
+}
+
+func (s *verifierSuite) TestVerify_ProblemsTruncated() {
+	s.repairEnabled = false
+	s.expectArchivalConfigured()
+	s.expectNamespace()
+	s.visibilityManager.EXPECT().ListWorkflowExecutions(gomock.Any(), gomock.Any()).Return(&manager.ListWorkflowExecutionsResponse{
+		Executions: []*workflowpb.WorkflowExecutionInfo{
+			{Execution: &commonpb.WorkflowExecution{WorkflowId: "wf-1", RunId: "run-1"}},
+			{Execution: &commonpb.WorkflowExecution{WorkflowId: "wf-2", RunId: "run-2"}},
+		},
+	}, nil)
+	s.executionManager.EXPECT().GetWorkflowExecution(gomock.Any(), gomock.Any()).Return(nil, serviceerror.NewNotFound("workflow not found")).Times(2)
+	s.historyArchiver.EXPECT().Get(gomock.Any(), gomock.Any(), gomock.Any()).
+		Return(nil, serviceerror.NewNotFound(carchiver.ErrHistoryNotExist.Error())).Times(2)
+
+	env := s.NewTestActivityEnvironment()
+	env.RegisterActivity(s.activities.VerifyArchivedHistories)
+	result, err := env.ExecuteActivity(s.activities.VerifyArchivedHistories, ArchivalVerifierInput{MaxReportedProblems: 1})
+	s.NoError(err)
+	var report Report
+	s.NoError(result.Get(&report))
+	s.Equal(int64(2), report.Namespaces[testNamespace].Missing)
+	s.Len(report.Problems, 1)
+	s.True(report.ProblemsTruncated)
+}
+
+func (s *verifierSuite) TestWorkflow() {
+	env := s.NewTestWorkflowEnvironment()
+	env.RegisterWorkflow(ArchivalVerifierWorkflow)
+	env.RegisterActivityWithOptions(s.activities.VerifyArchivedHistories, activity.RegisterOptions{Name: ArchivalVerifierActivityName})
+	expected := &Report{
+		Namespaces: map[string]*NamespaceReport{testNamespace: {Scanned: 1, Sampled: 1, Missing: 1}},
+		Problems:   []Problem{{Namespace: testNamespace, WorkflowID: testWorkflowID, RunID: testRunID, Type: ProblemMissing}},
+	}
+	env.OnActivity(ArchivalVerifierActivityName, mock.Anything, mock.Anything).Return(expected, nil)
+
+	env.ExecuteWorkflow(ArchivalVerifierWorkflow, ArchivalVerifierInput{})
+	s.True(env.IsWorkflowCompleted())
+	s.NoError(env.GetWorkflowError())
+	var report Report
+	s.NoError(env.GetWorkflowResult(&report))
+	s.Equal(expected, &report)
+}
+
+func (s *verifierSuite) runActivity() *Report {
+	env := s.NewTestActivityEnvironment()
+	env.RegisterActivity(s.activities.VerifyArchivedHistories)
+	result, err := env.ExecuteActivity(s.activities.VerifyArchivedHistories, ArchivalVerifierInput{})
+	s.NoError(err)
+	var report Report
+	s.NoError(result.Get(&report))
+	return &report
+}
+
+func (s *verifierSuite) expectArchivalConfigured() {
+	archivalConfig := carchiver.NewMockArchivalConfig(s.controller)
+	archivalConfig.EXPECT().ClusterConfiguredForArchival().Return(true).AnyTimes()
+	s.archivalMetadata.EXPECT().GetHistoryConfig().Return(archivalConfig).AnyTimes()
+}
+
+func (s *verifierSuite) namespaceResponse() *persistence.GetNamespaceResponse {
+	return &persistence.GetNamespaceResponse{
+		Namespace: &persistencespb.NamespaceDetail{
+			Info: &persistencespb.NamespaceInfo{Id: testNamespaceID, Name: testNamespace},
+		},
+	}
+}
+
+func (s *verifierSuite) expectNamespace() {
+	s.metadataManager.EXPECT().ListNamespaces(gomock.Any(), gomock.Any()).Return(&persistence.ListNamespacesResponse{
+		Namespaces: []*persistence.GetNamespaceResponse{s.namespaceResponse()},
+	}, nil)
+	s.namespaceRegistry.EXPECT().GetNamespaceByID(namespace.ID(testNamespaceID)).Return(
+		namespace.NewLocalNamespaceForTest(
+			&persistencespb.NamespaceInfo{Id: testNamespaceID, Name: testNamespace},
+			&persistencespb.NamespaceConfig{
+				HistoryArchivalState: enumspb.ARCHIVAL_STATE_ENABLED,
+				HistoryArchivalUri:   testArchivalURI,
+			},
+			testClusterName,
+		), nil)
+	s.archiverProvider.EXPECT().GetHistoryArchiver("file", string(primitives.WorkerService)).Return(s.historyArchiver, nil)
+}
+
+// expectExecution sets up a single namespace with history archival enabled and a single closed execution.
+func (s *verifierSuite) expectExecution() {
+	s.expectArchivalConfigured()
+	s.expectNamespace()
+	s.visibilityManager.EXPECT().ListWorkflowExecutions(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ context.Context, request *manager.ListWorkflowExecutionsRequestV2) (*manager.ListWorkflowExecutionsResponse, error) {
+			s.Equal(namespace.ID(testNamespaceID), request.NamespaceID)
+			s.Contains(request.Query, "ExecutionStatus != 'Running' AND CloseTime < ")
+			return &manager.ListWorkflowExecutionsResponse{
+				Executions: []*workflowpb.WorkflowExecutionInfo{
+					{Execution: &commonpb.WorkflowExecution{WorkflowId: testWorkflowID, RunId: testRunID}},
+				},
+			}, nil
+		})
+}
+
+func (s *verifierSuite) expectPrimaryHistory(events []*historypb.HistoryEvent) {
+	s.executionManager.EXPECT().GetWorkflowExecution(gomock.Any(), &persistence.GetWorkflowExecutionRequest{
+		ShardID:     s.shardID(),
+		NamespaceID: testNamespaceID,
+		WorkflowID:  testWorkflowID,
+		RunID:       testRunID,
+	}).Return(&persistence.GetWorkflowExecutionResponse{
+		State: &persistencespb.WorkflowMutableState{
+			ExecutionInfo: &persistencespb.WorkflowExecutionInfo{
+				VersionHistories: versionhistory.NewVersionHistories(versionhistory.NewVersionHistory(
+					[]byte("branch-token"),
+					[]*historyspb.VersionHistoryItem{versionhistory.NewVersionHistoryItem(testNextEventID-1, testCloseVersion)},
+				)),
+			},
+			NextEventId: testNextEventID,
+		},
+	}, nil)
+	s.executionManager.EXPECT().ReadHistoryBranch(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ context.Context, request *persistence.ReadHistoryBranchRequest) (*persistence.ReadHistoryBranchResponse, error) {
+			s.Equal([]byte("branch-token"), request.BranchToken)
+			s.Equal(testNextEventID, request.MaxEventID)
+			return &persistence.ReadHistoryBranchResponse{HistoryEvents: events}, nil
+		})
+}
+
+func (s *verifierSuite) shardID() int32 {
+	return common.WorkflowIDToHistoryShard(testNamespaceID, testWorkflowID, testNumHistoryShards)
+}
+
+func archivedHistory(events []*historypb.HistoryEvent) *carchiver.GetHistoryResponse {
+	return &carchiver.GetHistoryResponse{
+		HistoryBatches: []*historypb.History{
+			{Events: events[:1]},
+			{Events: events[1:]},
+		},
+	}
+}
+
+func testEvents() []*historypb.HistoryEvent {
+	eventTime := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+	return []*historypb.HistoryEvent{
+		{EventId: 1, Version: testCloseVersion, EventTime: timestamppb.New(eventTime), EventType: enumspb.EVENT_TYPE_WORKFLOW_EXECUTION_STARTED},
+		{EventId: 2, Version: testCloseVersion, EventTime: timestamppb.New(eventTime), EventType: enumspb.EVENT_TYPE_WORKFLOW_TASK_SCHEDULED},
+		{EventId: 3, Version: testCloseVersion, EventTime: timestamppb.New(eventTime), EventType: enumspb.EVENT_TYPE_WORKFLOW_EXECUTION_TERMINATED},
+	}
+}
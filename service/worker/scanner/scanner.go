@@ -39,6 +39,8 @@ import (
 	"go.temporal.io/server/api/historyservice/v1"
 	"go.temporal.io/server/api/matchingservice/v1"
 	"go.temporal.io/server/common"
+	"go.temporal.io/server/common/archiver"
+	"go.temporal.io/server/common/archiver/provider"
 	"go.temporal.io/server/common/config"
 	"go.temporal.io/server/common/headers"
 	"go.temporal.io/server/common/log"
@@ -47,7 +49,9 @@ import (
 	"go.temporal.io/server/common/persistence"
 	"go.temporal.io/server/common/persistence/visibility/manager"
 	"go.temporal.io/server/common/sdk"
+	"go.temporal.io/server/service/worker/scanner/archival"
 	"go.temporal.io/server/service/worker/scanner/build_ids"
+	"go.temporal.io/server/service/worker/scanner/visibility"
 
 	"go.temporal.io/server/common/backoff"
 	"go.temporal.io/server/common/dynamicconfig"
@@ -95,6 +99,28 @@ type (
 		RemovableBuildIdDurationSinceDefault dynamicconfig.DurationPropertyFn
 		// BuildIdScavengerVisibilityRPS is the rate limit for visibility calls from the build id scavenger
 		BuildIdScavengerVisibilityRPS dynamicconfig.FloatPropertyFn
+
+		// ArchivalVerifierEnabled indicates if the archival verifier should be started as part of scanner
+		ArchivalVerifierEnabled dynamicconfig.BoolPropertyFn
+		// ArchivalVerifierSampleRate is the fraction of closed executions whose archived history is verified
+		ArchivalVerifierSampleRate dynamicconfig.FloatPropertyFn
+		// ArchivalVerifierRPS is the rate limit of executions verified per second
+		ArchivalVerifierRPS dynamicconfig.FloatPropertyFn
+		// ArchivalVerifierRepairEnabled indicates if missing or corrupt histories should be re-archived
+		ArchivalVerifierRepairEnabled dynamicconfig.BoolPropertyFn
+		// ArchivalVerifierMinCloseAge is the minimum time since close before an execution is expected to be archived
+		ArchivalVerifierMinCloseAge dynamicconfig.DurationPropertyFn
+
+		// VisibilityScavengerEnabled indicates if the visibility scavenger should be started as part of scanner
+		VisibilityScavengerEnabled dynamicconfig.BoolPropertyFn
+		// VisibilityScavengerSampleRate is the fraction of visibility records checked against mutable state
+		VisibilityScavengerSampleRate dynamicconfig.FloatPropertyFn
+		// VisibilityScavengerRPS is the rate limit of visibility records checked per second
+		VisibilityScavengerRPS dynamicconfig.FloatPropertyFn
+		// VisibilityScavengerRepairEnabled indicates if records that don't match mutable state should be fixed
+		VisibilityScavengerRepairEnabled dynamicconfig.BoolPropertyFn
+		// VisibilityScavengerMinAge is the minimum time since the last update of an execution before it is checked
+		VisibilityScavengerMinAge dynamicconfig.DurationPropertyFn
 	}
 
 	// scannerContext is the context object that gets
@@ -112,6 +138,8 @@ type (
 		matchingClient     matchingservice.MatchingServiceClient
 		adminClient        adminservice.AdminServiceClient
 		namespaceRegistry  namespace.Registry
+		archivalMetadata   archiver.ArchivalMetadata
+		archiverProvider   provider.ArchiverProvider
 		currentClusterName string
 	}
 
@@ -143,6 +171,8 @@ func New(
 	adminClient adminservice.AdminServiceClient,
 	matchingClient matchingservice.MatchingServiceClient,
 	registry namespace.Registry,
+	archivalMetadata archiver.ArchivalMetadata,
+	archiverProvider provider.ArchiverProvider,
 	currentClusterName string,
 ) *Scanner {
 	return &Scanner{
@@ -159,6 +189,8 @@ func New(
 			matchingClient:     matchingClient,
 			adminClient:        adminClient,
 			namespaceRegistry:  registry,
+			archivalMetadata:   archivalMetadata,
+			archiverProvider:   archiverProvider,
 			currentClusterName: currentClusterName,
 		},
 	}
@@ -224,6 +256,67 @@ func (s *Scanner) Start() error {
 		}
 	}
 
+	if s.context.cfg.ArchivalVerifierEnabled() {
+		s.wg.Add(1)
+		go s.startWorkflowWithRetry(ctx, archival.ArchivalVerifierWFStartOptions, archival.ArchivalVerifierWorkflowName)
+
+		archivalActivities := archival.NewActivities(
+			s.context.logger,
+			s.context.metricsHandler,
+			s.context.metadataManager,
+			s.context.visibilityManager,
+			s.context.executionManager,
+			s.context.namespaceRegistry,
+			s.context.archivalMetadata,
+			s.context.archiverProvider,
+			s.context.currentClusterName,
+			s.context.cfg.Persistence.NumHistoryShards,
+			s.context.cfg.ArchivalVerifierSampleRate,
+			s.context.cfg.ArchivalVerifierRPS,
+			s.context.cfg.ArchivalVerifierRepairEnabled,
+			s.context.cfg.ArchivalVerifierMinCloseAge,
+		)
+
+		work := s.context.sdkClientFactory.NewWorker(s.context.sdkClientFactory.GetSystemClient(), archival.ArchivalVerifierTaskQueueName, workerOpts)
+		work.RegisterWorkflowWithOptions(archival.ArchivalVerifierWorkflow, workflow.RegisterOptions{Name: archival.ArchivalVerifierWorkflowName})
+		work.RegisterActivityWithOptions(archivalActivities.VerifyArchivedHistories, activity.RegisterOptions{Name: archival.ArchivalVerifierActivityName})
+
+		// TODO: Nothing is gracefully stopping these workers or listening for fatal errors.
+		if err := work.Start(); err != nil {
+			return err
+		}
+	}
+
+	if s.context.cfg.VisibilityScavengerEnabled() {
+		s.wg.Add(1)
+		go s.startWorkflowWithRetry(ctx, visibility.VisibilityScavengerWFStartOptions, visibility.VisibilityScavengerWorkflowName)
+
+		visibilityActivities := visibility.NewActivities(
+			s.context.logger,
+			s.context.metricsHandler,
+			s.context.metadataManager,
+			s.context.visibilityManager,
+			s.context.executionManager,
+			s.context.historyClient,
+			s.context.namespaceRegistry,
+			s.context.currentClusterName,
+			s.context.cfg.Persistence.NumHistoryShards,
+			s.context.cfg.VisibilityScavengerSampleRate,
+			s.context.cfg.VisibilityScavengerRPS,
+			s.context.cfg.VisibilityScavengerRepairEnabled,
+			s.context.cfg.VisibilityScavengerMinAge,
+		)
+
+		work := s.context.sdkClientFactory.NewWorker(s.context.sdkClientFactory.GetSystemClient(), visibility.VisibilityScavengerTaskQueueName, workerOpts)
+		work.RegisterWorkflowWithOptions(visibility.VisibilityScavengerWorkflow, workflow.RegisterOptions{Name: visibility.VisibilityScavengerWorkflowName})
+		work.RegisterActivityWithOptions(visibilityActivities.ScavengeVisibility, activity.RegisterOptions{Name: visibility.VisibilityScavengerActivityName})
+
+		// TODO: Nothing is gracefully stopping these workers or listening for fatal errors.
+		if err := work.Start(); err != nil {
+			return err
+		}
+	}
+
 	// TODO: There's no reason to register all activities and workflows on every task queue.
 	for _, tl := range workerTaskQueueNames {
 		work := s.context.sdkClientFactory.NewWorker(s.context.sdkClientFactory.GetSystemClient(), tl, workerOpts)
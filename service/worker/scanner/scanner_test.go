@@ -43,7 +43,9 @@ import (
 	p "go.temporal.io/server/common/persistence"
 	"go.temporal.io/server/common/sdk"
 	"go.temporal.io/server/common/testing/mocksdk"
+	"go.temporal.io/server/service/worker/scanner/archival"
 	"go.temporal.io/server/service/worker/scanner/build_ids"
+	"go.temporal.io/server/service/worker/scanner/visibility"
 )
 
 type scannerTestSuite struct {
@@ -76,15 +78,25 @@ func (s *scannerTestSuite) TestScannerEnabled() {
 		WFTypeName:    build_ids.BuildIdScavangerWorkflowName,
 		TaskQueueName: build_ids.BuildIdScavengerTaskQueueName,
 	}
+	archivalVerifier := expectedScanner{
+		WFTypeName:    archival.ArchivalVerifierWorkflowName,
+		TaskQueueName: archival.ArchivalVerifierTaskQueueName,
+	}
+	visibilityScavenger := expectedScanner{
+		WFTypeName:    visibility.VisibilityScavengerWorkflowName,
+		TaskQueueName: visibility.VisibilityScavengerTaskQueueName,
+	}
 
 	type testCase struct {
-		Name                     string
-		ExecutionsScannerEnabled bool
-		TaskQueueScannerEnabled  bool
-		HistoryScannerEnabled    bool
-		BuildIdScavengerEnabled  bool
-		DefaultStore             string
-		ExpectedScanners         []expectedScanner
+		Name                       string
+		ExecutionsScannerEnabled   bool
+		TaskQueueScannerEnabled    bool
+		HistoryScannerEnabled      bool
+		BuildIdScavengerEnabled    bool
+		ArchivalVerifierEnabled    bool
+		VisibilityScavengerEnabled bool
+		DefaultStore               string
+		ExpectedScanners           []expectedScanner
 	}
 
 	for _, c := range []testCase{
@@ -179,13 +191,36 @@ func (s *scannerTestSuite) TestScannerEnabled() {
 			ExpectedScanners:         []expectedScanner{buildIdScavenger},
 		},
 		{
-			Name:                     "AllScannersSQL",
-			ExecutionsScannerEnabled: true,
-			TaskQueueScannerEnabled:  true,
-			HistoryScannerEnabled:    true,
-			BuildIdScavengerEnabled:  true,
-			DefaultStore:             config.StoreTypeSQL,
-			ExpectedScanners:         []expectedScanner{historyScanner, taskQueueScanner, executionScanner, buildIdScavenger},
+			Name:                     "ArchivalVerifierNoSQL",
+			ExecutionsScannerEnabled: false,
+			TaskQueueScannerEnabled:  false,
+			HistoryScannerEnabled:    false,
+			BuildIdScavengerEnabled:  false,
+			ArchivalVerifierEnabled:  true,
+			DefaultStore:             config.StoreTypeNoSQL,
+			ExpectedScanners:         []expectedScanner{archivalVerifier},
+		},
+		{
+			Name:                       "VisibilityScavengerNoSQL",
+			ExecutionsScannerEnabled:   false,
+			TaskQueueScannerEnabled:    false,
+			HistoryScannerEnabled:      false,
+			BuildIdScavengerEnabled:    false,
+			ArchivalVerifierEnabled:    false,
+			VisibilityScavengerEnabled: true,
+			DefaultStore:               config.StoreTypeNoSQL,
+			ExpectedScanners:           []expectedScanner{visibilityScavenger},
+		},
+		{
+			Name:                       "AllScannersSQL",
+			ExecutionsScannerEnabled:   true,
+			TaskQueueScannerEnabled:    true,
+			HistoryScannerEnabled:      true,
+			BuildIdScavengerEnabled:    true,
+			ArchivalVerifierEnabled:    true,
+			VisibilityScavengerEnabled: true,
+			DefaultStore:               config.StoreTypeSQL,
+			ExpectedScanners:           []expectedScanner{historyScanner, taskQueueScanner, executionScanner, buildIdScavenger, archivalVerifier, visibilityScavenger},
 		},
 	} {
 		s.Run(c.Name, func() {
@@ -205,6 +240,8 @@ func (s *scannerTestSuite) TestScannerEnabled() {
 					BuildIdScavengerEnabled:                dynamicconfig.GetBoolPropertyFn(c.BuildIdScavengerEnabled),
 					ExecutionsScannerEnabled:               dynamicconfig.GetBoolPropertyFn(c.ExecutionsScannerEnabled),
 					TaskQueueScannerEnabled:                dynamicconfig.GetBoolPropertyFn(c.TaskQueueScannerEnabled),
+					ArchivalVerifierEnabled:                dynamicconfig.GetBoolPropertyFn(c.ArchivalVerifierEnabled),
+					VisibilityScavengerEnabled:             dynamicconfig.GetBoolPropertyFn(c.VisibilityScavengerEnabled),
 					Persistence: &config.Persistence{
 						DefaultStore: c.DefaultStore,
 						DataStores: map[string]config.DataStore{
@@ -226,6 +263,8 @@ func (s *scannerTestSuite) TestScannerEnabled() {
 				mockAdminClient,
 				nil,
 				mockNamespaceRegistry,
+				nil,
+				nil,
 				"active-cluster",
 			)
 			var wg sync.WaitGroup
@@ -283,6 +322,8 @@ func (s *scannerTestSuite) TestScannerShutdown() {
 			ExecutionsScannerEnabled:               dynamicconfig.GetBoolPropertyFn(false),
 			TaskQueueScannerEnabled:                dynamicconfig.GetBoolPropertyFn(false),
 			BuildIdScavengerEnabled:                dynamicconfig.GetBoolPropertyFn(false),
+			ArchivalVerifierEnabled:                dynamicconfig.GetBoolPropertyFn(false),
+			VisibilityScavengerEnabled:             dynamicconfig.GetBoolPropertyFn(false),
 			Persistence: &config.Persistence{
 				DefaultStore: config.StoreTypeNoSQL,
 				DataStores: map[string]config.DataStore{
@@ -301,6 +342,9 @@ func (s *scannerTestSuite) TestScannerShutdown() {
 		mockAdminClient,
 		nil,
 		mockNamespaceRegistry,
+		// These nils are irrelevant since they're only used by the archival verifier which is not tested here.
+		nil,
+		nil,
 		"active-cluster",
 	)
 	mockSdkClientFactory.EXPECT().GetSystemClient().Return(mockSdkClient).AnyTimes()
@@ -0,0 +1,76 @@
+// The MIT License
+//
+// Copyright (c) 2020 Temporal Technologies Inc.  All rights reserved.
+//
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package visibility
+
+type (
+	// MismatchType classifies a visibility record that doesn't match mutable state.
+	MismatchType string
+
+	// NamespaceReport aggregates the scavenger results of a single namespace.
+	NamespaceReport struct {
+		// Scanned is the number of executions listed from visibility, only the sampled window of start times is listed.
+		Scanned int64
+		// Sampled is the number of scanned executions selected for checking.
+		Sampled    int64
+		Consistent int64
+		// Skipped is the number of sampled executions that were updated too recently to be checked, or whose mutable
+		// state is neither running nor completed.
+		Skipped                  int64
+		Orphaned                 int64
+		StatusMismatch           int64
+		SearchAttributesMismatch int64
+		// Fixed is the number of mismatches for which a visibility task was enqueued or the orphaned record deleted.
+		Fixed     int64
+		FixFailed int64
+		// Errors is the number of executions that could not be checked, e.g. because primary storage was unavailable.
+		Errors int64
+	}
+
+	// Report is the result of a visibility scavenger run.
+	Report struct {
+		Namespaces map[string]*NamespaceReport
+	}
+)
+
+const (
+	// MismatchOrphaned means the visibility record has no mutable state, e.g. because the execution was deleted.
+	MismatchOrphaned MismatchType = "Orphaned"
+	// MismatchStatus means the visibility record has a different execution status than mutable state.
+	MismatchStatus MismatchType = "StatusMismatch"
+	// MismatchSearchAttributes means the visibility record has different search attributes than mutable state.
+	MismatchSearchAttributes MismatchType = "SearchAttributesMismatch"
+)
+
+func (r *Report) namespace(name string) *NamespaceReport {
+	if r.Namespaces == nil {
+		r.Namespaces = make(map[string]*NamespaceReport)
+	}
+	nsReport, ok := r.Namespaces[name]
+	if !ok {
+		nsReport = &NamespaceReport{}
+		r.Namespaces[name] = nsReport
+	}
+	return nsReport
+}
@@ -0,0 +1,568 @@
+// The MIT License
+//
+// Copyright (c) 2020 Temporal Technologies Inc.  All rights reserved.
+//
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package visibility
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"math/rand"
+	"reflect"
+	"sort"
+	"time"
+
+	commonpb "go.temporal.io/api/common/v1"
+	enumspb "go.temporal.io/api/enums/v1"
+	"go.temporal.io/api/serviceerror"
+	workflowpb "go.temporal.io/api/workflow/v1"
+	"go.temporal.io/sdk/activity"
+	"go.temporal.io/sdk/client"
+	"go.temporal.io/sdk/temporal"
+	"go.temporal.io/sdk/workflow"
+
+	enumsspb "go.temporal.io/server/api/enums/v1"
+	"go.temporal.io/server/api/historyservice/v1"
+	persistencespb "go.temporal.io/server/api/persistence/v1"
+	"go.temporal.io/server/common"
+	"go.temporal.io/server/common/definition"
+	"go.temporal.io/server/common/dynamicconfig"
+	"go.temporal.io/server/common/log"
+	"go.temporal.io/server/common/log/tag"
+	"go.temporal.io/server/common/metrics"
+	"go.temporal.io/server/common/namespace"
+	"go.temporal.io/server/common/persistence"
+	"go.temporal.io/server/common/persistence/serialization"
+	"go.temporal.io/server/common/persistence/versionhistory"
+	"go.temporal.io/server/common/persistence/visibility/manager"
+	"go.temporal.io/server/common/quotas"
+	"go.temporal.io/server/common/searchattribute"
+	"go.temporal.io/server/service/history/tasks"
+)
+
+const (
+	VisibilityScavengerWorkflowName = "visibility-scavenger"
+	VisibilityScavengerActivityName = "scavenge-visibility"
+
+	VisibilityScavengerWFID          = "temporal-sys-visibility-scavenger"
+	VisibilityScavengerTaskQueueName = "temporal-sys-visibility-scavenger-taskqueue-0"
+
+	// defaultSamplingSpan is the range of start times sampled in namespaces without retention.
+	defaultSamplingSpan = 30 * 24 * time.Hour
+)
+
+var (
+	VisibilityScavengerWFStartOptions = client.StartWorkflowOptions{
+		ID:                    VisibilityScavengerWFID,
+		TaskQueue:             VisibilityScavengerTaskQueueName,
+		WorkflowIDReusePolicy: enumspb.WORKFLOW_ID_REUSE_POLICY_ALLOW_DUPLICATE,
+		CronSchedule:          "0 */12 * * *",
+	}
+)
+
+type (
+	VisibilityScavengerInput struct {
+		NamespaceListPageSize int
+		ExecutionListPageSize int
+	}
+
+	Activities struct {
+		logger             log.Logger
+		metricsHandler     metrics.Handler
+		metadataManager    persistence.MetadataManager
+		visibilityManager  manager.VisibilityManager
+		executionManager   persistence.ExecutionManager
+		historyClient      historyservice.HistoryServiceClient
+		namespaceRegistry  namespace.Registry
+		taskSerializer     *serialization.TaskSerializer
+		currentClusterName string
+		numHistoryShards   int32
+
+		sampleRate    dynamicconfig.FloatPropertyFn
+		rps           dynamicconfig.FloatPropertyFn
+		repairEnabled dynamicconfig.BoolPropertyFn
+		// Executions updated more recently than minAge may still have visibility tasks in flight.
+		minAge dynamicconfig.DurationPropertyFn
+	}
+
+	heartbeatDetails struct {
+		NamespaceIdx           int
+		ExecutionIdx           int
+		NamespaceNextPageToken []byte
+		ExecutionNextPageToken []byte
+		// StartTimeCutoff is fixed for the whole run so that visibility page tokens stay valid across retries.
+		StartTimeCutoff time.Time
+		// WindowStart and WindowEnd are the start times sampled in the current namespace. WindowEnd is zero until the
+		// window is picked and WindowStart is zero if the window has no lower bound.
+		WindowStart time.Time
+		WindowEnd   time.Time
+		Report      Report
+	}
+)
+
+func NewActivities(
+	logger log.Logger,
+	metricsHandler metrics.Handler,
+	metadataManager persistence.MetadataManager,
+	visibilityManager manager.VisibilityManager,
+	executionManager persistence.ExecutionManager,
+	historyClient historyservice.HistoryServiceClient,
+	namespaceRegistry namespace.Registry,
+	currentClusterName string,
+	numHistoryShards int32,
+	sampleRate dynamicconfig.FloatPropertyFn,
+	rps dynamicconfig.FloatPropertyFn,
+	repairEnabled dynamicconfig.BoolPropertyFn,
+	minAge dynamicconfig.DurationPropertyFn,
+) *Activities {
+	return &Activities{
+		logger:             logger,
+		metricsHandler:     metricsHandler.WithTags(metrics.OperationTag(metrics.VisibilityScavengerScope)),
+		metadataManager:    metadataManager,
+		visibilityManager:  visibilityManager,
+		executionManager:   executionManager,
+		historyClient:      historyClient,
+		namespaceRegistry:  namespaceRegistry,
+		taskSerializer:     serialization.NewTaskSerializer(),
+		currentClusterName: currentClusterName,
+		numHistoryShards:   numHistoryShards,
+		sampleRate:         sampleRate,
+		rps:                rps,
+		repairEnabled:      repairEnabled,
+		minAge:             minAge,
+	}
+}
+
+// VisibilityScavengerWorkflow checks a sample of visibility records against mutable state, re-enqueuing visibility
+// tasks for records that drifted and deleting records of executions that no longer exist. The report of the run is
+// the workflow result. This workflow is a wrapper around the long running ScavengeVisibility activity.
+func VisibilityScavengerWorkflow(ctx workflow.Context, input VisibilityScavengerInput) (*Report, error) {
+	activityCtx := workflow.WithActivityOptions(ctx, workflow.ActivityOptions{
+		// Give the activity enough time to scan all namespaces
+		StartToCloseTimeout: 6 * time.Hour,
+		HeartbeatTimeout:    30 * time.Second,
+	})
+	var report Report
+	if err := workflow.ExecuteActivity(activityCtx, VisibilityScavengerActivityName, input).Get(ctx, &report); err != nil {
+		return nil, err
+	}
+	return &report, nil
+}
+
+func (a *Activities) setDefaults(input *VisibilityScavengerInput) {
+	if input.NamespaceListPageSize == 0 {
+		input.NamespaceListPageSize = 100
+	}
+	if input.ExecutionListPageSize == 0 {
+		input.ExecutionListPageSize = 100
+	}
+}
+
+func (a *Activities) recordHeartbeat(ctx context.Context, heartbeat *heartbeatDetails) {
+	activity.RecordHeartbeat(ctx, *heartbeat)
+}
+
+// ScavengeVisibility checks a sample of visibility records in all namespaces active in the current cluster.
+func (a *Activities) ScavengeVisibility(ctx context.Context, input VisibilityScavengerInput) (*Report, error) {
+	a.setDefaults(&input)
+
+	var heartbeat heartbeatDetails
+	if activity.HasHeartbeatDetails(ctx) {
+		if err := activity.GetHeartbeatDetails(ctx, &heartbeat); err != nil {
+			return nil, temporal.NewNonRetryableApplicationError("failed to load previous heartbeat details", "TypeError", err)
+		}
+	}
+	if heartbeat.StartTimeCutoff.IsZero() {
+		heartbeat.StartTimeCutoff = time.Now().Add(-a.minAge()).UTC()
+	}
+
+	rateLimiter := quotas.NewDefaultOutgoingRateLimiter(quotas.RateFn(a.rps))
+	for {
+		nsResponse, err := a.metadataManager.ListNamespaces(ctx, &persistence.ListNamespacesRequest{
+			PageSize:       input.NamespaceListPageSize,
+			NextPageToken:  heartbeat.NamespaceNextPageToken,
+			IncludeDeleted: false, // Visibility records of deleted namespaces are removed by the namespace delete workflow.
+		})
+		if err != nil {
+			return nil, err
+		}
+		for heartbeat.NamespaceIdx < len(nsResponse.Namespaces) {
+			nsId := nsResponse.Namespaces[heartbeat.NamespaceIdx].Namespace.Info.Id
+			if err := a.processNamespace(ctx, rateLimiter, input, &heartbeat, nsId); err != nil {
+				return nil, err
+			}
+			heartbeat.NamespaceIdx++
+			a.recordHeartbeat(ctx, &heartbeat)
+		}
+		heartbeat.NamespaceIdx = 0
+		heartbeat.NamespaceNextPageToken = nsResponse.NextPageToken
+		if len(heartbeat.NamespaceNextPageToken) == 0 {
+			break
+		}
+		a.recordHeartbeat(ctx, &heartbeat)
+	}
+
+	for nsName, nsReport := range heartbeat.Report.Namespaces {
+		a.logger.Info("Visibility scavenger finished for namespace",
+			tag.WorkflowNamespace(nsName),
+			tag.NewInt64("sampled", nsReport.Sampled),
+			tag.NewInt64("consistent", nsReport.Consistent),
+			tag.NewInt64("orphaned", nsReport.Orphaned),
+			tag.NewInt64("status-mismatch", nsReport.StatusMismatch),
+			tag.NewInt64("search-attributes-mismatch", nsReport.SearchAttributesMismatch),
+			tag.NewInt64("fixed", nsReport.Fixed),
+			tag.NewInt64("fix-failed", nsReport.FixFailed),
+		)
+	}
+	return &heartbeat.Report, nil
+}
+
+func (a *Activities) processNamespace(
+	ctx context.Context,
+	rateLimiter quotas.RateLimiter,
+	input VisibilityScavengerInput,
+	heartbeat *heartbeatDetails,
+	nsId string,
+) error {
+	ns, err := a.namespaceRegistry.GetNamespaceByID(namespace.ID(nsId))
+	if err != nil {
+		return err
+	}
+	// Mutable state of standby namespaces may lag behind replication, only the active cluster checks them.
+	if !ns.ActiveInCluster(a.currentClusterName) {
+		return nil
+	}
+
+	sampleRate := a.sampleRate()
+	if sampleRate <= 0 {
+		return nil
+	}
+	nsReport := heartbeat.Report.namespace(ns.Name().String())
+	if heartbeat.WindowEnd.IsZero() {
+		span := ns.Retention()
+		if span <= 0 {
+			span = defaultSamplingSpan
+		}
+		heartbeat.WindowStart, heartbeat.WindowEnd = sampleWindow(heartbeat.StartTimeCutoff, span, sampleRate)
+	}
+	query := fmt.Sprintf("%s < '%s'",
+		searchattribute.StartTime,
+		heartbeat.WindowEnd.Format(time.RFC3339Nano),
+	)
+	if !heartbeat.WindowStart.IsZero() {
+		query = fmt.Sprintf("%s >= '%s' and %s",
+			searchattribute.StartTime,
+			heartbeat.WindowStart.Format(time.RFC3339Nano),
+			query,
+		)
+	}
+	for {
+		listResponse, err := a.visibilityManager.ListWorkflowExecutions(ctx, &manager.ListWorkflowExecutionsRequestV2{
+			NamespaceID:   ns.ID(),
+			Namespace:     ns.Name(),
+			PageSize:      input.ExecutionListPageSize,
+			NextPageToken: heartbeat.ExecutionNextPageToken,
+			Query:         query,
+		})
+		if err != nil {
+			return err
+		}
+		for heartbeat.ExecutionIdx < len(listResponse.Executions) {
+			execution := listResponse.Executions[heartbeat.ExecutionIdx]
+			nsReport.Scanned++
+			nsReport.Sampled++
+			if err := rateLimiter.Wait(ctx); err != nil {
+				return context.DeadlineExceeded
+			}
+			if err := a.checkExecution(ctx, ns, nsReport, execution); err != nil {
+				if common.IsContextDeadlineExceededErr(err) {
+					return err
+				} else if ctx.Err() != nil {
+					return ctx.Err()
+				}
+				// Intentionally don't fail the activity on single execution errors.
+				nsReport.Errors++
+				a.logger.Error("Failed to check visibility record",
+					tag.WorkflowNamespace(ns.Name().String()),
+					tag.WorkflowID(execution.GetExecution().GetWorkflowId()),
+					tag.WorkflowRunID(execution.GetExecution().GetRunId()),
+					tag.Error(err))
+			}
+			heartbeat.ExecutionIdx++
+			a.recordHeartbeat(ctx, heartbeat)
+		}
+		heartbeat.ExecutionIdx = 0
+		heartbeat.ExecutionNextPageToken = listResponse.NextPageToken
+		if len(heartbeat.ExecutionNextPageToken) == 0 {
+			break
+		}
+		a.recordHeartbeat(ctx, heartbeat)
+	}
+	heartbeat.WindowStart, heartbeat.WindowEnd = time.Time{}, time.Time{}
+	return nil
+}
+
+// sampleWindow splits the span of start times before the cutoff into 1/sampleRate windows and returns one of them at
+// random, so the scavenger only lists the records it checks. The oldest window has no lower bound, it also covers the
+// executions started before the span. A zero start means no lower bound.
+func sampleWindow(cutoff time.Time, span time.Duration, sampleRate float64) (time.Time, time.Time) {
+	windows := int(math.Ceil(1 / sampleRate))
+	if windows <= 1 {
+		return time.Time{}, cutoff
+	}
+	width := span / time.Duration(windows)
+	idx := rand.Intn(windows)
+	end := cutoff.Add(-time.Duration(idx) * width)
+	if idx == windows-1 {
+		return time.Time{}, end
+	}
+	return end.Add(-width), end
+}
+
+// checkExecution compares a single visibility record with mutable state and fixes it if needed.
+func (a *Activities) checkExecution(
+	ctx context.Context,
+	ns *namespace.Namespace,
+	nsReport *NamespaceReport,
+	execution *workflowpb.WorkflowExecutionInfo,
+) error {
+	handler := a.metricsHandler.WithTags(metrics.NamespaceTag(ns.Name().String()))
+	workflowID := execution.GetExecution().GetWorkflowId()
+	runID := execution.GetExecution().GetRunId()
+	shardID := common.WorkflowIDToHistoryShard(ns.ID().String(), workflowID, a.numHistoryShards)
+
+	var mutableState *persistencespb.WorkflowMutableState
+	var mismatch MismatchType
+	var details string
+	resp, err := a.executionManager.GetWorkflowExecution(ctx, &persistence.GetWorkflowExecutionRequest{
+		ShardID:     shardID,
+		NamespaceID: ns.ID().String(),
+		WorkflowID:  workflowID,
+		RunID:       runID,
+	})
+	if err != nil {
+		var notFound *serviceerror.NotFound
+		if !errors.As(err, &notFound) {
+			return err
+		}
+		mismatch = MismatchOrphaned
+		details = "mutable state not found"
+	} else {
+		mutableState = resp.State
+		switch mutableState.GetExecutionState().GetState() {
+		case enumsspb.WORKFLOW_EXECUTION_STATE_RUNNING, enumsspb.WORKFLOW_EXECUTION_STATE_COMPLETED:
+		default:
+			// Created and zombie executions have no settled visibility record yet.
+			nsReport.Skipped++
+			return nil
+		}
+		if time.Since(mutableState.GetExecutionInfo().GetLastUpdateTime().AsTime()) < a.minAge() {
+			nsReport.Skipped++
+			return nil
+		}
+		mismatch, details = compareExecution(execution, mutableState)
+	}
+
+	switch mismatch {
+	case "":
+		nsReport.Consistent++
+		handler.Counter(metrics.VisibilityScavengerConsistentCount.Name()).Record(1)
+		return nil
+	case MismatchOrphaned:
+		nsReport.Orphaned++
+		handler.Counter(metrics.VisibilityScavengerOrphanedCount.Name()).Record(1)
+	case MismatchStatus:
+		nsReport.StatusMismatch++
+		handler.Counter(metrics.VisibilityScavengerStatusMismatchCount.Name()).Record(1)
+	case MismatchSearchAttributes:
+		nsReport.SearchAttributesMismatch++
+		handler.Counter(metrics.VisibilityScavengerSearchAttributesMismatchCount.Name()).Record(1)
+	}
+
+	fixed := false
+	if a.repairEnabled() {
+		if err := a.fix(ctx, ns, shardID, execution, mutableState); err != nil {
+			nsReport.FixFailed++
+			handler.Counter(metrics.VisibilityScavengerFixFailedCount.Name()).Record(1)
+			details = fmt.Sprintf("%s; fix failed: %v", details, err)
+		} else {
+			nsReport.Fixed++
+			handler.Counter(metrics.VisibilityScavengerFixedCount.Name()).Record(1)
+			fixed = true
+		}
+	}
+
+	a.logger.Warn("Visibility record doesn't match mutable state",
+		tag.WorkflowNamespace(ns.Name().String()),
+		tag.WorkflowID(workflowID),
+		tag.WorkflowRunID(runID),
+		tag.NewStringTag("mismatch", string(mismatch)),
+		tag.NewStringTag("details", details),
+		tag.NewBoolTag("fixed", fixed),
+	)
+	return nil
+}
+
+// fix deletes the visibility record of an execution without mutable state, and otherwise asks history to rewrite
+// the record from mutable state: a close visibility task for completed executions, an upsert for running ones.
+func (a *Activities) fix(
+	ctx context.Context,
+	ns *namespace.Namespace,
+	shardID int32,
+	execution *workflowpb.WorkflowExecutionInfo,
+	mutableState *persistencespb.WorkflowMutableState,
+) error {
+	if mutableState == nil {
+		_, err := a.historyClient.DeleteWorkflowVisibilityRecord(ctx, &historyservice.DeleteWorkflowVisibilityRecordRequest{
+			NamespaceId:       ns.ID().String(),
+			Execution:         execution.GetExecution(),
+			WorkflowStartTime: execution.GetStartTime(),
+			WorkflowCloseTime: execution.GetCloseTime(),
+		})
+		return err
+	}
+
+	currentVersionHistory, err := versionhistory.GetCurrentVersionHistory(mutableState.GetExecutionInfo().GetVersionHistories())
+	if err != nil {
+		return err
+	}
+	lastItem, err := versionhistory.GetLastVersionHistoryItem(currentVersionHistory)
+	if err != nil {
+		return err
+	}
+	workflowKey := definition.NewWorkflowKey(
+		ns.ID().String(),
+		execution.GetExecution().GetWorkflowId(),
+		execution.GetExecution().GetRunId(),
+	)
+	// TaskID and VisibilityTimestamp are set by the shard.
+	var task tasks.Task
+	if mutableState.GetExecutionState().GetState() == enumsspb.WORKFLOW_EXECUTION_STATE_COMPLETED {
+		task = &tasks.CloseExecutionVisibilityTask{
+			WorkflowKey: workflowKey,
+			Version:     lastItem.GetVersion(),
+		}
+	} else {
+		task = &tasks.UpsertExecutionVisibilityTask{
+			WorkflowKey: workflowKey,
+			Version:     lastItem.GetVersion(),
+		}
+	}
+	blob, err := a.taskSerializer.SerializeTask(task)
+	if err != nil {
+		return err
+	}
+	_, err = a.historyClient.AddTasks(ctx, &historyservice.AddTasksRequest{
+		ShardId: shardID,
+		Tasks: []*historyservice.AddTasksRequest_Task{
+			{
+				CategoryId: int32(tasks.CategoryIDVisibility),
+				Blob:       blob,
+			},
+		},
+	})
+	return err
+}
+
+// compareExecution returns the first difference between a visibility record and mutable state, or an empty
+// mismatch type if they match.
+func compareExecution(
+	execution *workflowpb.WorkflowExecutionInfo,
+	mutableState *persistencespb.WorkflowMutableState,
+) (MismatchType, string) {
+	if status := mutableState.GetExecutionState().GetStatus(); execution.GetStatus() != status {
+		return MismatchStatus, fmt.Sprintf("visibility status is %v, mutable state status is %v", execution.GetStatus(), status)
+	}
+	if name, equal := compareSearchAttributes(
+		execution.GetSearchAttributes().GetIndexedFields(),
+		mutableState.GetExecutionInfo().GetSearchAttributes(),
+	); !equal {
+		return MismatchSearchAttributes, fmt.Sprintf("search attribute %s differs", name)
+	}
+	return "", ""
+}
+
+// compareSearchAttributes returns the name of the first search attribute with different values, and false, if the
+// visibility record and mutable state don't have the same search attributes.
+func compareSearchAttributes(visibilityAttributes, mutableStateAttributes map[string]*commonpb.Payload) (string, bool) {
+	names := make([]string, 0, len(visibilityAttributes)+len(mutableStateAttributes))
+	for name := range visibilityAttributes {
+		names = append(names, name)
+	}
+	for name := range mutableStateAttributes {
+		if _, ok := visibilityAttributes[name]; !ok {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		visibilityValue, err := decodeSearchAttribute(visibilityAttributes[name])
+		if err != nil {
+			return name, false
+		}
+		mutableStateValue, err := decodeSearchAttribute(mutableStateAttributes[name])
+		if err != nil {
+			return name, false
+		}
+		if !reflect.DeepEqual(visibilityValue, mutableStateValue) {
+			return name, false
+		}
+	}
+	return "", true
+}
+
+// decodeSearchAttribute decodes a search attribute value so that equal values compare equal regardless of how each
+// store encodes them. A missing value decodes to nil.
+func decodeSearchAttribute(value *commonpb.Payload) (any, error) {
+	if value == nil {
+		return nil, nil
+	}
+	decoded, err := searchattribute.DecodeValue(value, enumspb.INDEXED_VALUE_TYPE_UNSPECIFIED, true)
+	if err != nil {
+		return nil, err
+	}
+	return normalizeSearchAttribute(decoded), nil
+}
+
+func normalizeSearchAttribute(value any) any {
+	if t, ok := value.(time.Time); ok {
+		// Visibility stores may keep datetimes with a lower precision than mutable state.
+		return t.UTC().Truncate(time.Millisecond)
+	}
+	rv := reflect.ValueOf(value)
+	if rv.Kind() != reflect.Slice {
+		return value
+	}
+	// A single value and a list with only that value are equivalent.
+	if rv.Len() == 1 {
+		return normalizeSearchAttribute(rv.Index(0).Interface())
+	}
+	normalized := make([]any, rv.Len())
+	for i := range normalized {
+		normalized[i] = normalizeSearchAttribute(rv.Index(i).Interface())
+	}
+	return normalized
+}
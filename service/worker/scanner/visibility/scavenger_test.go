@@ -0,0 +1,448 @@
+// The MIT License
+//
+// Copyright (c) 2020 Temporal Technologies Inc.  All rights reserved.
+//
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package visibility
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/suite"
+	commonpb "go.temporal.io/api/common/v1"
+	enumspb "go.temporal.io/api/enums/v1"
+	"go.temporal.io/api/serviceerror"
+	workflowpb "go.temporal.io/api/workflow/v1"
+	"go.temporal.io/sdk/testsuite"
+	"google.golang.org/protobuf/types/known/durationpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	enumsspb "go.temporal.io/server/api/enums/v1"
+	historyspb "go.temporal.io/server/api/history/v1"
+	"go.temporal.io/server/api/historyservice/v1"
+	"go.temporal.io/server/api/historyservicemock/v1"
+	persistencespb "go.temporal.io/server/api/persistence/v1"
+	"go.temporal.io/server/common"
+	"go.temporal.io/server/common/dynamicconfig"
+	"go.temporal.io/server/common/log"
+	"go.temporal.io/server/common/metrics"
+	"go.temporal.io/server/common/namespace"
+	"go.temporal.io/server/common/persistence"
+	"go.temporal.io/server/common/persistence/serialization"
+	"go.temporal.io/server/common/persistence/versionhistory"
+	"go.temporal.io/server/common/persistence/visibility/manager"
+	"go.temporal.io/server/common/searchattribute"
+	"go.temporal.io/server/service/history/tasks"
+)
+
+const (
+	testNamespaceID      = "test-namespace-id"
+	testNamespace        = "test-namespace"
+	testWorkflowID       = "test-workflow-id"
+	testRunID            = "test-run-id"
+	testClusterName      = "active"
+	testLastWriteVersion = int64(100)
+	testNumHistoryShards = 4
+)
+
+type (
+	scavengerSuite struct {
+		suite.Suite
+		testsuite.WorkflowTestSuite
+
+		controller        *gomock.Controller
+		metadataManager   *persistence.MockMetadataManager
+		visibilityManager *manager.MockVisibilityManager
+		executionManager  *persistence.MockExecutionManager
+		historyClient     *historyservicemock.MockHistoryServiceClient
+		namespaceRegistry *namespace.MockRegistry
+
+		repairEnabled bool
+		sampleRate    float64
+		activities    *Activities
+	}
+)
+
+func TestScavengerSuite(t *testing.T) {
+	suite.Run(t, new(scavengerSuite))
+}
+
+func (s *scavengerSuite) SetupTest() {
+	s.controller = gomock.NewController(s.T())
+	s.metadataManager = persistence.NewMockMetadataManager(s.controller)
+	s.visibilityManager = manager.NewMockVisibilityManager(s.controller)
+	s.executionManager = persistence.NewMockExecutionManager(s.controller)
+	s.historyClient = historyservicemock.NewMockHistoryServiceClient(s.controller)
+	s.namespaceRegistry = namespace.NewMockRegistry(s.controller)
+
+	s.repairEnabled = true
+	s.sampleRate = 1.0
+	s.activities = NewActivities(
+		log.NewTestLogger(),
+		metrics.NoopMetricsHandler,
+		s.metadataManager,
+		s.visibilityManager,
+		s.executionManager,
+		s.historyClient,
+		s.namespaceRegistry,
+		testClusterName,
+		testNumHistoryShards,
+		func() float64 { return s.sampleRate },
+		dynamicconfig.GetFloatPropertyFn(1000),
+		func() bool { return s.repairEnabled },
+		dynamicconfig.GetDurationPropertyFn(time.Hour),
+	)
+}
+
+func (s *scavengerSuite) TearDownTest() {
+	s.controller.Finish()
+}
+
+func (s *scavengerSuite) TestScavenge_StandbyNamespace() {
+	s.metadataManager.EXPECT().ListNamespaces(gomock.Any(), gomock.Any()).Return(&persistence.ListNamespacesResponse{
+		Namespaces: []*persistence.GetNamespaceResponse{s.namespaceResponse()},
+	}, nil)
+	s.namespaceRegistry.EXPECT().GetNamespaceByID(namespace.ID(testNamespaceID)).Return(
+		namespace.NewGlobalNamespaceForTest(
+			&persistencespb.NamespaceInfo{Id: testNamespaceID, Name: testNamespace},
+			nil,
+			&persistencespb.NamespaceReplicationConfig{
+				ActiveClusterName: "standby",
+				Clusters:          []string{testClusterName, "standby"},
+			},
+			testLastWriteVersion,
+		), nil)
+
+	report := s.runActivity()
+	s.Empty(report.Namespaces)
+}
+
+func (s *scavengerSuite) TestScavenge_Consistent() {
+	s.expectExecution(enumspb.WORKFLOW_EXECUTION_STATUS_RUNNING, s.keywordAttributes("foo"))
+	s.expectMutableState(enumsspb.WORKFLOW_EXECUTION_STATE_RUNNING, enumspb.WORKFLOW_EXECUTION_STATUS_RUNNING, s.keywordAttributes("foo"))
+
+	report := s.runActivity()
+	s.Equal(&NamespaceReport{Scanned: 1, Sampled: 1, Consistent: 1}, report.Namespaces[testNamespace])
+}
+
+func (s *scavengerSuite) TestScavenge_Orphaned_Deleted() {
+	s.expectExecution(enumspb.WORKFLOW_EXECUTION_STATUS_RUNNING, nil)
+	s.executionManager.EXPECT().GetWorkflowExecution(gomock.Any(), gomock.Any()).Return(nil, serviceerror.NewNotFound("not found"))
+	s.historyClient.EXPECT().DeleteWorkflowVisibilityRecord(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ context.Context, request *historyservice.DeleteWorkflowVisibilityRecordRequest, _ ...any) (*historyservice.DeleteWorkflowVisibilityRecordResponse, error) {
+			s.Equal(testNamespaceID, request.NamespaceId)
+			s.Equal(testWorkflowID, request.Execution.GetWorkflowId())
+			s.Equal(testRunID, request.Execution.GetRunId())
+			return &historyservice.DeleteWorkflowVisibilityRecordResponse{}, nil
+		})
+
+	report := s.runActivity()
+	s.Equal(&NamespaceReport{Scanned: 1, Sampled: 1, Orphaned: 1, Fixed: 1}, report.Namespaces[testNamespace])
+}
+
+func (s *scavengerSuite) TestScavenge_StatusMismatch_CloseTaskEnqueued() {
+	s.expectExecution(enumspb.WORKFLOW_EXECUTION_STATUS_RUNNING, nil)
+	s.expectMutableState(enumsspb.WORKFLOW_EXECUTION_STATE_COMPLETED, enumspb.WORKFLOW_EXECUTION_STATUS_COMPLETED, nil)
+	s.expectAddTask(&tasks.CloseExecutionVisibilityTask{})
+
+	report := s.runActivity()
+	s.Equal(&NamespaceReport{Scanned: 1, Sampled: 1, StatusMismatch: 1, Fixed: 1}, report.Namespaces[testNamespace])
+}
+
+func (s *scavengerSuite) TestScavenge_SearchAttributesMismatch_UpsertTaskEnqueued() {
+	s.expectExecution(enumspb.WORKFLOW_EXECUTION_STATUS_RUNNING, s.keywordAttributes("foo"))
+	s.expectMutableState(enumsspb.WORKFLOW_EXECUTION_STATE_RUNNING, enumspb.WORKFLOW_EXECUTION_STATUS_RUNNING, s.keywordAttributes("bar"))
+	s.expectAddTask(&tasks.UpsertExecutionVisibilityTask{})
+
+	report := s.runActivity()
+	s.Equal(&NamespaceReport{Scanned: 1, Sampled: 1, SearchAttributesMismatch: 1, Fixed: 1}, report.Namespaces[testNamespace])
+}
+
+func (s *scavengerSuite) TestScavenge_FixFailed() {
+	s.expectExecution(enumspb.WORKFLOW_EXECUTION_STATUS_RUNNING, nil)
+	s.expectMutableState(enumsspb.WORKFLOW_EXECUTION_STATE_RUNNING, enumspb.WORKFLOW_EXECUTION_STATUS_RUNNING, s.keywordAttributes("bar"))
+	s.historyClient.EXPECT().AddTasks(gomock.Any(), gomock.Any()).Return(nil, serviceerror.NewUnavailable("unavailable"))
+
+	report := s.runActivity()
+	s.Equal(&NamespaceReport{Scanned: 1, Sampled: 1, SearchAttributesMismatch: 1, FixFailed: 1}, report.Namespaces[testNamespace])
+}
+
+func (s *scavengerSuite) TestScavenge_RepairDisabled() {
+	s.repairEnabled = false
+	s.expectExecution(enumspb.WORKFLOW_EXECUTION_STATUS_RUNNING, nil)
+	s.expectMutableState(enumsspb.WORKFLOW_EXECUTION_STATE_COMPLETED, enumspb.WORKFLOW_EXECUTION_STATUS_TERMINATED, nil)
+
+	report := s.runActivity()
+	s.Equal(&NamespaceReport{Scanned: 1, Sampled: 1, StatusMismatch: 1}, report.Namespaces[testNamespace])
+}
+
+func (s *scavengerSuite) TestScavenge_RecentlyUpdated_Skipped() {
+	s.expectExecution(enumspb.WORKFLOW_EXECUTION_STATUS_RUNNING, nil)
+	s.executionManager.EXPECT().GetWorkflowExecution(gomock.Any(), gomock.Any()).Return(&persistence.GetWorkflowExecutionResponse{
+		State: s.mutableState(enumsspb.WORKFLOW_EXECUTION_STATE_COMPLETED, enumspb.WORKFLOW_EXECUTION_STATUS_COMPLETED, nil, time.Now()),
+	}, nil)
+
+	report := s.runActivity()
+	s.Equal(&NamespaceReport{Scanned: 1, Sampled: 1, Skipped: 1}, report.Namespaces[testNamespace])
+}
+
+func (s *scavengerSuite) TestScavenge_GetMutableStateError() {
+	s.expectExecution(enumspb.WORKFLOW_EXECUTION_STATUS_RUNNING, nil)
+	s.executionManager.EXPECT().GetWorkflowExecution(gomock.Any(), gomock.Any()).Return(nil, serviceerror.NewUnavailable("unavailable"))
+
+	report := s.runActivity()
+	s.Equal(&NamespaceReport{Scanned: 1, Sampled: 1, Errors: 1}, report.Namespaces[testNamespace])
+}
+
+func (s *scavengerSuite) TestScavenge_SampledWindow() {
+	s.sampleRate = 0.25
+	s.metadataManager.EXPECT().ListNamespaces(gomock.Any(), gomock.Any()).Return(&persistence.ListNamespacesResponse{
+		Namespaces: []*persistence.GetNamespaceResponse{s.namespaceResponse()},
+	}, nil)
+	s.namespaceRegistry.EXPECT().GetNamespaceByID(namespace.ID(testNamespaceID)).Return(
+		namespace.NewLocalNamespaceForTest(
+			&persistencespb.NamespaceInfo{Id: testNamespaceID, Name: testNamespace},
+			&persistencespb.NamespaceConfig{Retention: durationpb.New(4 * 24 * time.Hour)},
+			testClusterName,
+		), nil)
+	s.visibilityManager.EXPECT().ListWorkflowExecutions(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ context.Context, request *manager.ListWorkflowExecutionsRequestV2) (*manager.ListWorkflowExecutionsResponse, error) {
+			// Only the sampled window of start times is listed.
+			s.Contains(request.Query, "StartTime < ")
+			return &manager.ListWorkflowExecutionsResponse{}, nil
+		})
+
+	report := s.runActivity()
+	s.Equal(&NamespaceReport{}, report.Namespaces[testNamespace])
+}
+
+func (s *scavengerSuite) TestScavenge_SamplingDisabled() {
+	s.sampleRate = 0
+	s.metadataManager.EXPECT().ListNamespaces(gomock.Any(), gomock.Any()).Return(&persistence.ListNamespacesResponse{
+		Namespaces: []*persistence.GetNamespaceResponse{s.namespaceResponse()},
+	}, nil)
+	s.namespaceRegistry.EXPECT().GetNamespaceByID(namespace.ID(testNamespaceID)).Return(
+		namespace.NewLocalNamespaceForTest(
+			&persistencespb.NamespaceInfo{Id: testNamespaceID, Name: testNamespace},
+			nil,
+			testClusterName,
+		), nil)
+
+	report := s.runActivity()
+	s.Empty(report.Namespaces)
+}
+
+func (s *scavengerSuite) TestSampleWindow() {
+	cutoff := time.Date(2024, 5, 14, 0, 0, 0, 0, time.UTC)
+
+	start, end := sampleWindow(cutoff, 4*time.Hour, 1.0)
+	s.True(start.IsZero())
+	s.Equal(cutoff, end)
+
+	seen := make(map[time.Time]bool)
+	for i := 0; i < 1000; i++ {
+		start, end := sampleWindow(cutoff, 4*time.Hour, 0.25)
+		seen[end] = true
+		s.False(end.After(cutoff))
+		if end.Equal(cutoff.Add(-3 * time.Hour)) {
+			// The oldest window has no lower bound.
+			s.True(start.IsZero())
+		} else {
+			s.Equal(time.Hour, end.Sub(start))
+		}
+	}
+	s.Len(seen, 4)
+}
+
+func (s *scavengerSuite) TestCompareSearchAttributes() {
+	datetime := time.Date(2024, 5, 14, 10, 30, 0, 123456789, time.UTC)
+	encode := func(value any, t enumspb.IndexedValueType) *commonpb.Payload {
+		p, err := searchattribute.EncodeValue(value, t)
+		s.NoError(err)
+		return p
+	}
+
+	for _, tc := range []struct {
+		name       string
+		visibility map[string]*commonpb.Payload
+		mutable    map[string]*commonpb.Payload
+		mismatch   string
+	}{
+		{
+			name: "both empty",
+		},
+		{
+			name:       "equal keywords",
+			visibility: map[string]*commonpb.Payload{"CustomKeywordField": encode("foo", enumspb.INDEXED_VALUE_TYPE_KEYWORD)},
+			mutable:    map[string]*commonpb.Payload{"CustomKeywordField": encode("foo", enumspb.INDEXED_VALUE_TYPE_KEYWORD)},
+		},
+		{
+			name:       "single value and list of one value",
+			visibility: map[string]*commonpb.Payload{"CustomKeywordField": encode("foo", enumspb.INDEXED_VALUE_TYPE_KEYWORD)},
+			mutable:    map[string]*commonpb.Payload{"CustomKeywordField": encode([]string{"foo"}, enumspb.INDEXED_VALUE_TYPE_KEYWORD)},
+		},
+		{
+			name:       "datetime with lower precision",
+			visibility: map[string]*commonpb.Payload{"CustomDatetimeField": encode(datetime.Truncate(time.Millisecond), enumspb.INDEXED_VALUE_TYPE_DATETIME)},
+			mutable:    map[string]*commonpb.Payload{"CustomDatetimeField": encode(datetime, enumspb.INDEXED_VALUE_TYPE_DATETIME)},
+		},
+		{
+			name:       "different values",
+			visibility: map[string]*commonpb.Payload{"CustomIntField": encode(int64(1), enumspb.INDEXED_VALUE_TYPE_INT)},
+			mutable:    map[string]*commonpb.Payload{"CustomIntField": encode(int64(2), enumspb.INDEXED_VALUE_TYPE_INT)},
+			mismatch:   "CustomIntField",
+		},
+		{
+			name:       "missing in visibility",
+			visibility: map[string]*commonpb.Payload{"CustomIntField": encode(int64(1), enumspb.INDEXED_VALUE_TYPE_INT)},
+			mutable: map[string]*commonpb.Payload{
+				"CustomIntField":     encode(int64(1), enumspb.INDEXED_VALUE_TYPE_INT),
+				"CustomKeywordField": encode("foo", enumspb.INDEXED_VALUE_TYPE_KEYWORD),
+			},
+			mismatch: "CustomKeywordField",
+		},
+		{
+			name:       "removed from mutable state",
+			visibility: map[string]*commonpb.Payload{"CustomKeywordField": encode("foo", enumspb.INDEXED_VALUE_TYPE_KEYWORD)},
+			mismatch:   "CustomKeywordField",
+		},
+	} {
+		s.Run(tc.name, func() {
+			name, equal := compareSearchAttributes(tc.visibility, tc.mutable)
+			s.Equal(tc.mismatch == "", equal)
+			s.Equal(tc.mismatch, name)
+		})
+	}
+}
+
+func (s *scavengerSuite) runActivity() *Report {
+	env := s.NewTestActivityEnvironment()
+	env.RegisterActivity(s.activities.ScavengeVisibility)
+	result, err := env.ExecuteActivity(s.activities.ScavengeVisibility, VisibilityScavengerInput{})
+	s.NoError(err)
+	var report Report
+	s.NoError(result.Get(&report))
+	return &report
+}
+
+func (s *scavengerSuite) namespaceResponse() *persistence.GetNamespaceResponse {
+	return &persistence.GetNamespaceResponse{
+		Namespace: &persistencespb.NamespaceDetail{
+			Info: &persistencespb.NamespaceInfo{Id: testNamespaceID, Name: testNamespace},
+		},
+	}
+}
+
+// expectExecution sets up a single active namespace with a single visibility record.
+func (s *scavengerSuite) expectExecution(status enumspb.WorkflowExecutionStatus, searchAttributes map[string]*commonpb.Payload) {
+	s.metadataManager.EXPECT().ListNamespaces(gomock.Any(), gomock.Any()).Return(&persistence.ListNamespacesResponse{
+		Namespaces: []*persistence.GetNamespaceResponse{s.namespaceResponse()},
+	}, nil)
+	s.namespaceRegistry.EXPECT().GetNamespaceByID(namespace.ID(testNamespaceID)).Return(
+		namespace.NewLocalNamespaceForTest(
+			&persistencespb.NamespaceInfo{Id: testNamespaceID, Name: testNamespace},
+			nil,
+			testClusterName,
+		), nil)
+	s.visibilityManager.EXPECT().ListWorkflowExecutions(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ context.Context, request *manager.ListWorkflowExecutionsRequestV2) (*manager.ListWorkflowExecutionsResponse, error) {
+			s.Equal(namespace.ID(testNamespaceID), request.NamespaceID)
+			s.Contains(request.Query, "StartTime < ")
+			return &manager.ListWorkflowExecutionsResponse{
+				Executions: []*workflowpb.WorkflowExecutionInfo{
+					{
+						Execution:        &commonpb.WorkflowExecution{WorkflowId: testWorkflowID, RunId: testRunID},
+						Status:           status,
+						SearchAttributes: &commonpb.SearchAttributes{IndexedFields: searchAttributes},
+					},
+				},
+			}, nil
+		})
+}
+
+func (s *scavengerSuite) expectMutableState(
+	state enumsspb.WorkflowExecutionState,
+	status enumspb.WorkflowExecutionStatus,
+	searchAttributes map[string]*commonpb.Payload,
+) {
+	s.executionManager.EXPECT().GetWorkflowExecution(gomock.Any(), &persistence.GetWorkflowExecutionRequest{
+		ShardID:     s.shardID(),
+		NamespaceID: testNamespaceID,
+		WorkflowID:  testWorkflowID,
+		RunID:       testRunID,
+	}).Return(&persistence.GetWorkflowExecutionResponse{
+		State: s.mutableState(state, status, searchAttributes, time.Now().Add(-2*time.Hour)),
+	}, nil)
+}
+
+func (s *scavengerSuite) mutableState(
+	state enumsspb.WorkflowExecutionState,
+	status enumspb.WorkflowExecutionStatus,
+	searchAttributes map[string]*commonpb.Payload,
+	lastUpdateTime time.Time,
+) *persistencespb.WorkflowMutableState {
+	return &persistencespb.WorkflowMutableState{
+		ExecutionInfo: &persistencespb.WorkflowExecutionInfo{
+			LastUpdateTime:   timestamppb.New(lastUpdateTime),
+			SearchAttributes: searchAttributes,
+			VersionHistories: versionhistory.NewVersionHistories(versionhistory.NewVersionHistory(
+				[]byte("branch-token"),
+				[]*historyspb.VersionHistoryItem{versionhistory.NewVersionHistoryItem(3, testLastWriteVersion)},
+			)),
+		},
+		ExecutionState: &persistencespb.WorkflowExecutionState{
+			State:  state,
+			Status: status,
+		},
+	}
+}
+
+// expectAddTask expects a single visibility task of the same type as expectedTask to be added to the execution's shard.
+func (s *scavengerSuite) expectAddTask(expectedTask tasks.Task) {
+	s.historyClient.EXPECT().AddTasks(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ context.Context, request *historyservice.AddTasksRequest, _ ...any) (*historyservice.AddTasksResponse, error) {
+			s.Equal(s.shardID(), request.ShardId)
+			s.Len(request.Tasks, 1)
+			s.Equal(int32(tasks.CategoryIDVisibility), request.Tasks[0].CategoryId)
+			task, err := serialization.NewTaskSerializer().DeserializeTask(tasks.CategoryVisibility, request.Tasks[0].Blob)
+			s.NoError(err)
+			s.IsType(expectedTask, task)
+			s.Equal(testNamespaceID, task.GetNamespaceID())
+			s.Equal(testWorkflowID, task.GetWorkflowID())
+			s.Equal(testRunID, task.GetRunID())
+			s.Equal(testLastWriteVersion, task.GetVersion())
+			return &historyservice.AddTasksResponse{}, nil
+		})
+}
+
+func (s *scavengerSuite) keywordAttributes(value string) map[string]*commonpb.Payload {
+	p, err := searchattribute.EncodeValue(value, enumspb.INDEXED_VALUE_TYPE_KEYWORD)
+	s.NoError(err)
+	return map[string]*commonpb.Payload{"CustomKeywordField": p}
+}
+
+func (s *scavengerSuite) shardID() int32 {
+	return common.WorkflowIDToHistoryShard(testNamespaceID, testWorkflowID, testNumHistoryShards)
+}
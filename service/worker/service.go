@@ -33,6 +33,8 @@ import (
 
 	"go.temporal.io/server/api/matchingservice/v1"
 	"go.temporal.io/server/client"
+	"go.temporal.io/server/common/archiver"
+	"go.temporal.io/server/common/archiver/provider"
 	"go.temporal.io/server/common/cluster"
 	"go.temporal.io/server/common/config"
 	"go.temporal.io/server/common/dynamicconfig"
@@ -73,6 +75,8 @@ type (
 		namespaceRegistry      namespace.Registry
 		workerServiceResolver  membership.ServiceResolver
 		visibilityManager      manager.VisibilityManager
+		archivalMetadata       archiver.ArchivalMetadata
+		archiverProvider       provider.ArchiverProvider
 
 		namespaceReplicationQueue persistence.NamespaceReplicationQueue
 
@@ -142,6 +146,8 @@ func NewService(
 	visibilityManager manager.VisibilityManager,
 	matchingClient resource.MatchingClient,
 	namespaceReplicationTaskExecutor namespace.ReplicationTaskExecutor,
+	archivalMetadata archiver.ArchivalMetadata,
+	archiverProvider provider.ArchiverProvider,
 ) (*Service, error) {
 	workerServiceResolver, err := membershipMonitor.GetResolver(primitives.WorkerService)
 	if err != nil {
@@ -168,6 +174,8 @@ func NewService(
 		taskManager:               taskManager,
 		historyClient:             historyClient,
 		visibilityManager:         visibilityManager,
+		archivalMetadata:          archivalMetadata,
+		archiverProvider:          archiverProvider,
 
 		workerManager:                    workerManager,
 		perNamespaceWorkerManager:        perNamespaceWorkerManager,
@@ -285,6 +293,46 @@ func NewConfig(
 				dynamicconfig.BuildIdScavenengerVisibilityRPS,
 				1.0,
 			),
+			ArchivalVerifierEnabled: dc.GetBoolProperty(
+				dynamicconfig.ArchivalVerifierEnabled,
+				false,
+			),
+			ArchivalVerifierSampleRate: dc.GetFloat64Property(
+				dynamicconfig.ArchivalVerifierSampleRate,
+				0.1,
+			),
+			ArchivalVerifierRPS: dc.GetFloat64Property(
+				dynamicconfig.ArchivalVerifierRPS,
+				10.0,
+			),
+			ArchivalVerifierRepairEnabled: dc.GetBoolProperty(
+				dynamicconfig.ArchivalVerifierRepairEnabled,
+				true,
+			),
+			ArchivalVerifierMinCloseAge: dc.GetDurationProperty(
+				dynamicconfig.ArchivalVerifierMinCloseAge,
+				6*time.Hour,
+			),
+			VisibilityScavengerEnabled: dc.GetBoolProperty(
+				dynamicconfig.VisibilityScavengerEnabled,
+				false,
+			),
+			VisibilityScavengerSampleRate: dc.GetFloat64Property(
+				dynamicconfig.VisibilityScavengerSampleRate,
+				0.01,
+			),
+			VisibilityScavengerRPS: dc.GetFloat64Property(
+				dynamicconfig.VisibilityScavengerRPS,
+				10.0,
+			),
+			VisibilityScavengerRepairEnabled: dc.GetBoolProperty(
+				dynamicconfig.VisibilityScavengerRepairEnabled,
+				true,
+			),
+			VisibilityScavengerMinAge: dc.GetDurationProperty(
+				dynamicconfig.VisibilityScavengerMinAge,
+				time.Hour,
+			),
 		},
 		EnableBatcher:      dc.GetBoolProperty(dynamicconfig.EnableBatcher, true),
 		BatcherRPS:         dc.GetIntPropertyFilteredByNamespace(dynamicconfig.BatcherRPS, batcher.DefaultRPS),
@@ -448,6 +496,8 @@ func (s *Service) initScanner() error {
 		adminClient,
 		s.matchingClient,
 		s.namespaceRegistry,
+		s.archivalMetadata,
+		s.archiverProvider,
 		currentCluster,
 	)
 	return nil
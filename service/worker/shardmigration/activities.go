@@ -0,0 +1,141 @@
+// The MIT License
+//
+// Copyright (c) 2020 Temporal Technologies Inc.  All rights reserved.
+//
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package shardmigration
+
+import (
+	"context"
+	"slices"
+
+	"go.temporal.io/api/serviceerror"
+	"go.temporal.io/sdk/activity"
+	"go.temporal.io/sdk/temporal"
+
+	enumsspb "go.temporal.io/server/api/enums/v1"
+	persistencespb "go.temporal.io/server/api/persistence/v1"
+	"go.temporal.io/server/common/log"
+	"go.temporal.io/server/common/log/tag"
+	"go.temporal.io/server/common/persistence"
+	"go.temporal.io/server/common/persistence/shardmigration"
+)
+
+type (
+	activities struct {
+		clusterMetadataManager persistence.ClusterMetadataManager
+		copier                 *shardmigration.Copier
+		logger                 log.Logger
+	}
+)
+
+func (a *activities) GetMigration(ctx context.Context) (migrationInfo, error) {
+	migration, err := a.getCopyingMigration(ctx)
+	if err != nil {
+		return migrationInfo{}, err
+	}
+	return migrationInfo{
+		SourceShardCount: migration.SourceShardCount,
+		TargetShardCount: migration.TargetShardCount,
+		CopiedShardIDs:   migration.CopiedShardIds,
+	}, nil
+}
+
+func (a *activities) CopyShard(ctx context.Context, request copyShardRequest) error {
+	migration, err := a.getCopyingMigration(ctx)
+	if err != nil {
+		return err
+	}
+	copied, err := a.copier.CopyShard(ctx, migration, request.ShardID, func(copied int64) {
+		activity.RecordHeartbeat(ctx, copied)
+	})
+	if err != nil {
+		return err
+	}
+	_, err = shardmigration.UpdateMigration(ctx, a.clusterMetadataManager, func(metadata *persistencespb.ClusterMetadata) error {
+		m := metadata.GetHistoryShardCountMigration()
+		if err := checkCopying(m); err != nil {
+			return err
+		}
+		if !slices.Contains(m.CopiedShardIds, request.ShardID) {
+			m.CopiedShardIds = append(m.CopiedShardIds, request.ShardID)
+			m.ExecutionsCopied += copied
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	a.logger.Info("Copied history shard",
+		tag.ShardID(request.ShardID),
+		tag.NewInt64("executions-copied", copied),
+	)
+	return nil
+}
+
+// StartCutover switches the cluster to the target shard count. The new count is picked up by history hosts when they
+// are restarted.
+func (a *activities) StartCutover(ctx context.Context) error {
+	_, err := shardmigration.UpdateMigration(ctx, a.clusterMetadataManager, func(metadata *persistencespb.ClusterMetadata) error {
+		m := metadata.GetHistoryShardCountMigration()
+		if m.GetState() == enumsspb.HISTORY_SHARD_COUNT_MIGRATION_STATE_CUTOVER {
+			// Already switched by a previous attempt.
+			return nil
+		}
+		if err := checkCopying(m); err != nil {
+			return err
+		}
+		if int32(len(m.CopiedShardIds)) != m.SourceShardCount {
+			return serviceerror.NewFailedPrecondition("not all history shards were copied")
+		}
+		m.State = enumsspb.HISTORY_SHARD_COUNT_MIGRATION_STATE_CUTOVER
+		metadata.HistoryShardCount = m.TargetShardCount
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	a.logger.Info("History shard count migration is ready for cut-over, history hosts must be restarted")
+	return nil
+}
+
+func (a *activities) getCopyingMigration(ctx context.Context) (*persistencespb.HistoryShardCountMigration, error) {
+	migration, _, err := shardmigration.GetMigration(ctx, a.clusterMetadataManager)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkCopying(migration); err != nil {
+		return nil, err
+	}
+	return migration, nil
+}
+
+func checkCopying(migration *persistencespb.HistoryShardCountMigration) error {
+	if migration.GetState() != enumsspb.HISTORY_SHARD_COUNT_MIGRATION_STATE_COPYING {
+		return temporal.NewNonRetryableApplicationError(
+			"history shard count migration is not copying",
+			errorTypeNotCopying,
+			nil,
+		)
+	}
+	return nil
+}
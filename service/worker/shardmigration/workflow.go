@@ -0,0 +1,137 @@
+// The MIT License
+//
+// Copyright (c) 2020 Temporal Technologies Inc.  All rights reserved.
+//
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+// Package shardmigration contains the workflow which copies executions to the new shards of a history shard count
+// migration. See [go.temporal.io/server/common/persistence/shardmigration] for an overview of the migration.
+package shardmigration
+
+import (
+	"time"
+
+	"go.temporal.io/sdk/temporal"
+	"go.temporal.io/sdk/workflow"
+
+	"go.temporal.io/server/common/primitives"
+)
+
+type (
+	// WorkflowParams is the single argument to the history shard count migration workflow.
+	WorkflowParams struct {
+		// Concurrency is the number of source shards copied at the same time. The default is DefaultConcurrency.
+		Concurrency int
+	}
+
+	migrationInfo struct {
+		SourceShardCount int32
+		TargetShardCount int32
+		CopiedShardIDs   []int32
+	}
+
+	copyShardRequest struct {
+		ShardID int32
+	}
+)
+
+const (
+	// WorkflowName is the name of the history shard count migration workflow.
+	WorkflowName = "temporal-sys-history-shard-count-migration-workflow"
+	// WorkflowID is the ID of the history shard count migration workflow. There can only be one migration at a time.
+	WorkflowID = "temporal-sys-history-shard-count-migration"
+	// DefaultConcurrency is the default value for WorkflowParams.Concurrency.
+	DefaultConcurrency = 1
+
+	errorTypeNotCopying = "history-shard-count-migration-error-type-not-copying"
+
+	copyShardActivityTimeout = 24 * time.Hour
+	heartbeatTimeout         = time.Minute
+)
+
+var (
+	activityRetryPolicy = &temporal.RetryPolicy{
+		InitialInterval: time.Second * 10,
+		MaximumInterval: time.Minute * 5,
+	}
+)
+
+// Workflow copies the executions of every source shard which hasn't been copied yet, and then switches the cluster to
+// the target shard count. History hosts must be restarted after it completes for the cut-over to take place.
+func Workflow(ctx workflow.Context, params WorkflowParams) error {
+	concurrency := params.Concurrency
+	if concurrency <= 0 {
+		concurrency = DefaultConcurrency
+	}
+
+	var a *activities
+	actx := workflow.WithActivityOptions(ctx, workflow.ActivityOptions{
+		TaskQueue:           primitives.ShardMigrationActivityTQ,
+		StartToCloseTimeout: time.Minute,
+		RetryPolicy:         activityRetryPolicy,
+	})
+	var info migrationInfo
+	if err := workflow.ExecuteActivity(actx, a.GetMigration).Get(ctx, &info); err != nil {
+		return err
+	}
+
+	copied := make(map[int32]struct{}, len(info.CopiedShardIDs))
+	for _, shardID := range info.CopiedShardIDs {
+		copied[shardID] = struct{}{}
+	}
+
+	copyCtx := workflow.WithActivityOptions(ctx, workflow.ActivityOptions{
+		TaskQueue:           primitives.ShardMigrationActivityTQ,
+		StartToCloseTimeout: copyShardActivityTimeout,
+		HeartbeatTimeout:    heartbeatTimeout,
+		RetryPolicy:         activityRetryPolicy,
+	})
+	selector := workflow.NewSelector(ctx)
+	pending := 0
+	var lastErr error
+	for shardID := int32(1); shardID <= info.SourceShardCount; shardID++ {
+		if _, ok := copied[shardID]; ok {
+			continue
+		}
+		for pending >= concurrency {
+			selector.Select(ctx)
+		}
+		if lastErr != nil {
+			break
+		}
+		pending++
+		future := workflow.ExecuteActivity(copyCtx, a.CopyShard, copyShardRequest{ShardID: shardID})
+		selector.AddFuture(future, func(f workflow.Future) {
+			pending--
+			if err := f.Get(ctx, nil); err != nil {
+				lastErr = err
+			}
+		})
+	}
+	for pending > 0 {
+		selector.Select(ctx)
+	}
+	if lastErr != nil {
+		return lastErr
+	}
+
+	return workflow.ExecuteActivity(actx, a.StartCutover).Get(ctx, nil)
+}
@@ -0,0 +1,82 @@
+// The MIT License
+//
+// Copyright (c) 2020 Temporal Technologies Inc.  All rights reserved.
+//
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package shardmigration
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.temporal.io/sdk/temporal"
+	"go.temporal.io/sdk/testsuite"
+)
+
+func TestWorkflow(t *testing.T) {
+	var a *activities
+	env := (&testsuite.WorkflowTestSuite{}).NewTestWorkflowEnvironment()
+	env.OnActivity(a.GetMigration, mock.Anything).Return(migrationInfo{
+		SourceShardCount: 4,
+		TargetShardCount: 8,
+		CopiedShardIDs:   []int32{2},
+	}, nil)
+	var mu sync.Mutex
+	var copied []int32
+	env.OnActivity(a.CopyShard, mock.Anything, mock.Anything).Return(func(_ context.Context, request copyShardRequest) error {
+		mu.Lock()
+		defer mu.Unlock()
+		copied = append(copied, request.ShardID)
+		return nil
+	})
+	env.OnActivity(a.StartCutover, mock.Anything).Return(nil).Once()
+
+	env.ExecuteWorkflow(Workflow, WorkflowParams{Concurrency: 2})
+
+	require.True(t, env.IsWorkflowCompleted())
+	require.NoError(t, env.GetWorkflowError())
+	require.ElementsMatch(t, []int32{1, 3, 4}, copied)
+	env.AssertExpectations(t)
+}
+
+func TestWorkflow_CopyFailure(t *testing.T) {
+	var a *activities
+	env := (&testsuite.WorkflowTestSuite{}).NewTestWorkflowEnvironment()
+	env.OnActivity(a.GetMigration, mock.Anything).Return(migrationInfo{
+		SourceShardCount: 4,
+		TargetShardCount: 8,
+	}, nil)
+	env.OnActivity(a.CopyShard, mock.Anything, copyShardRequest{ShardID: 1}).Return(
+		temporal.NewNonRetryableApplicationError("boom", "test", errors.New("boom")),
+	)
+	env.OnActivity(a.CopyShard, mock.Anything, mock.Anything).Return(nil)
+
+	env.ExecuteWorkflow(Workflow, WorkflowParams{})
+
+	require.True(t, env.IsWorkflowCompleted())
+	require.ErrorContains(t, env.GetWorkflowError(), "boom")
+	env.AssertNotCalled(t, "StartCutover", mock.Anything)
+}
@@ -0,0 +1,343 @@
+// The MIT License
+//
+// Copyright (c) 2020 Temporal Technologies Inc.  All rights reserved.
+//
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package visibilityreindex
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	commonpb "go.temporal.io/api/common/v1"
+	"go.temporal.io/api/serviceerror"
+	"go.temporal.io/sdk/activity"
+	"go.temporal.io/sdk/temporal"
+
+	enumsspb "go.temporal.io/server/api/enums/v1"
+	persistencespb "go.temporal.io/server/api/persistence/v1"
+	"go.temporal.io/server/common/log"
+	"go.temporal.io/server/common/log/tag"
+	"go.temporal.io/server/common/namespace"
+	"go.temporal.io/server/common/persistence"
+	"go.temporal.io/server/common/persistence/versionhistory"
+	"go.temporal.io/server/common/persistence/visibility"
+	"go.temporal.io/server/common/persistence/visibility/manager"
+	"go.temporal.io/server/common/quotas"
+)
+
+type (
+	activities struct {
+		numHistoryShards  int32
+		executionManager  persistence.ExecutionManager
+		namespaceRegistry namespace.Registry
+		// visibilityManager returns a visibility manager which is able to write to all configured stores.
+		visibilityManager func() (manager.VisibilityManager, error)
+		logger            log.Logger
+	}
+
+	// shardProgress is recorded as heartbeat details, so a retried ReindexShard resumes from the last page.
+	shardProgress struct {
+		PageToken []byte
+		reindexShardResult
+	}
+)
+
+const (
+	executionsPageSize = 100
+	// maxWriteAttempts is the max number of times the record of an execution that changes while it's reindexed is
+	// written
+	maxWriteAttempts = 3
+
+	errorTypeInvalidTargetStore = "visibility-reindex-error-type-invalid-target-store"
+)
+
+func (a *activities) GetShardCount(_ context.Context) (int32, error) {
+	return a.numHistoryShards, nil
+}
+
+func (a *activities) ReindexShard(ctx context.Context, request reindexShardRequest) (reindexShardResult, error) {
+	visibilityManager, err := a.visibilityManager()
+	if err != nil {
+		return reindexShardResult{}, err
+	}
+	targetManager, err := visibility.ManagerForStore(visibilityManager, request.TargetStore)
+	if err != nil {
+		return reindexShardResult{}, temporal.NewNonRetryableApplicationError(err.Error(), errorTypeInvalidTargetStore, err)
+	}
+
+	var progress shardProgress
+	if activity.HasHeartbeatDetails(ctx) {
+		if err := activity.GetHeartbeatDetails(ctx, &progress); err != nil {
+			a.logger.Warn("Unable to resume visibility reindex from heartbeat, starting the shard over",
+				tag.ShardID(request.ShardID),
+				tag.Error(err),
+			)
+			progress = shardProgress{}
+		}
+	}
+
+	rateLimiter := quotas.NewRateLimiter(request.RPS, max(1, int(request.RPS)))
+	for first := true; first || len(progress.PageToken) != 0; first = false {
+		resp, err := a.executionManager.ListConcreteExecutions(ctx, &persistence.ListConcreteExecutionsRequest{
+			ShardID:   request.ShardID,
+			PageSize:  executionsPageSize,
+			PageToken: progress.PageToken,
+		})
+		if err != nil {
+			return reindexShardResult{}, err
+		}
+		for _, state := range resp.States {
+			if request.NamespaceID != "" && state.GetExecutionInfo().GetNamespaceId() != request.NamespaceID {
+				continue
+			}
+			if err := rateLimiter.Wait(ctx); err != nil {
+				return reindexShardResult{}, err
+			}
+			reindexed, err := a.reindexExecution(ctx, targetManager, request, state)
+			if err != nil {
+				return reindexShardResult{}, err
+			}
+			if reindexed {
+				progress.ExecutionsReindexed++
+			} else {
+				progress.ExecutionsSkipped++
+			}
+		}
+		progress.PageToken = resp.PageToken
+		activity.RecordHeartbeat(ctx, progress)
+	}
+
+	a.logger.Info("Reindexed visibility of history shard",
+		tag.ShardID(request.ShardID),
+		tag.NewInt64("executions-reindexed", progress.ExecutionsReindexed),
+		tag.NewInt64("executions-skipped", progress.ExecutionsSkipped),
+	)
+	return progress.reindexShardResult, nil
+}
+
+// reindexExecution writes the visibility record of a single execution the same way the visibility tasks of the
+// history service do. It returns false if the execution has no visibility record or if it changed after the reindex
+// started.
+//
+// Mutable state is read again right before writing, since the listed page can be minutes old when the RPS is low.
+// An execution which changes between that read and the write can still have the record of its visibility task
+// overwritten by the older one written here. Elasticsearch rejects it because of the task ID version, but SQL stores
+// have no such guard, so mutable state is read once more after writing, and the record is written again if the
+// execution changed in the meantime.
+func (a *activities) reindexExecution(
+	ctx context.Context,
+	targetManager manager.VisibilityManager,
+	request reindexShardRequest,
+	listedState *persistencespb.WorkflowMutableState,
+) (bool, error) {
+	shardID := request.ShardID
+	nsEntry, err := a.namespaceRegistry.GetNamespaceByID(namespace.ID(listedState.GetExecutionInfo().GetNamespaceId()))
+	if err != nil {
+		var notFound *serviceerror.NamespaceNotFound
+		if errors.As(err, &notFound) {
+			return false, nil
+		}
+		return false, err
+	}
+
+	resp, err := a.getExecution(ctx, shardID, listedState)
+	if err != nil || resp == nil {
+		return false, err
+	}
+	if !request.StartTime.IsZero() && resp.State.GetExecutionInfo().GetLastUpdateTime().AsTime().After(request.StartTime) {
+		// The visibility task of the change writes a newer record.
+		return false, nil
+	}
+
+	for attempt := 1; ; attempt++ {
+		written, err := a.writeRecord(ctx, targetManager, nsEntry, shardID, resp.State)
+		if err != nil || !written {
+			return attempt > 1, err
+		}
+		current, err := a.getExecution(ctx, shardID, listedState)
+		if err != nil {
+			return true, err
+		}
+		if current == nil || !executionChanged(resp, current) {
+			// Deleted executions have their record deleted by the delete task.
+			return true, nil
+		}
+		if attempt == maxWriteAttempts {
+			a.logger.Warn("Visibility record of a workflow execution that keeps changing may be stale after reindex",
+				tag.ShardID(shardID),
+				tag.WorkflowNamespaceID(listedState.GetExecutionInfo().GetNamespaceId()),
+				tag.WorkflowID(listedState.GetExecutionInfo().GetWorkflowId()),
+				tag.WorkflowRunID(listedState.GetExecutionState().GetRunId()),
+			)
+			return true, nil
+		}
+		resp = current
+	}
+}
+
+// getExecution reads the current mutable state of a listed execution. It returns nil if the execution was deleted.
+func (a *activities) getExecution(
+	ctx context.Context,
+	shardID int32,
+	listedState *persistencespb.WorkflowMutableState,
+) (*persistence.GetWorkflowExecutionResponse, error) {
+	resp, err := a.executionManager.GetWorkflowExecution(ctx, &persistence.GetWorkflowExecutionRequest{
+		ShardID:     shardID,
+		NamespaceID: listedState.GetExecutionInfo().GetNamespaceId(),
+		WorkflowID:  listedState.GetExecutionInfo().GetWorkflowId(),
+		RunID:       listedState.GetExecutionState().GetRunId(),
+	})
+	if err != nil {
+		var notFound *serviceerror.NotFound
+		if errors.As(err, &notFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return resp, nil
+}
+
+// writeRecord writes the visibility record of the execution state. It returns false if the execution isn't visible.
+func (a *activities) writeRecord(
+	ctx context.Context,
+	targetManager manager.VisibilityManager,
+	nsEntry *namespace.Namespace,
+	shardID int32,
+	state *persistencespb.WorkflowMutableState,
+) (bool, error) {
+	executionInfo := state.GetExecutionInfo()
+	requestBase := visibilityRequestBase(nsEntry, shardID, state)
+	switch state.GetExecutionState().GetState() {
+	case enumsspb.WORKFLOW_EXECUTION_STATE_CREATED, enumsspb.WORKFLOW_EXECUTION_STATE_RUNNING:
+		return true, targetManager.UpsertWorkflowExecution(ctx, &manager.UpsertWorkflowExecutionRequest{
+			VisibilityRequestBase: requestBase,
+		})
+	case enumsspb.WORKFLOW_EXECUTION_STATE_COMPLETED:
+		closeTime, err := a.closeTime(ctx, shardID, state)
+		if err != nil {
+			return false, err
+		}
+		return true, targetManager.RecordWorkflowExecutionClosed(ctx, &manager.RecordWorkflowExecutionClosedRequest{
+			VisibilityRequestBase: requestBase,
+			CloseTime:             closeTime,
+			HistoryLength:         state.GetNextEventId() - 1,
+			HistorySizeBytes:      executionInfo.GetExecutionStats().GetHistorySize(),
+			StateTransitionCount:  executionInfo.GetStateTransitionCount(),
+		})
+	default:
+		// Zombie and void executions are not visible.
+		return false, nil
+	}
+}
+
+// executionChanged returns true if the execution was updated between the two reads of its mutable state.
+func executionChanged(before, after *persistence.GetWorkflowExecutionResponse) bool {
+	return before.DBRecordVersion != after.DBRecordVersion ||
+		before.State.GetExecutionState().GetState() != after.State.GetExecutionState().GetState() ||
+		before.State.GetExecutionState().GetStatus() != after.State.GetExecutionState().GetStatus() ||
+		before.State.GetNextEventId() != after.State.GetNextEventId() ||
+		!before.State.GetExecutionInfo().GetLastUpdateTime().AsTime().Equal(after.State.GetExecutionInfo().GetLastUpdateTime().AsTime())
+}
+
+// closeTime returns the close time of a closed execution. Executions closed before close time was added to mutable
+// state have it read from their completion event.
+func (a *activities) closeTime(
+	ctx context.Context,
+	shardID int32,
+	state *persistencespb.WorkflowMutableState,
+) (time.Time, error) {
+	executionInfo := state.GetExecutionInfo()
+	if executionInfo.GetCloseTime() != nil || executionInfo.GetCompletionEventBatchId() == 0 {
+		return executionInfo.GetCloseTime().AsTime(), nil
+	}
+	currentVersionHistory, err := versionhistory.GetCurrentVersionHistory(executionInfo.GetVersionHistories())
+	if err != nil {
+		return time.Time{}, err
+	}
+	resp, err := a.executionManager.ReadHistoryBranch(ctx, &persistence.ReadHistoryBranchRequest{
+		ShardID:     shardID,
+		BranchToken: currentVersionHistory.GetBranchToken(),
+		MinEventID:  executionInfo.GetCompletionEventBatchId(),
+		MaxEventID:  state.GetNextEventId(),
+		PageSize:    1,
+	})
+	if err != nil {
+		return time.Time{}, err
+	}
+	if len(resp.HistoryEvents) == 0 {
+		return time.Time{}, serviceerror.NewInternal("unable to read the completion event of a closed workflow execution")
+	}
+	return resp.HistoryEvents[len(resp.HistoryEvents)-1].GetEventTime().AsTime(), nil
+}
+
+func visibilityRequestBase(
+	nsEntry *namespace.Namespace,
+	shardID int32,
+	state *persistencespb.WorkflowMutableState,
+) *manager.VisibilityRequestBase {
+	executionInfo := state.GetExecutionInfo()
+
+	var parentExecution *commonpb.WorkflowExecution
+	if executionInfo.GetParentWorkflowId() != "" && executionInfo.GetParentRunId() != "" {
+		parentExecution = &commonpb.WorkflowExecution{
+			WorkflowId: executionInfo.GetParentWorkflowId(),
+			RunId:      executionInfo.GetParentRunId(),
+		}
+	}
+	var memo *commonpb.Memo
+	if executionInfo.GetMemo() != nil {
+		memo = &commonpb.Memo{Fields: executionInfo.GetMemo()}
+	}
+	var searchAttributes *commonpb.SearchAttributes
+	if executionInfo.GetSearchAttributes() != nil {
+		searchAttributes = &commonpb.SearchAttributes{IndexedFields: executionInfo.GetSearchAttributes()}
+	}
+
+	// Elasticsearch uses the task ID as document version, so records written by visibility tasks of later changes are
+	// never overwritten.
+	taskID := executionInfo.GetLastEventTaskId()
+	if state.GetExecutionState().GetState() == enumsspb.WORKFLOW_EXECUTION_STATE_COMPLETED &&
+		executionInfo.GetCloseVisibilityTaskId() != 0 {
+		taskID = executionInfo.GetCloseVisibilityTaskId()
+	}
+
+	return &manager.VisibilityRequestBase{
+		NamespaceID: nsEntry.ID(),
+		Namespace:   nsEntry.Name(),
+		Execution: &commonpb.WorkflowExecution{
+			WorkflowId: executionInfo.GetWorkflowId(),
+			RunId:      state.GetExecutionState().GetRunId(),
+		},
+		WorkflowTypeName: executionInfo.GetWorkflowTypeName(),
+		StartTime:        executionInfo.GetStartTime().AsTime(),
+		Status:           state.GetExecutionState().GetStatus(),
+		ExecutionTime:    executionInfo.GetExecutionTime().AsTime(),
+		TaskID:           taskID,
+		ShardID:          shardID,
+		Memo:             memo,
+		TaskQueue:        executionInfo.GetTaskQueue(),
+		SearchAttributes: searchAttributes,
+		ParentExecution:  parentExecution,
+	}
+}
@@ -0,0 +1,321 @@
+// The MIT License
+//
+// Copyright (c) 2020 Temporal Technologies Inc.  All rights reserved.
+//
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package visibilityreindex
+
+import (
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+	commonpb "go.temporal.io/api/common/v1"
+	enumspb "go.temporal.io/api/enums/v1"
+	historypb "go.temporal.io/api/history/v1"
+	"go.temporal.io/api/serviceerror"
+	"go.temporal.io/sdk/testsuite"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	enumsspb "go.temporal.io/server/api/enums/v1"
+	historyspb "go.temporal.io/server/api/history/v1"
+	persistencespb "go.temporal.io/server/api/persistence/v1"
+	"go.temporal.io/server/common/log"
+	"go.temporal.io/server/common/namespace"
+	"go.temporal.io/server/common/persistence"
+	"go.temporal.io/server/common/persistence/visibility/manager"
+)
+
+func TestReindexShard(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	executionManager := persistence.NewMockExecutionManager(ctrl)
+	namespaceRegistry := namespace.NewMockRegistry(ctrl)
+	visibilityManager := manager.NewMockVisibilityManager(ctrl)
+
+	nsEntry := namespace.NewLocalNamespaceForTest(&persistencespb.NamespaceInfo{Id: "ns-id", Name: "ns"}, nil, "active")
+	namespaceRegistry.EXPECT().GetNamespaceByID(namespace.ID("ns-id")).Return(nsEntry, nil).AnyTimes()
+	namespaceRegistry.EXPECT().GetNamespaceByID(namespace.ID("deleted-ns-id")).
+		Return(nil, serviceerror.NewNamespaceNotFound("deleted-ns-id")).AnyTimes()
+
+	startTime := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	closeTime := startTime.Add(time.Hour)
+	newState := func(namespaceID, workflowID string, state enumsspb.WorkflowExecutionState) *persistencespb.WorkflowMutableState {
+		return &persistencespb.WorkflowMutableState{
+			ExecutionInfo: &persistencespb.WorkflowExecutionInfo{
+				NamespaceId:      namespaceID,
+				WorkflowId:       workflowID,
+				WorkflowTypeName: "workflow-type",
+				TaskQueue:        "task-queue",
+				StartTime:        timestamppb.New(startTime),
+				ExecutionTime:    timestamppb.New(startTime),
+				LastUpdateTime:   timestamppb.New(startTime),
+				LastEventTaskId:  7,
+				VersionHistories: &historyspb.VersionHistories{
+					Histories: []*historyspb.VersionHistory{{BranchToken: []byte("branch-token")}},
+				},
+			},
+			ExecutionState: &persistencespb.WorkflowExecutionState{
+				RunId:  workflowID + "-run-id",
+				State:  state,
+				Status: enumspb.WORKFLOW_EXECUTION_STATUS_RUNNING,
+			},
+			NextEventId: 11,
+		}
+	}
+	running := newState("ns-id", "running", enumsspb.WORKFLOW_EXECUTION_STATE_RUNNING)
+	running.ExecutionInfo.SearchAttributes = map[string]*commonpb.Payload{"CustomKeywordField": {Data: []byte(`"value"`)}}
+	closed := newState("ns-id", "closed", enumsspb.WORKFLOW_EXECUTION_STATE_COMPLETED)
+	closed.ExecutionState.Status = enumspb.WORKFLOW_EXECUTION_STATUS_COMPLETED
+	closed.ExecutionInfo.CloseTime = timestamppb.New(closeTime)
+	closed.ExecutionInfo.CloseVisibilityTaskId = 9
+	// Closed before the close time was recorded in mutable state.
+	oldClosed := newState("ns-id", "old-closed", enumsspb.WORKFLOW_EXECUTION_STATE_COMPLETED)
+	oldClosed.ExecutionState.Status = enumspb.WORKFLOW_EXECUTION_STATUS_FAILED
+	oldClosed.ExecutionInfo.CompletionEventBatchId = 10
+	zombie := newState("ns-id", "zombie", enumsspb.WORKFLOW_EXECUTION_STATE_ZOMBIE)
+	deleted := newState("deleted-ns-id", "deleted", enumsspb.WORKFLOW_EXECUTION_STATE_RUNNING)
+	// Changed after the reindex started, its visibility task writes it.
+	updated := newState("ns-id", "updated", enumsspb.WORKFLOW_EXECUTION_STATE_RUNNING)
+	// Deleted after it was listed.
+	gone := newState("ns-id", "gone", enumsspb.WORKFLOW_EXECUTION_STATE_RUNNING)
+
+	// The listed state is stale, the execution closed since.
+	staleClosed := newState("ns-id", "closed", enumsspb.WORKFLOW_EXECUTION_STATE_RUNNING)
+	reindexStartTime := closeTime.Add(time.Minute)
+	currentStates := map[string]*persistencespb.WorkflowMutableState{
+		"running":    running,
+		"closed":     closed,
+		"old-closed": oldClosed,
+		"zombie":     zombie,
+	}
+	updatedNow := newState("ns-id", "updated", enumsspb.WORKFLOW_EXECUTION_STATE_RUNNING)
+	updatedNow.ExecutionInfo.LastUpdateTime = timestamppb.New(reindexStartTime.Add(time.Second))
+	currentStates["updated"] = updatedNow
+	executionManager.EXPECT().GetWorkflowExecution(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ any, request *persistence.GetWorkflowExecutionRequest) (*persistence.GetWorkflowExecutionResponse, error) {
+			require.Equal(t, int32(3), request.ShardID)
+			require.Equal(t, "ns-id", request.NamespaceID)
+			require.Equal(t, request.WorkflowID+"-run-id", request.RunID)
+			state, ok := currentStates[request.WorkflowID]
+			if !ok {
+				return nil, serviceerror.NewNotFound("workflow execution not found")
+			}
+			return &persistence.GetWorkflowExecutionResponse{State: state}, nil
+		},
+	).Times(9) // written executions are read again after writing
+
+	executionManager.EXPECT().ListConcreteExecutions(gomock.Any(), &persistence.ListConcreteExecutionsRequest{
+		ShardID:  3,
+		PageSize: executionsPageSize,
+	}).Return(&persistence.ListConcreteExecutionsResponse{
+		States:    []*persistencespb.WorkflowMutableState{running, staleClosed, zombie, updated, gone},
+		PageToken: []byte("next"),
+	}, nil)
+	executionManager.EXPECT().ListConcreteExecutions(gomock.Any(), &persistence.ListConcreteExecutionsRequest{
+		ShardID:   3,
+		PageSize:  executionsPageSize,
+		PageToken: []byte("next"),
+	}).Return(&persistence.ListConcreteExecutionsResponse{
+		States: []*persistencespb.WorkflowMutableState{oldClosed, deleted},
+	}, nil)
+	executionManager.EXPECT().ReadHistoryBranch(gomock.Any(), &persistence.ReadHistoryBranchRequest{
+		ShardID:     3,
+		BranchToken: []byte("branch-token"),
+		MinEventID:  10,
+		MaxEventID:  11,
+		PageSize:    1,
+	}).Return(&persistence.ReadHistoryBranchResponse{
+		HistoryEvents: []*historypb.HistoryEvent{{EventId: 10, EventTime: timestamppb.New(closeTime)}},
+	}, nil)
+
+	visibilityManager.EXPECT().HasStoreName("es-visibility").Return(true)
+	visibilityManager.EXPECT().UpsertWorkflowExecution(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ any, request *manager.UpsertWorkflowExecutionRequest) error {
+			require.Equal(t, nsEntry.Name(), request.Namespace)
+			require.Equal(t, "running", request.Execution.GetWorkflowId())
+			require.Equal(t, "running-run-id", request.Execution.GetRunId())
+			require.Equal(t, startTime, request.StartTime)
+			require.Equal(t, int64(7), request.TaskID)
+			require.Equal(t, int32(3), request.ShardID)
+			require.Equal(t, running.ExecutionInfo.SearchAttributes, request.SearchAttributes.GetIndexedFields())
+			return nil
+		},
+	)
+	var closedRequests []*manager.RecordWorkflowExecutionClosedRequest
+	visibilityManager.EXPECT().RecordWorkflowExecutionClosed(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ any, request *manager.RecordWorkflowExecutionClosedRequest) error {
+			closedRequests = append(closedRequests, request)
+			return nil
+		},
+	).Times(2)
+
+	a := &activities{
+		numHistoryShards:  4,
+		executionManager:  executionManager,
+		namespaceRegistry: namespaceRegistry,
+		visibilityManager: func() (manager.VisibilityManager, error) {
+			return visibilityManager, nil
+		},
+		logger: log.NewNoopLogger(),
+	}
+	env := (&testsuite.WorkflowTestSuite{}).NewTestActivityEnvironment()
+	env.RegisterActivity(a)
+	result, err := env.ExecuteActivity(a.ReindexShard, reindexShardRequest{
+		ShardID:     3,
+		TargetStore: "es-visibility",
+		RPS:         1000,
+		StartTime:   reindexStartTime,
+	})
+	require.NoError(t, err)
+	var shardResult reindexShardResult
+	require.NoError(t, result.Get(&shardResult))
+	require.Equal(t, reindexShardResult{ExecutionsReindexed: 3, ExecutionsSkipped: 4}, shardResult)
+
+	require.Len(t, closedRequests, 2)
+	require.Equal(t, "closed", closedRequests[0].Execution.GetWorkflowId())
+	require.Equal(t, int64(9), closedRequests[0].TaskID)
+	require.Equal(t, closeTime, closedRequests[0].CloseTime)
+	require.Equal(t, int64(10), closedRequests[0].HistoryLength)
+	require.Equal(t, "old-closed", closedRequests[1].Execution.GetWorkflowId())
+	require.Equal(t, enumspb.WORKFLOW_EXECUTION_STATUS_FAILED, closedRequests[1].Status)
+	require.Equal(t, closeTime, closedRequests[1].CloseTime)
+}
+
+func TestReindexShard_NamespaceFilter(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	executionManager := persistence.NewMockExecutionManager(ctrl)
+	namespaceRegistry := namespace.NewMockRegistry(ctrl)
+	visibilityManager := manager.NewMockVisibilityManager(ctrl)
+
+	nsEntry := namespace.NewLocalNamespaceForTest(&persistencespb.NamespaceInfo{Id: "ns-id", Name: "ns"}, nil, "active")
+	namespaceRegistry.EXPECT().GetNamespaceByID(namespace.ID("ns-id")).Return(nsEntry, nil)
+	state := &persistencespb.WorkflowMutableState{
+		ExecutionInfo:  &persistencespb.WorkflowExecutionInfo{NamespaceId: "ns-id", WorkflowId: "wf"},
+		ExecutionState: &persistencespb.WorkflowExecutionState{State: enumsspb.WORKFLOW_EXECUTION_STATE_RUNNING},
+	}
+	executionManager.EXPECT().ListConcreteExecutions(gomock.Any(), gomock.Any()).Return(&persistence.ListConcreteExecutionsResponse{
+		States: []*persistencespb.WorkflowMutableState{
+			{
+				ExecutionInfo:  &persistencespb.WorkflowExecutionInfo{NamespaceId: "other-ns-id", WorkflowId: "other"},
+				ExecutionState: &persistencespb.WorkflowExecutionState{State: enumsspb.WORKFLOW_EXECUTION_STATE_RUNNING},
+			},
+			state,
+		},
+	}, nil)
+	executionManager.EXPECT().GetWorkflowExecution(gomock.Any(), gomock.Any()).Return(&persistence.GetWorkflowExecutionResponse{State: state}, nil).Times(2)
+	visibilityManager.EXPECT().UpsertWorkflowExecution(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ any, request *manager.UpsertWorkflowExecutionRequest) error {
+			require.Equal(t, "wf", request.Execution.GetWorkflowId())
+			return nil
+		},
+	)
+
+	a := &activities{
+		executionManager:  executionManager,
+		namespaceRegistry: namespaceRegistry,
+		visibilityManager: func() (manager.VisibilityManager, error) {
+			return visibilityManager, nil
+		},
+		logger: log.NewNoopLogger(),
+	}
+	env := (&testsuite.WorkflowTestSuite{}).NewTestActivityEnvironment()
+	env.RegisterActivity(a)
+	result, err := env.ExecuteActivity(a.ReindexShard, reindexShardRequest{ShardID: 1, NamespaceID: "ns-id", RPS: 1000})
+	require.NoError(t, err)
+	var shardResult reindexShardResult
+	require.NoError(t, result.Get(&shardResult))
+	require.Equal(t, reindexShardResult{ExecutionsReindexed: 1}, shardResult)
+}
+
+func TestReindexShard_ExecutionChangedWhileWriting(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	executionManager := persistence.NewMockExecutionManager(ctrl)
+	namespaceRegistry := namespace.NewMockRegistry(ctrl)
+	visibilityManager := manager.NewMockVisibilityManager(ctrl)
+
+	nsEntry := namespace.NewLocalNamespaceForTest(&persistencespb.NamespaceInfo{Id: "ns-id", Name: "ns"}, nil, "active")
+	namespaceRegistry.EXPECT().GetNamespaceByID(namespace.ID("ns-id")).Return(nsEntry, nil)
+	closeTime := time.Date(2024, 1, 1, 1, 0, 0, 0, time.UTC)
+	running := &persistencespb.WorkflowMutableState{
+		ExecutionInfo: &persistencespb.WorkflowExecutionInfo{NamespaceId: "ns-id", WorkflowId: "wf"},
+		ExecutionState: &persistencespb.WorkflowExecutionState{
+			RunId:  "run-id",
+			State:  enumsspb.WORKFLOW_EXECUTION_STATE_RUNNING,
+			Status: enumspb.WORKFLOW_EXECUTION_STATUS_RUNNING,
+		},
+		NextEventId: 5,
+	}
+	// The execution closes after it was read, and its visibility task runs before the reindex writes the record.
+	closed := &persistencespb.WorkflowMutableState{
+		ExecutionInfo: &persistencespb.WorkflowExecutionInfo{
+			NamespaceId:           "ns-id",
+			WorkflowId:            "wf",
+			LastUpdateTime:        timestamppb.New(closeTime),
+			CloseTime:             timestamppb.New(closeTime),
+			CloseVisibilityTaskId: 9,
+		},
+		ExecutionState: &persistencespb.WorkflowExecutionState{
+			RunId:  "run-id",
+			State:  enumsspb.WORKFLOW_EXECUTION_STATE_COMPLETED,
+			Status: enumspb.WORKFLOW_EXECUTION_STATUS_COMPLETED,
+		},
+		NextEventId: 7,
+	}
+	executionManager.EXPECT().ListConcreteExecutions(gomock.Any(), gomock.Any()).Return(&persistence.ListConcreteExecutionsResponse{
+		States: []*persistencespb.WorkflowMutableState{running},
+	}, nil)
+	gomock.InOrder(
+		executionManager.EXPECT().GetWorkflowExecution(gomock.Any(), gomock.Any()).
+			Return(&persistence.GetWorkflowExecutionResponse{State: running, DBRecordVersion: 4}, nil),
+		visibilityManager.EXPECT().UpsertWorkflowExecution(gomock.Any(), gomock.Any()).Return(nil),
+		executionManager.EXPECT().GetWorkflowExecution(gomock.Any(), gomock.Any()).
+			Return(&persistence.GetWorkflowExecutionResponse{State: closed, DBRecordVersion: 5}, nil),
+		// the older running record is replaced with the closed one
+		visibilityManager.EXPECT().RecordWorkflowExecutionClosed(gomock.Any(), gomock.Any()).DoAndReturn(
+			func(_ any, request *manager.RecordWorkflowExecutionClosedRequest) error {
+				require.Equal(t, enumspb.WORKFLOW_EXECUTION_STATUS_COMPLETED, request.Status)
+				require.Equal(t, closeTime, request.CloseTime)
+				require.Equal(t, int64(9), request.TaskID)
+				return nil
+			},
+		),
+		executionManager.EXPECT().GetWorkflowExecution(gomock.Any(), gomock.Any()).
+			Return(&persistence.GetWorkflowExecutionResponse{State: closed, DBRecordVersion: 5}, nil),
+	)
+
+	a := &activities{
+		executionManager:  executionManager,
+		namespaceRegistry: namespaceRegistry,
+		visibilityManager: func() (manager.VisibilityManager, error) {
+			return visibilityManager, nil
+		},
+		logger: log.NewNoopLogger(),
+	}
+	env := (&testsuite.WorkflowTestSuite{}).NewTestActivityEnvironment()
+	env.RegisterActivity(a)
+	result, err := env.ExecuteActivity(a.ReindexShard, reindexShardRequest{ShardID: 1, RPS: 1000})
+	require.NoError(t, err)
+	var shardResult reindexShardResult
+	require.NoError(t, result.Get(&shardResult))
+	require.Equal(t, reindexShardResult{ExecutionsReindexed: 1}, shardResult)
+}
@@ -0,0 +1,159 @@
+// The MIT License
+//
+// Copyright (c) 2020 Temporal Technologies Inc.  All rights reserved.
+//
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package visibilityreindex
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	sdkworker "go.temporal.io/sdk/worker"
+	"go.temporal.io/sdk/workflow"
+	"go.uber.org/fx"
+
+	"go.temporal.io/server/common"
+	"go.temporal.io/server/common/config"
+	"go.temporal.io/server/common/dynamicconfig"
+	"go.temporal.io/server/common/headers"
+	"go.temporal.io/server/common/log"
+	"go.temporal.io/server/common/metrics"
+	"go.temporal.io/server/common/namespace"
+	"go.temporal.io/server/common/persistence"
+	"go.temporal.io/server/common/persistence/visibility"
+	"go.temporal.io/server/common/persistence/visibility/manager"
+	"go.temporal.io/server/common/persistence/visibility/store/elasticsearch"
+	esclient "go.temporal.io/server/common/persistence/visibility/store/elasticsearch/client"
+	"go.temporal.io/server/common/primitives"
+	"go.temporal.io/server/common/resolver"
+	"go.temporal.io/server/common/searchattribute"
+	workercommon "go.temporal.io/server/service/worker/common"
+)
+
+type (
+	initParams struct {
+		fx.In
+		ExecutionManager               persistence.ExecutionManager
+		NamespaceRegistry              namespace.Registry
+		PersistenceConfig              *config.Persistence
+		PersistenceServiceResolver     resolver.ServiceResolver
+		CustomVisibilityStoreFactory   visibility.VisibilityStoreFactory
+		EsClient                       esclient.Client
+		SearchAttributesProvider       searchattribute.Provider
+		SearchAttributesMapperProvider searchattribute.MapperProvider
+		DynamicCollection              *dynamicconfig.Collection
+		MetricsHandler                 metrics.Handler
+		Logger                         log.Logger
+	}
+
+	workerComponent struct {
+		initParams
+
+		visibilityManagerLock sync.Mutex
+		visibilityManager     manager.VisibilityManager
+	}
+)
+
+var Module = workercommon.AnnotateWorkerComponentProvider(newComponent)
+
+func newComponent(params initParams) workercommon.WorkerComponent {
+	return &workerComponent{initParams: params}
+}
+
+func (wc *workerComponent) RegisterWorkflow(registry sdkworker.Registry) {
+	registry.RegisterWorkflowWithOptions(Workflow, workflow.RegisterOptions{Name: WorkflowName})
+}
+
+func (wc *workerComponent) DedicatedWorkflowWorkerOptions() *workercommon.DedicatedWorkerOptions {
+	// use default worker
+	return nil
+}
+
+func (wc *workerComponent) RegisterActivities(registry sdkworker.Registry) {
+	registry.RegisterActivity(wc.activities())
+}
+
+func (wc *workerComponent) DedicatedActivityWorkerOptions() *workercommon.DedicatedWorkerOptions {
+	return &workercommon.DedicatedWorkerOptions{
+		TaskQueue: primitives.VisibilityReindexActivityTQ,
+		Options: sdkworker.Options{
+			BackgroundActivityContext: headers.SetCallerType(context.Background(), headers.CallerTypePreemptable),
+		},
+	}
+}
+
+func (wc *workerComponent) activities() *activities {
+	return &activities{
+		numHistoryShards:  wc.PersistenceConfig.NumHistoryShards,
+		executionManager:  wc.ExecutionManager,
+		namespaceRegistry: wc.NamespaceRegistry,
+		visibilityManager: wc.getVisibilityManager,
+		logger:            wc.Logger,
+	}
+}
+
+// getVisibilityManager returns a visibility manager writing to all configured visibility stores. The visibility
+// manager of the worker service never writes, so a separate one is created on the first reindex.
+func (wc *workerComponent) getVisibilityManager() (manager.VisibilityManager, error) {
+	wc.visibilityManagerLock.Lock()
+	defer wc.visibilityManagerLock.Unlock()
+
+	if wc.visibilityManager != nil {
+		return wc.visibilityManager, nil
+	}
+
+	dc := wc.DynamicCollection
+	advancedVisibilityConfigExist := wc.PersistenceConfig.AdvancedVisibilityConfigExist()
+	visibilityManager, err := visibility.NewManager(
+		*wc.PersistenceConfig,
+		wc.PersistenceServiceResolver,
+		wc.CustomVisibilityStoreFactory,
+		wc.EsClient,
+		&elasticsearch.ProcessorConfig{
+			IndexerConcurrency:       dc.GetIntProperty(dynamicconfig.WorkerIndexerConcurrency, 100),
+			ESProcessorNumOfWorkers:  dc.GetIntProperty(dynamicconfig.WorkerESProcessorNumOfWorkers, 2),
+			ESProcessorBulkActions:   dc.GetIntProperty(dynamicconfig.WorkerESProcessorBulkActions, 500),
+			ESProcessorBulkSize:      dc.GetIntProperty(dynamicconfig.WorkerESProcessorBulkSize, 16*1024*1024),
+			ESProcessorFlushInterval: dc.GetDurationProperty(dynamicconfig.WorkerESProcessorFlushInterval, 1*time.Second),
+			ESProcessorAckTimeout:    dc.GetDurationProperty(dynamicconfig.WorkerESProcessorAckTimeout, 30*time.Second),
+		},
+		wc.SearchAttributesProvider,
+		wc.SearchAttributesMapperProvider,
+		visibility.GetVisibilityPersistenceMaxReadQPS(dc, advancedVisibilityConfigExist),
+		visibility.GetVisibilityPersistenceMaxWriteQPS(dc, advancedVisibilityConfigExist),
+		dc.GetFloat64Property(dynamicconfig.OperatorRPSRatio, common.DefaultOperatorRPSRatio),
+		dynamicconfig.GetBoolPropertyFnFilteredByNamespace(false),
+		// The target store is selected by the activity, writes never go to both stores.
+		dynamicconfig.GetStringPropertyFn(visibility.SecondaryVisibilityWritingModeOff),
+		dynamicconfig.GetBoolPropertyFnFilteredByNamespace(true),
+		dynamicconfig.GetBoolPropertyFnFilteredByNamespace(true),
+		wc.MetricsHandler,
+		wc.Logger,
+	)
+	if err != nil {
+		return nil, err
+	}
+	wc.visibilityManager = visibilityManager
+	return visibilityManager, nil
+}
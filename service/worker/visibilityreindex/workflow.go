@@ -0,0 +1,195 @@
+// The MIT License
+//
+// Copyright (c) 2020 Temporal Technologies Inc.  All rights reserved.
+//
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+// Package visibilityreindex contains the workflow which writes the visibility records of all existing executions to
+// a visibility store. It fills the gaps left when moving to a new visibility store with dual visibility, which only
+// writes executions that change after it is turned on.
+package visibilityreindex
+
+import (
+	"time"
+
+	"go.temporal.io/sdk/temporal"
+	"go.temporal.io/sdk/workflow"
+
+	"go.temporal.io/server/common/primitives"
+)
+
+type (
+	// WorkflowParams is the single argument to the visibility reindex workflow.
+	WorkflowParams struct {
+		// TargetStore is the name of the visibility store to write to. The secondary visibility store when empty.
+		TargetStore string
+		// NamespaceID restricts the reindex to the executions of one namespace. All namespaces when empty.
+		NamespaceID string
+		// Concurrency is the number of history shards reindexed at the same time. The default is DefaultConcurrency.
+		Concurrency int
+		// RPS is the maximum number of executions written per second, shared by all shards reindexed at the same
+		// time. The default is DefaultRPS.
+		RPS float64
+
+		// Progress is carried over from the previous run when the workflow continues as new.
+		Progress Progress
+	}
+
+	// Progress of the visibility reindex workflow.
+	Progress struct {
+		ShardCount          int32
+		ShardsCompleted     int32
+		ExecutionsReindexed int64
+		ExecutionsSkipped   int64
+
+		// NextShardID is the first shard of the next batch, all shards before it were reindexed.
+		NextShardID int32
+		// StartTime is the time the first run of the workflow started. Executions changed after it are not
+		// reindexed, their visibility tasks write them.
+		StartTime time.Time
+	}
+
+	reindexShardRequest struct {
+		ShardID     int32
+		TargetStore string
+		NamespaceID string
+		RPS         float64
+		StartTime   time.Time
+	}
+
+	reindexShardResult struct {
+		ExecutionsReindexed int64
+		ExecutionsSkipped   int64
+	}
+)
+
+const (
+	// WorkflowName is the name of the visibility reindex workflow.
+	WorkflowName = "temporal-sys-visibility-reindex-workflow"
+	// WorkflowID is the ID of the visibility reindex workflow. There can only be one reindex at a time.
+	WorkflowID = "temporal-sys-visibility-reindex"
+	// ProgressQueryType is the query type returning the WorkflowParams of the visibility reindex workflow with its
+	// current Progress.
+	ProgressQueryType = "progress"
+	// DefaultConcurrency is the default value for WorkflowParams.Concurrency.
+	DefaultConcurrency = 1
+	// DefaultRPS is the default value for WorkflowParams.RPS.
+	DefaultRPS = 100
+
+	// shardsPerRun bounds the history size of each run, the workflow continues as new after that many shards.
+	shardsPerRun = 100
+
+	reindexShardActivityTimeout = 24 * time.Hour
+	heartbeatTimeout            = time.Minute
+)
+
+var (
+	activityRetryPolicy = &temporal.RetryPolicy{
+		InitialInterval: time.Second * 10,
+		MaximumInterval: time.Minute * 5,
+	}
+)
+
+// Workflow reindexes the executions of every history shard in batches of shardsPerRun shards. Each shard is
+// reindexed by one activity which resumes from its last heartbeat when it is retried.
+func Workflow(ctx workflow.Context, params WorkflowParams) error {
+	concurrency := params.Concurrency
+	if concurrency <= 0 {
+		concurrency = DefaultConcurrency
+	}
+	rps := params.RPS
+	if rps <= 0 {
+		rps = DefaultRPS
+	}
+	progress := params.Progress
+	if err := workflow.SetQueryHandler(ctx, ProgressQueryType, func() (WorkflowParams, error) {
+		result := params
+		result.Progress = progress
+		return result, nil
+	}); err != nil {
+		return err
+	}
+
+	var a *activities
+	if progress.ShardCount == 0 {
+		actx := workflow.WithActivityOptions(ctx, workflow.ActivityOptions{
+			TaskQueue:           primitives.VisibilityReindexActivityTQ,
+			StartToCloseTimeout: time.Minute,
+			RetryPolicy:         activityRetryPolicy,
+		})
+		if err := workflow.ExecuteActivity(actx, a.GetShardCount).Get(ctx, &progress.ShardCount); err != nil {
+			return err
+		}
+		progress.NextShardID = 1
+		progress.StartTime = workflow.Now(ctx)
+	}
+
+	reindexCtx := workflow.WithActivityOptions(ctx, workflow.ActivityOptions{
+		TaskQueue:           primitives.VisibilityReindexActivityTQ,
+		StartToCloseTimeout: reindexShardActivityTimeout,
+		HeartbeatTimeout:    heartbeatTimeout,
+		RetryPolicy:         activityRetryPolicy,
+	})
+	lastShardID := min(progress.NextShardID+shardsPerRun-1, progress.ShardCount)
+	selector := workflow.NewSelector(ctx)
+	pending := 0
+	var lastErr error
+	for shardID := progress.NextShardID; shardID <= lastShardID; shardID++ {
+		for pending >= concurrency {
+			selector.Select(ctx)
+		}
+		if lastErr != nil {
+			break
+		}
+		pending++
+		future := workflow.ExecuteActivity(reindexCtx, a.ReindexShard, reindexShardRequest{
+			ShardID:     shardID,
+			TargetStore: params.TargetStore,
+			NamespaceID: params.NamespaceID,
+			RPS:         rps / float64(concurrency),
+			StartTime:   progress.StartTime,
+		})
+		selector.AddFuture(future, func(f workflow.Future) {
+			pending--
+			var result reindexShardResult
+			if err := f.Get(ctx, &result); err != nil {
+				lastErr = err
+				return
+			}
+			progress.ShardsCompleted++
+			progress.ExecutionsReindexed += result.ExecutionsReindexed
+			progress.ExecutionsSkipped += result.ExecutionsSkipped
+		})
+	}
+	for pending > 0 {
+		selector.Select(ctx)
+	}
+	if lastErr != nil {
+		return lastErr
+	}
+
+	progress.NextShardID = lastShardID + 1
+	if progress.NextShardID > progress.ShardCount {
+		return nil
+	}
+	params.Progress = progress
+	return workflow.NewContinueAsNewError(ctx, Workflow, params)
+}
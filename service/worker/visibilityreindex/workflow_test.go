@@ -0,0 +1,123 @@
+// The MIT License
+//
+// Copyright (c) 2020 Temporal Technologies Inc.  All rights reserved.
+//
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package visibilityreindex
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.temporal.io/sdk/converter"
+	"go.temporal.io/sdk/temporal"
+	"go.temporal.io/sdk/testsuite"
+	"go.temporal.io/sdk/workflow"
+)
+
+func TestWorkflow(t *testing.T) {
+	var a *activities
+	env := (&testsuite.WorkflowTestSuite{}).NewTestWorkflowEnvironment()
+	startTime := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	env.SetStartTime(startTime)
+	env.OnActivity(a.GetShardCount, mock.Anything).Return(int32(4), nil).Once()
+	var mu sync.Mutex
+	var reindexed []int32
+	env.OnActivity(a.ReindexShard, mock.Anything, mock.Anything).Return(
+		func(_ context.Context, request reindexShardRequest) (reindexShardResult, error) {
+			mu.Lock()
+			defer mu.Unlock()
+			require.Equal(t, "es-visibility", request.TargetStore)
+			require.Equal(t, 50.0, request.RPS)
+			require.True(t, startTime.Equal(request.StartTime))
+			reindexed = append(reindexed, request.ShardID)
+			return reindexShardResult{ExecutionsReindexed: 3, ExecutionsSkipped: 1}, nil
+		},
+	)
+
+	env.ExecuteWorkflow(Workflow, WorkflowParams{TargetStore: "es-visibility", Concurrency: 2, RPS: 100})
+
+	require.True(t, env.IsWorkflowCompleted())
+	require.NoError(t, env.GetWorkflowError())
+	require.ElementsMatch(t, []int32{1, 2, 3, 4}, reindexed)
+
+	result, err := env.QueryWorkflow(ProgressQueryType)
+	require.NoError(t, err)
+	var params WorkflowParams
+	require.NoError(t, result.Get(&params))
+	require.Equal(t, "es-visibility", params.TargetStore)
+	require.True(t, startTime.Equal(params.Progress.StartTime))
+	params.Progress.StartTime = time.Time{}
+	require.Equal(t, Progress{
+		ShardCount:          4,
+		ShardsCompleted:     4,
+		ExecutionsReindexed: 12,
+		ExecutionsSkipped:   4,
+		NextShardID:         5,
+	}, params.Progress)
+	env.AssertExpectations(t)
+}
+
+func TestWorkflow_ContinueAsNew(t *testing.T) {
+	var a *activities
+	env := (&testsuite.WorkflowTestSuite{}).NewTestWorkflowEnvironment()
+	env.OnActivity(a.ReindexShard, mock.Anything, mock.Anything).Return(reindexShardResult{ExecutionsReindexed: 1}, nil)
+
+	startTime := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	env.ExecuteWorkflow(Workflow, WorkflowParams{
+		Progress: Progress{ShardCount: 250, ShardsCompleted: 100, ExecutionsReindexed: 100, NextShardID: 101, StartTime: startTime},
+	})
+
+	require.True(t, env.IsWorkflowCompleted())
+	var continueAsNewErr *workflow.ContinueAsNewError
+	require.ErrorAs(t, env.GetWorkflowError(), &continueAsNewErr)
+	var params WorkflowParams
+	require.NoError(t, converter.GetDefaultDataConverter().FromPayloads(continueAsNewErr.Input, &params))
+	require.Equal(t, Progress{
+		ShardCount:          250,
+		ShardsCompleted:     200,
+		ExecutionsReindexed: 200,
+		NextShardID:         201,
+		StartTime:           startTime,
+	}, params.Progress)
+	env.AssertNotCalled(t, "GetShardCount", mock.Anything)
+}
+
+func TestWorkflow_ReindexFailure(t *testing.T) {
+	var a *activities
+	env := (&testsuite.WorkflowTestSuite{}).NewTestWorkflowEnvironment()
+	env.OnActivity(a.GetShardCount, mock.Anything).Return(int32(4), nil)
+	env.OnActivity(a.ReindexShard, mock.Anything, mock.MatchedBy(func(request reindexShardRequest) bool {
+		return request.ShardID == 1
+	})).Return(reindexShardResult{}, temporal.NewNonRetryableApplicationError("boom", "test", errors.New("boom")))
+	env.OnActivity(a.ReindexShard, mock.Anything, mock.Anything).Return(reindexShardResult{}, nil)
+
+	env.ExecuteWorkflow(Workflow, WorkflowParams{})
+
+	require.True(t, env.IsWorkflowCompleted())
+	require.ErrorContains(t, env.GetWorkflowError(), "boom")
+}
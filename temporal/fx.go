@@ -47,6 +47,7 @@ import (
 	persistencespb "go.temporal.io/server/api/persistence/v1"
 	"go.temporal.io/server/client"
 	"go.temporal.io/server/common/archiver"
+	"go.temporal.io/server/common/audit"
 	"go.temporal.io/server/common/authorization"
 	"go.temporal.io/server/common/cluster"
 	"go.temporal.io/server/common/collection"
@@ -125,6 +126,7 @@ type (
 
 		SearchAttributesMapper searchattribute.Mapper
 		CustomInterceptors     []grpc.UnaryServerInterceptor
+		AuditSink              audit.Sink
 		Authorizer             authorization.Authorizer
 		ClaimMapper            authorization.ClaimMapper
 		AudienceGetter         authorization.JWTAudienceMapper
@@ -288,6 +290,7 @@ func ServerOptionsProvider(opts []ServerOption) (serverOptionsProvider, error) {
 
 		SearchAttributesMapper: so.searchAttributesMapper,
 		CustomInterceptors:     so.customInterceptors,
+		AuditSink:              so.auditSink,
 		Authorizer:             so.authorizer,
 		ClaimMapper:            so.claimMapper,
 		AudienceGetter:         so.audienceGetter,
@@ -355,6 +358,7 @@ type (
 		PersistenceFactoryProvider persistenceClient.FactoryProviderFn
 		SearchAttributesMapper     searchattribute.Mapper
 		CustomInterceptors         []grpc.UnaryServerInterceptor
+		AuditSink                  audit.Sink
 		Authorizer                 authorization.Authorizer
 		ClaimMapper                authorization.ClaimMapper
 		DataStoreFactory           persistenceClient.AbstractDataStoreFactory
@@ -406,6 +410,9 @@ func (params ServiceProviderParamsCommon) GetCommonServiceOptions(serviceName pr
 			func() []grpc.UnaryServerInterceptor {
 				return params.CustomInterceptors
 			},
+			func() audit.Sink {
+				return params.AuditSink
+			},
 			func() authorization.Authorizer {
 				return params.Authorizer
 			},
@@ -435,6 +442,7 @@ func (params ServiceProviderParamsCommon) GetCommonServiceOptions(serviceName pr
 			},
 		),
 		ServiceTracingModule,
+		resource.MembershipModule(&params.Cfg.Global.Membership),
 		resource.DefaultOptions,
 		FxLogAdapter,
 	)
@@ -30,6 +30,7 @@ import (
 	"google.golang.org/grpc"
 
 	"go.temporal.io/server/client"
+	"go.temporal.io/server/common/audit"
 	"go.temporal.io/server/common/authorization"
 	"go.temporal.io/server/common/config"
 	"go.temporal.io/server/common/dynamicconfig"
@@ -189,6 +190,14 @@ func WithChainedFrontendGrpcInterceptors(
 	})
 }
 
+// WithAuditSink sets a custom audit sink that receives audit records of Frontend API calls, in
+// addition to the sinks configured in config.Global.Audit.
+func WithAuditSink(sink audit.Sink) ServerOption {
+	return applyFunc(func(s *serverOptions) {
+		s.auditSink = sink
+	})
+}
+
 // WithCustomerMetricsProvider sets a custom implementation of the metrics.MetricsHandler interface
 // metrics.MetricsHandler is the base interface for publishing metric events
 func WithCustomMetricsHandler(provider metrics.Handler) ServerOption {
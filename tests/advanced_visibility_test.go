@@ -1278,17 +1278,39 @@ func (s *advancedVisibilitySuite) TestCountGroupByWorkflow() {
 		resp.Groups[1],
 	)
 
-	query = `GROUP BY WorkflowType`
+	query = fmt.Sprintf(`WorkflowType = %q GROUP BY WorkflowType, ExecutionStatus`, wt)
+	countRequest.Query = query
+	resp, err = s.engine.CountWorkflowExecutions(NewContext(), countRequest)
+	s.NoError(err)
+	s.Equal(int64(numWorkflows), resp.GetCount())
+	s.Equal(2, len(resp.Groups))
+	wtPayload, _ := searchattribute.EncodeValue(wt, enumspb.INDEXED_VALUE_TYPE_KEYWORD)
+	s.ProtoEqual(
+		&workflowservice.CountWorkflowExecutionsResponse_AggregationGroup{
+			GroupValues: []*commonpb.Payload{wtPayload, runningStatusPayload},
+			Count:       int64(numWorkflows - numClosedWorkflows),
+		},
+		resp.Groups[0],
+	)
+
+	query = fmt.Sprintf(`WorkflowType = %q GROUP BY date_trunc('day', StartTime)`, wt)
+	countRequest.Query = query
+	resp, err = s.engine.CountWorkflowExecutions(NewContext(), countRequest)
+	s.NoError(err)
+	s.Equal(int64(numWorkflows), resp.GetCount())
+	s.NotEmpty(resp.Groups)
+
+	query = `GROUP BY StartTime`
 	countRequest.Query = query
 	_, err = s.engine.CountWorkflowExecutions(NewContext(), countRequest)
 	s.Error(err)
-	s.Contains(err.Error(), "'group by' clause is only supported for ExecutionStatus search attribute")
+	s.Contains(err.Error(), "'group by' clause is only supported for search attributes of type Keyword")
 
-	query = `GROUP BY ExecutionStatus, WorkflowType`
+	query = `GROUP BY ExecutionStatus, WorkflowType, TaskQueue, RunId`
 	countRequest.Query = query
 	_, err = s.engine.CountWorkflowExecutions(NewContext(), countRequest)
 	s.Error(err)
-	s.Contains(err.Error(), "'group by' clause supports only a single field")
+	s.Contains(err.Error(), "'group by' clause supports at most 3 fields")
 }
 
 func (s *advancedVisibilitySuite) createStartWorkflowExecutionRequest(id, wt, tl string) *workflowservice.StartWorkflowExecutionRequest {
@@ -59,6 +59,14 @@ func (s *simpleMonitor) EvictSelf() error {
 	return nil
 }
 
+func (s *simpleMonitor) SetDraining(_ bool) error {
+	return nil
+}
+
+func (s *simpleMonitor) SupportsDraining() bool {
+	return true
+}
+
 func (s *simpleMonitor) GetResolver(service primitives.ServiceName) (membership.ServiceResolver, error) {
 	resolver, ok := s.resolvers[service]
 	if !ok {
@@ -130,5 +130,9 @@ func (s *simpleResolver) Members() []membership.HostInfo {
 	return s.hostInfos
 }
 
+func (s *simpleResolver) DrainingMembers() []membership.HostInfo {
+	return nil
+}
+
 func (s *simpleResolver) RequestRefresh() {
 }
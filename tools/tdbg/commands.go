@@ -517,6 +517,106 @@ func AdminShardManagement(c *cli.Context, clientFactory ClientFactory) error {
 	return nil
 }
 
+// AdminStartHistoryShardCountMigration starts a history shard count migration
+func AdminStartHistoryShardCountMigration(c *cli.Context, clientFactory ClientFactory) error {
+	adminClient := clientFactory.AdminClient(c)
+	ctx, cancel := newContext(c)
+	defer cancel()
+
+	response, err := adminClient.StartHistoryShardCountMigration(ctx, &adminservice.StartHistoryShardCountMigrationRequest{
+		TargetShardCount: int32(c.Int(FlagTargetShardCount)),
+		Concurrency:      int32(c.Int(FlagConcurrency)),
+	})
+	if err != nil {
+		return fmt.Errorf("unable to start history shard count migration: %s", err)
+	}
+	prettyPrintJSONObject(response.Migration)
+	return nil
+}
+
+// AdminDescribeHistoryShardCountMigration describes the history shard count migration
+func AdminDescribeHistoryShardCountMigration(c *cli.Context, clientFactory ClientFactory) error {
+	adminClient := clientFactory.AdminClient(c)
+	ctx, cancel := newContext(c)
+	defer cancel()
+
+	response, err := adminClient.DescribeHistoryShardCountMigration(ctx, &adminservice.DescribeHistoryShardCountMigrationRequest{})
+	if err != nil {
+		return fmt.Errorf("unable to describe history shard count migration: %s", err)
+	}
+	prettyPrintJSONObject(response)
+	return nil
+}
+
+// AdminStartVisibilityReindex starts a visibility reindex
+func AdminStartVisibilityReindex(c *cli.Context, clientFactory ClientFactory) error {
+	adminClient := clientFactory.AdminClient(c)
+	ctx, cancel := newContext(c)
+	defer cancel()
+
+	request := &adminservice.StartVisibilityReindexRequest{
+		TargetStore: c.String(FlagTargetStore),
+		Concurrency: int32(c.Int(FlagConcurrency)),
+		Rps:         c.Float64(FlagRPS),
+	}
+	if c.IsSet(FlagNamespace) {
+		request.Namespace = c.String(FlagNamespace)
+	}
+	response, err := adminClient.StartVisibilityReindex(ctx, request)
+	if err != nil {
+		return fmt.Errorf("unable to start visibility reindex: %s", err)
+	}
+	prettyPrintJSONObject(response)
+	return nil
+}
+
+// AdminDescribeVisibilityReindex describes the visibility reindex
+func AdminDescribeVisibilityReindex(c *cli.Context, clientFactory ClientFactory) error {
+	adminClient := clientFactory.AdminClient(c)
+	ctx, cancel := newContext(c)
+	defer cancel()
+
+	response, err := adminClient.DescribeVisibilityReindex(ctx, &adminservice.DescribeVisibilityReindexRequest{})
+	if err != nil {
+		return fmt.Errorf("unable to describe visibility reindex: %s", err)
+	}
+	prettyPrintJSONObject(response)
+	return nil
+}
+
+// AdminSaveVisibilityQuery saves a visibility query in a namespace
+func AdminSaveVisibilityQuery(c *cli.Context, clientFactory ClientFactory) error {
+	adminClient := clientFactory.AdminClient(c)
+	ctx, cancel := newContext(c)
+	defer cancel()
+
+	_, err := adminClient.SaveVisibilityQuery(ctx, &adminservice.SaveVisibilityQueryRequest{
+		Namespace: c.String(FlagNamespace),
+		Name:      c.String(FlagSavedQueryName),
+		Query:     c.String(FlagQuery),
+	})
+	if err != nil {
+		return fmt.Errorf("unable to save visibility query: %s", err)
+	}
+	return nil
+}
+
+// AdminDeleteSavedVisibilityQuery deletes a saved visibility query from a namespace
+func AdminDeleteSavedVisibilityQuery(c *cli.Context, clientFactory ClientFactory) error {
+	adminClient := clientFactory.AdminClient(c)
+	ctx, cancel := newContext(c)
+	defer cancel()
+
+	_, err := adminClient.DeleteSavedVisibilityQuery(ctx, &adminservice.DeleteSavedVisibilityQueryRequest{
+		Namespace: c.String(FlagNamespace),
+		Name:      c.String(FlagSavedQueryName),
+	})
+	if err != nil {
+		return fmt.Errorf("unable to delete saved visibility query: %s", err)
+	}
+	return nil
+}
+
 // AdminListGossipMembers outputs a list of gossip members
 func AdminListGossipMembers(c *cli.Context, clientFactory ClientFactory) error {
 	roleFlag := c.String(FlagClusterMembershipRole)
@@ -575,6 +675,58 @@ func AdminListClusterMembers(c *cli.Context, clientFactory ClientFactory) error
 	return nil
 }
 
+// AdminDrainHistoryHost starts draining shards off a history host
+func AdminDrainHistoryHost(c *cli.Context, clientFactory ClientFactory) error {
+	adminClient := clientFactory.AdminClient(c)
+	ctx, cancel := newContext(c)
+	defer cancel()
+
+	resp, err := adminClient.DrainHistoryHost(ctx, &adminservice.DrainHistoryHostRequest{
+		HostAddress: c.String(FlagHistoryAddress),
+	})
+	if err != nil {
+		return fmt.Errorf("unable to drain History host: %s", err)
+	}
+	prettyPrintJSONObject(resp.GetDrainInfo())
+	return nil
+}
+
+// AdminUndrainHistoryHost stops draining shards off a history host
+func AdminUndrainHistoryHost(c *cli.Context, clientFactory ClientFactory) error {
+	adminClient := clientFactory.AdminClient(c)
+	ctx, cancel := newContext(c)
+	defer cancel()
+
+	resp, err := adminClient.UndrainHistoryHost(ctx, &adminservice.UndrainHistoryHostRequest{
+		HostAddress: c.String(FlagHistoryAddress),
+	})
+	if err != nil {
+		return fmt.Errorf("unable to undrain History host: %s", err)
+	}
+	prettyPrintJSONObject(resp.GetDrainInfo())
+	return nil
+}
+
+// AdminDescribeHistoryHostDrain outputs the drain status of a history host
+func AdminDescribeHistoryHostDrain(c *cli.Context, clientFactory ClientFactory) error {
+	adminClient := clientFactory.AdminClient(c)
+	ctx, cancel := newContext(c)
+	defer cancel()
+
+	resp, err := adminClient.DescribeHistoryHost(ctx, &adminservice.DescribeHistoryHostRequest{
+		HostAddress: c.String(FlagHistoryAddress),
+	})
+	if err != nil {
+		return fmt.Errorf("unable to describe History host: %s", err)
+	}
+	if resp.GetDrainInfo() == nil {
+		fmt.Println("History host is not draining")
+		return nil
+	}
+	prettyPrintJSONObject(resp.GetDrainInfo())
+	return nil
+}
+
 // AdminDescribeHistoryHost describes history host
 func AdminDescribeHistoryHost(c *cli.Context, clientFactory ClientFactory) error {
 	adminClient := clientFactory.AdminClient(c)
@@ -68,6 +68,7 @@ var (
 	FlagLastMessageID              = "last-message-id"
 	FlagJobToken                   = "job-token"
 	FlagReason                     = "reason"
+	FlagIdentity                   = "identity"
 	FlagYes                        = "yes"
 	FlagMore                       = "more"
 	FlagMinEventVersion            = "min-event-version"
@@ -86,4 +87,10 @@ var (
 	FlagBase64File                 = "base64-file"
 	FlagTaskCategoryID             = "task-category-id"
 	FlagEncoding                   = "encoding"
+	FlagTargetShardCount           = "target-shard-count"
+	FlagConcurrency                = "concurrency"
+	FlagQuery                      = "query"
+	FlagTargetStore                = "target-store"
+	FlagRPS                        = "rps"
+	FlagSavedQueryName             = "name"
 )
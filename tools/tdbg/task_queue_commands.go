@@ -105,3 +105,66 @@ func AdminListTaskQueueTasks(c *cli.Context, clientFactory ClientFactory) error
 	}
 	return nil
 }
+
+// AdminPauseTaskQueue pauses dispatch of tasks from a task queue
+func AdminPauseTaskQueue(c *cli.Context, clientFactory ClientFactory) error {
+	namespace, err := getRequiredOption(c, FlagNamespace)
+	if err != nil {
+		return err
+	}
+	tqType, err := parsePausableTaskQueueType(c)
+	if err != nil {
+		return err
+	}
+
+	client := clientFactory.AdminClient(c)
+	ctx, cancel := newContext(c)
+	defer cancel()
+	_, err = client.PauseTaskQueue(ctx, &adminservice.PauseTaskQueueRequest{
+		Namespace:     namespace,
+		TaskQueue:     c.String(FlagTaskQueue),
+		TaskQueueType: tqType,
+		Reason:        c.String(FlagReason),
+		Identity:      c.String(FlagIdentity),
+	})
+	if err != nil {
+		return fmt.Errorf("unable to pause Task Queue: %v", err)
+	}
+	fmt.Println("Task Queue paused.")
+	return nil
+}
+
+// AdminResumeTaskQueue resumes dispatch of tasks from a paused task queue
+func AdminResumeTaskQueue(c *cli.Context, clientFactory ClientFactory) error {
+	namespace, err := getRequiredOption(c, FlagNamespace)
+	if err != nil {
+		return err
+	}
+	tqType, err := parsePausableTaskQueueType(c)
+	if err != nil {
+		return err
+	}
+
+	client := clientFactory.AdminClient(c)
+	ctx, cancel := newContext(c)
+	defer cancel()
+	_, err = client.ResumeTaskQueue(ctx, &adminservice.ResumeTaskQueueRequest{
+		Namespace:     namespace,
+		TaskQueue:     c.String(FlagTaskQueue),
+		TaskQueueType: tqType,
+		Identity:      c.String(FlagIdentity),
+	})
+	if err != nil {
+		return fmt.Errorf("unable to resume Task Queue: %v", err)
+	}
+	fmt.Println("Task Queue resumed.")
+	return nil
+}
+
+func parsePausableTaskQueueType(c *cli.Context) (enumspb.TaskQueueType, error) {
+	tlTypeInt, err := StringToEnum(c.String(FlagTaskQueueType), enumspb.TaskQueueType_shorthandValue)
+	if err != nil {
+		return enumspb.TASK_QUEUE_TYPE_UNSPECIFIED, fmt.Errorf("invalid task queue type: %v", err)
+	}
+	return enumspb.TaskQueueType(tlTypeInt), nil
+}
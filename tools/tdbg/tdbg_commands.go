@@ -70,6 +70,12 @@ func getCommands(
 			Usage:       "Run admin operation on taskQueue",
 			Subcommands: newAdminTaskQueueCommands(clientFactory),
 		},
+		{
+			Name:        "visibility",
+			Aliases:     []string{"v"},
+			Usage:       "Run admin operation on visibility",
+			Subcommands: newAdminVisibilityCommands(clientFactory),
+		},
 		{
 			Name:        "membership",
 			Aliases:     []string{"m"},
@@ -219,6 +225,34 @@ func newAdminWorkflowCommands(clientFactory ClientFactory, prompterFactory Promp
 				return AdminRebuildMutableState(c, clientFactory)
 			},
 		},
+		{
+			Name:  "verify",
+			Usage: "Replay workflow history locally and compare the result with the persisted mutable state",
+			Flags: []cli.Flag{
+				&cli.StringFlag{
+					Name:    FlagWorkflowID,
+					Aliases: FlagWorkflowIDAlias,
+					Usage:   "Workflow ID",
+				},
+				&cli.StringFlag{
+					Name:    FlagRunID,
+					Aliases: FlagRunIDAlias,
+					Usage:   "Run ID",
+				},
+				&cli.StringFlag{
+					Name:  FlagQuery,
+					Usage: "Visibility query selecting the workflow executions to verify, instead of a single workflow",
+				},
+				&cli.IntFlag{
+					Name:  FlagPageSize,
+					Value: 100,
+					Usage: "Page size of the visibility query",
+				},
+			},
+			Action: func(c *cli.Context) error {
+				return AdminVerifyWorkflow(c, clientFactory)
+			},
+		},
 		{
 			Name:    "delete",
 			Aliases: []string{"del"},
@@ -353,6 +387,32 @@ func newAdminShardManagementCommands(clientFactory ClientFactory, taskCategoryRe
 				return AdminRemoveTask(c, clientFactory, taskCategoryRegistry)
 			},
 		},
+		{
+			Name:  "migrate-count",
+			Usage: "Start migrating the cluster to a larger number of history shards",
+			Flags: []cli.Flag{
+				&cli.IntFlag{
+					Name:     FlagTargetShardCount,
+					Usage:    "The new number of history shards, a multiple of the current one",
+					Required: true,
+				},
+				&cli.IntFlag{
+					Name:  FlagConcurrency,
+					Value: 1,
+					Usage: "The number of shards copied at the same time",
+				},
+			},
+			Action: func(c *cli.Context) error {
+				return AdminStartHistoryShardCountMigration(c, clientFactory)
+			},
+		},
+		{
+			Name:  "describe-count-migration",
+			Usage: "Describe the progress of the history shard count migration",
+			Action: func(c *cli.Context) error {
+				return AdminDescribeHistoryShardCountMigration(c, clientFactory)
+			},
+		},
 	}
 }
 
@@ -406,6 +466,48 @@ func newAdminMembershipCommands(clientFactory ClientFactory) []*cli.Command {
 				return AdminListClusterMembers(c, clientFactory)
 			},
 		},
+		{
+			Name:  "drain",
+			Usage: "Hand off all shards of a history host to the other hosts ahead of a shutdown",
+			Flags: []cli.Flag{
+				&cli.StringFlag{
+					Name:     FlagHistoryAddress,
+					Usage:    "History Host address(IP:PORT)",
+					Required: true,
+				},
+			},
+			Action: func(c *cli.Context) error {
+				return AdminDrainHistoryHost(c, clientFactory)
+			},
+		},
+		{
+			Name:  "undrain",
+			Usage: "Stop draining a history host, which acquires shards again",
+			Flags: []cli.Flag{
+				&cli.StringFlag{
+					Name:     FlagHistoryAddress,
+					Usage:    "History Host address(IP:PORT)",
+					Required: true,
+				},
+			},
+			Action: func(c *cli.Context) error {
+				return AdminUndrainHistoryHost(c, clientFactory)
+			},
+		},
+		{
+			Name:  "drain-status",
+			Usage: "Show drain status of a history host",
+			Flags: []cli.Flag{
+				&cli.StringFlag{
+					Name:     FlagHistoryAddress,
+					Usage:    "History Host address(IP:PORT)",
+					Required: true,
+				},
+			},
+			Action: func(c *cli.Context) error {
+				return AdminDescribeHistoryHostDrain(c, clientFactory)
+			},
+		},
 	}
 }
 
@@ -505,6 +607,124 @@ func newAdminTaskQueueCommands(clientFactory ClientFactory) []*cli.Command {
 				return AdminListTaskQueueTasks(c, clientFactory)
 			},
 		},
+		{
+			Name:  "pause",
+			Usage: "Pause dispatch of tasks from a task queue, tasks keep being added to its backlog",
+			Flags: []cli.Flag{
+				&cli.StringFlag{
+					Name:     FlagTaskQueue,
+					Usage:    "Task Queue name",
+					Required: true,
+				},
+				&cli.StringFlag{
+					Name:  FlagTaskQueueType,
+					Usage: "Task Queue type: activity, workflow. Both are paused if not set",
+				},
+				&cli.StringFlag{
+					Name:  FlagReason,
+					Usage: "Reason for pausing the task queue",
+				},
+				&cli.StringFlag{
+					Name:  FlagIdentity,
+					Usage: "Identity of the operator pausing the task queue",
+				},
+			},
+			Action: func(c *cli.Context) error {
+				return AdminPauseTaskQueue(c, clientFactory)
+			},
+		},
+		{
+			Name:  "resume",
+			Usage: "Resume dispatch of tasks from a paused task queue",
+			Flags: []cli.Flag{
+				&cli.StringFlag{
+					Name:     FlagTaskQueue,
+					Usage:    "Task Queue name",
+					Required: true,
+				},
+				&cli.StringFlag{
+					Name:  FlagTaskQueueType,
+					Usage: "Task Queue type: activity, workflow. Both are resumed if not set",
+				},
+				&cli.StringFlag{
+					Name:  FlagIdentity,
+					Usage: "Identity of the operator resuming the task queue",
+				},
+			},
+			Action: func(c *cli.Context) error {
+				return AdminResumeTaskQueue(c, clientFactory)
+			},
+		},
+	}
+}
+
+func newAdminVisibilityCommands(clientFactory ClientFactory) []*cli.Command {
+	return []*cli.Command{
+		{
+			Name: "reindex",
+			Usage: "Start writing the visibility records of existing workflow executions to a visibility store, " +
+				"only of the given namespace if --namespace is set",
+			Flags: []cli.Flag{
+				&cli.StringFlag{
+					Name:  FlagTargetStore,
+					Usage: "The name of the visibility store to write to, the secondary visibility store by default",
+				},
+				&cli.IntFlag{
+					Name:  FlagConcurrency,
+					Value: 1,
+					Usage: "The number of history shards reindexed at the same time",
+				},
+				&cli.Float64Flag{
+					Name:  FlagRPS,
+					Value: 100,
+					Usage: "The maximum number of executions written per second",
+				},
+			},
+			Action: func(c *cli.Context) error {
+				return AdminStartVisibilityReindex(c, clientFactory)
+			},
+		},
+		{
+			Name:  "describe-reindex",
+			Usage: "Describe the progress of the visibility reindex",
+			Action: func(c *cli.Context) error {
+				return AdminDescribeVisibilityReindex(c, clientFactory)
+			},
+		},
+		{
+			Name: "save-query",
+			Usage: "Save a visibility query in the namespace, users run it by passing \"@<name>\" as the query of " +
+				"ListWorkflowExecutions and it isn't checked against the namespace max visibility query cost",
+			Flags: []cli.Flag{
+				&cli.StringFlag{
+					Name:     FlagSavedQueryName,
+					Usage:    "The name of the saved query",
+					Required: true,
+				},
+				&cli.StringFlag{
+					Name:     FlagQuery,
+					Usage:    "The visibility query",
+					Required: true,
+				},
+			},
+			Action: func(c *cli.Context) error {
+				return AdminSaveVisibilityQuery(c, clientFactory)
+			},
+		},
+		{
+			Name:  "delete-query",
+			Usage: "Delete a saved visibility query from the namespace",
+			Flags: []cli.Flag{
+				&cli.StringFlag{
+					Name:     FlagSavedQueryName,
+					Usage:    "The name of the saved query",
+					Required: true,
+				},
+			},
+			Action: func(c *cli.Context) error {
+				return AdminDeleteSavedVisibilityQuery(c, clientFactory)
+			},
+		},
 	}
 }
 
@@ -0,0 +1,574 @@
+// The MIT License
+//
+// Copyright (c) 2020 Temporal Technologies Inc.  All rights reserved.
+//
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package tdbg
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"runtime/debug"
+	"sync/atomic"
+	"time"
+
+	"github.com/pborman/uuid"
+	commonpb "go.temporal.io/api/common/v1"
+	historypb "go.temporal.io/api/history/v1"
+	"go.temporal.io/api/serviceerror"
+
+	"go.temporal.io/server/api/adminservice/v1"
+	clockspb "go.temporal.io/server/api/clock/v1"
+	"go.temporal.io/server/api/historyservice/v1"
+	persistencespb "go.temporal.io/server/api/persistence/v1"
+	"go.temporal.io/server/common"
+	"go.temporal.io/server/common/archiver"
+	"go.temporal.io/server/common/clock"
+	"go.temporal.io/server/common/cluster"
+	"go.temporal.io/server/common/definition"
+	"go.temporal.io/server/common/log"
+	"go.temporal.io/server/common/metrics"
+	"go.temporal.io/server/common/namespace"
+	"go.temporal.io/server/common/persistence"
+	"go.temporal.io/server/common/persistence/serialization"
+	"go.temporal.io/server/common/searchattribute"
+	"go.temporal.io/server/service/history/configs"
+	"go.temporal.io/server/service/history/events"
+	"go.temporal.io/server/service/history/shard"
+	"go.temporal.io/server/service/history/tasks"
+	"go.temporal.io/server/service/history/workflow"
+)
+
+type (
+	// replayShard implements shard.Context for the mutable state rebuilder, so history can be
+	// replayed outside of the history service. Methods which need a running history service fail
+	// with errReplayNotSupported, or panic with it if they can't return an error.
+	replayShard struct {
+		namespaceRegistry *replayNamespaceRegistry
+		clusterMetadata   cluster.Metadata
+		config            *configs.Config
+		archivalMetadata  archiver.ArchivalMetadata
+		eventsCache       *replayEventsCache
+		serializer        serialization.Serializer
+		timeSource        clock.TimeSource
+		logger            log.Logger
+		shardID           int32
+		taskID            atomic.Int64
+	}
+
+	// replayNamespaceRegistry resolves namespaces through the admin API and caches them for the
+	// lifetime of the command.
+	replayNamespaceRegistry struct {
+		ctx         context.Context
+		adminClient adminservice.AdminServiceClient
+		byID        map[namespace.ID]*namespace.Namespace
+		byName      map[namespace.Name]*namespace.Namespace
+	}
+
+	// replayEventsCache keeps the events put by the rebuilder in memory, there is no persistence
+	// to fall back to.
+	replayEventsCache struct {
+		events map[events.EventKey]*historypb.HistoryEvent
+	}
+
+	// replayExecutionManager only provides the history branch utilities needed to initialize the
+	// history tree of the replayed workflow.
+	replayExecutionManager struct{}
+)
+
+var (
+	// errReplayNotSupported is reported when the replayed history needs more than the replay shard provides.
+	errReplayNotSupported = errors.New("replay is not supported for this history")
+
+	_ shard.Context                = (*replayShard)(nil)
+	_ namespace.Registry           = (*replayNamespaceRegistry)(nil)
+	_ events.Cache                 = (*replayEventsCache)(nil)
+	_ persistence.ExecutionManager = replayExecutionManager{}
+)
+
+func replayNotSupported(method string) error {
+	return fmt.Errorf("%w: %s needs a running history service", errReplayNotSupported, method)
+}
+
+// replay applies the history batch by batch, the same way the state rebuilder of the history service does.
+// Panics other than the ones of the replay shard are bugs and reported with their stack trace.
+func (s *replayShard) replay(
+	ctx context.Context,
+	nsEntry *namespace.Namespace,
+	execution *commonpb.WorkflowExecution,
+	history [][]*historypb.HistoryEvent,
+) (_ workflow.MutableState, retErr error) {
+	defer func() {
+		if r := recover(); r != nil {
+			if err, ok := r.(error); ok && errors.Is(err, errReplayNotSupported) {
+				retErr = err
+				return
+			}
+			retErr = fmt.Errorf("unexpected panic while replaying history: %v\n%s", r, debug.Stack())
+		}
+	}()
+
+	s.shardID = common.WorkflowIDToHistoryShard(nsEntry.ID().String(), execution.GetWorkflowId(), s.config.NumberOfShards)
+	mutableState := workflow.NewMutableState(
+		s,
+		s.eventsCache,
+		s.logger,
+		nsEntry,
+		history[0][0].GetEventTime().AsTime(),
+	)
+	rebuilder := workflow.NewMutableStateRebuilder(s, s.logger, mutableState)
+	requestID := uuid.New()
+	for _, batch := range history {
+		if _, err := rebuilder.ApplyEvents(
+			ctx,
+			nsEntry.ID(),
+			requestID,
+			execution,
+			[][]*historypb.HistoryEvent{batch},
+			nil, // the new run of continue-as-new is verified on its own
+		); err != nil {
+			return nil, err
+		}
+	}
+	return mutableState, nil
+}
+
+func (s *replayShard) GetShardID() int32 {
+	return s.shardID
+}
+
+func (s *replayShard) GetRangeID() int64 {
+	panic(replayNotSupported("GetRangeID"))
+}
+
+func (s *replayShard) GetOwner() string {
+	panic(replayNotSupported("GetOwner"))
+}
+
+func (s *replayShard) GetExecutionManager() persistence.ExecutionManager {
+	return replayExecutionManager{}
+}
+
+func (s *replayShard) GetNamespaceRegistry() namespace.Registry {
+	return s.namespaceRegistry
+}
+
+func (s *replayShard) GetClusterMetadata() cluster.Metadata {
+	return s.clusterMetadata
+}
+
+func (s *replayShard) GetConfig() *configs.Config {
+	return s.config
+}
+
+func (s *replayShard) GetEventsCache() events.Cache {
+	return s.eventsCache
+}
+
+func (s *replayShard) GetLogger() log.Logger {
+	return s.logger
+}
+
+func (s *replayShard) GetThrottledLogger() log.Logger {
+	return s.logger
+}
+
+func (s *replayShard) GetMetricsHandler() metrics.Handler {
+	return metrics.NoopMetricsHandler
+}
+
+func (s *replayShard) GetTimeSource() clock.TimeSource {
+	return s.timeSource
+}
+
+func (s *replayShard) GetRemoteAdminClient(string) (adminservice.AdminServiceClient, error) {
+	return nil, replayNotSupported("GetRemoteAdminClient")
+}
+
+func (s *replayShard) GetHistoryClient() historyservice.HistoryServiceClient {
+	panic(replayNotSupported("GetHistoryClient"))
+}
+
+func (s *replayShard) GetPayloadSerializer() serialization.Serializer {
+	return s.serializer
+}
+
+func (s *replayShard) GetSearchAttributesProvider() searchattribute.Provider {
+	panic(replayNotSupported("GetSearchAttributesProvider"))
+}
+
+func (s *replayShard) GetSearchAttributesMapperProvider() searchattribute.MapperProvider {
+	panic(replayNotSupported("GetSearchAttributesMapperProvider"))
+}
+
+func (s *replayShard) GetArchivalMetadata() archiver.ArchivalMetadata {
+	return s.archivalMetadata
+}
+
+func (s *replayShard) GetEngine(context.Context) (shard.Engine, error) {
+	return nil, replayNotSupported("GetEngine")
+}
+
+func (s *replayShard) AssertOwnership(context.Context) error {
+	return replayNotSupported("AssertOwnership")
+}
+
+func (s *replayShard) NewVectorClock() (*clockspb.VectorClock, error) {
+	return nil, replayNotSupported("NewVectorClock")
+}
+
+func (s *replayShard) CurrentVectorClock() *clockspb.VectorClock {
+	panic(replayNotSupported("CurrentVectorClock"))
+}
+
+func (s *replayShard) GenerateTaskID() (int64, error) {
+	return s.taskID.Add(1), nil
+}
+
+func (s *replayShard) GenerateTaskIDs(number int) ([]int64, error) {
+	result := make([]int64, number)
+	for i := range result {
+		result[i] = s.taskID.Add(1)
+	}
+	return result, nil
+}
+
+func (s *replayShard) GetQueueExclusiveHighReadWatermark(tasks.Category) tasks.Key {
+	panic(replayNotSupported("GetQueueExclusiveHighReadWatermark"))
+}
+
+func (s *replayShard) GetQueueState(tasks.Category) (*persistencespb.QueueState, bool) {
+	panic(replayNotSupported("GetQueueState"))
+}
+
+func (s *replayShard) SetQueueState(tasks.Category, *persistencespb.QueueState) error {
+	return replayNotSupported("SetQueueState")
+}
+
+func (s *replayShard) UpdateReplicationQueueReaderState(int64, *persistencespb.QueueReaderState) error {
+	return replayNotSupported("UpdateReplicationQueueReaderState")
+}
+
+func (s *replayShard) GetReplicatorDLQAckLevel(string) int64 {
+	panic(replayNotSupported("GetReplicatorDLQAckLevel"))
+}
+
+func (s *replayShard) UpdateReplicatorDLQAckLevel(string, int64) error {
+	return replayNotSupported("UpdateReplicatorDLQAckLevel")
+}
+
+func (s *replayShard) UpdateRemoteClusterInfo(string, int64, time.Time) {
+	panic(replayNotSupported("UpdateRemoteClusterInfo"))
+}
+
+func (s *replayShard) UpdateRemoteReaderInfo(int64, int64, time.Time) error {
+	return replayNotSupported("UpdateRemoteReaderInfo")
+}
+
+func (s *replayShard) SetCurrentTime(string, time.Time) {
+	panic(replayNotSupported("SetCurrentTime"))
+}
+
+func (s *replayShard) GetCurrentTime(string) time.Time {
+	panic(replayNotSupported("GetCurrentTime"))
+}
+
+func (s *replayShard) GetReplicationStatus([]string) (map[string]*historyservice.ShardReplicationStatusPerCluster, map[string]*historyservice.HandoverNamespaceInfo, error) {
+	return nil, nil, replayNotSupported("GetReplicationStatus")
+}
+
+func (s *replayShard) UpdateHandoverNamespace(*namespace.Namespace, bool) {
+	panic(replayNotSupported("UpdateHandoverNamespace"))
+}
+
+func (s *replayShard) AppendHistoryEvents(context.Context, *persistence.AppendHistoryNodesRequest, namespace.ID, *commonpb.WorkflowExecution) (int, error) {
+	return 0, replayNotSupported("AppendHistoryEvents")
+}
+
+func (s *replayShard) AddTasks(context.Context, *persistence.AddHistoryTasksRequest) error {
+	return replayNotSupported("AddTasks")
+}
+
+func (s *replayShard) AddSpeculativeWorkflowTaskTimeoutTask(*tasks.WorkflowTaskTimeoutTask) error {
+	return replayNotSupported("AddSpeculativeWorkflowTaskTimeoutTask")
+}
+
+func (s *replayShard) CreateWorkflowExecution(context.Context, *persistence.CreateWorkflowExecutionRequest) (*persistence.CreateWorkflowExecutionResponse, error) {
+	return nil, replayNotSupported("CreateWorkflowExecution")
+}
+
+func (s *replayShard) UpdateWorkflowExecution(context.Context, *persistence.UpdateWorkflowExecutionRequest) (*persistence.UpdateWorkflowExecutionResponse, error) {
+	return nil, replayNotSupported("UpdateWorkflowExecution")
+}
+
+func (s *replayShard) ConflictResolveWorkflowExecution(context.Context, *persistence.ConflictResolveWorkflowExecutionRequest) (*persistence.ConflictResolveWorkflowExecutionResponse, error) {
+	return nil, replayNotSupported("ConflictResolveWorkflowExecution")
+}
+
+func (s *replayShard) SetWorkflowExecution(context.Context, *persistence.SetWorkflowExecutionRequest) (*persistence.SetWorkflowExecutionResponse, error) {
+	return nil, replayNotSupported("SetWorkflowExecution")
+}
+
+func (s *replayShard) GetCurrentExecution(context.Context, *persistence.GetCurrentExecutionRequest) (*persistence.GetCurrentExecutionResponse, error) {
+	return nil, replayNotSupported("GetCurrentExecution")
+}
+
+func (s *replayShard) GetWorkflowExecution(context.Context, *persistence.GetWorkflowExecutionRequest) (*persistence.GetWorkflowExecutionResponse, error) {
+	return nil, replayNotSupported("GetWorkflowExecution")
+}
+
+func (s *replayShard) DeleteWorkflowExecution(context.Context, definition.WorkflowKey, []byte, time.Time, time.Time, int64, *tasks.DeleteWorkflowExecutionStage) error {
+	return replayNotSupported("DeleteWorkflowExecution")
+}
+
+func (s *replayShard) UnloadForOwnershipLost() {
+	panic(replayNotSupported("UnloadForOwnershipLost"))
+}
+
+func (r *replayNamespaceRegistry) GetPingChecks() []common.PingCheck {
+	return nil
+}
+
+func (r *replayNamespaceRegistry) GetNamespace(name namespace.Name) (*namespace.Namespace, error) {
+	if ns, ok := r.byName[name]; ok {
+		return ns, nil
+	}
+	return r.load(&adminservice.GetNamespaceRequest{
+		Attributes: &adminservice.GetNamespaceRequest_Namespace{Namespace: name.String()},
+	})
+}
+
+func (r *replayNamespaceRegistry) GetNamespaceByID(id namespace.ID) (*namespace.Namespace, error) {
+	if ns, ok := r.byID[id]; ok {
+		return ns, nil
+	}
+	return r.load(&adminservice.GetNamespaceRequest{
+		Attributes: &adminservice.GetNamespaceRequest_Id{Id: id.String()},
+	})
+}
+
+func (r *replayNamespaceRegistry) GetNamespaceID(name namespace.Name) (namespace.ID, error) {
+	ns, err := r.GetNamespace(name)
+	if err != nil {
+		return namespace.EmptyID, err
+	}
+	return ns.ID(), nil
+}
+
+func (r *replayNamespaceRegistry) GetNamespaceName(id namespace.ID) (namespace.Name, error) {
+	ns, err := r.GetNamespaceByID(id)
+	if err != nil {
+		return namespace.EmptyName, err
+	}
+	return ns.Name(), nil
+}
+
+func (r *replayNamespaceRegistry) GetCacheSize() (int64, int64) {
+	return int64(len(r.byName)), int64(len(r.byID))
+}
+
+func (r *replayNamespaceRegistry) RegisterStateChangeCallback(any, namespace.StateChangeCallbackFn) {
+	// Namespaces don't change while the command runs.
+}
+
+func (r *replayNamespaceRegistry) UnregisterStateChangeCallback(any) {
+}
+
+func (r *replayNamespaceRegistry) GetCustomSearchAttributesMapper(namespace.Name) (namespace.CustomSearchAttributesMapper, error) {
+	return namespace.CustomSearchAttributesMapper{}, replayNotSupported("GetCustomSearchAttributesMapper")
+}
+
+func (r *replayNamespaceRegistry) Start() {
+}
+
+func (r *replayNamespaceRegistry) Stop() {
+}
+
+func (r *replayNamespaceRegistry) add(ns *namespace.Namespace) {
+	r.byID[ns.ID()] = ns
+	r.byName[ns.Name()] = ns
+}
+
+func (r *replayNamespaceRegistry) load(request *adminservice.GetNamespaceRequest) (*namespace.Namespace, error) {
+	resp, err := r.adminClient.GetNamespace(r.ctx, request)
+	if err != nil {
+		return nil, err
+	}
+	ns := namespace.FromAdminClientApiResponse(resp)
+	r.add(ns)
+	return ns, nil
+}
+
+func (c *replayEventsCache) GetEvent(_ context.Context, key events.EventKey, _ int64, _ []byte) (*historypb.HistoryEvent, error) {
+	if event, ok := c.events[key]; ok {
+		return event, nil
+	}
+	return nil, serviceerror.NewNotFound(fmt.Sprintf("event %v is not in the replayed history", key.EventID))
+}
+
+func (c *replayEventsCache) PutEvent(key events.EventKey, event *historypb.HistoryEvent) {
+	c.events[key] = event
+}
+
+func (c *replayEventsCache) DeleteEvent(key events.EventKey) {
+	delete(c.events, key)
+}
+
+func (replayExecutionManager) Close() {
+}
+
+func (replayExecutionManager) GetName() string {
+	return "tdbg-replay"
+}
+
+func (replayExecutionManager) GetHistoryBranchUtil() persistence.HistoryBranchUtil {
+	return &persistence.HistoryBranchUtilImpl{}
+}
+
+func (replayExecutionManager) CreateWorkflowExecution(context.Context, *persistence.CreateWorkflowExecutionRequest) (*persistence.CreateWorkflowExecutionResponse, error) {
+	return nil, replayNotSupported("ExecutionManager.CreateWorkflowExecution")
+}
+
+func (replayExecutionManager) UpdateWorkflowExecution(context.Context, *persistence.UpdateWorkflowExecutionRequest) (*persistence.UpdateWorkflowExecutionResponse, error) {
+	return nil, replayNotSupported("ExecutionManager.UpdateWorkflowExecution")
+}
+
+func (replayExecutionManager) ConflictResolveWorkflowExecution(context.Context, *persistence.ConflictResolveWorkflowExecutionRequest) (*persistence.ConflictResolveWorkflowExecutionResponse, error) {
+	return nil, replayNotSupported("ExecutionManager.ConflictResolveWorkflowExecution")
+}
+
+func (replayExecutionManager) DeleteWorkflowExecution(context.Context, *persistence.DeleteWorkflowExecutionRequest) error {
+	return replayNotSupported("ExecutionManager.DeleteWorkflowExecution")
+}
+
+func (replayExecutionManager) DeleteCurrentWorkflowExecution(context.Context, *persistence.DeleteCurrentWorkflowExecutionRequest) error {
+	return replayNotSupported("ExecutionManager.DeleteCurrentWorkflowExecution")
+}
+
+func (replayExecutionManager) GetCurrentExecution(context.Context, *persistence.GetCurrentExecutionRequest) (*persistence.GetCurrentExecutionResponse, error) {
+	return nil, replayNotSupported("ExecutionManager.GetCurrentExecution")
+}
+
+func (replayExecutionManager) GetWorkflowExecution(context.Context, *persistence.GetWorkflowExecutionRequest) (*persistence.GetWorkflowExecutionResponse, error) {
+	return nil, replayNotSupported("ExecutionManager.GetWorkflowExecution")
+}
+
+func (replayExecutionManager) SetWorkflowExecution(context.Context, *persistence.SetWorkflowExecutionRequest) (*persistence.SetWorkflowExecutionResponse, error) {
+	return nil, replayNotSupported("ExecutionManager.SetWorkflowExecution")
+}
+
+func (replayExecutionManager) ListConcreteExecutions(context.Context, *persistence.ListConcreteExecutionsRequest) (*persistence.ListConcreteExecutionsResponse, error) {
+	return nil, replayNotSupported("ExecutionManager.ListConcreteExecutions")
+}
+
+func (replayExecutionManager) RegisterHistoryTaskReader(context.Context, *persistence.RegisterHistoryTaskReaderRequest) error {
+	return replayNotSupported("ExecutionManager.RegisterHistoryTaskReader")
+}
+
+func (replayExecutionManager) UnregisterHistoryTaskReader(context.Context, *persistence.UnregisterHistoryTaskReaderRequest) {
+	panic(replayNotSupported("ExecutionManager.UnregisterHistoryTaskReader"))
+}
+
+func (replayExecutionManager) UpdateHistoryTaskReaderProgress(context.Context, *persistence.UpdateHistoryTaskReaderProgressRequest) {
+	panic(replayNotSupported("ExecutionManager.UpdateHistoryTaskReaderProgress"))
+}
+
+func (replayExecutionManager) AddHistoryTasks(context.Context, *persistence.AddHistoryTasksRequest) error {
+	return replayNotSupported("ExecutionManager.AddHistoryTasks")
+}
+
+func (replayExecutionManager) GetHistoryTasks(context.Context, *persistence.GetHistoryTasksRequest) (*persistence.GetHistoryTasksResponse, error) {
+	return nil, replayNotSupported("ExecutionManager.GetHistoryTasks")
+}
+
+func (replayExecutionManager) CompleteHistoryTask(context.Context, *persistence.CompleteHistoryTaskRequest) error {
+	return replayNotSupported("ExecutionManager.CompleteHistoryTask")
+}
+
+func (replayExecutionManager) RangeCompleteHistoryTasks(context.Context, *persistence.RangeCompleteHistoryTasksRequest) error {
+	return replayNotSupported("ExecutionManager.RangeCompleteHistoryTasks")
+}
+
+func (replayExecutionManager) PutReplicationTaskToDLQ(context.Context, *persistence.PutReplicationTaskToDLQRequest) error {
+	return replayNotSupported("ExecutionManager.PutReplicationTaskToDLQ")
+}
+
+func (replayExecutionManager) GetReplicationTasksFromDLQ(context.Context, *persistence.GetReplicationTasksFromDLQRequest) (*persistence.GetHistoryTasksResponse, error) {
+	return nil, replayNotSupported("ExecutionManager.GetReplicationTasksFromDLQ")
+}
+
+func (replayExecutionManager) DeleteReplicationTaskFromDLQ(context.Context, *persistence.DeleteReplicationTaskFromDLQRequest) error {
+	return replayNotSupported("ExecutionManager.DeleteReplicationTaskFromDLQ")
+}
+
+func (replayExecutionManager) RangeDeleteReplicationTaskFromDLQ(context.Context, *persistence.RangeDeleteReplicationTaskFromDLQRequest) error {
+	return replayNotSupported("ExecutionManager.RangeDeleteReplicationTaskFromDLQ")
+}
+
+func (replayExecutionManager) IsReplicationDLQEmpty(context.Context, *persistence.GetReplicationTasksFromDLQRequest) (bool, error) {
+	return false, replayNotSupported("ExecutionManager.IsReplicationDLQEmpty")
+}
+
+func (replayExecutionManager) AppendHistoryNodes(context.Context, *persistence.AppendHistoryNodesRequest) (*persistence.AppendHistoryNodesResponse, error) {
+	return nil, replayNotSupported("ExecutionManager.AppendHistoryNodes")
+}
+
+func (replayExecutionManager) AppendRawHistoryNodes(context.Context, *persistence.AppendRawHistoryNodesRequest) (*persistence.AppendHistoryNodesResponse, error) {
+	return nil, replayNotSupported("ExecutionManager.AppendRawHistoryNodes")
+}
+
+func (replayExecutionManager) ReadHistoryBranch(context.Context, *persistence.ReadHistoryBranchRequest) (*persistence.ReadHistoryBranchResponse, error) {
+	return nil, replayNotSupported("ExecutionManager.ReadHistoryBranch")
+}
+
+func (replayExecutionManager) ReadHistoryBranchByBatch(context.Context, *persistence.ReadHistoryBranchRequest) (*persistence.ReadHistoryBranchByBatchResponse, error) {
+	return nil, replayNotSupported("ExecutionManager.ReadHistoryBranchByBatch")
+}
+
+func (replayExecutionManager) ReadHistoryBranchReverse(context.Context, *persistence.ReadHistoryBranchReverseRequest) (*persistence.ReadHistoryBranchReverseResponse, error) {
+	return nil, replayNotSupported("ExecutionManager.ReadHistoryBranchReverse")
+}
+
+func (replayExecutionManager) ReadRawHistoryBranch(context.Context, *persistence.ReadHistoryBranchRequest) (*persistence.ReadRawHistoryBranchResponse, error) {
+	return nil, replayNotSupported("ExecutionManager.ReadRawHistoryBranch")
+}
+
+func (replayExecutionManager) ForkHistoryBranch(context.Context, *persistence.ForkHistoryBranchRequest) (*persistence.ForkHistoryBranchResponse, error) {
+	return nil, replayNotSupported("ExecutionManager.ForkHistoryBranch")
+}
+
+func (replayExecutionManager) DeleteHistoryBranch(context.Context, *persistence.DeleteHistoryBranchRequest) error {
+	return replayNotSupported("ExecutionManager.DeleteHistoryBranch")
+}
+
+func (replayExecutionManager) TrimHistoryBranch(context.Context, *persistence.TrimHistoryBranchRequest) (*persistence.TrimHistoryBranchResponse, error) {
+	return nil, replayNotSupported("ExecutionManager.TrimHistoryBranch")
+}
+
+func (replayExecutionManager) GetHistoryTree(context.Context, *persistence.GetHistoryTreeRequest) (*persistence.GetHistoryTreeResponse, error) {
+	return nil, replayNotSupported("ExecutionManager.GetHistoryTree")
+}
+
+func (replayExecutionManager) GetAllHistoryTreeBranches(context.Context, *persistence.GetAllHistoryTreeBranchesRequest) (*persistence.GetAllHistoryTreeBranchesResponse, error) {
+	return nil, replayNotSupported("ExecutionManager.GetAllHistoryTreeBranches")
+}
@@ -0,0 +1,520 @@
+// The MIT License
+//
+// Copyright (c) 2020 Temporal Technologies Inc.  All rights reserved.
+//
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package tdbg
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	"github.com/temporalio/tctl-kit/pkg/color"
+	"github.com/urfave/cli/v2"
+	commonpb "go.temporal.io/api/common/v1"
+	historypb "go.temporal.io/api/history/v1"
+	"go.temporal.io/api/workflowservice/v1"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+
+	"go.temporal.io/server/api/adminservice/v1"
+	persistencespb "go.temporal.io/server/api/persistence/v1"
+	"go.temporal.io/server/common/archiver"
+	"go.temporal.io/server/common/clock"
+	"go.temporal.io/server/common/cluster"
+	"go.temporal.io/server/common/config"
+	"go.temporal.io/server/common/dynamicconfig"
+	"go.temporal.io/server/common/log"
+	"go.temporal.io/server/common/namespace"
+	"go.temporal.io/server/common/persistence/serialization"
+	"go.temporal.io/server/common/persistence/versionhistory"
+	"go.temporal.io/server/service/history/configs"
+	"go.temporal.io/server/service/history/events"
+)
+
+type (
+	// divergence is a single field on which the persisted mutable state and the mutable state
+	// replayed from history disagree.
+	divergence struct {
+		path         string
+		mutableState string
+		history      string
+	}
+
+	workflowVerifier struct {
+		adminClient    adminservice.AdminServiceClient
+		namespaceEntry *namespace.Namespace
+		shard          *replayShard
+		serializer     serialization.Serializer
+	}
+)
+
+var (
+	// Only fields that are fully determined by history are verified. Fields that change without
+	// an event, like activity attempts, heartbeats or transient workflow tasks, are skipped.
+	verifiedExecutionInfoFields = []protoreflect.Name{
+		"workflow_type_name",
+		"task_queue",
+		"workflow_execution_timeout",
+		"workflow_run_timeout",
+		"default_workflow_task_timeout",
+		"parent_namespace_id",
+		"parent_workflow_id",
+		"parent_run_id",
+		"parent_initiated_id",
+		"first_execution_run_id",
+		"completion_event_batch_id",
+		"cancel_requested",
+		"signal_count",
+		"activity_count",
+		"child_execution_count",
+		"user_timer_count",
+		"request_cancel_external_count",
+		"signal_external_count",
+		"new_execution_run_id",
+		"search_attributes",
+		"memo",
+	}
+	verifiedExecutionStateFields = []protoreflect.Name{
+		"state",
+		"status",
+	}
+	verifiedActivityInfoFields = []protoreflect.Name{
+		"version",
+		"scheduled_event_batch_id",
+		"activity_id",
+		"activity_type",
+		"task_queue",
+		"schedule_to_start_timeout",
+		"schedule_to_close_timeout",
+		"start_to_close_timeout",
+		"heartbeat_timeout",
+		"cancel_requested",
+		"has_retry_policy",
+		"retry_initial_interval",
+		"retry_maximum_interval",
+		"retry_maximum_attempts",
+		"retry_backoff_coefficient",
+		"retry_non_retryable_error_types",
+	}
+	verifiedTimerInfoFields = []protoreflect.Name{
+		"version",
+		"started_event_id",
+		"expiry_time",
+	}
+	verifiedChildExecutionInfoFields = []protoreflect.Name{
+		"version",
+		"initiated_event_batch_id",
+		"started_event_id",
+		"started_workflow_id",
+		"started_run_id",
+		"namespace",
+		"namespace_id",
+		"workflow_type_name",
+		"parent_close_policy",
+	}
+	verifiedSignalInfoFields = []protoreflect.Name{
+		"version",
+		"initiated_event_batch_id",
+	}
+	verifiedRequestCancelInfoFields = []protoreflect.Name{
+		"version",
+		"initiated_event_batch_id",
+	}
+)
+
+// AdminVerifyWorkflow replays the history of one or more workflow executions and compares the result
+// with the persisted mutable state
+func AdminVerifyWorkflow(c *cli.Context, clientFactory ClientFactory) error {
+	nsName, err := getRequiredOption(c, FlagNamespace)
+	if err != nil {
+		return err
+	}
+	wid := c.String(FlagWorkflowID)
+	query := c.String(FlagQuery)
+	if wid == "" && query == "" {
+		return fmt.Errorf("option %s or %s is required", FlagWorkflowID, FlagQuery)
+	}
+	if wid != "" && query != "" {
+		return fmt.Errorf("option %s cannot be used with %s", FlagQuery, FlagWorkflowID)
+	}
+
+	verifier, err := newWorkflowVerifierFromCluster(c, clientFactory.AdminClient(c), namespace.Name(nsName))
+	if err != nil {
+		return err
+	}
+
+	if wid != "" {
+		diverged, err := verifyAndPrint(c, verifier, &commonpb.WorkflowExecution{
+			WorkflowId: wid,
+			RunId:      c.String(FlagRunID),
+		})
+		if err != nil {
+			return err
+		}
+		if diverged {
+			return fmt.Errorf("mutable state diverged from history")
+		}
+		return nil
+	}
+
+	wfClient := clientFactory.WorkflowClient(c)
+	var verified, diverged, failed int
+	var token []byte
+	for doContinue := true; doContinue; doContinue = len(token) != 0 {
+		ctx, cancel := newContext(c)
+		resp, err := wfClient.ListWorkflowExecutions(ctx, &workflowservice.ListWorkflowExecutionsRequest{
+			Namespace:     nsName,
+			PageSize:      int32(c.Int(FlagPageSize)),
+			NextPageToken: token,
+			Query:         query,
+		})
+		cancel()
+		if err != nil {
+			return fmt.Errorf("unable to list workflow executions: %s", err)
+		}
+		for _, info := range resp.GetExecutions() {
+			verified++
+			d, err := verifyAndPrint(c, verifier, info.GetExecution())
+			if err != nil {
+				fmt.Println(color.Red(c, "Unable to verify workflow %s run %s:", info.GetExecution().GetWorkflowId(), info.GetExecution().GetRunId()), err)
+				failed++
+				continue
+			}
+			if d {
+				diverged++
+			}
+		}
+		token = resp.NextPageToken
+	}
+
+	fmt.Printf("======== verified %v, diverged %v, failed %v ======\n", verified, diverged, failed)
+	if diverged > 0 || failed > 0 {
+		return fmt.Errorf("%v of %v workflow executions could not be verified against their history", diverged+failed, verified)
+	}
+	return nil
+}
+
+func verifyAndPrint(c *cli.Context, verifier *workflowVerifier, execution *commonpb.WorkflowExecution) (bool, error) {
+	ctx, cancel := newContext(c)
+	defer cancel()
+
+	runID, divergences, err := verifier.verify(ctx, execution)
+	if err != nil {
+		return false, err
+	}
+	if len(divergences) == 0 {
+		fmt.Println(color.Green(c, "Workflow %s run %s: mutable state matches history", execution.GetWorkflowId(), runID))
+		return false, nil
+	}
+	fmt.Println(color.Red(c, "Workflow %s run %s: %d divergences", execution.GetWorkflowId(), runID, len(divergences)))
+	for _, d := range divergences {
+		fmt.Printf("  %s: mutable state %s, history %s\n", d.path, d.mutableState, d.history)
+	}
+	return true, nil
+}
+
+func newWorkflowVerifierFromCluster(
+	c *cli.Context,
+	adminClient adminservice.AdminServiceClient,
+	nsName namespace.Name,
+) (*workflowVerifier, error) {
+	ctx, cancel := newContext(c)
+	defer cancel()
+
+	clusterResp, err := adminClient.DescribeCluster(ctx, &adminservice.DescribeClusterRequest{})
+	if err != nil {
+		return nil, fmt.Errorf("unable to describe cluster: %s", err)
+	}
+	clusterInfo := make(map[string]cluster.ClusterInformation)
+	var token []byte
+	for doContinue := true; doContinue; doContinue = len(token) != 0 {
+		resp, err := adminClient.ListClusters(ctx, &adminservice.ListClustersRequest{
+			PageSize:      100,
+			NextPageToken: token,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("unable to list clusters: %s", err)
+		}
+		for _, metadata := range resp.GetClusters() {
+			clusterInfo[metadata.GetClusterName()] = cluster.ClusterInformation{
+				Enabled:                metadata.GetIsConnectionEnabled() || metadata.GetClusterName() == clusterResp.GetClusterName(),
+				InitialFailoverVersion: metadata.GetInitialFailoverVersion(),
+				RPCAddress:             metadata.GetClusterAddress(),
+				ClusterID:              metadata.GetClusterId(),
+				ShardCount:             metadata.GetHistoryShardCount(),
+				Tags:                   metadata.GetTags(),
+			}
+		}
+		token = resp.NextPageToken
+	}
+	if _, ok := clusterInfo[clusterResp.GetClusterName()]; !ok {
+		clusterInfo[clusterResp.GetClusterName()] = cluster.ClusterInformation{
+			Enabled:                true,
+			InitialFailoverVersion: clusterResp.GetInitialFailoverVersion(),
+			ClusterID:              clusterResp.GetClusterId(),
+			ShardCount:             clusterResp.GetHistoryShardCount(),
+		}
+	}
+	clusterMetadata := cluster.NewMetadata(
+		clusterResp.GetIsGlobalNamespaceEnabled(),
+		clusterResp.GetFailoverVersionIncrement(),
+		clusterResp.GetClusterName(),
+		clusterResp.GetClusterName(),
+		clusterInfo,
+		nil,
+		nil,
+		log.NewNoopLogger(),
+	)
+
+	verifier := newWorkflowVerifier(c.Context, adminClient, clusterMetadata, clusterResp.GetHistoryShardCount())
+	verifier.namespaceEntry, err = verifier.shard.namespaceRegistry.GetNamespace(nsName)
+	if err != nil {
+		return nil, fmt.Errorf("unable to get namespace %s: %s", nsName, err)
+	}
+	return verifier, nil
+}
+
+func newWorkflowVerifier(
+	ctx context.Context,
+	adminClient adminservice.AdminServiceClient,
+	clusterMetadata cluster.Metadata,
+	numberOfShards int32,
+) *workflowVerifier {
+	dc := dynamicconfig.NewNoopCollection()
+	return &workflowVerifier{
+		adminClient: adminClient,
+		serializer:  serialization.NewSerializer(),
+		shard: &replayShard{
+			namespaceRegistry: &replayNamespaceRegistry{
+				ctx:         ctx,
+				adminClient: adminClient,
+				byID:        make(map[namespace.ID]*namespace.Namespace),
+				byName:      make(map[namespace.Name]*namespace.Namespace),
+			},
+			clusterMetadata: clusterMetadata,
+			config:          configs.NewConfig(dc, numberOfShards, false, false),
+			archivalMetadata: archiver.NewArchivalMetadata(
+				dc,
+				config.ArchivalDisabled,
+				false,
+				config.ArchivalDisabled,
+				false,
+				&config.ArchivalNamespaceDefaults{},
+			),
+			eventsCache: &replayEventsCache{events: make(map[events.EventKey]*historypb.HistoryEvent)},
+			serializer:  serialization.NewSerializer(),
+			timeSource:  clock.NewRealTimeSource(),
+			logger:      log.NewNoopLogger(),
+		},
+	}
+}
+
+// verify compares the persisted mutable state of the execution with the mutable state rebuilt from its
+// history. It returns the run ID that was verified, which is resolved when the execution has none.
+func (v *workflowVerifier) verify(
+	ctx context.Context,
+	execution *commonpb.WorkflowExecution,
+) (string, []divergence, error) {
+	nsEntry := v.namespaceEntry
+	msResp, err := v.adminClient.DescribeMutableState(ctx, &adminservice.DescribeMutableStateRequest{
+		Namespace: nsEntry.Name().String(),
+		Execution: execution,
+	})
+	if err != nil {
+		return "", nil, fmt.Errorf("unable to get workflow mutable state: %s", err)
+	}
+	dbState := msResp.GetDatabaseMutableState()
+	execution = &commonpb.WorkflowExecution{
+		WorkflowId: execution.GetWorkflowId(),
+		RunId:      dbState.GetExecutionState().GetRunId(),
+	}
+
+	history, err := v.getHistory(ctx, nsEntry.ID(), execution, dbState)
+	if err != nil {
+		return execution.RunId, nil, err
+	}
+	replayed, err := v.shard.replay(ctx, nsEntry, execution, history)
+	if err != nil {
+		return execution.RunId, nil, fmt.Errorf("unable to replay history: %s", err)
+	}
+	return execution.RunId, diffMutableState(dbState, replayed.CloneToProto()), nil
+}
+
+// getHistory reads the history of the current branch up to the last event known to the mutable state, so
+// that a workflow making progress while it is verified doesn't show up as diverged.
+func (v *workflowVerifier) getHistory(
+	ctx context.Context,
+	nsID namespace.ID,
+	execution *commonpb.WorkflowExecution,
+	dbState *persistencespb.WorkflowMutableState,
+) ([][]*historypb.HistoryEvent, error) {
+	currentVersionHistory, err := versionhistory.GetCurrentVersionHistory(dbState.GetExecutionInfo().GetVersionHistories())
+	if err != nil {
+		return nil, err
+	}
+	lastItem, err := versionhistory.GetLastVersionHistoryItem(currentVersionHistory)
+	if err != nil {
+		return nil, err
+	}
+
+	var history [][]*historypb.HistoryEvent
+	var token []byte
+	for doContinue := true; doContinue; doContinue = len(token) != 0 {
+		resp, err := v.adminClient.GetWorkflowExecutionRawHistoryV2(ctx, &adminservice.GetWorkflowExecutionRawHistoryV2Request{
+			NamespaceId:     nsID.String(),
+			Execution:       execution,
+			EndEventId:      lastItem.GetEventId() + 1,
+			EndEventVersion: lastItem.GetVersion(),
+			MaximumPageSize: 100,
+			NextPageToken:   token,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("unable to recv History Branch: %s", err)
+		}
+		for _, blob := range resp.HistoryBatches {
+			batch, err := v.serializer.DeserializeEvents(blob)
+			if err != nil {
+				return nil, fmt.Errorf("unable to deserialize Events: %s", err)
+			}
+			history = append(history, batch)
+		}
+		token = resp.NextPageToken
+	}
+	if len(history) == 0 {
+		return nil, fmt.Errorf("workflow history is empty")
+	}
+	return history, nil
+}
+
+// diffMutableState reports every verified field that differs between the persisted mutable state and the
+// replayed one. The result is ordered for stable output.
+func diffMutableState(dbState, replayed *persistencespb.WorkflowMutableState) []divergence {
+	var result []divergence
+	if dbState.GetNextEventId() != replayed.GetNextEventId() {
+		result = append(result, divergence{
+			path:         "next_event_id",
+			mutableState: fmt.Sprint(dbState.GetNextEventId()),
+			history:      fmt.Sprint(replayed.GetNextEventId()),
+		})
+	}
+	result = append(result, diffFields("execution_info", dbState.GetExecutionInfo(), replayed.GetExecutionInfo(), verifiedExecutionInfoFields)...)
+	result = append(result, diffFields("execution_state", dbState.GetExecutionState(), replayed.GetExecutionState(), verifiedExecutionStateFields)...)
+	result = append(result, diffInfos("activity", dbState.GetActivityInfos(), replayed.GetActivityInfos(), verifiedActivityInfoFields)...)
+	result = append(result, diffInfos("timer", dbState.GetTimerInfos(), replayed.GetTimerInfos(), verifiedTimerInfoFields)...)
+	result = append(result, diffInfos("child", dbState.GetChildExecutionInfos(), replayed.GetChildExecutionInfos(), verifiedChildExecutionInfoFields)...)
+	result = append(result, diffInfos("signal", dbState.GetSignalInfos(), replayed.GetSignalInfos(), verifiedSignalInfoFields)...)
+	result = append(result, diffInfos("request_cancel", dbState.GetRequestCancelInfos(), replayed.GetRequestCancelInfos(), verifiedRequestCancelInfoFields)...)
+	return result
+}
+
+func diffInfos[K int64 | string, V proto.Message](
+	kind string,
+	dbInfos map[K]V,
+	replayedInfos map[K]V,
+	fields []protoreflect.Name,
+) []divergence {
+	keys := make(map[K]struct{}, len(dbInfos)+len(replayedInfos))
+	for k := range dbInfos {
+		keys[k] = struct{}{}
+	}
+	for k := range replayedInfos {
+		keys[k] = struct{}{}
+	}
+	sortedKeys := make([]K, 0, len(keys))
+	for k := range keys {
+		sortedKeys = append(sortedKeys, k)
+	}
+	sort.Slice(sortedKeys, func(i, j int) bool { return sortedKeys[i] < sortedKeys[j] })
+
+	var result []divergence
+	for _, k := range sortedKeys {
+		path := fmt.Sprintf("%s[%v]", kind, k)
+		dbInfo, inDB := dbInfos[k]
+		replayedInfo, inHistory := replayedInfos[k]
+		switch {
+		case !inHistory:
+			result = append(result, divergence{path: path, mutableState: "pending", history: "not pending"})
+		case !inDB:
+			result = append(result, divergence{path: path, mutableState: "not pending", history: "pending"})
+		default:
+			result = append(result, diffFields(path, dbInfo, replayedInfo, fields)...)
+		}
+	}
+	return result
+}
+
+func diffFields(path string, dbMessage, replayedMessage proto.Message, fields []protoreflect.Name) []divergence {
+	dbReflect := dbMessage.ProtoReflect()
+	replayedReflect := replayedMessage.ProtoReflect()
+	descriptor := dbReflect.Descriptor()
+
+	var result []divergence
+	for _, name := range fields {
+		fd := descriptor.Fields().ByName(name)
+		if fd == nil {
+			panic(fmt.Sprintf("unknown field %s in %s", name, descriptor.FullName()))
+		}
+		dbField := singleField(dbReflect, fd)
+		replayedField := singleField(replayedReflect, fd)
+		if proto.Equal(dbField, replayedField) {
+			continue
+		}
+		result = append(result, divergence{
+			path:         fmt.Sprintf("%s.%s", path, name),
+			mutableState: formatField(dbField, fd),
+			history:      formatField(replayedField, fd),
+		})
+	}
+	return result
+}
+
+// singleField returns a copy of the message with only the given field set, so fields of any kind can be
+// compared with proto.Equal.
+func singleField(m protoreflect.Message, fd protoreflect.FieldDescriptor) proto.Message {
+	result := m.Type().New()
+	if m.IsValid() && m.Has(fd) {
+		result.Set(fd, m.Get(fd))
+	}
+	return result.Interface()
+}
+
+func formatField(m proto.Message, fd protoreflect.FieldDescriptor) string {
+	if !m.ProtoReflect().Has(fd) {
+		return "<unset>"
+	}
+	data, err := protojson.Marshal(m)
+	if err != nil {
+		return fmt.Sprint(m.ProtoReflect().Get(fd).Interface())
+	}
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return string(data)
+	}
+	for _, value := range fields {
+		return string(value)
+	}
+	return "<unset>"
+}
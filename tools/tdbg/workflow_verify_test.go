@@ -0,0 +1,275 @@
+// The MIT License
+//
+// Copyright (c) 2020 Temporal Technologies Inc.  All rights reserved.
+//
+// Copyright (c) 2020 Uber Technologies, Inc.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in
+// all copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+// THE SOFTWARE.
+
+package tdbg
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/pborman/uuid"
+	"github.com/stretchr/testify/require"
+	commonpb "go.temporal.io/api/common/v1"
+	enumspb "go.temporal.io/api/enums/v1"
+	historypb "go.temporal.io/api/history/v1"
+	taskqueuepb "go.temporal.io/api/taskqueue/v1"
+	"google.golang.org/grpc"
+	"google.golang.org/protobuf/types/known/durationpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"go.temporal.io/server/api/adminservice/v1"
+	persistencespb "go.temporal.io/server/api/persistence/v1"
+	"go.temporal.io/server/common"
+	"go.temporal.io/server/common/cluster"
+	"go.temporal.io/server/common/namespace"
+	"go.temporal.io/server/common/persistence/serialization"
+)
+
+type verifyAdminClient struct {
+	adminservice.AdminServiceClient
+
+	dbState *persistencespb.WorkflowMutableState
+	history []*commonpb.DataBlob
+}
+
+func (c *verifyAdminClient) DescribeMutableState(
+	context.Context,
+	*adminservice.DescribeMutableStateRequest,
+	...grpc.CallOption,
+) (*adminservice.DescribeMutableStateResponse, error) {
+	return &adminservice.DescribeMutableStateResponse{DatabaseMutableState: c.dbState}, nil
+}
+
+func (c *verifyAdminClient) GetWorkflowExecutionRawHistoryV2(
+	context.Context,
+	*adminservice.GetWorkflowExecutionRawHistoryV2Request,
+	...grpc.CallOption,
+) (*adminservice.GetWorkflowExecutionRawHistoryV2Response, error) {
+	return &adminservice.GetWorkflowExecutionRawHistoryV2Response{HistoryBatches: c.history}, nil
+}
+
+func TestWorkflowVerifier(t *testing.T) {
+	ctx := context.Background()
+	nsEntry := namespace.NewLocalNamespaceForTest(
+		&persistencespb.NamespaceInfo{Id: uuid.New(), Name: "test-namespace"},
+		&persistencespb.NamespaceConfig{Retention: durationpb.New(24 * time.Hour)},
+		cluster.TestCurrentClusterName,
+	)
+	execution := &commonpb.WorkflowExecution{WorkflowId: "test-workflow-id", RunId: uuid.New()}
+	history := verifyTestHistory(nsEntry, execution.RunId)
+
+	serializer := serialization.NewSerializer()
+	client := &verifyAdminClient{}
+	for _, batch := range history {
+		blob, err := serializer.SerializeEvents(batch, enumspb.ENCODING_TYPE_PROTO3)
+		require.NoError(t, err)
+		client.history = append(client.history, blob)
+	}
+
+	verifier := newWorkflowVerifier(
+		ctx,
+		client,
+		cluster.NewMetadataForTest(cluster.NewTestClusterMetadataConfig(false, true)),
+		1,
+	)
+	verifier.shard.namespaceRegistry.add(nsEntry)
+	verifier.namespaceEntry = nsEntry
+
+	replayed, err := verifier.shard.replay(ctx, nsEntry, execution, history)
+	require.NoError(t, err)
+	dbState := replayed.CloneToProto()
+	require.Equal(t, int64(11), dbState.GetNextEventId())
+	require.Contains(t, dbState.GetActivityInfos(), int64(5))
+	require.Contains(t, dbState.GetTimerInfos(), "timer-id")
+	require.Equal(t, "child-run-id", dbState.GetChildExecutionInfos()[7].GetStartedRunId())
+	require.Contains(t, dbState.GetSignalInfos(), int64(8))
+	require.Equal(t, int64(1), dbState.GetExecutionInfo().GetSignalCount())
+
+	t.Run("matching", func(t *testing.T) {
+		client.dbState = common.CloneProto(dbState)
+		// attempts are not recorded in history and must not be reported
+		client.dbState.ActivityInfos[5].Attempt = 3
+
+		runID, divergences, err := verifier.verify(ctx, &commonpb.WorkflowExecution{WorkflowId: execution.WorkflowId})
+		require.NoError(t, err)
+		require.Equal(t, execution.RunId, runID)
+		require.Empty(t, divergences)
+	})
+
+	t.Run("diverged", func(t *testing.T) {
+		client.dbState = common.CloneProto(dbState)
+		client.dbState.NextEventId = 12
+		client.dbState.ExecutionInfo.SignalCount = 2
+		client.dbState.ActivityInfos[5].HeartbeatTimeout = durationpb.New(10 * time.Second)
+		delete(client.dbState.TimerInfos, "timer-id")
+		client.dbState.ChildExecutionInfos[7].StartedRunId = "other-child-run-id"
+		client.dbState.ChildExecutionInfos[11] = &persistencespb.ChildExecutionInfo{InitiatedEventId: 11, StartedWorkflowId: "other-child-workflow-id"}
+		delete(client.dbState.SignalInfos, 8)
+
+		_, divergences, err := verifier.verify(ctx, execution)
+		require.NoError(t, err)
+		require.Equal(t, []divergence{
+			{path: "next_event_id", mutableState: "12", history: "11"},
+			{path: "execution_info.signal_count", mutableState: `"2"`, history: `"1"`},
+			{path: "activity[5].heartbeat_timeout", mutableState: `"10s"`, history: "<unset>"},
+			{path: "timer[timer-id]", mutableState: "not pending", history: "pending"},
+			{path: "child[7].started_run_id", mutableState: `"other-child-run-id"`, history: `"child-run-id"`},
+			{path: "child[11]", mutableState: "pending", history: "not pending"},
+			{path: "signal[8]", mutableState: "not pending", history: "pending"},
+		}, divergences)
+	})
+
+	t.Run("unexpected panic", func(t *testing.T) {
+		// A history without events makes the replay index out of range.
+		_, err := verifier.shard.replay(ctx, nsEntry, execution, [][]*historypb.HistoryEvent{{}})
+		require.ErrorContains(t, err, "unexpected panic while replaying history")
+		require.ErrorContains(t, err, "runtime/debug.Stack")
+		require.NotErrorIs(t, err, errReplayNotSupported)
+	})
+
+	t.Run("not supported", func(t *testing.T) {
+		_, err := verifier.shard.GetEngine(ctx)
+		require.ErrorIs(t, err, errReplayNotSupported)
+		require.PanicsWithError(t, replayNotSupported("GetHistoryClient").Error(), func() {
+			verifier.shard.GetHistoryClient()
+		})
+	})
+}
+
+func verifyTestHistory(nsEntry *namespace.Namespace, runID string) [][]*historypb.HistoryEvent {
+	now := time.Now().UTC()
+	taskQueue := &taskqueuepb.TaskQueue{Name: "test-task-queue", Kind: enumspb.TASK_QUEUE_KIND_NORMAL}
+	event := func(eventID int64, eventType enumspb.EventType) *historypb.HistoryEvent {
+		return &historypb.HistoryEvent{
+			EventId:   eventID,
+			EventTime: timestamppb.New(now),
+			EventType: eventType,
+			TaskId:    eventID,
+		}
+	}
+
+	started := event(1, enumspb.EVENT_TYPE_WORKFLOW_EXECUTION_STARTED)
+	started.Attributes = &historypb.HistoryEvent_WorkflowExecutionStartedEventAttributes{
+		WorkflowExecutionStartedEventAttributes: &historypb.WorkflowExecutionStartedEventAttributes{
+			WorkflowType:           &commonpb.WorkflowType{Name: "test-workflow-type"},
+			TaskQueue:              taskQueue,
+			WorkflowRunTimeout:     durationpb.New(time.Hour),
+			WorkflowTaskTimeout:    durationpb.New(10 * time.Second),
+			OriginalExecutionRunId: runID,
+			FirstExecutionRunId:    runID,
+			Attempt:                1,
+		},
+	}
+	scheduled := event(2, enumspb.EVENT_TYPE_WORKFLOW_TASK_SCHEDULED)
+	scheduled.Attributes = &historypb.HistoryEvent_WorkflowTaskScheduledEventAttributes{
+		WorkflowTaskScheduledEventAttributes: &historypb.WorkflowTaskScheduledEventAttributes{
+			TaskQueue:           taskQueue,
+			StartToCloseTimeout: durationpb.New(10 * time.Second),
+			Attempt:             1,
+		},
+	}
+	taskStarted := event(3, enumspb.EVENT_TYPE_WORKFLOW_TASK_STARTED)
+	taskStarted.Attributes = &historypb.HistoryEvent_WorkflowTaskStartedEventAttributes{
+		WorkflowTaskStartedEventAttributes: &historypb.WorkflowTaskStartedEventAttributes{
+			ScheduledEventId: 2,
+			RequestId:        uuid.New(),
+		},
+	}
+	completed := event(4, enumspb.EVENT_TYPE_WORKFLOW_TASK_COMPLETED)
+	completed.Attributes = &historypb.HistoryEvent_WorkflowTaskCompletedEventAttributes{
+		WorkflowTaskCompletedEventAttributes: &historypb.WorkflowTaskCompletedEventAttributes{
+			ScheduledEventId: 2,
+			StartedEventId:   3,
+		},
+	}
+	activityScheduled := event(5, enumspb.EVENT_TYPE_ACTIVITY_TASK_SCHEDULED)
+	activityScheduled.Attributes = &historypb.HistoryEvent_ActivityTaskScheduledEventAttributes{
+		ActivityTaskScheduledEventAttributes: &historypb.ActivityTaskScheduledEventAttributes{
+			ActivityId:                   "activity-id",
+			ActivityType:                 &commonpb.ActivityType{Name: "test-activity-type"},
+			TaskQueue:                    taskQueue,
+			ScheduleToCloseTimeout:       durationpb.New(time.Minute),
+			ScheduleToStartTimeout:       durationpb.New(time.Minute),
+			StartToCloseTimeout:          durationpb.New(time.Minute),
+			WorkflowTaskCompletedEventId: 4,
+		},
+	}
+	timerStarted := event(6, enumspb.EVENT_TYPE_TIMER_STARTED)
+	timerStarted.Attributes = &historypb.HistoryEvent_TimerStartedEventAttributes{
+		TimerStartedEventAttributes: &historypb.TimerStartedEventAttributes{
+			TimerId:                      "timer-id",
+			StartToFireTimeout:           durationpb.New(time.Hour),
+			WorkflowTaskCompletedEventId: 4,
+		},
+	}
+
+	childInitiated := event(7, enumspb.EVENT_TYPE_START_CHILD_WORKFLOW_EXECUTION_INITIATED)
+	childInitiated.Attributes = &historypb.HistoryEvent_StartChildWorkflowExecutionInitiatedEventAttributes{
+		StartChildWorkflowExecutionInitiatedEventAttributes: &historypb.StartChildWorkflowExecutionInitiatedEventAttributes{
+			Namespace:                    nsEntry.Name().String(),
+			NamespaceId:                  nsEntry.ID().String(),
+			WorkflowId:                   "child-workflow-id",
+			WorkflowType:                 &commonpb.WorkflowType{Name: "test-child-workflow-type"},
+			TaskQueue:                    taskQueue,
+			ParentClosePolicy:            enumspb.PARENT_CLOSE_POLICY_ABANDON,
+			WorkflowTaskCompletedEventId: 4,
+		},
+	}
+	signalInitiated := event(8, enumspb.EVENT_TYPE_SIGNAL_EXTERNAL_WORKFLOW_EXECUTION_INITIATED)
+	signalInitiated.Attributes = &historypb.HistoryEvent_SignalExternalWorkflowExecutionInitiatedEventAttributes{
+		SignalExternalWorkflowExecutionInitiatedEventAttributes: &historypb.SignalExternalWorkflowExecutionInitiatedEventAttributes{
+			Namespace:                    nsEntry.Name().String(),
+			NamespaceId:                  nsEntry.ID().String(),
+			WorkflowExecution:            &commonpb.WorkflowExecution{WorkflowId: "signaled-workflow-id"},
+			SignalName:                   "test-signal",
+			WorkflowTaskCompletedEventId: 4,
+		},
+	}
+	childStarted := event(9, enumspb.EVENT_TYPE_CHILD_WORKFLOW_EXECUTION_STARTED)
+	childStarted.Attributes = &historypb.HistoryEvent_ChildWorkflowExecutionStartedEventAttributes{
+		ChildWorkflowExecutionStartedEventAttributes: &historypb.ChildWorkflowExecutionStartedEventAttributes{
+			Namespace:         nsEntry.Name().String(),
+			NamespaceId:       nsEntry.ID().String(),
+			InitiatedEventId:  7,
+			WorkflowExecution: &commonpb.WorkflowExecution{WorkflowId: "child-workflow-id", RunId: "child-run-id"},
+			WorkflowType:      &commonpb.WorkflowType{Name: "test-child-workflow-type"},
+		},
+	}
+	signaled := event(10, enumspb.EVENT_TYPE_WORKFLOW_EXECUTION_SIGNALED)
+	signaled.Attributes = &historypb.HistoryEvent_WorkflowExecutionSignaledEventAttributes{
+		WorkflowExecutionSignaledEventAttributes: &historypb.WorkflowExecutionSignaledEventAttributes{
+			SignalName: "test-signal",
+			Identity:   "test-identity",
+		},
+	}
+
+	return [][]*historypb.HistoryEvent{
+		{started, scheduled},
+		{taskStarted},
+		{completed, activityScheduled, timerStarted, childInitiated, signalInitiated},
+		{childStarted},
+		{signaled},
+	}
+}